@@ -2,22 +2,31 @@ package main
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
 	"flag"
+	"hash"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
+	"strings"
 
-	"github.com/golang/snappy"
+	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/klog/v2"
 
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/monitoring"
 	"kubevirt.io/containerized-data-importer/pkg/util"
+	"kubevirt.io/containerized-data-importer/pkg/util/cgroup"
+	"kubevirt.io/containerized-data-importer/pkg/util/logging"
 	prometheusutil "kubevirt.io/containerized-data-importer/pkg/util/prometheus"
 )
 
@@ -84,6 +93,31 @@ func createHTTPClient(clientKey, clientCert, serverCert []byte) *http.Client {
 	return client
 }
 
+// bearerTokenTransport authenticates the requests of a remote clone source to the upload proxy with an upload token
+type bearerTokenTransport struct {
+	token     string
+	transport http.RoundTripper
+}
+
+func (t *bearerTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return t.transport.RoundTrip(req)
+}
+
+// createTokenHTTPClient creates the client of a remote clone source, streaming to the upload proxy of the target
+// cluster with an upload token instead of a client certificate
+func createTokenHTTPClient(uploadToken string, serverCert []byte) *http.Client {
+	caCertPool, err := x509.SystemCertPool()
+	if err != nil {
+		caCertPool = x509.NewCertPool()
+	}
+	caCertPool.AppendCertsFromPEM(serverCert)
+
+	transport := &http.Transport{TLSClientConfig: &tls.Config{RootCAs: caCertPool}}
+	return &http.Client{Transport: &bearerTokenTransport{token: uploadToken, transport: transport}}
+}
+
 func startPrometheus() {
 	certsDirectory, err := os.MkdirTemp("", "certsdir")
 	if err != nil {
@@ -93,7 +127,7 @@ func startPrometheus() {
 	prometheusutil.StartPrometheusEndpoint(certsDirectory)
 }
 
-func createProgressReader(readCloser io.ReadCloser, ownerUID string, totalBytes uint64) io.ReadCloser {
+func createProgressReader(readCloser io.ReadCloser, ownerUID string, totalBytes, startBytes uint64) *prometheusutil.ProgressReader {
 	progress := prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: monitoring.MetricOptsList[monitoring.CloneProgress].Name,
@@ -104,22 +138,26 @@ func createProgressReader(readCloser io.ReadCloser, ownerUID string, totalBytes
 	prometheus.MustRegister(progress)
 
 	promReader := prometheusutil.NewProgressReader(readCloser, totalBytes, progress, ownerUID)
+	promReader.AddBytes(startBytes)
 	promReader.StartTimedUpdate()
 
 	return promReader
 }
 
-func pipeToSnappy(reader io.ReadCloser) io.ReadCloser {
+func pipeToCompressor(reader io.ReadCloser, compression string) io.ReadCloser {
 	pr, pw := io.Pipe()
-	sbw := snappy.NewBufferedWriter(pw)
+	cw, err := util.NewCloneCompressionWriter(pw, compression)
+	if err != nil {
+		klog.Fatalf("Error creating %s writer %+v", compression, err)
+	}
 
 	go func() {
-		n, err := io.Copy(sbw, reader)
+		n, err := io.Copy(cw, reader)
 		if err != nil {
-			klog.Fatalf("Error %s piping to gzip", err)
+			klog.Fatalf("Error %s piping to %s", err, compression)
 		}
-		if err = sbw.Close(); err != nil {
-			klog.Fatalf("Error closing snappy writer %+v", err)
+		if err = cw.Close(); err != nil {
+			klog.Fatalf("Error closing %s writer %+v", compression, err)
 		}
 		if err = pw.Close(); err != nil {
 			klog.Fatalf("Error closing pipe writer %+v", err)
@@ -130,6 +168,79 @@ func pipeToSnappy(reader io.ReadCloser) io.ReadCloser {
 	return pr
 }
 
+// negotiateCompression returns the compression codec of the clone stream, the one the clone source was asked for if
+// the upload server accepts it, otherwise snappy, which every upload server accepts
+func negotiateCompression(client *http.Client, uploadURL string) string {
+	compression := os.Getenv(common.ClonerCompression)
+	if compression == "" || compression == common.CloneCompressionSnappy {
+		return common.CloneCompressionSnappy
+	}
+	if err := util.ValidateCloneCompression(compression); err != nil {
+		klog.Warningf("Compressing the clone stream with snappy: %v", err)
+		return common.CloneCompressionSnappy
+	}
+	response, err := client.Head(uploadURL)
+	if err != nil {
+		klog.Warningf("Compressing the clone stream with snappy, unable to ask the upload server for its codecs: %v", err)
+		return common.CloneCompressionSnappy
+	}
+	response.Body.Close()
+	for _, accepted := range strings.Split(response.Header.Get(common.CloneCompressionAcceptHeader), ",") {
+		if accepted == compression {
+			klog.Infof("Compressing the clone stream with %s", compression)
+			return compression
+		}
+	}
+	klog.Warningf("Compressing the clone stream with snappy, the upload server does not accept %s", compression)
+	return common.CloneCompressionSnappy
+}
+
+// digestReadCloser writes the data read from the stream to the digest
+type digestReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func newDigestReadCloser(stream io.ReadCloser, digest hash.Hash) io.ReadCloser {
+	return &digestReadCloser{Reader: io.TeeReader(stream, digest), Closer: stream}
+}
+
+// checksumTrailerReader sets the SHA-256 of the cloned disk in the trailer of the request once its body is sent, the
+// digest being complete when the compressed stream ends
+type checksumTrailerReader struct {
+	io.Reader
+	req    *http.Request
+	digest hash.Hash
+}
+
+func (r *checksumTrailerReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err == io.EOF {
+		sum := hex.EncodeToString(r.digest.Sum(nil))
+		klog.Infof("Streamed the disk with SHA-256 %s", sum)
+		r.req.Trailer.Set(common.CloneSHA256Trailer, sum)
+	}
+	return n, err
+}
+
+// newCloneDigest returns the digest of the cloned disk, starting with its first offset bytes when the clone resumes
+// from offset, as the target verifies the whole disk
+func newCloneDigest(offset int64) (hash.Hash, error) {
+	digest := sha256.New()
+	if offset == 0 {
+		return digest, nil
+	}
+	f, err := os.Open(mountPoint)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if _, err := io.CopyN(digest, f, offset); err != nil {
+		return nil, err
+	}
+	return digest, nil
+}
+
 func validateContentType() {
 	switch contentType {
 	case "filesystem-clone", "blockdevice-clone":
@@ -144,6 +255,67 @@ func validateMount() {
 	}
 }
 
+// resolveSourcePath returns the single readable file of the filesystem mounted at mountPoint matching the sourcePath
+// selector, which may be a glob pattern
+func resolveSourcePath(mountPoint, sourcePath string) (string, error) {
+	if err := util.ValidateCloneSourcePath(sourcePath); err != nil {
+		return "", err
+	}
+	matches, err := filepath.Glob(filepath.Join(mountPoint, sourcePath))
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid clone source path %s", sourcePath)
+	}
+
+	var files []string
+	for _, match := range matches {
+		info, err := os.Lstat(match)
+		if err != nil {
+			return "", err
+		}
+		if info.Mode().IsRegular() {
+			files = append(files, match)
+		}
+	}
+	switch len(files) {
+	case 0:
+		return "", errors.Errorf("no file matches clone source path %s", sourcePath)
+	case 1:
+	default:
+		names := make([]string, len(files))
+		for i, f := range files {
+			names[i], _ = filepath.Rel(mountPoint, f)
+		}
+		return "", errors.Errorf("clone source path %s is ambiguous, it matches %s", sourcePath, strings.Join(names, ", "))
+	}
+
+	f, err := os.Open(files[0])
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to read clone source path %s", sourcePath)
+	}
+	f.Close()
+	return files[0], nil
+}
+
+// selectSourceDisk streams the disk selected by sourcePath, instead of the whole filesystem. The upload server
+// handles it like a disk image, converting it if needed.
+func selectSourceDisk(sourcePath string) {
+	if contentType != "filesystem-clone" {
+		klog.Fatalf("Clone source path %q requires a filesystem source", sourcePath)
+	}
+	diskPath, err := resolveSourcePath(mountPoint, sourcePath)
+	if err != nil {
+		klog.Fatalf("Error selecting the source disk: %+v", err)
+	}
+	info, err := os.Stat(diskPath)
+	if err != nil {
+		klog.Fatalf("Error reading the size of %q: %+v", diskPath, err)
+	}
+	klog.Infof("Cloning the source disk %q", diskPath)
+	contentType = "blockdevice-clone"
+	mountPoint = diskPath
+	uploadBytes = uint64(info.Size())
+}
+
 func newTarReader(preallocation bool) (io.ReadCloser, error) {
 	excludeMap := map[string]struct{}{
 		"lost+found": struct{}{},
@@ -194,7 +366,157 @@ func newTarReader(preallocation bool) (io.ReadCloser, error) {
 	return &execReader{cmd: cmd, stdout: stdout, stderr: io.NopCloser(&stderr)}, nil
 }
 
-func getInputStream(preallocation bool) (rc io.ReadCloser) {
+// resumeOffset returns the offset to resume the raw clone stream from, the offset of the checkpoint the upload server
+// kept of a previous attempt if the source still matches it, or 0 to clone from scratch
+func resumeOffset(client *http.Client, uploadURL string) int64 {
+	if contentType != "blockdevice-clone" {
+		klog.Infof("Cloning from scratch, a %s stream cannot be resumed", contentType)
+		return 0
+	}
+	checkpoint, err := getCloneCheckpoint(client, uploadURL)
+	if err != nil {
+		klog.Infof("Cloning from scratch: %v", err)
+		return 0
+	}
+	sum, err := util.Sha256sumRange(mountPoint, checkpoint.ChunkOffset, checkpoint.Offset-checkpoint.ChunkOffset)
+	if err != nil {
+		klog.Infof("Cloning from scratch, unable to check the source against the clone checkpoint: %v", err)
+		return 0
+	}
+	if sum != checkpoint.ChunkSHA256 {
+		klog.Infof("Cloning from scratch, the source does not match the clone checkpoint at offset %d", checkpoint.Offset)
+		return 0
+	}
+	klog.Infof("Resuming the clone from offset %d", checkpoint.Offset)
+	return checkpoint.Offset
+}
+
+func getCloneCheckpoint(client *http.Client, uploadURL string) (*util.CloneCheckpoint, error) {
+	checkpointURL, err := url.Parse(uploadURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid upload URL %s", uploadURL)
+	}
+	checkpointURL.Path = common.UploadPathCloneCheckpoint
+	response, err := client.Get(checkpointURL.String())
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get the clone checkpoint")
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read the clone checkpoint")
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("no clone checkpoint to resume from, status code %d: %s", response.StatusCode, strings.TrimSpace(string(body)))
+	}
+	checkpoint := &util.CloneCheckpoint{}
+	if err := json.Unmarshal(body, checkpoint); err != nil {
+		return nil, errors.Wrap(err, "unable to parse the clone checkpoint")
+	}
+	if checkpoint.Offset <= 0 || checkpoint.ChunkOffset < 0 || checkpoint.ChunkOffset >= checkpoint.Offset {
+		return nil, errors.Errorf("invalid clone checkpoint at offset %d", checkpoint.Offset)
+	}
+	return checkpoint, nil
+}
+
+// getTargetBlocks returns the block checksums of the target of an incremental clone, or nil to clone the whole disk.
+// Only a raw block device is cloned incrementally, to a raw block device target.
+func getTargetBlocks(client *http.Client, uploadURL, sourcePath string) *util.CloneBlockChecksums {
+	if os.Getenv(common.ClonerIncremental) != "true" {
+		return nil
+	}
+	if contentType != "blockdevice-clone" || sourcePath != "" {
+		klog.Infof("Cloning the whole disk, only a block device is cloned incrementally")
+		return nil
+	}
+	size, err := blockDeviceSize(mountPoint)
+	if err != nil {
+		klog.Infof("Cloning the whole disk: %v", err)
+		return nil
+	}
+	blocks, err := getCloneBlocks(client, uploadURL, size)
+	if err != nil {
+		klog.Infof("Cloning the whole disk: %v", err)
+		return nil
+	}
+	return blocks
+}
+
+func getCloneBlocks(client *http.Client, uploadURL string, size int64) (*util.CloneBlockChecksums, error) {
+	blocksURL, err := url.Parse(uploadURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid upload URL %s", uploadURL)
+	}
+	blocksURL.Path = common.UploadPathCloneBlocks
+	blocksURL.RawQuery = url.Values{"size": []string{strconv.FormatInt(size, 10)}}.Encode()
+	response, err := client.Get(blocksURL.String())
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get the target block checksums")
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read the target block checksums")
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("no target block checksums, status code %d: %s", response.StatusCode, strings.TrimSpace(string(body)))
+	}
+	blocks := &util.CloneBlockChecksums{}
+	if err := json.Unmarshal(body, blocks); err != nil {
+		return nil, errors.Wrap(err, "unable to parse the target block checksums")
+	}
+	if blocks.Size != size {
+		return nil, errors.Errorf("the target block checksums cover %d bytes, the source has %d", blocks.Size, size)
+	}
+	return blocks, nil
+}
+
+// changedRanges returns the ranges of the block device whose blocks differ from the target ones, and the SHA-256 of
+// the whole block device the target is verified with once the ranges are written
+func changedRanges(targetBlocks *util.CloneBlockChecksums) ([]util.CloneRange, hash.Hash, error) {
+	f, err := os.Open(mountPoint)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "unable to open block device %s", mountPoint)
+	}
+	defer f.Close()
+	digest := sha256.New()
+	sourceBlocks, err := util.ComputeCloneBlockChecksums(io.TeeReader(f, digest), targetBlocks.Size, targetBlocks.BlockSize)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "unable to compute the block checksums of %s", mountPoint)
+	}
+	ranges, err := sourceBlocks.ChangedRanges(targetBlocks)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ranges, digest, nil
+}
+
+func changedBytes(ranges []util.CloneRange) int64 {
+	var changed int64
+	for _, cloneRange := range ranges {
+		changed += cloneRange.Length()
+	}
+	return changed
+}
+
+// cloneIncremental streams the changed ranges of the block device one after the other, then ends the clone with the
+// SHA-256 of the whole block device, which the upload server checks the target against
+func cloneIncremental(client *http.Client, uploadURL string, ranges []util.CloneRange, size int64, digest hash.Hash, bandwidthLimit int64, compression string, progress *prometheusutil.ProgressReader) error {
+	klog.Infof("Cloning incrementally %d changed bytes in %d ranges of %d bytes", changedBytes(ranges), len(ranges), size)
+	for _, cloneRange := range ranges {
+		if err := streamCloneRange(client, uploadURL, cloneRange, bandwidthLimit, compression, true, &sharedProgressReader{progress: progress}); err != nil {
+			return err
+		}
+	}
+	header := http.Header{}
+	header.Set(common.CloneIncrementalHeader, strconv.FormatInt(size, 10))
+	if err := postCloneStream(client, uploadURL, strings.NewReader(""), digest, header); err != nil {
+		return errors.Wrap(err, "unable to end the incremental clone")
+	}
+	return nil
+}
+
+func getInputStream(preallocation bool, offset int64) (rc io.ReadCloser) {
 	var err error
 	switch contentType {
 	case "filesystem-clone":
@@ -203,10 +525,15 @@ func getInputStream(preallocation bool) (rc io.ReadCloser) {
 			klog.Fatalf("Error creating tar reader for %q: %+v", mountPoint, err)
 		}
 	case "blockdevice-clone":
-		rc, err = os.Open(mountPoint)
+		var f *os.File
+		f, err = os.Open(mountPoint)
 		if err != nil {
 			klog.Fatalf("Error opening block device %q: %+v", mountPoint, err)
 		}
+		if _, err = f.Seek(offset, io.SeekStart); err != nil {
+			klog.Fatalf("Error seeking block device %q to offset %d: %+v", mountPoint, offset, err)
+		}
+		rc = f
 	default:
 		klog.Fatalf("Invalid content-type %q", contentType)
 	}
@@ -215,6 +542,7 @@ func getInputStream(preallocation bool) (rc io.ReadCloser) {
 
 func main() {
 	flag.Parse()
+	logging.SetupFromEnv()
 	defer klog.Flush()
 
 	klog.Infof("content-type is %q\n", contentType)
@@ -223,28 +551,106 @@ func main() {
 
 	validateContentType()
 	validateMount()
+	sourcePath := os.Getenv(common.ClonerSourcePath)
+	if sourcePath != "" {
+		selectSourceDisk(sourcePath)
+	}
+	cgroup.ApplyIOLimitsFromEnv(mountPoint)
 
 	ownerUID := getEnvVarOrDie(common.OwnerUID)
 
-	clientKey := []byte(getEnvVarOrDie("CLIENT_KEY"))
-	clientCert := []byte(getEnvVarOrDie("CLIENT_CERT"))
 	serverCert := []byte(getEnvVarOrDie("SERVER_CA_CERT"))
 
-	url := getEnvVarOrDie("UPLOAD_URL")
+	uploadURL := getEnvVarOrDie("UPLOAD_URL")
 	preallocation, err := strconv.ParseBool(getEnvVarOrDie(common.Preallocation)) // False is default in case of error
 	if err != nil {
 		klog.V(3).Infof("Preallocation variable (%s) not set, defaulting to 'false'", common.Preallocation)
 	}
 
 	klog.V(1).Infoln("Starting cloner target")
+	logging.Lifecycle(logging.EventStart, logging.FieldBytes, uploadBytes)
 
-	reader := pipeToSnappy(createProgressReader(getInputStream(preallocation), ownerUID, uploadBytes))
+	var client *http.Client
+	if uploadToken := os.Getenv(common.ClonerUploadToken); uploadToken != "" {
+		klog.V(1).Infoln("Cloning to a remote cluster through its upload proxy")
+		client = createTokenHTTPClient(uploadToken, serverCert)
+	} else {
+		clientKey := []byte(getEnvVarOrDie("CLIENT_KEY"))
+		clientCert := []byte(getEnvVarOrDie("CLIENT_CERT"))
+		client = createHTTPClient(clientKey, clientCert, serverCert)
+	}
+	bandwidthLimit, err := util.ParseBandwidthLimit(os.Getenv(common.ClonerBandwidthLimit))
+	if err != nil {
+		klog.Fatalf("Error parsing the bandwidth limit: %+v", err)
+	}
+	if bandwidthLimit > 0 {
+		klog.Infof("Limiting the clone stream to %d bytes per second", bandwidthLimit)
+	}
 
-	startPrometheus()
+	compression := negotiateCompression(client, uploadURL)
 
-	client := createHTTPClient(clientKey, clientCert, serverCert)
+	if targetBlocks := getTargetBlocks(client, uploadURL, sourcePath); targetBlocks != nil {
+		ranges, digest, err := changedRanges(targetBlocks)
+		if err != nil {
+			failClone(err)
+		}
+		progress := createProgressReader(io.NopCloser(strings.NewReader("")), ownerUID, uint64(changedBytes(ranges)), 0)
+		startStreaming(sourcePath)
+		if err := cloneIncremental(client, uploadURL, ranges, targetBlocks.Size, digest, bandwidthLimit, compression, progress); err != nil {
+			failClone(err)
+		}
+	} else if streams := cloneStreams(sourcePath); streams > 1 {
+		progress := createProgressReader(io.NopCloser(strings.NewReader("")), ownerUID, uploadBytes, 0)
+		startStreaming(sourcePath)
+		if err := cloneParallel(client, uploadURL, streams, bandwidthLimit, compression, progress); err != nil {
+			failClone(err)
+		}
+	} else {
+		offset := resumeOffset(client, uploadURL)
+
+		digest, err := newCloneDigest(offset)
+		if err != nil {
+			klog.Fatalf("Error hashing %q up to offset %d: %+v", mountPoint, offset, err)
+		}
+		reader := pipeToCompressor(createProgressReader(newDigestReadCloser(getInputStream(preallocation, offset), digest), ownerUID, uploadBytes, uint64(offset)), compression)
+		startStreaming(sourcePath)
+
+		header := cloneCompressionHeader(compression)
+		if offset > 0 {
+			header.Set(common.CloneOffsetHeader, strconv.FormatInt(offset, 10))
+		}
+		// The limit applies to the compressed stream, the bytes actually sent over the network
+		if err := postCloneStream(client, uploadURL, util.NewThrottledReader(reader, bandwidthLimit), digest, header); err != nil {
+			failClone(err)
+		}
+	}
 
-	req, _ := http.NewRequest("POST", url, reader)
+	klog.V(1).Infoln("clone complete")
+	prometheusutil.SetPhase("Complete")
+	logging.Lifecycle(logging.EventComplete, logging.FieldBytes, uploadBytes)
+	err = util.WriteTerminationMessage(cloneCompleteTerminationMessage(preallocation, clonedDiskSize(sourcePath)))
+	if err != nil {
+		klog.Errorf("%+v", err)
+		os.Exit(1)
+	}
+}
+
+func startStreaming(sourcePath string) {
+	prometheusutil.SetSourceMetadata("contentType", contentType)
+	prometheusutil.SetSourceMetadata("sourcePath", sourcePath)
+	prometheusutil.SetPhase("Streaming")
+	startPrometheus()
+}
+
+// postCloneStream POSTs the clone stream read from body to the upload server, setting the SHA-256 of the data written
+// to digest in the trailer of the request
+func postCloneStream(client *http.Client, uploadURL string, body io.Reader, digest hash.Hash, header http.Header) error {
+	req, _ := http.NewRequest("POST", uploadURL, nil)
+	req.Body = io.NopCloser(&checksumTrailerReader{Reader: body, req: req, digest: digest})
+	req.Trailer = http.Header{common.CloneSHA256Trailer: nil}
+	for key, values := range header {
+		req.Header[key] = values
+	}
 
 	if contentType != "" {
 		req.Header.Set("x-cdi-content-type", contentType)
@@ -253,29 +659,177 @@ func main() {
 
 	response, err := client.Do(req)
 	if err != nil {
-		klog.Fatalf("Error %s POSTing to %s", err, url)
+		return errors.Wrapf(err, "Error POSTing to %s", uploadURL)
 	}
+	defer response.Body.Close()
 
+	responseBody, err := io.ReadAll(response.Body)
 	if response.StatusCode < 200 || response.StatusCode >= 300 {
-		klog.Fatalf("Unexpected status code %d", response.StatusCode)
+		if message := strings.TrimSpace(string(responseBody)); message != "" {
+			return errors.Errorf("Unexpected status code %d: %s", response.StatusCode, message)
+		}
+		return errors.Errorf("Unexpected status code %d", response.StatusCode)
 	}
-
-	var buf bytes.Buffer
-	_, err = io.Copy(&buf, response.Body)
 	if err != nil {
 		klog.Fatalf("Error %s copying response body", err)
 	}
 
-	klog.V(1).Infof("Response body:\n%s", buf.String())
+	klog.V(1).Infof("Response body:\n%s", string(responseBody))
+	return nil
+}
 
-	klog.V(1).Infoln("clone complete")
+// cloneCompressionHeader returns the header of a clone stream compressed with compression, snappy being implied
+func cloneCompressionHeader(compression string) http.Header {
+	header := http.Header{}
+	if compression != common.CloneCompressionSnappy {
+		header.Set(common.CloneCompressionHeader, compression)
+	}
+	return header
+}
+
+// cloneStreams returns the number of parallel streams to clone the source with. Only a raw block device is split, the
+// other sources are cloned over a single stream.
+func cloneStreams(sourcePath string) int {
+	value := os.Getenv(common.ClonerStreams)
+	if value == "" {
+		return 1
+	}
+	streams, err := strconv.Atoi(value)
+	if err != nil || streams < 1 || streams > common.MaxCloneStreams {
+		klog.Warningf("Cloning over a single stream, invalid number of clone streams %q", value)
+		return 1
+	}
+	if streams > 1 && (contentType != "blockdevice-clone" || sourcePath != "") {
+		klog.Infof("Cloning over a single stream, only a block device is split into parallel streams")
+		return 1
+	}
+	return streams
+}
+
+// sharedProgressReader adds the bytes read by one of the parallel streams to the progress of the clone
+type sharedProgressReader struct {
+	io.Reader
+	progress *prometheusutil.ProgressReader
+}
+
+func (r *sharedProgressReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.progress.AddBytes(uint64(n))
+	return n, err
+}
+
+// cloneParallel splits the block device into ranges streamed in parallel, each over its own connection to the upload
+// server with the SHA-256 of its range. The bandwidth limit is shared evenly between the streams, each getting at
+// least the minimum limit. A parallel clone is not resumed, all the ranges are streamed again after a failure.
+func cloneParallel(client *http.Client, uploadURL string, streams int, bandwidthLimit int64, compression string, progress *prometheusutil.ProgressReader) error {
+	size, err := blockDeviceSize(mountPoint)
+	if err != nil {
+		return err
+	}
+	ranges := util.SplitCloneRanges(size, streams)
+	klog.Infof("Cloning %d bytes over %d parallel streams", size, len(ranges))
+
+	rangeLimit := rangeBandwidthLimit(bandwidthLimit, len(ranges))
+	errs := make(chan error, len(ranges))
+	for _, cloneRange := range ranges {
+		go func(cloneRange util.CloneRange) {
+			errs <- streamCloneRange(client, uploadURL, cloneRange, rangeLimit, compression, false, &sharedProgressReader{progress: progress})
+		}(cloneRange)
+	}
+	for range ranges {
+		// The first failure fails the clone, the other streams are interrupted when the pod exits
+		if err := <-errs; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rangeBandwidthLimit returns the bandwidth limit of each of the parallel streams sharing bandwidthLimit, not below the
+// minimum limit a stream can be given
+func rangeBandwidthLimit(bandwidthLimit int64, streams int) int64 {
+	if bandwidthLimit <= 0 {
+		return 0
+	}
+	limit := bandwidthLimit / int64(streams)
+	if limit < util.MinBandwidthLimit {
+		return util.MinBandwidthLimit
+	}
+	return limit
+}
+
+func blockDeviceSize(path string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to open block device %s", path)
+	}
+	defer f.Close()
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to read the size of %s", path)
+	}
+	return size, nil
+}
+
+// streamCloneRange streams a range of the block device with its SHA-256. The range of an incremental clone does not
+// complete the clone, its final request does.
+func streamCloneRange(client *http.Client, uploadURL string, cloneRange util.CloneRange, bandwidthLimit int64, compression string, incremental bool, progress *sharedProgressReader) error {
+	f, err := os.Open(mountPoint)
+	if err != nil {
+		return errors.Wrapf(err, "unable to open block device %s", mountPoint)
+	}
+	defer f.Close()
+
+	digest := sha256.New()
+	progress.Reader = io.NewSectionReader(f, cloneRange.Start, cloneRange.Length())
+	reader := pipeToCompressor(newDigestReadCloser(io.NopCloser(progress), digest), compression)
+	header := cloneCompressionHeader(compression)
+	header.Set(common.CloneRangeHeader, cloneRange.String())
+	if incremental {
+		header.Set(common.CloneIncrementalHeader, strconv.FormatInt(cloneRange.Size, 10))
+	}
+	klog.Infof("Streaming the range %s", cloneRange)
+	if err := postCloneStream(client, uploadURL, util.NewThrottledReader(reader, bandwidthLimit), digest, header); err != nil {
+		return errors.Wrapf(err, "unable to stream the range %s", cloneRange)
+	}
+	return nil
+}
+
+// clonedDiskSize returns the size of the raw disk image streamed to the target, 0 if it is not known. The disk of a
+// filesystem source is its disk.img file.
+func clonedDiskSize(sourcePath string) int64 {
+	if sourcePath != "" {
+		// The selected disk can be in any format, the upload server converts it
+		return 0
+	}
+	if contentType == "blockdevice-clone" {
+		return int64(uploadBytes)
+	}
+	info, err := os.Stat(filepath.Join(mountPoint, common.DiskImageName))
+	if err != nil || !info.Mode().IsRegular() {
+		return 0
+	}
+	return info.Size()
+}
+
+func cloneCompleteTerminationMessage(preallocation bool, diskSize int64) string {
 	message := "Clone Complete"
 	if preallocation {
 		message += ", " + common.PreallocationApplied
 	}
-	err = util.WriteTerminationMessage(message)
-	if err != nil {
+	if diskSize > 0 {
+		info, _ := json.Marshal(util.ImageInfo{VirtualSize: diskSize})
+		message += "; " + common.ImageInfoPrefix + string(info)
+	}
+	return message
+}
+
+func failClone(err error) {
+	prometheusutil.SetLastError(err)
+	logging.LifecycleError(err)
+	if err := util.WriteTerminationMessage(err.Error()); err != nil {
 		klog.Errorf("%+v", err)
-		os.Exit(1)
 	}
+	klog.Flush()
+	os.Exit(1)
 }
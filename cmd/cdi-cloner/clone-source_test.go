@@ -1,13 +1,28 @@
 package main
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
 
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/util"
 	prometheusutil "kubevirt.io/containerized-data-importer/pkg/util/prometheus"
 )
 
@@ -28,6 +43,360 @@ var _ = Describe("Prometheus Endpoint", func() {
 	})
 })
 
+var _ = Describe("Clone source path", func() {
+	var mountPoint string
+
+	BeforeEach(func() {
+		var err error
+		mountPoint, err = os.MkdirTemp("", "clone-source")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.MkdirAll(filepath.Join(mountPoint, "disks", "vm1.img.d"), 0755)).To(Succeed())
+		for _, name := range []string{"disks/vm1.qcow2", "disks/vm2.qcow2", "disks/vm3.img"} {
+			Expect(os.WriteFile(filepath.Join(mountPoint, name), []byte(name), 0644)).To(Succeed())
+		}
+		Expect(os.Symlink("vm3.img", filepath.Join(mountPoint, "disks", "vm3-link.img"))).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(mountPoint)
+	})
+
+	table.DescribeTable("should select a single disk", func(sourcePath, expected string) {
+		diskPath, err := resolveSourcePath(mountPoint, sourcePath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(diskPath).To(Equal(filepath.Join(mountPoint, expected)))
+	},
+		table.Entry("by name", "disks/vm1.qcow2", "disks/vm1.qcow2"),
+		table.Entry("by pattern", "disks/vm2*", "disks/vm2.qcow2"),
+		table.Entry("ignoring directories and symlinks", "disks/vm*.img*", "disks/vm3.img"),
+	)
+
+	table.DescribeTable("should fail", func(sourcePath, errString string) {
+		_, err := resolveSourcePath(mountPoint, sourcePath)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(errString))
+	},
+		table.Entry("when nothing matches", "disks/vm4.qcow2", "no file matches clone source path disks/vm4.qcow2"),
+		table.Entry("when only a directory matches", "disks/vm1.img.d", "no file matches"),
+		table.Entry("when several files match", "disks/*.qcow2", "ambiguous, it matches disks/vm1.qcow2, disks/vm2.qcow2"),
+		table.Entry("when the path is outside the volume", "../disks/vm1.qcow2", "must be inside the source volume"),
+	)
+
+	It("should fail if the disk is not readable", func() {
+		if os.Geteuid() == 0 {
+			Skip("root can read any file")
+		}
+		Expect(os.Chmod(filepath.Join(mountPoint, "disks", "vm1.qcow2"), 0)).To(Succeed())
+		_, err := resolveSourcePath(mountPoint, "disks/vm1.qcow2")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("unable to read clone source path"))
+	})
+})
+
+var _ = Describe("Clone complete termination message", func() {
+	var savedContentType, savedMountPoint string
+
+	BeforeEach(func() {
+		savedContentType, savedMountPoint = contentType, mountPoint
+		var err error
+		mountPoint, err = os.MkdirTemp("", "clone-source")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(mountPoint)
+		contentType, mountPoint = savedContentType, savedMountPoint
+	})
+
+	It("should report the size of the disk image of a filesystem source", func() {
+		contentType = "filesystem-clone"
+		Expect(os.WriteFile(filepath.Join(mountPoint, "disk.img"), make([]byte, 4096), 0644)).To(Succeed())
+		message := cloneCompleteTerminationMessage(true, clonedDiskSize(""))
+		Expect(message).To(Equal(`Clone Complete, Preallocation applied; Image: {"VirtualSize":4096}`))
+	})
+
+	It("should not report a size when the source has no disk image or a disk is selected", func() {
+		contentType = "filesystem-clone"
+		Expect(clonedDiskSize("")).To(BeZero())
+		Expect(os.WriteFile(filepath.Join(mountPoint, "disk.img"), make([]byte, 4096), 0644)).To(Succeed())
+		Expect(clonedDiskSize("disk.img")).To(BeZero())
+		Expect(cloneCompleteTerminationMessage(false, 0)).To(Equal("Clone Complete"))
+	})
+})
+
+var _ = Describe("Clone resume", func() {
+	const chunkSize = 1024 * 1024
+
+	var (
+		savedContentType, savedMountPoint string
+		tmpDir                            string
+		source                            []byte
+		checkpoint                        util.CloneCheckpoint
+		checkpointStatus                  int
+		server                            *httptest.Server
+	)
+
+	BeforeEach(func() {
+		savedContentType, savedMountPoint = contentType, mountPoint
+		var err error
+		tmpDir, err = os.MkdirTemp("", "clone-source")
+		Expect(err).ToNot(HaveOccurred())
+		source = bytes.Repeat([]byte("source data"), 3*chunkSize/10)
+		mountPoint = filepath.Join(tmpDir, "source.img")
+		Expect(os.WriteFile(mountPoint, source, 0644)).To(Succeed())
+		contentType = "blockdevice-clone"
+
+		sum := sha256.Sum256(source[chunkSize : 2*chunkSize])
+		checkpoint = util.CloneCheckpoint{Offset: 2 * chunkSize, ChunkOffset: chunkSize, ChunkSHA256: hex.EncodeToString(sum[:])}
+		checkpointStatus = http.StatusOK
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != common.UploadPathCloneCheckpoint {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(checkpointStatus)
+			if checkpointStatus == http.StatusOK {
+				json.NewEncoder(w).Encode(checkpoint)
+			}
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+		os.RemoveAll(tmpDir)
+		contentType, mountPoint = savedContentType, savedMountPoint
+	})
+
+	It("should resume from the checkpoint matching the source, skipping the bytes already copied", func() {
+		offset := resumeOffset(server.Client(), server.URL+common.UploadPathSync)
+		Expect(offset).To(Equal(int64(2 * chunkSize)))
+		stream := getInputStream(false, offset)
+		defer stream.Close()
+		remaining, err := io.ReadAll(stream)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(remaining).To(Equal(source[2*chunkSize:]))
+	})
+
+	table.DescribeTable("should clone from scratch", func(setup func()) {
+		setup()
+		Expect(resumeOffset(server.Client(), server.URL+common.UploadPathSync)).To(BeZero())
+	},
+		table.Entry("a filesystem", func() {
+			contentType = "filesystem-clone"
+		}),
+		table.Entry("without checkpoint", func() {
+			checkpointStatus = http.StatusNotFound
+		}),
+		table.Entry("when the source does not match the checkpoint", func() {
+			checkpoint.ChunkSHA256 = strings.Repeat("0", 64)
+		}),
+		table.Entry("when the checkpoint is after the end of the source", func() {
+			checkpoint.Offset = 4 * chunkSize
+		}),
+	)
+})
+
+var _ = Describe("Parallel clone streams", func() {
+	const mi = 1024 * 1024
+
+	var savedContentType, savedMountPoint string
+
+	BeforeEach(func() {
+		savedContentType, savedMountPoint = contentType, mountPoint
+		contentType = "blockdevice-clone"
+	})
+
+	AfterEach(func() {
+		contentType, mountPoint = savedContentType, savedMountPoint
+		os.Unsetenv(common.ClonerStreams)
+	})
+
+	table.DescribeTable("should clone over", func(value, sourcePath string, setup func(), expected int) {
+		if value != "" {
+			os.Setenv(common.ClonerStreams, value)
+		}
+		if setup != nil {
+			setup()
+		}
+		Expect(cloneStreams(sourcePath)).To(Equal(expected))
+	},
+		table.Entry("a single stream by default", "", "", nil, 1),
+		table.Entry("parallel streams for a block device", "4", "", nil, 4),
+		table.Entry("a single stream for a filesystem", "4", "", func() { contentType = "filesystem-clone" }, 1),
+		table.Entry("a single stream for a selected disk", "4", "disk.img", nil, 1),
+		table.Entry("a single stream when too many are requested", "17", "", nil, 1),
+		table.Entry("a single stream when invalid", "many", "", nil, 1),
+	)
+
+	It("should stream the compressed ranges of the block device with their checksum", func() {
+		tmpDir, err := os.MkdirTemp("", "clone-source")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(tmpDir)
+		source := bytes.Repeat([]byte("source disk data"), 3*mi/16)
+		mountPoint = filepath.Join(tmpDir, "source.img")
+		Expect(os.WriteFile(mountPoint, source, 0644)).To(Succeed())
+
+		var mutex sync.Mutex
+		cloned := make([]byte, len(source))
+		ranges := map[string]bool{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			cloneRange, err := util.ParseCloneRange(r.Header.Get(common.CloneRangeHeader))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Header.Get(common.CloneCompressionHeader)).To(Equal(common.CloneCompressionZstd))
+			reader, err := util.NewCloneDecompressionReader(r.Body, common.CloneCompressionZstd)
+			Expect(err).ToNot(HaveOccurred())
+			data, err := io.ReadAll(reader)
+			Expect(err).ToNot(HaveOccurred())
+			sum := sha256.Sum256(data)
+			Expect(r.Trailer.Get(common.CloneSHA256Trailer)).To(Equal(hex.EncodeToString(sum[:])))
+			mutex.Lock()
+			defer mutex.Unlock()
+			copy(cloned[cloneRange.Start:cloneRange.End], data)
+			ranges[cloneRange.String()] = true
+		}))
+		defer server.Close()
+
+		progressCounter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_clone_progress"}, []string{"ownerUID"})
+		progress := prometheusutil.NewProgressReader(io.NopCloser(strings.NewReader("")), uint64(len(source)), progressCounter, "uid")
+		Expect(cloneParallel(server.Client(), server.URL+common.UploadPathSync, 3, 0, common.CloneCompressionZstd, progress)).To(Succeed())
+		Expect(ranges).To(HaveLen(3))
+		Expect(cloned).To(Equal(source))
+		Expect(progress.BytesRead()).To(Equal(uint64(len(source))))
+	})
+
+	table.DescribeTable("should share the bandwidth limit between the streams", func(bandwidthLimit int64, expected int64) {
+		Expect(rangeBandwidthLimit(bandwidthLimit, 4)).To(Equal(expected))
+	},
+		table.Entry("without limit", int64(0), int64(0)),
+		table.Entry("evenly", int64(100*mi), int64(25*mi)),
+		table.Entry("not below the minimum limit", int64(util.MinBandwidthLimit), int64(util.MinBandwidthLimit)),
+	)
+})
+
+var _ = Describe("Incremental clone", func() {
+	const blockSize = 4
+
+	var savedMountPoint string
+
+	BeforeEach(func() {
+		savedMountPoint = mountPoint
+	})
+
+	AfterEach(func() {
+		mountPoint = savedMountPoint
+	})
+
+	It("should only stream the changed blocks, then end with the checksum of the whole disk", func() {
+		tmpDir, err := os.MkdirTemp("", "clone-source")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(tmpDir)
+		source := []byte("aaaaXXXXccccYYYYee")
+		cloned := []byte("aaaabbbbccccddddee")
+		mountPoint = filepath.Join(tmpDir, "source.img")
+		Expect(os.WriteFile(mountPoint, source, 0644)).To(Succeed())
+		targetBlocks, err := util.ComputeCloneBlockChecksums(bytes.NewReader(cloned), int64(len(cloned)), blockSize)
+		Expect(err).ToNot(HaveOccurred())
+
+		ended := false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			Expect(r.Header.Get(common.CloneIncrementalHeader)).To(Equal(strconv.Itoa(len(source))))
+			if r.Header.Get(common.CloneRangeHeader) == "" {
+				data, err := io.ReadAll(r.Body)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(data).To(BeEmpty())
+				Expect(cloned).To(Equal(source))
+				sum := sha256.Sum256(cloned)
+				Expect(r.Trailer.Get(common.CloneSHA256Trailer)).To(Equal(hex.EncodeToString(sum[:])))
+				ended = true
+				return
+			}
+			reader, err := util.NewCloneDecompressionReader(r.Body, common.CloneCompressionZstd)
+			Expect(err).ToNot(HaveOccurred())
+			cloneRange, err := util.ParseCloneRange(r.Header.Get(common.CloneRangeHeader))
+			Expect(err).ToNot(HaveOccurred())
+			data, err := io.ReadAll(reader)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(data).To(HaveLen(blockSize))
+			copy(cloned[cloneRange.Start:cloneRange.End], data)
+		}))
+		defer server.Close()
+
+		ranges, digest, err := changedRanges(targetBlocks)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ranges).To(HaveLen(2))
+		Expect(changedBytes(ranges)).To(Equal(int64(2 * blockSize)))
+		progressCounter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_incremental_clone_progress"}, []string{"ownerUID"})
+		progress := prometheusutil.NewProgressReader(io.NopCloser(strings.NewReader("")), uint64(changedBytes(ranges)), progressCounter, "uid")
+		Expect(cloneIncremental(server.Client(), server.URL+common.UploadPathSync, ranges, int64(len(source)), digest, 0, common.CloneCompressionZstd, progress)).To(Succeed())
+		Expect(ended).To(BeTrue())
+		Expect(progress.Current).To(Equal(uint64(2 * blockSize)))
+	})
+
+	It("should clone the whole disk when the target has no block checksums", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+		_, err := getCloneBlocks(server.Client(), server.URL+common.UploadPathSync, 16)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Clone compression", func() {
+	AfterEach(func() {
+		os.Unsetenv(common.ClonerCompression)
+	})
+
+	table.DescribeTable("should compress the clone stream with", func(requested, accepted string, expected string) {
+		if requested != "" {
+			os.Setenv(common.ClonerCompression, requested)
+		}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			Expect(r.Method).To(Equal("HEAD"))
+			if accepted != "" {
+				w.Header().Set(common.CloneCompressionAcceptHeader, accepted)
+			}
+		}))
+		defer server.Close()
+		Expect(negotiateCompression(server.Client(), server.URL+common.UploadPathSync)).To(Equal(expected))
+	},
+		table.Entry("snappy by default", "", "none,gzip,snappy,zstd", common.CloneCompressionSnappy),
+		table.Entry("the requested codec the upload server accepts", "zstd", "none,gzip,snappy,zstd", common.CloneCompressionZstd),
+		table.Entry("no compression when requested", "none", "none,gzip,snappy,zstd", common.CloneCompressionNone),
+		table.Entry("snappy when the upload server does not accept the requested codec", "gzip", "snappy,zstd", common.CloneCompressionSnappy),
+		table.Entry("snappy when the upload server does not list its codecs", "zstd", "", common.CloneCompressionSnappy),
+		table.Entry("snappy when the requested codec is invalid", "lz4", "none,gzip,snappy,zstd", common.CloneCompressionSnappy),
+	)
+
+	It("should compress the clone stream with snappy when the upload server cannot be asked", func() {
+		os.Setenv(common.ClonerCompression, common.CloneCompressionZstd)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		server.Close()
+		Expect(negotiateCompression(server.Client(), server.URL+common.UploadPathSync)).To(Equal(common.CloneCompressionSnappy))
+	})
+})
+
+var _ = Describe("Remote clone source", func() {
+	It("should authenticate to the upload proxy with the upload token, trusting its CA", func() {
+		var authorization string
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization = r.Header.Get("Authorization")
+		}))
+		defer server.Close()
+		serverCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+		client := createTokenHTTPClient("upload-token", serverCert)
+		response, err := client.Post(server.URL+common.UploadPathSync, "", strings.NewReader("data"))
+		Expect(err).ToNot(HaveOccurred())
+		response.Body.Close()
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+		Expect(authorization).To(Equal("Bearer upload-token"))
+	})
+})
+
 func isDirEmpty(dirName string) (bool, error) {
 	f, err := os.Open(dirName)
 	if err != nil {
@@ -40,6 +40,7 @@ import (
 	"kubevirt.io/containerized-data-importer/pkg/util/cert"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert/fetcher"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert/generator"
+	"kubevirt.io/containerized-data-importer/pkg/util/logging"
 )
 
 const (
@@ -78,6 +79,7 @@ type ControllerEnvs struct {
 	UploadClientCertFile          string `default:"/var/run/certs/cdi-uploadserver-client-signer/tls.crt" split_words:"true"`
 	UploadServerCaBundleConfigMap string `default:"cdi-uploadserver-signer-bundle" split_words:"true"`
 	UploadClientCaBundleConfigMap string `default:"cdi-uploadserver-client-signer-bundle" split_words:"true"`
+	UploadProxyCaBundleConfigMap  string `default:"cdi-uploadproxy-signer-bundle" split_words:"true"`
 }
 
 // The importer and cloner images are obtained here along with the supported flags. IMPORTER_IMAGE, CLONER_IMAGE, and UPLOADSERVICE_IMAGE
@@ -89,6 +91,7 @@ func init() {
 	flag.StringVar(&kubeURL, "server", "", "(Optional) URL address of a remote api server.  Do not set for local clusters.")
 	klog.InitFlags(nil)
 	flag.Parse()
+	logging.SetupFromEnv()
 
 	if flag.Lookup("kubeconfig") != nil {
 		kubeconfig = flag.Lookup("kubeconfig").Value.String()
@@ -197,6 +200,11 @@ func start() {
 	}
 	uploadServerCertGenerator := &generator.FetchCertGenerator{Fetcher: uploadServerCAFetcher}
 
+	uploadProxyBundleFetcher := &fetcher.ConfigMapCertBundleFetcher{
+		Name:   controllerEnvs.UploadProxyCaBundleConfigMap,
+		Client: client.CoreV1().ConfigMaps(namespace),
+	}
+
 	if _, err := controller.NewConfigController(mgr, log, uploadProxyServiceName, configName, installerLabels); err != nil {
 		klog.Errorf("Unable to setup config controller: %v", err)
 		os.Exit(1)
@@ -233,16 +241,34 @@ func start() {
 		klog.Errorf("Unable to setup datavolume snapshot clone controller: %v", err)
 		os.Exit(1)
 	}
+	if _, err := dvc.NewRemotePvcCloneController(ctx, mgr, log,
+		clonerImage, pullPolicy, getTokenPrivateKey(), uploadProxyBundleFetcher, installerLabels); err != nil {
+		klog.Errorf("Unable to setup datavolume remote pvc clone controller: %v", err)
+		os.Exit(1)
+	}
 	if _, err := dvc.NewPopulatorController(ctx, mgr, log, installerLabels); err != nil {
 		klog.Errorf("Unable to setup datavolume external-population controller: %v", err)
 		os.Exit(1)
 	}
+	if err := dvc.AddLeaderMetrics(mgr); err != nil {
+		klog.Errorf("Unable to setup datavolume leader metrics: %v", err)
+		os.Exit(1)
+	}
 
+	if err := controller.CreateWorkerPodQueueIndexes(mgr); err != nil {
+		klog.Errorf("Unable to create worker pod queue indexes: %v", err)
+		os.Exit(1)
+	}
 	if _, err := controller.NewImportController(mgr, log, importerImage, pullPolicy, verbose, installerLabels); err != nil {
 		klog.Errorf("Unable to setup import controller: %v", err)
 		os.Exit(1)
 	}
 
+	if _, err := controller.NewImportVerifyController(mgr, log, importerImage, pullPolicy, verbose, installerLabels); err != nil {
+		klog.Errorf("Unable to setup import verify controller: %v", err)
+		os.Exit(1)
+	}
+
 	if _, err := controller.NewCloneController(mgr, log, clonerImage, pullPolicy, verbose, uploadClientCertGenerator, uploadServerBundleFetcher, getTokenPublicKey(), installerLabels); err != nil {
 		klog.Errorf("Unable to setup clone controller: %v", err)
 		os.Exit(1)
@@ -266,6 +292,10 @@ func start() {
 		klog.Errorf("Unable to setup datasource controller: %v", err)
 		os.Exit(1)
 	}
+	if _, err := controller.NewScratchGCController(mgr, log); err != nil {
+		klog.Errorf("Unable to setup scratch PVC garbage collection controller: %v", err)
+		os.Exit(1)
+	}
 
 	klog.V(1).Infoln("created cdi controllers")
 
@@ -287,7 +317,9 @@ func main() {
 		klog.Fatalf("Unable to get environment variables: %v\n", errors.WithStack(err))
 	}
 
-	logf.SetLogger(zap.New(zap.Level(zapcore.Level(-1*verbosityLevel)), zap.UseDevMode(debug)))
+	// The production encoder of the controller-runtime logger is JSON already
+	jsonLogs := logging.GetFormat() == logging.FormatJSON
+	logf.SetLogger(zap.New(zap.Level(zapcore.Level(-1*verbosityLevel)), zap.UseDevMode(debug && !jsonLogs)))
 	logf.Log.WithName("main").Info("Verbosity level", "verbose", verbose, "debug", debug)
 
 	if err = createReadyFile(); err != nil {
@@ -351,6 +383,7 @@ func registerMetrics() {
 	metrics.Registry.MustRegister(controller.IncompleteProfileGauge)
 	controller.IncompleteProfileGauge.Set(-1)
 	metrics.Registry.MustRegister(controller.DataImportCronOutdatedGauge)
+	metrics.Registry.MustRegister(dvc.DataVolumeReconcileDurationHistogram)
 }
 
 // Restricts some types in the cache's ListWatch to specific fields/labels per GVK at the specified object,
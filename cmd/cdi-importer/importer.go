@@ -13,9 +13,11 @@ package main
 //    ImporterSecretKey     Optional. Secret key is the password to your account.
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -32,12 +34,15 @@ import (
 	"kubevirt.io/containerized-data-importer/pkg/image"
 	"kubevirt.io/containerized-data-importer/pkg/importer"
 	"kubevirt.io/containerized-data-importer/pkg/util"
+	"kubevirt.io/containerized-data-importer/pkg/util/cgroup"
+	"kubevirt.io/containerized-data-importer/pkg/util/logging"
 	prometheusutil "kubevirt.io/containerized-data-importer/pkg/util/prometheus"
 )
 
 func init() {
 	klog.InitFlags(nil)
 	flag.Parse()
+	logging.SetupFromEnv()
 }
 
 func waitForReadyFile() {
@@ -102,9 +107,18 @@ func main() {
 	prometheusutil.StartPrometheusEndpoint(certsDirectory)
 	klog.V(1).Infoln("Starting importer")
 
+	if err := importer.ApplyProxyEnvironment(); err != nil {
+		klog.Errorf("%+v", err)
+		os.Exit(1)
+	}
+
 	source, _ := util.ParseEnvVar(common.ImporterSource, false)
 	contentType, _ := util.ParseEnvVar(common.ImporterContentType, false)
 	imageSize, _ := util.ParseEnvVar(common.ImporterImageSize, false)
+	ep, _ := util.ParseEnvVar(common.ImporterEndpoint, false)
+	prometheusutil.SetSourceMetadata("type", source)
+	prometheusutil.SetSourceMetadata("contentType", contentType)
+	prometheusutil.SetSourceMetadata("endpoint", util.RedactSourceURL(ep))
 	filesystemOverhead, _ := strconv.ParseFloat(os.Getenv(common.FilesystemOverheadVar), 64)
 	preallocation, err := strconv.ParseBool(os.Getenv(common.Preallocation))
 	if err != nil {
@@ -112,9 +126,30 @@ func main() {
 		os.Exit(1)
 	}
 
+	// A verify-only import has no target volume, only the source is checked
+	if verifyOnly, _ := strconv.ParseBool(os.Getenv(common.ImporterVerifyOnly)); verifyOnly {
+		if exitCode := handleVerify(source, contentType, imageSize, filesystemOverhead); exitCode != 0 {
+			os.Exit(exitCode)
+		}
+		return
+	}
+
 	volumeMode := v1.PersistentVolumeBlock
+	ioLimitsPath := common.WriteBlockPath
 	if _, err := os.Stat(common.WriteBlockPath); os.IsNotExist(err) {
 		volumeMode = v1.PersistentVolumeFilesystem
+		ioLimitsPath = common.ImporterDataDir
+	}
+	cgroup.ApplyIOLimitsFromEnv(ioLimitsPath)
+
+	if mustBeEmpty, _ := strconv.ParseBool(os.Getenv(common.TargetMustBeEmpty)); mustBeEmpty {
+		if err := util.CheckTargetEmpty(common.TargetCheckDir, common.ImporterDataDir, common.WriteBlockPath); err != nil {
+			klog.Errorf("%+v", err)
+			if err := util.WriteTerminationMessage(err.Error()); err != nil {
+				klog.Errorf("%+v", err)
+			}
+			os.Exit(1)
+		}
 	}
 
 	// With writeback cache mode it's possible that the process will exit before all writes have been commited to storage.
@@ -122,6 +157,13 @@ func main() {
 	// Also might be a good idea to sync any chmod's we might have done.
 	defer fsyncDataFile(contentType, volumeMode)
 
+	maxCopyBufferSize, err := util.ParseMaxCopyBufferSize(os.Getenv(common.ImporterMaxCopyBufferSize))
+	if err != nil {
+		klog.Errorf("%+v", err)
+		os.Exit(1)
+	}
+	util.SetMaxCopyBufferSize(maxCopyBufferSize)
+
 	//Registry import currently support kubevirt content type only
 	if contentType != string(cdiv1.DataVolumeKubeVirt) && (source == cc.SourceRegistry || source == cc.SourceImageio) {
 		klog.Errorf("Unsupported content type %s when importing from %s", contentType, source)
@@ -139,9 +181,27 @@ func main() {
 			klog.Errorf("%+v", err)
 			os.Exit(1)
 		}
+	} else if changedRangesURL, _ := util.ParseEnvVar(common.ImporterChangedRangesURL, false); changedRangesURL != "" && source == cc.SourceHTTP {
+		if exitCode := handleChangedRangesImport(changedRangesURL, contentType, volumeMode); exitCode != 0 {
+			os.Exit(exitCode)
+		}
 	} else {
 		waitForReadyFile()
-		exitCode := handleImport(source, contentType, volumeMode, imageSize, filesystemOverhead, preallocation)
+		shrinkToUsedSize, _ := strconv.ParseBool(os.Getenv(common.ImporterShrinkToUsedSize))
+		targetFormat, _ := util.ParseEnvVar(common.ImporterTargetFormat, false)
+		targetCompression, _ := util.ParseEnvVar(common.ImporterTargetCompression, false)
+		encryptionKeyFile, _ := util.ParseEnvVar(common.ImporterEncryptionKeyFile, false)
+		freeSpaceMargin, err := util.ParseFreeSpaceMargin(os.Getenv(common.ImporterFreeSpaceMargin))
+		if err != nil {
+			klog.Errorf("%+v", err)
+			os.Exit(1)
+		}
+		scratchSpaceLimit, err := getScratchSpaceLimit()
+		if err != nil {
+			klog.Errorf("%+v", err)
+			os.Exit(1)
+		}
+		exitCode := handleImport(source, contentType, volumeMode, imageSize, filesystemOverhead, preallocation, shrinkToUsedSize, targetFormat, targetCompression, encryptionKeyFile, freeSpaceMargin, scratchSpaceLimit)
 		if exitCode != 0 {
 			os.Exit(exitCode)
 		}
@@ -158,7 +218,7 @@ func handleEmptyImage(contentType string, imageSize string, availableDestSpace i
 		errorEmptyDiskWithContentTypeArchive()
 	}
 
-	err := importCompleteTerminationMessage(preallocationApplied)
+	err := importCompleteTerminationMessage(preallocationApplied, false, util.ImageInfo{}, util.TargetImageInfo{}, cdiv1.DataVolumeImportTimings{}, false, util.SourceValidators{}, nil)
 	return err
 }
 
@@ -168,20 +228,33 @@ func handleImport(
 	volumeMode v1.PersistentVolumeMode,
 	imageSize string,
 	filesystemOverhead float64,
-	preallocation bool) int {
+	preallocation bool,
+	shrinkToUsedSize bool,
+	targetFormat string,
+	targetCompression string,
+	encryptionKeyFile string,
+	freeSpaceMargin *util.FreeSpaceMargin,
+	scratchSpaceLimit int64) int {
 	klog.V(1).Infoln("begin import process")
+	logging.Lifecycle(logging.EventStart, "source", source)
 
 	ds := newDataSource(source, contentType, volumeMode)
 	defer ds.Close()
 
 	processor := newDataProcessor(contentType, volumeMode, ds, imageSize, filesystemOverhead, preallocation)
+	processor.SetShrinkToUsedSize(shrinkToUsedSize)
+	processor.SetTargetFormat(targetFormat, targetCompression)
+	processor.SetTargetEncryption(encryptionKeyFile)
+	processor.SetFreeSpaceMargin(freeSpaceMargin)
+	processor.SetScratchSpaceLimit(scratchSpaceLimit)
 	err := processor.ProcessData()
 
 	if err != nil {
-		klog.Errorf("%+v", err)
 		if err == importer.ErrRequiresScratchSpace {
+			klog.Errorf("%+v", err)
 			return common.ScratchSpaceNeededExitCode
 		}
+		logging.LifecycleError(err)
 		err = util.WriteTerminationMessage(fmt.Sprintf("Unable to process data: %v", err.Error()))
 		if err != nil {
 			klog.Errorf("%+v", err)
@@ -189,25 +262,162 @@ func handleImport(
 
 		return 1
 	}
+	if err := importer.CompleteConditionalImport(); err != nil {
+		klog.Errorf("%+v", err)
+		return 1
+	}
+	var sourceValidators util.SourceValidators
+	var notModified bool
+	if cds, ok := ds.(importer.ConditionalDataSource); ok {
+		sourceValidators = cds.SourceValidators()
+		notModified = cds.SourceNotModified()
+	}
+	sourceDigest, err := processor.SourceDigest()
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return 1
+	}
 	touchDoneFile()
 	// due to the way some data sources can add additional information to termination message
 	// after finished (ds.close() ) termination message has to be written first, before the
 	// the ds is closed
 	// TODO: think about making communication explicit, probably DS interface should be extended
-	err = importCompleteTerminationMessage(processor.PreallocationApplied())
+	err = importCompleteTerminationMessage(processor.PreallocationApplied(), processor.Passthrough(), processor.ImageInfo(), processor.TargetImageInfo(), processor.ImportTimings(), notModified, sourceValidators, sourceDigest)
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return 1
+	}
+	logging.Lifecycle(logging.EventComplete)
+
+	return 0
+}
+
+// getScratchSpaceLimit returns the size limit of an emptyDir scratch space, 0 when unlimited
+func getScratchSpaceLimit() (int64, error) {
+	limit, _ := util.ParseEnvVar(common.ImporterScratchSpaceLimit, false)
+	if limit == "" {
+		return 0, nil
+	}
+	scratchSpaceLimit, err := strconv.ParseInt(limit, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid %s", common.ImporterScratchSpaceLimit)
+	}
+	return scratchSpaceLimit, nil
+}
+
+// getGcsGeneration returns the generation the GCS object is pinned to, 0 for the latest one
+func getGcsGeneration() (int64, error) {
+	pin, _ := util.ParseEnvVar(common.ImporterGcsGeneration, false)
+	if pin == "" {
+		return 0, nil
+	}
+	generation, err := strconv.ParseInt(pin, 10, 64)
+	if err != nil || generation < 0 {
+		return 0, errors.Errorf("invalid %s %q", common.ImporterGcsGeneration, pin)
+	}
+	return generation, nil
+}
+
+// handleChangedRangesImport writes the changed ranges of the http source onto the base image held by the target
+func handleChangedRangesImport(changedRangesURL, contentType string, volumeMode v1.PersistentVolumeMode) int {
+	klog.V(1).Infoln("begin changed ranges import process")
+	logging.Lifecycle(logging.EventStart, "source", cc.SourceHTTP)
+	if contentType != string(cdiv1.DataVolumeKubeVirt) {
+		klog.Errorf("Unsupported content type %s when importing changed ranges", contentType)
+		return 1
+	}
+
+	ep, _ := util.ParseEnvVar(common.ImporterEndpoint, false)
+	acc, _ := util.ParseEnvVar(common.ImporterAccessKeyID, false)
+	sec, _ := util.ParseEnvVar(common.ImporterSecretKey, false)
+	certDir, _ := util.ParseEnvVar(common.ImporterCertDirVar, false)
+	scratchSpaceLimit, err := getScratchSpaceLimit()
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return 1
+	}
+	if _, err := importer.ImportChangedRanges(ep, changedRangesURL, acc, sec, certDir, getImporterDestPath(contentType, volumeMode), common.ScratchDataDir, scratchSpaceLimit); err != nil {
+		if err == importer.ErrRequiresScratchSpace {
+			klog.Errorf("%+v", err)
+			return common.ScratchSpaceNeededExitCode
+		}
+		logging.LifecycleError(err)
+		err = util.WriteTerminationMessage(fmt.Sprintf("Unable to import the changed ranges: %v", err.Error()))
+		if err != nil {
+			klog.Errorf("%+v", err)
+		}
+		return 1
+	}
+	touchDoneFile()
+	if err := importCompleteTerminationMessage(false, false, util.ImageInfo{}, util.TargetImageInfo{}, cdiv1.DataVolumeImportTimings{}, false, util.SourceValidators{}, nil); err != nil {
+		klog.Errorf("%+v", err)
+		return 1
+	}
+	logging.Lifecycle(logging.EventComplete)
+	return 0
+}
+
+// handleVerify connects to the source and checks its image fits the requested size, without transferring it
+func handleVerify(source, contentType, imageSize string, filesystemOverhead float64) int {
+	klog.V(1).Infoln("begin verify process")
+	ds := newDataSource(source, contentType, v1.PersistentVolumeFilesystem)
+	defer ds.Close()
+
+	var usableSpace int64
+	if imageSize != "" {
+		requestedSize := resource.MustParse(imageSize)
+		usableSpace = util.GetUsableSpace(filesystemOverhead, requestedSize.Value())
+	}
+	info, err := importer.VerifySource(ds, usableSpace)
 	if err != nil {
 		klog.Errorf("%+v", err)
+		err = util.WriteTerminationMessage(fmt.Sprintf("Unable to verify the source: %v", err.Error()))
+		if err != nil {
+			klog.Errorf("%+v", err)
+		}
 		return 1
 	}
 
+	message := fmt.Sprintf("%s, %s", common.SourceVerifiedMessage, info)
+	if err := util.WriteTerminationMessage(message); err != nil {
+		klog.Errorf("%+v", err)
+		return 1
+	}
+	klog.V(1).Infoln(message)
 	return 0
 }
 
-func importCompleteTerminationMessage(preallocationApplied bool) error {
+func importCompleteTerminationMessage(preallocationApplied, passthrough bool, imageInfo util.ImageInfo, targetImageInfo util.TargetImageInfo, timings cdiv1.DataVolumeImportTimings, notModified bool, sourceValidators util.SourceValidators, sourceDigest *util.SourceDigest) error {
 	message := "Import Complete"
 	if preallocationApplied {
 		message += ", " + common.PreallocationApplied
 	}
+	if passthrough {
+		message += ", " + common.PassthroughApplied
+	}
+	if notModified {
+		message += ", " + common.SourceNotModified
+	}
+	if imageInfo != (util.ImageInfo{}) {
+		info, _ := json.Marshal(imageInfo)
+		message += "; " + common.ImageInfoPrefix + string(info)
+	}
+	if targetImageInfo != (util.TargetImageInfo{}) {
+		info, _ := json.Marshal(targetImageInfo)
+		message += "; " + common.TargetImageInfoPrefix + string(info)
+	}
+	if timings != (cdiv1.DataVolumeImportTimings{}) {
+		info, _ := json.Marshal(timings)
+		message += "; " + common.ImportTimingsPrefix + string(info)
+	}
+	if sourceValidators != (util.SourceValidators{}) {
+		info, _ := json.Marshal(sourceValidators)
+		message += "; " + common.SourceValidatorsPrefix + string(info)
+	}
+	if sourceDigest != nil {
+		info, _ := json.Marshal(sourceDigest)
+		message += "; " + common.SourceDigestPrefix + string(info)
+	}
 	err := util.WriteTerminationMessage(message)
 	if err != nil {
 		return err
@@ -236,6 +446,24 @@ func getImporterDestPath(contentType string, volumeMode v1.PersistentVolumeMode)
 	return dest
 }
 
+// getTokenCredentials returns the configuration to exchange the projected service account token for the source
+// credentials, nil if the source keys come from a Secret
+func getTokenCredentials() *importer.TokenCredentials {
+	tokenFile, _ := util.ParseEnvVar(common.ImporterTokenFile, false)
+	if tokenFile == "" {
+		return nil
+	}
+	audience, _ := util.ParseEnvVar(common.ImporterTokenAudience, false)
+	roleARN, _ := util.ParseEnvVar(common.ImporterTokenRoleARN, false)
+	serviceAccount, _ := util.ParseEnvVar(common.ImporterTokenServiceAccount, false)
+	return &importer.TokenCredentials{
+		TokenFile:      tokenFile,
+		Audience:       audience,
+		RoleARN:        roleARN,
+		ServiceAccount: serviceAccount,
+	}
+}
+
 func newDataSource(source string, contentType string, volumeMode v1.PersistentVolumeMode) importer.DataSourceInterface {
 	ep, _ := util.ParseEnvVar(common.ImporterEndpoint, false)
 	acc, _ := util.ParseEnvVar(common.ImporterAccessKeyID, false)
@@ -259,6 +487,13 @@ func newDataSource(source string, contentType string, volumeMode v1.PersistentVo
 			errorCannotConnectDataSource(err, "http")
 		}
 		return ds
+	case cc.SourceRemote:
+		// The export of the remote cluster is served over HTTP, authorized by the bearer token of the source
+		ds, err := importer.NewHTTPDataSource(getHTTPEp(ep), "", "", certDir, cdiv1.DataVolumeContentType(contentType))
+		if err != nil {
+			errorCannotConnectDataSource(err, "remote")
+		}
+		return ds
 	case cc.SourceImageio:
 		ds, err := importer.NewImageioDataSource(ep, acc, sec, certDir, diskID, currentCheckpoint, previousCheckpoint)
 		if err != nil {
@@ -266,20 +501,60 @@ func newDataSource(source string, contentType string, volumeMode v1.PersistentVo
 		}
 		return ds
 	case cc.SourceRegistry:
-		ds := importer.NewRegistryDataSource(ep, acc, sec, certDir, insecureTLS)
+		diskPath, _ := util.ParseEnvVar(common.ImporterRegistryDiskPath, false)
+		ds := importer.NewRegistryDataSource(ep, acc, sec, certDir, insecureTLS, diskPath)
 		return ds
 	case cc.SourceS3:
-		ds, err := importer.NewS3DataSource(ep, acc, sec, certDir)
+		kmsKeyID, _ := util.ParseEnvVar(common.ImporterS3KMSKeyID, false)
+		ds, err := importer.NewS3DataSource(ep, acc, sec, certDir, kmsKeyID, getTokenCredentials())
 		if err != nil {
 			errorCannotConnectDataSource(err, "s3")
 		}
 		return ds
 	case cc.SourceGCS:
-		ds, err := importer.NewGCSDataSource(ep, keyf)
+		userProject, _ := util.ParseEnvVar(common.ImporterGcsUserProject, false)
+		generation, err := getGcsGeneration()
+		if err != nil {
+			errorCannotConnectDataSource(err, "gcs")
+		}
+		ds, err := importer.NewGCSDataSource(ep, keyf, userProject, generation, getTokenCredentials())
 		if err != nil {
 			errorCannotConnectDataSource(err, "gcs")
 		}
 		return ds
+	case cc.SourceFTP:
+		ds, err := importer.NewFTPDataSource(ep, acc, sec, certDir)
+		if err != nil {
+			errorCannotConnectDataSource(err, "ftp")
+		}
+		return ds
+	case cc.SourceAzure:
+		preallocation, _ := strconv.ParseBool(os.Getenv(common.Preallocation))
+		ds, err := importer.NewAzureDataSource(ep, common.ImporterAzureCredentialDir, certDir, preallocation)
+		if err != nil {
+			errorCannotConnectDataSource(err, "azure")
+		}
+		return ds
+	case cc.SourceSFTP:
+		hostKey, _ := util.ParseEnvVar(common.ImporterSFTPHostKey, false)
+		ds, err := importer.NewSFTPDataSource(ep, common.ImporterSFTPCredentialDir, hostKey)
+		if err != nil {
+			errorCannotConnectDataSource(err, "sftp")
+		}
+		return ds
+	case cc.SourceInline:
+		preallocation, _ := strconv.ParseBool(os.Getenv(common.Preallocation))
+		ds, err := importer.NewInlineDataSource(filepath.Join(common.ImporterInlineSourceDir, common.InlineSourceDataKey), cdiv1.DataVolumeContentType(contentType), preallocation)
+		if err != nil {
+			errorCannotConnectDataSource(err, "inline")
+		}
+		return ds
+	case cc.SourceNBD:
+		ds, err := importer.NewNBDDataSource(ep, common.ImporterNbdCertDir)
+		if err != nil {
+			errorCannotConnectDataSource(err, "nbd")
+		}
+		return ds
 	case cc.SourceVDDK:
 		ds, err := importer.NewVDDKDataSource(ep, acc, sec, thumbprint, uuid, backingFile, currentCheckpoint, previousCheckpoint, finalCheckpoint, volumeMode)
 		if err != nil {
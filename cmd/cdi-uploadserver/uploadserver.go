@@ -20,17 +20,20 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"os"
 	"strconv"
 	"strings"
 
 	ocpcrypto "github.com/openshift/library-go/pkg/crypto"
+	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
 
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/uploadserver"
 	"kubevirt.io/containerized-data-importer/pkg/util"
+	"kubevirt.io/containerized-data-importer/pkg/util/logging"
 	cryptowatch "kubevirt.io/containerized-data-importer/pkg/util/tls-crypto-watch"
 )
 
@@ -44,6 +47,7 @@ const (
 func init() {
 	klog.InitFlags(nil)
 	flag.Parse()
+	logging.SetupFromEnv()
 }
 
 func main() {
@@ -56,6 +60,21 @@ func main() {
 
 	filesystemOverhead, _ := strconv.ParseFloat(os.Getenv(common.FilesystemOverheadVar), 64)
 	preallocation, _ := strconv.ParseBool(os.Getenv(common.Preallocation))
+	cloneTarget, err := getCloneTarget()
+	if err != nil {
+		klog.Errorf("%+v", err)
+		os.Exit(1)
+	}
+
+	if mustBeEmpty, _ := strconv.ParseBool(os.Getenv(common.TargetMustBeEmpty)); mustBeEmpty {
+		if err := util.CheckTargetEmpty(common.TargetCheckDir, common.UploadServerDataDir, common.WriteBlockPath); err != nil {
+			klog.Errorf("%+v", err)
+			if err := util.WriteTerminationMessage(err.Error()); err != nil {
+				klog.Errorf("%+v", err)
+			}
+			os.Exit(1)
+		}
+	}
 
 	server := uploadserver.NewUploadServer(
 		listenAddress,
@@ -69,18 +88,21 @@ func main() {
 		filesystemOverhead,
 		preallocation,
 		cryptoConfig,
+		cloneTarget,
 	)
 
 	klog.Infof("Running server on %s:%d", listenAddress, listenPort)
+	logging.Lifecycle(logging.EventStart)
 
-	err := server.Run()
+	err = server.Run()
 	if err != nil {
-		klog.Errorf("UploadServer failed: %s", err)
+		logging.LifecycleError(errors.Wrap(err, "UploadServer failed"))
 		os.Exit(1)
 	}
 
-	// Check if cloning or uploading based on the existence of the scratch space. Clone won't have scratch space
-	clone := false
+	// Check if cloning or uploading based on the existence of the scratch space. Clone won't have scratch space,
+	// unless it is converted
+	clone := cloneTarget != nil
 	_, err = os.OpenFile(common.ScratchDataDir, os.O_RDONLY, 0600)
 	if err != nil {
 		// Cloning instead of uploading.
@@ -95,11 +117,17 @@ func main() {
 	if server.PreallocationApplied() {
 		message += ", " + common.PreallocationApplied
 	}
+	if cloneTarget != nil {
+		// Whether copied as is or converted, the clone is a qcow2 image with the requested compression
+		info, _ := json.Marshal(util.TargetImageInfo{Format: common.ImportTargetFormatQcow2, Compression: cloneTarget.Compression})
+		message += "; " + common.TargetImageInfoPrefix + string(info)
+	}
 	err = util.WriteTerminationMessage(message)
 	if err != nil {
 		klog.Errorf("%+v", err)
 		os.Exit(1)
 	}
+	logging.Lifecycle(logging.EventComplete)
 	klog.Info("UploadServer successfully exited")
 }
 
@@ -133,6 +161,25 @@ func getCryptoConfig() cryptowatch.CryptoConfig {
 	}
 }
 
+// getCloneTarget returns the qcow2 image a clone is converted into, nil when it is copied as is
+func getCloneTarget() (*uploadserver.CloneTargetImage, error) {
+	if os.Getenv(common.UploadTargetFormat) != common.ImportTargetFormatQcow2 {
+		return nil, nil
+	}
+	cloneTarget := &uploadserver.CloneTargetImage{
+		Compression: os.Getenv(common.UploadTargetCompression),
+	}
+	if val := os.Getenv(common.UploadQcow2ClusterSize); len(val) > 0 {
+		clusterSize, err := util.ParseQcow2ClusterSize(val)
+		if err != nil {
+			return nil, err
+		}
+		cloneTarget.ClusterSize = clusterSize
+	}
+	cloneTarget.Compact, _ = strconv.ParseBool(os.Getenv(common.UploadCompact))
+	return cloneTarget, nil
+}
+
 func getDestination() string {
 	destination := defaultDestination
 
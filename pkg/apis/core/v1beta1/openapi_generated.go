@@ -32,554 +32,580 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
-		"github.com/openshift/api/config/v1.APIServer":                                               schema_openshift_api_config_v1_APIServer(ref),
-		"github.com/openshift/api/config/v1.APIServerEncryption":                                     schema_openshift_api_config_v1_APIServerEncryption(ref),
-		"github.com/openshift/api/config/v1.APIServerList":                                           schema_openshift_api_config_v1_APIServerList(ref),
-		"github.com/openshift/api/config/v1.APIServerNamedServingCert":                               schema_openshift_api_config_v1_APIServerNamedServingCert(ref),
-		"github.com/openshift/api/config/v1.APIServerServingCerts":                                   schema_openshift_api_config_v1_APIServerServingCerts(ref),
-		"github.com/openshift/api/config/v1.APIServerSpec":                                           schema_openshift_api_config_v1_APIServerSpec(ref),
-		"github.com/openshift/api/config/v1.APIServerStatus":                                         schema_openshift_api_config_v1_APIServerStatus(ref),
-		"github.com/openshift/api/config/v1.AWSIngressSpec":                                          schema_openshift_api_config_v1_AWSIngressSpec(ref),
-		"github.com/openshift/api/config/v1.AWSPlatformSpec":                                         schema_openshift_api_config_v1_AWSPlatformSpec(ref),
-		"github.com/openshift/api/config/v1.AWSPlatformStatus":                                       schema_openshift_api_config_v1_AWSPlatformStatus(ref),
-		"github.com/openshift/api/config/v1.AWSResourceTag":                                          schema_openshift_api_config_v1_AWSResourceTag(ref),
-		"github.com/openshift/api/config/v1.AWSServiceEndpoint":                                      schema_openshift_api_config_v1_AWSServiceEndpoint(ref),
-		"github.com/openshift/api/config/v1.AdmissionConfig":                                         schema_openshift_api_config_v1_AdmissionConfig(ref),
-		"github.com/openshift/api/config/v1.AdmissionPluginConfig":                                   schema_openshift_api_config_v1_AdmissionPluginConfig(ref),
-		"github.com/openshift/api/config/v1.AlibabaCloudPlatformSpec":                                schema_openshift_api_config_v1_AlibabaCloudPlatformSpec(ref),
-		"github.com/openshift/api/config/v1.AlibabaCloudPlatformStatus":                              schema_openshift_api_config_v1_AlibabaCloudPlatformStatus(ref),
-		"github.com/openshift/api/config/v1.AlibabaCloudResourceTag":                                 schema_openshift_api_config_v1_AlibabaCloudResourceTag(ref),
-		"github.com/openshift/api/config/v1.Audit":                                                   schema_openshift_api_config_v1_Audit(ref),
-		"github.com/openshift/api/config/v1.AuditConfig":                                             schema_openshift_api_config_v1_AuditConfig(ref),
-		"github.com/openshift/api/config/v1.AuditCustomRule":                                         schema_openshift_api_config_v1_AuditCustomRule(ref),
-		"github.com/openshift/api/config/v1.Authentication":                                          schema_openshift_api_config_v1_Authentication(ref),
-		"github.com/openshift/api/config/v1.AuthenticationList":                                      schema_openshift_api_config_v1_AuthenticationList(ref),
-		"github.com/openshift/api/config/v1.AuthenticationSpec":                                      schema_openshift_api_config_v1_AuthenticationSpec(ref),
-		"github.com/openshift/api/config/v1.AuthenticationStatus":                                    schema_openshift_api_config_v1_AuthenticationStatus(ref),
-		"github.com/openshift/api/config/v1.AzurePlatformSpec":                                       schema_openshift_api_config_v1_AzurePlatformSpec(ref),
-		"github.com/openshift/api/config/v1.AzurePlatformStatus":                                     schema_openshift_api_config_v1_AzurePlatformStatus(ref),
-		"github.com/openshift/api/config/v1.AzureResourceTag":                                        schema_openshift_api_config_v1_AzureResourceTag(ref),
-		"github.com/openshift/api/config/v1.BareMetalPlatformLoadBalancer":                           schema_openshift_api_config_v1_BareMetalPlatformLoadBalancer(ref),
-		"github.com/openshift/api/config/v1.BareMetalPlatformSpec":                                   schema_openshift_api_config_v1_BareMetalPlatformSpec(ref),
-		"github.com/openshift/api/config/v1.BareMetalPlatformStatus":                                 schema_openshift_api_config_v1_BareMetalPlatformStatus(ref),
-		"github.com/openshift/api/config/v1.BasicAuthIdentityProvider":                               schema_openshift_api_config_v1_BasicAuthIdentityProvider(ref),
-		"github.com/openshift/api/config/v1.Build":                                                   schema_openshift_api_config_v1_Build(ref),
-		"github.com/openshift/api/config/v1.BuildDefaults":                                           schema_openshift_api_config_v1_BuildDefaults(ref),
-		"github.com/openshift/api/config/v1.BuildList":                                               schema_openshift_api_config_v1_BuildList(ref),
-		"github.com/openshift/api/config/v1.BuildOverrides":                                          schema_openshift_api_config_v1_BuildOverrides(ref),
-		"github.com/openshift/api/config/v1.BuildSpec":                                               schema_openshift_api_config_v1_BuildSpec(ref),
-		"github.com/openshift/api/config/v1.CertInfo":                                                schema_openshift_api_config_v1_CertInfo(ref),
-		"github.com/openshift/api/config/v1.ClientConnectionOverrides":                               schema_openshift_api_config_v1_ClientConnectionOverrides(ref),
-		"github.com/openshift/api/config/v1.ClusterCondition":                                        schema_openshift_api_config_v1_ClusterCondition(ref),
-		"github.com/openshift/api/config/v1.ClusterNetworkEntry":                                     schema_openshift_api_config_v1_ClusterNetworkEntry(ref),
-		"github.com/openshift/api/config/v1.ClusterOperator":                                         schema_openshift_api_config_v1_ClusterOperator(ref),
-		"github.com/openshift/api/config/v1.ClusterOperatorList":                                     schema_openshift_api_config_v1_ClusterOperatorList(ref),
-		"github.com/openshift/api/config/v1.ClusterOperatorSpec":                                     schema_openshift_api_config_v1_ClusterOperatorSpec(ref),
-		"github.com/openshift/api/config/v1.ClusterOperatorStatus":                                   schema_openshift_api_config_v1_ClusterOperatorStatus(ref),
-		"github.com/openshift/api/config/v1.ClusterOperatorStatusCondition":                          schema_openshift_api_config_v1_ClusterOperatorStatusCondition(ref),
-		"github.com/openshift/api/config/v1.ClusterVersion":                                          schema_openshift_api_config_v1_ClusterVersion(ref),
-		"github.com/openshift/api/config/v1.ClusterVersionCapabilitiesSpec":                          schema_openshift_api_config_v1_ClusterVersionCapabilitiesSpec(ref),
-		"github.com/openshift/api/config/v1.ClusterVersionCapabilitiesStatus":                        schema_openshift_api_config_v1_ClusterVersionCapabilitiesStatus(ref),
-		"github.com/openshift/api/config/v1.ClusterVersionList":                                      schema_openshift_api_config_v1_ClusterVersionList(ref),
-		"github.com/openshift/api/config/v1.ClusterVersionSpec":                                      schema_openshift_api_config_v1_ClusterVersionSpec(ref),
-		"github.com/openshift/api/config/v1.ClusterVersionStatus":                                    schema_openshift_api_config_v1_ClusterVersionStatus(ref),
-		"github.com/openshift/api/config/v1.ComponentOverride":                                       schema_openshift_api_config_v1_ComponentOverride(ref),
-		"github.com/openshift/api/config/v1.ComponentRouteSpec":                                      schema_openshift_api_config_v1_ComponentRouteSpec(ref),
-		"github.com/openshift/api/config/v1.ComponentRouteStatus":                                    schema_openshift_api_config_v1_ComponentRouteStatus(ref),
-		"github.com/openshift/api/config/v1.ConditionalUpdate":                                       schema_openshift_api_config_v1_ConditionalUpdate(ref),
-		"github.com/openshift/api/config/v1.ConditionalUpdateRisk":                                   schema_openshift_api_config_v1_ConditionalUpdateRisk(ref),
-		"github.com/openshift/api/config/v1.ConfigMapFileReference":                                  schema_openshift_api_config_v1_ConfigMapFileReference(ref),
-		"github.com/openshift/api/config/v1.ConfigMapNameReference":                                  schema_openshift_api_config_v1_ConfigMapNameReference(ref),
-		"github.com/openshift/api/config/v1.Console":                                                 schema_openshift_api_config_v1_Console(ref),
-		"github.com/openshift/api/config/v1.ConsoleAuthentication":                                   schema_openshift_api_config_v1_ConsoleAuthentication(ref),
-		"github.com/openshift/api/config/v1.ConsoleList":                                             schema_openshift_api_config_v1_ConsoleList(ref),
-		"github.com/openshift/api/config/v1.ConsoleSpec":                                             schema_openshift_api_config_v1_ConsoleSpec(ref),
-		"github.com/openshift/api/config/v1.ConsoleStatus":                                           schema_openshift_api_config_v1_ConsoleStatus(ref),
-		"github.com/openshift/api/config/v1.CustomFeatureGates":                                      schema_openshift_api_config_v1_CustomFeatureGates(ref),
-		"github.com/openshift/api/config/v1.CustomTLSProfile":                                        schema_openshift_api_config_v1_CustomTLSProfile(ref),
-		"github.com/openshift/api/config/v1.DNS":                                                     schema_openshift_api_config_v1_DNS(ref),
-		"github.com/openshift/api/config/v1.DNSList":                                                 schema_openshift_api_config_v1_DNSList(ref),
-		"github.com/openshift/api/config/v1.DNSSpec":                                                 schema_openshift_api_config_v1_DNSSpec(ref),
-		"github.com/openshift/api/config/v1.DNSStatus":                                               schema_openshift_api_config_v1_DNSStatus(ref),
-		"github.com/openshift/api/config/v1.DNSZone":                                                 schema_openshift_api_config_v1_DNSZone(ref),
-		"github.com/openshift/api/config/v1.DelegatedAuthentication":                                 schema_openshift_api_config_v1_DelegatedAuthentication(ref),
-		"github.com/openshift/api/config/v1.DelegatedAuthorization":                                  schema_openshift_api_config_v1_DelegatedAuthorization(ref),
-		"github.com/openshift/api/config/v1.DeprecatedWebhookTokenAuthenticator":                     schema_openshift_api_config_v1_DeprecatedWebhookTokenAuthenticator(ref),
-		"github.com/openshift/api/config/v1.EquinixMetalPlatformSpec":                                schema_openshift_api_config_v1_EquinixMetalPlatformSpec(ref),
-		"github.com/openshift/api/config/v1.EquinixMetalPlatformStatus":                              schema_openshift_api_config_v1_EquinixMetalPlatformStatus(ref),
-		"github.com/openshift/api/config/v1.EtcdConnectionInfo":                                      schema_openshift_api_config_v1_EtcdConnectionInfo(ref),
-		"github.com/openshift/api/config/v1.EtcdStorageConfig":                                       schema_openshift_api_config_v1_EtcdStorageConfig(ref),
-		"github.com/openshift/api/config/v1.ExternalIPConfig":                                        schema_openshift_api_config_v1_ExternalIPConfig(ref),
-		"github.com/openshift/api/config/v1.ExternalIPPolicy":                                        schema_openshift_api_config_v1_ExternalIPPolicy(ref),
-		"github.com/openshift/api/config/v1.ExternalPlatformSpec":                                    schema_openshift_api_config_v1_ExternalPlatformSpec(ref),
-		"github.com/openshift/api/config/v1.ExternalPlatformStatus":                                  schema_openshift_api_config_v1_ExternalPlatformStatus(ref),
-		"github.com/openshift/api/config/v1.FeatureGate":                                             schema_openshift_api_config_v1_FeatureGate(ref),
-		"github.com/openshift/api/config/v1.FeatureGateEnabledDisabled":                              schema_openshift_api_config_v1_FeatureGateEnabledDisabled(ref),
-		"github.com/openshift/api/config/v1.FeatureGateList":                                         schema_openshift_api_config_v1_FeatureGateList(ref),
-		"github.com/openshift/api/config/v1.FeatureGateSelection":                                    schema_openshift_api_config_v1_FeatureGateSelection(ref),
-		"github.com/openshift/api/config/v1.FeatureGateSpec":                                         schema_openshift_api_config_v1_FeatureGateSpec(ref),
-		"github.com/openshift/api/config/v1.FeatureGateStatus":                                       schema_openshift_api_config_v1_FeatureGateStatus(ref),
-		"github.com/openshift/api/config/v1.GCPPlatformSpec":                                         schema_openshift_api_config_v1_GCPPlatformSpec(ref),
-		"github.com/openshift/api/config/v1.GCPPlatformStatus":                                       schema_openshift_api_config_v1_GCPPlatformStatus(ref),
-		"github.com/openshift/api/config/v1.GenericAPIServerConfig":                                  schema_openshift_api_config_v1_GenericAPIServerConfig(ref),
-		"github.com/openshift/api/config/v1.GenericControllerConfig":                                 schema_openshift_api_config_v1_GenericControllerConfig(ref),
-		"github.com/openshift/api/config/v1.GitHubIdentityProvider":                                  schema_openshift_api_config_v1_GitHubIdentityProvider(ref),
-		"github.com/openshift/api/config/v1.GitLabIdentityProvider":                                  schema_openshift_api_config_v1_GitLabIdentityProvider(ref),
-		"github.com/openshift/api/config/v1.GoogleIdentityProvider":                                  schema_openshift_api_config_v1_GoogleIdentityProvider(ref),
-		"github.com/openshift/api/config/v1.HTPasswdIdentityProvider":                                schema_openshift_api_config_v1_HTPasswdIdentityProvider(ref),
-		"github.com/openshift/api/config/v1.HTTPServingInfo":                                         schema_openshift_api_config_v1_HTTPServingInfo(ref),
-		"github.com/openshift/api/config/v1.HubSource":                                               schema_openshift_api_config_v1_HubSource(ref),
-		"github.com/openshift/api/config/v1.HubSourceStatus":                                         schema_openshift_api_config_v1_HubSourceStatus(ref),
-		"github.com/openshift/api/config/v1.IBMCloudPlatformSpec":                                    schema_openshift_api_config_v1_IBMCloudPlatformSpec(ref),
-		"github.com/openshift/api/config/v1.IBMCloudPlatformStatus":                                  schema_openshift_api_config_v1_IBMCloudPlatformStatus(ref),
-		"github.com/openshift/api/config/v1.IdentityProvider":                                        schema_openshift_api_config_v1_IdentityProvider(ref),
-		"github.com/openshift/api/config/v1.IdentityProviderConfig":                                  schema_openshift_api_config_v1_IdentityProviderConfig(ref),
-		"github.com/openshift/api/config/v1.Image":                                                   schema_openshift_api_config_v1_Image(ref),
-		"github.com/openshift/api/config/v1.ImageContentPolicy":                                      schema_openshift_api_config_v1_ImageContentPolicy(ref),
-		"github.com/openshift/api/config/v1.ImageContentPolicyList":                                  schema_openshift_api_config_v1_ImageContentPolicyList(ref),
-		"github.com/openshift/api/config/v1.ImageContentPolicySpec":                                  schema_openshift_api_config_v1_ImageContentPolicySpec(ref),
-		"github.com/openshift/api/config/v1.ImageDigestMirrorSet":                                    schema_openshift_api_config_v1_ImageDigestMirrorSet(ref),
-		"github.com/openshift/api/config/v1.ImageDigestMirrorSetList":                                schema_openshift_api_config_v1_ImageDigestMirrorSetList(ref),
-		"github.com/openshift/api/config/v1.ImageDigestMirrorSetSpec":                                schema_openshift_api_config_v1_ImageDigestMirrorSetSpec(ref),
-		"github.com/openshift/api/config/v1.ImageDigestMirrorSetStatus":                              schema_openshift_api_config_v1_ImageDigestMirrorSetStatus(ref),
-		"github.com/openshift/api/config/v1.ImageDigestMirrors":                                      schema_openshift_api_config_v1_ImageDigestMirrors(ref),
-		"github.com/openshift/api/config/v1.ImageLabel":                                              schema_openshift_api_config_v1_ImageLabel(ref),
-		"github.com/openshift/api/config/v1.ImageList":                                               schema_openshift_api_config_v1_ImageList(ref),
-		"github.com/openshift/api/config/v1.ImageSpec":                                               schema_openshift_api_config_v1_ImageSpec(ref),
-		"github.com/openshift/api/config/v1.ImageStatus":                                             schema_openshift_api_config_v1_ImageStatus(ref),
-		"github.com/openshift/api/config/v1.ImageTagMirrorSet":                                       schema_openshift_api_config_v1_ImageTagMirrorSet(ref),
-		"github.com/openshift/api/config/v1.ImageTagMirrorSetList":                                   schema_openshift_api_config_v1_ImageTagMirrorSetList(ref),
-		"github.com/openshift/api/config/v1.ImageTagMirrorSetSpec":                                   schema_openshift_api_config_v1_ImageTagMirrorSetSpec(ref),
-		"github.com/openshift/api/config/v1.ImageTagMirrorSetStatus":                                 schema_openshift_api_config_v1_ImageTagMirrorSetStatus(ref),
-		"github.com/openshift/api/config/v1.ImageTagMirrors":                                         schema_openshift_api_config_v1_ImageTagMirrors(ref),
-		"github.com/openshift/api/config/v1.Infrastructure":                                          schema_openshift_api_config_v1_Infrastructure(ref),
-		"github.com/openshift/api/config/v1.InfrastructureList":                                      schema_openshift_api_config_v1_InfrastructureList(ref),
-		"github.com/openshift/api/config/v1.InfrastructureSpec":                                      schema_openshift_api_config_v1_InfrastructureSpec(ref),
-		"github.com/openshift/api/config/v1.InfrastructureStatus":                                    schema_openshift_api_config_v1_InfrastructureStatus(ref),
-		"github.com/openshift/api/config/v1.Ingress":                                                 schema_openshift_api_config_v1_Ingress(ref),
-		"github.com/openshift/api/config/v1.IngressList":                                             schema_openshift_api_config_v1_IngressList(ref),
-		"github.com/openshift/api/config/v1.IngressPlatformSpec":                                     schema_openshift_api_config_v1_IngressPlatformSpec(ref),
-		"github.com/openshift/api/config/v1.IngressSpec":                                             schema_openshift_api_config_v1_IngressSpec(ref),
-		"github.com/openshift/api/config/v1.IngressStatus":                                           schema_openshift_api_config_v1_IngressStatus(ref),
-		"github.com/openshift/api/config/v1.IntermediateTLSProfile":                                  schema_openshift_api_config_v1_IntermediateTLSProfile(ref),
-		"github.com/openshift/api/config/v1.KeystoneIdentityProvider":                                schema_openshift_api_config_v1_KeystoneIdentityProvider(ref),
-		"github.com/openshift/api/config/v1.KubeClientConfig":                                        schema_openshift_api_config_v1_KubeClientConfig(ref),
-		"github.com/openshift/api/config/v1.KubevirtPlatformSpec":                                    schema_openshift_api_config_v1_KubevirtPlatformSpec(ref),
-		"github.com/openshift/api/config/v1.KubevirtPlatformStatus":                                  schema_openshift_api_config_v1_KubevirtPlatformStatus(ref),
-		"github.com/openshift/api/config/v1.LDAPAttributeMapping":                                    schema_openshift_api_config_v1_LDAPAttributeMapping(ref),
-		"github.com/openshift/api/config/v1.LDAPIdentityProvider":                                    schema_openshift_api_config_v1_LDAPIdentityProvider(ref),
-		"github.com/openshift/api/config/v1.LeaderElection":                                          schema_openshift_api_config_v1_LeaderElection(ref),
-		"github.com/openshift/api/config/v1.LoadBalancer":                                            schema_openshift_api_config_v1_LoadBalancer(ref),
-		"github.com/openshift/api/config/v1.MTUMigration":                                            schema_openshift_api_config_v1_MTUMigration(ref),
-		"github.com/openshift/api/config/v1.MTUMigrationValues":                                      schema_openshift_api_config_v1_MTUMigrationValues(ref),
-		"github.com/openshift/api/config/v1.MaxAgePolicy":                                            schema_openshift_api_config_v1_MaxAgePolicy(ref),
-		"github.com/openshift/api/config/v1.ModernTLSProfile":                                        schema_openshift_api_config_v1_ModernTLSProfile(ref),
-		"github.com/openshift/api/config/v1.NamedCertificate":                                        schema_openshift_api_config_v1_NamedCertificate(ref),
-		"github.com/openshift/api/config/v1.Network":                                                 schema_openshift_api_config_v1_Network(ref),
-		"github.com/openshift/api/config/v1.NetworkList":                                             schema_openshift_api_config_v1_NetworkList(ref),
-		"github.com/openshift/api/config/v1.NetworkMigration":                                        schema_openshift_api_config_v1_NetworkMigration(ref),
-		"github.com/openshift/api/config/v1.NetworkSpec":                                             schema_openshift_api_config_v1_NetworkSpec(ref),
-		"github.com/openshift/api/config/v1.NetworkStatus":                                           schema_openshift_api_config_v1_NetworkStatus(ref),
-		"github.com/openshift/api/config/v1.Node":                                                    schema_openshift_api_config_v1_Node(ref),
-		"github.com/openshift/api/config/v1.NodeList":                                                schema_openshift_api_config_v1_NodeList(ref),
-		"github.com/openshift/api/config/v1.NodeSpec":                                                schema_openshift_api_config_v1_NodeSpec(ref),
-		"github.com/openshift/api/config/v1.NodeStatus":                                              schema_openshift_api_config_v1_NodeStatus(ref),
-		"github.com/openshift/api/config/v1.NutanixPlatformLoadBalancer":                             schema_openshift_api_config_v1_NutanixPlatformLoadBalancer(ref),
-		"github.com/openshift/api/config/v1.NutanixPlatformSpec":                                     schema_openshift_api_config_v1_NutanixPlatformSpec(ref),
-		"github.com/openshift/api/config/v1.NutanixPlatformStatus":                                   schema_openshift_api_config_v1_NutanixPlatformStatus(ref),
-		"github.com/openshift/api/config/v1.NutanixPrismElementEndpoint":                             schema_openshift_api_config_v1_NutanixPrismElementEndpoint(ref),
-		"github.com/openshift/api/config/v1.NutanixPrismEndpoint":                                    schema_openshift_api_config_v1_NutanixPrismEndpoint(ref),
-		"github.com/openshift/api/config/v1.OAuth":                                                   schema_openshift_api_config_v1_OAuth(ref),
-		"github.com/openshift/api/config/v1.OAuthList":                                               schema_openshift_api_config_v1_OAuthList(ref),
-		"github.com/openshift/api/config/v1.OAuthRemoteConnectionInfo":                               schema_openshift_api_config_v1_OAuthRemoteConnectionInfo(ref),
-		"github.com/openshift/api/config/v1.OAuthSpec":                                               schema_openshift_api_config_v1_OAuthSpec(ref),
-		"github.com/openshift/api/config/v1.OAuthStatus":                                             schema_openshift_api_config_v1_OAuthStatus(ref),
-		"github.com/openshift/api/config/v1.OAuthTemplates":                                          schema_openshift_api_config_v1_OAuthTemplates(ref),
-		"github.com/openshift/api/config/v1.ObjectReference":                                         schema_openshift_api_config_v1_ObjectReference(ref),
-		"github.com/openshift/api/config/v1.OldTLSProfile":                                           schema_openshift_api_config_v1_OldTLSProfile(ref),
-		"github.com/openshift/api/config/v1.OpenIDClaims":                                            schema_openshift_api_config_v1_OpenIDClaims(ref),
-		"github.com/openshift/api/config/v1.OpenIDIdentityProvider":                                  schema_openshift_api_config_v1_OpenIDIdentityProvider(ref),
-		"github.com/openshift/api/config/v1.OpenStackPlatformLoadBalancer":                           schema_openshift_api_config_v1_OpenStackPlatformLoadBalancer(ref),
-		"github.com/openshift/api/config/v1.OpenStackPlatformSpec":                                   schema_openshift_api_config_v1_OpenStackPlatformSpec(ref),
-		"github.com/openshift/api/config/v1.OpenStackPlatformStatus":                                 schema_openshift_api_config_v1_OpenStackPlatformStatus(ref),
-		"github.com/openshift/api/config/v1.OperandVersion":                                          schema_openshift_api_config_v1_OperandVersion(ref),
-		"github.com/openshift/api/config/v1.OperatorHub":                                             schema_openshift_api_config_v1_OperatorHub(ref),
-		"github.com/openshift/api/config/v1.OperatorHubList":                                         schema_openshift_api_config_v1_OperatorHubList(ref),
-		"github.com/openshift/api/config/v1.OperatorHubSpec":                                         schema_openshift_api_config_v1_OperatorHubSpec(ref),
-		"github.com/openshift/api/config/v1.OperatorHubStatus":                                       schema_openshift_api_config_v1_OperatorHubStatus(ref),
-		"github.com/openshift/api/config/v1.OvirtPlatformLoadBalancer":                               schema_openshift_api_config_v1_OvirtPlatformLoadBalancer(ref),
-		"github.com/openshift/api/config/v1.OvirtPlatformSpec":                                       schema_openshift_api_config_v1_OvirtPlatformSpec(ref),
-		"github.com/openshift/api/config/v1.OvirtPlatformStatus":                                     schema_openshift_api_config_v1_OvirtPlatformStatus(ref),
-		"github.com/openshift/api/config/v1.PlatformSpec":                                            schema_openshift_api_config_v1_PlatformSpec(ref),
-		"github.com/openshift/api/config/v1.PlatformStatus":                                          schema_openshift_api_config_v1_PlatformStatus(ref),
-		"github.com/openshift/api/config/v1.PowerVSPlatformSpec":                                     schema_openshift_api_config_v1_PowerVSPlatformSpec(ref),
-		"github.com/openshift/api/config/v1.PowerVSPlatformStatus":                                   schema_openshift_api_config_v1_PowerVSPlatformStatus(ref),
-		"github.com/openshift/api/config/v1.PowerVSServiceEndpoint":                                  schema_openshift_api_config_v1_PowerVSServiceEndpoint(ref),
-		"github.com/openshift/api/config/v1.Project":                                                 schema_openshift_api_config_v1_Project(ref),
-		"github.com/openshift/api/config/v1.ProjectList":                                             schema_openshift_api_config_v1_ProjectList(ref),
-		"github.com/openshift/api/config/v1.ProjectSpec":                                             schema_openshift_api_config_v1_ProjectSpec(ref),
-		"github.com/openshift/api/config/v1.ProjectStatus":                                           schema_openshift_api_config_v1_ProjectStatus(ref),
-		"github.com/openshift/api/config/v1.PromQLClusterCondition":                                  schema_openshift_api_config_v1_PromQLClusterCondition(ref),
-		"github.com/openshift/api/config/v1.Proxy":                                                   schema_openshift_api_config_v1_Proxy(ref),
-		"github.com/openshift/api/config/v1.ProxyList":                                               schema_openshift_api_config_v1_ProxyList(ref),
-		"github.com/openshift/api/config/v1.ProxySpec":                                               schema_openshift_api_config_v1_ProxySpec(ref),
-		"github.com/openshift/api/config/v1.ProxyStatus":                                             schema_openshift_api_config_v1_ProxyStatus(ref),
-		"github.com/openshift/api/config/v1.RegistryLocation":                                        schema_openshift_api_config_v1_RegistryLocation(ref),
-		"github.com/openshift/api/config/v1.RegistrySources":                                         schema_openshift_api_config_v1_RegistrySources(ref),
-		"github.com/openshift/api/config/v1.Release":                                                 schema_openshift_api_config_v1_Release(ref),
-		"github.com/openshift/api/config/v1.RemoteConnectionInfo":                                    schema_openshift_api_config_v1_RemoteConnectionInfo(ref),
-		"github.com/openshift/api/config/v1.RepositoryDigestMirrors":                                 schema_openshift_api_config_v1_RepositoryDigestMirrors(ref),
-		"github.com/openshift/api/config/v1.RequestHeaderIdentityProvider":                           schema_openshift_api_config_v1_RequestHeaderIdentityProvider(ref),
-		"github.com/openshift/api/config/v1.RequiredHSTSPolicy":                                      schema_openshift_api_config_v1_RequiredHSTSPolicy(ref),
-		"github.com/openshift/api/config/v1.Scheduler":                                               schema_openshift_api_config_v1_Scheduler(ref),
-		"github.com/openshift/api/config/v1.SchedulerList":                                           schema_openshift_api_config_v1_SchedulerList(ref),
-		"github.com/openshift/api/config/v1.SchedulerSpec":                                           schema_openshift_api_config_v1_SchedulerSpec(ref),
-		"github.com/openshift/api/config/v1.SchedulerStatus":                                         schema_openshift_api_config_v1_SchedulerStatus(ref),
-		"github.com/openshift/api/config/v1.SecretNameReference":                                     schema_openshift_api_config_v1_SecretNameReference(ref),
-		"github.com/openshift/api/config/v1.ServingInfo":                                             schema_openshift_api_config_v1_ServingInfo(ref),
-		"github.com/openshift/api/config/v1.StringSource":                                            schema_openshift_api_config_v1_StringSource(ref),
-		"github.com/openshift/api/config/v1.StringSourceSpec":                                        schema_openshift_api_config_v1_StringSourceSpec(ref),
-		"github.com/openshift/api/config/v1.TLSProfileSpec":                                          schema_openshift_api_config_v1_TLSProfileSpec(ref),
-		"github.com/openshift/api/config/v1.TLSSecurityProfile":                                      schema_openshift_api_config_v1_TLSSecurityProfile(ref),
-		"github.com/openshift/api/config/v1.TemplateReference":                                       schema_openshift_api_config_v1_TemplateReference(ref),
-		"github.com/openshift/api/config/v1.TokenConfig":                                             schema_openshift_api_config_v1_TokenConfig(ref),
-		"github.com/openshift/api/config/v1.Update":                                                  schema_openshift_api_config_v1_Update(ref),
-		"github.com/openshift/api/config/v1.UpdateHistory":                                           schema_openshift_api_config_v1_UpdateHistory(ref),
-		"github.com/openshift/api/config/v1.VSpherePlatformFailureDomainSpec":                        schema_openshift_api_config_v1_VSpherePlatformFailureDomainSpec(ref),
-		"github.com/openshift/api/config/v1.VSpherePlatformLoadBalancer":                             schema_openshift_api_config_v1_VSpherePlatformLoadBalancer(ref),
-		"github.com/openshift/api/config/v1.VSpherePlatformNodeNetworking":                           schema_openshift_api_config_v1_VSpherePlatformNodeNetworking(ref),
-		"github.com/openshift/api/config/v1.VSpherePlatformNodeNetworkingSpec":                       schema_openshift_api_config_v1_VSpherePlatformNodeNetworkingSpec(ref),
-		"github.com/openshift/api/config/v1.VSpherePlatformSpec":                                     schema_openshift_api_config_v1_VSpherePlatformSpec(ref),
-		"github.com/openshift/api/config/v1.VSpherePlatformStatus":                                   schema_openshift_api_config_v1_VSpherePlatformStatus(ref),
-		"github.com/openshift/api/config/v1.VSpherePlatformTopology":                                 schema_openshift_api_config_v1_VSpherePlatformTopology(ref),
-		"github.com/openshift/api/config/v1.VSpherePlatformVCenterSpec":                              schema_openshift_api_config_v1_VSpherePlatformVCenterSpec(ref),
-		"github.com/openshift/api/config/v1.WebhookTokenAuthenticator":                               schema_openshift_api_config_v1_WebhookTokenAuthenticator(ref),
-		"github.com/openshift/api/config/v1.featureSetBuilder":                                       schema_openshift_api_config_v1_featureSetBuilder(ref),
-		"github.com/openshift/custom-resource-status/conditions/v1.Condition":                        schema_openshift_custom_resource_status_conditions_v1_Condition(ref),
-		"k8s.io/api/core/v1.AWSElasticBlockStoreVolumeSource":                                        schema_k8sio_api_core_v1_AWSElasticBlockStoreVolumeSource(ref),
-		"k8s.io/api/core/v1.Affinity":                                                                schema_k8sio_api_core_v1_Affinity(ref),
-		"k8s.io/api/core/v1.AttachedVolume":                                                          schema_k8sio_api_core_v1_AttachedVolume(ref),
-		"k8s.io/api/core/v1.AvoidPods":                                                               schema_k8sio_api_core_v1_AvoidPods(ref),
-		"k8s.io/api/core/v1.AzureDiskVolumeSource":                                                   schema_k8sio_api_core_v1_AzureDiskVolumeSource(ref),
-		"k8s.io/api/core/v1.AzureFilePersistentVolumeSource":                                         schema_k8sio_api_core_v1_AzureFilePersistentVolumeSource(ref),
-		"k8s.io/api/core/v1.AzureFileVolumeSource":                                                   schema_k8sio_api_core_v1_AzureFileVolumeSource(ref),
-		"k8s.io/api/core/v1.Binding":                                                                 schema_k8sio_api_core_v1_Binding(ref),
-		"k8s.io/api/core/v1.CSIPersistentVolumeSource":                                               schema_k8sio_api_core_v1_CSIPersistentVolumeSource(ref),
-		"k8s.io/api/core/v1.CSIVolumeSource":                                                         schema_k8sio_api_core_v1_CSIVolumeSource(ref),
-		"k8s.io/api/core/v1.Capabilities":                                                            schema_k8sio_api_core_v1_Capabilities(ref),
-		"k8s.io/api/core/v1.CephFSPersistentVolumeSource":                                            schema_k8sio_api_core_v1_CephFSPersistentVolumeSource(ref),
-		"k8s.io/api/core/v1.CephFSVolumeSource":                                                      schema_k8sio_api_core_v1_CephFSVolumeSource(ref),
-		"k8s.io/api/core/v1.CinderPersistentVolumeSource":                                            schema_k8sio_api_core_v1_CinderPersistentVolumeSource(ref),
-		"k8s.io/api/core/v1.CinderVolumeSource":                                                      schema_k8sio_api_core_v1_CinderVolumeSource(ref),
-		"k8s.io/api/core/v1.ClaimSource":                                                             schema_k8sio_api_core_v1_ClaimSource(ref),
-		"k8s.io/api/core/v1.ClientIPConfig":                                                          schema_k8sio_api_core_v1_ClientIPConfig(ref),
-		"k8s.io/api/core/v1.ComponentCondition":                                                      schema_k8sio_api_core_v1_ComponentCondition(ref),
-		"k8s.io/api/core/v1.ComponentStatus":                                                         schema_k8sio_api_core_v1_ComponentStatus(ref),
-		"k8s.io/api/core/v1.ComponentStatusList":                                                     schema_k8sio_api_core_v1_ComponentStatusList(ref),
-		"k8s.io/api/core/v1.ConfigMap":                                                               schema_k8sio_api_core_v1_ConfigMap(ref),
-		"k8s.io/api/core/v1.ConfigMapEnvSource":                                                      schema_k8sio_api_core_v1_ConfigMapEnvSource(ref),
-		"k8s.io/api/core/v1.ConfigMapKeySelector":                                                    schema_k8sio_api_core_v1_ConfigMapKeySelector(ref),
-		"k8s.io/api/core/v1.ConfigMapList":                                                           schema_k8sio_api_core_v1_ConfigMapList(ref),
-		"k8s.io/api/core/v1.ConfigMapNodeConfigSource":                                               schema_k8sio_api_core_v1_ConfigMapNodeConfigSource(ref),
-		"k8s.io/api/core/v1.ConfigMapProjection":                                                     schema_k8sio_api_core_v1_ConfigMapProjection(ref),
-		"k8s.io/api/core/v1.ConfigMapVolumeSource":                                                   schema_k8sio_api_core_v1_ConfigMapVolumeSource(ref),
-		"k8s.io/api/core/v1.Container":                                                               schema_k8sio_api_core_v1_Container(ref),
-		"k8s.io/api/core/v1.ContainerImage":                                                          schema_k8sio_api_core_v1_ContainerImage(ref),
-		"k8s.io/api/core/v1.ContainerPort":                                                           schema_k8sio_api_core_v1_ContainerPort(ref),
-		"k8s.io/api/core/v1.ContainerState":                                                          schema_k8sio_api_core_v1_ContainerState(ref),
-		"k8s.io/api/core/v1.ContainerStateRunning":                                                   schema_k8sio_api_core_v1_ContainerStateRunning(ref),
-		"k8s.io/api/core/v1.ContainerStateTerminated":                                                schema_k8sio_api_core_v1_ContainerStateTerminated(ref),
-		"k8s.io/api/core/v1.ContainerStateWaiting":                                                   schema_k8sio_api_core_v1_ContainerStateWaiting(ref),
-		"k8s.io/api/core/v1.ContainerStatus":                                                         schema_k8sio_api_core_v1_ContainerStatus(ref),
-		"k8s.io/api/core/v1.DaemonEndpoint":                                                          schema_k8sio_api_core_v1_DaemonEndpoint(ref),
-		"k8s.io/api/core/v1.DownwardAPIProjection":                                                   schema_k8sio_api_core_v1_DownwardAPIProjection(ref),
-		"k8s.io/api/core/v1.DownwardAPIVolumeFile":                                                   schema_k8sio_api_core_v1_DownwardAPIVolumeFile(ref),
-		"k8s.io/api/core/v1.DownwardAPIVolumeSource":                                                 schema_k8sio_api_core_v1_DownwardAPIVolumeSource(ref),
-		"k8s.io/api/core/v1.EmptyDirVolumeSource":                                                    schema_k8sio_api_core_v1_EmptyDirVolumeSource(ref),
-		"k8s.io/api/core/v1.EndpointAddress":                                                         schema_k8sio_api_core_v1_EndpointAddress(ref),
-		"k8s.io/api/core/v1.EndpointPort":                                                            schema_k8sio_api_core_v1_EndpointPort(ref),
-		"k8s.io/api/core/v1.EndpointSubset":                                                          schema_k8sio_api_core_v1_EndpointSubset(ref),
-		"k8s.io/api/core/v1.Endpoints":                                                               schema_k8sio_api_core_v1_Endpoints(ref),
-		"k8s.io/api/core/v1.EndpointsList":                                                           schema_k8sio_api_core_v1_EndpointsList(ref),
-		"k8s.io/api/core/v1.EnvFromSource":                                                           schema_k8sio_api_core_v1_EnvFromSource(ref),
-		"k8s.io/api/core/v1.EnvVar":                                                                  schema_k8sio_api_core_v1_EnvVar(ref),
-		"k8s.io/api/core/v1.EnvVarSource":                                                            schema_k8sio_api_core_v1_EnvVarSource(ref),
-		"k8s.io/api/core/v1.EphemeralContainer":                                                      schema_k8sio_api_core_v1_EphemeralContainer(ref),
-		"k8s.io/api/core/v1.EphemeralContainerCommon":                                                schema_k8sio_api_core_v1_EphemeralContainerCommon(ref),
-		"k8s.io/api/core/v1.EphemeralVolumeSource":                                                   schema_k8sio_api_core_v1_EphemeralVolumeSource(ref),
-		"k8s.io/api/core/v1.Event":                                                                   schema_k8sio_api_core_v1_Event(ref),
-		"k8s.io/api/core/v1.EventList":                                                               schema_k8sio_api_core_v1_EventList(ref),
-		"k8s.io/api/core/v1.EventSeries":                                                             schema_k8sio_api_core_v1_EventSeries(ref),
-		"k8s.io/api/core/v1.EventSource":                                                             schema_k8sio_api_core_v1_EventSource(ref),
-		"k8s.io/api/core/v1.ExecAction":                                                              schema_k8sio_api_core_v1_ExecAction(ref),
-		"k8s.io/api/core/v1.FCVolumeSource":                                                          schema_k8sio_api_core_v1_FCVolumeSource(ref),
-		"k8s.io/api/core/v1.FlexPersistentVolumeSource":                                              schema_k8sio_api_core_v1_FlexPersistentVolumeSource(ref),
-		"k8s.io/api/core/v1.FlexVolumeSource":                                                        schema_k8sio_api_core_v1_FlexVolumeSource(ref),
-		"k8s.io/api/core/v1.FlockerVolumeSource":                                                     schema_k8sio_api_core_v1_FlockerVolumeSource(ref),
-		"k8s.io/api/core/v1.GCEPersistentDiskVolumeSource":                                           schema_k8sio_api_core_v1_GCEPersistentDiskVolumeSource(ref),
-		"k8s.io/api/core/v1.GRPCAction":                                                              schema_k8sio_api_core_v1_GRPCAction(ref),
-		"k8s.io/api/core/v1.GitRepoVolumeSource":                                                     schema_k8sio_api_core_v1_GitRepoVolumeSource(ref),
-		"k8s.io/api/core/v1.GlusterfsPersistentVolumeSource":                                         schema_k8sio_api_core_v1_GlusterfsPersistentVolumeSource(ref),
-		"k8s.io/api/core/v1.GlusterfsVolumeSource":                                                   schema_k8sio_api_core_v1_GlusterfsVolumeSource(ref),
-		"k8s.io/api/core/v1.HTTPGetAction":                                                           schema_k8sio_api_core_v1_HTTPGetAction(ref),
-		"k8s.io/api/core/v1.HTTPHeader":                                                              schema_k8sio_api_core_v1_HTTPHeader(ref),
-		"k8s.io/api/core/v1.HostAlias":                                                               schema_k8sio_api_core_v1_HostAlias(ref),
-		"k8s.io/api/core/v1.HostPathVolumeSource":                                                    schema_k8sio_api_core_v1_HostPathVolumeSource(ref),
-		"k8s.io/api/core/v1.ISCSIPersistentVolumeSource":                                             schema_k8sio_api_core_v1_ISCSIPersistentVolumeSource(ref),
-		"k8s.io/api/core/v1.ISCSIVolumeSource":                                                       schema_k8sio_api_core_v1_ISCSIVolumeSource(ref),
-		"k8s.io/api/core/v1.KeyToPath":                                                               schema_k8sio_api_core_v1_KeyToPath(ref),
-		"k8s.io/api/core/v1.Lifecycle":                                                               schema_k8sio_api_core_v1_Lifecycle(ref),
-		"k8s.io/api/core/v1.LifecycleHandler":                                                        schema_k8sio_api_core_v1_LifecycleHandler(ref),
-		"k8s.io/api/core/v1.LimitRange":                                                              schema_k8sio_api_core_v1_LimitRange(ref),
-		"k8s.io/api/core/v1.LimitRangeItem":                                                          schema_k8sio_api_core_v1_LimitRangeItem(ref),
-		"k8s.io/api/core/v1.LimitRangeList":                                                          schema_k8sio_api_core_v1_LimitRangeList(ref),
-		"k8s.io/api/core/v1.LimitRangeSpec":                                                          schema_k8sio_api_core_v1_LimitRangeSpec(ref),
-		"k8s.io/api/core/v1.List":                                                                    schema_k8sio_api_core_v1_List(ref),
-		"k8s.io/api/core/v1.LoadBalancerIngress":                                                     schema_k8sio_api_core_v1_LoadBalancerIngress(ref),
-		"k8s.io/api/core/v1.LoadBalancerStatus":                                                      schema_k8sio_api_core_v1_LoadBalancerStatus(ref),
-		"k8s.io/api/core/v1.LocalObjectReference":                                                    schema_k8sio_api_core_v1_LocalObjectReference(ref),
-		"k8s.io/api/core/v1.LocalVolumeSource":                                                       schema_k8sio_api_core_v1_LocalVolumeSource(ref),
-		"k8s.io/api/core/v1.NFSVolumeSource":                                                         schema_k8sio_api_core_v1_NFSVolumeSource(ref),
-		"k8s.io/api/core/v1.Namespace":                                                               schema_k8sio_api_core_v1_Namespace(ref),
-		"k8s.io/api/core/v1.NamespaceCondition":                                                      schema_k8sio_api_core_v1_NamespaceCondition(ref),
-		"k8s.io/api/core/v1.NamespaceList":                                                           schema_k8sio_api_core_v1_NamespaceList(ref),
-		"k8s.io/api/core/v1.NamespaceSpec":                                                           schema_k8sio_api_core_v1_NamespaceSpec(ref),
-		"k8s.io/api/core/v1.NamespaceStatus":                                                         schema_k8sio_api_core_v1_NamespaceStatus(ref),
-		"k8s.io/api/core/v1.Node":                                                                    schema_k8sio_api_core_v1_Node(ref),
-		"k8s.io/api/core/v1.NodeAddress":                                                             schema_k8sio_api_core_v1_NodeAddress(ref),
-		"k8s.io/api/core/v1.NodeAffinity":                                                            schema_k8sio_api_core_v1_NodeAffinity(ref),
-		"k8s.io/api/core/v1.NodeCondition":                                                           schema_k8sio_api_core_v1_NodeCondition(ref),
-		"k8s.io/api/core/v1.NodeConfigSource":                                                        schema_k8sio_api_core_v1_NodeConfigSource(ref),
-		"k8s.io/api/core/v1.NodeConfigStatus":                                                        schema_k8sio_api_core_v1_NodeConfigStatus(ref),
-		"k8s.io/api/core/v1.NodeDaemonEndpoints":                                                     schema_k8sio_api_core_v1_NodeDaemonEndpoints(ref),
-		"k8s.io/api/core/v1.NodeList":                                                                schema_k8sio_api_core_v1_NodeList(ref),
-		"k8s.io/api/core/v1.NodeProxyOptions":                                                        schema_k8sio_api_core_v1_NodeProxyOptions(ref),
-		"k8s.io/api/core/v1.NodeResources":                                                           schema_k8sio_api_core_v1_NodeResources(ref),
-		"k8s.io/api/core/v1.NodeSelector":                                                            schema_k8sio_api_core_v1_NodeSelector(ref),
-		"k8s.io/api/core/v1.NodeSelectorRequirement":                                                 schema_k8sio_api_core_v1_NodeSelectorRequirement(ref),
-		"k8s.io/api/core/v1.NodeSelectorTerm":                                                        schema_k8sio_api_core_v1_NodeSelectorTerm(ref),
-		"k8s.io/api/core/v1.NodeSpec":                                                                schema_k8sio_api_core_v1_NodeSpec(ref),
-		"k8s.io/api/core/v1.NodeStatus":                                                              schema_k8sio_api_core_v1_NodeStatus(ref),
-		"k8s.io/api/core/v1.NodeSystemInfo":                                                          schema_k8sio_api_core_v1_NodeSystemInfo(ref),
-		"k8s.io/api/core/v1.ObjectFieldSelector":                                                     schema_k8sio_api_core_v1_ObjectFieldSelector(ref),
-		"k8s.io/api/core/v1.ObjectReference":                                                         schema_k8sio_api_core_v1_ObjectReference(ref),
-		"k8s.io/api/core/v1.PersistentVolume":                                                        schema_k8sio_api_core_v1_PersistentVolume(ref),
-		"k8s.io/api/core/v1.PersistentVolumeClaim":                                                   schema_k8sio_api_core_v1_PersistentVolumeClaim(ref),
-		"k8s.io/api/core/v1.PersistentVolumeClaimCondition":                                          schema_k8sio_api_core_v1_PersistentVolumeClaimCondition(ref),
-		"k8s.io/api/core/v1.PersistentVolumeClaimList":                                               schema_k8sio_api_core_v1_PersistentVolumeClaimList(ref),
-		"k8s.io/api/core/v1.PersistentVolumeClaimSpec":                                               schema_k8sio_api_core_v1_PersistentVolumeClaimSpec(ref),
-		"k8s.io/api/core/v1.PersistentVolumeClaimStatus":                                             schema_k8sio_api_core_v1_PersistentVolumeClaimStatus(ref),
-		"k8s.io/api/core/v1.PersistentVolumeClaimTemplate":                                           schema_k8sio_api_core_v1_PersistentVolumeClaimTemplate(ref),
-		"k8s.io/api/core/v1.PersistentVolumeClaimVolumeSource":                                       schema_k8sio_api_core_v1_PersistentVolumeClaimVolumeSource(ref),
-		"k8s.io/api/core/v1.PersistentVolumeList":                                                    schema_k8sio_api_core_v1_PersistentVolumeList(ref),
-		"k8s.io/api/core/v1.PersistentVolumeSource":                                                  schema_k8sio_api_core_v1_PersistentVolumeSource(ref),
-		"k8s.io/api/core/v1.PersistentVolumeSpec":                                                    schema_k8sio_api_core_v1_PersistentVolumeSpec(ref),
-		"k8s.io/api/core/v1.PersistentVolumeStatus":                                                  schema_k8sio_api_core_v1_PersistentVolumeStatus(ref),
-		"k8s.io/api/core/v1.PhotonPersistentDiskVolumeSource":                                        schema_k8sio_api_core_v1_PhotonPersistentDiskVolumeSource(ref),
-		"k8s.io/api/core/v1.Pod":                                                                     schema_k8sio_api_core_v1_Pod(ref),
-		"k8s.io/api/core/v1.PodAffinity":                                                             schema_k8sio_api_core_v1_PodAffinity(ref),
-		"k8s.io/api/core/v1.PodAffinityTerm":                                                         schema_k8sio_api_core_v1_PodAffinityTerm(ref),
-		"k8s.io/api/core/v1.PodAntiAffinity":                                                         schema_k8sio_api_core_v1_PodAntiAffinity(ref),
-		"k8s.io/api/core/v1.PodAttachOptions":                                                        schema_k8sio_api_core_v1_PodAttachOptions(ref),
-		"k8s.io/api/core/v1.PodCondition":                                                            schema_k8sio_api_core_v1_PodCondition(ref),
-		"k8s.io/api/core/v1.PodDNSConfig":                                                            schema_k8sio_api_core_v1_PodDNSConfig(ref),
-		"k8s.io/api/core/v1.PodDNSConfigOption":                                                      schema_k8sio_api_core_v1_PodDNSConfigOption(ref),
-		"k8s.io/api/core/v1.PodExecOptions":                                                          schema_k8sio_api_core_v1_PodExecOptions(ref),
-		"k8s.io/api/core/v1.PodIP":                                                                   schema_k8sio_api_core_v1_PodIP(ref),
-		"k8s.io/api/core/v1.PodList":                                                                 schema_k8sio_api_core_v1_PodList(ref),
-		"k8s.io/api/core/v1.PodLogOptions":                                                           schema_k8sio_api_core_v1_PodLogOptions(ref),
-		"k8s.io/api/core/v1.PodOS":                                                                   schema_k8sio_api_core_v1_PodOS(ref),
-		"k8s.io/api/core/v1.PodPortForwardOptions":                                                   schema_k8sio_api_core_v1_PodPortForwardOptions(ref),
-		"k8s.io/api/core/v1.PodProxyOptions":                                                         schema_k8sio_api_core_v1_PodProxyOptions(ref),
-		"k8s.io/api/core/v1.PodReadinessGate":                                                        schema_k8sio_api_core_v1_PodReadinessGate(ref),
-		"k8s.io/api/core/v1.PodResourceClaim":                                                        schema_k8sio_api_core_v1_PodResourceClaim(ref),
-		"k8s.io/api/core/v1.PodSchedulingGate":                                                       schema_k8sio_api_core_v1_PodSchedulingGate(ref),
-		"k8s.io/api/core/v1.PodSecurityContext":                                                      schema_k8sio_api_core_v1_PodSecurityContext(ref),
-		"k8s.io/api/core/v1.PodSignature":                                                            schema_k8sio_api_core_v1_PodSignature(ref),
-		"k8s.io/api/core/v1.PodSpec":                                                                 schema_k8sio_api_core_v1_PodSpec(ref),
-		"k8s.io/api/core/v1.PodStatus":                                                               schema_k8sio_api_core_v1_PodStatus(ref),
-		"k8s.io/api/core/v1.PodStatusResult":                                                         schema_k8sio_api_core_v1_PodStatusResult(ref),
-		"k8s.io/api/core/v1.PodTemplate":                                                             schema_k8sio_api_core_v1_PodTemplate(ref),
-		"k8s.io/api/core/v1.PodTemplateList":                                                         schema_k8sio_api_core_v1_PodTemplateList(ref),
-		"k8s.io/api/core/v1.PodTemplateSpec":                                                         schema_k8sio_api_core_v1_PodTemplateSpec(ref),
-		"k8s.io/api/core/v1.PortStatus":                                                              schema_k8sio_api_core_v1_PortStatus(ref),
-		"k8s.io/api/core/v1.PortworxVolumeSource":                                                    schema_k8sio_api_core_v1_PortworxVolumeSource(ref),
-		"k8s.io/api/core/v1.PreferAvoidPodsEntry":                                                    schema_k8sio_api_core_v1_PreferAvoidPodsEntry(ref),
-		"k8s.io/api/core/v1.PreferredSchedulingTerm":                                                 schema_k8sio_api_core_v1_PreferredSchedulingTerm(ref),
-		"k8s.io/api/core/v1.Probe":                                                                   schema_k8sio_api_core_v1_Probe(ref),
-		"k8s.io/api/core/v1.ProbeHandler":                                                            schema_k8sio_api_core_v1_ProbeHandler(ref),
-		"k8s.io/api/core/v1.ProjectedVolumeSource":                                                   schema_k8sio_api_core_v1_ProjectedVolumeSource(ref),
-		"k8s.io/api/core/v1.QuobyteVolumeSource":                                                     schema_k8sio_api_core_v1_QuobyteVolumeSource(ref),
-		"k8s.io/api/core/v1.RBDPersistentVolumeSource":                                               schema_k8sio_api_core_v1_RBDPersistentVolumeSource(ref),
-		"k8s.io/api/core/v1.RBDVolumeSource":                                                         schema_k8sio_api_core_v1_RBDVolumeSource(ref),
-		"k8s.io/api/core/v1.RangeAllocation":                                                         schema_k8sio_api_core_v1_RangeAllocation(ref),
-		"k8s.io/api/core/v1.ReplicationController":                                                   schema_k8sio_api_core_v1_ReplicationController(ref),
-		"k8s.io/api/core/v1.ReplicationControllerCondition":                                          schema_k8sio_api_core_v1_ReplicationControllerCondition(ref),
-		"k8s.io/api/core/v1.ReplicationControllerList":                                               schema_k8sio_api_core_v1_ReplicationControllerList(ref),
-		"k8s.io/api/core/v1.ReplicationControllerSpec":                                               schema_k8sio_api_core_v1_ReplicationControllerSpec(ref),
-		"k8s.io/api/core/v1.ReplicationControllerStatus":                                             schema_k8sio_api_core_v1_ReplicationControllerStatus(ref),
-		"k8s.io/api/core/v1.ResourceClaim":                                                           schema_k8sio_api_core_v1_ResourceClaim(ref),
-		"k8s.io/api/core/v1.ResourceFieldSelector":                                                   schema_k8sio_api_core_v1_ResourceFieldSelector(ref),
-		"k8s.io/api/core/v1.ResourceQuota":                                                           schema_k8sio_api_core_v1_ResourceQuota(ref),
-		"k8s.io/api/core/v1.ResourceQuotaList":                                                       schema_k8sio_api_core_v1_ResourceQuotaList(ref),
-		"k8s.io/api/core/v1.ResourceQuotaSpec":                                                       schema_k8sio_api_core_v1_ResourceQuotaSpec(ref),
-		"k8s.io/api/core/v1.ResourceQuotaStatus":                                                     schema_k8sio_api_core_v1_ResourceQuotaStatus(ref),
-		"k8s.io/api/core/v1.ResourceRequirements":                                                    schema_k8sio_api_core_v1_ResourceRequirements(ref),
-		"k8s.io/api/core/v1.SELinuxOptions":                                                          schema_k8sio_api_core_v1_SELinuxOptions(ref),
-		"k8s.io/api/core/v1.ScaleIOPersistentVolumeSource":                                           schema_k8sio_api_core_v1_ScaleIOPersistentVolumeSource(ref),
-		"k8s.io/api/core/v1.ScaleIOVolumeSource":                                                     schema_k8sio_api_core_v1_ScaleIOVolumeSource(ref),
-		"k8s.io/api/core/v1.ScopeSelector":                                                           schema_k8sio_api_core_v1_ScopeSelector(ref),
-		"k8s.io/api/core/v1.ScopedResourceSelectorRequirement":                                       schema_k8sio_api_core_v1_ScopedResourceSelectorRequirement(ref),
-		"k8s.io/api/core/v1.SeccompProfile":                                                          schema_k8sio_api_core_v1_SeccompProfile(ref),
-		"k8s.io/api/core/v1.Secret":                                                                  schema_k8sio_api_core_v1_Secret(ref),
-		"k8s.io/api/core/v1.SecretEnvSource":                                                         schema_k8sio_api_core_v1_SecretEnvSource(ref),
-		"k8s.io/api/core/v1.SecretKeySelector":                                                       schema_k8sio_api_core_v1_SecretKeySelector(ref),
-		"k8s.io/api/core/v1.SecretList":                                                              schema_k8sio_api_core_v1_SecretList(ref),
-		"k8s.io/api/core/v1.SecretProjection":                                                        schema_k8sio_api_core_v1_SecretProjection(ref),
-		"k8s.io/api/core/v1.SecretReference":                                                         schema_k8sio_api_core_v1_SecretReference(ref),
-		"k8s.io/api/core/v1.SecretVolumeSource":                                                      schema_k8sio_api_core_v1_SecretVolumeSource(ref),
-		"k8s.io/api/core/v1.SecurityContext":                                                         schema_k8sio_api_core_v1_SecurityContext(ref),
-		"k8s.io/api/core/v1.SerializedReference":                                                     schema_k8sio_api_core_v1_SerializedReference(ref),
-		"k8s.io/api/core/v1.Service":                                                                 schema_k8sio_api_core_v1_Service(ref),
-		"k8s.io/api/core/v1.ServiceAccount":                                                          schema_k8sio_api_core_v1_ServiceAccount(ref),
-		"k8s.io/api/core/v1.ServiceAccountList":                                                      schema_k8sio_api_core_v1_ServiceAccountList(ref),
-		"k8s.io/api/core/v1.ServiceAccountTokenProjection":                                           schema_k8sio_api_core_v1_ServiceAccountTokenProjection(ref),
-		"k8s.io/api/core/v1.ServiceList":                                                             schema_k8sio_api_core_v1_ServiceList(ref),
-		"k8s.io/api/core/v1.ServicePort":                                                             schema_k8sio_api_core_v1_ServicePort(ref),
-		"k8s.io/api/core/v1.ServiceProxyOptions":                                                     schema_k8sio_api_core_v1_ServiceProxyOptions(ref),
-		"k8s.io/api/core/v1.ServiceSpec":                                                             schema_k8sio_api_core_v1_ServiceSpec(ref),
-		"k8s.io/api/core/v1.ServiceStatus":                                                           schema_k8sio_api_core_v1_ServiceStatus(ref),
-		"k8s.io/api/core/v1.SessionAffinityConfig":                                                   schema_k8sio_api_core_v1_SessionAffinityConfig(ref),
-		"k8s.io/api/core/v1.StorageOSPersistentVolumeSource":                                         schema_k8sio_api_core_v1_StorageOSPersistentVolumeSource(ref),
-		"k8s.io/api/core/v1.StorageOSVolumeSource":                                                   schema_k8sio_api_core_v1_StorageOSVolumeSource(ref),
-		"k8s.io/api/core/v1.Sysctl":                                                                  schema_k8sio_api_core_v1_Sysctl(ref),
-		"k8s.io/api/core/v1.TCPSocketAction":                                                         schema_k8sio_api_core_v1_TCPSocketAction(ref),
-		"k8s.io/api/core/v1.Taint":                                                                   schema_k8sio_api_core_v1_Taint(ref),
-		"k8s.io/api/core/v1.Toleration":                                                              schema_k8sio_api_core_v1_Toleration(ref),
-		"k8s.io/api/core/v1.TopologySelectorLabelRequirement":                                        schema_k8sio_api_core_v1_TopologySelectorLabelRequirement(ref),
-		"k8s.io/api/core/v1.TopologySelectorTerm":                                                    schema_k8sio_api_core_v1_TopologySelectorTerm(ref),
-		"k8s.io/api/core/v1.TopologySpreadConstraint":                                                schema_k8sio_api_core_v1_TopologySpreadConstraint(ref),
-		"k8s.io/api/core/v1.TypedLocalObjectReference":                                               schema_k8sio_api_core_v1_TypedLocalObjectReference(ref),
-		"k8s.io/api/core/v1.TypedObjectReference":                                                    schema_k8sio_api_core_v1_TypedObjectReference(ref),
-		"k8s.io/api/core/v1.Volume":                                                                  schema_k8sio_api_core_v1_Volume(ref),
-		"k8s.io/api/core/v1.VolumeDevice":                                                            schema_k8sio_api_core_v1_VolumeDevice(ref),
-		"k8s.io/api/core/v1.VolumeMount":                                                             schema_k8sio_api_core_v1_VolumeMount(ref),
-		"k8s.io/api/core/v1.VolumeNodeAffinity":                                                      schema_k8sio_api_core_v1_VolumeNodeAffinity(ref),
-		"k8s.io/api/core/v1.VolumeProjection":                                                        schema_k8sio_api_core_v1_VolumeProjection(ref),
-		"k8s.io/api/core/v1.VolumeSource":                                                            schema_k8sio_api_core_v1_VolumeSource(ref),
-		"k8s.io/api/core/v1.VsphereVirtualDiskVolumeSource":                                          schema_k8sio_api_core_v1_VsphereVirtualDiskVolumeSource(ref),
-		"k8s.io/api/core/v1.WeightedPodAffinityTerm":                                                 schema_k8sio_api_core_v1_WeightedPodAffinityTerm(ref),
-		"k8s.io/api/core/v1.WindowsSecurityContextOptions":                                           schema_k8sio_api_core_v1_WindowsSecurityContextOptions(ref),
-		"k8s.io/apimachinery/pkg/api/resource.Quantity":                                              schema_apimachinery_pkg_api_resource_Quantity(ref),
-		"k8s.io/apimachinery/pkg/api/resource.int64Amount":                                           schema_apimachinery_pkg_api_resource_int64Amount(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.APIGroup":                                              schema_pkg_apis_meta_v1_APIGroup(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.APIGroupList":                                          schema_pkg_apis_meta_v1_APIGroupList(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.APIResource":                                           schema_pkg_apis_meta_v1_APIResource(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.APIResourceList":                                       schema_pkg_apis_meta_v1_APIResourceList(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.APIVersions":                                           schema_pkg_apis_meta_v1_APIVersions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.ApplyOptions":                                          schema_pkg_apis_meta_v1_ApplyOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Condition":                                             schema_pkg_apis_meta_v1_Condition(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.CreateOptions":                                         schema_pkg_apis_meta_v1_CreateOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.DeleteOptions":                                         schema_pkg_apis_meta_v1_DeleteOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Duration":                                              schema_pkg_apis_meta_v1_Duration(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.FieldsV1":                                              schema_pkg_apis_meta_v1_FieldsV1(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.GetOptions":                                            schema_pkg_apis_meta_v1_GetOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupKind":                                             schema_pkg_apis_meta_v1_GroupKind(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupResource":                                         schema_pkg_apis_meta_v1_GroupResource(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersion":                                          schema_pkg_apis_meta_v1_GroupVersion(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersionForDiscovery":                              schema_pkg_apis_meta_v1_GroupVersionForDiscovery(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersionKind":                                      schema_pkg_apis_meta_v1_GroupVersionKind(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersionResource":                                  schema_pkg_apis_meta_v1_GroupVersionResource(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.InternalEvent":                                         schema_pkg_apis_meta_v1_InternalEvent(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector":                                         schema_pkg_apis_meta_v1_LabelSelector(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelectorRequirement":                              schema_pkg_apis_meta_v1_LabelSelectorRequirement(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.List":                                                  schema_pkg_apis_meta_v1_List(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta":                                              schema_pkg_apis_meta_v1_ListMeta(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.ListOptions":                                           schema_pkg_apis_meta_v1_ListOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.ManagedFieldsEntry":                                    schema_pkg_apis_meta_v1_ManagedFieldsEntry(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime":                                             schema_pkg_apis_meta_v1_MicroTime(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta":                                            schema_pkg_apis_meta_v1_ObjectMeta(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.OwnerReference":                                        schema_pkg_apis_meta_v1_OwnerReference(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.PartialObjectMetadata":                                 schema_pkg_apis_meta_v1_PartialObjectMetadata(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.PartialObjectMetadataList":                             schema_pkg_apis_meta_v1_PartialObjectMetadataList(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Patch":                                                 schema_pkg_apis_meta_v1_Patch(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.PatchOptions":                                          schema_pkg_apis_meta_v1_PatchOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Preconditions":                                         schema_pkg_apis_meta_v1_Preconditions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.RootPaths":                                             schema_pkg_apis_meta_v1_RootPaths(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.ServerAddressByClientCIDR":                             schema_pkg_apis_meta_v1_ServerAddressByClientCIDR(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Status":                                                schema_pkg_apis_meta_v1_Status(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.StatusCause":                                           schema_pkg_apis_meta_v1_StatusCause(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.StatusDetails":                                         schema_pkg_apis_meta_v1_StatusDetails(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Table":                                                 schema_pkg_apis_meta_v1_Table(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.TableColumnDefinition":                                 schema_pkg_apis_meta_v1_TableColumnDefinition(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.TableOptions":                                          schema_pkg_apis_meta_v1_TableOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.TableRow":                                              schema_pkg_apis_meta_v1_TableRow(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.TableRowCondition":                                     schema_pkg_apis_meta_v1_TableRowCondition(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Time":                                                  schema_pkg_apis_meta_v1_Time(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Timestamp":                                             schema_pkg_apis_meta_v1_Timestamp(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.TypeMeta":                                              schema_pkg_apis_meta_v1_TypeMeta(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.UpdateOptions":                                         schema_pkg_apis_meta_v1_UpdateOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.WatchEvent":                                            schema_pkg_apis_meta_v1_WatchEvent(ref),
-		"k8s.io/apimachinery/pkg/runtime.RawExtension":                                               schema_k8sio_apimachinery_pkg_runtime_RawExtension(ref),
-		"k8s.io/apimachinery/pkg/runtime.TypeMeta":                                                   schema_k8sio_apimachinery_pkg_runtime_TypeMeta(ref),
-		"k8s.io/apimachinery/pkg/runtime.Unknown":                                                    schema_k8sio_apimachinery_pkg_runtime_Unknown(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CDI":                      schema_pkg_apis_core_v1beta1_CDI(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CDICertConfig":            schema_pkg_apis_core_v1beta1_CDICertConfig(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CDIConfig":                schema_pkg_apis_core_v1beta1_CDIConfig(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CDIConfigList":            schema_pkg_apis_core_v1beta1_CDIConfigList(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CDIConfigSpec":            schema_pkg_apis_core_v1beta1_CDIConfigSpec(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CDIConfigStatus":          schema_pkg_apis_core_v1beta1_CDIConfigStatus(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CDIList":                  schema_pkg_apis_core_v1beta1_CDIList(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CDISpec":                  schema_pkg_apis_core_v1beta1_CDISpec(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CDIStatus":                schema_pkg_apis_core_v1beta1_CDIStatus(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CertConfig":               schema_pkg_apis_core_v1beta1_CertConfig(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.ClaimPropertySet":         schema_pkg_apis_core_v1beta1_ClaimPropertySet(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.ConditionState":           schema_pkg_apis_core_v1beta1_ConditionState(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataImportCron":           schema_pkg_apis_core_v1beta1_DataImportCron(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataImportCronCondition":  schema_pkg_apis_core_v1beta1_DataImportCronCondition(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataImportCronList":       schema_pkg_apis_core_v1beta1_DataImportCronList(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataImportCronSpec":       schema_pkg_apis_core_v1beta1_DataImportCronSpec(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataImportCronStatus":     schema_pkg_apis_core_v1beta1_DataImportCronStatus(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataSource":               schema_pkg_apis_core_v1beta1_DataSource(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataSourceCondition":      schema_pkg_apis_core_v1beta1_DataSourceCondition(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataSourceList":           schema_pkg_apis_core_v1beta1_DataSourceList(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataSourceSource":         schema_pkg_apis_core_v1beta1_DataSourceSource(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataSourceSpec":           schema_pkg_apis_core_v1beta1_DataSourceSpec(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataSourceStatus":         schema_pkg_apis_core_v1beta1_DataSourceStatus(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolume":               schema_pkg_apis_core_v1beta1_DataVolume(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeBlankImage":     schema_pkg_apis_core_v1beta1_DataVolumeBlankImage(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeCheckpoint":     schema_pkg_apis_core_v1beta1_DataVolumeCheckpoint(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeCondition":      schema_pkg_apis_core_v1beta1_DataVolumeCondition(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeList":           schema_pkg_apis_core_v1beta1_DataVolumeList(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSource":         schema_pkg_apis_core_v1beta1_DataVolumeSource(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceGCS":      schema_pkg_apis_core_v1beta1_DataVolumeSourceGCS(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceHTTP":     schema_pkg_apis_core_v1beta1_DataVolumeSourceHTTP(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceImageIO":  schema_pkg_apis_core_v1beta1_DataVolumeSourceImageIO(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourcePVC":      schema_pkg_apis_core_v1beta1_DataVolumeSourcePVC(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceRef":      schema_pkg_apis_core_v1beta1_DataVolumeSourceRef(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceRegistry": schema_pkg_apis_core_v1beta1_DataVolumeSourceRegistry(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceS3":       schema_pkg_apis_core_v1beta1_DataVolumeSourceS3(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceSnapshot": schema_pkg_apis_core_v1beta1_DataVolumeSourceSnapshot(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceUpload":   schema_pkg_apis_core_v1beta1_DataVolumeSourceUpload(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceVDDK":     schema_pkg_apis_core_v1beta1_DataVolumeSourceVDDK(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSpec":           schema_pkg_apis_core_v1beta1_DataVolumeSpec(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeStatus":         schema_pkg_apis_core_v1beta1_DataVolumeStatus(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.FilesystemOverhead":       schema_pkg_apis_core_v1beta1_FilesystemOverhead(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.ImportProxy":              schema_pkg_apis_core_v1beta1_ImportProxy(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.ImportStatus":             schema_pkg_apis_core_v1beta1_ImportStatus(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.ObjectTransfer":           schema_pkg_apis_core_v1beta1_ObjectTransfer(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.ObjectTransferCondition":  schema_pkg_apis_core_v1beta1_ObjectTransferCondition(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.ObjectTransferList":       schema_pkg_apis_core_v1beta1_ObjectTransferList(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.ObjectTransferSpec":       schema_pkg_apis_core_v1beta1_ObjectTransferSpec(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.ObjectTransferStatus":     schema_pkg_apis_core_v1beta1_ObjectTransferStatus(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.StorageProfile":           schema_pkg_apis_core_v1beta1_StorageProfile(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.StorageProfileList":       schema_pkg_apis_core_v1beta1_StorageProfileList(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.StorageProfileSpec":       schema_pkg_apis_core_v1beta1_StorageProfileSpec(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.StorageProfileStatus":     schema_pkg_apis_core_v1beta1_StorageProfileStatus(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.StorageSpec":              schema_pkg_apis_core_v1beta1_StorageSpec(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.TransferSource":           schema_pkg_apis_core_v1beta1_TransferSource(ref),
-		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.TransferTarget":           schema_pkg_apis_core_v1beta1_TransferTarget(ref),
-		"kubevirt.io/controller-lifecycle-operator-sdk/api.NodePlacement":                            schema_kubevirtio_controller_lifecycle_operator_sdk_api_NodePlacement(ref),
+		"github.com/openshift/api/config/v1.APIServer":                                                       schema_openshift_api_config_v1_APIServer(ref),
+		"github.com/openshift/api/config/v1.APIServerEncryption":                                             schema_openshift_api_config_v1_APIServerEncryption(ref),
+		"github.com/openshift/api/config/v1.APIServerList":                                                   schema_openshift_api_config_v1_APIServerList(ref),
+		"github.com/openshift/api/config/v1.APIServerNamedServingCert":                                       schema_openshift_api_config_v1_APIServerNamedServingCert(ref),
+		"github.com/openshift/api/config/v1.APIServerServingCerts":                                           schema_openshift_api_config_v1_APIServerServingCerts(ref),
+		"github.com/openshift/api/config/v1.APIServerSpec":                                                   schema_openshift_api_config_v1_APIServerSpec(ref),
+		"github.com/openshift/api/config/v1.APIServerStatus":                                                 schema_openshift_api_config_v1_APIServerStatus(ref),
+		"github.com/openshift/api/config/v1.AWSIngressSpec":                                                  schema_openshift_api_config_v1_AWSIngressSpec(ref),
+		"github.com/openshift/api/config/v1.AWSPlatformSpec":                                                 schema_openshift_api_config_v1_AWSPlatformSpec(ref),
+		"github.com/openshift/api/config/v1.AWSPlatformStatus":                                               schema_openshift_api_config_v1_AWSPlatformStatus(ref),
+		"github.com/openshift/api/config/v1.AWSResourceTag":                                                  schema_openshift_api_config_v1_AWSResourceTag(ref),
+		"github.com/openshift/api/config/v1.AWSServiceEndpoint":                                              schema_openshift_api_config_v1_AWSServiceEndpoint(ref),
+		"github.com/openshift/api/config/v1.AdmissionConfig":                                                 schema_openshift_api_config_v1_AdmissionConfig(ref),
+		"github.com/openshift/api/config/v1.AdmissionPluginConfig":                                           schema_openshift_api_config_v1_AdmissionPluginConfig(ref),
+		"github.com/openshift/api/config/v1.AlibabaCloudPlatformSpec":                                        schema_openshift_api_config_v1_AlibabaCloudPlatformSpec(ref),
+		"github.com/openshift/api/config/v1.AlibabaCloudPlatformStatus":                                      schema_openshift_api_config_v1_AlibabaCloudPlatformStatus(ref),
+		"github.com/openshift/api/config/v1.AlibabaCloudResourceTag":                                         schema_openshift_api_config_v1_AlibabaCloudResourceTag(ref),
+		"github.com/openshift/api/config/v1.Audit":                                                           schema_openshift_api_config_v1_Audit(ref),
+		"github.com/openshift/api/config/v1.AuditConfig":                                                     schema_openshift_api_config_v1_AuditConfig(ref),
+		"github.com/openshift/api/config/v1.AuditCustomRule":                                                 schema_openshift_api_config_v1_AuditCustomRule(ref),
+		"github.com/openshift/api/config/v1.Authentication":                                                  schema_openshift_api_config_v1_Authentication(ref),
+		"github.com/openshift/api/config/v1.AuthenticationList":                                              schema_openshift_api_config_v1_AuthenticationList(ref),
+		"github.com/openshift/api/config/v1.AuthenticationSpec":                                              schema_openshift_api_config_v1_AuthenticationSpec(ref),
+		"github.com/openshift/api/config/v1.AuthenticationStatus":                                            schema_openshift_api_config_v1_AuthenticationStatus(ref),
+		"github.com/openshift/api/config/v1.AzurePlatformSpec":                                               schema_openshift_api_config_v1_AzurePlatformSpec(ref),
+		"github.com/openshift/api/config/v1.AzurePlatformStatus":                                             schema_openshift_api_config_v1_AzurePlatformStatus(ref),
+		"github.com/openshift/api/config/v1.AzureResourceTag":                                                schema_openshift_api_config_v1_AzureResourceTag(ref),
+		"github.com/openshift/api/config/v1.BareMetalPlatformLoadBalancer":                                   schema_openshift_api_config_v1_BareMetalPlatformLoadBalancer(ref),
+		"github.com/openshift/api/config/v1.BareMetalPlatformSpec":                                           schema_openshift_api_config_v1_BareMetalPlatformSpec(ref),
+		"github.com/openshift/api/config/v1.BareMetalPlatformStatus":                                         schema_openshift_api_config_v1_BareMetalPlatformStatus(ref),
+		"github.com/openshift/api/config/v1.BasicAuthIdentityProvider":                                       schema_openshift_api_config_v1_BasicAuthIdentityProvider(ref),
+		"github.com/openshift/api/config/v1.Build":                                                           schema_openshift_api_config_v1_Build(ref),
+		"github.com/openshift/api/config/v1.BuildDefaults":                                                   schema_openshift_api_config_v1_BuildDefaults(ref),
+		"github.com/openshift/api/config/v1.BuildList":                                                       schema_openshift_api_config_v1_BuildList(ref),
+		"github.com/openshift/api/config/v1.BuildOverrides":                                                  schema_openshift_api_config_v1_BuildOverrides(ref),
+		"github.com/openshift/api/config/v1.BuildSpec":                                                       schema_openshift_api_config_v1_BuildSpec(ref),
+		"github.com/openshift/api/config/v1.CertInfo":                                                        schema_openshift_api_config_v1_CertInfo(ref),
+		"github.com/openshift/api/config/v1.ClientConnectionOverrides":                                       schema_openshift_api_config_v1_ClientConnectionOverrides(ref),
+		"github.com/openshift/api/config/v1.ClusterCondition":                                                schema_openshift_api_config_v1_ClusterCondition(ref),
+		"github.com/openshift/api/config/v1.ClusterNetworkEntry":                                             schema_openshift_api_config_v1_ClusterNetworkEntry(ref),
+		"github.com/openshift/api/config/v1.ClusterOperator":                                                 schema_openshift_api_config_v1_ClusterOperator(ref),
+		"github.com/openshift/api/config/v1.ClusterOperatorList":                                             schema_openshift_api_config_v1_ClusterOperatorList(ref),
+		"github.com/openshift/api/config/v1.ClusterOperatorSpec":                                             schema_openshift_api_config_v1_ClusterOperatorSpec(ref),
+		"github.com/openshift/api/config/v1.ClusterOperatorStatus":                                           schema_openshift_api_config_v1_ClusterOperatorStatus(ref),
+		"github.com/openshift/api/config/v1.ClusterOperatorStatusCondition":                                  schema_openshift_api_config_v1_ClusterOperatorStatusCondition(ref),
+		"github.com/openshift/api/config/v1.ClusterVersion":                                                  schema_openshift_api_config_v1_ClusterVersion(ref),
+		"github.com/openshift/api/config/v1.ClusterVersionCapabilitiesSpec":                                  schema_openshift_api_config_v1_ClusterVersionCapabilitiesSpec(ref),
+		"github.com/openshift/api/config/v1.ClusterVersionCapabilitiesStatus":                                schema_openshift_api_config_v1_ClusterVersionCapabilitiesStatus(ref),
+		"github.com/openshift/api/config/v1.ClusterVersionList":                                              schema_openshift_api_config_v1_ClusterVersionList(ref),
+		"github.com/openshift/api/config/v1.ClusterVersionSpec":                                              schema_openshift_api_config_v1_ClusterVersionSpec(ref),
+		"github.com/openshift/api/config/v1.ClusterVersionStatus":                                            schema_openshift_api_config_v1_ClusterVersionStatus(ref),
+		"github.com/openshift/api/config/v1.ComponentOverride":                                               schema_openshift_api_config_v1_ComponentOverride(ref),
+		"github.com/openshift/api/config/v1.ComponentRouteSpec":                                              schema_openshift_api_config_v1_ComponentRouteSpec(ref),
+		"github.com/openshift/api/config/v1.ComponentRouteStatus":                                            schema_openshift_api_config_v1_ComponentRouteStatus(ref),
+		"github.com/openshift/api/config/v1.ConditionalUpdate":                                               schema_openshift_api_config_v1_ConditionalUpdate(ref),
+		"github.com/openshift/api/config/v1.ConditionalUpdateRisk":                                           schema_openshift_api_config_v1_ConditionalUpdateRisk(ref),
+		"github.com/openshift/api/config/v1.ConfigMapFileReference":                                          schema_openshift_api_config_v1_ConfigMapFileReference(ref),
+		"github.com/openshift/api/config/v1.ConfigMapNameReference":                                          schema_openshift_api_config_v1_ConfigMapNameReference(ref),
+		"github.com/openshift/api/config/v1.Console":                                                         schema_openshift_api_config_v1_Console(ref),
+		"github.com/openshift/api/config/v1.ConsoleAuthentication":                                           schema_openshift_api_config_v1_ConsoleAuthentication(ref),
+		"github.com/openshift/api/config/v1.ConsoleList":                                                     schema_openshift_api_config_v1_ConsoleList(ref),
+		"github.com/openshift/api/config/v1.ConsoleSpec":                                                     schema_openshift_api_config_v1_ConsoleSpec(ref),
+		"github.com/openshift/api/config/v1.ConsoleStatus":                                                   schema_openshift_api_config_v1_ConsoleStatus(ref),
+		"github.com/openshift/api/config/v1.CustomFeatureGates":                                              schema_openshift_api_config_v1_CustomFeatureGates(ref),
+		"github.com/openshift/api/config/v1.CustomTLSProfile":                                                schema_openshift_api_config_v1_CustomTLSProfile(ref),
+		"github.com/openshift/api/config/v1.DNS":                                                             schema_openshift_api_config_v1_DNS(ref),
+		"github.com/openshift/api/config/v1.DNSList":                                                         schema_openshift_api_config_v1_DNSList(ref),
+		"github.com/openshift/api/config/v1.DNSSpec":                                                         schema_openshift_api_config_v1_DNSSpec(ref),
+		"github.com/openshift/api/config/v1.DNSStatus":                                                       schema_openshift_api_config_v1_DNSStatus(ref),
+		"github.com/openshift/api/config/v1.DNSZone":                                                         schema_openshift_api_config_v1_DNSZone(ref),
+		"github.com/openshift/api/config/v1.DelegatedAuthentication":                                         schema_openshift_api_config_v1_DelegatedAuthentication(ref),
+		"github.com/openshift/api/config/v1.DelegatedAuthorization":                                          schema_openshift_api_config_v1_DelegatedAuthorization(ref),
+		"github.com/openshift/api/config/v1.DeprecatedWebhookTokenAuthenticator":                             schema_openshift_api_config_v1_DeprecatedWebhookTokenAuthenticator(ref),
+		"github.com/openshift/api/config/v1.EquinixMetalPlatformSpec":                                        schema_openshift_api_config_v1_EquinixMetalPlatformSpec(ref),
+		"github.com/openshift/api/config/v1.EquinixMetalPlatformStatus":                                      schema_openshift_api_config_v1_EquinixMetalPlatformStatus(ref),
+		"github.com/openshift/api/config/v1.EtcdConnectionInfo":                                              schema_openshift_api_config_v1_EtcdConnectionInfo(ref),
+		"github.com/openshift/api/config/v1.EtcdStorageConfig":                                               schema_openshift_api_config_v1_EtcdStorageConfig(ref),
+		"github.com/openshift/api/config/v1.ExternalIPConfig":                                                schema_openshift_api_config_v1_ExternalIPConfig(ref),
+		"github.com/openshift/api/config/v1.ExternalIPPolicy":                                                schema_openshift_api_config_v1_ExternalIPPolicy(ref),
+		"github.com/openshift/api/config/v1.ExternalPlatformSpec":                                            schema_openshift_api_config_v1_ExternalPlatformSpec(ref),
+		"github.com/openshift/api/config/v1.ExternalPlatformStatus":                                          schema_openshift_api_config_v1_ExternalPlatformStatus(ref),
+		"github.com/openshift/api/config/v1.FeatureGate":                                                     schema_openshift_api_config_v1_FeatureGate(ref),
+		"github.com/openshift/api/config/v1.FeatureGateEnabledDisabled":                                      schema_openshift_api_config_v1_FeatureGateEnabledDisabled(ref),
+		"github.com/openshift/api/config/v1.FeatureGateList":                                                 schema_openshift_api_config_v1_FeatureGateList(ref),
+		"github.com/openshift/api/config/v1.FeatureGateSelection":                                            schema_openshift_api_config_v1_FeatureGateSelection(ref),
+		"github.com/openshift/api/config/v1.FeatureGateSpec":                                                 schema_openshift_api_config_v1_FeatureGateSpec(ref),
+		"github.com/openshift/api/config/v1.FeatureGateStatus":                                               schema_openshift_api_config_v1_FeatureGateStatus(ref),
+		"github.com/openshift/api/config/v1.GCPPlatformSpec":                                                 schema_openshift_api_config_v1_GCPPlatformSpec(ref),
+		"github.com/openshift/api/config/v1.GCPPlatformStatus":                                               schema_openshift_api_config_v1_GCPPlatformStatus(ref),
+		"github.com/openshift/api/config/v1.GenericAPIServerConfig":                                          schema_openshift_api_config_v1_GenericAPIServerConfig(ref),
+		"github.com/openshift/api/config/v1.GenericControllerConfig":                                         schema_openshift_api_config_v1_GenericControllerConfig(ref),
+		"github.com/openshift/api/config/v1.GitHubIdentityProvider":                                          schema_openshift_api_config_v1_GitHubIdentityProvider(ref),
+		"github.com/openshift/api/config/v1.GitLabIdentityProvider":                                          schema_openshift_api_config_v1_GitLabIdentityProvider(ref),
+		"github.com/openshift/api/config/v1.GoogleIdentityProvider":                                          schema_openshift_api_config_v1_GoogleIdentityProvider(ref),
+		"github.com/openshift/api/config/v1.HTPasswdIdentityProvider":                                        schema_openshift_api_config_v1_HTPasswdIdentityProvider(ref),
+		"github.com/openshift/api/config/v1.HTTPServingInfo":                                                 schema_openshift_api_config_v1_HTTPServingInfo(ref),
+		"github.com/openshift/api/config/v1.HubSource":                                                       schema_openshift_api_config_v1_HubSource(ref),
+		"github.com/openshift/api/config/v1.HubSourceStatus":                                                 schema_openshift_api_config_v1_HubSourceStatus(ref),
+		"github.com/openshift/api/config/v1.IBMCloudPlatformSpec":                                            schema_openshift_api_config_v1_IBMCloudPlatformSpec(ref),
+		"github.com/openshift/api/config/v1.IBMCloudPlatformStatus":                                          schema_openshift_api_config_v1_IBMCloudPlatformStatus(ref),
+		"github.com/openshift/api/config/v1.IdentityProvider":                                                schema_openshift_api_config_v1_IdentityProvider(ref),
+		"github.com/openshift/api/config/v1.IdentityProviderConfig":                                          schema_openshift_api_config_v1_IdentityProviderConfig(ref),
+		"github.com/openshift/api/config/v1.Image":                                                           schema_openshift_api_config_v1_Image(ref),
+		"github.com/openshift/api/config/v1.ImageContentPolicy":                                              schema_openshift_api_config_v1_ImageContentPolicy(ref),
+		"github.com/openshift/api/config/v1.ImageContentPolicyList":                                          schema_openshift_api_config_v1_ImageContentPolicyList(ref),
+		"github.com/openshift/api/config/v1.ImageContentPolicySpec":                                          schema_openshift_api_config_v1_ImageContentPolicySpec(ref),
+		"github.com/openshift/api/config/v1.ImageDigestMirrorSet":                                            schema_openshift_api_config_v1_ImageDigestMirrorSet(ref),
+		"github.com/openshift/api/config/v1.ImageDigestMirrorSetList":                                        schema_openshift_api_config_v1_ImageDigestMirrorSetList(ref),
+		"github.com/openshift/api/config/v1.ImageDigestMirrorSetSpec":                                        schema_openshift_api_config_v1_ImageDigestMirrorSetSpec(ref),
+		"github.com/openshift/api/config/v1.ImageDigestMirrorSetStatus":                                      schema_openshift_api_config_v1_ImageDigestMirrorSetStatus(ref),
+		"github.com/openshift/api/config/v1.ImageDigestMirrors":                                              schema_openshift_api_config_v1_ImageDigestMirrors(ref),
+		"github.com/openshift/api/config/v1.ImageLabel":                                                      schema_openshift_api_config_v1_ImageLabel(ref),
+		"github.com/openshift/api/config/v1.ImageList":                                                       schema_openshift_api_config_v1_ImageList(ref),
+		"github.com/openshift/api/config/v1.ImageSpec":                                                       schema_openshift_api_config_v1_ImageSpec(ref),
+		"github.com/openshift/api/config/v1.ImageStatus":                                                     schema_openshift_api_config_v1_ImageStatus(ref),
+		"github.com/openshift/api/config/v1.ImageTagMirrorSet":                                               schema_openshift_api_config_v1_ImageTagMirrorSet(ref),
+		"github.com/openshift/api/config/v1.ImageTagMirrorSetList":                                           schema_openshift_api_config_v1_ImageTagMirrorSetList(ref),
+		"github.com/openshift/api/config/v1.ImageTagMirrorSetSpec":                                           schema_openshift_api_config_v1_ImageTagMirrorSetSpec(ref),
+		"github.com/openshift/api/config/v1.ImageTagMirrorSetStatus":                                         schema_openshift_api_config_v1_ImageTagMirrorSetStatus(ref),
+		"github.com/openshift/api/config/v1.ImageTagMirrors":                                                 schema_openshift_api_config_v1_ImageTagMirrors(ref),
+		"github.com/openshift/api/config/v1.Infrastructure":                                                  schema_openshift_api_config_v1_Infrastructure(ref),
+		"github.com/openshift/api/config/v1.InfrastructureList":                                              schema_openshift_api_config_v1_InfrastructureList(ref),
+		"github.com/openshift/api/config/v1.InfrastructureSpec":                                              schema_openshift_api_config_v1_InfrastructureSpec(ref),
+		"github.com/openshift/api/config/v1.InfrastructureStatus":                                            schema_openshift_api_config_v1_InfrastructureStatus(ref),
+		"github.com/openshift/api/config/v1.Ingress":                                                         schema_openshift_api_config_v1_Ingress(ref),
+		"github.com/openshift/api/config/v1.IngressList":                                                     schema_openshift_api_config_v1_IngressList(ref),
+		"github.com/openshift/api/config/v1.IngressPlatformSpec":                                             schema_openshift_api_config_v1_IngressPlatformSpec(ref),
+		"github.com/openshift/api/config/v1.IngressSpec":                                                     schema_openshift_api_config_v1_IngressSpec(ref),
+		"github.com/openshift/api/config/v1.IngressStatus":                                                   schema_openshift_api_config_v1_IngressStatus(ref),
+		"github.com/openshift/api/config/v1.IntermediateTLSProfile":                                          schema_openshift_api_config_v1_IntermediateTLSProfile(ref),
+		"github.com/openshift/api/config/v1.KeystoneIdentityProvider":                                        schema_openshift_api_config_v1_KeystoneIdentityProvider(ref),
+		"github.com/openshift/api/config/v1.KubeClientConfig":                                                schema_openshift_api_config_v1_KubeClientConfig(ref),
+		"github.com/openshift/api/config/v1.KubevirtPlatformSpec":                                            schema_openshift_api_config_v1_KubevirtPlatformSpec(ref),
+		"github.com/openshift/api/config/v1.KubevirtPlatformStatus":                                          schema_openshift_api_config_v1_KubevirtPlatformStatus(ref),
+		"github.com/openshift/api/config/v1.LDAPAttributeMapping":                                            schema_openshift_api_config_v1_LDAPAttributeMapping(ref),
+		"github.com/openshift/api/config/v1.LDAPIdentityProvider":                                            schema_openshift_api_config_v1_LDAPIdentityProvider(ref),
+		"github.com/openshift/api/config/v1.LeaderElection":                                                  schema_openshift_api_config_v1_LeaderElection(ref),
+		"github.com/openshift/api/config/v1.LoadBalancer":                                                    schema_openshift_api_config_v1_LoadBalancer(ref),
+		"github.com/openshift/api/config/v1.MTUMigration":                                                    schema_openshift_api_config_v1_MTUMigration(ref),
+		"github.com/openshift/api/config/v1.MTUMigrationValues":                                              schema_openshift_api_config_v1_MTUMigrationValues(ref),
+		"github.com/openshift/api/config/v1.MaxAgePolicy":                                                    schema_openshift_api_config_v1_MaxAgePolicy(ref),
+		"github.com/openshift/api/config/v1.ModernTLSProfile":                                                schema_openshift_api_config_v1_ModernTLSProfile(ref),
+		"github.com/openshift/api/config/v1.NamedCertificate":                                                schema_openshift_api_config_v1_NamedCertificate(ref),
+		"github.com/openshift/api/config/v1.Network":                                                         schema_openshift_api_config_v1_Network(ref),
+		"github.com/openshift/api/config/v1.NetworkList":                                                     schema_openshift_api_config_v1_NetworkList(ref),
+		"github.com/openshift/api/config/v1.NetworkMigration":                                                schema_openshift_api_config_v1_NetworkMigration(ref),
+		"github.com/openshift/api/config/v1.NetworkSpec":                                                     schema_openshift_api_config_v1_NetworkSpec(ref),
+		"github.com/openshift/api/config/v1.NetworkStatus":                                                   schema_openshift_api_config_v1_NetworkStatus(ref),
+		"github.com/openshift/api/config/v1.Node":                                                            schema_openshift_api_config_v1_Node(ref),
+		"github.com/openshift/api/config/v1.NodeList":                                                        schema_openshift_api_config_v1_NodeList(ref),
+		"github.com/openshift/api/config/v1.NodeSpec":                                                        schema_openshift_api_config_v1_NodeSpec(ref),
+		"github.com/openshift/api/config/v1.NodeStatus":                                                      schema_openshift_api_config_v1_NodeStatus(ref),
+		"github.com/openshift/api/config/v1.NutanixPlatformLoadBalancer":                                     schema_openshift_api_config_v1_NutanixPlatformLoadBalancer(ref),
+		"github.com/openshift/api/config/v1.NutanixPlatformSpec":                                             schema_openshift_api_config_v1_NutanixPlatformSpec(ref),
+		"github.com/openshift/api/config/v1.NutanixPlatformStatus":                                           schema_openshift_api_config_v1_NutanixPlatformStatus(ref),
+		"github.com/openshift/api/config/v1.NutanixPrismElementEndpoint":                                     schema_openshift_api_config_v1_NutanixPrismElementEndpoint(ref),
+		"github.com/openshift/api/config/v1.NutanixPrismEndpoint":                                            schema_openshift_api_config_v1_NutanixPrismEndpoint(ref),
+		"github.com/openshift/api/config/v1.OAuth":                                                           schema_openshift_api_config_v1_OAuth(ref),
+		"github.com/openshift/api/config/v1.OAuthList":                                                       schema_openshift_api_config_v1_OAuthList(ref),
+		"github.com/openshift/api/config/v1.OAuthRemoteConnectionInfo":                                       schema_openshift_api_config_v1_OAuthRemoteConnectionInfo(ref),
+		"github.com/openshift/api/config/v1.OAuthSpec":                                                       schema_openshift_api_config_v1_OAuthSpec(ref),
+		"github.com/openshift/api/config/v1.OAuthStatus":                                                     schema_openshift_api_config_v1_OAuthStatus(ref),
+		"github.com/openshift/api/config/v1.OAuthTemplates":                                                  schema_openshift_api_config_v1_OAuthTemplates(ref),
+		"github.com/openshift/api/config/v1.ObjectReference":                                                 schema_openshift_api_config_v1_ObjectReference(ref),
+		"github.com/openshift/api/config/v1.OldTLSProfile":                                                   schema_openshift_api_config_v1_OldTLSProfile(ref),
+		"github.com/openshift/api/config/v1.OpenIDClaims":                                                    schema_openshift_api_config_v1_OpenIDClaims(ref),
+		"github.com/openshift/api/config/v1.OpenIDIdentityProvider":                                          schema_openshift_api_config_v1_OpenIDIdentityProvider(ref),
+		"github.com/openshift/api/config/v1.OpenStackPlatformLoadBalancer":                                   schema_openshift_api_config_v1_OpenStackPlatformLoadBalancer(ref),
+		"github.com/openshift/api/config/v1.OpenStackPlatformSpec":                                           schema_openshift_api_config_v1_OpenStackPlatformSpec(ref),
+		"github.com/openshift/api/config/v1.OpenStackPlatformStatus":                                         schema_openshift_api_config_v1_OpenStackPlatformStatus(ref),
+		"github.com/openshift/api/config/v1.OperandVersion":                                                  schema_openshift_api_config_v1_OperandVersion(ref),
+		"github.com/openshift/api/config/v1.OperatorHub":                                                     schema_openshift_api_config_v1_OperatorHub(ref),
+		"github.com/openshift/api/config/v1.OperatorHubList":                                                 schema_openshift_api_config_v1_OperatorHubList(ref),
+		"github.com/openshift/api/config/v1.OperatorHubSpec":                                                 schema_openshift_api_config_v1_OperatorHubSpec(ref),
+		"github.com/openshift/api/config/v1.OperatorHubStatus":                                               schema_openshift_api_config_v1_OperatorHubStatus(ref),
+		"github.com/openshift/api/config/v1.OvirtPlatformLoadBalancer":                                       schema_openshift_api_config_v1_OvirtPlatformLoadBalancer(ref),
+		"github.com/openshift/api/config/v1.OvirtPlatformSpec":                                               schema_openshift_api_config_v1_OvirtPlatformSpec(ref),
+		"github.com/openshift/api/config/v1.OvirtPlatformStatus":                                             schema_openshift_api_config_v1_OvirtPlatformStatus(ref),
+		"github.com/openshift/api/config/v1.PlatformSpec":                                                    schema_openshift_api_config_v1_PlatformSpec(ref),
+		"github.com/openshift/api/config/v1.PlatformStatus":                                                  schema_openshift_api_config_v1_PlatformStatus(ref),
+		"github.com/openshift/api/config/v1.PowerVSPlatformSpec":                                             schema_openshift_api_config_v1_PowerVSPlatformSpec(ref),
+		"github.com/openshift/api/config/v1.PowerVSPlatformStatus":                                           schema_openshift_api_config_v1_PowerVSPlatformStatus(ref),
+		"github.com/openshift/api/config/v1.PowerVSServiceEndpoint":                                          schema_openshift_api_config_v1_PowerVSServiceEndpoint(ref),
+		"github.com/openshift/api/config/v1.Project":                                                         schema_openshift_api_config_v1_Project(ref),
+		"github.com/openshift/api/config/v1.ProjectList":                                                     schema_openshift_api_config_v1_ProjectList(ref),
+		"github.com/openshift/api/config/v1.ProjectSpec":                                                     schema_openshift_api_config_v1_ProjectSpec(ref),
+		"github.com/openshift/api/config/v1.ProjectStatus":                                                   schema_openshift_api_config_v1_ProjectStatus(ref),
+		"github.com/openshift/api/config/v1.PromQLClusterCondition":                                          schema_openshift_api_config_v1_PromQLClusterCondition(ref),
+		"github.com/openshift/api/config/v1.Proxy":                                                           schema_openshift_api_config_v1_Proxy(ref),
+		"github.com/openshift/api/config/v1.ProxyList":                                                       schema_openshift_api_config_v1_ProxyList(ref),
+		"github.com/openshift/api/config/v1.ProxySpec":                                                       schema_openshift_api_config_v1_ProxySpec(ref),
+		"github.com/openshift/api/config/v1.ProxyStatus":                                                     schema_openshift_api_config_v1_ProxyStatus(ref),
+		"github.com/openshift/api/config/v1.RegistryLocation":                                                schema_openshift_api_config_v1_RegistryLocation(ref),
+		"github.com/openshift/api/config/v1.RegistrySources":                                                 schema_openshift_api_config_v1_RegistrySources(ref),
+		"github.com/openshift/api/config/v1.Release":                                                         schema_openshift_api_config_v1_Release(ref),
+		"github.com/openshift/api/config/v1.RemoteConnectionInfo":                                            schema_openshift_api_config_v1_RemoteConnectionInfo(ref),
+		"github.com/openshift/api/config/v1.RepositoryDigestMirrors":                                         schema_openshift_api_config_v1_RepositoryDigestMirrors(ref),
+		"github.com/openshift/api/config/v1.RequestHeaderIdentityProvider":                                   schema_openshift_api_config_v1_RequestHeaderIdentityProvider(ref),
+		"github.com/openshift/api/config/v1.RequiredHSTSPolicy":                                              schema_openshift_api_config_v1_RequiredHSTSPolicy(ref),
+		"github.com/openshift/api/config/v1.Scheduler":                                                       schema_openshift_api_config_v1_Scheduler(ref),
+		"github.com/openshift/api/config/v1.SchedulerList":                                                   schema_openshift_api_config_v1_SchedulerList(ref),
+		"github.com/openshift/api/config/v1.SchedulerSpec":                                                   schema_openshift_api_config_v1_SchedulerSpec(ref),
+		"github.com/openshift/api/config/v1.SchedulerStatus":                                                 schema_openshift_api_config_v1_SchedulerStatus(ref),
+		"github.com/openshift/api/config/v1.SecretNameReference":                                             schema_openshift_api_config_v1_SecretNameReference(ref),
+		"github.com/openshift/api/config/v1.ServingInfo":                                                     schema_openshift_api_config_v1_ServingInfo(ref),
+		"github.com/openshift/api/config/v1.StringSource":                                                    schema_openshift_api_config_v1_StringSource(ref),
+		"github.com/openshift/api/config/v1.StringSourceSpec":                                                schema_openshift_api_config_v1_StringSourceSpec(ref),
+		"github.com/openshift/api/config/v1.TLSProfileSpec":                                                  schema_openshift_api_config_v1_TLSProfileSpec(ref),
+		"github.com/openshift/api/config/v1.TLSSecurityProfile":                                              schema_openshift_api_config_v1_TLSSecurityProfile(ref),
+		"github.com/openshift/api/config/v1.TemplateReference":                                               schema_openshift_api_config_v1_TemplateReference(ref),
+		"github.com/openshift/api/config/v1.TokenConfig":                                                     schema_openshift_api_config_v1_TokenConfig(ref),
+		"github.com/openshift/api/config/v1.Update":                                                          schema_openshift_api_config_v1_Update(ref),
+		"github.com/openshift/api/config/v1.UpdateHistory":                                                   schema_openshift_api_config_v1_UpdateHistory(ref),
+		"github.com/openshift/api/config/v1.VSpherePlatformFailureDomainSpec":                                schema_openshift_api_config_v1_VSpherePlatformFailureDomainSpec(ref),
+		"github.com/openshift/api/config/v1.VSpherePlatformLoadBalancer":                                     schema_openshift_api_config_v1_VSpherePlatformLoadBalancer(ref),
+		"github.com/openshift/api/config/v1.VSpherePlatformNodeNetworking":                                   schema_openshift_api_config_v1_VSpherePlatformNodeNetworking(ref),
+		"github.com/openshift/api/config/v1.VSpherePlatformNodeNetworkingSpec":                               schema_openshift_api_config_v1_VSpherePlatformNodeNetworkingSpec(ref),
+		"github.com/openshift/api/config/v1.VSpherePlatformSpec":                                             schema_openshift_api_config_v1_VSpherePlatformSpec(ref),
+		"github.com/openshift/api/config/v1.VSpherePlatformStatus":                                           schema_openshift_api_config_v1_VSpherePlatformStatus(ref),
+		"github.com/openshift/api/config/v1.VSpherePlatformTopology":                                         schema_openshift_api_config_v1_VSpherePlatformTopology(ref),
+		"github.com/openshift/api/config/v1.VSpherePlatformVCenterSpec":                                      schema_openshift_api_config_v1_VSpherePlatformVCenterSpec(ref),
+		"github.com/openshift/api/config/v1.WebhookTokenAuthenticator":                                       schema_openshift_api_config_v1_WebhookTokenAuthenticator(ref),
+		"github.com/openshift/api/config/v1.featureSetBuilder":                                               schema_openshift_api_config_v1_featureSetBuilder(ref),
+		"github.com/openshift/custom-resource-status/conditions/v1.Condition":                                schema_openshift_custom_resource_status_conditions_v1_Condition(ref),
+		"k8s.io/api/core/v1.AWSElasticBlockStoreVolumeSource":                                                schema_k8sio_api_core_v1_AWSElasticBlockStoreVolumeSource(ref),
+		"k8s.io/api/core/v1.Affinity":                                                                        schema_k8sio_api_core_v1_Affinity(ref),
+		"k8s.io/api/core/v1.AttachedVolume":                                                                  schema_k8sio_api_core_v1_AttachedVolume(ref),
+		"k8s.io/api/core/v1.AvoidPods":                                                                       schema_k8sio_api_core_v1_AvoidPods(ref),
+		"k8s.io/api/core/v1.AzureDiskVolumeSource":                                                           schema_k8sio_api_core_v1_AzureDiskVolumeSource(ref),
+		"k8s.io/api/core/v1.AzureFilePersistentVolumeSource":                                                 schema_k8sio_api_core_v1_AzureFilePersistentVolumeSource(ref),
+		"k8s.io/api/core/v1.AzureFileVolumeSource":                                                           schema_k8sio_api_core_v1_AzureFileVolumeSource(ref),
+		"k8s.io/api/core/v1.Binding":                                                                         schema_k8sio_api_core_v1_Binding(ref),
+		"k8s.io/api/core/v1.CSIPersistentVolumeSource":                                                       schema_k8sio_api_core_v1_CSIPersistentVolumeSource(ref),
+		"k8s.io/api/core/v1.CSIVolumeSource":                                                                 schema_k8sio_api_core_v1_CSIVolumeSource(ref),
+		"k8s.io/api/core/v1.Capabilities":                                                                    schema_k8sio_api_core_v1_Capabilities(ref),
+		"k8s.io/api/core/v1.CephFSPersistentVolumeSource":                                                    schema_k8sio_api_core_v1_CephFSPersistentVolumeSource(ref),
+		"k8s.io/api/core/v1.CephFSVolumeSource":                                                              schema_k8sio_api_core_v1_CephFSVolumeSource(ref),
+		"k8s.io/api/core/v1.CinderPersistentVolumeSource":                                                    schema_k8sio_api_core_v1_CinderPersistentVolumeSource(ref),
+		"k8s.io/api/core/v1.CinderVolumeSource":                                                              schema_k8sio_api_core_v1_CinderVolumeSource(ref),
+		"k8s.io/api/core/v1.ClaimSource":                                                                     schema_k8sio_api_core_v1_ClaimSource(ref),
+		"k8s.io/api/core/v1.ClientIPConfig":                                                                  schema_k8sio_api_core_v1_ClientIPConfig(ref),
+		"k8s.io/api/core/v1.ComponentCondition":                                                              schema_k8sio_api_core_v1_ComponentCondition(ref),
+		"k8s.io/api/core/v1.ComponentStatus":                                                                 schema_k8sio_api_core_v1_ComponentStatus(ref),
+		"k8s.io/api/core/v1.ComponentStatusList":                                                             schema_k8sio_api_core_v1_ComponentStatusList(ref),
+		"k8s.io/api/core/v1.ConfigMap":                                                                       schema_k8sio_api_core_v1_ConfigMap(ref),
+		"k8s.io/api/core/v1.ConfigMapEnvSource":                                                              schema_k8sio_api_core_v1_ConfigMapEnvSource(ref),
+		"k8s.io/api/core/v1.ConfigMapKeySelector":                                                            schema_k8sio_api_core_v1_ConfigMapKeySelector(ref),
+		"k8s.io/api/core/v1.ConfigMapList":                                                                   schema_k8sio_api_core_v1_ConfigMapList(ref),
+		"k8s.io/api/core/v1.ConfigMapNodeConfigSource":                                                       schema_k8sio_api_core_v1_ConfigMapNodeConfigSource(ref),
+		"k8s.io/api/core/v1.ConfigMapProjection":                                                             schema_k8sio_api_core_v1_ConfigMapProjection(ref),
+		"k8s.io/api/core/v1.ConfigMapVolumeSource":                                                           schema_k8sio_api_core_v1_ConfigMapVolumeSource(ref),
+		"k8s.io/api/core/v1.Container":                                                                       schema_k8sio_api_core_v1_Container(ref),
+		"k8s.io/api/core/v1.ContainerImage":                                                                  schema_k8sio_api_core_v1_ContainerImage(ref),
+		"k8s.io/api/core/v1.ContainerPort":                                                                   schema_k8sio_api_core_v1_ContainerPort(ref),
+		"k8s.io/api/core/v1.ContainerState":                                                                  schema_k8sio_api_core_v1_ContainerState(ref),
+		"k8s.io/api/core/v1.ContainerStateRunning":                                                           schema_k8sio_api_core_v1_ContainerStateRunning(ref),
+		"k8s.io/api/core/v1.ContainerStateTerminated":                                                        schema_k8sio_api_core_v1_ContainerStateTerminated(ref),
+		"k8s.io/api/core/v1.ContainerStateWaiting":                                                           schema_k8sio_api_core_v1_ContainerStateWaiting(ref),
+		"k8s.io/api/core/v1.ContainerStatus":                                                                 schema_k8sio_api_core_v1_ContainerStatus(ref),
+		"k8s.io/api/core/v1.DaemonEndpoint":                                                                  schema_k8sio_api_core_v1_DaemonEndpoint(ref),
+		"k8s.io/api/core/v1.DownwardAPIProjection":                                                           schema_k8sio_api_core_v1_DownwardAPIProjection(ref),
+		"k8s.io/api/core/v1.DownwardAPIVolumeFile":                                                           schema_k8sio_api_core_v1_DownwardAPIVolumeFile(ref),
+		"k8s.io/api/core/v1.DownwardAPIVolumeSource":                                                         schema_k8sio_api_core_v1_DownwardAPIVolumeSource(ref),
+		"k8s.io/api/core/v1.EmptyDirVolumeSource":                                                            schema_k8sio_api_core_v1_EmptyDirVolumeSource(ref),
+		"k8s.io/api/core/v1.EndpointAddress":                                                                 schema_k8sio_api_core_v1_EndpointAddress(ref),
+		"k8s.io/api/core/v1.EndpointPort":                                                                    schema_k8sio_api_core_v1_EndpointPort(ref),
+		"k8s.io/api/core/v1.EndpointSubset":                                                                  schema_k8sio_api_core_v1_EndpointSubset(ref),
+		"k8s.io/api/core/v1.Endpoints":                                                                       schema_k8sio_api_core_v1_Endpoints(ref),
+		"k8s.io/api/core/v1.EndpointsList":                                                                   schema_k8sio_api_core_v1_EndpointsList(ref),
+		"k8s.io/api/core/v1.EnvFromSource":                                                                   schema_k8sio_api_core_v1_EnvFromSource(ref),
+		"k8s.io/api/core/v1.EnvVar":                                                                          schema_k8sio_api_core_v1_EnvVar(ref),
+		"k8s.io/api/core/v1.EnvVarSource":                                                                    schema_k8sio_api_core_v1_EnvVarSource(ref),
+		"k8s.io/api/core/v1.EphemeralContainer":                                                              schema_k8sio_api_core_v1_EphemeralContainer(ref),
+		"k8s.io/api/core/v1.EphemeralContainerCommon":                                                        schema_k8sio_api_core_v1_EphemeralContainerCommon(ref),
+		"k8s.io/api/core/v1.EphemeralVolumeSource":                                                           schema_k8sio_api_core_v1_EphemeralVolumeSource(ref),
+		"k8s.io/api/core/v1.Event":                                                                           schema_k8sio_api_core_v1_Event(ref),
+		"k8s.io/api/core/v1.EventList":                                                                       schema_k8sio_api_core_v1_EventList(ref),
+		"k8s.io/api/core/v1.EventSeries":                                                                     schema_k8sio_api_core_v1_EventSeries(ref),
+		"k8s.io/api/core/v1.EventSource":                                                                     schema_k8sio_api_core_v1_EventSource(ref),
+		"k8s.io/api/core/v1.ExecAction":                                                                      schema_k8sio_api_core_v1_ExecAction(ref),
+		"k8s.io/api/core/v1.FCVolumeSource":                                                                  schema_k8sio_api_core_v1_FCVolumeSource(ref),
+		"k8s.io/api/core/v1.FlexPersistentVolumeSource":                                                      schema_k8sio_api_core_v1_FlexPersistentVolumeSource(ref),
+		"k8s.io/api/core/v1.FlexVolumeSource":                                                                schema_k8sio_api_core_v1_FlexVolumeSource(ref),
+		"k8s.io/api/core/v1.FlockerVolumeSource":                                                             schema_k8sio_api_core_v1_FlockerVolumeSource(ref),
+		"k8s.io/api/core/v1.GCEPersistentDiskVolumeSource":                                                   schema_k8sio_api_core_v1_GCEPersistentDiskVolumeSource(ref),
+		"k8s.io/api/core/v1.GRPCAction":                                                                      schema_k8sio_api_core_v1_GRPCAction(ref),
+		"k8s.io/api/core/v1.GitRepoVolumeSource":                                                             schema_k8sio_api_core_v1_GitRepoVolumeSource(ref),
+		"k8s.io/api/core/v1.GlusterfsPersistentVolumeSource":                                                 schema_k8sio_api_core_v1_GlusterfsPersistentVolumeSource(ref),
+		"k8s.io/api/core/v1.GlusterfsVolumeSource":                                                           schema_k8sio_api_core_v1_GlusterfsVolumeSource(ref),
+		"k8s.io/api/core/v1.HTTPGetAction":                                                                   schema_k8sio_api_core_v1_HTTPGetAction(ref),
+		"k8s.io/api/core/v1.HTTPHeader":                                                                      schema_k8sio_api_core_v1_HTTPHeader(ref),
+		"k8s.io/api/core/v1.HostAlias":                                                                       schema_k8sio_api_core_v1_HostAlias(ref),
+		"k8s.io/api/core/v1.HostPathVolumeSource":                                                            schema_k8sio_api_core_v1_HostPathVolumeSource(ref),
+		"k8s.io/api/core/v1.ISCSIPersistentVolumeSource":                                                     schema_k8sio_api_core_v1_ISCSIPersistentVolumeSource(ref),
+		"k8s.io/api/core/v1.ISCSIVolumeSource":                                                               schema_k8sio_api_core_v1_ISCSIVolumeSource(ref),
+		"k8s.io/api/core/v1.KeyToPath":                                                                       schema_k8sio_api_core_v1_KeyToPath(ref),
+		"k8s.io/api/core/v1.Lifecycle":                                                                       schema_k8sio_api_core_v1_Lifecycle(ref),
+		"k8s.io/api/core/v1.LifecycleHandler":                                                                schema_k8sio_api_core_v1_LifecycleHandler(ref),
+		"k8s.io/api/core/v1.LimitRange":                                                                      schema_k8sio_api_core_v1_LimitRange(ref),
+		"k8s.io/api/core/v1.LimitRangeItem":                                                                  schema_k8sio_api_core_v1_LimitRangeItem(ref),
+		"k8s.io/api/core/v1.LimitRangeList":                                                                  schema_k8sio_api_core_v1_LimitRangeList(ref),
+		"k8s.io/api/core/v1.LimitRangeSpec":                                                                  schema_k8sio_api_core_v1_LimitRangeSpec(ref),
+		"k8s.io/api/core/v1.List":                                                                            schema_k8sio_api_core_v1_List(ref),
+		"k8s.io/api/core/v1.LoadBalancerIngress":                                                             schema_k8sio_api_core_v1_LoadBalancerIngress(ref),
+		"k8s.io/api/core/v1.LoadBalancerStatus":                                                              schema_k8sio_api_core_v1_LoadBalancerStatus(ref),
+		"k8s.io/api/core/v1.LocalObjectReference":                                                            schema_k8sio_api_core_v1_LocalObjectReference(ref),
+		"k8s.io/api/core/v1.LocalVolumeSource":                                                               schema_k8sio_api_core_v1_LocalVolumeSource(ref),
+		"k8s.io/api/core/v1.NFSVolumeSource":                                                                 schema_k8sio_api_core_v1_NFSVolumeSource(ref),
+		"k8s.io/api/core/v1.Namespace":                                                                       schema_k8sio_api_core_v1_Namespace(ref),
+		"k8s.io/api/core/v1.NamespaceCondition":                                                              schema_k8sio_api_core_v1_NamespaceCondition(ref),
+		"k8s.io/api/core/v1.NamespaceList":                                                                   schema_k8sio_api_core_v1_NamespaceList(ref),
+		"k8s.io/api/core/v1.NamespaceSpec":                                                                   schema_k8sio_api_core_v1_NamespaceSpec(ref),
+		"k8s.io/api/core/v1.NamespaceStatus":                                                                 schema_k8sio_api_core_v1_NamespaceStatus(ref),
+		"k8s.io/api/core/v1.Node":                                                                            schema_k8sio_api_core_v1_Node(ref),
+		"k8s.io/api/core/v1.NodeAddress":                                                                     schema_k8sio_api_core_v1_NodeAddress(ref),
+		"k8s.io/api/core/v1.NodeAffinity":                                                                    schema_k8sio_api_core_v1_NodeAffinity(ref),
+		"k8s.io/api/core/v1.NodeCondition":                                                                   schema_k8sio_api_core_v1_NodeCondition(ref),
+		"k8s.io/api/core/v1.NodeConfigSource":                                                                schema_k8sio_api_core_v1_NodeConfigSource(ref),
+		"k8s.io/api/core/v1.NodeConfigStatus":                                                                schema_k8sio_api_core_v1_NodeConfigStatus(ref),
+		"k8s.io/api/core/v1.NodeDaemonEndpoints":                                                             schema_k8sio_api_core_v1_NodeDaemonEndpoints(ref),
+		"k8s.io/api/core/v1.NodeList":                                                                        schema_k8sio_api_core_v1_NodeList(ref),
+		"k8s.io/api/core/v1.NodeProxyOptions":                                                                schema_k8sio_api_core_v1_NodeProxyOptions(ref),
+		"k8s.io/api/core/v1.NodeResources":                                                                   schema_k8sio_api_core_v1_NodeResources(ref),
+		"k8s.io/api/core/v1.NodeSelector":                                                                    schema_k8sio_api_core_v1_NodeSelector(ref),
+		"k8s.io/api/core/v1.NodeSelectorRequirement":                                                         schema_k8sio_api_core_v1_NodeSelectorRequirement(ref),
+		"k8s.io/api/core/v1.NodeSelectorTerm":                                                                schema_k8sio_api_core_v1_NodeSelectorTerm(ref),
+		"k8s.io/api/core/v1.NodeSpec":                                                                        schema_k8sio_api_core_v1_NodeSpec(ref),
+		"k8s.io/api/core/v1.NodeStatus":                                                                      schema_k8sio_api_core_v1_NodeStatus(ref),
+		"k8s.io/api/core/v1.NodeSystemInfo":                                                                  schema_k8sio_api_core_v1_NodeSystemInfo(ref),
+		"k8s.io/api/core/v1.ObjectFieldSelector":                                                             schema_k8sio_api_core_v1_ObjectFieldSelector(ref),
+		"k8s.io/api/core/v1.ObjectReference":                                                                 schema_k8sio_api_core_v1_ObjectReference(ref),
+		"k8s.io/api/core/v1.PersistentVolume":                                                                schema_k8sio_api_core_v1_PersistentVolume(ref),
+		"k8s.io/api/core/v1.PersistentVolumeClaim":                                                           schema_k8sio_api_core_v1_PersistentVolumeClaim(ref),
+		"k8s.io/api/core/v1.PersistentVolumeClaimCondition":                                                  schema_k8sio_api_core_v1_PersistentVolumeClaimCondition(ref),
+		"k8s.io/api/core/v1.PersistentVolumeClaimList":                                                       schema_k8sio_api_core_v1_PersistentVolumeClaimList(ref),
+		"k8s.io/api/core/v1.PersistentVolumeClaimSpec":                                                       schema_k8sio_api_core_v1_PersistentVolumeClaimSpec(ref),
+		"k8s.io/api/core/v1.PersistentVolumeClaimStatus":                                                     schema_k8sio_api_core_v1_PersistentVolumeClaimStatus(ref),
+		"k8s.io/api/core/v1.PersistentVolumeClaimTemplate":                                                   schema_k8sio_api_core_v1_PersistentVolumeClaimTemplate(ref),
+		"k8s.io/api/core/v1.PersistentVolumeClaimVolumeSource":                                               schema_k8sio_api_core_v1_PersistentVolumeClaimVolumeSource(ref),
+		"k8s.io/api/core/v1.PersistentVolumeList":                                                            schema_k8sio_api_core_v1_PersistentVolumeList(ref),
+		"k8s.io/api/core/v1.PersistentVolumeSource":                                                          schema_k8sio_api_core_v1_PersistentVolumeSource(ref),
+		"k8s.io/api/core/v1.PersistentVolumeSpec":                                                            schema_k8sio_api_core_v1_PersistentVolumeSpec(ref),
+		"k8s.io/api/core/v1.PersistentVolumeStatus":                                                          schema_k8sio_api_core_v1_PersistentVolumeStatus(ref),
+		"k8s.io/api/core/v1.PhotonPersistentDiskVolumeSource":                                                schema_k8sio_api_core_v1_PhotonPersistentDiskVolumeSource(ref),
+		"k8s.io/api/core/v1.Pod":                                                                             schema_k8sio_api_core_v1_Pod(ref),
+		"k8s.io/api/core/v1.PodAffinity":                                                                     schema_k8sio_api_core_v1_PodAffinity(ref),
+		"k8s.io/api/core/v1.PodAffinityTerm":                                                                 schema_k8sio_api_core_v1_PodAffinityTerm(ref),
+		"k8s.io/api/core/v1.PodAntiAffinity":                                                                 schema_k8sio_api_core_v1_PodAntiAffinity(ref),
+		"k8s.io/api/core/v1.PodAttachOptions":                                                                schema_k8sio_api_core_v1_PodAttachOptions(ref),
+		"k8s.io/api/core/v1.PodCondition":                                                                    schema_k8sio_api_core_v1_PodCondition(ref),
+		"k8s.io/api/core/v1.PodDNSConfig":                                                                    schema_k8sio_api_core_v1_PodDNSConfig(ref),
+		"k8s.io/api/core/v1.PodDNSConfigOption":                                                              schema_k8sio_api_core_v1_PodDNSConfigOption(ref),
+		"k8s.io/api/core/v1.PodExecOptions":                                                                  schema_k8sio_api_core_v1_PodExecOptions(ref),
+		"k8s.io/api/core/v1.PodIP":                                                                           schema_k8sio_api_core_v1_PodIP(ref),
+		"k8s.io/api/core/v1.PodList":                                                                         schema_k8sio_api_core_v1_PodList(ref),
+		"k8s.io/api/core/v1.PodLogOptions":                                                                   schema_k8sio_api_core_v1_PodLogOptions(ref),
+		"k8s.io/api/core/v1.PodOS":                                                                           schema_k8sio_api_core_v1_PodOS(ref),
+		"k8s.io/api/core/v1.PodPortForwardOptions":                                                           schema_k8sio_api_core_v1_PodPortForwardOptions(ref),
+		"k8s.io/api/core/v1.PodProxyOptions":                                                                 schema_k8sio_api_core_v1_PodProxyOptions(ref),
+		"k8s.io/api/core/v1.PodReadinessGate":                                                                schema_k8sio_api_core_v1_PodReadinessGate(ref),
+		"k8s.io/api/core/v1.PodResourceClaim":                                                                schema_k8sio_api_core_v1_PodResourceClaim(ref),
+		"k8s.io/api/core/v1.PodSchedulingGate":                                                               schema_k8sio_api_core_v1_PodSchedulingGate(ref),
+		"k8s.io/api/core/v1.PodSecurityContext":                                                              schema_k8sio_api_core_v1_PodSecurityContext(ref),
+		"k8s.io/api/core/v1.PodSignature":                                                                    schema_k8sio_api_core_v1_PodSignature(ref),
+		"k8s.io/api/core/v1.PodSpec":                                                                         schema_k8sio_api_core_v1_PodSpec(ref),
+		"k8s.io/api/core/v1.PodStatus":                                                                       schema_k8sio_api_core_v1_PodStatus(ref),
+		"k8s.io/api/core/v1.PodStatusResult":                                                                 schema_k8sio_api_core_v1_PodStatusResult(ref),
+		"k8s.io/api/core/v1.PodTemplate":                                                                     schema_k8sio_api_core_v1_PodTemplate(ref),
+		"k8s.io/api/core/v1.PodTemplateList":                                                                 schema_k8sio_api_core_v1_PodTemplateList(ref),
+		"k8s.io/api/core/v1.PodTemplateSpec":                                                                 schema_k8sio_api_core_v1_PodTemplateSpec(ref),
+		"k8s.io/api/core/v1.PortStatus":                                                                      schema_k8sio_api_core_v1_PortStatus(ref),
+		"k8s.io/api/core/v1.PortworxVolumeSource":                                                            schema_k8sio_api_core_v1_PortworxVolumeSource(ref),
+		"k8s.io/api/core/v1.PreferAvoidPodsEntry":                                                            schema_k8sio_api_core_v1_PreferAvoidPodsEntry(ref),
+		"k8s.io/api/core/v1.PreferredSchedulingTerm":                                                         schema_k8sio_api_core_v1_PreferredSchedulingTerm(ref),
+		"k8s.io/api/core/v1.Probe":                                                                           schema_k8sio_api_core_v1_Probe(ref),
+		"k8s.io/api/core/v1.ProbeHandler":                                                                    schema_k8sio_api_core_v1_ProbeHandler(ref),
+		"k8s.io/api/core/v1.ProjectedVolumeSource":                                                           schema_k8sio_api_core_v1_ProjectedVolumeSource(ref),
+		"k8s.io/api/core/v1.QuobyteVolumeSource":                                                             schema_k8sio_api_core_v1_QuobyteVolumeSource(ref),
+		"k8s.io/api/core/v1.RBDPersistentVolumeSource":                                                       schema_k8sio_api_core_v1_RBDPersistentVolumeSource(ref),
+		"k8s.io/api/core/v1.RBDVolumeSource":                                                                 schema_k8sio_api_core_v1_RBDVolumeSource(ref),
+		"k8s.io/api/core/v1.RangeAllocation":                                                                 schema_k8sio_api_core_v1_RangeAllocation(ref),
+		"k8s.io/api/core/v1.ReplicationController":                                                           schema_k8sio_api_core_v1_ReplicationController(ref),
+		"k8s.io/api/core/v1.ReplicationControllerCondition":                                                  schema_k8sio_api_core_v1_ReplicationControllerCondition(ref),
+		"k8s.io/api/core/v1.ReplicationControllerList":                                                       schema_k8sio_api_core_v1_ReplicationControllerList(ref),
+		"k8s.io/api/core/v1.ReplicationControllerSpec":                                                       schema_k8sio_api_core_v1_ReplicationControllerSpec(ref),
+		"k8s.io/api/core/v1.ReplicationControllerStatus":                                                     schema_k8sio_api_core_v1_ReplicationControllerStatus(ref),
+		"k8s.io/api/core/v1.ResourceClaim":                                                                   schema_k8sio_api_core_v1_ResourceClaim(ref),
+		"k8s.io/api/core/v1.ResourceFieldSelector":                                                           schema_k8sio_api_core_v1_ResourceFieldSelector(ref),
+		"k8s.io/api/core/v1.ResourceQuota":                                                                   schema_k8sio_api_core_v1_ResourceQuota(ref),
+		"k8s.io/api/core/v1.ResourceQuotaList":                                                               schema_k8sio_api_core_v1_ResourceQuotaList(ref),
+		"k8s.io/api/core/v1.ResourceQuotaSpec":                                                               schema_k8sio_api_core_v1_ResourceQuotaSpec(ref),
+		"k8s.io/api/core/v1.ResourceQuotaStatus":                                                             schema_k8sio_api_core_v1_ResourceQuotaStatus(ref),
+		"k8s.io/api/core/v1.ResourceRequirements":                                                            schema_k8sio_api_core_v1_ResourceRequirements(ref),
+		"k8s.io/api/core/v1.SELinuxOptions":                                                                  schema_k8sio_api_core_v1_SELinuxOptions(ref),
+		"k8s.io/api/core/v1.ScaleIOPersistentVolumeSource":                                                   schema_k8sio_api_core_v1_ScaleIOPersistentVolumeSource(ref),
+		"k8s.io/api/core/v1.ScaleIOVolumeSource":                                                             schema_k8sio_api_core_v1_ScaleIOVolumeSource(ref),
+		"k8s.io/api/core/v1.ScopeSelector":                                                                   schema_k8sio_api_core_v1_ScopeSelector(ref),
+		"k8s.io/api/core/v1.ScopedResourceSelectorRequirement":                                               schema_k8sio_api_core_v1_ScopedResourceSelectorRequirement(ref),
+		"k8s.io/api/core/v1.SeccompProfile":                                                                  schema_k8sio_api_core_v1_SeccompProfile(ref),
+		"k8s.io/api/core/v1.Secret":                                                                          schema_k8sio_api_core_v1_Secret(ref),
+		"k8s.io/api/core/v1.SecretEnvSource":                                                                 schema_k8sio_api_core_v1_SecretEnvSource(ref),
+		"k8s.io/api/core/v1.SecretKeySelector":                                                               schema_k8sio_api_core_v1_SecretKeySelector(ref),
+		"k8s.io/api/core/v1.SecretList":                                                                      schema_k8sio_api_core_v1_SecretList(ref),
+		"k8s.io/api/core/v1.SecretProjection":                                                                schema_k8sio_api_core_v1_SecretProjection(ref),
+		"k8s.io/api/core/v1.SecretReference":                                                                 schema_k8sio_api_core_v1_SecretReference(ref),
+		"k8s.io/api/core/v1.SecretVolumeSource":                                                              schema_k8sio_api_core_v1_SecretVolumeSource(ref),
+		"k8s.io/api/core/v1.SecurityContext":                                                                 schema_k8sio_api_core_v1_SecurityContext(ref),
+		"k8s.io/api/core/v1.SerializedReference":                                                             schema_k8sio_api_core_v1_SerializedReference(ref),
+		"k8s.io/api/core/v1.Service":                                                                         schema_k8sio_api_core_v1_Service(ref),
+		"k8s.io/api/core/v1.ServiceAccount":                                                                  schema_k8sio_api_core_v1_ServiceAccount(ref),
+		"k8s.io/api/core/v1.ServiceAccountList":                                                              schema_k8sio_api_core_v1_ServiceAccountList(ref),
+		"k8s.io/api/core/v1.ServiceAccountTokenProjection":                                                   schema_k8sio_api_core_v1_ServiceAccountTokenProjection(ref),
+		"k8s.io/api/core/v1.ServiceList":                                                                     schema_k8sio_api_core_v1_ServiceList(ref),
+		"k8s.io/api/core/v1.ServicePort":                                                                     schema_k8sio_api_core_v1_ServicePort(ref),
+		"k8s.io/api/core/v1.ServiceProxyOptions":                                                             schema_k8sio_api_core_v1_ServiceProxyOptions(ref),
+		"k8s.io/api/core/v1.ServiceSpec":                                                                     schema_k8sio_api_core_v1_ServiceSpec(ref),
+		"k8s.io/api/core/v1.ServiceStatus":                                                                   schema_k8sio_api_core_v1_ServiceStatus(ref),
+		"k8s.io/api/core/v1.SessionAffinityConfig":                                                           schema_k8sio_api_core_v1_SessionAffinityConfig(ref),
+		"k8s.io/api/core/v1.StorageOSPersistentVolumeSource":                                                 schema_k8sio_api_core_v1_StorageOSPersistentVolumeSource(ref),
+		"k8s.io/api/core/v1.StorageOSVolumeSource":                                                           schema_k8sio_api_core_v1_StorageOSVolumeSource(ref),
+		"k8s.io/api/core/v1.Sysctl":                                                                          schema_k8sio_api_core_v1_Sysctl(ref),
+		"k8s.io/api/core/v1.TCPSocketAction":                                                                 schema_k8sio_api_core_v1_TCPSocketAction(ref),
+		"k8s.io/api/core/v1.Taint":                                                                           schema_k8sio_api_core_v1_Taint(ref),
+		"k8s.io/api/core/v1.Toleration":                                                                      schema_k8sio_api_core_v1_Toleration(ref),
+		"k8s.io/api/core/v1.TopologySelectorLabelRequirement":                                                schema_k8sio_api_core_v1_TopologySelectorLabelRequirement(ref),
+		"k8s.io/api/core/v1.TopologySelectorTerm":                                                            schema_k8sio_api_core_v1_TopologySelectorTerm(ref),
+		"k8s.io/api/core/v1.TopologySpreadConstraint":                                                        schema_k8sio_api_core_v1_TopologySpreadConstraint(ref),
+		"k8s.io/api/core/v1.TypedLocalObjectReference":                                                       schema_k8sio_api_core_v1_TypedLocalObjectReference(ref),
+		"k8s.io/api/core/v1.TypedObjectReference":                                                            schema_k8sio_api_core_v1_TypedObjectReference(ref),
+		"k8s.io/api/core/v1.Volume":                                                                          schema_k8sio_api_core_v1_Volume(ref),
+		"k8s.io/api/core/v1.VolumeDevice":                                                                    schema_k8sio_api_core_v1_VolumeDevice(ref),
+		"k8s.io/api/core/v1.VolumeMount":                                                                     schema_k8sio_api_core_v1_VolumeMount(ref),
+		"k8s.io/api/core/v1.VolumeNodeAffinity":                                                              schema_k8sio_api_core_v1_VolumeNodeAffinity(ref),
+		"k8s.io/api/core/v1.VolumeProjection":                                                                schema_k8sio_api_core_v1_VolumeProjection(ref),
+		"k8s.io/api/core/v1.VolumeSource":                                                                    schema_k8sio_api_core_v1_VolumeSource(ref),
+		"k8s.io/api/core/v1.VsphereVirtualDiskVolumeSource":                                                  schema_k8sio_api_core_v1_VsphereVirtualDiskVolumeSource(ref),
+		"k8s.io/api/core/v1.WeightedPodAffinityTerm":                                                         schema_k8sio_api_core_v1_WeightedPodAffinityTerm(ref),
+		"k8s.io/api/core/v1.WindowsSecurityContextOptions":                                                   schema_k8sio_api_core_v1_WindowsSecurityContextOptions(ref),
+		"k8s.io/apimachinery/pkg/api/resource.Quantity":                                                      schema_apimachinery_pkg_api_resource_Quantity(ref),
+		"k8s.io/apimachinery/pkg/api/resource.int64Amount":                                                   schema_apimachinery_pkg_api_resource_int64Amount(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.APIGroup":                                                      schema_pkg_apis_meta_v1_APIGroup(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.APIGroupList":                                                  schema_pkg_apis_meta_v1_APIGroupList(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.APIResource":                                                   schema_pkg_apis_meta_v1_APIResource(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.APIResourceList":                                               schema_pkg_apis_meta_v1_APIResourceList(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.APIVersions":                                                   schema_pkg_apis_meta_v1_APIVersions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.ApplyOptions":                                                  schema_pkg_apis_meta_v1_ApplyOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Condition":                                                     schema_pkg_apis_meta_v1_Condition(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.CreateOptions":                                                 schema_pkg_apis_meta_v1_CreateOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.DeleteOptions":                                                 schema_pkg_apis_meta_v1_DeleteOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Duration":                                                      schema_pkg_apis_meta_v1_Duration(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.FieldsV1":                                                      schema_pkg_apis_meta_v1_FieldsV1(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.GetOptions":                                                    schema_pkg_apis_meta_v1_GetOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupKind":                                                     schema_pkg_apis_meta_v1_GroupKind(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupResource":                                                 schema_pkg_apis_meta_v1_GroupResource(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersion":                                                  schema_pkg_apis_meta_v1_GroupVersion(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersionForDiscovery":                                      schema_pkg_apis_meta_v1_GroupVersionForDiscovery(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersionKind":                                              schema_pkg_apis_meta_v1_GroupVersionKind(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersionResource":                                          schema_pkg_apis_meta_v1_GroupVersionResource(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.InternalEvent":                                                 schema_pkg_apis_meta_v1_InternalEvent(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector":                                                 schema_pkg_apis_meta_v1_LabelSelector(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelectorRequirement":                                      schema_pkg_apis_meta_v1_LabelSelectorRequirement(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.List":                                                          schema_pkg_apis_meta_v1_List(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta":                                                      schema_pkg_apis_meta_v1_ListMeta(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.ListOptions":                                                   schema_pkg_apis_meta_v1_ListOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.ManagedFieldsEntry":                                            schema_pkg_apis_meta_v1_ManagedFieldsEntry(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime":                                                     schema_pkg_apis_meta_v1_MicroTime(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta":                                                    schema_pkg_apis_meta_v1_ObjectMeta(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.OwnerReference":                                                schema_pkg_apis_meta_v1_OwnerReference(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.PartialObjectMetadata":                                         schema_pkg_apis_meta_v1_PartialObjectMetadata(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.PartialObjectMetadataList":                                     schema_pkg_apis_meta_v1_PartialObjectMetadataList(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Patch":                                                         schema_pkg_apis_meta_v1_Patch(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.PatchOptions":                                                  schema_pkg_apis_meta_v1_PatchOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Preconditions":                                                 schema_pkg_apis_meta_v1_Preconditions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.RootPaths":                                                     schema_pkg_apis_meta_v1_RootPaths(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.ServerAddressByClientCIDR":                                     schema_pkg_apis_meta_v1_ServerAddressByClientCIDR(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Status":                                                        schema_pkg_apis_meta_v1_Status(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.StatusCause":                                                   schema_pkg_apis_meta_v1_StatusCause(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.StatusDetails":                                                 schema_pkg_apis_meta_v1_StatusDetails(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Table":                                                         schema_pkg_apis_meta_v1_Table(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.TableColumnDefinition":                                         schema_pkg_apis_meta_v1_TableColumnDefinition(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.TableOptions":                                                  schema_pkg_apis_meta_v1_TableOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.TableRow":                                                      schema_pkg_apis_meta_v1_TableRow(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.TableRowCondition":                                             schema_pkg_apis_meta_v1_TableRowCondition(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Time":                                                          schema_pkg_apis_meta_v1_Time(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Timestamp":                                                     schema_pkg_apis_meta_v1_Timestamp(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.TypeMeta":                                                      schema_pkg_apis_meta_v1_TypeMeta(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.UpdateOptions":                                                 schema_pkg_apis_meta_v1_UpdateOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.WatchEvent":                                                    schema_pkg_apis_meta_v1_WatchEvent(ref),
+		"k8s.io/apimachinery/pkg/runtime.RawExtension":                                                       schema_k8sio_apimachinery_pkg_runtime_RawExtension(ref),
+		"k8s.io/apimachinery/pkg/runtime.TypeMeta":                                                           schema_k8sio_apimachinery_pkg_runtime_TypeMeta(ref),
+		"k8s.io/apimachinery/pkg/runtime.Unknown":                                                            schema_k8sio_apimachinery_pkg_runtime_Unknown(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CDI":                              schema_pkg_apis_core_v1beta1_CDI(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CDICertConfig":                    schema_pkg_apis_core_v1beta1_CDICertConfig(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CDIConfig":                        schema_pkg_apis_core_v1beta1_CDIConfig(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CDIConfigList":                    schema_pkg_apis_core_v1beta1_CDIConfigList(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CDIConfigSpec":                    schema_pkg_apis_core_v1beta1_CDIConfigSpec(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CDIConfigStatus":                  schema_pkg_apis_core_v1beta1_CDIConfigStatus(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CDIList":                          schema_pkg_apis_core_v1beta1_CDIList(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CDISpec":                          schema_pkg_apis_core_v1beta1_CDISpec(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CDIStatus":                        schema_pkg_apis_core_v1beta1_CDIStatus(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CertConfig":                       schema_pkg_apis_core_v1beta1_CertConfig(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.ClaimPropertySet":                 schema_pkg_apis_core_v1beta1_ClaimPropertySet(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CloneAuthorizationConfig":         schema_pkg_apis_core_v1beta1_CloneAuthorizationConfig(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CloneGrant":                       schema_pkg_apis_core_v1beta1_CloneGrant(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CloneGrantList":                   schema_pkg_apis_core_v1beta1_CloneGrantList(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CloneGrantServiceAccount":         schema_pkg_apis_core_v1beta1_CloneGrantServiceAccount(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CloneGrantSource":                 schema_pkg_apis_core_v1beta1_CloneGrantSource(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CloneGrantSpec":                   schema_pkg_apis_core_v1beta1_CloneGrantSpec(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CloneResourceAttributes":          schema_pkg_apis_core_v1beta1_CloneResourceAttributes(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.ClusterDelegatedAuthorizer":       schema_pkg_apis_core_v1beta1_ClusterDelegatedAuthorizer(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CompletionHook":                   schema_pkg_apis_core_v1beta1_CompletionHook(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.ConditionState":                   schema_pkg_apis_core_v1beta1_ConditionState(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataImportCron":                   schema_pkg_apis_core_v1beta1_DataImportCron(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataImportCronCondition":          schema_pkg_apis_core_v1beta1_DataImportCronCondition(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataImportCronList":               schema_pkg_apis_core_v1beta1_DataImportCronList(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataImportCronPollingConfig":      schema_pkg_apis_core_v1beta1_DataImportCronPollingConfig(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataImportCronSpec":               schema_pkg_apis_core_v1beta1_DataImportCronSpec(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataImportCronStatus":             schema_pkg_apis_core_v1beta1_DataImportCronStatus(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataSource":                       schema_pkg_apis_core_v1beta1_DataSource(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataSourceCondition":              schema_pkg_apis_core_v1beta1_DataSourceCondition(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataSourceList":                   schema_pkg_apis_core_v1beta1_DataSourceList(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataSourceSource":                 schema_pkg_apis_core_v1beta1_DataSourceSource(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataSourceSpec":                   schema_pkg_apis_core_v1beta1_DataSourceSpec(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataSourceStatus":                 schema_pkg_apis_core_v1beta1_DataSourceStatus(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolume":                       schema_pkg_apis_core_v1beta1_DataVolume(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeBlankImage":             schema_pkg_apis_core_v1beta1_DataVolumeBlankImage(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeCheckpoint":             schema_pkg_apis_core_v1beta1_DataVolumeCheckpoint(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeCondition":              schema_pkg_apis_core_v1beta1_DataVolumeCondition(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeDiskStatus":             schema_pkg_apis_core_v1beta1_DataVolumeDiskStatus(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeImportTimings":          schema_pkg_apis_core_v1beta1_DataVolumeImportTimings(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeList":                   schema_pkg_apis_core_v1beta1_DataVolumeList(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSnapshotTarget":         schema_pkg_apis_core_v1beta1_DataVolumeSnapshotTarget(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSource":                 schema_pkg_apis_core_v1beta1_DataVolumeSource(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceAzure":            schema_pkg_apis_core_v1beta1_DataVolumeSourceAzure(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceFTP":              schema_pkg_apis_core_v1beta1_DataVolumeSourceFTP(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceGCS":              schema_pkg_apis_core_v1beta1_DataVolumeSourceGCS(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceHTTP":             schema_pkg_apis_core_v1beta1_DataVolumeSourceHTTP(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceImageIO":          schema_pkg_apis_core_v1beta1_DataVolumeSourceImageIO(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceInline":           schema_pkg_apis_core_v1beta1_DataVolumeSourceInline(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceNBD":              schema_pkg_apis_core_v1beta1_DataVolumeSourceNBD(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourcePVC":              schema_pkg_apis_core_v1beta1_DataVolumeSourcePVC(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceRef":              schema_pkg_apis_core_v1beta1_DataVolumeSourceRef(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceRegistry":         schema_pkg_apis_core_v1beta1_DataVolumeSourceRegistry(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceRemote":           schema_pkg_apis_core_v1beta1_DataVolumeSourceRemote(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceRemotePVC":        schema_pkg_apis_core_v1beta1_DataVolumeSourceRemotePVC(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceS3":               schema_pkg_apis_core_v1beta1_DataVolumeSourceS3(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceSFTP":             schema_pkg_apis_core_v1beta1_DataVolumeSourceSFTP(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceSnapshot":         schema_pkg_apis_core_v1beta1_DataVolumeSourceSnapshot(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceTokenCredentials": schema_pkg_apis_core_v1beta1_DataVolumeSourceTokenCredentials(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceUpload":           schema_pkg_apis_core_v1beta1_DataVolumeSourceUpload(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceVDDK":             schema_pkg_apis_core_v1beta1_DataVolumeSourceVDDK(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSpec":                   schema_pkg_apis_core_v1beta1_DataVolumeSpec(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeStatus":                 schema_pkg_apis_core_v1beta1_DataVolumeStatus(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.FilesystemOverhead":               schema_pkg_apis_core_v1beta1_FilesystemOverhead(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.ImportProxy":                      schema_pkg_apis_core_v1beta1_ImportProxy(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.ImportStatus":                     schema_pkg_apis_core_v1beta1_ImportStatus(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.ObjectTransfer":                   schema_pkg_apis_core_v1beta1_ObjectTransfer(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.ObjectTransferCondition":          schema_pkg_apis_core_v1beta1_ObjectTransferCondition(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.ObjectTransferList":               schema_pkg_apis_core_v1beta1_ObjectTransferList(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.ObjectTransferSpec":               schema_pkg_apis_core_v1beta1_ObjectTransferSpec(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.ObjectTransferStatus":             schema_pkg_apis_core_v1beta1_ObjectTransferStatus(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.PodIOLimits":                      schema_pkg_apis_core_v1beta1_PodIOLimits(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.RegistryDisk":                     schema_pkg_apis_core_v1beta1_RegistryDisk(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.ScratchSpaceConfig":               schema_pkg_apis_core_v1beta1_ScratchSpaceConfig(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.StorageProfile":                   schema_pkg_apis_core_v1beta1_StorageProfile(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.StorageProfileList":               schema_pkg_apis_core_v1beta1_StorageProfileList(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.StorageProfileSpec":               schema_pkg_apis_core_v1beta1_StorageProfileSpec(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.StorageProfileStatus":             schema_pkg_apis_core_v1beta1_StorageProfileStatus(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.StorageSpec":                      schema_pkg_apis_core_v1beta1_StorageSpec(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.TransferSource":                   schema_pkg_apis_core_v1beta1_TransferSource(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.TransferTarget":                   schema_pkg_apis_core_v1beta1_TransferTarget(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.WorkerPodImage":                   schema_pkg_apis_core_v1beta1_WorkerPodImage(ref),
+		"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.WorkerPodPlacement":               schema_pkg_apis_core_v1beta1_WorkerPodPlacement(ref),
+		"kubevirt.io/controller-lifecycle-operator-sdk/api.NodePlacement":                                    schema_kubevirtio_controller_lifecycle_operator_sdk_api_NodePlacement(ref),
 	}
 }
 
@@ -25029,11 +25055,149 @@ func schema_pkg_apis_core_v1beta1_CDIConfigSpec(ref common.ReferenceCallback) co
 							},
 						},
 					},
+					"importMaxAttempts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ImportMaxAttempts is the number of failed attempts after which an import DataVolume fails. Unset means the import is retried indefinitely.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"podIOLimits": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PodIOLimits are the disk IO limits of the importer and clone source pods, applied through the cgroup of the pod where the container runtime supports it.",
+							Ref:         ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.PodIOLimits"),
+						},
+					},
+					"maxParallelWorkerPods": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxParallelWorkerPods is the maximum number of import, upload and host-assisted clone worker pods CDI runs simultaneously, the DataVolumes beyond it wait in creation order. Unset means no limit.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"cloneAnnotationAllowlist": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CloneAnnotationAllowlist is the list of annotations copied from the source to the target PVC of a host-assisted clone, in addition to the image format and virtual size recorded by CDI. Other annotations of the source are not copied.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"importTLSSecurityProfile": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ImportTLSSecurityProfile is the TLS security profile of the importer clients connecting to the HTTP, S3 and ImageIO import sources. The default is the intermediate profile, TLS 1.2 and above. A DataVolume can override the minimal version and the ciphers of its source with annotations.",
+							Ref:         ref("github.com/openshift/api/config/v1.TLSSecurityProfile"),
+						},
+					},
+					"scratchSpace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ScratchSpace configures the volume backing the scratch space of the importer pods. The default is a PVC.",
+							Ref:         ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.ScratchSpaceConfig"),
+						},
+					},
+					"dataImportCronPolling": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DataImportCronPolling configures how the CDI controller polls the sources of the DataImportCrons.",
+							Ref:         ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataImportCronPollingConfig"),
+						},
+					},
+					"workerPodPlacement": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WorkerPodPlacement is the default node selector and tolerations of the importer, clone and upload pods of the DataVolumes not setting them.",
+							Ref:         ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.WorkerPodPlacement"),
+						},
+					},
+					"allowedWorkerPodPlacement": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AllowedWorkerPodPlacement is the node selector labels and tolerations the DataVolumes are allowed to set in their workerPodPlacement. Unset means the DataVolumes cannot set a worker pod placement.",
+							Ref:         ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.WorkerPodPlacement"),
+						},
+					},
+					"workerPodImage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WorkerPodImage is the default image registry and pull policy of the importer, clone and upload pods of the DataVolumes not setting them.",
+							Ref:         ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.WorkerPodImage"),
+						},
+					},
+					"allowedWorkerPodRegistries": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AllowedWorkerPodRegistries are the image registries the DataVolumes are allowed to set in their workerPodImage. Unset means the DataVolumes cannot set a worker pod image registry.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"dataVolumeCompletionTimeout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DataVolumeCompletionTimeout is the time from its creation within which a DataVolume must succeed, not counting the time it is paused, after which it fails. Unset means no timeout.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"completionHooks": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CompletionHooks are the HTTP endpoints notified once the DataVolumes naming them in their cdi.kubevirt.io/storage.completionHook annotation succeeded. Unset means the DataVolumes cannot notify any endpoint.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CompletionHook"),
+									},
+								},
+							},
+						},
+					},
+					"clusterDelegatedAuthorizer": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ClusterDelegatedAuthorizer is an external HTTP authorization webhook deciding the cross-namespace clones, instead of or in addition to the built-in SubjectAccessReview checks.",
+							Ref:         ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.ClusterDelegatedAuthorizer"),
+						},
+					},
+					"cloneAuthorization": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CloneAuthorization overrides or extends the resource attributes of the SubjectAccessReviews allowing the cross-namespace clones, and configures the cache of their decisions",
+							Ref:         ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CloneAuthorizationConfig"),
+						},
+					},
+					"cloneBandwidthLimit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CloneBandwidthLimit is the default network bandwidth limit of the host-assisted clones, in bytes per second, enforced by the clone source pod streaming to the target. A DataVolume overrides it with its cdi.kubevirt.io/storage.clone.bandwidthLimit annotation. Unset or 0 means no limit.",
+							Ref:         ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+						},
+					},
+					"cloneStreams": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CloneStreams is the default number of parallel streams of the host-assisted clones of block disks, each streaming a range of the disk over its own connection. The StorageProfile of the target storage class overrides it. Unset means a single stream.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"cloneCompression": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CloneCompression is the default compression codec of the stream of the host-assisted clones, negotiated by the clone source with the target, which falls back to snappy if the target does not support it. A DataVolume overrides it with its cdi.kubevirt.io/storage.clone.compression annotation. Unset means snappy.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/openshift/api/config/v1.TLSSecurityProfile", "k8s.io/api/core/v1.LocalObjectReference", "k8s.io/api/core/v1.ResourceRequirements", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.FilesystemOverhead", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.ImportProxy"},
+			"github.com/openshift/api/config/v1.TLSSecurityProfile", "k8s.io/api/core/v1.LocalObjectReference", "k8s.io/api/core/v1.ResourceRequirements", "k8s.io/apimachinery/pkg/api/resource.Quantity", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CloneAuthorizationConfig", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.ClusterDelegatedAuthorizer", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CompletionHook", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataImportCronPollingConfig", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.FilesystemOverhead", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.ImportProxy", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.PodIOLimits", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.ScratchSpaceConfig", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.WorkerPodImage", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.WorkerPodPlacement"},
 	}
 }
 
@@ -25342,58 +25506,60 @@ func schema_pkg_apis_core_v1beta1_ClaimPropertySet(ref common.ReferenceCallback)
 	}
 }
 
-func schema_pkg_apis_core_v1beta1_ConditionState(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1beta1_CloneAuthorizationConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ConditionState represents the state of a condition",
+				Description: "CloneAuthorizationConfig defines the resource attributes of the SubjectAccessReviews allowing the cross-namespace clones, and the cache of their decisions",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"status": {
+					"resourceAttributes": {
 						SchemaProps: spec.SchemaProps{
-							Default: "",
-							Type:    []string{"string"},
-							Format:  "",
-						},
-					},
-					"lastTransitionTime": {
-						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+							Description: "ResourceAttributes are the verbs on resources of the clone source namespace allowing a user or ServiceAccount to clone from it, any of them allowing the clone. The namespace and name of the reviewed resource are those of the clone source.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CloneResourceAttributes"),
+									},
+								},
+							},
 						},
 					},
-					"lastHeartbeatTime": {
+					"mode": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+							Description: "Mode tells whether ResourceAttributes extend the built-in create datavolumes/source and create pods checks, or override them. The default is Extend.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"reason": {
+					"cacheTTL": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "CacheTTL is the time the decisions of the SubjectAccessReviews allowing the cross-namespace clones are cached by the CDI API server, such as 30s. Unset or zero disables the cache.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
 						},
 					},
-					"message": {
+					"auditLog": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "AuditLog writes the decisions of the cross-namespace clone authorizations as JSON lines to the log of the CDI API server, in addition to the Events recorded on the clone sources.",
+							Type:        []string{"boolean"},
+							Format:      "",
 						},
 					},
 				},
-				Required: []string{"status"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CloneResourceAttributes"},
 	}
 }
 
-func schema_pkg_apis_core_v1beta1_DataImportCron(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1beta1_CloneGrant(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "DataImportCron defines a cron job for recurring polling/importing disk images as PVCs into a golden image namespace",
+				Description: "CloneGrant pre-approves the cross-namespace clones of the PVCs and VolumeSnapshots of its namespace",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -25419,13 +25585,7 @@ func schema_pkg_apis_core_v1beta1_DataImportCron(ref common.ReferenceCallback) c
 					"spec": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataImportCronSpec"),
-						},
-					},
-					"status": {
-						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataImportCronStatus"),
+							Ref:     ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CloneGrantSpec"),
 						},
 					},
 				},
@@ -25433,69 +25593,15 @@ func schema_pkg_apis_core_v1beta1_DataImportCron(ref common.ReferenceCallback) c
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataImportCronSpec", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataImportCronStatus"},
-	}
-}
-
-func schema_pkg_apis_core_v1beta1_DataImportCronCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "DataImportCronCondition represents the state of a data import cron condition",
-				Type:        []string{"object"},
-				Properties: map[string]spec.Schema{
-					"type": {
-						SchemaProps: spec.SchemaProps{
-							Default: "",
-							Type:    []string{"string"},
-							Format:  "",
-						},
-					},
-					"status": {
-						SchemaProps: spec.SchemaProps{
-							Default: "",
-							Type:    []string{"string"},
-							Format:  "",
-						},
-					},
-					"lastTransitionTime": {
-						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
-						},
-					},
-					"lastHeartbeatTime": {
-						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
-						},
-					},
-					"reason": {
-						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
-						},
-					},
-					"message": {
-						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
-						},
-					},
-				},
-				Required: []string{"type", "status"},
-			},
-		},
-		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CloneGrantSpec"},
 	}
 }
 
-func schema_pkg_apis_core_v1beta1_DataImportCronList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1beta1_CloneGrantList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "DataImportCronList provides the needed parameters to do request a list of DataImportCrons from the system",
+				Description: "CloneGrantList provides the needed parameters to do request a list of CloneGrants from the system",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -25520,13 +25626,13 @@ func schema_pkg_apis_core_v1beta1_DataImportCronList(ref common.ReferenceCallbac
 					},
 					"items": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Items provides a list of DataImportCrons",
+							Description: "Items provides a list of CloneGrants",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataImportCron"),
+										Ref:     ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CloneGrant"),
 									},
 								},
 							},
@@ -25537,97 +25643,572 @@ func schema_pkg_apis_core_v1beta1_DataImportCronList(ref common.ReferenceCallbac
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataImportCron"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CloneGrant"},
 	}
 }
 
-func schema_pkg_apis_core_v1beta1_DataImportCronSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1beta1_CloneGrantServiceAccount(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "DataImportCronSpec defines specification for DataImportCron",
+				Description: "CloneGrantServiceAccount is a ServiceAccount granted by a CloneGrant",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"template": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Template specifies template for the DVs to be created",
-							Default:     map[string]interface{}{},
-							Ref:         ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolume"),
-						},
-					},
-					"schedule": {
+					"namespace": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Schedule specifies in cron format when and how often to look for new imports",
+							Description: "Namespace is the namespace of the ServiceAccount",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"garbageCollect": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "GarbageCollect specifies whether old PVCs should be cleaned up after a new PVC is imported. Options are currently \"Outdated\" and \"Never\", defaults to \"Outdated\".",
+							Description: "Name is the name of the ServiceAccount",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"importsToKeep": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Number of import PVCs to keep when garbage collecting. Default is 3.",
-							Type:        []string{"integer"},
-							Format:      "int32",
-						},
-					},
-					"managedDataSource": {
+				},
+				Required: []string{"namespace", "name"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1beta1_CloneGrantSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CloneGrantSource is a PVC or VolumeSnapshot granted by a CloneGrant",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ManagedDataSource specifies the name of the corresponding DataSource this cron will manage. DataSource has to be in the same namespace.",
+							Description: "Kind is the kind of the source, PersistentVolumeClaim or VolumeSnapshot",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"retentionPolicy": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "RetentionPolicy specifies whether the created DataVolumes and DataSources are retained when their DataImportCron is deleted. Default is RatainAll.",
+							Description: "Name is the name of the source",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
-				Required: []string{"template", "schedule", "managedDataSource"},
+				Required: []string{"kind", "name"},
 			},
 		},
-		Dependencies: []string{
-			"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolume"},
 	}
 }
 
-func schema_pkg_apis_core_v1beta1_DataImportCronStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1beta1_CloneGrantSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "DataImportCronStatus provides the most recently observed status of the DataImportCron",
+				Description: "CloneGrantSpec defines the clones pre-approved by the CloneGrant",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"currentImports": {
+					"sources": {
 						SchemaProps: spec.SchemaProps{
-							Description: "CurrentImports are the imports in progress. Currently only a single import is supported.",
+							Description: "Sources are the PVCs and VolumeSnapshots of the namespace the grant applies to, all of them when empty",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.ImportStatus"),
+										Ref:     ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CloneGrantSource"),
 									},
 								},
 							},
 						},
 					},
-					"lastImportedPVC": {
+					"targetNamespaces": {
 						SchemaProps: spec.SchemaProps{
-							Description: "LastImportedPVC is the last imported PVC",
-							Ref:         ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourcePVC"),
-						},
-					},
+							Description: "TargetNamespaces are the namespaces any user may clone the sources to",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"serviceAccounts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ServiceAccounts are the ServiceAccounts that may clone the sources to their namespace",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CloneGrantServiceAccount"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CloneGrantServiceAccount", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.CloneGrantSource"},
+	}
+}
+
+func schema_pkg_apis_core_v1beta1_CloneResourceAttributes(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CloneResourceAttributes defines a verb on a resource of the clone source namespace allowing to clone from it",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"verb": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Verb is the verb allowed on the resource, such as create or a dedicated clone verb",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"group": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Group is the API group of the resource, empty for the core group",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"resource": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Resource is the resource, such as datavolumes or a custom resource of an aggregated role",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"subresource": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Subresource is the subresource, such as source",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"verb", "resource"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1beta1_ClusterDelegatedAuthorizer(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ClusterDelegatedAuthorizer defines an external HTTP authorization webhook deciding the cross-namespace clones, compatible with the data API of Open Policy Agent",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"url": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URL is the http or https URL the clone authorization requests are POSTed to, such as the data API URL of an Open Policy Agent policy. The request body holds the clone in its input field, and the response body tells whether it is allowed in its result field, either a boolean or an object with allowed and reason fields.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"mode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Mode tells whether the webhook decides the cross-namespace clones in addition to the built-in SubjectAccessReview checks, both having to allow a clone, or instead of them. The default is Additional.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"caBundle": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CABundle is the PEM encoded CA bundle verifying the certificate of an https webhook. The system trust roots are used when empty.",
+							Type:        []string{"string"},
+							Format:      "byte",
+						},
+					},
+					"timeoutSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TimeoutSeconds is the timeout of a webhook request, after which the clone is denied. The default is 10 seconds.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+				Required: []string{"url"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1beta1_CompletionHook(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CompletionHook defines an HTTP endpoint notified once a DataVolume naming it succeeded",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the name the DataVolumes give to the hook in their cdi.kubevirt.io/storage.completionHook annotation",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"url": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URL is the http or https URL receiving a POST with a JSON body once a DataVolume naming the hook succeeded",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"tokenSecret": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TokenSecret is the name of the Secret of the CDI namespace holding the bearer token of the POST in its token key",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name", "url"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1beta1_ConditionState(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ConditionState represents the state of a condition",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"lastTransitionTime": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"lastHeartbeatTime": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+				Required: []string{"status"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_core_v1beta1_DataImportCron(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DataImportCron defines a cron job for recurring polling/importing disk images as PVCs into a golden image namespace",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataImportCronSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataImportCronStatus"),
+						},
+					},
+				},
+				Required: []string{"spec"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataImportCronSpec", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataImportCronStatus"},
+	}
+}
+
+func schema_pkg_apis_core_v1beta1_DataImportCronCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DataImportCronCondition represents the state of a data import cron condition",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"lastTransitionTime": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"lastHeartbeatTime": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+				Required: []string{"type", "status"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_core_v1beta1_DataImportCronList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DataImportCronList provides the needed parameters to do request a list of DataImportCrons from the system",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Items provides a list of DataImportCrons",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataImportCron"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"metadata", "items"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataImportCron"},
+	}
+}
+
+func schema_pkg_apis_core_v1beta1_DataImportCronPollingConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DataImportCronPollingConfig defines how the CDI controller polls the sources of the DataImportCrons",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"parallelism": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Parallelism is the maximum number of DataImportCron sources polled concurrently, at most 16. The default is 1. The polls of the same DataImportCron never overlap.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"registryPollsPerMinute": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RegistryPollsPerMinute is the maximum number of polls per minute of the same registry. Unset means no limit.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1beta1_DataImportCronSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DataImportCronSpec defines specification for DataImportCron",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"template": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Template specifies template for the DVs to be created",
+							Default:     map[string]interface{}{},
+							Ref:         ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolume"),
+						},
+					},
+					"schedule": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Schedule specifies in cron format when and how often to look for new imports",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"garbageCollect": {
+						SchemaProps: spec.SchemaProps{
+							Description: "GarbageCollect specifies whether old PVCs should be cleaned up after a new PVC is imported. Options are currently \"Outdated\" and \"Never\", defaults to \"Outdated\".",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"importsToKeep": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of import PVCs to keep when garbage collecting. Default is 3.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"managedDataSource": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ManagedDataSource specifies the name of the corresponding DataSource this cron will manage. DataSource has to be in the same namespace.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"retentionPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RetentionPolicy specifies whether the created DataVolumes and DataSources are retained when their DataImportCron is deleted. Default is RatainAll.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"sourceTagPattern": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SourceTagPattern is a regular expression selecting the tags of the registry URL source repository to import. The matching tag with the highest semantic version is imported, with an optional \"v\" prefix. Tags that do not match or are not semantic versions are ignored, as is the tag of the source URL.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"template", "schedule", "managedDataSource"},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolume"},
+	}
+}
+
+func schema_pkg_apis_core_v1beta1_DataImportCronStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DataImportCronStatus provides the most recently observed status of the DataImportCron",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"currentImports": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CurrentImports are the imports in progress. Currently only a single import is supported.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.ImportStatus"),
+									},
+								},
+							},
+						},
+					},
+					"lastImportedPVC": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastImportedPVC is the last imported PVC",
+							Ref:         ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourcePVC"),
+						},
+					},
 					"lastExecutionTimestamp": {
 						SchemaProps: spec.SchemaProps{
 							Description: "LastExecutionTimestamp is the time of the last polling",
@@ -26039,6 +26620,77 @@ func schema_pkg_apis_core_v1beta1_DataVolumeCondition(ref common.ReferenceCallba
 	}
 }
 
+func schema_pkg_apis_core_v1beta1_DataVolumeDiskStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DataVolumeDiskStatus reports the import of a disk of a multi-disk registry image",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"path": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Path is the path of the disk in the image",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"claimName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ClaimName is the name of the PVC the disk is imported into",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Phase is the current phase of the import of the disk",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"path", "claimName"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1beta1_DataVolumeImportTimings(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DataVolumeImportTimings holds the time the importer spent in the phases of the import, in whole seconds. A phase that was skipped or took less than a second is omitted. The decompression is part of the download, and the preallocation is part of the conversion and the resize.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"downloadSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DownloadSeconds is the time spent transferring the source to the scratch space or the target volume",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"convertSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConvertSeconds is the time spent converting the image to the target volume",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"resizeSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResizeSeconds is the time spent resizing the image to the requested size",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_core_v1beta1_DataVolumeList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -26089,6 +26741,40 @@ func schema_pkg_apis_core_v1beta1_DataVolumeList(ref common.ReferenceCallback) c
 	}
 }
 
+func schema_pkg_apis_core_v1beta1_DataVolumeSnapshotTarget(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DataVolumeSnapshotTarget defines the VolumeSnapshot a DataVolume imports into",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the name of the VolumeSnapshot, the name of the DataVolume if empty",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"volumeSnapshotClassName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VolumeSnapshotClassName is the class of the VolumeSnapshot. If empty, a class of the provisioner of the storage class of the PVC is used",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"pvcRetainPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PVCRetainPolicy tells whether the PVC is deleted once the VolumeSnapshot is ready, Delete if empty",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_core_v1beta1_DataVolumeSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -26118,39 +26804,146 @@ func schema_pkg_apis_core_v1beta1_DataVolumeSource(ref common.ReferenceCallback)
 					},
 					"pvc": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourcePVC"),
+							Ref: ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourcePVC"),
+						},
+					},
+					"upload": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceUpload"),
+						},
+					},
+					"blank": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeBlankImage"),
+						},
+					},
+					"imageio": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceImageIO"),
+						},
+					},
+					"vddk": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceVDDK"),
+						},
+					},
+					"snapshot": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceSnapshot"),
+						},
+					},
+					"inline": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceInline"),
+						},
+					},
+					"nbd": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceNBD"),
+						},
+					},
+					"remote": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceRemote"),
+						},
+					},
+					"remotePVC": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceRemotePVC"),
+						},
+					},
+					"ftp": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceFTP"),
+						},
+					},
+					"azure": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceAzure"),
+						},
+					},
+					"sftp": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceSFTP"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeBlankImage", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceAzure", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceFTP", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceGCS", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceHTTP", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceImageIO", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceInline", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceNBD", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourcePVC", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceRegistry", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceRemote", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceRemotePVC", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceS3", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceSFTP", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceSnapshot", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceUpload", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceVDDK"},
+	}
+}
+
+func schema_pkg_apis_core_v1beta1_DataVolumeSourceAzure(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DataVolumeSourceAzure provides the parameters to create a Data Volume from an Azure Blob Storage source",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"url": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URL is the url of the blob, as in https://<account>.blob.core.windows.net/<container>/<blob>, without a SAS token",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"upload": {
+					"secretRef": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceUpload"),
+							Description: "SecretRef provides the secret holding a SAS token in its sasToken key, or the tenantId, clientId and clientSecret of a service principal, the blob is read anonymously without it",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"blank": {
+					"certConfigMap": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeBlankImage"),
+							Description: "CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key of the Blob service",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"imageio": {
+				},
+				Required: []string{"url"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1beta1_DataVolumeSourceFTP(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DataVolumeSourceFTP provides the parameters to create a Data Volume from an FTP or FTPS source",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"url": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceImageIO"),
+							Description: "URL is the url of the image on the FTP server, as in ftp://host[:port]/path, or ftps://host[:port]/path for FTP over explicit TLS",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"vddk": {
+					"secretRef": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceVDDK"),
+							Description: "SecretRef provides the secret holding the user name and password of the FTP server in its accessKeyId and secretKey keys, the login is anonymous without it",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"snapshot": {
+					"certConfigMap": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceSnapshot"),
+							Description: "CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key of the FTPS server",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
+				Required: []string{"url"},
 			},
 		},
-		Dependencies: []string{
-			"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeBlankImage", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceGCS", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceHTTP", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceImageIO", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourcePVC", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceRegistry", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceS3", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceSnapshot", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceUpload", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceVDDK"},
 	}
 }
 
@@ -26176,10 +26969,32 @@ func schema_pkg_apis_core_v1beta1_DataVolumeSourceGCS(ref common.ReferenceCallba
 							Format:      "",
 						},
 					},
+					"tokenCredentials": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TokenCredentials gets the credentials through workload identity federation with a projected service account token, instead of the SecretRef key file",
+							Ref:         ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceTokenCredentials"),
+						},
+					},
+					"userProject": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UserProject is the project billed for the requests to a requester-pays bucket, required to read from such a bucket",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"generation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Generation pins the import to this generation of the object, the import fails if the generation does not exist. By default the import reads the latest generation, pinned for the whole download so a resumed download does not mix two generations",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
 				},
 				Required: []string{"url"},
 			},
 		},
+		Dependencies: []string{
+			"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceTokenCredentials"},
 	}
 }
 
@@ -26293,6 +27108,57 @@ func schema_pkg_apis_core_v1beta1_DataVolumeSourceImageIO(ref common.ReferenceCa
 	}
 }
 
+func schema_pkg_apis_core_v1beta1_DataVolumeSourceInline(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DataVolumeSourceInline provides the parameters to create a Data Volume from a small image embedded in the DataVolume",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"data": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Data is the base64 encoded image, optionally gzip compressed, of at most 1MiB once encoded",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"data"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1beta1_DataVolumeSourceNBD(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DataVolumeSourceNBD provides the parameters to create a Data Volume from a disk exported over NBD",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"url": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URL is the url of the NBD export, as in nbd://host[:port]/export, or nbds://host[:port]/export for NBD over TLS",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"secretRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SecretRef provides the secret holding the ca-cert.pem of the NBD server CA, and optionally the client-cert.pem and client-key.pem of a client certificate, required for NBD over TLS",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"url"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_core_v1beta1_DataVolumeSourcePVC(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -26402,7 +27268,98 @@ func schema_pkg_apis_core_v1beta1_DataVolumeSourceRegistry(ref common.ReferenceC
 							Format:      "",
 						},
 					},
+					"disks": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Disks lists the disks of an image holding several of them, each imported into its own PVC",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.RegistryDisk"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.RegistryDisk"},
+	}
+}
+
+func schema_pkg_apis_core_v1beta1_DataVolumeSourceRemote(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DataVolumeSourceRemote provides the parameters to clone a Data Volume from a disk exported by a remote cluster",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"url": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URL is the url of the disk image exported by the remote cluster",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"secretRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SecretRef provides the secret holding the token authorizing the export, in its token key",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"certConfigMap": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key of the remote endpoint",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"url", "secretRef"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1beta1_DataVolumeSourceRemotePVC(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DataVolumeSourceRemotePVC provides the parameters to clone a Data Volume from a PVC of a remote cluster",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kubeconfigSecretRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "KubeconfigSecretRef provides the secret holding the kubeconfig of the remote cluster, in its kubeconfig key",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The namespace of the source PVC in the remote cluster",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the source PVC in the remote cluster",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
+				Required: []string{"kubeconfigSecretRef", "namespace", "name"},
 			},
 		},
 	}
@@ -26437,10 +27394,63 @@ func schema_pkg_apis_core_v1beta1_DataVolumeSourceS3(ref common.ReferenceCallbac
 							Format:      "",
 						},
 					},
+					"tokenCredentials": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TokenCredentials gets the credentials by assuming an IAM role with a projected service account token (IRSA), instead of the SecretRef keys",
+							Ref:         ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceTokenCredentials"),
+						},
+					},
+					"kmsKeyId": {
+						SchemaProps: spec.SchemaProps{
+							Description: "KMSKeyID is the ID or ARN of the AWS KMS key the object is encrypted with (SSE-KMS), the import fails if the object is not encrypted with this key",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"url"},
 			},
 		},
+		Dependencies: []string{
+			"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceTokenCredentials"},
+	}
+}
+
+func schema_pkg_apis_core_v1beta1_DataVolumeSourceSFTP(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DataVolumeSourceSFTP provides the parameters to create a Data Volume from an SFTP source",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"url": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URL is the url of the image on the SFTP server, as in sftp://host[:port]/path, the path is relative to the home directory of the user if it starts with /~/",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"secretRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SecretRef provides the secret holding the user name in its username key, and its private key in the ssh-privatekey key, with the passphrase of an encrypted key in the passphrase key, or its password in the password key",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"hostKey": {
+						SchemaProps: spec.SchemaProps{
+							Description: "HostKey is the public host key the SFTP server must present, in the authorized_keys format, as in \"ssh-ed25519 AAAA...\", or its SHA256 fingerprint, as in \"SHA256:...\", printed by ssh-keygen -l",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"url", "secretRef", "hostKey"},
+			},
+		},
 	}
 }
 
@@ -26453,22 +27463,56 @@ func schema_pkg_apis_core_v1beta1_DataVolumeSourceSnapshot(ref common.ReferenceC
 				Properties: map[string]spec.Schema{
 					"namespace": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The namespace of the source VolumeSnapshot",
-							Default:     "",
+							Description: "The namespace of the source VolumeSnapshot",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the source VolumeSnapshot",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"namespace", "name"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1beta1_DataVolumeSourceTokenCredentials(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DataVolumeSourceTokenCredentials provides the parameters to get the credentials of an S3 or GCS source by exchanging a service account token projected in the importer pod",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"audience": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Audience is the audience of the projected token. For S3 it defaults to sts.amazonaws.com, for GCS it is required and is the workload identity pool provider, as in //iam.googleapis.com/projects/<number>/locations/global/workloadIdentityPools/<pool>/providers/<provider>",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"roleARN": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RoleARN is the ARN of the IAM role assumed with the token, required for S3",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"name": {
+					"serviceAccount": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The name of the source VolumeSnapshot",
-							Default:     "",
+							Description: "ServiceAccount is the email of the GCP service account impersonated with the federated token, optional for GCS",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
-				Required: []string{"namespace", "name"},
 			},
 		},
 	}
@@ -26613,11 +27657,29 @@ func schema_pkg_apis_core_v1beta1_DataVolumeSpec(ref common.ReferenceCallback) c
 							Format:      "",
 						},
 					},
+					"snapshotTarget": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SnapshotTarget makes a VolumeSnapshot of the imported PVC the final artifact of the DataVolume",
+							Ref:         ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSnapshotTarget"),
+						},
+					},
+					"workerPodPlacement": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WorkerPodPlacement is the node selector and tolerations of the importer, clone and upload pods of the DataVolume. Unset fields default to the ones of the CDIConfig.",
+							Ref:         ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.WorkerPodPlacement"),
+						},
+					},
+					"workerPodImage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WorkerPodImage is the image registry and pull policy of the importer, clone and upload pods of the DataVolume. Unset fields default to the ones of the CDIConfig.",
+							Ref:         ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.WorkerPodImage"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/api/core/v1.PersistentVolumeClaimSpec", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeCheckpoint", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSource", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceRef", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.StorageSpec"},
+			"k8s.io/api/core/v1.PersistentVolumeClaimSpec", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeCheckpoint", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSnapshotTarget", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSource", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeSourceRef", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.StorageSpec", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.WorkerPodImage", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.WorkerPodPlacement"},
 	}
 }
 
@@ -26668,11 +27730,58 @@ func schema_pkg_apis_core_v1beta1_DataVolumeStatus(ref common.ReferenceCallback)
 							},
 						},
 					},
+					"importTimings": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ImportTimings is the time the importer spent in the phases of the import",
+							Ref:         ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeImportTimings"),
+						},
+					},
+					"importPassthrough": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ImportPassthrough tells the importer copied the source image as is, without conversion, as it already had the target format",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"importSourceDigest": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ImportSourceDigest is the digest of the source bytes the importer read, as <algorithm>:<hex>, when requested with the storage.import.sourceDigestAlgorithm annotation",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"pausedSince": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PausedSince is the time the DataVolume entered the Paused phase of a multi-stage import, the time it stays paused does not count toward its completion timeout",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"pausedSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PausedSeconds is the time in seconds the DataVolume previously spent in the Paused phase, not counted toward its completion timeout",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"disks": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Disks reports the import of each disk of a multi-disk registry image",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeDiskStatus"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeCondition"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeCondition", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeDiskStatus", "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1.DataVolumeImportTimings"},
 	}
 }
 
@@ -26740,6 +27849,13 @@ func schema_pkg_apis_core_v1beta1_ImportProxy(ref common.ReferenceCallback) comm
 							Format:      "",
 						},
 					},
+					"proxyAuthSecret": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ProxyAuthSecret is the name of a Secret in the cdi namespace that contains the username and password keys used to authenticate against the import proxy. The credentials in the Secret take precedence over the ones in the proxy URLs.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"trustedCAProxy": {
 						SchemaProps: spec.SchemaProps{
 							Description: "TrustedCAProxy is the name of a ConfigMap in the cdi namespace that contains a user-provided trusted certificate authority (CA) bundle. The TrustedCAProxy ConfigMap is consumed by the DataImportCron controller for creating cronjobs, and by the import controller referring a copy of the ConfigMap in the import namespace. Here is an example of the ConfigMap (in yaml):\n\napiVersion: v1 kind: ConfigMap metadata:\n  name: my-ca-proxy-cm\n  namespace: cdi\ndata:\n  ca.pem: |\n    -----BEGIN CERTIFICATE-----\n\t   ... <base64 encoded cert> ...\n\t   -----END CERTIFICATE-----",
@@ -27020,6 +28136,113 @@ func schema_pkg_apis_core_v1beta1_ObjectTransferStatus(ref common.ReferenceCallb
 	}
 }
 
+func schema_pkg_apis_core_v1beta1_PodIOLimits(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PodIOLimits defines the disk IO limits of a CDI worker pod on its volume",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"blockIOClass": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BlockIOClass is the blockio class of the pods, set with the blockio.resources.beta.kubernetes.io/pod annotation. The container runtime applies the IO limits the class is configured with on the nodes, also when the pod can't write its own cgroup.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"maxBytesPerSecond": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxBytesPerSecond is the maximum read and write throughput of the pod on its volume, in bytes per second.",
+							Ref:         ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+						},
+					},
+					"maxIOPS": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxIOPS is the maximum number of read and write IO operations per second of the pod on its volume.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"weight": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Weight is the proportional disk IO weight of the pod, between 1 and 10000, the cgroup v2 io.weight. It is scaled to the 10 to 1000 range of the cgroup v1 blkio.weight.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/api/resource.Quantity"},
+	}
+}
+
+func schema_pkg_apis_core_v1beta1_RegistryDisk(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RegistryDisk selects a disk of a multi-disk registry image, and the PVC it is imported into",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"path": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Path is the path of the disk in the image, relative to its disk directory",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"pvcName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PVCName is the name of the PVC the disk is imported into, the PVC of the DataVolume when empty",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"size": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Size is the size requested for the PVC of the disk, the size of the DataVolume when not set. The disk imported into the PVC of the DataVolume gets the size of the DataVolume.",
+							Ref:         ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+						},
+					},
+				},
+				Required: []string{"path"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/api/resource.Quantity"},
+	}
+}
+
+func schema_pkg_apis_core_v1beta1_ScratchSpaceConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ScratchSpaceConfig defines the volume backing the scratch space of the importer pods",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"backend": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Backend is the kind of volume backing the scratch space, PVC, EmptyDirDisk or EmptyDirMemory. The default is PVC. A DataVolume can override it with an annotation.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"maxEmptyDirSize": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxEmptyDirSize is the largest scratch space backed by an emptyDir, a larger scratch space is backed by a PVC. Unset means no limit.",
+							Ref:         ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/api/resource.Quantity"},
+	}
+}
+
 func schema_pkg_apis_core_v1beta1_StorageProfile(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -27146,6 +28369,41 @@ func schema_pkg_apis_core_v1beta1_StorageProfileSpec(ref common.ReferenceCallbac
 							},
 						},
 					},
+					"preallocation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Preallocation is the recommended preallocation setting for DataVolumes which don't set their own",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"thinProvisioned": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ThinProvisioned marks the storage of the class as thin provisioned, DataVolumes which don't set their own preallocation are then not preallocated unless Preallocation is set",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"filesystemOverhead": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FilesystemOverhead is the recommended filesystem overhead for Filesystem volumes of the storage class",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"cloneStreams": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CloneStreams is the number of parallel streams of the host-assisted clones of block disks into the storage class",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"crossClassSnapshotRestore": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CrossClassSnapshotRestore marks the CSI driver of the storage class as able to restore the snapshots of the volumes of its other storage classes into this one, so snapshot clones from them are not host-assisted",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -27196,6 +28454,41 @@ func schema_pkg_apis_core_v1beta1_StorageProfileStatus(ref common.ReferenceCallb
 							},
 						},
 					},
+					"preallocation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Preallocation is the recommended preallocation setting for DataVolumes which don't set their own",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"thinProvisioned": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ThinProvisioned marks the storage of the class as thin provisioned, DataVolumes which don't set their own preallocation are then not preallocated unless Preallocation is set",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"filesystemOverhead": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FilesystemOverhead is the recommended filesystem overhead for Filesystem volumes of the storage class",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"cloneStreams": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CloneStreams is the number of parallel streams of the host-assisted clones of block disks into the storage class",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"crossClassSnapshotRestore": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CrossClassSnapshotRestore marks the CSI driver of the storage class as able to restore the snapshots of the volumes of its other storage classes into this one, so snapshot clones from them are not host-assisted",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -27361,6 +28654,78 @@ func schema_pkg_apis_core_v1beta1_TransferTarget(ref common.ReferenceCallback) c
 	}
 }
 
+func schema_pkg_apis_core_v1beta1_WorkerPodImage(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "WorkerPodImage overrides the image registry and pull policy of the worker pods populating a DataVolume",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"registry": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Registry replaces the registry and repository of the importer, cloner and upload server images, keeping their name, tag and digest, for instance to pull them from a mirror in an air-gapped cluster",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"pullPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PullPolicy is the image pull policy of the worker pods, overriding the one of the CDI CR\n\nPossible enum values:\n - `\"Always\"` means that kubelet always attempts to pull the latest image. Container will fail If the pull fails.\n - `\"IfNotPresent\"` means that kubelet pulls if the image isn't present on disk. Container will fail if the image isn't present and the pull fails.\n - `\"Never\"` means that kubelet never pulls an image, but only uses a local image. Container will fail if the image isn't present",
+							Type:        []string{"string"},
+							Format:      "",
+							Enum:        []interface{}{"Always", "IfNotPresent", "Never"}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1beta1_WorkerPodPlacement(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "WorkerPodPlacement is the node selector and tolerations of the worker pods populating a DataVolume",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"nodeSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NodeSelector is the node selector of the worker pods, merged with the node selector of the workload node placement of the CDI CR",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"tolerations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Tolerations are the tolerations of the worker pods, in addition to the tolerations of the workload node placement of the CDI CR",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/api/core/v1.Toleration"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/api/core/v1.Toleration"},
+	}
+}
+
 func schema_kubevirtio_controller_lifecycle_operator_sdk_api_NodePlacement(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
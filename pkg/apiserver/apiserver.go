@@ -38,15 +38,19 @@ import (
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 	aggregatorclient "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset"
 	k8sspec "k8s.io/kube-openapi/pkg/validation/spec"
 
 	snapclient "github.com/kubernetes-csi/external-snapshotter/client/v6/clientset/versioned"
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	cdiuploadv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/upload/v1beta1"
 	pkgcdiuploadv1 "kubevirt.io/containerized-data-importer/pkg/apis/upload/v1beta1"
 	"kubevirt.io/containerized-data-importer/pkg/apiserver/webhooks"
 	cdiclient "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned"
+	cdiinformers "kubevirt.io/containerized-data-importer/pkg/client/informers/externalversions"
+	"kubevirt.io/containerized-data-importer/pkg/clone"
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/keys"
 	"kubevirt.io/containerized-data-importer/pkg/token"
@@ -102,6 +106,7 @@ type cdiAPIApp struct {
 	authorizer          CdiAPIAuthorizer
 	authConfigWatcher   AuthConfigWatcher
 	cdiConfigTLSWatcher cryptowatch.CdiConfigTLSWatcher
+	cloneAuditLog       cloneAuditLog
 
 	certWarcher CertWatcher
 
@@ -151,6 +156,10 @@ func NewCdiAPIServer(bindAddress string,
 
 	app.composeUploadTokenAPI()
 
+	if cdiConfigTLSWatcher != nil {
+		app.watchCloneAuthorization()
+	}
+
 	app.container.Filter(func(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
 		var username = "-"
 		if req.Request.URL.User != nil {
@@ -205,9 +214,49 @@ func newUploadTokenGenerator(key *rsa.PrivateKey) token.Generator {
 }
 
 func (app *cdiAPIApp) Start(ch <-chan struct{}) error {
+	stopAudit := app.auditCloneAuthorizations()
+	defer stopAudit()
+	app.watchCloneGrants(ch)
 	return app.startTLS(ch)
 }
 
+// watchCloneGrants lets the clone authorization allow the cross-namespace clones granted by the CloneGrants
+func (app *cdiAPIApp) watchCloneGrants(ch <-chan struct{}) {
+	cdiInformerFactory := cdiinformers.NewSharedInformerFactory(app.cdiClient, common.DefaultResyncPeriod)
+	cloneGrantInformer := cdiInformerFactory.Cdi().V1beta1().CloneGrants()
+	clone.SetCloneGrantLister(cloneGrantInformer.Lister())
+	cdiInformerFactory.Start(ch)
+
+	klog.V(3).Infoln("Waiting for CloneGrant cache sync")
+	cache.WaitForCacheSync(ch, cloneGrantInformer.Informer().HasSynced)
+}
+
+// watchCloneAuthorization keeps the clone delegated authorizer, resource attributes and audit log in sync with those of
+// CDIConfig
+func (app *cdiAPIApp) watchCloneAuthorization() {
+	setCloneAuthorization := func(obj interface{}) {
+		config := obj.(*cdiv1.CDIConfig)
+		if err := clone.SetClusterDelegatedAuthorizer(config.Spec.ClusterDelegatedAuthorizer); err != nil {
+			klog.Errorf("Invalid clusterDelegatedAuthorizer in CDIConfig %s, denying cross-namespace clones: %v", config.Name, err)
+		}
+		if err := clone.SetCloneAuthorizationConfig(config.Spec.CloneAuthorization); err != nil {
+			klog.Errorf("Invalid cloneAuthorization in CDIConfig %s, denying cross-namespace clones unless it is in Extend mode: %v", config.Name, err)
+		}
+		app.cloneAuditLog.setEnabled(config.Spec.CloneAuthorization != nil && config.Spec.CloneAuthorization.AuditLog)
+	}
+	app.cdiConfigTLSWatcher.GetInformer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: setCloneAuthorization,
+		UpdateFunc: func(_, obj interface{}) {
+			setCloneAuthorization(obj)
+		},
+		DeleteFunc: func(_ interface{}) {
+			_ = clone.SetClusterDelegatedAuthorizer(nil)
+			_ = clone.SetCloneAuthorizationConfig(nil)
+			app.cloneAuditLog.setEnabled(false)
+		},
+	})
+}
+
 func (app *cdiAPIApp) getKeysAndCerts() error {
 	namespace := util.GetNamespace()
 
@@ -0,0 +1,112 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"os"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/clone"
+)
+
+const (
+	// CloneAuthorized provides a const to indicate a cross-namespace clone was authorized
+	CloneAuthorized = "CloneAuthorized"
+	// CloneDenied provides a const to indicate a cross-namespace clone was denied
+	CloneDenied = "CloneDenied"
+
+	// MessageCloneAuthorized provides a const to form the cross-namespace clone authorized message
+	MessageCloneAuthorized = "User %s authorized to clone %s %s to namespace %s by %s"
+	// MessageCloneDenied provides a const to form the cross-namespace clone denied message
+	MessageCloneDenied = "User %s denied to clone %s %s to namespace %s: %s"
+
+	apiServerEventComponent = "cdi-apiserver"
+)
+
+// auditCloneAuthorizations records the decisions of the cross-namespace clone authorizations as Events on the target
+// DataVolumes, the returned func stops it
+func (app *cdiAPIApp) auditCloneAuthorizations() func() {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: app.client.CoreV1().Events("")})
+	recorder := broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: apiServerEventComponent})
+	unregister := clone.RegisterCloneAuthorizationAuditor(newCloneAuthorizationEventAuditor(recorder))
+	return func() {
+		unregister()
+		broadcaster.Shutdown()
+	}
+}
+
+// newCloneAuthorizationEventAuditor returns the auditor recording the decisions as Events on the target DataVolumes.
+// The Events stay in the namespace of the requester, so a decision without target DataVolume is only in the audit log.
+func newCloneAuthorizationEventAuditor(recorder record.EventRecorder) clone.CloneAuthorizationAuditor {
+	return func(decision *clone.CloneAuthorizationDecision) {
+		if decision.TargetName == "" {
+			return
+		}
+		target := &corev1.ObjectReference{
+			Kind:       "DataVolume",
+			APIVersion: cdiv1.SchemeGroupVersion.String(),
+			Namespace:  decision.TargetNamespace,
+			Name:       decision.TargetName,
+		}
+		sourceName := decision.Source.Namespace + "/" + decision.Source.Name
+
+		if decision.Allowed {
+			var matched []string
+			for _, ra := range decision.MatchedResourceAttributes {
+				matched = append(matched, clone.FormatResourceAttributes(ra))
+			}
+			by := "the delegated authorizer"
+			switch {
+			case decision.Grant != "":
+				by = "CloneGrant " + decision.Grant
+			case len(matched) > 0:
+				by = strings.Join(matched, ", ")
+			}
+			recorder.Eventf(target, corev1.EventTypeNormal, CloneAuthorized, MessageCloneAuthorized,
+				decision.User, decision.Source.Kind, sourceName, decision.TargetNamespace, by)
+			return
+		}
+		reason := decision.Reason
+		if decision.Error != "" {
+			reason = decision.Error
+		}
+		recorder.Eventf(target, corev1.EventTypeWarning, CloneDenied, MessageCloneDenied,
+			decision.User, decision.Source.Kind, sourceName, decision.TargetNamespace, reason)
+	}
+}
+
+// cloneAuditLog registers the JSON auditor of the clone authorizations writing to the log while it is enabled
+type cloneAuditLog struct {
+	unregister func()
+}
+
+func (l *cloneAuditLog) setEnabled(enabled bool) {
+	switch {
+	case enabled && l.unregister == nil:
+		l.unregister = clone.RegisterCloneAuthorizationAuditor(clone.NewJSONCloneAuthorizationAuditor(os.Stdout))
+	case !enabled && l.unregister != nil:
+		l.unregister()
+		l.unregister = nil
+	}
+}
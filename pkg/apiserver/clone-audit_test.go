@@ -0,0 +1,86 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	authorization "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/tools/record"
+
+	"kubevirt.io/containerized-data-importer/pkg/clone"
+)
+
+var _ = Describe("Clone authorization Events", func() {
+	var (
+		recorder *record.FakeRecorder
+		auditor  clone.CloneAuthorizationAuditor
+	)
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+		auditor = newCloneAuthorizationEventAuditor(recorder)
+	})
+
+	It("should record an authorized clone with the resource attributes allowing it", func() {
+		auditor(&clone.CloneAuthorizationDecision{
+			User:            "user",
+			Source:          clone.CloneSource{Kind: clone.SourceKindPVC, Namespace: "golden", Name: "fedora"},
+			TargetNamespace: "dev",
+			TargetName:      "fedora-dv",
+			Allowed:         true,
+			MatchedResourceAttributes: []authorization.ResourceAttributes{
+				{Verb: "create", Group: "cdi.kubevirt.io", Resource: "datavolumes", Subresource: "source"},
+			},
+		})
+		Expect(<-recorder.Events).To(Equal("Normal CloneAuthorized User user authorized to clone PersistentVolumeClaim golden/fedora to namespace dev by create datavolumes.cdi.kubevirt.io/source"))
+	})
+
+	It("should record an authorized clone with the CloneGrant allowing it", func() {
+		auditor(&clone.CloneAuthorizationDecision{
+			User:            "system:serviceaccount:dev:default",
+			Source:          clone.CloneSource{Kind: clone.SourceKindPVC, Namespace: "golden", Name: "fedora"},
+			TargetNamespace: "dev",
+			TargetName:      "fedora-dv",
+			Allowed:         true,
+			Grant:           "golden/dev-team",
+		})
+		Expect(<-recorder.Events).To(Equal("Normal CloneAuthorized User system:serviceaccount:dev:default authorized to clone PersistentVolumeClaim golden/fedora to namespace dev by CloneGrant golden/dev-team"))
+	})
+
+	It("should record a denied clone with its reason", func() {
+		auditor(&clone.CloneAuthorizationDecision{
+			User:            "user",
+			Source:          clone.CloneSource{Kind: clone.SourceKindSnapshot, Namespace: "golden", Name: "snapshot"},
+			TargetNamespace: "dev",
+			TargetName:      "fedora-dv",
+			Reason:          "User user has insufficient permissions in clone source namespace golden",
+		})
+		Expect(<-recorder.Events).To(Equal("Warning CloneDenied User user denied to clone VolumeSnapshot golden/snapshot to namespace dev: User user has insufficient permissions in clone source namespace golden"))
+	})
+
+	It("should not record a decision without target DataVolume", func() {
+		auditor(&clone.CloneAuthorizationDecision{
+			User:            "user",
+			Source:          clone.CloneSource{Kind: clone.SourceKindPVC, Namespace: "golden", Name: "fedora"},
+			TargetNamespace: "dev",
+			Reason:          "User user has insufficient permissions in clone source namespace golden",
+		})
+		Expect(recorder.Events).To(BeEmpty())
+	})
+})
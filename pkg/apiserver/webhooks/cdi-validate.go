@@ -27,12 +27,18 @@ import (
 	sdkapi "kubevirt.io/controller-lifecycle-operator-sdk/api"
 
 	admissionv1 "k8s.io/api/admission/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kvalidation "k8s.io/apimachinery/pkg/util/validation"
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/klog/v2"
 
 	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	cdiclient "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned"
 	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+	"kubevirt.io/containerized-data-importer/pkg/util/cgroup"
 )
 
 const uninstallErrorMsg = "Rejecting the uninstall request, since there are still DataVolumes present. Either delete all DataVolumes or change the uninstall strategy before uninstalling CDI."
@@ -49,6 +55,10 @@ func (wh *cdiValidatingWebhook) Admit(ar admissionv1.AdmissionReview) *admission
 		return toAdmissionResponseError(fmt.Errorf("unexpected resource: %s", ar.Request.Resource.Resource))
 	}
 
+	if ar.Request.Operation == admissionv1.Create || ar.Request.Operation == admissionv1.Update {
+		return wh.admitConfig(ar)
+	}
+
 	if ar.Request.Operation != admissionv1.Delete {
 		klog.V(3).Infof("Got unexpected operation type %s", ar.Request.Operation)
 		return allowedAdmissionResponse()
@@ -78,6 +88,88 @@ func (wh *cdiValidatingWebhook) Admit(ar admissionv1.AdmissionReview) *admission
 	return allowedAdmissionResponse()
 }
 
+// admitConfig rejects a CDI whose config has invalid values. An update is only checked when it changes the config, not
+// to block the updates of a CDI admitted before the value was validated.
+func (wh *cdiValidatingWebhook) admitConfig(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
+	if len(ar.Request.Object.Raw) == 0 {
+		return allowedAdmissionResponse()
+	}
+	cdi := &cdiv1.CDI{}
+	if err := json.Unmarshal(ar.Request.Object.Raw, cdi); err != nil {
+		return toAdmissionResponseError(err)
+	}
+	if ar.Request.Operation == admissionv1.Update && len(ar.Request.OldObject.Raw) > 0 {
+		oldCDI := &cdiv1.CDI{}
+		if err := json.Unmarshal(ar.Request.OldObject.Raw, oldCDI); err != nil {
+			return toAdmissionResponseError(err)
+		}
+		if apiequality.Semantic.DeepEqual(cdi.Spec.Config, oldCDI.Spec.Config) {
+			return allowedAdmissionResponse()
+		}
+	}
+	if cdi.Spec.Config == nil {
+		return allowedAdmissionResponse()
+	}
+	causes := validatePodIOLimits(k8sfield.NewPath("spec", "config", "podIOLimits"), cdi.Spec.Config.PodIOLimits)
+	causes = append(causes, validateConfigCloneBandwidthLimit(k8sfield.NewPath("spec", "config", "cloneBandwidthLimit"), cdi.Spec.Config.CloneBandwidthLimit)...)
+	if len(causes) > 0 {
+		klog.Infof("rejected CDI admission %s", causes)
+		return toRejectedAdmissionResponse(causes)
+	}
+	return allowedAdmissionResponse()
+}
+
+// validatePodIOLimits validates the IO limits are in the range the cgroup accepts
+func validatePodIOLimits(field *k8sfield.Path, limits *cdiv1.PodIOLimits) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	if limits == nil {
+		return causes
+	}
+	if limits.Weight != nil && (*limits.Weight < cgroup.MinIOWeight || *limits.Weight > cgroup.MaxIOWeight) {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("The IO weight %d must be between %d and %d", *limits.Weight, cgroup.MinIOWeight, cgroup.MaxIOWeight),
+			Field:   field.Child("weight").String(),
+		})
+	}
+	if limits.MaxIOPS != nil && *limits.MaxIOPS < 0 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("The maximum IOPS %d must not be negative", *limits.MaxIOPS),
+			Field:   field.Child("maxIOPS").String(),
+		})
+	}
+	if limits.MaxBytesPerSecond != nil && limits.MaxBytesPerSecond.Sign() < 0 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("The maximum throughput %s must not be negative", limits.MaxBytesPerSecond.String()),
+			Field:   field.Child("maxBytesPerSecond").String(),
+		})
+	}
+	if limits.BlockIOClass != nil {
+		for _, msg := range kvalidation.IsQualifiedName(*limits.BlockIOClass) {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("Invalid blockio class %q: %s", *limits.BlockIOClass, msg),
+				Field:   field.Child("blockIOClass").String(),
+			})
+		}
+	}
+	return causes
+}
+
+// validateConfigCloneBandwidthLimit validates the default clone bandwidth limit is 0 or at least the minimum limit
+func validateConfigCloneBandwidthLimit(field *k8sfield.Path, limit *resource.Quantity) []metav1.StatusCause {
+	if limit == nil || limit.IsZero() || (limit.Sign() > 0 && limit.Value() >= util.MinBandwidthLimit) {
+		return nil
+	}
+	return []metav1.StatusCause{{
+		Type:    metav1.CauseTypeFieldValueInvalid,
+		Message: fmt.Sprintf("The clone bandwidth limit %s must be 0 or at least %d bytes per second", limit.String(), util.MinBandwidthLimit),
+		Field:   field.String(),
+	}}
+}
+
 func (wh *cdiValidatingWebhook) getResource(ar admissionv1.AdmissionReview) (*cdiv1.CDI, error) {
 	var cdi *cdiv1.CDI
 
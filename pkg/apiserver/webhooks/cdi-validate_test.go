@@ -28,8 +28,10 @@ import (
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
 
 	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	cdiclient "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned/fake"
@@ -250,6 +252,79 @@ var _ = Describe("CDI Delete Webhook", func() {
 	})
 })
 
+var _ = Describe("CDI Config Webhook", func() {
+	newConfigReview := func(op admissionv1.Operation, config, oldConfig *cdiv1.CDIConfigSpec) *admissionv1.AdmissionReview {
+		bytes, _ := json.Marshal(&cdiv1.CDI{ObjectMeta: metav1.ObjectMeta{Name: "cdi"}, Spec: cdiv1.CDISpec{Config: config}})
+		oldBytes, _ := json.Marshal(&cdiv1.CDI{ObjectMeta: metav1.ObjectMeta{Name: "cdi"}, Spec: cdiv1.CDISpec{Config: oldConfig}})
+		ar := &admissionv1.AdmissionReview{
+			Request: &admissionv1.AdmissionRequest{
+				Operation: op,
+				Resource: metav1.GroupVersionResource{
+					Group:    cdiv1.SchemeGroupVersion.Group,
+					Version:  cdiv1.SchemeGroupVersion.Version,
+					Resource: "cdis",
+				},
+				Object: runtime.RawExtension{
+					Raw: bytes,
+				},
+			},
+		}
+		if op == admissionv1.Update {
+			ar.Request.OldObject = runtime.RawExtension{Raw: oldBytes}
+		}
+		return ar
+	}
+
+	ioLimits := func(limits cdiv1.PodIOLimits) *cdiv1.CDIConfigSpec {
+		return &cdiv1.CDIConfigSpec{PodIOLimits: &limits}
+	}
+
+	DescribeTable("should validate the IO limits", func(config *cdiv1.CDIConfigSpec, allowed bool) {
+		resp := validateCDIs(newConfigReview(admissionv1.Create, config, nil))
+		Expect(resp.Allowed).To(Equal(allowed))
+		if !allowed {
+			Expect(resp.Result.Details.Causes[0].Field).To(HavePrefix("spec.config.podIOLimits"))
+		}
+	},
+		Entry("accept no config", nil, true),
+		Entry("accept valid limits", ioLimits(cdiv1.PodIOLimits{Weight: pointer.Int32(100), MaxIOPS: pointer.Int64(500), BlockIOClass: pointer.String("throttled")}), true),
+		Entry("reject a zero weight", ioLimits(cdiv1.PodIOLimits{Weight: pointer.Int32(0)}), false),
+		Entry("reject a weight above the maximum", ioLimits(cdiv1.PodIOLimits{Weight: pointer.Int32(10001)}), false),
+		Entry("reject negative IOPS", ioLimits(cdiv1.PodIOLimits{MaxIOPS: pointer.Int64(-1)}), false),
+		Entry("reject an invalid blockio class", ioLimits(cdiv1.PodIOLimits{BlockIOClass: pointer.String("bad class")}), false),
+	)
+
+	bandwidthLimit := func(limit string) *cdiv1.CDIConfigSpec {
+		quantity := resource.MustParse(limit)
+		return &cdiv1.CDIConfigSpec{CloneBandwidthLimit: &quantity}
+	}
+
+	DescribeTable("should validate the clone bandwidth limit", func(config *cdiv1.CDIConfigSpec, allowed bool) {
+		resp := validateCDIs(newConfigReview(admissionv1.Create, config, nil))
+		Expect(resp.Allowed).To(Equal(allowed))
+		if !allowed {
+			Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.config.cloneBandwidthLimit"))
+		}
+	},
+		Entry("accept no limit", bandwidthLimit("0"), true),
+		Entry("accept the minimum limit", bandwidthLimit("64Ki"), true),
+		Entry("accept a limit above the minimum", bandwidthLimit("100Mi"), true),
+		Entry("reject a limit below the minimum", bandwidthLimit("1Ki"), false),
+		Entry("reject a negative limit", bandwidthLimit("-100Mi"), false),
+	)
+
+	It("should reject an update setting invalid limits", func() {
+		resp := validateCDIs(newConfigReview(admissionv1.Update, ioLimits(cdiv1.PodIOLimits{Weight: pointer.Int32(20000)}), nil))
+		Expect(resp.Allowed).To(BeFalse())
+	})
+
+	It("should accept an update keeping the config", func() {
+		config := ioLimits(cdiv1.PodIOLimits{Weight: pointer.Int32(20000)})
+		resp := validateCDIs(newConfigReview(admissionv1.Update, config, config))
+		Expect(resp.Allowed).To(BeTrue())
+	})
+})
+
 func newDataVolumeWithName(name string) *cdiv1.DataVolume {
 	return &cdiv1.DataVolume{
 		ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"encoding/json"
+	"time"
+
+	authorization "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/clone"
+)
+
+const (
+	// cloneBatchAuthTTL is how long the SubjectAccessReview decisions taken for a DataVolume of a clone batch are
+	// reused by the other DataVolumes of the batch
+	cloneBatchAuthTTL = time.Minute
+	// cloneBatchAuthCacheSize is the number of clone batch decisions cached, the least recently used ones being evicted
+	// first
+	cloneBatchAuthCacheSize = 1024
+)
+
+// cloneBatchAuthCache remembers the SubjectAccessReview decisions taken for the DataVolumes of the clone batches, so a
+// batch fanning one source out to many targets sends them once per user and source rather than once per target. It
+// does not depend on the clone authorization cacheTTL, which is off by default.
+type cloneBatchAuthCache struct {
+	decisions *cache.LRUExpireCache
+}
+
+func newCloneBatchAuthCache() *cloneBatchAuthCache {
+	return &cloneBatchAuthCache{decisions: cache.NewLRUExpireCache(cloneBatchAuthCacheSize)}
+}
+
+// proxy returns the client sharing the SubjectAccessReview decisions with the other DataVolumes of the batch
+func (c *cloneBatchAuthCache) proxy(client clone.SubjectAccessReviewsProxy, batch string) clone.SubjectAccessReviewsProxy {
+	return &cloneBatchSubjectAccessReviewsProxy{client: client, decisions: c.decisions, batch: batch}
+}
+
+type cloneBatchSubjectAccessReviewsProxy struct {
+	client    clone.SubjectAccessReviewsProxy
+	decisions *cache.LRUExpireCache
+	batch     string
+}
+
+// Create sends the SubjectAccessReview, unless the same review, for the same user and resource attributes, was sent
+// for another DataVolume of the batch. A decision failing to evaluate is not reused.
+func (p *cloneBatchSubjectAccessReviewsProxy) Create(sar *authorization.SubjectAccessReview) (*authorization.SubjectAccessReview, error) {
+	// The UserInfo extra map is marshaled with sorted keys, so the same review always gets the same key
+	spec, err := json.Marshal(&sar.Spec)
+	if err != nil {
+		return p.client.Create(sar)
+	}
+	key := p.batch + "/" + string(spec)
+	if status, found := p.decisions.Get(key); found {
+		klog.V(3).Infof("Using the SubjectAccessReview decision of clone batch %s %+v", p.batch, status)
+		response := sar.DeepCopy()
+		response.Status = status.(authorization.SubjectAccessReviewStatus)
+		return response, nil
+	}
+
+	response, err := p.client.Create(sar)
+	if err != nil {
+		return nil, err
+	}
+	if response.Status.EvaluationError == "" {
+		p.decisions.Add(key, response.Status, cloneBatchAuthTTL)
+	}
+	return response, nil
+}
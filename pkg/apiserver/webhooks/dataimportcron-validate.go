@@ -22,6 +22,7 @@ package webhooks
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
 
 	"github.com/gorhill/cronexpr"
 
@@ -109,6 +110,15 @@ func (wh *dataImportCronValidatingWebhook) validateDataImportCronSpec(request *a
 		return causes
 	}
 
+	if len(spec.Template.Spec.Source.Registry.Disks) > 0 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: "A multi-disk registry image can't be imported by a DataImportCron",
+			Field:   field.Child("Template", "source", "Registry", "disks").String(),
+		})
+		return causes
+	}
+
 	causes = wh.validateDataVolumeSpec(request, k8sfield.NewPath("Template"), &spec.Template.Spec, nil)
 	if len(causes) > 0 {
 		return causes
@@ -143,6 +153,25 @@ func (wh *dataImportCronValidatingWebhook) validateDataImportCronSpec(request *a
 		return causes
 	}
 
+	if spec.SourceTagPattern != nil {
+		if spec.Template.Spec.Source.Registry.URL == nil {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: "SourceTagPattern requires a Registry source URL",
+				Field:   field.Child("SourceTagPattern").String(),
+			})
+			return causes
+		}
+		if _, err := regexp.Compile(*spec.SourceTagPattern); err != nil {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("Illegal SourceTagPattern: %v", err),
+				Field:   field.Child("SourceTagPattern").String(),
+			})
+			return causes
+		}
+	}
+
 	if spec.ManagedDataSource == "" {
 		causes = append(causes, metav1.StatusCause{
 			Type:    metav1.CauseTypeFieldValueInvalid,
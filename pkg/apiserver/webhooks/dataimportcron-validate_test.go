@@ -32,6 +32,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	fakeclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
 
 	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	cdiclientfake "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned/fake"
@@ -92,6 +93,11 @@ var _ = Describe("Validating Webhook", func() {
 			resp := validateDataImportCronCreate(cron)
 			Expect(resp.Allowed).To(Equal(false))
 		})
+		It("should reject DataImportCron with a multi-disk Registry source on create", func() {
+			cron := newDataImportCron(cdiv1.DataVolumeSourceRegistry{URL: &testRegistryURL, Disks: []cdiv1.RegistryDisk{{Path: "os.qcow2"}}})
+			resp := validateDataImportCronCreate(cron)
+			Expect(resp.Allowed).To(Equal(false))
+		})
 		It("should reject DataImportCron with both Registry source URL and ImageStream on create", func() {
 			cron := newDataImportCron(cdiv1.DataVolumeSourceRegistry{URL: &testRegistryURL, ImageStream: &testImageStream})
 			resp := validateDataImportCronCreate(cron)
@@ -141,6 +147,24 @@ var _ = Describe("Validating Webhook", func() {
 			resp := validateDataImportCronCreate(cron)
 			Expect(resp.Allowed).To(Equal(false))
 		})
+		It("should accept DataImportCron with a SourceTagPattern on create", func() {
+			cron := newDataImportCron(cdiv1.DataVolumeSourceRegistry{URL: &testRegistryURL})
+			cron.Spec.SourceTagPattern = pointer.String(`^v1\.`)
+			resp := validateDataImportCronCreate(cron)
+			Expect(resp.Allowed).To(Equal(true))
+		})
+		It("should reject DataImportCron with illegal SourceTagPattern on create", func() {
+			cron := newDataImportCron(cdiv1.DataVolumeSourceRegistry{URL: &testRegistryURL})
+			cron.Spec.SourceTagPattern = pointer.String("v1.(")
+			resp := validateDataImportCronCreate(cron)
+			Expect(resp.Allowed).To(Equal(false))
+		})
+		It("should reject DataImportCron with SourceTagPattern and Registry source ImageStream on create", func() {
+			cron := newDataImportCron(cdiv1.DataVolumeSourceRegistry{ImageStream: &testImageStream, PullMethod: &registryPullNode})
+			cron.Spec.SourceTagPattern = pointer.String(".*")
+			resp := validateDataImportCronCreate(cron)
+			Expect(resp.Allowed).To(Equal(false))
+		})
 		It("should reject invalid DataImportCron spec update", func() {
 			newCron := newDataImportCron(cdiv1.DataVolumeSourceRegistry{URL: &testRegistryURL})
 			newBytes, _ := json.Marshal(&newCron)
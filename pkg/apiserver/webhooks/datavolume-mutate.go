@@ -37,6 +37,7 @@ import (
 	"kubevirt.io/containerized-data-importer/pkg/clone"
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+	featuregates "kubevirt.io/containerized-data-importer/pkg/feature-gates"
 	"kubevirt.io/containerized-data-importer/pkg/token"
 )
 
@@ -45,6 +46,7 @@ type dataVolumeMutatingWebhook struct {
 	cdiClient      cdiclient.Interface
 	tokenGenerator token.Generator
 	proxy          clone.SubjectAccessReviewsProxy
+	batchAuth      *cloneBatchAuthCache
 }
 
 type sarProxy struct {
@@ -105,8 +107,10 @@ func (wh *dataVolumeMutatingWebhook) Admit(ar admissionv1.AdmissionReview) *admi
 
 	modifiedDataVolume := dataVolume.DeepCopy()
 
+	var config *cdiv1.CDIConfig
 	if ar.Request.Operation == admissionv1.Create {
-		config, err := wh.cdiClient.CdiV1beta1().CDIConfigs().Get(context.TODO(), common.ConfigName, metav1.GetOptions{})
+		var err error
+		config, err = wh.cdiClient.CdiV1beta1().CDIConfigs().Get(context.TODO(), common.ConfigName, metav1.GetOptions{})
 		if err != nil {
 			return toAdmissionResponseError(err)
 		}
@@ -148,9 +152,23 @@ func (wh *dataVolumeMutatingWebhook) Admit(ar admissionv1.AdmissionReview) *admi
 		return toPatchResponse(dataVolume, modifiedDataVolume)
 	}
 
-	// only add token at create time
+	// only add token at create time, and when an update asks for a re-sync of the clone
+	tokenAnnotation := cc.AnnCloneToken
+	var resync string
 	if ar.Request.Operation != admissionv1.Create {
-		return toPatchResponse(dataVolume, modifiedDataVolume)
+		var requested bool
+		resync, requested, err = cloneResyncRequested(ar, dataVolume)
+		if err != nil {
+			return toAdmissionResponseError(err)
+		}
+		if !requested {
+			return toPatchResponse(dataVolume, modifiedDataVolume)
+		}
+		tokenAnnotation = cc.AnnCloneResyncToken
+		config, err = wh.cdiClient.CdiV1beta1().CDIConfigs().Get(context.TODO(), common.ConfigName, metav1.GetOptions{})
+		if err != nil {
+			return toAdmissionResponseError(err)
+		}
 	}
 
 	sourceName, sourceNamespace := cloneSourceHandler.sourceName, cloneSourceHandler.sourceNamespace
@@ -168,7 +186,18 @@ func (wh *dataVolumeMutatingWebhook) Admit(ar admissionv1.AdmissionReview) *admi
 		return toAdmissionResponseError(err)
 	}
 
-	ok, reason, err := cloneSourceHandler.cloneAuthFunc(wh.proxy, sourceNamespace, sourceName, targetNamespace, ar.Request.UserInfo)
+	skipSourceReadCheck := cloneSourceHandler.cloneType == snapshotClone && isFeatureGateEnabled(config, featuregates.SkipSnapshotSourceReadCheck)
+	if skipSourceReadCheck {
+		cloneSourceHandler.cloneAuthFunc = clone.CanUserCloneSnapshotWithoutReadCheck
+	}
+
+	proxy := wh.proxy
+	if batch := dataVolume.Labels[cc.LabelCloneBatch]; batch != "" && wh.batchAuth != nil {
+		proxy = wh.batchAuth.proxy(proxy, batch)
+	}
+	dryRun := ar.Request.DryRun != nil && *ar.Request.DryRun
+	proxy = clone.WithCloneRequest(proxy, targetName, dryRun)
+	ok, reason, err := cloneSourceHandler.cloneAuthFunc(proxy, sourceNamespace, sourceName, targetNamespace, ar.Request.UserInfo)
 	if err != nil {
 		return toAdmissionResponseError(err)
 	}
@@ -199,6 +228,9 @@ func (wh *dataVolumeMutatingWebhook) Admit(ar admissionv1.AdmissionReview) *admi
 			"targetName":      targetName,
 		},
 	}
+	if resync != "" {
+		tokenData.Params["resync"] = resync
+	}
 
 	token, err := wh.tokenGenerator.Generate(tokenData)
 	if err != nil {
@@ -208,13 +240,30 @@ func (wh *dataVolumeMutatingWebhook) Admit(ar admissionv1.AdmissionReview) *admi
 	if modifiedDataVolume.Annotations == nil {
 		modifiedDataVolume.Annotations = make(map[string]string)
 	}
-	modifiedDataVolume.Annotations[cc.AnnCloneToken] = token
+	modifiedDataVolume.Annotations[tokenAnnotation] = token
 
 	klog.V(3).Infof("Sending patch response...")
 
 	return toPatchResponse(dataVolume, modifiedDataVolume)
 }
 
+// cloneResyncRequested returns the value of the re-sync annotation of the DataVolume, and whether the update changes
+// it, asking for a re-sync the user has to be authorized for again
+func cloneResyncRequested(ar admissionv1.AdmissionReview, dataVolume *cdiv1.DataVolume) (string, bool, error) {
+	value, ok := dataVolume.Annotations[cc.AnnCloneResync]
+	if !ok || value == "" {
+		return "", false, nil
+	}
+	oldDataVolume := &cdiv1.DataVolume{}
+	if len(ar.Request.OldObject.Raw) > 0 {
+		if err := json.Unmarshal(ar.Request.OldObject.Raw, oldDataVolume); err != nil {
+			return "", false, err
+		}
+	}
+	oldValue, ok := oldDataVolume.Annotations[cc.AnnCloneResync]
+	return value, !ok || oldValue != value, nil
+}
+
 func newCloneSourceHandler(dataVolume *cdiv1.DataVolume, cdiClient cdiclient.Interface) (*cloneSourceHandler, error) {
 	var pvcSource *cdiv1.DataVolumeSourcePVC
 	var snapshotSource *cdiv1.DataVolumeSourceSnapshot
@@ -264,3 +313,12 @@ func newCloneSourceHandler(dataVolume *cdiv1.DataVolume, cdiClient cdiclient.Int
 		}, nil
 	}
 }
+
+func isFeatureGateEnabled(config *cdiv1.CDIConfig, featureGate string) bool {
+	for _, fg := range config.Spec.FeatureGates {
+		if fg == featureGate {
+			return true
+		}
+	}
+	return false
+}
@@ -32,6 +32,7 @@ import (
 
 	"github.com/appscode/jsonpatch"
 	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	authorization "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -41,8 +42,11 @@ import (
 	"k8s.io/utils/pointer"
 
 	cdiclientfake "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned/fake"
+	"kubevirt.io/containerized-data-importer/pkg/clone"
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+	featuregates "kubevirt.io/containerized-data-importer/pkg/feature-gates"
+	"kubevirt.io/containerized-data-importer/pkg/token"
 
 	cdicorev1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 )
@@ -228,6 +232,53 @@ var _ = Describe("Mutating DataVolume Webhook", func() {
 			Expect(resp.Patch).To(BeNil())
 		})
 
+		DescribeTable("should authorize the re-sync of a clone", func(oldResync, resync string, isAuthorized, expectAllowed, expectToken bool) {
+			dataVolume := newPVCDataVolume("testDV", "testNamespace", "test")
+			dataVolume.Annotations = map[string]string{cc.AnnCloneToken: "baz"}
+			if oldResync != "" {
+				dataVolume.Annotations[cc.AnnCloneResync] = oldResync
+			}
+			dvBytes, _ := json.Marshal(&dataVolume)
+			dataVolume.Annotations[cc.AnnCloneResync] = resync
+			dvBytesUpdated, _ := json.Marshal(&dataVolume)
+
+			ar := &admissionv1.AdmissionReview{
+				Request: &admissionv1.AdmissionRequest{
+					Operation: admissionv1.Update,
+					Resource: metav1.GroupVersionResource{
+						Group:    cdicorev1.SchemeGroupVersion.Group,
+						Version:  cdicorev1.SchemeGroupVersion.Version,
+						Resource: "datavolumes",
+					},
+					Object: runtime.RawExtension{
+						Raw: dvBytesUpdated,
+					},
+					OldObject: runtime.RawExtension{
+						Raw: dvBytes,
+					},
+				},
+			}
+
+			resp := mutateDVs(key, ar, isAuthorized)
+			Expect(resp.Allowed).To(Equal(expectAllowed))
+			if !expectToken {
+				Expect(resp.Patch).To(BeNil())
+				return
+			}
+			var patchObjs []jsonpatch.Operation
+			Expect(json.Unmarshal(resp.Patch, &patchObjs)).To(Succeed())
+			Expect(patchObjs).Should(HaveLen(1))
+			Expect(patchObjs[0].Path).Should(Equal("/metadata/annotations/cdi.kubevirt.io~1storage.clone.resyncToken"))
+			payload, err := token.NewValidator(common.CloneTokenIssuer, &key.PublicKey, time.Minute).Validate(patchObjs[0].Value.(string))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(payload.Params).To(HaveKeyWithValue("resync", resync))
+		},
+			Entry("with a token for the new value", "1", "2", true, true, true),
+			Entry("with a token for the first value", "", "1", true, true, true),
+			Entry("rejecting a user no longer authorized", "1", "2", false, false, false),
+			Entry("without token for the value already handled", "1", "1", false, true, false),
+		)
+
 		It("should reject a clone DataVolume", func() {
 			dataVolume := newPVCDataVolume("testDV", "testNamespace", "test")
 			dvBytes, _ := json.Marshal(&dataVolume)
@@ -251,6 +302,127 @@ var _ = Describe("Mutating DataVolume Webhook", func() {
 			Expect(resp.Patch).To(BeNil())
 		})
 
+		It("should reject a clone DataVolume allowed by RBAC but denied by a registered auth func", func() {
+			unregister := clone.RegisterUserCloneAuthFunc(func(clone.SubjectAccessReviewsProxy, string, string, string, authenticationv1.UserInfo) (bool, string, error) {
+				return false, "cross-tenant clones are not allowed", nil
+			})
+			defer unregister()
+			dataVolume := newPVCDataVolume("testDV", "testNamespace", "test")
+			dvBytes, _ := json.Marshal(&dataVolume)
+
+			ar := &admissionv1.AdmissionReview{
+				Request: &admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					Resource: metav1.GroupVersionResource{
+						Group:    cdicorev1.SchemeGroupVersion.Group,
+						Version:  cdicorev1.SchemeGroupVersion.Version,
+						Resource: "datavolumes",
+					},
+					Object: runtime.RawExtension{
+						Raw: dvBytes,
+					},
+				},
+			}
+
+			resp := mutateDVs(key, ar, true)
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Patch).To(BeNil())
+			Expect(resp.Result.Details.Causes).To(HaveLen(1))
+			Expect(resp.Result.Details.Causes[0].Message).To(Equal("cross-tenant clones are not allowed"))
+		})
+
+		It("should authorize the clones of a batch once per user and source with the default settings", func() {
+			defaultNs := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+			testNs := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "testNamespace"}}
+			client := fakeclient.NewSimpleClientset(&defaultNs, &testNs)
+			sars := 0
+			client.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				sars++
+				sar := action.(k8stesting.CreateAction).GetObject().(*authorization.SubjectAccessReview)
+				sar.Status.Allowed = true
+				return true, sar, nil
+			})
+			cdiClient := cdiclientfake.NewSimpleClientset(cc.MakeEmptyCDIConfigSpec(common.ConfigName))
+			wh := NewDataVolumeMutatingWebhook(client, cdiClient, key)
+
+			admit := func(name, batch, user string) {
+				dataVolume := newPVCDataVolume(name, "testNamespace", "test")
+				if batch != "" {
+					dataVolume.Labels = map[string]string{cc.LabelCloneBatch: batch}
+				}
+				dvBytes, _ := json.Marshal(&dataVolume)
+				ar := &admissionv1.AdmissionReview{
+					Request: &admissionv1.AdmissionRequest{
+						Operation: admissionv1.Create,
+						Resource: metav1.GroupVersionResource{
+							Group:    cdicorev1.SchemeGroupVersion.Group,
+							Version:  cdicorev1.SchemeGroupVersion.Version,
+							Resource: "datavolumes",
+						},
+						Object: runtime.RawExtension{
+							Raw: dvBytes,
+						},
+						UserInfo: authenticationv1.UserInfo{Username: user},
+					},
+				}
+				resp := serve(ar, wh)
+				Expect(resp.Allowed).To(BeTrue())
+				Expect(resp.Patch).ToNot(BeNil())
+			}
+
+			admit("testDV0", "classroom", "teacher")
+			sarsPerClone := sars
+			Expect(sarsPerClone).ToNot(BeZero())
+			for i := 1; i < 5; i++ {
+				admit(fmt.Sprintf("testDV%d", i), "classroom", "teacher")
+			}
+			Expect(sars).To(Equal(sarsPerClone))
+
+			By("Authorizing again another user of the batch")
+			admit("testDV5", "classroom", "assistant")
+			Expect(sars).To(Equal(2 * sarsPerClone))
+
+			By("Authorizing each clone which is not part of a batch")
+			admit("testDV6", "", "teacher")
+			admit("testDV7", "", "teacher")
+			Expect(sars).To(Equal(4 * sarsPerClone))
+		})
+
+		DescribeTable("should audit the clone authorization of the DataVolume", func(dryRun bool, expectedDecisions int) {
+			var decisions []*clone.CloneAuthorizationDecision
+			defer clone.RegisterCloneAuthorizationAuditor(func(decision *clone.CloneAuthorizationDecision) {
+				decisions = append(decisions, decision)
+			})()
+			dataVolume := newPVCDataVolume("testDV", "testNamespace", "test")
+			dvBytes, _ := json.Marshal(&dataVolume)
+
+			ar := &admissionv1.AdmissionReview{
+				Request: &admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					Resource: metav1.GroupVersionResource{
+						Group:    cdicorev1.SchemeGroupVersion.Group,
+						Version:  cdicorev1.SchemeGroupVersion.Version,
+						Resource: "datavolumes",
+					},
+					Object: runtime.RawExtension{
+						Raw: dvBytes,
+					},
+					DryRun: pointer.Bool(dryRun),
+				},
+			}
+
+			resp := mutateDVs(key, ar, true)
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(decisions).To(HaveLen(expectedDecisions))
+			for _, decision := range decisions {
+				Expect(decision.TargetNamespace).To(Equal(dataVolume.Namespace))
+				Expect(decision.TargetName).To(Equal("testDV"))
+			}
+		},
+			Entry("naming the target DataVolume", false, 1),
+			Entry("unless the request is a dry run", true, 0),
+		)
+
 		DescribeTable("should accept a clone DataVolume", func(anno string) {
 			dataVolume := newPVCDataVolume("testDV", "testNamespace", "test")
 			Expect(dataVolume.Annotations).To(BeNil())
@@ -365,6 +537,48 @@ var _ = Describe("Mutating DataVolume Webhook", func() {
 			Entry("succeed with empty namespace", ""),
 		)
 
+		DescribeTable("should check the source snapshot read access on snapshot clone", func(featureGates []string, canReadSnapshot, expectReadCheck, expectAllowed bool) {
+			dataVolume := newSnapshotDataVolume("testDV", "testNamespace", "test")
+			dvBytes, _ := json.Marshal(&dataVolume)
+
+			ar := &admissionv1.AdmissionReview{
+				Request: &admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					Resource: metav1.GroupVersionResource{
+						Group:    cdicorev1.SchemeGroupVersion.Group,
+						Version:  cdicorev1.SchemeGroupVersion.Version,
+						Resource: "datavolumes",
+					},
+					Object: runtime.RawExtension{
+						Raw: dvBytes,
+					},
+				},
+			}
+
+			readCheckSent := false
+			resp := mutateDVsWithSAR(key, ar, featureGates, func(ra *authorization.ResourceAttributes) bool {
+				switch ra.Resource {
+				case "datavolumes":
+					// Not explicitly allowed, rely on the implicit permissions
+					return false
+				case "volumesnapshots":
+					Expect(ra.Verb).To(Equal("get"))
+					Expect(ra.Group).To(Equal("snapshot.storage.k8s.io"))
+					Expect(ra.Namespace).To(Equal("testNamespace"))
+					Expect(ra.Name).To(Equal("test"))
+					readCheckSent = true
+					return canReadSnapshot
+				}
+				return true
+			})
+			Expect(readCheckSent).To(Equal(expectReadCheck))
+			Expect(resp.Allowed).To(Equal(expectAllowed))
+		},
+			Entry("allow the clone if the snapshot can be read", nil, true, true, true),
+			Entry("reject the clone if the snapshot cannot be read", nil, false, true, false),
+			Entry("not check the snapshot read access if skipped", []string{featuregates.SkipSnapshotSourceReadCheck}, false, false, true),
+		)
+
 		DescribeTable("should", func(ttl int) {
 			dataVolume := newHTTPDataVolume("testDV", "http://www.example.com")
 			dvBytes, _ := json.Marshal(&dataVolume)
@@ -442,3 +656,20 @@ func mutateDVsEx(key *rsa.PrivateKey, ar *admissionv1.AdmissionReview, isAuthori
 	wh := NewDataVolumeMutatingWebhook(client, cdiClient, key)
 	return serve(ar, wh)
 }
+
+func mutateDVsWithSAR(key *rsa.PrivateKey, ar *admissionv1.AdmissionReview, featureGates []string, isAllowed func(*authorization.ResourceAttributes) bool) *admissionv1.AdmissionResponse {
+	defaultNs := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+	testNs := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "testNamespace"}}
+	client := fakeclient.NewSimpleClientset(&defaultNs, &testNs)
+	client.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		sar := action.(k8stesting.CreateAction).GetObject().(*authorization.SubjectAccessReview)
+		sar.Status.Allowed = isAllowed(sar.Spec.ResourceAttributes)
+		return true, sar, nil
+	})
+
+	cdiConfig := cc.MakeEmptyCDIConfigSpec(common.ConfigName)
+	cdiConfig.Spec.FeatureGates = featureGates
+	cdiClient := cdiclientfake.NewSimpleClientset(cdiConfig)
+	wh := NewDataVolumeMutatingWebhook(client, cdiClient, key)
+	return serve(ar, wh)
+}
@@ -21,17 +21,32 @@ package webhooks
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	neturl "net/url"
+	"path"
+	"path/filepath"
 	"reflect"
+	"strings"
+	"time"
 
 	snapclient "github.com/kubernetes-csi/external-snapshotter/client/v6/clientset/versioned"
+	ocpcrypto "github.com/openshift/library-go/pkg/crypto"
+	"golang.org/x/crypto/ssh"
 	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	kvalidation "k8s.io/apimachinery/pkg/util/validation"
 	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/kubernetes"
@@ -39,7 +54,9 @@ import (
 
 	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	cdiclient "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned"
+	"kubevirt.io/containerized-data-importer/pkg/common"
 	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+	"kubevirt.io/containerized-data-importer/pkg/util"
 )
 
 type dataVolumeValidatingWebhook struct {
@@ -54,31 +71,1276 @@ func validateSourceURL(sourceURL string) string {
 	}
 	url, err := neturl.ParseRequestURI(sourceURL)
 	if err != nil {
-		return fmt.Sprintf("Invalid source URL: %s", sourceURL)
+		return fmt.Sprintf("Invalid source URL: %s", util.RedactSourceURL(sourceURL))
 	}
 
 	if url.Scheme != "http" && url.Scheme != "https" && url.Scheme != "gs" {
-		return fmt.Sprintf("Invalid source URL scheme: %s", sourceURL)
+		return fmt.Sprintf("Invalid source URL scheme: %s", util.RedactSourceURL(sourceURL))
 	}
 	return ""
 }
 
+// dataVolumeSource describes a source of a DataVolume, for the checks depending on the source
+type dataVolumeSource struct {
+	// name is the name of the source in the messages
+	name string
+	// isSet returns true if the DataVolume has this source
+	isSet func(source *cdiv1.DataVolumeSource) bool
+	// validate validates the source, given the field of the DataVolume source, nil if it has no checks of its own
+	validate func(source *cdiv1.DataVolumeSource, field *k8sfield.Path) *metav1.StatusCause
+	// kubevirtOnly is true when the source can't be imported as an archive
+	kubevirtOnly bool
+	// encryptable is true when the image imported from the source can be written into a LUKS container
+	encryptable bool
+	// verifiable is true when the importer can check the source without transferring the image
+	verifiable bool
+}
+
+// dataVolumeSources are the sources with checks depending on the source, the other ones are checked on their own
+var dataVolumeSources = []dataVolumeSource{
+	{
+		name:  "HTTP",
+		isSet: func(source *cdiv1.DataVolumeSource) bool { return source.HTTP != nil },
+		validate: func(source *cdiv1.DataVolumeSource, field *k8sfield.Path) *metav1.StatusCause {
+			return validateURLSource(source.HTTP.URL, field, "HTTP")
+		},
+		encryptable: true,
+		verifiable:  true,
+	},
+	{
+		name:  "S3",
+		isSet: func(source *cdiv1.DataVolumeSource) bool { return source.S3 != nil },
+		validate: func(source *cdiv1.DataVolumeSource, field *k8sfield.Path) *metav1.StatusCause {
+			return validateS3Source(source.S3, field)
+		},
+		encryptable: true,
+		verifiable:  true,
+	},
+	{
+		name:  "GCS",
+		isSet: func(source *cdiv1.DataVolumeSource) bool { return source.GCS != nil },
+		validate: func(source *cdiv1.DataVolumeSource, field *k8sfield.Path) *metav1.StatusCause {
+			return validateGCSSource(source.GCS, field)
+		},
+		encryptable: true,
+		verifiable:  true,
+	},
+	{
+		name:         "Registry",
+		isSet:        func(source *cdiv1.DataVolumeSource) bool { return source.Registry != nil },
+		kubevirtOnly: true,
+		encryptable:  true,
+	},
+	{
+		name:  "Imageio",
+		isSet: func(source *cdiv1.DataVolumeSource) bool { return source.Imageio != nil },
+		validate: func(source *cdiv1.DataVolumeSource, field *k8sfield.Path) *metav1.StatusCause {
+			return validateURLSource(source.Imageio.URL, field, "Imageio")
+		},
+		encryptable: true,
+	},
+	{
+		name:  "VDDK",
+		isSet: func(source *cdiv1.DataVolumeSource) bool { return source.VDDK != nil },
+		validate: func(source *cdiv1.DataVolumeSource, field *k8sfield.Path) *metav1.StatusCause {
+			return validateURLSource(source.VDDK.URL, field, "VDDK")
+		},
+		// encrypting it is rejected by validateEncryption with its own reason
+		encryptable: true,
+	},
+	{
+		name:  "Inline",
+		isSet: func(source *cdiv1.DataVolumeSource) bool { return source.Inline != nil },
+		validate: func(source *cdiv1.DataVolumeSource, field *k8sfield.Path) *metav1.StatusCause {
+			return validateInlineSource(source.Inline, field.Child("inline", "data"))
+		},
+	},
+	{
+		name:  "NBD",
+		isSet: func(source *cdiv1.DataVolumeSource) bool { return source.NBD != nil },
+		validate: func(source *cdiv1.DataVolumeSource, field *k8sfield.Path) *metav1.StatusCause {
+			return validateNBDSource(source.NBD, field.Child("nbd"))
+		},
+		kubevirtOnly: true,
+	},
+	{
+		name:  "Remote",
+		isSet: func(source *cdiv1.DataVolumeSource) bool { return source.Remote != nil },
+		validate: func(source *cdiv1.DataVolumeSource, field *k8sfield.Path) *metav1.StatusCause {
+			return validateRemoteSource(source.Remote, field.Child("remote"))
+		},
+		kubevirtOnly: true,
+	},
+	{
+		name:  "RemotePVC",
+		isSet: func(source *cdiv1.DataVolumeSource) bool { return source.RemotePVC != nil },
+		validate: func(source *cdiv1.DataVolumeSource, field *k8sfield.Path) *metav1.StatusCause {
+			return validateRemotePVCSource(source.RemotePVC, field.Child("remotePVC"))
+		},
+		kubevirtOnly: true,
+	},
+	{
+		name:  "FTP",
+		isSet: func(source *cdiv1.DataVolumeSource) bool { return source.FTP != nil },
+		validate: func(source *cdiv1.DataVolumeSource, field *k8sfield.Path) *metav1.StatusCause {
+			return validateFTPSource(source.FTP, field.Child("ftp"))
+		},
+		kubevirtOnly: true,
+		encryptable:  true,
+	},
+	{
+		// the raw blobs and fixed VHDs are written to the target by the data source, they can't be encrypted
+		name:  "Azure",
+		isSet: func(source *cdiv1.DataVolumeSource) bool { return source.Azure != nil },
+		validate: func(source *cdiv1.DataVolumeSource, field *k8sfield.Path) *metav1.StatusCause {
+			return validateAzureSource(source.Azure, field.Child("azure"))
+		},
+		kubevirtOnly: true,
+	},
+	{
+		// the raw images are written to the target by the data source, they can't be encrypted
+		name:  "SFTP",
+		isSet: func(source *cdiv1.DataVolumeSource) bool { return source.SFTP != nil },
+		validate: func(source *cdiv1.DataVolumeSource, field *k8sfield.Path) *metav1.StatusCause {
+			return validateSFTPSource(source.SFTP, field.Child("sftp"))
+		},
+		kubevirtOnly: true,
+	},
+}
+
+// findDataVolumeSource returns the description of the source of a DataVolume, nil if it has none of dataVolumeSources
+func findDataVolumeSource(source *cdiv1.DataVolumeSource) *dataVolumeSource {
+	if source == nil {
+		return nil
+	}
+	for i := range dataVolumeSources {
+		if dataVolumeSources[i].isSet(source) {
+			return &dataVolumeSources[i]
+		}
+	}
+	return nil
+}
+
+// validateURLSource validates the URL of the source named name
+func validateURLSource(sourceURL string, field *k8sfield.Path, name string) *metav1.StatusCause {
+	if err := validateSourceURL(sourceURL); err != "" {
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s %s", field.String(), err),
+			Field:   field.Child(name, "url").String(),
+		}
+	}
+	return nil
+}
+
+// validateS3Source validates the URL of an S3 source, and that its token credentials have the role to assume
+func validateS3Source(s3 *cdiv1.DataVolumeSourceS3, field *k8sfield.Path) *metav1.StatusCause {
+	if cause := validateURLSource(s3.URL, field, "S3"); cause != nil {
+		return cause
+	}
+	if s3.TokenCredentials != nil && s3.TokenCredentials.RoleARN == "" {
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueRequired,
+			Message: "S3 token credentials require a role ARN",
+			Field:   field.Child("S3", "tokenCredentials", "roleARN").String(),
+		}
+	}
+	return nil
+}
+
+// validateGCSSource validates the URL of a GCS source, the audience of its token credentials and the generation of
+// the object
+func validateGCSSource(gcs *cdiv1.DataVolumeSourceGCS, field *k8sfield.Path) *metav1.StatusCause {
+	if cause := validateURLSource(gcs.URL, field, "GCS"); cause != nil {
+		return cause
+	}
+	if gcs.TokenCredentials != nil && gcs.TokenCredentials.Audience == "" {
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueRequired,
+			Message: "GCS token credentials require the workload identity provider audience",
+			Field:   field.Child("GCS", "tokenCredentials", "audience").String(),
+		}
+	}
+	if gcs.Generation < 0 {
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("GCS object generation %d is invalid", gcs.Generation),
+			Field:   field.Child("GCS", "generation").String(),
+		}
+	}
+	return nil
+}
+
 func validateNameLength(name string, maxLen int) []metav1.StatusCause {
 	var causes []metav1.StatusCause
-	if len(name) > maxLen {
+	if len(name) > maxLen {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Name cannot be longer than %d characters", maxLen),
+			Field:   "",
+		})
+	}
+	return causes
+}
+
+// reservedDataVolumeAnnotations are set by CDI on the PVC, and can't be passed from the DataVolume
+var reservedDataVolumeAnnotations = []string{
+	cc.AnnPodPhase,
+	cc.AnnPodReady,
+	cc.AnnPodRestarts,
+	cc.AnnContentType,
+	cc.AnnPreallocationRequested,
+	cc.AnnOwnerUID,
+	cc.AnnPopulatedVerified,
+	cc.AnnImportAttemptsBase,
+	cc.AnnImportAttemptsExhausted,
+	cc.AnnImageFormat,
+	cc.AnnImageVirtualSize,
+	cc.AnnImportDownloadSeconds,
+	cc.AnnImportConvertSeconds,
+	cc.AnnImportResizeSeconds,
+	cc.AnnImageTargetFormat,
+	cc.AnnImageTargetCompression,
+	cc.AnnSourceDigest,
+	cc.AnnCompletionTimedOut,
+	cc.AnnAdoptedPVC,
+	cc.AnnCanceled,
+}
+
+// validateDataVolumeMetadata validates the labels and annotations the DataVolume passes to its PVC. The reserved
+// annotations and the CDI label can't be set. On update, only the labels and annotations added or changed are
+// validated, the ones set by CDI or before the validation existed are kept.
+func validateDataVolumeMetadata(dv, oldDV *cdiv1.DataVolume) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	labelsPath := k8sfield.NewPath("metadata").Child("labels")
+	for key, value := range dv.Labels {
+		if oldDV != nil {
+			if oldValue, ok := oldDV.Labels[key]; ok && oldValue == value {
+				continue
+			}
+		}
+		for _, msg := range kvalidation.IsQualifiedName(key) {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("Invalid label key %q: %s", key, msg),
+				Field:   labelsPath.Key(key).String(),
+			})
+		}
+		for _, msg := range kvalidation.IsValidLabelValue(value) {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("Invalid value %q of label %s: %s", value, key, msg),
+				Field:   labelsPath.Key(key).String(),
+			})
+		}
+		if key == common.CDILabelKey && value != common.CDILabelValue {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueNotSupported,
+				Message: fmt.Sprintf("Label %s is reserved for CDI, it can only be set to %s", key, common.CDILabelValue),
+				Field:   labelsPath.Key(key).String(),
+			})
+		}
+	}
+	annotationsPath := k8sfield.NewPath("metadata").Child("annotations")
+	for key, value := range dv.Annotations {
+		if oldDV != nil {
+			if oldValue, ok := oldDV.Annotations[key]; ok && oldValue == value {
+				continue
+			}
+		}
+		for _, msg := range kvalidation.IsQualifiedName(strings.ToLower(key)) {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("Invalid annotation key %q: %s", key, msg),
+				Field:   annotationsPath.Key(key).String(),
+			})
+		}
+	}
+	for _, ann := range reservedDataVolumeAnnotations {
+		value, ok := dv.Annotations[ann]
+		if !ok {
+			continue
+		}
+		if oldDV != nil {
+			if oldValue, ok := oldDV.Annotations[ann]; ok && oldValue == value {
+				continue
+			}
+		}
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: fmt.Sprintf("Annotation %s is reserved for CDI and can't be set on a DataVolume", ann),
+			Field:   annotationsPath.Key(ann).String(),
+		})
+	}
+	return causes
+}
+
+// validateCompletionTimeout validates the completion timeout is a positive duration, when it is set or changed
+func validateCompletionTimeout(dv, oldDV *cdiv1.DataVolume) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	value, ok := dv.Annotations[cc.AnnCompletionTimeout]
+	if !ok {
+		return causes
+	}
+	if oldDV != nil {
+		if oldValue, ok := oldDV.Annotations[cc.AnnCompletionTimeout]; ok && oldValue == value {
+			return causes
+		}
+	}
+	if timeout, err := time.ParseDuration(value); err != nil || timeout <= 0 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Invalid completion timeout %q, should be a positive duration such as 2h", value),
+			Field:   k8sfield.NewPath("metadata").Child("annotations").Key(cc.AnnCompletionTimeout).String(),
+		})
+	}
+	return causes
+}
+
+// validateVerifyPopulated validates the verification requested for the populated PVC. The digest verification needs
+// the expected digest, and the digest of the source computed with its algorithm unless the PVC is populated out of CDI.
+func validateVerifyPopulated(dv *cdiv1.DataVolume) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	verify, ok := dv.Annotations[cc.AnnVerifyPopulated]
+	if !ok {
+		return causes
+	}
+	annotations := k8sfield.NewPath("metadata").Child("annotations")
+	switch verify {
+	case cc.VerifyPopulatedBound, cc.VerifyPopulatedSize:
+		return causes
+	case cc.VerifyPopulatedDigest:
+	default:
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: fmt.Sprintf("Invalid populated verification %q, should be %q, %q or %q", verify, cc.VerifyPopulatedBound, cc.VerifyPopulatedSize, cc.VerifyPopulatedDigest),
+			Field:   annotations.Key(cc.AnnVerifyPopulated).String(),
+		})
+		return causes
+	}
+	field := annotations.Key(cc.AnnVerifyPopulatedDigest).String()
+	algorithm, digest, _ := strings.Cut(dv.Annotations[cc.AnnVerifyPopulatedDigest], ":")
+	digestLength := map[string]int{common.SourceDigestSHA256: sha256.Size * 2, common.SourceDigestSHA512: sha512.Size * 2}[algorithm]
+	if _, err := hex.DecodeString(digest); err != nil || digestLength == 0 || len(digest) != digestLength {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("The digest verification needs the expected digest, as %s:<hex> or %s:<hex>", common.SourceDigestSHA256, common.SourceDigestSHA512),
+			Field:   field,
+		})
+		return causes
+	}
+	if _, prePopulated := dv.Annotations[cc.AnnPrePopulated]; !prePopulated && dv.Annotations[cc.AnnSourceDigestAlgorithm] != algorithm {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("The digest verification needs the source digest computed with %s, set %s to %s", algorithm, cc.AnnSourceDigestAlgorithm, algorithm),
+			Field:   field,
+		})
+	}
+	return causes
+}
+
+// validateVerifyOnly validates a DataVolume only verifying its import source, the source must be one the importer
+// can check without transferring the image
+func validateVerifyOnly(dv *cdiv1.DataVolume) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	if !cc.IsVerifyOnly(dv) {
+		return causes
+	}
+	field := k8sfield.NewPath("metadata").Child("annotations").Key(cc.AnnVerifyOnly).String()
+	if dvSource := findDataVolumeSource(dv.Spec.Source); dvSource == nil || !dvSource.verifiable {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: "Only the http, s3 and gcs sources can be verified",
+			Field:   field,
+		})
+		return causes
+	}
+	if dv.Spec.ContentType == cdiv1.DataVolumeArchive {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: "The source of an archive content type DataVolume can't be verified",
+			Field:   field,
+		})
+	}
+	return causes
+}
+
+// validateInlineSource validates the image embedded in a DataVolume is valid base64 and under the size cap, so inline
+// sources can't be used to store large objects in etcd
+func validateInlineSource(inline *cdiv1.DataVolumeSourceInline, field *k8sfield.Path) *metav1.StatusCause {
+	if inline.Data == "" {
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueRequired,
+			Message: "Inline source requires base64 encoded data",
+			Field:   field.String(),
+		}
+	}
+	if len(inline.Data) > common.InlineSourceMaxSize {
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Inline source data is %d bytes, larger than the %d bytes limit", len(inline.Data), common.InlineSourceMaxSize),
+			Field:   field.String(),
+		}
+	}
+	if _, err := io.Copy(io.Discard, base64.NewDecoder(base64.StdEncoding, strings.NewReader(inline.Data))); err != nil {
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Inline source data is not valid base64: %v", err),
+			Field:   field.String(),
+		}
+	}
+	return nil
+}
+
+// validateNBDSource validates the URL of an NBD source, and that NBD over TLS has the secret holding its credentials
+func validateNBDSource(nbd *cdiv1.DataVolumeSourceNBD, field *k8sfield.Path) *metav1.StatusCause {
+	url, err := neturl.Parse(nbd.URL)
+	if err != nil || (url.Scheme != "nbd" && url.Scheme != "nbds") || url.Hostname() == "" || url.RawQuery != "" {
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Invalid NBD source URL: %s, expected nbd://host[:port]/export or nbds://host[:port]/export", util.RedactSourceURL(nbd.URL)),
+			Field:   field.Child("url").String(),
+		}
+	}
+	if url.Scheme == "nbds" && nbd.SecretRef == "" {
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueRequired,
+			Message: "NBD over TLS requires a secret with the CA certificate of the server",
+			Field:   field.Child("secretRef").String(),
+		}
+	}
+	return nil
+}
+
+// validateFTPSource validates the URL of an FTP source points to a file of an FTP or FTPS server
+func validateFTPSource(ftp *cdiv1.DataVolumeSourceFTP, field *k8sfield.Path) *metav1.StatusCause {
+	url, err := neturl.Parse(ftp.URL)
+	if err != nil || (url.Scheme != "ftp" && url.Scheme != "ftps") || url.Hostname() == "" || strings.Trim(url.Path, "/") == "" || url.RawQuery != "" {
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Invalid FTP source URL: %s, expected ftp://host[:port]/path or ftps://host[:port]/path", util.RedactSourceURL(ftp.URL)),
+			Field:   field.Child("url").String(),
+		}
+	}
+	if url.User != nil {
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: "FTP source URL must not hold credentials, use the secret of the source",
+			Field:   field.Child("url").String(),
+		}
+	}
+	// the path is sent in the FTP commands, which end with a line break
+	if strings.ContainsAny(url.Path, "\r\n") {
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: "FTP source URL path must not hold line breaks",
+			Field:   field.Child("url").String(),
+		}
+	}
+	return nil
+}
+
+// validateAzureSource validates the URL of an Azure Blob source points to a blob of a container, without a SAS token,
+// and that the credentials of its secret are only sent over https
+func validateAzureSource(azure *cdiv1.DataVolumeSourceAzure, field *k8sfield.Path) *metav1.StatusCause {
+	url, err := neturl.Parse(azure.URL)
+	if err != nil || (url.Scheme != "https" && url.Scheme != "http") || url.Hostname() == "" || url.User != nil || !strings.Contains(strings.Trim(url.Path, "/"), "/") {
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Invalid Azure Blob source URL: %s, expected https://account.blob.core.windows.net/container/blob", util.RedactSourceURL(azure.URL)),
+			Field:   field.Child("url").String(),
+		}
+	}
+	if url.RawQuery != "" {
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: "Azure Blob source URL must not hold a SAS token, use the secret of the source",
+			Field:   field.Child("url").String(),
+		}
+	}
+	if url.Scheme != "https" && azure.SecretRef != "" {
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: "Azure Blob source URL must be https when the source has a secret, its credentials are not sent in cleartext",
+			Field:   field.Child("url").String(),
+		}
+	}
+	return nil
+}
+
+// validateSFTPSource validates the URL of an SFTP source points to a file of an SFTP server, that the source has the
+// secret holding the credentials, and that the host key of the server is pinned
+func validateSFTPSource(sftp *cdiv1.DataVolumeSourceSFTP, field *k8sfield.Path) *metav1.StatusCause {
+	url, err := neturl.Parse(sftp.URL)
+	if err != nil || url.Scheme != "sftp" || url.Hostname() == "" || strings.Trim(url.Path, "/") == "" || url.RawQuery != "" {
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Invalid SFTP source URL: %s, expected sftp://host[:port]/path", util.RedactSourceURL(sftp.URL)),
+			Field:   field.Child("url").String(),
+		}
+	}
+	if url.User != nil {
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: "SFTP source URL must not hold credentials, use the secret of the source",
+			Field:   field.Child("url").String(),
+		}
+	}
+	if sftp.SecretRef == "" {
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueRequired,
+			Message: "SFTP source requires a secret with the user name and its private key or password",
+			Field:   field.Child("secretRef").String(),
+		}
+	}
+	hostKey := strings.TrimSpace(sftp.HostKey)
+	if hostKey == "" {
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueRequired,
+			Message: "SFTP source requires the host key of the server, or its SHA256 fingerprint",
+			Field:   field.Child("hostKey").String(),
+		}
+	}
+	if !strings.HasPrefix(hostKey, "SHA256:") {
+		if _, _, _, _, err := ssh.ParseAuthorizedKey([]byte(hostKey)); err != nil {
+			return &metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: "Invalid SFTP host key, expected a public key in the authorized_keys format or a SHA256:... fingerprint",
+				Field:   field.Child("hostKey").String(),
+			}
+		}
+	}
+	return nil
+}
+
+// validateRemoteSource validates the URL of a remote clone source, and that it has the secret holding the token
+// authorizing the export
+func validateRemoteSource(remote *cdiv1.DataVolumeSourceRemote, field *k8sfield.Path) *metav1.StatusCause {
+	url, err := neturl.Parse(remote.URL)
+	if err != nil || (url.Scheme != "http" && url.Scheme != "https") || url.Hostname() == "" {
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Invalid remote source URL: %s, expected an http(s) URL", util.RedactSourceURL(remote.URL)),
+			Field:   field.Child("url").String(),
+		}
+	}
+	if remote.SecretRef == "" {
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueRequired,
+			Message: "Remote source requires a secret with the token authorizing the export",
+			Field:   field.Child("secretRef").String(),
+		}
+	}
+	return nil
+}
+
+// validateRemotePVCSource validates that a remote PVC clone source has the source PVC and the secret holding the
+// kubeconfig of the remote cluster
+func validateRemotePVCSource(remotePVC *cdiv1.DataVolumeSourceRemotePVC, field *k8sfield.Path) *metav1.StatusCause {
+	if remotePVC.KubeconfigSecretRef == "" {
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueRequired,
+			Message: "Remote PVC source requires a secret with the kubeconfig of the remote cluster",
+			Field:   field.Child("kubeconfigSecretRef").String(),
+		}
+	}
+	if remotePVC.Namespace == "" || remotePVC.Name == "" {
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s source remote PVC is not valid", field.String()),
+			Field:   field.String(),
+		}
+	}
+	return nil
+}
+
+// validateRemotePVCSecretAccess validates that the requester of a remote PVC clone may get the kubeconfig secret, as
+// the controller reads it on their behalf
+func (wh *dataVolumeValidatingWebhook) validateRemotePVCSecretAccess(dv *cdiv1.DataVolume, namespace string, userInfo authenticationv1.UserInfo) ([]metav1.StatusCause, error) {
+	if dv.Spec.Source == nil || dv.Spec.Source.RemotePVC == nil {
+		return nil, nil
+	}
+	secretName := dv.Spec.Source.RemotePVC.KubeconfigSecretRef
+	var extra map[string]authorizationv1.ExtraValue
+	if len(userInfo.Extra) > 0 {
+		extra = make(map[string]authorizationv1.ExtraValue)
+		for k, v := range userInfo.Extra {
+			extra[k] = authorizationv1.ExtraValue(v)
+		}
+	}
+	sar := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   userInfo.Username,
+			Groups: userInfo.Groups,
+			Extra:  extra,
+			UID:    userInfo.UID,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "get",
+				Resource:  "secrets",
+				Name:      secretName,
+			},
+		},
+	}
+	response, err := wh.k8sClient.AuthorizationV1().SubjectAccessReviews().Create(context.TODO(), sar, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if !response.Status.Allowed {
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("User %s is not allowed to get the kubeconfig secret %s", userInfo.Username, secretName),
+			Field:   k8sfield.NewPath("spec", "source", "remotePVC", "kubeconfigSecretRef").String(),
+		}}, nil
+	}
+	return nil, nil
+}
+
+// validateShrinkToUsedSize validates a DataVolume shrinking the imported image to the used space of its filesystem,
+// only the disk images written by the importer can be shrunk
+func validateShrinkToUsedSize(dv *cdiv1.DataVolume) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	value, ok := dv.Annotations[cc.AnnShrinkToUsedSize]
+	if !ok {
+		return causes
+	}
+	field := k8sfield.NewPath("metadata").Child("annotations").Key(cc.AnnShrinkToUsedSize).String()
+	if value != "true" && value != "false" {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Invalid value %q, should be \"true\" or \"false\"", value),
+			Field:   field,
+		})
+		return causes
+	}
+	if value == "false" {
+		return causes
+	}
+	source := dv.Spec.Source
+	if source == nil || (source.HTTP == nil && source.S3 == nil && source.GCS == nil && source.Registry == nil && source.Imageio == nil && source.VDDK == nil) {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: "Only imported images can be shrunk",
+			Field:   field,
+		})
+		return causes
+	}
+	if dv.Spec.ContentType == cdiv1.DataVolumeArchive {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: "An archive content type DataVolume can't be shrunk",
+			Field:   field,
+		})
+	}
+	return causes
+}
+
+// validateScratchSpaceBackend validates the kind of volume backing the scratch space of a DataVolume
+func validateScratchSpaceBackend(dv *cdiv1.DataVolume) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	value, ok := dv.Annotations[cc.AnnScratchSpaceBackend]
+	if !ok || cc.IsValidScratchSpaceBackend(value) {
+		return causes
+	}
+	causes = append(causes, metav1.StatusCause{
+		Type:    metav1.CauseTypeFieldValueInvalid,
+		Message: fmt.Sprintf("Invalid scratch space backend %q, should be %q, %q or %q", value, cdiv1.ScratchSpaceBackendPVC, cdiv1.ScratchSpaceBackendEmptyDirDisk, cdiv1.ScratchSpaceBackendEmptyDirMemory),
+		Field:   k8sfield.NewPath("metadata").Child("annotations").Key(cc.AnnScratchSpaceBackend).String(),
+	})
+	return causes
+}
+
+// validateSnapshotTarget validates a DataVolume importing into a VolumeSnapshot. Only a single stage import can
+// populate the PVC the snapshot is taken from.
+func validateSnapshotTarget(dv *cdiv1.DataVolume) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	target := dv.Spec.SnapshotTarget
+	if target == nil {
+		return causes
+	}
+	field := k8sfield.NewPath("spec").Child("snapshotTarget")
+	source := dv.Spec.Source
+	if source == nil || source.PVC != nil || source.Snapshot != nil || source.Upload != nil {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: "Only an imported DataVolume can have a snapshot target",
+			Field:   field.String(),
+		})
+		return causes
+	}
+	if len(dv.Spec.Checkpoints) > 0 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: "A multistage import can't have a snapshot target",
+			Field:   field.String(),
+		})
+	}
+	if target.Name != "" {
+		for _, msg := range kvalidation.IsDNS1123Subdomain(target.Name) {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("Invalid VolumeSnapshot name %q: %s", target.Name, msg),
+				Field:   field.Child("name").String(),
+			})
+		}
+	}
+	if policy := target.PVCRetainPolicy; policy != "" && policy != cdiv1.SnapshotTargetPVCDelete && policy != cdiv1.SnapshotTargetPVCRetain {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Invalid PVC retain policy %q, should be %q or %q", policy, cdiv1.SnapshotTargetPVCDelete, cdiv1.SnapshotTargetPVCRetain),
+			Field:   field.Child("pvcRetainPolicy").String(),
+		})
+	}
+	return causes
+}
+
+// validateRegistryDiskPVCs validates the PVCs of the disks of a multi-disk registry image. The PVC of the DataVolume
+// is the one of the disk without a PVC name, and the disks can't be imported into a VolumeSnapshot.
+func validateRegistryDiskPVCs(dv *cdiv1.DataVolume) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	if dv.Spec.Source == nil || dv.Spec.Source.Registry == nil || len(dv.Spec.Source.Registry.Disks) == 0 {
+		return causes
+	}
+	field := k8sfield.NewPath("spec", "source", "Registry", "disks")
+	if dv.Spec.SnapshotTarget != nil {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: "A multi-disk registry image can't be imported into a VolumeSnapshot",
+			Field:   field.String(),
+		})
+	}
+	for i, disk := range dv.Spec.Source.Registry.Disks {
+		if disk.PVCName == dv.Name {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("PVC name %q is the one of the DataVolume, leave it empty to import the disk into the PVC of the DataVolume", disk.PVCName),
+				Field:   field.Index(i).Child("pvcName").String(),
+			})
+		}
+	}
+	return causes
+}
+
+// validateTargetFormat validates a DataVolume writing a qcow2 image to its PVC, optionally with compressed clusters.
+// Compression is only supported for qcow2 targets, and only the disk images written by the importer can be qcow2: the
+// imported ones and the PVC clones, from the source of the DataVolume or the one its sourceRef resolves to.
+func (wh *dataVolumeValidatingWebhook) validateTargetFormat(dv *cdiv1.DataVolume) ([]metav1.StatusCause, error) {
+	var causes []metav1.StatusCause
+	format, hasFormat := dv.Annotations[cc.AnnTargetFormat]
+	compression, hasCompression := dv.Annotations[cc.AnnTargetCompression]
+	formatField := k8sfield.NewPath("metadata").Child("annotations").Key(cc.AnnTargetFormat).String()
+	compressionField := k8sfield.NewPath("metadata").Child("annotations").Key(cc.AnnTargetCompression).String()
+	if hasFormat && format != common.ImportTargetFormatRaw && format != common.ImportTargetFormatQcow2 {
 		causes = append(causes, metav1.StatusCause{
 			Type:    metav1.CauseTypeFieldValueInvalid,
-			Message: fmt.Sprintf("Name cannot be longer than %d characters", maxLen),
-			Field:   "",
+			Message: fmt.Sprintf("Invalid target format %q, should be %q or %q", format, common.ImportTargetFormatRaw, common.ImportTargetFormatQcow2),
+			Field:   formatField,
+		})
+		return causes, nil
+	}
+	if hasCompression {
+		if compression != common.ImportTargetCompressionZlib && compression != common.ImportTargetCompressionZstd {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("Invalid target compression %q, should be %q or %q", compression, common.ImportTargetCompressionZlib, common.ImportTargetCompressionZstd),
+				Field:   compressionField,
+			})
+			return causes, nil
+		}
+		if format != common.ImportTargetFormatQcow2 {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueNotSupported,
+				Message: fmt.Sprintf("Compression is only supported for %s targets, set %s to %q", common.ImportTargetFormatQcow2, cc.AnnTargetFormat, common.ImportTargetFormatQcow2),
+				Field:   compressionField,
+			})
+			return causes, nil
+		}
+	}
+	if format != common.ImportTargetFormatQcow2 {
+		return causes, nil
+	}
+	source := dv.Spec.Source
+	if source == nil || (source.HTTP == nil && source.S3 == nil && source.GCS == nil && source.Registry == nil && source.Imageio == nil && source.VDDK == nil) {
+		cloneSource, err := newCloneSourceHandler(dv, wh.cdiClient)
+		if err != nil {
+			return nil, err
+		}
+		if cloneSource.cloneType != pvcClone {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueNotSupported,
+				Message: "Only imported and cloned images can be written as qcow2",
+				Field:   formatField,
+			})
+			return causes, nil
+		}
+	}
+	if dv.Spec.ContentType == cdiv1.DataVolumeArchive {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: "An archive content type DataVolume can't be written as qcow2",
+			Field:   formatField,
+		})
+	}
+	if dv.Spec.Preallocation != nil && *dv.Spec.Preallocation {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: "Preallocation only applies to raw targets",
+			Field:   formatField,
+		})
+	}
+	if dv.Annotations[cc.AnnShrinkToUsedSize] == "true" {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: "Only raw targets can be shrunk to their used size",
+			Field:   formatField,
+		})
+	}
+	return causes, nil
+}
+
+// validateSourceDigestAlgorithm validates the algorithm of the digest of the source bytes the importer reports. Only
+// the importers reading the source bytes themselves compute it.
+func validateSourceDigestAlgorithm(dv *cdiv1.DataVolume) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	algorithm, ok := dv.Annotations[cc.AnnSourceDigestAlgorithm]
+	if !ok {
+		return causes
+	}
+	field := k8sfield.NewPath("metadata").Child("annotations").Key(cc.AnnSourceDigestAlgorithm).String()
+	if algorithm != common.SourceDigestSHA256 && algorithm != common.SourceDigestSHA512 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Invalid source digest algorithm %q, should be %q or %q", algorithm, common.SourceDigestSHA256, common.SourceDigestSHA512),
+			Field:   field,
+		})
+		return causes
+	}
+	source := dv.Spec.Source
+	if source == nil || (source.HTTP == nil && source.S3 == nil && source.GCS == nil && source.Remote == nil && source.FTP == nil) {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: "The source digest is only computed for the HTTP, S3, GCS, remote and FTP sources",
+			Field:   field,
+		})
+	}
+	return causes
+}
+
+// validateEncryption validates a DataVolume writing the imported image into a LUKS container on its PVC. The container
+// spans the whole block device, and the importer reads the passphrase from the referenced Secret.
+func validateEncryption(dv *cdiv1.DataVolume) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	secretName, ok := dv.Annotations[cc.AnnEncryptionSecret]
+	if !ok {
+		return causes
+	}
+	field := k8sfield.NewPath("metadata").Child("annotations").Key(cc.AnnEncryptionSecret).String()
+	if secretName == "" {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueRequired,
+			Message: fmt.Sprintf("The Secret holding the %s key of the LUKS passphrase is required", common.EncryptionPassphraseKey),
+			Field:   field,
+		})
+		return causes
+	}
+	if dvSource := findDataVolumeSource(dv.Spec.Source); dvSource == nil || !dvSource.encryptable {
+		message := "Only imported images can be encrypted"
+		if dvSource != nil {
+			message = fmt.Sprintf("An image imported from the %s source can't be encrypted", dvSource.name)
+		}
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: message,
+			Field:   field,
+		})
+		return causes
+	}
+	if dv.Spec.ContentType == cdiv1.DataVolumeArchive {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: "An archive content type DataVolume can't be encrypted",
+			Field:   field,
+		})
+	}
+	// the raw images written to the target by the other sources are staged in scratch space and converted instead
+	if dv.Spec.Source.VDDK != nil || len(dv.Spec.Checkpoints) > 0 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: "A VDDK source or a multi-stage import writes to the target directly, it can't be encrypted",
+			Field:   field,
+		})
+	}
+	var volumeMode *v1.PersistentVolumeMode
+	if dv.Spec.PVC != nil {
+		volumeMode = dv.Spec.PVC.VolumeMode
+	} else if dv.Spec.Storage != nil {
+		volumeMode = dv.Spec.Storage.VolumeMode
+	}
+	if volumeMode == nil || *volumeMode != v1.PersistentVolumeBlock {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: "Only a Block volume mode DataVolume can be encrypted",
+			Field:   field,
+		})
+	}
+	if dv.Annotations[cc.AnnTargetFormat] == common.ImportTargetFormatQcow2 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: "An encrypted DataVolume can't be written as qcow2",
+			Field:   field,
+		})
+	}
+	return causes
+}
+
+// validateTarMember validates the member of a tar archive source selected for import
+func validateTarMember(dv *cdiv1.DataVolume) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	member, ok := dv.Annotations[cc.AnnTarMember]
+	if !ok {
+		return causes
+	}
+	field := k8sfield.NewPath("metadata").Child("annotations").Key(cc.AnnTarMember).String()
+	if dv.Spec.ContentType == cdiv1.DataVolumeArchive {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: "An archive content type DataVolume unpacks the whole archive, a tar member can't be selected",
+			Field:   field,
+		})
+		return causes
+	}
+	if member == "" || path.IsAbs(member) || strings.HasPrefix(path.Clean(member), "..") {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Invalid tar member %q, should be a relative path in the archive", member),
+			Field:   field,
+		})
+	}
+	return causes
+}
+
+// validateFreeSpaceMargin validates the space an import keeps free on the target
+func validateFreeSpaceMargin(dv *cdiv1.DataVolume) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	margin, ok := dv.Annotations[cc.AnnFreeSpaceMargin]
+	if !ok {
+		return causes
+	}
+	if _, err := util.ParseFreeSpaceMargin(margin); err != nil || margin == "" {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Invalid free-space margin %q, should be a positive size like 512Mi or a percentage below 100 like 5%%", margin),
+			Field:   k8sfield.NewPath("metadata").Child("annotations").Key(cc.AnnFreeSpaceMargin).String(),
+		})
+	}
+	return causes
+}
+
+// validateMaxCopyBufferSize validates the bound of the copy buffer of an import
+func validateMaxCopyBufferSize(dv *cdiv1.DataVolume) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	size, ok := dv.Annotations[cc.AnnMaxCopyBufferSize]
+	if !ok {
+		return causes
+	}
+	if _, err := util.ParseMaxCopyBufferSize(size); err != nil || size == "" {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Invalid copy buffer size %q, should be a size between 32Ki and 64Mi like 8Mi", size),
+			Field:   k8sfield.NewPath("metadata").Child("annotations").Key(cc.AnnMaxCopyBufferSize).String(),
+		})
+	}
+	return causes
+}
+
+// validateCloneQcow2Layout validates the cluster size and compaction of a PVC clone written as qcow2, the PVC being the
+// source of the DataVolume or the one its sourceRef resolves to
+func (wh *dataVolumeValidatingWebhook) validateCloneQcow2Layout(dv *cdiv1.DataVolume) ([]metav1.StatusCause, error) {
+	var causes []metav1.StatusCause
+	clusterSize, hasClusterSize := dv.Annotations[cc.AnnCloneQcow2ClusterSize]
+	compact, hasCompact := dv.Annotations[cc.AnnCloneCompact]
+	if !hasClusterSize && !hasCompact {
+		return causes, nil
+	}
+	cloneSource, err := newCloneSourceHandler(dv, wh.cdiClient)
+	if err != nil {
+		return nil, err
+	}
+	if cloneSource.cloneType != pvcClone || dv.Annotations[cc.AnnTargetFormat] != common.ImportTargetFormatQcow2 {
+		annotation := cc.AnnCloneQcow2ClusterSize
+		if !hasClusterSize {
+			annotation = cc.AnnCloneCompact
+		}
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: fmt.Sprintf("Only PVC clones written as qcow2 support %s, set %s to %q", annotation, cc.AnnTargetFormat, common.ImportTargetFormatQcow2),
+			Field:   k8sfield.NewPath("metadata").Child("annotations").Key(annotation).String(),
+		})
+		return causes, nil
+	}
+	if hasClusterSize {
+		if _, err := util.ParseQcow2ClusterSize(clusterSize); err != nil {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("Invalid qcow2 cluster size %q, should be a power of two from 512 to 2Mi", clusterSize),
+				Field:   k8sfield.NewPath("metadata").Child("annotations").Key(cc.AnnCloneQcow2ClusterSize).String(),
+			})
+		}
+	}
+	if hasCompact && compact != "true" && compact != "false" {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Invalid value %q, should be \"true\" or \"false\"", compact),
+			Field:   k8sfield.NewPath("metadata").Child("annotations").Key(cc.AnnCloneCompact).String(),
+		})
+	}
+	return causes, nil
+}
+
+// validateCloneBandwidthLimit validates the network bandwidth limit of a host-assisted clone
+func validateCloneBandwidthLimit(dv *cdiv1.DataVolume) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	limit, ok := dv.Annotations[cc.AnnCloneBandwidthLimit]
+	if !ok {
+		return causes
+	}
+	if _, err := util.ParseBandwidthLimit(limit); err != nil || limit == "" {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Invalid bandwidth limit %q, should be 0 for no limit or a number of bytes per second of at least 64Ki like 100Mi", limit),
+			Field:   k8sfield.NewPath("metadata").Child("annotations").Key(cc.AnnCloneBandwidthLimit).String(),
+		})
+	}
+	return causes
+}
+
+// validateCloneCompression validates the compression codec of the stream of a host-assisted clone
+func validateCloneCompression(dv *cdiv1.DataVolume) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	compression, ok := dv.Annotations[cc.AnnCloneCompression]
+	if !ok {
+		return causes
+	}
+	if err := util.ValidateCloneCompression(compression); err != nil {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Invalid clone compression %q, should be none, gzip, snappy or zstd", compression),
+			Field:   k8sfield.NewPath("metadata").Child("annotations").Key(cc.AnnCloneCompression).String(),
+		})
+	}
+	return causes
+}
+
+// validateWorkerPodPlacement validates the node selector and tolerations of the worker pods of the DataVolume, and
+// checks they are allowed by the CDIConfig
+func (wh *dataVolumeValidatingWebhook) validateWorkerPodPlacement(dv *cdiv1.DataVolume) ([]metav1.StatusCause, error) {
+	var causes []metav1.StatusCause
+	if dv.Spec.WorkerPodPlacement == nil {
+		return causes, nil
+	}
+	field := k8sfield.NewPath("spec", "workerPodPlacement").String()
+	if err := cc.ValidateWorkerPodPlacement(dv.Spec.WorkerPodPlacement); err != nil {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: err.Error(),
+			Field:   field,
+		})
+		return causes, nil
+	}
+	config, err := wh.cdiClient.CdiV1beta1().CDIConfigs().Get(context.TODO(), common.ConfigName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if err := cc.CheckWorkerPodPlacementAllowed(dv.Spec.WorkerPodPlacement, config.Spec.AllowedWorkerPodPlacement); err != nil {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: err.Error(),
+			Field:   field,
+		})
+	}
+	return causes, nil
+}
+
+// validateWorkerPodImage validates the image registry and pull policy of the worker pods of the DataVolume, and checks
+// the registry is allowed by the CDIConfig
+func (wh *dataVolumeValidatingWebhook) validateWorkerPodImage(dv *cdiv1.DataVolume) ([]metav1.StatusCause, error) {
+	var causes []metav1.StatusCause
+	if dv.Spec.WorkerPodImage == nil {
+		return causes, nil
+	}
+	field := k8sfield.NewPath("spec", "workerPodImage").String()
+	if err := cc.ValidateWorkerPodImage(dv.Spec.WorkerPodImage); err != nil {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: err.Error(),
+			Field:   field,
+		})
+		return causes, nil
+	}
+	config, err := wh.cdiClient.CdiV1beta1().CDIConfigs().Get(context.TODO(), common.ConfigName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if err := cc.CheckWorkerPodImageAllowed(dv.Spec.WorkerPodImage, config.Spec.AllowedWorkerPodRegistries); err != nil {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: err.Error(),
+			Field:   field,
+		})
+	}
+	return causes, nil
+}
+
+// validateImportTLS validates the minimal TLS version overriding the CDIConfig one for the import source
+func validateImportTLS(annotations map[string]string) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	minVersion, ok := annotations[cc.AnnImportTLSMinVersion]
+	if !ok {
+		return causes
+	}
+	if _, err := ocpcrypto.TLSVersion(minVersion); err != nil || minVersion == "" {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Invalid minimal TLS version %q, supported versions are %s", minVersion, strings.Join(ocpcrypto.ValidTLSVersions(), ", ")),
+			Field:   k8sfield.NewPath("metadata").Child("annotations").Key(cc.AnnImportTLSMinVersion).String(),
+		})
+	}
+	return causes
+}
+
+// validateTopology validates the topology the DataVolume is pinned to can be satisfied by its storage class. The
+// requested topology has to intersect the allowed topologies of the storage class, and an Immediate binding storage
+// class, which provisions the volume before the importer pod is scheduled, must only allow the requested topology.
+func (wh *dataVolumeValidatingWebhook) validateTopology(dv *cdiv1.DataVolume) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	field := k8sfield.NewPath("metadata").Child("annotations").Key(cc.AnnTopology).String()
+	requirements, err := cc.GetTopologyRequirements(dv.Annotations)
+	if err != nil {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: err.Error(),
+			Field:   field,
+		})
+		return causes
+	}
+	if len(requirements) == 0 {
+		return causes
+	}
+
+	var storageClassName *string
+	if dv.Spec.PVC != nil {
+		storageClassName = dv.Spec.PVC.StorageClassName
+	} else if dv.Spec.Storage != nil {
+		storageClassName = dv.Spec.Storage.StorageClassName
+	}
+	storageClass, err := wh.getStorageClass(storageClassName)
+	if err != nil {
+		causes = append(causes, metav1.StatusCause{
+			Message: err.Error(),
+			Field:   field,
+		})
+		return causes
+	}
+	if storageClass == nil {
+		return causes
+	}
+
+	if !topologyIntersects(requirements, storageClass.AllowedTopologies) {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Topology %q is outside the allowed topologies of storage class %s", dv.Annotations[cc.AnnTopology], storageClass.Name),
+			Field:   field,
+		})
+		return causes
+	}
+	immediate := storageClass.VolumeBindingMode == nil || *storageClass.VolumeBindingMode == storagev1.VolumeBindingImmediate
+	if immediate && !topologyContains(requirements, storageClass.AllowedTopologies) {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Storage class %s binds volumes immediately and may provision outside topology %q, a WaitForFirstConsumer storage class is required", storageClass.Name, dv.Annotations[cc.AnnTopology]),
+			Field:   field,
 		})
 	}
 	return causes
 }
 
+// topologyIntersects returns true if some allowed topology term may match the requirements, or there is no allowed
+// topology. Only the keys of the term are checked, the nodes may have other topology labels.
+func topologyIntersects(requirements []v1.NodeSelectorRequirement, allowedTopologies []v1.TopologySelectorTerm) bool {
+	if len(allowedTopologies) == 0 {
+		return true
+	}
+	for _, term := range allowedTopologies {
+		intersects := true
+		for _, requirement := range requirements {
+			for _, expression := range term.MatchLabelExpressions {
+				if expression.Key == requirement.Key && !topologyValuesIntersect(requirement, expression.Values) {
+					intersects = false
+				}
+			}
+		}
+		if intersects {
+			return true
+		}
+	}
+	return false
+}
+
+func topologyValuesIntersect(requirement v1.NodeSelectorRequirement, values []string) bool {
+	for _, value := range values {
+		if topologyValueMatches(requirement, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// topologyContains returns true if every allowed topology term only matches nodes satisfying the requirements
+func topologyContains(requirements []v1.NodeSelectorRequirement, allowedTopologies []v1.TopologySelectorTerm) bool {
+	if len(allowedTopologies) == 0 {
+		return false
+	}
+	for _, term := range allowedTopologies {
+		for _, requirement := range requirements {
+			contained := false
+			for _, expression := range term.MatchLabelExpressions {
+				if expression.Key == requirement.Key && topologyValuesContained(requirement, expression.Values) {
+					contained = true
+				}
+			}
+			if !contained {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func topologyValuesContained(requirement v1.NodeSelectorRequirement, values []string) bool {
+	for _, value := range values {
+		if !topologyValueMatches(requirement, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// topologyValueMatches returns true if a node with the requirement key label set to the value satisfies it
+func topologyValueMatches(requirement v1.NodeSelectorRequirement, value string) bool {
+	switch requirement.Operator {
+	case v1.NodeSelectorOpIn:
+		return sets.NewString(requirement.Values...).Has(value)
+	case v1.NodeSelectorOpNotIn:
+		return !sets.NewString(requirement.Values...).Has(value)
+	case v1.NodeSelectorOpExists:
+		return true
+	}
+	return false
+}
+
 func (wh *dataVolumeValidatingWebhook) validateDataVolumeSpec(request *admissionv1.AdmissionRequest, field *k8sfield.Path, spec *cdiv1.DataVolumeSpec, namespace *string) []metav1.StatusCause {
 	var causes []metav1.StatusCause
 	var sourceType string
-	var url string
 	var dataSourceRef *v1.TypedObjectReference
 	var dataSource *v1.TypedLocalObjectReference
 
@@ -151,6 +1413,13 @@ func (wh *dataVolumeValidatingWebhook) validateDataVolumeSpec(request *admission
 		}
 	}
 
+	if request.Operation == admissionv1.Create {
+		if cause := wh.validateStorageProfileModes(spec, field); cause != nil {
+			causes = append(causes, *cause)
+			return causes
+		}
+	}
+
 	// The PVC is externally populated when using dataSource and/or dataSourceRef
 	if externalPopulation := dataSourceRef != nil || dataSource != nil; externalPopulation {
 		causes = append(causes, validateExternalPopulation(spec, field, dataSource, dataSourceRef)...)
@@ -195,32 +1464,11 @@ func (wh *dataVolumeValidatingWebhook) validateDataVolumeSpec(request *admission
 			Field:   field.Child("source").String(),
 		})
 		return causes
-	}
-	// if source types are HTTP, Imageio, S3, GCS or VDDK, check if URL is valid
-	if spec.Source.HTTP != nil || spec.Source.S3 != nil || spec.Source.GCS != nil || spec.Source.Imageio != nil || spec.Source.VDDK != nil {
-		if spec.Source.HTTP != nil {
-			url = spec.Source.HTTP.URL
-			sourceType = field.Child("source", "HTTP", "url").String()
-		} else if spec.Source.S3 != nil {
-			url = spec.Source.S3.URL
-			sourceType = field.Child("source", "S3", "url").String()
-		} else if spec.Source.GCS != nil {
-			url = spec.Source.GCS.URL
-			sourceType = field.Child("source", "GCS", "url").String()
-		} else if spec.Source.Imageio != nil {
-			url = spec.Source.Imageio.URL
-			sourceType = field.Child("source", "Imageio", "url").String()
-		} else if spec.Source.VDDK != nil {
-			url = spec.Source.VDDK.URL
-			sourceType = field.Child("source", "VDDK", "url").String()
-		}
-		err := validateSourceURL(url)
-		if err != "" {
-			causes = append(causes, metav1.StatusCause{
-				Type:    metav1.CauseTypeFieldValueInvalid,
-				Message: fmt.Sprintf("%s %s", field.Child("source").String(), err),
-				Field:   sourceType,
-			})
+	}
+	dvSource := findDataVolumeSource(spec.Source)
+	if dvSource != nil && dvSource.validate != nil {
+		if cause := dvSource.validate(spec.Source, field.Child("source")); cause != nil {
+			causes = append(causes, *cause)
 			return causes
 		}
 	}
@@ -246,17 +1494,16 @@ func (wh *dataVolumeValidatingWebhook) validateDataVolumeSpec(request *admission
 		return causes
 	}
 
-	if spec.Source.Registry != nil {
-		if spec.ContentType != "" && string(spec.ContentType) != string(cdiv1.DataVolumeKubeVirt) {
-			sourceType = field.Child("contentType").String()
-			causes = append(causes, metav1.StatusCause{
-				Type:    metav1.CauseTypeFieldValueInvalid,
-				Message: fmt.Sprintf("ContentType must be %s when Source is Registry", cdiv1.DataVolumeKubeVirt),
-				Field:   sourceType,
-			})
-			return causes
-		}
+	if dvSource != nil && dvSource.kubevirtOnly && spec.ContentType == cdiv1.DataVolumeArchive {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("ContentType must be %s when Source is %s", cdiv1.DataVolumeKubeVirt, dvSource.name),
+			Field:   field.Child("contentType").String(),
+		})
+		return causes
+	}
 
+	if spec.Source.Registry != nil {
 		causes := validateDataVolumeSourceRegistry(spec.Source.Registry, field)
 		if len(causes) > 0 {
 			return causes
@@ -340,7 +1587,7 @@ func validateDataVolumeSourceRegistry(sourceRegistry *cdiv1.DataVolumeSourceRegi
 		if err != nil {
 			causes = append(causes, metav1.StatusCause{
 				Type:    metav1.CauseTypeFieldValueInvalid,
-				Message: fmt.Sprintf("Illegal registry source URL %s", *sourceURL),
+				Message: fmt.Sprintf("Illegal registry source URL %s", util.RedactSourceURL(*sourceURL)),
 				Field:   field.Child("source", "Registry", "URL").String(),
 			})
 			return causes
@@ -349,7 +1596,7 @@ func validateDataVolumeSourceRegistry(sourceRegistry *cdiv1.DataVolumeSourceRegi
 		if scheme != cdiv1.RegistrySchemeDocker && scheme != cdiv1.RegistrySchemeOci {
 			causes = append(causes, metav1.StatusCause{
 				Type:    metav1.CauseTypeFieldValueInvalid,
-				Message: fmt.Sprintf("Illegal registry source URL scheme %s", url),
+				Message: fmt.Sprintf("Illegal registry source URL scheme %s", util.RedactSourceURL(url.String())),
 				Field:   field.Child("source", "Registry", "URL").String(),
 			})
 			return causes
@@ -383,6 +1630,77 @@ func validateDataVolumeSourceRegistry(sourceRegistry *cdiv1.DataVolumeSourceRegi
 		return causes
 	}
 
+	return validateRegistryDisks(sourceRegistry, field.Child("source", "Registry", "disks"))
+}
+
+// validateRegistryDisks validates the disks of a multi-disk registry image. Exactly one disk has no PVC name, it is
+// imported into the PVC of the DataVolume, the others into distinct PVCs.
+func validateRegistryDisks(sourceRegistry *cdiv1.DataVolumeSourceRegistry, field *k8sfield.Path) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	if len(sourceRegistry.Disks) == 0 {
+		return causes
+	}
+	if sourceRegistry.PullMethod != nil && *sourceRegistry.PullMethod == cdiv1.RegistryPullNode {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: "A multi-disk registry image is not supported with node pull import method",
+			Field:   field.String(),
+		})
+		return causes
+	}
+
+	dataVolumeDisks := 0
+	pvcNames := map[string]bool{}
+	for i, disk := range sourceRegistry.Disks {
+		diskPath := filepath.Clean(disk.Path)
+		if disk.Path == "" || filepath.IsAbs(diskPath) || diskPath == "." || diskPath == ".." || strings.HasPrefix(diskPath, "../") {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("Invalid disk path %q, should be relative to the disk directory of the image", disk.Path),
+				Field:   field.Index(i).Child("path").String(),
+			})
+		}
+		if disk.Size != nil && disk.Size.Sign() <= 0 {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("Invalid disk size %s, should be positive", disk.Size.String()),
+				Field:   field.Index(i).Child("size").String(),
+			})
+		}
+		if disk.PVCName == "" {
+			if disk.Size != nil {
+				causes = append(causes, metav1.StatusCause{
+					Type:    metav1.CauseTypeFieldValueInvalid,
+					Message: "The disk imported into the PVC of the DataVolume gets the size of the DataVolume",
+					Field:   field.Index(i).Child("size").String(),
+				})
+			}
+			dataVolumeDisks++
+			continue
+		}
+		for _, msg := range kvalidation.IsDNS1123Subdomain(disk.PVCName) {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("Invalid PVC name %q: %s", disk.PVCName, msg),
+				Field:   field.Index(i).Child("pvcName").String(),
+			})
+		}
+		if pvcNames[disk.PVCName] {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueDuplicate,
+				Message: fmt.Sprintf("Disks can't be imported into the same PVC %q", disk.PVCName),
+				Field:   field.Index(i).Child("pvcName").String(),
+			})
+		}
+		pvcNames[disk.PVCName] = true
+	}
+	if dataVolumeDisks != 1 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: "Exactly one disk should have no PVC name, to be imported into the PVC of the DataVolume",
+			Field:   field.String(),
+		})
+	}
 	return causes
 }
 
@@ -456,7 +1774,14 @@ func (wh *dataVolumeValidatingWebhook) validateDataVolumeSourcePVC(PVC *cdiv1.Da
 		}
 	}
 
-	if err := cc.ValidateClone(sourcePVC, spec); err != nil {
+	sourceFsOverhead, err := wh.getFilesystemOverhead(sourcePVC.Spec.StorageClassName)
+	if err != nil {
+		return &metav1.StatusCause{
+			Message: err.Error(),
+			Field:   field.String(),
+		}
+	}
+	if err := cc.ValidateClone(sourcePVC, spec, sourceFsOverhead); err != nil {
 		return &metav1.StatusCause{
 			Type:    metav1.CauseTypeFieldValueInvalid,
 			Message: err.Error(),
@@ -467,6 +1792,148 @@ func (wh *dataVolumeValidatingWebhook) validateDataVolumeSourcePVC(PVC *cdiv1.Da
 	return nil
 }
 
+// getFilesystemOverhead returns the filesystem overhead of a storage class, from its StorageProfile or the CDIConfig
+// status. No overhead is returned when the CDIConfig is not reconciled yet.
+func (wh *dataVolumeValidatingWebhook) getFilesystemOverhead(storageClassName *string) (cdiv1.Percent, error) {
+	config, err := wh.cdiClient.CdiV1beta1().CDIConfigs().Get(context.TODO(), common.ConfigName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return "0", nil
+		}
+		return "0", err
+	}
+	if config.Status.FilesystemOverhead == nil {
+		return "0", nil
+	}
+	if storageClassName == nil {
+		return config.Status.FilesystemOverhead.Global, nil
+	}
+
+	// An overhead explicitly set in CDIConfig for the storage class takes precedence over the StorageProfile one
+	overhead, found := config.Status.FilesystemOverhead.StorageClass[*storageClassName]
+	if config.Spec.FilesystemOverhead != nil && found {
+		if _, explicit := config.Spec.FilesystemOverhead.StorageClass[*storageClassName]; explicit {
+			return overhead, nil
+		}
+	}
+	storageProfile, err := wh.cdiClient.CdiV1beta1().StorageProfiles().Get(context.TODO(), *storageClassName, metav1.GetOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return "0", err
+	}
+	if err == nil && storageProfile.Status.FilesystemOverhead != nil {
+		return *storageProfile.Status.FilesystemOverhead, nil
+	}
+	if found {
+		return overhead, nil
+	}
+	return config.Status.FilesystemOverhead.Global, nil
+}
+
+// validateStorageProfileModes rejects a volume and access modes combination which is not supported by the StorageProfile
+// of the target storage class. Nothing is validated when the storage class or its StorageProfile is not known yet.
+func (wh *dataVolumeValidatingWebhook) validateStorageProfileModes(spec *cdiv1.DataVolumeSpec, field *k8sfield.Path) *metav1.StatusCause {
+	var storageClassName *string
+	var volumeMode *v1.PersistentVolumeMode
+	var accessModes []v1.PersistentVolumeAccessMode
+	if spec.PVC != nil {
+		storageClassName = spec.PVC.StorageClassName
+		accessModes = spec.PVC.AccessModes
+		// A PVC without volume mode gets the Filesystem one
+		volumeMode = spec.PVC.VolumeMode
+		if volumeMode == nil {
+			filesystem := v1.PersistentVolumeFilesystem
+			volumeMode = &filesystem
+		}
+		field = field.Child("PVC")
+	} else if spec.Storage != nil {
+		// The StorageProfile fills in the modes missing from a storage spec
+		storageClassName = spec.Storage.StorageClassName
+		accessModes = spec.Storage.AccessModes
+		volumeMode = spec.Storage.VolumeMode
+		field = field.Child("storage")
+	}
+	if volumeMode == nil && len(accessModes) == 0 {
+		return nil
+	}
+
+	storageClass, err := wh.getStorageClass(storageClassName)
+	if err != nil {
+		return &metav1.StatusCause{
+			Message: err.Error(),
+			Field:   field.Child("storageClassName").String(),
+		}
+	}
+	if storageClass == nil {
+		return nil
+	}
+	storageProfile, err := wh.cdiClient.CdiV1beta1().StorageProfiles().Get(context.TODO(), storageClass.Name, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return &metav1.StatusCause{
+			Message: err.Error(),
+			Field:   field.Child("storageClassName").String(),
+		}
+	}
+	// A StorageProfile without claim property sets does not know the modes of the storage class yet
+	claimPropertySets := storageProfile.Status.ClaimPropertySets
+	if len(claimPropertySets) == 0 {
+		return nil
+	}
+	for _, claimPropertySet := range claimPropertySets {
+		if cc.ClaimPropertySetSupports(claimPropertySet, volumeMode, accessModes) {
+			return nil
+		}
+	}
+
+	modes := fmt.Sprintf("access modes %v", accessModes)
+	if volumeMode != nil {
+		modes = fmt.Sprintf("volume mode %s and %s", *volumeMode, modes)
+	}
+	var supported []string
+	for _, claimPropertySet := range claimPropertySets {
+		supported = append(supported, fmt.Sprintf("%s %v", claimPropertySetVolumeMode(claimPropertySet), claimPropertySet.AccessModes))
+	}
+	return &metav1.StatusCause{
+		Type:    metav1.CauseTypeFieldValueNotSupported,
+		Message: fmt.Sprintf("StorageProfile %s does not support %s, supported combinations: %s", storageProfile.Name, modes, strings.Join(supported, ", ")),
+		Field:   field.Child("accessModes").String(),
+	}
+}
+
+// getStorageClass returns the storage class of the passed in name, or the default storage class if the name is nil.
+// Returns nil if there is no such storage class.
+func (wh *dataVolumeValidatingWebhook) getStorageClass(storageClassName *string) (*storagev1.StorageClass, error) {
+	if storageClassName != nil {
+		if *storageClassName == "" {
+			return nil, nil
+		}
+		storageClass, err := wh.k8sClient.StorageV1().StorageClasses().Get(context.TODO(), *storageClassName, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return storageClass, err
+	}
+	storageClasses, err := wh.k8sClient.StorageV1().StorageClasses().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range storageClasses.Items {
+		if storageClasses.Items[i].Annotations[cc.AnnDefaultStorageClass] == "true" {
+			return &storageClasses.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func claimPropertySetVolumeMode(claimPropertySet cdiv1.ClaimPropertySet) v1.PersistentVolumeMode {
+	if claimPropertySet.VolumeMode == nil {
+		return v1.PersistentVolumeFilesystem
+	}
+	return *claimPropertySet.VolumeMode
+}
+
 // validateDataSource validates a DataSource in a DataVolume spec
 func validateDataSource(dataSource *v1.TypedLocalObjectReference, field *k8sfield.Path) []metav1.StatusCause {
 	var causes []metav1.StatusCause
@@ -632,6 +2099,83 @@ func validateExternalPopulation(spec *cdiv1.DataVolumeSpec, field *k8sfield.Path
 	return causes
 }
 
+// requestedStorage returns the storage requests of the DataVolume spec, nil when it has neither a PVC nor a Storage spec
+func requestedStorage(spec *cdiv1.DataVolumeSpec) v1.ResourceList {
+	if spec.PVC != nil {
+		return spec.PVC.Resources.Requests
+	} else if spec.Storage != nil {
+		return spec.Storage.Resources.Requests
+	}
+	return nil
+}
+
+// isRequestedSizeUpdate tells whether the spec update only changes the requested storage size
+func isRequestedSizeUpdate(spec, oldSpec *cdiv1.DataVolumeSpec) bool {
+	size, ok := requestedStorage(spec)[v1.ResourceStorage]
+	if !ok {
+		return false
+	}
+	oldSize, ok := requestedStorage(oldSpec)[v1.ResourceStorage]
+	if !ok || size.Cmp(oldSize) == 0 {
+		return false
+	}
+	specWithOldSize := spec.DeepCopy()
+	requestedStorage(specWithOldSize)[v1.ResourceStorage] = oldSize
+	return apiequality.Semantic.DeepEqual(*specWithOldSize, *oldSpec)
+}
+
+// validateRequestedSizeUpdate checks the requested size of the DataVolume can be changed: any size is allowed before
+// its PVC exists, then the bound PVC can only grow, in a storage class allowing volume expansion
+func (wh *dataVolumeValidatingWebhook) validateRequestedSizeUpdate(dv, oldDV *cdiv1.DataVolume) ([]metav1.StatusCause, error) {
+	size := requestedStorage(&dv.Spec)[v1.ResourceStorage]
+	oldSize := requestedStorage(&oldDV.Spec)[v1.ResourceStorage]
+	field := k8sfield.NewPath("spec", "pvc", "resources", "requests", "storage")
+	if dv.Spec.Storage != nil {
+		field = k8sfield.NewPath("spec", "storage", "resources", "requests", "storage")
+	}
+	rejected := func(message string, args ...interface{}) ([]metav1.StatusCause, error) {
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf(message, args...),
+			Field:   field.String(),
+		}}, nil
+	}
+
+	pvc, err := wh.k8sClient.CoreV1().PersistentVolumeClaims(dv.Namespace).Get(context.TODO(), dv.Name, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			// The PVC will be created with the new size
+			return nil, nil
+		}
+		return nil, err
+	}
+	if pvc.Status.Phase != v1.ClaimBound {
+		return rejected("Cannot change the requested size of DataVolume %s while its PVC %s is not bound", dv.Name, pvc.Name)
+	}
+	capacity, ok := pvc.Status.Capacity[v1.ResourceStorage]
+	if !ok {
+		capacity = pvc.Spec.Resources.Requests[v1.ResourceStorage]
+	}
+	// The PVC of a Storage spec is bigger than the requested size by the filesystem overhead, so only the DataVolume
+	// size is compared for it
+	if size.Cmp(oldSize) < 0 || (dv.Spec.PVC != nil && size.Cmp(capacity) < 0) {
+		return rejected("Cannot shrink DataVolume %s from %s to %s, its PVC %s is bound with capacity %s and a PVC cannot shrink",
+			dv.Name, oldSize.String(), size.String(), pvc.Name, capacity.String())
+	}
+	storageClass, err := wh.getStorageClass(pvc.Spec.StorageClassName)
+	if err != nil {
+		return nil, err
+	}
+	if storageClass == nil || storageClass.AllowVolumeExpansion == nil || !*storageClass.AllowVolumeExpansion {
+		storageClassName := ""
+		if pvc.Spec.StorageClassName != nil {
+			storageClassName = *pvc.Spec.StorageClassName
+		}
+		return rejected("Cannot grow DataVolume %s to %s, the storage class %q of its PVC %s does not allow volume expansion", dv.Name, size.String(), storageClassName, pvc.Name)
+	}
+	return nil, nil
+}
+
 func (wh *dataVolumeValidatingWebhook) Admit(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
 	klog.V(3).Infof("Got AdmissionReview %+v", ar)
 
@@ -647,9 +2191,10 @@ func (wh *dataVolumeValidatingWebhook) Admit(ar admissionv1.AdmissionReview) *ad
 		return toAdmissionResponseError(err)
 	}
 
+	var oldDV *cdiv1.DataVolume
 	if ar.Request.Operation == admissionv1.Update {
-		oldDV := cdiv1.DataVolume{}
-		err = json.Unmarshal(ar.Request.OldObject.Raw, &oldDV)
+		oldDV = &cdiv1.DataVolume{}
+		err = json.Unmarshal(ar.Request.OldObject.Raw, oldDV)
 		if err != nil {
 			return toAdmissionResponseError(err)
 		}
@@ -670,7 +2215,16 @@ func (wh *dataVolumeValidatingWebhook) Admit(ar admissionv1.AdmissionReview) *ad
 			multiStageAdmitted = apiequality.Semantic.DeepEqual(newSpec, oldSpec)
 		}
 
-		if !multiStageAdmitted && !apiequality.Semantic.DeepEqual(dv.Spec, oldDV.Spec) {
+		if !multiStageAdmitted && isRequestedSizeUpdate(&dv.Spec, &oldDV.Spec) {
+			causes, err := wh.validateRequestedSizeUpdate(&dv, oldDV)
+			if err != nil {
+				return toAdmissionResponseError(err)
+			}
+			if len(causes) > 0 {
+				klog.Infof("rejected DataVolume admission %s", causes)
+				return toRejectedAdmissionResponse(causes)
+			}
+		} else if !multiStageAdmitted && !apiequality.Semantic.DeepEqual(dv.Spec, oldDV.Spec) {
 			klog.Errorf("Cannot update spec for DataVolume %s/%s", dv.GetNamespace(), dv.GetName())
 			var causes []metav1.StatusCause
 			causes = append(causes, metav1.StatusCause{
@@ -688,7 +2242,158 @@ func (wh *dataVolumeValidatingWebhook) Admit(ar admissionv1.AdmissionReview) *ad
 		return toRejectedAdmissionResponse(causes)
 	}
 
+	causes = validateDataVolumeMetadata(&dv, oldDV)
+	if len(causes) > 0 {
+		klog.Infof("rejected DataVolume admission %s", causes)
+		return toRejectedAdmissionResponse(causes)
+	}
+
+	causes = validateCompletionTimeout(&dv, oldDV)
+	if len(causes) > 0 {
+		klog.Infof("rejected DataVolume admission %s", causes)
+		return toRejectedAdmissionResponse(causes)
+	}
+
 	if ar.Request.Operation == admissionv1.Create {
+		causes = validateVerifyPopulated(&dv)
+		if len(causes) > 0 {
+			klog.Infof("rejected DataVolume admission %s", causes)
+			return toRejectedAdmissionResponse(causes)
+		}
+
+		causes = validateVerifyOnly(&dv)
+		if len(causes) > 0 {
+			klog.Infof("rejected DataVolume admission %s", causes)
+			return toRejectedAdmissionResponse(causes)
+		}
+
+		causes = validateShrinkToUsedSize(&dv)
+		if len(causes) > 0 {
+			klog.Infof("rejected DataVolume admission %s", causes)
+			return toRejectedAdmissionResponse(causes)
+		}
+
+		causes, err = wh.validateTargetFormat(&dv)
+		if err != nil {
+			return toAdmissionResponseError(err)
+		}
+		if len(causes) > 0 {
+			klog.Infof("rejected DataVolume admission %s", causes)
+			return toRejectedAdmissionResponse(causes)
+		}
+
+		causes = validateSourceDigestAlgorithm(&dv)
+		if len(causes) > 0 {
+			klog.Infof("rejected DataVolume admission %s", causes)
+			return toRejectedAdmissionResponse(causes)
+		}
+
+		causes = validateEncryption(&dv)
+		if len(causes) > 0 {
+			klog.Infof("rejected DataVolume admission %s", causes)
+			return toRejectedAdmissionResponse(causes)
+		}
+
+		causes = validateTarMember(&dv)
+		if len(causes) > 0 {
+			klog.Infof("rejected DataVolume admission %s", causes)
+			return toRejectedAdmissionResponse(causes)
+		}
+
+		causes = validateFreeSpaceMargin(&dv)
+		if len(causes) > 0 {
+			klog.Infof("rejected DataVolume admission %s", causes)
+			return toRejectedAdmissionResponse(causes)
+		}
+
+		causes = validateMaxCopyBufferSize(&dv)
+		if len(causes) > 0 {
+			klog.Infof("rejected DataVolume admission %s", causes)
+			return toRejectedAdmissionResponse(causes)
+		}
+
+		causes, err = wh.validateCloneQcow2Layout(&dv)
+		if err != nil {
+			return toAdmissionResponseError(err)
+		}
+		if len(causes) > 0 {
+			klog.Infof("rejected DataVolume admission %s", causes)
+			return toRejectedAdmissionResponse(causes)
+		}
+
+		causes = validateCloneBandwidthLimit(&dv)
+		if len(causes) > 0 {
+			klog.Infof("rejected DataVolume admission %s", causes)
+			return toRejectedAdmissionResponse(causes)
+		}
+
+		causes = validateCloneCompression(&dv)
+		if len(causes) > 0 {
+			klog.Infof("rejected DataVolume admission %s", causes)
+			return toRejectedAdmissionResponse(causes)
+		}
+
+		namespace := dv.Namespace
+		if namespace == "" {
+			namespace = ar.Request.Namespace
+		}
+		causes, err = wh.validateRemotePVCSecretAccess(&dv, namespace, ar.Request.UserInfo)
+		if err != nil {
+			return toAdmissionResponseError(err)
+		}
+		if len(causes) > 0 {
+			klog.Infof("rejected DataVolume admission %s", causes)
+			return toRejectedAdmissionResponse(causes)
+		}
+
+		causes, err = wh.validateWorkerPodPlacement(&dv)
+		if err != nil {
+			return toAdmissionResponseError(err)
+		}
+		if len(causes) > 0 {
+			klog.Infof("rejected DataVolume admission %s", causes)
+			return toRejectedAdmissionResponse(causes)
+		}
+
+		causes, err = wh.validateWorkerPodImage(&dv)
+		if err != nil {
+			return toAdmissionResponseError(err)
+		}
+		if len(causes) > 0 {
+			klog.Infof("rejected DataVolume admission %s", causes)
+			return toRejectedAdmissionResponse(causes)
+		}
+
+		causes = validateScratchSpaceBackend(&dv)
+		if len(causes) > 0 {
+			klog.Infof("rejected DataVolume admission %s", causes)
+			return toRejectedAdmissionResponse(causes)
+		}
+
+		causes = validateSnapshotTarget(&dv)
+		if len(causes) > 0 {
+			klog.Infof("rejected DataVolume admission %s", causes)
+			return toRejectedAdmissionResponse(causes)
+		}
+
+		causes = validateRegistryDiskPVCs(&dv)
+		if len(causes) > 0 {
+			klog.Infof("rejected DataVolume admission %s", causes)
+			return toRejectedAdmissionResponse(causes)
+		}
+
+		causes = validateImportTLS(dv.Annotations)
+		if len(causes) > 0 {
+			klog.Infof("rejected DataVolume admission %s", causes)
+			return toRejectedAdmissionResponse(causes)
+		}
+
+		causes = wh.validateTopology(&dv)
+		if len(causes) > 0 {
+			klog.Infof("rejected DataVolume admission %s", causes)
+			return toRejectedAdmissionResponse(causes)
+		}
+
 		pvc, err := wh.k8sClient.CoreV1().PersistentVolumeClaims(dv.GetNamespace()).Get(context.TODO(), dv.GetName(), metav1.GetOptions{})
 		if err != nil {
 			if !k8serrors.IsNotFound(err) {
@@ -696,7 +2401,9 @@ func (wh *dataVolumeValidatingWebhook) Admit(ar admissionv1.AdmissionReview) *ad
 			}
 		} else {
 			dvName, ok := pvc.Annotations[cc.AnnPopulatedFor]
-			if !ok || dvName != dv.GetName() {
+			// A DataVolume adopting the PVC populates it, the controller checks the PVC can be adopted
+			adopting := dv.Annotations[cc.AnnAdoptPVC] == "true"
+			if !adopting && (!ok || dvName != dv.GetName()) {
 				pvcOwner := metav1.GetControllerOf(pvc)
 				// We should reject the DV if a PVC with the same name exists, and that PVC has no ownerRef, or that
 				// PVC has an ownerRef that is not a DataVolume. Because that means that PVC is not managed by the
@@ -30,6 +30,7 @@ type CdiV1beta1Interface interface {
 	RESTClient() rest.Interface
 	CDIsGetter
 	CDIConfigsGetter
+	CloneGrantsGetter
 	DataImportCronsGetter
 	DataSourcesGetter
 	DataVolumesGetter
@@ -50,6 +51,10 @@ func (c *CdiV1beta1Client) CDIConfigs() CDIConfigInterface {
 	return newCDIConfigs(c)
 }
 
+func (c *CdiV1beta1Client) CloneGrants(namespace string) CloneGrantInterface {
+	return newCloneGrants(c, namespace)
+}
+
 func (c *CdiV1beta1Client) DataImportCrons(namespace string) DataImportCronInterface {
 	return newDataImportCrons(c, namespace)
 }
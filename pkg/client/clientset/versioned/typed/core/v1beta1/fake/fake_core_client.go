@@ -36,6 +36,10 @@ func (c *FakeCdiV1beta1) CDIConfigs() v1beta1.CDIConfigInterface {
 	return &FakeCDIConfigs{c}
 }
 
+func (c *FakeCdiV1beta1) CloneGrants(namespace string) v1beta1.CloneGrantInterface {
+	return &FakeCloneGrants{c, namespace}
+}
+
 func (c *FakeCdiV1beta1) DataImportCrons(namespace string) v1beta1.DataImportCronInterface {
 	return &FakeDataImportCrons{c, namespace}
 }
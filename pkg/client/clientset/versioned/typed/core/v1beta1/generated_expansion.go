@@ -22,6 +22,8 @@ type CDIExpansion interface{}
 
 type CDIConfigExpansion interface{}
 
+type CloneGrantExpansion interface{}
+
 type DataImportCronExpansion interface{}
 
 type DataSourceExpansion interface{}
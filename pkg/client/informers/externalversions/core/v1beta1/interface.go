@@ -28,6 +28,8 @@ type Interface interface {
 	CDIs() CDIInformer
 	// CDIConfigs returns a CDIConfigInformer.
 	CDIConfigs() CDIConfigInformer
+	// CloneGrants returns a CloneGrantInformer.
+	CloneGrants() CloneGrantInformer
 	// DataImportCrons returns a DataImportCronInformer.
 	DataImportCrons() DataImportCronInformer
 	// DataSources returns a DataSourceInformer.
@@ -61,6 +63,11 @@ func (v *version) CDIConfigs() CDIConfigInformer {
 	return &cDIConfigInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// CloneGrants returns a CloneGrantInformer.
+func (v *version) CloneGrants() CloneGrantInformer {
+	return &cloneGrantInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // DataImportCrons returns a DataImportCronInformer.
 func (v *version) DataImportCrons() DataImportCronInformer {
 	return &dataImportCronInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -63,6 +63,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Cdi().V1beta1().CDIs().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("cdiconfigs"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Cdi().V1beta1().CDIConfigs().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("clonegrants"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cdi().V1beta1().CloneGrants().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("dataimportcrons"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Cdi().V1beta1().DataImportCrons().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("datasources"):
@@ -26,6 +26,14 @@ type CDIListerExpansion interface{}
 // CDIConfigLister.
 type CDIConfigListerExpansion interface{}
 
+// CloneGrantListerExpansion allows custom methods to be added to
+// CloneGrantLister.
+type CloneGrantListerExpansion interface{}
+
+// CloneGrantNamespaceListerExpansion allows custom methods to be added to
+// CloneGrantNamespaceLister.
+type CloneGrantNamespaceListerExpansion interface{}
+
 // DataImportCronListerExpansion allows custom methods to be added to
 // DataImportCronLister.
 type DataImportCronListerExpansion interface{}
@@ -0,0 +1,210 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clone
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	authentication "k8s.io/api/authentication/v1"
+	authorization "k8s.io/api/authorization/v1"
+	"k8s.io/klog/v2"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+)
+
+// CloneSource is the source of an audited clone
+type CloneSource struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// CloneAuthorizationDecision is the audited decision of a cross-namespace clone authorization
+type CloneAuthorizationDecision struct {
+	Time time.Time `json:"time"`
+	// User is the requestor of the clone, the ServiceAccount user for a ServiceAccount
+	User            string      `json:"user"`
+	Groups          []string    `json:"groups,omitempty"`
+	Source          CloneSource `json:"source"`
+	TargetNamespace string      `json:"targetNamespace"`
+	// TargetName is the name of the DataVolume cloning the source, when the clone is authorized for an admission request
+	TargetName string `json:"targetName,omitempty"`
+	Allowed         bool        `json:"allowed"`
+	Reason          string      `json:"reason,omitempty"`
+	Error           string      `json:"error,omitempty"`
+	// MatchedResourceAttributes are those of the SubjectAccessReviews allowing the clone
+	MatchedResourceAttributes []authorization.ResourceAttributes `json:"matchedResourceAttributes,omitempty"`
+	// Grant is the namespace/name of the CloneGrant allowing the clone
+	Grant string `json:"grant,omitempty"`
+}
+
+// CloneAuthorizationAuditor records the decisions of the cross-namespace clone authorizations
+type CloneAuthorizationAuditor func(decision *CloneAuthorizationDecision)
+
+type registeredCloneAuthorizationAuditor struct {
+	id int
+	f  CloneAuthorizationAuditor
+}
+
+var (
+	cloneAuthorizationAuditorsLock   sync.RWMutex
+	cloneAuthorizationAuditorsNextID int
+	cloneAuthorizationAuditors       []registeredCloneAuthorizationAuditor
+)
+
+// RegisterCloneAuthorizationAuditor registers an auditor of the decisions of the cross-namespace clone authorizations
+// of CanUserClonePVC, CanServiceAccountClonePVC and their snapshot counterparts. The returned func unregisters it.
+func RegisterCloneAuthorizationAuditor(f CloneAuthorizationAuditor) func() {
+	cloneAuthorizationAuditorsLock.Lock()
+	defer cloneAuthorizationAuditorsLock.Unlock()
+	id := cloneAuthorizationAuditorsNextID
+	cloneAuthorizationAuditorsNextID++
+	cloneAuthorizationAuditors = append(cloneAuthorizationAuditors, registeredCloneAuthorizationAuditor{id: id, f: f})
+	return func() {
+		cloneAuthorizationAuditorsLock.Lock()
+		defer cloneAuthorizationAuditorsLock.Unlock()
+		for i, registered := range cloneAuthorizationAuditors {
+			if registered.id == id {
+				cloneAuthorizationAuditors = append(cloneAuthorizationAuditors[:i:i], cloneAuthorizationAuditors[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// NewJSONCloneAuthorizationAuditor returns an auditor writing the decisions to w as JSON lines
+func NewJSONCloneAuthorizationAuditor(w io.Writer) CloneAuthorizationAuditor {
+	var lock sync.Mutex
+	encoder := json.NewEncoder(w)
+	return func(decision *CloneAuthorizationDecision) {
+		lock.Lock()
+		defer lock.Unlock()
+		if err := encoder.Encode(decision); err != nil {
+			klog.Errorf("Unable to write the clone authorization audit log: %v", err)
+		}
+	}
+}
+
+// FormatResourceAttributes formats resource attributes like "create datavolumes.cdi.kubevirt.io/source"
+func FormatResourceAttributes(ra authorization.ResourceAttributes) string {
+	resource := ra.Resource
+	if ra.Group != "" {
+		resource += "." + ra.Group
+	}
+	if ra.Subresource != "" {
+		resource += "/" + ra.Subresource
+	}
+	return fmt.Sprintf("%s %s", ra.Verb, resource)
+}
+
+// cloneRequestProxy is the client of the clone auth funcs authorizing the clone of an admission request
+type cloneRequestProxy struct {
+	SubjectAccessReviewsProxy
+	targetName string
+	dryRun     bool
+}
+
+// WithCloneRequest returns the client of the clone auth funcs authorizing the clone to the target of an admission
+// request. The audited decision names the target, and the decision of a dry run request is not audited.
+func WithCloneRequest(client SubjectAccessReviewsProxy, targetName string, dryRun bool) SubjectAccessReviewsProxy {
+	return &cloneRequestProxy{SubjectAccessReviewsProxy: client, targetName: targetName, dryRun: dryRun}
+}
+
+// auditingSubjectAccessReviewsProxy records the resource attributes of the allowed SubjectAccessReviews, and the
+// CloneGrant allowing the clone
+type auditingSubjectAccessReviewsProxy struct {
+	client   SubjectAccessReviewsProxy
+	decision *CloneAuthorizationDecision
+}
+
+func (p *auditingSubjectAccessReviewsProxy) Create(sar *authorization.SubjectAccessReview) (*authorization.SubjectAccessReview, error) {
+	response, err := p.client.Create(sar)
+	if err == nil && response.Status.Allowed && sar.Spec.ResourceAttributes != nil {
+		p.decision.MatchedResourceAttributes = append(p.decision.MatchedResourceAttributes, *sar.Spec.ResourceAttributes)
+	}
+	return response, err
+}
+
+func (p *auditingSubjectAccessReviewsProxy) recordCloneGrant(grant *cdiv1.CloneGrant) {
+	p.decision.Grant = grant.Namespace + "/" + grant.Name
+}
+
+// authorizeClone decides the clone with the client caching the SubjectAccessReview decisions, and audits the decision
+// of a cross-namespace clone, whether it was served from the cache or not, unless it is for a dry run request
+func authorizeClone(client SubjectAccessReviewsProxy, decision *CloneAuthorizationDecision, authorize func(SubjectAccessReviewsProxy) (bool, string, error)) (bool, string, error) {
+	dryRun := false
+	if request, ok := client.(*cloneRequestProxy); ok {
+		client, decision.TargetName, dryRun = request.SubjectAccessReviewsProxy, request.targetName, request.dryRun
+	}
+	client = withSubjectAccessReviewCache(client)
+	if decision.Source.Namespace == decision.TargetNamespace || dryRun {
+		return authorize(client)
+	}
+
+	allowed, reason, err := authorize(&auditingSubjectAccessReviewsProxy{client: client, decision: decision})
+	decision.Time = time.Now().UTC()
+	decision.Allowed = allowed && err == nil
+	decision.Reason = reason
+	if err != nil {
+		decision.Error = err.Error()
+	}
+	if !decision.Allowed {
+		decision.MatchedResourceAttributes = nil
+		decision.Grant = ""
+	}
+
+	cloneAuthorizationAuditorsLock.RLock()
+	defer cloneAuthorizationAuditorsLock.RUnlock()
+	for _, registered := range cloneAuthorizationAuditors {
+		registered.f(decision)
+	}
+	return allowed, reason, err
+}
+
+// withUserCloneAudit returns the user clone auth func auditing the decisions of f about a source of the kind
+func withUserCloneAudit(sourceKind string, f UserCloneAuthFunc) UserCloneAuthFunc {
+	return func(client SubjectAccessReviewsProxy, sourceNamespace, name, targetNamespace string, userInfo authentication.UserInfo) (bool, string, error) {
+		decision := &CloneAuthorizationDecision{
+			User:            userInfo.Username,
+			Groups:          userInfo.Groups,
+			Source:          CloneSource{Kind: sourceKind, Namespace: sourceNamespace, Name: name},
+			TargetNamespace: targetNamespace,
+		}
+		return authorizeClone(client, decision, func(client SubjectAccessReviewsProxy) (bool, string, error) {
+			return f(client, sourceNamespace, name, targetNamespace, userInfo)
+		})
+	}
+}
+
+// withServiceAccountCloneAudit returns the ServiceAccount clone auth func auditing the decisions of f about a source
+// of the kind
+func withServiceAccountCloneAudit(sourceKind string, f ServiceAccountCloneAuthFunc) ServiceAccountCloneAuthFunc {
+	return func(client SubjectAccessReviewsProxy, pvcNamespace, pvcName, saNamespace, saName string) (bool, string, error) {
+		decision := &CloneAuthorizationDecision{
+			User:            fmt.Sprintf("system:serviceaccount:%s:%s", saNamespace, saName),
+			Source:          CloneSource{Kind: sourceKind, Namespace: pvcNamespace, Name: pvcName},
+			TargetNamespace: saNamespace,
+		}
+		return authorizeClone(client, decision, func(client SubjectAccessReviewsProxy) (bool, string, error) {
+			return f(client, pvcNamespace, pvcName, saNamespace, saName)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package clone_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	authentication "k8s.io/api/authentication/v1"
+	authorization "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/clone"
+)
+
+var _ = Describe("Clone authorization audit", func() {
+	var (
+		decisions  []*clone.CloneAuthorizationDecision
+		unregister func()
+		userInfo   = authentication.UserInfo{Username: "user", Groups: []string{"devs"}}
+	)
+
+	BeforeEach(func() {
+		decisions = nil
+		unregister = clone.RegisterCloneAuthorizationAuditor(func(decision *clone.CloneAuthorizationDecision) {
+			decisions = append(decisions, decision)
+		})
+	})
+
+	AfterEach(func() {
+		unregister()
+	})
+
+	It("should audit an authorized clone with the resource attributes allowing it", func() {
+		proxy := &attributesProxy{allowed: map[string]bool{"create pods/": true}}
+		allowed, _, err := clone.CanUserClonePVC(proxy, "golden", "fedora", "dev", userInfo)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allowed).To(BeTrue())
+		Expect(decisions).To(HaveLen(1))
+		decision := decisions[0]
+		Expect(decision.User).To(Equal("user"))
+		Expect(decision.Groups).To(Equal([]string{"devs"}))
+		Expect(decision.Source).To(Equal(clone.CloneSource{Kind: clone.SourceKindPVC, Namespace: "golden", Name: "fedora"}))
+		Expect(decision.TargetNamespace).To(Equal("dev"))
+		Expect(decision.Allowed).To(BeTrue())
+		Expect(decision.Time.IsZero()).To(BeFalse())
+		Expect(decision.MatchedResourceAttributes).To(Equal([]authorization.ResourceAttributes{
+			{Namespace: "golden", Verb: "create", Resource: "pods", Name: "fedora"},
+		}))
+		Expect(clone.FormatResourceAttributes(decision.MatchedResourceAttributes[0])).To(Equal("create pods"))
+	})
+
+	It("should audit a denied clone without resource attributes", func() {
+		proxy := &attributesProxy{allowed: map[string]bool{"create pods/": true}}
+		allowed, _, err := clone.CanServiceAccountCloneSnapshot(proxy, "golden", "snapshot", "dev", "sa")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allowed).To(BeFalse())
+		Expect(decisions).To(HaveLen(1))
+		Expect(decisions[0].User).To(Equal("system:serviceaccount:dev:sa"))
+		Expect(decisions[0].Source.Kind).To(Equal(clone.SourceKindSnapshot))
+		Expect(decisions[0].Allowed).To(BeFalse())
+		Expect(decisions[0].Reason).To(ContainSubstring("insufficient permissions"))
+		Expect(decisions[0].MatchedResourceAttributes).To(BeEmpty())
+	})
+
+	It("should not audit a clone within a namespace", func() {
+		proxy := &attributesProxy{}
+		allowed, _, err := clone.CanUserClonePVC(proxy, "dev", "fedora", "dev", userInfo)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allowed).To(BeTrue())
+		Expect(decisions).To(BeEmpty())
+	})
+
+	It("should audit the target of an admission request", func() {
+		proxy := clone.WithCloneRequest(&attributesProxy{allowed: map[string]bool{"create pods/": true}}, "fedora-dv", false)
+		allowed, _, err := clone.CanUserClonePVC(proxy, "golden", "fedora", "dev", userInfo)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allowed).To(BeTrue())
+		Expect(decisions).To(HaveLen(1))
+		Expect(decisions[0].TargetNamespace).To(Equal("dev"))
+		Expect(decisions[0].TargetName).To(Equal("fedora-dv"))
+	})
+
+	It("should not audit a dry run request", func() {
+		sars := &attributesProxy{allowed: map[string]bool{}}
+		allowed, reason, err := clone.CanUserClonePVC(clone.WithCloneRequest(sars, "fedora-dv", true), "golden", "fedora", "dev", userInfo)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allowed).To(BeFalse())
+		Expect(reason).To(ContainSubstring("insufficient permissions"))
+		Expect(sars.reviewed).ToNot(BeEmpty())
+		Expect(decisions).To(BeEmpty())
+	})
+
+	It("should audit the decisions served from the cache", func() {
+		Expect(clone.SetCloneAuthorizationConfig(&cdiv1.CloneAuthorizationConfig{
+			CacheTTL: &metav1.Duration{Duration: time.Minute},
+		})).To(Succeed())
+		defer func() {
+			Expect(clone.SetCloneAuthorizationConfig(nil)).To(Succeed())
+		}()
+		proxy := &attributesProxy{allowed: map[string]bool{"create pods/": true}}
+		for i := 0; i < 2; i++ {
+			allowed, _, err := clone.CanUserClonePVC(proxy, "golden", "fedora", "dev", userInfo)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowed).To(BeTrue())
+		}
+		Expect(proxy.reviewed).To(HaveLen(2))
+		Expect(decisions).To(HaveLen(2))
+		Expect(decisions[1].MatchedResourceAttributes).To(Equal(decisions[0].MatchedResourceAttributes))
+	})
+
+	It("should write the decisions as JSON lines", func() {
+		var out bytes.Buffer
+		defer clone.RegisterCloneAuthorizationAuditor(clone.NewJSONCloneAuthorizationAuditor(&out))()
+		proxy := &attributesProxy{allowed: map[string]bool{"create datavolumes/source": true}}
+		for i := 0; i < 2; i++ {
+			_, _, err := clone.CanUserClonePVC(proxy, "golden", "fedora", "dev", userInfo)
+			Expect(err).ToNot(HaveOccurred())
+		}
+		lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+		Expect(lines).To(HaveLen(2))
+		decision := map[string]interface{}{}
+		Expect(json.Unmarshal(lines[0], &decision)).To(Succeed())
+		Expect(decision).To(HaveKeyWithValue("user", "user"))
+		Expect(decision).To(HaveKeyWithValue("allowed", true))
+		Expect(decision).To(HaveKeyWithValue("targetNamespace", "dev"))
+		Expect(decision["source"]).To(Equal(map[string]interface{}{"kind": clone.SourceKindPVC, "namespace": "golden", "name": "fedora"}))
+		Expect(decision["matchedResourceAttributes"]).To(HaveLen(1))
+	})
+})
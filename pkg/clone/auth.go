@@ -21,7 +21,11 @@ package clone
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	"github.com/pkg/errors"
 	authentication "k8s.io/api/authentication/v1"
 	authorization "k8s.io/api/authorization/v1"
 	"k8s.io/klog/v2"
@@ -40,8 +44,212 @@ type UserCloneAuthFunc func(client SubjectAccessReviewsProxy, sourceNamespace, p
 // ServiceAccountCloneAuthFunc represents a serviceaccount clone auth func
 type ServiceAccountCloneAuthFunc func(client SubjectAccessReviewsProxy, pvcNamespace, pvcName, saNamespace, saName string) (bool, string, error)
 
+type registeredUserCloneAuthFunc struct {
+	id int
+	f  UserCloneAuthFunc
+}
+
+type registeredServiceAccountCloneAuthFunc struct {
+	id int
+	f  ServiceAccountCloneAuthFunc
+}
+
+var (
+	cloneAuthFuncsLock           sync.RWMutex
+	cloneAuthFuncsNextID         int
+	userCloneAuthFuncs           []registeredUserCloneAuthFunc
+	serviceAccountCloneAuthFuncs []registeredServiceAccountCloneAuthFunc
+
+	cloneAuthorizationLock   sync.RWMutex
+	cloneAuthorizationConfig *cdiv1.CloneAuthorizationConfig
+)
+
+// SetCloneAuthorizationConfig overrides or extends the resource attributes of the SubjectAccessReviews allowing the
+// cross-namespace clones and caches their decisions, nil restores the built-in ones without cache. An invalid config is
+// returned as error, and is not cached until it is fixed: an invalid Extend config uses the built-in resource
+// attributes, while any other invalid config denies the cross-namespace clones, as it may be meant to restrict them.
+func SetCloneAuthorizationConfig(config *cdiv1.CloneAuthorizationConfig) error {
+	var err error
+	if config != nil {
+		config = config.DeepCopy()
+		if err = validateCloneAuthorizationConfig(config); err != nil {
+			if config.Mode == "" || config.Mode == cdiv1.CloneAuthorizationModeExtend {
+				config = nil
+			} else {
+				// No resource attributes to review, so only a CloneGrant or the delegated authorizer may allow a clone
+				config = &cdiv1.CloneAuthorizationConfig{Mode: cdiv1.CloneAuthorizationModeOverride}
+			}
+		}
+	}
+	var cacheTTL time.Duration
+	if config != nil && config.CacheTTL != nil {
+		cacheTTL = config.CacheTTL.Duration
+	}
+	setSubjectAccessReviewCacheTTL(cacheTTL)
+	cloneAuthorizationLock.Lock()
+	defer cloneAuthorizationLock.Unlock()
+	cloneAuthorizationConfig = config
+	return err
+}
+
+func validateCloneAuthorizationConfig(config *cdiv1.CloneAuthorizationConfig) error {
+	switch config.Mode {
+	case "", cdiv1.CloneAuthorizationModeExtend:
+	case cdiv1.CloneAuthorizationModeOverride:
+		if len(config.ResourceAttributes) == 0 {
+			return errors.New("clone authorization mode Override requires resource attributes")
+		}
+	default:
+		return errors.Errorf("invalid clone authorization mode %q, expecting %s or %s", config.Mode,
+			cdiv1.CloneAuthorizationModeExtend, cdiv1.CloneAuthorizationModeOverride)
+	}
+	if config.CacheTTL != nil && config.CacheTTL.Duration < 0 {
+		return errors.Errorf("invalid clone authorization cache TTL %s, must not be negative", config.CacheTTL.Duration)
+	}
+	for i, ra := range config.ResourceAttributes {
+		if ra.Verb == "" || ra.Resource == "" {
+			return errors.Errorf("clone authorization resource attributes %d require a verb and a resource", i)
+		}
+	}
+	return nil
+}
+
+// getConfiguredResourceAttributes returns the configured resource attributes allowing to clone from the source, and
+// whether they override the built-in ones
+func getConfiguredResourceAttributes(namespace, name string) ([]authorization.ResourceAttributes, bool) {
+	cloneAuthorizationLock.RLock()
+	defer cloneAuthorizationLock.RUnlock()
+	if cloneAuthorizationConfig == nil {
+		return nil, false
+	}
+	var resourceAttributes []authorization.ResourceAttributes
+	for _, ra := range cloneAuthorizationConfig.ResourceAttributes {
+		resourceAttributes = append(resourceAttributes, authorization.ResourceAttributes{
+			Namespace:   namespace,
+			Verb:        ra.Verb,
+			Group:       ra.Group,
+			Resource:    ra.Resource,
+			Subresource: ra.Subresource,
+			Name:        name,
+		})
+	}
+	return resourceAttributes, cloneAuthorizationConfig.Mode == cdiv1.CloneAuthorizationModeOverride
+}
+
+// RegisterUserCloneAuthFunc registers an additional user clone auth func, for instance enforcing an org-specific
+// policy. The registered funcs run in registration order after the built-in SubjectAccessReview checks of
+// CanUserClonePVC, CanUserCloneSnapshot and CanUserCloneSnapshotWithoutReadCheck, and any denial takes precedence.
+// The returned func unregisters it.
+func RegisterUserCloneAuthFunc(f UserCloneAuthFunc) func() {
+	cloneAuthFuncsLock.Lock()
+	defer cloneAuthFuncsLock.Unlock()
+	id := cloneAuthFuncsNextID
+	cloneAuthFuncsNextID++
+	userCloneAuthFuncs = append(userCloneAuthFuncs, registeredUserCloneAuthFunc{id: id, f: f})
+	return func() {
+		cloneAuthFuncsLock.Lock()
+		defer cloneAuthFuncsLock.Unlock()
+		for i, registered := range userCloneAuthFuncs {
+			if registered.id == id {
+				userCloneAuthFuncs = append(userCloneAuthFuncs[:i:i], userCloneAuthFuncs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// RegisterServiceAccountCloneAuthFunc registers an additional ServiceAccount clone auth func. The registered funcs run
+// in registration order after the built-in SubjectAccessReview checks of CanServiceAccountClonePVC and
+// CanServiceAccountCloneSnapshot, and any denial takes precedence. The returned func unregisters it.
+func RegisterServiceAccountCloneAuthFunc(f ServiceAccountCloneAuthFunc) func() {
+	cloneAuthFuncsLock.Lock()
+	defer cloneAuthFuncsLock.Unlock()
+	id := cloneAuthFuncsNextID
+	cloneAuthFuncsNextID++
+	serviceAccountCloneAuthFuncs = append(serviceAccountCloneAuthFuncs, registeredServiceAccountCloneAuthFunc{id: id, f: f})
+	return func() {
+		cloneAuthFuncsLock.Lock()
+		defer cloneAuthFuncsLock.Unlock()
+		for i, registered := range serviceAccountCloneAuthFuncs {
+			if registered.id == id {
+				serviceAccountCloneAuthFuncs = append(serviceAccountCloneAuthFuncs[:i:i], serviceAccountCloneAuthFuncs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// ChainUserCloneAuthFuncs returns a user clone auth func allowing the clone only if all the funcs allow it. The funcs
+// run in order, and the chain stops at the first denial or error, returning its reason and error.
+func ChainUserCloneAuthFuncs(funcs ...UserCloneAuthFunc) UserCloneAuthFunc {
+	return func(client SubjectAccessReviewsProxy, sourceNamespace, pvcName, targetNamespace string, userInfo authentication.UserInfo) (bool, string, error) {
+		for _, f := range funcs {
+			if allowed, reason, err := f(client, sourceNamespace, pvcName, targetNamespace, userInfo); err != nil || !allowed {
+				return false, reason, err
+			}
+		}
+		return true, "", nil
+	}
+}
+
+// ChainServiceAccountCloneAuthFuncs returns a ServiceAccount clone auth func allowing the clone only if all the funcs
+// allow it. The funcs run in order, and the chain stops at the first denial or error, returning its reason and error.
+func ChainServiceAccountCloneAuthFuncs(funcs ...ServiceAccountCloneAuthFunc) ServiceAccountCloneAuthFunc {
+	return func(client SubjectAccessReviewsProxy, pvcNamespace, pvcName, saNamespace, saName string) (bool, string, error) {
+		for _, f := range funcs {
+			if allowed, reason, err := f(client, pvcNamespace, pvcName, saNamespace, saName); err != nil || !allowed {
+				return false, reason, err
+			}
+		}
+		return true, "", nil
+	}
+}
+
+// withRegisteredUserCloneAuthFuncs chains the delegated authorizer and the registered user clone auth funcs after the
+// built-in one, which allows the clones granted by a CloneGrant before sending SubjectAccessReviews. The delegated
+// authorizer replaces the built-in one in Replace mode.
+func withRegisteredUserCloneAuthFuncs(sourceKind string, builtin UserCloneAuthFunc) UserCloneAuthFunc {
+	funcs := []UserCloneAuthFunc{withUserCloneGrants(sourceKind, builtin)}
+	if authorizer := getDelegatedAuthorizer(); authorizer != nil {
+		if authorizer.replace {
+			funcs = nil
+		}
+		funcs = append(funcs, authorizer.userCloneAuthFunc(sourceKind))
+	}
+	cloneAuthFuncsLock.RLock()
+	defer cloneAuthFuncsLock.RUnlock()
+	for _, registered := range userCloneAuthFuncs {
+		funcs = append(funcs, registered.f)
+	}
+	return ChainUserCloneAuthFuncs(funcs...)
+}
+
+// withRegisteredServiceAccountCloneAuthFuncs chains the delegated authorizer and the registered ServiceAccount clone
+// auth funcs after the built-in one, which allows the clones granted by a CloneGrant before sending
+// SubjectAccessReviews. The delegated authorizer replaces the built-in one in Replace mode.
+func withRegisteredServiceAccountCloneAuthFuncs(sourceKind string, builtin ServiceAccountCloneAuthFunc) ServiceAccountCloneAuthFunc {
+	funcs := []ServiceAccountCloneAuthFunc{withServiceAccountCloneGrants(sourceKind, builtin)}
+	if authorizer := getDelegatedAuthorizer(); authorizer != nil {
+		if authorizer.replace {
+			funcs = nil
+		}
+		funcs = append(funcs, authorizer.serviceAccountCloneAuthFunc(sourceKind))
+	}
+	cloneAuthFuncsLock.RLock()
+	defer cloneAuthFuncsLock.RUnlock()
+	for _, registered := range serviceAccountCloneAuthFuncs {
+		funcs = append(funcs, registered.f)
+	}
+	return ChainServiceAccountCloneAuthFuncs(funcs...)
+}
+
 // CanUserClonePVC checks if a user has "appropriate" permission to clone from the given PVC
 func CanUserClonePVC(client SubjectAccessReviewsProxy, sourceNamespace, pvcName, targetNamespace string,
+	userInfo authentication.UserInfo) (bool, string, error) {
+	return withUserCloneAudit(SourceKindPVC, withRegisteredUserCloneAuthFuncs(SourceKindPVC, canUserClonePVC))(client, sourceNamespace, pvcName, targetNamespace, userInfo)
+}
+
+func canUserClonePVC(client SubjectAccessReviewsProxy, sourceNamespace, pvcName, targetNamespace string,
 	userInfo authentication.UserInfo) (bool, string, error) {
 	if sourceNamespace == targetNamespace {
 		return true, "", nil
@@ -66,6 +274,10 @@ func CanUserClonePVC(client SubjectAccessReviewsProxy, sourceNamespace, pvcName,
 
 // CanServiceAccountClonePVC checks if a ServiceAccount has "appropriate" permission to clone from the given PVC
 func CanServiceAccountClonePVC(client SubjectAccessReviewsProxy, pvcNamespace, pvcName, saNamespace, saName string) (bool, string, error) {
+	return withServiceAccountCloneAudit(SourceKindPVC, withRegisteredServiceAccountCloneAuthFuncs(SourceKindPVC, canServiceAccountClonePVC))(client, pvcNamespace, pvcName, saNamespace, saName)
+}
+
+func canServiceAccountClonePVC(client SubjectAccessReviewsProxy, pvcNamespace, pvcName, saNamespace, saName string) (bool, string, error) {
 	if pvcNamespace == saNamespace {
 		return true, "", nil
 	}
@@ -87,6 +299,22 @@ func CanServiceAccountClonePVC(client SubjectAccessReviewsProxy, pvcNamespace, p
 // CanUserCloneSnapshot checks if a user has "appropriate" permission to clone from the given snapshot
 func CanUserCloneSnapshot(client SubjectAccessReviewsProxy, sourceNamespace, pvcName, targetNamespace string,
 	userInfo authentication.UserInfo) (bool, string, error) {
+	return withUserCloneAudit(SourceKindSnapshot, withRegisteredUserCloneAuthFuncs(SourceKindSnapshot, func(client SubjectAccessReviewsProxy, sourceNamespace, pvcName, targetNamespace string, userInfo authentication.UserInfo) (bool, string, error) {
+		return canUserCloneSnapshot(client, sourceNamespace, pvcName, targetNamespace, userInfo, true)
+	}))(client, sourceNamespace, pvcName, targetNamespace, userInfo)
+}
+
+// CanUserCloneSnapshotWithoutReadCheck checks if a user has "appropriate" permission to clone from the given snapshot,
+// without requiring read access to the snapshot when relying on the implicit permissions
+func CanUserCloneSnapshotWithoutReadCheck(client SubjectAccessReviewsProxy, sourceNamespace, pvcName, targetNamespace string,
+	userInfo authentication.UserInfo) (bool, string, error) {
+	return withUserCloneAudit(SourceKindSnapshot, withRegisteredUserCloneAuthFuncs(SourceKindSnapshot, func(client SubjectAccessReviewsProxy, sourceNamespace, pvcName, targetNamespace string, userInfo authentication.UserInfo) (bool, string, error) {
+		return canUserCloneSnapshot(client, sourceNamespace, pvcName, targetNamespace, userInfo, false)
+	}))(client, sourceNamespace, pvcName, targetNamespace, userInfo)
+}
+
+func canUserCloneSnapshot(client SubjectAccessReviewsProxy, sourceNamespace, pvcName, targetNamespace string,
+	userInfo authentication.UserInfo, checkRead bool) (bool, string, error) {
 	if sourceNamespace == targetNamespace {
 		return true, "", nil
 	}
@@ -105,11 +333,15 @@ func CanUserCloneSnapshot(client SubjectAccessReviewsProxy, sourceNamespace, pvc
 		Extra:  newExtra,
 	}
 
-	return sendSubjectAccessReviewsSnapshot(client, sourceNamespace, pvcName, sarSpec)
+	return sendSubjectAccessReviewsSnapshot(client, sourceNamespace, pvcName, sarSpec, checkRead)
 }
 
 // CanServiceAccountCloneSnapshot checks if a ServiceAccount has "appropriate" permission to clone from the given snapshot
 func CanServiceAccountCloneSnapshot(client SubjectAccessReviewsProxy, pvcNamespace, pvcName, saNamespace, saName string) (bool, string, error) {
+	return withServiceAccountCloneAudit(SourceKindSnapshot, withRegisteredServiceAccountCloneAuthFuncs(SourceKindSnapshot, canServiceAccountCloneSnapshot))(client, pvcNamespace, pvcName, saNamespace, saName)
+}
+
+func canServiceAccountCloneSnapshot(client SubjectAccessReviewsProxy, pvcNamespace, pvcName, saNamespace, saName string) (bool, string, error) {
 	if pvcNamespace == saNamespace {
 		return true, "", nil
 	}
@@ -125,7 +357,7 @@ func CanServiceAccountCloneSnapshot(client SubjectAccessReviewsProxy, pvcNamespa
 		},
 	}
 
-	return sendSubjectAccessReviewsSnapshot(client, pvcNamespace, pvcName, sarSpec)
+	return sendSubjectAccessReviewsSnapshot(client, pvcNamespace, pvcName, sarSpec, true)
 }
 
 func sendSubjectAccessReviewsPvc(client SubjectAccessReviewsProxy, namespace, name string, sarSpec authorization.SubjectAccessReviewSpec) (bool, string, error) {
@@ -159,37 +391,49 @@ func sendSubjectAccessReviewsPvc(client SubjectAccessReviewsProxy, namespace, na
 	return true, "", nil
 }
 
-func sendSubjectAccessReviewsSnapshot(client SubjectAccessReviewsProxy, namespace, name string, sarSpec authorization.SubjectAccessReviewSpec) (bool, string, error) {
+func sendSubjectAccessReviewsSnapshot(client SubjectAccessReviewsProxy, namespace, name string, sarSpec authorization.SubjectAccessReviewSpec, checkRead bool) (bool, string, error) {
 	// Either explicitly allowed
-	sar := &authorization.SubjectAccessReview{
-		Spec: sarSpec,
+	explicitResourceAttrs, override := getConfiguredResourceAttributes(namespace, name)
+	if !override {
+		explicitResourceAttrs = append([]authorization.ResourceAttributes{getExplicitResourceAttributeSnapshot(namespace, name)}, explicitResourceAttrs...)
 	}
-	explicitResourceAttr := getExplicitResourceAttributeSnapshot(namespace, name)
-	sar.Spec.ResourceAttributes = &explicitResourceAttr
+	for _, ra := range explicitResourceAttrs {
+		sar := &authorization.SubjectAccessReview{
+			Spec: sarSpec,
+		}
+		sar.Spec.ResourceAttributes = &ra
 
-	klog.V(3).Infof("Sending SubjectAccessReview %+v", sar)
+		klog.V(3).Infof("Sending SubjectAccessReview %+v", sar)
 
-	response, err := client.Create(sar)
-	if err != nil {
-		return false, "", err
-	}
+		response, err := client.Create(sar)
+		if err != nil {
+			return false, "", err
+		}
 
-	klog.V(3).Infof("SubjectAccessReview response %+v", response)
+		klog.V(3).Infof("SubjectAccessReview response %+v", response)
 
-	if response.Status.Allowed {
-		return true, "", nil
+		if response.Status.Allowed {
+			return true, "", nil
+		}
+	}
+	if override {
+		return false, fmt.Sprintf("User %s has insufficient permissions in clone source namespace %s", sarSpec.User, namespace), nil
 	}
 
-	// Or both implicit conditions hold
-	for _, ra := range getImplicitResourceAttributesSnapshot(namespace, name) {
-		sar = &authorization.SubjectAccessReview{
+	// Or all implicit conditions hold
+	implicitResourceAttrs := getImplicitResourceAttributesSnapshot(namespace, name)
+	if checkRead {
+		implicitResourceAttrs = append(implicitResourceAttrs, getReadResourceAttributeSnapshot(namespace, name))
+	}
+	for _, ra := range implicitResourceAttrs {
+		sar := &authorization.SubjectAccessReview{
 			Spec: sarSpec,
 		}
 		sar.Spec.ResourceAttributes = &ra
 
 		klog.V(3).Infof("Sending SubjectAccessReview %+v", sar)
 
-		response, err = client.Create(sar)
+		response, err := client.Create(sar)
 		if err != nil {
 			return false, "", err
 		}
@@ -205,7 +449,11 @@ func sendSubjectAccessReviewsSnapshot(client SubjectAccessReviewsProxy, namespac
 }
 
 func getResourceAttributesPvc(namespace, name string) []authorization.ResourceAttributes {
-	return []authorization.ResourceAttributes{
+	configured, override := getConfiguredResourceAttributes(namespace, name)
+	if override {
+		return configured
+	}
+	return append([]authorization.ResourceAttributes{
 		{
 			Namespace:   namespace,
 			Verb:        "create",
@@ -220,7 +468,7 @@ func getResourceAttributesPvc(namespace, name string) []authorization.ResourceAt
 			Resource:  "pods",
 			Name:      name,
 		},
-	}
+	}, configured...)
 }
 
 func getExplicitResourceAttributeSnapshot(namespace, name string) authorization.ResourceAttributes {
@@ -250,3 +498,13 @@ func getImplicitResourceAttributesSnapshot(namespace, name string) []authorizati
 		},
 	}
 }
+
+func getReadResourceAttributeSnapshot(namespace, name string) authorization.ResourceAttributes {
+	return authorization.ResourceAttributes{
+		Namespace: namespace,
+		Verb:      "get",
+		Group:     snapshotv1.GroupName,
+		Resource:  "volumesnapshots",
+		Name:      name,
+	}
+}
@@ -0,0 +1,301 @@
+package clone_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	authentication "k8s.io/api/authentication/v1"
+	authorization "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/clone"
+)
+
+type fakeProxy struct {
+	allowed bool
+	reviews int
+}
+
+func (p *fakeProxy) Create(sar *authorization.SubjectAccessReview) (*authorization.SubjectAccessReview, error) {
+	p.reviews++
+	sar.Status.Allowed = p.allowed
+	sar.Status.Reason = fmt.Sprintf("allowed=%t", p.allowed)
+	return sar, nil
+}
+
+// attributesProxy allows the SubjectAccessReviews of the allowed verb and resource pairs, recording them all
+type attributesProxy struct {
+	allowed         map[string]bool
+	evaluationError string
+	reviewed        []string
+}
+
+func (p *attributesProxy) Create(sar *authorization.SubjectAccessReview) (*authorization.SubjectAccessReview, error) {
+	ra := sar.Spec.ResourceAttributes
+	key := fmt.Sprintf("%s %s/%s", ra.Verb, ra.Resource, ra.Subresource)
+	p.reviewed = append(p.reviewed, key)
+	sar.Status.Allowed = p.allowed[key]
+	sar.Status.EvaluationError = p.evaluationError
+	return sar, nil
+}
+
+func userAuthFunc(allowed bool, reason string, err error, calls *int) clone.UserCloneAuthFunc {
+	return func(clone.SubjectAccessReviewsProxy, string, string, string, authentication.UserInfo) (bool, string, error) {
+		*calls++
+		return allowed, reason, err
+	}
+}
+
+func serviceAccountAuthFunc(allowed bool, reason string, err error, calls *int) clone.ServiceAccountCloneAuthFunc {
+	return func(clone.SubjectAccessReviewsProxy, string, string, string, string) (bool, string, error) {
+		*calls++
+		return allowed, reason, err
+	}
+}
+
+var _ = Describe("Clone auth", func() {
+	var (
+		proxy      *fakeProxy
+		userInfo   = authentication.UserInfo{Username: "user"}
+		unregister []func()
+	)
+
+	BeforeEach(func() {
+		proxy = &fakeProxy{allowed: true}
+		unregister = nil
+	})
+
+	AfterEach(func() {
+		for _, f := range unregister {
+			f()
+		}
+	})
+
+	Context("chaining", func() {
+		It("should allow when all the user clone auth funcs allow", func() {
+			var first, second int
+			allowed, reason, err := clone.ChainUserCloneAuthFuncs(
+				userAuthFunc(true, "", nil, &first),
+				userAuthFunc(true, "", nil, &second),
+			)(proxy, "source", "pvc", "target", userInfo)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowed).To(BeTrue())
+			Expect(reason).To(BeEmpty())
+			Expect(first).To(Equal(1))
+			Expect(second).To(Equal(1))
+		})
+
+		It("should short-circuit on the first user clone auth func denying", func() {
+			var first, second, third int
+			allowed, reason, err := clone.ChainUserCloneAuthFuncs(
+				userAuthFunc(true, "", nil, &first),
+				userAuthFunc(false, "denied by policy", nil, &second),
+				userAuthFunc(false, "not reached", nil, &third),
+			)(proxy, "source", "pvc", "target", userInfo)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowed).To(BeFalse())
+			Expect(reason).To(Equal("denied by policy"))
+			Expect(second).To(Equal(1))
+			Expect(third).To(BeZero())
+		})
+
+		It("should short-circuit on the first ServiceAccount clone auth func failing", func() {
+			var first, second int
+			allowed, _, err := clone.ChainServiceAccountCloneAuthFuncs(
+				serviceAccountAuthFunc(true, "", fmt.Errorf("policy unavailable"), &first),
+				serviceAccountAuthFunc(true, "", nil, &second),
+			)(proxy, "source", "pvc", "target", "sa")
+			Expect(err).To(MatchError("policy unavailable"))
+			Expect(allowed).To(BeFalse())
+			Expect(second).To(BeZero())
+		})
+	})
+
+	Context("registered auth funcs", func() {
+		It("should let a registered user clone auth func deny a clone allowed by RBAC", func() {
+			var calls int
+			unregister = append(unregister, clone.RegisterUserCloneAuthFunc(userAuthFunc(false, "cross-tenant clone", nil, &calls)))
+			allowed, reason, err := clone.CanUserClonePVC(proxy, "source", "pvc", "target", userInfo)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowed).To(BeFalse())
+			Expect(reason).To(Equal("cross-tenant clone"))
+			Expect(proxy.reviews).ToNot(BeZero())
+			Expect(calls).To(Equal(1))
+		})
+
+		It("should not run the registered user clone auth funcs when RBAC denies", func() {
+			var calls int
+			proxy.allowed = false
+			unregister = append(unregister, clone.RegisterUserCloneAuthFunc(userAuthFunc(true, "", nil, &calls)))
+			allowed, reason, err := clone.CanUserClonePVC(proxy, "source", "pvc", "target", userInfo)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowed).To(BeFalse())
+			Expect(reason).To(ContainSubstring("insufficient permissions"))
+			Expect(calls).To(BeZero())
+		})
+
+		It("should let a registered ServiceAccount clone auth func deny a snapshot clone allowed by RBAC", func() {
+			var calls int
+			unregister = append(unregister, clone.RegisterServiceAccountCloneAuthFunc(serviceAccountAuthFunc(false, "cross-tenant clone", nil, &calls)))
+			allowed, reason, err := clone.CanServiceAccountCloneSnapshot(proxy, "source", "snapshot", "target", "sa")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowed).To(BeFalse())
+			Expect(reason).To(Equal("cross-tenant clone"))
+			Expect(calls).To(Equal(1))
+		})
+
+		It("should stop running an unregistered auth func", func() {
+			var calls int
+			f := clone.RegisterUserCloneAuthFunc(userAuthFunc(false, "cross-tenant clone", nil, &calls))
+			f()
+			allowed, _, err := clone.CanUserClonePVC(proxy, "source", "pvc", "target", userInfo)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowed).To(BeTrue())
+			Expect(calls).To(BeZero())
+		})
+	})
+
+	Context("configured resource attributes", func() {
+		cloneVerb := cdiv1.CloneResourceAttributes{Verb: "clone", Group: "cdi.kubevirt.io", Resource: "datavolumes", Subresource: "source"}
+
+		AfterEach(func() {
+			Expect(clone.SetCloneAuthorizationConfig(nil)).To(Succeed())
+		})
+
+		It("should allow a clone allowed by a resource attribute extending the built-in ones", func() {
+			Expect(clone.SetCloneAuthorizationConfig(&cdiv1.CloneAuthorizationConfig{
+				ResourceAttributes: []cdiv1.CloneResourceAttributes{cloneVerb},
+			})).To(Succeed())
+			proxy := &attributesProxy{allowed: map[string]bool{"clone datavolumes/source": true}}
+			allowed, _, err := clone.CanUserClonePVC(proxy, "source", "pvc", "target", userInfo)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowed).To(BeTrue())
+			Expect(proxy.reviewed).To(Equal([]string{"create datavolumes/source", "create pods/", "clone datavolumes/source"}))
+		})
+
+		It("should only review the overriding resource attributes", func() {
+			Expect(clone.SetCloneAuthorizationConfig(&cdiv1.CloneAuthorizationConfig{
+				ResourceAttributes: []cdiv1.CloneResourceAttributes{cloneVerb},
+				Mode:               cdiv1.CloneAuthorizationModeOverride,
+			})).To(Succeed())
+			proxy := &attributesProxy{allowed: map[string]bool{"create datavolumes/source": true, "create pods/": true, "create pvcs/": true}}
+			allowed, reason, err := clone.CanServiceAccountCloneSnapshot(proxy, "source", "snapshot", "target", "sa")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowed).To(BeFalse())
+			Expect(reason).To(ContainSubstring("insufficient permissions"))
+			Expect(proxy.reviewed).To(Equal([]string{"clone datavolumes/source"}))
+		})
+
+		It("should allow a snapshot clone allowed by a resource attribute before the implicit checks", func() {
+			Expect(clone.SetCloneAuthorizationConfig(&cdiv1.CloneAuthorizationConfig{
+				ResourceAttributes: []cdiv1.CloneResourceAttributes{cloneVerb},
+			})).To(Succeed())
+			proxy := &attributesProxy{allowed: map[string]bool{"clone datavolumes/source": true}}
+			allowed, _, err := clone.CanUserCloneSnapshot(proxy, "source", "snapshot", "target", userInfo)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowed).To(BeTrue())
+			Expect(proxy.reviewed).To(Equal([]string{"create datavolumes/source", "clone datavolumes/source"}))
+		})
+
+		It("should use the built-in resource attributes when the Extend config is invalid", func() {
+			Expect(clone.SetCloneAuthorizationConfig(&cdiv1.CloneAuthorizationConfig{
+				ResourceAttributes: []cdiv1.CloneResourceAttributes{{Resource: "datavolumes"}},
+			})).ToNot(Succeed())
+			proxy := &attributesProxy{allowed: map[string]bool{"create pods/": true}}
+			allowed, _, err := clone.CanUserClonePVC(proxy, "source", "pvc", "target", userInfo)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowed).To(BeTrue())
+			Expect(proxy.reviewed).To(Equal([]string{"create datavolumes/source", "create pods/"}))
+		})
+
+		table.DescribeTable("should deny the cross-namespace clones while the config is invalid", func(config *cdiv1.CloneAuthorizationConfig) {
+			Expect(clone.SetCloneAuthorizationConfig(config)).ToNot(Succeed())
+			proxy := &attributesProxy{allowed: map[string]bool{"create datavolumes/source": true, "create pods/": true, "create pvcs/": true}}
+			allowed, reason, err := clone.CanUserClonePVC(proxy, "source", "pvc", "target", userInfo)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowed).To(BeFalse())
+			Expect(reason).To(ContainSubstring("insufficient permissions"))
+			allowed, _, err = clone.CanServiceAccountCloneSnapshot(proxy, "source", "snapshot", "target", "sa")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowed).To(BeFalse())
+			Expect(proxy.reviewed).To(BeEmpty())
+
+			By("Allowing the clones of the same namespace")
+			allowed, _, err = clone.CanUserClonePVC(proxy, "source", "pvc", "source", userInfo)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowed).To(BeTrue())
+		},
+			table.Entry("with an Override config without resource attributes", &cdiv1.CloneAuthorizationConfig{
+				Mode: cdiv1.CloneAuthorizationModeOverride,
+			}),
+			table.Entry("with an Override resource attribute missing its verb", &cdiv1.CloneAuthorizationConfig{
+				Mode:               cdiv1.CloneAuthorizationModeOverride,
+				ResourceAttributes: []cdiv1.CloneResourceAttributes{{Resource: "datavolumes"}},
+			}),
+			table.Entry("with an unknown mode", &cdiv1.CloneAuthorizationConfig{
+				Mode:               "Overide",
+				ResourceAttributes: []cdiv1.CloneResourceAttributes{cloneVerb},
+			}),
+		)
+	})
+
+	Context("cached decisions", func() {
+		BeforeEach(func() {
+			Expect(clone.SetCloneAuthorizationConfig(&cdiv1.CloneAuthorizationConfig{
+				CacheTTL: &metav1.Duration{Duration: time.Minute},
+			})).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(clone.SetCloneAuthorizationConfig(nil)).To(Succeed())
+		})
+
+		It("should reuse the cached decision of the same user and source", func() {
+			proxy := &attributesProxy{allowed: map[string]bool{"create pods/": true}}
+			for i := 0; i < 3; i++ {
+				allowed, _, err := clone.CanUserClonePVC(proxy, "source", "pvc", "target", userInfo)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(allowed).To(BeTrue())
+			}
+			Expect(proxy.reviewed).To(HaveLen(2))
+
+			By("Reviewing another user")
+			_, _, err := clone.CanUserClonePVC(proxy, "source", "pvc", "target", authentication.UserInfo{Username: "other"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(proxy.reviewed).To(HaveLen(4))
+
+			By("Reviewing another source")
+			_, _, err = clone.CanUserClonePVC(proxy, "source", "other-pvc", "target", userInfo)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(proxy.reviewed).To(HaveLen(6))
+		})
+
+		It("should drop the cached decisions when the cache is disabled", func() {
+			proxy := &attributesProxy{allowed: map[string]bool{}}
+			allowed, _, err := clone.CanUserClonePVC(proxy, "source", "pvc", "target", userInfo)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowed).To(BeFalse())
+
+			Expect(clone.SetCloneAuthorizationConfig(nil)).To(Succeed())
+			proxy.allowed["create pods/"] = true
+			allowed, _, err = clone.CanUserClonePVC(proxy, "source", "pvc", "target", userInfo)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowed).To(BeTrue())
+		})
+
+		It("should not cache a decision failing to evaluate", func() {
+			proxy := &attributesProxy{allowed: map[string]bool{}, evaluationError: "webhook authorizer unavailable"}
+			for i := 0; i < 2; i++ {
+				allowed, _, err := clone.CanUserClonePVC(proxy, "source", "pvc", "target", userInfo)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(allowed).To(BeFalse())
+			}
+			Expect(proxy.reviewed).To(HaveLen(4))
+		})
+	})
+})
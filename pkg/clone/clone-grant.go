@@ -0,0 +1,153 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clone
+
+import (
+	"sort"
+	"sync"
+
+	authentication "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	cdilisters "kubevirt.io/containerized-data-importer/pkg/client/listers/core/v1beta1"
+)
+
+var (
+	cloneGrantListerLock sync.RWMutex
+	cloneGrantLister     cdilisters.CloneGrantLister
+)
+
+// cloneGrantRecorder is implemented by the clients recording the CloneGrant allowing a clone
+type cloneGrantRecorder interface {
+	recordCloneGrant(grant *cdiv1.CloneGrant)
+}
+
+// SetCloneGrantLister sets the lister of the CloneGrants pre-approving the cross-namespace clones of CanUserClonePVC,
+// CanServiceAccountClonePVC and their snapshot counterparts before the built-in SubjectAccessReview checks, nil
+// disables the CloneGrants
+func SetCloneGrantLister(lister cdilisters.CloneGrantLister) {
+	cloneGrantListerLock.Lock()
+	defer cloneGrantListerLock.Unlock()
+	cloneGrantLister = lister
+}
+
+func getCloneGrantLister() cdilisters.CloneGrantLister {
+	cloneGrantListerLock.RLock()
+	defer cloneGrantListerLock.RUnlock()
+	return cloneGrantLister
+}
+
+// findCloneGrant returns the first CloneGrant by name of the source namespace granting the source of the kind to the
+// requestor, or nil
+func findCloneGrant(sourceKind, sourceNamespace, sourceName string, grantsRequestor func(*cdiv1.CloneGrantSpec) bool) (*cdiv1.CloneGrant, error) {
+	lister := getCloneGrantLister()
+	if lister == nil {
+		return nil, nil
+	}
+	grants, err := lister.CloneGrants(sourceNamespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(grants, func(i, j int) bool { return grants[i].Name < grants[j].Name })
+	for _, grant := range grants {
+		if grantsSource(&grant.Spec, sourceKind, sourceName) && grantsRequestor(&grant.Spec) {
+			return grant, nil
+		}
+	}
+	return nil, nil
+}
+
+// grantsSource tells whether the grant applies to the source of the kind, a grant without sources applying to all
+func grantsSource(spec *cdiv1.CloneGrantSpec, sourceKind, sourceName string) bool {
+	if len(spec.Sources) == 0 {
+		return true
+	}
+	for _, source := range spec.Sources {
+		if source.Kind == sourceKind && source.Name == sourceName {
+			return true
+		}
+	}
+	return false
+}
+
+func grantsTargetNamespace(spec *cdiv1.CloneGrantSpec, targetNamespace string) bool {
+	for _, namespace := range spec.TargetNamespaces {
+		if namespace == targetNamespace {
+			return true
+		}
+	}
+	return false
+}
+
+func grantsServiceAccount(spec *cdiv1.CloneGrantSpec, saNamespace, saName string) bool {
+	for _, sa := range spec.ServiceAccounts {
+		if sa.Namespace == saNamespace && sa.Name == saName {
+			return true
+		}
+	}
+	return grantsTargetNamespace(spec, saNamespace)
+}
+
+func recordCloneGrant(client SubjectAccessReviewsProxy, grant *cdiv1.CloneGrant) {
+	klog.V(3).Infof("Clone granted by CloneGrant %s/%s", grant.Namespace, grant.Name)
+	if recorder, ok := client.(cloneGrantRecorder); ok {
+		recorder.recordCloneGrant(grant)
+	}
+}
+
+// withUserCloneGrants returns the user clone auth func allowing the cross-namespace clones from a source of the kind
+// granted by a CloneGrant of the source namespace, and falling back to f for the others
+func withUserCloneGrants(sourceKind string, f UserCloneAuthFunc) UserCloneAuthFunc {
+	return func(client SubjectAccessReviewsProxy, sourceNamespace, name, targetNamespace string, userInfo authentication.UserInfo) (bool, string, error) {
+		if sourceNamespace != targetNamespace {
+			grant, err := findCloneGrant(sourceKind, sourceNamespace, name, func(spec *cdiv1.CloneGrantSpec) bool {
+				return grantsTargetNamespace(spec, targetNamespace)
+			})
+			if err != nil {
+				return false, "", err
+			}
+			if grant != nil {
+				recordCloneGrant(client, grant)
+				return true, "", nil
+			}
+		}
+		return f(client, sourceNamespace, name, targetNamespace, userInfo)
+	}
+}
+
+// withServiceAccountCloneGrants returns the ServiceAccount clone auth func allowing the cross-namespace clones from a
+// source of the kind granted by a CloneGrant of the source namespace to the ServiceAccount or its namespace, and
+// falling back to f for the others
+func withServiceAccountCloneGrants(sourceKind string, f ServiceAccountCloneAuthFunc) ServiceAccountCloneAuthFunc {
+	return func(client SubjectAccessReviewsProxy, pvcNamespace, pvcName, saNamespace, saName string) (bool, string, error) {
+		if pvcNamespace != saNamespace {
+			grant, err := findCloneGrant(sourceKind, pvcNamespace, pvcName, func(spec *cdiv1.CloneGrantSpec) bool {
+				return grantsServiceAccount(spec, saNamespace, saName)
+			})
+			if err != nil {
+				return false, "", err
+			}
+			if grant != nil {
+				recordCloneGrant(client, grant)
+				return true, "", nil
+			}
+		}
+		return f(client, pvcNamespace, pvcName, saNamespace, saName)
+	}
+}
@@ -0,0 +1,104 @@
+package clone_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	authentication "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	cdilisters "kubevirt.io/containerized-data-importer/pkg/client/listers/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/clone"
+)
+
+var _ = Describe("Clone grants", func() {
+	var (
+		proxy    *fakeProxy
+		indexer  cache.Indexer
+		userInfo = authentication.UserInfo{Username: "user", Groups: []string{"devs"}}
+	)
+
+	addGrant := func(name string, spec cdiv1.CloneGrantSpec) {
+		grant := &cdiv1.CloneGrant{ObjectMeta: metav1.ObjectMeta{Namespace: "golden", Name: name}, Spec: spec}
+		Expect(indexer.Add(grant)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		proxy = &fakeProxy{allowed: false}
+		indexer = cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		clone.SetCloneGrantLister(cdilisters.NewCloneGrantLister(indexer))
+	})
+
+	AfterEach(func() {
+		clone.SetCloneGrantLister(nil)
+	})
+
+	It("should allow a clone to a granted namespace without SubjectAccessReviews", func() {
+		var decisions []*clone.CloneAuthorizationDecision
+		defer clone.RegisterCloneAuthorizationAuditor(func(decision *clone.CloneAuthorizationDecision) {
+			decisions = append(decisions, decision)
+		})()
+		addGrant("dev-team", cdiv1.CloneGrantSpec{TargetNamespaces: []string{"dev"}})
+		allowed, _, err := clone.CanUserClonePVC(proxy, "golden", "fedora", "dev", userInfo)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allowed).To(BeTrue())
+		Expect(proxy.reviews).To(BeZero())
+		Expect(decisions).To(HaveLen(1))
+		Expect(decisions[0].Grant).To(Equal("golden/dev-team"))
+		Expect(decisions[0].MatchedResourceAttributes).To(BeEmpty())
+	})
+
+	It("should fall back to SubjectAccessReviews for a namespace not granted", func() {
+		addGrant("dev-team", cdiv1.CloneGrantSpec{TargetNamespaces: []string{"dev"}})
+		allowed, _, err := clone.CanUserCloneSnapshot(proxy, "golden", "fedora", "prod", userInfo)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allowed).To(BeFalse())
+		Expect(proxy.reviews).ToNot(BeZero())
+	})
+
+	It("should only grant the listed sources of their kind", func() {
+		addGrant("fedora", cdiv1.CloneGrantSpec{
+			Sources:          []cdiv1.CloneGrantSource{{Kind: clone.SourceKindPVC, Name: "fedora"}},
+			TargetNamespaces: []string{"dev"},
+		})
+		allowed, _, err := clone.CanUserClonePVC(proxy, "golden", "fedora", "dev", userInfo)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allowed).To(BeTrue())
+		allowed, _, err = clone.CanUserClonePVC(proxy, "golden", "centos", "dev", userInfo)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allowed).To(BeFalse())
+		allowed, _, err = clone.CanUserCloneSnapshot(proxy, "golden", "fedora", "dev", userInfo)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allowed).To(BeFalse())
+	})
+
+	It("should allow a granted ServiceAccount or a ServiceAccount of a granted namespace", func() {
+		addGrant("pipelines", cdiv1.CloneGrantSpec{
+			TargetNamespaces: []string{"dev"},
+			ServiceAccounts:  []cdiv1.CloneGrantServiceAccount{{Namespace: "ci", Name: "pipeline"}},
+		})
+		allowed, _, err := clone.CanServiceAccountClonePVC(proxy, "golden", "fedora", "ci", "pipeline")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allowed).To(BeTrue())
+		allowed, _, err = clone.CanServiceAccountCloneSnapshot(proxy, "golden", "fedora", "dev", "default")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allowed).To(BeTrue())
+		allowed, _, err = clone.CanServiceAccountClonePVC(proxy, "golden", "fedora", "ci", "default")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allowed).To(BeFalse())
+		Expect(proxy.reviews).ToNot(BeZero())
+	})
+
+	It("should not grant the clones of another namespace", func() {
+		grant := &cdiv1.CloneGrant{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "dev", Name: "self-granted"},
+			Spec:       cdiv1.CloneGrantSpec{TargetNamespaces: []string{"dev"}},
+		}
+		Expect(indexer.Add(grant)).To(Succeed())
+		allowed, _, err := clone.CanUserClonePVC(proxy, "golden", "fedora", "dev", userInfo)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allowed).To(BeFalse())
+	})
+})
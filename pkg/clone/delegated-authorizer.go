@@ -0,0 +1,254 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clone
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	authentication "k8s.io/api/authentication/v1"
+	"k8s.io/klog/v2"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+)
+
+const (
+	// SourceKindPVC is the kind of a PVC clone source sent to the delegated authorizer
+	SourceKindPVC = "PersistentVolumeClaim"
+	// SourceKindSnapshot is the kind of a VolumeSnapshot clone source sent to the delegated authorizer
+	SourceKindSnapshot = "VolumeSnapshot"
+
+	defaultDelegatedAuthorizerTimeout = 10 * time.Second
+	// maxDelegatedAuthorizerResponseSize bounds the response body read from the delegated authorizer
+	maxDelegatedAuthorizerResponseSize = 1024 * 1024
+)
+
+// delegatedAuthorizer is the external HTTP authorization webhook of CDIConfig deciding the cross-namespace clones
+type delegatedAuthorizer struct {
+	config *cdiv1.ClusterDelegatedAuthorizer
+	client *http.Client
+	// replace tells the authorizer decides the clones instead of the built-in checks
+	replace bool
+	// err is the error of an invalid config, denying all the cross-namespace clones
+	err error
+}
+
+// delegatedAuthorizationRequest is the body POSTed to the delegated authorizer, the clone is in its input field like
+// the input of the data API of Open Policy Agent
+type delegatedAuthorizationRequest struct {
+	Input delegatedAuthorizationInput `json:"input"`
+}
+
+type delegatedAuthorizationInput struct {
+	Operation       string                        `json:"operation"`
+	Source          delegatedAuthorizationSource  `json:"source"`
+	TargetNamespace string                        `json:"targetNamespace"`
+	User            delegatedAuthorizationUser    `json:"user"`
+	ServiceAccount  *delegatedAuthorizationSource `json:"serviceAccount,omitempty"`
+}
+
+type delegatedAuthorizationSource struct {
+	Kind      string `json:"kind,omitempty"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+type delegatedAuthorizationUser struct {
+	Username string              `json:"username"`
+	UID      string              `json:"uid,omitempty"`
+	Groups   []string            `json:"groups,omitempty"`
+	Extra    map[string][]string `json:"extra,omitempty"`
+}
+
+// delegatedAuthorizationResponse is the body returned by the delegated authorizer, its result is either a boolean or
+// an object with allowed and reason fields. A missing result, an undefined Open Policy Agent decision, denies the clone.
+type delegatedAuthorizationResponse struct {
+	Result json.RawMessage `json:"result"`
+}
+
+type delegatedAuthorizationDecision struct {
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason"`
+}
+
+var (
+	delegatedAuthorizerLock    sync.RWMutex
+	currentDelegatedAuthorizer *delegatedAuthorizer
+)
+
+// SetClusterDelegatedAuthorizer configures the external HTTP authorization webhook deciding the cross-namespace
+// clones of CanUserClonePVC, CanServiceAccountClonePVC and their snapshot counterparts, nil removes it. An invalid
+// config is returned as error, and denies the cross-namespace clones until it is fixed.
+func SetClusterDelegatedAuthorizer(config *cdiv1.ClusterDelegatedAuthorizer) error {
+	delegatedAuthorizerLock.Lock()
+	defer delegatedAuthorizerLock.Unlock()
+	if config == nil {
+		currentDelegatedAuthorizer = nil
+		return nil
+	}
+	if currentDelegatedAuthorizer != nil && reflect.DeepEqual(currentDelegatedAuthorizer.config, config) {
+		return nil
+	}
+	authorizer := newDelegatedAuthorizer(config.DeepCopy())
+	currentDelegatedAuthorizer = authorizer
+	return authorizer.err
+}
+
+func getDelegatedAuthorizer() *delegatedAuthorizer {
+	delegatedAuthorizerLock.RLock()
+	defer delegatedAuthorizerLock.RUnlock()
+	return currentDelegatedAuthorizer
+}
+
+func newDelegatedAuthorizer(config *cdiv1.ClusterDelegatedAuthorizer) *delegatedAuthorizer {
+	authorizer := &delegatedAuthorizer{config: config}
+	switch config.Mode {
+	case "", cdiv1.DelegatedAuthorizerModeAdditional:
+	case cdiv1.DelegatedAuthorizerModeReplace:
+		authorizer.replace = true
+	default:
+		authorizer.err = errors.Errorf("invalid delegated authorizer mode %q, expecting %s or %s", config.Mode,
+			cdiv1.DelegatedAuthorizerModeAdditional, cdiv1.DelegatedAuthorizerModeReplace)
+		return authorizer
+	}
+	u, err := url.Parse(config.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		authorizer.err = errors.Errorf("invalid delegated authorizer URL %q, expecting an http or https URL", config.URL)
+		return authorizer
+	}
+
+	timeout := defaultDelegatedAuthorizerTimeout
+	if config.TimeoutSeconds != nil && *config.TimeoutSeconds > 0 {
+		timeout = time.Duration(*config.TimeoutSeconds) * time.Second
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if len(config.CABundle) > 0 {
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(config.CABundle) {
+			authorizer.err = errors.New("invalid delegated authorizer CA bundle, no PEM certificate found")
+			return authorizer
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: certPool, MinVersion: tls.VersionTLS12}
+	}
+	authorizer.client = &http.Client{Transport: transport, Timeout: timeout}
+	return authorizer
+}
+
+// authorize asks the delegated authorizer whether the input clone is allowed
+func (a *delegatedAuthorizer) authorize(input delegatedAuthorizationInput) (bool, string, error) {
+	if a.err != nil {
+		return false, "", a.err
+	}
+	input.Operation = "clone"
+	body, err := json.Marshal(&delegatedAuthorizationRequest{Input: input})
+	if err != nil {
+		return false, "", err
+	}
+	klog.V(3).Infof("Sending delegated clone authorization request %s", body)
+	resp, err := a.client.Post(a.config.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return false, "", errors.Wrap(err, "delegated clone authorization request failed")
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxDelegatedAuthorizerResponseSize))
+	if err != nil {
+		return false, "", errors.Wrap(err, "could not read the delegated clone authorization response")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, "", errors.Errorf("delegated clone authorization request failed with %s", resp.Status)
+	}
+	klog.V(3).Infof("Delegated clone authorization response %s", respBody)
+
+	response := &delegatedAuthorizationResponse{}
+	if err := json.Unmarshal(respBody, response); err != nil {
+		return false, "", errors.Wrap(err, "invalid delegated clone authorization response")
+	}
+	decision := delegatedAuthorizationDecision{}
+	if len(response.Result) > 0 && json.Unmarshal(response.Result, &decision.Allowed) != nil {
+		if err := json.Unmarshal(response.Result, &decision); err != nil {
+			return false, "", errors.Errorf("invalid delegated clone authorization result %s, expecting a boolean or an object with an allowed field", response.Result)
+		}
+	}
+	if !decision.Allowed {
+		reason := fmt.Sprintf("User %s is not allowed to clone from namespace %s by the delegated authorizer", input.User.Username, input.Source.Namespace)
+		if decision.Reason != "" {
+			reason = fmt.Sprintf("%s: %s", reason, decision.Reason)
+		}
+		return false, reason, nil
+	}
+	return true, "", nil
+}
+
+// userCloneAuthFunc returns the user clone auth func asking the delegated authorizer about the cross-namespace clones
+// from a source of the kind
+func (a *delegatedAuthorizer) userCloneAuthFunc(sourceKind string) UserCloneAuthFunc {
+	return func(_ SubjectAccessReviewsProxy, sourceNamespace, name, targetNamespace string, userInfo authentication.UserInfo) (bool, string, error) {
+		if sourceNamespace == targetNamespace {
+			return true, "", nil
+		}
+		var extra map[string][]string
+		if len(userInfo.Extra) > 0 {
+			extra = make(map[string][]string)
+			for k, v := range userInfo.Extra {
+				extra[k] = v
+			}
+		}
+		return a.authorize(delegatedAuthorizationInput{
+			Source:          delegatedAuthorizationSource{Kind: sourceKind, Namespace: sourceNamespace, Name: name},
+			TargetNamespace: targetNamespace,
+			User: delegatedAuthorizationUser{
+				Username: userInfo.Username,
+				UID:      userInfo.UID,
+				Groups:   userInfo.Groups,
+				Extra:    extra,
+			},
+		})
+	}
+}
+
+// serviceAccountCloneAuthFunc returns the ServiceAccount clone auth func asking the delegated authorizer about the
+// cross-namespace clones from a source of the kind
+func (a *delegatedAuthorizer) serviceAccountCloneAuthFunc(sourceKind string) ServiceAccountCloneAuthFunc {
+	return func(_ SubjectAccessReviewsProxy, pvcNamespace, pvcName, saNamespace, saName string) (bool, string, error) {
+		if pvcNamespace == saNamespace {
+			return true, "", nil
+		}
+		return a.authorize(delegatedAuthorizationInput{
+			Source:          delegatedAuthorizationSource{Kind: sourceKind, Namespace: pvcNamespace, Name: pvcName},
+			TargetNamespace: saNamespace,
+			User: delegatedAuthorizationUser{
+				Username: fmt.Sprintf("system:serviceaccount:%s:%s", saNamespace, saName),
+				Groups: []string{
+					"system:serviceaccounts",
+					"system:serviceaccounts:" + saNamespace,
+					"system:authenticated",
+				},
+			},
+			ServiceAccount: &delegatedAuthorizationSource{Namespace: saNamespace, Name: saName},
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package clone_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	authentication "k8s.io/api/authentication/v1"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/clone"
+)
+
+var _ = Describe("Clone delegated authorizer", func() {
+	var (
+		proxy    *fakeProxy
+		ts       *httptest.Server
+		inputs   []map[string]interface{}
+		response string
+		status   int
+		userInfo = authentication.UserInfo{Username: "user", Groups: []string{"devs"}}
+	)
+
+	setAuthorizer := func(mode cdiv1.DelegatedAuthorizerMode) {
+		Expect(clone.SetClusterDelegatedAuthorizer(&cdiv1.ClusterDelegatedAuthorizer{URL: ts.URL + "/v1/data/cdi/clone", Mode: mode})).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		proxy = &fakeProxy{allowed: true}
+		inputs = nil
+		response = `{"result": true}`
+		status = http.StatusOK
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			Expect(r.Method).To(Equal(http.MethodPost))
+			Expect(r.URL.Path).To(Equal("/v1/data/cdi/clone"))
+			request := map[string]map[string]interface{}{}
+			Expect(json.NewDecoder(r.Body).Decode(&request)).To(Succeed())
+			inputs = append(inputs, request["input"])
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(response))
+		}))
+	})
+
+	AfterEach(func() {
+		Expect(clone.SetClusterDelegatedAuthorizer(nil)).To(Succeed())
+		ts.Close()
+	})
+
+	It("should send the clone as the input of the delegated authorizer", func() {
+		setAuthorizer(cdiv1.DelegatedAuthorizerModeAdditional)
+		allowed, _, err := clone.CanUserClonePVC(proxy, "source", "pvc", "target", userInfo)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allowed).To(BeTrue())
+		Expect(inputs).To(HaveLen(1))
+		Expect(inputs[0]).To(Equal(map[string]interface{}{
+			"operation":       "clone",
+			"source":          map[string]interface{}{"kind": clone.SourceKindPVC, "namespace": "source", "name": "pvc"},
+			"targetNamespace": "target",
+			"user":            map[string]interface{}{"username": "user", "groups": []interface{}{"devs"}},
+		}))
+	})
+
+	It("should deny a clone allowed by RBAC when the delegated authorizer denies it in Additional mode", func() {
+		setAuthorizer(cdiv1.DelegatedAuthorizerModeAdditional)
+		response = `{"result": {"allowed": false, "reason": "source namespace is restricted"}}`
+		allowed, reason, err := clone.CanUserCloneSnapshot(proxy, "source", "snapshot", "target", userInfo)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allowed).To(BeFalse())
+		Expect(reason).To(ContainSubstring("source namespace is restricted"))
+		Expect(proxy.reviews).ToNot(BeZero())
+		Expect(inputs[0]["source"]).To(HaveKeyWithValue("kind", clone.SourceKindSnapshot))
+	})
+
+	It("should not ask the delegated authorizer when RBAC denies the clone in Additional mode", func() {
+		setAuthorizer(cdiv1.DelegatedAuthorizerModeAdditional)
+		proxy.allowed = false
+		allowed, _, err := clone.CanUserClonePVC(proxy, "source", "pvc", "target", userInfo)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allowed).To(BeFalse())
+		Expect(inputs).To(BeEmpty())
+	})
+
+	It("should decide the clone without RBAC in Replace mode", func() {
+		setAuthorizer(cdiv1.DelegatedAuthorizerModeReplace)
+		proxy.allowed = false
+		allowed, _, err := clone.CanServiceAccountClonePVC(proxy, "source", "pvc", "target", "sa")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allowed).To(BeTrue())
+		Expect(proxy.reviews).To(BeZero())
+		Expect(inputs).To(HaveLen(1))
+		Expect(inputs[0]["user"]).To(HaveKeyWithValue("username", "system:serviceaccount:target:sa"))
+		Expect(inputs[0]["serviceAccount"]).To(Equal(map[string]interface{}{"namespace": "target", "name": "sa"}))
+	})
+
+	It("should not ask the delegated authorizer about a clone within a namespace", func() {
+		setAuthorizer(cdiv1.DelegatedAuthorizerModeReplace)
+		allowed, _, err := clone.CanUserClonePVC(proxy, "target", "pvc", "target", userInfo)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allowed).To(BeTrue())
+		Expect(inputs).To(BeEmpty())
+	})
+
+	It("should deny the clone on an undefined decision", func() {
+		setAuthorizer(cdiv1.DelegatedAuthorizerModeReplace)
+		response = `{}`
+		allowed, _, err := clone.CanUserClonePVC(proxy, "source", "pvc", "target", userInfo)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allowed).To(BeFalse())
+	})
+
+	It("should fail the clone when the delegated authorizer fails", func() {
+		setAuthorizer(cdiv1.DelegatedAuthorizerModeReplace)
+		status = http.StatusInternalServerError
+		allowed, _, err := clone.CanUserClonePVC(proxy, "source", "pvc", "target", userInfo)
+		Expect(err).To(MatchError(ContainSubstring("500 Internal Server Error")))
+		Expect(allowed).To(BeFalse())
+	})
+
+	It("should fail the cross-namespace clones while the config is invalid", func() {
+		err := clone.SetClusterDelegatedAuthorizer(&cdiv1.ClusterDelegatedAuthorizer{URL: "ftp://authorizer", Mode: cdiv1.DelegatedAuthorizerModeReplace})
+		Expect(err).To(HaveOccurred())
+		allowed, _, err := clone.CanUserClonePVC(proxy, "source", "pvc", "target", userInfo)
+		Expect(err).To(HaveOccurred())
+		Expect(allowed).To(BeFalse())
+		allowed, _, err = clone.CanUserClonePVC(proxy, "target", "pvc", "target", userInfo)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allowed).To(BeTrue())
+	})
+})
@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clone
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	authorization "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/klog/v2"
+)
+
+// subjectAccessReviewCacheSize is the number of SubjectAccessReview decisions cached, the least recently used ones
+// being evicted first
+const subjectAccessReviewCacheSize = 4096
+
+var (
+	subjectAccessReviewCacheLock sync.RWMutex
+	subjectAccessReviewCache     *cache.LRUExpireCache
+	subjectAccessReviewCacheTTL  time.Duration
+)
+
+// setSubjectAccessReviewCacheTTL caches the SubjectAccessReview decisions for the ttl, zero disabling the cache. The
+// cached decisions are dropped when the ttl changes.
+func setSubjectAccessReviewCacheTTL(ttl time.Duration) {
+	subjectAccessReviewCacheLock.Lock()
+	defer subjectAccessReviewCacheLock.Unlock()
+	if ttl == subjectAccessReviewCacheTTL {
+		return
+	}
+	subjectAccessReviewCacheTTL = ttl
+	subjectAccessReviewCache = nil
+	if ttl > 0 {
+		subjectAccessReviewCache = cache.NewLRUExpireCache(subjectAccessReviewCacheSize)
+	}
+}
+
+func getSubjectAccessReviewCache() (*cache.LRUExpireCache, time.Duration) {
+	subjectAccessReviewCacheLock.RLock()
+	defer subjectAccessReviewCacheLock.RUnlock()
+	return subjectAccessReviewCache, subjectAccessReviewCacheTTL
+}
+
+// cachedSubjectAccessReviewsProxy sends the SubjectAccessReviews whose decisions are not cached
+type cachedSubjectAccessReviewsProxy struct {
+	client    SubjectAccessReviewsProxy
+	decisions *cache.LRUExpireCache
+	ttl       time.Duration
+}
+
+// withSubjectAccessReviewCache returns the client caching the SubjectAccessReview decisions when the cache is enabled
+func withSubjectAccessReviewCache(client SubjectAccessReviewsProxy) SubjectAccessReviewsProxy {
+	decisions, ttl := getSubjectAccessReviewCache()
+	if decisions == nil {
+		return client
+	}
+	return &cachedSubjectAccessReviewsProxy{client: client, decisions: decisions, ttl: ttl}
+}
+
+// Create sends the SubjectAccessReview, unless the decision of the same review, for the same user and resource
+// attributes, is cached. A decision failing to evaluate is not cached.
+func (p *cachedSubjectAccessReviewsProxy) Create(sar *authorization.SubjectAccessReview) (*authorization.SubjectAccessReview, error) {
+	key, err := json.Marshal(&sar.Spec)
+	if err != nil {
+		return p.client.Create(sar)
+	}
+	if status, found := p.decisions.Get(string(key)); found {
+		klog.V(3).Infof("Using the cached SubjectAccessReview decision %+v", status)
+		response := sar.DeepCopy()
+		response.Status = status.(authorization.SubjectAccessReviewStatus)
+		return response, nil
+	}
+
+	response, err := p.client.Create(sar)
+	if err != nil {
+		return nil, err
+	}
+	if response.Status.EvaluationError == "" {
+		p.decisions.Add(string(key), response.Status, p.ttl)
+	}
+	return response, nil
+}
@@ -68,10 +68,21 @@ const (
 	ImporterDataDir = "/data"
 	// ScratchDataDir provides a constant for the controller pkg to use as a hardcoded path to where scratch space is located.
 	ScratchDataDir = "/scratch"
+	// CloneCheckpointDir provides a constant for the directory where the upload server of a clone target keeps the checkpoint of the clone stream
+	CloneCheckpointDir = "/var/run/cdi/clone-checkpoint"
+	// TargetCheckDir provides a constant for the directory where a worker records it found its adopted target volume empty
+	TargetCheckDir = "/var/run/cdi/target-check"
 	// ImporterS3Host provides an S3 string used by importer/dataStream.go only
 	ImporterS3Host = "s3.amazonaws.com"
 	// ImporterCertDir is where the configmap containing certs will be mounted
 	ImporterCertDir = "/certs"
+	// ImporterInlineSourceDir is where the configmap containing the image of an inline source will be mounted
+	ImporterInlineSourceDir = "/inline-source"
+	// InlineSourceDataKey is the key of the base64 encoded image in the configmap of an inline source
+	InlineSourceDataKey = "data"
+	// InlineSourceMaxSize is the maximum size in bytes of the base64 encoded image of an inline source, keeping the
+	// DataVolume and the configmap holding the image well below the etcd object size limit
+	InlineSourceMaxSize = 1024 * 1024
 	// DefaultPullPolicy imports k8s "IfNotPresent" string for the import_controller_gingko_test and the cdi-controller executable
 	DefaultPullPolicy = string(v1.PullIfNotPresent)
 	// ImportProxyConfigMapName provides the key for getting the name of the ConfigMap in the cdi namespace containing a CA certificate bundle
@@ -123,12 +134,42 @@ const (
 	ImporterFinalCheckpoint = "IMPORTER_FINAL_CHECKPOINT"
 	// Preallocation provides a constant to capture out env variable "PREALLOCATION"
 	Preallocation = "PREALLOCATION"
+	// ClonerSourcePath provides a constant to capture our env variable "CLONER_SOURCE_PATH"
+	ClonerSourcePath = "CLONER_SOURCE_PATH"
+	// ClonerUploadToken provides a constant to capture our env variable "CLONER_UPLOAD_TOKEN"
+	ClonerUploadToken = "CLONER_UPLOAD_TOKEN"
+	// LogFormat provides a constant to capture our env variable "LOG_FORMAT", the format of the logs, text or json
+	LogFormat = "LOG_FORMAT"
+	// LogNamespace provides a constant to capture our env variable "LOG_NAMESPACE", added to every structured log line
+	LogNamespace = "LOG_NAMESPACE"
+	// LogDataVolume provides a constant to capture our env variable "LOG_DATAVOLUME", added to every structured log line
+	LogDataVolume = "LOG_DATAVOLUME"
+	// IOMaxBytesPerSecond provides a constant to capture our env variable "IO_MAX_BYTES_PER_SECOND", the disk throughput limit of the pod
+	IOMaxBytesPerSecond = "IO_MAX_BYTES_PER_SECOND"
+	// IOMaxIOPS provides a constant to capture our env variable "IO_MAX_IOPS", the disk IO operations limit of the pod
+	IOMaxIOPS = "IO_MAX_IOPS"
+	// IOWeight provides a constant to capture our env variable "IO_WEIGHT", the proportional disk IO weight of the pod
+	IOWeight = "IO_WEIGHT"
+	// ClonerBandwidthLimit provides a constant to capture our env variable "CLONER_BANDWIDTH_LIMIT", the bytes per second the clone source streams at most
+	ClonerBandwidthLimit = "CLONER_BANDWIDTH_LIMIT"
+	// ClonerStreams provides a constant to capture our env variable "CLONER_STREAMS", the number of parallel streams the clone source splits a block disk into
+	ClonerStreams = "CLONER_STREAMS"
+	// ClonerCompression provides a constant to capture our env variable "CLONER_COMPRESSION", the compression codec the clone source asks the target for
+	ClonerCompression = "CLONER_COMPRESSION"
+	// ClonerIncremental provides a constant to capture our env variable "CLONER_INCREMENTAL", set when the clone source only streams the blocks changed since the target was cloned
+	ClonerIncremental = "CLONER_INCREMENTAL"
 	// ImportProxyHTTP provides a constant to capture our env variable "http_proxy"
 	ImportProxyHTTP = "http_proxy"
 	// ImportProxyHTTPS provides a constant to capture our env variable "https_proxy"
 	ImportProxyHTTPS = "https_proxy"
 	// ImportProxyNoProxy provides a constant to capture our env variable "no_proxy"
 	ImportProxyNoProxy = "no_proxy"
+	// ImportProxyAuthSecretName provides the key for getting the name of the Secret in the cdi namespace containing the proxy credentials
+	ImportProxyAuthSecretName = "proxy-auth-secret"
+	// ImportProxyUsername provides a constant to capture our env variable "IMPORT_PROXY_USERNAME"
+	ImportProxyUsername = "IMPORT_PROXY_USERNAME"
+	// ImportProxyPassword provides a constant to capture our env variable "IMPORT_PROXY_PASSWORD"
+	ImportProxyPassword = "IMPORT_PROXY_PASSWORD"
 	// ImporterProxyCertDirVar provides a constant to capture our env variable "IMPORTER_PROXY_CERT_DIR"
 	ImporterProxyCertDirVar = "IMPORTER_PROXY_CERT_DIR"
 	// InstallerPartOfLabel provides a constant to capture our env variable "INSTALLER_PART_OF_LABEL"
@@ -139,6 +180,66 @@ const (
 	ImporterExtraHeader = "IMPORTER_EXTRA_HEADER_"
 	// ImporterSecretExtraHeadersDir is where the secrets containing extra HTTP headers will be mounted
 	ImporterSecretExtraHeadersDir = "/extraheaders"
+	// ImporterMultipartParallelism provides a constant to capture our env variable "IMPORTER_MULTIPART_PARALLELISM"
+	ImporterMultipartParallelism = "IMPORTER_MULTIPART_PARALLELISM"
+	// ImporterMultipartPartSize provides a constant to capture our env variable "IMPORTER_MULTIPART_PART_SIZE"
+	ImporterMultipartPartSize = "IMPORTER_MULTIPART_PART_SIZE"
+	// ImporterS3KMSKeyID provides a constant to capture our env variable "IMPORTER_S3_KMS_KEY_ID"
+	ImporterS3KMSKeyID = "IMPORTER_S3_KMS_KEY_ID"
+	// ImporterVerifyOnly provides a constant to capture our env variable "IMPORTER_VERIFY_ONLY"
+	ImporterVerifyOnly = "IMPORTER_VERIFY_ONLY"
+	// TargetMustBeEmpty provides a constant to capture our env variable "TARGET_MUST_BE_EMPTY"
+	TargetMustBeEmpty = "TARGET_MUST_BE_EMPTY"
+	// ImporterScratchSpaceLimit provides a constant to capture our env variable "IMPORTER_SCRATCH_SPACE_LIMIT"
+	ImporterScratchSpaceLimit = "IMPORTER_SCRATCH_SPACE_LIMIT"
+	// ImporterShrinkToUsedSize provides a constant to capture our env variable "IMPORTER_SHRINK_TO_USED_SIZE"
+	ImporterShrinkToUsedSize = "IMPORTER_SHRINK_TO_USED_SIZE"
+	// ImporterTargetFormat provides a constant to capture our env variable "IMPORTER_TARGET_FORMAT"
+	ImporterTargetFormat = "IMPORTER_TARGET_FORMAT"
+	// ImporterTargetCompression provides a constant to capture our env variable "IMPORTER_TARGET_COMPRESSION"
+	ImporterTargetCompression = "IMPORTER_TARGET_COMPRESSION"
+	// ImporterSourceETag provides a constant to capture our env variable "IMPORTER_SOURCE_ETAG"
+	ImporterSourceETag = "IMPORTER_SOURCE_ETAG"
+	// ImporterSourceLastModified provides a constant to capture our env variable "IMPORTER_SOURCE_LAST_MODIFIED"
+	ImporterSourceLastModified = "IMPORTER_SOURCE_LAST_MODIFIED"
+	// ImporterChangedRangesURL provides a constant to capture our env variable "IMPORTER_CHANGED_RANGES_URL"
+	ImporterChangedRangesURL = "IMPORTER_CHANGED_RANGES_URL"
+	// ImporterChecksumManifestURL provides a constant to capture our env variable "IMPORTER_CHECKSUM_MANIFEST_URL"
+	ImporterChecksumManifestURL = "IMPORTER_CHECKSUM_MANIFEST_URL"
+	// ImporterSourceDigestAlgorithm provides a constant to capture our env variable "IMPORTER_SOURCE_DIGEST_ALGORITHM"
+	ImporterSourceDigestAlgorithm = "IMPORTER_SOURCE_DIGEST_ALGORITHM"
+	// ImporterEncryptionKeyFile provides a constant to capture our env variable "IMPORTER_ENCRYPTION_KEY_FILE"
+	ImporterEncryptionKeyFile = "IMPORTER_ENCRYPTION_KEY_FILE"
+	// ImporterTarMember provides a constant to capture our env variable "IMPORTER_TAR_MEMBER"
+	ImporterTarMember = "IMPORTER_TAR_MEMBER"
+	// ImporterFreeSpaceMargin provides a constant to capture our env variable "IMPORTER_FREE_SPACE_MARGIN"
+	ImporterFreeSpaceMargin = "IMPORTER_FREE_SPACE_MARGIN"
+	// ImporterMaxCopyBufferSize provides a constant to capture our env variable "IMPORTER_MAX_COPY_BUFFER_SIZE"
+	ImporterMaxCopyBufferSize = "IMPORTER_MAX_COPY_BUFFER_SIZE"
+	// ImporterBearerToken provides a constant to capture our env variable "IMPORTER_BEARER_TOKEN"
+	ImporterBearerToken = "IMPORTER_BEARER_TOKEN"
+	// ImporterRegistryDiskPath provides a constant to capture our env variable "IMPORTER_REGISTRY_DISK_PATH"
+	ImporterRegistryDiskPath = "IMPORTER_REGISTRY_DISK_PATH"
+	// ImporterGcsUserProject provides a constant to capture our env variable "IMPORTER_GCS_USER_PROJECT"
+	ImporterGcsUserProject = "IMPORTER_GCS_USER_PROJECT"
+	// ImporterGcsGeneration provides a constant to capture our env variable "IMPORTER_GCS_GENERATION"
+	ImporterGcsGeneration = "IMPORTER_GCS_GENERATION"
+	// ImporterSFTPHostKey provides a constant to capture our env variable "IMPORTER_SFTP_HOST_KEY"
+	ImporterSFTPHostKey = "IMPORTER_SFTP_HOST_KEY"
+	// ImporterTokenFile provides a constant to capture our env variable "IMPORTER_TOKEN_FILE"
+	ImporterTokenFile = "IMPORTER_TOKEN_FILE"
+	// ImporterTokenAudience provides a constant to capture our env variable "IMPORTER_TOKEN_AUDIENCE"
+	ImporterTokenAudience = "IMPORTER_TOKEN_AUDIENCE"
+	// ImporterTokenRoleARN provides a constant to capture our env variable "IMPORTER_TOKEN_ROLE_ARN"
+	ImporterTokenRoleARN = "IMPORTER_TOKEN_ROLE_ARN"
+	// ImporterTokenServiceAccount provides a constant to capture our env variable "IMPORTER_TOKEN_SERVICE_ACCOUNT"
+	ImporterTokenServiceAccount = "IMPORTER_TOKEN_SERVICE_ACCOUNT"
+	// ImporterTokenDir is where the projected service account token used to get the source credentials is mounted
+	ImporterTokenDir = "/var/run/secrets/cdi/token"
+	// ImporterTokenPath is the projected service account token file
+	ImporterTokenPath = ImporterTokenDir + "/token"
+	// DefaultS3TokenAudience is the audience of the projected service account token exchanged with AWS STS
+	DefaultS3TokenAudience = "sts.amazonaws.com"
 
 	// ImporterGoogleCredentialFileVar provides a constant to capture our env variable "GOOGLE_APPLICATION_CREDENTIALS"
 	ImporterGoogleCredentialFileVar = "GOOGLE_APPLICATION_CREDENTIALS"
@@ -146,6 +247,20 @@ const (
 	ImporterGoogleCredentialDir = "/google"
 	// ImporterGoogleCredentialFile provides a constant to capture our credentials.json file
 	ImporterGoogleCredentialFile = "/google/credentials.json"
+	// ImporterEncryptionKeyDir is where the secret holding the passphrase of a LUKS target will be mounted
+	ImporterEncryptionKeyDir = "/encryption-key"
+	// EncryptionPassphraseKey is the key of the passphrase of a LUKS target in its secret
+	EncryptionPassphraseKey = "passphrase"
+	// ImporterNbdCertDir is where the secret containing the TLS credentials of an NBD source will be mounted
+	ImporterNbdCertDir = "/nbd-certs"
+	// ImporterRegistryCredentialDir is where the secret containing the credentials of a registry source will be mounted
+	ImporterRegistryCredentialDir = "/registry-credentials"
+	// ImporterAzureCredentialDir is where the secret containing the credentials of an Azure Blob source will be mounted
+	ImporterAzureCredentialDir = "/azure-credentials"
+	// ImporterSFTPCredentialDir is where the secret containing the credentials of an SFTP source will be mounted
+	ImporterSFTPCredentialDir = "/sftp-credentials"
+	// ImporterRegistryCredentialDirVar provides a constant to capture our env variable "IMPORTER_REGISTRY_CREDENTIAL_DIR"
+	ImporterRegistryCredentialDirVar = "IMPORTER_REGISTRY_CREDENTIAL_DIR"
 
 	// CloningLabelValue provides a constant to use as a label value for pod affinity (controller pkg only)
 	CloningLabelValue = "host-assisted-cloning"
@@ -181,6 +296,14 @@ const (
 	UploadServerServiceLabel = "service"
 	// UploadImageSize provides a constant to capture our env variable "UPLOAD_IMAGE_SIZE"
 	UploadImageSize = "UPLOAD_IMAGE_SIZE"
+	// UploadTargetFormat provides a constant to capture our env variable "UPLOAD_TARGET_FORMAT"
+	UploadTargetFormat = "UPLOAD_TARGET_FORMAT"
+	// UploadTargetCompression provides a constant to capture our env variable "UPLOAD_TARGET_COMPRESSION"
+	UploadTargetCompression = "UPLOAD_TARGET_COMPRESSION"
+	// UploadQcow2ClusterSize provides a constant to capture our env variable "UPLOAD_QCOW2_CLUSTER_SIZE"
+	UploadQcow2ClusterSize = "UPLOAD_QCOW2_CLUSTER_SIZE"
+	// UploadCompact provides a constant to capture our env variable "UPLOAD_COMPACT"
+	UploadCompact = "UPLOAD_COMPACT"
 
 	// FilesystemOverheadVar provides a constant to capture our env variable "FILESYSTEM_OVERHEAD"
 	FilesystemOverheadVar = "FILESYSTEM_OVERHEAD"
@@ -197,6 +320,29 @@ const (
 	KeyAccess = "accessKeyId"
 	// KeySecret provides a constant to the secretKey label using in controller pkg and transport_test.go
 	KeySecret = "secretKey"
+	// KeyToken provides a constant to the token key of the secret authorizing a remote clone source
+	KeyToken = "token"
+	// KeyKubeconfig provides a constant to the kubeconfig key of the secret of a remote PVC clone source
+	KeyKubeconfig = "kubeconfig"
+	// KeyAzureSASToken provides a constant to the sasToken key of the secret of an Azure Blob source
+	KeyAzureSASToken = "sasToken"
+	// KeyAzureTenantID provides a constant to the tenantId key of the service principal secret of an Azure Blob source
+	KeyAzureTenantID = "tenantId"
+	// KeyAzureClientID provides a constant to the clientId key of the service principal secret of an Azure Blob source
+	KeyAzureClientID = "clientId"
+	// KeyAzureClientSecret provides a constant to the clientSecret key of the service principal secret of an Azure Blob
+	// source
+	KeyAzureClientSecret = "clientSecret"
+	// KeySFTPUsername provides a constant to the username key of the secret of an SFTP source
+	KeySFTPUsername = "username"
+	// KeySFTPPassword provides a constant to the password key of the secret of an SFTP source
+	KeySFTPPassword = "password"
+	// KeySFTPPrivateKey provides a constant to the ssh-privatekey key of the secret of an SFTP source, the one of the
+	// kubernetes.io/ssh-auth secrets
+	KeySFTPPrivateKey = "ssh-privatekey"
+	// KeySFTPPassphrase provides a constant to the passphrase key of the secret of an SFTP source, decrypting its
+	// private key
+	KeySFTPPassphrase = "passphrase"
 
 	// DefaultResyncPeriod sets a 10 minute resync period, used in the controller pkg and the controller cmd executable
 	DefaultResyncPeriod = 10 * time.Minute
@@ -243,6 +389,43 @@ const (
 	// BlockdeviceClone is the content type when cloning a block device
 	BlockdeviceClone = "blockdevice-clone"
 
+	// CloneOffsetHeader is the header a clone source sets to the offset it resumes a raw clone stream from
+	CloneOffsetHeader = "x-cdi-clone-offset"
+
+	// CloneSHA256Trailer is the trailer a clone source sets to the SHA-256 of the disk it streamed, which the target
+	// verifies against the disk it received
+	CloneSHA256Trailer = "X-Cdi-Clone-Sha256"
+
+	// CloneRangeHeader is the header a clone source streaming a raw disk over parallel streams sets to the range of the
+	// disk a stream holds, as "<start>-<end>/<size>" with the end offset excluded. The SHA-256 trailer of the stream is
+	// the one of its range.
+	CloneRangeHeader = "x-cdi-clone-range"
+
+	// CloneIncrementalHeader is the header an incremental clone source sets to the size of the disk on the ranges it
+	// streams, which only cover the changed blocks, and on the request ending the clone once they are written. The
+	// SHA-256 trailer of the ending request is the one of the whole disk.
+	CloneIncrementalHeader = "x-cdi-clone-incremental"
+
+	// MaxCloneStreams is the highest number of parallel streams of a host-assisted clone
+	MaxCloneStreams = 16
+
+	// CloneCompressionHeader is the header a clone source sets to the compression codec of its stream, a stream without
+	// it is compressed with snappy
+	CloneCompressionHeader = "x-cdi-clone-compression"
+
+	// CloneCompressionAcceptHeader is the header the upload server answers a HEAD request with, listing the compression
+	// codecs of the clone streams it accepts
+	CloneCompressionAcceptHeader = "x-cdi-clone-compression-accept"
+
+	// CloneCompressionNone streams a host-assisted clone uncompressed
+	CloneCompressionNone = "none"
+	// CloneCompressionGzip compresses the stream of a host-assisted clone with gzip
+	CloneCompressionGzip = "gzip"
+	// CloneCompressionSnappy compresses the stream of a host-assisted clone with snappy, the default
+	CloneCompressionSnappy = "snappy"
+	// CloneCompressionZstd compresses the stream of a host-assisted clone with zstd
+	CloneCompressionZstd = "zstd"
+
 	// UploadPathSync is the path to POST CDI uploads
 	UploadPathSync = "/v1beta1/upload"
 
@@ -261,8 +444,66 @@ const (
 	// UploadFormAsync is the path to POST CDI uploads as form data in async mode
 	UploadFormAsync = "/v1beta1/upload-form-async"
 
+	// UploadPathCloneCheckpoint is the path to GET the checkpoint a clone source may resume a raw clone stream from
+	UploadPathCloneCheckpoint = "/v1beta1/clone-checkpoint"
+
+	// UploadPathCloneBlocks is the path to GET the checksums of the blocks of the target an incremental clone compares
+	UploadPathCloneBlocks = "/v1beta1/clone-blocks"
+
 	// PreallocationApplied is a string inserted into importer's/uploader's exit message
 	PreallocationApplied = "Preallocation applied"
+	// PassthroughApplied is a string inserted into importer's exit message when the image already had the target
+	// format and was copied without conversion
+	PassthroughApplied = "Passthrough applied"
+
+	// SourceNotModified is a string inserted into importer's exit message when the source was unchanged since the
+	// previous import, and the existing content of the target was kept instead of transferring it again
+	SourceNotModified = "Source not modified"
+
+	// SourceValidatorsPrefix prefixes the JSON validators of the source in the importer's exit message
+	SourceValidatorsPrefix = "Source: "
+
+	// ImageInfoPrefix prefixes the JSON image info in the importer's/cloner's exit message
+	ImageInfoPrefix = "Image: "
+
+	// ImportTimingsPrefix prefixes the JSON import phase timings in the importer's exit message
+	ImportTimingsPrefix = "Timings: "
+
+	// TargetImageInfoPrefix prefixes the JSON info of the image written to the target in the importer's exit message
+	TargetImageInfoPrefix = "Target: "
+
+	// SourceDigestPrefix prefixes the JSON digest of the source bytes in the importer's exit message
+	SourceDigestPrefix = "Digest: "
+
+	// ImportTargetFormatRaw is the default format of the image written to the target of an import
+	ImportTargetFormatRaw = "raw"
+	// ImportTargetFormatQcow2 is the qcow2 format of the image written to the target of an import
+	ImportTargetFormatQcow2 = "qcow2"
+	// ImportTargetFormatLuks is the format of a raw image encrypted in a LUKS container written to the target of an import
+	ImportTargetFormatLuks = "luks"
+	// ImportTargetCompressionZlib is the zlib (deflate, as used by gzip) compression of the clusters of a qcow2 target
+	ImportTargetCompressionZlib = "zlib"
+	// ImportTargetCompressionZstd is the zstd compression of the clusters of a qcow2 target
+	ImportTargetCompressionZstd = "zstd"
+	// SourceDigestSHA256 is the SHA-256 digest algorithm of the source bytes of an import
+	SourceDigestSHA256 = "sha256"
+	// SourceDigestSHA512 is the SHA-512 digest algorithm of the source bytes of an import
+	SourceDigestSHA512 = "sha512"
+
+	// S3KMSAccessDeniedMessage is a string inserted into importer's exit message when S3 denies the use of the KMS key of the object
+	S3KMSAccessDeniedMessage = "Access denied to the KMS key of the S3 object"
+	// ScratchSpaceExhaustedMessage is a string inserted into importer's exit message when the scratch space ran out
+	ScratchSpaceExhaustedMessage = "Scratch space exhausted"
+	// CloneChecksumMismatchMessage is a string inserted into cloner's exit message when the target received data not
+	// matching the SHA-256 of the source
+	CloneChecksumMismatchMessage = "Clone checksum mismatch"
+	// UnsupportedFormatMessage is a string inserted into importer's exit message when qemu-img does not support the format of the image
+	UnsupportedFormatMessage = "Unsupported image format"
+	// QemuImgUnavailableMessage is a string inserted into importer's exit message when qemu-img cannot be run
+	QemuImgUnavailableMessage = "qemu-img is unavailable"
+
+	// SourceVerifiedMessage is the prefix of importer's exit message when it verified the source of a verify-only import
+	SourceVerifiedMessage = "Source verified"
 
 	// SecretHeader is the key in a secret containing a sensitive extra header for HTTP data sources
 	SecretHeader = "secretHeader"
@@ -37,6 +37,8 @@ import (
 	"kubevirt.io/containerized-data-importer/pkg/util"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert/fetcher"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert/generator"
+	"kubevirt.io/containerized-data-importer/pkg/util/cgroup"
+	"kubevirt.io/containerized-data-importer/pkg/util/logging"
 )
 
 const (
@@ -55,6 +57,19 @@ const (
 	// CloneSucceededPVC provides a const to indicate a clone to the PVC succeeded
 	CloneSucceededPVC = "CloneSucceeded"
 
+	// CloneImageAnnotationMismatch provides a const to indicate an image annotation of the clone source does not match
+	// the cloned content, and was not copied to the target
+	CloneImageAnnotationMismatch = "CloneImageAnnotationMismatch"
+
+	// CloneChecksumMismatch provides a const to indicate the target of a clone received data not matching the SHA-256
+	// of the source
+	CloneChecksumMismatch = "CloneChecksumMismatch"
+
+	// MessageCloneImageVirtualSizeMismatch provides a const to form the clone image virtual size mismatch message
+	MessageCloneImageVirtualSizeMismatch = "Image virtual size %s recorded on the source PVC is larger than the %d bytes of the cloned disk, not copying it"
+	// MessageCloneImageVirtualSizeInvalid provides a const to form the clone image virtual size invalid message
+	MessageCloneImageVirtualSizeInvalid = "Image virtual size %q recorded on the source PVC is invalid, not copying it"
+
 	cloneSourcePodFinalizer = "cdi.kubevirt.io/cloneSource"
 
 	uploadClientCertDuration = 365 * 24 * time.Hour
@@ -151,6 +166,7 @@ func addCloneControllerWatches(mgr manager.Manager, cloneController controller.C
 func (r *CloneReconciler) shouldReconcile(pvc *corev1.PersistentVolumeClaim, log logr.Logger) bool {
 	return checkPVC(pvc, cc.AnnCloneRequest, log) &&
 		!metav1.HasAnnotation(pvc.ObjectMeta, cc.AnnCloneOf) &&
+		!cc.IsTransferStopped(pvc) &&
 		isBound(pvc, log)
 }
 
@@ -171,6 +187,7 @@ func (r *CloneReconciler) Reconcile(_ context.Context, req reconcile.Request) (r
 			"checkPVC(AnnCloneRequest)", checkPVC(pvc, cc.AnnCloneRequest, log),
 			"NOT has annotation(AnnCloneOf)", !metav1.HasAnnotation(pvc.ObjectMeta, cc.AnnCloneOf),
 			"isBound", isBound(pvc, log),
+			"transfer stopped", cc.IsTransferStopped(pvc),
 			"has finalizer?", cc.HasFinalizer(pvc, cloneSourcePodFinalizer))
 		if cc.HasFinalizer(pvc, cloneSourcePodFinalizer) || pvc.DeletionTimestamp != nil {
 			// Clone completed, remove source pod and/or finalizer
@@ -327,6 +344,9 @@ func (r *CloneReconciler) updatePvcFromPod(sourcePod *corev1.Pod, pvc *corev1.Pe
 	log.V(3).Info("Pod phase for PVC", "PVC phase", pvc.Annotations[cc.AnnPodPhase])
 
 	if podSucceededFromPVC(pvc) && pvc.Annotations[cc.AnnCloneOf] != "true" && sourcePodFinished(sourcePod) {
+		if err := r.copySourceAnnotations(sourcePod, pvc, log); err != nil {
+			return err
+		}
 		log.V(1).Info("Adding CloneOf annotation to PVC")
 		pvc.Annotations[cc.AnnCloneOf] = "true"
 		r.recorder.Event(pvc, corev1.EventTypeNormal, CloneSucceededPVC, cc.CloneComplete)
@@ -340,6 +360,51 @@ func (r *CloneReconciler) updatePvcFromPod(sourcePod *corev1.Pod, pvc *corev1.Pe
 	return nil
 }
 
+// copySourceAnnotations copies the allowed annotations of the source PVC to the target once the clone succeeded,
+// without overriding the ones of the target
+func (r *CloneReconciler) copySourceAnnotations(sourcePod *corev1.Pod, targetPvc *corev1.PersistentVolumeClaim, log logr.Logger) error {
+	sourcePvc, err := r.getCloneRequestSourcePVC(targetPvc)
+	if err != nil {
+		if k8serrors.IsNotFound(errors.Cause(err)) {
+			log.V(1).Info("Clone source PVC not found, not copying its annotations")
+			return nil
+		}
+		return err
+	}
+	for _, key := range cc.GetCloneAnnotationAllowlist(r.client) {
+		value, ok := sourcePvc.Annotations[key]
+		if _, exists := targetPvc.Annotations[key]; !ok || exists {
+			continue
+		}
+		targetPvc.Annotations[key] = value
+		if key == cc.AnnImageVirtualSize {
+			r.validateImageVirtualSize(sourcePod, targetPvc)
+		}
+	}
+	// A clone converting the source, or selecting one of its disks, writes its own image
+	if _, selectsDisk := targetPvc.Annotations[cc.AnnCloneSourcePath]; !selectsDisk && !isQcow2CloneTarget(targetPvc) {
+		cc.CopyTargetImageAnnotations(sourcePvc.Annotations, targetPvc.Annotations)
+	}
+	return nil
+}
+
+// validateImageVirtualSize removes the image virtual size copied from the source PVC if it does not fit the disk
+// cloned by the source pod, as it then does not describe the cloned content
+func (r *CloneReconciler) validateImageVirtualSize(sourcePod *corev1.Pod, targetPvc *corev1.PersistentVolumeClaim) {
+	value := targetPvc.Annotations[cc.AnnImageVirtualSize]
+	virtualSize, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || virtualSize <= 0 {
+		delete(targetPvc.Annotations, cc.AnnImageVirtualSize)
+		r.recorder.Eventf(targetPvc, corev1.EventTypeWarning, CloneImageAnnotationMismatch, MessageCloneImageVirtualSizeInvalid, value)
+		return
+	}
+	clonedDisk := getImageInfoFromPod(sourcePod)
+	if clonedDisk != nil && clonedDisk.VirtualSize > 0 && virtualSize > clonedDisk.VirtualSize {
+		delete(targetPvc.Annotations, cc.AnnImageVirtualSize)
+		r.recorder.Eventf(targetPvc, corev1.EventTypeWarning, CloneImageAnnotationMismatch, MessageCloneImageVirtualSizeMismatch, value, clonedDisk.VirtualSize)
+	}
+}
+
 func sourcePodFinished(sourcePod *corev1.Pod) bool {
 	if sourcePod == nil {
 		return true
@@ -497,12 +562,32 @@ func (r *CloneReconciler) CreateCloneSourcePod(image, pullPolicy string, pvc *co
 		return nil, err
 	}
 
+	podIOLimits, err := cc.GetPodIOLimits(r.client)
+	if err != nil {
+		return nil, err
+	}
+
+	bandwidthLimit, err := cc.GetCloneBandwidthLimit(r.client, pvc)
+	if err != nil {
+		return nil, err
+	}
+
+	compression, err := cc.GetCloneCompression(r.client, pvc)
+	if err != nil {
+		return nil, err
+	}
+
 	imagePullSecrets, err := cc.GetImagePullSecrets(r.client)
 	if err != nil {
 		return nil, err
 	}
 
-	workloadNodePlacement, err := cc.GetWorkloadNodePlacement(r.client)
+	workloadNodePlacement, err := cc.GetPvcWorkerPodNodePlacement(r.client, pvc)
+	if err != nil {
+		return nil, err
+	}
+
+	image, pullPolicy, err = cc.GetPvcWorkerPodImage(r.client, pvc, image, pullPolicy)
 	if err != nil {
 		return nil, err
 	}
@@ -519,7 +604,12 @@ func (r *CloneReconciler) CreateCloneSourcePod(image, pullPolicy string, pvc *co
 		sourceVolumeMode = corev1.PersistentVolumeFilesystem
 	}
 
-	pod := MakeCloneSourcePodSpec(sourceVolumeMode, image, pullPolicy, imagePullSecrets, sourcePvcName, sourcePvcNamespace, ownerKey, serverCABundle, pvc, podResourceRequirements, workloadNodePlacement)
+	cloneStreams, err := cc.GetCloneStreams(r.client, sourcePvc, pvc)
+	if err != nil {
+		return nil, err
+	}
+
+	pod := MakeCloneSourcePodSpec(sourceVolumeMode, image, pullPolicy, imagePullSecrets, sourcePvcName, sourcePvcNamespace, ownerKey, serverCABundle, pvc, podResourceRequirements, podIOLimits, bandwidthLimit, cloneStreams, compression, workloadNodePlacement)
 	util.SetRecommendedLabels(pod, r.installerLabels, "cdi-controller")
 
 	if err := r.client.Create(context.TODO(), pod); err != nil {
@@ -534,7 +624,7 @@ func (r *CloneReconciler) CreateCloneSourcePod(image, pullPolicy string, pvc *co
 // MakeCloneSourcePodSpec creates and returns the clone source pod spec based on the target pvc.
 func MakeCloneSourcePodSpec(sourceVolumeMode corev1.PersistentVolumeMode, image, pullPolicy string, imagePullSecrets []corev1.LocalObjectReference, sourcePvcName, sourcePvcNamespace, ownerRefAnno string,
 	serverCACert []byte, targetPvc *corev1.PersistentVolumeClaim, resourceRequirements *corev1.ResourceRequirements,
-	workloadNodePlacement *sdkapi.NodePlacement) *corev1.Pod {
+	podIOLimits *cdiv1.PodIOLimits, bandwidthLimit int64, cloneStreams int32, compression string, workloadNodePlacement *sdkapi.NodePlacement) *corev1.Pod {
 
 	var ownerID string
 	cloneSourcePodName := targetPvc.Annotations[AnnCloneSourcePod]
@@ -706,10 +796,48 @@ func MakeCloneSourcePodSpec(sourceVolumeMode corev1.PersistentVolumeMode, image,
 				Value: common.ClonerMountPath,
 			},
 		}
+		sourcePath, ok := targetPvc.Annotations[cc.AnnCloneSourcePath]
+		if !ok && isQcow2CloneTarget(targetPvc) {
+			// Only the disk image can be converted, the rest of the filesystem is not cloned
+			sourcePath, ok = common.DiskImageName, true
+		}
+		if ok {
+			addVars = append(addVars, corev1.EnvVar{
+				Name:  common.ClonerSourcePath,
+				Value: sourcePath,
+			})
+		}
 	}
 
+	addVars = append(addVars, logging.PodEnv(targetPvc.Namespace, targetPvc.Name)...)
+	addVars = append(addVars, cgroup.PodEnv(podIOLimits)...)
+	if bandwidthLimit > 0 {
+		addVars = append(addVars, corev1.EnvVar{
+			Name:  common.ClonerBandwidthLimit,
+			Value: strconv.FormatInt(bandwidthLimit, 10),
+		})
+	}
+	if cloneStreams > 1 {
+		addVars = append(addVars, corev1.EnvVar{
+			Name:  common.ClonerStreams,
+			Value: strconv.Itoa(int(cloneStreams)),
+		})
+	}
+	if compression != "" {
+		addVars = append(addVars, corev1.EnvVar{
+			Name:  common.ClonerCompression,
+			Value: compression,
+		})
+	}
+	if targetPvc.Annotations[cc.AnnCloneIncremental] == "true" {
+		addVars = append(addVars, corev1.EnvVar{
+			Name:  common.ClonerIncremental,
+			Value: "true",
+		})
+	}
 	pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, addVars...)
 	setPodPvcAnnotations(pod, targetPvc)
+	cgroup.SetPodBlockIOClass(pod, podIOLimits)
 	cc.SetRestrictedSecurityContext(&pod.Spec)
 	return pod
 }
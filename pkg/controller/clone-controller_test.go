@@ -33,6 +33,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -41,6 +42,7 @@ import (
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
 	"kubevirt.io/containerized-data-importer/pkg/token"
+	"kubevirt.io/containerized-data-importer/pkg/util/cgroup"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert/fetcher"
 )
 
@@ -262,6 +264,332 @@ var _ = Describe("Clone controller reconcile loop", func() {
 		}),
 	)
 
+	DescribeTable("Should create source pod with the priority class of the target PVC", func(priorityClassName string) {
+		annotations := map[string]string{
+			cc.AnnCloneRequest:  "default/source",
+			cc.AnnPodReady:      "true",
+			cc.AnnCloneToken:    "foobaz",
+			AnnUploadClientName: "uploadclient",
+			AnnCloneSourcePod:   "default-testPvc1-source-pod"}
+		if priorityClassName != "" {
+			annotations[cc.AnnPriorityClassName] = priorityClassName
+		}
+		testPvc := cc.CreatePvc("testPvc1", "default", annotations, nil)
+		reconciler = createCloneReconciler(testPvc, cc.CreatePvc("source", "default", map[string]string{}, nil))
+		By("Setting up the match token")
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Match = "foobaz"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Name = "source"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Namespace = "default"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Params["targetNamespace"] = "default"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Params["targetName"] = "testPvc1"
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		By("Verifying source pod has the priority class")
+		sourcePod, err := reconciler.findCloneSourcePod(testPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sourcePod).ToNot(BeNil())
+		Expect(sourcePod.Spec.PriorityClassName).To(Equal(priorityClassName))
+	},
+		Entry("when the priority class is set", "p0"),
+		Entry("when the priority class is not set", ""),
+	)
+
+	DescribeTable("Should pass the clone source path to the source pod", func(annotations map[string]string, sourcePath string) {
+		annotations[cc.AnnCloneRequest] = "default/source"
+		annotations[cc.AnnPodReady] = "true"
+		annotations[cc.AnnCloneToken] = "foobaz"
+		annotations[AnnUploadClientName] = "uploadclient"
+		annotations[AnnCloneSourcePod] = "default-testPvc1-source-pod"
+		testPvc := cc.CreatePvc("testPvc1", "default", annotations, nil)
+		reconciler = createCloneReconciler(testPvc, cc.CreatePvc("source", "default", map[string]string{}, nil))
+		By("Setting up the match token")
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Match = "foobaz"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Name = "source"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Namespace = "default"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Params["targetNamespace"] = "default"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Params["targetName"] = "testPvc1"
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		By("Verifying source pod has the clone source path")
+		sourcePod, err := reconciler.findCloneSourcePod(testPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sourcePod).ToNot(BeNil())
+		Expect(sourcePod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: common.ClonerSourcePath, Value: sourcePath}))
+	},
+		Entry("when a disk is selected", map[string]string{cc.AnnCloneSourcePath: "disks/vm1.qcow2"}, "disks/vm1.qcow2"),
+		Entry("when the clone is written as qcow2", map[string]string{cc.AnnTargetFormat: "qcow2"}, common.DiskImageName),
+	)
+
+	It("Should pass the IO limits of the CDIConfig to the source pod", func() {
+		testPvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnCloneRequest:  "default/source",
+			cc.AnnPodReady:      "true",
+			cc.AnnCloneToken:    "foobaz",
+			AnnUploadClientName: "uploadclient",
+			AnnCloneSourcePod:   "default-testPvc1-source-pod"}, nil)
+		reconciler = createCloneReconciler(testPvc, cc.CreatePvc("source", "default", map[string]string{}, nil))
+		cdiConfig := &cdiv1.CDIConfig{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+		maxBytesPerSecond := resource.MustParse("50M")
+		cdiConfig.Spec.PodIOLimits = &cdiv1.PodIOLimits{MaxBytesPerSecond: &maxBytesPerSecond, BlockIOClass: pointer.String("throttled")}
+		Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+		By("Setting up the match token")
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Match = "foobaz"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Name = "source"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Namespace = "default"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Params["targetNamespace"] = "default"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Params["targetName"] = "testPvc1"
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		By("Verifying source pod has the IO limits")
+		sourcePod, err := reconciler.findCloneSourcePod(testPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sourcePod).ToNot(BeNil())
+		Expect(sourcePod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: common.IOMaxBytesPerSecond, Value: "50000000"}))
+		for _, env := range sourcePod.Spec.Containers[0].Env {
+			Expect(env.Name).ToNot(BeElementOf(common.IOMaxIOPS, common.IOWeight))
+		}
+		Expect(sourcePod.Annotations).To(HaveKeyWithValue(cgroup.BlockIOClassAnnotation, "throttled"))
+	})
+
+	It("Should create the source pod with the worker pod placement of the target PVC", func() {
+		testPvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnCloneRequest:       "default/source",
+			cc.AnnPodReady:           "true",
+			cc.AnnCloneToken:         "foobaz",
+			AnnUploadClientName:      "uploadclient",
+			AnnCloneSourcePod:        "default-testPvc1-source-pod",
+			cc.AnnWorkerPodPlacement: `{"nodeSelector":{"disktype":"ssd"},"tolerations":[{"key":"storage","operator":"Exists"}]}`}, nil)
+		reconciler = createCloneReconciler(testPvc, cc.CreatePvc("source", "default", map[string]string{}, nil))
+		cdiConfig := &cdiv1.CDIConfig{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+		cdiConfig.Spec.AllowedWorkerPodPlacement = &cdiv1.WorkerPodPlacement{
+			NodeSelector: map[string]string{"disktype": "ssd"},
+			Tolerations:  []corev1.Toleration{{Key: "storage", Operator: corev1.TolerationOpExists}},
+		}
+		Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+		By("Setting up the match token")
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Match = "foobaz"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Name = "source"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Namespace = "default"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Params["targetNamespace"] = "default"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Params["targetName"] = "testPvc1"
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		By("Verifying source pod has the worker pod placement")
+		sourcePod, err := reconciler.findCloneSourcePod(testPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sourcePod).ToNot(BeNil())
+		Expect(sourcePod.Spec.NodeSelector).To(Equal(map[string]string{"disktype": "ssd"}))
+		Expect(sourcePod.Spec.Tolerations).To(Equal([]corev1.Toleration{{Key: "storage", Operator: corev1.TolerationOpExists}}))
+	})
+
+	It("Should create the source pod with the worker pod image of the target PVC", func() {
+		testPvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnCloneRequest:   "default/source",
+			cc.AnnPodReady:       "true",
+			cc.AnnCloneToken:     "foobaz",
+			AnnUploadClientName:  "uploadclient",
+			AnnCloneSourcePod:    "default-testPvc1-source-pod",
+			cc.AnnWorkerPodImage: `{"registry":"mirror.example.com/kubevirt","pullPolicy":"IfNotPresent"}`}, nil)
+		reconciler = createCloneReconciler(testPvc, cc.CreatePvc("source", "default", map[string]string{}, nil))
+		reconciler.image = "quay.io/kubevirt/cdi-cloner:v1.57.0"
+		cdiConfig := &cdiv1.CDIConfig{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+		cdiConfig.Spec.AllowedWorkerPodRegistries = []string{"mirror.example.com/kubevirt"}
+		Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+		By("Setting up the match token")
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Match = "foobaz"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Name = "source"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Namespace = "default"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Params["targetNamespace"] = "default"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Params["targetName"] = "testPvc1"
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		By("Verifying source pod has the worker pod image")
+		sourcePod, err := reconciler.findCloneSourcePod(testPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sourcePod).ToNot(BeNil())
+		Expect(sourcePod.Spec.Containers[0].Image).To(Equal("mirror.example.com/kubevirt/cdi-cloner:v1.57.0"))
+		Expect(sourcePod.Spec.Containers[0].ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+	})
+
+	DescribeTable("Should pass the bandwidth limit of the clone to the source pod", func(configLimit string, annotations map[string]string, expected string) {
+		annotations[cc.AnnCloneRequest] = "default/source"
+		annotations[cc.AnnPodReady] = "true"
+		annotations[cc.AnnCloneToken] = "foobaz"
+		annotations[AnnUploadClientName] = "uploadclient"
+		annotations[AnnCloneSourcePod] = "default-testPvc1-source-pod"
+		testPvc := cc.CreatePvc("testPvc1", "default", annotations, nil)
+		reconciler = createCloneReconciler(testPvc, cc.CreatePvc("source", "default", map[string]string{}, nil))
+		if configLimit != "" {
+			cdiConfig := &cdiv1.CDIConfig{}
+			Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+			limit := resource.MustParse(configLimit)
+			cdiConfig.Spec.CloneBandwidthLimit = &limit
+			Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+		}
+		By("Setting up the match token")
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Match = "foobaz"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Name = "source"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Namespace = "default"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Params["targetNamespace"] = "default"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Params["targetName"] = "testPvc1"
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		By("Verifying source pod has the bandwidth limit")
+		sourcePod, err := reconciler.findCloneSourcePod(testPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sourcePod).ToNot(BeNil())
+		if expected == "" {
+			for _, env := range sourcePod.Spec.Containers[0].Env {
+				Expect(env.Name).ToNot(Equal(common.ClonerBandwidthLimit))
+			}
+		} else {
+			Expect(sourcePod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: common.ClonerBandwidthLimit, Value: expected}))
+		}
+	},
+		Entry("without limit", "", map[string]string{}, ""),
+		Entry("with the default limit of the CDIConfig", "100M", map[string]string{}, "100000000"),
+		Entry("with the limit of the DataVolume without default", "", map[string]string{cc.AnnCloneBandwidthLimit: "10Mi"}, "10485760"),
+		Entry("with the limit of the DataVolume below the default", "100M", map[string]string{cc.AnnCloneBandwidthLimit: "10Mi"}, "10485760"),
+		Entry("with the default when the DataVolume asks for more", "100M", map[string]string{cc.AnnCloneBandwidthLimit: "1Gi"}, "100000000"),
+		Entry("with the default when the DataVolume asks for no limit", "100M", map[string]string{cc.AnnCloneBandwidthLimit: "0"}, "100000000"),
+		Entry("with the minimum limit when the default is below it", "1Ki", map[string]string{}, "65536"),
+	)
+
+	DescribeTable("Should pass the compression of the clone to the source pod", func(configCompression string, annotations map[string]string, expected string) {
+		annotations[cc.AnnCloneRequest] = "default/source"
+		annotations[cc.AnnPodReady] = "true"
+		annotations[cc.AnnCloneToken] = "foobaz"
+		annotations[AnnUploadClientName] = "uploadclient"
+		annotations[AnnCloneSourcePod] = "default-testPvc1-source-pod"
+		testPvc := cc.CreatePvc("testPvc1", "default", annotations, nil)
+		reconciler = createCloneReconciler(testPvc, cc.CreatePvc("source", "default", map[string]string{}, nil))
+		if configCompression != "" {
+			cdiConfig := &cdiv1.CDIConfig{}
+			Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+			cdiConfig.Spec.CloneCompression = &configCompression
+			Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+		}
+		By("Setting up the match token")
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Match = "foobaz"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Name = "source"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Namespace = "default"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Params["targetNamespace"] = "default"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Params["targetName"] = "testPvc1"
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		By("Verifying source pod has the compression")
+		sourcePod, err := reconciler.findCloneSourcePod(testPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sourcePod).ToNot(BeNil())
+		if expected == "" {
+			for _, env := range sourcePod.Spec.Containers[0].Env {
+				Expect(env.Name).ToNot(Equal(common.ClonerCompression))
+			}
+		} else {
+			Expect(sourcePod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: common.ClonerCompression, Value: expected}))
+		}
+	},
+		Entry("without compression setting", "", map[string]string{}, ""),
+		Entry("with the default compression of the CDIConfig", "zstd", map[string]string{}, "zstd"),
+		Entry("with the compression of the DataVolume over the default", "zstd", map[string]string{cc.AnnCloneCompression: "gzip"}, "gzip"),
+	)
+
+	DescribeTable("Should pass the number of clone streams to the source pod", func(volumeMode corev1.PersistentVolumeMode, expected string) {
+		testPvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnCloneRequest:  "default/source",
+			cc.AnnPodReady:      "true",
+			cc.AnnCloneToken:    "foobaz",
+			AnnUploadClientName: "uploadclient",
+			AnnCloneSourcePod:   "default-testPvc1-source-pod"}, nil)
+		testPvc.Spec.VolumeMode = &volumeMode
+		sourcePvc := cc.CreatePvc("source", "default", map[string]string{}, nil)
+		sourcePvc.Spec.VolumeMode = &volumeMode
+		reconciler = createCloneReconciler(testPvc, sourcePvc)
+		cdiConfig := &cdiv1.CDIConfig{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+		streams := int32(4)
+		cdiConfig.Spec.CloneStreams = &streams
+		Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+		By("Setting up the match token")
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Match = "foobaz"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Name = "source"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Namespace = "default"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Params["targetNamespace"] = "default"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Params["targetName"] = "testPvc1"
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		By("Verifying source pod has the number of clone streams")
+		sourcePod, err := reconciler.findCloneSourcePod(testPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sourcePod).ToNot(BeNil())
+		if expected == "" {
+			for _, env := range sourcePod.Spec.Containers[0].Env {
+				Expect(env.Name).ToNot(Equal(common.ClonerStreams))
+			}
+		} else {
+			Expect(sourcePod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: common.ClonerStreams, Value: expected}))
+		}
+	},
+		Entry("for a block clone", corev1.PersistentVolumeBlock, "4"),
+		Entry("but not for a filesystem clone", corev1.PersistentVolumeFilesystem, ""),
+	)
+
+	DescribeTable("Should ask the source pod to clone incrementally", func(annotations map[string]string, expected bool) {
+		pvcAnnotations := map[string]string{
+			cc.AnnCloneRequest:  "default/source",
+			cc.AnnPodReady:      "true",
+			cc.AnnCloneToken:    "foobaz",
+			AnnUploadClientName: "uploadclient",
+			AnnCloneSourcePod:   "default-testPvc1-source-pod"}
+		for key, value := range annotations {
+			pvcAnnotations[key] = value
+		}
+		testPvc := cc.CreatePvc("testPvc1", "default", pvcAnnotations, nil)
+		reconciler = createCloneReconciler(testPvc, cc.CreatePvc("source", "default", map[string]string{}, nil))
+		By("Setting up the match token")
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Match = "foobaz"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Name = "source"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Namespace = "default"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Params["targetNamespace"] = "default"
+		reconciler.shortTokenValidator.(*cc.FakeValidator).Params["targetName"] = "testPvc1"
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		sourcePod, err := reconciler.findCloneSourcePod(testPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sourcePod).ToNot(BeNil())
+		incremental := corev1.EnvVar{Name: common.ClonerIncremental, Value: "true"}
+		if expected {
+			Expect(sourcePod.Spec.Containers[0].Env).To(ContainElement(incremental))
+		} else {
+			Expect(sourcePod.Spec.Containers[0].Env).ToNot(ContainElement(incremental))
+		}
+	},
+		Entry("when the clone is re-synced", map[string]string{cc.AnnCloneIncremental: "true"}, true),
+		Entry("but not for the first clone", nil, false),
+	)
+
+	It("Should delete the source pod and remove the finalizer once the DataVolume failed its completion timeout", func() {
+		testPvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnCloneRequest: "default/source", cc.AnnPodReady: "true", cc.AnnCloneToken: "foobaz", AnnUploadClientName: "uploadclient",
+			AnnCloneSourcePod: "default-testPvc1-source-pod", cc.AnnPodRetainAfterCompletion: "true", cc.AnnCompletionTimedOut: "true"}, nil)
+		cc.AddFinalizer(testPvc, cloneSourcePodFinalizer)
+		sourcePod := createSourcePod(testPvc, string(testPvc.GetUID()))
+		sourcePod.Namespace = "default"
+		sourcePod.Status.Phase = corev1.PodRunning
+		reconciler = createCloneReconciler(testPvc, cc.CreatePvc("source", "default", map[string]string{}, nil), sourcePod)
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		pod, err := reconciler.findCloneSourcePod(testPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod).To(BeNil())
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, testPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cc.HasFinalizer(testPvc, cloneSourcePodFinalizer)).To(BeFalse())
+	})
+
 	It("Should error with missing upload client name annotation if none provided", func() {
 		testPvc := cc.CreatePvc("testPvc1", "default", map[string]string{
 			cc.AnnCloneRequest: "default/source", cc.AnnPodReady: "true", cc.AnnCloneToken: "foobaz", AnnCloneSourcePod: "default-testPvc1-source-pod"}, nil)
@@ -612,6 +940,126 @@ var _ = Describe("Update PVC", func() {
 	})
 })
 
+var _ = Describe("Copy clone source annotations", func() {
+	const (
+		userAnnotation  = "example.com/team"
+		otherAnnotation = "example.com/os"
+	)
+	var reconciler *CloneReconciler
+
+	AfterEach(func() {
+		if reconciler != nil {
+			close(reconciler.recorder.(*record.FakeRecorder).Events)
+			reconciler = nil
+		}
+	})
+
+	createSourcePvc := func(virtualSize string) *corev1.PersistentVolumeClaim {
+		return cc.CreatePvc("source", "default", map[string]string{
+			cc.AnnImageFormat:      "qcow2",
+			cc.AnnImageVirtualSize: virtualSize,
+			cc.AnnPodPhase:         string(corev1.PodSucceeded),
+			userAnnotation:         "storage",
+			otherAnnotation:        "fedora",
+		}, nil)
+	}
+
+	createTargetPvc := func() *corev1.PersistentVolumeClaim {
+		return cc.CreatePvc("target", "default", map[string]string{
+			cc.AnnCloneRequest: "default/source",
+			otherAnnotation:    "centos",
+		}, nil)
+	}
+
+	createFinishedSourcePod := func(message string) *corev1.Pod {
+		pod := createSourcePod(createTargetPvc(), "default-target")
+		pod.Status.Phase = corev1.PodSucceeded
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			State: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{Message: message},
+			},
+		}}
+		return pod
+	}
+
+	It("Should copy the image annotations recorded by CDI and not the user ones", func() {
+		targetPvc := createTargetPvc()
+		reconciler = createCloneReconciler(createSourcePvc("46137344"), targetPvc)
+		sourcePod := createFinishedSourcePod(`Clone Complete; Image: {"VirtualSize":1073741824}`)
+		Expect(reconciler.copySourceAnnotations(sourcePod, targetPvc, cloneLog)).To(Succeed())
+		Expect(targetPvc.Annotations[cc.AnnImageFormat]).To(Equal("qcow2"))
+		Expect(targetPvc.Annotations[cc.AnnImageVirtualSize]).To(Equal("46137344"))
+		Expect(targetPvc.Annotations).ToNot(HaveKey(userAnnotation))
+		Expect(targetPvc.Annotations).ToNot(HaveKey(cc.AnnPodPhase))
+		Expect(targetPvc.Annotations[otherAnnotation]).To(Equal("centos"))
+	})
+
+	It("Should copy the annotations allowed in the CDIConfig without overriding the target ones", func() {
+		targetPvc := createTargetPvc()
+		reconciler = createCloneReconciler(createSourcePvc("46137344"), targetPvc)
+		cdiConfig := &cdiv1.CDIConfig{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+		cdiConfig.Spec.CloneAnnotationAllowlist = []string{userAnnotation, otherAnnotation}
+		Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+
+		Expect(reconciler.copySourceAnnotations(nil, targetPvc, cloneLog)).To(Succeed())
+		Expect(targetPvc.Annotations[cc.AnnImageFormat]).To(Equal("qcow2"))
+		Expect(targetPvc.Annotations[userAnnotation]).To(Equal("storage"))
+		Expect(targetPvc.Annotations[otherAnnotation]).To(Equal("centos"))
+	})
+
+	It("Should copy the format of the image written to the source when the clone copies it as is", func() {
+		sourcePvc := createSourcePvc("46137344")
+		sourcePvc.Annotations[cc.AnnImageTargetFormat] = "qcow2"
+		sourcePvc.Annotations[cc.AnnImageTargetCompression] = "zlib"
+		targetPvc := createTargetPvc()
+		reconciler = createCloneReconciler(sourcePvc, targetPvc)
+		Expect(reconciler.copySourceAnnotations(nil, targetPvc, cloneLog)).To(Succeed())
+		Expect(targetPvc.Annotations[cc.AnnImageTargetFormat]).To(Equal("qcow2"))
+		Expect(targetPvc.Annotations[cc.AnnImageTargetCompression]).To(Equal("zlib"))
+	})
+
+	It("Should not copy the format of the image written to the source when the clone converts it", func() {
+		sourcePvc := createSourcePvc("46137344")
+		sourcePvc.Annotations[cc.AnnImageTargetFormat] = "qcow2"
+		sourcePvc.Annotations[cc.AnnImageTargetCompression] = "zlib"
+		targetPvc := createTargetPvc()
+		targetPvc.Annotations[cc.AnnTargetFormat] = "qcow2"
+		reconciler = createCloneReconciler(sourcePvc, targetPvc)
+		Expect(reconciler.copySourceAnnotations(nil, targetPvc, cloneLog)).To(Succeed())
+		Expect(targetPvc.Annotations).ToNot(HaveKey(cc.AnnImageTargetFormat))
+		Expect(targetPvc.Annotations).ToNot(HaveKey(cc.AnnImageTargetCompression))
+	})
+
+	It("Should not copy a virtual size larger than the cloned disk", func() {
+		targetPvc := createTargetPvc()
+		reconciler = createCloneReconciler(createSourcePvc("2147483648"), targetPvc)
+		sourcePod := createFinishedSourcePod(`Clone Complete; Image: {"VirtualSize":1073741824}`)
+		Expect(reconciler.copySourceAnnotations(sourcePod, targetPvc, cloneLog)).To(Succeed())
+		Expect(targetPvc.Annotations[cc.AnnImageFormat]).To(Equal("qcow2"))
+		Expect(targetPvc.Annotations).ToNot(HaveKey(cc.AnnImageVirtualSize))
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring(CloneImageAnnotationMismatch))
+		Expect(event).To(ContainSubstring(fmt.Sprintf(MessageCloneImageVirtualSizeMismatch, "2147483648", 1073741824)))
+	})
+
+	It("Should not copy an invalid virtual size", func() {
+		targetPvc := createTargetPvc()
+		reconciler = createCloneReconciler(createSourcePvc("1Gi"), targetPvc)
+		Expect(reconciler.copySourceAnnotations(nil, targetPvc, cloneLog)).To(Succeed())
+		Expect(targetPvc.Annotations).ToNot(HaveKey(cc.AnnImageVirtualSize))
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring(CloneImageAnnotationMismatch))
+	})
+
+	It("Should copy nothing once the source PVC is gone", func() {
+		targetPvc := createTargetPvc()
+		reconciler = createCloneReconciler(targetPvc)
+		Expect(reconciler.copySourceAnnotations(nil, targetPvc, cloneLog)).To(Succeed())
+		Expect(targetPvc.Annotations).ToNot(HaveKey(cc.AnnImageFormat))
+	})
+})
+
 var _ = Describe("TokenValidation", func() {
 	g := token.NewGenerator(common.CloneTokenIssuer, cc.GetAPIServerKey(), 5*time.Minute)
 	v := cc.NewCloneTokenValidator(common.CloneTokenIssuer, &cc.GetAPIServerKey().PublicKey)
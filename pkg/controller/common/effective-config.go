@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+)
+
+var (
+	// dataVolumeSourceKinds are the DataVolume source kinds, named after the DataVolumeSource fields
+	dataVolumeSourceKinds = sets.NewString("http", "s3", "gcs", "registry", "pvc", "upload", "blank", "imageio", "vddk", "snapshot")
+	// scratchSourceKinds are the source kinds that may need scratch space, depending on the format of the image
+	scratchSourceKinds = sets.NewString("http", "s3", "gcs", "registry", "upload", "imageio")
+)
+
+// EffectiveConfig is the configuration applied to a DataVolume of a storage class and source kind, as merged from the
+// CDIConfig, the StorageProfile of the storage class and the defaults
+type EffectiveConfig struct {
+	// StorageClass is the storage class the configuration applies to, the default one if none was requested
+	StorageClass string `json:"storageClass,omitempty"`
+	SourceKind   string `json:"sourceKind"`
+	// FilesystemOverhead is the fraction of a Filesystem volume reserved for the filesystem
+	FilesystemOverhead cdiv1.Percent `json:"filesystemOverhead"`
+	// Preallocation applies unless the DataVolume sets its own
+	Preallocation bool `json:"preallocation"`
+	// CloneStrategy is only set for the pvc source kind
+	CloneStrategy *cdiv1.CDICloneStrategy `json:"cloneStrategy,omitempty"`
+	// ScratchSpaceStorageClass is only set for the source kinds that may need scratch space
+	ScratchSpaceStorageClass string `json:"scratchSpaceStorageClass,omitempty"`
+}
+
+// GetEffectiveConfig resolves the configuration applied to a DataVolume of the storage class and source kind, with the
+// same functions the controllers use. A nil storage class name means the default storage class.
+func GetEffectiveConfig(c client.Client, storageClassName *string, sourceKind string) (*EffectiveConfig, error) {
+	if !dataVolumeSourceKinds.Has(sourceKind) {
+		return nil, errors.Errorf("unknown source kind %q, must be one of %v", sourceKind, dataVolumeSourceKinds.List())
+	}
+	storageClass, err := GetStorageClassByName(c, storageClassName)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &EffectiveConfig{
+		SourceKind: sourceKind,
+	}
+	if storageClass != nil {
+		config.StorageClass = storageClass.Name
+		storageClassName = &storageClass.Name
+	}
+	if config.FilesystemOverhead, err = GetFilesystemOverheadForStorageClass(c, storageClassName); err != nil {
+		return nil, err
+	}
+	config.Preallocation = getStorageClassPreallocation(c, storageClassName)
+	if sourceKind == "pvc" {
+		if config.CloneStrategy, err = GetCloneStrategy(c, storageClass); err != nil {
+			return nil, err
+		}
+	}
+	if scratchSourceKinds.Has(sourceKind) {
+		config.ScratchSpaceStorageClass = GetScratchStorageClass(c, storageClassName)
+	}
+	return config, nil
+}
@@ -0,0 +1,133 @@
+package common
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+var _ = Describe("GetEffectiveConfig", func() {
+	var c client.Client
+
+	BeforeEach(func() {
+		preallocation := true
+		overhead := cdiv1.Percent("0.1")
+		cloneStrategy := cdiv1.CloneStrategyCsiClone
+		profile := &cdiv1.StorageProfile{}
+		profile.Name = "profiled-sc"
+		profile.Status = cdiv1.StorageProfileStatus{
+			Preallocation:      &preallocation,
+			FilesystemOverhead: &overhead,
+			CloneStrategy:      &cloneStrategy,
+		}
+		defaultProfile := &cdiv1.StorageProfile{}
+		defaultProfile.Name = "default-sc"
+
+		config := MakeEmptyCDIConfigSpec(common.ConfigName)
+		config.Status = cdiv1.CDIConfigStatus{
+			FilesystemOverhead: &cdiv1.FilesystemOverhead{
+				Global:       "0.055",
+				StorageClass: map[string]cdiv1.Percent{"default-sc": "0.07"},
+			},
+		}
+
+		c = CreateClient([]runtime.Object{
+			CreateStorageClass("default-sc", map[string]string{AnnDefaultStorageClass: "true"}),
+			CreateStorageClass("profiled-sc", nil),
+			profile,
+			defaultProfile,
+			config,
+			MakeEmptyCDICR(),
+		}...)
+	})
+
+	newDataVolume := func(storageClassName *string) *cdiv1.DataVolume {
+		return &cdiv1.DataVolume{
+			Spec: cdiv1.DataVolumeSpec{
+				Storage: &cdiv1.StorageSpec{StorageClassName: storageClassName},
+			},
+		}
+	}
+
+	table.DescribeTable("should match the configuration the controllers apply", func(storageClassName *string, sourceKind string, expected *EffectiveConfig) {
+		config, err := GetEffectiveConfig(c, storageClassName, sourceKind)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config).To(Equal(expected))
+
+		overhead, err := GetFilesystemOverheadForStorageClass(c, storageClassName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config.FilesystemOverhead).To(Equal(overhead))
+		Expect(config.Preallocation).To(Equal(GetPreallocation(c, newDataVolume(storageClassName))))
+		if config.CloneStrategy != nil {
+			storageClass, err := GetStorageClassByName(c, storageClassName)
+			Expect(err).ToNot(HaveOccurred())
+			cloneStrategy, err := GetCloneStrategy(c, storageClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.CloneStrategy).To(Equal(cloneStrategy))
+		}
+	},
+		table.Entry("for an import to the default storage class", nil, "http", &EffectiveConfig{
+			StorageClass:             "default-sc",
+			SourceKind:               "http",
+			FilesystemOverhead:       "0.07",
+			ScratchSpaceStorageClass: "default-sc",
+		}),
+		table.Entry("for a clone to the default storage class", nil, "pvc", &EffectiveConfig{
+			StorageClass:       "default-sc",
+			SourceKind:         "pvc",
+			FilesystemOverhead: "0.07",
+			CloneStrategy:      &[]cdiv1.CDICloneStrategy{cdiv1.CloneStrategySnapshot}[0],
+		}),
+		table.Entry("for a clone to a storage class with a StorageProfile", &[]string{"profiled-sc"}[0], "pvc", &EffectiveConfig{
+			StorageClass:       "profiled-sc",
+			SourceKind:         "pvc",
+			FilesystemOverhead: "0.1",
+			Preallocation:      true,
+			CloneStrategy:      &[]cdiv1.CDICloneStrategy{cdiv1.CloneStrategyCsiClone}[0],
+		}),
+		table.Entry("for a blank image to a storage class with a StorageProfile", &[]string{"profiled-sc"}[0], "blank", &EffectiveConfig{
+			StorageClass:       "profiled-sc",
+			SourceKind:         "blank",
+			FilesystemOverhead: "0.1",
+			Preallocation:      true,
+		}),
+	)
+
+	It("should apply the CDIConfig scratch space storage class and the CDI clone strategy override", func() {
+		config := &cdiv1.CDIConfig{}
+		Expect(c.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, config)).To(Succeed())
+		config.Status.ScratchSpaceStorageClass = "scratch-sc"
+		Expect(c.Update(context.TODO(), config)).To(Succeed())
+		cr := &cdiv1.CDI{}
+		Expect(c.Get(context.TODO(), types.NamespacedName{Name: "cdi"}, cr)).To(Succeed())
+		cloneStrategy := cdiv1.CloneStrategyHostAssisted
+		cr.Spec.CloneStrategyOverride = &cloneStrategy
+		Expect(c.Update(context.TODO(), cr)).To(Succeed())
+
+		effective, err := GetEffectiveConfig(c, &[]string{"profiled-sc"}[0], "pvc")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*effective.CloneStrategy).To(Equal(cdiv1.CloneStrategyHostAssisted))
+		effective, err = GetEffectiveConfig(c, &[]string{"profiled-sc"}[0], "upload")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(effective.ScratchSpaceStorageClass).To(Equal("scratch-sc"))
+	})
+
+	It("should reject an unknown source kind", func() {
+		_, err := GetEffectiveConfig(c, nil, "ftp")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`unknown source kind "ftp"`))
+	})
+
+	It("should fail if the storage class does not exist", func() {
+		_, err := GetEffectiveConfig(c, &[]string{"missing-sc"}[0], "http")
+		Expect(err).To(HaveOccurred())
+	})
+})
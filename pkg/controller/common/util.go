@@ -20,11 +20,15 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/rsa"
+	"encoding/json"
 	"fmt"
+	"path"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/containers/image/v5/docker/reference"
 	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
 	ocpconfigv1 "github.com/openshift/api/config/v1"
 	"github.com/pkg/errors"
@@ -34,9 +38,12 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/pointer"
@@ -48,6 +55,7 @@ import (
 	featuregates "kubevirt.io/containerized-data-importer/pkg/feature-gates"
 	"kubevirt.io/containerized-data-importer/pkg/token"
 	"kubevirt.io/containerized-data-importer/pkg/util"
+	"kubevirt.io/containerized-data-importer/pkg/util/naming"
 	sdkapi "kubevirt.io/controller-lifecycle-operator-sdk/api"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -69,17 +77,67 @@ const (
 	AnnPodPhase = AnnAPIGroup + "/storage.pod.phase"
 	// AnnPodReady tells whether the pod is ready
 	AnnPodReady = AnnAPIGroup + "/storage.pod.ready"
+	// AnnWorkerPodQueued is a PVC annotation telling its worker pod waits for a slot under the MaxParallelWorkerPods limit
+	AnnWorkerPodQueued = AnnAPIGroup + "/storage.pod.queued"
 	// AnnPodRestarts is a PVC annotation that tells how many times a related pod was restarted
 	AnnPodRestarts = AnnAPIGroup + "/storage.pod.restarts"
 	// AnnPopulatedFor is a PVC annotation telling the datavolume controller that the PVC is already populated
 	AnnPopulatedFor = AnnAPIGroup + "/storage.populatedFor"
 	// AnnPrePopulated is a PVC annotation telling the datavolume controller that the PVC is already populated
 	AnnPrePopulated = AnnAPIGroup + "/storage.prePopulated"
+	// AnnVerifyPopulated is a DataVolume annotation requesting the populated PVC to be verified, "true", "size" or "digest"
+	AnnVerifyPopulated = AnnAPIGroup + "/storage.populated.verify"
+	// AnnVerifyPopulatedDigest is a DataVolume annotation holding the expected digest of the source, as <algorithm>:<hex>, checked by the "digest" verification
+	AnnVerifyPopulatedDigest = AnnAPIGroup + "/storage.populated.expectedDigest"
+	// AnnPopulatedVerified is a PVC annotation telling the populating DataVolume succeeded and the PVC passed verification
+	AnnPopulatedVerified = AnnAPIGroup + "/storage.populated.verified"
+	// AnnAdoptPVC is a DataVolume annotation telling the datavolume controller to populate the existing empty PVC with the DataVolume name instead of creating one
+	AnnAdoptPVC = AnnAPIGroup + "/storage.adoptPVC"
+	// AnnAdoptedPVC is a PVC annotation telling a DataVolume adopted the existing PVC instead of creating it
+	AnnAdoptedPVC = AnnAPIGroup + "/storage.adopted"
 	// AnnPriorityClassName is PVC annotation to indicate the priority class name for importer, cloner and uploader pod
 	AnnPriorityClassName = AnnAPIGroup + "/storage.pod.priorityclassname"
+	// AnnWorkerPodPlacement is PVC annotation holding the JSON encoded node selector and tolerations of the importer, cloner and uploader pod
+	AnnWorkerPodPlacement = AnnAPIGroup + "/storage.pod.placement"
+	// AnnWorkerPodImage is PVC annotation holding the JSON encoded image registry and pull policy of the importer, cloner and uploader pod
+	AnnWorkerPodImage = AnnAPIGroup + "/storage.pod.image"
 	// AnnExternalPopulation annotation marks a PVC as "externally populated", allowing the import-controller to skip it
 	AnnExternalPopulation = AnnAPIGroup + "/externalPopulation"
 
+	// AnnCloneSourcePath is a DataVolume annotation selecting the single disk image to clone from a filesystem source PVC
+	AnnCloneSourcePath = AnnAPIGroup + "/storage.clone.sourcePath"
+	// AnnCloneQcow2ClusterSize is a DataVolume annotation setting the cluster size of a clone written as qcow2
+	AnnCloneQcow2ClusterSize = AnnAPIGroup + "/storage.clone.qcow2ClusterSize"
+	// AnnCloneCompact is a DataVolume annotation asking to rewrite a qcow2 clone source written as qcow2, defragmenting it
+	AnnCloneCompact = AnnAPIGroup + "/storage.clone.compact"
+	// AnnCloneBandwidthLimit is a DataVolume annotation overriding the network bandwidth limit of a host-assisted clone, in bytes per second
+	AnnCloneBandwidthLimit = AnnAPIGroup + "/storage.clone.bandwidthLimit"
+	// AnnCloneCompression is a DataVolume annotation overriding the compression codec of the stream of a host-assisted clone
+	AnnCloneCompression = AnnAPIGroup + "/storage.clone.compression"
+	// AnnCloneResync is a DataVolume annotation asking to re-sync a succeeded host-assisted clone from its source whenever its value changes, the PVC annotation holding the last value handled
+	AnnCloneResync = AnnAPIGroup + "/storage.clone.resync"
+	// AnnCloneResyncToken is a DataVolume annotation holding the clone token issued to the user asking for its current re-sync
+	AnnCloneResyncToken = AnnAPIGroup + "/storage.clone.resyncToken"
+	// AnnCloneIncremental is a PVC annotation asking the clone source to only stream the blocks changed since the target was cloned
+	AnnCloneIncremental = AnnAPIGroup + "/storage.clone.incremental"
+	// AnnCancel is a DataVolume annotation asking the datavolume controller to stop the transfer and clean up its resources
+	AnnCancel = AnnAPIGroup + "/storage.cancel"
+	// AnnCanceled is a PVC annotation marking the DataVolume that adopted it was canceled, so its worker pods are stopped and not recreated
+	AnnCanceled = AnnAPIGroup + "/storage.canceled"
+	// AnnVerifyOnly is a DataVolume annotation asking to only verify the import source, without creating the PVC
+	AnnVerifyOnly = AnnAPIGroup + "/storage.import.verifyOnly"
+	// AnnTopology is a DataVolume annotation pinning the worker pod, and so the volume of a WaitForFirstConsumer storage class, to the nodes matching its label selector on the node topology labels
+	AnnTopology = AnnAPIGroup + "/storage.topology"
+	// AnnCompletionTimeout is a DataVolume annotation setting the time from its creation within which it must succeed, as a duration such as 2h
+	AnnCompletionTimeout = AnnAPIGroup + "/storage.completionTimeout"
+	// AnnCompletionTimedOut is a PVC annotation marking its DataVolume failed its completion timeout, so its worker pods are stopped and not recreated
+	AnnCompletionTimedOut = AnnAPIGroup + "/storage.completionTimedOut"
+	// AnnCompletionHookAnnotation is a DataVolume annotation naming the annotation written on the DataVolume once it succeeded, as key or key=value, the value defaulting to the completion time
+	AnnCompletionHookAnnotation = AnnAPIGroup + "/storage.completionHook.annotation"
+	// AnnCompletionHook is a DataVolume annotation naming the completion hook of the CDIConfig notified with a POST once the DataVolume succeeded
+	AnnCompletionHook = AnnAPIGroup + "/storage.completionHook"
+	// AnnCompletionHookFired is a DataVolume annotation recording when the completion hook fired, the hook never fires again once it is set
+	AnnCompletionHookFired = AnnAPIGroup + "/storage.completionHook.fired"
 	// AnnDeleteAfterCompletion is PVC annotation for deleting DV after completion
 	AnnDeleteAfterCompletion = AnnAPIGroup + "/storage.deleteAfterCompletion"
 	// AnnPodRetainAfterCompletion is PVC annotation for retaining transfer pods after completion
@@ -104,6 +162,65 @@ const (
 	// AnnPreallocationApplied provides a const for PVC preallocation annotation
 	AnnPreallocationApplied = AnnAPIGroup + "/storage.preallocation"
 
+	// AnnImageFormat is a PVC annotation telling the format of the source image the PVC was imported from
+	AnnImageFormat = AnnAPIGroup + "/storage.image.format"
+	// AnnImageVirtualSize is a PVC annotation telling the virtual size in bytes of the source image the PVC was imported from
+	AnnImageVirtualSize = AnnAPIGroup + "/storage.image.virtualSize"
+
+	// AnnShrinkToUsedSize is a PVC annotation requesting to shrink the imported image to the used space of its filesystem
+	AnnShrinkToUsedSize = AnnAPIGroup + "/storage.import.shrinkToUsedSize"
+
+	// AnnTargetFormat is a PVC annotation requesting the format of the imported image written to the PVC, raw or qcow2
+	AnnTargetFormat = AnnAPIGroup + "/storage.import.targetFormat"
+	// AnnTargetCompression is a PVC annotation requesting to compress the clusters of a qcow2 target, with zlib or zstd
+	AnnTargetCompression = AnnAPIGroup + "/storage.import.targetCompression"
+	// AnnImageTargetFormat is a PVC annotation telling the format of the image written to the PVC, when it is not raw
+	AnnImageTargetFormat = AnnAPIGroup + "/storage.image.targetFormat"
+	// AnnImageTargetCompression is a PVC annotation telling the compression of the clusters of the qcow2 image written to the PVC
+	AnnImageTargetCompression = AnnAPIGroup + "/storage.image.targetCompression"
+
+	// AnnImportDownloadSeconds is a PVC annotation telling the time in seconds the importer spent downloading the source
+	AnnImportDownloadSeconds = AnnAPIGroup + "/storage.import.downloadSeconds"
+	// AnnImportConvertSeconds is a PVC annotation telling the time in seconds the importer spent converting the image
+	AnnImportConvertSeconds = AnnAPIGroup + "/storage.import.convertSeconds"
+	// AnnImportResizeSeconds is a PVC annotation telling the time in seconds the importer spent resizing the image
+	AnnImportResizeSeconds = AnnAPIGroup + "/storage.import.resizeSeconds"
+	// AnnImportPassthrough is a PVC annotation telling the importer copied the image as is, as it already had the target format
+	AnnImportPassthrough = AnnAPIGroup + "/storage.import.passthrough"
+	// AnnSourceETag is a PVC annotation telling the ETag of the HTTP source the PVC was imported from
+	AnnSourceETag = AnnAPIGroup + "/storage.import.sourceETag"
+	// AnnSourceLastModified is a PVC annotation telling the Last-Modified date of the HTTP source the PVC was imported from
+	AnnSourceLastModified = AnnAPIGroup + "/storage.import.sourceLastModified"
+	// AnnSourceNotModified is a PVC annotation telling the last import kept the content of the PVC, as its source was unchanged
+	AnnSourceNotModified = AnnAPIGroup + "/storage.import.sourceNotModified"
+	// AnnSourceDigestAlgorithm is a PVC annotation requesting the importer to compute the digest of the source bytes, with sha256 or sha512
+	AnnSourceDigestAlgorithm = AnnAPIGroup + "/storage.import.sourceDigestAlgorithm"
+	// AnnSourceDigest is a PVC annotation telling the digest of the source bytes the PVC was imported from, as <algorithm>:<hex>
+	AnnSourceDigest = AnnAPIGroup + "/storage.import.sourceDigest"
+	// AnnEncryptionSecret is a PVC annotation requesting the importer to write the image into a LUKS container on the block
+	// PVC, with the passphrase held by the passphrase key of the named Secret of the PVC namespace
+	AnnEncryptionSecret = AnnAPIGroup + "/storage.import.encryptionSecret"
+	// AnnTarMember is a PVC annotation selecting the member of a tar archive source to import, when it holds several disk images
+	AnnTarMember = AnnAPIGroup + "/storage.import.tarMember"
+	// AnnFreeSpaceMargin is a PVC annotation with the space an import keeps free on the target, a size or a percentage
+	AnnFreeSpaceMargin = AnnAPIGroup + "/storage.import.freeSpaceMargin"
+	// AnnMaxCopyBufferSize is a PVC annotation bounding the copy buffer the importer grows on the throughput of the source
+	AnnMaxCopyBufferSize = AnnAPIGroup + "/storage.import.maxCopyBufferSize"
+	// AnnChangedRangesURL is a PVC annotation telling the URL of the manifest of the byte ranges of the HTTP source to
+	// write onto the base image held by the PVC, instead of importing the whole source
+	AnnChangedRangesURL = AnnAPIGroup + "/storage.import.changedRangesURL"
+	// AnnChangedRangesRefresh is a PVC annotation requesting a new import of the changed ranges of a completed import
+	// whenever its value changes
+	AnnChangedRangesRefresh = AnnAPIGroup + "/storage.import.changedRangesRefresh"
+	// AnnChangedRangesLastRefresh is a PVC annotation telling the value of AnnChangedRangesRefresh the last import of
+	// the changed ranges was started for
+	AnnChangedRangesLastRefresh = AnnAPIGroup + "/storage.import.changedRangesLastRefresh"
+	// AnnChecksumManifestURL is a PVC annotation telling the URL of the manifest of the digests of the regions of the
+	// HTTP source, which the importer verifies as the source is downloaded
+	AnnChecksumManifestURL = AnnAPIGroup + "/storage.import.checksumManifestURL"
+	// AnnScratchSpaceBackend provides a const for the kind of volume backing the scratch space of the PVC worker pods
+	AnnScratchSpaceBackend = AnnAPIGroup + "/storage.scratch.backend"
+
 	// AnnRunningCondition provides a const for the running condition
 	AnnRunningCondition = AnnAPIGroup + "/storage.condition.running"
 	// AnnRunningConditionMessage provides a const for the running condition
@@ -151,8 +268,16 @@ const (
 	AnnRegistryImportMethod = AnnAPIGroup + "/storage.import.registryImportMethod"
 	// AnnRegistryImageStream provides a const for registry image stream annotation
 	AnnRegistryImageStream = AnnAPIGroup + "/storage.import.registryImageStream"
+	// AnnRegistryDiskPath provides a const for the path of the disk of a multi-disk registry image imported into the PVC
+	AnnRegistryDiskPath = AnnAPIGroup + "/storage.import.registryDiskPath"
 	// AnnImportPod provides a const for our PVC importPodName annotation
 	AnnImportPod = AnnAPIGroup + "/storage.import.importPodName"
+	// AnnImportMaxAttempts provides a const for the number of failed import attempts after which the import fails
+	AnnImportMaxAttempts = AnnAPIGroup + "/storage.import.maxAttempts"
+	// AnnImportAttemptsBase provides a const for the importer pod restart count when the import last made progress
+	AnnImportAttemptsBase = AnnAPIGroup + "/storage.import.attemptsBase"
+	// AnnImportAttemptsExhausted is a PVC annotation telling the import failed after the maximum number of attempts
+	AnnImportAttemptsExhausted = AnnAPIGroup + "/storage.import.attemptsExhausted"
 	// AnnDiskID provides a const for our PVC diskId annotation
 	AnnDiskID = AnnAPIGroup + "/storage.import.diskId"
 	// AnnUUID provides a const for our PVC uuid annotation
@@ -165,6 +290,28 @@ const (
 	AnnExtraHeaders = AnnAPIGroup + "/storage.import.extraHeaders"
 	// AnnSecretExtraHeaders provides a const for our PVC secretExtraHeaders annotation
 	AnnSecretExtraHeaders = AnnAPIGroup + "/storage.import.secretExtraHeaders"
+	// AnnMultipartParallelism provides a const for the number of concurrent range requests used to download the source
+	AnnMultipartParallelism = AnnAPIGroup + "/storage.import.multipartParallelism"
+	// AnnMultipartPartSize provides a const for the size in bytes of each range request used to download the source
+	AnnMultipartPartSize = AnnAPIGroup + "/storage.import.multipartPartSize"
+	// AnnS3KMSKeyID provides a const for the AWS KMS key an S3 object must be encrypted with
+	AnnS3KMSKeyID = AnnAPIGroup + "/storage.import.s3KmsKeyId"
+	// AnnGcsUserProject provides a const for the project billed for the requests to a requester-pays GCS bucket
+	AnnGcsUserProject = AnnAPIGroup + "/storage.import.gcsUserProject"
+	// AnnGcsGeneration provides a const for the generation of the GCS object the import is pinned to
+	AnnGcsGeneration = AnnAPIGroup + "/storage.import.gcsGeneration"
+	// AnnSFTPHostKey provides a const for the host key, or its fingerprint, an SFTP server must present
+	AnnSFTPHostKey = AnnAPIGroup + "/storage.import.sftpHostKey"
+	// AnnImportTLSMinVersion provides a const for the minimal TLS version used to connect to the import source, overriding the CDIConfig one
+	AnnImportTLSMinVersion = AnnAPIGroup + "/storage.import.tlsMinVersion"
+	// AnnImportTLSCiphers provides a const for the comma separated cipher suites allowed to connect to the import source, overriding the CDIConfig ones
+	AnnImportTLSCiphers = AnnAPIGroup + "/storage.import.tlsCiphers"
+	// AnnTokenAudience provides a const for the audience of the service account token the importer exchanges for the source credentials
+	AnnTokenAudience = AnnAPIGroup + "/storage.import.tokenAudience"
+	// AnnTokenRoleARN provides a const for the IAM role the importer assumes with the service account token
+	AnnTokenRoleARN = AnnAPIGroup + "/storage.import.tokenRoleARN"
+	// AnnTokenServiceAccount provides a const for the GCP service account the importer impersonates with the service account token
+	AnnTokenServiceAccount = AnnAPIGroup + "/storage.import.tokenServiceAccount"
 
 	// AnnCloneToken is the annotation containing the clone token
 	AnnCloneToken = AnnAPIGroup + "/storage.clone.token"
@@ -188,6 +335,9 @@ const (
 	//AnnDefaultStorageClass is the annotation indicating that a storage class is the default one.
 	AnnDefaultStorageClass = "storageclass.kubernetes.io/is-default-class"
 
+	// AnnSelectedNode is the annotation of a WaitForFirstConsumer PVC naming the node its first consumer is scheduled on
+	AnnSelectedNode = "volume.kubernetes.io/selected-node"
+
 	// AnnOpenShiftImageLookup is the annotation for OpenShift image stream lookup
 	AnnOpenShiftImageLookup = "alpha.image.policy.openshift.io/resolve-names"
 
@@ -214,6 +364,16 @@ const (
 	// CloneSourceInUse is reason for event created when clone source pvc is in use
 	CloneSourceInUse = "CloneSourceInUse"
 
+	// WorkerPodQueued is the reason of the running condition while the worker pod waits for a slot under the
+	// MaxParallelWorkerPods limit
+	WorkerPodQueued = "WorkerPodQueued"
+	// MessageWorkerPodSlotsInUse is the message of the running condition while the worker pod waits for a slot
+	MessageWorkerPodSlotsInUse = "All %d worker pod slots are in use, waiting in creation order"
+
+	// ScratchSpaceExhausted is the reason of the running condition and event when the scratch space of the worker pod
+	// ran out, as opposed to the space of the target
+	ScratchSpaceExhausted = "ScratchSpaceExhausted"
+
 	// CloneComplete message
 	CloneComplete = "Clone Complete"
 
@@ -233,6 +393,13 @@ const (
 	// ErrIncompatiblePVC provides a const to indicate a clone is not possible due to an incompatible PVC
 	ErrIncompatiblePVC = "ErrIncompatiblePVC"
 
+	// VerifyPopulatedBound verifies the populated PVC is bound
+	VerifyPopulatedBound = "true"
+	// VerifyPopulatedSize verifies in addition the PVC records the virtual size of its image, and the image fits in the PVC
+	VerifyPopulatedSize = "size"
+	// VerifyPopulatedDigest verifies in addition the PVC records the digest of its source, matching the expected digest
+	VerifyPopulatedDigest = "digest"
+
 	// SourceHTTP is the source type HTTP, if unspecified or invalid, it defaults to SourceHTTP
 	SourceHTTP = "http"
 	// SourceS3 is the source type S3
@@ -249,9 +416,27 @@ const (
 	SourceImageio = "imageio"
 	// SourceVDDK is the source type of VDDK
 	SourceVDDK = "vddk"
-
+	// SourceInline is the source type of an image embedded in the DataVolume
+	SourceInline = "inline"
+	// SourceNBD is the source type of a disk exported over NBD
+	SourceNBD = "nbd"
+	// SourceRemote is the source type of a disk cloned from the export of a remote cluster
+	SourceRemote = "remote"
+	// SourceFTP is the source type FTP, or FTPS
+	SourceFTP = "ftp"
+	// SourceAzure is the source type Azure Blob Storage
+	SourceAzure = "azure"
+	// SourceSFTP is the source type SFTP
+	SourceSFTP = "sftp"
+
+	// IncompleteStorageProfile reason const, the StorageProfile of the storage class can't complete the DataVolume storage spec
+	IncompleteStorageProfile = "IncompleteStorageProfile"
 	// ClaimLost reason const
 	ClaimLost = "ClaimLost"
+	// ScratchSpacePending reason const, the scratch space PVC is not bound yet
+	ScratchSpacePending = "ScratchSpacePending"
+	// ScratchSpaceLost reason const, the scratch space PVC lost its volume
+	ScratchSpaceLost = "ScratchSpaceLost"
 	// NotFound reason const
 	NotFound = "NotFound"
 
@@ -263,6 +448,9 @@ const (
 	LabelDefaultPreference = "instancetype.kubevirt.io/default-preference"
 	// LabelDefaultPreferenceKind provides a default kind of either VirtualMachineClusterPreference or VirtualMachinePreference
 	LabelDefaultPreferenceKind = "instancetype.kubevirt.io/default-preference-kind"
+	// LabelCloneBatch groups the clone DataVolumes fanning one source out to many targets, which read the source once and
+	// are authorized once per user and source
+	LabelCloneBatch = "cdi.kubevirt.io/cloneBatch"
 )
 
 // Size-detection pod error codes
@@ -398,6 +586,19 @@ func GetFilesystemOverheadForStorageClass(client client.Client, storageClassName
 
 	perStorageConfig := cdiConfig.Status.FilesystemOverhead.StorageClass
 
+	// An overhead explicitly set in CDIConfig for the storage class takes precedence over the StorageProfile one
+	if cdiConfig.Spec.FilesystemOverhead != nil {
+		if _, found := cdiConfig.Spec.FilesystemOverhead.StorageClass[targetStorageClass.GetName()]; found {
+			if storageClassOverhead, found := perStorageConfig[targetStorageClass.GetName()]; found {
+				return storageClassOverhead, nil
+			}
+		}
+	}
+
+	if storageProfile := getStorageProfile(client, targetStorageClass.GetName()); storageProfile != nil && storageProfile.Status.FilesystemOverhead != nil {
+		return *storageProfile.Status.FilesystemOverhead, nil
+	}
+
 	storageClassOverhead, found := perStorageConfig[targetStorageClass.GetName()]
 	if found {
 		return storageClassOverhead, nil
@@ -406,6 +607,107 @@ func GetFilesystemOverheadForStorageClass(client client.Client, storageClassName
 	return cdiConfig.Status.FilesystemOverhead.Global, nil
 }
 
+// getStorageProfile returns the StorageProfile of the storage class, or nil if it can't be found
+func getStorageProfile(client client.Client, storageClassName string) *cdiv1.StorageProfile {
+	storageProfile := &cdiv1.StorageProfile{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: storageClassName}, storageProfile); err != nil {
+		klog.V(3).Info("Unable to retrieve storage profile", "storage profile name", storageClassName)
+		return nil
+	}
+	return storageProfile
+}
+
+// ClaimPropertySetSupports returns true if the claim property set supports all the access modes with the volume mode,
+// any volume mode when it is nil
+func ClaimPropertySetSupports(claimPropertySet cdiv1.ClaimPropertySet, volumeMode *v1.PersistentVolumeMode, accessModes []v1.PersistentVolumeAccessMode) bool {
+	if volumeMode != nil && *volumeMode != util.ResolveVolumeMode(claimPropertySet.VolumeMode) {
+		return false
+	}
+	for _, accessMode := range accessModes {
+		supported := false
+		for _, mode := range claimPropertySet.AccessModes {
+			if mode == accessMode {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return false
+		}
+	}
+	return true
+}
+
+// GetCloneStrategyOverride returns the clone strategy set for all the storage classes in the CDI CR, or nil
+func GetCloneStrategyOverride(c client.Client) (*cdiv1.CDICloneStrategy, error) {
+	cr, err := GetActiveCDI(c)
+	if err != nil {
+		return nil, err
+	}
+
+	if cr == nil {
+		return nil, fmt.Errorf("no active CDI")
+	}
+
+	if cr.Spec.CloneStrategyOverride == nil {
+		return nil, nil
+	}
+
+	klog.V(3).Infof("Overriding default clone strategy with %s", *cr.Spec.CloneStrategyOverride)
+	return cr.Spec.CloneStrategyOverride, nil
+}
+
+// GetCloneStrategy returns the preferred clone strategy from the StorageProfile of the storage class, unless overridden
+// in the CDI CR, defaulting to snapshot
+func GetCloneStrategy(c client.Client, storageClass *storagev1.StorageClass) (*cdiv1.CDICloneStrategy, error) {
+	strategy, err := GetConfiguredCloneStrategy(c, storageClass)
+	if err != nil || strategy != nil {
+		return strategy, err
+	}
+
+	defaultCloneStrategy := cdiv1.CloneStrategySnapshot
+	return &defaultCloneStrategy, nil
+}
+
+// GetConfiguredCloneStrategy returns the clone strategy set in the CDI CR override or the StorageProfile of the storage
+// class, nil when neither sets one
+func GetConfiguredCloneStrategy(c client.Client, storageClass *storagev1.StorageClass) (*cdiv1.CDICloneStrategy, error) {
+	strategyOverride, err := GetCloneStrategyOverride(c)
+	if err != nil {
+		return nil, err
+	}
+	if strategyOverride != nil {
+		return strategyOverride, nil
+	}
+
+	// do check storageProfile and apply the preferences
+	if storageClass != nil {
+		storageProfile := &cdiv1.StorageProfile{}
+		if err := c.Get(context.TODO(), types.NamespacedName{Name: storageClass.Name}, storageProfile); err != nil {
+			return nil, errors.Wrap(err, "cannot get StorageProfile")
+		}
+		return storageProfile.Status.CloneStrategy, nil
+	}
+
+	return nil, nil
+}
+
+// GetScratchStorageClass returns the storage class of the scratch space: the one set in the CDIConfig, falling back to
+// the storage class of the PVC needing the scratch space. It returns blank if none is available.
+func GetScratchStorageClass(c client.Client, storageClassName *string) string {
+	config := &cdiv1.CDIConfig{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, config); err != nil {
+		return ""
+	}
+	if config.Status.ScratchSpaceStorageClass != "" {
+		return config.Status.ScratchSpaceStorageClass
+	}
+	if storageClassName != nil {
+		return *storageClassName
+	}
+	return ""
+}
+
 // GetDefaultPodResourceRequirements gets default pod resource requirements from cdi config status
 func GetDefaultPodResourceRequirements(client client.Client) (*v1.ResourceRequirements, error) {
 	cdiconfig := &cdiv1.CDIConfig{}
@@ -417,6 +719,96 @@ func GetDefaultPodResourceRequirements(client client.Client) (*v1.ResourceRequir
 	return cdiconfig.Status.DefaultPodResourceRequirements, nil
 }
 
+// GetPodIOLimits gets the disk IO limits of the CDI worker pods from the cdi config
+func GetPodIOLimits(client client.Client) (*cdiv1.PodIOLimits, error) {
+	cdiconfig := &cdiv1.CDIConfig{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiconfig); err != nil {
+		klog.Errorf("Unable to find CDI configuration, %v\n", err)
+		return nil, err
+	}
+
+	return cdiconfig.Spec.PodIOLimits, nil
+}
+
+// GetCloneBandwidthLimit gets the network bandwidth limit of the host-assisted clone into the target PVC, in bytes per
+// second, 0 for no limit. The annotation of the PVC can only lower the limit of the cdi config, which a config below
+// the minimum limit does not lift but raises to the minimum.
+func GetCloneBandwidthLimit(client client.Client, pvc *corev1.PersistentVolumeClaim) (int64, error) {
+	cdiconfig := &cdiv1.CDIConfig{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiconfig); err != nil {
+		klog.Errorf("Unable to find CDI configuration, %v\n", err)
+		return 0, err
+	}
+	var limit int64
+	if cdiconfig.Spec.CloneBandwidthLimit != nil && !cdiconfig.Spec.CloneBandwidthLimit.IsZero() {
+		limit = cdiconfig.Spec.CloneBandwidthLimit.Value()
+		if limit < util.MinBandwidthLimit {
+			klog.Errorf("Invalid clone bandwidth limit %s in the CDI configuration, using %d", cdiconfig.Spec.CloneBandwidthLimit.String(), util.MinBandwidthLimit)
+			limit = util.MinBandwidthLimit
+		}
+	}
+	value, ok := pvc.Annotations[AnnCloneBandwidthLimit]
+	if !ok {
+		return limit, nil
+	}
+	pvcLimit, err := util.ParseBandwidthLimit(value)
+	if err != nil {
+		return 0, err
+	}
+	if pvcLimit != 0 && (limit == 0 || pvcLimit < limit) {
+		return pvcLimit, nil
+	}
+	return limit, nil
+}
+
+// GetCloneCompression gets the compression codec the source of the host-assisted clone into the target PVC asks the
+// target for, from its annotation or the cdi config, empty for the default snappy
+func GetCloneCompression(client client.Client, pvc *corev1.PersistentVolumeClaim) (string, error) {
+	if value, ok := pvc.Annotations[AnnCloneCompression]; ok {
+		return value, util.ValidateCloneCompression(value)
+	}
+	cdiconfig := &cdiv1.CDIConfig{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiconfig); err != nil {
+		klog.Errorf("Unable to find CDI configuration, %v\n", err)
+		return "", err
+	}
+	if cdiconfig.Spec.CloneCompression == nil {
+		return "", nil
+	}
+	return *cdiconfig.Spec.CloneCompression, nil
+}
+
+// GetCloneStreams gets the number of parallel streams of the host-assisted clone of the source PVC into the target
+// PVC, from the StorageProfile of the target storage class or the cdi config. Only a raw block disk cloned into a
+// raw block disk is split into parallel streams, the other clones have a single stream.
+func GetCloneStreams(client client.Client, sourcePvc, targetPvc *corev1.PersistentVolumeClaim) (int32, error) {
+	if GetVolumeMode(sourcePvc) != corev1.PersistentVolumeBlock || GetVolumeMode(targetPvc) != corev1.PersistentVolumeBlock ||
+		targetPvc.Annotations[AnnTargetFormat] == common.ImportTargetFormatQcow2 {
+		return 1, nil
+	}
+	var streams *int32
+	if targetPvc.Spec.StorageClassName != nil {
+		if storageProfile := getStorageProfile(client, *targetPvc.Spec.StorageClassName); storageProfile != nil {
+			streams = storageProfile.Status.CloneStreams
+		}
+	}
+	if streams == nil {
+		cdiconfig := &cdiv1.CDIConfig{}
+		if err := client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiconfig); err != nil {
+			klog.Errorf("Unable to find CDI configuration, %v\n", err)
+			return 1, err
+		}
+		streams = cdiconfig.Spec.CloneStreams
+	}
+	switch {
+	case streams == nil || *streams < 1:
+		return 1, nil
+	case *streams > common.MaxCloneStreams:
+		return common.MaxCloneStreams, nil
+	}
+	return *streams, nil
+}
+
 // GetImagePullSecrets gets the imagePullSecrets needed to pull images from the cdi config
 func GetImagePullSecrets(client client.Client) ([]corev1.LocalObjectReference, error) {
 	cdiconfig := &cdiv1.CDIConfig{}
@@ -501,6 +893,314 @@ func GetWorkloadNodePlacement(c client.Client) (*sdkapi.NodePlacement, error) {
 	return &cr.Spec.Workloads, nil
 }
 
+// GetWorkerPodNodePlacement returns the node placement of the worker pods of a DataVolume: the workload node placement
+// of the CDI CR, with the node selector and tolerations of the DataVolume, or of the CDIConfig when the DataVolume does
+// not set them. The node selector of the CDI CR takes precedence on the keys set by both.
+func GetWorkerPodNodePlacement(c client.Client, placement *cdiv1.WorkerPodPlacement) (*sdkapi.NodePlacement, error) {
+	workloadNodePlacement, err := GetWorkloadNodePlacement(c)
+	if err != nil {
+		return nil, err
+	}
+	cdiconfig := &cdiv1.CDIConfig{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiconfig); err != nil {
+		return nil, err
+	}
+
+	var nodeSelector map[string]string
+	var tolerations []v1.Toleration
+	if placement != nil {
+		// the placement may come from a PVC annotation, not validated by the DataVolume webhook
+		if err := ValidateWorkerPodPlacement(placement); err != nil {
+			return nil, err
+		}
+		if err := CheckWorkerPodPlacementAllowed(placement, cdiconfig.Spec.AllowedWorkerPodPlacement); err != nil {
+			return nil, err
+		}
+		nodeSelector = placement.NodeSelector
+		tolerations = placement.Tolerations
+	}
+	if defaults := cdiconfig.Spec.WorkerPodPlacement; defaults != nil {
+		if err := ValidateWorkerPodPlacement(defaults); err != nil {
+			return nil, errors.Wrap(err, "invalid CDIConfig worker pod placement")
+		}
+		if len(nodeSelector) == 0 {
+			nodeSelector = defaults.NodeSelector
+		}
+		if len(tolerations) == 0 {
+			tolerations = defaults.Tolerations
+		}
+	}
+	if len(nodeSelector) == 0 && len(tolerations) == 0 {
+		return workloadNodePlacement, nil
+	}
+
+	nodePlacement := workloadNodePlacement.DeepCopy()
+	for key, value := range nodeSelector {
+		if _, ok := nodePlacement.NodeSelector[key]; ok {
+			continue
+		}
+		if nodePlacement.NodeSelector == nil {
+			nodePlacement.NodeSelector = map[string]string{}
+		}
+		nodePlacement.NodeSelector[key] = value
+	}
+	for _, toleration := range tolerations {
+		if !containsToleration(nodePlacement.Tolerations, toleration) {
+			nodePlacement.Tolerations = append(nodePlacement.Tolerations, toleration)
+		}
+	}
+	return nodePlacement, nil
+}
+
+// GetPvcWorkerPodNodePlacement returns the node placement of the worker pods of the PVC, from the node selector and
+// tolerations of its AnnWorkerPodPlacement annotation
+func GetPvcWorkerPodNodePlacement(c client.Client, pvc *v1.PersistentVolumeClaim) (*sdkapi.NodePlacement, error) {
+	var placement *cdiv1.WorkerPodPlacement
+	if value, ok := pvc.GetAnnotations()[AnnWorkerPodPlacement]; ok {
+		placement = &cdiv1.WorkerPodPlacement{}
+		if err := json.Unmarshal([]byte(value), placement); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s annotation", AnnWorkerPodPlacement)
+		}
+	}
+	return GetWorkerPodNodePlacement(c, placement)
+}
+
+// SetWorkerPodPlacementAnnotation sets the AnnWorkerPodPlacement annotation of the PVC populated by the DataVolume
+func SetWorkerPodPlacementAnnotation(dataVolume *cdiv1.DataVolume, annotations map[string]string) error {
+	if dataVolume.Spec.WorkerPodPlacement == nil {
+		return nil
+	}
+	placement, err := json.Marshal(dataVolume.Spec.WorkerPodPlacement)
+	if err != nil {
+		return err
+	}
+	annotations[AnnWorkerPodPlacement] = string(placement)
+	return nil
+}
+
+func containsToleration(tolerations []v1.Toleration, toleration v1.Toleration) bool {
+	for i := range tolerations {
+		if tolerations[i].MatchToleration(&toleration) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateWorkerPodPlacement validates the node selector labels and the tolerations of a worker pod placement
+func ValidateWorkerPodPlacement(placement *cdiv1.WorkerPodPlacement) error {
+	for key, value := range placement.NodeSelector {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return errors.Errorf("invalid node selector label %q: %s", key, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return errors.Errorf("invalid node selector label %q value %q: %s", key, value, strings.Join(errs, "; "))
+		}
+	}
+	for i := range placement.Tolerations {
+		if err := validateToleration(&placement.Tolerations[i]); err != nil {
+			return errors.Wrapf(err, "invalid toleration %d", i)
+		}
+	}
+	return nil
+}
+
+func validateToleration(toleration *v1.Toleration) error {
+	if toleration.Key != "" {
+		if errs := validation.IsQualifiedName(toleration.Key); len(errs) > 0 {
+			return errors.Errorf("invalid key %q: %s", toleration.Key, strings.Join(errs, "; "))
+		}
+	}
+	switch toleration.Operator {
+	case "", v1.TolerationOpEqual:
+		if toleration.Key == "" {
+			return errors.New("the operator must be Exists when the key is empty")
+		}
+		if errs := validation.IsValidLabelValue(toleration.Value); len(errs) > 0 {
+			return errors.Errorf("invalid value %q: %s", toleration.Value, strings.Join(errs, "; "))
+		}
+	case v1.TolerationOpExists:
+		if toleration.Value != "" {
+			return errors.New("the value must be empty when the operator is Exists")
+		}
+	default:
+		return errors.Errorf("invalid operator %q, expected %s or %s", toleration.Operator, v1.TolerationOpEqual, v1.TolerationOpExists)
+	}
+	switch toleration.Effect {
+	case "", v1.TaintEffectNoSchedule, v1.TaintEffectPreferNoSchedule:
+		if toleration.TolerationSeconds != nil {
+			return errors.Errorf("tolerationSeconds requires the %s effect", v1.TaintEffectNoExecute)
+		}
+	case v1.TaintEffectNoExecute:
+	default:
+		return errors.Errorf("invalid effect %q, expected %s, %s or %s", toleration.Effect, v1.TaintEffectNoSchedule, v1.TaintEffectPreferNoSchedule, v1.TaintEffectNoExecute)
+	}
+	return nil
+}
+
+// CheckWorkerPodPlacementAllowed checks the node selector labels and the tolerations of the worker pod placement of a
+// DataVolume are in the allowed placement of the CDIConfig, so that only an administrator can let the worker pods run on
+// dedicated or tainted nodes
+func CheckWorkerPodPlacementAllowed(placement, allowed *cdiv1.WorkerPodPlacement) error {
+	if len(placement.NodeSelector) == 0 && len(placement.Tolerations) == 0 {
+		return nil
+	}
+	if allowed == nil {
+		return errors.New("the CDIConfig does not allow DataVolumes to set a worker pod placement")
+	}
+	for key, value := range placement.NodeSelector {
+		if allowedValue, ok := allowed.NodeSelector[key]; !ok || allowedValue != value {
+			return errors.Errorf("node selector label %s=%s is not allowed by the CDIConfig", key, value)
+		}
+	}
+	for _, toleration := range placement.Tolerations {
+		if !toleratesAsAllowed(allowed.Tolerations, toleration) {
+			return errors.Errorf("toleration of key %q, value %q and effect %q is not allowed by the CDIConfig", toleration.Key, toleration.Value, toleration.Effect)
+		}
+	}
+	return nil
+}
+
+// toleratesAsAllowed returns true if the toleration matches one of the allowed tolerations, the empty operator being Equal
+func toleratesAsAllowed(allowed []v1.Toleration, toleration v1.Toleration) bool {
+	if toleration.Operator == "" {
+		toleration.Operator = v1.TolerationOpEqual
+	}
+	for _, allowedToleration := range allowed {
+		if allowedToleration.Operator == "" {
+			allowedToleration.Operator = v1.TolerationOpEqual
+		}
+		if allowedToleration.MatchToleration(&toleration) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetWorkerPodImage returns the image and pull policy of a worker pod of a DataVolume: the image moved to the registry
+// of the DataVolume, or of the CDIConfig when the DataVolume does not set it, and the pull policy of the DataVolume, of
+// the CDIConfig, or else of the CDI CR
+func GetWorkerPodImage(c client.Client, workerPodImage *cdiv1.WorkerPodImage, image, pullPolicy string) (string, string, error) {
+	cdiconfig := &cdiv1.CDIConfig{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiconfig); err != nil {
+		return "", "", err
+	}
+
+	var registry string
+	var policy v1.PullPolicy
+	if workerPodImage != nil {
+		// the image may come from a PVC annotation, not validated by the DataVolume webhook
+		if err := ValidateWorkerPodImage(workerPodImage); err != nil {
+			return "", "", err
+		}
+		if err := CheckWorkerPodImageAllowed(workerPodImage, cdiconfig.Spec.AllowedWorkerPodRegistries); err != nil {
+			return "", "", err
+		}
+		registry = workerPodImage.Registry
+		policy = workerPodImage.PullPolicy
+	}
+	if defaults := cdiconfig.Spec.WorkerPodImage; defaults != nil {
+		if err := ValidateWorkerPodImage(defaults); err != nil {
+			return "", "", errors.Wrap(err, "invalid CDIConfig worker pod image")
+		}
+		if registry == "" {
+			registry = defaults.Registry
+		}
+		if policy == "" {
+			policy = defaults.PullPolicy
+		}
+	}
+	if registry != "" {
+		var err error
+		if image, err = ReplaceImageRegistry(image, registry); err != nil {
+			return "", "", err
+		}
+	}
+	if policy != "" {
+		pullPolicy = string(policy)
+	}
+	return image, pullPolicy, nil
+}
+
+// GetPvcWorkerPodImage returns the image and pull policy of a worker pod of the PVC, from the image registry and pull
+// policy of its AnnWorkerPodImage annotation
+func GetPvcWorkerPodImage(c client.Client, pvc *v1.PersistentVolumeClaim, image, pullPolicy string) (string, string, error) {
+	var workerPodImage *cdiv1.WorkerPodImage
+	if value, ok := pvc.GetAnnotations()[AnnWorkerPodImage]; ok {
+		workerPodImage = &cdiv1.WorkerPodImage{}
+		if err := json.Unmarshal([]byte(value), workerPodImage); err != nil {
+			return "", "", errors.Wrapf(err, "invalid %s annotation", AnnWorkerPodImage)
+		}
+	}
+	return GetWorkerPodImage(c, workerPodImage, image, pullPolicy)
+}
+
+// SetWorkerPodImageAnnotation sets the AnnWorkerPodImage annotation of the PVC populated by the DataVolume
+func SetWorkerPodImageAnnotation(dataVolume *cdiv1.DataVolume, annotations map[string]string) error {
+	if dataVolume.Spec.WorkerPodImage == nil {
+		return nil
+	}
+	workerPodImage, err := json.Marshal(dataVolume.Spec.WorkerPodImage)
+	if err != nil {
+		return err
+	}
+	annotations[AnnWorkerPodImage] = string(workerPodImage)
+	return nil
+}
+
+// ReplaceImageRegistry replaces the registry and repository of the image with the registry, keeping the name, tag and
+// digest of the image
+func ReplaceImageRegistry(image, registry string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid image %q", image)
+	}
+	replaced := strings.TrimSuffix(registry, "/") + "/" + path.Base(reference.Path(named))
+	if tagged, ok := named.(reference.Tagged); ok {
+		replaced += ":" + tagged.Tag()
+	}
+	if digested, ok := named.(reference.Digested); ok {
+		replaced += "@" + digested.Digest().String()
+	}
+	if _, err := reference.ParseNormalizedNamed(replaced); err != nil {
+		return "", errors.Wrapf(err, "invalid image registry %q", registry)
+	}
+	return replaced, nil
+}
+
+// ValidateWorkerPodImage validates the image registry and pull policy overriding the ones of the worker pods
+func ValidateWorkerPodImage(workerPodImage *cdiv1.WorkerPodImage) error {
+	if workerPodImage.Registry != "" {
+		// the registry must be a name once the image name is appended, without a tag or a digest
+		named, err := reference.ParseNormalizedNamed(strings.TrimSuffix(workerPodImage.Registry, "/") + "/image")
+		if err != nil || !reference.IsNameOnly(named) {
+			return errors.Errorf("invalid image registry %q, expected a registry host with an optional repository path", workerPodImage.Registry)
+		}
+	}
+	switch workerPodImage.PullPolicy {
+	case "", v1.PullAlways, v1.PullIfNotPresent, v1.PullNever:
+	default:
+		return errors.Errorf("invalid image pull policy %q, expected %s, %s or %s", workerPodImage.PullPolicy, v1.PullAlways, v1.PullIfNotPresent, v1.PullNever)
+	}
+	return nil
+}
+
+// CheckWorkerPodImageAllowed checks the image registry of the worker pod image of a DataVolume is in the allowed
+// registries of the CDIConfig, so that only an administrator can let the worker pods, some of them holding the client
+// certificates of CDI, run other images
+func CheckWorkerPodImageAllowed(workerPodImage *cdiv1.WorkerPodImage, allowed []string) error {
+	if workerPodImage.Registry == "" {
+		return nil
+	}
+	registry := strings.TrimSuffix(workerPodImage.Registry, "/")
+	for _, allowedRegistry := range allowed {
+		if strings.TrimSuffix(allowedRegistry, "/") == registry {
+			return nil
+		}
+	}
+	return errors.Errorf("image registry %q is not allowed by the CDIConfig", workerPodImage.Registry)
+}
+
 // GetActiveCDI returns the active CDI CR
 func GetActiveCDI(c client.Client) (*cdiv1.CDI, error) {
 	crList := &cdiv1.CDIList{}
@@ -535,13 +1235,25 @@ func IsPopulated(pvc *v1.PersistentVolumeClaim, c client.Client) (bool, error) {
 	})
 }
 
-// GetPreallocation retuns the preallocation setting for DV, falling back to StorageClass and global setting (in this order)
+// GetPreallocation retuns the preallocation setting for DV, falling back to StorageProfile and global setting (in this order)
 func GetPreallocation(client client.Client, dataVolume *cdiv1.DataVolume) bool {
 	// First, the DV's preallocation
 	if dataVolume.Spec.Preallocation != nil {
 		return *dataVolume.Spec.Preallocation
 	}
 
+	return getStorageClassPreallocation(client, getDataVolumeStorageClassName(dataVolume))
+}
+
+// getStorageClassPreallocation returns the preallocation recommended by the StorageProfile of the storage class, falling
+// back to the global setting
+func getStorageClassPreallocation(client client.Client, storageClassName *string) bool {
+	if storageClass, err := GetStorageClassByName(client, storageClassName); err == nil && storageClass != nil {
+		if storageProfile := getStorageProfile(client, storageClass.Name); storageProfile != nil && storageProfile.Status.Preallocation != nil {
+			return *storageProfile.Status.Preallocation
+		}
+	}
+
 	cdiconfig := &cdiv1.CDIConfig{}
 	if err := client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiconfig); err != nil {
 		klog.Errorf("Unable to find CDI configuration, %v\n", err)
@@ -551,15 +1263,278 @@ func GetPreallocation(client client.Client, dataVolume *cdiv1.DataVolume) bool {
 	return cdiconfig.Status.Preallocation
 }
 
+// GetImportMaxAttempts returns the number of failed attempts after which the PVC import fails, falling back to the global
+// setting. Zero means the import is retried indefinitely.
+func GetImportMaxAttempts(client client.Client, pvc *v1.PersistentVolumeClaim) int32 {
+	if val, ok := pvc.Annotations[AnnImportMaxAttempts]; ok {
+		maxAttempts, err := strconv.ParseInt(val, 10, 32)
+		if err == nil && maxAttempts > 0 {
+			return int32(maxAttempts)
+		}
+		klog.Errorf("Ignoring invalid %s annotation %q on PVC %s/%s", AnnImportMaxAttempts, val, pvc.Namespace, pvc.Name)
+	}
+
+	cdiconfig := &cdiv1.CDIConfig{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiconfig); err != nil {
+		klog.Errorf("Unable to find CDI configuration, %v\n", err)
+		return 0
+	}
+	if cdiconfig.Spec.ImportMaxAttempts != nil && *cdiconfig.Spec.ImportMaxAttempts > 0 {
+		return *cdiconfig.Spec.ImportMaxAttempts
+	}
+	return 0
+}
+
+// GetCompletionTimeout returns the time from its creation within which the DataVolume must succeed, falling back to the
+// global setting. Zero means no timeout.
+func GetCompletionTimeout(client client.Client, dv *cdiv1.DataVolume) time.Duration {
+	if val, ok := dv.Annotations[AnnCompletionTimeout]; ok {
+		timeout, err := time.ParseDuration(val)
+		if err == nil && timeout > 0 {
+			return timeout
+		}
+		// rejected by the webhook, only set before it validated the annotation
+		klog.Errorf("Ignoring invalid %s annotation %q on DataVolume %s/%s", AnnCompletionTimeout, val, dv.Namespace, dv.Name)
+	}
+
+	cdiconfig := &cdiv1.CDIConfig{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiconfig); err != nil {
+		klog.Errorf("Unable to find CDI configuration, %v\n", err)
+		return 0
+	}
+	if cdiconfig.Spec.DataVolumeCompletionTimeout != nil && cdiconfig.Spec.DataVolumeCompletionTimeout.Duration > 0 {
+		return cdiconfig.Spec.DataVolumeCompletionTimeout.Duration
+	}
+	return 0
+}
+
+// DefaultMaxMemoryScratchSpaceSize is the largest scratch space backed by a memory emptyDir without MaxEmptyDirSize,
+// the memory of the emptyDir being added to the memory request of the importer pod
+var DefaultMaxMemoryScratchSpaceSize = resource.MustParse("1Gi")
+
+// GetScratchSpaceBackend returns the kind of volume backing the scratch space of the PVC importer pod, falling back to
+// the global setting. A scratch space larger than the global MaxEmptyDirSize is always backed by a PVC, as is a memory
+// backed scratch space larger than DefaultMaxMemoryScratchSpaceSize without MaxEmptyDirSize.
+func GetScratchSpaceBackend(client client.Client, pvc *v1.PersistentVolumeClaim) cdiv1.ScratchSpaceBackend {
+	cdiconfig := &cdiv1.CDIConfig{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiconfig); err != nil {
+		klog.Errorf("Unable to find CDI configuration, %v\n", err)
+		return cdiv1.ScratchSpaceBackendPVC
+	}
+	config := cdiconfig.Spec.ScratchSpace
+	if config == nil {
+		config = &cdiv1.ScratchSpaceConfig{}
+	}
+
+	backend := config.Backend
+	if val, ok := pvc.Annotations[AnnScratchSpaceBackend]; ok {
+		if IsValidScratchSpaceBackend(val) {
+			backend = cdiv1.ScratchSpaceBackend(val)
+		} else {
+			klog.Errorf("Ignoring invalid %s annotation %q on PVC %s/%s", AnnScratchSpaceBackend, val, pvc.Namespace, pvc.Name)
+		}
+	}
+	if backend == "" || backend == cdiv1.ScratchSpaceBackendPVC {
+		return cdiv1.ScratchSpaceBackendPVC
+	}
+
+	maxSize := config.MaxEmptyDirSize
+	if maxSize == nil && backend == cdiv1.ScratchSpaceBackendEmptyDirMemory {
+		maxSize = &DefaultMaxMemoryScratchSpaceSize
+	}
+	if maxSize != nil {
+		size, ok := pvc.Spec.Resources.Requests[v1.ResourceStorage]
+		if !ok || size.Cmp(*maxSize) > 0 {
+			klog.V(3).Infof("Scratch space of PVC %s/%s exceeds the maximum emptyDir size %s, using a PVC", pvc.Namespace, pvc.Name, maxSize.String())
+			return cdiv1.ScratchSpaceBackendPVC
+		}
+	}
+	return backend
+}
+
+// IsValidScratchSpaceBackend returns true if backend is a known kind of scratch space volume
+func IsValidScratchSpaceBackend(backend string) bool {
+	switch cdiv1.ScratchSpaceBackend(backend) {
+	case cdiv1.ScratchSpaceBackendPVC, cdiv1.ScratchSpaceBackendEmptyDirDisk, cdiv1.ScratchSpaceBackendEmptyDirMemory:
+		return true
+	}
+	return false
+}
+
+// GetMaxParallelWorkerPods returns the maximum number of worker pods CDI runs simultaneously, zero means no limit
+func GetMaxParallelWorkerPods(client client.Client) int32 {
+	cdiconfig := &cdiv1.CDIConfig{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiconfig); err != nil {
+		klog.Errorf("Unable to find CDI configuration, %v\n", err)
+		return 0
+	}
+	if cdiconfig.Spec.MaxParallelWorkerPods != nil && *cdiconfig.Spec.MaxParallelWorkerPods > 0 {
+		return *cdiconfig.Spec.MaxParallelWorkerPods
+	}
+	return 0
+}
+
+// GetDataImportCronPolling returns the maximum number of DataImportCron sources polled concurrently, and the maximum
+// number of polls per minute of the same registry, zero meaning no limit
+func GetDataImportCronPolling(client client.Client) (int32, int32) {
+	cdiconfig := &cdiv1.CDIConfig{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiconfig); err != nil {
+		klog.Errorf("Unable to find CDI configuration, %v\n", err)
+		return 1, 0
+	}
+	parallelism, pollsPerMinute := int32(1), int32(0)
+	if config := cdiconfig.Spec.DataImportCronPolling; config != nil {
+		if config.Parallelism != nil && *config.Parallelism > 0 {
+			parallelism = *config.Parallelism
+		}
+		if config.RegistryPollsPerMinute != nil && *config.RegistryPollsPerMinute > 0 {
+			pollsPerMinute = *config.RegistryPollsPerMinute
+		}
+	}
+	return parallelism, pollsPerMinute
+}
+
+// CopyTargetImageAnnotations copies the format and compression of the image written to the source of a clone, from
+// the source annotations to the target ones, as the clone copies the image as is. A raw image has none.
+func CopyTargetImageAnnotations(source, target map[string]string) {
+	for _, key := range []string{AnnImageTargetFormat, AnnImageTargetCompression} {
+		if value, ok := source[key]; ok {
+			target[key] = value
+		}
+	}
+}
+
+// GetCloneAnnotationAllowlist returns the annotations copied from the source to the target PVC of a host-assisted
+// clone: the image annotations recorded by CDI, and the ones allowed in the CDI config
+func GetCloneAnnotationAllowlist(client client.Client) []string {
+	allowlist := []string{AnnImageFormat, AnnImageVirtualSize}
+	cdiconfig := &cdiv1.CDIConfig{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiconfig); err != nil {
+		klog.Errorf("Unable to find CDI configuration, %v\n", err)
+		return allowlist
+	}
+	return append(allowlist, cdiconfig.Spec.CloneAnnotationAllowlist...)
+}
+
+// IsWorkerPodQueued returns true if the worker pod of the PVC waits for a slot under the MaxParallelWorkerPods limit
+func IsWorkerPodQueued(pvc *v1.PersistentVolumeClaim) bool {
+	return pvc.Annotations[AnnWorkerPodQueued] == "true"
+}
+
+// IsCompletionTimedOut returns true if the DataVolume of the PVC failed its completion timeout
+func IsCompletionTimedOut(pvc *v1.PersistentVolumeClaim) bool {
+	return pvc.Annotations[AnnCompletionTimedOut] == "true"
+}
+
+// IsTransferStopped returns true if the worker pods of the PVC must be stopped and not recreated, because its
+// DataVolume failed its completion timeout, or was canceled after adopting the PVC
+func IsTransferStopped(pvc *v1.PersistentVolumeClaim) bool {
+	return IsCompletionTimedOut(pvc) || pvc.Annotations[AnnCanceled] == "true"
+}
+
+// IsVerifyOnly returns true if the DataVolume only verifies its import source
+func IsVerifyOnly(dv *cdiv1.DataVolume) bool {
+	return dv.Annotations[AnnVerifyOnly] == "true"
+}
+
+// GetImportVerifyPodName returns the name of the importer pod verifying the source of the DataVolume
+func GetImportVerifyPodName(dvName string) string {
+	return naming.GetResourceName("importer-verify", dvName)
+}
+
+// GetInlineSourceConfigMapName returns the name of the ConfigMap holding the image of the inline source of the DataVolume
+func GetInlineSourceConfigMapName(dvName string) string {
+	return naming.GetResourceName(dvName, "inline-source")
+}
+
+func getDataVolumeStorageClassName(dataVolume *cdiv1.DataVolume) *string {
+	if dataVolume.Spec.PVC != nil {
+		return dataVolume.Spec.PVC.StorageClassName
+	}
+	if dataVolume.Spec.Storage != nil {
+		return dataVolume.Spec.Storage.StorageClassName
+	}
+	return nil
+}
+
 // GetPriorityClass gets PVC priority class
 func GetPriorityClass(pvc *v1.PersistentVolumeClaim) string {
 	anno := pvc.GetAnnotations()
 	return anno[AnnPriorityClassName]
 }
 
+// GetTopologyRequirements returns the node selector requirements of the AnnTopology annotation, nil if it is not set.
+// The annotation is a label selector on the node topology labels, for instance
+// "topology.kubernetes.io/zone in (us-east-1a,us-east-1b),topology.kubernetes.io/region=us-east-1".
+func GetTopologyRequirements(annotations map[string]string) ([]v1.NodeSelectorRequirement, error) {
+	topology, ok := annotations[AnnTopology]
+	if !ok {
+		return nil, nil
+	}
+	selector, err := labels.Parse(topology)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid topology %q", topology)
+	}
+	selectorRequirements, _ := selector.Requirements()
+	if len(selectorRequirements) == 0 {
+		return nil, errors.Errorf("topology %q has no constraint", topology)
+	}
+	var requirements []v1.NodeSelectorRequirement
+	for _, r := range selectorRequirements {
+		var operator v1.NodeSelectorOperator
+		switch r.Operator() {
+		case selection.In, selection.Equals, selection.DoubleEquals:
+			operator = v1.NodeSelectorOpIn
+		case selection.NotIn, selection.NotEquals:
+			operator = v1.NodeSelectorOpNotIn
+		case selection.Exists:
+			operator = v1.NodeSelectorOpExists
+		case selection.DoesNotExist:
+			operator = v1.NodeSelectorOpDoesNotExist
+		default:
+			return nil, errors.Errorf("unsupported operator %s in topology %q", r.Operator(), topology)
+		}
+		requirements = append(requirements, v1.NodeSelectorRequirement{
+			Key:      r.Key(),
+			Operator: operator,
+			Values:   r.Values().List(),
+		})
+	}
+	return requirements, nil
+}
+
+// SetTopologyNodeAffinity requires the pod to run on the nodes of the topology of the PVC AnnTopology annotation,
+// in addition to the node affinity it already requires
+func SetTopologyNodeAffinity(podSpec *v1.PodSpec, pvc *v1.PersistentVolumeClaim) error {
+	requirements, err := GetTopologyRequirements(pvc.GetAnnotations())
+	if err != nil || len(requirements) == 0 {
+		return err
+	}
+	// The affinity may be shared with the workload node placement of the CDI CR
+	affinity := podSpec.Affinity.DeepCopy()
+	if affinity == nil {
+		affinity = &v1.Affinity{}
+	}
+	if affinity.NodeAffinity == nil {
+		affinity.NodeAffinity = &v1.NodeAffinity{}
+	}
+	required := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if required == nil || len(required.NodeSelectorTerms) == 0 {
+		required = &v1.NodeSelector{NodeSelectorTerms: []v1.NodeSelectorTerm{{}}}
+	}
+	// The terms are ORed, the topology has to be required by each of them
+	for i := range required.NodeSelectorTerms {
+		required.NodeSelectorTerms[i].MatchExpressions = append(required.NodeSelectorTerms[i].MatchExpressions, requirements...)
+	}
+	affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = required
+	podSpec.Affinity = affinity
+	return nil
+}
+
 // ShouldDeletePod returns whether the PVC workload pod should be deleted
 func ShouldDeletePod(pvc *v1.PersistentVolumeClaim) bool {
-	return pvc.GetAnnotations()[AnnPodRetainAfterCompletion] != "true" || pvc.GetAnnotations()[AnnRequiresScratch] == "true" || pvc.DeletionTimestamp != nil
+	return pvc.GetAnnotations()[AnnPodRetainAfterCompletion] != "true" || pvc.GetAnnotations()[AnnRequiresScratch] == "true" ||
+		IsTransferStopped(pvc) || pvc.DeletionTimestamp != nil
 }
 
 // AddFinalizer adds a finalizer to a resource
@@ -616,12 +1591,22 @@ func ValidateCloneTokenPVC(t string, v token.Validator, source, target *v1.Persi
 
 // ValidateCloneTokenDV validates clone token for DV
 func ValidateCloneTokenDV(validator token.Validator, dv *cdiv1.DataVolume) error {
+	return validateCloneTokenDV(validator, dv, AnnCloneToken, "")
+}
+
+// ValidateCloneResyncTokenDV validates the token issued to the user asking for the current re-sync of the clone
+// DataVolume, a re-sync of a clone from another namespace needing the user to be authorized again
+func ValidateCloneResyncTokenDV(validator token.Validator, dv *cdiv1.DataVolume) error {
+	return validateCloneTokenDV(validator, dv, AnnCloneResyncToken, dv.Annotations[AnnCloneResync])
+}
+
+func validateCloneTokenDV(validator token.Validator, dv *cdiv1.DataVolume, annotation, resync string) error {
 	sourceName, sourceNamespace := GetCloneSourceNameAndNamespace(dv)
 	if sourceNamespace == "" || sourceNamespace == dv.Namespace {
 		return nil
 	}
 
-	tok, ok := dv.Annotations[AnnCloneToken]
+	tok, ok := dv.Annotations[annotation]
 	if !ok {
 		return errors.New("clone token missing")
 	}
@@ -630,6 +1615,9 @@ func ValidateCloneTokenDV(validator token.Validator, dv *cdiv1.DataVolume) error
 	if err != nil {
 		return errors.Wrap(err, "error verifying token")
 	}
+	if tokenData.Params["resync"] != resync {
+		return errors.New("invalid token")
+	}
 
 	tokenResourceName := getTokenResourceNameDataVolume(dv.Spec.Source)
 	if tokenResourceName == "" {
@@ -692,8 +1680,9 @@ func validateContentTypes(sourcePVC *v1.PersistentVolumeClaim, spec *cdiv1.DataV
 	return sourceContentType == targetContentType, sourceContentType, targetContentType
 }
 
-// ValidateClone compares a clone spec against its source PVC to validate its creation
-func ValidateClone(sourcePVC *v1.PersistentVolumeClaim, spec *cdiv1.DataVolumeSpec) error {
+// ValidateClone compares a clone spec against its source PVC to validate its creation. The filesystem overhead of the
+// source storage class sizes the disk image of a filesystem source converted to a block target.
+func ValidateClone(sourcePVC *v1.PersistentVolumeClaim, spec *cdiv1.DataVolumeSpec, sourceFilesystemOverhead cdiv1.Percent) error {
 	var targetResources v1.ResourceRequirements
 
 	valid, sourceContentType, targetContentType := validateContentTypes(sourcePVC, spec)
@@ -715,6 +1704,16 @@ func ValidateClone(sourcePVC *v1.PersistentVolumeClaim, spec *cdiv1.DataVolumeSp
 		}
 	}
 
+	// A disk image converted between volume modes is not as large as the volume holding it. The disk.img file of a
+	// filesystem source is at most the source size without the filesystem overhead, and the target of a block source
+	// is enlarged with the filesystem overhead by the clone controllers.
+	if isCloneVolumeModeConversion(sourcePVC, spec) {
+		if isSizelessClone || GetVolumeMode(sourcePVC) == v1.PersistentVolumeBlock {
+			return nil
+		}
+		return validateConvertedCloneSize(sourcePVC, targetResources, sourceFilesystemOverhead)
+	}
+
 	// TODO: Spec.Storage API needs a better more complex check to validate clone size - to account for fsOverhead
 	// simple size comparison will not work here
 	if (!isSizelessClone && GetVolumeMode(sourcePVC) == v1.PersistentVolumeBlock) || explicitPvcRequest {
@@ -726,6 +1725,42 @@ func ValidateClone(sourcePVC *v1.PersistentVolumeClaim, spec *cdiv1.DataVolumeSp
 	return nil
 }
 
+// validateConvertedCloneSize verifies a block target is large enough for the disk.img file of a filesystem source, the
+// source size without the filesystem overhead
+func validateConvertedCloneSize(sourcePVC *v1.PersistentVolumeClaim, targetResources v1.ResourceRequirements, sourceFilesystemOverhead cdiv1.Percent) error {
+	sourceSize, ok := sourcePVC.Status.Capacity[v1.ResourceStorage]
+	if !ok {
+		sourceSize = sourcePVC.Spec.Resources.Requests[v1.ResourceStorage]
+	}
+	fsOverhead, err := strconv.ParseFloat(string(sourceFilesystemOverhead), 64)
+	if err != nil {
+		return errors.Wrapf(err, "invalid filesystem overhead %q", sourceFilesystemOverhead)
+	}
+	imageSize := util.RoundDown(int64(float64(sourceSize.Value())*(1-fsOverhead)), util.DefaultAlignBlockSize)
+	targetRequest := targetResources.Requests[v1.ResourceStorage]
+	if targetRequest.Value() < imageSize {
+		return errors.Errorf("target resources requests storage size is smaller than the disk image of the source (%s < %s)",
+			targetRequest.String(), resource.NewScaledQuantity(imageSize, 0).String())
+	}
+	return nil
+}
+
+// isCloneVolumeModeConversion returns true if the KubeVirt disk image of the source is converted between block and
+// filesystem volumes. Only a target asking for a volume mode is considered converted, the size of one without is
+// compared as is.
+func isCloneVolumeModeConversion(sourcePVC *v1.PersistentVolumeClaim, spec *cdiv1.DataVolumeSpec) bool {
+	if GetContentType(sourcePVC) != string(cdiv1.DataVolumeKubeVirt) {
+		return false
+	}
+	var targetVolumeMode *v1.PersistentVolumeMode
+	if spec.PVC != nil {
+		targetVolumeMode = spec.PVC.VolumeMode
+	} else if spec.Storage != nil {
+		targetVolumeMode = spec.Storage.VolumeMode
+	}
+	return targetVolumeMode != nil && GetVolumeMode(sourcePVC) != *targetVolumeMode
+}
+
 // ValidateSnapshotClone compares a snapshot clone spec against its source snapshot to validate its creation
 func ValidateSnapshotClone(sourceSnapshot *snapshotv1.VolumeSnapshot, spec *cdiv1.DataVolumeSpec) error {
 	var sourceResources, targetResources v1.ResourceRequirements
@@ -827,13 +1862,53 @@ func GetSource(pvc *corev1.PersistentVolumeClaim) string {
 		SourceNone,
 		SourceRegistry,
 		SourceImageio,
-		SourceVDDK:
+		SourceVDDK,
+		SourceInline,
+		SourceNBD,
+		SourceRemote,
+		SourceFTP,
+		SourceAzure,
+		SourceSFTP:
 	default:
 		source = SourceHTTP
 	}
 	return source
 }
 
+// dataVolumeImportSources are the DataVolume sources imported by the importer, with their source type
+var dataVolumeImportSources = []struct {
+	sourceType string
+	isSet      func(source *cdiv1.DataVolumeSource) bool
+}{
+	{SourceHTTP, func(source *cdiv1.DataVolumeSource) bool { return source.HTTP != nil }},
+	{SourceS3, func(source *cdiv1.DataVolumeSource) bool { return source.S3 != nil }},
+	{SourceGCS, func(source *cdiv1.DataVolumeSource) bool { return source.GCS != nil }},
+	{SourceRegistry, func(source *cdiv1.DataVolumeSource) bool { return source.Registry != nil }},
+	{SourceNone, func(source *cdiv1.DataVolumeSource) bool { return source.Blank != nil }},
+	{SourceImageio, func(source *cdiv1.DataVolumeSource) bool { return source.Imageio != nil }},
+	{SourceVDDK, func(source *cdiv1.DataVolumeSource) bool { return source.VDDK != nil }},
+	{SourceInline, func(source *cdiv1.DataVolumeSource) bool { return source.Inline != nil }},
+	{SourceNBD, func(source *cdiv1.DataVolumeSource) bool { return source.NBD != nil }},
+	{SourceRemote, func(source *cdiv1.DataVolumeSource) bool { return source.Remote != nil }},
+	{SourceFTP, func(source *cdiv1.DataVolumeSource) bool { return source.FTP != nil }},
+	{SourceAzure, func(source *cdiv1.DataVolumeSource) bool { return source.Azure != nil }},
+	{SourceSFTP, func(source *cdiv1.DataVolumeSource) bool { return source.SFTP != nil }},
+}
+
+// GetDataVolumeImportSource returns the source type the importer imports the source of a DataVolume with, an empty
+// string if the source is not imported
+func GetDataVolumeImportSource(source *cdiv1.DataVolumeSource) string {
+	if source == nil {
+		return ""
+	}
+	for _, importSource := range dataVolumeImportSources {
+		if importSource.isSet(source) {
+			return importSource.sourceType
+		}
+	}
+	return ""
+}
+
 // GetEndpoint returns the endpoint string which contains the full path URI of the target object to be copied.
 func GetEndpoint(pvc *corev1.PersistentVolumeClaim) (string, error) {
 	ep, found := pvc.Annotations[AnnEndpoint]
@@ -864,7 +1939,7 @@ func ValidateRequestedCloneSize(sourceResources corev1.ResourceRequirements, tar
 	targetRequest := targetResources.Requests[corev1.ResourceStorage]
 	// Verify that the target PVC size is equal or larger than the source.
 	if sourceRequest.Value() > targetRequest.Value() {
-		return errors.New("target resources requests storage size is smaller than the source")
+		return errors.Errorf("target resources requests storage size is smaller than the source (%s < %s)", targetRequest.String(), sourceRequest.String())
 	}
 	return nil
 }
@@ -874,6 +1949,11 @@ func CreateCloneSourcePodName(targetPvc *corev1.PersistentVolumeClaim) string {
 	return string(targetPvc.GetUID()) + common.ClonerSourcePodNameSuffix
 }
 
+// IsAdoptedPVC returns true if a DataVolume adopted the existing PVC instead of creating it
+func IsAdoptedPVC(pvc *v1.PersistentVolumeClaim) bool {
+	return pvc.Annotations[AnnAdoptedPVC] == "true"
+}
+
 // IsPVCComplete returns true if a PVC is in 'Succeeded' phase, false if not
 func IsPVCComplete(pvc *v1.PersistentVolumeClaim) bool {
 	if pvc != nil {
@@ -1208,7 +2288,9 @@ func GetCloneSourceNameAndNamespace(dv *cdiv1.DataVolume) (name, namespace strin
 func IsWaitForFirstConsumerEnabled(obj metav1.Object, gates featuregates.FeatureGates) (bool, error) {
 	// when PVC requests immediateBinding it cannot honor wffc logic
 	_, isImmediateBindingRequested := obj.GetAnnotations()[AnnImmediateBinding]
-	pvcHonorWaitForFirstConsumer := !isImmediateBindingRequested
+	// when PVC is pinned to a topology, its worker pod is the first consumer binding the volume in that topology
+	_, isTopologyRequested := obj.GetAnnotations()[AnnTopology]
+	pvcHonorWaitForFirstConsumer := !isImmediateBindingRequested && !isTopologyRequested
 	globalHonorWaitForFirstConsumer, err := gates.HonorWaitForFirstConsumerEnabled()
 	if err != nil {
 		return false, err
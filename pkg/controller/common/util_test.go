@@ -7,6 +7,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
 )
 
 var _ = Describe("GetRequestedImageSize", func() {
@@ -53,6 +54,18 @@ var _ = Describe("validateContentTypes", func() {
 	)
 })
 
+var _ = Describe("GetDataVolumeImportSource", func() {
+	table.DescribeTable("should return", func(source *cdiv1.DataVolumeSource, expected string) {
+		Expect(GetDataVolumeImportSource(source)).To(Equal(expected))
+	},
+		table.Entry("the source type of an import source", &cdiv1.DataVolumeSource{Azure: &cdiv1.DataVolumeSourceAzure{}}, SourceAzure),
+		table.Entry("none for a blank image", &cdiv1.DataVolumeSource{Blank: &cdiv1.DataVolumeBlankImage{}}, SourceNone),
+		table.Entry("nothing for a clone source", &cdiv1.DataVolumeSource{PVC: &cdiv1.DataVolumeSourcePVC{}}, ""),
+		table.Entry("nothing for an upload source", &cdiv1.DataVolumeSource{Upload: &cdiv1.DataVolumeSourceUpload{}}, ""),
+		table.Entry("nothing without source", nil, ""),
+	)
+})
+
 var _ = Describe("GetDefaultStorageClass", func() {
 	It("Should return the default storage class name", func() {
 		client := CreateClient(
@@ -75,6 +88,34 @@ var _ = Describe("GetDefaultStorageClass", func() {
 	})
 })
 
+var _ = Describe("GetCloneStreams", func() {
+	const storageClassName = "test-storage-class"
+
+	table.DescribeTable("Should return the number of clone streams", func(configStreams, profileStreams *int32, sourceMode, targetMode v1.PersistentVolumeMode, targetFormat string, expected int32) {
+		cdiConfig := &cdiv1.CDIConfig{ObjectMeta: metav1.ObjectMeta{Name: common.ConfigName}}
+		cdiConfig.Spec.CloneStreams = configStreams
+		storageProfile := &cdiv1.StorageProfile{ObjectMeta: metav1.ObjectMeta{Name: storageClassName}}
+		storageProfile.Status.CloneStreams = profileStreams
+		sourcePvc := CreatePvc("source", "default", nil, nil)
+		sourcePvc.Spec.VolumeMode = &sourceMode
+		targetPvc := CreatePvcInStorageClass("target", "default", &[]string{storageClassName}[0], map[string]string{AnnTargetFormat: targetFormat}, nil, v1.ClaimBound)
+		targetPvc.Spec.VolumeMode = &targetMode
+		client := CreateClient(cdiConfig, storageProfile)
+
+		streams, err := GetCloneStreams(client, sourcePvc, targetPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(streams).To(Equal(expected))
+	},
+		table.Entry("one by default", nil, nil, v1.PersistentVolumeBlock, v1.PersistentVolumeBlock, "", int32(1)),
+		table.Entry("the streams of the CDIConfig", &[]int32{4}[0], nil, v1.PersistentVolumeBlock, v1.PersistentVolumeBlock, "", int32(4)),
+		table.Entry("the streams of the storage profile over the CDIConfig", &[]int32{4}[0], &[]int32{1}[0], v1.PersistentVolumeBlock, v1.PersistentVolumeBlock, "", int32(1)),
+		table.Entry("at most the maximum streams", &[]int32{64}[0], nil, v1.PersistentVolumeBlock, v1.PersistentVolumeBlock, "", int32(common.MaxCloneStreams)),
+		table.Entry("one for a filesystem source", &[]int32{4}[0], nil, v1.PersistentVolumeFilesystem, v1.PersistentVolumeBlock, "", int32(1)),
+		table.Entry("one for a filesystem target", &[]int32{4}[0], nil, v1.PersistentVolumeBlock, v1.PersistentVolumeFilesystem, "", int32(1)),
+		table.Entry("one for a qcow2 target", &[]int32{4}[0], nil, v1.PersistentVolumeBlock, v1.PersistentVolumeBlock, "qcow2", int32(1)),
+	)
+})
+
 func createPvcNoSize(name, ns string, annotations, labels map[string]string) *v1.PersistentVolumeClaim {
 	return &v1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
@@ -683,6 +683,10 @@ func GetImportProxyConfig(config *cdiv1.CDIConfig, field string) (string, error)
 		if config.Status.ImportProxy.TrustedCAProxy != nil {
 			return *config.Status.ImportProxy.TrustedCAProxy, nil
 		}
+	case common.ImportProxyAuthSecretName:
+		if config.Status.ImportProxy.ProxyAuthSecret != nil {
+			return *config.Status.ImportProxy.ProxyAuthSecret, nil
+		}
 	default:
 		return "", errors.Errorf("CDIConfig ImportProxy does not have the field: %s\n", field)
 	}
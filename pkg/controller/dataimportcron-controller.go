@@ -22,6 +22,7 @@ import (
 	"net/url"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -69,6 +70,14 @@ const (
 	ErrDataSourceAlreadyManaged = "ErrDataSourceAlreadyManaged"
 	// MessageDataSourceAlreadyManaged provides a const to form DataSource already managed error message
 	MessageDataSourceAlreadyManaged = "DataSource %s is already managed by DataImportCron %s"
+	// SourceUnreachable provides a const to indicate the source could not be polled several consecutive times
+	SourceUnreachable = "SourceUnreachable"
+	// MessageSourceUnreachable provides a const to form the source unreachable message
+	MessageSourceUnreachable = "Source could not be polled %d consecutive times: %s"
+	// SourceReachable provides a const to indicate the source could be polled again after being unreachable
+	SourceReachable = "SourceReachable"
+	// MessageSourceReachable provides a const for the source reachable message
+	MessageSourceReachable = "Source polled successfully after %d consecutive failures"
 
 	prometheusNsLabel       = "ns"
 	prometheusCronNameLabel = "cron_name"
@@ -96,6 +105,7 @@ type DataImportCronReconciler struct {
 	pullPolicy      string
 	cdiNamespace    string
 	installerLabels map[string]string
+	poller          *sourcePoller
 }
 
 const (
@@ -111,12 +121,19 @@ const (
 	AnnLastUseTime = cc.AnnAPIGroup + "/storage.import.lastUseTime"
 	// AnnLastAppliedConfig is the cron last applied configuration
 	AnnLastAppliedConfig = cc.AnnAPIGroup + "/lastAppliedConfiguration"
+	// AnnSourcePollFailures is the number of consecutive failed source polls
+	AnnSourcePollFailures = cc.AnnAPIGroup + "/storage.import.sourcePollFailures"
+	// AnnSourcePollCron is the namespace/name of the DataImportCron whose URL source a Job polls
+	AnnSourcePollCron = cc.AnnAPIGroup + "/storage.import.sourcePollCron"
+	// AnnLastSourcePollJobTime is the completion time of the last URL source poll Job counted in the failed polls
+	AnnLastSourcePollJobTime = cc.AnnAPIGroup + "/storage.import.lastSourcePollJobTime"
 
 	dataImportControllerName    = "dataimportcron-controller"
 	digestPrefix                = "sha256:"
 	digestDvNameSuffixLength    = 12
 	cronJobUIDSuffixLength      = 8
 	defaultImportsToKeepPerCron = 3
+	sourcePollFailureThreshold  = 3
 )
 
 // Reconcile loop for DataImportCronReconciler
@@ -166,6 +183,8 @@ func (r *DataImportCronReconciler) shouldReconcileCron(ctx context.Context, cron
 	return true, nil
 }
 
+// initCron creates the CronJob polling a URL source, and its initial Job polling the source right away. The poll Jobs
+// are created suspended, and started once the poller gives them a poll slot.
 func (r *DataImportCronReconciler) initCron(ctx context.Context, dataImportCron *cdiv1.DataImportCron) error {
 	if isImageStreamSource(dataImportCron) {
 		if dataImportCron.Annotations[AnnNextCronTime] == "" {
@@ -191,10 +210,7 @@ func (r *DataImportCronReconciler) initCron(ctx context.Context, dataImportCron
 	if err != nil {
 		return err
 	}
-	if err := r.client.Create(ctx, job); err != nil {
-		return err
-	}
-	return nil
+	return r.client.Create(ctx, job)
 }
 
 func (r *DataImportCronReconciler) getImageStream(ctx context.Context, imageStreamName, imageStreamNamespace string) (*imagev1.ImageStream, string, error) {
@@ -245,6 +261,22 @@ func getImageStreamDigest(imageStream *imagev1.ImageStream, imageStreamTag strin
 	return tags[tagIdx].Items[0].Image, tags[tagIdx].Items[0].DockerImageReference, nil
 }
 
+// getImageStreamImportError returns an error if the last import of the ImageStream tag from its registry failed
+func getImageStreamImportError(imageStream *imagev1.ImageStream, imageStreamTag string) error {
+	for i, tag := range imageStream.Status.Tags {
+		if tag.Tag != imageStreamTag && (imageStreamTag != "" || i != 0) {
+			continue
+		}
+		for _, cond := range tag.Conditions {
+			if cond.Type == imagev1.ImportSuccess && cond.Status == corev1.ConditionFalse {
+				return errors.Errorf("ImageStream %s tag %s import failed: %s", imageStream.Name, tag.Tag, cond.Message)
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
 func splitImageStreamName(imageStreamName string) (string, string, error) {
 	if subs := strings.Split(imageStreamName, ":"); len(subs) == 1 {
 		return imageStreamName, "", nil
@@ -261,8 +293,12 @@ func (r *DataImportCronReconciler) pollImageStreamDigest(ctx context.Context, da
 			return reconcile.Result{}, err
 		}
 		if nextTime.Before(time.Now()) {
-			if err := r.updateImageStreamDesiredDigest(ctx, dataImportCron); err != nil {
-				return reconcile.Result{}, err
+			// The ImageStream is read from the API server, not from its registry, so the poll is not rate limited
+			delay, err := r.poller.poll(client.ObjectKeyFromObject(dataImportCron), "", func() error {
+				return r.updateImageStreamDesiredDigest(ctx, dataImportCron)
+			})
+			if err != nil || delay > 0 {
+				return reconcile.Result{RequeueAfter: delay}, err
 			}
 		}
 	}
@@ -358,12 +394,30 @@ func (r *DataImportCronReconciler) update(ctx context.Context, dataImportCron *c
 		if err != nil {
 			return res, err
 		}
+	} else if isURLSource(dataImportCron) {
+		jobs, err := r.listPollJobs(ctx, dataImportCron)
+		if err != nil {
+			return res, err
+		}
+		if err := r.countURLSourcePolls(ctx, dataImportCron, jobs); err != nil {
+			return res, err
+		}
+		if res.RequeueAfter, err = r.startPollJobs(ctx, dataImportCron, jobs); err != nil {
+			return res, err
+		}
+	}
+
+	updateUpToDateCondition := func(status corev1.ConditionStatus, message, reason string) {
+		// Keep reporting the source as unreachable until a poll succeeds
+		if getSourcePollFailures(dataImportCron) < sourcePollFailureThreshold {
+			updateDataImportCronCondition(dataImportCron, cdiv1.DataImportCronUpToDate, status, message, reason)
+		}
 	}
 
 	desiredDigest := dataImportCron.Annotations[AnnSourceDesiredDigest]
 	digestUpdated := desiredDigest != "" && (len(imports) == 0 || desiredDigest != imports[0].Digest)
 	if digestUpdated {
-		updateDataImportCronCondition(dataImportCron, cdiv1.DataImportCronUpToDate, corev1.ConditionFalse, "Source digest updated since last import", outdated)
+		updateUpToDateCondition(corev1.ConditionFalse, "Source digest updated since last import", outdated)
 		if dv != nil {
 			if err := r.deleteErroneousDataVolume(ctx, dataImportCron, dv); err != nil {
 				return res, err
@@ -375,11 +429,11 @@ func (r *DataImportCronReconciler) update(ctx context.Context, dataImportCron *c
 			}
 		}
 	} else if importSucceeded {
-		updateDataImportCronCondition(dataImportCron, cdiv1.DataImportCronUpToDate, corev1.ConditionTrue, "Latest import is up to date", upToDate)
+		updateUpToDateCondition(corev1.ConditionTrue, "Latest import is up to date", upToDate)
 	} else if len(imports) > 0 {
-		updateDataImportCronCondition(dataImportCron, cdiv1.DataImportCronUpToDate, corev1.ConditionFalse, "Import is progressing", inProgress)
+		updateUpToDateCondition(corev1.ConditionFalse, "Import is progressing", inProgress)
 	} else {
-		updateDataImportCronCondition(dataImportCron, cdiv1.DataImportCronUpToDate, corev1.ConditionFalse, "No source digest", noDigest)
+		updateUpToDateCondition(corev1.ConditionFalse, "No source digest", noDigest)
 	}
 
 	if err := updateLastExecutionTimestamp(dataImportCron); err != nil {
@@ -464,10 +518,15 @@ func (r *DataImportCronReconciler) updateImageStreamDesiredDigest(ctx context.Co
 	if err != nil {
 		return err
 	}
+	if err := getImageStreamImportError(imageStream, imageStreamTag); err != nil {
+		r.handleSourcePollFailure(dataImportCron, err)
+		return nil
+	}
 	digest, dockerRef, err := getImageStreamDigest(imageStream, imageStreamTag)
 	if err != nil {
 		return err
 	}
+	r.handleSourcePollSuccess(dataImportCron)
 	cc.AddAnnotation(dataImportCron, AnnLastCronTime, time.Now().Format(time.RFC3339))
 	if digest != "" && dataImportCron.Annotations[AnnSourceDesiredDigest] != digest {
 		log.Info("Updating DataImportCron", "digest", digest)
@@ -477,6 +536,167 @@ func (r *DataImportCronReconciler) updateImageStreamDesiredDigest(ctx context.Co
 	return nil
 }
 
+// listPollJobs returns the poll Jobs of the URL source of the cron
+func (r *DataImportCronReconciler) listPollJobs(ctx context.Context, cron *cdiv1.DataImportCron) ([]*batchv1.Job, error) {
+	selector, err := getSelector(map[string]string{common.DataImportCronLabel: getCronJobLabelValue(cron.Namespace, cron.Name)})
+	if err != nil {
+		return nil, err
+	}
+	jobList := &batchv1.JobList{}
+	if err := r.client.List(ctx, jobList, &client.ListOptions{Namespace: r.cdiNamespace, LabelSelector: selector}); err != nil {
+		return nil, err
+	}
+	var jobs []*batchv1.Job
+	for i := range jobList.Items {
+		if jobList.Items[i].Annotations[AnnSourcePollCron] == cron.Namespace+"/"+cron.Name {
+			jobs = append(jobs, &jobList.Items[i])
+		}
+	}
+	return jobs, nil
+}
+
+// isPollJobFinished tells whether a poll Job completed or failed
+func isPollJobFinished(job *batchv1.Job) bool {
+	for _, cond := range job.Status.Conditions {
+		if (cond.Type == batchv1.JobComplete || cond.Type == batchv1.JobFailed) && cond.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// startPollJobs starts the suspended poll Jobs of the URL source once the poller gives them a poll slot, the Jobs being
+// the ones calling the registry. The slot of a Job is freed once it finishes or is deleted. Returns the delay after
+// which to retry starting the Jobs left suspended.
+func (r *DataImportCronReconciler) startPollJobs(ctx context.Context, cron *cdiv1.DataImportCron, jobs []*batchv1.Job) (time.Duration, error) {
+	cronKey := client.ObjectKeyFromObject(cron)
+	registry := getDockerURLRegistry(*cron.Spec.Template.Spec.Source.Registry.URL)
+	keep := map[string]bool{}
+	var retryDelay time.Duration
+	for _, job := range jobs {
+		key := "job/" + job.Name
+		if isPollJobFinished(job) {
+			continue
+		}
+		keep[key] = true
+		if job.Spec.Suspend == nil || !*job.Spec.Suspend {
+			r.poller.adopt(key, cronKey)
+			continue
+		}
+		if delay := r.poller.start(key, cronKey, registry); delay > 0 {
+			if retryDelay == 0 || delay < retryDelay {
+				retryDelay = delay
+			}
+			continue
+		}
+		job.Spec.Suspend = pointer.Bool(false)
+		if err := r.client.Update(ctx, job); err != nil {
+			r.poller.done(key)
+			return 0, cc.IgnoreNotFound(err)
+		}
+		r.log.V(3).Info("Started source poll Job", "name", cron.Name, "job", job.Name)
+	}
+	r.poller.forget(cronKey, keep)
+	return retryDelay, nil
+}
+
+// countURLSourcePolls counts the polls of the URL source from its poll Jobs that finished since the last counted one.
+// The Jobs are deleted shortly after they finish, the controller is triggered as they finish to count them.
+func (r *DataImportCronReconciler) countURLSourcePolls(ctx context.Context, cron *cdiv1.DataImportCron, jobs []*batchv1.Job) error {
+	var lastCounted time.Time
+	if value := cron.Annotations[AnnLastSourcePollJobTime]; value != "" {
+		var err error
+		if lastCounted, err = time.Parse(time.RFC3339, value); err != nil {
+			return err
+		}
+	}
+	type finishedJob struct {
+		job    *batchv1.Job
+		time   time.Time
+		failed bool
+	}
+	var finished []finishedJob
+	for _, job := range jobs {
+		for _, cond := range job.Status.Conditions {
+			if (cond.Type == batchv1.JobComplete || cond.Type == batchv1.JobFailed) && cond.Status == corev1.ConditionTrue &&
+				cond.LastTransitionTime.Time.After(lastCounted) {
+				finished = append(finished, finishedJob{job: job, time: cond.LastTransitionTime.Time, failed: cond.Type == batchv1.JobFailed})
+			}
+		}
+	}
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].time.Before(finished[j].time)
+	})
+	for _, f := range finished {
+		if f.failed {
+			r.handleSourcePollFailure(cron, r.getPollJobError(ctx, f.job))
+		} else {
+			r.handleSourcePollSuccess(cron)
+		}
+		cc.AddAnnotation(cron, AnnLastSourcePollJobTime, f.time.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// getPollJobError returns the error of a failed poll Job, the termination message of its last failed pod
+func (r *DataImportCronReconciler) getPollJobError(ctx context.Context, job *batchv1.Job) error {
+	podList := &corev1.PodList{}
+	if err := r.client.List(ctx, podList, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+		r.log.Error(err, "Failed to list the pods of the poll Job", "job", job.Name)
+	}
+	var message string
+	var finishedAt time.Time
+	for _, pod := range podList.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			if terminated := status.State.Terminated; terminated != nil && terminated.ExitCode != 0 &&
+				terminated.Message != "" && !terminated.FinishedAt.Time.Before(finishedAt) {
+				message = strings.TrimSpace(terminated.Message)
+				finishedAt = terminated.FinishedAt.Time
+			}
+		}
+	}
+	if message == "" {
+		for _, cond := range job.Status.Conditions {
+			if cond.Type == batchv1.JobFailed {
+				message = cond.Message
+			}
+		}
+	}
+	return errors.Errorf("poll Job %s failed: %s", job.Name, message)
+}
+
+// handleSourcePollFailure counts the consecutive failed polls, and reports the source as unreachable once they reach the threshold
+func (r *DataImportCronReconciler) handleSourcePollFailure(cron *cdiv1.DataImportCron, pollErr error) {
+	failures := getSourcePollFailures(cron) + 1
+	cc.AddAnnotation(cron, AnnSourcePollFailures, strconv.Itoa(failures))
+	r.log.Info("Failed to poll source", "name", cron.Name, "failures", failures, "error", pollErr.Error())
+	if failures < sourcePollFailureThreshold {
+		return
+	}
+	msg := fmt.Sprintf(MessageSourceUnreachable, failures, pollErr.Error())
+	updateDataImportCronCondition(cron, cdiv1.DataImportCronUpToDate, corev1.ConditionFalse, msg, SourceUnreachable)
+	if failures == sourcePollFailureThreshold {
+		r.recorder.Event(cron, corev1.EventTypeWarning, SourceUnreachable, msg)
+	}
+}
+
+// handleSourcePollSuccess resets the failed polls count, and reports the recovery if the source was unreachable
+func (r *DataImportCronReconciler) handleSourcePollSuccess(cron *cdiv1.DataImportCron) {
+	failures := getSourcePollFailures(cron)
+	if failures >= sourcePollFailureThreshold {
+		r.recorder.Event(cron, corev1.EventTypeNormal, SourceReachable, fmt.Sprintf(MessageSourceReachable, failures))
+	}
+	delete(cron.Annotations, AnnSourcePollFailures)
+}
+
+func getSourcePollFailures(cron *cdiv1.DataImportCron) int {
+	failures, err := strconv.Atoi(cron.Annotations[AnnSourcePollFailures])
+	if err != nil {
+		return 0
+	}
+	return failures
+}
+
 func (r *DataImportCronReconciler) updateDataSource(ctx context.Context, dataImportCron *cdiv1.DataImportCron) error {
 	log := r.log.WithName("updateDataSource")
 	dataSourceName := dataImportCron.Spec.ManagedDataSource
@@ -617,6 +837,7 @@ func (r *DataImportCronReconciler) garbageCollectOldImports(ctx context.Context,
 func (r *DataImportCronReconciler) cleanup(ctx context.Context, cron types.NamespacedName) error {
 	// Don't keep alerting over a cron thats being deleted, will get set back to 1 again by reconcile loop if needed.
 	DataImportCronOutdatedGauge.With(getPrometheusCronLabels(cron)).Set(0)
+	r.poller.forget(cron, nil)
 	if err := r.deleteJobs(ctx, cron); err != nil {
 		return err
 	}
@@ -684,8 +905,12 @@ func NewDataImportCronController(mgr manager.Manager, log logr.Logger, importerI
 		pullPolicy:      pullPolicy,
 		cdiNamespace:    util.GetNamespace(),
 		installerLabels: installerLabels,
+		poller:          newSourcePoller(mgr.GetClient()),
 	}
-	dataImportCronController, err := controller.New(dataImportControllerName, mgr, controller.Options{Reconciler: reconciler})
+	dataImportCronController, err := controller.New(dataImportControllerName, mgr, controller.Options{
+		Reconciler:              reconciler,
+		MaxConcurrentReconciles: maxSourcePollParallelism,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -730,6 +955,24 @@ func addDataImportCronControllerWatches(mgr manager.Manager, c controller.Contro
 	); err != nil {
 		return err
 	}
+	// The URL source poll Jobs are started as they are created, and counted as they finish, before they are deleted
+	mapPollJobToCron := func(obj client.Object) []reconcile.Request {
+		namespace, name, found := strings.Cut(obj.GetAnnotations()[AnnSourcePollCron], "/")
+		if !found {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}}
+	}
+	if err := c.Watch(&source.Kind{Type: &batchv1.Job{}},
+		handler.EnqueueRequestsFromMapFunc(mapPollJobToCron),
+		predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool { return e.Object.GetAnnotations()[AnnSourcePollCron] != "" },
+			UpdateFunc: func(e event.UpdateEvent) bool { return e.ObjectNew.GetAnnotations()[AnnSourcePollCron] != "" },
+			DeleteFunc: func(e event.DeleteEvent) bool { return e.Object.GetAnnotations()[AnnSourcePollCron] != "" },
+		},
+	); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -789,7 +1032,10 @@ func (r *DataImportCronReconciler) newCronJob(cron *cdiv1.DataImportCron) (*batc
 		},
 		ImagePullPolicy:          corev1.PullPolicy(r.pullPolicy),
 		TerminationMessagePath:   corev1.TerminationMessagePathDefault,
-		TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+	}
+	if cron.Spec.SourceTagPattern != nil {
+		container.Command = append(container.Command, "-tag-pattern", *cron.Spec.SourceTagPattern)
 	}
 
 	volumes := []corev1.Volume{}
@@ -837,7 +1083,18 @@ func (r *DataImportCronReconciler) newCronJob(cron *cdiv1.DataImportCron) (*batc
 					},
 				},
 			},
+			corev1.EnvVar{
+				Name:  common.ImporterRegistryCredentialDirVar,
+				Value: common.ImporterRegistryCredentialDir,
+			},
 		)
+		vm := corev1.VolumeMount{
+			Name:      SecretVolName,
+			MountPath: common.ImporterRegistryCredentialDir,
+			ReadOnly:  true,
+		}
+		container.VolumeMounts = append(container.VolumeMounts, vm)
+		volumes = append(volumes, createSecretVolume(SecretVolName, *regSource.SecretRef))
 	}
 
 	addEnvVar := func(varName, value string) {
@@ -876,6 +1133,14 @@ func (r *DataImportCronReconciler) newCronJob(cron *cdiv1.DataImportCron) (*batc
 			SuccessfulJobsHistoryLimit: pointer.Int32(1),
 			FailedJobsHistoryLimit:     pointer.Int32(1),
 			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						common.DataImportCronLabel: getCronJobLabelValue(cron.Namespace, cron.Name),
+					},
+					Annotations: map[string]string{
+						AnnSourcePollCron: cron.Namespace + "/" + cron.Name,
+					},
+				},
 				Spec: batchv1.JobSpec{
 					Template: corev1.PodTemplateSpec{
 						Spec: corev1.PodSpec{
@@ -889,6 +1154,8 @@ func (r *DataImportCronReconciler) newCronJob(cron *cdiv1.DataImportCron) (*batc
 					},
 					BackoffLimit:            pointer.Int32(2),
 					TTLSecondsAfterFinished: pointer.Int32(10),
+					// Started by the controller once the poller gives the Job a poll slot
+					Suspend: pointer.Bool(true),
 				},
 			},
 		},
@@ -915,6 +1182,7 @@ func (r *DataImportCronReconciler) newInitialJob(cron *cdiv1.DataImportCron, cro
 	if err := r.setJobCommon(cron, job); err != nil {
 		return nil, err
 	}
+	cc.AddAnnotation(job, AnnSourcePollCron, cron.Namespace+"/"+cron.Name)
 	return job, nil
 }
 
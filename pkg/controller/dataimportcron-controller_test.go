@@ -290,6 +290,27 @@ var _ = Describe("All DataImportCron Tests", func() {
 			Expect(jobPodTemplateSpec.Volumes).To(HaveLen(0))
 		})
 
+		It("Should mount the registry credentials secret in the CronJob poller", func() {
+			cron = newDataImportCron(cronName)
+			cron.Spec.Template.Spec.Source.Registry.SecretRef = pointer.String("registry-login")
+			reconciler = createDataImportCronReconciler(cron)
+			_, err := reconciler.Reconcile(context.TODO(), cronReq)
+			Expect(err).ToNot(HaveOccurred())
+
+			cronjob := &batchv1.CronJob{}
+			err = reconciler.client.Get(context.TODO(), cronJobKey(cron), cronjob)
+			Expect(err).ToNot(HaveOccurred())
+
+			podSpec := cronjob.Spec.JobTemplate.Spec.Template.Spec
+			Expect(podSpec.Containers[0].VolumeMounts).To(ConsistOf(corev1.VolumeMount{
+				Name:      SecretVolName,
+				MountPath: common.ImporterRegistryCredentialDir,
+				ReadOnly:  true,
+			}))
+			Expect(podSpec.Volumes).To(ConsistOf(createSecretVolume(SecretVolName, "registry-login")))
+			Expect(getEnvVar(podSpec.Containers[0].Env, common.ImporterRegistryCredentialDirVar)).To(Equal(common.ImporterRegistryCredentialDir))
+		})
+
 		It("Should update CronJob on reconcile", func() {
 			cron = newDataImportCron(cronName)
 			reconciler = createDataImportCronReconciler(cron)
@@ -312,6 +333,51 @@ var _ = Describe("All DataImportCron Tests", func() {
 			verifyCronJobContainerImage("new-image")
 		})
 
+		It("Should pass the SourceTagPattern to the CronJob poller, and create a new DataVolume when a higher tag has a new digest", func() {
+			cron = newDataImportCron(cronName)
+			cron.Spec.SourceTagPattern = pointer.String(`^v1\.`)
+			reconciler = createDataImportCronReconciler(cron)
+			_, err := reconciler.Reconcile(context.TODO(), cronReq)
+			Expect(err).ToNot(HaveOccurred())
+
+			cronjob := &batchv1.CronJob{}
+			err = reconciler.client.Get(context.TODO(), cronJobKey(cron), cronjob)
+			Expect(err).ToNot(HaveOccurred())
+			containers := cronjob.Spec.JobTemplate.Spec.Template.Spec.Containers
+			Expect(containers).To(HaveLen(1))
+			Expect(containers[0].Command).To(ContainElements("-tag-pattern", `^v1\.`))
+
+			// The poller sets the digest of the highest tag, each new digest is imported to a new DataVolume
+			for _, digest := range []string{testDigest, "sha256:b2" + testDigest[len("sha256:b2"):]} {
+				err = reconciler.client.Get(context.TODO(), cronKey, cron)
+				Expect(err).ToNot(HaveOccurred())
+				cc.AddAnnotation(cron, AnnSourceDesiredDigest, digest)
+				err = reconciler.client.Update(context.TODO(), cron)
+				Expect(err).ToNot(HaveOccurred())
+				_, err = reconciler.Reconcile(context.TODO(), cronReq)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = reconciler.client.Get(context.TODO(), cronKey, cron)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cron.Status.CurrentImports).To(HaveLen(1))
+				Expect(cron.Status.CurrentImports[0].Digest).To(Equal(digest))
+				dv := &cdiv1.DataVolume{}
+				err = reconciler.client.Get(context.TODO(), dvKey(cron.Status.CurrentImports[0].DataVolumeName), dv)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(*dv.Spec.Source.Registry.URL).To(Equal(testRegistryURL + "@" + digest))
+
+				dv.Status.Phase = cdiv1.Succeeded
+				err = reconciler.client.Update(context.TODO(), dv)
+				Expect(err).ToNot(HaveOccurred())
+				err = reconciler.client.Create(context.TODO(), cc.CreatePvc(dv.Name, dv.Namespace, nil, nil))
+				Expect(err).ToNot(HaveOccurred())
+			}
+			dvList := &cdiv1.DataVolumeList{}
+			err = reconciler.client.List(context.TODO(), dvList, &client.ListOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dvList.Items).To(HaveLen(2))
+		})
+
 		It("Should create DataVolume on AnnSourceDesiredDigest annotation update, and update DataImportCron and DataSource on DataVolume Succeeded", func() {
 			cron = newDataImportCron(cronName)
 			dataSource = nil
@@ -626,6 +692,138 @@ var _ = Describe("All DataImportCron Tests", func() {
 			Entry("has no tag", imageStreamName, 1),
 		)
 
+		It("Should report the source as unreachable after consecutive failed polls, and recover after a successful poll", func() {
+			cron = newDataImportCronWithImageStream(cronName, imageStreamName+":"+imageStreamTag)
+			imageStream := newImageStream(imageStreamName)
+			imageStream.Status.Tags[1].Conditions = []imagev1.TagEventCondition{{
+				Type:    imagev1.ImportSuccess,
+				Status:  corev1.ConditionFalse,
+				Message: "dial tcp: lookup registry.example.com: no such host",
+			}}
+			reconciler = createDataImportCronReconciler(cron, imageStream)
+
+			poll := func() {
+				err := reconciler.client.Get(context.TODO(), cronKey, cron)
+				Expect(err).ToNot(HaveOccurred())
+				cc.AddAnnotation(cron, AnnNextCronTime, time.Now().Add(-time.Minute).Format(time.RFC3339))
+				err = reconciler.client.Update(context.TODO(), cron)
+				Expect(err).ToNot(HaveOccurred())
+				_, err = reconciler.Reconcile(context.TODO(), cronReq)
+				Expect(err).ToNot(HaveOccurred())
+				err = reconciler.client.Get(context.TODO(), cronKey, cron)
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			for i := 1; i < sourcePollFailureThreshold; i++ {
+				poll()
+				Expect(cron.Annotations[AnnSourcePollFailures]).To(Equal(strconv.Itoa(i)))
+				cond := FindDataImportCronConditionByType(cron, cdiv1.DataImportCronUpToDate)
+				Expect(cond).ToNot(BeNil())
+				Expect(cond.Reason).ToNot(Equal(SourceUnreachable))
+			}
+
+			poll()
+			Expect(cron.Annotations[AnnSourcePollFailures]).To(Equal(strconv.Itoa(sourcePollFailureThreshold)))
+			cond := FindDataImportCronConditionByType(cron, cdiv1.DataImportCronUpToDate)
+			Expect(cond).ToNot(BeNil())
+			verifyConditionState(string(cdiv1.DataImportCronUpToDate), cond.ConditionState, false, SourceUnreachable)
+			Expect(cond.Message).To(ContainSubstring("no such host"))
+			event := <-reconciler.recorder.(*record.FakeRecorder).Events
+			Expect(event).To(ContainSubstring(SourceUnreachable))
+			Expect(event).To(ContainSubstring("no such host"))
+
+			By("Polling successfully")
+			imageStream.Status.Tags[1].Conditions = nil
+			err := reconciler.client.Update(context.TODO(), imageStream)
+			Expect(err).ToNot(HaveOccurred())
+			poll()
+			Expect(cron.Annotations).ToNot(HaveKey(AnnSourcePollFailures))
+			cond = FindDataImportCronConditionByType(cron, cdiv1.DataImportCronUpToDate)
+			Expect(cond).ToNot(BeNil())
+			Expect(cond.Reason).ToNot(Equal(SourceUnreachable))
+			Expect(cron.Annotations[AnnSourceDesiredDigest]).To(Equal(testDigest))
+			event = <-reconciler.recorder.(*record.FakeRecorder).Events
+			Expect(event).To(ContainSubstring(SourceReachable))
+		})
+
+		It("Should report a URL source as unreachable after consecutive failed poll Jobs, and recover after a successful one", func() {
+			cron = newDataImportCron(cronName)
+			reconciler = createDataImportCronReconciler(cron)
+			_, err := reconciler.Reconcile(context.TODO(), cronReq)
+			Expect(err).ToNot(HaveOccurred())
+
+			start := time.Now().Add(-time.Hour)
+			finishJob := func(i int, failed bool) {
+				name := fmt.Sprintf("poll-%d", i)
+				job := &batchv1.Job{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        name,
+						Namespace:   reconciler.cdiNamespace,
+						Labels:      map[string]string{common.DataImportCronLabel: getCronJobLabelValue(cron.Namespace, cron.Name)},
+						Annotations: map[string]string{AnnSourcePollCron: cron.Namespace + "/" + cron.Name},
+					},
+				}
+				condType := batchv1.JobComplete
+				if failed {
+					condType = batchv1.JobFailed
+				}
+				job.Status.Conditions = []batchv1.JobCondition{{
+					Type:               condType,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: metav1.NewTime(start.Add(time.Duration(i) * time.Minute)),
+					Message:            "Job has reached the specified backoff limit",
+				}}
+				Expect(reconciler.client.Create(context.TODO(), job)).To(Succeed())
+				if failed {
+					pod := &corev1.Pod{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      name + "-pod",
+							Namespace: reconciler.cdiNamespace,
+							Labels:    map[string]string{"job-name": name},
+						},
+						Status: corev1.PodStatus{
+							ContainerStatuses: []corev1.ContainerStatus{{
+								State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+									ExitCode: 1,
+									Message:  "dial tcp: lookup quay.io: no such host",
+								}},
+							}},
+						},
+					}
+					Expect(reconciler.client.Create(context.TODO(), pod)).To(Succeed())
+				}
+				_, err := reconciler.Reconcile(context.TODO(), cronReq)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(reconciler.client.Get(context.TODO(), cronKey, cron)).To(Succeed())
+			}
+
+			for i := 1; i < sourcePollFailureThreshold; i++ {
+				finishJob(i, true)
+				Expect(cron.Annotations[AnnSourcePollFailures]).To(Equal(strconv.Itoa(i)))
+			}
+			finishJob(sourcePollFailureThreshold, true)
+			// the Jobs already counted are not counted again
+			_, err = reconciler.Reconcile(context.TODO(), cronReq)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reconciler.client.Get(context.TODO(), cronKey, cron)).To(Succeed())
+			Expect(cron.Annotations[AnnSourcePollFailures]).To(Equal(strconv.Itoa(sourcePollFailureThreshold)))
+			cond := FindDataImportCronConditionByType(cron, cdiv1.DataImportCronUpToDate)
+			Expect(cond).ToNot(BeNil())
+			verifyConditionState(string(cdiv1.DataImportCronUpToDate), cond.ConditionState, false, SourceUnreachable)
+			Expect(cond.Message).To(ContainSubstring("no such host"))
+			event := <-reconciler.recorder.(*record.FakeRecorder).Events
+			Expect(event).To(ContainSubstring(SourceUnreachable))
+
+			By("Polling successfully")
+			finishJob(sourcePollFailureThreshold+1, false)
+			Expect(cron.Annotations).ToNot(HaveKey(AnnSourcePollFailures))
+			cond = FindDataImportCronConditionByType(cron, cdiv1.DataImportCronUpToDate)
+			Expect(cond).ToNot(BeNil())
+			Expect(cond.Reason).ToNot(Equal(SourceUnreachable))
+			event = <-reconciler.recorder.(*record.FakeRecorder).Events
+			Expect(event).To(ContainSubstring(SourceReachable))
+		})
+
 		It("should pass through defaultInstancetype and defaultPreference metadata to DataVolume and DataSource", func() {
 			cron = newDataImportCron(cronName)
 			cron.Annotations[AnnSourceDesiredDigest] = testDigest
@@ -706,6 +904,7 @@ func createDataImportCronReconcilerWithoutConfig(objects ...runtime.Object) *Dat
 		scheme:         s,
 		log:            cronLog,
 		recorder:       rec,
+		poller:         newSourcePoller(cl),
 	}
 	return r
 }
@@ -0,0 +1,154 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/containers/image/v5/docker/reference"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+)
+
+// maxSourcePollParallelism bounds the DataImportCron sources polled concurrently
+const maxSourcePollParallelism = 16
+
+// sourcePollRetryDelay is the delay after which a poll that found no free poll slot is retried
+const sourcePollRetryDelay = 5 * time.Second
+
+// sourcePoller bounds the source polls of the DataImportCrons. A poll holds a poll slot until it completes, and the
+// DataImportCronPolling of the CDIConfig bounds the slots and the polls per minute of each registry. The polls of the
+// same DataImportCron are serialized. A poll that can't start is not waited for, the caller retries it after the
+// returned delay, so the reconcile workers are never blocked. The config is read on every poll, so changing it applies
+// to the next polls.
+type sourcePoller struct {
+	client client.Client
+	mutex  sync.Mutex
+	// running maps the polls holding a poll slot to their DataImportCron
+	running  map[string]types.NamespacedName
+	limiters map[string]*rate.Limiter
+}
+
+func newSourcePoller(c client.Client) *sourcePoller {
+	return &sourcePoller{
+		client:   c,
+		running:  map[string]types.NamespacedName{},
+		limiters: map[string]*rate.Limiter{},
+	}
+}
+
+// start gives a poll slot to the poll named key of the cron, unless another poll of the cron holds one, and counts the
+// poll in the polls per minute of the registry. It returns the delay after which to retry when the poll can't start.
+// An empty registry is not rate limited.
+func (p *sourcePoller) start(key string, cron types.NamespacedName, registry string) time.Duration {
+	parallelism, pollsPerMinute := cc.GetDataImportCronPolling(p.client)
+	if parallelism > maxSourcePollParallelism {
+		parallelism = maxSourcePollParallelism
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if len(p.running) >= int(parallelism) {
+		return sourcePollRetryDelay
+	}
+	for _, runningCron := range p.running {
+		if runningCron == cron {
+			return sourcePollRetryDelay
+		}
+	}
+	if limiter := p.getRegistryLimiter(registry, pollsPerMinute); limiter != nil {
+		reservation := limiter.Reserve()
+		if delay := reservation.Delay(); delay > 0 {
+			reservation.Cancel()
+			return delay
+		}
+	}
+	p.running[key] = cron
+	return 0
+}
+
+// adopt counts a poll found running in the poll slots, like a poll Job started before the controller restarted
+func (p *sourcePoller) adopt(key string, cron types.NamespacedName) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.running[key] = cron
+}
+
+// done frees the poll slot of the poll named key
+func (p *sourcePoller) done(key string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	delete(p.running, key)
+}
+
+// forget frees the poll slots of the polls of the cron other than the ones in keep
+func (p *sourcePoller) forget(cron types.NamespacedName, keep map[string]bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for key, runningCron := range p.running {
+		if runningCron == cron && !keep[key] {
+			delete(p.running, key)
+		}
+	}
+}
+
+// poll runs pollFunc for the cron if the poll can start, holding a poll slot while it runs. Otherwise pollFunc is not
+// run, and the delay after which to poll again is returned.
+func (p *sourcePoller) poll(cron types.NamespacedName, registry string, pollFunc func() error) (time.Duration, error) {
+	key := "cron/" + cron.String()
+	if delay := p.start(key, cron, registry); delay > 0 {
+		return delay, nil
+	}
+	defer p.done(key)
+	return 0, pollFunc()
+}
+
+// getRegistryLimiter returns the rate limiter of the registry, nil if its polls are not limited. Called with the mutex
+// held.
+func (p *sourcePoller) getRegistryLimiter(registry string, pollsPerMinute int32) *rate.Limiter {
+	if registry == "" || pollsPerMinute == 0 {
+		delete(p.limiters, registry)
+		return nil
+	}
+	limit := rate.Every(time.Minute / time.Duration(pollsPerMinute))
+	limiter, ok := p.limiters[registry]
+	if !ok {
+		limiter = rate.NewLimiter(limit, 1)
+		p.limiters[registry] = limiter
+	} else if limiter.Limit() != limit {
+		limiter.SetLimit(limit)
+	}
+	return limiter
+}
+
+// getDockerURLRegistry returns the registry of a docker URL source, empty if it can't be parsed
+func getDockerURLRegistry(dockerURL string) string {
+	u, err := url.Parse(dockerURL)
+	if err != nil {
+		return ""
+	}
+	named, err := reference.ParseNormalizedNamed(u.Host + u.Path)
+	if err != nil {
+		return ""
+	}
+	return reference.Domain(named)
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+var _ = Describe("DataImportCron source poller", func() {
+	const pollDuration = 200 * time.Millisecond
+
+	// pollConcurrently runs the polls of the crons concurrently, retrying the ones that can't start, and returns the
+	// maximum number of polls running at the same time, overall and for the same cron
+	pollConcurrently := func(poller *sourcePoller, crons []types.NamespacedName) (int, int) {
+		var mutex sync.Mutex
+		running, maxRunning, maxRunningPerCron := 0, 0, 0
+		runningPerCron := map[types.NamespacedName]int{}
+		var wg sync.WaitGroup
+		for _, cron := range crons {
+			wg.Add(1)
+			go func(cron types.NamespacedName) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				pollFunc := func() error {
+					mutex.Lock()
+					running++
+					runningPerCron[cron]++
+					if running > maxRunning {
+						maxRunning = running
+					}
+					if runningPerCron[cron] > maxRunningPerCron {
+						maxRunningPerCron = runningPerCron[cron]
+					}
+					mutex.Unlock()
+					time.Sleep(pollDuration)
+					mutex.Lock()
+					running--
+					runningPerCron[cron]--
+					mutex.Unlock()
+					return nil
+				}
+				for {
+					delay, err := poller.poll(cron, "", pollFunc)
+					Expect(err).ToNot(HaveOccurred())
+					if delay == 0 {
+						break
+					}
+					Expect(delay).To(Equal(sourcePollRetryDelay))
+					time.Sleep(pollDuration / 20)
+				}
+			}(cron)
+		}
+		wg.Wait()
+		return maxRunning, maxRunningPerCron
+	}
+
+	newCronKeys := func(count int) []types.NamespacedName {
+		crons := make([]types.NamespacedName, count)
+		for i := range crons {
+			crons[i] = types.NamespacedName{Name: fmt.Sprintf("cron%d", i), Namespace: metav1.NamespaceDefault}
+		}
+		return crons
+	}
+
+	DescribeTable("Should poll the crons within the window of their parallelism", func(parallelism *int32, expectedParallelism int) {
+		reconciler := createDataImportCronReconciler()
+		setDataImportCronPolling(reconciler, &cdiv1.DataImportCronPollingConfig{Parallelism: parallelism})
+		const numCrons = 8
+
+		start := time.Now()
+		maxRunning, _ := pollConcurrently(reconciler.poller, newCronKeys(numCrons))
+		elapsed := time.Since(start)
+
+		Expect(maxRunning).To(Equal(expectedParallelism))
+		rounds := (numCrons + expectedParallelism - 1) / expectedParallelism
+		Expect(elapsed).To(BeNumerically(">=", time.Duration(rounds)*pollDuration))
+		Expect(elapsed).To(BeNumerically("<", time.Duration(rounds+1)*pollDuration))
+	},
+		Entry("serially by default", nil, 1),
+		Entry("by 4", pointer.Int32(4), 4),
+		Entry("all at once", pointer.Int32(8), 8),
+	)
+
+	It("Should serialize the polls of the same cron", func() {
+		reconciler := createDataImportCronReconciler()
+		setDataImportCronPolling(reconciler, &cdiv1.DataImportCronPollingConfig{Parallelism: pointer.Int32(4)})
+		crons := newCronKeys(2)
+		crons = append(crons, crons...)
+		crons = append(crons, crons...)
+
+		start := time.Now()
+		maxRunning, maxRunningPerCron := pollConcurrently(reconciler.poller, crons)
+
+		Expect(maxRunningPerCron).To(Equal(1))
+		Expect(maxRunning).To(Equal(2))
+		Expect(time.Since(start)).To(BeNumerically(">=", 4*pollDuration))
+	})
+
+	It("Should delay the polls of a registry over its polls per minute limit", func() {
+		reconciler := createDataImportCronReconciler()
+		setDataImportCronPolling(reconciler, &cdiv1.DataImportCronPollingConfig{RegistryPollsPerMinute: pointer.Int32(2)})
+		crons := newCronKeys(3)
+		polled := 0
+		pollFunc := func() error {
+			polled++
+			return nil
+		}
+
+		delay, err := reconciler.poller.poll(crons[0], "quay.io", pollFunc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(delay).To(BeZero())
+		delay, err = reconciler.poller.poll(crons[1], "quay.io", pollFunc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(delay).To(BeNumerically("~", 30*time.Second, time.Second))
+		Expect(polled).To(Equal(1))
+
+		delay, err = reconciler.poller.poll(crons[2], "registry.example.com", pollFunc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(delay).To(BeZero())
+		Expect(polled).To(Equal(2))
+	})
+
+	getJob := func(reconciler *DataImportCronReconciler, name string) *batchv1.Job {
+		job := &batchv1.Job{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: reconciler.cdiNamespace}, job)).To(Succeed())
+		return job
+	}
+
+	reconcileCron := func(reconciler *DataImportCronReconciler, cron *cdiv1.DataImportCron) reconcile.Result {
+		res, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: cron.Name, Namespace: cron.Namespace}})
+		Expect(err).ToNot(HaveOccurred())
+		return res
+	}
+
+	It("Should create the poll Jobs suspended, and start them once they get a poll slot", func() {
+		cron := newDataImportCron("cron")
+		reconciler := createDataImportCronReconciler(cron)
+
+		reconcileCron(reconciler, cron)
+		cronJob := &batchv1.CronJob{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: GetCronJobName(cron), Namespace: reconciler.cdiNamespace}, cronJob)).To(Succeed())
+		Expect(*cronJob.Spec.JobTemplate.Spec.Suspend).To(BeTrue())
+		Expect(*getJob(reconciler, GetInitialJobName(cron)).Spec.Suspend).To(BeFalse())
+	})
+
+	It("Should requeue the poll Job of a cron instead of waiting for a poll slot", func() {
+		first := newDataImportCron("first")
+		second := newDataImportCron("second")
+		second.Spec.ManagedDataSource = "second"
+		reconciler := createDataImportCronReconciler(first, second)
+
+		Expect(reconcileCron(reconciler, first).RequeueAfter).To(BeZero())
+		Expect(*getJob(reconciler, GetInitialJobName(first)).Spec.Suspend).To(BeFalse())
+		Expect(reconcileCron(reconciler, second).RequeueAfter).To(Equal(sourcePollRetryDelay))
+		Expect(*getJob(reconciler, GetInitialJobName(second)).Spec.Suspend).To(BeTrue())
+
+		// The slot of the first poll Job is freed once it finishes
+		job := getJob(reconciler, GetInitialJobName(first))
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue, LastTransitionTime: metav1.Now()}}
+		Expect(reconciler.client.Update(context.TODO(), job)).To(Succeed())
+		reconcileCron(reconciler, first)
+		Expect(reconcileCron(reconciler, second).RequeueAfter).To(BeZero())
+		Expect(*getJob(reconciler, GetInitialJobName(second)).Spec.Suspend).To(BeFalse())
+	})
+
+	It("Should free the poll slot of a deleted poll Job", func() {
+		first := newDataImportCron("first")
+		second := newDataImportCron("second")
+		second.Spec.ManagedDataSource = "second"
+		reconciler := createDataImportCronReconciler(first, second)
+
+		reconcileCron(reconciler, first)
+		Expect(reconciler.client.Delete(context.TODO(), getJob(reconciler, GetInitialJobName(first)))).To(Succeed())
+		reconcileCron(reconciler, first)
+		Expect(reconcileCron(reconciler, second).RequeueAfter).To(BeZero())
+		Expect(*getJob(reconciler, GetInitialJobName(second)).Spec.Suspend).To(BeFalse())
+	})
+
+	It("Should keep the poll Job of a cron suspended while its registry is over its polls per minute limit", func() {
+		first := newDataImportCron("first")
+		second := newDataImportCron("second")
+		second.Spec.ManagedDataSource = "second"
+		reconciler := createDataImportCronReconciler(first, second)
+		setDataImportCronPolling(reconciler, &cdiv1.DataImportCronPollingConfig{Parallelism: pointer.Int32(2), RegistryPollsPerMinute: pointer.Int32(1)})
+
+		Expect(reconcileCron(reconciler, first).RequeueAfter).To(BeZero())
+		Expect(*getJob(reconciler, GetInitialJobName(first)).Spec.Suspend).To(BeFalse())
+
+		Expect(reconcileCron(reconciler, second).RequeueAfter).To(BeNumerically(">", 55*time.Second))
+		Expect(*getJob(reconciler, GetInitialJobName(second)).Spec.Suspend).To(BeTrue())
+	})
+
+	DescribeTable("Should get the registry of", func(dockerURL, registry string) {
+		Expect(getDockerURLRegistry(dockerURL)).To(Equal(registry))
+	},
+		Entry("a URL with a registry", "docker://quay.io/kubevirt/fedora:38", "quay.io"),
+		Entry("a URL with a registry port", "docker://registry:5000/fedora", "registry:5000"),
+		Entry("a URL without registry", "docker://fedora", "docker.io"),
+		Entry("an invalid URL", "docker://Fedora", ""),
+	)
+})
+
+func setDataImportCronPolling(reconciler *DataImportCronReconciler, config *cdiv1.DataImportCronPollingConfig) {
+	cdiConfig := &cdiv1.CDIConfig{}
+	Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+	cdiConfig.Spec.DataImportCronPolling = config
+	Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datavolume
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+const (
+	// ErrUnableToAdoptPVC provides a const to indicate the existing PVC cannot be adopted by the DataVolume
+	ErrUnableToAdoptPVC = "ErrUnableToAdoptPVC"
+	// PVCAdopted provides a const to indicate the existing PVC was adopted by the DataVolume
+	PVCAdopted = "PVCAdopted"
+
+	// MessagePVCAdopted provides a const to form the PVC adopted message
+	MessagePVCAdopted = "Existing PVC %s adopted by DataVolume"
+)
+
+// dvRequestsPvcAdoption returns true if the DataVolume asks to populate the existing PVC with its name
+func dvRequestsPvcAdoption(dv *cdiv1.DataVolume) bool {
+	return dv.Annotations[cc.AnnAdoptPVC] == "true"
+}
+
+// pvcNeedsAdoption returns true if the PVC exists, was not created by the DataVolume and the DataVolume asks to adopt it
+func pvcNeedsAdoption(pvc *corev1.PersistentVolumeClaim, dv *cdiv1.DataVolume) bool {
+	return pvc != nil && dvRequestsPvcAdoption(dv) && !metav1.IsControlledBy(pvc, dv) && !pvcIsPopulated(pvc, dv)
+}
+
+// validatePvcAdoption checks the existing PVC is empty and compatible with the DataVolume
+func (r *ReconcilerBase) validatePvcAdoption(dv *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim, pvcSpec *corev1.PersistentVolumeClaimSpec) error {
+	err := checkPvcAdoption(dv, pvc, pvcSpec, getDataVolumeOp(r.log, dv, r.client))
+	if err == nil {
+		err = r.checkPvcNotInUse(pvc)
+	}
+	if err != nil {
+		msg := fmt.Sprintf("Unable to adopt PVC %s: %s", pvc.Name, err.Error())
+		r.recorder.Event(dv, corev1.EventTypeWarning, ErrUnableToAdoptPVC, msg)
+		return errors.New(msg)
+	}
+	return nil
+}
+
+// checkPvcNotInUse fails when a pod uses the PVC, the workload could write to it while it is populated
+func (r *ReconcilerBase) checkPvcNotInUse(pvc *corev1.PersistentVolumeClaim) error {
+	pods, err := cc.GetPodsUsingPVCs(r.client, pvc.Namespace, sets.NewString(pvc.Name), false)
+	if err != nil {
+		return err
+	}
+	if len(pods) > 0 {
+		return errors.Errorf("PVC is used by pod %s", pods[0].Name)
+	}
+	return nil
+}
+
+func checkPvcAdoption(dv *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim, pvcSpec *corev1.PersistentVolumeClaimSpec, op dataVolumeOp) error {
+	if op != dataVolumeImport && op != dataVolumeUpload && op != dataVolumePvcClone {
+		return errors.New("adoption is only supported for import, upload and PVC clone DataVolumes")
+	}
+	if owner := metav1.GetControllerOf(pvc); owner != nil {
+		return errors.Errorf("PVC is controlled by %s %s", owner.Kind, owner.Name)
+	}
+	// The worker pod checks the PVC is empty before writing, refuse early what is known to have populated it
+	if pvc.Spec.DataSource != nil || pvc.Spec.DataSourceRef != nil {
+		return errors.New("PVC already contains data from its data source")
+	}
+	if _, ok := pvc.Annotations[cc.AnnPopulatedFor]; ok {
+		return errors.New("PVC already contains data")
+	}
+	if _, ok := pvc.Annotations[cc.AnnPodPhase]; ok {
+		return errors.New("PVC already contains data")
+	}
+	if volumeMode := getExplicitVolumeMode(dv); volumeMode != nil &&
+		util.ResolveVolumeMode(volumeMode) != util.ResolveVolumeMode(pvc.Spec.VolumeMode) {
+		return errors.Errorf("PVC volume mode %s does not match the requested volume mode %s",
+			util.ResolveVolumeMode(pvc.Spec.VolumeMode), util.ResolveVolumeMode(volumeMode))
+	}
+	requested, ok := pvcSpec.Resources.Requests[corev1.ResourceStorage]
+	if !ok || requested.IsZero() {
+		return nil
+	}
+	size, ok := pvc.Status.Capacity[corev1.ResourceStorage]
+	if !ok {
+		size = pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	}
+	if size.Cmp(requested) < 0 {
+		return errors.Errorf("PVC size %s is smaller than the requested size %s", size.String(), requested.String())
+	}
+	return nil
+}
+
+func getExplicitVolumeMode(dv *cdiv1.DataVolume) *corev1.PersistentVolumeMode {
+	if dv.Spec.PVC != nil {
+		return dv.Spec.PVC.VolumeMode
+	}
+	if dv.Spec.Storage != nil {
+		return dv.Spec.Storage.VolumeMode
+	}
+	return nil
+}
+
+// adoptPvc sets the labels, annotations and owner reference the DataVolume would have set when creating the PVC
+func (r *ReconcilerBase) adoptPvc(syncState *dvSyncState, pvcModifier pvcModifierFunc) error {
+	pvc := syncState.pvc
+	dv := syncState.dvMutated
+	newPvc, err := r.newPersistentVolumeClaim(dv, syncState.pvcSpec, pvc.Namespace, pvc.Name, pvcModifier)
+	if err != nil {
+		return err
+	}
+	util.SetRecommendedLabels(newPvc, r.installerLabels, "cdi-controller")
+
+	pvcCpy := pvc.DeepCopy()
+	if pvcCpy.Labels == nil {
+		pvcCpy.Labels = make(map[string]string)
+	}
+	for k, v := range newPvc.Labels {
+		pvcCpy.Labels[k] = v
+	}
+	for k, v := range newPvc.Annotations {
+		cc.AddAnnotation(pvcCpy, k, v)
+	}
+	cc.AddAnnotation(pvcCpy, cc.AnnAdoptedPVC, "true")
+	pvcCpy.OwnerReferences = append(pvcCpy.OwnerReferences, newPvc.OwnerReferences...)
+	if err := r.updatePVC(pvcCpy); err != nil {
+		return err
+	}
+	r.recorder.Event(dv, corev1.EventTypeNormal, PVCAdopted, fmt.Sprintf(MessagePVCAdopted, pvc.Name))
+	syncState.pvc = pvcCpy
+	return nil
+}
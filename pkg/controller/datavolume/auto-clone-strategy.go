@@ -0,0 +1,173 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datavolume
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+)
+
+const (
+	// annAutoCloneStrategy holds why the auto clone strategy selected the clone type of the DataVolume
+	annAutoCloneStrategy = "cdi.kubevirt.io/autoCloneStrategy"
+
+	cloneStrategyCsiClone     = "CsiClone"
+	cloneStrategySnapshot     = "Snapshot"
+	cloneStrategyHostAssisted = "HostAssisted"
+)
+
+// autoCloneFacts are what the auto clone strategy knows of the source and target of a clone
+type autoCloneFacts struct {
+	// sourceStorageClass and targetStorageClass are the storage class names, empty when not found
+	sourceStorageClass string
+	targetStorageClass string
+	// sourceProvisioner and targetProvisioner are the provisioners of the storage classes
+	sourceProvisioner string
+	targetProvisioner string
+	// csiDriverAvailable is true if the target provisioner is a CSI driver, which can clone the volumes of its class
+	csiDriverAvailable bool
+	// snapshotClass is the snapshot class of the target provisioner, empty when there is none
+	snapshotClass string
+	// crossClassSnapshotRestore is true if the StorageProfile of the target storage class declares its CSI driver
+	// restores the snapshots of its other storage classes
+	crossClassSnapshotRestore bool
+	// sameVolumeMode is true if the source and target volume modes match
+	sameVolumeMode bool
+	// sizeCompatible is true if the source can be cloned into the target size without a host assisted clone
+	sizeCompatible bool
+	// crossNamespaceWaitForFirstConsumer is true for a clone into another namespace with a WaitForFirstConsumer target
+	crossNamespaceWaitForFirstConsumer bool
+}
+
+// decideAutoCloneStrategy selects the clone strategy for the facts, a CSI clone when the source and target share a
+// storage class of a CSI driver, a snapshot clone when they share a provisioner with a snapshot class, and a host
+// assisted clone otherwise. It also returns why the strategy was selected.
+func decideAutoCloneStrategy(facts autoCloneFacts) (cloneStrategy, string) {
+	switch {
+	case facts.targetStorageClass == "":
+		return HostAssistedClone, "Target storage class not found, using a host assisted clone"
+	case facts.crossNamespaceWaitForFirstConsumer:
+		return HostAssistedClone, fmt.Sprintf("Storage class %s binds its volumes on first consumer, using a host assisted clone into another namespace", facts.targetStorageClass)
+	case !facts.sameVolumeMode:
+		return HostAssistedClone, "Source and target volume modes do not match, using a host assisted clone"
+	case !facts.sizeCompatible:
+		return HostAssistedClone, "Source size is not compatible with the target size, using a host assisted clone"
+	case facts.sourceStorageClass == facts.targetStorageClass && facts.csiDriverAvailable:
+		return CsiClone, fmt.Sprintf("Source and target share storage class %s of CSI driver %s, using a CSI clone", facts.targetStorageClass, facts.targetProvisioner)
+	case facts.sourceStorageClass != facts.targetStorageClass && !facts.crossClassSnapshotRestore:
+		return HostAssistedClone, fmt.Sprintf("StorageProfile %s does not restore snapshots of storage class %s, using a host assisted clone", facts.targetStorageClass, facts.sourceStorageClass)
+	case facts.sourceProvisioner == facts.targetProvisioner && facts.snapshotClass != "":
+		return SmartClone, fmt.Sprintf("Source storage class %s and target storage class %s share provisioner %s with snapshot class %s, using a snapshot clone",
+			facts.sourceStorageClass, facts.targetStorageClass, facts.targetProvisioner, facts.snapshotClass)
+	case facts.sourceProvisioner != facts.targetProvisioner:
+		return HostAssistedClone, fmt.Sprintf("Source storage class %s and target storage class %s have different provisioners, using a host assisted clone", facts.sourceStorageClass, facts.targetStorageClass)
+	}
+	return HostAssistedClone, fmt.Sprintf("Provisioner %s has no snapshot class, using a host assisted clone", facts.targetProvisioner)
+}
+
+// getAutoCloneFacts gathers the facts the auto clone strategy decides on
+func (r *PvcCloneReconciler) getAutoCloneFacts(dataVolume *cdiv1.DataVolume, targetStorageSpec *corev1.PersistentVolumeClaimSpec, bindingMode *storagev1.VolumeBindingMode) (autoCloneFacts, error) {
+	facts := autoCloneFacts{}
+	sourcePvc, err := r.findSourcePvc(dataVolume)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return facts, errors.New("source PVC not found")
+		}
+		return facts, err
+	}
+	targetStorageClass, err := cc.GetStorageClassByName(r.client, targetStorageSpec.StorageClassName)
+	if err != nil || targetStorageClass == nil {
+		return facts, err
+	}
+	facts.targetStorageClass = targetStorageClass.Name
+	facts.targetProvisioner = targetStorageClass.Provisioner
+	if sourcePvc.Spec.StorageClassName != nil {
+		sourceStorageClass, err := cc.GetStorageClassByName(r.client, sourcePvc.Spec.StorageClassName)
+		if err != nil {
+			return facts, err
+		}
+		facts.sourceStorageClass = *sourcePvc.Spec.StorageClassName
+		if sourceStorageClass != nil {
+			facts.sourceProvisioner = sourceStorageClass.Provisioner
+		}
+	}
+
+	facts.crossNamespaceWaitForFirstConsumer = isCrossNamespaceClone(dataVolume) &&
+		(bindingMode == nil || *bindingMode != storagev1.VolumeBindingImmediate)
+	if facts.sameVolumeMode, err = r.validateSameVolumeMode(dataVolume, sourcePvc, targetStorageClass); err != nil {
+		return facts, err
+	}
+	if facts.sizeCompatible, err = r.validateAdvancedCloneSizeCompatible(sourcePvc, targetStorageSpec); err != nil {
+		return facts, err
+	}
+	if facts.csiDriverAvailable, err = r.storageClassCSIDriverExists(targetStorageSpec.StorageClassName); err != nil && !k8serrors.IsNotFound(err) {
+		return facts, err
+	}
+	if facts.snapshotClass, err = r.getSnapshotClassForSmartClone(dataVolume, targetStorageSpec); err != nil {
+		return facts, err
+	}
+	if facts.crossClassSnapshotRestore, err = getCrossClassSnapshotRestore(r.client, targetStorageClass); err != nil {
+		return facts, err
+	}
+	return facts, nil
+}
+
+// selectAutoCloneStrategy selects the clone strategy of a DataVolume using the auto clone strategy, and records why
+// in the DataVolume annotations for its CloneStrategy condition
+func (r *PvcCloneReconciler) selectAutoCloneStrategy(dataVolume *cdiv1.DataVolume, targetStorageSpec *corev1.PersistentVolumeClaimSpec, bindingMode *storagev1.VolumeBindingMode) (cloneStrategy, error) {
+	facts, err := r.getAutoCloneFacts(dataVolume, targetStorageSpec, bindingMode)
+	if err != nil {
+		return NoClone, err
+	}
+	strategy, message := decideAutoCloneStrategy(facts)
+	r.log.V(3).Info("Auto clone strategy selected", "datavolume", dataVolume.Name, "reason", message)
+	cc.AddAnnotation(dataVolume, annAutoCloneStrategy, message)
+	if strategy == SmartClone && !isCrossNamespaceClone(dataVolume) {
+		sharedSnapshotClone, err := r.isSharedSnapshotClone(dataVolume)
+		if err != nil {
+			return NoClone, err
+		}
+		if sharedSnapshotClone {
+			return SharedSnapshotClone, nil
+		}
+	}
+	return strategy, nil
+}
+
+// updateCloneStrategyCondition reports the clone strategy the auto clone strategy selected for the DataVolume
+func updateCloneStrategyCondition(conditions []cdiv1.DataVolumeCondition, dataVolume *cdiv1.DataVolume) []cdiv1.DataVolumeCondition {
+	message, ok := dataVolume.Annotations[annAutoCloneStrategy]
+	if !ok {
+		return conditions
+	}
+	reason := cloneStrategyHostAssisted
+	switch dataVolume.Annotations[annCloneType] {
+	case cloneStrategyToCloneType(CsiClone):
+		reason = cloneStrategyCsiClone
+	case cloneStrategyToCloneType(SmartClone):
+		reason = cloneStrategySnapshot
+	}
+	return updateCondition(conditions, cdiv1.DataVolumeCloneStrategy, corev1.ConditionTrue, message, reason)
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datavolume
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	. "kubevirt.io/containerized-data-importer/pkg/controller/common"
+)
+
+var _ = Describe("Auto clone strategy", func() {
+	sameClassFacts := func() autoCloneFacts {
+		return autoCloneFacts{
+			sourceStorageClass: "sc",
+			targetStorageClass: "sc",
+			sourceProvisioner:  "csi-plugin",
+			targetProvisioner:  "csi-plugin",
+			csiDriverAvailable: true,
+			snapshotClass:      "snap-class",
+			sameVolumeMode:     true,
+			sizeCompatible:     true,
+		}
+	}
+	crossClassFacts := func() autoCloneFacts {
+		facts := sameClassFacts()
+		facts.targetStorageClass = "other-sc"
+		facts.crossClassSnapshotRestore = true
+		return facts
+	}
+
+	table.DescribeTable("should decide", func(facts autoCloneFacts, expected cloneStrategy, expectedMessage string) {
+		strategy, message := decideAutoCloneStrategy(facts)
+		Expect(strategy).To(Equal(expected))
+		Expect(message).To(ContainSubstring(expectedMessage))
+	},
+		table.Entry("a CSI clone within a storage class of a CSI driver", sameClassFacts(), CsiClone, "share storage class sc of CSI driver csi-plugin"),
+		table.Entry("a snapshot clone within a storage class without CSI driver", func() autoCloneFacts {
+			facts := sameClassFacts()
+			facts.csiDriverAvailable = false
+			return facts
+		}(), SmartClone, "share provisioner csi-plugin with snapshot class snap-class"),
+		table.Entry("a snapshot clone across the storage classes of a provisioner", crossClassFacts(), SmartClone, "target storage class other-sc share provisioner"),
+		table.Entry("a host assisted clone across storage classes the StorageProfile does not restore snapshots of", func() autoCloneFacts {
+			facts := crossClassFacts()
+			facts.crossClassSnapshotRestore = false
+			return facts
+		}(), HostAssistedClone, "StorageProfile other-sc does not restore snapshots of storage class sc"),
+		table.Entry("a host assisted clone across the storage classes of a provisioner without snapshot class", func() autoCloneFacts {
+			facts := crossClassFacts()
+			facts.snapshotClass = ""
+			return facts
+		}(), HostAssistedClone, "has no snapshot class"),
+		table.Entry("a host assisted clone across provisioners", func() autoCloneFacts {
+			facts := crossClassFacts()
+			facts.targetProvisioner = "other-plugin"
+			return facts
+		}(), HostAssistedClone, "have different provisioners"),
+		table.Entry("a host assisted clone between volume modes", func() autoCloneFacts {
+			facts := sameClassFacts()
+			facts.sameVolumeMode = false
+			return facts
+		}(), HostAssistedClone, "volume modes do not match"),
+		table.Entry("a host assisted clone into an incompatible size", func() autoCloneFacts {
+			facts := sameClassFacts()
+			facts.sizeCompatible = false
+			return facts
+		}(), HostAssistedClone, "not compatible with the target size"),
+		table.Entry("a host assisted clone into another namespace bound on first consumer", func() autoCloneFacts {
+			facts := sameClassFacts()
+			facts.crossNamespaceWaitForFirstConsumer = true
+			return facts
+		}(), HostAssistedClone, "binds its volumes on first consumer"),
+		table.Entry("a host assisted clone without target storage class", autoCloneFacts{}, HostAssistedClone, "Target storage class not found"),
+	)
+
+	Context("with the reconciler", func() {
+		auto := cdiv1.CloneStrategyAuto
+		accessMode := []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+
+		newReconciler := func(dv *cdiv1.DataVolume, sourceScName string, objects ...runtime.Object) *PvcCloneReconciler {
+			srcPvc := CreatePvcInStorageClass("test", metav1.NamespaceDefault, &sourceScName, nil, nil, corev1.ClaimBound)
+			targetScName := *dv.Spec.PVC.StorageClassName
+			targetStorageProfile := createStorageProfileWithCloneStrategy(targetScName,
+				[]cdiv1.ClaimPropertySet{{AccessModes: accessMode, VolumeMode: &FilesystemMode}}, &auto)
+			objects = append(objects, dv, srcPvc, targetStorageProfile, createVolumeSnapshotContentCrd(), createVolumeSnapshotClassCrd(), createVolumeSnapshotCrd())
+			return createCloneReconciler(objects...)
+		}
+
+		table.DescribeTable("should select and record the clone strategy", func(targetScName, targetProvisioner string, csiDriver, snapshotClass bool, crossClassSnapshotRestore *bool, expected cloneStrategy, expectedReason string) {
+			sourceScName := "sourcesc"
+			dv := newCloneDataVolume("test-dv")
+			dv.Spec.PVC.StorageClassName = &targetScName
+			objects := []runtime.Object{CreateStorageClassWithProvisioner(sourceScName, nil, map[string]string{}, "csi-plugin")}
+			if targetScName != sourceScName {
+				objects = append(objects, CreateStorageClassWithProvisioner(targetScName, nil, map[string]string{}, targetProvisioner))
+			}
+			if csiDriver {
+				objects = append(objects, &storagev1.CSIDriver{ObjectMeta: metav1.ObjectMeta{Name: targetProvisioner}})
+			}
+			if snapshotClass {
+				objects = append(objects, createSnapshotClass("snap-class", nil, targetProvisioner))
+			}
+			reconciler := newReconciler(dv, sourceScName, objects...)
+			if crossClassSnapshotRestore != nil {
+				storageProfile := &cdiv1.StorageProfile{}
+				Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: targetScName}, storageProfile)).To(Succeed())
+				storageProfile.Status.CrossClassSnapshotRestore = crossClassSnapshotRestore
+				Expect(reconciler.client.Status().Update(context.TODO(), storageProfile)).To(Succeed())
+			}
+
+			strategy, err := reconciler.selectCloneStrategy(dv, dv.Spec.PVC)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(strategy).To(Equal(expected))
+			Expect(dv.Annotations).To(HaveKey(annAutoCloneStrategy))
+
+			AddAnnotation(dv, annCloneType, cloneStrategyToCloneType(strategy))
+			conditions := updateCloneStrategyCondition(nil, dv)
+			condition := FindConditionByType(cdiv1.DataVolumeCloneStrategy, conditions)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(expectedReason))
+			Expect(condition.Message).To(Equal(dv.Annotations[annAutoCloneStrategy]))
+		},
+			table.Entry("CSI clone within a storage class", "sourcesc", "csi-plugin", true, true, nil, CsiClone, cloneStrategyCsiClone),
+			table.Entry("snapshot clone across storage classes", "targetsc", "csi-plugin", true, true, pointer.Bool(true), SmartClone, cloneStrategySnapshot),
+			table.Entry("host assisted clone across storage classes without the StorageProfile declaration", "targetsc", "csi-plugin", true, true, nil, HostAssistedClone, cloneStrategyHostAssisted),
+			table.Entry("host assisted clone across provisioners", "targetsc", "other-plugin", true, true, pointer.Bool(true), HostAssistedClone, cloneStrategyHostAssisted),
+			table.Entry("host assisted clone without snapshot class", "targetsc", "csi-plugin", true, false, pointer.Bool(true), HostAssistedClone, cloneStrategyHostAssisted),
+		)
+
+		It("should record why a host assisted clone is required", func() {
+			scName := "sourcesc"
+			dv := newCloneDataVolume("test-dv")
+			dv.Spec.PVC.StorageClassName = &scName
+			AddAnnotation(dv, AnnTargetFormat, "qcow2")
+			reconciler := newReconciler(dv, scName, CreateStorageClassWithProvisioner(scName, nil, map[string]string{}, "csi-plugin"))
+
+			strategy, err := reconciler.selectCloneStrategy(dv, dv.Spec.PVC)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(strategy).To(Equal(HostAssistedClone))
+			Expect(dv.Annotations[annAutoCloneStrategy]).To(ContainSubstring("qcow2"))
+		})
+
+		It("should not report the condition without the auto clone strategy", func() {
+			dv := newCloneDataVolume("test-dv")
+			Expect(updateCloneStrategyCondition(nil, dv)).To(BeEmpty())
+		})
+	})
+})
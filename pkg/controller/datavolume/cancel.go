@@ -0,0 +1,166 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datavolume
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+	"kubevirt.io/containerized-data-importer/pkg/util/naming"
+)
+
+const (
+	// DataVolumeCanceled provides a const to indicate the DataVolume transfer was canceled
+	DataVolumeCanceled = "Canceled"
+
+	// MessageDataVolumeCanceled provides a const to form the DataVolume canceled message
+	MessageDataVolumeCanceled = "DataVolume %s canceled"
+)
+
+// dvCancelRequested returns true if the DataVolume asks to cancel its transfer, or was already canceled
+func dvCancelRequested(dv *cdiv1.DataVolume) bool {
+	return dv.Annotations[cc.AnnCancel] == "true" || dv.Status.Phase == cdiv1.Canceled
+}
+
+// dvCompleted returns true if the DataVolume transfer already completed, so there is nothing left to cancel
+func dvCompleted(dv *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim) bool {
+	if dv.Status.Phase == cdiv1.Succeeded || dv.Status.Phase == cdiv1.SourceVerified {
+		return true
+	}
+	return pvc != nil && (pvcIsPopulated(pvc, dv) || pvc.Annotations[cc.AnnPodPhase] == string(corev1.PodSucceeded))
+}
+
+// handleCancel stops the transfer of a canceled DataVolume: it deletes the worker pod, the scratch space and the
+// partially populated PVC, then moves the DataVolume to the terminal Canceled phase once the PVC is gone, its
+// finalizers tearing down the rest of the transfer, like the source pod of a clone. A PVC not controlled by the
+// DataVolume, or adopted by it, is retained. Canceling a completed DataVolume has no effect.
+func (r *ReconcilerBase) handleCancel(syncState *dvSyncState, log logr.Logger, cleanup dvSyncStateFunc) error {
+	dv := syncState.dvMutated
+	if !dvCancelRequested(dv) {
+		return nil
+	}
+	if dv.Status.Phase != cdiv1.Canceled && dvCompleted(dv, syncState.pvc) {
+		log.V(1).Info("DataVolume already completed, ignoring cancel")
+		return nil
+	}
+
+	log.Info("DataVolume canceled, cleaning up")
+	if cleanup != nil {
+		if err := cleanup(syncState); err != nil {
+			return err
+		}
+	}
+	if pvc := syncState.pvc; pvc != nil && pvc.DeletionTimestamp == nil {
+		if metav1.IsControlledBy(pvc, dv) && cc.IsAdoptedPVC(pvc) {
+			if err := r.releaseAdoptedPvc(pvc, dv); err != nil {
+				return err
+			}
+		}
+		if err := r.deleteTransferResources(pvc); err != nil {
+			return err
+		}
+		if metav1.IsControlledBy(pvc, dv) {
+			if err := r.client.Delete(context.TODO(), pvc); cc.IgnoreNotFound(err) != nil {
+				return err
+			}
+		}
+	}
+	syncState.result = &reconcile.Result{}
+	if pvc := syncState.pvc; pvc != nil && metav1.IsControlledBy(pvc, dv) {
+		if deleted, err := r.pvcDeleted(pvc); err != nil || !deleted {
+			// the PVC deletion reconciles the DataVolume again
+			log.V(1).Info("Waiting for the PVC of the canceled DataVolume to be deleted")
+			return err
+		}
+	}
+
+	return r.syncDataVolumeStatusPhaseWithEvent(syncState, cdiv1.Canceled, nil,
+		Event{
+			eventType: corev1.EventTypeNormal,
+			reason:    DataVolumeCanceled,
+			message:   fmt.Sprintf(MessageDataVolumeCanceled, dv.Name),
+		})
+}
+
+// releaseAdoptedPvc keeps the PVC the canceled DataVolume adopted for its owner: it marks the PVC so the worker pod
+// controllers stop its pods and don't recreate them, and removes the owner reference of the DataVolume
+func (r *ReconcilerBase) releaseAdoptedPvc(pvc *corev1.PersistentVolumeClaim, dv *cdiv1.DataVolume) error {
+	cc.AddAnnotation(pvc, cc.AnnCanceled, "true")
+	var ownerRefs []metav1.OwnerReference
+	for _, ref := range pvc.OwnerReferences {
+		if ref.UID != dv.UID {
+			ownerRefs = append(ownerRefs, ref)
+		}
+	}
+	pvc.OwnerReferences = ownerRefs
+	return r.updatePVC(pvc)
+}
+
+// pvcDeleted returns true if the PVC is gone
+func (r *ReconcilerBase) pvcDeleted(pvc *corev1.PersistentVolumeClaim) (bool, error) {
+	if err := r.client.Get(context.TODO(), client.ObjectKeyFromObject(pvc), &corev1.PersistentVolumeClaim{}); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	return false, nil
+}
+
+// updateCanceledCondition reports the DataVolume was canceled, it is only set once its transfer is torn down
+func updateCanceledCondition(conditions []cdiv1.DataVolumeCondition, dv *cdiv1.DataVolume) []cdiv1.DataVolumeCondition {
+	if dv.Status.Phase != cdiv1.Canceled {
+		return conditions
+	}
+	return updateCondition(conditions, cdiv1.DataVolumeCanceled, corev1.ConditionTrue, fmt.Sprintf(MessageDataVolumeCanceled, dv.Name), DataVolumeCanceled)
+}
+
+// deleteTransferResources deletes the importer pod and the scratch space of the PVC. The other worker pods are
+// owned by the PVC, so they are garbage collected with it.
+func (r *ReconcilerBase) deleteTransferResources(pvc *corev1.PersistentVolumeClaim) error {
+	if podName, ok := pvc.Annotations[cc.AnnImportPod]; ok {
+		pod := &corev1.Pod{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: pvc.Namespace, Name: podName}, pod); cc.IgnoreNotFound(err) != nil {
+			return err
+		} else if err == nil {
+			if err := r.client.Delete(context.TODO(), pod); cc.IgnoreNotFound(err) != nil {
+				return err
+			}
+		}
+	}
+	scratchPvc := &corev1.PersistentVolumeClaim{}
+	scratchName := naming.GetResourceName(pvc.Name, common.ScratchNameSuffix)
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: pvc.Namespace, Name: scratchName}, scratchPvc); err != nil {
+		return cc.IgnoreNotFound(err)
+	}
+	// The scratch space is owned by the worker pod, don't touch a PVC that just happens to have the same name
+	if owner := metav1.GetControllerOf(scratchPvc); owner == nil || owner.Kind != "Pod" {
+		return nil
+	}
+	return cc.IgnoreNotFound(r.client.Delete(context.TODO(), scratchPvc))
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datavolume
+
+import (
+	"context"
+	"fmt"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+// cloneTornDown returns true if the clone of the DataVolume is stopped, because it was canceled or deleted before it
+// succeeded, so the objects created for it must be torn down
+func cloneTornDown(dv *cdiv1.DataVolume) bool {
+	return (dv.DeletionTimestamp != nil || dvCancelRequested(dv)) && dv.Status.Phase != cdiv1.Succeeded
+}
+
+// deleteCloneSnapshot deletes the snapshot taken for the snapshot clone of the DataVolume, so no PVC is restored from
+// it any more. It returns an error until the snapshot is gone, so the DataVolume is not canceled, or its finalizer
+// removed, while the snapshot remains. A snapshot shared with other clones of the source is kept for them.
+func (r *PvcCloneReconciler) deleteCloneSnapshot(dv *cdiv1.DataVolume) error {
+	if dv.Spec.Source == nil || dv.Spec.Source.PVC == nil {
+		return nil
+	}
+	name := dv.Name
+	if isCrossNamespaceClone(dv) {
+		name = getTransferName(dv)
+	}
+	namespace := dv.Spec.Source.PVC.Namespace
+	if namespace == "" {
+		namespace = dv.Namespace
+	}
+
+	snapshot := &snapshotv1.VolumeSnapshot{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, snapshot); err != nil {
+		if k8serrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+	// Only delete the snapshot CDI took for this DataVolume
+	if _, ok := snapshot.Labels[common.CDIComponentLabel]; !ok || isSharedCloneSnapshot(snapshot) {
+		return nil
+	}
+	if ownerNamespace, ownerName, err := getAnnOwnedByDataVolume(snapshot); err != nil || ownerNamespace != dv.Namespace || ownerName != dv.Name {
+		return nil
+	}
+	if snapshot.DeletionTimestamp == nil {
+		r.log.V(1).Info("Deleting the snapshot of the stopped clone", "snapshot.Namespace", namespace, "snapshot.Name", name)
+		if err := r.client.Delete(context.TODO(), snapshot); err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, &snapshotv1.VolumeSnapshot{}); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return fmt.Errorf("waiting for VolumeSnapshot %s/%s to delete", namespace, name)
+}
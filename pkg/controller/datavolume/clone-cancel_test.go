@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datavolume
+
+import (
+	"context"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	. "kubevirt.io/containerized-data-importer/pkg/controller/common"
+)
+
+var _ = Describe("Clone cancel", func() {
+	dvKey := types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}
+
+	newCanceledDv := func() *cdiv1.DataVolume {
+		dv := newCloneDataVolume("test-dv")
+		AddAnnotation(dv, AnnCancel, "true")
+		dv.Status.Phase = cdiv1.SnapshotForSmartCloneInProgress
+		return dv
+	}
+
+	newSnapshot := func(dv *cdiv1.DataVolume) *snapshotv1.VolumeSnapshot {
+		snapshot := createSnapshotVolume(dv.Name, dv.Namespace, nil)
+		setAnnOwnedByDataVolume(snapshot, dv)
+		return snapshot
+	}
+
+	getDv := func(reconciler *PvcCloneReconciler) *cdiv1.DataVolume {
+		dv := &cdiv1.DataVolume{}
+		err := reconciler.client.Get(context.TODO(), dvKey, dv)
+		Expect(err).ToNot(HaveOccurred())
+		return dv
+	}
+
+	It("should delete the snapshot of a canceled snapshot clone", func() {
+		dv := newCanceledDv()
+		snapshot := newSnapshot(dv)
+		reconciler := createCloneReconciler(dv, snapshot)
+
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: dvKey})
+		Expect(err).ToNot(HaveOccurred())
+		err = reconciler.client.Get(context.TODO(), client.ObjectKeyFromObject(snapshot), &snapshotv1.VolumeSnapshot{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		dv = getDv(reconciler)
+		Expect(dv.Status.Phase).To(Equal(cdiv1.Canceled))
+		condition := FindConditionByType(cdiv1.DataVolumeCanceled, dv.Status.Conditions)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+	})
+
+	It("should not cancel the clone until its snapshot is deleted", func() {
+		dv := newCanceledDv()
+		snapshot := newSnapshot(dv)
+		snapshot.Finalizers = []string{"test/finalizer"}
+		reconciler := createCloneReconciler(dv, snapshot)
+
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: dvKey})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("waiting for VolumeSnapshot default/test-dv to delete"))
+		Expect(getDv(reconciler).Status.Phase).To(Equal(cdiv1.SnapshotForSmartCloneInProgress))
+
+		By("Removing the snapshot finalizer")
+		snapshot = &snapshotv1.VolumeSnapshot{}
+		err = reconciler.client.Get(context.TODO(), dvKey, snapshot)
+		Expect(err).ToNot(HaveOccurred())
+		snapshot.Finalizers = nil
+		err = reconciler.client.Update(context.TODO(), snapshot)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: dvKey})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getDv(reconciler).Status.Phase).To(Equal(cdiv1.Canceled))
+	})
+
+	It("should keep a snapshot the canceled clone does not own", func() {
+		dv := newCanceledDv()
+		snapshot := newSnapshot(newCloneDataVolume("other-dv"))
+		snapshot.Name = dv.Name
+		reconciler := createCloneReconciler(dv, snapshot)
+
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: dvKey})
+		Expect(err).ToNot(HaveOccurred())
+		err = reconciler.client.Get(context.TODO(), client.ObjectKeyFromObject(snapshot), &snapshotv1.VolumeSnapshot{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getDv(reconciler).Status.Phase).To(Equal(cdiv1.Canceled))
+	})
+
+	It("should not restore the snapshot of a canceled clone", func() {
+		dv := newCanceledDv()
+		snapshot := newSnapshot(dv)
+		snapshot.Spec.Source = snapshotv1.VolumeSnapshotSource{
+			PersistentVolumeClaimName: &[]string{"test"}[0],
+		}
+		snapshot.Status.ReadyToUse = &[]bool{true}[0]
+		reconciler := createSmartCloneReconciler(dv, snapshot)
+
+		_, err := reconciler.reconcileSnapshot(reconciler.log, snapshot)
+		Expect(err).ToNot(HaveOccurred())
+		err = reconciler.client.Get(context.TODO(), client.ObjectKeyFromObject(snapshot), &snapshotv1.VolumeSnapshot{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		err = reconciler.client.Get(context.TODO(), dvKey, &corev1.PersistentVolumeClaim{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+	})
+})
@@ -118,6 +118,26 @@ const (
 	CloneWithoutSource = "CloneWithoutSource"
 	// MessageCloneWithoutSource reports that the source of a clone doesn't exists (message)
 	MessageCloneWithoutSource = "The source %s %s doesn't exist"
+	// CloneVolumeModeMismatch reports that the source and target volume modes of a clone can't be converted (reason)
+	CloneVolumeModeMismatch = "CloneVolumeModeMismatch"
+	// MessageCloneVolumeModeMismatch reports that the source and target volume modes of a clone can't be converted (message)
+	MessageCloneVolumeModeMismatch = "Source volume mode %s and target volume mode %s do not match, and content type %s can't be converted"
+	// CloneAccessModesUnsupported reports that the target access modes of a clone are not supported by the target storage class (reason)
+	CloneAccessModesUnsupported = "CloneAccessModesUnsupported"
+	// MessageCloneAccessModesUnsupported reports that the target access modes of a clone are not supported by the target storage class (message)
+	MessageCloneAccessModesUnsupported = "Target access modes %v are not supported by StorageProfile %s"
+	// CloneSourcePathInvalid reports that the disk selected from the source of a clone can't be cloned (reason)
+	CloneSourcePathInvalid = "CloneSourcePathInvalid"
+	// MessageCloneSourcePathInvalid reports that the disk selected from the source of a clone can't be cloned (message)
+	MessageCloneSourcePathInvalid = "Unable to clone the disk selected by %s: %s"
+	// CloneVolumeModeConversion reports that a host-assisted clone converts the image between volume modes (reason)
+	CloneVolumeModeConversion = "CloneVolumeModeConversion"
+	// MessageCloneVolumeModeConversion reports that a host-assisted clone converts the image between volume modes (message)
+	MessageCloneVolumeModeConversion = "Source volume mode %s and target volume mode %s do not match, the disk image will be converted by a host-assisted clone"
+	// CloneVolumeModeSizeAdjusted reports that the target of a clone converting the image between volume modes was enlarged to hold it (reason)
+	CloneVolumeModeSizeAdjusted = "CloneVolumeModeSizeAdjusted"
+	// MessageCloneVolumeModeSizeAdjusted reports that the target of a clone converting the image between volume modes was enlarged to hold it (message)
+	MessageCloneVolumeModeSizeAdjusted = "Target size %s is too small to hold the %s disk image of the source with the filesystem overhead, requesting %s"
 
 	// AnnCSICloneRequest annotation associates object with CSI Clone Request
 	AnnCSICloneRequest = "cdi.kubevirt.io/CSICloneRequest"
@@ -145,6 +165,37 @@ type CloneReconcilerBase struct {
 	tokenGenerator token.Generator
 }
 
+// adjustConvertedCloneSize enlarges the target of a host-assisted clone converting a block source into the disk.img
+// file of a filesystem target, so the file fits with the filesystem overhead. The source of a clone the other way
+// round is larger than its disk.img, so the target needs no adjustment. It is shared by the local and remote clones.
+func (r *ReconcilerBase) adjustConvertedCloneSize(syncState *dvSyncState, sourcePvc *corev1.PersistentVolumeClaim) error {
+	pvcSpec := syncState.pvcSpec
+	if cc.GetVolumeMode(sourcePvc) != corev1.PersistentVolumeBlock ||
+		util.ResolveVolumeMode(pvcSpec.VolumeMode) != corev1.PersistentVolumeFilesystem ||
+		cc.GetContentType(sourcePvc) != string(cdiv1.DataVolumeKubeVirt) {
+		return nil
+	}
+
+	sourceSize, ok := sourcePvc.Status.Capacity[corev1.ResourceStorage]
+	if !ok {
+		sourceSize = sourcePvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	}
+	required, err := inflateSizeWithOverhead(r.client, sourceSize.Value(), pvcSpec)
+	if err != nil {
+		return err
+	}
+	requested := pvcSpec.Resources.Requests[corev1.ResourceStorage]
+	if requested.Cmp(required) >= 0 {
+		return nil
+	}
+
+	r.log.V(1).Info("Enlarging the target of the volume mode conversion", "requested", requested.String(), "required", required.String())
+	r.recorder.Eventf(syncState.dvMutated, corev1.EventTypeNormal, CloneVolumeModeSizeAdjusted, MessageCloneVolumeModeSizeAdjusted,
+		requested.String(), sourceSize.String(), required.String())
+	pvcSpec.Resources.Requests[corev1.ResourceStorage] = required
+	return nil
+}
+
 func (r *CloneReconcilerBase) ensureExtendedToken(pvc *corev1.PersistentVolumeClaim) error {
 	_, ok := pvc.Annotations[cc.AnnExtendedCloneToken]
 	if ok {
@@ -425,7 +476,12 @@ func (r *CloneReconcilerBase) createExpansionPod(pvc *corev1.PersistentVolumeCla
 		return nil, err
 	}
 
-	workloadNodePlacement, err := cc.GetWorkloadNodePlacement(r.client)
+	workloadNodePlacement, err := cc.GetWorkerPodNodePlacement(r.client, dv.Spec.WorkerPodPlacement)
+	if err != nil {
+		return nil, err
+	}
+
+	image, pullPolicy, err := cc.GetWorkerPodImage(r.client, dv.Spec.WorkerPodImage, r.clonerImage, r.pullPolicy)
 	if err != nil {
 		return nil, err
 	}
@@ -446,8 +502,8 @@ func (r *CloneReconcilerBase) createExpansionPod(pvc *corev1.PersistentVolumeCla
 			Containers: []corev1.Container{
 				{
 					Name:            "dummy",
-					Image:           r.clonerImage,
-					ImagePullPolicy: corev1.PullPolicy(r.pullPolicy),
+					Image:           image,
+					ImagePullPolicy: corev1.PullPolicy(pullPolicy),
 					Command:         []string{"/bin/bash"},
 					Args:            []string{"-c", "echo", "'hello cdi'"},
 				},
@@ -615,7 +671,7 @@ func (r *CloneReconcilerBase) cleanupTransfer(dv *cdiv1.DataVolume) error {
 
 	r.log.V(1).Info("Doing cleanup of transfer")
 
-	if dv.DeletionTimestamp != nil && dv.Status.Phase != cdiv1.Succeeded {
+	if cloneTornDown(dv) {
 		// delete all potential PVCs that may not have owner refs
 		namespaces := []string{dv.Namespace}
 		names := []string{dv.Name}
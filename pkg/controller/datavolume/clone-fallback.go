@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datavolume
+
+import (
+	"fmt"
+	"time"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+)
+
+const (
+	// annCloneFallback holds why the snapshot or CSI clone of the DataVolume fell back to a host assisted clone, once
+	// set the DataVolume keeps the host assisted clone
+	annCloneFallback = "cdi.kubevirt.io/cloneFallback"
+
+	// CloneFallback provides a const to indicate a snapshot or CSI clone fell back to a host assisted clone
+	CloneFallback = "CloneFallback"
+	// MessageCloneFallback provides a const to form the clone fallback message
+	MessageCloneFallback = "%s, falling back to a host assisted clone"
+)
+
+// snapshotReadyTimeout is how long a snapshot clone waits for its snapshot to be ready before falling back to a host
+// assisted clone
+var snapshotReadyTimeout = 10 * time.Minute
+
+// snapshotCloneFailure returns why the clone can't restore the snapshot, a snapshot failing or not ready in time. When
+// the snapshot is not ready yet, it returns how long the clone still waits for it.
+func snapshotCloneFailure(snapshot *snapshotv1.VolumeSnapshot) (string, time.Duration) {
+	if snapshot.Status != nil && snapshot.Status.Error != nil {
+		reason := fmt.Sprintf("Snapshot clone failed: snapshot %s/%s failed", snapshot.Namespace, snapshot.Name)
+		if msg := snapshot.Status.Error.Message; msg != nil {
+			reason = fmt.Sprintf("%s: %s", reason, *msg)
+		}
+		return reason, 0
+	}
+	if snapshot.Status != nil && snapshot.Status.ReadyToUse != nil && *snapshot.Status.ReadyToUse {
+		return "", 0
+	}
+	remaining := snapshotReadyTimeout
+	if !snapshot.CreationTimestamp.IsZero() {
+		remaining = time.Until(snapshot.CreationTimestamp.Add(snapshotReadyTimeout))
+	}
+	if remaining <= 0 {
+		return fmt.Sprintf("Snapshot clone failed: snapshot %s/%s not ready after %s", snapshot.Namespace, snapshot.Name, snapshotReadyTimeout), 0
+	}
+	return "", remaining
+}
+
+// cloneFellBack returns true if the clone of the DataVolume already fell back to a host assisted clone
+func cloneFellBack(dataVolume *cdiv1.DataVolume) bool {
+	_, ok := dataVolume.Annotations[annCloneFallback]
+	return ok
+}
+
+// recordCloneFallback records in the DataVolume annotations why its clone falls back to a host assisted clone, and
+// emits the fallback event. Only the first reason is kept, it returns false if the clone already fell back.
+func recordCloneFallback(recorder record.EventRecorder, dataVolume *cdiv1.DataVolume, reason string) bool {
+	if cloneFellBack(dataVolume) {
+		return false
+	}
+	message := fmt.Sprintf(MessageCloneFallback, reason)
+	cc.AddAnnotation(dataVolume, annCloneFallback, message)
+	// The auto clone strategy reported another clone type, report the one the clone falls back to
+	if _, ok := dataVolume.Annotations[annAutoCloneStrategy]; ok {
+		cc.AddAnnotation(dataVolume, annAutoCloneStrategy, message)
+	}
+	recorder.Event(dataVolume, corev1.EventTypeWarning, CloneFallback, message)
+	return true
+}
+
+// hostAssistedCloneFallback returns the host assisted clone the DataVolume falls back to, recording why in the
+// DataVolume annotations the caller persists
+func (r *PvcCloneReconciler) hostAssistedCloneFallback(dataVolume *cdiv1.DataVolume, reason string) cloneStrategy {
+	if recordCloneFallback(r.recorder, dataVolume, reason) {
+		r.log.V(1).Info("Clone falling back to a host assisted clone", "datavolume", dataVolume.Name, "reason", reason)
+	}
+	return HostAssistedClone
+}
+
+// configuredCloneFallback returns the host assisted clone a snapshot or CSI clone falls back to, recording why when the
+// clone strategy is configured. Trying the default snapshot clone first is not a failure.
+func (r *PvcCloneReconciler) configuredCloneFallback(dataVolume *cdiv1.DataVolume, targetStorageSpec *corev1.PersistentVolumeClaimSpec, reason string) (cloneStrategy, error) {
+	storageClass, err := cc.GetStorageClassByName(r.client, targetStorageSpec.StorageClassName)
+	if err != nil {
+		return NoClone, err
+	}
+	configuredCloneStrategy, err := cc.GetConfiguredCloneStrategy(r.client, storageClass)
+	if err != nil {
+		return NoClone, err
+	}
+	if configuredCloneStrategy == nil {
+		return HostAssistedClone, nil
+	}
+	return r.hostAssistedCloneFallback(dataVolume, reason), nil
+}
+
+// updateCloneFallbackCondition reports why the clone of the DataVolume fell back to a host assisted clone
+func updateCloneFallbackCondition(conditions []cdiv1.DataVolumeCondition, dataVolume *cdiv1.DataVolume) []cdiv1.DataVolumeCondition {
+	message, ok := dataVolume.Annotations[annCloneFallback]
+	if !ok {
+		return conditions
+	}
+	return updateCondition(conditions, cdiv1.DataVolumeCloneFallback, corev1.ConditionTrue, message, cloneStrategyHostAssisted)
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datavolume
+
+import (
+	"context"
+	"time"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	. "kubevirt.io/containerized-data-importer/pkg/controller/common"
+)
+
+var _ = Describe("Clone strategy fallback", func() {
+	snapshot := cdiv1.CloneStrategySnapshot
+	csiClone := cdiv1.CloneStrategyCsiClone
+	accessMode := []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+	sourceScName := "sourcesc"
+
+	newReconciler := func(dv *cdiv1.DataVolume, cloneStrategy *cdiv1.CDICloneStrategy, objects ...runtime.Object) *PvcCloneReconciler {
+		srcPvc := CreatePvcInStorageClass("test", metav1.NamespaceDefault, &sourceScName, nil, nil, corev1.ClaimBound)
+		targetScName := *dv.Spec.PVC.StorageClassName
+		targetStorageProfile := createStorageProfileWithCloneStrategy(targetScName,
+			[]cdiv1.ClaimPropertySet{{AccessModes: accessMode, VolumeMode: &FilesystemMode}}, cloneStrategy)
+		objects = append(objects, dv, srcPvc, targetStorageProfile,
+			CreateStorageClassWithProvisioner(sourceScName, nil, map[string]string{}, "csi-plugin"),
+			createVolumeSnapshotContentCrd(), createVolumeSnapshotClassCrd(), createVolumeSnapshotCrd())
+		if targetScName != sourceScName {
+			objects = append(objects, CreateStorageClassWithProvisioner(targetScName, nil, map[string]string{}, "csi-plugin"))
+		}
+		return createCloneReconciler(objects...)
+	}
+
+	table.DescribeTable("should report why a configured clone strategy falls back", func(cloneStrategy cdiv1.CDICloneStrategy, targetScName string, snapshotClass bool, expectedReason string) {
+		dv := newCloneDataVolume("test-dv")
+		dv.Spec.PVC.StorageClassName = &targetScName
+		objects := []runtime.Object{}
+		if snapshotClass {
+			objects = append(objects, createSnapshotClass("snap-class", nil, "csi-plugin"))
+		}
+		reconciler := newReconciler(dv, &cloneStrategy, objects...)
+
+		strategy, err := reconciler.selectCloneStrategy(dv, dv.Spec.PVC)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strategy).To(Equal(HostAssistedClone))
+		Expect(dv.Annotations[annCloneFallback]).To(ContainSubstring(expectedReason))
+		Expect(dv.Annotations[annCloneFallback]).To(HaveSuffix("falling back to a host assisted clone"))
+		Expect(reconciler.recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring(CloneFallback)))
+
+		conditions := updateCloneFallbackCondition(nil, dv)
+		condition := FindConditionByType(cdiv1.DataVolumeCloneFallback, conditions)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(cloneStrategyHostAssisted))
+		Expect(condition.Message).To(Equal(dv.Annotations[annCloneFallback]))
+	},
+		table.Entry("snapshot clone without snapshot class", snapshot, sourceScName, false, "Snapshot clone not possible: no VolumeSnapshotClass"),
+		table.Entry("snapshot clone across storage classes", snapshot, "targetsc", true, "StorageProfile targetsc does not restore its snapshots"),
+		table.Entry("CSI clone across storage classes", csiClone, "targetsc", true, "CSI clone not possible: source storage class sourcesc and target storage class targetsc differ"),
+	)
+
+	It("should not report the fallback of the default snapshot clone", func() {
+		dv := newCloneDataVolume("test-dv")
+		dv.Spec.PVC.StorageClassName = &sourceScName
+		reconciler := newReconciler(dv, nil)
+
+		strategy, err := reconciler.selectCloneStrategy(dv, dv.Spec.PVC)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strategy).To(Equal(HostAssistedClone))
+		Expect(dv.Annotations).ToNot(HaveKey(annCloneFallback))
+		Expect(updateCloneFallbackCondition(nil, dv)).To(BeEmpty())
+	})
+
+	It("should keep the host assisted clone once fallen back", func() {
+		dv := newCloneDataVolume("test-dv")
+		dv.Spec.PVC.StorageClassName = &sourceScName
+		AddAnnotation(dv, annCloneFallback, "Snapshot clone failed, falling back to a host assisted clone")
+		reconciler := newReconciler(dv, &snapshot, createSnapshotClass("snap-class", nil, "csi-plugin"))
+
+		strategy, err := reconciler.selectCloneStrategy(dv, dv.Spec.PVC)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strategy).To(Equal(HostAssistedClone))
+		Expect(dv.Annotations[annCloneFallback]).To(Equal("Snapshot clone failed, falling back to a host assisted clone"))
+	})
+
+	It("should fall back to a host assisted clone without CSI driver", func() {
+		dv := newCloneDataVolume("test-dv")
+		dv.Spec.PVC.StorageClassName = &sourceScName
+		reconciler := newReconciler(dv, &csiClone)
+
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		By("Verifying that the host assisted clone created the target PVC")
+		targetPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Namespace: dv.Namespace, Name: dv.Name}, targetPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(targetPvc.Annotations[AnnCloneRequest]).To(Equal("default/test"))
+		Expect(targetPvc.Annotations).ToNot(HaveKey(AnnCSICloneRequest))
+		dv = &cdiv1.DataVolume{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dv.Annotations[annCloneType]).To(Equal(cloneStrategyToCloneType(HostAssistedClone)))
+		Expect(dv.Annotations[annCloneFallback]).To(ContainSubstring("no CSIDriver available for sourcesc"))
+		condition := FindConditionByType(cdiv1.DataVolumeCloneFallback, dv.Status.Conditions)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+	})
+
+	Context("with the smart clone controller", func() {
+		newSnapshot := func(dv *cdiv1.DataVolume, status *snapshotv1.VolumeSnapshotStatus) *snapshotv1.VolumeSnapshot {
+			snapshot := createSnapshotVolume(dv.Name, dv.Namespace, nil)
+			snapshot.Spec.Source = snapshotv1.VolumeSnapshotSource{
+				PersistentVolumeClaimName: &[]string{"source"}[0],
+			}
+			snapshot.Status = status
+			setAnnOwnedByDataVolume(snapshot, dv)
+			return snapshot
+		}
+
+		expectFallback := func(reconciler *SmartCloneReconciler, snapshot *snapshotv1.VolumeSnapshot, expectedReason string) {
+			dv := &cdiv1.DataVolume{}
+			err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dv.Annotations[annCloneFallback]).To(ContainSubstring(expectedReason))
+			Expect(reconciler.recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring(CloneFallback)))
+			By("Verifying that the snapshot was deleted and no PVC restored")
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Namespace: snapshot.Namespace, Name: snapshot.Name}, &snapshotv1.VolumeSnapshot{})
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Namespace: dv.Namespace, Name: dv.Name}, &corev1.PersistentVolumeClaim{})
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		}
+
+		It("should fall back to a host assisted clone if the snapshot failed", func() {
+			dv := newCloneDataVolume("test-dv")
+			snapshot := newSnapshot(dv, &snapshotv1.VolumeSnapshotStatus{
+				ReadyToUse: &[]bool{false}[0],
+				Error: &snapshotv1.VolumeSnapshotError{
+					Message: &[]string{"VolumeSnapshotClass snap-class not found"}[0],
+				},
+			})
+			reconciler := createSmartCloneReconciler(dv, snapshot)
+
+			_, err := reconciler.reconcileSnapshot(reconciler.log, snapshot)
+			Expect(err).ToNot(HaveOccurred())
+			expectFallback(reconciler, snapshot, "failed: VolumeSnapshotClass snap-class not found")
+		})
+
+		It("should fall back to a host assisted clone if the snapshot is not ready in time", func() {
+			dv := newCloneDataVolume("test-dv")
+			snapshot := newSnapshot(dv, &snapshotv1.VolumeSnapshotStatus{ReadyToUse: &[]bool{false}[0]})
+			reconciler := createSmartCloneReconciler(dv, snapshot)
+
+			result, err := reconciler.reconcileSnapshot(reconciler.log, snapshot)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+
+			snapshot.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * snapshotReadyTimeout))
+			_, err = reconciler.reconcileSnapshot(reconciler.log, snapshot)
+			Expect(err).ToNot(HaveOccurred())
+			expectFallback(reconciler, snapshot, "not ready after 10m0s")
+		})
+
+		It("should fall back to a host assisted clone if the snapshot restores larger than the target", func() {
+			dv := newCloneDataVolume("test-dv")
+			restoreSize := resource.MustParse("2G")
+			snapshot := newSnapshot(dv, &snapshotv1.VolumeSnapshotStatus{
+				ReadyToUse:  &[]bool{true}[0],
+				RestoreSize: &restoreSize,
+			})
+			reconciler := createSmartCloneReconciler(dv, snapshot)
+
+			_, err := reconciler.reconcileSnapshot(reconciler.log, snapshot)
+			Expect(err).ToNot(HaveOccurred())
+			expectFallback(reconciler, snapshot, "snapshot restore size 2G is larger than the target size 1G")
+		})
+
+		It("should delete the snapshot of a clone that fell back", func() {
+			dv := newCloneDataVolume("test-dv")
+			AddAnnotation(dv, annCloneFallback, "Snapshot clone failed, falling back to a host assisted clone")
+			snapshot := newSnapshot(dv, &snapshotv1.VolumeSnapshotStatus{})
+			reconciler := createSmartCloneReconciler(dv, snapshot)
+
+			_, err := reconciler.reconcileSnapshot(reconciler.log, snapshot)
+			Expect(err).ToNot(HaveOccurred())
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Namespace: snapshot.Namespace, Name: snapshot.Name}, &snapshotv1.VolumeSnapshot{})
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+})
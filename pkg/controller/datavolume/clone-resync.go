@@ -0,0 +1,121 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datavolume
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+	"kubevirt.io/containerized-data-importer/pkg/util/naming"
+)
+
+const (
+	// annCloneSourcePod is the PVC annotation the clone controller names the clone source pod with
+	annCloneSourcePod = "cdi.kubevirt.io/storage.sourceClonePodName"
+
+	// CloneResyncStarted provides a const to indicate a succeeded clone is re-synced from its source
+	CloneResyncStarted = "CloneResyncStarted"
+	// CloneResyncUnsupported provides a const to indicate a clone cannot be re-synced from its source
+	CloneResyncUnsupported = "CloneResyncUnsupported"
+	// CloneResyncUnauthorized provides a const to indicate the user asking for the re-sync of a clone is not authorized
+	CloneResyncUnauthorized = "CloneResyncUnauthorized"
+
+	// MessageCloneResyncStarted provides a const to form the clone re-sync started message
+	MessageCloneResyncStarted = "Re-syncing DataVolume %s from %s/%s, only streaming the changed blocks"
+	// MessageCloneResyncUnsupported provides a const to form the clone re-sync unsupported message
+	MessageCloneResyncUnsupported = "DataVolume %s cannot be re-synced, only a host-assisted clone can"
+	// MessageCloneResyncUnauthorized provides a const to form the clone re-sync unauthorized message
+	MessageCloneResyncUnauthorized = "DataVolume %s cannot be re-synced, the re-sync request is not authorized: %v"
+)
+
+// cloneResyncRequested returns true if the succeeded clone DataVolume asks to be re-synced with a value of its resync
+// annotation the PVC did not handle yet. The PVC created for the DataVolume gets its annotations, so the value the
+// DataVolume was created with does not ask for a re-sync.
+func cloneResyncRequested(dv *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim) bool {
+	value, ok := dv.Annotations[cc.AnnCloneResync]
+	return ok && pvc != nil && dv.Status.Phase == cdiv1.Succeeded && pvc.Annotations[cc.AnnCloneResync] != value
+}
+
+// resyncClone starts the incremental re-sync of a succeeded host-assisted clone: it resets the clone annotations of
+// the PVC, so the upload and clone controllers run their pods again, with the clone source only streaming the blocks
+// changed since the PVC was cloned. The DataVolume moves back to CloneScheduled until the pods succeed. The long term
+// token of the PVC does not authorize a re-sync of a clone from another namespace, the user asking for it has to be
+// authorized again, the webhook then giving the DataVolume a fresh token for the requested re-sync.
+func (r *PvcCloneReconciler) resyncClone(syncState *dvSyncState, log logr.Logger) error {
+	dv := syncState.dvMutated
+	pvc := syncState.pvc
+	if !cloneResyncRequested(dv, pvc) {
+		return nil
+	}
+
+	pvcCopy := pvc.DeepCopy()
+	pvcCopy.Annotations[cc.AnnCloneResync] = dv.Annotations[cc.AnnCloneResync]
+	if dv.Annotations[annCloneType] != cloneStrategyToCloneType(HostAssistedClone) || pvc.Annotations[cc.AnnCloneRequest] == "" {
+		log.V(1).Info("Only a host-assisted clone can be re-synced, ignoring")
+		r.recorder.Eventf(dv, corev1.EventTypeWarning, CloneResyncUnsupported, MessageCloneResyncUnsupported, dv.Name)
+		return r.updatePVC(pvcCopy)
+	}
+	if err := cc.ValidateCloneResyncTokenDV(r.tokenValidator, dv); err != nil {
+		log.V(1).Info("The re-sync of the clone is not authorized, ignoring", "error", err)
+		r.recorder.Eventf(dv, corev1.EventTypeWarning, CloneResyncUnauthorized, MessageCloneResyncUnauthorized, dv.Name, err)
+		return r.updatePVC(pvcCopy)
+	}
+
+	// The pods of the previous clone are retained with storage.pod.retainAfterCompletion, and would be found again
+	sourceNamespace, sourceName, err := cache.SplitMetaNamespaceKey(pvc.Annotations[cc.AnnCloneRequest])
+	if err != nil {
+		return err
+	}
+	if err := r.deletePod(sourceNamespace, cc.CreateCloneSourcePodName(pvc)); err != nil {
+		return err
+	}
+	if err := r.deletePod(pvc.Namespace, naming.GetResourceName(common.UploadPodName, pvc.Name)); err != nil {
+		return err
+	}
+
+	log.Info("Re-syncing the clone from its source")
+	for _, key := range []string{cc.AnnPodPhase, cc.AnnPodReady, cc.AnnCloneOf, cc.AnnRunningCondition, cc.AnnRunningConditionMessage, cc.AnnRunningConditionReason} {
+		delete(pvcCopy.Annotations, key)
+	}
+	// Name the source pod again, so the clone controller protects it with its finalizer
+	delete(pvcCopy.Annotations, annCloneSourcePod)
+	pvcCopy.Annotations[cc.AnnCloneIncremental] = "true"
+	if err := r.updatePVC(pvcCopy); err != nil {
+		return err
+	}
+	syncState.pvc = pvcCopy
+	r.recorder.Eventf(dv, corev1.EventTypeNormal, CloneResyncStarted, MessageCloneResyncStarted, dv.Name, sourceNamespace, sourceName)
+	return nil
+}
+
+func (r *PvcCloneReconciler) deletePod(namespace, name string) error {
+	pod := &corev1.Pod{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, pod); err != nil {
+		return cc.IgnoreNotFound(err)
+	}
+	if pod.DeletionTimestamp != nil {
+		return nil
+	}
+	return cc.IgnoreNotFound(r.client.Delete(context.TODO(), pod))
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datavolume
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	. "kubevirt.io/containerized-data-importer/pkg/controller/common"
+	"kubevirt.io/containerized-data-importer/pkg/util/naming"
+)
+
+var _ = Describe("Clone re-sync", func() {
+	dvKey := types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}
+
+	// clonedPvc returns the PVC of a succeeded clone DataVolume, with the pods of the clone retained
+	clonedPvc := func(dv *cdiv1.DataVolume) (*PvcCloneReconciler, *dvSyncState) {
+		dv.Status.Phase = cdiv1.Succeeded
+		pvc := CreatePvc(dv.Name, dv.Namespace, map[string]string{
+			AnnCloneRequest:     "default/test",
+			AnnCloneOf:          "true",
+			AnnPodPhase:         string(corev1.PodSucceeded),
+			AnnPodReady:         "false",
+			AnnCloneResync:      "1",
+			annCloneSourcePod:   "uid-source-pod",
+			AnnRunningCondition: "false",
+		}, nil)
+		pvc.UID = "uid"
+		sourcePod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: CreateCloneSourcePodName(pvc), Namespace: "default"}}
+		uploadPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: naming.GetResourceName(common.UploadPodName, pvc.Name), Namespace: "default"}}
+		reconciler := createCloneReconciler(dv, pvc, sourcePod, uploadPod)
+		return reconciler, &dvSyncState{dv: dv, dvMutated: dv.DeepCopy(), pvc: pvc}
+	}
+
+	getPvc := func(reconciler *PvcCloneReconciler) *corev1.PersistentVolumeClaim {
+		pvc := &corev1.PersistentVolumeClaim{}
+		Expect(reconciler.client.Get(context.TODO(), dvKey, pvc)).To(Succeed())
+		return pvc
+	}
+
+	podExists := func(reconciler *PvcCloneReconciler, name string) bool {
+		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: "default"}, &corev1.Pod{})
+		if k8serrors.IsNotFound(err) {
+			return false
+		}
+		Expect(err).ToNot(HaveOccurred())
+		return true
+	}
+
+	It("should restart a host-assisted clone incrementally when the resync annotation changes", func() {
+		dv := newCloneDataVolume("test-dv")
+		dv.Annotations[annCloneType] = cloneStrategyToCloneType(HostAssistedClone)
+		dv.Annotations[AnnCloneResync] = "2"
+		reconciler, syncState := clonedPvc(dv)
+		clonedClaim := syncState.pvc
+
+		Expect(reconciler.resyncClone(syncState, reconciler.log)).To(Succeed())
+		pvc := getPvc(reconciler)
+		Expect(pvc.Annotations).To(HaveKeyWithValue(AnnCloneResync, "2"))
+		Expect(pvc.Annotations).To(HaveKeyWithValue(AnnCloneIncremental, "true"))
+		for _, key := range []string{AnnPodPhase, AnnPodReady, AnnCloneOf, AnnRunningCondition, annCloneSourcePod} {
+			Expect(pvc.Annotations).ToNot(HaveKey(key))
+		}
+		Expect(podExists(reconciler, CreateCloneSourcePodName(clonedClaim))).To(BeFalse())
+		Expect(podExists(reconciler, naming.GetResourceName(common.UploadPodName, pvc.Name))).To(BeFalse())
+		Expect(reconciler.recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring(CloneResyncStarted)))
+	})
+
+	It("should not re-sync again for the value of the annotation already handled", func() {
+		dv := newCloneDataVolume("test-dv")
+		dv.Annotations[annCloneType] = cloneStrategyToCloneType(HostAssistedClone)
+		dv.Annotations[AnnCloneResync] = "1"
+		reconciler, syncState := clonedPvc(dv)
+
+		Expect(reconciler.resyncClone(syncState, reconciler.log)).To(Succeed())
+		Expect(getPvc(reconciler).Annotations).To(HaveKeyWithValue(AnnPodPhase, string(corev1.PodSucceeded)))
+		Expect(podExists(reconciler, naming.GetResourceName(common.UploadPodName, dv.Name))).To(BeTrue())
+	})
+
+	Context("of a clone from another namespace", func() {
+		// resyncRequest returns a DataVolume cloned from another namespace asking for a re-sync with token, and its
+		// reconciler validating the tokens issued for the re-sync value tokenResync
+		resyncRequest := func(token, tokenResync string) (*PvcCloneReconciler, *dvSyncState) {
+			dv := newCloneDataVolumeWithPVCNS("test-dv", "source-ns")
+			dv.Annotations[annCloneType] = cloneStrategyToCloneType(HostAssistedClone)
+			dv.Annotations[AnnCloneResync] = "2"
+			if token != "" {
+				dv.Annotations[AnnCloneResyncToken] = token
+			}
+			reconciler, syncState := clonedPvc(dv)
+			reconciler.tokenValidator = &FakeValidator{
+				Match:     "resync-token",
+				Name:      "test",
+				Namespace: "source-ns",
+				Params:    map[string]string{"targetNamespace": "default", "targetName": "test-dv", "resync": tokenResync},
+			}
+			return reconciler, syncState
+		}
+
+		It("should re-sync with the token of the re-sync request", func() {
+			reconciler, syncState := resyncRequest("resync-token", "2")
+			Expect(reconciler.resyncClone(syncState, reconciler.log)).To(Succeed())
+			Expect(getPvc(reconciler).Annotations).To(HaveKeyWithValue(AnnCloneIncremental, "true"))
+			Expect(reconciler.recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring(CloneResyncStarted)))
+		})
+
+		DescribeTable("should only record the annotation", func(token, tokenResync string) {
+			reconciler, syncState := resyncRequest(token, tokenResync)
+			Expect(reconciler.resyncClone(syncState, reconciler.log)).To(Succeed())
+			pvc := getPvc(reconciler)
+			Expect(pvc.Annotations).To(HaveKeyWithValue(AnnCloneResync, "2"))
+			Expect(pvc.Annotations).To(HaveKeyWithValue(AnnPodPhase, string(corev1.PodSucceeded)))
+			Expect(pvc.Annotations).ToNot(HaveKey(AnnCloneIncremental))
+			Expect(reconciler.recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring(CloneResyncUnauthorized)))
+		},
+			Entry("without token", "", "2"),
+			Entry("with an invalid token", "foobar", "2"),
+			Entry("with the token of another re-sync", "resync-token", "1"),
+		)
+	})
+
+	It("should only record the annotation of a clone that is not host-assisted", func() {
+		dv := newCloneDataVolume("test-dv")
+		dv.Annotations[annCloneType] = cloneStrategyToCloneType(CsiClone)
+		dv.Annotations[AnnCloneResync] = "2"
+		reconciler, syncState := clonedPvc(dv)
+
+		Expect(reconciler.resyncClone(syncState, reconciler.log)).To(Succeed())
+		pvc := getPvc(reconciler)
+		Expect(pvc.Annotations).To(HaveKeyWithValue(AnnCloneResync, "2"))
+		Expect(pvc.Annotations).To(HaveKeyWithValue(AnnPodPhase, string(corev1.PodSucceeded)))
+		Expect(pvc.Annotations).ToNot(HaveKey(AnnCloneIncremental))
+		Expect(reconciler.recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring(CloneResyncUnsupported)))
+	})
+})
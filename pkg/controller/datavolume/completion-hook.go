@@ -0,0 +1,282 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datavolume
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+const (
+	// CompletionHookNotified provides a const to indicate the completion notification was delivered
+	CompletionHookNotified = "CompletionHookNotified"
+	// CompletionHookFailed provides a const to indicate the completion notification failed and is retried
+	CompletionHookFailed = "CompletionHookFailed"
+	// CompletionHookRejected provides a const to indicate the completion notification was rejected and is not retried
+	CompletionHookRejected = "CompletionHookRejected"
+
+	// MessageCompletionHookNotified provides a const to form the completion notification delivered message
+	MessageCompletionHookNotified = "Completion of DataVolume %s notified to %s"
+	// MessageCompletionHookFailed provides a const to form the completion notification failure message
+	MessageCompletionHookFailed = "Completion notification of DataVolume %s failed, retrying: %v"
+	// MessageCompletionHookRejected provides a const to form the completion notification rejection message
+	MessageCompletionHookRejected = "Completion notification of DataVolume %s rejected, not retrying: %v"
+
+	// completionHookTokenKey is the key of the bearer token in the Secret of the completion hook
+	completionHookTokenKey = "token"
+)
+
+// completionHookClient is the HTTP client of the completion notifications
+var completionHookClient = &http.Client{Timeout: 30 * time.Second}
+
+// completionNotification is the JSON body POSTed to the URL of the completion hook
+type completionNotification struct {
+	Namespace      string                `json:"namespace"`
+	Name           string                `json:"name"`
+	UID            types.UID             `json:"uid"`
+	Phase          cdiv1.DataVolumePhase `json:"phase"`
+	ClaimName      string                `json:"claimName"`
+	CompletionTime string                `json:"completionTime"`
+}
+
+// completionHookRejectedError is a completion notification failure retrying won't fix
+type completionHookRejectedError struct {
+	err error
+}
+
+func (e *completionHookRejectedError) Error() string {
+	return e.err.Error()
+}
+
+// completionNotifications holds the UIDs of the DataVolumes which completion is being notified
+var completionNotifications sync.Map
+
+// completionHookBackoff is the backoff between the attempts of a completion notification failing on a transient error
+var completionHookBackoff = wait.Backoff{Duration: 5 * time.Second, Factor: 2, Steps: 10, Cap: 5 * time.Minute}
+
+func hasCompletionHook(dv *cdiv1.DataVolume) bool {
+	return dv.Annotations[cc.AnnCompletionHookAnnotation] != "" || dv.Annotations[cc.AnnCompletionHook] != ""
+}
+
+func completionHookFired(dv *cdiv1.DataVolume) bool {
+	_, fired := dv.Annotations[cc.AnnCompletionHookFired]
+	return fired
+}
+
+// setCompletionHookFired writes the completion annotation of the DataVolume and marks its hook as fired
+func setCompletionHookFired(dv *cdiv1.DataVolume, completionTime string) {
+	if annotation := dv.Annotations[cc.AnnCompletionHookAnnotation]; annotation != "" {
+		key, value, found := strings.Cut(annotation, "=")
+		if !found {
+			value = completionTime
+		}
+		cc.AddAnnotation(dv, key, value)
+	}
+	cc.AddAnnotation(dv, cc.AnnCompletionHookFired, completionTime)
+}
+
+// fireCompletionHook writes the completion annotation and notifies the completion hook of a succeeded DataVolume, once.
+// The hook is one of the CDIConfig, so the endpoint and its credentials are the ones set by the admin. The notification
+// is sent in the background, retrying transient failures, and the hook is only marked as fired, along with the
+// completion annotation, once it was delivered or rejected.
+func (r *ReconcilerBase) fireCompletionHook(syncState *dvSyncState) error {
+	dv := syncState.dv
+	if dv.Status.Phase != cdiv1.Succeeded || !hasCompletionHook(dv) || completionHookFired(dv) {
+		return nil
+	}
+
+	completionTime := time.Now().UTC().Format(time.RFC3339)
+	if hookName := dv.Annotations[cc.AnnCompletionHook]; hookName != "" {
+		hook, err := r.getCompletionHook(hookName)
+		if err != nil {
+			return err
+		}
+		if hook != nil {
+			claimName := dv.Name
+			if syncState.pvc != nil {
+				claimName = syncState.pvc.Name
+			}
+			if _, inFlight := completionNotifications.LoadOrStore(dv.UID, struct{}{}); !inFlight {
+				go r.notifyCompletionInBackground(dv.DeepCopy(), *hook, claimName, completionTime)
+			}
+			return nil
+		}
+		r.recorder.Eventf(dv, corev1.EventTypeWarning, CompletionHookRejected, MessageCompletionHookRejected, dv.Name,
+			errors.Errorf("completion hook %s is not configured in the CDIConfig", hookName))
+	}
+
+	setCompletionHookFired(syncState.dvMutated, completionTime)
+	// Persisted right away, so the hook does not fire again whatever the rest of the reconcile does
+	if err := r.updateDataVolume(syncState.dvMutated); err != nil {
+		return err
+	}
+	syncState.dv = syncState.dvMutated.DeepCopy()
+	return nil
+}
+
+// getCompletionHook returns the completion hook of the CDIConfig with the given name, nil if there is none
+func (r *ReconcilerBase) getCompletionHook(name string) (*cdiv1.CompletionHook, error) {
+	cdiConfig := &cdiv1.CDIConfig{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig); err != nil {
+		return nil, err
+	}
+	for i := range cdiConfig.Spec.CompletionHooks {
+		if cdiConfig.Spec.CompletionHooks[i].Name == name {
+			return &cdiConfig.Spec.CompletionHooks[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// notifyCompletionInBackground notifies the completion hook of the DataVolume until the notification is delivered or
+// rejected, then marks the hook as fired. It gives up if the DataVolume is deleted or recreated meanwhile.
+func (r *ReconcilerBase) notifyCompletionInBackground(dv *cdiv1.DataVolume, hook cdiv1.CompletionHook, claimName, completionTime string) {
+	defer completionNotifications.Delete(dv.UID)
+	log := r.log.WithValues("DataVolume", dv.Name, "namespace", dv.Namespace)
+	backoff := completionHookBackoff
+
+	for {
+		err := r.notifyCompletion(dv, hook, claimName, completionTime)
+		var rejected *completionHookRejectedError
+		if err == nil {
+			r.recorder.Eventf(dv, corev1.EventTypeNormal, CompletionHookNotified, MessageCompletionHookNotified, dv.Name, util.RedactSourceURL(hook.URL))
+			break
+		}
+		if errors.As(err, &rejected) {
+			r.recorder.Eventf(dv, corev1.EventTypeWarning, CompletionHookRejected, MessageCompletionHookRejected, dv.Name, err)
+			break
+		}
+		r.recorder.Eventf(dv, corev1.EventTypeWarning, CompletionHookFailed, MessageCompletionHookFailed, dv.Name, err)
+		time.Sleep(backoff.Step())
+		if _, err := r.getCurrentDataVolume(dv); err != nil {
+			log.V(1).Info("Giving up the completion notification", "reason", err.Error())
+			return
+		}
+	}
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current, err := r.getCurrentDataVolume(dv)
+		if err != nil {
+			return err
+		}
+		setCompletionHookFired(current, completionTime)
+		return r.updateDataVolume(current)
+	})
+	if err != nil {
+		log.Error(err, "Unable to mark the completion hook as fired")
+	}
+}
+
+// getCurrentDataVolume returns the DataVolume as stored, failing if it was deleted or recreated
+func (r *ReconcilerBase) getCurrentDataVolume(dv *cdiv1.DataVolume) (*cdiv1.DataVolume, error) {
+	current := &cdiv1.DataVolume{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: dv.Namespace, Name: dv.Name}, current); err != nil {
+		return nil, err
+	}
+	if current.UID != dv.UID {
+		return nil, errors.Errorf("DataVolume %s/%s was recreated", dv.Namespace, dv.Name)
+	}
+	return current, nil
+}
+
+// notifyCompletion POSTs the completion of the DataVolume to the URL of the hook, with the bearer token of the Secret
+// of the hook if any. The UID of the DataVolume is sent as Idempotency-Key, as a retried notification may be
+// delivered more than once.
+func (r *ReconcilerBase) notifyCompletion(dv *cdiv1.DataVolume, hook cdiv1.CompletionHook, claimName, completionTime string) error {
+	hookURL := hook.URL
+	u, err := url.Parse(hookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &completionHookRejectedError{errors.Errorf("invalid completion hook URL %s, expecting an http or https URL", util.RedactSourceURL(hookURL))}
+	}
+
+	body, err := json.Marshal(&completionNotification{
+		Namespace:      dv.Namespace,
+		Name:           dv.Name,
+		UID:            dv.UID,
+		Phase:          dv.Status.Phase,
+		ClaimName:      claimName,
+		CompletionTime: completionTime,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, hookURL, bytes.NewReader(body))
+	if err != nil {
+		return &completionHookRejectedError{errors.Errorf("invalid completion hook URL %s", util.RedactSourceURL(hookURL))}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", string(dv.UID))
+
+	if hook.TokenSecret != "" {
+		token, err := r.getCompletionHookToken(hook.TokenSecret)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := completionHookClient.Do(req)
+	if err != nil {
+		// The error of the client holds the URL as is
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return errors.Wrapf(err, "could not notify %s", util.RedactSourceURL(hookURL))
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode >= 500:
+		return errors.Errorf("%s answered %s", util.RedactSourceURL(hookURL), resp.Status)
+	}
+	return &completionHookRejectedError{errors.Errorf("%s answered %s", util.RedactSourceURL(hookURL), resp.Status)}
+}
+
+// getCompletionHookToken returns the bearer token of the Secret of the completion hook, which lives in the CDI
+// namespace. A missing Secret is retried, as it may be created after the CDIConfig entry.
+func (r *ReconcilerBase) getCompletionHookToken(secretName string) (string, error) {
+	secret := &corev1.Secret{}
+	if err := r.uncachedClient.Get(context.TODO(), types.NamespacedName{Namespace: util.GetNamespace(), Name: secretName}, secret); err != nil {
+		return "", errors.Wrapf(err, "could not get the completion hook Secret %s", secretName)
+	}
+	token, ok := secret.Data[completionHookTokenKey]
+	if !ok {
+		return "", fmt.Errorf("completion hook Secret %s has no %s key", secretName, completionHookTokenKey)
+	}
+	return strings.TrimSpace(string(token)), nil
+}
@@ -0,0 +1,301 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datavolume
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	. "kubevirt.io/containerized-data-importer/pkg/controller/common"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+var _ = Describe("DataVolume completion hook", func() {
+	dvKey := types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}
+
+	// succeededDataVolume returns the reconciler of a DataVolume with the annotations, which import succeeded
+	succeededDataVolume := func(annotations map[string]string, objects ...*corev1.Secret) *ImportReconciler {
+		dv := NewImportDataVolume("test-dv")
+		for k, v := range annotations {
+			AddAnnotation(dv, k, v)
+		}
+		reconciler := createImportReconciler(dv)
+		for _, obj := range objects {
+			Expect(reconciler.client.Create(context.TODO(), obj)).To(Succeed())
+		}
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: dvKey})
+		Expect(err).ToNot(HaveOccurred())
+
+		pvc := &corev1.PersistentVolumeClaim{}
+		Expect(reconciler.client.Get(context.TODO(), dvKey, pvc)).To(Succeed())
+		pvc.Status.Phase = corev1.ClaimBound
+		pvc.Annotations[AnnPodPhase] = string(corev1.PodSucceeded)
+		Expect(reconciler.client.Update(context.TODO(), pvc)).To(Succeed())
+		Expect(reconciler.client.Get(context.TODO(), dvKey, dv)).To(Succeed())
+		_, err = reconciler.updateStatus(getReconcileRequest(dv), nil, reconciler)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reconciler.client.Get(context.TODO(), dvKey, dv)).To(Succeed())
+		Expect(dv.Status.Phase).To(Equal(cdiv1.Succeeded))
+		return reconciler
+	}
+
+	reconcileDataVolume := func(reconciler *ImportReconciler) (*cdiv1.DataVolume, error) {
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: dvKey})
+		dv := &cdiv1.DataVolume{}
+		Expect(reconciler.client.Get(context.TODO(), dvKey, dv)).To(Succeed())
+		return dv, err
+	}
+
+	events := func(reconciler *ImportReconciler) []string {
+		var events []string
+		for len(reconciler.recorder.(*record.FakeRecorder).Events) > 0 {
+			events = append(events, <-reconciler.recorder.(*record.FakeRecorder).Events)
+		}
+		return events
+	}
+
+	It("should write the completion annotation once the DataVolume succeeded", func() {
+		reconciler := succeededDataVolume(map[string]string{AnnCompletionHookAnnotation: "example.com/imported=true"})
+		dv, err := reconcileDataVolume(reconciler)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dv.Annotations).To(HaveKeyWithValue("example.com/imported", "true"))
+		fired := dv.Annotations[AnnCompletionHookFired]
+		_, err = time.Parse(time.RFC3339, fired)
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Not writing the annotation again")
+		dv.Annotations["example.com/imported"] = "consumed"
+		Expect(reconciler.client.Update(context.TODO(), dv)).To(Succeed())
+		dv, err = reconcileDataVolume(reconciler)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dv.Annotations).To(HaveKeyWithValue("example.com/imported", "consumed"))
+		Expect(dv.Annotations).To(HaveKeyWithValue(AnnCompletionHookFired, fired))
+	})
+
+	It("should default the value of the completion annotation to the completion time", func() {
+		reconciler := succeededDataVolume(map[string]string{AnnCompletionHookAnnotation: "example.com/imported"})
+		dv, err := reconcileDataVolume(reconciler)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dv.Annotations["example.com/imported"]).To(Equal(dv.Annotations[AnnCompletionHookFired]))
+	})
+
+	It("should not fire the completion hook before the DataVolume succeeded", func() {
+		dv := NewImportDataVolume("test-dv")
+		AddAnnotation(dv, AnnCompletionHookAnnotation, "example.com/imported")
+		reconciler := createImportReconciler(dv)
+		dv, err := reconcileDataVolume(reconciler)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dv.Annotations).ToNot(HaveKey("example.com/imported"))
+		Expect(dv.Annotations).ToNot(HaveKey(AnnCompletionHookFired))
+	})
+
+	Context("with a completion hook of the CDIConfig", func() {
+		var (
+			ts            *httptest.Server
+			mutex         sync.Mutex
+			statusCodes   []int
+			requests      []*http.Request
+			notifications []completionNotification
+			backoff       wait.Backoff
+		)
+
+		numRequests := func() int {
+			mutex.Lock()
+			defer mutex.Unlock()
+			return len(requests)
+		}
+
+		configureCompletionHooks := func(reconciler *ImportReconciler, hooks ...cdiv1.CompletionHook) {
+			cdiConfig := &cdiv1.CDIConfig{}
+			Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+			cdiConfig.Spec.CompletionHooks = hooks
+			Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+		}
+
+		waitForCompletionHook := func(reconciler *ImportReconciler) *cdiv1.DataVolume {
+			dv := &cdiv1.DataVolume{}
+			Eventually(func() map[string]string {
+				Expect(reconciler.client.Get(context.TODO(), dvKey, dv)).To(Succeed())
+				return dv.Annotations
+			}, 5*time.Second, 10*time.Millisecond).Should(HaveKey(AnnCompletionHookFired))
+			return dv
+		}
+
+		BeforeEach(func() {
+			backoff = completionHookBackoff
+			completionHookBackoff = wait.Backoff{Duration: 10 * time.Millisecond, Steps: 10}
+			statusCodes = nil
+			requests = nil
+			notifications = nil
+			ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+				mutex.Lock()
+				defer mutex.Unlock()
+				notification := completionNotification{}
+				Expect(json.NewDecoder(r.Body).Decode(&notification)).To(Succeed())
+				requests = append(requests, r)
+				notifications = append(notifications, notification)
+				statusCode := http.StatusOK
+				if len(statusCodes) > 0 {
+					statusCode, statusCodes = statusCodes[0], statusCodes[1:]
+				}
+				w.WriteHeader(statusCode)
+			}))
+		})
+
+		AfterEach(func() {
+			ts.Close()
+			completionHookBackoff = backoff
+		})
+
+		It("should retry the notification until it is delivered, and only then write the completion annotation", func() {
+			statusCodes = []int{http.StatusServiceUnavailable}
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "hook-secret", Namespace: util.GetNamespace()},
+				Data:       map[string][]byte{"token": []byte("hook-token")},
+			}
+			reconciler := succeededDataVolume(map[string]string{
+				AnnCompletionHook:           "imported",
+				AnnCompletionHookAnnotation: "example.com/imported=true",
+			}, secret)
+			configureCompletionHooks(reconciler, cdiv1.CompletionHook{Name: "imported", URL: ts.URL + "/imported", TokenSecret: "hook-secret"})
+			events(reconciler)
+
+			By("Not waiting for the notification")
+			dv, err := reconcileDataVolume(reconciler)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dv.Annotations).ToNot(HaveKey("example.com/imported"))
+
+			By("Delivering the retried notification")
+			dv = waitForCompletionHook(reconciler)
+			Expect(dv.Annotations).To(HaveKeyWithValue("example.com/imported", "true"))
+			Expect(events(reconciler)).To(ContainElements(
+				ContainSubstring("Warning "+CompletionHookFailed),
+				ContainSubstring("Normal "+CompletionHookNotified)))
+			Expect(requests).To(HaveLen(2))
+			for _, r := range requests {
+				Expect(r.Method).To(Equal(http.MethodPost))
+				Expect(r.URL.Path).To(Equal("/imported"))
+				Expect(r.Header.Get("Authorization")).To(Equal("Bearer hook-token"))
+				Expect(r.Header.Get("Idempotency-Key")).To(Equal(string(dv.UID)))
+			}
+			Expect(notifications[1]).To(Equal(completionNotification{
+				Namespace:      dv.Namespace,
+				Name:           dv.Name,
+				UID:            dv.UID,
+				Phase:          cdiv1.Succeeded,
+				ClaimName:      dv.Name,
+				CompletionTime: dv.Annotations[AnnCompletionHookFired],
+			}))
+
+			By("Not notifying again")
+			_, err = reconcileDataVolume(reconciler)
+			Expect(err).ToNot(HaveOccurred())
+			Consistently(numRequests, 100*time.Millisecond, 10*time.Millisecond).Should(Equal(2))
+		})
+
+		It("should not retry a rejected notification", func() {
+			statusCodes = []int{http.StatusNotFound}
+			reconciler := succeededDataVolume(map[string]string{AnnCompletionHook: "imported"})
+			configureCompletionHooks(reconciler, cdiv1.CompletionHook{Name: "imported", URL: ts.URL})
+			events(reconciler)
+			_, err := reconcileDataVolume(reconciler)
+			Expect(err).ToNot(HaveOccurred())
+			waitForCompletionHook(reconciler)
+			Expect(events(reconciler)).To(ContainElement(ContainSubstring("Warning " + CompletionHookRejected)))
+
+			_, err = reconcileDataVolume(reconciler)
+			Expect(err).ToNot(HaveOccurred())
+			Consistently(numRequests, 100*time.Millisecond, 10*time.Millisecond).Should(Equal(1))
+		})
+
+		It("should reject a completion hook which is not in the CDIConfig", func() {
+			reconciler := succeededDataVolume(map[string]string{
+				AnnCompletionHook:           "unknown",
+				AnnCompletionHookAnnotation: "example.com/imported=true",
+			})
+			configureCompletionHooks(reconciler, cdiv1.CompletionHook{Name: "imported", URL: ts.URL})
+			events(reconciler)
+			dv, err := reconcileDataVolume(reconciler)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dv.Annotations).To(HaveKey(AnnCompletionHookFired))
+			Expect(dv.Annotations).To(HaveKeyWithValue("example.com/imported", "true"))
+			Expect(events(reconciler)).To(ContainElement(ContainSubstring("completion hook unknown is not configured in the CDIConfig")))
+			Expect(numRequests()).To(BeZero())
+		})
+
+		It("should only read the Secret of the completion hook in the CDI namespace", func() {
+			statusCodes = []int{http.StatusOK}
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "hook-secret", Namespace: metav1.NamespaceDefault},
+				Data:       map[string][]byte{"token": []byte("hook-token")},
+			}
+			reconciler := succeededDataVolume(map[string]string{AnnCompletionHook: "imported"}, secret)
+			configureCompletionHooks(reconciler, cdiv1.CompletionHook{Name: "imported", URL: ts.URL, TokenSecret: "hook-secret"})
+			events(reconciler)
+			_, err := reconcileDataVolume(reconciler)
+			Expect(err).ToNot(HaveOccurred())
+			Eventually(func() []string {
+				return events(reconciler)
+			}, 5*time.Second, 10*time.Millisecond).Should(ContainElement(ContainSubstring("could not get the completion hook Secret hook-secret")))
+			Expect(numRequests()).To(BeZero())
+
+			By("Delivering the notification once the Secret exists in the CDI namespace")
+			secret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "hook-secret", Namespace: util.GetNamespace()},
+				Data:       map[string][]byte{"token": []byte("cdi-token")},
+			}
+			Expect(reconciler.client.Create(context.TODO(), secret)).To(Succeed())
+			waitForCompletionHook(reconciler)
+			mutex.Lock()
+			defer mutex.Unlock()
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].Header.Get("Authorization")).To(Equal("Bearer cdi-token"))
+		})
+
+		It("should not garbage collect the DataVolume before its completion hook fired", func() {
+			dv := NewImportDataVolume("test-dv")
+			AddAnnotation(dv, AnnDeleteAfterCompletion, "true")
+			AddAnnotation(dv, AnnCompletionHook, "imported")
+			reconciler := createImportReconciler(dv)
+			allowed, err := reconciler.isGarbageCollectionAllowed(dv, reconciler.log)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowed).To(BeFalse())
+
+			AddAnnotation(dv, AnnCompletionHookFired, time.Now().UTC().Format(time.RFC3339))
+			allowed, err = reconciler.isGarbageCollectionAllowed(dv, reconciler.log)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowed).To(BeTrue())
+		})
+	})
+})
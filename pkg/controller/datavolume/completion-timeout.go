@@ -0,0 +1,132 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datavolume
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+)
+
+const (
+	// CompletionTimeout provides a const to indicate the DataVolume did not succeed within its completion timeout
+	CompletionTimeout = "CompletionTimeout"
+
+	// MessageCompletionTimeout provides a const to form the completion timeout message
+	MessageCompletionTimeout = "DataVolume did not complete within %s, last phase %s"
+)
+
+// reconcileCompletionTimeout fails the DataVolume that did not succeed within its completion timeout from its creation,
+// not counting the time it was paused, or requeues it to check again when the timeout expires. The failure is terminal:
+// the worker pods are stopped and not recreated, and the DataVolume stays failed even if its PVC completes later.
+func (r *ReconcilerBase) reconcileCompletionTimeout(dv, dataVolumeCopy *cdiv1.DataVolume, event *Event, result *reconcile.Result) error {
+	now := time.Now()
+	trackPausedTime(dataVolumeCopy, now)
+
+	if cond := FindConditionByType(cdiv1.DataVolumeRunning, dv.Status.Conditions); dv.Status.Phase == cdiv1.Failed && cond != nil && cond.Reason == CompletionTimeout {
+		// keep the failure of the timeout once it fired, with its message and without a new event
+		dataVolumeCopy.Status.Phase = cdiv1.Failed
+		dataVolumeCopy.Status.Conditions = updateCondition(dataVolumeCopy.Status.Conditions, cdiv1.DataVolumeRunning, corev1.ConditionFalse, cond.Message, CompletionTimeout)
+		dataVolumeCopy.Status.Conditions = UpdateReadyCondition(dataVolumeCopy.Status.Conditions, corev1.ConditionFalse, cond.Message, CompletionTimeout)
+		result.RequeueAfter = 0
+		return r.stopTimedOutTransfer(dv)
+	}
+
+	switch dataVolumeCopy.Status.Phase {
+	case cdiv1.Succeeded, cdiv1.SourceVerified, cdiv1.Failed, cdiv1.Paused:
+		// completed, failed for its own reason, or resumed by the next checkpoint reconciling it again
+		return nil
+	}
+	timeout := cc.GetCompletionTimeout(r.client, dv)
+	if timeout == 0 {
+		return nil
+	}
+	deadline := dv.CreationTimestamp.Add(timeout + time.Duration(dataVolumeCopy.Status.PausedSeconds)*time.Second)
+	if remaining := deadline.Sub(now); remaining > 0 {
+		if result.RequeueAfter == 0 || remaining < result.RequeueAfter {
+			result.RequeueAfter = remaining
+		}
+		return nil
+	}
+
+	message := completionTimeoutMessage(timeout, dataVolumeCopy)
+	dataVolumeCopy.Status.Phase = cdiv1.Failed
+	dataVolumeCopy.Status.Conditions = updateCondition(dataVolumeCopy.Status.Conditions, cdiv1.DataVolumeRunning, corev1.ConditionFalse, message, CompletionTimeout)
+	dataVolumeCopy.Status.Conditions = UpdateReadyCondition(dataVolumeCopy.Status.Conditions, corev1.ConditionFalse, message, CompletionTimeout)
+	event.eventType = corev1.EventTypeWarning
+	event.reason = CompletionTimeout
+	event.message = message
+	// no progress to poll anymore
+	result.RequeueAfter = 0
+	return r.stopTimedOutTransfer(dv)
+}
+
+// stopTimedOutTransfer marks the PVC of the timed out DataVolume so the worker pod controllers don't recreate its
+// pods, then deletes the importer pod and the scratch space. The upload and clone source pods are deleted by their
+// controllers once the PVC is marked.
+func (r *ReconcilerBase) stopTimedOutTransfer(dv *cdiv1.DataVolume) error {
+	pvc, err := r.getPVC(types.NamespacedName{Namespace: dv.Namespace, Name: dv.Name})
+	if err != nil || pvc == nil || pvc.DeletionTimestamp != nil {
+		return err
+	}
+	if !cc.IsCompletionTimedOut(pvc) {
+		cc.AddAnnotation(pvc, cc.AnnCompletionTimedOut, "true")
+		if err := r.updatePVC(pvc); err != nil {
+			return err
+		}
+	}
+	return r.deleteTransferResources(pvc)
+}
+
+// trackPausedTime records the time the DataVolume spends in the Paused phase between the stages of a multi-stage
+// import, so it does not count toward its completion timeout
+func trackPausedTime(dv *cdiv1.DataVolume, now time.Time) {
+	if dv.Status.Phase == cdiv1.Paused {
+		if dv.Status.PausedSince == nil {
+			pausedSince := metav1.NewTime(now)
+			dv.Status.PausedSince = &pausedSince
+		}
+		return
+	}
+	if dv.Status.PausedSince != nil {
+		dv.Status.PausedSeconds += int64(now.Sub(dv.Status.PausedSince.Time).Seconds())
+		dv.Status.PausedSince = nil
+	}
+}
+
+// completionTimeoutMessage summarizes the phase the DataVolume was stuck in, and its first unmet condition
+func completionTimeoutMessage(timeout time.Duration, dv *cdiv1.DataVolume) string {
+	phase := dv.Status.Phase
+	if phase == cdiv1.PhaseUnset {
+		phase = cdiv1.Pending
+	}
+	message := fmt.Sprintf(MessageCompletionTimeout, timeout, phase)
+	for _, conditionType := range []cdiv1.DataVolumeConditionType{cdiv1.DataVolumeBound, cdiv1.DataVolumeRunning} {
+		cond := FindConditionByType(conditionType, dv.Status.Conditions)
+		if cond != nil && cond.Status != corev1.ConditionTrue && cond.Reason != "" {
+			return fmt.Sprintf("%s, %s condition %s: %s", message, conditionType, cond.Reason, cond.Message)
+		}
+	}
+	return message
+}
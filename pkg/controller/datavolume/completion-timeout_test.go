@@ -0,0 +1,203 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datavolume
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	. "kubevirt.io/containerized-data-importer/pkg/controller/common"
+)
+
+var _ = Describe("DataVolume completion timeout", func() {
+	dvKey := types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}
+
+	// reconcileCreatedAgo creates the PVC of a DataVolume created age ago, and updates the status of the DataVolume
+	// once the annotations are added to it and its PVC is updated
+	reconcileCreatedAgo := func(age time.Duration, annotations map[string]string, updatePVC func(*corev1.PersistentVolumeClaim)) (*ImportReconciler, reconcile.Result) {
+		dv := NewImportDataVolume("test-dv")
+		dv.CreationTimestamp = metav1.NewTime(time.Now().Add(-age))
+		reconciler := createImportReconciler(dv)
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: dvKey})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(reconciler.client.Get(context.TODO(), dvKey, dv)).To(Succeed())
+		for k, v := range annotations {
+			AddAnnotation(dv, k, v)
+		}
+		Expect(reconciler.client.Update(context.TODO(), dv)).To(Succeed())
+		pvc := &corev1.PersistentVolumeClaim{}
+		Expect(reconciler.client.Get(context.TODO(), dvKey, pvc)).To(Succeed())
+		updatePVC(pvc)
+		Expect(reconciler.client.Update(context.TODO(), pvc)).To(Succeed())
+		result, err := reconciler.updateStatus(getReconcileRequest(dv), nil, reconciler)
+		Expect(err).ToNot(HaveOccurred())
+		return reconciler, result
+	}
+
+	getDataVolume := func(reconciler *ImportReconciler) *cdiv1.DataVolume {
+		dv := &cdiv1.DataVolume{}
+		Expect(reconciler.client.Get(context.TODO(), dvKey, dv)).To(Succeed())
+		return dv
+	}
+
+	pvcPending := func(pvc *corev1.PersistentVolumeClaim) {
+		pvc.Status.Phase = corev1.ClaimPending
+	}
+
+	It("should fail a DataVolume stuck past its completion timeout", func() {
+		reconciler, result := reconcileCreatedAgo(2*time.Hour, map[string]string{AnnCompletionTimeout: "1h"}, pvcPending)
+		Expect(result.RequeueAfter).To(BeZero())
+
+		dv := getDataVolume(reconciler)
+		Expect(dv.Status.Phase).To(Equal(cdiv1.Failed))
+		running := FindConditionByType(cdiv1.DataVolumeRunning, dv.Status.Conditions)
+		Expect(running.Status).To(Equal(corev1.ConditionFalse))
+		Expect(running.Reason).To(Equal(CompletionTimeout))
+		Expect(running.Message).To(Equal("DataVolume did not complete within 1h0m0s, last phase Pending, Bound condition Pending: PVC test-dv Pending"))
+		ready := FindConditionByType(cdiv1.DataVolumeReady, dv.Status.Conditions)
+		Expect(ready.Status).To(Equal(corev1.ConditionFalse))
+		Expect(ready.Reason).To(Equal(CompletionTimeout))
+
+		found := false
+		for len(reconciler.recorder.(*record.FakeRecorder).Events) > 0 {
+			event := <-reconciler.recorder.(*record.FakeRecorder).Events
+			if strings.Contains(event, "Warning CompletionTimeout DataVolume did not complete within 1h0m0s") {
+				found = true
+			}
+		}
+		Expect(found).To(BeTrue())
+
+		By("Keeping the DataVolume failed with the same message")
+		_, err := reconciler.updateStatus(getReconcileRequest(dv), nil, reconciler)
+		Expect(err).ToNot(HaveOccurred())
+		dv = getDataVolume(reconciler)
+		Expect(dv.Status.Phase).To(Equal(cdiv1.Failed))
+		Expect(FindConditionByType(cdiv1.DataVolumeRunning, dv.Status.Conditions).Message).To(Equal(running.Message))
+	})
+
+	It("should stop the worker pod of a timed out DataVolume and keep it failed", func() {
+		importPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "importer-test-dv", Namespace: metav1.NamespaceDefault}}
+		reconciler, _ := reconcileCreatedAgo(2*time.Hour, map[string]string{AnnCompletionTimeout: "1h"}, func(pvc *corev1.PersistentVolumeClaim) {
+			pvc.Status.Phase = corev1.ClaimBound
+			pvc.Annotations[AnnPodPhase] = string(corev1.PodRunning)
+			pvc.Annotations[AnnImportPod] = importPod.Name
+		})
+		Expect(reconciler.client.Create(context.TODO(), importPod)).To(Succeed())
+		result, err := reconciler.updateStatus(getReconcileRequest(getDataVolume(reconciler)), nil, reconciler)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(getDataVolume(reconciler).Status.Phase).To(Equal(cdiv1.Failed))
+
+		pvc := &corev1.PersistentVolumeClaim{}
+		Expect(reconciler.client.Get(context.TODO(), dvKey, pvc)).To(Succeed())
+		Expect(IsCompletionTimedOut(pvc)).To(BeTrue())
+		err = reconciler.client.Get(context.TODO(), client.ObjectKeyFromObject(importPod), &corev1.Pod{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+
+		By("Keeping the DataVolume failed once its PVC completes")
+		pvc.Annotations[AnnPodPhase] = string(corev1.PodSucceeded)
+		Expect(reconciler.client.Update(context.TODO(), pvc)).To(Succeed())
+		_, err = reconciler.updateStatus(getReconcileRequest(getDataVolume(reconciler)), nil, reconciler)
+		Expect(err).ToNot(HaveOccurred())
+		dv := getDataVolume(reconciler)
+		Expect(dv.Status.Phase).To(Equal(cdiv1.Failed))
+		Expect(FindConditionByType(cdiv1.DataVolumeReady, dv.Status.Conditions).Reason).To(Equal(CompletionTimeout))
+	})
+
+	It("should not fail a DataVolume before its completion timeout", func() {
+		reconciler, result := reconcileCreatedAgo(10*time.Minute, map[string]string{AnnCompletionTimeout: "1h"}, pvcPending)
+		Expect(result.RequeueAfter).ToNot(BeZero())
+		Expect(result.RequeueAfter).To(BeNumerically("<=", 50*time.Minute))
+		Expect(getDataVolume(reconciler).Status.Phase).To(Equal(cdiv1.Pending))
+	})
+
+	It("should not fail a DataVolume that succeeded within its completion timeout", func() {
+		reconciler, result := reconcileCreatedAgo(2*time.Hour, map[string]string{AnnCompletionTimeout: "1h"}, func(pvc *corev1.PersistentVolumeClaim) {
+			pvc.Status.Phase = corev1.ClaimBound
+			pvc.Annotations[AnnPodPhase] = string(corev1.PodSucceeded)
+		})
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(getDataVolume(reconciler).Status.Phase).To(Equal(cdiv1.Succeeded))
+	})
+
+	It("should ignore an invalid completion timeout annotation set before the webhook rejected it", func() {
+		reconciler, _ := reconcileCreatedAgo(2*time.Hour, map[string]string{AnnCompletionTimeout: "forever"}, pvcPending)
+		Expect(getDataVolume(reconciler).Status.Phase).To(Equal(cdiv1.Pending))
+	})
+
+	It("should fall back to the completion timeout of the CDIConfig", func() {
+		dv := NewImportDataVolume("test-dv")
+		dv.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+		reconciler := createImportReconciler(dv)
+		cdiConfig := &cdiv1.CDIConfig{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+		cdiConfig.Spec.DataVolumeCompletionTimeout = &metav1.Duration{Duration: time.Hour}
+		Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+		Expect(GetCompletionTimeout(reconciler.client, dv)).To(Equal(time.Hour))
+
+		AddAnnotation(dv, AnnCompletionTimeout, "3h")
+		Expect(GetCompletionTimeout(reconciler.client, dv)).To(Equal(3 * time.Hour))
+	})
+
+	It("should not count the time the DataVolume was paused", func() {
+		dv := NewImportDataVolume("test-dv")
+		dv.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+		AddAnnotation(dv, AnnCompletionTimeout, "1h")
+		dv.Status.Phase = cdiv1.ImportInProgress
+		dv.Status.PausedSeconds = int64((80 * time.Minute).Seconds())
+		reconciler := createImportReconcilerWithoutConfig()
+		dvCopy := dv.DeepCopy()
+		result := reconcile.Result{}
+		Expect(reconciler.reconcileCompletionTimeout(dv, dvCopy, &Event{}, &result)).To(Succeed())
+		Expect(dvCopy.Status.Phase).To(Equal(cdiv1.ImportInProgress))
+		Expect(result.RequeueAfter).To(BeNumerically("~", 20*time.Minute, time.Minute))
+	})
+
+	It("should track the time the DataVolume is paused", func() {
+		now := time.Now()
+		dv := NewImportDataVolume("test-dv")
+		dv.Status.Phase = cdiv1.Paused
+		trackPausedTime(dv, now.Add(-30*time.Minute))
+		Expect(dv.Status.PausedSince.Time).To(BeTemporally("~", now.Add(-30*time.Minute), time.Second))
+
+		By("Keeping the start of the pause while paused")
+		trackPausedTime(dv, now.Add(-10*time.Minute))
+		Expect(dv.Status.PausedSince.Time).To(BeTemporally("~", now.Add(-30*time.Minute), time.Second))
+
+		By("Adding the paused time when the DataVolume resumes")
+		dv.Status.PausedSeconds = 60
+		dv.Status.Phase = cdiv1.ImportScheduled
+		trackPausedTime(dv, now)
+		Expect(dv.Status.PausedSince).To(BeNil())
+		Expect(dv.Status.PausedSeconds).To(Equal(int64(60 + 30*60)))
+	})
+})
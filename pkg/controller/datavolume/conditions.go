@@ -31,6 +31,14 @@ const (
 	transferRunning = "TransferRunning"
 	pvcBound        = "Bound"
 	pvcPending      = "Pending"
+
+	populatedVerified          = "Verified"
+	populationPending          = "PopulationPending"
+	populatedVerificationError = "VerificationFailed"
+
+	conversionInProgress = "ConversionInProgress"
+	conversionComplete   = "ConversionComplete"
+	conversionFailed     = "ConversionFailed"
 )
 
 // FindConditionByType finds condition by type
@@ -158,6 +166,24 @@ func updateBoundCondition(conditions []cdiv1.DataVolumeCondition, pvc *corev1.Pe
 	return conditions
 }
 
+func updateVerifiedCondition(conditions []cdiv1.DataVolumeCondition, dv *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim) []cdiv1.DataVolumeCondition {
+	switch {
+	case pvcIsVerified(pvc, dv):
+		conditions = updateCondition(conditions, cdiv1.DataVolumeVerified, corev1.ConditionTrue, fmt.Sprintf("PVC %s populated and verified", pvc.Name), populatedVerified)
+	case pvc == nil || dv.Status.Phase != cdiv1.Succeeded:
+		conditions = updateCondition(conditions, cdiv1.DataVolumeVerified, corev1.ConditionFalse, "Waiting for the population to succeed", populationPending)
+	default:
+		message := "Waiting for the PVC to be marked as verified"
+		reason := populationPending
+		if err := verifyPopulatedPvc(pvc, dv); err != nil {
+			message = err.Error()
+			reason = populatedVerificationError
+		}
+		conditions = updateCondition(conditions, cdiv1.DataVolumeVerified, corev1.ConditionFalse, message, reason)
+	}
+	return conditions
+}
+
 func getPVCCondition(anno map[string]string) *cdiv1.DataVolumeCondition {
 	if val, ok := anno[cc.AnnBoundCondition]; ok {
 		status := corev1.ConditionUnknown
@@ -174,3 +200,26 @@ func getPVCCondition(anno map[string]string) *cdiv1.DataVolumeCondition {
 	}
 	return nil
 }
+
+// updateConvertingCondition reports the qemu-img conversion progress, a negative progress means qemu-img is converting
+// without reporting progress
+func updateConvertingCondition(conditions []cdiv1.DataVolumeCondition, progress float64) []cdiv1.DataVolumeCondition {
+	message := "Converting the image, qemu-img did not report progress yet"
+	if progress >= 0 {
+		message = fmt.Sprintf("Converting the image: %.2f%%", progress)
+	}
+	return updateCondition(conditions, cdiv1.DataVolumeConverting, corev1.ConditionTrue, message, conversionInProgress)
+}
+
+// updateConversionDoneCondition closes the converting condition once the DataVolume is done, it is only ever set while
+// a conversion was seen
+func updateConversionDoneCondition(conditions []cdiv1.DataVolumeCondition, phase cdiv1.DataVolumePhase) []cdiv1.DataVolumeCondition {
+	condition := FindConditionByType(cdiv1.DataVolumeConverting, conditions)
+	if condition == nil || condition.Status != corev1.ConditionTrue {
+		return conditions
+	}
+	if phase == cdiv1.Succeeded {
+		return updateCondition(conditions, cdiv1.DataVolumeConverting, corev1.ConditionFalse, "Conversion complete", conversionComplete)
+	}
+	return updateCondition(conditions, cdiv1.DataVolumeConverting, corev1.ConditionFalse, "Conversion did not complete", conversionFailed)
+}
@@ -56,6 +56,7 @@ import (
 	featuregates "kubevirt.io/containerized-data-importer/pkg/feature-gates"
 	"kubevirt.io/containerized-data-importer/pkg/token"
 	"kubevirt.io/containerized-data-importer/pkg/util"
+	"kubevirt.io/containerized-data-importer/pkg/util/logging"
 )
 
 const (
@@ -65,6 +66,8 @@ const (
 	ErrResourceMarkedForDeletion = "ErrResourceMarkedForDeletion"
 	// ErrClaimLost provides a const to indicate a claim is lost
 	ErrClaimLost = "ErrClaimLost"
+	// ExpandingPVC provides a const to indicate the PVC is expanded to the requested size of the DataVolume
+	ExpandingPVC = "ExpandingPVC"
 
 	// MessageResourceMarkedForDeletion provides a const to form a resource marked for deletion error message
 	MessageResourceMarkedForDeletion = "Resource %q marked for deletion"
@@ -72,6 +75,14 @@ const (
 	MessageResourceExists = "Resource %q already exists and is not managed by DataVolume"
 	// MessageErrClaimLost provides a const to form claim lost message
 	MessageErrClaimLost = "PVC %s lost"
+	// MessageExpandingPVC provides a const to form the PVC expansion message
+	MessageExpandingPVC = "Expanding PVC %s from %s to %s"
+	// MessageIncompleteStorageProfile provides a const to form the incomplete StorageProfile message
+	MessageIncompleteStorageProfile = "StorageProfile %s has no access modes to complete the DataVolume storage spec, set the claimPropertySets of StorageProfile %s or the accessModes of the DataVolume storage spec"
+	// MessageNoProvisionerStorageProfile provides a const to form the message of a storage class without provisioner nor available PV
+	MessageNoProvisionerStorageProfile = "StorageClass %s has no provisioner and no available PersistentVolume, create a PersistentVolume of StorageClass %s for the DataVolume"
+	// MessageWorkerPodQueued provides a const to form the worker pod queued message
+	MessageWorkerPodQueued = "Worker pod of PVC %s queued, waiting for a slot under the maximum number of parallel worker pods"
 
 	dvPhaseField = "status.phase"
 
@@ -109,11 +120,13 @@ type dvSyncState struct {
 // ReconcilerBase members
 type ReconcilerBase struct {
 	client          client.Client
+	uncachedClient  client.Reader
 	recorder        record.EventRecorder
 	scheme          *runtime.Scheme
 	log             logr.Logger
 	featureGates    featuregates.FeatureGates
 	installerLabels map[string]string
+	controllerName  string
 }
 
 func pvcIsPopulated(pvc *corev1.PersistentVolumeClaim, dv *cdiv1.DataVolume) bool {
@@ -129,6 +142,69 @@ func dvIsPrePopulated(dv *cdiv1.DataVolume) bool {
 	return ok
 }
 
+func dvRequestsPopulatedVerification(dv *cdiv1.DataVolume) bool {
+	_, ok := dv.Annotations[cc.AnnVerifyPopulated]
+	return ok
+}
+
+func pvcIsVerified(pvc *corev1.PersistentVolumeClaim, dv *cdiv1.DataVolume) bool {
+	if pvc == nil || dv == nil {
+		return false
+	}
+	dvName, ok := pvc.Annotations[cc.AnnPopulatedVerified]
+	return ok && dvName == dv.Name
+}
+
+// verifyPopulatedPvc checks the PVC of a succeeded DataVolume can be consumed, and holds the image expected by the
+// requested verification
+func verifyPopulatedPvc(pvc *corev1.PersistentVolumeClaim, dv *cdiv1.DataVolume) error {
+	if pvc.Status.Phase != corev1.ClaimBound {
+		return errors.Errorf("PVC %s is not bound", pvc.Name)
+	}
+	switch dv.Annotations[cc.AnnVerifyPopulated] {
+	case cc.VerifyPopulatedSize:
+		return verifyPopulatedSize(pvc)
+	case cc.VerifyPopulatedDigest:
+		return verifyPopulatedDigest(pvc, dv)
+	}
+	return nil
+}
+
+// verifyPopulatedSize checks the PVC records the virtual size of the image it was populated with, and the image fits
+// in the PVC
+func verifyPopulatedSize(pvc *corev1.PersistentVolumeClaim) error {
+	value, ok := pvc.Annotations[cc.AnnImageVirtualSize]
+	if !ok {
+		return errors.Errorf("PVC %s does not record the virtual size of its image", pvc.Name)
+	}
+	virtualSize, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || virtualSize <= 0 {
+		return errors.Errorf("PVC %s records an invalid image virtual size %q", pvc.Name, value)
+	}
+	capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]
+	if !ok {
+		return errors.Errorf("PVC %s has no capacity", pvc.Name)
+	}
+	if capacity.Value() < virtualSize {
+		return errors.Errorf("PVC %s image virtual size %d exceeds its capacity %s", pvc.Name, virtualSize, capacity.String())
+	}
+	return nil
+}
+
+// verifyPopulatedDigest checks the PVC records the digest of the source it was populated from, matching the digest
+// expected by the DataVolume
+func verifyPopulatedDigest(pvc *corev1.PersistentVolumeClaim, dv *cdiv1.DataVolume) error {
+	digest, ok := pvc.Annotations[cc.AnnSourceDigest]
+	if !ok {
+		return errors.Errorf("PVC %s does not record the digest of its source", pvc.Name)
+	}
+	expected := dv.Annotations[cc.AnnVerifyPopulatedDigest]
+	if !strings.EqualFold(digest, expected) {
+		return errors.Errorf("PVC %s source digest %s does not match the expected digest %s", pvc.Name, digest, expected)
+	}
+	return nil
+}
+
 func checkStaticProvisionPending(pvc *corev1.PersistentVolumeClaim, dv *cdiv1.DataVolume) bool {
 	if pvc == nil || dv == nil {
 		return false
@@ -161,6 +237,7 @@ const (
 	dataVolumePvcClone
 	dataVolumeSnapshotClone
 	dataVolumePopulator
+	dataVolumeRemotePvcClone
 )
 
 type indexArgs struct {
@@ -299,9 +376,38 @@ func addDataVolumeControllerCommonWatches(mgr manager.Manager, dataVolumeControl
 		return err
 	}
 
+	// Watch for StorageProfile updates, and PV updates for the storage classes without provisioner, and reconcile the
+	// DVs waiting for their StorageProfile to be completed
+	for _, k := range []client.Object{&cdiv1.StorageProfile{}, &corev1.PersistentVolume{}} {
+		if err := dataVolumeController.Watch(&source.Kind{Type: k}, handler.EnqueueRequestsFromMapFunc(
+			func(obj client.Object) (reqs []reconcile.Request) {
+				dvList := &cdiv1.DataVolumeList{}
+				if err := mgr.GetClient().List(context.TODO(), dvList, client.MatchingFields{dvPhaseField: string(cdiv1.Pending)}); err != nil {
+					return
+				}
+				for _, dv := range dvList.Items {
+					if isWaitingForStorageProfile(&dv) && getDataVolumeOp(mgr.GetLogger(), &dv, mgr.GetClient()) == op {
+						reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: dv.Name, Namespace: dv.Namespace}})
+					}
+				}
+				return
+			},
+		),
+		); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// isWaitingForStorageProfile returns true if the PVC of the DataVolume can't be created until the StorageProfile of its
+// storage class is completed
+func isWaitingForStorageProfile(dv *cdiv1.DataVolume) bool {
+	boundCondition := FindConditionByType(cdiv1.DataVolumeBound, dv.Status.Conditions)
+	return boundCondition != nil && boundCondition.Reason == cc.IncompleteStorageProfile
+}
+
 func getDataVolumeOp(log logr.Logger, dv *cdiv1.DataVolume, client client.Client) dataVolumeOp {
 	src := dv.Spec.Source
 
@@ -323,7 +429,10 @@ func getDataVolumeOp(log logr.Logger, dv *cdiv1.DataVolume, client client.Client
 	if src.Upload != nil {
 		return dataVolumeUpload
 	}
-	if src.HTTP != nil || src.S3 != nil || src.GCS != nil || src.Registry != nil || src.Blank != nil || src.Imageio != nil || src.VDDK != nil {
+	if src.RemotePVC != nil {
+		return dataVolumeRemotePvcClone
+	}
+	if cc.GetDataVolumeImportSource(src) != "" {
 		return dataVolumeImport
 	}
 
@@ -359,6 +468,7 @@ type dvController interface {
 }
 
 func (r *ReconcilerBase) reconcile(ctx context.Context, req reconcile.Request, dvc dvController) (reconcile.Result, error) {
+	start := time.Now()
 	log := r.log.WithValues("DataVolume", req.NamespacedName)
 	syncRes, syncErr := dvc.sync(log, req)
 	res, err := r.updateStatus(req, syncRes.phaseSync, dvc)
@@ -368,6 +478,7 @@ func (r *ReconcilerBase) reconcile(ctx context.Context, req reconcile.Request, d
 	if syncRes.result != nil {
 		res = *syncRes.result
 	}
+	observeReconcileDuration(r.controllerName, start, res, err)
 	return res, err
 }
 
@@ -406,6 +517,10 @@ func (r *ReconcilerBase) syncDvPvcState(log logr.Logger, req reconcile.Request,
 		return syncState, nil
 	}
 
+	if err := r.handleCancel(&syncState, log, cleanup); err != nil || syncState.result != nil {
+		return syncState, err
+	}
+
 	if prepare != nil {
 		if err := prepare(&syncState); err != nil {
 			return syncState, err
@@ -413,7 +528,17 @@ func (r *ReconcilerBase) syncDvPvcState(log logr.Logger, req reconcile.Request,
 	}
 
 	syncState.pvcSpec, err = renderPvcSpec(r.client, r.recorder, log, dv)
+	if err == nil && syncState.pvc == nil && (dv.Status.Phase == "" || dv.Status.Phase == cdiv1.Pending) {
+		err = checkNoProvisionerStorageClass(r.client, syncState.pvcSpec)
+	}
 	if err != nil {
+		var profileErr *incompleteStorageProfileError
+		if syncState.pvc == nil && errors.As(err, &profileErr) {
+			// Retrying won't help, the StorageProfile watch reconciles the DataVolume once the profile is fixed
+			syncState.result = &reconcile.Result{}
+			return syncState, r.syncDataVolumeStatusPhaseWithEvent(&syncState, cdiv1.Pending, nil,
+				Event{reason: cc.IncompleteStorageProfile, message: profileErr.Error()})
+		}
 		return syncState, err
 	}
 
@@ -421,6 +546,11 @@ func (r *ReconcilerBase) syncDvPvcState(log logr.Logger, req reconcile.Request,
 		return syncState, err
 	}
 
+	// The hook fires before the DataVolume may be garbage collected
+	if err := r.fireCompletionHook(&syncState); err != nil {
+		return syncState, err
+	}
+
 	if syncState.pvc != nil {
 		if err := r.garbageCollect(&syncState, log); err != nil {
 			return syncState, err
@@ -428,7 +558,10 @@ func (r *ReconcilerBase) syncDvPvcState(log logr.Logger, req reconcile.Request,
 		if syncState.result != nil || syncState.dv == nil {
 			return syncState, nil
 		}
-		if err := r.validatePVC(dv, syncState.pvc); err != nil {
+		if err := r.validatePVC(dv, syncState.pvc, syncState.pvcSpec); err != nil {
+			return syncState, err
+		}
+		if err := r.expandPvcToRequestedSize(&syncState); err != nil {
 			return syncState, err
 		}
 		r.handlePrePopulation(syncState.dvMutated, syncState.pvc)
@@ -558,7 +691,7 @@ func (r *ReconcilerBase) handlePrePopulation(dv *cdiv1.DataVolume, pvc *corev1.P
 	}
 }
 
-func (r *ReconcilerBase) validatePVC(dv *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim) error {
+func (r *ReconcilerBase) validatePVC(dv *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim, pvcSpec *corev1.PersistentVolumeClaimSpec) error {
 	// If the PVC is being deleted, we should log a warning to the event recorder and return to wait the deletion complete
 	// don't bother with owner refs is the pvc is deleted
 	if pvc.DeletionTimestamp != nil {
@@ -573,6 +706,9 @@ func (r *ReconcilerBase) validatePVC(dv *cdiv1.DataVolume, pvc *corev1.Persisten
 			if err := r.addOwnerRef(pvc, dv); err != nil {
 				return err
 			}
+		} else if dvRequestsPvcAdoption(dv) {
+			// The PVC is adopted when the DataVolume would have created it
+			return r.validatePvcAdoption(dv, pvc, pvcSpec)
 		} else {
 			msg := fmt.Sprintf(MessageResourceExists, pvc.Name)
 			r.recorder.Event(dv, corev1.EventTypeWarning, ErrResourceExists, msg)
@@ -582,6 +718,34 @@ func (r *ReconcilerBase) validatePVC(dv *cdiv1.DataVolume, pvc *corev1.Persisten
 	return nil
 }
 
+// expandPvcToRequestedSize grows the bound PVC when the requested size of the DataVolume was increased after the PVC
+// was created. The spec of a DataVolume is only updated since its creation, bumping its generation, for the growth
+// admitted when the storage class allows volume expansion, or the checkpoints of a multi-stage import.
+func (r *ReconcilerBase) expandPvcToRequestedSize(syncState *dvSyncState) error {
+	dv := syncState.dv
+	pvc := syncState.pvc
+	if dv.Generation <= 1 || pvc.Status.Phase != corev1.ClaimBound || !metav1.IsControlledBy(pvc, dv) {
+		return nil
+	}
+	requested, ok := syncState.pvcSpec.Resources.Requests[corev1.ResourceStorage]
+	if !ok {
+		return nil
+	}
+	current := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	if requested.Cmp(current) <= 0 {
+		return nil
+	}
+	pvcCopy := pvc.DeepCopy()
+	pvcCopy.Spec.Resources.Requests[corev1.ResourceStorage] = requested
+	if err := r.updatePVC(pvcCopy); err != nil {
+		return err
+	}
+	r.log.V(1).Info("Expanding PVC to the requested size of the DataVolume", "pvc", pvc.Name, "size", requested.String())
+	r.recorder.Eventf(dv, corev1.EventTypeNormal, ExpandingPVC, MessageExpandingPVC, pvc.Name, current.String(), requested.String())
+	syncState.pvc = pvcCopy
+	return nil
+}
+
 func (r *ReconcilerBase) getPVC(key types.NamespacedName) (*corev1.PersistentVolumeClaim, error) {
 	pvc := &corev1.PersistentVolumeClaim{}
 	if err := r.client.Get(context.TODO(), key, pvc); err != nil {
@@ -658,15 +822,47 @@ func (r *ReconcilerBase) reconcileProgressUpdate(datavolume *cdiv1.DataVolume, p
 			// Avoid long timeouts and error traces from HTTP get when pod is already gone
 			return nil
 		}
+		previousProgress := datavolume.Status.Progress
 		if err := updateProgressUsingPod(datavolume, pod); err != nil {
 			return err
 		}
+		if err := r.resetImportAttemptsOnProgress(pvc, pod, previousProgress, datavolume.Status.Progress); err != nil {
+			return err
+		}
 	}
 	// We are not done yet, force a re-reconcile in 2 seconds to get an update.
 	result.RequeueAfter = 2 * time.Second
 	return nil
 }
 
+// resetImportAttemptsOnProgress forgives the failed attempts of the importer pod once the import makes progress, so only
+// the attempts that fail without progress count toward the maximum number of attempts
+func (r *ReconcilerBase) resetImportAttemptsOnProgress(pvc *corev1.PersistentVolumeClaim, pod *corev1.Pod, previous, current cdiv1.DataVolumeProgress) error {
+	if _, ok := pvc.Annotations[cc.AnnImportPod]; !ok || len(pod.Status.ContainerStatuses) == 0 {
+		return nil
+	}
+	if progressPercent(current) <= progressPercent(previous) {
+		return nil
+	}
+	restarts := int(pod.Status.ContainerStatuses[0].RestartCount)
+	base, _ := strconv.Atoi(pvc.Annotations[cc.AnnImportAttemptsBase])
+	if restarts <= base {
+		return nil
+	}
+	pvcCpy := pvc.DeepCopy()
+	cc.AddAnnotation(pvcCpy, cc.AnnImportAttemptsBase, strconv.Itoa(restarts))
+	return r.updatePVC(pvcCpy)
+}
+
+// progressPercent returns the percentage of a DataVolume progress, or 0 if it is not available
+func progressPercent(progress cdiv1.DataVolumeProgress) float64 {
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(string(progress), "%"), 64)
+	if err != nil {
+		return 0
+	}
+	return percent
+}
+
 func (r *ReconcilerBase) syncDataVolumeStatusPhaseWithEvent(syncState *dvSyncState, phase cdiv1.DataVolumePhase, pvc *corev1.PersistentVolumeClaim, event Event) error {
 	if syncState.phaseSync != nil {
 		return fmt.Errorf("phaseSync is already set")
@@ -679,7 +875,7 @@ func (r *ReconcilerBase) syncDataVolumeStatusPhaseWithEvent(syncState *dvSyncSta
 	return nil
 }
 
-func (r *ReconcilerBase) updateDataVolumeStatusPhaseSync(ps *statusPhaseSync, dv *cdiv1.DataVolume, dvCopy *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim) error {
+func (r *ReconcilerBase) updateDataVolumeStatusPhaseSync(ps *statusPhaseSync, dv *cdiv1.DataVolume, dvCopy *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim, result *reconcile.Result) error {
 	var condPvc *corev1.PersistentVolumeClaim
 	var err error
 	if ps.pvcKey != nil {
@@ -692,7 +888,7 @@ func (r *ReconcilerBase) updateDataVolumeStatusPhaseSync(ps *statusPhaseSync, dv
 			condPvc = pvc
 		}
 	}
-	return r.updateDataVolumeStatusPhaseWithEvent(ps.phase, dv, dvCopy, condPvc, ps.event)
+	return r.updateDataVolumeStatusPhaseWithEvent(ps.phase, dv, dvCopy, condPvc, ps.event, result)
 }
 
 func (r *ReconcilerBase) updateDataVolumeStatusPhaseWithEvent(
@@ -700,7 +896,8 @@ func (r *ReconcilerBase) updateDataVolumeStatusPhaseWithEvent(
 	dataVolume *cdiv1.DataVolume,
 	dataVolumeCopy *cdiv1.DataVolume,
 	pvc *corev1.PersistentVolumeClaim,
-	event Event) error {
+	event Event,
+	result *reconcile.Result) error {
 
 	if dataVolume == nil {
 		return nil
@@ -713,7 +910,10 @@ func (r *ReconcilerBase) updateDataVolumeStatusPhaseWithEvent(
 	if pvc == nil {
 		reason = event.reason
 	}
-	r.updateConditions(dataVolumeCopy, pvc, reason)
+	r.updateConditions(dataVolumeCopy, pvc, reason, event.message)
+	if err := r.reconcileCompletionTimeout(dataVolume, dataVolumeCopy, &event, result); err != nil {
+		return err
+	}
 	return r.emitEvent(dataVolume, dataVolumeCopy, curPhase, dataVolume.Status.Conditions, &event)
 }
 
@@ -732,8 +932,8 @@ func (r ReconcilerBase) updateStatus(req reconcile.Request, phaseSync *statusPha
 	}
 
 	if phaseSync != nil {
-		err = r.updateDataVolumeStatusPhaseSync(phaseSync, dv, dataVolumeCopy, pvc)
-		return reconcile.Result{}, err
+		err = r.updateDataVolumeStatusPhaseSync(phaseSync, dv, dataVolumeCopy, pvc, &result)
+		return result, err
 	}
 
 	curPhase := dataVolumeCopy.Status.Phase
@@ -790,21 +990,87 @@ func (r ReconcilerBase) updateStatus(req reconcile.Request, phaseSync *statusPha
 				}
 			}
 		}
+		if cc.IsWorkerPodQueued(pvc) {
+			// The worker pod waits for a slot under the MaxParallelWorkerPods limit
+			dataVolumeCopy.Status.Phase = cdiv1.Pending
+			event.eventType = corev1.EventTypeNormal
+			event.reason = cc.WorkerPodQueued
+			event.message = fmt.Sprintf(MessageWorkerPodQueued, pvc.Name)
+		}
 		if i, err := strconv.Atoi(pvc.Annotations[cc.AnnPodRestarts]); err == nil && i >= 0 {
 			dataVolumeCopy.Status.RestartCount = int32(i)
 		}
+		if timings := getImportTimings(pvc); timings != nil {
+			dataVolumeCopy.Status.ImportTimings = timings
+		}
+		dataVolumeCopy.Status.ImportPassthrough = pvc.Annotations[cc.AnnImportPassthrough] == "true"
+		dataVolumeCopy.Status.ImportSourceDigest = pvc.Annotations[cc.AnnSourceDigest]
 		if err := r.reconcileProgressUpdate(dataVolumeCopy, pvc, &result); err != nil {
 			return result, err
 		}
 	}
 
+	if err := r.updateRegistryDisksStatus(dataVolumeCopy, &event); err != nil {
+		return result, err
+	}
+
+	if pvc, err = r.reconcilePopulatedVerification(dataVolumeCopy, pvc); err != nil {
+		return result, err
+	}
+
 	currentCond := make([]cdiv1.DataVolumeCondition, len(dataVolumeCopy.Status.Conditions))
 	copy(currentCond, dataVolumeCopy.Status.Conditions)
-	r.updateConditions(dataVolumeCopy, pvc, "")
+	r.updateConditions(dataVolumeCopy, pvc, "", "")
+	if err := r.reconcileCompletionTimeout(dv, dataVolumeCopy, &event, &result); err != nil {
+		return result, err
+	}
 	return result, r.emitEvent(dv, dataVolumeCopy, curPhase, currentCond, &event)
 }
 
-func (r *ReconcilerBase) updateConditions(dataVolume *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim, reason string) {
+// getImportTimings returns the time the importer spent in the phases of the import, as recorded in the PVC
+// annotations, nil if the import did not complete yet
+func getImportTimings(pvc *corev1.PersistentVolumeClaim) *cdiv1.DataVolumeImportTimings {
+	timings := &cdiv1.DataVolumeImportTimings{}
+	found := false
+	for ann, seconds := range map[string]*int64{
+		cc.AnnImportDownloadSeconds: &timings.DownloadSeconds,
+		cc.AnnImportConvertSeconds:  &timings.ConvertSeconds,
+		cc.AnnImportResizeSeconds:   &timings.ResizeSeconds,
+	} {
+		if i, err := strconv.ParseInt(pvc.Annotations[ann], 10, 64); err == nil && i >= 0 {
+			*seconds = i
+			found = true
+		}
+	}
+	if !found {
+		return nil
+	}
+	return timings
+}
+
+// reconcilePopulatedVerification marks the PVC as verified once the DataVolume succeeded and the PVC passed
+// verification, so consumers don't start before the population is complete
+func (r *ReconcilerBase) reconcilePopulatedVerification(dataVolume *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim) (*corev1.PersistentVolumeClaim, error) {
+	if pvc == nil || !dvRequestsPopulatedVerification(dataVolume) {
+		return pvc, nil
+	}
+	verified := dataVolume.Status.Phase == cdiv1.Succeeded && verifyPopulatedPvc(pvc, dataVolume) == nil
+	if verified == pvcIsVerified(pvc, dataVolume) {
+		return pvc, nil
+	}
+	pvcCpy := pvc.DeepCopy()
+	if verified {
+		cc.AddAnnotation(pvcCpy, cc.AnnPopulatedVerified, dataVolume.Name)
+	} else {
+		delete(pvcCpy.Annotations, cc.AnnPopulatedVerified)
+	}
+	if err := r.updatePVC(pvcCpy); err != nil {
+		return nil, err
+	}
+	return pvcCpy, nil
+}
+
+func (r *ReconcilerBase) updateConditions(dataVolume *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim, reason, message string) {
 	var anno map[string]string
 
 	if dataVolume.Status.Conditions == nil {
@@ -813,6 +1079,9 @@ func (r *ReconcilerBase) updateConditions(dataVolume *cdiv1.DataVolume, pvc *cor
 
 	if pvc != nil {
 		anno = pvc.Annotations
+	} else if cc.IsVerifyOnly(dataVolume) {
+		// a verify-only DataVolume has no PVC, the running condition is the one of its importer pod
+		anno = r.verifyPodRunningAnnotations(dataVolume)
 	} else {
 		anno = make(map[string]string)
 	}
@@ -827,9 +1096,23 @@ func (r *ReconcilerBase) updateConditions(dataVolume *cdiv1.DataVolume, pvc *cor
 		readyStatus = corev1.ConditionFalse
 	}
 
-	dataVolume.Status.Conditions = updateBoundCondition(dataVolume.Status.Conditions, pvc, reason)
+	if pvc == nil && reason == cc.IncompleteStorageProfile {
+		// The PVC can't be created until the StorageProfile is completed
+		dataVolume.Status.Conditions = updateCondition(dataVolume.Status.Conditions, cdiv1.DataVolumeBound, corev1.ConditionFalse, message, reason)
+	} else {
+		dataVolume.Status.Conditions = updateBoundCondition(dataVolume.Status.Conditions, pvc, reason)
+	}
 	dataVolume.Status.Conditions = UpdateReadyCondition(dataVolume.Status.Conditions, readyStatus, "", reason)
 	dataVolume.Status.Conditions = updateRunningCondition(dataVolume.Status.Conditions, anno)
+	if dataVolume.Status.Phase == cdiv1.Succeeded || dataVolume.Status.Phase == cdiv1.Failed {
+		dataVolume.Status.Conditions = updateConversionDoneCondition(dataVolume.Status.Conditions, dataVolume.Status.Phase)
+	}
+	if dvRequestsPopulatedVerification(dataVolume) {
+		dataVolume.Status.Conditions = updateVerifiedCondition(dataVolume.Status.Conditions, dataVolume, pvc)
+	}
+	dataVolume.Status.Conditions = updateCloneStrategyCondition(dataVolume.Status.Conditions, dataVolume)
+	dataVolume.Status.Conditions = updateCloneFallbackCondition(dataVolume.Status.Conditions, dataVolume)
+	dataVolume.Status.Conditions = updateCanceledCondition(dataVolume.Status.Conditions, dataVolume)
 }
 
 func (r *ReconcilerBase) emitConditionEvent(dataVolume *cdiv1.DataVolume, originalCond []cdiv1.DataVolumeCondition) {
@@ -853,6 +1136,10 @@ func (r *ReconcilerBase) emitFailureConditionEvent(dataVolume *cdiv1.DataVolume,
 	if curReady == nil || curBound == nil || curRunning == nil {
 		return
 	}
+	if curRunning.Reason == cc.WorkerPodQueued {
+		// Waiting for a worker pod slot is not a failure, the phase event reports it
+		return
+	}
 	if curReady.Status == corev1.ConditionFalse && curRunning.Status == corev1.ConditionFalse && curBound.Status == corev1.ConditionTrue {
 		//Bound, not ready, and not running
 		if curRunning.Message != "" && orgRunning.Message != curRunning.Message {
@@ -875,11 +1162,49 @@ func (r *ReconcilerBase) emitEvent(dataVolume *cdiv1.DataVolume, dataVolumeCopy
 		if event.eventType != "" && curPhase != dataVolumeCopy.Status.Phase {
 			r.recorder.Event(dataVolumeCopy, event.eventType, event.reason, event.message)
 		}
+		if curPhase != dataVolumeCopy.Status.Phase {
+			r.logLifecycle(dataVolumeCopy, event)
+		}
 		r.emitConditionEvent(dataVolumeCopy, originalCond)
 	}
 	return nil
 }
 
+// logLifecycle logs the transfer lifecycle event matching the phase of the DataVolume, with the structured fields
+// shared with the CDI pods
+func (r *ReconcilerBase) logLifecycle(dv *cdiv1.DataVolume, event *Event) {
+	lifecycleEvent := phaseLifecycleEvent(dv.Status.Phase)
+	if lifecycleEvent == "" {
+		return
+	}
+	keysAndValues := []interface{}{
+		logging.FieldEvent, lifecycleEvent,
+		logging.FieldNamespace, dv.Namespace,
+		logging.FieldDataVolume, dv.Name,
+		logging.FieldPhase, dv.Status.Phase,
+	}
+	if lifecycleEvent == logging.EventFail {
+		keysAndValues = append(keysAndValues, logging.FieldError, event.message)
+	}
+	r.log.Info(fmt.Sprintf("Transfer %s", lifecycleEvent), keysAndValues...)
+}
+
+func phaseLifecycleEvent(phase cdiv1.DataVolumePhase) string {
+	switch phase {
+	case cdiv1.ImportScheduled, cdiv1.CloneScheduled, cdiv1.UploadScheduled:
+		return logging.EventStart
+	case cdiv1.ImportInProgress, cdiv1.CloneInProgress, cdiv1.UploadReady, cdiv1.SnapshotForSmartCloneInProgress,
+		cdiv1.CloneFromSnapshotSourceInProgress, cdiv1.SmartClonePVCInProgress, cdiv1.CSICloneInProgress,
+		cdiv1.ExpansionInProgress, cdiv1.NamespaceTransferInProgress:
+		return logging.EventProgress
+	case cdiv1.Succeeded, cdiv1.SourceVerified:
+		return logging.EventComplete
+	case cdiv1.Failed:
+		return logging.EventFail
+	}
+	return ""
+}
+
 // getPodFromPvc determines the pod associated with the pvc passed in.
 func (r *ReconcilerBase) getPodFromPvc(namespace string, pvc *corev1.PersistentVolumeClaim) (*corev1.Pod, error) {
 	l, _ := labels.Parse(common.PrometheusLabelKey)
@@ -934,6 +1259,8 @@ func updateProgressUsingPod(dataVolumeCopy *cdiv1.DataVolume, pod *corev1.Pod) e
 	httpClient := buildHTTPClient()
 	// Example value: import_progress{ownerUID="b856691e-1038-11e9-a5ab-525500d15501"} 13.45
 	var importRegExp = regexp.MustCompile("progress\\{ownerUID\\=\"" + string(dataVolumeCopy.UID) + "\"\\} (\\d{1,3}\\.?\\d*)")
+	// Example value: conversion_progress_percent{ownerUID="b856691e-1038-11e9-a5ab-525500d15501"} -1
+	var conversionRegExp = regexp.MustCompile("conversion_progress_percent\\{ownerUID\\=\"" + string(dataVolumeCopy.UID) + "\"\\} (-?\\d{1,3}\\.?\\d*)")
 
 	port, err := getPodMetricsPort(pod)
 	if err == nil && pod.Status.PodIP != "" {
@@ -951,6 +1278,11 @@ func updateProgressUsingPod(dataVolumeCopy *cdiv1.DataVolume, pod *corev1.Pod) e
 			return err
 		}
 
+		if match := conversionRegExp.FindStringSubmatch(string(body)); match != nil {
+			if f, err := strconv.ParseFloat(match[1], 64); err == nil {
+				dataVolumeCopy.Status.Conditions = updateConvertingCondition(dataVolumeCopy.Status.Conditions, f)
+			}
+		}
 		match := importRegExp.FindStringSubmatch(string(body))
 		if match == nil {
 			// No match
@@ -1007,15 +1339,15 @@ func buildHTTPClient() *http.Client {
 // which allows handleObject to discover the DataVolume resource
 // that 'owns' it.
 func (r *ReconcilerBase) newPersistentVolumeClaim(dataVolume *cdiv1.DataVolume, targetPvcSpec *corev1.PersistentVolumeClaimSpec, namespace, name string, pvcModifier pvcModifierFunc) (*corev1.PersistentVolumeClaim, error) {
-	labels := map[string]string{
-		common.CDILabelKey: common.CDILabelValue,
-	}
+	labels := make(map[string]string)
 	if util.ResolveVolumeMode(targetPvcSpec.VolumeMode) == corev1.PersistentVolumeFilesystem {
 		labels[common.KubePersistentVolumeFillingUpSuppressLabelKey] = common.KubePersistentVolumeFillingUpSuppressLabelValue
 	}
 	for k, v := range dataVolume.Labels {
 		labels[k] = v
 	}
+	// The CDI label is used to select the PVCs managed by CDI, it takes precedence over the DataVolume labels
+	labels[common.CDILabelKey] = common.CDILabelValue
 
 	annotations := make(map[string]string)
 	for k, v := range dataVolume.ObjectMeta.Annotations {
@@ -1026,6 +1358,12 @@ func (r *ReconcilerBase) newPersistentVolumeClaim(dataVolume *cdiv1.DataVolume,
 	if dataVolume.Spec.PriorityClassName != "" {
 		annotations[cc.AnnPriorityClassName] = dataVolume.Spec.PriorityClassName
 	}
+	if err := cc.SetWorkerPodPlacementAnnotation(dataVolume, annotations); err != nil {
+		return nil, err
+	}
+	if err := cc.SetWorkerPodImageAnnotation(dataVolume, annotations); err != nil {
+		return nil, err
+	}
 	annotations[cc.AnnPreallocationRequested] = strconv.FormatBool(cc.GetPreallocation(r.client, dataVolume))
 
 	pvc := &corev1.PersistentVolumeClaim{
@@ -1105,8 +1443,10 @@ func (r *ReconcilerBase) shouldBeMarkedWaitForFirstConsumer(pvc *corev1.Persiste
 	if err != nil {
 		return false, err
 	}
+	// the worker pod of a PVC pinned to a topology is its first consumer
+	_, isTopologyRequested := pvc.Annotations[cc.AnnTopology]
 
-	res := honorWaitForFirstConsumerEnabled &&
+	res := honorWaitForFirstConsumerEnabled && !isTopologyRequested &&
 		storageClassBindingMode != nil && *storageClassBindingMode == storagev1.VolumeBindingWaitForFirstConsumer &&
 		pvc.Status.Phase == corev1.ClaimPending
 
@@ -1116,6 +1456,9 @@ func (r *ReconcilerBase) shouldBeMarkedWaitForFirstConsumer(pvc *corev1.Persiste
 // handlePvcCreation works as a wrapper for non-clone PVC creation and error handling
 func (r *ReconcilerBase) handlePvcCreation(log logr.Logger, syncState *dvSyncState, pvcModifier pvcModifierFunc) error {
 	if syncState.pvc != nil {
+		if pvcNeedsAdoption(syncState.pvc, syncState.dvMutated) {
+			return r.adoptPvc(syncState, pvcModifier)
+		}
 		return nil
 	}
 	if dvIsPrePopulated(syncState.dvMutated) {
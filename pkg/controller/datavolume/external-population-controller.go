@@ -62,8 +62,10 @@ func NewPopulatorController(ctx context.Context, mgr manager.Manager, log logr.L
 	reconciler := &PopulatorReconciler{
 		ReconcilerBase: ReconcilerBase{
 			client:          client,
+			uncachedClient:  mgr.GetAPIReader(),
 			scheme:          mgr.GetScheme(),
 			log:             log.WithName(populatorControllerName),
+			controllerName:  populatorControllerName,
 			recorder:        mgr.GetEventRecorderFor(populatorControllerName),
 			featureGates:    featuregates.NewFeatureGates(client),
 			installerLabels: installerLabels,
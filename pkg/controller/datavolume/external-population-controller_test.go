@@ -369,11 +369,12 @@ func createPopulatorReconcilerWithoutConfig(objects ...runtime.Object) *Populato
 	// Create a ReconcileMemcached object with the scheme and fake client.
 	r := &PopulatorReconciler{
 		ReconcilerBase: ReconcilerBase{
-			client:       cl,
-			scheme:       s,
-			log:          dvPopulatorLog,
-			recorder:     rec,
-			featureGates: featuregates.NewFeatureGates(cl),
+			client:         cl,
+			uncachedClient: cl,
+			scheme:         s,
+			log:            dvPopulatorLog,
+			recorder:       rec,
+			featureGates:   featuregates.NewFeatureGates(cl),
 			installerLabels: map[string]string{
 				common.AppKubernetesPartOfLabel:  "testing",
 				common.AppKubernetesVersionLabel: "v0.0.0-tests",
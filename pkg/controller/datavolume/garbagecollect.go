@@ -66,6 +66,10 @@ func (r *ReconcilerBase) isGarbageCollectionAllowed(dv *cdiv1.DataVolume, log lo
 		log.Info("DataVolume is not annotated to be garbage collected")
 		return false, nil
 	}
+	if hasCompletionHook(dv) && !completionHookFired(dv) {
+		log.Info("DataVolume cannot be garbage collected before its completion hook fired")
+		return false, nil
+	}
 	for _, ref := range dv.OwnerReferences {
 		if ref.BlockOwnerDeletion == nil || !*ref.BlockOwnerDeletion {
 			continue
@@ -102,6 +106,9 @@ func (r *ReconcilerBase) canUpdateFinalizers(ownerRef metav1.OwnerReference) (bo
 }
 
 func (r *ReconcilerBase) detachPvcDeleteDv(syncState *dvSyncState) error {
+	if err := r.detachRegistryDiskPvcs(syncState.dv); err != nil {
+		return err
+	}
 	updatePvcOwnerRefs(syncState.pvc, syncState.dv)
 	delete(syncState.pvc.Annotations, cc.AnnPopulatedFor)
 	if err := r.updatePVC(syncState.pvc); err != nil {
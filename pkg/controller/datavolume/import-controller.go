@@ -26,14 +26,19 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 
+	"kubevirt.io/containerized-data-importer/pkg/common"
 	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
 	featuregates "kubevirt.io/containerized-data-importer/pkg/feature-gates"
+	"kubevirt.io/containerized-data-importer/pkg/util"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 const (
@@ -59,6 +64,15 @@ const (
 	// MessageImportPaused provides a const for a "multistage import paused" message
 	MessageImportPaused = "Multistage import into PVC %s is paused"
 
+	// MessageRemoteCloneScheduled provides a const to form remote clone is scheduled message
+	MessageRemoteCloneScheduled = "Cloning from %s into %s scheduled"
+	// MessageRemoteCloneInProgress provides a const to form remote clone is in progress message
+	MessageRemoteCloneInProgress = "Cloning from %s into %s in progress"
+	// MessageRemoteCloneFailed provides a const to form remote clone has failed message
+	MessageRemoteCloneFailed = "Failed to clone from %s into PVC %s"
+	// MessageRemoteCloneSucceeded provides a const to form remote clone has succeeded message
+	MessageRemoteCloneSucceeded = "Successfully cloned from %s into PVC %s"
+
 	importControllerName = "datavolume-import-controller"
 )
 
@@ -78,8 +92,10 @@ func NewImportController(
 	reconciler := &ImportReconciler{
 		ReconcilerBase: ReconcilerBase{
 			client:          client,
+			uncachedClient:  mgr.GetAPIReader(),
 			scheme:          mgr.GetScheme(),
 			log:             log.WithName(importControllerName),
+			controllerName:  importControllerName,
 			recorder:        mgr.GetEventRecorderFor(importControllerName),
 			featureGates:    featuregates.NewFeatureGates(client),
 			installerLabels: installerLabels,
@@ -103,6 +119,16 @@ func addDataVolumeImportControllerWatches(mgr manager.Manager, datavolumeControl
 	if err := addDataVolumeControllerCommonWatches(mgr, datavolumeController, dataVolumeImport); err != nil {
 		return err
 	}
+	if err := addSnapshotTargetWatch(mgr, datavolumeController); err != nil {
+		return err
+	}
+	// The importer pod verifying the source of a verify-only DataVolume is owned by it
+	if err := datavolumeController.Watch(&source.Kind{Type: &corev1.Pod{}}, &handler.EnqueueRequestForOwner{
+		OwnerType:    &cdiv1.DataVolume{},
+		IsController: true,
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -114,7 +140,11 @@ func (r *ImportReconciler) updateAnnotations(dataVolume *cdiv1.DataVolume, pvc *
 		annotations[cc.AnnPreviousCheckpoint] = checkpoint.Previous
 		annotations[cc.AnnFinalCheckpoint] = strconv.FormatBool(checkpoint.IsFinal)
 	}
+	return SetImportSourceAnnotations(dataVolume, annotations)
+}
 
+// SetImportSourceAnnotations sets the annotations passing the import source of the DataVolume to the importer
+func SetImportSourceAnnotations(dataVolume *cdiv1.DataVolume, annotations map[string]string) error {
 	if dataVolume.Spec.Source.HTTP != nil {
 		annotations[cc.AnnEndpoint] = dataVolume.Spec.Source.HTTP.URL
 		annotations[cc.AnnSource] = cc.SourceHTTP
@@ -142,6 +172,16 @@ func (r *ImportReconciler) updateAnnotations(dataVolume *cdiv1.DataVolume, pvc *
 		if dataVolume.Spec.Source.S3.CertConfigMap != "" {
 			annotations[cc.AnnCertConfigMap] = dataVolume.Spec.Source.S3.CertConfigMap
 		}
+		if dataVolume.Spec.Source.S3.KMSKeyID != "" {
+			annotations[cc.AnnS3KMSKeyID] = dataVolume.Spec.Source.S3.KMSKeyID
+		}
+		if token := dataVolume.Spec.Source.S3.TokenCredentials; token != nil {
+			annotations[cc.AnnTokenAudience] = token.Audience
+			if token.Audience == "" {
+				annotations[cc.AnnTokenAudience] = common.DefaultS3TokenAudience
+			}
+			annotations[cc.AnnTokenRoleARN] = token.RoleARN
+		}
 		return nil
 	}
 	if dataVolume.Spec.Source.GCS != nil {
@@ -150,6 +190,18 @@ func (r *ImportReconciler) updateAnnotations(dataVolume *cdiv1.DataVolume, pvc *
 		if dataVolume.Spec.Source.GCS.SecretRef != "" {
 			annotations[cc.AnnSecret] = dataVolume.Spec.Source.GCS.SecretRef
 		}
+		if dataVolume.Spec.Source.GCS.UserProject != "" {
+			annotations[cc.AnnGcsUserProject] = dataVolume.Spec.Source.GCS.UserProject
+		}
+		if dataVolume.Spec.Source.GCS.Generation != 0 {
+			annotations[cc.AnnGcsGeneration] = strconv.FormatInt(dataVolume.Spec.Source.GCS.Generation, 10)
+		}
+		if token := dataVolume.Spec.Source.GCS.TokenCredentials; token != nil {
+			annotations[cc.AnnTokenAudience] = token.Audience
+			if token.ServiceAccount != "" {
+				annotations[cc.AnnTokenServiceAccount] = token.ServiceAccount
+			}
+		}
 		return nil
 	}
 	if dataVolume.Spec.Source.Registry != nil {
@@ -176,12 +228,68 @@ func (r *ImportReconciler) updateAnnotations(dataVolume *cdiv1.DataVolume, pvc *
 		if certConfigMap != nil && *certConfigMap != "" {
 			annotations[cc.AnnCertConfigMap] = *certConfigMap
 		}
+		if diskPath := getRegistryDiskPath(dataVolume); diskPath != "" {
+			annotations[cc.AnnRegistryDiskPath] = diskPath
+		}
 		return nil
 	}
 	if dataVolume.Spec.Source.Blank != nil {
 		annotations[cc.AnnSource] = cc.SourceNone
 		return nil
 	}
+	if dataVolume.Spec.Source.Inline != nil {
+		// The endpoint of an inline source is the ConfigMap holding its image
+		annotations[cc.AnnEndpoint] = cc.GetInlineSourceConfigMapName(dataVolume.Name)
+		annotations[cc.AnnSource] = cc.SourceInline
+		return nil
+	}
+	if dataVolume.Spec.Source.NBD != nil {
+		annotations[cc.AnnEndpoint] = dataVolume.Spec.Source.NBD.URL
+		annotations[cc.AnnSource] = cc.SourceNBD
+		if dataVolume.Spec.Source.NBD.SecretRef != "" {
+			annotations[cc.AnnSecret] = dataVolume.Spec.Source.NBD.SecretRef
+		}
+		return nil
+	}
+	if dataVolume.Spec.Source.Remote != nil {
+		// A remote clone reuses the import pipeline, authorized by the token of its secret
+		annotations[cc.AnnEndpoint] = dataVolume.Spec.Source.Remote.URL
+		annotations[cc.AnnSource] = cc.SourceRemote
+		annotations[cc.AnnSecret] = dataVolume.Spec.Source.Remote.SecretRef
+		if dataVolume.Spec.Source.Remote.CertConfigMap != "" {
+			annotations[cc.AnnCertConfigMap] = dataVolume.Spec.Source.Remote.CertConfigMap
+		}
+		return nil
+	}
+	if dataVolume.Spec.Source.FTP != nil {
+		annotations[cc.AnnEndpoint] = dataVolume.Spec.Source.FTP.URL
+		annotations[cc.AnnSource] = cc.SourceFTP
+		if dataVolume.Spec.Source.FTP.SecretRef != "" {
+			annotations[cc.AnnSecret] = dataVolume.Spec.Source.FTP.SecretRef
+		}
+		if dataVolume.Spec.Source.FTP.CertConfigMap != "" {
+			annotations[cc.AnnCertConfigMap] = dataVolume.Spec.Source.FTP.CertConfigMap
+		}
+		return nil
+	}
+	if dataVolume.Spec.Source.Azure != nil {
+		annotations[cc.AnnEndpoint] = dataVolume.Spec.Source.Azure.URL
+		annotations[cc.AnnSource] = cc.SourceAzure
+		if dataVolume.Spec.Source.Azure.SecretRef != "" {
+			annotations[cc.AnnSecret] = dataVolume.Spec.Source.Azure.SecretRef
+		}
+		if dataVolume.Spec.Source.Azure.CertConfigMap != "" {
+			annotations[cc.AnnCertConfigMap] = dataVolume.Spec.Source.Azure.CertConfigMap
+		}
+		return nil
+	}
+	if dataVolume.Spec.Source.SFTP != nil {
+		annotations[cc.AnnEndpoint] = dataVolume.Spec.Source.SFTP.URL
+		annotations[cc.AnnSource] = cc.SourceSFTP
+		annotations[cc.AnnSecret] = dataVolume.Spec.Source.SFTP.SecretRef
+		annotations[cc.AnnSFTPHostKey] = dataVolume.Spec.Source.SFTP.HostKey
+		return nil
+	}
 	if dataVolume.Spec.Source.Imageio != nil {
 		annotations[cc.AnnEndpoint] = dataVolume.Spec.Source.Imageio.URL
 		annotations[cc.AnnSource] = cc.SourceImageio
@@ -223,6 +331,24 @@ func (r *ImportReconciler) syncImport(log logr.Logger, req reconcile.Request) (d
 	if syncErr != nil || syncState.result != nil {
 		return syncState, syncErr
 	}
+	if cc.IsVerifyOnly(syncState.dvMutated) {
+		return syncState, r.syncVerifyOnly(&syncState)
+	}
+	if syncState.dvMutated.Spec.SnapshotTarget != nil {
+		if done, err := r.syncSnapshotTarget(&syncState); done || err != nil {
+			return syncState, err
+		}
+	}
+	if syncState.pvc == nil {
+		if err := r.createInlineSourceConfigMap(syncState.dvMutated); err != nil {
+			return syncState, err
+		}
+	}
+	if len(getRegistryDisks(syncState.dvMutated)) > 0 {
+		if conflict, err := r.syncRegistryDisks(&syncState); conflict || err != nil {
+			return syncState, err
+		}
+	}
 	if err := r.handlePvcCreation(log, &syncState, r.updateAnnotations); err != nil {
 		syncErr = err
 	}
@@ -234,6 +360,41 @@ func (r *ImportReconciler) syncImport(log logr.Logger, req reconcile.Request) (d
 }
 
 func (r *ImportReconciler) updateStatusPhase(pvc *corev1.PersistentVolumeClaim, dataVolumeCopy *cdiv1.DataVolume, event *Event) error {
+	if err := r.updateImportStatusPhase(pvc, dataVolumeCopy, event); err != nil {
+		return err
+	}
+	if dataVolumeCopy.Spec.Source != nil && dataVolumeCopy.Spec.Source.Remote != nil {
+		updateRemoteCloneStatusPhase(pvc, dataVolumeCopy, event)
+	}
+	return nil
+}
+
+// updateRemoteCloneStatusPhase reports the import of a remote clone source with the phases and events of a clone
+func updateRemoteCloneStatusPhase(pvc *corev1.PersistentVolumeClaim, dataVolumeCopy *cdiv1.DataVolume, event *Event) {
+	switch dataVolumeCopy.Status.Phase {
+	case cdiv1.ImportScheduled:
+		dataVolumeCopy.Status.Phase = cdiv1.CloneScheduled
+	case cdiv1.ImportInProgress:
+		dataVolumeCopy.Status.Phase = cdiv1.CloneInProgress
+	}
+	url := util.RedactSourceURL(dataVolumeCopy.Spec.Source.Remote.URL)
+	switch event.reason {
+	case ImportScheduled:
+		event.reason = CloneScheduled
+		event.message = fmt.Sprintf(MessageRemoteCloneScheduled, url, pvc.Name)
+	case ImportInProgress:
+		event.reason = CloneInProgress
+		event.message = fmt.Sprintf(MessageRemoteCloneInProgress, url, pvc.Name)
+	case ImportFailed:
+		event.reason = CloneFailed
+		event.message = fmt.Sprintf(MessageRemoteCloneFailed, url, pvc.Name)
+	case ImportSucceeded:
+		event.reason = CloneSucceeded
+		event.message = fmt.Sprintf(MessageRemoteCloneSucceeded, url, pvc.Name)
+	}
+}
+
+func (r *ImportReconciler) updateImportStatusPhase(pvc *corev1.PersistentVolumeClaim, dataVolumeCopy *cdiv1.DataVolume, event *Event) error {
 	phase, ok := pvc.Annotations[cc.AnnPodPhase]
 	if phase != string(corev1.PodSucceeded) {
 		_, ok := pvc.Annotations[cc.AnnImportPod]
@@ -263,6 +424,12 @@ func (r *ImportReconciler) updateStatusPhase(pvc *corev1.PersistentVolumeClaim,
 		event.eventType = corev1.EventTypeWarning
 		event.reason = ImportFailed
 		event.message = fmt.Sprintf(MessageImportFailed, pvc.Name)
+		if pvc.Annotations[cc.AnnImportAttemptsExhausted] == "true" {
+			// the import gave up, this is terminal
+			dataVolumeCopy.Status.Phase = cdiv1.Failed
+			event.reason = pvc.Annotations[cc.AnnRunningConditionReason]
+			event.message = pvc.Annotations[cc.AnnRunningConditionMessage]
+		}
 	case string(corev1.PodSucceeded):
 		if _, ok := pvc.Annotations[cc.AnnCurrentCheckpoint]; ok {
 			if err := r.updatesMultistageImportSucceeded(pvc, dataVolumeCopy); err != nil {
@@ -281,6 +448,41 @@ func (r *ImportReconciler) updateStatusPhase(pvc *corev1.PersistentVolumeClaim,
 		event.reason = ImportSucceeded
 		event.message = fmt.Sprintf(MessageImportSucceeded, pvc.Name)
 	}
+	if phase != string(corev1.PodSucceeded) && pvc.Annotations[cc.AnnRunningConditionReason] == cc.ScratchSpaceExhausted {
+		// the importer pod is retried with the same scratch space, tell the user to increase it
+		event.eventType = corev1.EventTypeWarning
+		event.reason = cc.ScratchSpaceExhausted
+		event.message = pvc.Annotations[cc.AnnRunningConditionMessage]
+	}
+	return nil
+}
+
+// createInlineSourceConfigMap creates the ConfigMap holding the image of the inline source of the DataVolume, mounted
+// by the importer pod. The ConfigMap is owned by the DataVolume, so it is deleted along with it.
+func (r *ImportReconciler) createInlineSourceConfigMap(dv *cdiv1.DataVolume) error {
+	if dv.Spec.Source == nil || dv.Spec.Source.Inline == nil {
+		return nil
+	}
+	immutable := true
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cc.GetInlineSourceConfigMapName(dv.Name),
+			Namespace: dv.Namespace,
+			Labels: map[string]string{
+				common.CDILabelKey:       common.CDILabelValue,
+				common.CDIComponentLabel: "",
+			},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(dv, cdiv1.SchemeGroupVersion.WithKind("DataVolume"))},
+		},
+		Data: map[string]string{
+			common.InlineSourceDataKey: dv.Spec.Source.Inline.Data,
+		},
+		Immutable: &immutable,
+	}
+	util.SetRecommendedLabels(configMap, r.installerLabels, "cdi-controller")
+	if err := r.client.Create(context.TODO(), configMap); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return err
+	}
 	return nil
 }
 
@@ -98,6 +98,27 @@ var _ = Describe("All DataVolume Tests", func() {
 			Expect(pvc.Labels[common.KubePersistentVolumeFillingUpSuppressLabelKey]).To(Equal(common.KubePersistentVolumeFillingUpSuppressLabelValue))
 		})
 
+		It("Should create a ConfigMap holding the image of an inline source", func() {
+			dv := NewImportDataVolume("test-dv")
+			dv.Spec.Source = &cdiv1.DataVolumeSource{Inline: &cdiv1.DataVolumeSourceInline{Data: "c2VlZCBpbWFnZQ=="}}
+			reconciler = createImportReconciler(dv)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+
+			configMap := &corev1.ConfigMap{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv-inline-source", Namespace: metav1.NamespaceDefault}, configMap)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(configMap.Data[common.InlineSourceDataKey]).To(Equal("c2VlZCBpbWFnZQ=="))
+			Expect(configMap.Immutable).To(HaveValue(BeTrue()))
+			Expect(metav1.IsControlledBy(configMap, dv)).To(BeTrue())
+
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.Annotations[AnnSource]).To(Equal(SourceInline))
+			Expect(pvc.Annotations[AnnEndpoint]).To(Equal(configMap.Name))
+		})
+
 		It("Should pass instancetype labels from DV to PVC", func() {
 			dv := NewImportDataVolume("test-dv")
 			dv.Labels = map[string]string{}
@@ -121,6 +142,46 @@ var _ = Describe("All DataVolume Tests", func() {
 			Expect(pvc.Labels[LabelDefaultPreferenceKind]).To(Equal(LabelDefaultPreferenceKind))
 		})
 
+		It("Should pass user labels and annotations from DV to PVC, without overriding CDI ones", func() {
+			dv := NewImportDataVolume("test-dv")
+			dv.Labels = map[string]string{
+				"cost-center":      "1234",
+				common.CDILabelKey: "user-app",
+			}
+			dv.Annotations = map[string]string{
+				"example.com/owner": "team",
+				AnnPodRestarts:      "5",
+			}
+
+			reconciler = createImportReconciler(dv)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(pvc.Labels["cost-center"]).To(Equal("1234"))
+			Expect(pvc.Labels[common.CDILabelKey]).To(Equal(common.CDILabelValue))
+			Expect(pvc.Annotations["example.com/owner"]).To(Equal("team"))
+			Expect(pvc.Annotations[AnnPodRestarts]).To(Equal("0"))
+		})
+
+		It("Should pass the topology from DV to PVC", func() {
+			topology := "topology.kubernetes.io/zone=us-east-1a"
+			dv := NewImportDataVolume("test-dv")
+			dv.Annotations = map[string]string{AnnTopology: topology}
+
+			reconciler = createImportReconciler(dv)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.Annotations[AnnTopology]).To(Equal(topology))
+		})
+
 		It("Should set params on a PVC from import DV.PVC", func() {
 			importDataVolume := NewImportDataVolume("test-dv")
 			importDataVolume.Spec.PVC.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
@@ -209,6 +270,201 @@ var _ = Describe("All DataVolume Tests", func() {
 			Expect(err.Error()).To(ContainSubstring("missing storage size"))
 		})
 
+		It("Should set params on a PVC from the storageProfile of the picked storage class instead of the default one", func() {
+			defaultScName := "defaultSc"
+			targetScName := "targetSc"
+			importDataVolume := newImportDataVolumeWithPvc("test-dv", nil)
+			importDataVolume.Spec.Storage = &cdiv1.StorageSpec{
+				StorageClassName: &targetScName,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("1G"),
+					},
+				},
+			}
+			defaultStorageClass := CreateStorageClass(defaultScName, map[string]string{AnnDefaultStorageClass: "true"})
+			defaultStorageProfile := createStorageProfile(defaultScName, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}, FilesystemMode)
+			targetStorageClass := CreateStorageClass(targetScName, nil)
+			targetStorageProfile := createStorageProfile(targetScName, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}, BlockMode)
+
+			reconciler = createImportReconciler(defaultStorageClass, defaultStorageProfile, targetStorageClass, targetStorageProfile, importDataVolume)
+
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*pvc.Spec.StorageClassName).To(Equal(targetScName))
+			Expect(pvc.Spec.AccessModes).To(ConsistOf(corev1.ReadWriteMany))
+			Expect(*pvc.Spec.VolumeMode).To(Equal(BlockMode))
+		})
+
+		It("Should fail when the picked storage class does not exist", func() {
+			scName := "missingSc"
+			importDataVolume := newImportDataVolumeWithPvc("test-dv", nil)
+			importDataVolume.Spec.Storage = createStorageSpec()
+			importDataVolume.Spec.Storage.StorageClassName = &scName
+			defaultStorageClass := CreateStorageClass("defaultSc", map[string]string{AnnDefaultStorageClass: "true"})
+			reconciler = createImportReconciler(defaultStorageClass, importDataVolume)
+
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("StorageClass missingSc not found"))
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("Should fail when the picked storage class has no storageProfile to resolve the access mode", func() {
+			scName := "targetSc"
+			importDataVolume := newImportDataVolumeWithPvc("test-dv", nil)
+			importDataVolume.Spec.Storage = &cdiv1.StorageSpec{
+				StorageClassName: &scName,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("1G"),
+					},
+				},
+			}
+			defaultStorageClass := CreateStorageClass("defaultSc", map[string]string{AnnDefaultStorageClass: "true"})
+			defaultStorageProfile := createStorageProfile("defaultSc", []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}, FilesystemMode)
+			targetStorageClass := CreateStorageClass(scName, nil)
+			reconciler = createImportReconciler(defaultStorageClass, defaultStorageProfile, targetStorageClass, importDataVolume)
+
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("cannot get StorageProfile"))
+		})
+
+		It("Should set the Bound condition when the storageProfile is incomplete, and clear it once the profile is fixed", func() {
+			scName := "testStorageClass"
+			importDataVolume := newImportDataVolumeWithPvc("test-dv", nil)
+			importDataVolume.Spec.Storage = &cdiv1.StorageSpec{
+				StorageClassName: &scName,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("1G"),
+					},
+				},
+			}
+			storageClass := CreateStorageClass(scName, map[string]string{AnnDefaultStorageClass: "true"})
+			storageProfile := createStorageProfile(scName, nil, FilesystemMode)
+			reconciler = createImportReconciler(storageClass, storageProfile, importDataVolume)
+
+			dvKey := types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}
+			result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: dvKey})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(reconcile.Result{}))
+			dv := &cdiv1.DataVolume{}
+			Expect(reconciler.client.Get(context.TODO(), dvKey, dv)).To(Succeed())
+			Expect(dv.Status.Phase).To(Equal(cdiv1.Pending))
+			boundCondition := FindConditionByType(cdiv1.DataVolumeBound, dv.Status.Conditions)
+			Expect(boundCondition.Status).To(Equal(corev1.ConditionFalse))
+			Expect(boundCondition.Reason).To(Equal(IncompleteStorageProfile))
+			Expect(boundCondition.Message).To(ContainSubstring("set the claimPropertySets of StorageProfile testStorageClass"))
+			Expect(isWaitingForStorageProfile(dv)).To(BeTrue())
+			err = reconciler.client.Get(context.TODO(), dvKey, &corev1.PersistentVolumeClaim{})
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+
+			By("Reconciling again while the profile is still incomplete")
+			transitionTime := boundCondition.LastTransitionTime
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: dvKey})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reconciler.client.Get(context.TODO(), dvKey, dv)).To(Succeed())
+			boundCondition = FindConditionByType(cdiv1.DataVolumeBound, dv.Status.Conditions)
+			Expect(boundCondition.Reason).To(Equal(IncompleteStorageProfile))
+			Expect(boundCondition.LastTransitionTime).To(Equal(transitionTime))
+
+			By("Fixing the storage profile")
+			Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: scName}, storageProfile)).To(Succeed())
+			storageProfile.Status.ClaimPropertySets[0].AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+			Expect(reconciler.client.Update(context.TODO(), storageProfile)).To(Succeed())
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: dvKey})
+			Expect(err).ToNot(HaveOccurred())
+			pvc := &corev1.PersistentVolumeClaim{}
+			Expect(reconciler.client.Get(context.TODO(), dvKey, pvc)).To(Succeed())
+			Expect(pvc.Spec.AccessModes).To(Equal([]corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}))
+			Expect(reconciler.client.Get(context.TODO(), dvKey, dv)).To(Succeed())
+			boundCondition = FindConditionByType(cdiv1.DataVolumeBound, dv.Status.Conditions)
+			Expect(boundCondition.Reason).ToNot(Equal(IncompleteStorageProfile))
+			Expect(isWaitingForStorageProfile(dv)).To(BeFalse())
+		})
+
+		It("Should set the Bound condition when the storage class has no provisioner nor PV, and clear it once a PV is available", func() {
+			scName := "local"
+			importDataVolume := newImportDataVolumeWithPvc("test-dv", nil)
+			importDataVolume.Spec.Storage = &cdiv1.StorageSpec{
+				StorageClassName: &scName,
+				AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("1G"),
+					},
+				},
+			}
+			storageClass := CreateStorageClassWithProvisioner(scName, nil, nil, noProvisioner)
+			storageProfile := createStorageProfile(scName, nil, FilesystemMode)
+			boundPv := &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: "bound-pv"},
+				Spec:       corev1.PersistentVolumeSpec{StorageClassName: scName},
+				Status:     corev1.PersistentVolumeStatus{Phase: corev1.VolumeBound},
+			}
+			reconciler = createImportReconciler(storageClass, storageProfile, importDataVolume, boundPv)
+
+			dvKey := types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}
+			result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: dvKey})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(reconcile.Result{}))
+			dv := &cdiv1.DataVolume{}
+			Expect(reconciler.client.Get(context.TODO(), dvKey, dv)).To(Succeed())
+			Expect(dv.Status.Phase).To(Equal(cdiv1.Pending))
+			boundCondition := FindConditionByType(cdiv1.DataVolumeBound, dv.Status.Conditions)
+			Expect(boundCondition.Status).To(Equal(corev1.ConditionFalse))
+			Expect(boundCondition.Reason).To(Equal(IncompleteStorageProfile))
+			Expect(boundCondition.Message).To(Equal(fmt.Sprintf(MessageNoProvisionerStorageProfile, scName, scName)))
+			Expect(isWaitingForStorageProfile(dv)).To(BeTrue())
+			err = reconciler.client.Get(context.TODO(), dvKey, &corev1.PersistentVolumeClaim{})
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+
+			By("Creating an available PV of the storage class")
+			availablePv := &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: "available-pv"},
+				Spec:       corev1.PersistentVolumeSpec{StorageClassName: scName},
+				Status:     corev1.PersistentVolumeStatus{Phase: corev1.VolumeAvailable},
+			}
+			Expect(reconciler.client.Create(context.TODO(), availablePv)).To(Succeed())
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: dvKey})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reconciler.client.Get(context.TODO(), dvKey, &corev1.PersistentVolumeClaim{})).To(Succeed())
+			Expect(reconciler.client.Get(context.TODO(), dvKey, dv)).To(Succeed())
+			boundCondition = FindConditionByType(cdiv1.DataVolumeBound, dv.Status.Conditions)
+			Expect(boundCondition.Reason).ToNot(Equal(IncompleteStorageProfile))
+			Expect(isWaitingForStorageProfile(dv)).To(BeFalse())
+		})
+
+		It("Should keep failing on an incomplete storageProfile once the PVC exists", func() {
+			scName := "testStorageClass"
+			importDataVolume := newImportDataVolumeWithPvc("test-dv", nil)
+			importDataVolume.Spec.Storage = &cdiv1.StorageSpec{
+				StorageClassName: &scName,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("1G"),
+					},
+				},
+			}
+			storageClass := CreateStorageClass(scName, map[string]string{AnnDefaultStorageClass: "true"})
+			storageProfile := createStorageProfile(scName, nil, FilesystemMode)
+			isController := true
+			pvc := CreatePvcInStorageClass("test-dv", metav1.NamespaceDefault, &scName, nil, nil, corev1.ClaimBound)
+			pvc.OwnerReferences = []metav1.OwnerReference{{Kind: "DataVolume", Name: "test-dv", UID: importDataVolume.UID, Controller: &isController}}
+			reconciler = createImportReconciler(storageClass, storageProfile, importDataVolume, pvc)
+
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("has no access modes"))
+		})
+
 		DescribeTable("Should set params on a PVC from storageProfile when import DV has no accessMode and no volume mode", func(contentType cdiv1.DataVolumeContentType) {
 			scName := "testStorageClass"
 			importDataVolume := newImportDataVolumeWithPvc("test-dv", nil)
@@ -461,6 +717,114 @@ var _ = Describe("All DataVolume Tests", func() {
 			Expect(pvc.Spec.Resources.Requests.Storage().Value()).To(Equal(expectedSize.Value()))
 		})
 
+		It("Should use the preallocation and filesystem overhead recommended by the storageProfile", func() {
+			cdiConfig := MakeEmptyCDIConfigSpec(common.ConfigName)
+			cdiConfig.Status = cdiv1.CDIConfigStatus{
+				FilesystemOverhead: &cdiv1.FilesystemOverhead{
+					Global: cdiv1.Percent("0"),
+				},
+			}
+
+			scName := "testStorageClass"
+			importDataVolume := newImportDataVolumeWithPvc("test-dv", nil)
+			importDataVolume.Spec.Storage = &cdiv1.StorageSpec{
+				StorageClassName: &scName,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("1G"),
+					},
+				},
+			}
+
+			preallocation := true
+			overhead := cdiv1.Percent("0.5")
+			storageClass := CreateStorageClass(scName, nil)
+			storageProfile := createStorageProfile(scName, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}, FilesystemMode)
+			storageProfile.Status.Preallocation = &preallocation
+			storageProfile.Status.FilesystemOverhead = &overhead
+
+			reconciler = createImportReconcilerWithoutConfig(storageClass, storageProfile, importDataVolume, cdiConfig)
+
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.Annotations[AnnPreallocationRequested]).To(Equal("true"))
+			requestedSize := resource.MustParse("1G")
+			expectedSize := GetRequiredSpace(0.5, requestedSize.Value())
+			Expect(pvc.Spec.Resources.Requests.Storage().Value()).To(Equal(expectedSize))
+		})
+
+		It("Should prefer the DataVolume preallocation over the storageProfile one", func() {
+			scName := "testStorageClass"
+			importDataVolume := newImportDataVolumeWithPvc("test-dv", nil)
+			importDataVolume.Spec.Storage = &cdiv1.StorageSpec{
+				StorageClassName: &scName,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("1G"),
+					},
+				},
+			}
+			dvPreallocation := false
+			importDataVolume.Spec.Preallocation = &dvPreallocation
+
+			preallocation := true
+			storageClass := CreateStorageClass(scName, nil)
+			storageProfile := createStorageProfile(scName, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}, BlockMode)
+			storageProfile.Status.Preallocation = &preallocation
+
+			reconciler = createImportReconciler(storageClass, storageProfile, importDataVolume)
+
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.Annotations[AnnPreallocationRequested]).To(Equal("false"))
+		})
+
+		DescribeTable("Should not preallocate on a thin provisioned storage class unless the DataVolume asks", func(dvPreallocation *bool, expected string) {
+			scName := "testStorageClass"
+			importDataVolume := newImportDataVolumeWithPvc("test-dv", nil)
+			importDataVolume.Spec.Storage = &cdiv1.StorageSpec{
+				StorageClassName: &scName,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("1G"),
+					},
+				},
+			}
+			importDataVolume.Spec.Preallocation = dvPreallocation
+
+			cdiConfig := MakeEmptyCDIConfigSpec(common.ConfigName)
+			cdiConfig.Status = cdiv1.CDIConfigStatus{
+				Preallocation: true,
+				FilesystemOverhead: &cdiv1.FilesystemOverhead{
+					Global: cdiv1.Percent("0"),
+				},
+			}
+			thinProvisioned := true
+			preallocation := false
+			storageClass := CreateStorageClass(scName, nil)
+			storageProfile := createStorageProfile(scName, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}, BlockMode)
+			storageProfile.Status.ThinProvisioned = &thinProvisioned
+			storageProfile.Status.Preallocation = &preallocation
+
+			reconciler = createImportReconcilerWithoutConfig(storageClass, storageProfile, importDataVolume, cdiConfig)
+
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.Annotations[AnnPreallocationRequested]).To(Equal(expected))
+		},
+			Entry("by default", nil, "false"),
+			Entry("when the DataVolume asks", &[]bool{true}[0], "true"),
+		)
+
 		It("Should pass annotations and labels from DV to created PVC", func() {
 			dv := NewImportDataVolume("test-dv")
 			dv.SetAnnotations(make(map[string]string))
@@ -508,6 +872,174 @@ var _ = Describe("All DataVolume Tests", func() {
 			Expect(pvc.GetAnnotations()[AnnPriorityClassName]).To(Equal("p0-s3"))
 		})
 
+		It("Should pass the worker pod placement of a DV to the created PVC", func() {
+			dv := NewImportDataVolume("test-dv")
+			dv.Spec.WorkerPodPlacement = &cdiv1.WorkerPodPlacement{
+				NodeSelector: map[string]string{"disktype": "ssd"},
+				Tolerations:  []corev1.Toleration{{Key: "storage", Operator: corev1.TolerationOpExists}},
+			}
+			reconciler = createImportReconciler(dv)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.GetAnnotations()[AnnWorkerPodPlacement]).To(Equal(`{"nodeSelector":{"disktype":"ssd"},"tolerations":[{"key":"storage","operator":"Exists"}]}`))
+		})
+
+		It("Should pass the worker pod image of a DV to the created PVC", func() {
+			dv := NewImportDataVolume("test-dv")
+			dv.Spec.WorkerPodImage = &cdiv1.WorkerPodImage{Registry: "mirror.example.com/kubevirt", PullPolicy: corev1.PullIfNotPresent}
+			reconciler = createImportReconciler(dv)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.GetAnnotations()[AnnWorkerPodImage]).To(Equal(`{"registry":"mirror.example.com/kubevirt","pullPolicy":"IfNotPresent"}`))
+		})
+
+		It("Should pass the token credentials of a DV with S3 source to the created PVC", func() {
+			dv := newS3ImportDataVolume("test-dv")
+			dv.Spec.Source.S3.TokenCredentials = &cdiv1.DataVolumeSourceTokenCredentials{RoleARN: "arn:aws:iam::123456789012:role/importer"}
+			reconciler = createImportReconciler(dv)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.GetAnnotations()[AnnTokenRoleARN]).To(Equal("arn:aws:iam::123456789012:role/importer"))
+			Expect(pvc.GetAnnotations()[AnnTokenAudience]).To(Equal(common.DefaultS3TokenAudience))
+			Expect(pvc.GetAnnotations()).ToNot(HaveKey(AnnSecret))
+		})
+
+		It("Should pass the KMS key id of a DV with S3 source to the created PVC", func() {
+			dv := NewImportDataVolume("test-dv")
+			dv.Spec.Source = &cdiv1.DataVolumeSource{
+				S3: &cdiv1.DataVolumeSourceS3{URL: "http://s3.amazonaws.com/bucket/disk.img", KMSKeyID: "1234abcd-12ab-34cd-56ef-1234567890ab"},
+			}
+			reconciler = createImportReconciler(dv)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.GetAnnotations()[AnnSource]).To(Equal(SourceS3))
+			Expect(pvc.GetAnnotations()[AnnS3KMSKeyID]).To(Equal("1234abcd-12ab-34cd-56ef-1234567890ab"))
+		})
+
+		It("Should pass the user project of a DV with GCS source to the created PVC", func() {
+			dv := NewImportDataVolume("test-dv")
+			dv.Spec.Source = &cdiv1.DataVolumeSource{
+				GCS: &cdiv1.DataVolumeSourceGCS{URL: "gs://bucket/disk.img", UserProject: "billing-project"},
+			}
+			reconciler = createImportReconciler(dv)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.GetAnnotations()[AnnSource]).To(Equal(SourceGCS))
+			Expect(pvc.GetAnnotations()[AnnGcsUserProject]).To(Equal("billing-project"))
+			Expect(pvc.GetAnnotations()).ToNot(HaveKey(AnnGcsGeneration))
+		})
+
+		It("Should pass the object generation of a DV with GCS source to the created PVC", func() {
+			dv := NewImportDataVolume("test-dv")
+			dv.Spec.Source = &cdiv1.DataVolumeSource{
+				GCS: &cdiv1.DataVolumeSourceGCS{URL: "gs://bucket/disk.img", Generation: 1686562345678901},
+			}
+			reconciler = createImportReconciler(dv)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.GetAnnotations()[AnnGcsGeneration]).To(Equal("1686562345678901"))
+		})
+
+		It("Should pass the URL and secret of a DV with NBD source to the created PVC", func() {
+			dv := NewImportDataVolume("test-dv")
+			dv.Spec.Source = &cdiv1.DataVolumeSource{
+				NBD: &cdiv1.DataVolumeSourceNBD{URL: "nbds://nbd.example.com/disk0", SecretRef: "nbd-tls"},
+			}
+			reconciler = createImportReconciler(dv)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.GetAnnotations()[AnnSource]).To(Equal(SourceNBD))
+			Expect(pvc.GetAnnotations()[AnnEndpoint]).To(Equal("nbds://nbd.example.com/disk0"))
+			Expect(pvc.GetAnnotations()[AnnSecret]).To(Equal("nbd-tls"))
+		})
+
+		It("Should pass the URL, token secret and CA of a DV with remote source to the created PVC", func() {
+			dv := newRemoteCloneDataVolume("test-dv")
+			dv.Spec.Source.Remote.CertConfigMap = "hub-ca"
+			reconciler = createImportReconciler(dv)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.GetAnnotations()[AnnSource]).To(Equal(SourceRemote))
+			Expect(pvc.GetAnnotations()[AnnEndpoint]).To(Equal("https://hub.example.com/volumes/golden/disk.img"))
+			Expect(pvc.GetAnnotations()[AnnSecret]).To(Equal("hub-token"))
+			Expect(pvc.GetAnnotations()[AnnCertConfigMap]).To(Equal("hub-ca"))
+		})
+
+		It("Should pass the URL, secret and CA of a DV with FTP source to the created PVC", func() {
+			dv := NewImportDataVolume("test-dv")
+			dv.Spec.Source = &cdiv1.DataVolumeSource{
+				FTP: &cdiv1.DataVolumeSourceFTP{URL: "ftps://ftp.example.com/images/disk.qcow2", SecretRef: "ftp-login", CertConfigMap: "ftp-ca"},
+			}
+			reconciler = createImportReconciler(dv)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.GetAnnotations()[AnnSource]).To(Equal(SourceFTP))
+			Expect(pvc.GetAnnotations()[AnnEndpoint]).To(Equal("ftps://ftp.example.com/images/disk.qcow2"))
+			Expect(pvc.GetAnnotations()[AnnSecret]).To(Equal("ftp-login"))
+			Expect(pvc.GetAnnotations()[AnnCertConfigMap]).To(Equal("ftp-ca"))
+		})
+
+		It("Should pass the URL, secret and CA of a DV with Azure source to the created PVC", func() {
+			dv := NewImportDataVolume("test-dv")
+			dv.Spec.Source = &cdiv1.DataVolumeSource{
+				Azure: &cdiv1.DataVolumeSourceAzure{URL: "https://account.blob.core.windows.net/vhds/disk.vhd", SecretRef: "azure-sas", CertConfigMap: "azure-ca"},
+			}
+			reconciler = createImportReconciler(dv)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.GetAnnotations()[AnnSource]).To(Equal(SourceAzure))
+			Expect(pvc.GetAnnotations()[AnnEndpoint]).To(Equal("https://account.blob.core.windows.net/vhds/disk.vhd"))
+			Expect(pvc.GetAnnotations()[AnnSecret]).To(Equal("azure-sas"))
+			Expect(pvc.GetAnnotations()[AnnCertConfigMap]).To(Equal("azure-ca"))
+		})
+
+		It("Should pass the URL, secret and host key of a DV with SFTP source to the created PVC", func() {
+			dv := NewImportDataVolume("test-dv")
+			dv.Spec.Source = &cdiv1.DataVolumeSource{
+				SFTP: &cdiv1.DataVolumeSourceSFTP{URL: "sftp://sftp.example.com/images/disk.qcow2", SecretRef: "sftp-creds", HostKey: "SHA256:V1/bfxdCuyghbClB77aalvbwiXjIS96trrcq/hG51CE"},
+			}
+			reconciler = createImportReconciler(dv)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.GetAnnotations()[AnnSource]).To(Equal(SourceSFTP))
+			Expect(pvc.GetAnnotations()[AnnEndpoint]).To(Equal("sftp://sftp.example.com/images/disk.qcow2"))
+			Expect(pvc.GetAnnotations()[AnnSecret]).To(Equal("sftp-creds"))
+			Expect(pvc.GetAnnotations()[AnnSFTPHostKey]).To(Equal("SHA256:V1/bfxdCuyghbClB77aalvbwiXjIS96trrcq/hG51CE"))
+		})
+
 		It("Should follow the phase of the created PVC", func() {
 			reconciler = createImportReconciler(NewImportDataVolume("test-dv"))
 			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
@@ -562,6 +1094,84 @@ var _ = Describe("All DataVolume Tests", func() {
 			Expect(dv.Status.RestartCount).To(Equal(int32(2)))
 		})
 
+		It("Should report the import timings recorded on the PVC", func() {
+			reconciler = createImportReconciler(NewImportDataVolume("test-dv"))
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+
+			dv := &cdiv1.DataVolume{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dv.Status.ImportTimings).To(BeNil())
+
+			pvc.Annotations[AnnImportDownloadSeconds] = "42"
+			pvc.Annotations[AnnImportConvertSeconds] = "7"
+			pvc.Annotations[AnnImportResizeSeconds] = "0"
+			err = reconciler.client.Update(context.TODO(), pvc)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+
+			dv = &cdiv1.DataVolume{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dv.Status.ImportTimings).To(Equal(&cdiv1.DataVolumeImportTimings{DownloadSeconds: 42, ConvertSeconds: 7}))
+		})
+
+		It("Should report an import passthrough recorded on the PVC", func() {
+			reconciler = createImportReconciler(NewImportDataVolume("test-dv"))
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+
+			dv := &cdiv1.DataVolume{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dv.Status.ImportPassthrough).To(BeFalse())
+
+			pvc.Annotations[AnnImportPassthrough] = "true"
+			err = reconciler.client.Update(context.TODO(), pvc)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+
+			dv = &cdiv1.DataVolume{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dv.Status.ImportPassthrough).To(BeTrue())
+		})
+
+		It("Should report the source digest recorded on the PVC", func() {
+			dv := NewImportDataVolume("test-dv")
+			dv.Annotations = map[string]string{AnnSourceDigestAlgorithm: "sha256"}
+			reconciler = createImportReconciler(dv)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.Annotations[AnnSourceDigestAlgorithm]).To(Equal("sha256"))
+
+			pvc.Annotations[AnnSourceDigest] = "sha256:a8dd75ecffd4cdd96072d60c2237b448e0c8b2bc94d57f10fdbc8c481d9005b8"
+			err = reconciler.client.Update(context.TODO(), pvc)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+
+			dv = &cdiv1.DataVolume{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dv.Status.ImportSourceDigest).To(Equal("sha256:a8dd75ecffd4cdd96072d60c2237b448e0c8b2bc94d57f10fdbc8c481d9005b8"))
+		})
+
 		It("Should error if a PVC with same name already exists that is not owned by us", func() {
 			reconciler = createImportReconciler(CreatePvc("test-dv", metav1.NamespaceDefault, map[string]string{}, nil), NewImportDataVolume("test-dv"))
 			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
@@ -593,6 +1203,32 @@ var _ = Describe("All DataVolume Tests", func() {
 			Expect(string(dv.Status.Progress)).To(Equal("N/A"))
 		})
 
+		DescribeTable("Should expand the bound PVC to the requested size of the DataVolume", func(generation int64, size, expectedSize string) {
+			dv := newImportDataVolumeWithPvc("test-dv", &corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse(size),
+					},
+				},
+			})
+			dv.Generation = generation
+			isController := true
+			pvc := CreatePvc("test-dv", metav1.NamespaceDefault, map[string]string{}, nil)
+			pvc.OwnerReferences = []metav1.OwnerReference{{Kind: "DataVolume", Name: "test-dv", UID: dv.UID, Controller: &isController}}
+			reconciler = createImportReconciler(pvc, dv)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.Spec.Resources.Requests.Storage().Cmp(resource.MustParse(expectedSize))).To(Equal(0))
+		},
+			Entry("when the DataVolume grew", int64(2), "2G", "2G"),
+			Entry("not when the DataVolume requests less than the PVC", int64(2), "500M", "1G"),
+			Entry("not when the DataVolume spec was not updated", int64(1), "2G", "1G"),
+		)
+
 		It("Should set multistage migration annotations on a newly created PVC", func() {
 			dv := NewImportDataVolume("test-dv")
 			dv.Spec.Checkpoints = []cdiv1.DataVolumeCheckpoint{
@@ -786,18 +1422,99 @@ var _ = Describe("All DataVolume Tests", func() {
 			Expect(newDv.GetAnnotations()[AnnVddkVersion]).To(Equal("1.3.4"))
 		})
 
-		It("Should add VDDK image URL to PVC", func() {
-			dv := newVDDKDataVolume("test-dv")
-			dv.Spec.Source.VDDK.InitImageURL = "test://image"
-			reconciler = createImportReconciler(dv)
+		It("Should add VDDK image URL to PVC", func() {
+			dv := newVDDKDataVolume("test-dv")
+			dv.Spec.Source.VDDK.InitImageURL = "test://image"
+			reconciler = createImportReconciler(dv)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc).ToNot(BeNil())
+			Expect(pvc.GetAnnotations()[AnnVddkInitImageURL]).To(Equal("test://image"))
+		})
+
+		It("Should adopt an existing empty PVC, if the DV requests it", func() {
+			dv := NewImportDataVolume("test-dv")
+			AddAnnotation(dv, AnnAdoptPVC, "true")
+			existingPvc := CreatePvc("test-dv", metav1.NamespaceDefault, map[string]string{"user-annotation": "value"}, nil)
+			reconciler = createImportReconciler(dv, existingPvc)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.UID).To(Equal(existingPvc.UID))
+			Expect(metav1.IsControlledBy(pvc, dv)).To(BeTrue())
+			Expect(pvc.GetAnnotations()[AnnEndpoint]).To(Equal("http://example.com/data"))
+			Expect(pvc.GetAnnotations()[AnnSource]).To(Equal(SourceHTTP))
+			Expect(pvc.GetAnnotations()["user-annotation"]).To(Equal("value"))
+			Expect(pvc.Labels[common.CDILabelKey]).To(Equal(common.CDILabelValue))
+			Expect(pvc.GetAnnotations()[AnnAdoptedPVC]).To(Equal("true"))
+			Expect(<-reconciler.recorder.(*record.FakeRecorder).Events).To(ContainSubstring(PVCAdopted))
+		})
+
+		It("Should refuse to adopt an existing PVC used by a pod", func() {
+			dv := NewImportDataVolume("test-dv")
+			AddAnnotation(dv, AnnAdoptPVC, "true")
+			existingPvc := CreatePvc("test-dv", metav1.NamespaceDefault, map[string]string{}, nil)
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "workload", Namespace: metav1.NamespaceDefault},
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{{
+						Name:         "data",
+						VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "test-dv", ReadOnly: true}},
+					}},
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning},
+			}
+			reconciler = createImportReconciler(dv, existingPvc, pod)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("PVC is used by pod workload"))
+			Expect(<-reconciler.recorder.(*record.FakeRecorder).Events).To(ContainSubstring(ErrUnableToAdoptPVC))
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(metav1.IsControlledBy(pvc, dv)).To(BeFalse())
+			Expect(pvc.GetAnnotations()).ToNot(HaveKey(AnnAdoptedPVC))
+		})
+
+		DescribeTable("Should refuse to adopt an existing PVC", func(modifyDv func(*cdiv1.DataVolume), modifyPvc func(*corev1.PersistentVolumeClaim), expectedMessage string) {
+			dv := NewImportDataVolume("test-dv")
+			AddAnnotation(dv, AnnAdoptPVC, "true")
+			modifyDv(dv)
+			existingPvc := CreatePvc("test-dv", metav1.NamespaceDefault, map[string]string{}, nil)
+			modifyPvc(existingPvc)
+			reconciler = createImportReconciler(dv, existingPvc)
 			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
-			Expect(err).ToNot(HaveOccurred())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(expectedMessage))
+			Expect(<-reconciler.recorder.(*record.FakeRecorder).Events).To(ContainSubstring(ErrUnableToAdoptPVC))
 			pvc := &corev1.PersistentVolumeClaim{}
 			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(pvc).ToNot(BeNil())
-			Expect(pvc.GetAnnotations()[AnnVddkInitImageURL]).To(Equal("test://image"))
-		})
+			Expect(metav1.IsControlledBy(pvc, dv)).To(BeFalse())
+			Expect(pvc.GetAnnotations()).ToNot(HaveKey(AnnEndpoint))
+		},
+			Entry("with a different volume mode", func(dv *cdiv1.DataVolume) {
+				dv.Spec.PVC.VolumeMode = &BlockMode
+			}, func(pvc *corev1.PersistentVolumeClaim) {}, "does not match the requested volume mode"),
+			Entry("smaller than requested", func(dv *cdiv1.DataVolume) {
+				dv.Spec.PVC.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("2G")}
+			}, func(pvc *corev1.PersistentVolumeClaim) {}, "is smaller than the requested size"),
+			Entry("already populated by CDI", func(dv *cdiv1.DataVolume) {}, func(pvc *corev1.PersistentVolumeClaim) {
+				pvc.Annotations[AnnPodPhase] = string(corev1.PodSucceeded)
+			}, "PVC already contains data"),
+			Entry("populated from a data source", func(dv *cdiv1.DataVolume) {}, func(pvc *corev1.PersistentVolumeClaim) {
+				pvc.Spec.DataSource = &corev1.TypedLocalObjectReference{Kind: "PersistentVolumeClaim", Name: "source"}
+			}, "PVC already contains data from its data source"),
+			Entry("controlled by another resource", func(dv *cdiv1.DataVolume) {}, func(pvc *corev1.PersistentVolumeClaim) {
+				isController := true
+				pvc.OwnerReferences = []metav1.OwnerReference{{Kind: "DataVolume", Name: "other-dv", UID: "other-uid", Controller: &isController}}
+			}, "PVC is controlled by DataVolume other-dv"),
+		)
 	})
 
 	var _ = Describe("Reconcile Datavolume status", func() {
@@ -924,6 +1641,35 @@ var _ = Describe("All DataVolume Tests", func() {
 			Expect(found).To(BeTrue())
 		})
 
+		It("Should not set DV phase to WaitForFirstConsumer if the DV is pinned to a topology", func() {
+			sc := createStorageClassWithBindingMode("default_test_sc",
+				map[string]string{
+					AnnDefaultStorageClass: "true",
+				},
+				storagev1.VolumeBindingWaitForFirstConsumer)
+			importDataVolume := NewImportDataVolume("test-dv")
+			importDataVolume.Annotations = map[string]string{AnnTopology: "topology.kubernetes.io/zone=us-east-1a"}
+			reconciler = createImportReconciler(sc, importDataVolume)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			dv := &cdiv1.DataVolume{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+			Expect(err).ToNot(HaveOccurred())
+
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			pvc.Status.Phase = corev1.ClaimPending
+			err = reconciler.client.Update(context.TODO(), pvc)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = reconciler.updateStatus(getReconcileRequest(dv), nil, reconciler)
+			Expect(err).ToNot(HaveOccurred())
+			dv = &cdiv1.DataVolume{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dv.Status.Phase).To(Equal(cdiv1.Pending))
+		})
+
 		It("Should set DV phase to WaitForFirstConsumer if storage class on PVC is WFFC", func() {
 			scName := "pvc_sc_wffc"
 			scDefault := CreateStorageClass("default_test_sc", map[string]string{
@@ -1077,13 +1823,439 @@ var _ = Describe("All DataVolume Tests", func() {
 			Entry("should stay the same for import after pod fails", NewImportDataVolume("test-dv"), cdiv1.Pending, cdiv1.ImportScheduled, corev1.ClaimBound, corev1.PodFailed, AnnImportPod, "Failed to import into PVC test-dv", AnnPriorityClassName, "p0"),
 			Entry("should switch to failed on claim lost for impot", NewImportDataVolume("test-dv"), cdiv1.Pending, cdiv1.Failed, corev1.ClaimLost, corev1.PodFailed, AnnImportPod, "PVC test-dv lost", AnnPriorityClassName, "p0"),
 			Entry("should switch to succeeded for import", NewImportDataVolume("test-dv"), cdiv1.Pending, cdiv1.Succeeded, corev1.ClaimBound, corev1.PodSucceeded, AnnImportPod, "Successfully imported into PVC test-dv", AnnPriorityClassName, "p0"),
+			Entry("should switch to failed for import after the last attempt fails", NewImportDataVolume("test-dv"), cdiv1.ImportInProgress, cdiv1.Failed, corev1.ClaimBound, corev1.PodFailed, AnnImportPod, "Import failed after 3 attempts: Unable to connect", AnnImportAttemptsExhausted, "true", AnnRunningConditionReason, "ImportAttemptsExhausted", AnnRunningConditionMessage, "Import failed after 3 attempts: Unable to connect"),
+			Entry("should warn when the scratch space of the import ran out", NewImportDataVolume("test-dv"), cdiv1.ImportInProgress, cdiv1.ImportInProgress, corev1.ClaimBound, corev1.PodRunning, AnnImportPod, "Warning ScratchSpaceExhausted Scratch space exhausted, scratch space size 1Gi, needed 2Gi", AnnRunningConditionReason, ScratchSpaceExhausted, AnnRunningConditionMessage, "Scratch space exhausted, scratch space size 1Gi, needed 2Gi: no space left on device"),
 			Entry("should switch to scheduled for blank", newBlankImageDataVolume("test-dv"), cdiv1.Pending, cdiv1.ImportScheduled, corev1.ClaimBound, corev1.PodPending, AnnImportPod, "Import into test-dv scheduled", AnnPriorityClassName, "p0-upload"),
 			Entry("should switch to inprogress for blank", newBlankImageDataVolume("test-dv"), cdiv1.Pending, cdiv1.ImportInProgress, corev1.ClaimBound, corev1.PodRunning, AnnImportPod, "Import into test-dv in progress"),
 			Entry("should stay the same for blank after pod fails", newBlankImageDataVolume("test-dv"), cdiv1.Pending, cdiv1.ImportScheduled, corev1.ClaimBound, corev1.PodFailed, AnnImportPod, "Failed to import into PVC test-dv"),
 			Entry("should switch to failed on claim lost for blank", newBlankImageDataVolume("test-dv"), cdiv1.Pending, cdiv1.Failed, corev1.ClaimLost, corev1.PodFailed, AnnImportPod, "PVC test-dv lost"),
 			Entry("should switch to succeeded for blank", newBlankImageDataVolume("test-dv"), cdiv1.Pending, cdiv1.Succeeded, corev1.ClaimBound, corev1.PodSucceeded, AnnImportPod, "Successfully imported into PVC test-dv"),
+			Entry("should switch to clone scheduled for remote clone", newRemoteCloneDataVolume("test-dv"), cdiv1.Pending, cdiv1.CloneScheduled, corev1.ClaimBound, corev1.PodPending, AnnImportPod, "Cloning from https://hub.example.com/volumes/golden/disk.img into test-dv scheduled"),
+			Entry("should switch to clone inprogress for remote clone", newRemoteCloneDataVolume("test-dv"), cdiv1.Pending, cdiv1.CloneInProgress, corev1.ClaimBound, corev1.PodRunning, AnnImportPod, "Cloning from https://hub.example.com/volumes/golden/disk.img into test-dv in progress"),
+			Entry("should stay the same for remote clone after pod fails", newRemoteCloneDataVolume("test-dv"), cdiv1.Pending, cdiv1.CloneScheduled, corev1.ClaimBound, corev1.PodFailed, AnnImportPod, "Failed to clone from https://hub.example.com/volumes/golden/disk.img into PVC test-dv"),
+			Entry("should switch to succeeded for remote clone", newRemoteCloneDataVolume("test-dv"), cdiv1.Pending, cdiv1.Succeeded, corev1.ClaimBound, corev1.PodSucceeded, AnnImportPod, "Successfully cloned from https://hub.example.com/volumes/golden/disk.img into PVC test-dv"),
+		)
+	})
+	var _ = Describe("Reset import attempts on progress", func() {
+		DescribeTable("Should forgive the failed attempts", func(previous, current cdiv1.DataVolumeProgress, restarts int32, base, expectedBase string) {
+			pvc := CreatePvc("test-dv", metav1.NamespaceDefault, map[string]string{AnnImportPod: "importer-test-dv"}, nil)
+			if base != "" {
+				pvc.Annotations[AnnImportAttemptsBase] = base
+			}
+			pod := &corev1.Pod{
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{{RestartCount: restarts}},
+				},
+			}
+			reconciler = createImportReconciler(pvc)
+			err := reconciler.resetImportAttemptsOnProgress(pvc, pod, previous, current)
+			Expect(err).ToNot(HaveOccurred())
+			resPvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, resPvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resPvc.Annotations[AnnImportAttemptsBase]).To(Equal(expectedBase))
+		},
+			Entry("when the import makes progress", cdiv1.DataVolumeProgress("N/A"), cdiv1.DataVolumeProgress("12.50%"), int32(2), "", "2"),
+			Entry("made since the last progress", cdiv1.DataVolumeProgress("12.50%"), cdiv1.DataVolumeProgress("20.00%"), int32(4), "2", "4"),
+			Entry("not without progress", cdiv1.DataVolumeProgress("12.50%"), cdiv1.DataVolumeProgress("12.50%"), int32(2), "", ""),
+			Entry("not without new failed attempts", cdiv1.DataVolumeProgress("12.50%"), cdiv1.DataVolumeProgress("20.00%"), int32(2), "2", "2"),
+		)
+	})
+
+	var _ = Describe("Populated PVC verification", func() {
+		getVerifiedState := func(dv *cdiv1.DataVolume) (*cdiv1.DataVolumeCondition, string) {
+			err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: dv.Name, Namespace: dv.Namespace}, dv)
+			Expect(err).ToNot(HaveOccurred())
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: dv.Name, Namespace: dv.Namespace}, pvc)
+			if k8serrors.IsNotFound(err) {
+				return FindConditionByType(cdiv1.DataVolumeVerified, dv.Status.Conditions), ""
+			}
+			Expect(err).ToNot(HaveOccurred())
+			return FindConditionByType(cdiv1.DataVolumeVerified, dv.Status.Conditions), pvc.Annotations[AnnPopulatedVerified]
+		}
+
+		DescribeTable("Should mark the PVC as verified only after a successful import", func(verify string, pvcAnnotations map[string]string, podPhase corev1.PodPhase, capacity string, expectedStatus corev1.ConditionStatus, expectedReason string) {
+			dv := NewImportDataVolume("test-dv")
+			AddAnnotation(dv, AnnVerifyPopulated, verify)
+			AddAnnotation(dv, AnnVerifyPopulatedDigest, "sha256:"+strings.Repeat("ab", 32))
+			dv.Spec.PVC.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1G")}
+			reconciler = createImportReconciler(dv)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			pvc.Status.Phase = corev1.ClaimBound
+			pvc.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(capacity)}
+			pvc.GetAnnotations()[AnnPodPhase] = string(podPhase)
+			for key, value := range pvcAnnotations {
+				pvc.GetAnnotations()[key] = value
+			}
+			err = reconciler.client.Update(context.TODO(), pvc)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = reconciler.updateStatus(getReconcileRequest(dv), nil, reconciler)
+			Expect(err).ToNot(HaveOccurred())
+
+			condition, verifiedFor := getVerifiedState(dv)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(expectedStatus))
+			Expect(condition.Reason).To(Equal(expectedReason))
+			if expectedStatus == corev1.ConditionTrue {
+				Expect(dv.Status.Phase).To(Equal(cdiv1.Succeeded))
+				Expect(verifiedFor).To(Equal("test-dv"))
+			} else {
+				Expect(verifiedFor).To(BeEmpty())
+			}
+		},
+			Entry("not while the import is running", "true", nil, corev1.PodRunning, "1G", corev1.ConditionFalse, populationPending),
+			Entry("once the import succeeded", "true", nil, corev1.PodSucceeded, "1G", corev1.ConditionTrue, populatedVerified),
+			Entry("once the import succeeded, with an image fitting the PVC", "size", map[string]string{AnnImageVirtualSize: "1000000000"}, corev1.PodSucceeded, "1G", corev1.ConditionTrue, populatedVerified),
+			Entry("not without the image virtual size", "size", nil, corev1.PodSucceeded, "1G", corev1.ConditionFalse, populatedVerificationError),
+			Entry("not if the image is larger than the PVC", "size", map[string]string{AnnImageVirtualSize: "2000000000"}, corev1.PodSucceeded, "1G", corev1.ConditionFalse, populatedVerificationError),
+			Entry("once the import succeeded, with the expected digest", "digest", map[string]string{AnnSourceDigest: "sha256:" + strings.Repeat("AB", 32)}, corev1.PodSucceeded, "1G", corev1.ConditionTrue, populatedVerified),
+			Entry("not without the source digest", "digest", nil, corev1.PodSucceeded, "1G", corev1.ConditionFalse, populatedVerificationError),
+			Entry("not with another source digest", "digest", map[string]string{AnnSourceDigest: "sha256:" + strings.Repeat("cd", 32)}, corev1.PodSucceeded, "1G", corev1.ConditionFalse, populatedVerificationError),
 		)
+
+		It("Should mark a prePopulated PVC as verified only once it is populated", func() {
+			dv := NewImportDataVolume("test-dv")
+			AddAnnotation(dv, AnnPrePopulated, "test-dv")
+			AddAnnotation(dv, AnnVerifyPopulated, "true")
+			reconciler = createImportReconciler(dv)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			condition, verifiedFor := getVerifiedState(dv)
+			Expect(dv.Status.Phase).To(Equal(cdiv1.Pending))
+			Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+			Expect(verifiedFor).To(BeEmpty())
+
+			pvc := CreatePvc("test-dv", metav1.NamespaceDefault, map[string]string{AnnPopulatedFor: "test-dv"}, nil)
+			pvc.Status.Phase = corev1.ClaimBound
+			err = reconciler.client.Create(context.TODO(), pvc)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			condition, verifiedFor = getVerifiedState(dv)
+			Expect(dv.Status.Phase).To(Equal(cdiv1.Succeeded))
+			Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+			Expect(verifiedFor).To(Equal("test-dv"))
+		})
+
+		It("Should not verify the PVC unless requested", func() {
+			dv := NewImportDataVolume("test-dv")
+			pvc := CreatePvc("test-dv", metav1.NamespaceDefault, map[string]string{AnnPopulatedFor: "test-dv"}, nil)
+			pvc.Status.Phase = corev1.ClaimBound
+			reconciler = createImportReconciler(dv, pvc)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			condition, verifiedFor := getVerifiedState(dv)
+			Expect(dv.Status.Phase).To(Equal(cdiv1.Succeeded))
+			Expect(condition).To(BeNil())
+			Expect(verifiedFor).To(BeEmpty())
+		})
+	})
+
+	var _ = Describe("Reconcile Datavolume status with scratch space", func() {
+		updatePvc := func(podPhase corev1.PodPhase, boundCondition, reason string) {
+			pvc := &corev1.PersistentVolumeClaim{}
+			err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			pvc.Status.Phase = corev1.ClaimBound
+			AddAnnotation(pvc, AnnImportPod, "importer-test-dv")
+			AddAnnotation(pvc, AnnPodPhase, string(podPhase))
+			AddAnnotation(pvc, AnnBoundCondition, boundCondition)
+			AddAnnotation(pvc, AnnBoundConditionMessage, reason)
+			AddAnnotation(pvc, AnnBoundConditionReason, reason)
+			err = reconciler.client.Update(context.TODO(), pvc)
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		updateStatus := func() *cdiv1.DataVolume {
+			dv := &cdiv1.DataVolume{}
+			err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = reconciler.updateStatus(getReconcileRequest(dv), nil, reconciler)
+			Expect(err).ToNot(HaveOccurred())
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+			Expect(err).ToNot(HaveOccurred())
+			return dv
+		}
+
+		BeforeEach(func() {
+			reconciler = createImportReconciler(NewImportDataVolume("test-dv"))
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should report waiting for scratch space, and switch to in progress once it is bound", func() {
+			updatePvc(corev1.PodPending, "false", ScratchSpacePending)
+			dv := updateStatus()
+			Expect(dv.Status.Phase).To(Equal(cdiv1.ImportScheduled))
+			boundCondition := FindConditionByType(cdiv1.DataVolumeBound, dv.Status.Conditions)
+			Expect(boundCondition.Status).To(Equal(corev1.ConditionFalse))
+			Expect(boundCondition.Reason).To(Equal(ScratchSpacePending))
+
+			updatePvc(corev1.PodRunning, "true", "")
+			dv = updateStatus()
+			Expect(dv.Status.Phase).To(Equal(cdiv1.ImportInProgress))
+			boundCondition = FindConditionByType(cdiv1.DataVolumeBound, dv.Status.Conditions)
+			Expect(boundCondition.Status).To(Equal(corev1.ConditionTrue))
+			Expect(boundCondition.Reason).To(Equal("Bound"))
+		})
+
+		It("Should report scratch space lost", func() {
+			updatePvc(corev1.PodPending, "false", ScratchSpaceLost)
+			dv := updateStatus()
+			Expect(dv.Status.Phase).To(Equal(cdiv1.ImportScheduled))
+			boundCondition := FindConditionByType(cdiv1.DataVolumeBound, dv.Status.Conditions)
+			Expect(boundCondition.Status).To(Equal(corev1.ConditionFalse))
+			Expect(boundCondition.Reason).To(Equal(ScratchSpaceLost))
+			readyCondition := FindConditionByType(cdiv1.DataVolumeReady, dv.Status.Conditions)
+			Expect(readyCondition.Status).To(Equal(corev1.ConditionFalse))
+		})
+	})
+
+	var _ = Describe("Reconcile Datavolume status with a queued worker pod", func() {
+		BeforeEach(func() {
+			reconciler = createImportReconciler(NewImportDataVolume("test-dv"))
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should report the DataVolume pending while its worker pod is queued, and scheduled once it is admitted", func() {
+			pvc := &corev1.PersistentVolumeClaim{}
+			err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			pvc.Status.Phase = corev1.ClaimBound
+			AddAnnotation(pvc, AnnImportPod, "importer-test-dv")
+			AddAnnotation(pvc, AnnWorkerPodQueued, "true")
+			AddAnnotation(pvc, AnnRunningCondition, "false")
+			AddAnnotation(pvc, AnnRunningConditionReason, WorkerPodQueued)
+			AddAnnotation(pvc, AnnRunningConditionMessage, fmt.Sprintf(MessageWorkerPodSlotsInUse, 2))
+			err = reconciler.client.Update(context.TODO(), pvc)
+			Expect(err).ToNot(HaveOccurred())
+
+			dv := &cdiv1.DataVolume{}
+			_, err = reconciler.updateStatus(getReconcileRequest(NewImportDataVolume("test-dv")), nil, reconciler)
+			Expect(err).ToNot(HaveOccurred())
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dv.Status.Phase).To(Equal(cdiv1.Pending))
+			runningCondition := FindConditionByType(cdiv1.DataVolumeRunning, dv.Status.Conditions)
+			Expect(runningCondition.Status).To(Equal(corev1.ConditionFalse))
+			Expect(runningCondition.Reason).To(Equal(WorkerPodQueued))
+			Expect(runningCondition.Message).To(Equal(fmt.Sprintf(MessageWorkerPodSlotsInUse, 2)))
+
+			delete(pvc.Annotations, AnnWorkerPodQueued)
+			delete(pvc.Annotations, AnnRunningCondition)
+			delete(pvc.Annotations, AnnRunningConditionReason)
+			delete(pvc.Annotations, AnnRunningConditionMessage)
+			err = reconciler.client.Update(context.TODO(), pvc)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = reconciler.updateStatus(getReconcileRequest(dv), nil, reconciler)
+			Expect(err).ToNot(HaveOccurred())
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dv.Status.Phase).To(Equal(cdiv1.ImportScheduled))
+
+			close(reconciler.recorder.(*record.FakeRecorder).Events)
+			found := false
+			for event := range reconciler.recorder.(*record.FakeRecorder).Events {
+				if strings.Contains(event, WorkerPodQueued) {
+					found = true
+				}
+			}
+			Expect(found).To(BeTrue())
+		})
+	})
+
+	var _ = Describe("Cancel DataVolume", func() {
+		dvKey := types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}
+
+		reconcileDv := func() *cdiv1.DataVolume {
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: dvKey})
+			Expect(err).ToNot(HaveOccurred())
+			dv := &cdiv1.DataVolume{}
+			err = reconciler.client.Get(context.TODO(), dvKey, dv)
+			Expect(err).ToNot(HaveOccurred())
+			return dv
+		}
+
+		cancelDv := func() {
+			dv := &cdiv1.DataVolume{}
+			err := reconciler.client.Get(context.TODO(), dvKey, dv)
+			Expect(err).ToNot(HaveOccurred())
+			AddAnnotation(dv, AnnCancel, "true")
+			err = reconciler.client.Update(context.TODO(), dv)
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		updatePvc := func(podPhase corev1.PodPhase) *corev1.PersistentVolumeClaim {
+			pvc := &corev1.PersistentVolumeClaim{}
+			err := reconciler.client.Get(context.TODO(), dvKey, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			pvc.Status.Phase = corev1.ClaimBound
+			AddAnnotation(pvc, AnnImportPod, "importer-test-dv")
+			AddAnnotation(pvc, AnnPodPhase, string(podPhase))
+			err = reconciler.client.Update(context.TODO(), pvc)
+			Expect(err).ToNot(HaveOccurred())
+			return pvc
+		}
+
+		BeforeEach(func() {
+			reconciler = createImportReconciler(NewImportDataVolume("test-dv"))
+			reconcileDv()
+		})
+
+		AfterEach(func() {
+			if reconciler != nil && reconciler.recorder != nil {
+				close(reconciler.recorder.(*record.FakeRecorder).Events)
+			}
+		})
+
+		It("Should tear down an in progress import and move the DataVolume to Canceled", func() {
+			pvc := updatePvc(corev1.PodRunning)
+			isController := true
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "importer-test-dv",
+					Namespace: metav1.NamespaceDefault,
+					UID:       "importer-pod-uid",
+				},
+			}
+			err := reconciler.client.Create(context.TODO(), pod)
+			Expect(err).ToNot(HaveOccurred())
+			scratchPvc := CreatePvc("test-dv-scratch", metav1.NamespaceDefault, nil, nil)
+			scratchPvc.OwnerReferences = []metav1.OwnerReference{{
+				APIVersion: "v1",
+				Kind:       "Pod",
+				Name:       pod.Name,
+				UID:        pod.UID,
+				Controller: &isController,
+			}}
+			err = reconciler.client.Create(context.TODO(), scratchPvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(metav1.GetControllerOf(pvc).Kind).To(Equal("DataVolume"))
+
+			cancelDv()
+			dv := reconcileDv()
+			Expect(dv.Status.Phase).To(Equal(cdiv1.Canceled))
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace}, &corev1.Pod{})
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: scratchPvc.Name, Namespace: scratchPvc.Namespace}, &corev1.PersistentVolumeClaim{})
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			err = reconciler.client.Get(context.TODO(), dvKey, &corev1.PersistentVolumeClaim{})
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			condition := FindConditionByType(cdiv1.DataVolumeCanceled, dv.Status.Conditions)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(DataVolumeCanceled))
+			By("Reconciling the canceled DataVolume again")
+			dv = reconcileDv()
+			Expect(dv.Status.Phase).To(Equal(cdiv1.Canceled))
+			err = reconciler.client.Get(context.TODO(), dvKey, &corev1.PersistentVolumeClaim{})
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+
+			By("Checking events recorded")
+			close(reconciler.recorder.(*record.FakeRecorder).Events)
+			canceledEvents := 0
+			for event := range reconciler.recorder.(*record.FakeRecorder).Events {
+				if strings.Contains(event, fmt.Sprintf(MessageDataVolumeCanceled, "test-dv")) {
+					canceledEvents++
+				}
+			}
+			reconciler.recorder = nil
+			Expect(canceledEvents).To(Equal(1))
+		})
+
+		It("Should not move the DataVolume to Canceled until its PVC is deleted", func() {
+			pvc := updatePvc(corev1.PodRunning)
+			pvc.Finalizers = []string{"test/finalizer"}
+			err := reconciler.client.Update(context.TODO(), pvc)
+			Expect(err).ToNot(HaveOccurred())
+
+			cancelDv()
+			dv := reconcileDv()
+			Expect(dv.Status.Phase).ToNot(Equal(cdiv1.Canceled))
+			Expect(FindConditionByType(cdiv1.DataVolumeCanceled, dv.Status.Conditions)).To(BeNil())
+			pvc = &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), dvKey, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.DeletionTimestamp).ToNot(BeNil())
+
+			By("Removing the PVC finalizer")
+			pvc.Finalizers = nil
+			err = reconciler.client.Update(context.TODO(), pvc)
+			Expect(err).ToNot(HaveOccurred())
+			dv = reconcileDv()
+			Expect(dv.Status.Phase).To(Equal(cdiv1.Canceled))
+		})
+
+		It("Should keep an adopted PVC and only remove the owner reference", func() {
+			reconciler = createImportReconciler(NewImportDataVolume("test-dv"))
+			dv := &cdiv1.DataVolume{}
+			err := reconciler.client.Get(context.TODO(), dvKey, dv)
+			Expect(err).ToNot(HaveOccurred())
+			AddAnnotation(dv, AnnAdoptPVC, "true")
+			err = reconciler.client.Update(context.TODO(), dv)
+			Expect(err).ToNot(HaveOccurred())
+			isController := true
+			userOwner := metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "user-owner", UID: "user-owner-uid"}
+			existingPvc := CreatePvc("test-dv", metav1.NamespaceDefault, map[string]string{"user-annotation": "value"}, nil)
+			existingPvc.OwnerReferences = []metav1.OwnerReference{userOwner}
+			err = reconciler.client.Create(context.TODO(), existingPvc)
+			Expect(err).ToNot(HaveOccurred())
+			reconcileDv()
+			pvc := updatePvc(corev1.PodRunning)
+			Expect(pvc.UID).To(Equal(existingPvc.UID))
+			Expect(pvc.Annotations[AnnAdoptedPVC]).To(Equal("true"))
+			Expect(metav1.GetControllerOf(pvc).Kind).To(Equal("DataVolume"))
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "importer-test-dv",
+					Namespace:       metav1.NamespaceDefault,
+					OwnerReferences: []metav1.OwnerReference{{APIVersion: "v1", Kind: "PersistentVolumeClaim", Name: pvc.Name, UID: pvc.UID, Controller: &isController}},
+				},
+			}
+			err = reconciler.client.Create(context.TODO(), pod)
+			Expect(err).ToNot(HaveOccurred())
+
+			cancelDv()
+			dv = reconcileDv()
+			Expect(dv.Status.Phase).To(Equal(cdiv1.Canceled))
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace}, &corev1.Pod{})
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			pvc = &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), dvKey, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.DeletionTimestamp).To(BeNil())
+			Expect(pvc.UID).To(Equal(existingPvc.UID))
+			Expect(pvc.OwnerReferences).To(ConsistOf(userOwner))
+			Expect(pvc.Annotations[AnnCanceled]).To(Equal("true"))
+			Expect(pvc.Annotations["user-annotation"]).To(Equal("value"))
+
+			By("Reconciling the canceled DataVolume again")
+			dv = reconcileDv()
+			Expect(dv.Status.Phase).To(Equal(cdiv1.Canceled))
+			err = reconciler.client.Get(context.TODO(), dvKey, &corev1.PersistentVolumeClaim{})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should ignore the cancel of a completed DataVolume", func() {
+			updatePvc(corev1.PodSucceeded)
+			dv := reconcileDv()
+			Expect(dv.Status.Phase).To(Equal(cdiv1.Succeeded))
+
+			cancelDv()
+			dv = reconcileDv()
+			Expect(dv.Status.Phase).To(Equal(cdiv1.Succeeded))
+			err := reconciler.client.Get(context.TODO(), dvKey, &corev1.PersistentVolumeClaim{})
+			Expect(err).ToNot(HaveOccurred())
+		})
 	})
+
 	var _ = Describe("Get Pod from PVC", func() {
 		var (
 			pvc *corev1.PersistentVolumeClaim
@@ -1232,6 +2404,51 @@ var _ = Describe("All DataVolume Tests", func() {
 			Expect(err).ToNot(HaveOccurred())
 			Expect(dv.Status.Progress).To(BeEquivalentTo("2.3%"))
 		})
+
+		DescribeTable("Should update the converting condition from the conversion progress", func(metrics, expectedMessage string) {
+			dv.SetUID("b856691e-1038-11e9-a5ab-525500d15501")
+			ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(metrics))
+				w.WriteHeader(200)
+			}))
+			defer ts.Close()
+			ep, err := url.Parse(ts.URL)
+			Expect(err).ToNot(HaveOccurred())
+			port, err := strconv.Atoi(ep.Port())
+			Expect(err).ToNot(HaveOccurred())
+			pod.Spec.Containers[0].Ports[0].ContainerPort = int32(port)
+			pod.Status.PodIP = ep.Hostname()
+			err = updateProgressUsingPod(dv, pod)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dv.Status.Progress).To(BeEquivalentTo("13.45%"))
+			condition := FindConditionByType(cdiv1.DataVolumeConverting, dv.Status.Conditions)
+			if expectedMessage == "" {
+				Expect(condition).To(BeNil())
+				return
+			}
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(conversionInProgress))
+			Expect(condition.Message).To(Equal(expectedMessage))
+		},
+			Entry("with progress", "conversion_progress_percent{ownerUID=\"b856691e-1038-11e9-a5ab-525500d15501\"} 45.34\n"+
+				"import_progress{ownerUID=\"b856691e-1038-11e9-a5ab-525500d15501\"} 13.45\n", "Converting the image: 45.34%"),
+			Entry("without progress", "conversion_progress_percent{ownerUID=\"b856691e-1038-11e9-a5ab-525500d15501\"} -1\n"+
+				"import_progress{ownerUID=\"b856691e-1038-11e9-a5ab-525500d15501\"} 13.45\n", "Converting the image, qemu-img did not report progress yet"),
+			Entry("without conversion", "import_progress{ownerUID=\"b856691e-1038-11e9-a5ab-525500d15501\"} 13.45\n", ""),
+		)
+
+		DescribeTable("Should close the converting condition once the DataVolume is done", func(phase cdiv1.DataVolumePhase, expectedReason string) {
+			conditions := updateConvertingCondition(nil, 45.34)
+			conditions = updateConversionDoneCondition(conditions, phase)
+			condition := FindConditionByType(cdiv1.DataVolumeConverting, conditions)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(expectedReason))
+		},
+			Entry("succeeded", cdiv1.Succeeded, conversionComplete),
+			Entry("failed", cdiv1.Failed, conversionFailed),
+		)
 	})
 
 	const (
@@ -1432,11 +2649,12 @@ func createImportReconcilerWithoutConfig(objects ...runtime.Object) *ImportRecon
 	// Create a ReconcileMemcached object with the scheme and fake client.
 	r := &ImportReconciler{
 		ReconcilerBase: ReconcilerBase{
-			client:       cl,
-			scheme:       s,
-			log:          dvImportLog,
-			recorder:     rec,
-			featureGates: featuregates.NewFeatureGates(cl),
+			client:         cl,
+			uncachedClient: cl,
+			scheme:         s,
+			log:            dvImportLog,
+			recorder:       rec,
+			featureGates:   featuregates.NewFeatureGates(cl),
 			installerLabels: map[string]string{
 				common.AppKubernetesPartOfLabel:  "testing",
 				common.AppKubernetesVersionLabel: "v0.0.0-tests",
@@ -1524,6 +2742,14 @@ func newBlankImageDataVolume(name string) *cdiv1.DataVolume {
 	}
 }
 
+func newRemoteCloneDataVolume(name string) *cdiv1.DataVolume {
+	dv := NewImportDataVolume(name)
+	dv.Spec.Source = &cdiv1.DataVolumeSource{
+		Remote: &cdiv1.DataVolumeSourceRemote{URL: "https://hub.example.com/volumes/golden/disk.img", SecretRef: "hub-token"},
+	}
+	return dv
+}
+
 func newVDDKDataVolume(name string) *cdiv1.DataVolume {
 	return &cdiv1.DataVolume{
 		TypeMeta: metav1.TypeMeta{APIVersion: cdiv1.SchemeGroupVersion.String()},
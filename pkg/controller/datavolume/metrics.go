@@ -0,0 +1,121 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datavolume
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"kubevirt.io/containerized-data-importer/pkg/monitoring"
+)
+
+const (
+	prometheusControllerLabel = "controller"
+	prometheusResultLabel     = "result"
+
+	reconcileResultSuccess = "success"
+	reconcileResultRequeue = "requeue"
+	reconcileResultError   = "error"
+)
+
+var (
+	// DataVolumeReconcileDurationHistogram is the metric we use to attribute the DataVolume reconcile latency to the leader
+	DataVolumeReconcileDurationHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    monitoring.MetricOptsList[monitoring.DataVolumeReconcile].Name,
+			Help:    monitoring.MetricOptsList[monitoring.DataVolumeReconcile].Help,
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{prometheusControllerLabel, prometheusResultLabel},
+	)
+	// ControllerLeaderGauge is the metric we use to tell which controller replica is the active leader
+	ControllerLeaderGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: monitoring.MetricOptsList[monitoring.ControllerLeader].Name,
+			Help: monitoring.MetricOptsList[monitoring.ControllerLeader].Help,
+		})
+
+	// leading is set while the controller holds the leader election
+	leading int32
+)
+
+// leaderMetrics emits the leader metrics while the manager holds the leader election, and stops emitting them once the
+// manager stops, which it does when the leader election is lost. The leader gauge is only registered while leading, so
+// the other replicas don't export it.
+type leaderMetrics struct {
+	registerer prometheus.Registerer
+}
+
+// AddLeaderMetrics adds the runnable emitting the leader metrics to the manager
+func AddLeaderMetrics(mgr manager.Manager) error {
+	return mgr.Add(&leaderMetrics{registerer: metrics.Registry})
+}
+
+// NeedLeaderElection only starts the runnable on the leader
+func (*leaderMetrics) NeedLeaderElection() bool {
+	return true
+}
+
+// Start emits the leader metrics until the context is done
+func (m *leaderMetrics) Start(ctx context.Context) error {
+	if err := m.registerer.Register(ControllerLeaderGauge); err != nil {
+		if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+			return err
+		}
+	}
+	startLeading()
+	<-ctx.Done()
+	stopLeading()
+	m.registerer.Unregister(ControllerLeaderGauge)
+	return nil
+}
+
+func startLeading() {
+	atomic.StoreInt32(&leading, 1)
+	ControllerLeaderGauge.Set(1)
+}
+
+// stopLeading resets the metrics, the next leader reports them from scratch
+func stopLeading() {
+	atomic.StoreInt32(&leading, 0)
+	ControllerLeaderGauge.Set(0)
+	DataVolumeReconcileDurationHistogram.Reset()
+}
+
+func isLeading() bool {
+	return atomic.LoadInt32(&leading) == 1
+}
+
+// observeReconcileDuration records the duration of a DataVolume reconcile of the leader
+func observeReconcileDuration(controllerName string, start time.Time, res reconcile.Result, err error) {
+	if !isLeading() {
+		return
+	}
+	result := reconcileResultSuccess
+	if err != nil {
+		result = reconcileResultError
+	} else if res.Requeue || res.RequeueAfter > 0 {
+		result = reconcileResultRequeue
+	}
+	DataVolumeReconcileDurationHistogram.WithLabelValues(controllerName, result).Observe(time.Since(start).Seconds())
+}
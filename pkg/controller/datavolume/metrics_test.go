@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datavolume
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	. "kubevirt.io/containerized-data-importer/pkg/controller/common"
+	"kubevirt.io/containerized-data-importer/pkg/monitoring"
+)
+
+var _ = Describe("DataVolume controller leader metrics", func() {
+	dvKey := types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}
+
+	// reconcileSamples returns the number of reconcile durations observed, by controller and result
+	reconcileSamples := func() map[string]uint64 {
+		registry := prometheus.NewPedanticRegistry()
+		Expect(registry.Register(DataVolumeReconcileDurationHistogram)).To(Succeed())
+		families, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+		samples := map[string]uint64{}
+		for _, family := range families {
+			for _, metric := range family.GetMetric() {
+				labels := map[string]string{}
+				for _, label := range metric.GetLabel() {
+					labels[label.GetName()] = label.GetValue()
+				}
+				samples[labels[prometheusControllerLabel]+"/"+labels[prometheusResultLabel]] += metric.GetHistogram().GetSampleCount()
+			}
+		}
+		return samples
+	}
+
+	reconcileImport := func() string {
+		reconciler := createImportReconciler(NewImportDataVolume("test-dv"))
+		reconciler.controllerName = importControllerName
+		res, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: dvKey})
+		Expect(err).ToNot(HaveOccurred())
+		if res.Requeue || res.RequeueAfter > 0 {
+			return importControllerName + "/" + reconcileResultRequeue
+		}
+		return importControllerName + "/" + reconcileResultSuccess
+	}
+
+	// leaderGaugeExported returns true if the registry exports the leader gauge
+	leaderGaugeExported := func(registry *prometheus.Registry) bool {
+		families, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+		for _, family := range families {
+			if family.GetName() == monitoring.MetricOptsList[monitoring.ControllerLeader].Name {
+				return true
+			}
+		}
+		return false
+	}
+
+	AfterEach(func() {
+		stopLeading()
+	})
+
+	It("should observe the reconcile durations while leading, and stop once the leadership is lost", func() {
+		registry := prometheus.NewPedanticRegistry()
+		Expect(leaderGaugeExported(registry)).To(BeFalse())
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			Expect((&leaderMetrics{registerer: registry}).Start(ctx)).To(Succeed())
+			close(done)
+		}()
+		Eventually(func() float64 {
+			return testutil.ToFloat64(ControllerLeaderGauge)
+		}).Should(Equal(float64(1)))
+		Expect(leaderGaugeExported(registry)).To(BeTrue())
+
+		key := reconcileImport()
+		Expect(reconcileSamples()).To(Equal(map[string]uint64{key: 1}))
+		reconcileImport()
+		Expect(reconcileSamples()).To(HaveKeyWithValue(key, uint64(2)))
+
+		By("Losing the leadership")
+		cancel()
+		Eventually(done).Should(BeClosed())
+		Expect(testutil.ToFloat64(ControllerLeaderGauge)).To(Equal(float64(0)))
+		Expect(leaderGaugeExported(registry)).To(BeFalse())
+		Expect(reconcileSamples()).To(BeEmpty())
+
+		reconcileImport()
+		Expect(reconcileSamples()).To(BeEmpty())
+	})
+
+	It("should not observe the reconcile durations when not leading", func() {
+		reconcileImport()
+		Expect(reconcileSamples()).To(BeEmpty())
+		Expect(testutil.ToFloat64(ControllerLeaderGauge)).To(Equal(float64(0)))
+	})
+
+	It("should only start on the leader", func() {
+		Expect((&leaderMetrics{}).NeedLeaderElection()).To(BeTrue())
+	})
+})
@@ -60,6 +60,7 @@ const (
 	HostAssistedClone
 	SmartClone
 	CsiClone
+	SharedSnapshotClone
 )
 
 const pvcCloneControllerName = "datavolume-pvc-clone-controller"
@@ -96,8 +97,10 @@ func NewPvcCloneController(
 		CloneReconcilerBase: CloneReconcilerBase{
 			ReconcilerBase: ReconcilerBase{
 				client:          client,
+				uncachedClient:  mgr.GetAPIReader(),
 				scheme:          mgr.GetScheme(),
 				log:             log.WithName(pvcCloneControllerName),
+				controllerName:  pvcCloneControllerName,
 				featureGates:    featuregates.NewFeatureGates(client),
 				recorder:        mgr.GetEventRecorderFor(pvcCloneControllerName),
 				installerLabels: installerLabels,
@@ -122,6 +125,9 @@ func NewPvcCloneController(
 	if err := addDataVolumeCloneControllerWatches(mgr, dataVolumeCloneController); err != nil {
 		return nil, err
 	}
+	if err := addSharedSnapshotSourceController(mgr, log); err != nil {
+		return nil, err
+	}
 
 	mgr.Add(sccs)
 	return dataVolumeCloneController, nil
@@ -175,6 +181,10 @@ func addDataVolumeCloneControllerWatches(mgr manager.Manager, datavolumeControll
 		return err
 	}
 
+	if err := addSharedSnapshotWatch(mgr, datavolumeController); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -234,6 +244,7 @@ func (r *PvcCloneReconciler) prepare(syncState *dvSyncState) error {
 		if err := r.cleanup(syncState); err != nil {
 			return err
 		}
+		return r.resyncClone(syncState, r.log.WithValues("DataVolume", types.NamespacedName{Namespace: dv.Namespace, Name: dv.Name}))
 	}
 	return nil
 }
@@ -298,7 +309,7 @@ func (r *PvcCloneReconciler) syncClone(log logr.Logger, req reconcile.Request) (
 		return syncRes, nil
 	}
 
-	if selectedCloneStrategy == SmartClone {
+	if selectedCloneStrategy == SmartClone || selectedCloneStrategy == SharedSnapshotClone {
 		r.sccs.StartController()
 	}
 
@@ -325,6 +336,15 @@ func (r *PvcCloneReconciler) syncClone(log logr.Logger, req reconcile.Request) (
 	}
 
 	if pvc == nil {
+		if selectedCloneStrategy == HostAssistedClone {
+			sourcePvc, err := r.findSourcePvc(datavolume)
+			if err != nil {
+				return syncRes, err
+			}
+			if err := r.adjustConvertedCloneSize(&syncRes, sourcePvc); err != nil {
+				return syncRes, err
+			}
+		}
 		if selectedCloneStrategy == SmartClone {
 			snapshotClassName, err := r.getSnapshotClassForSmartClone(datavolume, pvcSpec)
 			if err != nil {
@@ -334,33 +354,36 @@ func (r *PvcCloneReconciler) syncClone(log logr.Logger, req reconcile.Request) (
 			syncRes.result = &res
 			return syncRes, err
 		}
+		if selectedCloneStrategy == SharedSnapshotClone {
+			snapshotClassName, err := r.getSnapshotClassForSmartClone(datavolume, pvcSpec)
+			if err != nil {
+				return syncRes, err
+			}
+			res, err := r.reconcileSharedSnapshotClonePvc(log, &syncRes, snapshotClassName)
+			syncRes.result = &res
+			return syncRes, err
+		}
 		if selectedCloneStrategy == CsiClone {
 			csiDriverAvailable, err := r.storageClassCSIDriverExists(pvcSpec.StorageClassName)
 			if err != nil && !k8serrors.IsNotFound(err) {
 				return syncRes, err
 			}
-			if !csiDriverAvailable {
-				// err csi clone not possible
-				storageClass, err := cc.GetStorageClassByName(r.client, pvcSpec.StorageClassName)
-				if err != nil {
-					return syncRes, err
-				}
-				noCsiDriverMsg := "CSI Clone configured, failed to look for CSIDriver - target storage class could not be found"
-				if storageClass != nil {
-					noCsiDriverMsg = fmt.Sprintf("CSI Clone configured, but no CSIDriver available for %s", storageClass.Name)
-				}
-				return syncRes,
-					r.syncDataVolumeStatusPhaseWithEvent(&syncRes, cdiv1.CloneScheduled, pvc,
-						Event{
-							eventType: corev1.EventTypeWarning,
-							reason:    ErrUnableToClone,
-							message:   noCsiDriverMsg,
-						})
+			if csiDriverAvailable {
+				res, err := r.reconcileCsiClonePvc(log, &syncRes, transferName)
+				syncRes.result = &res
+				return syncRes, err
 			}
-
-			res, err := r.reconcileCsiClonePvc(log, &syncRes, transferName)
-			syncRes.result = &res
-			return syncRes, err
+			// CSI clone not possible, the host assisted clone creates the PVC
+			storageClass, err := cc.GetStorageClassByName(r.client, pvcSpec.StorageClassName)
+			if err != nil {
+				return syncRes, err
+			}
+			noCsiDriverMsg := "CSI Clone configured, failed to look for CSIDriver - target storage class could not be found"
+			if storageClass != nil {
+				noCsiDriverMsg = fmt.Sprintf("CSI Clone configured, but no CSIDriver available for %s", storageClass.Name)
+			}
+			selectedCloneStrategy = r.hostAssistedCloneFallback(datavolume, noCsiDriverMsg)
+			cc.AddAnnotation(datavolume, annCloneType, cloneStrategyToCloneType(selectedCloneStrategy))
 		}
 
 		newPvc, err := r.createPvcForDatavolume(datavolume, pvcSpec, r.updateAnnotations)
@@ -376,6 +399,11 @@ func (r *PvcCloneReconciler) syncClone(log logr.Logger, req reconcile.Request) (
 			return syncRes, err
 		}
 		pvc = newPvc
+	} else if pvcNeedsAdoption(pvc, datavolume) {
+		if err := r.adoptPvc(&syncRes, r.updateAnnotations); err != nil {
+			return syncRes, err
+		}
+		pvc = syncRes.pvc
 	}
 
 	shouldBeMarkedWaitForFirstConsumer, err := r.shouldBeMarkedWaitForFirstConsumer(pvc)
@@ -409,7 +437,7 @@ func (r *PvcCloneReconciler) syncClone(log logr.Logger, req reconcile.Request) (
 					})
 		}
 		fallthrough
-	case SmartClone:
+	case SmartClone, SharedSnapshotClone:
 		if !shouldBeMarkedWaitForFirstConsumer {
 			res, err := r.finishClone(log, &syncRes, transferName)
 			syncRes.result = &res
@@ -422,7 +450,7 @@ func (r *PvcCloneReconciler) syncClone(log logr.Logger, req reconcile.Request) (
 }
 
 func (r *PvcCloneReconciler) selectCloneStrategy(datavolume *cdiv1.DataVolume, pvcSpec *corev1.PersistentVolumeClaimSpec) (cloneStrategy, error) {
-	preferredCloneStrategy, err := r.getCloneStrategy(datavolume)
+	preferredCloneStrategy, err := r.getCloneStrategy(datavolume, pvcSpec)
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
 			return NoClone, nil
@@ -430,6 +458,24 @@ func (r *PvcCloneReconciler) selectCloneStrategy(datavolume *cdiv1.DataVolume, p
 		return NoClone, err
 	}
 
+	// A clone that fell back to a host assisted clone keeps it
+	if cloneFellBack(datavolume) {
+		return HostAssistedClone, nil
+	}
+
+	// The adopted PVC already exists, so it can only be populated by a host assisted clone
+	if dvRequestsPvcAdoption(datavolume) {
+		return hostAssistedCloneRequired(datavolume, preferredCloneStrategy, "The adopted PVC can only be populated by a host assisted clone")
+	}
+
+	// Only the host assisted clone can copy a single disk of the source, or convert it to qcow2
+	if _, ok := datavolume.Annotations[cc.AnnCloneSourcePath]; ok {
+		return hostAssistedCloneRequired(datavolume, preferredCloneStrategy, "Copying a single disk of the source requires a host assisted clone")
+	}
+	if datavolume.Annotations[cc.AnnTargetFormat] == common.ImportTargetFormatQcow2 {
+		return hostAssistedCloneRequired(datavolume, preferredCloneStrategy, "Converting the source to qcow2 requires a host assisted clone")
+	}
+
 	bindingMode, err := r.getStorageClassBindingMode(pvcSpec.StorageClassName)
 	if err != nil {
 		return NoClone, err
@@ -440,41 +486,83 @@ func (r *PvcCloneReconciler) selectCloneStrategy(datavolume *cdiv1.DataVolume, p
 			return NoClone, err
 		}
 		if !waitForFirstConsumerEnabled {
-			return HostAssistedClone, nil
+			return hostAssistedCloneRequired(datavolume, preferredCloneStrategy, "The WaitForFirstConsumer target requires a host assisted clone unless the HonorWaitForFirstConsumer feature gate is enabled")
 		}
 	}
 
+	if preferredCloneStrategy != nil && *preferredCloneStrategy == cdiv1.CloneStrategyAuto {
+		return r.selectAutoCloneStrategy(datavolume, pvcSpec, bindingMode)
+	}
+
 	if preferredCloneStrategy != nil && *preferredCloneStrategy == cdiv1.CloneStrategyCsiClone {
-		csiClonePossible, err := r.advancedClonePossible(datavolume, pvcSpec)
+		csiClonePossible, reason, err := r.advancedClonePossible(datavolume, pvcSpec, false)
 		if err != nil {
 			return NoClone, err
 		}
-
-		if csiClonePossible &&
-			(!isCrossNamespaceClone(datavolume) || *bindingMode == storagev1.VolumeBindingImmediate) {
-			return CsiClone, nil
+		if !csiClonePossible {
+			return r.configuredCloneFallback(datavolume, pvcSpec, "CSI clone not possible: "+reason)
+		}
+		if isCrossNamespaceClone(datavolume) && (bindingMode == nil || *bindingMode != storagev1.VolumeBindingImmediate) {
+			return r.configuredCloneFallback(datavolume, pvcSpec, "CSI clone not possible: the target storage class binds its volumes on first consumer, and the source is in another namespace")
 		}
+		return CsiClone, nil
 	} else if preferredCloneStrategy != nil && *preferredCloneStrategy == cdiv1.CloneStrategySnapshot {
 		snapshotClassName, err := r.getSnapshotClassForSmartClone(datavolume, pvcSpec)
 		if err != nil {
 			return NoClone, err
 		}
-		snapshotClassAvailable := snapshotClassName != ""
 
-		snapshotPossible, err := r.advancedClonePossible(datavolume, pvcSpec)
+		snapshotPossible, reason, err := r.advancedClonePossible(datavolume, pvcSpec, true)
 		if err != nil {
 			return NoClone, err
 		}
 
-		if snapshotClassAvailable && snapshotPossible &&
-			(!isCrossNamespaceClone(datavolume) || *bindingMode == storagev1.VolumeBindingImmediate) {
-			return SmartClone, nil
+		if snapshotClassName == "" {
+			return r.configuredCloneFallback(datavolume, pvcSpec, "Snapshot clone not possible: no VolumeSnapshotClass matches the provisioner of the target storage class")
+		}
+		if !snapshotPossible {
+			return r.configuredCloneFallback(datavolume, pvcSpec, "Snapshot clone not possible: "+reason)
+		}
+		if isCrossNamespaceClone(datavolume) && (bindingMode == nil || *bindingMode != storagev1.VolumeBindingImmediate) {
+			return r.configuredCloneFallback(datavolume, pvcSpec, "Snapshot clone not possible: the target storage class binds its volumes on first consumer, and the source is in another namespace")
+		}
+		if !isCrossNamespaceClone(datavolume) {
+			sharedSnapshotClone, err := r.isSharedSnapshotClone(datavolume)
+			if err != nil {
+				return NoClone, err
+			}
+			if sharedSnapshotClone {
+				return SharedSnapshotClone, nil
+			}
 		}
+		return SmartClone, nil
 	}
 
 	return HostAssistedClone, nil
 }
 
+// hostAssistedCloneRequired returns the host assisted clone required by the DataVolume, recording why when the auto
+// clone strategy is used
+func hostAssistedCloneRequired(datavolume *cdiv1.DataVolume, preferredCloneStrategy *cdiv1.CDICloneStrategy, message string) (cloneStrategy, error) {
+	if preferredCloneStrategy != nil && *preferredCloneStrategy == cdiv1.CloneStrategyAuto {
+		cc.AddAnnotation(datavolume, annAutoCloneStrategy, message)
+	}
+	return HostAssistedClone, nil
+}
+
+// isSharedSnapshotClone returns true if the snapshot clone restores from a snapshot shared with the concurrent clones of
+// the same source, a clone that already joined a shared snapshot keeps it even if the feature gate is disabled meanwhile.
+// The clones of a batch always share the snapshot, so the source is read once for all the targets of the batch.
+func (r *PvcCloneReconciler) isSharedSnapshotClone(datavolume *cdiv1.DataVolume) (bool, error) {
+	if _, ok := datavolume.Annotations[annCloneSharedSnapshot]; ok {
+		return true, nil
+	}
+	if datavolume.Labels[cc.LabelCloneBatch] != "" {
+		return true, nil
+	}
+	return r.featureGates.SharedSnapshotCloneEnabled()
+}
+
 func (r *PvcCloneReconciler) reconcileCsiClonePvc(log logr.Logger,
 	syncRes *dvSyncState,
 	transferName string) (reconcile.Result, error) {
@@ -565,7 +653,7 @@ func (r *PvcCloneReconciler) reconcileCsiClonePvc(log logr.Logger,
 
 func cloneStrategyToCloneType(selectedCloneStrategy cloneStrategy) string {
 	switch selectedCloneStrategy {
-	case SmartClone:
+	case SmartClone, SharedSnapshotClone:
 		return "snapshot"
 	case CsiClone:
 		return "csivolumeclone"
@@ -625,6 +713,12 @@ func (r *PvcCloneReconciler) reconcileSmartClonePvc(log logr.Logger,
 				return reconcile.Result{}, err
 			}
 
+			// The PVC restored from the snapshot reads the format of the source image from it
+			sourcePvc, err := r.findSourcePvc(datavolume)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			cc.CopyTargetImageAnnotations(sourcePvc.Annotations, newSnapshot.Annotations)
 			if err := r.client.Create(context.TODO(), newSnapshot); err != nil {
 				if !k8serrors.IsAlreadyExists(err) {
 					return reconcile.Result{}, err
@@ -709,6 +803,12 @@ func (r *PvcCloneReconciler) cleanup(syncState *dvSyncState) error {
 		return err
 	}
 
+	if cloneTornDown(dv) {
+		if err := r.deleteCloneSnapshot(dv); err != nil {
+			return err
+		}
+	}
+
 	if isCrossNamespaceClone(dv) {
 		if err := r.cleanupTransfer(dv); err != nil {
 			return err
@@ -795,36 +895,49 @@ func isCrdDeployed(c client.Client, name, version string, log logr.Logger) bool
 }
 
 // Returns true if methods different from HostAssisted are possible,
-// both snapshot and csi volume clone share the same basic requirements
-func (r *PvcCloneReconciler) advancedClonePossible(dataVolume *cdiv1.DataVolume, targetStorageSpec *corev1.PersistentVolumeClaimSpec) (bool, error) {
+// both snapshot and csi volume clone share the same basic requirements.
+// A snapshot of the source can also be restored into another storage class of the same CSI driver,
+// when the StorageProfile of the target storage class declares it.
+// When they are not possible, it also returns why.
+func (r *PvcCloneReconciler) advancedClonePossible(dataVolume *cdiv1.DataVolume, targetStorageSpec *corev1.PersistentVolumeClaimSpec, snapshotRestore bool) (bool, string, error) {
 	log := r.log.WithName("ClonePossible").V(3)
 
 	sourcePvc, err := r.findSourcePvc(dataVolume)
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
-			return false, errors.New("source PVC not found")
+			return false, "", errors.New("source PVC not found")
 		}
-		return false, err
+		return false, "", err
 	}
 
 	targetStorageClass, err := cc.GetStorageClassByName(r.client, targetStorageSpec.StorageClassName)
 	if err != nil {
-		return false, err
+		return false, "", err
 	}
 	if targetStorageClass == nil {
 		log.Info("Target PVC's Storage Class not found")
-		return false, nil
+		return false, "target storage class not found", nil
 	}
 
 	if ok := r.validateSameStorageClass(sourcePvc, targetStorageClass); !ok {
-		return false, nil
+		differentStorageClass := fmt.Sprintf("source storage class %s and target storage class %s differ", *sourcePvc.Spec.StorageClassName, targetStorageClass.Name)
+		if !snapshotRestore {
+			return false, differentStorageClass, nil
+		}
+		if ok, err := r.validateCrossClassSnapshotRestore(sourcePvc, targetStorageClass); !ok || err != nil {
+			return false, differentStorageClass + fmt.Sprintf(", and StorageProfile %s does not restore its snapshots", targetStorageClass.Name), err
+		}
 	}
 
 	if ok, err := r.validateSameVolumeMode(dataVolume, sourcePvc, targetStorageClass); !ok || err != nil {
-		return false, err
+		return false, "source and target volume modes do not match", err
+	}
+
+	if ok, err := r.validateAdvancedCloneSizeCompatible(sourcePvc, targetStorageSpec); !ok || err != nil {
+		return false, "source size is not compatible with the target size", err
 	}
 
-	return r.validateAdvancedCloneSizeCompatible(sourcePvc, targetStorageSpec)
+	return true, "", nil
 }
 
 func (r *PvcCloneReconciler) validateSameStorageClass(
@@ -845,6 +958,48 @@ func (r *PvcCloneReconciler) validateSameStorageClass(
 	return true
 }
 
+// validateCrossClassSnapshotRestore checks the snapshot of the source PVC can be restored into the target storage class:
+// both storage classes share a provisioner, and the StorageProfile of the target one declares its CSI driver restores
+// snapshots across storage classes
+func (r *PvcCloneReconciler) validateCrossClassSnapshotRestore(
+	sourcePvc *corev1.PersistentVolumeClaim,
+	targetStorageClass *storagev1.StorageClass) (bool, error) {
+
+	crossClassSnapshotRestore, err := getCrossClassSnapshotRestore(r.client, targetStorageClass)
+	if err != nil {
+		return false, err
+	}
+	if !crossClassSnapshotRestore {
+		r.log.V(3).Info("Target storage class does not restore snapshots of other storage classes",
+			"target storage class", targetStorageClass.Name)
+		return false, nil
+	}
+
+	sourceStorageClass, err := cc.GetStorageClassByName(r.client, sourcePvc.Spec.StorageClassName)
+	if err != nil || sourceStorageClass == nil {
+		return false, err
+	}
+	if sourceStorageClass.Provisioner != targetStorageClass.Provisioner {
+		r.log.V(3).Info("Source and target storage classes have different provisioners",
+			"source provisioner", sourceStorageClass.Provisioner,
+			"target provisioner", targetStorageClass.Provisioner)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// getCrossClassSnapshotRestore returns whether the StorageProfile of the storage class declares its CSI driver restores
+// the snapshots of its other storage classes into it. It is the single place defaulting the declaration for the snapshot
+// and auto clone strategies: a StorageProfile that does not say does not restore them.
+func getCrossClassSnapshotRestore(c client.Client, storageClass *storagev1.StorageClass) (bool, error) {
+	storageProfile := &cdiv1.StorageProfile{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: storageClass.Name}, storageProfile); err != nil {
+		return false, cc.IgnoreNotFound(err)
+	}
+	return storageProfile.Status.CrossClassSnapshotRestore != nil && *storageProfile.Status.CrossClassSnapshotRestore, nil
+}
+
 func (r *PvcCloneReconciler) validateSameVolumeMode(
 	dataVolume *cdiv1.DataVolume,
 	sourcePvc *corev1.PersistentVolumeClaim,
@@ -914,35 +1069,19 @@ func (r *PvcCloneReconciler) validateAdvancedCloneSizeCompatible(
 	return true, nil
 }
 
-func (r *PvcCloneReconciler) getCloneStrategy(dataVolume *cdiv1.DataVolume) (*cdiv1.CDICloneStrategy, error) {
-	defaultCloneStrategy := cdiv1.CloneStrategySnapshot
-	sourcePvc, err := r.findSourcePvc(dataVolume)
-	if err != nil {
-		return nil, err
-	}
-	storageClass, err := cc.GetStorageClassByName(r.client, sourcePvc.Spec.StorageClassName)
-	if err != nil {
-		return nil, err
-	}
-
-	strategyOverride, err := r.getGlobalCloneStrategyOverride()
-	if err != nil {
+// getCloneStrategy returns the preferred clone strategy from the StorageProfile of the target storage class, unless
+// overridden in the CDI config. The storage class of the source PVC doesn't matter, a clone to another storage class
+// falls back to host assisted anyway, unless its snapshot can be restored into the target storage class.
+func (r *PvcCloneReconciler) getCloneStrategy(dataVolume *cdiv1.DataVolume, targetPvcSpec *corev1.PersistentVolumeClaimSpec) (*cdiv1.CDICloneStrategy, error) {
+	if _, err := r.findSourcePvc(dataVolume); err != nil {
 		return nil, err
 	}
-	if strategyOverride != nil {
-		return strategyOverride, nil
-	}
-
-	// do check storageProfile and apply the preferences
-	strategy, err := r.getPreferredCloneStrategyForStorageClass(storageClass)
+	storageClass, err := cc.GetStorageClassByName(r.client, targetPvcSpec.StorageClassName)
 	if err != nil {
 		return nil, err
 	}
-	if strategy != nil {
-		return strategy, err
-	}
 
-	return &defaultCloneStrategy, nil
+	return cc.GetCloneStrategy(r.client, storageClass)
 }
 
 func (r *PvcCloneReconciler) findSourcePvc(dataVolume *cdiv1.DataVolume) (*corev1.PersistentVolumeClaim, error) {
@@ -967,24 +1106,6 @@ func (r *PvcCloneReconciler) findSourcePvc(dataVolume *cdiv1.DataVolume) (*corev
 	return pvc, nil
 }
 
-func (r *PvcCloneReconciler) getGlobalCloneStrategyOverride() (*cdiv1.CDICloneStrategy, error) {
-	cr, err := cc.GetActiveCDI(r.client)
-	if err != nil {
-		return nil, err
-	}
-
-	if cr == nil {
-		return nil, fmt.Errorf("no active CDI")
-	}
-
-	if cr.Spec.CloneStrategyOverride == nil {
-		return nil, nil
-	}
-
-	r.log.V(3).Info(fmt.Sprintf("Overriding default clone strategy with %s", *cr.Spec.CloneStrategyOverride))
-	return cr.Spec.CloneStrategyOverride, nil
-}
-
 // NewVolumeClonePVC creates a PVC object to be used during CSI volume cloning.
 func (r *PvcCloneReconciler) newVolumeClonePVC(dv *cdiv1.DataVolume,
 	sourcePvc *corev1.PersistentVolumeClaim,
@@ -1017,25 +1138,11 @@ func (r *PvcCloneReconciler) newVolumeClonePVC(dv *cdiv1.DataVolume,
 		Name: dv.Spec.Source.PVC.Name,
 		Kind: "PersistentVolumeClaim",
 	}
+	cc.CopyTargetImageAnnotations(sourcePvc.Annotations, pvc.Annotations)
 
 	return pvc, nil
 }
 
-func (r *PvcCloneReconciler) getPreferredCloneStrategyForStorageClass(storageClass *storagev1.StorageClass) (*cdiv1.CDICloneStrategy, error) {
-	if storageClass == nil {
-		// fallback to defaults
-		return nil, nil
-	}
-
-	storageProfile := &cdiv1.StorageProfile{}
-	err := r.client.Get(context.TODO(), types.NamespacedName{Name: storageClass.Name}, storageProfile)
-	if err != nil {
-		return nil, errors.Wrap(err, "cannot get StorageProfile")
-	}
-
-	return storageProfile.Status.CloneStrategy, nil
-}
-
 // validateCloneAndSourcePVC checks if the source PVC of a clone exists and does proper validation
 func (r *PvcCloneReconciler) validateCloneAndSourcePVC(syncState *dvSyncState) (bool, error) {
 	datavolume := syncState.dvMutated
@@ -1054,15 +1161,157 @@ func (r *PvcCloneReconciler) validateCloneAndSourcePVC(syncState *dvSyncState) (
 		return false, err
 	}
 
-	err = cc.ValidateClone(sourcePvc, &datavolume.Spec)
-	if err != nil {
-		r.recorder.Event(datavolume, corev1.EventTypeWarning, CloneValidationFailed, MessageCloneValidationFailed)
-		return false, err
+	var done bool
+	if sourcePath, ok := datavolume.Annotations[cc.AnnCloneSourcePath]; ok {
+		done, err = r.validateCloneSourcePath(syncState, sourcePvc, sourcePath)
+	} else {
+		sourceFsOverhead, err := cc.GetFilesystemOverheadForStorageClass(r.client, sourcePvc.Spec.StorageClassName)
+		if err != nil {
+			return false, err
+		}
+		err = cc.ValidateClone(sourcePvc, &datavolume.Spec, sourceFsOverhead)
+		if err != nil {
+			r.recorder.Event(datavolume, corev1.EventTypeWarning, CloneValidationFailed, MessageCloneValidationFailed)
+			return false, err
+		}
+		done, err = r.validateCloneVolumeMode(syncState, sourcePvc)
+	}
+	if err != nil || !done {
+		return done, err
+	}
+
+	return r.validateCloneAccessModes(syncState, sourcePvc)
+}
+
+// validateCloneAccessModes checks the target storage class supports the target access modes of the clone which differ
+// from the source ones. They are resolved from the DataVolume and the target StorageProfile, independently of the
+// source access modes, so for example a ReadWriteOnce source can be cloned into a ReadWriteMany target for live migration.
+func (r *PvcCloneReconciler) validateCloneAccessModes(syncState *dvSyncState, sourcePvc *corev1.PersistentVolumeClaim) (bool, error) {
+	if syncState.pvc != nil {
+		return true, nil
+	}
+	storageClassName, volumeMode, accessModes := getTargetClaimProperties(syncState)
+	if !hasAccessModesNotIn(accessModes, sourcePvc.Spec.AccessModes) {
+		return true, nil
+	}
+	// Without a storage class or StorageProfile there is nothing to validate against, the target PVC reports it
+	storageClass, err := getTargetStorageClass(r.client, storageClassName)
+	if err != nil || storageClass == nil {
+		return true, nil
+	}
+	storageProfile := &cdiv1.StorageProfile{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: storageClass.Name}, storageProfile); err != nil {
+		return true, cc.IgnoreNotFound(err)
+	}
+	if storageProfileSupportsModes(storageProfile, volumeMode, accessModes) {
+		return true, nil
+	}
+
+	datavolume := syncState.dvMutated
+	return false, r.syncDataVolumeStatusPhaseWithEvent(syncState, datavolume.Status.Phase, nil,
+		Event{
+			eventType: corev1.EventTypeWarning,
+			reason:    CloneAccessModesUnsupported,
+			message:   fmt.Sprintf(MessageCloneAccessModesUnsupported, accessModes, storageProfile.Name),
+		})
+}
+
+// hasAccessModesNotIn returns true if some of the access modes are not in the other ones
+func hasAccessModesNotIn(accessModes, others []corev1.PersistentVolumeAccessMode) bool {
+	for _, accessMode := range accessModes {
+		found := false
+		for _, other := range others {
+			if accessMode == other {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return true
+		}
+	}
+	return false
+}
+
+// getTargetClaimProperties returns the storage class name, volume mode and access modes of the clone target
+func getTargetClaimProperties(syncState *dvSyncState) (*string, *corev1.PersistentVolumeMode, []corev1.PersistentVolumeAccessMode) {
+	if syncState.pvcSpec != nil {
+		return syncState.pvcSpec.StorageClassName, syncState.pvcSpec.VolumeMode, syncState.pvcSpec.AccessModes
+	}
+	if pvcSpec := syncState.dvMutated.Spec.PVC; pvcSpec != nil {
+		return pvcSpec.StorageClassName, pvcSpec.VolumeMode, pvcSpec.AccessModes
+	}
+	if storage := syncState.dvMutated.Spec.Storage; storage != nil {
+		return storage.StorageClassName, storage.VolumeMode, storage.AccessModes
+	}
+	return nil, nil, nil
+}
+
+// validateCloneSourcePath checks a single disk can be selected from the source PVC. The disk is converted into the
+// target, so neither the content type nor the size of the whole source volume matter, but the target size can't be
+// detected from the source.
+func (r *PvcCloneReconciler) validateCloneSourcePath(syncState *dvSyncState, sourcePvc *corev1.PersistentVolumeClaim, sourcePath string) (bool, error) {
+	var validationErr error
+	if err := util.ValidateCloneSourcePath(sourcePath); err != nil {
+		validationErr = err
+	} else if cc.GetVolumeMode(sourcePvc) != corev1.PersistentVolumeFilesystem {
+		validationErr = errors.New("the source PVC must have Filesystem volume mode")
+	} else if targetRequest, ok := syncState.pvcSpec.Resources.Requests[corev1.ResourceStorage]; !ok || targetRequest.IsZero() {
+		validationErr = errors.New("the target size must be specified")
+	}
+	if validationErr == nil {
+		return true, nil
+	}
+
+	datavolume := syncState.dvMutated
+	return false, r.syncDataVolumeStatusPhaseWithEvent(syncState, datavolume.Status.Phase, nil,
+		Event{
+			eventType: corev1.EventTypeWarning,
+			reason:    CloneSourcePathInvalid,
+			message:   fmt.Sprintf(MessageCloneSourcePathInvalid, cc.AnnCloneSourcePath, validationErr.Error()),
+		})
+}
+
+// validateCloneVolumeMode checks if the source volume mode can be cloned into the target volume mode.
+// A KubeVirt disk image is converted between block and filesystem volumes by the host-assisted clone,
+// other content types can't be converted so the clone is rejected.
+func (r *PvcCloneReconciler) validateCloneVolumeMode(syncState *dvSyncState, sourcePvc *corev1.PersistentVolumeClaim) (bool, error) {
+	datavolume := syncState.dvMutated
+	sourceVolumeMode := cc.GetVolumeMode(sourcePvc)
+	targetVolumeMode := util.ResolveVolumeMode(getTargetVolumeMode(syncState))
+	if sourceVolumeMode == targetVolumeMode {
+		return true, nil
 	}
 
+	contentType := cc.GetContentType(sourcePvc)
+	if contentType != string(cdiv1.DataVolumeKubeVirt) {
+		return false, r.syncDataVolumeStatusPhaseWithEvent(syncState, datavolume.Status.Phase, nil,
+			Event{
+				eventType: corev1.EventTypeWarning,
+				reason:    CloneVolumeModeMismatch,
+				message:   fmt.Sprintf(MessageCloneVolumeModeMismatch, sourceVolumeMode, targetVolumeMode, contentType),
+			})
+	}
+
+	if syncState.pvc == nil {
+		r.recorder.Eventf(datavolume, corev1.EventTypeNormal, CloneVolumeModeConversion, MessageCloneVolumeModeConversion, sourceVolumeMode, targetVolumeMode)
+	}
 	return true, nil
 }
 
+func getTargetVolumeMode(syncState *dvSyncState) *corev1.PersistentVolumeMode {
+	if syncState.pvcSpec != nil {
+		return syncState.pvcSpec.VolumeMode
+	}
+	if syncState.dvMutated.Spec.PVC != nil {
+		return syncState.dvMutated.Spec.PVC.VolumeMode
+	}
+	if syncState.dvMutated.Spec.Storage != nil {
+		return syncState.dvMutated.Spec.Storage.VolumeMode
+	}
+	return nil
+}
+
 // isSourceReadyToClone handles the reconciling process of a clone when the source PVC is not ready
 func (r *PvcCloneReconciler) isSourceReadyToClone(
 	datavolume *cdiv1.DataVolume,
@@ -1071,7 +1320,7 @@ func (r *PvcCloneReconciler) isSourceReadyToClone(
 	var eventReason string
 
 	switch selectedCloneStrategy {
-	case SmartClone:
+	case SmartClone, SharedSnapshotClone:
 		eventReason = SmartCloneSourceInUse
 	case CsiClone:
 		eventReason = CSICloneSourceInUse
@@ -1213,14 +1462,15 @@ func (r *PvcCloneReconciler) getOrCreateSizeDetectionPod(
 	nn := types.NamespacedName{Namespace: sourcePvc.Namespace, Name: podName}
 
 	// Trying to get the pod if it already exists/create it if not
-	if err := r.client.Get(context.TODO(), nn, pod); err != nil {
+	err := r.client.Get(context.TODO(), nn, pod)
+	if err != nil {
 		if !k8serrors.IsNotFound(err) {
 			return nil, err
 		}
 		// Generate the pod spec
-		pod = r.makeSizeDetectionPodSpec(sourcePvc, dv)
-		if pod == nil {
-			return nil, errors.Errorf("Size-detection pod spec could not be generated")
+		pod, err = r.makeSizeDetectionPodSpec(sourcePvc, dv)
+		if err != nil {
+			return nil, errors.Wrap(err, "Size-detection pod spec could not be generated")
 		}
 		// Create the pod
 		if err := r.client.Create(context.TODO(), pod); err != nil {
@@ -1239,22 +1489,26 @@ func (r *PvcCloneReconciler) getOrCreateSizeDetectionPod(
 // makeSizeDetectionPodSpec creates and returns the full size-detection pod spec
 func (r *PvcCloneReconciler) makeSizeDetectionPodSpec(
 	sourcePvc *corev1.PersistentVolumeClaim,
-	dv *cdiv1.DataVolume) *corev1.Pod {
+	dv *cdiv1.DataVolume) (*corev1.Pod, error) {
 
-	workloadNodePlacement, err := cc.GetWorkloadNodePlacement(r.client)
+	workloadNodePlacement, err := cc.GetWorkerPodNodePlacement(r.client, dv.Spec.WorkerPodPlacement)
 	if err != nil {
-		return nil
+		return nil, err
+	}
+	image, pullPolicy, err := cc.GetWorkerPodImage(r.client, dv.Spec.WorkerPodImage, r.importerImage, r.pullPolicy)
+	if err != nil {
+		return nil, err
 	}
 	// Generate individual specs
 	objectMeta := makeSizeDetectionObjectMeta(sourcePvc, dv)
 	volume := makeSizeDetectionVolumeSpec(sourcePvc.Name)
-	container := r.makeSizeDetectionContainerSpec(volume.Name)
-	if container == nil {
-		return nil
+	container, err := r.makeSizeDetectionContainerSpec(volume.Name, image, pullPolicy)
+	if err != nil {
+		return nil, err
 	}
 	imagePullSecrets, err := cc.GetImagePullSecrets(r.client)
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
 	// Assemble the pod
@@ -1286,14 +1540,14 @@ func (r *PvcCloneReconciler) makeSizeDetectionPodSpec(
 		}
 	} else {
 		if err := setAnnOwnedByDataVolume(pod, dv); err != nil {
-			return nil
+			return nil, err
 		}
 		pod.Annotations[cc.AnnOwnerUID] = string(dv.UID)
 	}
 
 	cc.SetRestrictedSecurityContext(&pod.Spec)
 
-	return pod
+	return pod, nil
 }
 
 // makeSizeDetectionObjectMeta creates and returns the object metadata for the size-detection pod
@@ -1309,11 +1563,11 @@ func makeSizeDetectionObjectMeta(sourcePvc *corev1.PersistentVolumeClaim, dataVo
 }
 
 // makeSizeDetectionContainerSpec creates and returns the size-detection pod's Container spec
-func (r *PvcCloneReconciler) makeSizeDetectionContainerSpec(volName string) *corev1.Container {
+func (r *PvcCloneReconciler) makeSizeDetectionContainerSpec(volName, image, pullPolicy string) (*corev1.Container, error) {
 	container := corev1.Container{
 		Name:            "size-detection-volume",
-		Image:           r.importerImage,
-		ImagePullPolicy: corev1.PullPolicy(r.pullPolicy),
+		Image:           image,
+		ImagePullPolicy: corev1.PullPolicy(pullPolicy),
 		Command:         []string{"/usr/bin/cdi-image-size-detection"},
 		Args:            []string{"-image-path", common.ImporterWritePath},
 		VolumeMounts: []corev1.VolumeMount{
@@ -1327,13 +1581,13 @@ func (r *PvcCloneReconciler) makeSizeDetectionContainerSpec(volName string) *cor
 	// Get and assign container's default resource requirements
 	resourceRequirements, err := cc.GetDefaultPodResourceRequirements(r.client)
 	if err != nil {
-		return nil
+		return nil, err
 	}
 	if resourceRequirements != nil {
 		container.Resources = *resourceRequirements
 	}
 
-	return &container
+	return &container, nil
 }
 
 // makeSizeDetectionVolumeSpec creates and returns the size-detection pod's Volume spec
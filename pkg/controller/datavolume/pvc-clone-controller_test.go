@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
@@ -36,6 +37,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -94,6 +96,371 @@ var _ = Describe("All DataVolume Tests", func() {
 			Expect(dv.Status.Phase).To(Equal(cdiv1.SnapshotForSmartCloneInProgress))
 		})
 
+		Context("with shared snapshot clones", func() {
+			var objs []runtime.Object
+			scName := "testsc"
+
+			BeforeEach(func() {
+				sc := CreateStorageClassWithProvisioner(scName, map[string]string{
+					AnnDefaultStorageClass: "true",
+				}, map[string]string{}, "csi-plugin")
+				sp := createStorageProfile(scName, []corev1.PersistentVolumeAccessMode{corev1.ReadOnlyMany}, BlockMode)
+				pvc := CreatePvcInStorageClass("test", metav1.NamespaceDefault, &scName, nil, nil, corev1.ClaimBound)
+				snapClass := createSnapshotClass("snap-class", nil, "csi-plugin")
+				objs = []runtime.Object{sc, sp, pvc, snapClass, createVolumeSnapshotContentCrd(), createVolumeSnapshotClassCrd(), createVolumeSnapshotCrd()}
+			})
+
+			createSharedCloneReconciler := func(gateEnabled bool, dvNames ...string) {
+				for _, name := range dvNames {
+					dv := newCloneDataVolume(name)
+					dv.Spec.PVC.StorageClassName = &scName
+					objs = append(objs, dv)
+				}
+				reconciler = createCloneReconciler(objs...)
+				if gateEnabled {
+					cdiConfig := &cdiv1.CDIConfig{}
+					Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+					cdiConfig.Spec.FeatureGates = append(cdiConfig.Spec.FeatureGates, featuregates.SharedSnapshotClone)
+					Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+				}
+			}
+
+			reconcileDataVolume := func(name string) *cdiv1.DataVolume {
+				_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: metav1.NamespaceDefault}})
+				Expect(err).ToNot(HaveOccurred())
+				for len(reconciler.recorder.(*record.FakeRecorder).Events) > 0 {
+					<-reconciler.recorder.(*record.FakeRecorder).Events
+				}
+				dv := &cdiv1.DataVolume{}
+				Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: metav1.NamespaceDefault}, dv)).To(Succeed())
+				return dv
+			}
+
+			listSnapshots := func() []snapshotv1.VolumeSnapshot {
+				snapshots := &snapshotv1.VolumeSnapshotList{}
+				Expect(reconciler.client.List(context.TODO(), snapshots)).To(Succeed())
+				return snapshots.Items
+			}
+
+			It("Should take a single snapshot for concurrent clones of the same source", func() {
+				dvNames := []string{"test-dv1", "test-dv2", "test-dv3"}
+				createSharedCloneReconciler(true, dvNames...)
+				for _, name := range dvNames {
+					dv := reconcileDataVolume(name)
+					Expect(dv.Status.Phase).To(Equal(cdiv1.SnapshotForSmartCloneInProgress))
+				}
+
+				snapshots := listSnapshots()
+				Expect(snapshots).To(HaveLen(1))
+				snapshot := &snapshots[0]
+				Expect(snapshot.Labels[labelSharedCloneSnapshot]).To(Equal("true"))
+				Expect(snapshot.Labels[common.AppKubernetesPartOfLabel]).To(Equal("testing"))
+				Expect(*snapshot.Spec.Source.PersistentVolumeClaimName).To(Equal("test"))
+				Expect(*snapshot.Spec.VolumeSnapshotClassName).To(Equal("snap-class"))
+				Expect(snapshot.OwnerReferences).To(BeEmpty())
+
+				By("Restoring every target PVC from the shared snapshot once it is ready")
+				restoreSize := resource.MustParse("1G")
+				snapshot.Status = &snapshotv1.VolumeSnapshotStatus{
+					ReadyToUse:  &[]bool{true}[0],
+					RestoreSize: &restoreSize,
+				}
+				Expect(reconciler.client.Update(context.TODO(), snapshot)).To(Succeed())
+				for _, name := range dvNames {
+					dv := reconcileDataVolume(name)
+					Expect(dv.Annotations[annCloneSharedSnapshot]).To(Equal(snapshot.Name))
+					Expect(dv.Annotations[annCloneType]).To(Equal("snapshot"))
+					Expect(dv.Status.Phase).To(Equal(cdiv1.SmartClonePVCInProgress))
+
+					pvc := &corev1.PersistentVolumeClaim{}
+					Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: metav1.NamespaceDefault}, pvc)).To(Succeed())
+					Expect(pvc.Spec.DataSource.Name).To(Equal(snapshot.Name))
+					Expect(pvc.Annotations[annCloneSharedSnapshot]).To(Equal(snapshot.Name))
+					Expect(pvc.Annotations).ToNot(HaveKey(annSmartCloneSnapshot))
+					Expect(hasAnnOwnedByDataVolume(pvc)).To(BeTrue())
+					Expect(metav1.IsControlledBy(pvc, dv)).To(BeTrue())
+				}
+				Expect(listSnapshots()).To(HaveLen(1))
+			})
+
+			It("Should share the snapshot when only the metadata of the source changed", func() {
+				createSharedCloneReconciler(true, "test-dv1", "test-dv2")
+				dv1 := reconcileDataVolume("test-dv1")
+
+				source := &corev1.PersistentVolumeClaim{}
+				Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test", Namespace: metav1.NamespaceDefault}, source)).To(Succeed())
+				AddAnnotation(source, "test", "changed")
+				Expect(reconciler.client.Update(context.TODO(), source)).To(Succeed())
+
+				dv2 := reconcileDataVolume("test-dv2")
+				Expect(listSnapshots()).To(HaveLen(1))
+				Expect(dv2.Annotations[annCloneSharedSnapshot]).To(Equal(dv1.Annotations[annCloneSharedSnapshot]))
+				snapshot := &snapshotv1.VolumeSnapshot{}
+				Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: dv2.Annotations[annCloneSharedSnapshot], Namespace: metav1.NamespaceDefault}, snapshot)).To(Succeed())
+				Expect(snapshot.Annotations[annSharedSnapshotSourceUID]).To(Equal(string(source.UID)))
+			})
+
+			newSourcePod := func(name string, readOnly bool, phase corev1.PodPhase) *corev1.Pod {
+				return &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceDefault},
+					Spec: corev1.PodSpec{
+						Volumes: []corev1.Volume{{
+							Name:         "data",
+							VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "test", ReadOnly: readOnly}},
+						}},
+					},
+					Status: corev1.PodStatus{Phase: phase},
+				}
+			}
+
+			It("Should not join a shared snapshot while the source is used by a running pod", func() {
+				createSharedCloneReconciler(true, "test-dv1", "test-dv2")
+				dv1 := reconcileDataVolume("test-dv1")
+				Expect(dv1.Annotations).To(HaveKey(annCloneSharedSnapshot))
+				Expect(reconciler.client.Create(context.TODO(), newSourcePod("writer", false, corev1.PodRunning))).To(Succeed())
+
+				dv2 := reconcileDataVolume("test-dv2")
+				Expect(dv2.Annotations).ToNot(HaveKey(annCloneSharedSnapshot))
+				Expect(dv2.Status.Phase).To(Equal(cdiv1.CloneScheduled))
+				Expect(listSnapshots()).To(HaveLen(1))
+			})
+
+			It("Should take a new shared snapshot when the source was written between two clone requests", func() {
+				createSharedCloneReconciler(true, "test-dv1", "test-dv2")
+				dv1 := reconcileDataVolume("test-dv1")
+				Expect(dv1.Annotations).To(HaveKey(annCloneSharedSnapshot))
+
+				By("Creating a pod that writes the source and is gone before the next clone")
+				writer := newSourcePod("writer", false, corev1.PodSucceeded)
+				Expect(reconciler.client.Create(context.TODO(), writer)).To(Succeed())
+				sourceReconciler := &sharedSnapshotSourceReconciler{client: reconciler.client, log: reconciler.log}
+				for _, req := range readWritePvcRequests(writer) {
+					_, err := sourceReconciler.Reconcile(context.TODO(), req)
+					Expect(err).ToNot(HaveOccurred())
+				}
+				Expect(reconciler.client.Delete(context.TODO(), writer)).To(Succeed())
+
+				dv2 := reconcileDataVolume("test-dv2")
+				Expect(dv2.Annotations).To(HaveKey(annCloneSharedSnapshot))
+				Expect(dv2.Annotations[annCloneSharedSnapshot]).ToNot(Equal(dv1.Annotations[annCloneSharedSnapshot]))
+				Expect(listSnapshots()).To(HaveLen(2))
+				snapshot := &snapshotv1.VolumeSnapshot{}
+				Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: dv2.Annotations[annCloneSharedSnapshot], Namespace: metav1.NamespaceDefault}, snapshot)).To(Succeed())
+				Expect(snapshot.Annotations[annSharedSnapshotGeneration]).To(Equal("1"))
+			})
+
+			It("Should not join a shared snapshot of an older write generation of the source", func() {
+				createSharedCloneReconciler(true, "test-dv1", "test-dv2")
+				dv1 := reconcileDataVolume("test-dv1")
+				snapshot := &snapshotv1.VolumeSnapshot{}
+				Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: dv1.Annotations[annCloneSharedSnapshot], Namespace: metav1.NamespaceDefault}, snapshot)).To(Succeed())
+				snapshot.Annotations[annSharedSnapshotGeneration] = "-1"
+				Expect(reconciler.client.Update(context.TODO(), snapshot)).To(Succeed())
+
+				dv2 := reconcileDataVolume("test-dv2")
+				Expect(dv2.Annotations).ToNot(HaveKey(annCloneSharedSnapshot))
+				Expect(dv2.Status.Phase).To(Equal(cdiv1.CloneScheduled))
+			})
+
+			It("Should only bump the write generation of a source mounted read-write", func() {
+				Expect(readWritePvcRequests(newSourcePod("reader", true, corev1.PodRunning))).To(BeEmpty())
+				Expect(readWritePvcRequests(newSourcePod("writer", false, corev1.PodRunning))).To(ConsistOf(
+					reconcile.Request{NamespacedName: types.NamespacedName{Name: "test", Namespace: metav1.NamespaceDefault}}))
+
+				createSharedCloneReconciler(true)
+				sourceReconciler := &sharedSnapshotSourceReconciler{client: reconciler.client, log: reconciler.log}
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test", Namespace: metav1.NamespaceDefault}}
+				_, err := sourceReconciler.Reconcile(context.TODO(), req)
+				Expect(err).ToNot(HaveOccurred())
+				source := &corev1.PersistentVolumeClaim{}
+				Expect(reconciler.client.Get(context.TODO(), req.NamespacedName, source)).To(Succeed())
+				Expect(source.Annotations).ToNot(HaveKey(annSourceWriteGeneration))
+			})
+
+			It("Should not join a shared snapshot past its window", func() {
+				createSharedCloneReconciler(true, "test-dv1", "test-dv2")
+				dv1 := reconcileDataVolume("test-dv1")
+				snapshot := &snapshotv1.VolumeSnapshot{}
+				Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: dv1.Annotations[annCloneSharedSnapshot], Namespace: metav1.NamespaceDefault}, snapshot)).To(Succeed())
+				snapshot.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * sharedSnapshotWindow))
+				Expect(reconciler.client.Update(context.TODO(), snapshot)).To(Succeed())
+
+				dv2 := reconcileDataVolume("test-dv2")
+				Expect(dv2.Annotations).ToNot(HaveKey(annCloneSharedSnapshot))
+				Expect(dv2.Status.Phase).To(Equal(cdiv1.CloneScheduled))
+				Expect(listSnapshots()).To(HaveLen(1))
+			})
+
+			DescribeTable("Should fall back to a host assisted clone when the shared snapshot", func(updateSnapshot func(*snapshotv1.VolumeSnapshot), expectedReason string) {
+				createSharedCloneReconciler(true, "test-dv1")
+				dv := reconcileDataVolume("test-dv1")
+				snapshot := &snapshotv1.VolumeSnapshot{}
+				Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: dv.Annotations[annCloneSharedSnapshot], Namespace: metav1.NamespaceDefault}, snapshot)).To(Succeed())
+				updateSnapshot(snapshot)
+				Expect(reconciler.client.Update(context.TODO(), snapshot)).To(Succeed())
+
+				dv = reconcileDataVolume("test-dv1")
+				Expect(dv.Annotations).ToNot(HaveKey(annCloneSharedSnapshot))
+				Expect(dv.Annotations[annCloneFallback]).To(ContainSubstring(expectedReason))
+				Expect(dv.Status.Phase).To(Equal(cdiv1.CloneScheduled))
+
+				dv = reconcileDataVolume("test-dv1")
+				Expect(dv.Annotations[annCloneType]).To(Equal(cloneStrategyToCloneType(HostAssistedClone)))
+			},
+				Entry("fails", func(snapshot *snapshotv1.VolumeSnapshot) {
+					snapshot.Status = &snapshotv1.VolumeSnapshotStatus{Error: &snapshotv1.VolumeSnapshotError{Message: pointer.String("snapshot class not found")}}
+				}, "failed: snapshot class not found"),
+				Entry("is not ready in time", func(snapshot *snapshotv1.VolumeSnapshot) {
+					snapshot.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * snapshotReadyTimeout))
+				}, "not ready after"),
+			)
+
+			It("Should read the source once for a batch of clones, and report a failing target on its own", func() {
+				dvNames := []string{"test-dv1", "test-dv2", "test-dv3", "test-dv4", "test-dv5"}
+				for _, name := range dvNames {
+					dv := newCloneDataVolume(name)
+					dv.Spec.PVC.StorageClassName = &scName
+					dv.Labels = map[string]string{LabelCloneBatch: "classroom"}
+					if name == "test-dv5" {
+						// The content type of the source can't be changed by a clone
+						dv.Spec.ContentType = cdiv1.DataVolumeArchive
+					}
+					objs = append(objs, dv)
+				}
+				// The batch shares the snapshot of the source without the feature gate
+				reconciler = createCloneReconciler(objs...)
+
+				for _, name := range dvNames[:4] {
+					dv := reconcileDataVolume(name)
+					Expect(dv.Status.Phase).To(Equal(cdiv1.SnapshotForSmartCloneInProgress))
+				}
+				_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv5", Namespace: metav1.NamespaceDefault}})
+				Expect(err).To(HaveOccurred())
+				Expect(reconciler.recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring(CloneValidationFailed)))
+				failed := &cdiv1.DataVolume{}
+				Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv5", Namespace: metav1.NamespaceDefault}, failed)).To(Succeed())
+				Expect(failed.Annotations).ToNot(HaveKey(annCloneSharedSnapshot))
+
+				snapshots := listSnapshots()
+				Expect(snapshots).To(HaveLen(1))
+				snapshot := &snapshots[0]
+				Expect(isSharedCloneSnapshot(snapshot)).To(BeTrue())
+
+				By("Restoring the target PVCs of the successful clones from the single snapshot")
+				restoreSize := resource.MustParse("1G")
+				snapshot.Status = &snapshotv1.VolumeSnapshotStatus{
+					ReadyToUse:  &[]bool{true}[0],
+					RestoreSize: &restoreSize,
+				}
+				Expect(reconciler.client.Update(context.TODO(), snapshot)).To(Succeed())
+				for _, name := range dvNames[:4] {
+					dv := reconcileDataVolume(name)
+					Expect(dv.Status.Phase).To(Equal(cdiv1.SmartClonePVCInProgress))
+					pvc := &corev1.PersistentVolumeClaim{}
+					Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: metav1.NamespaceDefault}, pvc)).To(Succeed())
+					Expect(pvc.Spec.DataSource.Name).To(Equal(snapshot.Name))
+				}
+				pvc := &corev1.PersistentVolumeClaim{}
+				err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv5", Namespace: metav1.NamespaceDefault}, pvc)
+				Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+				Expect(listSnapshots()).To(HaveLen(1))
+			})
+
+			It("Should snapshot the source once per clone without the feature gate", func() {
+				createSharedCloneReconciler(false, "test-dv1", "test-dv2")
+				reconcileDataVolume("test-dv1")
+				reconcileDataVolume("test-dv2")
+				snapshots := listSnapshots()
+				Expect(snapshots).To(HaveLen(2))
+				for _, snapshot := range snapshots {
+					Expect(isSharedCloneSnapshot(&snapshot)).To(BeFalse())
+				}
+			})
+		})
+
+		It("Should adopt an existing empty PVC with a host assisted clone, if the DV requests it", func() {
+			dv := newCloneDataVolume("test-dv")
+			AddAnnotation(dv, AnnAdoptPVC, "true")
+			scName := "testsc"
+			sc := CreateStorageClassWithProvisioner(scName, map[string]string{
+				AnnDefaultStorageClass: "true",
+			}, map[string]string{}, "csi-plugin")
+			sp := createStorageProfile(scName, []corev1.PersistentVolumeAccessMode{corev1.ReadOnlyMany}, BlockMode)
+
+			dv.Spec.PVC.StorageClassName = &scName
+			pvc := CreatePvcInStorageClass("test", metav1.NamespaceDefault, &scName, nil, nil, corev1.ClaimBound)
+			targetPvc := CreatePvcInStorageClass("test-dv", metav1.NamespaceDefault, &scName, nil, nil, corev1.ClaimBound)
+			snapClass := createSnapshotClass("snap-class", nil, "csi-plugin")
+			reconciler = createCloneReconciler(sc, sp, dv, pvc, targetPvc, snapClass, createVolumeSnapshotContentCrd(), createVolumeSnapshotClassCrd(), createVolumeSnapshotCrd())
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			By("Verifying that no snapshot was created")
+			snap := &snapshotv1.VolumeSnapshot{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Namespace: dv.Namespace, Name: dv.Name}, snap)
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			By("Verifying that the existing PVC was adopted")
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Namespace: dv.Namespace, Name: dv.Name}, targetPvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(metav1.IsControlledBy(targetPvc, dv)).To(BeTrue())
+			Expect(targetPvc.Annotations[AnnCloneRequest]).To(Equal("default/test"))
+		})
+
+		It("Should use a host assisted clone, if the DV selects a disk of the source", func() {
+			dv := newCloneDataVolume("test-dv")
+			AddAnnotation(dv, AnnCloneSourcePath, "disks/vm1.qcow2")
+			scName := "testsc"
+			sc := CreateStorageClassWithProvisioner(scName, map[string]string{
+				AnnDefaultStorageClass: "true",
+			}, map[string]string{}, "csi-plugin")
+			sp := createStorageProfile(scName, []corev1.PersistentVolumeAccessMode{corev1.ReadOnlyMany}, BlockMode)
+
+			dv.Spec.PVC.StorageClassName = &scName
+			pvc := CreatePvcInStorageClass("test", metav1.NamespaceDefault, &scName, nil, nil, corev1.ClaimBound)
+			snapClass := createSnapshotClass("snap-class", nil, "csi-plugin")
+			reconciler = createCloneReconciler(sc, sp, dv, pvc, snapClass, createVolumeSnapshotContentCrd(), createVolumeSnapshotClassCrd(), createVolumeSnapshotCrd())
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			By("Verifying that no snapshot was created")
+			snap := &snapshotv1.VolumeSnapshot{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Namespace: dv.Namespace, Name: dv.Name}, snap)
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			By("Verifying that the target PVC selects the disk")
+			targetPvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Namespace: dv.Namespace, Name: dv.Name}, targetPvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(targetPvc.Annotations[AnnCloneRequest]).To(Equal("default/test"))
+			Expect(targetPvc.Annotations[AnnCloneSourcePath]).To(Equal("disks/vm1.qcow2"))
+			dv = &cdiv1.DataVolume{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dv.Annotations[annCloneType]).To(Equal(cloneStrategyToCloneType(HostAssistedClone)))
+		})
+
+		It("Should use a host assisted clone, if the DV writes the clone as qcow2", func() {
+			dv := newCloneDataVolume("test-dv")
+			AddAnnotation(dv, AnnTargetFormat, "qcow2")
+			scName := "testsc"
+			sc := CreateStorageClassWithProvisioner(scName, map[string]string{
+				AnnDefaultStorageClass: "true",
+			}, map[string]string{}, "csi-plugin")
+			sp := createStorageProfile(scName, []corev1.PersistentVolumeAccessMode{corev1.ReadOnlyMany}, BlockMode)
+
+			dv.Spec.PVC.StorageClassName = &scName
+			pvc := CreatePvcInStorageClass("test", metav1.NamespaceDefault, &scName, nil, nil, corev1.ClaimBound)
+			snapClass := createSnapshotClass("snap-class", nil, "csi-plugin")
+			reconciler = createCloneReconciler(sc, sp, dv, pvc, snapClass, createVolumeSnapshotContentCrd(), createVolumeSnapshotClassCrd(), createVolumeSnapshotCrd())
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			By("Verifying that no snapshot was created")
+			snap := &snapshotv1.VolumeSnapshot{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Namespace: dv.Namespace, Name: dv.Name}, snap)
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			dv = &cdiv1.DataVolume{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dv.Annotations[annCloneType]).To(Equal(cloneStrategyToCloneType(HostAssistedClone)))
+		})
+
 		It("Should not recreate snpashot that was cleaned-up", func() {
 			dv := newCloneDataVolume("test-dv")
 			scName := "testsc"
@@ -297,7 +664,7 @@ var _ = Describe("All DataVolume Tests", func() {
 		It("Should err, if no source pvc provided", func() {
 			dv := NewImportDataVolume("test-dv")
 			reconciler = createCloneReconciler(dv)
-			possible, err := reconciler.advancedClonePossible(dv, dv.Spec.PVC)
+			possible, _, err := reconciler.advancedClonePossible(dv, dv.Spec.PVC, false)
 			Expect(err).To(HaveOccurred())
 			Expect(possible).To(BeFalse())
 		})
@@ -333,7 +700,7 @@ var _ = Describe("All DataVolume Tests", func() {
 				AnnDefaultStorageClass: "true",
 			})
 			reconciler = createCloneReconciler(dv, sc, createVolumeSnapshotContentCrd(), createVolumeSnapshotClassCrd(), createVolumeSnapshotCrd())
-			possible, err := reconciler.advancedClonePossible(dv, dv.Spec.PVC)
+			possible, _, err := reconciler.advancedClonePossible(dv, dv.Spec.PVC, false)
 			Expect(err).To(HaveOccurred())
 			Expect(possible).To(BeFalse())
 		})
@@ -342,7 +709,7 @@ var _ = Describe("All DataVolume Tests", func() {
 			dv := newCloneDataVolume("test-dv")
 			pvc := CreatePvc("test", metav1.NamespaceDefault, nil, nil)
 			reconciler = createCloneReconciler(dv, pvc)
-			possible, err := reconciler.advancedClonePossible(dv, dv.Spec.PVC)
+			possible, _, err := reconciler.advancedClonePossible(dv, dv.Spec.PVC, false)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(possible).To(BeFalse())
 		})
@@ -360,11 +727,35 @@ var _ = Describe("All DataVolume Tests", func() {
 			})
 			pvc := CreatePvcInStorageClass("test", metav1.NamespaceDefault, &sourceSc, nil, nil, corev1.ClaimBound)
 			reconciler = createCloneReconciler(ssc, tsc, dv, pvc)
-			possible, err := reconciler.advancedClonePossible(dv, dv.Spec.PVC)
+			possible, reason, err := reconciler.advancedClonePossible(dv, dv.Spec.PVC, false)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(possible).To(BeFalse())
+			Expect(reason).To(Equal("source storage class testsc2 and target storage class testsc differ"))
 		})
 
+		DescribeTable("Should restore the snapshot of a source of another storage class",
+			func(sourceProvisioner string, crossClassSnapshotRestore *bool, snapshotRestore, expected bool) {
+				dv := newCloneDataVolume("test-dv")
+				targetSc := "testsc"
+				tsc := CreateStorageClassWithProvisioner(targetSc, map[string]string{}, map[string]string{}, "csi-plugin")
+				dv.Spec.PVC.StorageClassName = &targetSc
+				sourceSc := "testsc2"
+				ssc := CreateStorageClassWithProvisioner(sourceSc, map[string]string{}, map[string]string{}, sourceProvisioner)
+				storageProfile := createStorageProfile(targetSc, nil, FilesystemMode)
+				storageProfile.Status.CrossClassSnapshotRestore = crossClassSnapshotRestore
+				pvc := CreatePvcInStorageClass("test", metav1.NamespaceDefault, &sourceSc, nil, nil, corev1.ClaimBound)
+				reconciler = createCloneReconciler(ssc, tsc, storageProfile, dv, pvc)
+				possible, _, err := reconciler.advancedClonePossible(dv, dv.Spec.PVC, snapshotRestore)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(possible).To(Equal(expected))
+			},
+			Entry("when the target StorageProfile declares it", "csi-plugin", pointer.Bool(true), true, true),
+			Entry("but not for a CSI clone", "csi-plugin", pointer.Bool(true), false, false),
+			Entry("but not when the target StorageProfile does not declare it", "csi-plugin", nil, true, false),
+			Entry("but not when the target StorageProfile denies it", "csi-plugin", pointer.Bool(false), true, false),
+			Entry("but not from another provisioner", "other-plugin", pointer.Bool(true), true, false),
+		)
+
 		It("Should not return snapshot class, if storage class does not exist", func() {
 			dv := newCloneDataVolume("test-dv")
 			scName := "testsc"
@@ -407,7 +798,7 @@ var _ = Describe("All DataVolume Tests", func() {
 			Expect(snapclass).To(Equal(expectedSnapshotClass))
 		})
 
-		DescribeTable("Setting clone strategy affects the output of getGlobalCloneStrategyOverride", func(expectedCloneStrategy cdiv1.CDICloneStrategy) {
+		DescribeTable("Setting clone strategy affects the output of GetCloneStrategyOverride", func(expectedCloneStrategy cdiv1.CDICloneStrategy) {
 			dv := newCloneDataVolume("test-dv")
 			reconciler = createCloneReconciler(dv)
 
@@ -419,7 +810,7 @@ var _ = Describe("All DataVolume Tests", func() {
 			err = reconciler.client.Update(context.TODO(), cr)
 			Expect(err).ToNot(HaveOccurred())
 
-			cloneStrategy, err := reconciler.getGlobalCloneStrategyOverride()
+			cloneStrategy, err := GetCloneStrategyOverride(reconciler.client)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(*cloneStrategy).To(Equal(expectedCloneStrategy))
 		},
@@ -529,6 +920,21 @@ var _ = Describe("All DataVolume Tests", func() {
 			Entry("Should be Succeeded, if source pvc is ClaimBound", corev1.ClaimBound, cdiv1.Succeeded),
 		)
 
+		It("Should carry the format of the image written to the source to the target PVC", func() {
+			dv := newCloneDataVolume("test-dv")
+			scName := "testsc"
+			srcPvc := CreatePvcInStorageClass("test", metav1.NamespaceDefault, &scName, map[string]string{
+				AnnImageTargetFormat:      "qcow2",
+				AnnImageTargetCompression: "zstd",
+			}, nil, corev1.ClaimBound)
+			reconciler = createCloneReconciler(dv, srcPvc)
+
+			pvc, err := reconciler.newVolumeClonePVC(dv, srcPvc, &corev1.PersistentVolumeClaimSpec{}, "test-dv")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.Annotations[AnnImageTargetFormat]).To(Equal("qcow2"))
+			Expect(pvc.Annotations[AnnImageTargetCompression]).To(Equal("zstd"))
+		})
+
 		It("Should not panic if CSI Driver not available and no storage class on PVC spec", func() {
 			strategy := cdiv1.CDICloneStrategy(cdiv1.CloneStrategyCsiClone)
 
@@ -656,6 +1062,198 @@ var _ = Describe("All DataVolume Tests", func() {
 			Entry("Empty (kubeVirt by default) in source and archive in target", "", string(cdiv1.DataVolumeArchive), false),
 			Entry("Archive in source and empty (KubeVirt by default) in target", string(cdiv1.DataVolumeArchive), "", false),
 		)
+
+		DescribeTable("Validation mechanism handles the source and target volume modes",
+			func(contentType cdiv1.DataVolumeContentType, sourceVolumeMode, targetVolumeMode corev1.PersistentVolumeMode, expectedResult bool, expectedEvent string) {
+				dv := newCloneDataVolume("test-dv")
+				dv.Spec.ContentType = contentType
+				dv.Spec.PVC.VolumeMode = &targetVolumeMode
+				pvc := CreatePvcInStorageClass("test", metav1.NamespaceDefault, &scName, map[string]string{
+					AnnContentType: string(contentType)}, nil, corev1.ClaimBound)
+				pvc.Spec.VolumeMode = &sourceVolumeMode
+				storageProfile := createStorageProfile(scName, nil, FilesystemMode)
+				reconciler = createCloneReconciler(dv, pvc, storageProfile, sc)
+
+				state := syncState(dv)
+				done, err := reconciler.validateCloneAndSourcePVC(state)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(done).To(Equal(expectedResult))
+				if expectedResult {
+					Expect(state.phaseSync).To(BeNil())
+				} else {
+					Expect(state.phaseSync).ToNot(BeNil())
+					Expect(state.phaseSync.event.reason).To(Equal(CloneVolumeModeMismatch))
+				}
+
+				events := reconciler.recorder.(*record.FakeRecorder).Events
+				if expectedEvent == "" {
+					Expect(events).To(BeEmpty())
+				} else {
+					Expect(events).To(Receive(ContainSubstring(expectedEvent)))
+				}
+			},
+			Entry("Block to filesystem with KubeVirt content converts the image", cdiv1.DataVolumeKubeVirt, BlockMode, FilesystemMode, true, CloneVolumeModeConversion),
+			Entry("Filesystem to block with KubeVirt content converts the image", cdiv1.DataVolumeKubeVirt, FilesystemMode, BlockMode, true, CloneVolumeModeConversion),
+			Entry("Filesystem to block with archive content is rejected", cdiv1.DataVolumeArchive, FilesystemMode, BlockMode, false, ""),
+			Entry("Matching block volume modes", cdiv1.DataVolumeKubeVirt, BlockMode, BlockMode, true, ""),
+			Entry("Matching filesystem volume modes", cdiv1.DataVolumeKubeVirt, FilesystemMode, FilesystemMode, true, ""),
+		)
+
+		It("Should set the DataVolume conditions if the volume modes can't be converted", func() {
+			dv := newCloneDataVolume("test-dv")
+			dv.Spec.ContentType = cdiv1.DataVolumeArchive
+			dv.Spec.PVC.VolumeMode = &BlockMode
+			dv.Spec.PVC.StorageClassName = &scName
+			pvc := CreatePvcInStorageClass("test", metav1.NamespaceDefault, &scName, map[string]string{
+				AnnContentType: string(cdiv1.DataVolumeArchive)}, nil, corev1.ClaimBound)
+			pvc.Spec.VolumeMode = &FilesystemMode
+			storageProfile := createStorageProfile(scName, nil, FilesystemMode)
+			reconciler = createCloneReconciler(dv, pvc, storageProfile, sc)
+
+			_, err := reconciler.Reconcile(context.TODO(), getReconcileRequest(dv))
+			Expect(err).ToNot(HaveOccurred())
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+			Expect(err).ToNot(HaveOccurred())
+			readyCondition := FindConditionByType(cdiv1.DataVolumeReady, dv.Status.Conditions)
+			Expect(readyCondition).ToNot(BeNil())
+			Expect(readyCondition.Status).To(Equal(corev1.ConditionFalse))
+			Expect(readyCondition.Reason).To(Equal(CloneVolumeModeMismatch))
+			Expect(reconciler.recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring(CloneVolumeModeMismatch)))
+		})
+
+		newAccessModesCloneDataVolume := func(accessModes ...corev1.PersistentVolumeAccessMode) *cdiv1.DataVolume {
+			dv := newCloneDataVolume("test-dv")
+			dv.Spec.PVC = nil
+			dv.Spec.Storage = &cdiv1.StorageSpec{
+				AccessModes:      accessModes,
+				StorageClassName: &scName,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1G")},
+				},
+			}
+			return dv
+		}
+
+		DescribeTable("Validation mechanism handles the target access modes",
+			func(claimPropertySets []cdiv1.ClaimPropertySet, targetVolumeMode *corev1.PersistentVolumeMode, expectedResult bool) {
+				dv := newAccessModesCloneDataVolume(corev1.ReadWriteMany)
+				dv.Spec.Storage.VolumeMode = targetVolumeMode
+				pvc := CreatePvcInStorageClass("test", metav1.NamespaceDefault, &scName, nil, nil, corev1.ClaimBound)
+				pvc.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+				storageProfile := createStorageProfileWithClaimPropertySets(scName, claimPropertySets)
+				reconciler = createCloneReconciler(dv, pvc, storageProfile, sc)
+
+				state := syncState(dv)
+				done, err := reconciler.validateCloneAndSourcePVC(state)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(done).To(Equal(expectedResult))
+				if expectedResult {
+					Expect(state.phaseSync).To(BeNil())
+				} else {
+					Expect(state.phaseSync).ToNot(BeNil())
+					Expect(state.phaseSync.event.reason).To(Equal(CloneAccessModesUnsupported))
+				}
+			},
+			Entry("ReadWriteOnce to ReadWriteMany with a class supporting it", []cdiv1.ClaimPropertySet{
+				{AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}, VolumeMode: &FilesystemMode},
+			}, nil, true),
+			Entry("ReadWriteOnce to ReadWriteMany with a class only supporting ReadWriteOnce", []cdiv1.ClaimPropertySet{
+				{AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}, VolumeMode: &FilesystemMode},
+			}, nil, false),
+			Entry("ReadWriteOnce to ReadWriteMany with a class only supporting it in another volume mode", []cdiv1.ClaimPropertySet{
+				{AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}, VolumeMode: &BlockMode},
+				{AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}, VolumeMode: &FilesystemMode},
+			}, &FilesystemMode, false),
+			Entry("ReadWriteOnce to ReadWriteMany with a StorageProfile without access modes", []cdiv1.ClaimPropertySet{
+				{VolumeMode: &FilesystemMode},
+			}, nil, true),
+		)
+
+		It("Should clone a ReadWriteOnce source into a ReadWriteMany target", func() {
+			dv := newAccessModesCloneDataVolume()
+			pvc := CreatePvcInStorageClass("test", metav1.NamespaceDefault, &scName, nil, nil, corev1.ClaimBound)
+			pvc.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+			hostAssisted := cdiv1.CloneStrategyHostAssisted
+			storageProfile := createStorageProfileWithCloneStrategy(scName, []cdiv1.ClaimPropertySet{
+				{AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}, VolumeMode: &FilesystemMode},
+			}, &hostAssisted)
+			reconciler = createCloneReconciler(dv, pvc, storageProfile, sc)
+
+			_, err := reconciler.Reconcile(context.TODO(), getReconcileRequest(dv))
+			Expect(err).ToNot(HaveOccurred())
+			targetPvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, targetPvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(targetPvc.Spec.AccessModes).To(Equal([]corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}))
+			Expect(targetPvc.Annotations[AnnCloneRequest]).To(Equal("default/test"))
+		})
+
+		It("Should set the DataVolume conditions if the target access modes are not supported", func() {
+			dv := newAccessModesCloneDataVolume(corev1.ReadWriteMany)
+			pvc := CreatePvcInStorageClass("test", metav1.NamespaceDefault, &scName, nil, nil, corev1.ClaimBound)
+			pvc.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+			storageProfile := createStorageProfile(scName, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}, FilesystemMode)
+			reconciler = createCloneReconciler(dv, pvc, storageProfile, sc)
+
+			_, err := reconciler.Reconcile(context.TODO(), getReconcileRequest(dv))
+			Expect(err).ToNot(HaveOccurred())
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, &corev1.PersistentVolumeClaim{})
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+			Expect(err).ToNot(HaveOccurred())
+			readyCondition := FindConditionByType(cdiv1.DataVolumeReady, dv.Status.Conditions)
+			Expect(readyCondition).ToNot(BeNil())
+			Expect(readyCondition.Reason).To(Equal(CloneAccessModesUnsupported))
+			Expect(reconciler.recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring(CloneAccessModesUnsupported)))
+		})
+	})
+
+	var _ = Describe("Clone of a selected disk", func() {
+		scName := "testsc"
+		sc := CreateStorageClassWithProvisioner(scName, map[string]string{
+			AnnDefaultStorageClass: "true",
+		}, map[string]string{}, "csi-plugin")
+
+		AfterEach(func() {
+			if reconciler != nil && reconciler.recorder != nil {
+				close(reconciler.recorder.(*record.FakeRecorder).Events)
+			}
+		})
+
+		DescribeTable("Validation mechanism handles the clone source path",
+			func(sourcePath string, sourceVolumeMode corev1.PersistentVolumeMode, sourceContentType cdiv1.DataVolumeContentType, targetSize string, errString string) {
+				dv := newCloneDataVolume("test-dv")
+				AddAnnotation(dv, AnnCloneSourcePath, sourcePath)
+				pvc := CreatePvcInStorageClass("test", metav1.NamespaceDefault, &scName, map[string]string{
+					AnnContentType: string(sourceContentType)}, nil, corev1.ClaimBound)
+				pvc.Spec.VolumeMode = &sourceVolumeMode
+				storageProfile := createStorageProfile(scName, nil, FilesystemMode)
+				reconciler = createCloneReconciler(dv, pvc, storageProfile, sc)
+
+				pvcSpec := dv.Spec.PVC.DeepCopy()
+				pvcSpec.Resources.Requests = corev1.ResourceList{}
+				if targetSize != "" {
+					pvcSpec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse(targetSize)
+				}
+				state := &dvSyncState{dv: dv, dvMutated: dv.DeepCopy(), pvcSpec: pvcSpec}
+				done, err := reconciler.validateCloneAndSourcePVC(state)
+				Expect(err).ToNot(HaveOccurred())
+				if errString == "" {
+					Expect(done).To(BeTrue())
+					Expect(state.phaseSync).To(BeNil())
+				} else {
+					Expect(done).To(BeFalse())
+					Expect(state.phaseSync).ToNot(BeNil())
+					Expect(state.phaseSync.event.reason).To(Equal(CloneSourcePathInvalid))
+					Expect(state.phaseSync.event.message).To(ContainSubstring(errString))
+				}
+			},
+			Entry("accepts a disk of a filesystem source", "disks/vm1.qcow2", FilesystemMode, cdiv1.DataVolumeKubeVirt, "1G", ""),
+			Entry("accepts a disk of an archive source, smaller than the source", "disks/vm1*", FilesystemMode, cdiv1.DataVolumeArchive, "1M", ""),
+			Entry("rejects a block source", "disks/vm1.qcow2", BlockMode, cdiv1.DataVolumeKubeVirt, "1G", "the source PVC must have Filesystem volume mode"),
+			Entry("rejects a missing target size", "disks/vm1.qcow2", FilesystemMode, cdiv1.DataVolumeKubeVirt, "", "the target size must be specified"),
+			Entry("rejects a path outside the source", "../vm1.qcow2", FilesystemMode, cdiv1.DataVolumeKubeVirt, "1G", "must be inside the source volume"),
+		)
 	})
 
 	var _ = Describe("Clone strategy", func() {
@@ -689,9 +1287,13 @@ var _ = Describe("All DataVolume Tests", func() {
 				err = reconciler.client.Update(context.TODO(), cr)
 				Expect(err).ToNot(HaveOccurred())
 
-				cloneStrategy, err := reconciler.getCloneStrategy(dv)
+				cloneStrategy, err := reconciler.getCloneStrategy(dv, dv.Spec.PVC)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(*cloneStrategy).To(Equal(expectedCloneStrategy))
+
+				effectiveConfig, err := GetEffectiveConfig(reconciler.client, dv.Spec.PVC.StorageClassName, "pvc")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(effectiveConfig.CloneStrategy).To(Equal(cloneStrategy))
 			},
 			Entry("override hostAssisted /host", &hostAssisted, &hostAssisted, cdiv1.CloneStrategyHostAssisted),
 			Entry("override hostAssisted /snapshot", &hostAssisted, &snapshot, cdiv1.CloneStrategyHostAssisted),
@@ -710,6 +1312,37 @@ var _ = Describe("All DataVolume Tests", func() {
 		)
 	})
 
+	var _ = Describe("Clone strategy with a target storage class", func() {
+		It("should resolve the clone strategy from the storageProfile of the target storage class", func() {
+			hostAssisted := cdiv1.CloneStrategyHostAssisted
+			csiClone := cdiv1.CloneStrategyCsiClone
+			sourceScName := "sourcesc"
+			targetScName := "targetsc"
+			dv := newCloneDataVolume("test-dv")
+			dv.Spec.PVC.StorageClassName = &targetScName
+			pvc := CreatePvcInStorageClass("test", metav1.NamespaceDefault, &sourceScName, nil, nil, corev1.ClaimBound)
+			sourceSc := CreateStorageClassWithProvisioner(sourceScName, map[string]string{
+				AnnDefaultStorageClass: "true",
+			}, map[string]string{}, "csi-plugin")
+			targetSc := CreateStorageClassWithProvisioner(targetScName, nil, map[string]string{}, "csi-plugin")
+			accessMode := []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+			sourceStorageProfile := createStorageProfileWithCloneStrategy(sourceScName,
+				[]cdiv1.ClaimPropertySet{{AccessModes: accessMode, VolumeMode: &BlockMode}}, &csiClone)
+			targetStorageProfile := createStorageProfileWithCloneStrategy(targetScName,
+				[]cdiv1.ClaimPropertySet{{AccessModes: accessMode, VolumeMode: &BlockMode}}, &hostAssisted)
+
+			reconciler = createCloneReconciler(dv, pvc, sourceSc, targetSc, sourceStorageProfile, targetStorageProfile)
+
+			cloneStrategy, err := reconciler.getCloneStrategy(dv, dv.Spec.PVC)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*cloneStrategy).To(Equal(cdiv1.CloneStrategyHostAssisted))
+
+			selectedCloneStrategy, err := reconciler.selectCloneStrategy(dv, dv.Spec.PVC)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(selectedCloneStrategy).To(Equal(HostAssistedClone))
+		})
+	})
+
 	var _ = Describe("Clone with empty storage size", func() {
 		scName := "testsc"
 		accessMode := []corev1.PersistentVolumeAccessMode{corev1.ReadOnlyMany}
@@ -805,9 +1438,10 @@ var _ = Describe("All DataVolume Tests", func() {
 			reconciler := createCloneReconciler(dv, pvc, storageProfile, sc)
 
 			// Prepare the size-detection Pod with the required information
-			pod := reconciler.makeSizeDetectionPodSpec(pvc, dv)
+			pod, err := reconciler.makeSizeDetectionPodSpec(pvc, dv)
+			Expect(err).ToNot(HaveOccurred())
 			pod.Status.Phase = corev1.PodSucceeded
-			err := reconciler.client.Create(context.TODO(), pod)
+			err = reconciler.client.Create(context.TODO(), pod)
 			Expect(err).ToNot(HaveOccurred())
 
 			// Checks
@@ -824,6 +1458,35 @@ var _ = Describe("All DataVolume Tests", func() {
 			Expect(event).To(ContainSubstring("Size-detection pod failed due to"))
 		})
 
+		It("Should create the size-detection pod with the worker pod image of the DV", func() {
+			dv := newCloneDataVolumeWithEmptyStorage("test-dv", "default")
+			dv.Spec.WorkerPodImage = &cdiv1.WorkerPodImage{Registry: "mirror.example.com/kubevirt", PullPolicy: corev1.PullIfNotPresent}
+			pvc := CreatePvcInStorageClass("test", metav1.NamespaceDefault, &scName, nil, nil, corev1.ClaimBound)
+			reconciler := createCloneReconciler(dv, pvc, sc)
+			reconciler.importerImage = "quay.io/kubevirt/cdi-importer:v1.57.0"
+			reconciler.pullPolicy = "Always"
+			cdiConfig := &cdiv1.CDIConfig{}
+			Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+			cdiConfig.Spec.AllowedWorkerPodRegistries = []string{"mirror.example.com/kubevirt"}
+			Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+
+			pod, err := reconciler.makeSizeDetectionPodSpec(pvc, dv)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pod.Spec.Containers[0].Image).To(Equal("mirror.example.com/kubevirt/cdi-importer:v1.57.0"))
+			Expect(pod.Spec.Containers[0].ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+		})
+
+		It("Should not create the size-detection pod with a worker pod image registry not allowed by the CDIConfig", func() {
+			dv := newCloneDataVolumeWithEmptyStorage("test-dv", "default")
+			dv.Spec.WorkerPodImage = &cdiv1.WorkerPodImage{Registry: "mirror.example.com/kubevirt"}
+			pvc := CreatePvcInStorageClass("test", metav1.NamespaceDefault, &scName, nil, nil, corev1.ClaimBound)
+			reconciler := createCloneReconciler(dv, pvc, sc)
+
+			_, err := reconciler.getOrCreateSizeDetectionPod(pvc, dv)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`image registry "mirror.example.com/kubevirt" is not allowed by the CDIConfig`))
+		})
+
 		It("Should get the size from the size-detection pod", func() {
 			dv := newCloneDataVolumeWithEmptyStorage("test-dv", "default")
 			cloneStrategy := cdiv1.CloneStrategyHostAssisted
@@ -836,7 +1499,8 @@ var _ = Describe("All DataVolume Tests", func() {
 			reconciler := createCloneReconciler(dv, pvc, storageProfile, sc)
 
 			// Prepare the size-detection Pod with the required information
-			pod := reconciler.makeSizeDetectionPodSpec(pvc, dv)
+			pod, err := reconciler.makeSizeDetectionPodSpec(pvc, dv)
+			Expect(err).ToNot(HaveOccurred())
 			pod.Status.Phase = corev1.PodSucceeded
 			pod.Status.ContainerStatuses = []corev1.ContainerStatus{
 				{
@@ -848,7 +1512,7 @@ var _ = Describe("All DataVolume Tests", func() {
 					},
 				},
 			}
-			err := reconciler.client.Create(context.TODO(), pod)
+			err = reconciler.client.Create(context.TODO(), pod)
 			Expect(err).ToNot(HaveOccurred())
 
 			// Get the expected value
@@ -925,6 +1589,39 @@ var _ = Describe("All DataVolume Tests", func() {
 			Entry("csiClone with empty size and 'Filesystem' volume mode", cdiv1.CloneStrategyCsiClone, CsiClone, FilesystemMode),
 		)
 	})
+
+	var _ = Describe("Clone converting the volume mode", func() {
+		DescribeTable("Should adjust the size of the target",
+			func(sourceVolumeMode, targetVolumeMode corev1.PersistentVolumeMode, targetSize string, adjusted bool) {
+				dv := newCloneDataVolume("test-dv")
+				dv.Spec.PVC.VolumeMode = &targetVolumeMode
+				sourcePvc := CreatePvcInStorageClass("test", metav1.NamespaceDefault, nil, map[string]string{}, nil, corev1.ClaimBound)
+				sourcePvc.Spec.VolumeMode = &sourceVolumeMode
+				reconciler = createCloneReconciler(dv, sourcePvc)
+				cdiConfig := &cdiv1.CDIConfig{}
+				Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+				cdiConfig.Status.FilesystemOverhead = &cdiv1.FilesystemOverhead{Global: "0.1"}
+				Expect(reconciler.client.Status().Update(context.TODO(), cdiConfig)).To(Succeed())
+
+				pvcSpec := dv.Spec.PVC.DeepCopy()
+				pvcSpec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse(targetSize)
+				Expect(reconciler.adjustConvertedCloneSize(&dvSyncState{dv: dv, dvMutated: dv.DeepCopy(), pvcSpec: pvcSpec}, sourcePvc)).To(Succeed())
+				requested := pvcSpec.Resources.Requests[corev1.ResourceStorage]
+				if adjusted {
+					// The 1G disk.img and the 10% filesystem overhead
+					Expect(requested.Cmp(resource.MustParse("1111111112"))).To(BeNumerically(">=", 0))
+					Expect(reconciler.recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring(CloneVolumeModeSizeAdjusted)))
+				} else {
+					Expect(requested.Cmp(resource.MustParse(targetSize))).To(Equal(0))
+					Expect(reconciler.recorder.(*record.FakeRecorder).Events).To(BeEmpty())
+				}
+			},
+			Entry("Block to filesystem target of the source size", BlockMode, FilesystemMode, "1G", true),
+			Entry("Block to filesystem target large enough", BlockMode, FilesystemMode, "2G", false),
+			Entry("Filesystem to block target smaller than the source", FilesystemMode, BlockMode, "900M", false),
+			Entry("Matching block volume modes", BlockMode, BlockMode, "1G", false),
+		)
+	})
 })
 
 func podUsingCloneSource(dv *cdiv1.DataVolume, readOnly bool) *corev1.Pod {
@@ -992,11 +1689,12 @@ func createCloneReconcilerWithoutConfig(objects ...runtime.Object) *PvcCloneReco
 	r := &PvcCloneReconciler{
 		CloneReconcilerBase: CloneReconcilerBase{
 			ReconcilerBase: ReconcilerBase{
-				client:       cl,
-				scheme:       s,
-				log:          dvCloneLog,
-				recorder:     rec,
-				featureGates: featuregates.NewFeatureGates(cl),
+				client:         cl,
+				uncachedClient: cl,
+				scheme:         s,
+				log:            dvCloneLog,
+				recorder:       rec,
+				featureGates:   featuregates.NewFeatureGates(cl),
 				installerLabels: map[string]string{
 					common.AppKubernetesPartOfLabel:  "testing",
 					common.AppKubernetesVersionLabel: "v0.0.0-tests",
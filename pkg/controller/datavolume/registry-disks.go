@@ -0,0 +1,291 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datavolume
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+const (
+	// RegistryDiskFailed provides a const to indicate the import of a disk of a multi-disk registry image failed
+	RegistryDiskFailed = "RegistryDiskFailed"
+	// RegistryDisksInProgress provides a const to indicate the import of other disks of a multi-disk registry image is in progress
+	RegistryDisksInProgress = "RegistryDisksInProgress"
+	// RegistryDiskConflict provides a const to indicate the PVC of a disk exists and is not owned by the DataVolume
+	RegistryDiskConflict = "RegistryDiskConflict"
+
+	// MessageRegistryDiskFailed provides a const to form the disk import failed message
+	MessageRegistryDiskFailed = "Failed to import disk %s into PVC %s"
+	// MessageRegistryDisksInProgress provides a const to form the disks import in progress message
+	MessageRegistryDisksInProgress = "Import of %d of %d disks in progress"
+	// MessageRegistryDiskConflict provides a const to form the disk PVC conflict message
+	MessageRegistryDiskConflict = "PVC %s of disk %s already exists and is not owned by the DataVolume"
+)
+
+// getRegistryDisks returns the disks of the multi-disk registry image a DataVolume imports, nil for any other source
+func getRegistryDisks(dv *cdiv1.DataVolume) []cdiv1.RegistryDisk {
+	if dv.Spec.Source == nil || dv.Spec.Source.Registry == nil {
+		return nil
+	}
+	return dv.Spec.Source.Registry.Disks
+}
+
+// getRegistryDiskClaimName returns the name of the PVC a disk of a multi-disk registry image is imported into
+func getRegistryDiskClaimName(dv *cdiv1.DataVolume, disk cdiv1.RegistryDisk) string {
+	if disk.PVCName == "" {
+		return dv.Name
+	}
+	return disk.PVCName
+}
+
+// getRegistryDiskPath returns the path of the disk imported into the PVC of the DataVolume, empty when the registry
+// image has a single disk
+func getRegistryDiskPath(dv *cdiv1.DataVolume) string {
+	for _, disk := range getRegistryDisks(dv) {
+		if getRegistryDiskClaimName(dv, disk) == dv.Name {
+			return disk.Path
+		}
+	}
+	return ""
+}
+
+// syncRegistryDisks creates the PVCs of the disks of a multi-disk registry image other than the one imported into the
+// PVC of the DataVolume. They get the spec and the import annotations of the PVC of the DataVolume, the size of the disk
+// when it has one, and are owned by it. Returns true when the PVC of a disk conflicts with an existing one, and no PVC
+// must be created.
+func (r *ImportReconciler) syncRegistryDisks(syncState *dvSyncState) (bool, error) {
+	dv := syncState.dvMutated
+	var missing []cdiv1.RegistryDisk
+	for _, disk := range getRegistryDisks(dv) {
+		claimName := getRegistryDiskClaimName(dv, disk)
+		if claimName == dv.Name {
+			continue
+		}
+		pvc, err := r.getPVC(types.NamespacedName{Namespace: dv.Namespace, Name: claimName})
+		if err != nil {
+			return false, err
+		}
+		if pvc == nil {
+			missing = append(missing, disk)
+			continue
+		}
+		if !metav1.IsControlledBy(pvc, dv) {
+			phase := syncState.dv.Status.Phase
+			if phase == cdiv1.PhaseUnset {
+				phase = cdiv1.Pending
+			}
+			return true, r.syncDataVolumeStatusPhaseWithEvent(syncState, phase, nil, Event{
+				eventType: corev1.EventTypeWarning,
+				reason:    RegistryDiskConflict,
+				message:   fmt.Sprintf(MessageRegistryDiskConflict, claimName, disk.Path),
+			})
+		}
+		if err := r.selectRegistryDiskNode(syncState.pvc, pvc); err != nil {
+			return false, err
+		}
+	}
+	if dvIsPrePopulated(dv) {
+		return false, nil
+	}
+
+	for _, disk := range missing {
+		diskPath := disk.Path
+		pvcSpec, err := r.getRegistryDiskPvcSpec(dv, syncState.pvcSpec, disk)
+		if err != nil {
+			return false, err
+		}
+		pvc, err := r.newPersistentVolumeClaim(dv, pvcSpec, dv.Namespace, getRegistryDiskClaimName(dv, disk),
+			func(dv *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim) error {
+				if err := r.updateAnnotations(dv, pvc); err != nil {
+					return err
+				}
+				pvc.Annotations[cc.AnnRegistryDiskPath] = diskPath
+				return nil
+			})
+		if err != nil {
+			return false, err
+		}
+		util.SetRecommendedLabels(pvc, r.installerLabels, "cdi-controller")
+		if err := r.client.Create(context.TODO(), pvc); err != nil && !k8serrors.IsAlreadyExists(err) {
+			return false, err
+		}
+		r.log.V(1).Info("Registry disk PVC created", "pvc.Namespace", pvc.Namespace, "pvc.Name", pvc.Name, "disk", diskPath)
+	}
+	return false, nil
+}
+
+// getRegistryDiskPvcSpec returns the spec of the PVC of a disk of a multi-disk registry image, the one of the PVC of the
+// DataVolume requesting the size of the disk when it has one. The size of the disk is inflated with the filesystem
+// overhead like the size of the storage of the DataVolume.
+func (r *ImportReconciler) getRegistryDiskPvcSpec(dv *cdiv1.DataVolume, dvPvcSpec *corev1.PersistentVolumeClaimSpec, disk cdiv1.RegistryDisk) (*corev1.PersistentVolumeClaimSpec, error) {
+	pvcSpec := dvPvcSpec.DeepCopy()
+	if disk.Size == nil {
+		return pvcSpec, nil
+	}
+	size := disk.Size.DeepCopy()
+	if dv.Spec.Storage != nil {
+		var err error
+		if size, err = inflateSizeWithOverhead(r.client, disk.Size.Value(), pvcSpec); err != nil {
+			return nil, err
+		}
+	}
+	if pvcSpec.Resources.Requests == nil {
+		pvcSpec.Resources.Requests = corev1.ResourceList{}
+	}
+	pvcSpec.Resources.Requests[corev1.ResourceStorage] = size
+	return pvcSpec, nil
+}
+
+// selectRegistryDiskNode binds the pending PVC of a disk of a multi-disk registry image in a WaitForFirstConsumer
+// storage class on the node selected for the PVC of the DataVolume. The consumer of the DataVolume only consumes its
+// PVC, the PVCs of the other disks would otherwise never be bound nor imported.
+func (r *ImportReconciler) selectRegistryDiskNode(dvPvc, pvc *corev1.PersistentVolumeClaim) error {
+	if dvPvc == nil || dvPvc.Annotations[cc.AnnSelectedNode] == "" || pvc.Annotations[cc.AnnSelectedNode] != "" {
+		return nil
+	}
+	waitForFirstConsumer, err := r.shouldBeMarkedWaitForFirstConsumer(pvc)
+	if err != nil || !waitForFirstConsumer {
+		return err
+	}
+	if pvc.Annotations == nil {
+		pvc.Annotations = map[string]string{}
+	}
+	pvc.Annotations[cc.AnnSelectedNode] = dvPvc.Annotations[cc.AnnSelectedNode]
+	r.log.V(1).Info("Registry disk PVC bound on the node of the DataVolume", "pvc.Namespace", pvc.Namespace, "pvc.Name", pvc.Name, "node", pvc.Annotations[cc.AnnSelectedNode])
+	return r.updatePVC(pvc)
+}
+
+// updateRegistryDisksStatus reports the phase of each disk of a multi-disk registry image in the status of the
+// DataVolume, the phase of the DataVolume being the one of its own disk. The DataVolume fails as soon as a disk fails,
+// the import of the other disks goes on and their PVCs are kept. It succeeds once all the disks are imported.
+func (r *ReconcilerBase) updateRegistryDisksStatus(dataVolumeCopy *cdiv1.DataVolume, event *Event) error {
+	disks := getRegistryDisks(dataVolumeCopy)
+	if len(disks) == 0 {
+		return nil
+	}
+
+	statuses := make([]cdiv1.DataVolumeDiskStatus, 0, len(disks))
+	var failed *cdiv1.DataVolumeDiskStatus
+	succeeded := 0
+	for _, disk := range disks {
+		status := cdiv1.DataVolumeDiskStatus{
+			Path:      disk.Path,
+			ClaimName: getRegistryDiskClaimName(dataVolumeCopy, disk),
+			Phase:     dataVolumeCopy.Status.Phase,
+		}
+		if status.ClaimName != dataVolumeCopy.Name {
+			pvc, err := r.getPVC(types.NamespacedName{Namespace: dataVolumeCopy.Namespace, Name: status.ClaimName})
+			if err != nil {
+				return err
+			}
+			status.Phase = getRegistryDiskPhase(pvc)
+			if status.Phase == cdiv1.Pending && pvc != nil {
+				waitForFirstConsumer, err := r.shouldBeMarkedWaitForFirstConsumer(pvc)
+				if err != nil {
+					return err
+				}
+				if waitForFirstConsumer {
+					status.Phase = cdiv1.WaitForFirstConsumer
+				}
+			}
+		}
+		switch status.Phase {
+		case cdiv1.Succeeded:
+			succeeded++
+		case cdiv1.Failed:
+			if failed == nil {
+				failed = &status
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	dataVolumeCopy.Status.Disks = statuses
+
+	switch {
+	case failed != nil:
+		dataVolumeCopy.Status.Phase = cdiv1.Failed
+		event.eventType = corev1.EventTypeWarning
+		event.reason = RegistryDiskFailed
+		event.message = fmt.Sprintf(MessageRegistryDiskFailed, failed.Path, failed.ClaimName)
+	case dataVolumeCopy.Status.Phase == cdiv1.Succeeded && succeeded < len(disks):
+		dataVolumeCopy.Status.Phase = cdiv1.ImportInProgress
+		event.eventType = corev1.EventTypeNormal
+		event.reason = RegistryDisksInProgress
+		event.message = fmt.Sprintf(MessageRegistryDisksInProgress, len(disks)-succeeded, len(disks))
+	}
+	return nil
+}
+
+// getRegistryDiskPhase returns the phase of the import of a disk of a multi-disk registry image into its PVC
+func getRegistryDiskPhase(pvc *corev1.PersistentVolumeClaim) cdiv1.DataVolumePhase {
+	if pvc == nil {
+		return cdiv1.Pending
+	}
+	if pvc.Status.Phase == corev1.ClaimLost {
+		return cdiv1.Failed
+	}
+	switch pvc.Annotations[cc.AnnPodPhase] {
+	case string(corev1.PodSucceeded):
+		return cdiv1.Succeeded
+	case string(corev1.PodFailed):
+		if pvc.Annotations[cc.AnnImportAttemptsExhausted] == "true" {
+			return cdiv1.Failed
+		}
+		return cdiv1.ImportInProgress
+	case string(corev1.PodRunning):
+		return cdiv1.ImportInProgress
+	case string(corev1.PodPending):
+		return cdiv1.ImportScheduled
+	}
+	if pvc.Status.Phase == corev1.ClaimBound {
+		return cdiv1.PVCBound
+	}
+	return cdiv1.Pending
+}
+
+// detachRegistryDiskPvcs removes the DataVolume from the owners of the PVCs of the disks of a multi-disk registry
+// image, so they are kept when the DataVolume is garbage collected
+func (r *ReconcilerBase) detachRegistryDiskPvcs(dv *cdiv1.DataVolume) error {
+	for _, disk := range getRegistryDisks(dv) {
+		claimName := getRegistryDiskClaimName(dv, disk)
+		if claimName == dv.Name {
+			continue
+		}
+		pvc, err := r.getPVC(types.NamespacedName{Namespace: dv.Namespace, Name: claimName})
+		if err != nil {
+			return err
+		}
+		if pvc == nil || !metav1.IsControlledBy(pvc, dv) {
+			continue
+		}
+		updatePvcOwnerRefs(pvc, dv)
+		if err := r.updatePVC(pvc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
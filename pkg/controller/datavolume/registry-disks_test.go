@@ -0,0 +1,249 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datavolume
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	. "kubevirt.io/containerized-data-importer/pkg/controller/common"
+)
+
+var _ = Describe("DataVolume importing a multi-disk registry image", func() {
+	const dataPvcName = "test-dv-data"
+	storageClassName := "sc"
+	url := "docker://registry:5000/appliance"
+	dvKey := types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}
+	dataPvcKey := types.NamespacedName{Name: dataPvcName, Namespace: metav1.NamespaceDefault}
+
+	newMultiDiskDataVolume := func() *cdiv1.DataVolume {
+		dv := newImportDataVolumeWithPvc("test-dv", &corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1G")},
+			},
+			StorageClassName: &storageClassName,
+		})
+		dv.Spec.Source = &cdiv1.DataVolumeSource{
+			Registry: &cdiv1.DataVolumeSourceRegistry{
+				URL: &url,
+				Disks: []cdiv1.RegistryDisk{
+					{Path: "os.qcow2"},
+					{Path: "data.qcow2", PVCName: dataPvcName},
+				},
+			},
+		}
+		return dv
+	}
+
+	newDiskPvc := func(dv *cdiv1.DataVolume, name string, podPhase corev1.PodPhase) *corev1.PersistentVolumeClaim {
+		pvc := CreatePvcInStorageClass(name, metav1.NamespaceDefault, &storageClassName,
+			map[string]string{AnnPodPhase: string(podPhase), AnnImportPod: "importer-" + name}, nil, corev1.ClaimBound)
+		pvc.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(dv, cdiv1.SchemeGroupVersion.WithKind("DataVolume"))}
+		return pvc
+	}
+
+	reconcileMultiDisk := func(objects ...runtime.Object) *ImportReconciler {
+		objects = append(objects, CreateStorageClass(storageClassName, nil))
+		reconciler := createImportReconciler(objects...)
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: dvKey})
+		Expect(err).ToNot(HaveOccurred())
+		return reconciler
+	}
+
+	getDataVolume := func(reconciler *ImportReconciler) *cdiv1.DataVolume {
+		dv := &cdiv1.DataVolume{}
+		Expect(reconciler.client.Get(context.TODO(), dvKey, dv)).To(Succeed())
+		return dv
+	}
+
+	expectEvent := func(reconciler *ImportReconciler, substrings ...string) {
+		close(reconciler.recorder.(*record.FakeRecorder).Events)
+		found := false
+		for event := range reconciler.recorder.(*record.FakeRecorder).Events {
+			matches := true
+			for _, s := range substrings {
+				matches = matches && strings.Contains(event, s)
+			}
+			found = found || matches
+		}
+		Expect(found).To(BeTrue())
+	}
+
+	It("Should create a PVC for each disk", func() {
+		reconciler := reconcileMultiDisk(newMultiDiskDataVolume())
+
+		pvc := &corev1.PersistentVolumeClaim{}
+		Expect(reconciler.client.Get(context.TODO(), dvKey, pvc)).To(Succeed())
+		Expect(pvc.Annotations[AnnSource]).To(Equal(SourceRegistry))
+		Expect(pvc.Annotations[AnnEndpoint]).To(Equal(url))
+		Expect(pvc.Annotations[AnnRegistryDiskPath]).To(Equal("os.qcow2"))
+
+		dataPvc := &corev1.PersistentVolumeClaim{}
+		Expect(reconciler.client.Get(context.TODO(), dataPvcKey, dataPvc)).To(Succeed())
+		Expect(dataPvc.Annotations[AnnSource]).To(Equal(SourceRegistry))
+		Expect(dataPvc.Annotations[AnnEndpoint]).To(Equal(url))
+		Expect(dataPvc.Annotations[AnnRegistryDiskPath]).To(Equal("data.qcow2"))
+		Expect(dataPvc.Spec.Resources).To(Equal(pvc.Spec.Resources))
+		Expect(*dataPvc.Spec.StorageClassName).To(Equal(storageClassName))
+		Expect(metav1.GetControllerOf(dataPvc).Name).To(Equal("test-dv"))
+
+		dv := getDataVolume(reconciler)
+		Expect(dv.Status.Disks).To(HaveLen(2))
+		Expect(dv.Status.Disks[0].ClaimName).To(Equal("test-dv"))
+		Expect(dv.Status.Disks[1].ClaimName).To(Equal(dataPvcName))
+		Expect(dv.Status.Disks[1].Path).To(Equal("data.qcow2"))
+	})
+
+	It("Should request the size of a disk for its PVC", func() {
+		dv := newMultiDiskDataVolume()
+		dataDiskSize := resource.MustParse("5G")
+		dv.Spec.Source.Registry.Disks[1].Size = &dataDiskSize
+		reconciler := reconcileMultiDisk(dv)
+
+		pvc := &corev1.PersistentVolumeClaim{}
+		Expect(reconciler.client.Get(context.TODO(), dvKey, pvc)).To(Succeed())
+		Expect(pvc.Spec.Resources.Requests[corev1.ResourceStorage]).To(Equal(resource.MustParse("1G")))
+		dataPvc := &corev1.PersistentVolumeClaim{}
+		Expect(reconciler.client.Get(context.TODO(), dataPvcKey, dataPvc)).To(Succeed())
+		Expect(dataPvc.Spec.Resources.Requests[corev1.ResourceStorage]).To(Equal(dataDiskSize))
+	})
+
+	Context("in a WaitForFirstConsumer storage class", func() {
+		newPendingDiskPvc := func(dv *cdiv1.DataVolume, name string) *corev1.PersistentVolumeClaim {
+			pvc := newDiskPvc(dv, name, "")
+			delete(pvc.Annotations, AnnPodPhase)
+			pvc.Status.Phase = corev1.ClaimPending
+			return pvc
+		}
+
+		reconcileWaitForFirstConsumer := func(objects ...runtime.Object) *ImportReconciler {
+			objects = append(objects, createStorageClassWithBindingMode(storageClassName, nil, storagev1.VolumeBindingWaitForFirstConsumer))
+			reconciler := createImportReconciler(objects...)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: dvKey})
+			Expect(err).ToNot(HaveOccurred())
+			return reconciler
+		}
+
+		It("Should report the disks waiting for their first consumer", func() {
+			dv := newMultiDiskDataVolume()
+			reconciler := reconcileWaitForFirstConsumer(dv, newPendingDiskPvc(dv, "test-dv"), newPendingDiskPvc(dv, dataPvcName))
+
+			dv = getDataVolume(reconciler)
+			Expect(dv.Status.Phase).To(Equal(cdiv1.WaitForFirstConsumer))
+			Expect(dv.Status.Disks).To(ConsistOf(
+				cdiv1.DataVolumeDiskStatus{Path: "os.qcow2", ClaimName: "test-dv", Phase: cdiv1.WaitForFirstConsumer},
+				cdiv1.DataVolumeDiskStatus{Path: "data.qcow2", ClaimName: dataPvcName, Phase: cdiv1.WaitForFirstConsumer},
+			))
+			dataPvc := &corev1.PersistentVolumeClaim{}
+			Expect(reconciler.client.Get(context.TODO(), dataPvcKey, dataPvc)).To(Succeed())
+			Expect(dataPvc.Annotations).ToNot(HaveKey(AnnSelectedNode))
+		})
+
+		It("Should bind the PVCs of the disks on the node selected for the PVC of the DataVolume", func() {
+			dv := newMultiDiskDataVolume()
+			pvc := newPendingDiskPvc(dv, "test-dv")
+			pvc.Annotations[AnnSelectedNode] = "node01"
+			reconciler := reconcileWaitForFirstConsumer(dv, pvc, newPendingDiskPvc(dv, dataPvcName))
+
+			dataPvc := &corev1.PersistentVolumeClaim{}
+			Expect(reconciler.client.Get(context.TODO(), dataPvcKey, dataPvc)).To(Succeed())
+			Expect(dataPvc.Annotations[AnnSelectedNode]).To(Equal("node01"))
+		})
+	})
+
+	It("Should succeed once all the disks are imported", func() {
+		dv := newMultiDiskDataVolume()
+		reconciler := reconcileMultiDisk(dv, newDiskPvc(dv, "test-dv", corev1.PodSucceeded), newDiskPvc(dv, dataPvcName, corev1.PodSucceeded))
+
+		dv = getDataVolume(reconciler)
+		Expect(dv.Status.Phase).To(Equal(cdiv1.Succeeded))
+		Expect(dv.Status.Disks).To(ConsistOf(
+			cdiv1.DataVolumeDiskStatus{Path: "os.qcow2", ClaimName: "test-dv", Phase: cdiv1.Succeeded},
+			cdiv1.DataVolumeDiskStatus{Path: "data.qcow2", ClaimName: dataPvcName, Phase: cdiv1.Succeeded},
+		))
+		readyCondition := FindConditionByType(cdiv1.DataVolumeReady, dv.Status.Conditions)
+		Expect(readyCondition.Status).To(Equal(corev1.ConditionTrue))
+	})
+
+	It("Should wait for the other disks once the disk of the DataVolume is imported", func() {
+		dv := newMultiDiskDataVolume()
+		reconciler := reconcileMultiDisk(dv, newDiskPvc(dv, "test-dv", corev1.PodSucceeded), newDiskPvc(dv, dataPvcName, corev1.PodRunning))
+
+		dv = getDataVolume(reconciler)
+		Expect(dv.Status.Phase).To(Equal(cdiv1.ImportInProgress))
+		Expect(dv.Status.Disks).To(ConsistOf(
+			cdiv1.DataVolumeDiskStatus{Path: "os.qcow2", ClaimName: "test-dv", Phase: cdiv1.Succeeded},
+			cdiv1.DataVolumeDiskStatus{Path: "data.qcow2", ClaimName: dataPvcName, Phase: cdiv1.ImportInProgress},
+		))
+		readyCondition := FindConditionByType(cdiv1.DataVolumeReady, dv.Status.Conditions)
+		Expect(readyCondition.Status).To(Equal(corev1.ConditionFalse))
+		expectEvent(reconciler, RegistryDisksInProgress, "Import of 1 of 2 disks in progress")
+	})
+
+	It("Should fail when a disk fails, and keep the PVCs of all the disks", func() {
+		dv := newMultiDiskDataVolume()
+		dataPvc := newDiskPvc(dv, dataPvcName, corev1.PodFailed)
+		dataPvc.Annotations[AnnImportAttemptsExhausted] = "true"
+		reconciler := reconcileMultiDisk(dv, newDiskPvc(dv, "test-dv", corev1.PodSucceeded), dataPvc)
+
+		dv = getDataVolume(reconciler)
+		Expect(dv.Status.Phase).To(Equal(cdiv1.Failed))
+		Expect(dv.Status.Disks).To(ConsistOf(
+			cdiv1.DataVolumeDiskStatus{Path: "os.qcow2", ClaimName: "test-dv", Phase: cdiv1.Succeeded},
+			cdiv1.DataVolumeDiskStatus{Path: "data.qcow2", ClaimName: dataPvcName, Phase: cdiv1.Failed},
+		))
+		readyCondition := FindConditionByType(cdiv1.DataVolumeReady, dv.Status.Conditions)
+		Expect(readyCondition.Status).To(Equal(corev1.ConditionFalse))
+		expectEvent(reconciler, RegistryDiskFailed, "Failed to import disk data.qcow2 into PVC "+dataPvcName)
+		Expect(reconciler.client.Get(context.TODO(), dvKey, &corev1.PersistentVolumeClaim{})).To(Succeed())
+		Expect(reconciler.client.Get(context.TODO(), dataPvcKey, &corev1.PersistentVolumeClaim{})).To(Succeed())
+	})
+
+	It("Should keep importing a disk whose importer pod restarts", func() {
+		dv := newMultiDiskDataVolume()
+		reconciler := reconcileMultiDisk(dv, newDiskPvc(dv, "test-dv", corev1.PodSucceeded), newDiskPvc(dv, dataPvcName, corev1.PodFailed))
+
+		dv = getDataVolume(reconciler)
+		Expect(dv.Status.Phase).To(Equal(cdiv1.ImportInProgress))
+		Expect(dv.Status.Disks[1].Phase).To(Equal(cdiv1.ImportInProgress))
+	})
+
+	It("Should not create any PVC when the PVC of a disk is not owned by the DataVolume", func() {
+		dataPvc := CreatePvcInStorageClass(dataPvcName, metav1.NamespaceDefault, &storageClassName, nil, nil, corev1.ClaimBound)
+		reconciler := reconcileMultiDisk(newMultiDiskDataVolume(), dataPvc)
+
+		err := reconciler.client.Get(context.TODO(), dvKey, &corev1.PersistentVolumeClaim{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		Expect(getDataVolume(reconciler).Status.Phase).To(Equal(cdiv1.Pending))
+		expectEvent(reconciler, RegistryDiskConflict, "PVC "+dataPvcName+" of disk data.qcow2 already exists")
+	})
+})
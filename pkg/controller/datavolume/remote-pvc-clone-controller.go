@@ -0,0 +1,580 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datavolume
+
+import (
+	"context"
+	"crypto/rsa"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+	featuregates "kubevirt.io/containerized-data-importer/pkg/feature-gates"
+	"kubevirt.io/containerized-data-importer/pkg/token"
+	"kubevirt.io/containerized-data-importer/pkg/util/cert/fetcher"
+)
+
+const (
+	// RemoteCloneSourceFailed provides a const to indicate the source pod of a remote PVC clone has failed
+	RemoteCloneSourceFailed = "RemoteCloneSourceFailed"
+	// RemoteCloneSourceCleanupFailed provides a const to indicate the source pod of a remote PVC clone was not deleted
+	RemoteCloneSourceCleanupFailed = "RemoteCloneSourceCleanupFailed"
+
+	// MessageRemoteCloneSourceFailed provides a const to form remote clone source pod has failed message
+	MessageRemoteCloneSourceFailed = "Source pod %s of the remote clone failed, retrying: %s"
+	// MessageRemoteCloneSourceCleanupFailed provides a const to form remote clone source pod was not deleted message
+	MessageRemoteCloneSourceCleanupFailed = "Unable to delete source pod %s of the remote clone: %s"
+
+	remotePvcCloneControllerName = "datavolume-remote-pvc-clone-controller"
+
+	// remotePvcCloneFinalizer is set while the source pod of a remote PVC clone may exist in the remote cluster
+	remotePvcCloneFinalizer = "cdi.kubevirt.io/remotePVCCloneFinalizer"
+
+	// remoteCloneTokenLifetime is the lifetime of the upload token of a remote clone source pod, long enough for the
+	// remote cluster to pull its image
+	remoteCloneTokenLifetime = time.Hour
+
+	// remoteCloneSourcePollInterval is the interval the remote clone source pod is polled at, it is not watched
+	remoteCloneSourcePollInterval = 10 * time.Second
+)
+
+// RemoteClientFunc creates a client of the remote cluster of a remote PVC clone source from its kubeconfig
+type RemoteClientFunc func(kubeconfig []byte) (kubernetes.Interface, error)
+
+// RemotePvcCloneReconciler members
+type RemotePvcCloneReconciler struct {
+	ReconcilerBase
+	clonerImage              string
+	pullPolicy               string
+	tokenGenerator           token.Generator
+	uploadProxyBundleFetcher fetcher.CertBundleFetcher
+	newRemoteClient          RemoteClientFunc
+}
+
+// NewRemotePvcCloneController creates a new instance of the datavolume remote PVC clone controller
+func NewRemotePvcCloneController(
+	ctx context.Context,
+	mgr manager.Manager,
+	log logr.Logger,
+	clonerImage string,
+	pullPolicy string,
+	tokenPrivateKey *rsa.PrivateKey,
+	uploadProxyBundleFetcher fetcher.CertBundleFetcher,
+	installerLabels map[string]string,
+) (controller.Controller, error) {
+	client := mgr.GetClient()
+	reconciler := &RemotePvcCloneReconciler{
+		ReconcilerBase: ReconcilerBase{
+			client:          client,
+			uncachedClient:  mgr.GetAPIReader(),
+			scheme:          mgr.GetScheme(),
+			log:             log.WithName(remotePvcCloneControllerName),
+			controllerName:  remotePvcCloneControllerName,
+			recorder:        mgr.GetEventRecorderFor(remotePvcCloneControllerName),
+			featureGates:    featuregates.NewFeatureGates(client),
+			installerLabels: installerLabels,
+		},
+		clonerImage:              clonerImage,
+		pullPolicy:               pullPolicy,
+		tokenGenerator:           newRemoteCloneTokenGenerator(tokenPrivateKey),
+		uploadProxyBundleFetcher: uploadProxyBundleFetcher,
+		newRemoteClient:          newRemoteClient,
+	}
+
+	datavolumeController, err := controller.New(remotePvcCloneControllerName, mgr, controller.Options{
+		Reconciler: reconciler,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := addDataVolumeControllerCommonWatches(mgr, datavolumeController, dataVolumeRemotePvcClone); err != nil {
+		return nil, err
+	}
+	return datavolumeController, nil
+}
+
+// newRemoteCloneTokenGenerator creates the generator of the upload tokens of the remote clone source pods, validated
+// by the upload proxy like the tokens of the apiserver
+func newRemoteCloneTokenGenerator(key *rsa.PrivateKey) token.Generator {
+	return token.NewGenerator(common.UploadTokenIssuer, key, remoteCloneTokenLifetime)
+}
+
+// newRemoteClient creates the client of the remote cluster from the kubeconfig of the user. The kubeconfig is loaded
+// by the controller, so it may only hold inline credentials: a command, an auth provider or a file path would be run
+// or read with the privileges of the controller.
+func newRemoteClient(kubeconfig []byte) (kubernetes.Interface, error) {
+	config, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateRemoteKubeconfig(config); err != nil {
+		return nil, err
+	}
+	restConfig, err := clientcmd.NewDefaultClientConfig(*config, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(restConfig)
+}
+
+// validateRemoteKubeconfig validates that the kubeconfig of a remote cluster only holds an inline token or inline
+// client certificate and key as credentials, and an inline certificate authority
+func validateRemoteKubeconfig(config *clientcmdapi.Config) error {
+	for name, cluster := range config.Clusters {
+		if cluster.CertificateAuthority != "" {
+			return errors.Errorf("cluster %s sets certificate-authority, only certificate-authority-data is supported", name)
+		}
+	}
+	for name, authInfo := range config.AuthInfos {
+		var field string
+		switch {
+		case authInfo.Exec != nil:
+			field = "exec"
+		case authInfo.AuthProvider != nil:
+			field = "auth-provider"
+		case authInfo.TokenFile != "":
+			field = "tokenFile"
+		case authInfo.ClientCertificate != "":
+			field = "client-certificate"
+		case authInfo.ClientKey != "":
+			field = "client-key"
+		default:
+			continue
+		}
+		return errors.Errorf("user %s sets %s, only token or client-certificate-data and client-key-data are supported", name, field)
+	}
+	return nil
+}
+
+func (r *RemotePvcCloneReconciler) updateAnnotations(dataVolume *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim) error {
+	if dataVolume.Spec.Source.RemotePVC == nil {
+		return errors.Errorf("no source set for remote PVC clone datavolume")
+	}
+	// The target is populated by an upload server, the remote source streams to it through the upload proxy
+	pvc.Annotations[cc.AnnUploadRequest] = ""
+	return nil
+}
+
+// Reconcile loop for the remote PVC clone data volumes
+func (r *RemotePvcCloneReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	return r.reconcile(ctx, req, r)
+}
+
+func (r *RemotePvcCloneReconciler) sync(log logr.Logger, req reconcile.Request) (dvSyncResult, error) {
+	syncState, err := r.syncRemoteClone(log, req)
+	if err == nil {
+		err = r.syncUpdate(log, &syncState)
+	}
+	return syncState.dvSyncResult, err
+}
+
+func (r *RemotePvcCloneReconciler) syncRemoteClone(log logr.Logger, req reconcile.Request) (dvSyncState, error) {
+	syncState, syncErr := r.syncCommon(log, req, r.cleanup, nil)
+	if syncErr != nil || syncState.result != nil {
+		return syncState, syncErr
+	}
+	if syncState.pvc == nil {
+		if err := r.adjustRemoteConvertedCloneSize(&syncState); err != nil {
+			return syncState, err
+		}
+	}
+	if err := r.handlePvcCreation(log, &syncState, r.updateAnnotations); err != nil {
+		return syncState, err
+	}
+
+	pvc := syncState.pvc
+	dv := syncState.dvMutated
+	if pvc == nil || pvcIsPopulated(pvc, dv) {
+		return syncState, nil
+	}
+	if pvc.Annotations[cc.AnnPodPhase] == string(corev1.PodSucceeded) {
+		return syncState, r.cleanupRemoteSource(log, dv)
+	}
+	if pvc.Annotations[cc.AnnPodReady] != "true" {
+		// Waiting for the upload server to be ready before streaming to it
+		return syncState, nil
+	}
+	if !cc.HasFinalizer(dv, remotePvcCloneFinalizer) {
+		cc.AddFinalizer(dv, remotePvcCloneFinalizer)
+		return syncState, nil
+	}
+
+	requeue, err := r.reconcileRemoteSource(log, dv, pvc)
+	if err != nil {
+		return syncState, err
+	}
+	if requeue {
+		syncState.result = &reconcile.Result{RequeueAfter: remoteCloneSourcePollInterval}
+	}
+	return syncState, nil
+}
+
+// adjustRemoteConvertedCloneSize enlarges the target of a remote clone converting the block source into a disk.img file
+func (r *RemotePvcCloneReconciler) adjustRemoteConvertedCloneSize(syncState *dvSyncState) error {
+	dv := syncState.dvMutated
+	if dvIsPrePopulated(dv) || syncState.pvcSpec == nil {
+		return nil
+	}
+	remote, err := r.getRemoteClient(dv)
+	if err != nil {
+		return err
+	}
+	source := dv.Spec.Source.RemotePVC
+	sourcePvc, err := remote.CoreV1().PersistentVolumeClaims(source.Namespace).Get(context.TODO(), source.Name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "unable to get the remote source PVC %s/%s", source.Namespace, source.Name)
+	}
+	return r.adjustConvertedCloneSize(syncState, sourcePvc)
+}
+
+func (r *RemotePvcCloneReconciler) cleanup(syncState *dvSyncState) error {
+	return r.cleanupRemoteSource(r.log, syncState.dvMutated)
+}
+
+// cleanupRemoteSource deletes the source pod of the remote clone and its token secret. The deletion is best effort,
+// an unreachable remote cluster does not block the DataVolume.
+func (r *RemotePvcCloneReconciler) cleanupRemoteSource(log logr.Logger, dv *cdiv1.DataVolume) error {
+	if !cc.HasFinalizer(dv, remotePvcCloneFinalizer) {
+		return nil
+	}
+	name := remoteCloneSourceName(dv)
+	if err := r.deleteRemoteSource(dv, name); err != nil {
+		log.Error(err, "Unable to delete the remote clone source", "name", name)
+		r.recorder.Eventf(dv, corev1.EventTypeWarning, RemoteCloneSourceCleanupFailed, MessageRemoteCloneSourceCleanupFailed, name, err.Error())
+	}
+	cc.RemoveFinalizer(dv, remotePvcCloneFinalizer)
+	return nil
+}
+
+func (r *RemotePvcCloneReconciler) deleteRemoteSource(dv *cdiv1.DataVolume, name string) error {
+	remote, err := r.getRemoteClient(dv)
+	if err != nil {
+		return err
+	}
+	namespace := dv.Spec.Source.RemotePVC.Namespace
+	if err := remote.CoreV1().Pods(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	if err := remote.CoreV1().Secrets(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// reconcileRemoteSource creates the source pod of the remote clone, and recreates it with a new upload token if it
+// failed. It returns whether the pod should be polled.
+func (r *RemotePvcCloneReconciler) reconcileRemoteSource(log logr.Logger, dv *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim) (bool, error) {
+	remote, err := r.getRemoteClient(dv)
+	if err != nil {
+		return false, err
+	}
+	name := remoteCloneSourceName(dv)
+	namespace := dv.Spec.Source.RemotePVC.Namespace
+	pod, err := remote.CoreV1().Pods(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return false, err
+		}
+		return true, r.createRemoteSource(log, remote, dv, pvc, name)
+	}
+
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		// The upload server completes the clone
+		return false, nil
+	case corev1.PodFailed:
+		r.recorder.Eventf(dv, corev1.EventTypeWarning, RemoteCloneSourceFailed, MessageRemoteCloneSourceFailed, name, remoteCloneSourceFailure(pod))
+		if err := remote.CoreV1().Pods(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (r *RemotePvcCloneReconciler) createRemoteSource(log logr.Logger, remote kubernetes.Interface, dv *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim, name string) error {
+	source := dv.Spec.Source.RemotePVC
+	sourcePvc, err := remote.CoreV1().PersistentVolumeClaims(source.Namespace).Get(context.TODO(), source.Name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "unable to get the remote source PVC %s/%s", source.Namespace, source.Name)
+	}
+	uploadURL, err := r.getUploadProxyURL()
+	if err != nil {
+		return err
+	}
+	caBundle, err := r.uploadProxyBundleFetcher.BundleBytes()
+	if err != nil {
+		return errors.Wrap(err, "unable to get the CA bundle of the upload proxy")
+	}
+	bandwidthLimit, err := cc.GetCloneBandwidthLimit(r.client, pvc)
+	if err != nil {
+		return err
+	}
+	cloneStreams, err := cc.GetCloneStreams(r.client, sourcePvc, pvc)
+	if err != nil {
+		return err
+	}
+	compression, err := cc.GetCloneCompression(r.client, pvc)
+	if err != nil {
+		return err
+	}
+	uploadToken, err := r.tokenGenerator.Generate(&token.Payload{
+		Operation: token.OperationUpload,
+		Name:      pvc.Name,
+		Namespace: pvc.Namespace,
+		Resource: metav1.GroupVersionResource{
+			Group:    "",
+			Version:  "v1",
+			Resource: "persistentvolumeclaims",
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	secret := newRemoteCloneSourceSecret(name, source.Namespace, uploadToken)
+	if _, err := remote.CoreV1().Secrets(source.Namespace).Create(context.TODO(), secret, metav1.CreateOptions{}); err != nil {
+		if !k8serrors.IsAlreadyExists(err) {
+			return err
+		}
+		if _, err := remote.CoreV1().Secrets(source.Namespace).Update(context.TODO(), secret, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	pod := newRemoteCloneSourcePod(name, r.clonerImage, r.pullPolicy, uploadURL, caBundle, bandwidthLimit, cloneStreams, compression, dv, pvc, sourcePvc)
+	if _, err := remote.CoreV1().Pods(source.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return err
+	}
+	log.V(1).Info("Created the remote clone source pod", "namespace", source.Namespace, "name", name)
+	return nil
+}
+
+// getRemoteClient creates the client of the remote cluster from the kubeconfig secret of the DataVolume namespace
+func (r *RemotePvcCloneReconciler) getRemoteClient(dv *cdiv1.DataVolume) (kubernetes.Interface, error) {
+	secretName := dv.Spec.Source.RemotePVC.KubeconfigSecretRef
+	secret := &corev1.Secret{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: dv.Namespace, Name: secretName}, secret); err != nil {
+		return nil, errors.Wrapf(err, "unable to get the kubeconfig secret %s", secretName)
+	}
+	kubeconfig, ok := secret.Data[common.KeyKubeconfig]
+	if !ok {
+		return nil, errors.Errorf("secret %s has no %s key", secretName, common.KeyKubeconfig)
+	}
+	remote, err := r.newRemoteClient(kubeconfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid kubeconfig in secret %s", secretName)
+	}
+	return remote, nil
+}
+
+// getUploadProxyURL returns the URL the remote clone source streams to, the upload proxy of this cluster
+func (r *RemotePvcCloneReconciler) getUploadProxyURL() (string, error) {
+	config := &cdiv1.CDIConfig{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, config); err != nil {
+		return "", err
+	}
+	if config.Status.UploadProxyURL == nil || *config.Status.UploadProxyURL == "" {
+		return "", errors.New("the upload proxy has no URL reachable from the remote cluster, set uploadProxyURLOverride in the CDIConfig")
+	}
+	url := *config.Status.UploadProxyURL
+	if !strings.Contains(url, "://") {
+		url = "https://" + url
+	}
+	return strings.TrimSuffix(url, "/") + common.UploadPathSync, nil
+}
+
+// remoteCloneSourceName returns the name of the source pod of a remote clone and of its token secret, unique to the
+// DataVolume as the remote namespace is shared by the clones of all the clusters
+func remoteCloneSourceName(dv *cdiv1.DataVolume) string {
+	return "cdi-remote-clone-source-" + string(dv.UID)
+}
+
+func remoteCloneSourceFailure(pod *corev1.Pod) string {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Terminated != nil && status.State.Terminated.Message != "" {
+			return status.State.Terminated.Message
+		}
+	}
+	if pod.Status.Message != "" {
+		return pod.Status.Message
+	}
+	return "unknown error"
+}
+
+func newRemoteCloneSourceSecret(name, namespace, uploadToken string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				common.CDILabelKey:       common.CDILabelValue,
+				common.CDIComponentLabel: common.ClonerSourcePodName,
+			},
+		},
+		StringData: map[string]string{
+			common.KeyToken: uploadToken,
+		},
+	}
+}
+
+// newRemoteCloneSourcePod creates the pod of the remote cluster streaming the source PVC to the upload proxy of this
+// cluster. A filesystem source only streams its disk image, which the upload server writes to a target of any mode.
+func newRemoteCloneSourcePod(name, image, pullPolicy, uploadURL string, caBundle []byte, bandwidthLimit int64, cloneStreams int32, compression string, dv *cdiv1.DataVolume, pvc, sourcePvc *corev1.PersistentVolumeClaim) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: sourcePvc.Namespace,
+			Labels: map[string]string{
+				common.CDILabelKey:       common.CDILabelValue,
+				common.CDIComponentLabel: common.ClonerSourcePodName,
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:            common.ClonerSourcePodName,
+					Image:           image,
+					ImagePullPolicy: corev1.PullPolicy(pullPolicy),
+					Env: []corev1.EnvVar{
+						{
+							Name: common.ClonerUploadToken,
+							ValueFrom: &corev1.EnvVarSource{
+								SecretKeyRef: &corev1.SecretKeySelector{
+									LocalObjectReference: corev1.LocalObjectReference{
+										Name: name,
+									},
+									Key: common.KeyToken,
+								},
+							},
+						},
+						{
+							Name:  "SERVER_CA_CERT",
+							Value: string(caBundle),
+						},
+						{
+							Name:  "UPLOAD_URL",
+							Value: uploadURL,
+						},
+						{
+							Name:  common.OwnerUID,
+							Value: string(dv.UID),
+						},
+						{
+							Name:  common.Preallocation,
+							Value: pvc.Annotations[cc.AnnPreallocationRequested],
+						},
+					},
+				},
+			},
+			RestartPolicy: corev1.RestartPolicyNever,
+			Volumes: []corev1.Volume{
+				{
+					Name: cc.DataVolName,
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: sourcePvc.Name,
+							ReadOnly:  true,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	container := &pod.Spec.Containers[0]
+	if cc.GetVolumeMode(sourcePvc) == corev1.PersistentVolumeBlock {
+		container.VolumeDevices = cc.AddVolumeDevices()
+		container.Env = append(container.Env,
+			corev1.EnvVar{Name: "VOLUME_MODE", Value: "block"},
+			corev1.EnvVar{Name: "MOUNT_POINT", Value: common.WriteBlockPath},
+		)
+	} else {
+		container.VolumeMounts = []corev1.VolumeMount{
+			{
+				Name:      cc.DataVolName,
+				MountPath: common.ClonerMountPath,
+				ReadOnly:  true,
+			},
+		}
+		container.Env = append(container.Env,
+			corev1.EnvVar{Name: "VOLUME_MODE", Value: "filesystem"},
+			corev1.EnvVar{Name: "MOUNT_POINT", Value: common.ClonerMountPath},
+			corev1.EnvVar{Name: common.ClonerSourcePath, Value: common.DiskImageName},
+		)
+	}
+	if bandwidthLimit > 0 {
+		container.Env = append(container.Env, corev1.EnvVar{Name: common.ClonerBandwidthLimit, Value: strconv.FormatInt(bandwidthLimit, 10)})
+	}
+	if cloneStreams > 1 {
+		container.Env = append(container.Env, corev1.EnvVar{Name: common.ClonerStreams, Value: strconv.Itoa(int(cloneStreams))})
+	}
+	if compression != "" {
+		container.Env = append(container.Env, corev1.EnvVar{Name: common.ClonerCompression, Value: compression})
+	}
+	cc.SetRestrictedSecurityContext(&pod.Spec)
+	return pod
+}
+
+func (r *RemotePvcCloneReconciler) updateStatusPhase(pvc *corev1.PersistentVolumeClaim, dataVolumeCopy *cdiv1.DataVolume, event *Event) error {
+	updateUploadStatusPhase(pvc, dataVolumeCopy, event)
+	if dataVolumeCopy.Spec.Source != nil && dataVolumeCopy.Spec.Source.RemotePVC != nil {
+		updateRemotePvcCloneStatusPhase(pvc, dataVolumeCopy, event)
+	}
+	return nil
+}
+
+// updateRemotePvcCloneStatusPhase reports the upload of a remote PVC clone with the phases and events of a clone
+func updateRemotePvcCloneStatusPhase(pvc *corev1.PersistentVolumeClaim, dataVolumeCopy *cdiv1.DataVolume, event *Event) {
+	switch dataVolumeCopy.Status.Phase {
+	case cdiv1.UploadScheduled:
+		dataVolumeCopy.Status.Phase = cdiv1.CloneScheduled
+	case cdiv1.UploadReady:
+		dataVolumeCopy.Status.Phase = cdiv1.CloneInProgress
+	}
+	source := dataVolumeCopy.Spec.Source.RemotePVC
+	from := fmt.Sprintf("remote PVC %s/%s", source.Namespace, source.Name)
+	switch event.reason {
+	case UploadScheduled:
+		event.reason = CloneScheduled
+		event.message = fmt.Sprintf(MessageRemoteCloneScheduled, from, pvc.Name)
+	case UploadReady:
+		event.reason = CloneInProgress
+		event.message = fmt.Sprintf(MessageRemoteCloneInProgress, from, pvc.Name)
+	case UploadFailed:
+		event.reason = CloneFailed
+		event.message = fmt.Sprintf(MessageRemoteCloneFailed, from, pvc.Name)
+	case UploadSucceeded:
+		event.reason = CloneSucceeded
+		event.message = fmt.Sprintf(MessageRemoteCloneSucceeded, from, pvc.Name)
+	}
+}
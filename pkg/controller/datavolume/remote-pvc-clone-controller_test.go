@@ -0,0 +1,393 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datavolume
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	. "kubevirt.io/containerized-data-importer/pkg/controller/common"
+	featuregates "kubevirt.io/containerized-data-importer/pkg/feature-gates"
+	"kubevirt.io/containerized-data-importer/pkg/token"
+	"kubevirt.io/containerized-data-importer/pkg/util/cert/fetcher"
+)
+
+var (
+	dvRemotePvcCloneLog = logf.Log.WithName("datavolume-remote-pvc-clone-controller-test")
+)
+
+var _ = Describe("Remote PVC clone DataVolume", func() {
+	const remotePodName = "cdi-remote-clone-source-test-uid"
+
+	var (
+		key        *rsa.PrivateKey
+		remote     *k8sfake.Clientset
+		reconciler *RemotePvcCloneReconciler
+		request    = reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}}
+	)
+
+	BeforeEach(func() {
+		var err error
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).ToNot(HaveOccurred())
+		remote = k8sfake.NewSimpleClientset(&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "golden", Name: "fedora"},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				VolumeMode: &BlockMode,
+			},
+		})
+	})
+
+	reconcileUploadReady := func() *corev1.PersistentVolumeClaim {
+		_, err := reconciler.Reconcile(context.TODO(), request)
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		Expect(reconciler.client.Get(context.TODO(), request.NamespacedName, pvc)).To(Succeed())
+		Expect(pvc.Annotations).To(HaveKey(AnnUploadRequest))
+		pvc.Annotations[AnnPodPhase] = string(corev1.PodRunning)
+		pvc.Annotations[AnnPodReady] = "true"
+		Expect(reconciler.client.Update(context.TODO(), pvc)).To(Succeed())
+		_, err = reconciler.Reconcile(context.TODO(), request)
+		Expect(err).ToNot(HaveOccurred())
+		return pvc
+	}
+
+	getRemotePod := func() (*corev1.Pod, error) {
+		return remote.CoreV1().Pods("golden").Get(context.TODO(), remotePodName, metav1.GetOptions{})
+	}
+
+	It("should stream the remote PVC to the upload proxy with an upload token of the target", func() {
+		reconciler = createRemotePvcCloneReconciler(key, remote, "cdi-uploadproxy.example.com", newRemotePvcCloneDataVolume("test-dv"))
+		reconcileUploadReady()
+		_, err := getRemotePod()
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		dv := &cdiv1.DataVolume{}
+		Expect(reconciler.client.Get(context.TODO(), request.NamespacedName, dv)).To(Succeed())
+		Expect(HasFinalizer(dv, remotePvcCloneFinalizer)).To(BeTrue())
+
+		result, err := reconciler.Reconcile(context.TODO(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(remoteCloneSourcePollInterval))
+		pod, err := getRemotePod()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+		Expect(pod.Spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal("fedora"))
+		Expect(pod.Spec.Containers[0].VolumeDevices).ToNot(BeEmpty())
+		env := map[string]string{}
+		for _, e := range pod.Spec.Containers[0].Env {
+			env[e.Name] = e.Value
+		}
+		Expect(env).To(HaveKeyWithValue("UPLOAD_URL", "https://cdi-uploadproxy.example.com"+common.UploadPathSync))
+		Expect(env).To(HaveKeyWithValue("SERVER_CA_CERT", "upload proxy CA"))
+		Expect(env).To(HaveKeyWithValue("VOLUME_MODE", "block"))
+
+		secret, err := remote.CoreV1().Secrets("golden").Get(context.TODO(), remotePodName, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		validator := token.NewValidator(common.UploadTokenIssuer, &key.PublicKey, time.Minute)
+		payload, err := validator.Validate(secret.StringData[common.KeyToken])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(payload.Operation).To(Equal(token.OperationUpload))
+		Expect(payload.Namespace).To(Equal(metav1.NamespaceDefault))
+		Expect(payload.Name).To(Equal("test-dv"))
+		Expect(payload.Resource.Resource).To(Equal("persistentvolumeclaims"))
+	})
+
+	It("should limit the bandwidth of the remote source pod", func() {
+		dv := newRemotePvcCloneDataVolume("test-dv")
+		dv.Annotations = map[string]string{AnnCloneBandwidthLimit: "10Mi"}
+		reconciler = createRemotePvcCloneReconciler(key, remote, "cdi-uploadproxy.example.com", dv)
+		reconcileUploadReady()
+		_, err := reconciler.Reconcile(context.TODO(), request)
+		Expect(err).ToNot(HaveOccurred())
+		pod, err := getRemotePod()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: common.ClonerBandwidthLimit, Value: "10485760"}))
+	})
+
+	It("should pass the compression of the clone to the remote source pod", func() {
+		dv := newRemotePvcCloneDataVolume("test-dv")
+		dv.Annotations = map[string]string{AnnCloneCompression: "zstd"}
+		reconciler = createRemotePvcCloneReconciler(key, remote, "cdi-uploadproxy.example.com", dv)
+		reconcileUploadReady()
+		_, err := reconciler.Reconcile(context.TODO(), request)
+		Expect(err).ToNot(HaveOccurred())
+		pod, err := getRemotePod()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: common.ClonerCompression, Value: "zstd"}))
+	})
+
+	It("should enlarge the filesystem target of a remote block source with the filesystem overhead", func() {
+		sourcePvc, err := remote.CoreV1().PersistentVolumeClaims("golden").Get(context.TODO(), "fedora", metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		sourcePvc.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1G")}
+		_, err = remote.CoreV1().PersistentVolumeClaims("golden").UpdateStatus(context.TODO(), sourcePvc, metav1.UpdateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		dv := newRemotePvcCloneDataVolume("test-dv")
+		dv.Spec.PVC.VolumeMode = &FilesystemMode
+		dv.Spec.PVC.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1G")}
+		reconciler = createRemotePvcCloneReconciler(key, remote, "cdi-uploadproxy.example.com", dv)
+		cdiConfig := &cdiv1.CDIConfig{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+		cdiConfig.Status.FilesystemOverhead = &cdiv1.FilesystemOverhead{Global: "0.1"}
+		Expect(reconciler.client.Status().Update(context.TODO(), cdiConfig)).To(Succeed())
+
+		_, err = reconciler.Reconcile(context.TODO(), request)
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		Expect(reconciler.client.Get(context.TODO(), request.NamespacedName, pvc)).To(Succeed())
+		requested := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		// The 1G disk.img and the 10% filesystem overhead
+		Expect(requested.Cmp(resource.MustParse("1111111112"))).To(BeNumerically(">=", 0))
+		Expect(reconciler.recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring(CloneVolumeModeSizeAdjusted)))
+	})
+
+	It("should recreate a failed remote source pod", func() {
+		reconciler = createRemotePvcCloneReconciler(key, remote, "cdi-uploadproxy.example.com", newRemotePvcCloneDataVolume("test-dv"))
+		reconcileUploadReady()
+		_, err := reconciler.Reconcile(context.TODO(), request)
+		Expect(err).ToNot(HaveOccurred())
+		pod, err := getRemotePod()
+		Expect(err).ToNot(HaveOccurred())
+		pod.Status.Phase = corev1.PodFailed
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: "Unexpected status code 401"}},
+		}}
+		_, err = remote.CoreV1().Pods("golden").UpdateStatus(context.TODO(), pod, metav1.UpdateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = reconciler.Reconcile(context.TODO(), request)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = getRemotePod()
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		events := reconciler.recorder.(*record.FakeRecorder).Events
+		Eventually(events).Should(Receive(ContainSubstring("Unexpected status code 401")))
+
+		_, err = reconciler.Reconcile(context.TODO(), request)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = getRemotePod()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should delete the remote source once the clone succeeded", func() {
+		reconciler = createRemotePvcCloneReconciler(key, remote, "cdi-uploadproxy.example.com", newRemotePvcCloneDataVolume("test-dv"))
+		pvc := reconcileUploadReady()
+		_, err := reconciler.Reconcile(context.TODO(), request)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = getRemotePod()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(reconciler.client.Get(context.TODO(), request.NamespacedName, pvc)).To(Succeed())
+		pvc.Annotations[AnnPodPhase] = string(corev1.PodSucceeded)
+		Expect(reconciler.client.Update(context.TODO(), pvc)).To(Succeed())
+		_, err = reconciler.Reconcile(context.TODO(), request)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = getRemotePod()
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		_, err = remote.CoreV1().Secrets("golden").Get(context.TODO(), remotePodName, metav1.GetOptions{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		dv := &cdiv1.DataVolume{}
+		Expect(reconciler.client.Get(context.TODO(), request.NamespacedName, dv)).To(Succeed())
+		Expect(HasFinalizer(dv, remotePvcCloneFinalizer)).To(BeFalse())
+	})
+
+	It("should fail without upload proxy URL reachable from the remote cluster", func() {
+		reconciler = createRemotePvcCloneReconciler(key, remote, "", newRemotePvcCloneDataVolume("test-dv"))
+		reconcileUploadReady()
+		_, err := reconciler.Reconcile(context.TODO(), request)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("uploadProxyURLOverride"))
+		_, err = getRemotePod()
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	DescribeTable("should only accept a kubeconfig with inline credentials", func(user string, valid bool) {
+		kubeconfig := `apiVersion: v1
+kind: Config
+clusters:
+- name: hub
+  cluster:
+    server: https://hub.example.com:6443
+    certificate-authority-data: Q0E=
+contexts:
+- name: hub
+  context:
+    cluster: hub
+    user: hub
+current-context: hub
+users:
+- name: hub
+  user:
+` + user
+		config, err := clientcmd.Load([]byte(kubeconfig))
+		Expect(err).ToNot(HaveOccurred())
+		err = validateRemoteKubeconfig(config)
+		if valid {
+			Expect(err).ToNot(HaveOccurred())
+		} else {
+			Expect(err).To(MatchError(ContainSubstring("only token or client-certificate-data and client-key-data are supported")))
+		}
+	},
+		Entry("accepting an inline token", "    token: secret", true),
+		Entry("accepting an inline client certificate", "    client-certificate-data: Q0VSVA==\n    client-key-data: S0VZ", true),
+		Entry("rejecting a token file", "    tokenFile: /var/run/secrets/kubernetes.io/serviceaccount/token", false),
+		Entry("rejecting a client certificate file", "    client-certificate: /etc/pki/tls.crt\n    client-key-data: S0VZ", false),
+		Entry("rejecting a client key file", "    client-certificate-data: Q0VSVA==\n    client-key: /etc/pki/tls.key", false),
+		Entry("rejecting an exec command", "    exec:\n      apiVersion: client.authentication.k8s.io/v1\n      command: /bin/sh", false),
+		Entry("rejecting an auth provider", "    auth-provider:\n      name: gcp", false),
+	)
+
+	It("should not accept a kubeconfig with a certificate authority file", func() {
+		kubeconfig := `apiVersion: v1
+kind: Config
+clusters:
+- name: hub
+  cluster:
+    server: https://hub.example.com:6443
+    certificate-authority: /var/run/secrets/kubernetes.io/serviceaccount/ca.crt
+contexts:
+- name: hub
+  context:
+    cluster: hub
+    user: hub
+current-context: hub
+users:
+- name: hub
+  user:
+    token: secret
+`
+		config, err := clientcmd.Load([]byte(kubeconfig))
+		Expect(err).ToNot(HaveOccurred())
+		err = validateRemoteKubeconfig(config)
+		Expect(err).To(MatchError(ContainSubstring("only certificate-authority-data is supported")))
+	})
+
+	DescribeTable("DV phase", func(current, expected cdiv1.DataVolumePhase, podPhase corev1.PodPhase, expectedEvent string, extraAnnotations ...string) {
+		scName := "testpvc"
+		sc := CreateStorageClassWithProvisioner(scName, map[string]string{AnnDefaultStorageClass: "true"}, map[string]string{}, "csi-plugin")
+		storageProfile := createStorageProfile(scName, nil, BlockMode)
+
+		r := createRemotePvcCloneReconciler(key, remote, "cdi-uploadproxy.example.com", newRemotePvcCloneDataVolume("test-dv"), sc, storageProfile)
+		dvPhaseTest(r.ReconcilerBase, r, newRemotePvcCloneDataVolume("test-dv"), current, expected, corev1.ClaimBound, podPhase, AnnUploadRequest, expectedEvent, extraAnnotations...)
+	},
+		Entry("should switch to clone scheduled", cdiv1.Pending, cdiv1.CloneScheduled, corev1.PodPending, "Cloning from remote PVC golden/fedora into test-dv scheduled"),
+		Entry("should switch to clone in progress", cdiv1.Pending, cdiv1.CloneInProgress, corev1.PodRunning, "Cloning from remote PVC golden/fedora into test-dv in progress", AnnPodReady, "true"),
+		Entry("should switch to succeeded", cdiv1.Pending, cdiv1.Succeeded, corev1.PodSucceeded, "Successfully cloned from remote PVC golden/fedora into PVC test-dv"),
+	)
+})
+
+func newRemotePvcCloneDataVolume(name string) *cdiv1.DataVolume {
+	return &cdiv1.DataVolume{
+		TypeMeta: metav1.TypeMeta{APIVersion: cdiv1.SchemeGroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceDefault,
+			UID:       "test-uid",
+		},
+		Spec: cdiv1.DataVolumeSpec{
+			Source: &cdiv1.DataVolumeSource{
+				RemotePVC: &cdiv1.DataVolumeSourceRemotePVC{
+					KubeconfigSecretRef: "hub-kubeconfig",
+					Namespace:           "golden",
+					Name:                "fedora",
+				},
+			},
+			PVC: &corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			},
+		},
+	}
+}
+
+func createRemotePvcCloneReconciler(key *rsa.PrivateKey, remote kubernetes.Interface, uploadProxyURL string, objects ...runtime.Object) *RemotePvcCloneReconciler {
+	cdiConfig := MakeEmptyCDIConfigSpec(common.ConfigName)
+	cdiConfig.Status = cdiv1.CDIConfigStatus{
+		ScratchSpaceStorageClass: testStorageClass,
+	}
+	if uploadProxyURL != "" {
+		cdiConfig.Status.UploadProxyURL = &uploadProxyURL
+	}
+	cdiConfig.Spec.FeatureGates = []string{featuregates.HonorWaitForFirstConsumer}
+	kubeconfig := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "hub-kubeconfig"},
+		Data:       map[string][]byte{common.KeyKubeconfig: []byte("kubeconfig")},
+	}
+
+	objs := []runtime.Object{}
+	objs = append(objs, objects...)
+	objs = append(objs, cdiConfig, kubeconfig, MakeEmptyCDICR())
+
+	// Register operator types with the runtime scheme.
+	s := scheme.Scheme
+	cdiv1.AddToScheme(s)
+	snapshotv1.AddToScheme(s)
+	extv1.AddToScheme(s)
+
+	builder := fake.NewClientBuilder().
+		WithScheme(s).
+		WithRuntimeObjects(objs...)
+	for _, ia := range getIndexArgs() {
+		builder = builder.WithIndex(ia.obj, ia.field, ia.extractValue)
+	}
+	cl := builder.Build()
+
+	return &RemotePvcCloneReconciler{
+		ReconcilerBase: ReconcilerBase{
+			client:         cl,
+			uncachedClient: cl,
+			scheme:         s,
+			log:            dvRemotePvcCloneLog,
+			recorder:       record.NewFakeRecorder(10),
+			featureGates:   featuregates.NewFeatureGates(cl),
+			installerLabels: map[string]string{
+				common.AppKubernetesPartOfLabel:  "testing",
+				common.AppKubernetesVersionLabel: "v0.0.0-tests",
+			},
+		},
+		clonerImage:              "cloner",
+		pullPolicy:               string(corev1.PullIfNotPresent),
+		tokenGenerator:           newRemoteCloneTokenGenerator(key),
+		uploadProxyBundleFetcher: &fetcher.MemCertBundleFetcher{Bundle: []byte("upload proxy CA")},
+		newRemoteClient: func(kubeconfig []byte) (kubernetes.Interface, error) {
+			return remote, nil
+		},
+	}
+}
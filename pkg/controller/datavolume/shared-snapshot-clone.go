@@ -0,0 +1,393 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datavolume
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+	"kubevirt.io/containerized-data-importer/pkg/util/naming"
+)
+
+const (
+	// annCloneSharedSnapshot is the name of the shared snapshot a clone DataVolume and its target PVC restore from
+	annCloneSharedSnapshot = "cdi.kubevirt.io/cloneSharedSnapshot"
+	// annSharedSnapshotSourceUID is the UID of the source PVC of the shared snapshot
+	annSharedSnapshotSourceUID = "cdi.kubevirt.io/sharedSnapshotSourceUID"
+	// annSharedSnapshotGeneration is the write generation of the source PVC the shared snapshot was taken at
+	annSharedSnapshotGeneration = "cdi.kubevirt.io/sharedSnapshotGeneration"
+	// annSourceWriteGeneration counts the pods mounting a shared snapshot source PVC read-write since its first shared snapshot
+	annSourceWriteGeneration = "cdi.kubevirt.io/sourceWriteGeneration"
+	// labelSharedCloneSnapshot marks the snapshots shared by several clones
+	labelSharedCloneSnapshot = "cdi.kubevirt.io/sharedCloneSnapshot"
+
+	sharedSnapshotNamePrefix = "cdi-shared-clone"
+
+	sharedSnapshotSourceControllerName = "shared-snapshot-source-controller"
+)
+
+// sharedSnapshotWindow is how long after its creation a shared snapshot accepts new clones, and the minimum time it is kept
+var sharedSnapshotWindow = 2 * time.Minute
+
+// sharedSnapshotName returns the name of the shared snapshot of the current data of the source PVC. It is versioned
+// with the write generation of the source, not with its resource version, which changes with every metadata or status
+// update while the data stays the same. The data only changes when a pod writes the source, and every pod mounting it
+// read-write bumps the generation, so a clone requested after a write never joins a snapshot taken before it.
+func sharedSnapshotName(sourcePvc *corev1.PersistentVolumeClaim) string {
+	return naming.GetResourceName(sharedSnapshotNamePrefix,
+		sourcePvc.Name+"-"+string(sourcePvc.UID)+"-"+sourceWriteGeneration(sourcePvc))
+}
+
+// sourceWriteGeneration returns the write generation of the source PVC, the number of pods seen mounting it read-write
+// since its first shared snapshot
+func sourceWriteGeneration(sourcePvc *corev1.PersistentVolumeClaim) string {
+	if generation, ok := sourcePvc.Annotations[annSourceWriteGeneration]; ok {
+		return generation
+	}
+	return "0"
+}
+
+func isSharedCloneSnapshot(obj metav1.Object) bool {
+	return obj.GetLabels()[labelSharedCloneSnapshot] == "true"
+}
+
+// sharedSnapshotWindowRemaining returns how long the shared snapshot still accepts new clones
+func sharedSnapshotWindowRemaining(snapshot *snapshotv1.VolumeSnapshot) time.Duration {
+	if snapshot.CreationTimestamp.IsZero() {
+		return sharedSnapshotWindow
+	}
+	return time.Until(snapshot.CreationTimestamp.Add(sharedSnapshotWindow))
+}
+
+func newSharedSnapshot(sourcePvc *corev1.PersistentVolumeClaim, snapshotName, snapshotClassName string) *snapshotv1.VolumeSnapshot {
+	className := snapshotClassName
+	snapshot := &snapshotv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      snapshotName,
+			Namespace: sourcePvc.Namespace,
+			Labels: map[string]string{
+				common.CDILabelKey:       common.CDILabelValue,
+				common.CDIComponentLabel: common.SmartClonerCDILabel,
+				labelSharedCloneSnapshot: "true",
+			},
+			Annotations: map[string]string{
+				annSharedSnapshotSourceUID:  string(sourcePvc.UID),
+				annSharedSnapshotGeneration: sourceWriteGeneration(sourcePvc),
+			},
+		},
+		Spec: snapshotv1.VolumeSnapshotSpec{
+			Source: snapshotv1.VolumeSnapshotSource{
+				PersistentVolumeClaimName: &sourcePvc.Name,
+			},
+			VolumeSnapshotClassName: &className,
+		},
+	}
+	cc.CopyTargetImageAnnotations(sourcePvc.Annotations, snapshot.Annotations)
+	return snapshot
+}
+
+// addSharedSnapshotWatch reconciles the DataVolumes waiting for a shared snapshot to be ready
+func addSharedSnapshotWatch(mgr manager.Manager, c controller.Controller) error {
+	// check if volume snapshots exist
+	err := mgr.GetClient().List(context.TODO(), &snapshotv1.VolumeSnapshotList{})
+	if meta.IsNoMatchError(err) {
+		return nil
+	}
+	if err != nil && !cc.IsErrCacheNotStarted(err) {
+		return err
+	}
+
+	mapToDataVolume := func(obj client.Object) (reqs []reconcile.Request) {
+		if !isSharedCloneSnapshot(obj) {
+			return
+		}
+		var dvs cdiv1.DataVolumeList
+		if err := mgr.GetClient().List(context.TODO(), &dvs, client.InNamespace(obj.GetNamespace())); err != nil {
+			c.GetLogger().Error(err, "Unable to list DataVolumes", "namespace", obj.GetNamespace())
+			return
+		}
+		for _, dv := range dvs.Items {
+			if dv.Annotations[annCloneSharedSnapshot] == obj.GetName() {
+				reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: dv.Namespace, Name: dv.Name}})
+			}
+		}
+		return
+	}
+
+	return c.Watch(&source.Kind{Type: &snapshotv1.VolumeSnapshot{}}, handler.EnqueueRequestsFromMapFunc(mapToDataVolume))
+}
+
+// reconcileSharedSnapshotClonePvc joins the clone to the shared snapshot of the current data of its source, taking
+// the snapshot if no recent one exists, and restores the target PVC from it once it is ready
+func (r *PvcCloneReconciler) reconcileSharedSnapshotClonePvc(log logr.Logger,
+	syncState *dvSyncState,
+	snapshotClassName string) (reconcile.Result, error) {
+
+	log = log.WithName("reconcileSharedSnapshotClonePvc")
+	datavolume := syncState.dvMutated
+
+	snapshotName, joined := datavolume.Annotations[annCloneSharedSnapshot]
+	var sourcePvc *corev1.PersistentVolumeClaim
+	if !joined {
+		var err error
+		if sourcePvc, err = r.findSourcePvc(datavolume); err != nil {
+			return reconcile.Result{}, err
+		}
+		if err := r.trackSourceWrites(sourcePvc); err != nil {
+			return reconcile.Result{}, err
+		}
+		snapshotName = sharedSnapshotName(sourcePvc)
+	}
+
+	snapshot := &snapshotv1.VolumeSnapshot{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: datavolume.Namespace, Name: snapshotName}, snapshot); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		snapshot = nil
+	}
+
+	if !joined {
+		if snapshot != nil && (snapshot.DeletionTimestamp != nil || sharedSnapshotWindowRemaining(snapshot) <= 0) {
+			// Too late to join, the source is snapshotted again once the expired snapshot is gone
+			log.V(3).Info("Shared snapshot expired, waiting for its removal", "snapshot.Name", snapshotName)
+			return reconcile.Result{RequeueAfter: time.Second * 5},
+				r.syncCloneStatusPhase(syncState, cdiv1.CloneScheduled, nil)
+		}
+		// Every clone checks the source, not only the one taking the snapshot
+		if readyToClone, err := r.isSourceReadyToClone(datavolume, SharedSnapshotClone); err != nil {
+			return reconcile.Result{}, err
+		} else if !readyToClone {
+			return reconcile.Result{Requeue: true},
+				r.syncCloneStatusPhase(syncState, cdiv1.CloneScheduled, nil)
+		}
+		if snapshot != nil && snapshot.Annotations[annSharedSnapshotGeneration] != sourceWriteGeneration(sourcePvc) {
+			// The snapshot does not have the current data of the source, wait for the next one
+			log.V(3).Info("Source written since the shared snapshot was taken, waiting for its removal", "snapshot.Name", snapshotName)
+			return reconcile.Result{RequeueAfter: sharedSnapshotWindowRemaining(snapshot) + time.Second*5},
+				r.syncCloneStatusPhase(syncState, cdiv1.CloneScheduled, nil)
+		}
+		if snapshot == nil {
+			newSnapshot := newSharedSnapshot(sourcePvc, snapshotName, snapshotClassName)
+			util.SetRecommendedLabels(newSnapshot, r.installerLabels, "cdi-controller")
+			if err := r.client.Create(context.TODO(), newSnapshot); err != nil {
+				if !k8serrors.IsAlreadyExists(err) {
+					return reconcile.Result{}, err
+				}
+			} else {
+				log.V(1).Info("shared snapshot created successfully", "snapshot.Namespace", newSnapshot.Namespace, "snapshot.Name", newSnapshot.Name)
+			}
+		}
+		cc.AddAnnotation(datavolume, annCloneSharedSnapshot, snapshotName)
+		return reconcile.Result{}, r.syncCloneStatusPhase(syncState, cdiv1.SnapshotForSmartCloneInProgress, nil)
+	}
+
+	if snapshot == nil || snapshot.DeletionTimestamp != nil {
+		// The snapshot went away before the target PVC was restored, start over
+		log.V(3).Info("Shared snapshot removed before the clone restored it", "snapshot.Name", snapshotName)
+		delete(datavolume.Annotations, annCloneSharedSnapshot)
+		return reconcile.Result{Requeue: true},
+			r.syncCloneStatusPhase(syncState, cdiv1.CloneScheduled, nil)
+	}
+
+	if reason, wait := snapshotCloneFailure(snapshot); reason != "" {
+		// Leaving the shared snapshot releases it, the smart clone controller deletes it once no clone needs it
+		if recordCloneFallback(r.recorder, datavolume, reason) {
+			log.V(1).Info("Shared snapshot clone falling back to a host assisted clone", "reason", reason)
+		}
+		delete(datavolume.Annotations, annCloneSharedSnapshot)
+		return reconcile.Result{Requeue: true},
+			r.syncCloneStatusPhase(syncState, cdiv1.CloneScheduled, nil)
+	} else if wait > 0 {
+		// wait for ready to use
+		return reconcile.Result{RequeueAfter: wait}, r.syncCloneStatusPhase(syncState, cdiv1.SnapshotForSmartCloneInProgress, nil)
+	}
+
+	newPvc, err := newPvcFromSnapshot(datavolume, datavolume.Name, snapshot, syncState.pvcSpec)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	util.SetRecommendedLabels(newPvc, r.installerLabels, "cdi-controller")
+	if err := setAnnOwnedByDataVolume(newPvc, datavolume); err != nil {
+		return reconcile.Result{}, err
+	}
+	// The shared snapshot is not deleted once this PVC is bound, but when no clone needs it anymore
+	delete(newPvc.Annotations, annSmartCloneSnapshot)
+	newPvc.OwnerReferences = []metav1.OwnerReference{
+		*metav1.NewControllerRef(datavolume, schema.GroupVersionKind{
+			Group:   cdiv1.SchemeGroupVersion.Group,
+			Version: cdiv1.SchemeGroupVersion.Version,
+			Kind:    "DataVolume",
+		}),
+	}
+
+	log.V(3).Info("Creating PVC from shared snapshot", "pvc.Namespace", newPvc.Namespace, "pvc.Name", newPvc.Name)
+	if err := r.client.Create(context.TODO(), newPvc); err != nil && !k8serrors.IsAlreadyExists(err) {
+		if cc.ErrQuotaExceeded(err) {
+			r.syncDataVolumeStatusPhaseWithEvent(syncState, cdiv1.Pending, nil,
+				Event{
+					eventType: corev1.EventTypeWarning,
+					reason:    cc.ErrExceededQuota,
+					message:   err.Error(),
+				})
+		}
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{}, r.syncDataVolumeStatusPhaseWithEvent(syncState, cdiv1.SmartClonePVCInProgress, nil,
+		Event{
+			eventType: corev1.EventTypeNormal,
+			reason:    SmartClonePVCInProgress,
+			message:   fmt.Sprintf(MessageSmartClonePVCInProgress, snapshot.Namespace, *snapshot.Spec.Source.PersistentVolumeClaimName),
+		})
+}
+
+// trackSourceWrites starts counting the pods mounting the source PVC read-write before its first shared snapshot
+func (r *PvcCloneReconciler) trackSourceWrites(sourcePvc *corev1.PersistentVolumeClaim) error {
+	if _, ok := sourcePvc.Annotations[annSourceWriteGeneration]; ok {
+		return nil
+	}
+	cc.AddAnnotation(sourcePvc, annSourceWriteGeneration, "0")
+	return r.client.Update(context.TODO(), sourcePvc)
+}
+
+// sharedSnapshotSourceReconciler bumps the write generation of a shared snapshot source PVC when a pod mounting it
+// read-write is created, so the clones requested since then don't join a snapshot taken before the pod wrote to it
+type sharedSnapshotSourceReconciler struct {
+	client client.Client
+	log    logr.Logger
+}
+
+// addSharedSnapshotSourceController watches the pods created with a read-write PVC volume to version the shared
+// snapshots of the PVC. A pod is seen even when it is deleted before it is reconciled.
+func addSharedSnapshotSourceController(mgr manager.Manager, log logr.Logger) error {
+	reconciler := &sharedSnapshotSourceReconciler{
+		client: mgr.GetClient(),
+		log:    log.WithName(sharedSnapshotSourceControllerName),
+	}
+	sourceController, err := controller.New(sharedSnapshotSourceControllerName, mgr, controller.Options{
+		Reconciler: reconciler,
+	})
+	if err != nil {
+		return err
+	}
+	return sourceController.Watch(&source.Kind{Type: &corev1.Pod{}},
+		handler.EnqueueRequestsFromMapFunc(readWritePvcRequests),
+		predicate.Funcs{
+			CreateFunc:  func(e event.CreateEvent) bool { return true },
+			DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+			UpdateFunc:  func(e event.UpdateEvent) bool { return false },
+			GenericFunc: func(e event.GenericEvent) bool { return false },
+		})
+}
+
+// readWritePvcRequests returns the PVCs the pod mounts read-write
+func readWritePvcRequests(obj client.Object) (reqs []reconcile.Request) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil && !volume.PersistentVolumeClaim.ReadOnly {
+			reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: pod.Namespace, Name: volume.PersistentVolumeClaim.ClaimName}})
+		}
+	}
+	return
+}
+
+// Reconcile bumps the write generation of the PVC, only the PVCs that were the source of a shared snapshot have one
+func (r *sharedSnapshotSourceReconciler) Reconcile(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, pvc); err != nil {
+		return reconcile.Result{}, cc.IgnoreNotFound(err)
+	}
+	value, ok := pvc.Annotations[annSourceWriteGeneration]
+	if !ok {
+		return reconcile.Result{}, nil
+	}
+	generation, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		r.log.Info("Resetting the invalid write generation of the shared snapshot source", "pvc", req.NamespacedName, "generation", value)
+	}
+	pvc.Annotations[annSourceWriteGeneration] = strconv.FormatInt(generation+1, 10)
+	r.log.V(3).Info("Source written by a new pod", "pvc", req.NamespacedName, "generation", pvc.Annotations[annSourceWriteGeneration])
+	return reconcile.Result{}, r.client.Update(context.TODO(), pvc)
+}
+
+// reconcileSharedSnapshot deletes the shared snapshot once its window is over and every clone restoring from it has a
+// bound target PVC
+func (r *SmartCloneReconciler) reconcileSharedSnapshot(log logr.Logger, snapshot *snapshotv1.VolumeSnapshot) (reconcile.Result, error) {
+	if remaining := sharedSnapshotWindowRemaining(snapshot); remaining > 0 {
+		return reconcile.Result{RequeueAfter: remaining}, nil
+	}
+
+	inUse, err := r.isSharedSnapshotInUse(snapshot)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if inUse {
+		return reconcile.Result{RequeueAfter: sharedSnapshotWindow}, nil
+	}
+
+	return reconcile.Result{}, r.deleteSnapshot(log, snapshot.Namespace, snapshot.Name)
+}
+
+// isSharedSnapshotInUse returns true if a clone DataVolume joined the shared snapshot and its target PVC is not bound yet.
+// A failed or canceled clone does not keep the snapshot, so it does not hold back the other targets of its batch.
+func (r *SmartCloneReconciler) isSharedSnapshotInUse(snapshot *snapshotv1.VolumeSnapshot) (bool, error) {
+	dvs := &cdiv1.DataVolumeList{}
+	if err := r.client.List(context.TODO(), dvs, client.InNamespace(snapshot.Namespace)); err != nil {
+		return false, err
+	}
+	for i := range dvs.Items {
+		dv := &dvs.Items[i]
+		if cloneTornDown(dv) || dv.Status.Phase == cdiv1.Failed || dv.Annotations[annCloneSharedSnapshot] != snapshot.Name {
+			continue
+		}
+		targetPVC, err := r.getTargetPVC(dv)
+		if err != nil {
+			return false, err
+		}
+		if targetPVC == nil || targetPVC.Status.Phase != corev1.ClaimBound {
+			return true, nil
+		}
+	}
+	return false, nil
+}
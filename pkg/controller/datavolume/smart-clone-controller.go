@@ -115,7 +115,7 @@ func addSmartCloneControllerWatches(mgr manager.Manager, smartCloneController co
 	if err := smartCloneController.Watch(&source.Kind{Type: &snapshotv1.VolumeSnapshot{}}, handler.EnqueueRequestsFromMapFunc(
 		func(obj client.Object) []reconcile.Request {
 			snapshot := obj.(*snapshotv1.VolumeSnapshot)
-			if hasAnnOwnedByDataVolume(snapshot) && shouldReconcileSnapshot(snapshot) {
+			if (hasAnnOwnedByDataVolume(snapshot) && shouldReconcileSnapshot(snapshot)) || isSharedCloneSnapshot(snapshot) {
 				return []reconcile.Request{
 					{
 						NamespacedName: types.NamespacedName{
@@ -170,6 +170,7 @@ func (r *SmartCloneReconciler) reconcilePvc(log logr.Logger, pvc *corev1.Persist
 	log.WithValues("pvc.Name", pvc.Name).WithValues("pvc.Namespace", pvc.Namespace).Info("Reconciling PVC")
 
 	snapshotName, hasSnapshot := pvc.Annotations[annSmartCloneSnapshot]
+	sharedSnapshotName, hasSharedSnapshot := pvc.Annotations[annCloneSharedSnapshot]
 
 	// Don't delete snapshot unless the PVC is bound.
 	if hasSnapshot && pvc.Status.Phase == corev1.ClaimBound {
@@ -182,21 +183,43 @@ func (r *SmartCloneReconciler) reconcilePvc(log logr.Logger, pvc *corev1.Persist
 			return reconcile.Result{}, err
 		}
 
-		if v, ok := pvc.Annotations[cc.AnnCloneOf]; !ok || v != "true" {
-			if pvc.Annotations == nil {
-				pvc.Annotations = make(map[string]string)
-			}
-			pvc.Annotations[cc.AnnCloneOf] = "true"
+		if err := r.setCloneOf(pvc); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
 
-			if err := r.client.Update(context.TODO(), pvc); err != nil {
-				return reconcile.Result{}, err
+	// The shared snapshot is only deleted once no other clone restores from it
+	if hasSharedSnapshot && pvc.Status.Phase == corev1.ClaimBound {
+		if err := r.setCloneOf(pvc); err != nil {
+			return reconcile.Result{}, err
+		}
+
+		snapshot := &snapshotv1.VolumeSnapshot{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: sharedSnapshotName, Namespace: pvc.Namespace}, snapshot); err != nil {
+			if k8serrors.IsNotFound(err) {
+				return reconcile.Result{}, nil
 			}
+			return reconcile.Result{}, err
 		}
+		return r.reconcileSnapshot(log, snapshot)
 	}
 
 	return reconcile.Result{}, nil
 }
 
+func (r *SmartCloneReconciler) setCloneOf(pvc *corev1.PersistentVolumeClaim) error {
+	if v, ok := pvc.Annotations[cc.AnnCloneOf]; !ok || v != "true" {
+		if pvc.Annotations == nil {
+			pvc.Annotations = make(map[string]string)
+		}
+		pvc.Annotations[cc.AnnCloneOf] = "true"
+
+		return r.client.Update(context.TODO(), pvc)
+	}
+
+	return nil
+}
+
 func (r *SmartCloneReconciler) reconcileSnapshot(log logr.Logger, snapshot *snapshotv1.VolumeSnapshot) (reconcile.Result, error) {
 	log.WithValues("snapshot.Name", snapshot.Name).
 		WithValues("snapshot.Namespace", snapshot.Namespace).
@@ -206,12 +229,17 @@ func (r *SmartCloneReconciler) reconcileSnapshot(log logr.Logger, snapshot *snap
 		return reconcile.Result{}, nil
 	}
 
+	if isSharedCloneSnapshot(snapshot) {
+		return r.reconcileSharedSnapshot(log, snapshot)
+	}
+
 	dataVolume, err := r.getDataVolume(snapshot)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	if dataVolume == nil || dataVolume.DeletionTimestamp != nil {
+	// a canceled clone must not restore the target PVC
+	if dataVolume == nil || cloneTornDown(dataVolume) {
 		if err := r.deleteSnapshot(log, snapshot.Namespace, snapshot.Name); err != nil {
 			return reconcile.Result{}, err
 		}
@@ -229,9 +257,16 @@ func (r *SmartCloneReconciler) reconcileSnapshot(log logr.Logger, snapshot *snap
 		return reconcile.Result{}, nil
 	}
 
-	if snapshot.Status == nil || snapshot.Status.ReadyToUse == nil || !*snapshot.Status.ReadyToUse {
+	if cloneFellBack(dataVolume) {
+		// the host assisted clone does not need the snapshot
+		return reconcile.Result{}, r.deleteSnapshot(log, snapshot.Namespace, snapshot.Name)
+	}
+
+	if reason, wait := snapshotCloneFailure(snapshot); reason != "" {
+		return reconcile.Result{}, r.fallBackToHostAssistedClone(log, dataVolume, snapshot, reason)
+	} else if wait > 0 {
 		// wait for ready to use
-		return reconcile.Result{}, nil
+		return reconcile.Result{RequeueAfter: wait}, nil
 	}
 
 	targetPvcSpec, err := renderPvcSpec(r.client, r.recorder, r.log, dataVolume)
@@ -239,6 +274,13 @@ func (r *SmartCloneReconciler) reconcileSnapshot(log logr.Logger, snapshot *snap
 		return reconcile.Result{}, err
 	}
 
+	// A restored PVC can't be shrunk to the target size
+	restoreSize, targetSize := snapshot.Status.RestoreSize, targetPvcSpec.Resources.Requests[corev1.ResourceStorage]
+	if restoreSize != nil && !targetSize.IsZero() && restoreSize.Cmp(targetSize) > 0 {
+		reason := fmt.Sprintf("Snapshot clone not possible: snapshot restore size %s is larger than the target size %s", restoreSize.String(), targetSize.String())
+		return reconcile.Result{}, r.fallBackToHostAssistedClone(log, dataVolume, snapshot, reason)
+	}
+
 	newPvc, err := newPvcFromSnapshot(dataVolume, snapshot.Name, snapshot, targetPvcSpec)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -284,6 +326,19 @@ func (r *SmartCloneReconciler) reconcileSnapshot(log logr.Logger, snapshot *snap
 	return reconcile.Result{}, nil
 }
 
+// fallBackToHostAssistedClone records why the snapshot clone of the DataVolume falls back to a host assisted clone,
+// and deletes its snapshot. The DataVolume controller then clones the source with a host assisted clone.
+func (r *SmartCloneReconciler) fallBackToHostAssistedClone(log logr.Logger, dataVolume *cdiv1.DataVolume, snapshot *snapshotv1.VolumeSnapshot, reason string) error {
+	dataVolume = dataVolume.DeepCopy()
+	if recordCloneFallback(r.recorder, dataVolume, reason) {
+		log.V(1).Info("Snapshot clone falling back to a host assisted clone", "reason", reason)
+		if err := r.client.Update(context.TODO(), dataVolume); err != nil {
+			return err
+		}
+	}
+	return r.deleteSnapshot(log, snapshot.Namespace, snapshot.Name)
+}
+
 func (r *SmartCloneReconciler) deleteSnapshot(log logr.Logger, namespace, name string) error {
 	snapshotToDelete := &snapshotv1.VolumeSnapshot{}
 	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, snapshotToDelete); err != nil {
@@ -397,6 +452,7 @@ func newPvcFromSnapshot(dv *cdiv1.DataVolume, name string, snapshot *snapshotv1.
 	for k, v := range dv.ObjectMeta.Annotations {
 		annotations[k] = v
 	}
+	cc.CopyTargetImageAnnotations(snapshot.Annotations, annotations)
 
 	if util.ResolveVolumeMode(targetPvcSpecCopy.VolumeMode) == corev1.PersistentVolumeFilesystem {
 		labels[common.KubePersistentVolumeFillingUpSuppressLabelKey] = common.KubePersistentVolumeFillingUpSuppressLabelValue
@@ -19,6 +19,7 @@ package datavolume
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/resource"
 
@@ -245,6 +246,81 @@ var _ = Describe("All smart clone tests", func() {
 		})
 	})
 
+	var _ = Describe("Smart-clone controller shared snapshot cleanup", func() {
+		createSharedSnapshot := func(age time.Duration) *snapshotv1.VolumeSnapshot {
+			snapshot := createSnapshotVolume("shared", metav1.NamespaceDefault, nil)
+			snapshot.Labels[labelSharedCloneSnapshot] = "true"
+			snapshot.CreationTimestamp = metav1.NewTime(time.Now().Add(-age))
+			return snapshot
+		}
+
+		createSharedCloneTarget := func(name string, phase corev1.PersistentVolumeClaimPhase) (*cdiv1.DataVolume, *corev1.PersistentVolumeClaim) {
+			dv := newCloneDataVolume(name)
+			dv.Annotations[annCloneSharedSnapshot] = "shared"
+			pvc := CreatePvc(name, metav1.NamespaceDefault, map[string]string{annCloneSharedSnapshot: "shared"}, nil)
+			pvc.Status.Phase = phase
+			return dv, pvc
+		}
+
+		expectSnapshotExists := func(reconciler *SmartCloneReconciler, exists bool) {
+			err := reconciler.client.Get(context.TODO(), types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "shared"}, &snapshotv1.VolumeSnapshot{})
+			if exists {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			}
+		}
+
+		It("Should keep the shared snapshot during its window", func() {
+			dv, pvc := createSharedCloneTarget("test-dv", corev1.ClaimBound)
+			reconciler := createSmartCloneReconciler(dv, pvc, createSharedSnapshot(time.Second))
+			res, err := reconciler.reconcilePvc(reconciler.log, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.RequeueAfter).To(BeNumerically(">", 0))
+			expectSnapshotExists(reconciler, true)
+
+			updatedPvc := &corev1.PersistentVolumeClaim{}
+			Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Namespace: pvc.Namespace, Name: pvc.Name}, updatedPvc)).To(Succeed())
+			Expect(updatedPvc.Annotations[AnnCloneOf]).To(Equal("true"))
+		})
+
+		It("Should keep the shared snapshot while a clone restoring from it is not bound", func() {
+			dv1, pvc1 := createSharedCloneTarget("test-dv1", corev1.ClaimBound)
+			dv2, pvc2 := createSharedCloneTarget("test-dv2", corev1.ClaimPending)
+			dv3 := newCloneDataVolume("test-dv3")
+			dv3.Annotations[annCloneSharedSnapshot] = "shared"
+			snapshot := createSharedSnapshot(2 * sharedSnapshotWindow)
+			reconciler := createSmartCloneReconciler(dv1, pvc1, dv2, pvc2, dv3, snapshot)
+
+			res, err := reconciler.reconcileSnapshot(reconciler.log, snapshot)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.RequeueAfter).To(Equal(sharedSnapshotWindow))
+			expectSnapshotExists(reconciler, true)
+		})
+
+		It("Should not keep the shared snapshot for a failed clone of the batch", func() {
+			dv1, pvc1 := createSharedCloneTarget("test-dv1", corev1.ClaimBound)
+			dv2, pvc2 := createSharedCloneTarget("test-dv2", corev1.ClaimLost)
+			dv2.Status.Phase = cdiv1.Failed
+			snapshot := createSharedSnapshot(2 * sharedSnapshotWindow)
+			reconciler := createSmartCloneReconciler(dv1, pvc1, dv2, pvc2, snapshot)
+
+			_, err := reconciler.reconcileSnapshot(reconciler.log, snapshot)
+			Expect(err).ToNot(HaveOccurred())
+			expectSnapshotExists(reconciler, false)
+		})
+
+		It("Should delete the shared snapshot past its window once every clone is bound", func() {
+			dv1, pvc1 := createSharedCloneTarget("test-dv1", corev1.ClaimBound)
+			dv2, pvc2 := createSharedCloneTarget("test-dv2", corev1.ClaimBound)
+			reconciler := createSmartCloneReconciler(dv1, pvc1, dv2, pvc2, createSharedSnapshot(2*sharedSnapshotWindow))
+
+			_, err := reconciler.reconcilePvc(reconciler.log, pvc2)
+			Expect(err).ToNot(HaveOccurred())
+			expectSnapshotExists(reconciler, false)
+		})
+	})
+
 	createSnapshotWithRestoreSize := func(size int64) *snapshotv1.VolumeSnapshot {
 		snapshot := createSnapshotVolume("snapshot", "default", nil)
 		snapshot.Status.RestoreSize = resource.NewQuantity(size, resource.BinarySI)
@@ -304,6 +380,18 @@ var _ = Describe("All smart clone tests", func() {
 		table.Entry("with negative restoreSize, and set dv size storage", createCloneDataVolumeWithRequestSizeStorage(int64(20480)), createSnapshotWithRestoreSize(int64(-20480)), int64(0), fmt.Errorf("snapshot has no RestoreSize")),
 		table.Entry("with postive restoreSize, and set larger dv size storage", createCloneDataVolumeWithRequestSizeStorage(int64(204800)), createSnapshotWithRestoreSize(int64(2048)), int64(2048), nil),
 	)
+
+	It("newPvcFromSnapshot should carry the format of the image written to the source", func() {
+		snapshot := createSnapshotWithRestoreSize(int64(1024))
+		snapshot.Annotations = map[string]string{
+			AnnImageTargetFormat:      "qcow2",
+			AnnImageTargetCompression: "zstd",
+		}
+		pvc, err := newPvcFromSnapshot(createCloneDataVolume("testDv", "default", "snapshot", "default"), "targetPvc", snapshot, &corev1.PersistentVolumeClaimSpec{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.Annotations[AnnImageTargetFormat]).To(Equal("qcow2"))
+		Expect(pvc.Annotations[AnnImageTargetCompression]).To(Equal("zstd"))
+	})
 })
 
 func createSmartCloneReconciler(objects ...runtime.Object) *SmartCloneReconciler {
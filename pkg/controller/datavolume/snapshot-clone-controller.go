@@ -69,8 +69,10 @@ func NewSnapshotCloneController(
 		CloneReconcilerBase: CloneReconcilerBase{
 			ReconcilerBase: ReconcilerBase{
 				client:          client,
+				uncachedClient:  mgr.GetAPIReader(),
 				scheme:          mgr.GetScheme(),
 				log:             log.WithName(snapshotCloneControllerName),
+				controllerName:  snapshotCloneControllerName,
 				featureGates:    featuregates.NewFeatureGates(client),
 				recorder:        mgr.GetEventRecorderFor(snapshotCloneControllerName),
 				installerLabels: installerLabels,
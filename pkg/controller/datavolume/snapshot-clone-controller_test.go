@@ -268,11 +268,12 @@ func createSnapshotCloneReconcilerWithoutConfig(objects ...runtime.Object) *Snap
 	r := &SnapshotCloneReconciler{
 		CloneReconcilerBase: CloneReconcilerBase{
 			ReconcilerBase: ReconcilerBase{
-				client:       cl,
-				scheme:       s,
-				log:          dvSnapshotCloneLog,
-				recorder:     rec,
-				featureGates: featuregates.NewFeatureGates(cl),
+				client:         cl,
+				uncachedClient: cl,
+				scheme:         s,
+				log:            dvSnapshotCloneLog,
+				recorder:       rec,
+				featureGates:   featuregates.NewFeatureGates(cl),
 				installerLabels: map[string]string{
 					common.AppKubernetesPartOfLabel:  "testing",
 					common.AppKubernetesVersionLabel: "v0.0.0-tests",
@@ -0,0 +1,264 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datavolume
+
+import (
+	"context"
+	"fmt"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+)
+
+const (
+	// SnapshotTargetInProgress provides a const to indicate the VolumeSnapshot of the imported PVC is being taken
+	SnapshotTargetInProgress = "SnapshotTargetInProgress"
+	// SnapshotTargetReady provides a const to indicate the VolumeSnapshot of the imported PVC is ready
+	SnapshotTargetReady = "SnapshotTargetReady"
+	// SnapshotTargetFailed provides a const to indicate the VolumeSnapshot of the imported PVC reports an error
+	SnapshotTargetFailed = "SnapshotTargetFailed"
+	// SnapshotTargetUnsupported provides a const to indicate the storage class of the PVC can't be snapshotted
+	SnapshotTargetUnsupported = "SnapshotTargetUnsupported"
+	// SnapshotTargetConflict provides a const to indicate a VolumeSnapshot with the name of the target already exists
+	SnapshotTargetConflict = "SnapshotTargetConflict"
+
+	// MessageSnapshotTargetInProgress provides a const to form the snapshot target in progress message
+	MessageSnapshotTargetInProgress = "Snapshot %s of PVC %s in progress"
+	// MessageSnapshotTargetReady provides a const to form the snapshot target ready message
+	MessageSnapshotTargetReady = "Successfully imported into VolumeSnapshot %s"
+	// MessageSnapshotTargetFailed provides a const to form the snapshot target failed message
+	MessageSnapshotTargetFailed = "VolumeSnapshot %s failed: %s"
+	// MessageSnapshotTargetNoCRDs provides a const for the VolumeSnapshot CRDs not deployed message
+	MessageSnapshotTargetNoCRDs = "The VolumeSnapshot CRDs are not deployed"
+	// MessageSnapshotTargetNoStorageClass provides a const for the storage class of the PVC not found message
+	MessageSnapshotTargetNoStorageClass = "The storage class of the PVC is not found"
+	// MessageSnapshotTargetUnsupported provides a const to form the no matching VolumeSnapshotClass message
+	MessageSnapshotTargetUnsupported = "No VolumeSnapshotClass matches the provisioner of storage class %s"
+	// MessageSnapshotClassUnsupported provides a const to form the invalid VolumeSnapshotClass message
+	MessageSnapshotClassUnsupported = "VolumeSnapshotClass %s does not exist or does not match the provisioner of storage class %s"
+	// MessageSnapshotTargetConflict provides a const to form the snapshot target conflict message
+	MessageSnapshotTargetConflict = "VolumeSnapshot %s already exists and was not taken for the DataVolume"
+
+	// annSnapshotTargetFor is set on the VolumeSnapshot taken for a DataVolume, to the name of the DataVolume
+	annSnapshotTargetFor = cc.AnnAPIGroup + "/storage.snapshot.targetFor"
+)
+
+// getSnapshotTargetName returns the name of the VolumeSnapshot a DataVolume imports into
+func getSnapshotTargetName(dv *cdiv1.DataVolume) string {
+	if dv.Spec.SnapshotTarget.Name != "" {
+		return dv.Spec.SnapshotTarget.Name
+	}
+	return dv.Name
+}
+
+// getSnapshotTargetPVCRetainPolicy returns whether the PVC of a DataVolume importing into a VolumeSnapshot is kept
+func getSnapshotTargetPVCRetainPolicy(dv *cdiv1.DataVolume) cdiv1.SnapshotTargetPVCRetainPolicy {
+	if dv.Spec.SnapshotTarget.PVCRetainPolicy == "" {
+		return cdiv1.SnapshotTargetPVCDelete
+	}
+	return dv.Spec.SnapshotTarget.PVCRetainPolicy
+}
+
+// syncSnapshotTarget sets the status of a DataVolume importing into a VolumeSnapshot. The storage class of the PVC is
+// checked before the PVC is created. Once the import succeeded, the PVC is snapshotted, and it is deleted when the
+// snapshot is ready unless it is retained. Returns true when the snapshot takes over the DataVolume, and the PVC must
+// not be synced nor created again.
+func (r *ImportReconciler) syncSnapshotTarget(syncState *dvSyncState) (bool, error) {
+	dv := syncState.dvMutated
+	pvc := syncState.pvc
+	snapshotName := getSnapshotTargetName(dv)
+	snapshot := &snapshotv1.VolumeSnapshot{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: dv.Namespace, Name: snapshotName}, snapshot); err != nil {
+		if !k8serrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return false, err
+		}
+		snapshot = nil
+	}
+	if snapshot != nil && snapshot.Annotations[annSnapshotTargetFor] != dv.Name {
+		return true, r.syncSnapshotTargetUnsupported(syncState, SnapshotTargetConflict, fmt.Sprintf(MessageSnapshotTargetConflict, snapshotName))
+	}
+
+	if snapshot == nil {
+		if pvc != nil && !snapshotTargetPvcImported(pvc, dv) {
+			return false, nil
+		}
+		snapshotClassName, message, err := r.getSnapshotTargetClass(dv, syncState.pvcSpec)
+		if err != nil {
+			return false, err
+		}
+		if snapshotClassName == "" {
+			return true, r.syncSnapshotTargetUnsupported(syncState, SnapshotTargetUnsupported, message)
+		}
+		if pvc == nil {
+			return false, nil
+		}
+		snapshot = newSnapshotTarget(dv, pvc.Name, snapshotName, snapshotClassName)
+		if err := r.client.Create(context.TODO(), snapshot); err != nil && !k8serrors.IsAlreadyExists(err) {
+			return false, err
+		}
+		r.log.V(1).Info("Snapshot target created", "snapshot.Namespace", snapshot.Namespace, "snapshot.Name", snapshot.Name)
+	}
+
+	if snapshot.Status == nil || snapshot.Status.ReadyToUse == nil || !*snapshot.Status.ReadyToUse {
+		event := Event{
+			eventType: corev1.EventTypeNormal,
+			reason:    SnapshotTargetInProgress,
+			message:   fmt.Sprintf(MessageSnapshotTargetInProgress, snapshotName, dv.Name),
+		}
+		if snapshot.Status != nil && snapshot.Status.Error != nil && snapshot.Status.Error.Message != nil {
+			event.eventType = corev1.EventTypeWarning
+			event.reason = SnapshotTargetFailed
+			event.message = fmt.Sprintf(MessageSnapshotTargetFailed, snapshotName, *snapshot.Status.Error.Message)
+		}
+		return true, r.syncDataVolumeStatusPhaseWithEvent(syncState, cdiv1.ImportInProgress, pvc, event)
+	}
+
+	if pvc != nil && getSnapshotTargetPVCRetainPolicy(dv) == cdiv1.SnapshotTargetPVCDelete {
+		if pvc.DeletionTimestamp == nil {
+			if err := r.client.Delete(context.TODO(), pvc); err != nil && !k8serrors.IsNotFound(err) {
+				return false, err
+			}
+			r.log.V(1).Info("Snapshot target ready, PVC deleted", "pvc.Namespace", pvc.Namespace, "pvc.Name", pvc.Name)
+		}
+		pvc = nil
+	}
+	return true, r.syncDataVolumeStatusPhaseWithEvent(syncState, cdiv1.Succeeded, pvc, Event{
+		eventType: corev1.EventTypeNormal,
+		reason:    SnapshotTargetReady,
+		message:   fmt.Sprintf(MessageSnapshotTargetReady, snapshotName),
+	})
+}
+
+// syncSnapshotTargetUnsupported reports the snapshot target of the DataVolume can't be taken, the DataVolume keeps its
+// phase until it is fixed
+func (r *ImportReconciler) syncSnapshotTargetUnsupported(syncState *dvSyncState, reason, message string) error {
+	phase := syncState.dv.Status.Phase
+	if phase == cdiv1.PhaseUnset {
+		phase = cdiv1.Pending
+	}
+	return r.syncDataVolumeStatusPhaseWithEvent(syncState, phase, nil, Event{
+		eventType: corev1.EventTypeWarning,
+		reason:    reason,
+		message:   message,
+	})
+}
+
+// snapshotTargetPvcImported tells whether the import into the PVC of the DataVolume is complete
+func snapshotTargetPvcImported(pvc *corev1.PersistentVolumeClaim, dv *cdiv1.DataVolume) bool {
+	if pvcIsPopulated(pvc, dv) {
+		return true
+	}
+	return pvc.Status.Phase == corev1.ClaimBound &&
+		pvc.Annotations[cc.AnnPodPhase] == string(corev1.PodSucceeded) &&
+		!metav1.HasAnnotation(pvc.ObjectMeta, cc.AnnCurrentCheckpoint)
+}
+
+// getSnapshotTargetClass returns the VolumeSnapshotClass of the snapshot target of the DataVolume. The class of the
+// spec must match the provisioner of the storage class of the PVC, otherwise a matching class is looked up. If there is
+// none, an empty name is returned with the reason.
+func (r *ImportReconciler) getSnapshotTargetClass(dv *cdiv1.DataVolume, pvcSpec *corev1.PersistentVolumeClaimSpec) (string, string, error) {
+	if !isCsiCrdsDeployed(r.client, r.log) {
+		return "", MessageSnapshotTargetNoCRDs, nil
+	}
+	storageClass, err := cc.GetStorageClassByName(r.client, pvcSpec.StorageClassName)
+	if err != nil {
+		return "", "", err
+	}
+	if storageClass == nil {
+		return "", MessageSnapshotTargetNoStorageClass, nil
+	}
+
+	if className := dv.Spec.SnapshotTarget.VolumeSnapshotClassName; className != nil && *className != "" {
+		snapshotClass := &snapshotv1.VolumeSnapshotClass{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: *className}, snapshotClass); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return "", "", err
+			}
+			return "", fmt.Sprintf(MessageSnapshotClassUnsupported, *className, storageClass.Name), nil
+		}
+		if snapshotClass.Driver != storageClass.Provisioner {
+			return "", fmt.Sprintf(MessageSnapshotClassUnsupported, *className, storageClass.Name), nil
+		}
+		return *className, "", nil
+	}
+
+	snapshotClasses := &snapshotv1.VolumeSnapshotClassList{}
+	if err := r.client.List(context.TODO(), snapshotClasses); err != nil {
+		return "", "", err
+	}
+	for _, snapshotClass := range snapshotClasses.Items {
+		if snapshotClass.Driver == storageClass.Provisioner {
+			return snapshotClass.Name, "", nil
+		}
+	}
+	return "", fmt.Sprintf(MessageSnapshotTargetUnsupported, storageClass.Name), nil
+}
+
+// newSnapshotTarget returns the VolumeSnapshot of the PVC of a DataVolume. It is not owned by the DataVolume, so it is
+// kept as the imported artifact when the DataVolume is deleted.
+func newSnapshotTarget(dv *cdiv1.DataVolume, pvcName, snapshotName, snapshotClassName string) *snapshotv1.VolumeSnapshot {
+	return &snapshotv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      snapshotName,
+			Namespace: dv.Namespace,
+			Annotations: map[string]string{
+				annSnapshotTargetFor: dv.Name,
+			},
+		},
+		Spec: snapshotv1.VolumeSnapshotSpec{
+			Source: snapshotv1.VolumeSnapshotSource{
+				PersistentVolumeClaimName: &pvcName,
+			},
+			VolumeSnapshotClassName: &snapshotClassName,
+		},
+	}
+}
+
+// addSnapshotTargetWatch reconciles the DataVolumes importing into a VolumeSnapshot when their snapshot changes
+func addSnapshotTargetWatch(mgr manager.Manager, c controller.Controller) error {
+	// check if volume snapshots exist
+	err := mgr.GetClient().List(context.TODO(), &snapshotv1.VolumeSnapshotList{})
+	if meta.IsNoMatchError(err) {
+		return nil
+	}
+	if err != nil && !cc.IsErrCacheNotStarted(err) {
+		return err
+	}
+
+	return c.Watch(&source.Kind{Type: &snapshotv1.VolumeSnapshot{}}, handler.EnqueueRequestsFromMapFunc(
+		func(obj client.Object) []reconcile.Request {
+			dvName, ok := obj.GetAnnotations()[annSnapshotTargetFor]
+			if !ok {
+				return nil
+			}
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: dvName}}}
+		},
+	))
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datavolume
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	. "kubevirt.io/containerized-data-importer/pkg/controller/common"
+)
+
+var _ = Describe("DataVolume importing into a VolumeSnapshot", func() {
+	const snapshotName = "golden"
+	storageClassName := "csi-sc"
+	snapshotClassName := "snap-class"
+	otherSnapshotClassName := "other-class"
+	readyToUse := true
+	dvKey := types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}
+	snapshotKey := types.NamespacedName{Name: snapshotName, Namespace: metav1.NamespaceDefault}
+
+	newSnapshotTargetDataVolume := func(target *cdiv1.DataVolumeSnapshotTarget) *cdiv1.DataVolume {
+		dv := newImportDataVolumeWithPvc("test-dv", &corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1G")},
+			},
+			StorageClassName: &storageClassName,
+		})
+		dv.Spec.SnapshotTarget = target
+		return dv
+	}
+
+	newImportedPvc := func(dv *cdiv1.DataVolume) *corev1.PersistentVolumeClaim {
+		pvc := CreatePvcInStorageClass("test-dv", metav1.NamespaceDefault, &storageClassName,
+			map[string]string{AnnPodPhase: string(corev1.PodSucceeded)}, nil, corev1.ClaimBound)
+		pvc.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(dv, cdiv1.SchemeGroupVersion.WithKind("DataVolume"))}
+		return pvc
+	}
+
+	newReadySnapshot := func(annotations map[string]string) *snapshotv1.VolumeSnapshot {
+		return &snapshotv1.VolumeSnapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        snapshotName,
+				Namespace:   metav1.NamespaceDefault,
+				Annotations: annotations,
+			},
+			Status: &snapshotv1.VolumeSnapshotStatus{
+				ReadyToUse: &readyToUse,
+			},
+		}
+	}
+
+	reconcileSnapshotTarget := func(objects ...runtime.Object) *ImportReconciler {
+		objects = append(objects,
+			CreateStorageClassWithProvisioner(storageClassName, nil, nil, "csi-plugin"),
+			createVolumeSnapshotContentCrd(), createVolumeSnapshotClassCrd(), createVolumeSnapshotCrd())
+		reconciler := createImportReconciler(objects...)
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: dvKey})
+		Expect(err).ToNot(HaveOccurred())
+		return reconciler
+	}
+
+	getDataVolume := func(reconciler *ImportReconciler) *cdiv1.DataVolume {
+		dv := &cdiv1.DataVolume{}
+		Expect(reconciler.client.Get(context.TODO(), dvKey, dv)).To(Succeed())
+		return dv
+	}
+
+	expectPvcExists := func(reconciler *ImportReconciler, exists bool) {
+		err := reconciler.client.Get(context.TODO(), dvKey, &corev1.PersistentVolumeClaim{})
+		if exists {
+			Expect(err).ToNot(HaveOccurred())
+		} else {
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		}
+	}
+
+	expectEvent := func(reconciler *ImportReconciler, substrings ...string) {
+		close(reconciler.recorder.(*record.FakeRecorder).Events)
+		found := false
+		for event := range reconciler.recorder.(*record.FakeRecorder).Events {
+			matches := true
+			for _, s := range substrings {
+				matches = matches && strings.Contains(event, s)
+			}
+			found = found || matches
+		}
+		Expect(found).To(BeTrue())
+	}
+
+	It("Should create the PVC when a VolumeSnapshotClass matches its storage class", func() {
+		dv := newSnapshotTargetDataVolume(&cdiv1.DataVolumeSnapshotTarget{Name: snapshotName})
+		reconciler := reconcileSnapshotTarget(dv, createSnapshotClass("snap-class", nil, "csi-plugin"))
+		expectPvcExists(reconciler, true)
+		err := reconciler.client.Get(context.TODO(), snapshotKey, &snapshotv1.VolumeSnapshot{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Should not create the PVC when no VolumeSnapshotClass matches its storage class", func() {
+		dv := newSnapshotTargetDataVolume(&cdiv1.DataVolumeSnapshotTarget{Name: snapshotName})
+		reconciler := reconcileSnapshotTarget(dv, createSnapshotClass("snap-class", nil, "other-plugin"))
+		expectPvcExists(reconciler, false)
+		dv = getDataVolume(reconciler)
+		Expect(dv.Status.Phase).To(Equal(cdiv1.Pending))
+		expectEvent(reconciler, SnapshotTargetUnsupported, "No VolumeSnapshotClass matches the provisioner of storage class "+storageClassName)
+	})
+
+	It("Should not create the PVC when the VolumeSnapshotClass of the spec does not match its storage class", func() {
+		dv := newSnapshotTargetDataVolume(&cdiv1.DataVolumeSnapshotTarget{
+			Name:                    snapshotName,
+			VolumeSnapshotClassName: &otherSnapshotClassName,
+		})
+		reconciler := reconcileSnapshotTarget(dv,
+			createSnapshotClass("snap-class", nil, "csi-plugin"), createSnapshotClass("other-class", nil, "other-plugin"))
+		expectPvcExists(reconciler, false)
+		expectEvent(reconciler, SnapshotTargetUnsupported, "VolumeSnapshotClass other-class does not exist or does not match")
+	})
+
+	It("Should snapshot the PVC once the import succeeded", func() {
+		dv := newSnapshotTargetDataVolume(&cdiv1.DataVolumeSnapshotTarget{
+			Name:                    snapshotName,
+			VolumeSnapshotClassName: &snapshotClassName,
+		})
+		reconciler := reconcileSnapshotTarget(dv, newImportedPvc(dv),
+			createSnapshotClass("snap-class", nil, "csi-plugin"))
+
+		snapshot := &snapshotv1.VolumeSnapshot{}
+		Expect(reconciler.client.Get(context.TODO(), snapshotKey, snapshot)).To(Succeed())
+		Expect(*snapshot.Spec.Source.PersistentVolumeClaimName).To(Equal("test-dv"))
+		Expect(*snapshot.Spec.VolumeSnapshotClassName).To(Equal("snap-class"))
+		Expect(snapshot.Annotations[annSnapshotTargetFor]).To(Equal("test-dv"))
+		Expect(snapshot.OwnerReferences).To(BeEmpty())
+		expectPvcExists(reconciler, true)
+		dv = getDataVolume(reconciler)
+		Expect(dv.Status.Phase).To(Equal(cdiv1.ImportInProgress))
+		expectEvent(reconciler, SnapshotTargetInProgress)
+	})
+
+	It("Should not snapshot the PVC while the import runs", func() {
+		dv := newSnapshotTargetDataVolume(&cdiv1.DataVolumeSnapshotTarget{Name: snapshotName})
+		pvc := newImportedPvc(dv)
+		pvc.Annotations[AnnPodPhase] = string(corev1.PodRunning)
+		reconciler := reconcileSnapshotTarget(dv, pvc, createSnapshotClass("snap-class", nil, "csi-plugin"))
+
+		err := reconciler.client.Get(context.TODO(), snapshotKey, &snapshotv1.VolumeSnapshot{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		expectPvcExists(reconciler, true)
+	})
+
+	It("Should delete the PVC once the snapshot is ready, and not create it again", func() {
+		dv := newSnapshotTargetDataVolume(&cdiv1.DataVolumeSnapshotTarget{Name: snapshotName})
+		reconciler := reconcileSnapshotTarget(dv, newImportedPvc(dv),
+			newReadySnapshot(map[string]string{annSnapshotTargetFor: "test-dv"}))
+		expectPvcExists(reconciler, false)
+		dv = getDataVolume(reconciler)
+		Expect(dv.Status.Phase).To(Equal(cdiv1.Succeeded))
+		readyCondition := FindConditionByType(cdiv1.DataVolumeReady, dv.Status.Conditions)
+		Expect(readyCondition.Status).To(Equal(corev1.ConditionTrue))
+		expectEvent(reconciler, SnapshotTargetReady, "Successfully imported into VolumeSnapshot "+snapshotName)
+
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: dvKey})
+		Expect(err).ToNot(HaveOccurred())
+		expectPvcExists(reconciler, false)
+		Expect(getDataVolume(reconciler).Status.Phase).To(Equal(cdiv1.Succeeded))
+	})
+
+	It("Should keep the PVC once the snapshot is ready with the Retain policy", func() {
+		dv := newSnapshotTargetDataVolume(&cdiv1.DataVolumeSnapshotTarget{
+			Name:            snapshotName,
+			PVCRetainPolicy: cdiv1.SnapshotTargetPVCRetain,
+		})
+		reconciler := reconcileSnapshotTarget(dv, newImportedPvc(dv),
+			newReadySnapshot(map[string]string{annSnapshotTargetFor: "test-dv"}))
+		expectPvcExists(reconciler, true)
+		Expect(getDataVolume(reconciler).Status.Phase).To(Equal(cdiv1.Succeeded))
+	})
+
+	It("Should not import into a VolumeSnapshot that was not taken for the DataVolume", func() {
+		dv := newSnapshotTargetDataVolume(&cdiv1.DataVolumeSnapshotTarget{Name: snapshotName})
+		reconciler := reconcileSnapshotTarget(dv, newReadySnapshot(nil), createSnapshotClass("snap-class", nil, "csi-plugin"))
+		expectPvcExists(reconciler, false)
+		expectEvent(reconciler, SnapshotTargetConflict)
+	})
+})
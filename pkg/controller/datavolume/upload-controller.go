@@ -71,8 +71,10 @@ func NewUploadController(
 	reconciler := &UploadReconciler{
 		ReconcilerBase: ReconcilerBase{
 			client:          client,
+			uncachedClient:  mgr.GetAPIReader(),
 			scheme:          mgr.GetScheme(),
 			log:             log.WithName(uploadControllerName),
+			controllerName:  uploadControllerName,
 			recorder:        mgr.GetEventRecorderFor(uploadControllerName),
 			featureGates:    featuregates.NewFeatureGates(client),
 			installerLabels: installerLabels,
@@ -124,16 +126,22 @@ func (r *UploadReconciler) syncUpload(log logr.Logger, req reconcile.Request) (d
 }
 
 func (r *UploadReconciler) updateStatusPhase(pvc *corev1.PersistentVolumeClaim, dataVolumeCopy *cdiv1.DataVolume, event *Event) error {
+	updateUploadStatusPhase(pvc, dataVolumeCopy, event)
+	return nil
+}
+
+// updateUploadStatusPhase updates the phase of a DataVolume populated by an upload server pod
+func updateUploadStatusPhase(pvc *corev1.PersistentVolumeClaim, dataVolumeCopy *cdiv1.DataVolume, event *Event) {
 	phase, ok := pvc.Annotations[cc.AnnPodPhase]
 	if phase != string(corev1.PodSucceeded) {
 		_, ok = pvc.Annotations[cc.AnnUploadRequest]
 		if !ok || pvc.Status.Phase != corev1.ClaimBound || pvcIsPopulated(pvc, dataVolumeCopy) {
-			return nil
+			return
 		}
 		dataVolumeCopy.Status.Phase = cdiv1.UploadScheduled
 	}
 	if !ok {
-		return nil
+		return
 	}
 	switch phase {
 	case string(corev1.PodPending):
@@ -161,5 +169,4 @@ func (r *UploadReconciler) updateStatusPhase(pvc *corev1.PersistentVolumeClaim,
 		event.reason = UploadSucceeded
 		event.message = fmt.Sprintf(MessageUploadSucceeded, pvc.Name)
 	}
-	return nil
 }
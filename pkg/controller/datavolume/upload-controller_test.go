@@ -107,11 +107,12 @@ func createUploadReconcilerWithoutConfig(objects ...runtime.Object) *UploadRecon
 	// Create a ReconcileMemcached object with the scheme and fake client.
 	r := &UploadReconciler{
 		ReconcilerBase: ReconcilerBase{
-			client:       cl,
-			scheme:       s,
-			log:          dvUploadLog,
-			recorder:     rec,
-			featureGates: featuregates.NewFeatureGates(cl),
+			client:         cl,
+			uncachedClient: cl,
+			scheme:         s,
+			log:            dvUploadLog,
+			recorder:       rec,
+			featureGates:   featuregates.NewFeatureGates(cl),
 			installerLabels: map[string]string{
 				common.AppKubernetesPartOfLabel:  "testing",
 				common.AppKubernetesVersionLabel: "v0.0.0-tests",
@@ -27,6 +27,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -44,8 +45,26 @@ import (
 const (
 	// AnnOwnedByDataVolume annotation has the owner DataVolume name
 	AnnOwnedByDataVolume = "cdi.kubevirt.io/ownedByDataVolume"
+
+	// noProvisioner is the provisioner of the storage classes of statically provisioned PVs
+	noProvisioner = "kubernetes.io/no-provisioner"
 )
 
+// incompleteStorageProfileError reports the StorageProfile of the target storage class has no access modes to complete
+// the DataVolume storage spec, or that the storage class has no provisioner nor available PV to bind the PVC, retrying
+// won't help until the StorageProfile, the PVs or the DataVolume are fixed
+type incompleteStorageProfileError struct {
+	storageClass  string
+	noProvisioner bool
+}
+
+func (e *incompleteStorageProfileError) Error() string {
+	if e.noProvisioner {
+		return fmt.Sprintf(MessageNoProvisionerStorageProfile, e.storageClass, e.storageClass)
+	}
+	return fmt.Sprintf(MessageIncompleteStorageProfile, e.storageClass, e.storageClass)
+}
+
 // renderPvcSpec creates a new PVC Spec based on either the dv.spec.pvc or dv.spec.storage section
 func renderPvcSpec(client client.Client, recorder record.EventRecorder, log logr.Logger, dv *cdiv1.DataVolume) (*v1.PersistentVolumeClaimSpec, error) {
 	if dv.Spec.PVC != nil {
@@ -73,8 +92,10 @@ func pvcFromStorage(client client.Client, recorder record.EventRecorder, log log
 		pvcSpec.VolumeMode = &volumeMode
 	}
 
-	storageClass, err := cc.GetStorageClassByName(client, storage.StorageClassName)
+	storageClass, err := getTargetStorageClass(client, storage.StorageClassName)
 	if err != nil {
+		log.V(1).Info("Cannot resolve the storageClass for new pvc", "namespace", dv.Namespace, "name", dv.Name, "Error", err)
+		recorder.Eventf(dv, v1.EventTypeWarning, cc.ErrClaimNotValid, "DataVolume.storage spec has an invalid storageClassName: %s", err.Error())
 		return nil, err
 	}
 
@@ -128,6 +149,23 @@ func pvcFromStorage(client client.Client, recorder record.EventRecorder, log log
 	return pvcSpec, nil
 }
 
+// getTargetStorageClass returns the storage class picked by the DataVolume, or the default storage class. The access
+// modes and volume mode missing from the spec are resolved from the StorageProfile of this storage class, regardless
+// of the storage class of any source.
+func getTargetStorageClass(c client.Client, name *string) (*storagev1.StorageClass, error) {
+	if name == nil {
+		return cc.GetStorageClassByName(c, nil)
+	}
+	storageClass := &storagev1.StorageClass{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: *name}, storageClass); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, errors.Errorf("StorageClass %s not found", *name)
+		}
+		return nil, err
+	}
+	return storageClass, nil
+}
+
 func getName(storageClass *storagev1.StorageClass) string {
 	if storageClass != nil {
 		return storageClass.Name
@@ -151,6 +189,37 @@ func copyStorageAsPvc(log logr.Logger, storage *cdiv1.StorageSpec) *v1.Persisten
 	return pvcSpec
 }
 
+// checkNoProvisionerStorageClass returns an incompleteStorageProfileError if the storage class of the PVC spec has no
+// provisioner and no PV available to bind the PVC, which would then stay pending
+func checkNoProvisionerStorageClass(c client.Client, pvcSpec *v1.PersistentVolumeClaimSpec) error {
+	var storageClass *storagev1.StorageClass
+	if pvcSpec.StorageClassName == nil {
+		defaultClass, err := cc.GetDefaultStorageClass(c)
+		if err != nil {
+			return err
+		}
+		storageClass = defaultClass
+	} else if *pvcSpec.StorageClassName != "" {
+		storageClass = &storagev1.StorageClass{}
+		if err := c.Get(context.TODO(), types.NamespacedName{Name: *pvcSpec.StorageClassName}, storageClass); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+	}
+	if storageClass == nil || storageClass.Provisioner != noProvisioner {
+		return nil
+	}
+	pvList := &v1.PersistentVolumeList{}
+	if err := c.List(context.TODO(), pvList); err != nil {
+		return err
+	}
+	for _, pv := range pvList.Items {
+		if pv.Spec.StorageClassName == storageClass.Name && pv.Status.Phase == v1.VolumeAvailable {
+			return nil
+		}
+	}
+	return &incompleteStorageProfileError{storageClass: storageClass.Name, noProvisioner: true}
+}
+
 func getDefaultVolumeAndAccessMode(c client.Client, storageClass *storagev1.StorageClass) ([]v1.PersistentVolumeAccessMode, *v1.PersistentVolumeMode, error) {
 	if storageClass == nil {
 		return nil, nil, errors.Errorf("no accessMode defined on DV and no StorageProfile")
@@ -170,7 +239,7 @@ func getDefaultVolumeAndAccessMode(c client.Client, storageClass *storagev1.Stor
 	}
 
 	// no accessMode configured on storageProfile
-	return nil, nil, errors.Errorf("no accessMode defined DV nor on StorageProfile for %s StorageClass", storageClass.Name)
+	return nil, nil, &incompleteStorageProfileError{storageClass: storageClass.Name}
 }
 
 func getDefaultVolumeMode(c client.Client, storageClass *storagev1.StorageClass, pvcAccessModes []v1.PersistentVolumeAccessMode) (*v1.PersistentVolumeMode, error) {
@@ -237,7 +306,23 @@ func getDefaultAccessModes(c client.Client, storageClass *storagev1.StorageClass
 	}
 
 	// no accessMode configured on storageProfile
-	return nil, errors.Errorf("no accessMode defined on StorageProfile for %s StorageClass", storageClass.Name)
+	return nil, &incompleteStorageProfileError{storageClass: storageClass.Name}
+}
+
+// storageProfileSupportsModes returns true if a claim property set of the StorageProfile supports all the access modes
+// with the volume mode, any volume mode when it is nil. A StorageProfile without access modes supports any of them.
+func storageProfileSupportsModes(storageProfile *cdiv1.StorageProfile, volumeMode *v1.PersistentVolumeMode, accessModes []v1.PersistentVolumeAccessMode) bool {
+	complete := false
+	for _, cps := range storageProfile.Status.ClaimPropertySets {
+		if len(cps.AccessModes) == 0 {
+			continue
+		}
+		complete = true
+		if cc.ClaimPropertySetSupports(cps, volumeMode, accessModes) {
+			return true
+		}
+	}
+	return !complete
 }
 
 func resolveVolumeSize(c client.Client, dvSpec cdiv1.DataVolumeSpec, pvcSpec *v1.PersistentVolumeClaimSpec) (*resource.Quantity, error) {
@@ -0,0 +1,156 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datavolume
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+)
+
+const (
+	// SourceVerificationScheduled provides a const to indicate the verification of the import source is scheduled
+	SourceVerificationScheduled = "SourceVerificationScheduled"
+	// SourceVerificationInProgress provides a const to indicate the import source is being verified
+	SourceVerificationInProgress = "SourceVerificationInProgress"
+	// SourceVerified provides a const to indicate the import source was verified
+	SourceVerified = "SourceVerified"
+	// SourceVerificationFailed provides a const to indicate the verification of the import source failed
+	SourceVerificationFailed = "SourceVerificationFailed"
+
+	// MessageSourceVerificationScheduled provides a const to form the source verification scheduled message
+	MessageSourceVerificationScheduled = "Verification of the source of %s scheduled"
+	// MessageSourceVerificationInProgress provides a const to form the source verification in progress message
+	MessageSourceVerificationInProgress = "Verification of the source of %s in progress"
+
+	// podRunningReason is the reason of the running condition of a running pod, as set by the import controller
+	podRunningReason = "Pod is running"
+)
+
+// syncVerifyOnly sets the status of a verify-only DataVolume, no PVC is created for it. The import verify controller
+// runs the importer pod checking the source, and its phase and running condition are reported in the DataVolume status,
+// as the import controller reports them through the PVC annotations. The outcome is kept once the pod is deleted.
+func (r *ImportReconciler) syncVerifyOnly(syncState *dvSyncState) error {
+	dv := syncState.dvMutated
+	if IsVerifyOnlyDone(dv) {
+		return r.syncDataVolumeStatusPhaseWithEvent(syncState, dv.Status.Phase, nil, Event{})
+	}
+	pod, err := r.getVerifyPod(dv)
+	if err != nil {
+		return err
+	}
+	var phase cdiv1.DataVolumePhase
+	event := Event{}
+	podPhase := corev1.PodPhase("")
+	if pod != nil {
+		podPhase = pod.Status.Phase
+	}
+	switch podPhase {
+	case corev1.PodPending:
+		phase = cdiv1.ImportScheduled
+		event.eventType = corev1.EventTypeNormal
+		event.reason = SourceVerificationScheduled
+		event.message = fmt.Sprintf(MessageSourceVerificationScheduled, dv.Name)
+	case corev1.PodRunning:
+		phase = cdiv1.ImportInProgress
+		event.eventType = corev1.EventTypeNormal
+		event.reason = SourceVerificationInProgress
+		event.message = fmt.Sprintf(MessageSourceVerificationInProgress, dv.Name)
+	case corev1.PodSucceeded:
+		phase = cdiv1.SourceVerified
+		event.eventType = corev1.EventTypeNormal
+		event.reason = SourceVerified
+		event.message = podRunningAnnotations(pod)[cc.AnnRunningConditionMessage]
+	case corev1.PodFailed:
+		phase = cdiv1.Failed
+		event.eventType = corev1.EventTypeWarning
+		event.reason = SourceVerificationFailed
+		event.message = podRunningAnnotations(pod)[cc.AnnRunningConditionMessage]
+	default:
+		phase = cdiv1.Pending
+	}
+	return r.syncDataVolumeStatusPhaseWithEvent(syncState, phase, nil, event)
+}
+
+// IsVerifyOnlyDone returns true once the status of the verify-only DataVolume reports the outcome of the verification,
+// or it was canceled
+func IsVerifyOnlyDone(dv *cdiv1.DataVolume) bool {
+	return dv.Status.Phase == cdiv1.SourceVerified || dv.Status.Phase == cdiv1.Failed || dv.Status.Phase == cdiv1.Canceled
+}
+
+// getVerifyPod returns the importer pod verifying the source of the DataVolume, nil if it does not exist
+func (r *ReconcilerBase) getVerifyPod(dv *cdiv1.DataVolume) (*corev1.Pod, error) {
+	pod := &corev1.Pod{}
+	key := types.NamespacedName{Namespace: dv.Namespace, Name: cc.GetImportVerifyPodName(dv.Name)}
+	if err := r.client.Get(context.TODO(), key, pod); err != nil {
+		return nil, cc.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(pod, dv) {
+		return nil, nil
+	}
+	return pod, nil
+}
+
+// verifyPodRunningAnnotations returns the running condition of the importer pod verifying the source, in the
+// annotations the import controller sets on the PVC. Once the pod is gone the current running condition is kept.
+func (r *ReconcilerBase) verifyPodRunningAnnotations(dv *cdiv1.DataVolume) map[string]string {
+	pod, err := r.getVerifyPod(dv)
+	if err != nil {
+		r.log.Error(err, "Unable to get the importer pod verifying the source", "DataVolume", dv.Name)
+	}
+	if pod != nil {
+		return podRunningAnnotations(pod)
+	}
+	anno := make(map[string]string)
+	if running := FindConditionByType(cdiv1.DataVolumeRunning, dv.Status.Conditions); running != nil {
+		anno[cc.AnnRunningCondition] = strings.ToLower(string(running.Status))
+		anno[cc.AnnRunningConditionReason] = running.Reason
+		anno[cc.AnnRunningConditionMessage] = running.Message
+	}
+	return anno
+}
+
+// podRunningAnnotations returns the running condition of the container of the pod, in the running condition
+// annotations
+func podRunningAnnotations(pod *corev1.Pod) map[string]string {
+	anno := make(map[string]string)
+	if len(pod.Status.ContainerStatuses) == 0 {
+		return anno
+	}
+	state := pod.Status.ContainerStatuses[0].State
+	switch {
+	case state.Running != nil:
+		anno[cc.AnnRunningCondition] = "true"
+		anno[cc.AnnRunningConditionReason] = podRunningReason
+	case state.Terminated != nil:
+		anno[cc.AnnRunningCondition] = "false"
+		anno[cc.AnnRunningConditionReason] = state.Terminated.Reason
+		anno[cc.AnnRunningConditionMessage] = state.Terminated.Message
+	case state.Waiting != nil:
+		anno[cc.AnnRunningCondition] = "false"
+		anno[cc.AnnRunningConditionReason] = state.Waiting.Reason
+		anno[cc.AnnRunningConditionMessage] = state.Waiting.Message
+	}
+	return anno
+}
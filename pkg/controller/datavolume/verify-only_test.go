@@ -0,0 +1,150 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datavolume
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	. "kubevirt.io/containerized-data-importer/pkg/controller/common"
+)
+
+var _ = Describe("Verify-only DataVolume", func() {
+	dvKey := types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}
+
+	newVerifyOnlyDataVolume := func() *cdiv1.DataVolume {
+		dv := newImportDataVolumeWithPvc("test-dv", &corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1G")},
+			},
+		})
+		dv.Annotations = map[string]string{AnnVerifyOnly: "true"}
+		return dv
+	}
+
+	newVerifyPod := func(dv *cdiv1.DataVolume, phase corev1.PodPhase, state corev1.ContainerState) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            GetImportVerifyPodName(dv.Name),
+				Namespace:       dv.Namespace,
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(dv, cdiv1.SchemeGroupVersion.WithKind("DataVolume"))},
+			},
+			Status: corev1.PodStatus{
+				Phase:             phase,
+				ContainerStatuses: []corev1.ContainerStatus{{State: state}},
+			},
+		}
+	}
+
+	reconcileVerifyOnly := func(objects ...runtime.Object) (*ImportReconciler, *cdiv1.DataVolume) {
+		reconciler := createImportReconciler(objects...)
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: dvKey})
+		Expect(err).ToNot(HaveOccurred())
+
+		err = reconciler.client.Get(context.TODO(), dvKey, &corev1.PersistentVolumeClaim{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		dv := &cdiv1.DataVolume{}
+		Expect(reconciler.client.Get(context.TODO(), dvKey, dv)).To(Succeed())
+		return reconciler, dv
+	}
+
+	expectEvent := func(reconciler *ImportReconciler, substrings ...string) {
+		close(reconciler.recorder.(*record.FakeRecorder).Events)
+		found := false
+		for event := range reconciler.recorder.(*record.FakeRecorder).Events {
+			matches := true
+			for _, s := range substrings {
+				matches = matches && strings.Contains(event, s)
+			}
+			found = found || matches
+		}
+		Expect(found).To(BeTrue())
+	}
+
+	It("Should stay pending without creating the PVC until the importer pod exists", func() {
+		_, dv := reconcileVerifyOnly(newVerifyOnlyDataVolume())
+		Expect(dv.Status.Phase).To(Equal(cdiv1.Pending))
+	})
+
+	It("Should be in progress while the importer pod runs", func() {
+		dv := newVerifyOnlyDataVolume()
+		reconciler, dv := reconcileVerifyOnly(dv, newVerifyPod(dv, corev1.PodRunning, corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}))
+		Expect(dv.Status.Phase).To(Equal(cdiv1.ImportInProgress))
+		running := FindConditionByType(cdiv1.DataVolumeRunning, dv.Status.Conditions)
+		Expect(running).ToNot(BeNil())
+		Expect(running.Status).To(Equal(corev1.ConditionTrue))
+		Expect(dv.Annotations).ToNot(HaveKey(AnnPodPhase))
+		expectEvent(reconciler, SourceVerificationInProgress)
+	})
+
+	It("Should report the verified source once the importer pod succeeds", func() {
+		message := "Source verified, format: qcow2, virtual size: 46137344"
+		dv := newVerifyOnlyDataVolume()
+		reconciler, dv := reconcileVerifyOnly(dv, newVerifyPod(dv, corev1.PodSucceeded, corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{Reason: "Completed", Message: message},
+		}))
+		Expect(dv.Status.Phase).To(Equal(cdiv1.SourceVerified))
+		running := FindConditionByType(cdiv1.DataVolumeRunning, dv.Status.Conditions)
+		Expect(running).ToNot(BeNil())
+		Expect(running.Status).To(Equal(corev1.ConditionFalse))
+		Expect(running.Message).To(Equal(message))
+		Expect(dv.Annotations).To(Equal(map[string]string{AnnVerifyOnly: "true"}))
+		expectEvent(reconciler, corev1.EventTypeNormal, SourceVerified, message)
+	})
+
+	It("Should fail with a warning once the importer pod fails", func() {
+		message := "Unable to connect to http data source: expected status code 200, got 401. Status: 401 Unauthorized"
+		dv := newVerifyOnlyDataVolume()
+		reconciler, dv := reconcileVerifyOnly(dv, newVerifyPod(dv, corev1.PodFailed, corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{Reason: "Error", Message: message},
+		}))
+		Expect(dv.Status.Phase).To(Equal(cdiv1.Failed))
+		running := FindConditionByType(cdiv1.DataVolumeRunning, dv.Status.Conditions)
+		Expect(running).ToNot(BeNil())
+		Expect(running.Message).To(Equal(message))
+		expectEvent(reconciler, corev1.EventTypeWarning, SourceVerificationFailed, message)
+	})
+
+	It("Should keep the outcome once the importer pod is deleted", func() {
+		message := "Source verified, format: qcow2, virtual size: 46137344"
+		dv := newVerifyOnlyDataVolume()
+		dv.Status.Phase = cdiv1.SourceVerified
+		dv.Status.Conditions = []cdiv1.DataVolumeCondition{{
+			Type: cdiv1.DataVolumeRunning, Status: corev1.ConditionFalse, Reason: "Completed", Message: message,
+		}}
+		_, dv = reconcileVerifyOnly(dv)
+		Expect(dv.Status.Phase).To(Equal(cdiv1.SourceVerified))
+		running := FindConditionByType(cdiv1.DataVolumeRunning, dv.Status.Conditions)
+		Expect(running).ToNot(BeNil())
+		Expect(running.Reason).To(Equal("Completed"))
+		Expect(running.Message).To(Equal(message))
+	})
+})
@@ -11,10 +11,12 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	ocpcrypto "github.com/openshift/library-go/pkg/crypto"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -33,7 +35,10 @@ import (
 	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
 	featuregates "kubevirt.io/containerized-data-importer/pkg/feature-gates"
 	"kubevirt.io/containerized-data-importer/pkg/util"
+	"kubevirt.io/containerized-data-importer/pkg/util/cgroup"
+	"kubevirt.io/containerized-data-importer/pkg/util/logging"
 	"kubevirt.io/containerized-data-importer/pkg/util/naming"
+	cryptowatch "kubevirt.io/containerized-data-importer/pkg/util/tls-crypto-watch"
 	sdkapi "kubevirt.io/controller-lifecycle-operator-sdk/api"
 )
 
@@ -49,12 +54,35 @@ const (
 	// ImportTargetInUse is reason for event created when an import pvc is in use
 	ImportTargetInUse = "ImportTargetInUse"
 
+	// ImportAttemptsExhausted provides a const to indicate the import failed the maximum number of attempts
+	ImportAttemptsExhausted = "ImportAttemptsExhausted"
+	// MessageImportAttemptsExhausted provides a const to form the message of an import that failed the maximum number of attempts
+	MessageImportAttemptsExhausted = "Import failed after %d attempts: %s"
+
+	// S3KMSAccessDenied provides a const to indicate the importer was denied the use of the KMS key of an S3 object
+	S3KMSAccessDenied = "S3KMSAccessDenied"
+
+	// UnsupportedFormat provides a const to indicate the qemu-img of the importer does not support the format of the image
+	UnsupportedFormat = "UnsupportedFormat"
+	// ToolUnavailable provides a const to indicate the importer could not run qemu-img to convert the image
+	ToolUnavailable = "ToolUnavailable"
+
+	// ImportTLSDowngraded provides a const to indicate the DataVolume weakened the TLS settings of the connection to the import source
+	ImportTLSDowngraded = "ImportTLSDowngraded"
+	// MessageImportTLSDowngraded provides a const to form the message of the weakened TLS settings of the connection to the import source
+	MessageImportTLSDowngraded = "The TLS settings of the import source are weaker than the CDIConfig import TLS security profile, minimal version %s, ciphers %s"
+
 	// importPodImageStreamFinalizer ensures image stream import pod is deleted when pvc is deleted,
 	// as in this case pod has no pvc OwnerReference
 	importPodImageStreamFinalizer = "cdi.kubevirt.io/importImageStream"
 
 	// secretExtraHeadersVolumeName is the format string that specifies where extra HTTP header secrets will be mounted
 	secretExtraHeadersVolumeName = "cdi-secret-extra-headers-vol-%d"
+
+	// tokenVolumeName is the name of the volume containing the projected service account token
+	tokenVolumeName = "cdi-token-vol"
+	// tokenExpirationSeconds is the requested lifetime of the projected service account token, the kubelet rotates it before it expires
+	tokenExpirationSeconds = int64(3600)
 )
 
 // ImportReconciler members
@@ -96,8 +124,35 @@ type importPodEnvVar struct {
 	httpsProxy         string
 	noProxy            string
 	certConfigMapProxy string
+	proxyAuthSecret    string
 	extraHeaders       []string
 	secretExtraHeaders []string
+	multipartParallel  string
+	multipartPartSize  string
+	tokenAudience      string
+	tokenRoleARN       string
+	tokenSA            string
+	gcsUserProject     string
+	gcsGeneration      string
+	sftpHostKey        string
+	s3KMSKeyID         string
+	tlsMinVersion      string
+	tlsCiphers         string
+	verifyOnly         bool
+	shrinkToUsedSize   bool
+	targetFormat       string
+	targetCompression  string
+	sourceETag         string
+	sourceLastModified string
+	changedRangesURL   string
+	checksumManifest   string
+	registryDiskPath   string
+	digestAlgorithm    string
+	tarMember          string
+	encryptionSecret   string
+	freeSpaceMargin    string
+	maxCopyBufferSize  string
+	targetMustBeEmpty  bool
 }
 
 type importerPodArgs struct {
@@ -108,11 +163,14 @@ type importerPodArgs struct {
 	podEnvVar               *importPodEnvVar
 	pvc                     *corev1.PersistentVolumeClaim
 	scratchPvcName          *string
+	scratchEmptyDir         *corev1.EmptyDirVolumeSource
 	podResourceRequirements *corev1.ResourceRequirements
+	podIOLimits             *cdiv1.PodIOLimits
 	imagePullSecrets        []corev1.LocalObjectReference
 	workloadNodePlacement   *sdkapi.NodePlacement
 	vddkImageName           *string
 	priorityClassName       string
+	verifyOnly              bool
 }
 
 // NewImportController creates a new instance of the import controller.
@@ -204,6 +262,10 @@ func (r *ImportReconciler) Reconcile(_ context.Context, req reconcile.Request) (
 		return reconcile.Result{}, err
 	}
 
+	if res, err := r.reconcileChangedRangesRefresh(pvc, log); res != nil || err != nil {
+		return *res, err
+	}
+
 	shouldReconcile, err := r.shouldReconcilePVC(pvc, log)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -237,6 +299,34 @@ func (r *ImportReconciler) Reconcile(_ context.Context, req reconcile.Request) (
 	return r.reconcilePvc(pvc, log)
 }
 
+// reconcileChangedRangesRefresh starts a new import of the changed ranges of a completed PVC whose
+// AnnChangedRangesRefresh changed since the last one, once the pod of the previous import is gone. Returns nil when
+// there is nothing to refresh.
+func (r *ImportReconciler) reconcileChangedRangesRefresh(pvc *corev1.PersistentVolumeClaim, log logr.Logger) (*reconcile.Result, error) {
+	refresh := pvc.Annotations[cc.AnnChangedRangesRefresh]
+	if refresh == "" || refresh == pvc.Annotations[cc.AnnChangedRangesLastRefresh] ||
+		pvc.Annotations[cc.AnnChangedRangesURL] == "" || pvc.Annotations[cc.AnnSource] != cc.SourceHTTP || !cc.IsPVCComplete(pvc) {
+		return nil, nil
+	}
+	pod, err := r.findImporterPod(pvc, log)
+	if err != nil {
+		return &reconcile.Result{}, err
+	}
+	if pod != nil {
+		// The pod of the previous import was retained, its phase would complete the new import
+		if pod.DeletionTimestamp == nil {
+			if err := r.client.Delete(context.TODO(), pod); cc.IgnoreNotFound(err) != nil {
+				return &reconcile.Result{}, err
+			}
+		}
+		return &reconcile.Result{RequeueAfter: 2 * time.Second}, nil
+	}
+	log.V(1).Info("Importing the changed ranges again", "refresh", refresh)
+	delete(pvc.Annotations, cc.AnnPodPhase)
+	pvc.Annotations[cc.AnnChangedRangesLastRefresh] = refresh
+	return &reconcile.Result{}, r.updatePVC(pvc, log)
+}
+
 func (r *ImportReconciler) findImporterPod(pvc *corev1.PersistentVolumeClaim, log logr.Logger) (*corev1.Pod, error) {
 	podName := getImportPodNameFromPvc(pvc)
 	pod := &corev1.Pod{}
@@ -260,10 +350,23 @@ func (r *ImportReconciler) reconcilePvc(pvc *corev1.PersistentVolumeClaim, log l
 		return reconcile.Result{}, err
 	}
 
+	if cc.IsTransferStopped(pvc) {
+		// Stop the import and don't recreate the POD once the DataVolume failed its completion timeout or was canceled
+		log.V(1).Info("PVC DataVolume failed its completion timeout or was canceled")
+		if pod != nil {
+			return reconcile.Result{}, r.cleanup(pvc, pod, log)
+		}
+		return reconcile.Result{}, nil
+	}
+
 	if pod == nil {
 		if cc.IsPVCComplete(pvc) {
 			// Don't create the POD if the PVC is completed already
 			log.V(1).Info("PVC is already complete")
+		} else if importAttemptsExhausted(pvc) {
+			// Don't recreate the POD once the import failed the maximum number of attempts
+			log.V(1).Info("PVC import failed the maximum number of attempts")
+			return reconcile.Result{}, nil
 		} else if pvc.DeletionTimestamp == nil {
 			podsUsingPVC, err := cc.GetPodsUsingPVCs(r.client, pvc.Namespace, sets.NewString(pvc.Name), false)
 			if err != nil {
@@ -282,6 +385,10 @@ func (r *ImportReconciler) reconcilePvc(pvc *corev1.PersistentVolumeClaim, log l
 			}
 
 			if _, ok := pvc.Annotations[cc.AnnImportPod]; ok {
+				// Wait for a worker pod slot under the limit
+				if admitted, err := reconcileWorkerPodQueue(r.client, pvc); err != nil || !admitted {
+					return reconcile.Result{RequeueAfter: workerPodQueueRequeueInterval}, err
+				}
 				// Create importer pod, make sure the PVC owns it.
 				if err := r.createImporterPod(pvc); err != nil {
 					return reconcile.Result{}, err
@@ -304,6 +411,10 @@ func (r *ImportReconciler) reconcilePvc(pvc *corev1.PersistentVolumeClaim, log l
 			if err := r.copyImportProxyConfigMap(pvc, pod); err != nil {
 				return reconcile.Result{}, err
 			}
+			// Copy import proxy auth Secret (if exists) from cdi namespace to the import namespace
+			if err := r.copyImportProxyAuthSecret(pvc, pod); err != nil {
+				return reconcile.Result{}, err
+			}
 			// Pod exists, we need to update the PVC status.
 			if err := r.updatePvcFromPod(pvc, pod, log); err != nil {
 				return reconcile.Result{}, err
@@ -359,6 +470,48 @@ func GetImportProxyConfigMapName(pvcName string) string {
 	return naming.GetResourceName("import-proxy-cm", pvcName)
 }
 
+func (r *ImportReconciler) copyImportProxyAuthSecret(pvc *corev1.PersistentVolumeClaim, pod *corev1.Pod) error {
+	cdiConfig := &cdiv1.CDIConfig{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig); err != nil {
+		return err
+	}
+	secretName, err := GetImportProxyConfig(cdiConfig, common.ImportProxyAuthSecretName)
+	if err != nil || secretName == "" {
+		return nil
+	}
+	cdiSecret := &corev1.Secret{}
+	if err := r.uncachedClient.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: r.cdiNamespace}, cdiSecret); err != nil {
+		return err
+	}
+	importSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetImportProxyAuthSecretName(pvc.Name),
+			Namespace: pvc.Namespace,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         pod.APIVersion,
+				Kind:               pod.Kind,
+				Name:               pod.Name,
+				UID:                pod.UID,
+				BlockOwnerDeletion: pointer.Bool(true),
+				Controller:         pointer.Bool(true),
+			}},
+		},
+		Data: map[string][]byte{
+			corev1.BasicAuthUsernameKey: cdiSecret.Data[corev1.BasicAuthUsernameKey],
+			corev1.BasicAuthPasswordKey: cdiSecret.Data[corev1.BasicAuthPasswordKey],
+		},
+	}
+	if err := r.client.Create(context.TODO(), importSecret); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// GetImportProxyAuthSecretName returns the import proxy auth Secret name
+func GetImportProxyAuthSecretName(pvcName string) string {
+	return naming.GetResourceName("import-proxy-auth", pvcName)
+}
+
 func (r *ImportReconciler) initPvcPodName(pvc *corev1.PersistentVolumeClaim, log logr.Logger) error {
 	currentPvcCopy := pvc.DeepCopyObject()
 
@@ -388,6 +541,11 @@ func (r *ImportReconciler) updatePvcFromPod(pvc *corev1.PersistentVolumeClaim, p
 	log.V(1).Info("Updating PVC from pod")
 	anno := pvc.GetAnnotations()
 	setAnnotationsFromPodWithPrefix(anno, pod, cc.AnnRunningCondition)
+	setImageAnnotations(anno, pod)
+	setImportTimingsAnnotations(anno, pod)
+	setTargetImageAnnotations(anno, pod)
+	setSourceValidatorsAnnotations(anno, pod)
+	setSourceDigestAnnotation(anno, pod)
 
 	scratchExitCode := false
 	if pod.Status.ContainerStatuses != nil &&
@@ -399,7 +557,8 @@ func (r *ImportReconciler) updatePvcFromPod(pvc *corev1.PersistentVolumeClaim, p
 			scratchExitCode = true
 			anno[cc.AnnRequiresScratch] = "true"
 		} else {
-			r.recorder.Event(pvc, corev1.EventTypeWarning, ErrImportFailedPVC, pod.Status.ContainerStatuses[0].LastTerminationState.Terminated.Message)
+			message := pod.Status.ContainerStatuses[0].LastTerminationState.Terminated.Message
+			r.recorder.Event(pvc, corev1.EventTypeWarning, knownFailureReason(message, ErrImportFailedPVC), message)
 		}
 	}
 
@@ -407,15 +566,20 @@ func (r *ImportReconciler) updatePvcFromPod(pvc *corev1.PersistentVolumeClaim, p
 		anno[cc.AnnCurrentPodID] = string(pod.ObjectMeta.UID)
 	}
 
+	attemptsExhausted := !scratchExitCode && r.checkImportAttempts(pvc, pod, log)
+
 	anno[cc.AnnImportPod] = string(pod.Name)
-	if !scratchExitCode {
+	if attemptsExhausted {
+		anno[cc.AnnPodPhase] = string(corev1.PodFailed)
+	} else if !scratchExitCode {
 		// No scratch exit code, update the phase based on the pod. If we do have scratch exit code we don't want to update the
 		// phase, because the pod might terminate cleanly and mistakenly mark the import complete.
 		anno[cc.AnnPodPhase] = string(pod.Status.Phase)
 	}
 
-	// Check if the POD is waiting for scratch space, if so create some.
-	if pod.Status.Phase == corev1.PodPending && r.requiresScratchSpace(pvc) {
+	// Check if the POD is waiting for scratch space, if so create some, or report whether the existing one is bound.
+	_, hasScratch := getScratchNameFromPod(pod)
+	if pod.Status.Phase == corev1.PodPending && (r.requiresScratchSpace(pvc) || hasScratch) && !hasScratchEmptyDir(pod) {
 		if err := r.createScratchPvcForPod(pvc, pod); err != nil {
 			if !k8serrors.IsAlreadyExists(err) {
 				return err
@@ -442,6 +606,12 @@ func (r *ImportReconciler) updatePvcFromPod(pvc *corev1.PersistentVolumeClaim, p
 		log.V(1).Info("Updated PVC", "pvc.anno.Phase", anno[cc.AnnPodPhase], "pvc.anno.Restarts", anno[cc.AnnPodRestarts])
 	}
 
+	if attemptsExhausted {
+		r.recorder.Event(pvc, corev1.EventTypeWarning, ImportAttemptsExhausted, anno[cc.AnnRunningConditionMessage])
+		log.V(1).Info("Deleting pod of failed import", "pod.Name", pod.Name)
+		return r.cleanup(pvc, pod, log)
+	}
+
 	if cc.IsPVCComplete(pvc) || scratchExitCode {
 		if !scratchExitCode {
 			r.recorder.Event(pvc, corev1.EventTypeNormal, ImportSucceededPVC, "Import Successful")
@@ -457,6 +627,35 @@ func (r *ImportReconciler) updatePvcFromPod(pvc *corev1.PersistentVolumeClaim, p
 	return nil
 }
 
+// checkImportAttempts fails the import once the importer pod failed the maximum number of attempts since the import
+// last made progress, and returns whether it did
+func (r *ImportReconciler) checkImportAttempts(pvc *corev1.PersistentVolumeClaim, pod *corev1.Pod, log logr.Logger) bool {
+	maxAttempts := cc.GetImportMaxAttempts(r.client, pvc)
+	if maxAttempts == 0 || len(pod.Status.ContainerStatuses) == 0 {
+		return false
+	}
+	base, _ := strconv.Atoi(pvc.Annotations[cc.AnnImportAttemptsBase])
+	failedAttempts := int(pod.Status.ContainerStatuses[0].RestartCount) - base
+	if failedAttempts < int(maxAttempts) {
+		return false
+	}
+	lastError := "unknown error"
+	if terminated := pod.Status.ContainerStatuses[0].LastTerminationState.Terminated; terminated != nil && terminated.Message != "" {
+		lastError = simplifyKnownMessage(terminated.Message)
+	}
+	log.Info("Import failed the maximum number of attempts", "pod.Name", pod.Name, "attempts", failedAttempts)
+	anno := pvc.GetAnnotations()
+	anno[cc.AnnImportAttemptsExhausted] = "true"
+	anno[cc.AnnRunningCondition] = "false"
+	anno[cc.AnnRunningConditionReason] = ImportAttemptsExhausted
+	anno[cc.AnnRunningConditionMessage] = fmt.Sprintf(MessageImportAttemptsExhausted, failedAttempts, lastError)
+	return true
+}
+
+func importAttemptsExhausted(pvc *corev1.PersistentVolumeClaim) bool {
+	return pvc.Annotations[cc.AnnImportAttemptsExhausted] == "true"
+}
+
 func (r *ImportReconciler) cleanup(pvc *corev1.PersistentVolumeClaim, pod *corev1.Pod, log logr.Logger) error {
 	if err := r.client.Delete(context.TODO(), pod); cc.IgnoreNotFound(err) != nil {
 		return err
@@ -481,13 +680,25 @@ func (r *ImportReconciler) updatePVC(pvc *corev1.PersistentVolumeClaim, log logr
 func (r *ImportReconciler) createImporterPod(pvc *corev1.PersistentVolumeClaim) error {
 	r.log.V(1).Info("Creating importer POD for PVC", "pvc.Name", pvc.Name)
 	var scratchPvcName *string
+	var scratchEmptyDir *corev1.EmptyDirVolumeSource
 	var vddkImageName *string
 	var err error
 
+	// A new importer pod counts its failed attempts from zero
+	if _, ok := pvc.Annotations[cc.AnnImportAttemptsBase]; ok {
+		delete(pvc.Annotations, cc.AnnImportAttemptsBase)
+		if err := r.updatePVC(pvc, r.log); err != nil {
+			return err
+		}
+	}
+
 	requiresScratch := r.requiresScratchSpace(pvc)
 	if requiresScratch {
-		name := createScratchNameFromPvc(pvc)
-		scratchPvcName = &name
+		scratchEmptyDir = makeScratchEmptyDir(cc.GetScratchSpaceBackend(r.client, pvc), pvc)
+		if scratchEmptyDir == nil {
+			name := createScratchNameFromPvc(pvc)
+			scratchPvcName = &name
+		}
 	}
 
 	if cc.GetSource(pvc) == cc.SourceVDDK {
@@ -524,6 +735,7 @@ func (r *ImportReconciler) createImporterPod(pvc *corev1.PersistentVolumeClaim)
 		podEnvVar:         podEnvVar,
 		pvc:               pvc,
 		scratchPvcName:    scratchPvcName,
+		scratchEmptyDir:   scratchEmptyDir,
 		vddkImageName:     vddkImageName,
 		priorityClassName: cc.GetPriorityClass(pvc),
 	}
@@ -545,7 +757,7 @@ func (r *ImportReconciler) createImporterPod(pvc *corev1.PersistentVolumeClaim)
 		}
 	}
 
-	if requiresScratch {
+	if scratchPvcName != nil {
 		r.log.V(1).Info("Pod requires scratch space")
 		return r.createScratchPvcForPod(pvc, pod)
 	}
@@ -553,6 +765,32 @@ func (r *ImportReconciler) createImporterPod(pvc *corev1.PersistentVolumeClaim)
 	return nil
 }
 
+// targetMustBeEmpty returns true if the importer must check the adopted PVC is empty before writing to it. The later
+// stages of a multi-stage import and a refresh of the changed ranges find the data of the first import.
+func targetMustBeEmpty(pvc *corev1.PersistentVolumeClaim) bool {
+	return cc.IsAdoptedPVC(pvc) && pvc.Annotations[cc.AnnPreviousCheckpoint] == "" &&
+		pvc.Annotations[cc.AnnChangedRangesLastRefresh] == ""
+}
+
+// makeScratchEmptyDir returns the emptyDir backing the scratch space of the PVC importer pod, sized like the PVC, nil when
+// the scratch space is backed by a PVC. A PVC without size has its scratch space backed by a PVC, as an emptyDir
+// can't be sized for it.
+func makeScratchEmptyDir(backend cdiv1.ScratchSpaceBackend, pvc *corev1.PersistentVolumeClaim) *corev1.EmptyDirVolumeSource {
+	size, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	if !ok || size.IsZero() {
+		return nil
+	}
+	emptyDir := &corev1.EmptyDirVolumeSource{SizeLimit: &size}
+	switch backend {
+	case cdiv1.ScratchSpaceBackendEmptyDirDisk:
+	case cdiv1.ScratchSpaceBackendEmptyDirMemory:
+		emptyDir.Medium = corev1.StorageMediumMemory
+	default:
+		return nil
+	}
+	return emptyDir
+}
+
 func createScratchNameFromPvc(pvc *v1.PersistentVolumeClaim) string {
 	return naming.GetResourceName(pvc.Name, common.ScratchNameSuffix)
 }
@@ -570,7 +808,7 @@ func (r *ImportReconciler) createImportEnvVar(pvc *corev1.PersistentVolumeClaim)
 		}
 		podEnvVar.secretName = r.getSecretName(pvc)
 		if podEnvVar.secretName == "" {
-			r.log.V(2).Info("no secret will be supplied to endpoint", "endPoint", podEnvVar.ep)
+			r.log.V(2).Info("no secret will be supplied to endpoint", "endPoint", util.RedactSourceURL(podEnvVar.ep))
 		}
 		//get the CDIConfig to extract the proxy configuration to be used to import an image
 		cdiConfig := &cdiv1.CDIConfig{}
@@ -586,6 +824,10 @@ func (r *ImportReconciler) createImportEnvVar(pvc *corev1.PersistentVolumeClaim)
 		if err != nil {
 			return nil, err
 		}
+		podEnvVar.tlsMinVersion, podEnvVar.tlsCiphers, err = r.getImportTLS(pvc, cdiConfig)
+		if err != nil {
+			return nil, err
+		}
 		podEnvVar.diskID = getValueFromAnnotation(pvc, cc.AnnDiskID)
 		podEnvVar.backingFile = getValueFromAnnotation(pvc, cc.AnnBackingFile)
 		podEnvVar.uuid = getValueFromAnnotation(pvc, cc.AnnUUID)
@@ -593,6 +835,26 @@ func (r *ImportReconciler) createImportEnvVar(pvc *corev1.PersistentVolumeClaim)
 		podEnvVar.previousCheckpoint = getValueFromAnnotation(pvc, cc.AnnPreviousCheckpoint)
 		podEnvVar.currentCheckpoint = getValueFromAnnotation(pvc, cc.AnnCurrentCheckpoint)
 		podEnvVar.finalCheckpoint = getValueFromAnnotation(pvc, cc.AnnFinalCheckpoint)
+		podEnvVar.multipartParallel = getValueFromAnnotation(pvc, cc.AnnMultipartParallelism)
+		podEnvVar.multipartPartSize = getValueFromAnnotation(pvc, cc.AnnMultipartPartSize)
+		podEnvVar.tokenAudience = getValueFromAnnotation(pvc, cc.AnnTokenAudience)
+		podEnvVar.tokenRoleARN = getValueFromAnnotation(pvc, cc.AnnTokenRoleARN)
+		podEnvVar.tokenSA = getValueFromAnnotation(pvc, cc.AnnTokenServiceAccount)
+		podEnvVar.gcsUserProject = getValueFromAnnotation(pvc, cc.AnnGcsUserProject)
+		podEnvVar.gcsGeneration = getValueFromAnnotation(pvc, cc.AnnGcsGeneration)
+		podEnvVar.sftpHostKey = getValueFromAnnotation(pvc, cc.AnnSFTPHostKey)
+		podEnvVar.s3KMSKeyID = getValueFromAnnotation(pvc, cc.AnnS3KMSKeyID)
+		podEnvVar.registryDiskPath = getValueFromAnnotation(pvc, cc.AnnRegistryDiskPath)
+		podEnvVar.digestAlgorithm = getValueFromAnnotation(pvc, cc.AnnSourceDigestAlgorithm)
+		podEnvVar.tarMember = getValueFromAnnotation(pvc, cc.AnnTarMember)
+		podEnvVar.freeSpaceMargin = getValueFromAnnotation(pvc, cc.AnnFreeSpaceMargin)
+		podEnvVar.maxCopyBufferSize = getValueFromAnnotation(pvc, cc.AnnMaxCopyBufferSize)
+		if podEnvVar.source == cc.SourceHTTP {
+			podEnvVar.sourceETag = getValueFromAnnotation(pvc, cc.AnnSourceETag)
+			podEnvVar.sourceLastModified = getValueFromAnnotation(pvc, cc.AnnSourceLastModified)
+			podEnvVar.changedRangesURL = getValueFromAnnotation(pvc, cc.AnnChangedRangesURL)
+			podEnvVar.checksumManifest = getValueFromAnnotation(pvc, cc.AnnChecksumManifestURL)
+		}
 
 		for annotation, value := range pvc.Annotations {
 			if strings.HasPrefix(annotation, cc.AnnExtraHeaders) {
@@ -620,6 +882,12 @@ func (r *ImportReconciler) createImportEnvVar(pvc *corev1.PersistentVolumeClaim)
 			r.log.V(3).Info("no proxy CA certiticate will be supplied:", err.Error())
 		}
 		podEnvVar.certConfigMapProxy = field
+		if field, err = GetImportProxyConfig(cdiConfig, common.ImportProxyAuthSecretName); err != nil {
+			r.log.V(3).Info("no proxy credentials will be supplied:", err.Error())
+		}
+		if field != "" {
+			podEnvVar.proxyAuthSecret = GetImportProxyAuthSecretName(pvc.Name)
+		}
 	}
 
 	fsOverhead, err := GetFilesystemOverhead(r.client, pvc)
@@ -631,6 +899,11 @@ func (r *ImportReconciler) createImportEnvVar(pvc *corev1.PersistentVolumeClaim)
 	if preallocation, err := strconv.ParseBool(getValueFromAnnotation(pvc, cc.AnnPreallocationRequested)); err == nil {
 		podEnvVar.preallocation = preallocation
 	} // else use the default "false"
+	podEnvVar.shrinkToUsedSize = pvc.Annotations[cc.AnnShrinkToUsedSize] == "true"
+	podEnvVar.targetFormat = pvc.Annotations[cc.AnnTargetFormat]
+	podEnvVar.targetCompression = pvc.Annotations[cc.AnnTargetCompression]
+	podEnvVar.encryptionSecret = pvc.Annotations[cc.AnnEncryptionSecret]
+	podEnvVar.targetMustBeEmpty = targetMustBeEmpty(pvc)
 
 	//get the requested image size.
 	podEnvVar.imageSize, err = cc.GetRequestedImageSize(pvc)
@@ -648,6 +921,45 @@ func (r *ImportReconciler) isInsecureTLS(pvc *corev1.PersistentVolumeClaim, cdiC
 	return IsInsecureTLS(ep, cdiConfig, r.uncachedClient, r.log)
 }
 
+// getImportTLS returns the minimal TLS version and the comma separated cipher suites of the connection to the import
+// source. They are taken from the CDIConfig import TLS security profile, unless overridden by the PVC annotations.
+// Weakening the profile is opt-in through the annotations, and reported with a warning event.
+func (r *ImportReconciler) getImportTLS(pvc *corev1.PersistentVolumeClaim, cdiConfig *cdiv1.CDIConfig) (string, string, error) {
+	profileCiphers, profileMinVersion := cryptowatch.SelectCipherSuitesAndMinTLSVersion(cdiConfig.Spec.ImportTLSSecurityProfile)
+	minVersion := string(profileMinVersion)
+	ciphers := profileCiphers
+	downgraded := false
+
+	if override, ok := pvc.Annotations[cc.AnnImportTLSMinVersion]; ok {
+		overrideVersion, err := ocpcrypto.TLSVersion(override)
+		if err != nil || override == "" {
+			return "", "", errors.Errorf("invalid minimal TLS version %q in annotation %s", override, cc.AnnImportTLSMinVersion)
+		}
+		profileVersion, err := ocpcrypto.TLSVersion(minVersion)
+		if err != nil {
+			return "", "", err
+		}
+		downgraded = overrideVersion < profileVersion
+		minVersion = override
+	}
+	if override, ok := pvc.Annotations[cc.AnnImportTLSCiphers]; ok {
+		profileCipherSet := sets.NewString(profileCiphers...)
+		ciphers = nil
+		for _, cipher := range strings.Split(override, ",") {
+			if cipher = strings.TrimSpace(cipher); cipher != "" {
+				ciphers = append(ciphers, cipher)
+				downgraded = downgraded || !profileCipherSet.Has(cipher)
+			}
+		}
+	}
+
+	if downgraded {
+		r.log.Info("Import source TLS settings weaker than the CDIConfig profile", "PVC", pvc.Name, "minVersion", minVersion, "ciphers", ciphers)
+		r.recorder.Eventf(pvc, corev1.EventTypeWarning, ImportTLSDowngraded, MessageImportTLSDowngraded, minVersion, strings.Join(ciphers, ","))
+	}
+	return minVersion, strings.Join(ciphers, ","), nil
+}
+
 // IsInsecureTLS checks if TLS security is disabled for the given endpoint
 func IsInsecureTLS(ep string, cdiConfig *cdiv1.CDIConfig, client client.Client, log logr.Logger) (bool, error) {
 	url, err := url.Parse(ep)
@@ -787,7 +1099,7 @@ func (r *ImportReconciler) createScratchPvcForPod(pvc *corev1.PersistentVolumeCl
 			}
 			return fmt.Errorf("terminating scratch space found, deleting pod %s", pod.Name)
 		}
-		setBoundConditionFromPVC(anno, cc.AnnBoundCondition, scratchPvc)
+		setScratchBoundConditionFromPVC(anno, scratchPvc)
 	}
 	anno[cc.AnnRequiresScratch] = "false"
 	return nil
@@ -824,7 +1136,7 @@ func getRegistryImportImage(pvc *corev1.PersistentVolumeClaim) (string, error) {
 	}
 	url, err := url.Parse(ep)
 	if err != nil {
-		return "", errors.Errorf("illegal registry endpoint %s", ep)
+		return "", errors.Errorf("illegal registry endpoint %s", util.RedactSourceURL(ep))
 	}
 	return url.Host + url.Path, nil
 }
@@ -867,12 +1179,22 @@ func createImporterPod(log logr.Logger, client client.Client, args *importerPodA
 		return nil, err
 	}
 
+	args.podIOLimits, err = cc.GetPodIOLimits(client)
+	if err != nil {
+		return nil, err
+	}
+
 	args.imagePullSecrets, err = cc.GetImagePullSecrets(client)
 	if err != nil {
 		return nil, err
 	}
 
-	args.workloadNodePlacement, err = cc.GetWorkloadNodePlacement(client)
+	args.workloadNodePlacement, err = cc.GetPvcWorkerPodNodePlacement(client, args.pvc)
+	if err != nil {
+		return nil, err
+	}
+
+	args.image, args.pullPolicy, err = cc.GetPvcWorkerPodImage(client, args.pvc, args.image, args.pullPolicy)
 	if err != nil {
 		return nil, err
 	}
@@ -888,6 +1210,10 @@ func createImporterPod(log logr.Logger, client client.Client, args *importerPodA
 		pod = makeImporterPodSpec(args)
 	}
 
+	if err = cc.SetTopologyNodeAffinity(&pod.Spec, args.pvc); err != nil {
+		return nil, err
+	}
+
 	util.SetRecommendedLabels(pod, installerLabels, "cdi-controller")
 
 	if err = client.Create(context.TODO(), pod); err != nil {
@@ -1009,7 +1335,7 @@ func makeNodeImporterPodSpec(args *importerPodArgs) *corev1.Pod {
 	args.podEnvVar.ep = "http://localhost:8100/disk.img"
 	args.podEnvVar.readyFile = "/shared/ready"
 	args.podEnvVar.doneFile = "/shared/done"
-	setImporterPodCommons(pod, args.podEnvVar, args.pvc, args.podResourceRequirements, args.imagePullSecrets)
+	setImporterPodCommons(pod, args.podEnvVar, args.pvc, args.podResourceRequirements, args.podIOLimits, args.imagePullSecrets)
 	pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
 		MountPath: "/shared",
 		Name:      "shared-volume",
@@ -1050,6 +1376,13 @@ func makeImporterPodSpec(args *importerPodArgs) *corev1.Pod {
 				},
 			},
 		})
+	} else if args.scratchEmptyDir != nil {
+		volumes = append(volumes, corev1.Volume{
+			Name: cc.ScratchVolName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: args.scratchEmptyDir,
+			},
+		})
 	}
 
 	importerContainer := makeImporterContainerSpec(args.image, args.verbose, args.pullPolicy)
@@ -1095,15 +1428,33 @@ func makeImporterPodSpec(args *importerPodArgs) *corev1.Pod {
 		},
 	}
 
-	setImporterPodCommons(pod, args.podEnvVar, args.pvc, args.podResourceRequirements, args.imagePullSecrets)
+	setImporterPodCommons(pod, args.podEnvVar, args.pvc, args.podResourceRequirements, args.podIOLimits, args.imagePullSecrets)
 
-	if args.scratchPvcName != nil {
+	if args.scratchEmptyDir != nil {
+		// The importer keeps the transfer within the limit, the kubelet evicts a pod exceeding it
+		size := *args.scratchEmptyDir.SizeLimit
+		pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  common.ImporterScratchSpaceLimit,
+			Value: strconv.FormatInt(size.Value(), 10),
+		})
+		if args.scratchEmptyDir.Medium == corev1.StorageMediumMemory {
+			addScratchResource(&pod.Spec.Containers[0], corev1.ResourceMemory, size)
+		} else {
+			addScratchResource(&pod.Spec.Containers[0], corev1.ResourceEphemeralStorage, size)
+		}
+	}
+
+	if args.scratchPvcName != nil || args.scratchEmptyDir != nil {
 		pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
 			Name:      cc.ScratchVolName,
 			MountPath: common.ScratchDataDir,
 		})
 	}
 
+	if args.podEnvVar.targetMustBeEmpty {
+		addTargetEmptyCheck(pod)
+	}
+
 	if args.vddkImageName != nil {
 		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
 			Name: "vddk-vol-mount",
@@ -1145,6 +1496,16 @@ func makeImporterPodSpec(args *importerPodArgs) *corev1.Pod {
 		pod.Spec.Volumes = append(pod.Spec.Volumes, createConfigMapVolume(ProxyCertVolName, GetImportProxyConfigMapName(args.pvc.Name)))
 	}
 
+	if args.podEnvVar.source == cc.SourceInline {
+		vm := corev1.VolumeMount{
+			Name:      InlineSourceVolName,
+			MountPath: common.ImporterInlineSourceDir,
+			ReadOnly:  true,
+		}
+		pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, vm)
+		pod.Spec.Volumes = append(pod.Spec.Volumes, createConfigMapVolume(InlineSourceVolName, args.podEnvVar.ep))
+	}
+
 	if args.podEnvVar.source == cc.SourceGCS && args.podEnvVar.secretName != "" {
 		vm := corev1.VolumeMount{
 			Name:      SecretVolName,
@@ -1154,6 +1515,66 @@ func makeImporterPodSpec(args *importerPodArgs) *corev1.Pod {
 		pod.Spec.Volumes = append(pod.Spec.Volumes, createSecretVolume(SecretVolName, args.podEnvVar.secretName))
 	}
 
+	if args.podEnvVar.source == cc.SourceNBD && args.podEnvVar.secretName != "" {
+		vm := corev1.VolumeMount{
+			Name:      SecretVolName,
+			MountPath: common.ImporterNbdCertDir,
+			ReadOnly:  true,
+		}
+		pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, vm)
+		pod.Spec.Volumes = append(pod.Spec.Volumes, createSecretVolume(SecretVolName, args.podEnvVar.secretName))
+	}
+
+	if args.podEnvVar.source == cc.SourceAzure && args.podEnvVar.secretName != "" {
+		vm := corev1.VolumeMount{
+			Name:      SecretVolName,
+			MountPath: common.ImporterAzureCredentialDir,
+			ReadOnly:  true,
+		}
+		pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, vm)
+		pod.Spec.Volumes = append(pod.Spec.Volumes, createSecretVolume(SecretVolName, args.podEnvVar.secretName))
+	}
+
+	if args.podEnvVar.source == cc.SourceSFTP && args.podEnvVar.secretName != "" {
+		vm := corev1.VolumeMount{
+			Name:      SecretVolName,
+			MountPath: common.ImporterSFTPCredentialDir,
+			ReadOnly:  true,
+		}
+		pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, vm)
+		pod.Spec.Volumes = append(pod.Spec.Volumes, createSecretVolume(SecretVolName, args.podEnvVar.secretName))
+	}
+
+	if args.podEnvVar.source == cc.SourceRegistry && args.podEnvVar.secretName != "" {
+		vm := corev1.VolumeMount{
+			Name:      SecretVolName,
+			MountPath: common.ImporterRegistryCredentialDir,
+			ReadOnly:  true,
+		}
+		pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, vm)
+		pod.Spec.Volumes = append(pod.Spec.Volumes, createSecretVolume(SecretVolName, args.podEnvVar.secretName))
+	}
+
+	if args.podEnvVar.encryptionSecret != "" {
+		vm := corev1.VolumeMount{
+			Name:      EncryptionKeyVolName,
+			MountPath: common.ImporterEncryptionKeyDir,
+			ReadOnly:  true,
+		}
+		pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, vm)
+		pod.Spec.Volumes = append(pod.Spec.Volumes, createEncryptionKeyVolume(args.podEnvVar.encryptionSecret))
+	}
+
+	if args.podEnvVar.tokenAudience != "" {
+		vm := corev1.VolumeMount{
+			Name:      tokenVolumeName,
+			MountPath: common.ImporterTokenDir,
+			ReadOnly:  true,
+		}
+		pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, vm)
+		pod.Spec.Volumes = append(pod.Spec.Volumes, createTokenVolume(args.podEnvVar))
+	}
+
 	for index, header := range args.podEnvVar.secretExtraHeaders {
 		vm := corev1.VolumeMount{
 			Name:      fmt.Sprintf(secretExtraHeadersVolumeName, index),
@@ -1173,12 +1594,16 @@ func makeImporterPodSpec(args *importerPodArgs) *corev1.Pod {
 		pod.Spec.Volumes = append(pod.Spec.Volumes, vol)
 	}
 
+	if args.verifyOnly {
+		setVerifyOnlyPodSpec(pod, args.pvc)
+	}
+
 	cc.SetRestrictedSecurityContext(&pod.Spec)
 
 	return pod
 }
 
-func setImporterPodCommons(pod *corev1.Pod, podEnvVar *importPodEnvVar, pvc *corev1.PersistentVolumeClaim, podResourceRequirements *corev1.ResourceRequirements, imagePullSecrets []corev1.LocalObjectReference) {
+func setImporterPodCommons(pod *corev1.Pod, podEnvVar *importPodEnvVar, pvc *corev1.PersistentVolumeClaim, podResourceRequirements *corev1.ResourceRequirements, podIOLimits *cdiv1.PodIOLimits, imagePullSecrets []corev1.LocalObjectReference) {
 	if podResourceRequirements != nil {
 		for i := range pod.Spec.Containers {
 			pod.Spec.Containers[i].Resources = *podResourceRequirements
@@ -1197,11 +1622,31 @@ func setImporterPodCommons(pod *corev1.Pod, podEnvVar *importPodEnvVar, pvc *cor
 		pod.Spec.Containers[0].VolumeMounts = cc.AddImportVolumeMounts()
 	}
 
-	pod.Spec.Containers[0].Env = makeImportEnv(podEnvVar, ownerUID)
+	pod.Spec.Containers[0].Env = append(makeImportEnv(podEnvVar, ownerUID), logging.PodEnv(pvc.Namespace, pvc.Name)...)
+	pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, cgroup.PodEnv(podIOLimits)...)
+	cgroup.SetPodBlockIOClass(pod, podIOLimits)
 
 	setPodPvcAnnotations(pod, pvc)
 }
 
+// addScratchResource adds the size of an emptyDir backed scratch space to the memory or ephemeral storage requested by
+// the container, and to its limit if it has one, as the emptyDir is charged to the pod. The request keeps the pod off the
+// nodes without room for the scratch space.
+func addScratchResource(container *corev1.Container, name corev1.ResourceName, size resource.Quantity) {
+	resources := container.Resources.DeepCopy()
+	if resources.Requests == nil {
+		resources.Requests = corev1.ResourceList{}
+	}
+	request := resources.Requests[name]
+	request.Add(size)
+	resources.Requests[name] = request
+	if limit, ok := resources.Limits[name]; ok {
+		limit.Add(size)
+		resources.Limits[name] = limit
+	}
+	container.Resources = *resources
+}
+
 func makeImporterContainerSpec(image, verbose, pullPolicy string) *corev1.Container {
 	return &corev1.Container{
 		Name:            common.ImporterPodName,
@@ -1242,6 +1687,58 @@ func createSecretVolume(thisVolName, objRef string) corev1.Volume {
 	}
 }
 
+// createEncryptionKeyVolume returns the volume holding the passphrase of a LUKS target, which fails to mount when the
+// secret has no passphrase
+func createEncryptionKeyVolume(secretName string) corev1.Volume {
+	return corev1.Volume{
+		Name: EncryptionKeyVolName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: secretName,
+				Items: []corev1.KeyToPath{{
+					Key:  common.EncryptionPassphraseKey,
+					Path: common.EncryptionPassphraseKey,
+				}},
+			},
+		},
+	}
+}
+
+// createTokenVolume returns the volume projecting the service account token the importer exchanges for the source
+// credentials
+func createTokenVolume(podEnvVar *importPodEnvVar) corev1.Volume {
+	expirationSeconds := tokenExpirationSeconds
+	return corev1.Volume{
+		Name: tokenVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{
+					{
+						ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+							Audience:          getTokenProjectionAudience(podEnvVar),
+							ExpirationSeconds: &expirationSeconds,
+							Path:              path.Base(common.ImporterTokenPath),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// getTokenProjectionAudience returns the audience of the projected token. A GCP workload identity provider accepts by
+// default the tokens whose audience is its https URL, while it is referred to as //iam.googleapis.com/...
+func getTokenProjectionAudience(podEnvVar *importPodEnvVar) string {
+	if podEnvVar.source == cc.SourceGCS && strings.HasPrefix(podEnvVar.tokenAudience, "//") {
+		return "https:" + podEnvVar.tokenAudience
+	}
+	return podEnvVar.tokenAudience
+}
+
+// ownCredentialSources are the sources reading the credentials of their secret on their own, the secret of the other
+// sources is passed to the importer as an access key id and secret key
+var ownCredentialSources = sets.NewString(cc.SourceGCS, cc.SourceNBD, cc.SourceRemote, cc.SourceAzure, cc.SourceSFTP)
+
 // return the Env portion for the importer container.
 func makeImportEnv(podEnvVar *importPodEnvVar, uid types.UID) []corev1.EnvVar {
 	env := []corev1.EnvVar{
@@ -1326,7 +1823,7 @@ func makeImportEnv(podEnvVar *importPodEnvVar, uid types.UID) []corev1.EnvVar {
 			Value: strconv.FormatBool(podEnvVar.preallocation),
 		},
 	}
-	if podEnvVar.secretName != "" && podEnvVar.source != cc.SourceGCS {
+	if podEnvVar.secretName != "" && !ownCredentialSources.Has(podEnvVar.source) {
 		env = append(env, corev1.EnvVar{
 			Name: common.ImporterAccessKeyID,
 			ValueFrom: &corev1.EnvVarSource{
@@ -1350,6 +1847,26 @@ func makeImportEnv(podEnvVar *importPodEnvVar, uid types.UID) []corev1.EnvVar {
 		})
 
 	}
+	if podEnvVar.secretName != "" && podEnvVar.source == cc.SourceRegistry {
+		// The mounted secret is re-read on each registry request, so its rotation is picked up without a new pod
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterRegistryCredentialDirVar,
+			Value: common.ImporterRegistryCredentialDir,
+		})
+	}
+	if podEnvVar.secretName != "" && podEnvVar.source == cc.SourceRemote {
+		env = append(env, corev1.EnvVar{
+			Name: common.ImporterBearerToken,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: podEnvVar.secretName,
+					},
+					Key: common.KeyToken,
+				},
+			},
+		})
+	}
 	if podEnvVar.secretName != "" && podEnvVar.source == cc.SourceGCS {
 		env = append(env, corev1.EnvVar{
 			Name:  common.ImporterGoogleCredentialFileVar,
@@ -1368,11 +1885,181 @@ func makeImportEnv(podEnvVar *importPodEnvVar, uid types.UID) []corev1.EnvVar {
 			Value: common.ImporterProxyCertDir,
 		})
 	}
+	if podEnvVar.proxyAuthSecret != "" {
+		env = append(env, corev1.EnvVar{
+			Name: common.ImportProxyUsername,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: podEnvVar.proxyAuthSecret,
+					},
+					Key: corev1.BasicAuthUsernameKey,
+				},
+			},
+		}, corev1.EnvVar{
+			Name: common.ImportProxyPassword,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: podEnvVar.proxyAuthSecret,
+					},
+					Key: corev1.BasicAuthPasswordKey,
+				},
+			},
+		})
+	}
 	for index, header := range podEnvVar.extraHeaders {
 		env = append(env, corev1.EnvVar{
 			Name:  fmt.Sprintf("%s%d", common.ImporterExtraHeader, index),
 			Value: header,
 		})
 	}
+	if podEnvVar.multipartParallel != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterMultipartParallelism,
+			Value: podEnvVar.multipartParallel,
+		})
+	}
+	if podEnvVar.multipartPartSize != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterMultipartPartSize,
+			Value: podEnvVar.multipartPartSize,
+		})
+	}
+	if podEnvVar.tokenAudience != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterTokenFile,
+			Value: common.ImporterTokenPath,
+		}, corev1.EnvVar{
+			Name:  common.ImporterTokenAudience,
+			Value: podEnvVar.tokenAudience,
+		}, corev1.EnvVar{
+			Name:  common.ImporterTokenRoleARN,
+			Value: podEnvVar.tokenRoleARN,
+		}, corev1.EnvVar{
+			Name:  common.ImporterTokenServiceAccount,
+			Value: podEnvVar.tokenSA,
+		})
+	}
+	if podEnvVar.gcsUserProject != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterGcsUserProject,
+			Value: podEnvVar.gcsUserProject,
+		})
+	}
+	if podEnvVar.gcsGeneration != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterGcsGeneration,
+			Value: podEnvVar.gcsGeneration,
+		})
+	}
+	if podEnvVar.sftpHostKey != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterSFTPHostKey,
+			Value: podEnvVar.sftpHostKey,
+		})
+	}
+	if podEnvVar.s3KMSKeyID != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterS3KMSKeyID,
+			Value: podEnvVar.s3KMSKeyID,
+		})
+	}
+	if podEnvVar.tlsMinVersion != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.MinVersionTLSVar,
+			Value: podEnvVar.tlsMinVersion,
+		})
+	}
+	if podEnvVar.tlsCiphers != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.CiphersTLSVar,
+			Value: podEnvVar.tlsCiphers,
+		})
+	}
+	if podEnvVar.verifyOnly {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterVerifyOnly,
+			Value: "true",
+		})
+	}
+	if podEnvVar.shrinkToUsedSize {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterShrinkToUsedSize,
+			Value: "true",
+		})
+	}
+	if podEnvVar.targetFormat != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterTargetFormat,
+			Value: podEnvVar.targetFormat,
+		})
+	}
+	if podEnvVar.targetCompression != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterTargetCompression,
+			Value: podEnvVar.targetCompression,
+		})
+	}
+	if podEnvVar.sourceETag != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterSourceETag,
+			Value: podEnvVar.sourceETag,
+		})
+	}
+	if podEnvVar.sourceLastModified != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterSourceLastModified,
+			Value: podEnvVar.sourceLastModified,
+		})
+	}
+	if podEnvVar.changedRangesURL != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterChangedRangesURL,
+			Value: podEnvVar.changedRangesURL,
+		})
+	}
+	if podEnvVar.checksumManifest != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterChecksumManifestURL,
+			Value: podEnvVar.checksumManifest,
+		})
+	}
+	if podEnvVar.digestAlgorithm != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterSourceDigestAlgorithm,
+			Value: podEnvVar.digestAlgorithm,
+		})
+	}
+	if podEnvVar.encryptionSecret != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterEncryptionKeyFile,
+			Value: path.Join(common.ImporterEncryptionKeyDir, common.EncryptionPassphraseKey),
+		})
+	}
+	if podEnvVar.tarMember != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterTarMember,
+			Value: podEnvVar.tarMember,
+		})
+	}
+	if podEnvVar.freeSpaceMargin != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterFreeSpaceMargin,
+			Value: podEnvVar.freeSpaceMargin,
+		})
+	}
+	if podEnvVar.maxCopyBufferSize != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterMaxCopyBufferSize,
+			Value: podEnvVar.maxCopyBufferSize,
+		})
+	}
+	if podEnvVar.registryDiskPath != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterRegistryDiskPath,
+			Value: podEnvVar.registryDiskPath,
+		})
+	}
 	return env
 }
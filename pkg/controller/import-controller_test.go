@@ -26,6 +26,7 @@ import (
 
 	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
 	featuregates "kubevirt.io/containerized-data-importer/pkg/feature-gates"
+	"kubevirt.io/containerized-data-importer/pkg/util/cgroup"
 	"kubevirt.io/containerized-data-importer/pkg/util/naming"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -34,6 +35,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
+	ocpconfigv1 "github.com/openshift/api/config/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -46,11 +48,13 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	kvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 )
 
 const (
@@ -123,6 +127,17 @@ var _ = Describe("Test PVC annotations status", func() {
 		}, nil)
 		Expect(r.shouldReconcilePVC(testPvc, importLog)).To(BeTrue())
 	})
+	It("Should be interesting if NOT BOUND, and endpoint and source is set, and honorWaitForFirstConsumerEnabled and a topology is requested", func() {
+		r := createImportReconciler()
+		r.featureGates = &FakeFeatureGates{honorWaitForFirstConsumerEnabled: true}
+		testPvc := createPendingPvc("testPvc1", "default", map[string]string{
+			cc.AnnPodPhase: string(corev1.PodPending),
+			cc.AnnEndpoint: testEndPoint,
+			cc.AnnSource:   cc.SourceHTTP,
+			cc.AnnTopology: "topology.kubernetes.io/zone=us-east-1a",
+		}, nil)
+		Expect(r.shouldReconcilePVC(testPvc, importLog)).To(BeTrue())
+	})
 	It("Should be interesting if NOT BOUND, and endpoint and source is set, and honorWaitForFirstConsumerEnabled is false and isImmediateBindingRequested is requested", func() {
 		r := createImportReconciler()
 		r.featureGates = &FakeFeatureGates{honorWaitForFirstConsumerEnabled: false}
@@ -209,6 +224,62 @@ var _ = Describe("ImportConfig Controller reconcile loop", func() {
 		Expect(reflect.DeepEqual(orgPvc, resPvc)).To(BeTrue())
 	})
 
+	It("Should import the changed ranges of a completed PVC again when their refresh annotation changes", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:                 testEndPoint,
+			cc.AnnSource:                   cc.SourceHTTP,
+			cc.AnnImportPod:                "importer-testPvc1",
+			cc.AnnPodPhase:                 string(corev1.PodSucceeded),
+			cc.AnnChangedRangesURL:         "http://example.com/ranges.json",
+			cc.AnnChangedRangesRefresh:     "2",
+			cc.AnnChangedRangesLastRefresh: "1",
+		}, nil)
+		pvc.Status.Phase = v1.ClaimBound
+		retainedPod := cc.CreateImporterTestPod(pvc, "testPvc1", nil)
+		reconciler = createImportReconciler(pvc, retainedPod)
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}}
+
+		By("Deleting the retained pod of the previous import first")
+		result, err := reconciler.Reconcile(context.TODO(), req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).ToNot(BeZero())
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: retainedPod.Name, Namespace: "default"}, &corev1.Pod{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		By("Restarting the import")
+		_, err = reconciler.Reconcile(context.TODO(), req)
+		Expect(err).ToNot(HaveOccurred())
+		resultPvc := &corev1.PersistentVolumeClaim{}
+		Expect(reconciler.client.Get(context.TODO(), req.NamespacedName, resultPvc)).To(Succeed())
+		Expect(resultPvc.Annotations).ToNot(HaveKey(cc.AnnPodPhase))
+		Expect(resultPvc.Annotations[cc.AnnChangedRangesLastRefresh]).To(Equal("2"))
+
+		_, err = reconciler.Reconcile(context.TODO(), req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, &corev1.Pod{})).To(Succeed())
+	})
+
+	It("Should not import the changed ranges of a completed PVC again for an unchanged refresh annotation", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:                 testEndPoint,
+			cc.AnnSource:                   cc.SourceHTTP,
+			cc.AnnImportPod:                "importer-testPvc1",
+			cc.AnnPodPhase:                 string(corev1.PodSucceeded),
+			cc.AnnChangedRangesURL:         "http://example.com/ranges.json",
+			cc.AnnChangedRangesRefresh:     "1",
+			cc.AnnChangedRangesLastRefresh: "1",
+		}, nil)
+		pvc.Status.Phase = v1.ClaimBound
+		reconciler = createImportReconciler(pvc)
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		resultPvc := &corev1.PersistentVolumeClaim{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, resultPvc)).To(Succeed())
+		Expect(resultPvc.Annotations[cc.AnnPodPhase]).To(Equal(string(corev1.PodSucceeded)))
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, &corev1.Pod{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
 	It("Should init PVC with a POD name if a PVC with all needed annotations is passed", func() {
 		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{cc.AnnEndpoint: testEndPoint}, nil)
 		pvc.Status.Phase = v1.ClaimBound
@@ -316,6 +387,247 @@ var _ = Describe("ImportConfig Controller reconcile loop", func() {
 		Expect(pod.Spec.Tolerations).To(Equal(dummyTolerations))
 	})
 
+	It("Should pin the POD to the topology of the PVC in addition to the node placement", func() {
+		topology := "topology.kubernetes.io/zone in (us-east-1a,us-east-1b),topology.kubernetes.io/region=us-east-1"
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{cc.AnnEndpoint: testEndPoint, cc.AnnImportPod: "importer-testPvc1", cc.AnnTopology: topology}, nil)
+		pvc.Status.Phase = v1.ClaimPending
+		reconciler = createImportReconciler(pvc)
+
+		cr := &cdiv1.CDI{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "cdi"}, cr)).To(Succeed())
+		hostnameRequirement := v1.NodeSelectorRequirement{Key: "kubernetes.io/hostname", Operator: v1.NodeSelectorOpIn, Values: []string{"node01", "node02"}}
+		archRequirement := v1.NodeSelectorRequirement{Key: "kubernetes.io/arch", Operator: v1.NodeSelectorOpIn, Values: []string{"amd64"}}
+		cr.Spec.Workloads.Affinity = &v1.Affinity{
+			NodeAffinity: &v1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+					NodeSelectorTerms: []v1.NodeSelectorTerm{
+						{MatchExpressions: []v1.NodeSelectorRequirement{hostnameRequirement}},
+						{MatchExpressions: []v1.NodeSelectorRequirement{archRequirement}},
+					},
+				},
+			},
+		}
+		Expect(reconciler.client.Update(context.TODO(), cr)).To(Succeed())
+
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		pod := &corev1.Pod{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, pod)).To(Succeed())
+
+		regionRequirement := v1.NodeSelectorRequirement{Key: "topology.kubernetes.io/region", Operator: v1.NodeSelectorOpIn, Values: []string{"us-east-1"}}
+		zoneRequirement := v1.NodeSelectorRequirement{Key: "topology.kubernetes.io/zone", Operator: v1.NodeSelectorOpIn, Values: []string{"us-east-1a", "us-east-1b"}}
+		Expect(pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(Equal([]v1.NodeSelectorTerm{
+			{MatchExpressions: []v1.NodeSelectorRequirement{hostnameRequirement, regionRequirement, zoneRequirement}},
+			{MatchExpressions: []v1.NodeSelectorRequirement{archRequirement, regionRequirement, zoneRequirement}},
+		}))
+
+		By("Not changing the node placement of the CDI CR")
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "cdi"}, cr)).To(Succeed())
+		Expect(cr.Spec.Workloads.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions).To(HaveLen(1))
+	})
+
+	It("Should pin the POD to the topology of the PVC without node placement", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{cc.AnnEndpoint: testEndPoint, cc.AnnImportPod: "importer-testPvc1", cc.AnnTopology: "topology.kubernetes.io/zone!=us-east-1c"}, nil)
+		pvc.Status.Phase = v1.ClaimPending
+		reconciler = createImportReconciler(pvc)
+
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		pod := &corev1.Pod{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, pod)).To(Succeed())
+		Expect(pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(Equal([]v1.NodeSelectorTerm{{
+			MatchExpressions: []v1.NodeSelectorRequirement{{Key: "topology.kubernetes.io/zone", Operator: v1.NodeSelectorOpNotIn, Values: []string{"us-east-1c"}}},
+		}}))
+	})
+
+	It("Should create a POD with the worker pod placement of the PVC merged with the node placement", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:           testEndPoint,
+			cc.AnnImportPod:          "importer-testPvc1",
+			cc.AnnTopology:           "topology.kubernetes.io/zone=us-east-1a",
+			cc.AnnWorkerPodPlacement: `{"nodeSelector":{"disktype":"ssd","kubernetes.io/arch":"arm64"},"tolerations":[{"key":"storage","operator":"Exists","effect":"NoSchedule"},{"key":"test","value":"123"}]}`,
+		}, nil)
+		pvc.Status.Phase = v1.ClaimPending
+		reconciler = createImportReconciler(pvc)
+
+		cr := &cdiv1.CDI{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "cdi"}, cr)).To(Succeed())
+		cr.Spec.Workloads.NodeSelector = map[string]string{"kubernetes.io/arch": "amd64"}
+		cr.Spec.Workloads.Tolerations = []v1.Toleration{{Key: "test", Value: "123"}}
+		Expect(reconciler.client.Update(context.TODO(), cr)).To(Succeed())
+		cdiConfig := &cdiv1.CDIConfig{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+		cdiConfig.Spec.AllowedWorkerPodPlacement = &cdiv1.WorkerPodPlacement{
+			NodeSelector: map[string]string{"disktype": "ssd", "kubernetes.io/arch": "arm64"},
+			Tolerations: []v1.Toleration{
+				{Key: "storage", Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule},
+				{Key: "test", Operator: v1.TolerationOpEqual, Value: "123"},
+			},
+		}
+		Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		pod := &corev1.Pod{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, pod)).To(Succeed())
+		Expect(pod.Spec.NodeSelector).To(Equal(map[string]string{"kubernetes.io/arch": "amd64", "disktype": "ssd"}))
+		Expect(pod.Spec.Tolerations).To(Equal([]v1.Toleration{
+			{Key: "test", Value: "123"},
+			{Key: "storage", Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule},
+		}))
+		Expect(pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(Equal([]v1.NodeSelectorTerm{{
+			MatchExpressions: []v1.NodeSelectorRequirement{{Key: "topology.kubernetes.io/zone", Operator: v1.NodeSelectorOpIn, Values: []string{"us-east-1a"}}},
+		}}))
+	})
+
+	It("Should create a POD with the worker pod placement of the CDIConfig when the PVC does not set it", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:           testEndPoint,
+			cc.AnnImportPod:          "importer-testPvc1",
+			cc.AnnWorkerPodPlacement: `{"nodeSelector":{"disktype":"ssd"}}`,
+		}, nil)
+		pvc.Status.Phase = v1.ClaimBound
+		reconciler = createImportReconciler(pvc)
+
+		cdiConfig := &cdiv1.CDIConfig{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+		cdiConfig.Spec.WorkerPodPlacement = &cdiv1.WorkerPodPlacement{
+			NodeSelector: map[string]string{"disktype": "hdd"},
+			Tolerations:  []v1.Toleration{{Key: "storage", Operator: v1.TolerationOpExists}},
+		}
+		cdiConfig.Spec.AllowedWorkerPodPlacement = &cdiv1.WorkerPodPlacement{NodeSelector: map[string]string{"disktype": "ssd"}}
+		Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		pod := &corev1.Pod{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, pod)).To(Succeed())
+		Expect(pod.Spec.NodeSelector).To(Equal(map[string]string{"disktype": "ssd"}))
+		Expect(pod.Spec.Tolerations).To(Equal([]v1.Toleration{{Key: "storage", Operator: v1.TolerationOpExists}}))
+	})
+
+	table.DescribeTable("Should not create a POD with a worker pod placement of the PVC not allowed by the CDIConfig", func(placement string, allowed *cdiv1.WorkerPodPlacement, message string) {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:           testEndPoint,
+			cc.AnnImportPod:          "importer-testPvc1",
+			cc.AnnWorkerPodPlacement: placement,
+		}, nil)
+		pvc.Status.Phase = v1.ClaimBound
+		reconciler = createImportReconciler(pvc)
+
+		cdiConfig := &cdiv1.CDIConfig{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+		cdiConfig.Spec.AllowedWorkerPodPlacement = allowed
+		Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(message))
+		pod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, pod)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	},
+		table.Entry("without an allowed placement", `{"tolerations":[{"key":"storage","operator":"Exists"}]}`, nil,
+			"does not allow DataVolumes to set a worker pod placement"),
+		table.Entry("with another node selector value", `{"nodeSelector":{"disktype":"ssd"}}`,
+			&cdiv1.WorkerPodPlacement{NodeSelector: map[string]string{"disktype": "hdd"}}, "node selector label disktype=ssd is not allowed"),
+		table.Entry("with a wider toleration", `{"tolerations":[{"operator":"Exists"}]}`,
+			&cdiv1.WorkerPodPlacement{Tolerations: []v1.Toleration{{Key: "storage", Operator: v1.TolerationOpExists}}}, "is not allowed by the CDIConfig"),
+		table.Entry("with an invalid toleration", `{"tolerations":[{"operator":"Equal"}]}`,
+			&cdiv1.WorkerPodPlacement{Tolerations: []v1.Toleration{{Operator: v1.TolerationOpEqual}}}, "the operator must be Exists when the key is empty"),
+	)
+
+	It("Should create a POD with the worker pod image of the PVC", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:       testEndPoint,
+			cc.AnnImportPod:      "importer-testPvc1",
+			cc.AnnWorkerPodImage: `{"registry":"mirror.example.com:5000/kubevirt","pullPolicy":"IfNotPresent"}`,
+		}, nil)
+		pvc.Status.Phase = v1.ClaimBound
+		reconciler = createImportReconciler(pvc)
+		reconciler.image = "quay.io/kubevirt/cdi-importer:v1.57.0"
+		reconciler.pullPolicy = testPullPolicy
+
+		cdiConfig := &cdiv1.CDIConfig{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+		cdiConfig.Spec.AllowedWorkerPodRegistries = []string{"mirror.example.com:5000/kubevirt/"}
+		Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		pod := &corev1.Pod{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, pod)).To(Succeed())
+		Expect(pod.Spec.Containers[0].Image).To(Equal("mirror.example.com:5000/kubevirt/cdi-importer:v1.57.0"))
+		Expect(pod.Spec.Containers[0].ImagePullPolicy).To(Equal(v1.PullIfNotPresent))
+	})
+
+	It("Should create a POD with the worker pod image of the CDIConfig when the PVC does not set it", func() {
+		digest := "sha256:" + strings.Repeat("a", 64)
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:       testEndPoint,
+			cc.AnnImportPod:      "importer-testPvc1",
+			cc.AnnWorkerPodImage: `{"pullPolicy":"Never"}`,
+		}, nil)
+		pvc.Status.Phase = v1.ClaimBound
+		reconciler = createImportReconciler(pvc)
+		reconciler.image = "quay.io/kubevirt/cdi-importer@" + digest
+		reconciler.pullPolicy = testPullPolicy
+
+		cdiConfig := &cdiv1.CDIConfig{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+		cdiConfig.Spec.WorkerPodImage = &cdiv1.WorkerPodImage{Registry: "mirror.example.com", PullPolicy: v1.PullIfNotPresent}
+		Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		pod := &corev1.Pod{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, pod)).To(Succeed())
+		Expect(pod.Spec.Containers[0].Image).To(Equal("mirror.example.com/cdi-importer@" + digest))
+		Expect(pod.Spec.Containers[0].ImagePullPolicy).To(Equal(v1.PullNever))
+	})
+
+	It("Should not create a POD with a worker pod image registry of the PVC not allowed by the CDIConfig", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:       testEndPoint,
+			cc.AnnImportPod:      "importer-testPvc1",
+			cc.AnnWorkerPodImage: `{"registry":"attacker.example.com/kubevirt"}`,
+		}, nil)
+		pvc.Status.Phase = v1.ClaimBound
+		reconciler = createImportReconciler(pvc)
+		reconciler.image = "quay.io/kubevirt/cdi-importer:v1.57.0"
+
+		cdiConfig := &cdiv1.CDIConfig{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+		cdiConfig.Spec.AllowedWorkerPodRegistries = []string{"mirror.example.com/kubevirt"}
+		Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`image registry "attacker.example.com/kubevirt" is not allowed by the CDIConfig`))
+		pod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, pod)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Should not create a POD with an invalid worker pod image registry", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:  testEndPoint,
+			cc.AnnImportPod: "importer-testPvc1",
+		}, nil)
+		pvc.Status.Phase = v1.ClaimBound
+		reconciler = createImportReconciler(pvc)
+		reconciler.image = "quay.io/kubevirt/cdi-importer:v1.57.0"
+
+		cdiConfig := &cdiv1.CDIConfig{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+		cdiConfig.Spec.WorkerPodImage = &cdiv1.WorkerPodImage{Registry: "Mirror.example.com/KubeVirt"}
+		Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`invalid image registry "Mirror.example.com/KubeVirt"`))
+	})
+
 	It("Should create a POD if a PVC with all needed annotations is passed", func() {
 		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{cc.AnnEndpoint: testEndPoint, cc.AnnImportPod: "importer-testPvc1", cc.AnnPodNetwork: "net1"}, nil)
 		pvc.Status.Phase = v1.ClaimBound
@@ -339,6 +651,32 @@ var _ = Describe("ImportConfig Controller reconcile loop", func() {
 		Expect(pod.GetAnnotations()[cc.AnnPodSidecarInjection]).To(Equal(cc.AnnPodSidecarInjectionDefault))
 	})
 
+	It("Should delete the POD and not recreate it once the DataVolume failed its completion timeout", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{cc.AnnEndpoint: testEndPoint, cc.AnnImportPod: "importer-testPvc1"}, nil)
+		pvc.Status.Phase = v1.ClaimBound
+		reconciler = createImportReconciler(pvc)
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}}
+		_, err := reconciler.Reconcile(context.TODO(), req)
+		Expect(err).ToNot(HaveOccurred())
+		pod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, pod)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(reconciler.client.Get(context.TODO(), req.NamespacedName, pvc)).To(Succeed())
+		pvc.Annotations[cc.AnnCompletionTimedOut] = "true"
+		Expect(reconciler.client.Update(context.TODO(), pvc)).To(Succeed())
+		_, err = reconciler.Reconcile(context.TODO(), req)
+		Expect(err).ToNot(HaveOccurred())
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, pod)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		By("Verifying the importer pod is not recreated")
+		_, err = reconciler.Reconcile(context.TODO(), req)
+		Expect(err).ToNot(HaveOccurred())
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, pod)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
 	It("Should not pass non-approved PVC annotation to created POD", func() {
 		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{cc.AnnEndpoint: testEndPoint, cc.AnnImportPod: "importer-testPvc1", "annot1": "value1"}, nil)
 		pvc.Status.Phase = v1.ClaimBound
@@ -506,6 +844,54 @@ var _ = Describe("Update PVC from POD", func() {
 
 	})
 
+	table.DescribeTable("Should reflect the scratch PVC phase in the bound condition, if pod is pending", func(scratchPhase corev1.PersistentVolumeClaimPhase, expectedStatus, expectedReason string) {
+		pvc := cc.CreatePvcInStorageClass("testPvc1", "default", &testStorageClass, map[string]string{cc.AnnEndpoint: testEndPoint, cc.AnnPodPhase: string(corev1.PodPending), cc.AnnRequiresScratch: "false"}, nil, corev1.ClaimBound)
+		scratchPvc := cc.CreatePvcInStorageClass("testPvc1-scratch", "default", &testStorageClass, nil, nil, scratchPhase)
+		pod := cc.CreateImporterTestPod(pvc, "testPvc1", scratchPvc)
+		pod.Status = corev1.PodStatus{
+			Phase: corev1.PodPending,
+		}
+		reconciler = createImportReconciler(pvc, pod, scratchPvc)
+		err := reconciler.updatePvcFromPod(pvc, pod, reconciler.log)
+		Expect(err).ToNot(HaveOccurred())
+
+		resPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, resPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resPvc.GetAnnotations()[cc.AnnBoundCondition]).To(Equal(expectedStatus))
+		Expect(resPvc.GetAnnotations()[cc.AnnBoundConditionReason]).To(Equal(expectedReason))
+	},
+		table.Entry("waiting for scratch space", corev1.ClaimPending, "false", cc.ScratchSpacePending),
+		table.Entry("scratch space lost", corev1.ClaimLost, "false", cc.ScratchSpaceLost),
+		table.Entry("scratch space bound", corev1.ClaimBound, "true", ""),
+	)
+
+	It("Should clear the scratch bound condition, once the pod is running", func() {
+		pvc := cc.CreatePvcInStorageClass("testPvc1", "default", &testStorageClass, map[string]string{
+			cc.AnnEndpoint:              testEndPoint,
+			cc.AnnPodPhase:              string(corev1.PodPending),
+			cc.AnnRequiresScratch:       "false",
+			cc.AnnBoundCondition:        "false",
+			cc.AnnBoundConditionMessage: "Waiting for scratch space PVC testPvc1-scratch to be bound",
+			cc.AnnBoundConditionReason:  cc.ScratchSpacePending,
+		}, nil, corev1.ClaimBound)
+		scratchPvc := cc.CreatePvcInStorageClass("testPvc1-scratch", "default", &testStorageClass, nil, nil, corev1.ClaimBound)
+		pod := cc.CreateImporterTestPod(pvc, "testPvc1", scratchPvc)
+		pod.Status = corev1.PodStatus{
+			Phase: corev1.PodRunning,
+		}
+		reconciler = createImportReconciler(pvc, pod, scratchPvc)
+		err := reconciler.updatePvcFromPod(pvc, pod, reconciler.log)
+		Expect(err).ToNot(HaveOccurred())
+
+		resPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, resPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resPvc.GetAnnotations()[cc.AnnPodPhase]).To(BeEquivalentTo(corev1.PodRunning))
+		Expect(resPvc.GetAnnotations()).ToNot(HaveKey(cc.AnnBoundCondition))
+		Expect(resPvc.GetAnnotations()).ToNot(HaveKey(cc.AnnBoundConditionReason))
+	})
+
 	// TODO: Update me to stay in progress if we were in progress already, its a pod failure and it will get restarted.
 	It("Should update phase on PVC, if pod exited with error state that is NOT scratchspace exit", func() {
 		pvc := cc.CreatePvcInStorageClass("testPvc1", "default", &testStorageClass, map[string]string{cc.AnnEndpoint: testEndPoint, cc.AnnPodPhase: string(corev1.PodRunning)}, nil, corev1.ClaimBound)
@@ -550,6 +936,117 @@ var _ = Describe("Update PVC from POD", func() {
 		Expect(resPvc.GetAnnotations()[cc.AnnRunningConditionReason]).To(Equal("Explosion"))
 	})
 
+	It("Should report an S3 KMS access denied failure with its own reason", func() {
+		message := "Unable to connect to s3 data source: " + common.S3KMSAccessDeniedMessage + ", the credentials need the kms:Decrypt permission on the KMS key of the object"
+		pvc := cc.CreatePvcInStorageClass("testPvc1", "default", &testStorageClass, map[string]string{cc.AnnEndpoint: testEndPoint, cc.AnnPodPhase: string(corev1.PodRunning)}, nil, corev1.ClaimBound)
+		pod := cc.CreateImporterTestPod(pvc, "testPvc1", nil)
+		terminated := &corev1.ContainerStateTerminated{ExitCode: 1, Message: message, Reason: "Error"}
+		pod.Status = corev1.PodStatus{
+			Phase: corev1.PodFailed,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					State:                v1.ContainerState{Terminated: terminated},
+					LastTerminationState: corev1.ContainerState{Terminated: terminated},
+				},
+			},
+		}
+		reconciler = createImportReconciler(pvc, pod)
+		err := reconciler.updatePvcFromPod(pvc, pod, reconciler.log)
+		Expect(err).ToNot(HaveOccurred())
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(Equal(fmt.Sprintf("%s %s %s", corev1.EventTypeWarning, S3KMSAccessDenied, message)))
+		resPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, resPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resPvc.GetAnnotations()[cc.AnnRunningConditionReason]).To(Equal(S3KMSAccessDenied))
+	})
+
+	table.DescribeTable("Should limit the failed import attempts", func(annotations map[string]string, configMaxAttempts *int32, restarts int32, expectExhausted bool) {
+		annotations[cc.AnnEndpoint] = testEndPoint
+		annotations[cc.AnnPodPhase] = string(corev1.PodRunning)
+		pvc := cc.CreatePvcInStorageClass("testPvc1", "default", &testStorageClass, annotations, nil, corev1.ClaimBound)
+		pod := cc.CreateImporterTestPod(pvc, "testPvc1", nil)
+		pod.Status = corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					RestartCount: restarts,
+					State: v1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+					},
+					LastTerminationState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: 1,
+							Message:  "Unable to connect to http data source",
+							Reason:   "Error",
+						},
+					},
+				},
+			},
+		}
+		reconciler = createImportReconciler(pvc, pod)
+		reconciler.recorder = record.NewFakeRecorder(10)
+		if configMaxAttempts != nil {
+			cdiConfig := &cdiv1.CDIConfig{}
+			Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+			cdiConfig.Spec.ImportMaxAttempts = configMaxAttempts
+			Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+		}
+		err := reconciler.updatePvcFromPod(pvc, pod, reconciler.log)
+		Expect(err).ToNot(HaveOccurred())
+
+		resPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, resPvc)
+		Expect(err).ToNot(HaveOccurred())
+		resPod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace}, resPod)
+		if !expectExhausted {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resPvc.GetAnnotations()).ToNot(HaveKey(cc.AnnImportAttemptsExhausted))
+			Expect(resPvc.GetAnnotations()[cc.AnnPodPhase]).To(BeEquivalentTo(corev1.PodRunning))
+			return
+		}
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		Expect(resPvc.GetAnnotations()[cc.AnnImportAttemptsExhausted]).To(Equal("true"))
+		Expect(resPvc.GetAnnotations()[cc.AnnPodPhase]).To(BeEquivalentTo(corev1.PodFailed))
+		Expect(resPvc.GetAnnotations()[cc.AnnRunningCondition]).To(Equal("false"))
+		Expect(resPvc.GetAnnotations()[cc.AnnRunningConditionReason]).To(Equal(ImportAttemptsExhausted))
+		Expect(resPvc.GetAnnotations()[cc.AnnRunningConditionMessage]).To(Equal(
+			fmt.Sprintf(MessageImportAttemptsExhausted, 3, "Unable to connect to http data source")))
+
+		By("Verifying the importer pod is not recreated")
+		_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace}, resPod)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	},
+		table.Entry("not when unset", map[string]string{}, nil, int32(10), false),
+		table.Entry("not below the annotation cap", map[string]string{cc.AnnImportMaxAttempts: "3"}, nil, int32(2), false),
+		table.Entry("when reaching the annotation cap", map[string]string{cc.AnnImportMaxAttempts: "3"}, nil, int32(3), true),
+		table.Entry("when reaching the CDIConfig cap", map[string]string{}, pointer.Int32(3), int32(3), true),
+		table.Entry("with the annotation overriding the CDIConfig cap", map[string]string{cc.AnnImportMaxAttempts: "5"}, pointer.Int32(3), int32(3), false),
+		table.Entry("not counting the attempts before the last progress", map[string]string{cc.AnnImportMaxAttempts: "3", cc.AnnImportAttemptsBase: "2"}, nil, int32(4), false),
+		table.Entry("counting the attempts after the last progress", map[string]string{cc.AnnImportMaxAttempts: "3", cc.AnnImportAttemptsBase: "2"}, nil, int32(5), true),
+	)
+
+	It("Should reset the attempts base when creating a new importer pod", func() {
+		pvc := cc.CreatePvcInStorageClass("testPvc1", "default", &testStorageClass, map[string]string{
+			cc.AnnEndpoint:           testEndPoint,
+			cc.AnnImportPod:          "importer-testPvc1",
+			cc.AnnImportAttemptsBase: "2",
+		}, nil, corev1.ClaimBound)
+		reconciler = createImportReconciler(pvc)
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		resPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, resPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resPvc.GetAnnotations()).ToNot(HaveKey(cc.AnnImportAttemptsBase))
+		pod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, pod)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
 	It("Should NOT update phase on PVC, if pod exited with error state that is scratchspace exit", func() {
 		pvc := cc.CreatePvcInStorageClass("testPvc1", "default", &testStorageClass, map[string]string{cc.AnnEndpoint: testEndPoint, cc.AnnPodPhase: string(corev1.PodRunning)}, nil, corev1.ClaimBound)
 		scratchPvcName := &corev1.PersistentVolumeClaim{}
@@ -792,14 +1289,711 @@ var _ = Describe("Create Importer Pod", func() {
 		table.Entry("should create pod with block volume mode and scratchspace", createBlockPvc("testBlockPvc1", "default", map[string]string{cc.AnnEndpoint: testEndPoint, cc.AnnPodPhase: string(corev1.PodPending), cc.AnnImportPod: "podName", cc.AnnPriorityClassName: "p0"}, nil), &scratchPvcName),
 	)
 
-	table.DescribeTable("should append current checkpoint name to importer pod", func(pvcName, checkpointID string) {
-		pvc := cc.CreatePvc(pvcName, "default", map[string]string{cc.AnnCurrentCheckpoint: checkpointID, cc.AnnEndpoint: testEndPoint}, nil)
-		pvc.Status.Phase = v1.ClaimBound
+	It("should pass the IO limits of the CDIConfig to the importer pod", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{cc.AnnEndpoint: testEndPoint, cc.AnnImportPod: "podName"}, nil)
+		reconciler := createImportReconciler(pvc)
+		cdiConfig := &cdiv1.CDIConfig{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+		maxBytesPerSecond := resource.MustParse("100Mi")
+		cdiConfig.Spec.PodIOLimits = &cdiv1.PodIOLimits{
+			MaxBytesPerSecond: &maxBytesPerSecond,
+			MaxIOPS:           pointer.Int64(500),
+			Weight:            pointer.Int32(200),
+			BlockIOClass:      pointer.String("throttled"),
+		}
+		Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
 
-		suffix := fmt.Sprintf("%s-checkpoint-%s", pvcName, checkpointID)
-		expectedName := fmt.Sprintf("importer-%s", suffix)
-		if len(expectedName) > kvalidation.DNS1123SubdomainMaxLength {
-			expectedName = naming.GetResourceName("importer", suffix)
+		podArgs := &importerPodArgs{
+			image:      testImage,
+			verbose:    "5",
+			pullPolicy: testPullPolicy,
+			podEnvVar:  &importPodEnvVar{imageSize: "1G", filesystemOverhead: "0.055"},
+			pvc:        pvc,
+		}
+		pod, err := createImporterPod(reconciler.log, reconciler.client, podArgs, map[string]string{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].Env).To(ContainElements(
+			corev1.EnvVar{Name: common.IOMaxBytesPerSecond, Value: "104857600"},
+			corev1.EnvVar{Name: common.IOMaxIOPS, Value: "500"},
+			corev1.EnvVar{Name: common.IOWeight, Value: "200"},
+		))
+		Expect(pod.Annotations).To(HaveKeyWithValue(cgroup.BlockIOClassAnnotation, "throttled"))
+	})
+
+	It("should not pass IO limits to the importer pod by default", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{cc.AnnEndpoint: testEndPoint, cc.AnnImportPod: "podName"}, nil)
+		reconciler := createImportReconciler(pvc)
+		podArgs := &importerPodArgs{
+			image:      testImage,
+			verbose:    "5",
+			pullPolicy: testPullPolicy,
+			podEnvVar:  &importPodEnvVar{imageSize: "1G", filesystemOverhead: "0.055"},
+			pvc:        pvc,
+		}
+		pod, err := createImporterPod(reconciler.log, reconciler.client, podArgs, map[string]string{})
+		Expect(err).ToNot(HaveOccurred())
+		for _, env := range pod.Spec.Containers[0].Env {
+			Expect(env.Name).ToNot(BeElementOf(common.IOMaxBytesPerSecond, common.IOMaxIOPS, common.IOWeight))
+		}
+		Expect(pod.Annotations).ToNot(HaveKey(cgroup.BlockIOClassAnnotation))
+	})
+
+	It("should project the service account token in the importer pod for token credentials", func() {
+		audience := "//iam.googleapis.com/projects/1/locations/global/workloadIdentityPools/pool/providers/provider"
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:            "gs://bucket/disk.img",
+			cc.AnnSource:              cc.SourceGCS,
+			cc.AnnImportPod:           "podName",
+			cc.AnnTokenAudience:       audience,
+			cc.AnnTokenServiceAccount: "importer@project.iam.gserviceaccount.com",
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		podArgs := &importerPodArgs{
+			image:      testImage,
+			verbose:    "5",
+			pullPolicy: testPullPolicy,
+			podEnvVar:  podEnvVar,
+			pvc:        pvc,
+		}
+		pod, err := createImporterPod(reconciler.log, reconciler.client, podArgs, map[string]string{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].Env).To(ContainElements(
+			corev1.EnvVar{Name: common.ImporterTokenFile, Value: common.ImporterTokenPath},
+			corev1.EnvVar{Name: common.ImporterTokenAudience, Value: audience},
+			corev1.EnvVar{Name: common.ImporterTokenServiceAccount, Value: "importer@project.iam.gserviceaccount.com"},
+		))
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      tokenVolumeName,
+			MountPath: common.ImporterTokenDir,
+			ReadOnly:  true,
+		}))
+		var projection *corev1.ServiceAccountTokenProjection
+		for _, volume := range pod.Spec.Volumes {
+			if volume.Name == tokenVolumeName {
+				projection = volume.Projected.Sources[0].ServiceAccountToken
+			}
+		}
+		Expect(projection).ToNot(BeNil())
+		Expect(projection.Audience).To(Equal("https:" + audience))
+		Expect(projection.Path).To(Equal("token"))
+		Expect(*projection.ExpirationSeconds).To(Equal(tokenExpirationSeconds))
+	})
+
+	It("should mount the passphrase of a LUKS target in the importer pod", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:         testEndPoint,
+			cc.AnnSource:           cc.SourceHTTP,
+			cc.AnnImportPod:        "podName",
+			cc.AnnEncryptionSecret: "disk-key",
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		podArgs := &importerPodArgs{
+			image:      testImage,
+			verbose:    "5",
+			pullPolicy: testPullPolicy,
+			podEnvVar:  podEnvVar,
+			pvc:        pvc,
+		}
+		pod, err := createImporterPod(reconciler.log, reconciler.client, podArgs, map[string]string{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: common.ImporterEncryptionKeyFile, Value: "/encryption-key/passphrase"}))
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      EncryptionKeyVolName,
+			MountPath: common.ImporterEncryptionKeyDir,
+			ReadOnly:  true,
+		}))
+		var secretVolume *corev1.SecretVolumeSource
+		for _, volume := range pod.Spec.Volumes {
+			if volume.Name == EncryptionKeyVolName {
+				secretVolume = volume.Secret
+			}
+		}
+		Expect(secretVolume.SecretName).To(Equal("disk-key"))
+		Expect(secretVolume.Items).To(Equal([]corev1.KeyToPath{{Key: "passphrase", Path: "passphrase"}}))
+	})
+
+	It("should mount the ConfigMap holding the image of an inline source in the importer pod", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:  "testPvc1-inline-source",
+			cc.AnnSource:    cc.SourceInline,
+			cc.AnnImportPod: "podName",
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		podArgs := &importerPodArgs{
+			image:      testImage,
+			verbose:    "5",
+			pullPolicy: testPullPolicy,
+			podEnvVar:  podEnvVar,
+			pvc:        pvc,
+		}
+		pod, err := createImporterPod(reconciler.log, reconciler.client, podArgs, map[string]string{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      InlineSourceVolName,
+			MountPath: common.ImporterInlineSourceDir,
+			ReadOnly:  true,
+		}))
+		Expect(pod.Spec.Volumes).To(ContainElement(createConfigMapVolume(InlineSourceVolName, "testPvc1-inline-source")))
+	})
+
+	It("should mount the secret holding the TLS credentials of an NBD source in the importer pod", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:  "nbds://nbd.example.com/disk0",
+			cc.AnnSource:    cc.SourceNBD,
+			cc.AnnImportPod: "podName",
+			cc.AnnSecret:    "nbd-tls",
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		podArgs := &importerPodArgs{
+			image:      testImage,
+			verbose:    "5",
+			pullPolicy: testPullPolicy,
+			podEnvVar:  podEnvVar,
+			pvc:        pvc,
+		}
+		pod, err := createImporterPod(reconciler.log, reconciler.client, podArgs, map[string]string{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      SecretVolName,
+			MountPath: common.ImporterNbdCertDir,
+			ReadOnly:  true,
+		}))
+		Expect(pod.Spec.Volumes).To(ContainElement(createSecretVolume(SecretVolName, "nbd-tls")))
+		for _, env := range pod.Spec.Containers[0].Env {
+			Expect(env.Name).ToNot(BeElementOf(common.ImporterAccessKeyID, common.ImporterSecretKey))
+		}
+	})
+
+	It("should mount the secret holding the credentials of a registry source in the importer pod", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:  "docker://registry.example.com/fedora:latest",
+			cc.AnnSource:    cc.SourceRegistry,
+			cc.AnnImportPod: "podName",
+			cc.AnnSecret:    "registry-login",
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		podArgs := &importerPodArgs{
+			image:      testImage,
+			verbose:    "5",
+			pullPolicy: testPullPolicy,
+			podEnvVar:  podEnvVar,
+			pvc:        pvc,
+		}
+		pod, err := createImporterPod(reconciler.log, reconciler.client, podArgs, map[string]string{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      SecretVolName,
+			MountPath: common.ImporterRegistryCredentialDir,
+			ReadOnly:  true,
+		}))
+		Expect(pod.Spec.Volumes).To(ContainElement(createSecretVolume(SecretVolName, "registry-login")))
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+			Name:  common.ImporterRegistryCredentialDirVar,
+			Value: common.ImporterRegistryCredentialDir,
+		}))
+		// The credentials of the environment are kept when the secret cannot be read
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+			Name: common.ImporterAccessKeyID,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "registry-login"},
+					Key:                  common.KeyAccess,
+				},
+			},
+		}))
+	})
+
+	It("should pass the token of a remote clone source to the importer pod", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:  "https://hub.example.com/volumes/golden/disk.img",
+			cc.AnnSource:    cc.SourceRemote,
+			cc.AnnImportPod: "podName",
+			cc.AnnSecret:    "hub-token",
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		podArgs := &importerPodArgs{
+			image:      testImage,
+			verbose:    "5",
+			pullPolicy: testPullPolicy,
+			podEnvVar:  podEnvVar,
+			pvc:        pvc,
+		}
+		pod, err := createImporterPod(reconciler.log, reconciler.client, podArgs, map[string]string{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: common.ImporterSource, Value: cc.SourceRemote}))
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: common.ImporterEndpoint, Value: "https://hub.example.com/volumes/golden/disk.img"}))
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+			Name: common.ImporterBearerToken,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "hub-token"},
+					Key:                  common.KeyToken,
+				},
+			},
+		}))
+		for _, env := range pod.Spec.Containers[0].Env {
+			Expect(env.Name).ToNot(BeElementOf(common.ImporterAccessKeyID, common.ImporterSecretKey))
+		}
+	})
+
+	It("should mount the secret holding the credentials of an Azure Blob source in the importer pod", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:  "https://account.blob.core.windows.net/vhds/disk.vhd",
+			cc.AnnSource:    cc.SourceAzure,
+			cc.AnnImportPod: "podName",
+			cc.AnnSecret:    "azure-sas",
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		podArgs := &importerPodArgs{
+			image:      testImage,
+			verbose:    "5",
+			pullPolicy: testPullPolicy,
+			podEnvVar:  podEnvVar,
+			pvc:        pvc,
+		}
+		pod, err := createImporterPod(reconciler.log, reconciler.client, podArgs, map[string]string{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: common.ImporterSource, Value: cc.SourceAzure}))
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      SecretVolName,
+			MountPath: common.ImporterAzureCredentialDir,
+			ReadOnly:  true,
+		}))
+		Expect(pod.Spec.Volumes).To(ContainElement(createSecretVolume(SecretVolName, "azure-sas")))
+		for _, env := range pod.Spec.Containers[0].Env {
+			Expect(env.Name).ToNot(BeElementOf(common.ImporterAccessKeyID, common.ImporterSecretKey))
+		}
+	})
+
+	It("should mount the secret of an SFTP source and pass its host key to the importer pod", func() {
+		hostKey := "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAINCPHZdZnwHfv3CEN/CuyHpmGyxoaVzywfYr0HJJTVdz"
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:    "sftp://sftp.example.com/images/disk.qcow2",
+			cc.AnnSource:      cc.SourceSFTP,
+			cc.AnnImportPod:   "podName",
+			cc.AnnSecret:      "sftp-creds",
+			cc.AnnSFTPHostKey: hostKey,
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		podArgs := &importerPodArgs{
+			image:      testImage,
+			verbose:    "5",
+			pullPolicy: testPullPolicy,
+			podEnvVar:  podEnvVar,
+			pvc:        pvc,
+		}
+		pod, err := createImporterPod(reconciler.log, reconciler.client, podArgs, map[string]string{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: common.ImporterSource, Value: cc.SourceSFTP}))
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: common.ImporterSFTPHostKey, Value: hostKey}))
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      SecretVolName,
+			MountPath: common.ImporterSFTPCredentialDir,
+			ReadOnly:  true,
+		}))
+		Expect(pod.Spec.Volumes).To(ContainElement(createSecretVolume(SecretVolName, "sftp-creds")))
+		for _, env := range pod.Spec.Containers[0].Env {
+			Expect(env.Name).ToNot(BeElementOf(common.ImporterAccessKeyID, common.ImporterSecretKey))
+		}
+	})
+
+	It("should pass the login of an FTP source to the importer pod", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:  "ftp://ftp.example.com/images/disk.qcow2",
+			cc.AnnSource:    cc.SourceFTP,
+			cc.AnnImportPod: "podName",
+			cc.AnnSecret:    "ftp-login",
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		podArgs := &importerPodArgs{
+			image:      testImage,
+			verbose:    "5",
+			pullPolicy: testPullPolicy,
+			podEnvVar:  podEnvVar,
+			pvc:        pvc,
+		}
+		pod, err := createImporterPod(reconciler.log, reconciler.client, podArgs, map[string]string{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: common.ImporterSource, Value: cc.SourceFTP}))
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+			Name: common.ImporterAccessKeyID,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "ftp-login"},
+					Key:                  common.KeyAccess,
+				},
+			},
+		}))
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+			Name: common.ImporterSecretKey,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "ftp-login"},
+					Key:                  common.KeySecret,
+				},
+			},
+		}))
+	})
+
+	It("should pass the KMS key id of an S3 object to the importer pod", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:   testEndPoint,
+			cc.AnnSource:     cc.SourceS3,
+			cc.AnnImportPod:  "podName",
+			cc.AnnS3KMSKeyID: "1234abcd-12ab-34cd-56ef-1234567890ab",
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(makeImportEnv(podEnvVar, "1111-1111-1111-1111")).To(ContainElement(corev1.EnvVar{Name: common.ImporterS3KMSKeyID, Value: "1234abcd-12ab-34cd-56ef-1234567890ab"}))
+	})
+
+	It("should pass the user project of a requester-pays GCS bucket to the importer pod", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:       "gs://bucket/disk.img",
+			cc.AnnSource:         cc.SourceGCS,
+			cc.AnnImportPod:      "podName",
+			cc.AnnGcsUserProject: "billing-project",
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(makeImportEnv(podEnvVar, "1111-1111-1111-1111")).To(ContainElement(corev1.EnvVar{Name: common.ImporterGcsUserProject, Value: "billing-project"}))
+	})
+
+	It("should pass the pinned generation of the GCS object to the importer pod", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:      "gs://bucket/disk.img",
+			cc.AnnSource:        cc.SourceGCS,
+			cc.AnnImportPod:     "podName",
+			cc.AnnGcsGeneration: "1686562345678901",
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(makeImportEnv(podEnvVar, "1111-1111-1111-1111")).To(ContainElement(corev1.EnvVar{Name: common.ImporterGcsGeneration, Value: "1686562345678901"}))
+	})
+
+	It("should ask the importer pod to shrink the image to the used space of its filesystem", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:         testEndPoint,
+			cc.AnnImportPod:        "podName",
+			cc.AnnShrinkToUsedSize: "true",
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(makeImportEnv(podEnvVar, "1111-1111-1111-1111")).To(ContainElement(corev1.EnvVar{Name: common.ImporterShrinkToUsedSize, Value: "true"}))
+
+		delete(pvc.Annotations, cc.AnnShrinkToUsedSize)
+		podEnvVar, err = reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		for _, env := range makeImportEnv(podEnvVar, "1111-1111-1111-1111") {
+			Expect(env.Name).ToNot(Equal(common.ImporterShrinkToUsedSize))
+		}
+	})
+
+	It("should ask the importer pod to write a compressed qcow2 image", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:          testEndPoint,
+			cc.AnnImportPod:         "podName",
+			cc.AnnTargetFormat:      common.ImportTargetFormatQcow2,
+			cc.AnnTargetCompression: common.ImportTargetCompressionZstd,
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		env := makeImportEnv(podEnvVar, "1111-1111-1111-1111")
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ImporterTargetFormat, Value: "qcow2"}))
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ImporterTargetCompression, Value: "zstd"}))
+	})
+
+	It("should pass the validators of the previous import of an http source to the importer pod", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:           testEndPoint,
+			cc.AnnSource:             cc.SourceHTTP,
+			cc.AnnImportPod:          "podName",
+			cc.AnnSourceETag:         `"v1"`,
+			cc.AnnSourceLastModified: "Mon, 02 Jan 2023 15:04:05 GMT",
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		env := makeImportEnv(podEnvVar, "1111-1111-1111-1111")
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ImporterSourceETag, Value: `"v1"`}))
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ImporterSourceLastModified, Value: "Mon, 02 Jan 2023 15:04:05 GMT"}))
+
+		pvc.Annotations[cc.AnnSource] = cc.SourceRegistry
+		podEnvVar, err = reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		for _, env := range makeImportEnv(podEnvVar, "1111-1111-1111-1111") {
+			Expect(env.Name).ToNot(Equal(common.ImporterSourceETag))
+		}
+	})
+
+	It("should pass the changed ranges of an http source to the importer pod", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:         testEndPoint,
+			cc.AnnSource:           cc.SourceHTTP,
+			cc.AnnImportPod:        "podName",
+			cc.AnnChangedRangesURL: "http://example.com/ranges.json",
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		env := makeImportEnv(podEnvVar, "1111-1111-1111-1111")
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ImporterChangedRangesURL, Value: "http://example.com/ranges.json"}))
+
+		pvc.Annotations[cc.AnnSource] = cc.SourceS3
+		podEnvVar, err = reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		for _, env := range makeImportEnv(podEnvVar, "1111-1111-1111-1111") {
+			Expect(env.Name).ToNot(Equal(common.ImporterChangedRangesURL))
+		}
+	})
+
+	table.DescribeTable("should make the importer pod check an adopted PVC is empty", func(annotations map[string]string, expectCheck bool) {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:  testEndPoint,
+			cc.AnnSource:    cc.SourceHTTP,
+			cc.AnnImportPod: "podName",
+		}, nil)
+		for k, v := range annotations {
+			pvc.Annotations[k] = v
+		}
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		podArgs := &importerPodArgs{
+			image:      testImage,
+			verbose:    "5",
+			pullPolicy: testPullPolicy,
+			podEnvVar:  podEnvVar,
+			pvc:        pvc,
+		}
+		pod, err := createImporterPod(reconciler.log, reconciler.client, podArgs, map[string]string{})
+		Expect(err).ToNot(HaveOccurred())
+		checkEnv := corev1.EnvVar{Name: common.TargetMustBeEmpty, Value: "true"}
+		checkMount := corev1.VolumeMount{Name: targetCheckVolName, MountPath: common.TargetCheckDir}
+		if expectCheck {
+			Expect(pod.Spec.Containers[0].Env).To(ContainElement(checkEnv))
+			Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(checkMount))
+		} else {
+			Expect(pod.Spec.Containers[0].Env).ToNot(ContainElement(checkEnv))
+			Expect(pod.Spec.Containers[0].VolumeMounts).ToNot(ContainElement(checkMount))
+		}
+	},
+		table.Entry("when adopted", map[string]string{cc.AnnAdoptedPVC: "true"}, true),
+		table.Entry("not when created by the DataVolume", map[string]string{}, false),
+		table.Entry("not in a later stage of a multi-stage import", map[string]string{cc.AnnAdoptedPVC: "true", cc.AnnPreviousCheckpoint: "snap1"}, false),
+		table.Entry("not when refreshing the changed ranges", map[string]string{cc.AnnAdoptedPVC: "true", cc.AnnChangedRangesLastRefresh: "1"}, false),
+	)
+
+	It("should pass the checksum manifest of an http source to the importer pod", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:            testEndPoint,
+			cc.AnnSource:              cc.SourceHTTP,
+			cc.AnnImportPod:           "podName",
+			cc.AnnChecksumManifestURL: "http://example.com/checksums.json",
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		env := makeImportEnv(podEnvVar, "1111-1111-1111-1111")
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ImporterChecksumManifestURL, Value: "http://example.com/checksums.json"}))
+
+		pvc.Annotations[cc.AnnSource] = cc.SourceS3
+		podEnvVar, err = reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		for _, env := range makeImportEnv(podEnvVar, "1111-1111-1111-1111") {
+			Expect(env.Name).ToNot(Equal(common.ImporterChecksumManifestURL))
+		}
+	})
+
+	It("should ask the importer pod to compute the digest of the source", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:              testEndPoint,
+			cc.AnnSource:                cc.SourceHTTP,
+			cc.AnnImportPod:             "podName",
+			cc.AnnSourceDigestAlgorithm: common.SourceDigestSHA512,
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		env := makeImportEnv(podEnvVar, "1111-1111-1111-1111")
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ImporterSourceDigestAlgorithm, Value: "sha512"}))
+	})
+
+	It("should pass the selected member of a tar archive to the importer pod", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:  testEndPoint,
+			cc.AnnSource:    cc.SourceHTTP,
+			cc.AnnImportPod: "podName",
+			cc.AnnTarMember: "disks/disk.qcow2",
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		env := makeImportEnv(podEnvVar, "1111-1111-1111-1111")
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ImporterTarMember, Value: "disks/disk.qcow2"}))
+	})
+
+	It("should pass the free-space margin to the importer pod", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:        testEndPoint,
+			cc.AnnSource:          cc.SourceHTTP,
+			cc.AnnImportPod:       "podName",
+			cc.AnnFreeSpaceMargin: "5%",
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		env := makeImportEnv(podEnvVar, "1111-1111-1111-1111")
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ImporterFreeSpaceMargin, Value: "5%"}))
+	})
+
+	It("should pass the bound of the copy buffer to the importer pod", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:          testEndPoint,
+			cc.AnnSource:            cc.SourceHTTP,
+			cc.AnnImportPod:         "podName",
+			cc.AnnMaxCopyBufferSize: "16Mi",
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		env := makeImportEnv(podEnvVar, "1111-1111-1111-1111")
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ImporterMaxCopyBufferSize, Value: "16Mi"}))
+	})
+
+	It("should pass the disk of a multi-disk registry image to the importer pod", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:         "docker://registry:5000/appliance",
+			cc.AnnSource:           cc.SourceRegistry,
+			cc.AnnImportPod:        "podName",
+			cc.AnnRegistryDiskPath: "data.qcow2",
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		env := makeImportEnv(podEnvVar, "1111-1111-1111-1111")
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ImporterRegistryDiskPath, Value: "data.qcow2"}))
+	})
+
+	table.DescribeTable("should pass the import TLS security profile to the importer pod", func(profile *ocpconfigv1.TLSSecurityProfile) {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{cc.AnnEndpoint: testEndPoint, cc.AnnImportPod: "podName"}, nil)
+		reconciler := createImportReconciler(pvc)
+		profileType := ocpconfigv1.TLSProfileIntermediateType
+		if profile != nil {
+			profileType = profile.Type
+			cdiConfig := &cdiv1.CDIConfig{}
+			Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+			cdiConfig.Spec.ImportTLSSecurityProfile = profile
+			Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+		}
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(makeImportEnv(podEnvVar, "1111-1111-1111-1111")).To(ContainElements(
+			corev1.EnvVar{Name: common.MinVersionTLSVar, Value: string(ocpconfigv1.TLSProfiles[profileType].MinTLSVersion)},
+			corev1.EnvVar{Name: common.CiphersTLSVar, Value: strings.Join(ocpconfigv1.TLSProfiles[profileType].Ciphers, ",")},
+		))
+		Expect(reconciler.recorder.(*record.FakeRecorder).Events).To(BeEmpty())
+	},
+		table.Entry("no profile set", nil),
+		table.Entry("'Modern' profile set", &ocpconfigv1.TLSSecurityProfile{Type: ocpconfigv1.TLSProfileModernType, Modern: &ocpconfigv1.ModernTLSProfile{}}),
+	)
+
+	It("should pass the TLS settings of the DataVolume annotations and warn about the downgrade", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:            testEndPoint,
+			cc.AnnImportPod:           "podName",
+			cc.AnnImportTLSMinVersion: "VersionTLS10",
+			cc.AnnImportTLSCiphers:    "ECDHE-RSA-AES128-SHA,ECDHE-RSA-AES256-SHA",
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(makeImportEnv(podEnvVar, "1111-1111-1111-1111")).To(ContainElements(
+			corev1.EnvVar{Name: common.MinVersionTLSVar, Value: "VersionTLS10"},
+			corev1.EnvVar{Name: common.CiphersTLSVar, Value: "ECDHE-RSA-AES128-SHA,ECDHE-RSA-AES256-SHA"},
+		))
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring(corev1.EventTypeWarning))
+		Expect(event).To(ContainSubstring(ImportTLSDowngraded))
+		Expect(event).To(ContainSubstring("minimal version VersionTLS10"))
+	})
+
+	It("should not warn about DataVolume annotations strengthening the TLS settings", func() {
+		ciphers := ocpconfigv1.TLSProfiles[ocpconfigv1.TLSProfileIntermediateType].Ciphers[:2]
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:            testEndPoint,
+			cc.AnnImportPod:           "podName",
+			cc.AnnImportTLSMinVersion: "VersionTLS13",
+			cc.AnnImportTLSCiphers:    strings.Join(ciphers, ","),
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podEnvVar.tlsMinVersion).To(Equal("VersionTLS13"))
+		Expect(reconciler.recorder.(*record.FakeRecorder).Events).To(BeEmpty())
+	})
+
+	It("should fail with an invalid minimal TLS version annotation", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:            testEndPoint,
+			cc.AnnImportPod:           "podName",
+			cc.AnnImportTLSMinVersion: "TLSv1",
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		_, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(cc.AnnImportTLSMinVersion))
+	})
+
+	It("should not project a service account token in the importer pod for secret credentials", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{cc.AnnEndpoint: testEndPoint, cc.AnnSource: cc.SourceS3, cc.AnnSecret: "s3-secret", cc.AnnImportPod: "podName"}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(makeImportEnv(podEnvVar, "1111-1111-1111-1111")).ToNot(ContainElement(HaveField("Name", common.ImporterTokenFile)))
+		podArgs := &importerPodArgs{
+			image:      testImage,
+			verbose:    "5",
+			pullPolicy: testPullPolicy,
+			podEnvVar:  podEnvVar,
+			pvc:        pvc,
+		}
+		pod, err := createImporterPod(reconciler.log, reconciler.client, podArgs, map[string]string{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Volumes).ToNot(ContainElement(HaveField("Name", tokenVolumeName)))
+	})
+
+	table.DescribeTable("should append current checkpoint name to importer pod", func(pvcName, checkpointID string) {
+		pvc := cc.CreatePvc(pvcName, "default", map[string]string{cc.AnnCurrentCheckpoint: checkpointID, cc.AnnEndpoint: testEndPoint}, nil)
+		pvc.Status.Phase = v1.ClaimBound
+
+		suffix := fmt.Sprintf("%s-checkpoint-%s", pvcName, checkpointID)
+		expectedName := fmt.Sprintf("importer-%s", suffix)
+		if len(expectedName) > kvalidation.DNS1123SubdomainMaxLength {
+			expectedName = naming.GetResourceName("importer", suffix)
 		}
 
 		reconciler := createImportReconciler(pvc)
@@ -827,6 +2021,124 @@ var _ = Describe("Create Importer Pod", func() {
 	)
 })
 
+var _ = Describe("Scratch space backend", func() {
+	createScratchImporterPod := func(scratchSpace *cdiv1.ScratchSpaceConfig, annotations map[string]string) (*ImportReconciler, *corev1.Pod) {
+		annotations[cc.AnnEndpoint] = testEndPoint
+		annotations[cc.AnnImportPod] = "importer-testPvc1"
+		annotations[cc.AnnContentType] = string(cdiv1.DataVolumeArchive)
+		pvc := cc.CreatePvcInStorageClass("testPvc1", "default", &testStorageClass, annotations, nil, corev1.ClaimBound)
+		reconciler := createImportReconciler(pvc)
+		cdiConfig := &cdiv1.CDIConfig{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+		cdiConfig.Spec.ScratchSpace = scratchSpace
+		cdiConfig.Status.DefaultPodResourceRequirements = &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("60M")},
+			Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("600M")},
+		}
+		Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+
+		Expect(reconciler.createImporterPod(pvc)).To(Succeed())
+		pod := &corev1.Pod{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, pod)).To(Succeed())
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: cc.ScratchVolName, MountPath: common.ScratchDataDir}))
+		return reconciler, pod
+	}
+
+	getScratchVolume := func(pod *corev1.Pod) *corev1.Volume {
+		for _, vol := range pod.Spec.Volumes {
+			if vol.Name == cc.ScratchVolName {
+				return &vol
+			}
+		}
+		return nil
+	}
+
+	expectScratchPvc := func(reconciler *ImportReconciler, pod *corev1.Pod) {
+		vol := getScratchVolume(pod)
+		Expect(vol).ToNot(BeNil())
+		Expect(vol.PersistentVolumeClaim).ToNot(BeNil())
+		Expect(vol.PersistentVolumeClaim.ClaimName).To(Equal("testPvc1-scratch"))
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1-scratch", Namespace: "default"}, &corev1.PersistentVolumeClaim{})).To(Succeed())
+	}
+
+	expectScratchEmptyDir := func(reconciler *ImportReconciler, pod *corev1.Pod, medium corev1.StorageMedium) {
+		vol := getScratchVolume(pod)
+		Expect(vol).ToNot(BeNil())
+		Expect(vol.EmptyDir).ToNot(BeNil())
+		Expect(vol.EmptyDir.Medium).To(Equal(medium))
+		Expect(vol.EmptyDir.SizeLimit).ToNot(BeNil())
+		Expect(vol.EmptyDir.SizeLimit.Cmp(resource.MustParse("1G"))).To(BeZero())
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: common.ImporterScratchSpaceLimit, Value: "1000000000"}))
+		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1-scratch", Namespace: "default"}, &corev1.PersistentVolumeClaim{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	}
+
+	It("should back the scratch space with a PVC by default", func() {
+		reconciler, pod := createScratchImporterPod(nil, map[string]string{})
+		expectScratchPvc(reconciler, pod)
+	})
+
+	It("should back the scratch space with a disk emptyDir charged to the importer ephemeral storage", func() {
+		reconciler, pod := createScratchImporterPod(&cdiv1.ScratchSpaceConfig{Backend: cdiv1.ScratchSpaceBackendEmptyDirDisk}, map[string]string{})
+		expectScratchEmptyDir(reconciler, pod, corev1.StorageMediumDefault)
+		Expect(pod.Spec.Containers[0].Resources.Requests.StorageEphemeral().Cmp(resource.MustParse("1G"))).To(BeZero())
+		Expect(pod.Spec.Containers[0].Resources.Requests.Memory().Cmp(resource.MustParse("60M"))).To(BeZero())
+	})
+
+	It("should back the scratch space with a memory emptyDir charged to the importer memory", func() {
+		reconciler, pod := createScratchImporterPod(&cdiv1.ScratchSpaceConfig{Backend: cdiv1.ScratchSpaceBackendEmptyDirMemory}, map[string]string{})
+		expectScratchEmptyDir(reconciler, pod, corev1.StorageMediumMemory)
+		Expect(pod.Spec.Containers[0].Resources.Requests.Memory().Cmp(resource.MustParse("1060M"))).To(BeZero())
+		Expect(pod.Spec.Containers[0].Resources.Limits.Memory().Cmp(resource.MustParse("1600M"))).To(BeZero())
+	})
+
+	It("should back the scratch space with a PVC when it exceeds the default maximum memory emptyDir size", func() {
+		defaultMaxSize := cc.DefaultMaxMemoryScratchSpaceSize
+		defer func() { cc.DefaultMaxMemoryScratchSpaceSize = defaultMaxSize }()
+		cc.DefaultMaxMemoryScratchSpaceSize = resource.MustParse("500M")
+		reconciler, pod := createScratchImporterPod(&cdiv1.ScratchSpaceConfig{Backend: cdiv1.ScratchSpaceBackendEmptyDirMemory}, map[string]string{})
+		expectScratchPvc(reconciler, pod)
+		Expect(pod.Spec.Containers[0].Resources.Requests.Memory().Cmp(resource.MustParse("60M"))).To(BeZero())
+		for _, env := range pod.Spec.Containers[0].Env {
+			Expect(env.Name).ToNot(Equal(common.ImporterScratchSpaceLimit))
+		}
+	})
+
+	It("should back the scratch space with a PVC when it exceeds the maximum emptyDir size", func() {
+		maxEmptyDirSize := resource.MustParse("500M")
+		reconciler, pod := createScratchImporterPod(&cdiv1.ScratchSpaceConfig{Backend: cdiv1.ScratchSpaceBackendEmptyDirDisk, MaxEmptyDirSize: &maxEmptyDirSize}, map[string]string{})
+		expectScratchPvc(reconciler, pod)
+	})
+
+	It("should back the scratch space with an emptyDir within the maximum emptyDir size", func() {
+		maxEmptyDirSize := resource.MustParse("1G")
+		reconciler, pod := createScratchImporterPod(&cdiv1.ScratchSpaceConfig{Backend: cdiv1.ScratchSpaceBackendEmptyDirDisk, MaxEmptyDirSize: &maxEmptyDirSize}, map[string]string{})
+		expectScratchEmptyDir(reconciler, pod, corev1.StorageMediumDefault)
+	})
+
+	table.DescribeTable("should let the PVC annotation override the global backend", func(scratchSpace *cdiv1.ScratchSpaceConfig, backend string, expectEmptyDir bool) {
+		reconciler, pod := createScratchImporterPod(scratchSpace, map[string]string{cc.AnnScratchSpaceBackend: backend})
+		if expectEmptyDir {
+			expectScratchEmptyDir(reconciler, pod, corev1.StorageMediumDefault)
+		} else {
+			expectScratchPvc(reconciler, pod)
+		}
+	},
+		table.Entry("with an emptyDir", nil, string(cdiv1.ScratchSpaceBackendEmptyDirDisk), true),
+		table.Entry("with a PVC", &cdiv1.ScratchSpaceConfig{Backend: cdiv1.ScratchSpaceBackendEmptyDirDisk}, string(cdiv1.ScratchSpaceBackendPVC), false),
+		table.Entry("unless it is invalid", &cdiv1.ScratchSpaceConfig{Backend: cdiv1.ScratchSpaceBackendEmptyDirDisk}, "HostPath", true),
+	)
+
+	It("should not create a scratch PVC for a pending pod with an emptyDir scratch space", func() {
+		reconciler, pod := createScratchImporterPod(&cdiv1.ScratchSpaceConfig{Backend: cdiv1.ScratchSpaceBackendEmptyDirDisk}, map[string]string{})
+		pod.Status.Phase = corev1.PodPending
+		pvc := &corev1.PersistentVolumeClaim{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, pvc)).To(Succeed())
+		Expect(reconciler.updatePvcFromPod(pvc, pod, reconciler.log)).To(Succeed())
+		expectScratchEmptyDir(reconciler, pod, corev1.StorageMediumDefault)
+	})
+})
+
 var _ = Describe("Import test env", func() {
 	const mockUID = "1111-1111-1111-1111"
 
@@ -855,6 +2167,65 @@ var _ = Describe("Import test env", func() {
 			preallocation:      false}
 		Expect(reflect.DeepEqual(makeImportEnv(testEnvVar, mockUID), createImportTestEnv(testEnvVar, mockUID))).To(BeTrue())
 	})
+
+	It("Should add multipart download settings to import env", func() {
+		testEnvVar := &importPodEnvVar{
+			ep:                 "myendpoint",
+			source:             cc.SourceHTTP,
+			contentType:        string(cdiv1.DataVolumeKubeVirt),
+			imageSize:          "1G",
+			filesystemOverhead: "0.055",
+			multipartParallel:  "4",
+			multipartPartSize:  "1048576",
+		}
+		env := makeImportEnv(testEnvVar, mockUID)
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ImporterMultipartParallelism, Value: "4"}))
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ImporterMultipartPartSize, Value: "1048576"}))
+	})
+})
+
+var _ = Describe("Import proxy auth secret", func() {
+	It("Should copy the proxy auth secret and reference it in the import env", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{cc.AnnEndpoint: testEndPoint, cc.AnnSource: cc.SourceHTTP}, nil)
+		pod := cc.CreateImporterTestPod(pvc, "testPvc1", nil)
+		proxySecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "proxy-creds", Namespace: "cdi"},
+			Data: map[string][]byte{
+				corev1.BasicAuthUsernameKey: []byte("user"),
+				corev1.BasicAuthPasswordKey: []byte("password"),
+			},
+		}
+		reconciler := createImportReconciler(pvc, pod, proxySecret)
+		reconciler.cdiNamespace = "cdi"
+		cdiConfig := &cdiv1.CDIConfig{}
+		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)
+		Expect(err).ToNot(HaveOccurred())
+		cdiConfig.Status.ImportProxy = &cdiv1.ImportProxy{ProxyAuthSecret: pointer.String("proxy-creds")}
+		err = reconciler.client.Update(context.TODO(), cdiConfig)
+		Expect(err).ToNot(HaveOccurred())
+
+		err = reconciler.copyImportProxyAuthSecret(pvc, pod)
+		Expect(err).ToNot(HaveOccurred())
+		importSecret := &corev1.Secret{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: GetImportProxyAuthSecretName(pvc.Name), Namespace: "default"}, importSecret)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(importSecret.Data).To(Equal(proxySecret.Data))
+		Expect(importSecret.OwnerReferences[0].Name).To(Equal(pod.Name))
+
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podEnvVar.proxyAuthSecret).To(Equal(GetImportProxyAuthSecretName(pvc.Name)))
+		env := makeImportEnv(podEnvVar, "1111-1111-1111-1111")
+		Expect(env).To(ContainElement(corev1.EnvVar{
+			Name: common.ImportProxyPassword,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: podEnvVar.proxyAuthSecret},
+					Key:                  corev1.BasicAuthPasswordKey,
+				},
+			},
+		}))
+	})
 })
 
 var _ = Describe("getSecretName", func() {
@@ -973,6 +2344,9 @@ var _ = Describe("getSource", func() {
 	pvcRegistryAnno := cc.CreatePvc("testPVCRegistryAnno", "default", map[string]string{cc.AnnSource: cc.SourceRegistry}, nil)
 	pvcImageIOAnno := cc.CreatePvc("testPVCImageIOAnno", "default", map[string]string{cc.AnnSource: cc.SourceImageio}, nil)
 	pvcVDDKAnno := cc.CreatePvc("testPVCVDDKAnno", "default", map[string]string{cc.AnnSource: cc.SourceVDDK}, nil)
+	pvcInlineAnno := cc.CreatePvc("testPVCInlineAnno", "default", map[string]string{cc.AnnSource: cc.SourceInline}, nil)
+	pvcNBDAnno := cc.CreatePvc("testPVCNBDAnno", "default", map[string]string{cc.AnnSource: cc.SourceNBD}, nil)
+	pvcRemoteAnno := cc.CreatePvc("testPVCRemoteAnno", "default", map[string]string{cc.AnnSource: cc.SourceRemote}, nil)
 
 	table.DescribeTable("should", func(pvc *corev1.PersistentVolumeClaim, expectedResult string) {
 		result := cc.GetSource(pvc)
@@ -985,6 +2359,9 @@ var _ = Describe("getSource", func() {
 		table.Entry("return registry if registry annotation provided", pvcRegistryAnno, cc.SourceRegistry),
 		table.Entry("return imageio if imageio annotation provided", pvcImageIOAnno, cc.SourceImageio),
 		table.Entry("return vddk if vddk annotation provided", pvcVDDKAnno, cc.SourceVDDK),
+		table.Entry("return inline if inline annotation provided", pvcInlineAnno, cc.SourceInline),
+		table.Entry("return nbd if nbd annotation provided", pvcNBDAnno, cc.SourceNBD),
+		table.Entry("return remote if remote annotation provided", pvcRemoteAnno, cc.SourceRemote),
 	)
 })
 
@@ -1025,7 +2402,11 @@ func createImportReconciler(objects ...runtime.Object) *ImportReconciler {
 	objs = append(objs, cdiConfig)
 
 	// Create a fake client to mock API calls.
-	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objs...).Build()
+	builder := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objs...)
+	for _, ia := range getWorkerPodQueueIndexArgs() {
+		builder = builder.WithIndex(ia.obj, ia.field, ia.extractValue)
+	}
+	cl := builder.Build()
 
 	// Increase this if you have more than one event that fires.
 	rec := record.NewFakeRecorder(1)
@@ -1163,6 +2544,10 @@ func (f *FakeFeatureGates) HonorWaitForFirstConsumerEnabled() (bool, error) {
 	return f.honorWaitForFirstConsumerEnabled, nil
 }
 
+func (f *FakeFeatureGates) SharedSnapshotCloneEnabled() (bool, error) {
+	return false, nil
+}
+
 func createPendingPvc(name, ns string, annotations, labels map[string]string) *v1.PersistentVolumeClaim {
 	return cc.CreatePvcInStorageClass(name, ns, nil, annotations, labels, v1.ClaimPending)
 }
@@ -0,0 +1,230 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+	dvc "kubevirt.io/containerized-data-importer/pkg/controller/datavolume"
+	featuregates "kubevirt.io/containerized-data-importer/pkg/feature-gates"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+const importVerifyControllerName = "import-verify-controller"
+
+// ImportVerifyReconciler runs the importer pods of the verify-only DataVolumes. The pod checks the import source
+// without populating a PVC, the datavolume controller reports its outcome in the DataVolume status.
+type ImportVerifyReconciler struct {
+	*ImportReconciler
+}
+
+// Reconcile creates the importer pod verifying the source of the DataVolume, and reports its state
+func (r *ImportVerifyReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("DataVolume", req.NamespacedName)
+	dv := &cdiv1.DataVolume{}
+	if err := r.client.Get(ctx, req.NamespacedName, dv); err != nil {
+		return reconcile.Result{}, cc.IgnoreNotFound(err)
+	}
+	if !cc.IsVerifyOnly(dv) || dv.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	pod, err := r.getVerifyPod(ctx, dv)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if dvc.IsVerifyOnlyDone(dv) {
+		if pod != nil && dv.Annotations[cc.AnnPodRetainAfterCompletion] != "true" {
+			log.V(1).Info("Deleting the importer pod of the completed verification", "pod.Name", pod.Name)
+			if err := r.client.Delete(ctx, pod); cc.IgnoreNotFound(err) != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		return reconcile.Result{}, nil
+	}
+	if pod != nil && (pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed) {
+		// Kept until the datavolume controller reports the outcome
+		return reconcile.Result{}, nil
+	}
+
+	pvc, err := newVerifyPvc(dv)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if pod == nil {
+		log.V(1).Info("Creating importer pod verifying the source")
+		return reconcile.Result{}, r.createVerifyPod(pvc)
+	}
+	if err := r.copyImportProxyConfigMap(pvc, pod); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, r.copyImportProxyAuthSecret(pvc, pod)
+}
+
+// getVerifyPod returns the importer pod verifying the source of the DataVolume, nil if it does not exist
+func (r *ImportVerifyReconciler) getVerifyPod(ctx context.Context, dv *cdiv1.DataVolume) (*corev1.Pod, error) {
+	pod := &corev1.Pod{}
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: dv.Namespace, Name: cc.GetImportVerifyPodName(dv.Name)}, pod); err != nil {
+		return nil, cc.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(pod, dv) {
+		return nil, errors.Errorf("pod %s/%s is not controlled by the DataVolume", pod.Namespace, pod.Name)
+	}
+	return pod, nil
+}
+
+// newVerifyPvc returns the PVC the verify-only DataVolume would import into. It is not created, but gives the
+// importer pod the source to verify and the requested size the image has to fit in.
+func newVerifyPvc(dv *cdiv1.DataVolume) (*corev1.PersistentVolumeClaim, error) {
+	annotations := make(map[string]string)
+	for k, v := range dv.Annotations {
+		annotations[k] = v
+	}
+	annotations[cc.AnnImportPod] = cc.GetImportVerifyPodName(dv.Name)
+	annotations[cc.AnnContentType] = string(dv.Spec.ContentType)
+	if dv.Spec.PriorityClassName != "" {
+		annotations[cc.AnnPriorityClassName] = dv.Spec.PriorityClassName
+	}
+	if err := cc.SetWorkerPodPlacementAnnotation(dv, annotations); err != nil {
+		return nil, err
+	}
+	if err := cc.SetWorkerPodImageAnnotation(dv, annotations); err != nil {
+		return nil, err
+	}
+	if err := dvc.SetImportSourceAnnotations(dv, annotations); err != nil {
+		return nil, err
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            dv.Name,
+			Namespace:       dv.Namespace,
+			Annotations:     annotations,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(dv, cdiv1.SchemeGroupVersion.WithKind("DataVolume"))},
+		},
+	}
+	if dv.Spec.PVC != nil {
+		pvc.Spec = *dv.Spec.PVC.DeepCopy()
+	} else if dv.Spec.Storage != nil {
+		pvc.Spec.Resources = *dv.Spec.Storage.Resources.DeepCopy()
+		pvc.Spec.VolumeMode = dv.Spec.Storage.VolumeMode
+		pvc.Spec.StorageClassName = dv.Spec.Storage.StorageClassName
+	}
+	return pvc, nil
+}
+
+func (r *ImportVerifyReconciler) createVerifyPod(pvc *corev1.PersistentVolumeClaim) error {
+	podEnvVar, err := r.createImportEnvVar(pvc)
+	if err != nil {
+		return err
+	}
+	podEnvVar.verifyOnly = true
+	podArgs := &importerPodArgs{
+		image:             r.image,
+		verbose:           r.verbose,
+		pullPolicy:        r.pullPolicy,
+		podEnvVar:         podEnvVar,
+		pvc:               pvc,
+		priorityClassName: cc.GetPriorityClass(pvc),
+		verifyOnly:        true,
+	}
+	pod, err := createImporterPod(r.log, r.client, podArgs, r.installerLabels)
+	if err != nil {
+		return err
+	}
+	r.log.V(1).Info("Created POD", "pod.Name", pod.Name)
+	return nil
+}
+
+// setVerifyOnlyPodSpec makes the importer pod only verify the source: it is owned by the DataVolume as the PVC is not
+// created, it gets an empty data volume, and it is not restarted once it completes
+func setVerifyOnlyPodSpec(pod *corev1.Pod, pvc *corev1.PersistentVolumeClaim) {
+	pod.OwnerReferences = pvc.OwnerReferences
+	pod.Spec.RestartPolicy = corev1.RestartPolicyNever
+	pod.Spec.Volumes[0].VolumeSource = corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+	container := &pod.Spec.Containers[0]
+	if container.VolumeDevices != nil {
+		container.VolumeDevices = nil
+		container.VolumeMounts = append(cc.AddImportVolumeMounts(), container.VolumeMounts...)
+	}
+}
+
+// NewImportVerifyController creates a new instance of the import verify controller
+func NewImportVerifyController(mgr manager.Manager, log logr.Logger, importerImage, pullPolicy, verbose string, installerLabels map[string]string) (controller.Controller, error) {
+	uncachedClient, err := client.New(mgr.GetConfig(), client.Options{
+		Scheme: mgr.GetScheme(),
+		Mapper: mgr.GetRESTMapper(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	client := mgr.GetClient()
+	reconciler := &ImportVerifyReconciler{
+		ImportReconciler: &ImportReconciler{
+			client:          client,
+			uncachedClient:  uncachedClient,
+			scheme:          mgr.GetScheme(),
+			log:             log.WithName(importVerifyControllerName),
+			image:           importerImage,
+			verbose:         verbose,
+			pullPolicy:      pullPolicy,
+			recorder:        mgr.GetEventRecorderFor(importVerifyControllerName),
+			cdiNamespace:    util.GetNamespace(),
+			featureGates:    featuregates.NewFeatureGates(client),
+			installerLabels: installerLabels,
+		},
+	}
+	importVerifyController, err := controller.New(importVerifyControllerName, mgr, controller.Options{Reconciler: reconciler})
+	if err != nil {
+		return nil, err
+	}
+	if err := addImportVerifyControllerWatches(importVerifyController); err != nil {
+		return nil, err
+	}
+	log.Info("Initialized import verify controller")
+	return importVerifyController, nil
+}
+
+func addImportVerifyControllerWatches(c controller.Controller) error {
+	if err := c.Watch(&source.Kind{Type: &cdiv1.DataVolume{}}, &handler.EnqueueRequestForObject{},
+		predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			dv, ok := obj.(*cdiv1.DataVolume)
+			return ok && cc.IsVerifyOnly(dv)
+		}),
+	); err != nil {
+		return err
+	}
+	return c.Watch(&source.Kind{Type: &corev1.Pod{}}, &handler.EnqueueRequestForOwner{
+		OwnerType:    &cdiv1.DataVolume{},
+		IsController: true,
+	})
+}
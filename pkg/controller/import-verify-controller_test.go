@@ -0,0 +1,160 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+)
+
+var _ = Describe("Import verify controller reconcile loop", func() {
+	const dvName = "verify-dv"
+	var (
+		dvKey  = types.NamespacedName{Name: dvName, Namespace: metav1.NamespaceDefault}
+		podKey = types.NamespacedName{Name: cc.GetImportVerifyPodName(dvName), Namespace: metav1.NamespaceDefault}
+	)
+
+	newVerifyDataVolume := func() *cdiv1.DataVolume {
+		dv := cc.NewImportDataVolume(dvName)
+		dv.Annotations = map[string]string{cc.AnnVerifyOnly: "true"}
+		dv.Spec.PVC.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1G")}
+		return dv
+	}
+
+	createVerifyReconciler := func(objects ...runtime.Object) *ImportVerifyReconciler {
+		return &ImportVerifyReconciler{ImportReconciler: createImportReconciler(objects...)}
+	}
+
+	reconcileDataVolume := func(reconciler *ImportVerifyReconciler) {
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: dvKey})
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	getPod := func(reconciler *ImportVerifyReconciler) *corev1.Pod {
+		pod := &corev1.Pod{}
+		Expect(reconciler.client.Get(context.TODO(), podKey, pod)).To(Succeed())
+		return pod
+	}
+
+	getDataVolume := func(reconciler *ImportVerifyReconciler) *cdiv1.DataVolume {
+		dv := &cdiv1.DataVolume{}
+		Expect(reconciler.client.Get(context.TODO(), dvKey, dv)).To(Succeed())
+		return dv
+	}
+
+	terminatePod := func(reconciler *ImportVerifyReconciler, phase corev1.PodPhase, reason, message string) {
+		pod := getPod(reconciler)
+		pod.Status.Phase = phase
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			State: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{Reason: reason, Message: message},
+			},
+		}}
+		Expect(reconciler.client.Update(context.TODO(), pod)).To(Succeed())
+	}
+
+	It("Should create an importer pod verifying the source without creating the PVC", func() {
+		dv := newVerifyDataVolume()
+		reconciler := createVerifyReconciler(dv)
+		reconcileDataVolume(reconciler)
+
+		pod := getPod(reconciler)
+		Expect(metav1.IsControlledBy(pod, dv)).To(BeTrue())
+		Expect(pod.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+		Expect(pod.Spec.Volumes[0].Name).To(Equal(cc.DataVolName))
+		Expect(pod.Spec.Volumes[0].EmptyDir).ToNot(BeNil())
+		Expect(pod.Spec.Containers[0].VolumeDevices).To(BeEmpty())
+		Expect(pod.Spec.Containers[0].Env).To(ContainElements(
+			corev1.EnvVar{Name: common.ImporterVerifyOnly, Value: "true"},
+			corev1.EnvVar{Name: common.ImporterEndpoint, Value: dv.Spec.Source.HTTP.URL},
+			corev1.EnvVar{Name: common.ImporterSource, Value: cc.SourceHTTP},
+			corev1.EnvVar{Name: common.ImporterImageSize, Value: "1G"},
+		))
+		Expect(pod.Spec.PriorityClassName).To(Equal("p0"))
+
+		err := reconciler.client.Get(context.TODO(), dvKey, &corev1.PersistentVolumeClaim{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Should mount the data volume of a block verify-only DataVolume as a directory", func() {
+		dv := newVerifyDataVolume()
+		volumeMode := corev1.PersistentVolumeBlock
+		dv.Spec.PVC.VolumeMode = &volumeMode
+		reconciler := createVerifyReconciler(dv)
+		reconcileDataVolume(reconciler)
+
+		pod := getPod(reconciler)
+		Expect(pod.Spec.Containers[0].VolumeDevices).To(BeEmpty())
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: cc.DataVolName, MountPath: common.ImporterDataDir}))
+	})
+
+	It("Should keep the completed importer pod and leave the DataVolume untouched until its status reports the outcome", func() {
+		reconciler := createVerifyReconciler(newVerifyDataVolume())
+		reconcileDataVolume(reconciler)
+		message := common.SourceVerifiedMessage + ", format: qcow2, virtual size: 46137344"
+		terminatePod(reconciler, corev1.PodSucceeded, "Completed", message)
+		reconcileDataVolume(reconciler)
+
+		Expect(getPod(reconciler).Status.Phase).To(Equal(corev1.PodSucceeded))
+		dv := getDataVolume(reconciler)
+		Expect(dv.Annotations).To(Equal(map[string]string{cc.AnnVerifyOnly: "true"}))
+	})
+
+	table.DescribeTable("Should delete the importer pod and not recreate it once the verification is done", func(phase cdiv1.DataVolumePhase, podPhase corev1.PodPhase) {
+		reconciler := createVerifyReconciler(newVerifyDataVolume())
+		reconcileDataVolume(reconciler)
+		terminatePod(reconciler, podPhase, "Completed", common.SourceVerifiedMessage)
+		dv := getDataVolume(reconciler)
+		dv.Status.Phase = phase
+		Expect(reconciler.client.Status().Update(context.TODO(), dv)).To(Succeed())
+		reconcileDataVolume(reconciler)
+
+		err := reconciler.client.Get(context.TODO(), podKey, &corev1.Pod{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		reconcileDataVolume(reconciler)
+		err = reconciler.client.Get(context.TODO(), podKey, &corev1.Pod{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+	},
+		table.Entry("verified", cdiv1.SourceVerified, corev1.PodSucceeded),
+		table.Entry("failed", cdiv1.Failed, corev1.PodFailed),
+	)
+
+	It("Should ignore a DataVolume that is not verify-only", func() {
+		dv := newVerifyDataVolume()
+		dv.Annotations = nil
+		reconciler := createVerifyReconciler(dv)
+		reconcileDataVolume(reconciler)
+
+		err := reconciler.client.Get(context.TODO(), podKey, &corev1.Pod{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+	})
+})
@@ -0,0 +1,190 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+	"kubevirt.io/containerized-data-importer/pkg/util/naming"
+)
+
+const scratchGCControllerName = "scratch-gc-controller"
+
+// scratchGCGracePeriod is the minimal age of a scratch PVC before it is garbage collected, so the in-flight imports
+// whose worker pod is not in the cache yet are not raced
+var scratchGCGracePeriod = 10 * time.Minute
+
+// ScratchGCReconciler garbage collects the orphaned scratch PVCs, left behind by worker pods that were force deleted
+type ScratchGCReconciler struct {
+	client client.Client
+	log    logr.Logger
+}
+
+// Reconcile deletes the scratch PVC if it is orphaned
+func (r *ScratchGCReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("PVC", req.NamespacedName)
+	scratchPvc := &corev1.PersistentVolumeClaim{}
+	if err := r.client.Get(ctx, req.NamespacedName, scratchPvc); err != nil {
+		return reconcile.Result{}, cc.IgnoreNotFound(err)
+	}
+	if !isScratchPvc(scratchPvc) || scratchPvc.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+	if age := time.Since(scratchPvc.CreationTimestamp.Time); age < scratchGCGracePeriod {
+		return reconcile.Result{RequeueAfter: scratchGCGracePeriod - age}, nil
+	}
+
+	orphaned, err := r.isScratchPvcOrphaned(ctx, scratchPvc)
+	if err != nil || !orphaned {
+		return reconcile.Result{}, err
+	}
+	log.Info("Deleting orphaned scratch PVC")
+	if err := r.client.Delete(ctx, scratchPvc, client.Preconditions{UID: &scratchPvc.UID}); cc.IgnoreNotFound(err) != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+// isScratchPvc returns true if the PVC is the scratch space of a worker pod
+func isScratchPvc(pvc *corev1.PersistentVolumeClaim) bool {
+	owner := metav1.GetControllerOf(pvc)
+	return owner != nil && owner.Kind == "Pod" && strings.HasSuffix(pvc.Name, "-"+common.ScratchNameSuffix)
+}
+
+// isScratchPvcOrphaned returns true if the worker pod owning the scratch PVC is gone, no other pod uses it, and the PVC
+// it is the scratch space of or the DataVolume of that PVC is gone
+func (r *ScratchGCReconciler) isScratchPvcOrphaned(ctx context.Context, scratchPvc *corev1.PersistentVolumeClaim) (bool, error) {
+	owner := metav1.GetControllerOf(scratchPvc)
+	pod := &corev1.Pod{}
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: scratchPvc.Namespace, Name: owner.Name}, pod); cc.IgnoreNotFound(err) != nil {
+		return false, err
+	} else if err == nil && pod.UID == owner.UID {
+		return false, nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.client.List(ctx, pods, client.InNamespace(scratchPvc.Namespace)); err != nil {
+		return false, err
+	}
+	for _, pod := range pods.Items {
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == scratchPvc.Name {
+				return false, nil
+			}
+		}
+	}
+
+	pvc, err := r.getScratchTargetPvc(ctx, scratchPvc)
+	if err != nil || pvc == nil {
+		return pvc == nil, err
+	}
+	if owner := metav1.GetControllerOf(pvc); owner != nil && owner.Kind == "DataVolume" {
+		dv := &cdiv1.DataVolume{}
+		if err := r.client.Get(ctx, types.NamespacedName{Namespace: pvc.Namespace, Name: owner.Name}, dv); err != nil {
+			return k8serrors.IsNotFound(err), cc.IgnoreNotFound(err)
+		}
+		return dv.UID != owner.UID, nil
+	}
+	return false, nil
+}
+
+// getScratchTargetPvc returns the PVC the scratch PVC is the scratch space of, nil if it does not exist
+func (r *ScratchGCReconciler) getScratchTargetPvc(ctx context.Context, scratchPvc *corev1.PersistentVolumeClaim) (*corev1.PersistentVolumeClaim, error) {
+	pvcs := &corev1.PersistentVolumeClaimList{}
+	if err := r.client.List(ctx, pvcs, client.InNamespace(scratchPvc.Namespace)); err != nil {
+		return nil, err
+	}
+	for i := range pvcs.Items {
+		pvc := &pvcs.Items[i]
+		if pvc.Name != scratchPvc.Name && naming.GetResourceName(pvc.Name, common.ScratchNameSuffix) == scratchPvc.Name {
+			return pvc, nil
+		}
+	}
+	return nil, nil
+}
+
+// NewScratchGCController creates a new instance of the scratch PVC garbage collection controller
+func NewScratchGCController(mgr manager.Manager, log logr.Logger) (controller.Controller, error) {
+	reconciler := &ScratchGCReconciler{
+		client: mgr.GetClient(),
+		log:    log.WithName(scratchGCControllerName),
+	}
+	scratchGCController, err := controller.New(scratchGCControllerName, mgr, controller.Options{Reconciler: reconciler})
+	if err != nil {
+		return nil, err
+	}
+	if err := addScratchGCControllerWatches(scratchGCController); err != nil {
+		return nil, err
+	}
+	log.Info("Initialized scratch PVC garbage collection controller")
+	return scratchGCController, nil
+}
+
+func addScratchGCControllerWatches(c controller.Controller) error {
+	if err := c.Watch(&source.Kind{Type: &corev1.PersistentVolumeClaim{}}, &handler.EnqueueRequestForObject{},
+		predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			pvc, ok := obj.(*corev1.PersistentVolumeClaim)
+			return ok && isScratchPvc(pvc)
+		}),
+	); err != nil {
+		return err
+	}
+
+	// The deletion of a worker pod, of the PVC needing the scratch space or of its DataVolume may orphan a scratch PVC
+	onDelete := predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool { return false },
+		UpdateFunc: func(event.UpdateEvent) bool { return false },
+		DeleteFunc: func(event.DeleteEvent) bool { return true },
+	}
+	mapToScratchPvc := handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+		name := naming.GetResourceName(obj.GetName(), common.ScratchNameSuffix)
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: name}}}
+	})
+	if err := c.Watch(&source.Kind{Type: &corev1.Pod{}}, handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+		if pod, ok := obj.(*corev1.Pod); ok {
+			if name, exists := getScratchNameFromPod(pod); exists {
+				return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: pod.Namespace, Name: name}}}
+			}
+		}
+		return nil
+	}), onDelete); err != nil {
+		return err
+	}
+	if err := c.Watch(&source.Kind{Type: &corev1.PersistentVolumeClaim{}}, mapToScratchPvc, onDelete); err != nil {
+		return err
+	}
+	return c.Watch(&source.Kind{Type: &cdiv1.DataVolume{}}, mapToScratchPvc, onDelete)
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	. "kubevirt.io/containerized-data-importer/pkg/controller/common"
+)
+
+var _ = Describe("Scratch PVC garbage collection controller reconcile loop", func() {
+	const (
+		targetName  = "target"
+		scratchName = "target-scratch"
+		podName     = "importer-target"
+	)
+	var (
+		scratchKey = types.NamespacedName{Name: scratchName, Namespace: metav1.NamespaceDefault}
+		scratchReq = reconcile.Request{NamespacedName: scratchKey}
+	)
+
+	newScratchPvc := func(age time.Duration) *corev1.PersistentVolumeClaim {
+		pvc := CreatePvc(scratchName, metav1.NamespaceDefault, nil, nil)
+		pvc.UID = "scratch-uid"
+		pvc.CreationTimestamp = metav1.NewTime(time.Now().Add(-age))
+		pvc.OwnerReferences = []metav1.OwnerReference{MakePodOwnerReference(newScratchPod(podName, "pod-uid"))}
+		return pvc
+	}
+
+	newTargetPvc := func(dv *cdiv1.DataVolume) *corev1.PersistentVolumeClaim {
+		pvc := CreatePvc(targetName, metav1.NamespaceDefault, nil, nil)
+		pvc.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(dv, cdiv1.SchemeGroupVersion.WithKind("DataVolume"))}
+		return pvc
+	}
+
+	newDataVolume := func() *cdiv1.DataVolume {
+		dv := NewImportDataVolume(targetName)
+		dv.UID = "dv-uid"
+		return dv
+	}
+
+	reconcileAndExpectScratch := func(reconciler *ScratchGCReconciler, exists bool) reconcile.Result {
+		res, err := reconciler.Reconcile(context.TODO(), scratchReq)
+		Expect(err).ToNot(HaveOccurred())
+		err = reconciler.client.Get(context.TODO(), scratchKey, &corev1.PersistentVolumeClaim{})
+		if exists {
+			Expect(err).ToNot(HaveOccurred())
+		} else {
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		}
+		return res
+	}
+
+	It("Should delete an orphaned scratch PVC whose PVC is gone", func() {
+		reconciler := createScratchGCReconciler(newScratchPvc(time.Hour))
+		reconcileAndExpectScratch(reconciler, false)
+	})
+
+	It("Should delete an orphaned scratch PVC whose DataVolume is gone", func() {
+		dv := newDataVolume()
+		reconciler := createScratchGCReconciler(newScratchPvc(time.Hour), newTargetPvc(dv))
+		reconcileAndExpectScratch(reconciler, false)
+	})
+
+	It("Should not delete a scratch PVC within the grace period", func() {
+		reconciler := createScratchGCReconciler(newScratchPvc(time.Minute))
+		res := reconcileAndExpectScratch(reconciler, true)
+		Expect(res.RequeueAfter).To(BeNumerically("~", scratchGCGracePeriod-time.Minute, time.Second))
+	})
+
+	It("Should not delete a scratch PVC whose worker pod exists", func() {
+		reconciler := createScratchGCReconciler(newScratchPvc(time.Hour), newScratchPod(podName, "pod-uid"))
+		reconcileAndExpectScratch(reconciler, true)
+	})
+
+	It("Should not delete a scratch PVC used by a live pod", func() {
+		reconciler := createScratchGCReconciler(newScratchPvc(time.Hour), newScratchPod("importer-target-retry", "other-uid"))
+		reconcileAndExpectScratch(reconciler, true)
+	})
+
+	It("Should not delete a scratch PVC whose PVC and DataVolume exist", func() {
+		dv := newDataVolume()
+		reconciler := createScratchGCReconciler(newScratchPvc(time.Hour), newTargetPvc(dv), dv)
+		reconcileAndExpectScratch(reconciler, true)
+	})
+
+	It("Should not delete a PVC that is not a scratch PVC", func() {
+		pvc := newScratchPvc(time.Hour)
+		pvc.OwnerReferences = nil
+		reconciler := createScratchGCReconciler(pvc)
+		reconcileAndExpectScratch(reconciler, true)
+	})
+})
+
+func createScratchGCReconciler(objects ...runtime.Object) *ScratchGCReconciler {
+	s := scheme.Scheme
+	_ = cdiv1.AddToScheme(s)
+	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objects...).Build()
+	return &ScratchGCReconciler{
+		client: cl,
+		log:    logf.Log.WithName("scratch-gc-controller-test"),
+	}
+}
+
+func newScratchPod(name string, uid types.UID) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceDefault,
+			UID:       uid,
+		},
+		Spec: corev1.PodSpec{
+			Volumes: []corev1.Volume{
+				{
+					Name: ScratchVolName,
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "target-scratch"},
+					},
+				},
+			},
+		},
+	}
+}
@@ -103,6 +103,24 @@ func (r *StorageProfileReconciler) reconcileStorageProfile(sc *storagev1.Storage
 
 	storageProfile.Status.ClaimPropertySets = claimPropertySets
 
+	if overhead := storageProfile.Spec.FilesystemOverhead; overhead != nil {
+		if valid, _ := validOverhead(*overhead); !valid {
+			err = fmt.Errorf("invalid filesystem overhead: %s", *overhead)
+			log.Error(err, "Unable to update StorageProfile")
+			return reconcile.Result{}, err
+		}
+	}
+	if streams := storageProfile.Spec.CloneStreams; streams != nil && (*streams < 1 || *streams > common.MaxCloneStreams) {
+		err = fmt.Errorf("invalid number of clone streams: %d", *streams)
+		log.Error(err, "Unable to update StorageProfile")
+		return reconcile.Result{}, err
+	}
+	storageProfile.Status.FilesystemOverhead = storageProfile.Spec.FilesystemOverhead
+	storageProfile.Status.CloneStreams = storageProfile.Spec.CloneStreams
+	storageProfile.Status.CrossClassSnapshotRestore = storageProfile.Spec.CrossClassSnapshotRestore
+	storageProfile.Status.ThinProvisioned = storageProfile.Spec.ThinProvisioned
+	storageProfile.Status.Preallocation = getRecommendedPreallocation(storageProfile)
+
 	util.SetRecommendedLabels(storageProfile, r.installerLabels, "cdi-controller")
 	if err := r.updateStorageProfile(prevStorageProfile, storageProfile, log); err != nil {
 		return reconcile.Result{}, err
@@ -111,6 +129,16 @@ func (r *StorageProfileReconciler) reconcileStorageProfile(sc *storagev1.Storage
 	return reconcile.Result{}, nil
 }
 
+// getRecommendedPreallocation returns the preallocation set in the spec, or no preallocation when the storage is thin
+// provisioned
+func getRecommendedPreallocation(storageProfile *cdiv1.StorageProfile) *bool {
+	if storageProfile.Spec.Preallocation == nil && storageProfile.Spec.ThinProvisioned != nil && *storageProfile.Spec.ThinProvisioned {
+		preallocation := false
+		return &preallocation
+	}
+	return storageProfile.Spec.Preallocation
+}
+
 func (r *StorageProfileReconciler) updateStorageProfile(prevStorageProfile runtime.Object, storageProfile *cdiv1.StorageProfile, log logr.Logger) error {
 	if prevStorageProfile == nil {
 		return r.client.Create(context.TODO(), storageProfile)
@@ -170,6 +198,9 @@ func (r *StorageProfileReconciler) reconcileCloneStrategy(sc *storagev1.StorageC
 		} else if sc.Annotations["cdi.kubevirt.io/clone-strategy"] == "csi-clone" {
 			strategy := cdiv1.CloneStrategyCsiClone
 			return &strategy
+		} else if sc.Annotations["cdi.kubevirt.io/clone-strategy"] == "auto" {
+			strategy := cdiv1.CloneStrategyAuto
+			return &strategy
 		} else {
 			return clonestrategy
 		}
@@ -35,6 +35,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
 
 	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	"kubevirt.io/containerized-data-importer/pkg/common"
@@ -288,6 +289,141 @@ var _ = Describe("Storage profile controller reconcile loop", func() {
 		Expect(updatedSp.Spec.ClaimPropertySets).To(Equal(claimPropertySets))
 	})
 
+	It("Should update storage profile with the recommended preallocation and filesystem overhead", func() {
+		reconciler := createStorageProfileReconciler(CreateStorageClass(storageClassName, map[string]string{AnnDefaultStorageClass: "true"}))
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: storageClassName}})
+		Expect(err).ToNot(HaveOccurred())
+		sp := &cdiv1.StorageProfile{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: storageClassName}, sp)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sp.Status.Preallocation).To(BeNil())
+		Expect(sp.Status.FilesystemOverhead).To(BeNil())
+
+		preallocation := true
+		overhead := cdiv1.Percent("0.1")
+		sp.Spec.Preallocation = &preallocation
+		sp.Spec.FilesystemOverhead = &overhead
+		err = reconciler.client.Update(context.TODO(), sp.DeepCopy())
+		Expect(err).ToNot(HaveOccurred())
+		_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: storageClassName}})
+		Expect(err).ToNot(HaveOccurred())
+		updatedSp := &cdiv1.StorageProfile{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: storageClassName}, updatedSp)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*updatedSp.Status.Preallocation).To(BeTrue())
+		Expect(*updatedSp.Status.FilesystemOverhead).To(Equal(overhead))
+	})
+
+	It("Should error when updating storage profile with an invalid filesystem overhead", func() {
+		reconciler := createStorageProfileReconciler(CreateStorageClass(storageClassName, map[string]string{AnnDefaultStorageClass: "true"}))
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: storageClassName}})
+		Expect(err).ToNot(HaveOccurred())
+		sp := &cdiv1.StorageProfile{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: storageClassName}, sp)
+		Expect(err).ToNot(HaveOccurred())
+
+		overhead := cdiv1.Percent("1.5")
+		sp.Spec.FilesystemOverhead = &overhead
+		err = reconciler.client.Update(context.TODO(), sp.DeepCopy())
+		Expect(err).ToNot(HaveOccurred())
+		_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: storageClassName}})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("invalid filesystem overhead: 1.5"))
+		updatedSp := &cdiv1.StorageProfile{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: storageClassName}, updatedSp)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updatedSp.Status.FilesystemOverhead).To(BeNil())
+	})
+
+	It("Should update storage profile with the clone streams", func() {
+		reconciler := createStorageProfileReconciler(CreateStorageClass(storageClassName, map[string]string{AnnDefaultStorageClass: "true"}))
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: storageClassName}})
+		Expect(err).ToNot(HaveOccurred())
+		sp := &cdiv1.StorageProfile{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: storageClassName}, sp)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sp.Status.CloneStreams).To(BeNil())
+
+		streams := int32(4)
+		sp.Spec.CloneStreams = &streams
+		err = reconciler.client.Update(context.TODO(), sp.DeepCopy())
+		Expect(err).ToNot(HaveOccurred())
+		_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: storageClassName}})
+		Expect(err).ToNot(HaveOccurred())
+		updatedSp := &cdiv1.StorageProfile{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: storageClassName}, updatedSp)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*updatedSp.Status.CloneStreams).To(Equal(streams))
+	})
+
+	It("Should update storage profile with the cross storage class snapshot restore capability", func() {
+		reconciler := createStorageProfileReconciler(CreateStorageClass(storageClassName, map[string]string{AnnDefaultStorageClass: "true"}))
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: storageClassName}})
+		Expect(err).ToNot(HaveOccurred())
+		sp := &cdiv1.StorageProfile{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: storageClassName}, sp)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sp.Status.CrossClassSnapshotRestore).To(BeNil())
+
+		crossClassSnapshotRestore := true
+		sp.Spec.CrossClassSnapshotRestore = &crossClassSnapshotRestore
+		err = reconciler.client.Update(context.TODO(), sp.DeepCopy())
+		Expect(err).ToNot(HaveOccurred())
+		_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: storageClassName}})
+		Expect(err).ToNot(HaveOccurred())
+		updatedSp := &cdiv1.StorageProfile{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: storageClassName}, updatedSp)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*updatedSp.Status.CrossClassSnapshotRestore).To(BeTrue())
+	})
+
+	It("Should error when updating storage profile with an invalid number of clone streams", func() {
+		reconciler := createStorageProfileReconciler(CreateStorageClass(storageClassName, map[string]string{AnnDefaultStorageClass: "true"}))
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: storageClassName}})
+		Expect(err).ToNot(HaveOccurred())
+		sp := &cdiv1.StorageProfile{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: storageClassName}, sp)
+		Expect(err).ToNot(HaveOccurred())
+
+		streams := int32(32)
+		sp.Spec.CloneStreams = &streams
+		err = reconciler.client.Update(context.TODO(), sp.DeepCopy())
+		Expect(err).ToNot(HaveOccurred())
+		_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: storageClassName}})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("invalid number of clone streams: 32"))
+		updatedSp := &cdiv1.StorageProfile{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: storageClassName}, updatedSp)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updatedSp.Status.CloneStreams).To(BeNil())
+	})
+
+	table.DescribeTable("Should recommend the preallocation of a thin provisioned storage class", func(thinProvisioned, preallocation, expected *bool) {
+		reconciler := createStorageProfileReconciler(CreateStorageClass(storageClassName, map[string]string{AnnDefaultStorageClass: "true"}))
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: storageClassName}})
+		Expect(err).ToNot(HaveOccurred())
+		sp := &cdiv1.StorageProfile{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: storageClassName}, sp)
+		Expect(err).ToNot(HaveOccurred())
+
+		sp.Spec.ThinProvisioned = thinProvisioned
+		sp.Spec.Preallocation = preallocation
+		err = reconciler.client.Update(context.TODO(), sp.DeepCopy())
+		Expect(err).ToNot(HaveOccurred())
+		_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: storageClassName}})
+		Expect(err).ToNot(HaveOccurred())
+		updatedSp := &cdiv1.StorageProfile{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: storageClassName}, updatedSp)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updatedSp.Status.ThinProvisioned).To(Equal(thinProvisioned))
+		Expect(updatedSp.Status.Preallocation).To(Equal(expected))
+	},
+		table.Entry("no preallocation when thin provisioned", pointer.Bool(true), nil, pointer.Bool(false)),
+		table.Entry("the spec preallocation when thin provisioned", pointer.Bool(true), pointer.Bool(true), pointer.Bool(true)),
+		table.Entry("none when not thin provisioned", pointer.Bool(false), nil, nil),
+		table.Entry("none when unknown", nil, nil, nil),
+	)
+
 	table.DescribeTable("should create clone strategy", func(cloneStrategy cdiv1.CDICloneStrategy) {
 		storageClass := CreateStorageClass(storageClassName, map[string]string{AnnDefaultStorageClass: "true"})
 
@@ -309,6 +445,7 @@ var _ = Describe("Storage profile controller reconcile loop", func() {
 		table.Entry("None", cdiv1.CloneStrategyHostAssisted),
 		table.Entry("Snapshot", cdiv1.CloneStrategySnapshot),
 		table.Entry("Clone", cdiv1.CloneStrategyCsiClone),
+		table.Entry("Auto", cdiv1.CloneStrategyAuto),
 	)
 
 	table.DescribeTable("Should set the IncompleteProfileGauge correctly", func(provisioner string, count int) {
@@ -26,6 +26,7 @@ import (
 
 	featuregates "kubevirt.io/containerized-data-importer/pkg/feature-gates"
 	"kubevirt.io/containerized-data-importer/pkg/util"
+	"kubevirt.io/containerized-data-importer/pkg/util/logging"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
@@ -70,6 +71,9 @@ const (
 
 	uploadServerCertDuration = 365 * 24 * time.Hour
 
+	// cloneCheckpointVolName is the volume where the upload server of a clone target keeps the checkpoint of the clone stream
+	cloneCheckpointVolName = "cdi-clone-checkpoint-vol"
+
 	// UploadSucceededPVC provides a const to indicate an import to the PVC failed
 	UploadSucceededPVC = "UploadSucceeded"
 
@@ -136,7 +140,7 @@ func (r *UploadReconciler) Reconcile(_ context.Context, req reconcile.Request) (
 	if err != nil {
 		return reconcile.Result{}, err
 	}
-	// force cleanup if PVC pending delete and pod running or the upload/clone annotation was removed
+	// force cleanup if PVC pending delete and pod running, the upload/clone annotation was removed or the DataVolume timed out or was canceled
 	if !shouldReconcile || podSucceededFromPVC(pvc) || pvc.DeletionTimestamp != nil {
 		log.V(1).Info("not doing anything with PVC",
 			"isUpload", isUpload,
@@ -161,6 +165,7 @@ func (r *UploadReconciler) shouldReconcile(isUpload bool, isCloneTarget bool, pv
 	}
 
 	return (isUpload || isCloneTarget) &&
+			!cc.IsTransferStopped(pvc) &&
 			shouldHandlePvc(pvc, waitForFirstConsumerEnabled, log),
 		nil
 }
@@ -223,6 +228,10 @@ func (r *UploadReconciler) reconcilePVC(log logr.Logger, pvc *corev1.PersistentV
 			}
 			return reconcile.Result{Requeue: true}, nil
 		}
+		// Wait for a worker pod slot under the limit
+		if admitted, err := reconcileWorkerPodQueue(r.client, pvcCopy); err != nil || !admitted {
+			return reconcile.Result{RequeueAfter: workerPodQueueRequeueInterval}, err
+		}
 		pod, err = r.createUploadPodForPvc(pvc, podName, scratchPVCName, uploadClientName)
 		if err != nil {
 			return reconcile.Result{}, err
@@ -434,7 +443,7 @@ func (r *UploadReconciler) getOrCreateScratchPvc(pvc *v1.PersistentVolumeClaim,
 		if !metav1.IsControlledBy(scratchPvc, pod) {
 			return nil, errors.Errorf("%s scratch PVC not controlled by pod %s", scratchPvc.Name, pod.Name)
 		}
-		setBoundConditionFromPVC(anno, cc.AnnBoundCondition, scratchPvc)
+		setScratchBoundConditionFromPVC(anno, scratchPvc)
 	}
 
 	return scratchPvc, nil
@@ -484,6 +493,7 @@ func updateUploadAnnotations(pvc *corev1.PersistentVolumeClaim, anno map[string]
 	anno[cc.AnnPodReady] = strconv.FormatBool(isPodReady(pod))
 
 	setAnnotationsFromPodWithPrefix(anno, pod, cc.AnnRunningCondition)
+	setTargetImageAnnotations(anno, pod)
 }
 
 func isPodReady(pod *v1.Pod) bool {
@@ -583,12 +593,20 @@ func (r *UploadReconciler) createUploadPod(args UploadPodArgs) (*v1.Pod, error)
 		return nil, err
 	}
 
-	workloadNodePlacement, err := cc.GetWorkloadNodePlacement(r.client)
+	workloadNodePlacement, err := cc.GetPvcWorkerPodNodePlacement(r.client, args.PVC)
+	if err != nil {
+		return nil, err
+	}
+
+	image, pullPolicy, err := cc.GetPvcWorkerPodImage(r.client, args.PVC, r.image, r.pullPolicy)
 	if err != nil {
 		return nil, err
 	}
 
-	pod := r.makeUploadPodSpec(args, podResourceRequirements, imagePullSecrets, workloadNodePlacement)
+	pod := r.makeUploadPodSpec(args, image, pullPolicy, podResourceRequirements, imagePullSecrets, workloadNodePlacement)
+	if err := cc.SetTopologyNodeAffinity(&pod.Spec, args.PVC); err != nil {
+		return nil, err
+	}
 	util.SetRecommendedLabels(pod, r.installerLabels, "cdi-controller")
 
 	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: args.Name, Namespace: ns}, pod); err != nil {
@@ -600,7 +618,7 @@ func (r *UploadReconciler) createUploadPod(args UploadPodArgs) (*v1.Pod, error)
 		}
 	}
 
-	r.log.V(1).Info("upload pod created\n", "Namespace", pod.Namespace, "Name", pod.Name, "Image name", r.image)
+	r.log.V(1).Info("upload pod created\n", "Namespace", pod.Namespace, "Name", pod.Name, "Image name", image)
 	return pod, nil
 }
 
@@ -691,13 +709,46 @@ func addUploadControllerWatches(mgr manager.Manager, uploadController controller
 }
 
 func createScratchPvcNameFromPvc(pvc *v1.PersistentVolumeClaim, isCloneTarget bool) string {
-	if isCloneTarget {
+	// A disk selected from the clone source may need to be converted, which requires scratch space, as does a clone
+	// written as qcow2
+	_, selectsDisk := pvc.Annotations[cc.AnnCloneSourcePath]
+	if isCloneTarget && !selectsDisk && !isQcow2CloneTarget(pvc) {
 		return ""
 	}
 
 	return naming.GetResourceName(pvc.Name, common.ScratchNameSuffix)
 }
 
+// isQcow2CloneTarget returns true if the clone into the target PVC is written as qcow2
+func isQcow2CloneTarget(pvc *v1.PersistentVolumeClaim) bool {
+	return pvc.Annotations[cc.AnnTargetFormat] == common.ImportTargetFormatQcow2
+}
+
+// cloneTargetImageEnv returns the env of the upload server converting a clone written as qcow2
+func cloneTargetImageEnv(pvc *v1.PersistentVolumeClaim) []v1.EnvVar {
+	if !isQcow2CloneTarget(pvc) {
+		return nil
+	}
+	return []v1.EnvVar{
+		{
+			Name:  common.UploadTargetFormat,
+			Value: common.ImportTargetFormatQcow2,
+		},
+		{
+			Name:  common.UploadTargetCompression,
+			Value: pvc.Annotations[cc.AnnTargetCompression],
+		},
+		{
+			Name:  common.UploadQcow2ClusterSize,
+			Value: pvc.Annotations[cc.AnnCloneQcow2ClusterSize],
+		},
+		{
+			Name:  common.UploadCompact,
+			Value: pvc.Annotations[cc.AnnCloneCompact],
+		},
+	}
+}
+
 // getUploadResourceName returns the name given to upload resources
 func getUploadResourceNameFromPvc(pvc *corev1.PersistentVolumeClaim) string {
 	podName, ok := pvc.Annotations[AnnUploadPod]
@@ -734,7 +785,7 @@ func createUploadServiceNameFromPvcName(pvc string) string {
 	return naming.GetServiceNameFromResourceName(createUploadResourceName(pvc))
 }
 
-func (r *UploadReconciler) makeUploadPodSpec(args UploadPodArgs, resourceRequirements *v1.ResourceRequirements, imagePullSecrets []v1.LocalObjectReference, workloadNodePlacement *sdkapi.NodePlacement) *v1.Pod {
+func (r *UploadReconciler) makeUploadPodSpec(args UploadPodArgs, image, pullPolicy string, resourceRequirements *v1.ResourceRequirements, imagePullSecrets []v1.LocalObjectReference, workloadNodePlacement *sdkapi.NodePlacement) *v1.Pod {
 	requestImageSize, _ := cc.GetRequestedImageSize(args.PVC)
 	serviceName := naming.GetServiceNameFromResourceName(args.Name)
 	pod := &v1.Pod{
@@ -758,8 +809,8 @@ func (r *UploadReconciler) makeUploadPodSpec(args UploadPodArgs, resourceRequire
 			Containers: []v1.Container{
 				{
 					Name:            common.UploadServerPodname,
-					Image:           r.image,
-					ImagePullPolicy: v1.PullPolicy(r.pullPolicy),
+					Image:           image,
+					ImagePullPolicy: v1.PullPolicy(pullPolicy),
 					Env: []v1.EnvVar{
 						{
 							Name: "TLS_KEY",
@@ -888,6 +939,25 @@ func (r *UploadReconciler) makeUploadPodSpec(args UploadPodArgs, resourceRequire
 			MountPath: common.ScratchDataDir,
 		})
 	}
+	if _, isCloneTarget := args.PVC.Annotations[cc.AnnCloneRequest]; isCloneTarget {
+		// Survives container restarts, so a clone restarted after a crash can resume from the checkpoint
+		pod.Spec.Volumes = append(pod.Spec.Volumes, v1.Volume{
+			Name: cloneCheckpointVolName,
+			VolumeSource: v1.VolumeSource{
+				EmptyDir: &v1.EmptyDirVolumeSource{},
+			},
+		})
+
+		pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, v1.VolumeMount{
+			Name:      cloneCheckpointVolName,
+			MountPath: common.CloneCheckpointDir,
+		})
+		pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, cloneTargetImageEnv(args.PVC)...)
+	}
+	if cc.IsAdoptedPVC(args.PVC) {
+		addTargetEmptyCheck(pod)
+	}
+	pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, logging.PodEnv(args.PVC.Namespace, args.PVC.Name)...)
 	setPodPvcAnnotations(pod, args.PVC)
 	cc.SetRestrictedSecurityContext(&pod.Spec)
 	return pod
@@ -179,6 +179,48 @@ var _ = Describe("Upload controller reconcile loop", func() {
 
 	})
 
+	table.DescribeTable("Should return nil and remove any service and pod if the transfer is stopped", func(annotation string) {
+		testPvc := cc.CreatePvc("testPvc1", "default", map[string]string{cc.AnnUploadRequest: "", cc.AnnPodPhase: string(corev1.PodRunning), annotation: "true"}, nil)
+		reconciler := createUploadReconciler(testPvc,
+			createUploadPod(testPvc),
+			createUploadService(testPvc),
+		)
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		By("Verifying the pod and service no longer exist")
+		podList := &corev1.PodList{}
+		err = reconciler.client.List(context.TODO(), podList, &client.ListOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podList.Items).To(BeEmpty())
+
+		serviceList := &corev1.ServiceList{}
+		err = reconciler.client.List(context.TODO(), serviceList, &client.ListOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(serviceList.Items).To(BeEmpty())
+	},
+		table.Entry("because the DataVolume failed its completion timeout", cc.AnnCompletionTimedOut),
+		table.Entry("because the DataVolume that adopted the PVC was canceled", cc.AnnCanceled),
+	)
+
+	It("Should make the upload pod check an adopted pvc is empty", func() {
+		testPvc := cc.CreatePvc("testPvc1", "default", map[string]string{cc.AnnUploadRequest: "", AnnUploadPod: createUploadResourceName("testPvc1"), cc.AnnAdoptedPVC: "true"}, nil)
+		reconciler := createUploadReconciler(testPvc)
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		uploadPod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: createUploadResourceName("testPvc1"), Namespace: "default"}, uploadPod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(uploadPod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: common.TargetMustBeEmpty, Value: "true"}))
+		Expect(uploadPod.Spec.Volumes).To(ContainElement(corev1.Volume{
+			Name:         targetCheckVolName,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		}))
+		Expect(uploadPod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      targetCheckVolName,
+			MountPath: common.TargetCheckDir,
+		}))
+	})
+
 	It("Should return nil and remove any service and pod if pvc marked for deletion", func() {
 		testPvc := cc.CreatePvc("testPvc1", "default", map[string]string{cc.AnnUploadRequest: "", cc.AnnPodPhase: string(corev1.PodPending)}, nil)
 		now := metav1.NewTime(time.Now())
@@ -261,6 +303,15 @@ var _ = Describe("Upload controller reconcile loop", func() {
 		Expect(uploadPod.Name).To(Equal(createUploadResourceName(testPvc.Name)))
 		Expect(uploadPod.Spec.PriorityClassName).To(Equal("p0"))
 		Expect(uploadPod.Labels[common.AppKubernetesPartOfLabel]).To(Equal("testing"))
+		By("Verifying the pod keeps the clone checkpoint in an emptyDir")
+		Expect(uploadPod.Spec.Volumes).To(ContainElement(corev1.Volume{
+			Name:         cloneCheckpointVolName,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		}))
+		Expect(uploadPod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      cloneCheckpointVolName,
+			MountPath: common.CloneCheckpointDir,
+		}))
 
 		uploadService = &corev1.Service{}
 		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: createUploadResourceName("testPvc1"), Namespace: "default"}, uploadService)
@@ -280,6 +331,56 @@ var _ = Describe("Upload controller reconcile loop", func() {
 	})
 })
 
+var _ = Describe("Upload controller scratch space for clones", func() {
+	table.DescribeTable("should", func(annotations map[string]string, expectScratch bool) {
+		annotations[cc.AnnCloneRequest] = "default/testPvc2"
+		annotations[AnnUploadPod] = createUploadResourceName("testPvc1")
+		testPvc := cc.CreatePvc("testPvc1", "default", annotations, nil)
+		testPvcSource := cc.CreatePvc("testPvc2", "default", map[string]string{}, nil)
+		reconciler := createUploadReconciler(testPvc, testPvcSource)
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+
+		uploadPod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: createUploadResourceName("testPvc1"), Namespace: "default"}, uploadPod)
+		Expect(err).ToNot(HaveOccurred())
+		scratchName, hasScratch := getScratchNameFromPod(uploadPod)
+		Expect(hasScratch).To(Equal(expectScratch))
+		if expectScratch {
+			Expect(scratchName).To(Equal("testPvc1-scratch"))
+		}
+	},
+		table.Entry("not use scratch space when cloning the whole volume", map[string]string{}, false),
+		table.Entry("use scratch space when cloning a selected disk", map[string]string{cc.AnnCloneSourcePath: "disks/vm1.qcow2"}, true),
+		table.Entry("use scratch space when writing the clone as qcow2", map[string]string{cc.AnnTargetFormat: "qcow2"}, true),
+	)
+
+	It("should pass the qcow2 layout of the clone target to the upload server", func() {
+		testPvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnCloneRequest:          "default/testPvc2",
+			AnnUploadPod:                createUploadResourceName("testPvc1"),
+			cc.AnnTargetFormat:          "qcow2",
+			cc.AnnTargetCompression:     "zstd",
+			cc.AnnCloneQcow2ClusterSize: "1Mi",
+			cc.AnnCloneCompact:          "true",
+		}, nil)
+		testPvcSource := cc.CreatePvc("testPvc2", "default", map[string]string{}, nil)
+		reconciler := createUploadReconciler(testPvc, testPvcSource)
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+
+		uploadPod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: createUploadResourceName("testPvc1"), Namespace: "default"}, uploadPod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(uploadPod.Spec.Containers[0].Env).To(ContainElements(
+			corev1.EnvVar{Name: common.UploadTargetFormat, Value: "qcow2"},
+			corev1.EnvVar{Name: common.UploadTargetCompression, Value: "zstd"},
+			corev1.EnvVar{Name: common.UploadQcow2ClusterSize, Value: "1Mi"},
+			corev1.EnvVar{Name: common.UploadCompact, Value: "true"},
+		))
+	})
+})
+
 var _ = Describe("reconcilePVC loop", func() {
 	testPvcName := "testPvc1"
 	uploadResourceName := "uploader" //createUploadResourceName(testPvcName)
@@ -448,6 +549,71 @@ var _ = Describe("reconcilePVC loop", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 
+		It("Should create the pod with the worker pod placement of the PVC", func() {
+			testPvc := cc.CreatePvc(testPvcName, "default", map[string]string{
+				cc.AnnUploadRequest:      "",
+				AnnUploadPod:             uploadResourceName,
+				cc.AnnWorkerPodPlacement: `{"nodeSelector":{"disktype":"ssd"},"tolerations":[{"key":"storage","operator":"Exists"}]}`,
+			}, nil)
+			reconciler := createUploadReconciler(testPvc)
+			cdiConfig := &cdiv1.CDIConfig{}
+			Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+			cdiConfig.Spec.AllowedWorkerPodPlacement = &cdiv1.WorkerPodPlacement{
+				NodeSelector: map[string]string{"disktype": "ssd"},
+				Tolerations:  []corev1.Toleration{{Key: "storage", Operator: corev1.TolerationOpExists}},
+			}
+			Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+
+			_, err := reconciler.reconcilePVC(reconciler.log, testPvc, isClone)
+			Expect(err).ToNot(HaveOccurred())
+			uploadPod := &corev1.Pod{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: uploadResourceName, Namespace: "default"}, uploadPod)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(uploadPod.Spec.NodeSelector).To(Equal(map[string]string{"disktype": "ssd"}))
+			Expect(uploadPod.Spec.Tolerations).To(Equal([]corev1.Toleration{{Key: "storage", Operator: corev1.TolerationOpExists}}))
+		})
+
+		It("Should pin the pod to the topology of the PVC", func() {
+			testPvc := cc.CreatePvc(testPvcName, "default", map[string]string{
+				cc.AnnUploadRequest: "",
+				AnnUploadPod:        uploadResourceName,
+				cc.AnnTopology:      "topology.kubernetes.io/zone=us-east-1a",
+			}, nil)
+			reconciler := createUploadReconciler(testPvc)
+
+			_, err := reconciler.reconcilePVC(reconciler.log, testPvc, isClone)
+			Expect(err).ToNot(HaveOccurred())
+			uploadPod := &corev1.Pod{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: uploadResourceName, Namespace: "default"}, uploadPod)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(uploadPod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(Equal([]corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "topology.kubernetes.io/zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"us-east-1a"}}},
+			}}))
+		})
+
+		It("Should create the pod with the worker pod image of the PVC", func() {
+			testPvc := cc.CreatePvc(testPvcName, "default", map[string]string{
+				cc.AnnUploadRequest:  "",
+				AnnUploadPod:         uploadResourceName,
+				cc.AnnWorkerPodImage: `{"registry":"mirror.example.com/kubevirt","pullPolicy":"IfNotPresent"}`,
+			}, nil)
+			reconciler := createUploadReconciler(testPvc)
+			reconciler.image = "quay.io/kubevirt/cdi-uploadserver:v1.57.0"
+			reconciler.pullPolicy = "Always"
+			cdiConfig := &cdiv1.CDIConfig{}
+			Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+			cdiConfig.Spec.AllowedWorkerPodRegistries = []string{"mirror.example.com/kubevirt"}
+			Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+
+			_, err := reconciler.reconcilePVC(reconciler.log, testPvc, isClone)
+			Expect(err).ToNot(HaveOccurred())
+			uploadPod := &corev1.Pod{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: uploadResourceName, Namespace: "default"}, uploadPod)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(uploadPod.Spec.Containers[0].Image).To(Equal("mirror.example.com/kubevirt/cdi-uploadserver:v1.57.0"))
+			Expect(uploadPod.Spec.Containers[0].ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+		})
+
 		table.DescribeTable("should pass correct crypto config to created pod", func(profile *ocpconfigv1.TLSSecurityProfile) {
 			testPvc := cc.CreatePvc(testPvcName, "default", map[string]string{cc.AnnUploadRequest: "", AnnUploadPod: uploadResourceName}, nil)
 			reconciler := createUploadReconciler(testPvc)
@@ -601,6 +767,29 @@ var _ = Describe("updateUploadAnnotations", func() {
 		Expect(pvcCopy.GetAnnotations()[cc.AnnRunningConditionMessage]).To(Equal(""))
 		Expect(pvcCopy.GetAnnotations()[cc.AnnRunningConditionReason]).To(Equal(PodRunningReason))
 	})
+
+	It("Should record the format of a clone written as qcow2", func() {
+		testPvc := cc.CreatePvc("testPvc", "default", map[string]string{cc.AnnCloneRequest: "default/testPvc2"}, nil)
+		pod := createUploadPod(testPvc)
+		pod.Status = corev1.PodStatus{
+			Phase: corev1.PodSucceeded,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							Message: `Clone Complete; Target: {"Format":"qcow2","Compression":"zstd"}`,
+						},
+					},
+				},
+			},
+		}
+
+		pvcCopy := testPvc.DeepCopy()
+
+		updateUploadAnnotations(testPvc, pvcCopy.Annotations, pod, true)
+		Expect(pvcCopy.Annotations[cc.AnnImageTargetFormat]).To(Equal("qcow2"))
+		Expect(pvcCopy.Annotations[cc.AnnImageTargetCompression]).To(Equal("zstd"))
+	})
 })
 
 func createUploadReconciler(objects ...runtime.Object) *UploadReconciler {
@@ -620,7 +809,11 @@ func createUploadReconciler(objects ...runtime.Object) *UploadReconciler {
 	_ = cdiv1.AddToScheme(s)
 
 	// Create a fake client to mock API calls.
-	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objs...).Build()
+	builder := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objs...)
+	for _, ia := range getWorkerPodQueueIndexArgs() {
+		builder = builder.WithIndex(ia.obj, ia.field, ia.extractValue)
+	}
+	cl := builder.Build()
 
 	rec := record.NewFakeRecorder(10)
 
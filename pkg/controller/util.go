@@ -20,6 +20,7 @@ import (
 	"context"
 	"crypto/rsa"
 	"encoding/json"
+	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
@@ -49,9 +50,18 @@ const (
 	// CertVolName is the name of the volume containing certs
 	CertVolName = "cdi-cert-vol"
 
-	// SecretVolName is the name of the volume containing gcs key
+	// SecretVolName is the name of the volume containing the gcs key, the NBD TLS credentials or the registry credentials
 	SecretVolName = "cdi-secret-vol"
 
+	// InlineSourceVolName is the name of the volume containing the image of an inline source
+	InlineSourceVolName = "cdi-inline-source-vol"
+
+	// EncryptionKeyVolName is the name of the volume containing the passphrase of a LUKS target
+	EncryptionKeyVolName = "cdi-encryption-key-vol"
+
+	// targetCheckVolName is the volume where a worker records it found its adopted target volume empty
+	targetCheckVolName = "cdi-target-check-vol"
+
 	// AnnOwnerRef is used when owner is in a different namespace
 	AnnOwnerRef = cc.AnnAPIGroup + "/storage.ownerRef"
 
@@ -77,7 +87,12 @@ const (
 )
 
 var (
-	vddkInfoMatch = regexp.MustCompile(`((.*; )|^)VDDK: (?P<info>{.*})`)
+	vddkInfoMatch      = regexp.MustCompile(`((.*; )|^)VDDK: (?P<info>{.*})`)
+	imageInfoMatch     = regexp.MustCompile(`((.*; )|^)` + common.ImageInfoPrefix + `(?P<info>{[^}]*})`)
+	importTimingsMatch = regexp.MustCompile(`((.*; )|^)` + common.ImportTimingsPrefix + `(?P<info>{[^}]*})`)
+	targetImageMatch   = regexp.MustCompile(`((.*; )|^)` + common.TargetImageInfoPrefix + `(?P<info>{[^}]*})`)
+	validatorsMatch    = regexp.MustCompile(`((.*; )|^)` + common.SourceValidatorsPrefix + `(?P<info>{[^}]*})`)
+	sourceDigestMatch  = regexp.MustCompile(`((.*; )|^)` + common.SourceDigestPrefix + `(?P<info>{[^}]*})`)
 )
 
 func checkPVC(pvc *v1.PersistentVolumeClaim, annotation string, log logr.Logger) bool {
@@ -192,23 +207,7 @@ func GetFilesystemOverhead(client client.Client, pvc *v1.PersistentVolumeClaim)
 // 2. If 1 is not available, use the storage class name of the original pvc that will own the scratch pvc.
 // 3. If none of those are available, return blank.
 func GetScratchPvcStorageClass(client client.Client, pvc *v1.PersistentVolumeClaim) string {
-	config := &cdiv1.CDIConfig{}
-	if err := client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, config); err != nil {
-		return ""
-	}
-	storageClassName := config.Status.ScratchSpaceStorageClass
-	if storageClassName == "" {
-		// Unable to determine scratch storage class, attempt to read the storage class from the pvc.
-		if pvc.Spec.StorageClassName != nil {
-			storageClassName = *pvc.Spec.StorageClassName
-			if storageClassName != "" {
-				return storageClassName
-			}
-		}
-	} else {
-		return storageClassName
-	}
-	return ""
+	return cc.GetScratchStorageClass(client, pvc.Spec.StorageClassName)
 }
 
 // DecodePublicKey turns a bunch of bytes into a public key
@@ -289,15 +288,22 @@ func setAnnotationsFromPodWithPrefix(anno map[string]string, pod *v1.Pod, prefix
 			anno[prefix+".reason"] = containerState.Waiting.Reason
 		} else if containerState.Terminated != nil {
 			anno[prefix+".message"] = simplifyKnownMessage(containerState.Terminated.Message)
-			anno[prefix+".reason"] = containerState.Terminated.Reason
+			anno[prefix+".reason"] = knownFailureReason(containerState.Terminated.Message, containerState.Terminated.Reason)
 			if strings.Contains(containerState.Terminated.Message, common.PreallocationApplied) {
 				anno[cc.AnnPreallocationApplied] = "true"
 			}
+			if strings.Contains(containerState.Terminated.Message, common.PassthroughApplied) {
+				anno[cc.AnnImportPassthrough] = "true"
+			}
 		}
 	}
 }
 
 func simplifyKnownMessage(msg string) string {
+	// the scratch space ran out, and not the target: keep the sizes of the scratch space in the message
+	if strings.Contains(msg, common.ScratchSpaceExhaustedMessage) {
+		return msg
+	}
 	if strings.Contains(msg, "is larger than the reported available") ||
 		strings.Contains(msg, "no space left on device") ||
 		strings.Contains(msg, "file largest block is bigger than maxblock") {
@@ -307,6 +313,26 @@ func simplifyKnownMessage(msg string) string {
 	return msg
 }
 
+// knownFailureReason returns the reason of a known failure reported in the termination message, otherwise the passed reason
+func knownFailureReason(msg, reason string) string {
+	if strings.Contains(msg, common.S3KMSAccessDeniedMessage) {
+		return S3KMSAccessDenied
+	}
+	if strings.Contains(msg, common.UnsupportedFormatMessage) {
+		return UnsupportedFormat
+	}
+	if strings.Contains(msg, common.QemuImgUnavailableMessage) {
+		return ToolUnavailable
+	}
+	if strings.Contains(msg, common.ScratchSpaceExhaustedMessage) {
+		return cc.ScratchSpaceExhausted
+	}
+	if strings.Contains(msg, common.CloneChecksumMismatchMessage) {
+		return CloneChecksumMismatch
+	}
+	return reason
+}
+
 func setVddkAnnotations(anno map[string]string, pod *v1.Pod) {
 	if pod.Status.ContainerStatuses[0].State.Terminated == nil {
 		return
@@ -336,6 +362,131 @@ func setVddkAnnotations(anno map[string]string, pod *v1.Pod) {
 	}
 }
 
+// getImageInfoFromPod returns the image info reported in the termination message of the pod, nil if there is none
+func getImageInfoFromPod(pod *v1.Pod) *util.ImageInfo {
+	if pod == nil || len(pod.Status.ContainerStatuses) == 0 || pod.Status.ContainerStatuses[0].State.Terminated == nil {
+		return nil
+	}
+	matches := imageInfoMatch.FindStringSubmatch(pod.Status.ContainerStatuses[0].State.Terminated.Message)
+	if matches == nil {
+		return nil
+	}
+	imageInfo := &util.ImageInfo{}
+	if err := json.Unmarshal([]byte(matches[imageInfoMatch.SubexpIndex("info")]), imageInfo); err != nil {
+		return nil
+	}
+	return imageInfo
+}
+
+// setImageAnnotations records the format and virtual size of the source image reported by the importer pod
+func setImageAnnotations(anno map[string]string, pod *v1.Pod) {
+	imageInfo := getImageInfoFromPod(pod)
+	if imageInfo == nil {
+		return
+	}
+	if imageInfo.Format != "" {
+		anno[cc.AnnImageFormat] = imageInfo.Format
+	}
+	if imageInfo.VirtualSize > 0 {
+		anno[cc.AnnImageVirtualSize] = strconv.FormatInt(imageInfo.VirtualSize, 10)
+	}
+}
+
+// setImportTimingsAnnotations records the time the importer pod spent in the phases of the import. The timings are
+// best effort, a termination message without them leaves the annotations unchanged.
+func setImportTimingsAnnotations(anno map[string]string, pod *v1.Pod) {
+	if pod == nil || len(pod.Status.ContainerStatuses) == 0 || pod.Status.ContainerStatuses[0].State.Terminated == nil {
+		return
+	}
+	matches := importTimingsMatch.FindStringSubmatch(pod.Status.ContainerStatuses[0].State.Terminated.Message)
+	if matches == nil {
+		return
+	}
+	timings := &cdiv1.DataVolumeImportTimings{}
+	if err := json.Unmarshal([]byte(matches[importTimingsMatch.SubexpIndex("info")]), timings); err != nil {
+		return
+	}
+	anno[cc.AnnImportDownloadSeconds] = strconv.FormatInt(timings.DownloadSeconds, 10)
+	anno[cc.AnnImportConvertSeconds] = strconv.FormatInt(timings.ConvertSeconds, 10)
+	anno[cc.AnnImportResizeSeconds] = strconv.FormatInt(timings.ResizeSeconds, 10)
+}
+
+// setTargetImageAnnotations records the format and compression of the image the importer pod wrote to the PVC, when it
+// is not raw
+func setTargetImageAnnotations(anno map[string]string, pod *v1.Pod) {
+	if pod == nil || len(pod.Status.ContainerStatuses) == 0 || pod.Status.ContainerStatuses[0].State.Terminated == nil {
+		return
+	}
+	matches := targetImageMatch.FindStringSubmatch(pod.Status.ContainerStatuses[0].State.Terminated.Message)
+	if matches == nil {
+		return
+	}
+	info := &util.TargetImageInfo{}
+	if err := json.Unmarshal([]byte(matches[targetImageMatch.SubexpIndex("info")]), info); err != nil {
+		return
+	}
+	if info.Format != "" {
+		anno[cc.AnnImageTargetFormat] = info.Format
+	}
+	if info.Compression != "" {
+		anno[cc.AnnImageTargetCompression] = info.Compression
+	}
+}
+
+// setSourceValidatorsAnnotations records the validators of the source reported by the importer pod of a successful
+// import, so a later import of the PVC can skip the transfer of an unchanged source
+func setSourceValidatorsAnnotations(anno map[string]string, pod *v1.Pod) {
+	if pod == nil || len(pod.Status.ContainerStatuses) == 0 || pod.Status.ContainerStatuses[0].State.Terminated == nil ||
+		pod.Status.ContainerStatuses[0].State.Terminated.ExitCode != 0 {
+		return
+	}
+	message := pod.Status.ContainerStatuses[0].State.Terminated.Message
+	if strings.Contains(message, common.SourceNotModified) {
+		anno[cc.AnnSourceNotModified] = "true"
+	} else {
+		delete(anno, cc.AnnSourceNotModified)
+	}
+	validators := &util.SourceValidators{}
+	if matches := validatorsMatch.FindStringSubmatch(message); matches != nil {
+		if err := json.Unmarshal([]byte(matches[validatorsMatch.SubexpIndex("info")]), validators); err != nil {
+			return
+		}
+	}
+	setOrDelete := func(key, value string) {
+		if value != "" {
+			anno[key] = value
+		} else {
+			delete(anno, key)
+		}
+	}
+	setOrDelete(cc.AnnSourceETag, validators.ETag)
+	setOrDelete(cc.AnnSourceLastModified, validators.LastModified)
+}
+
+// setSourceDigestAnnotation records the digest of the source bytes reported by the importer pod of a successful import.
+// The digest of the previous import is kept when the source was unchanged and not transferred again.
+func setSourceDigestAnnotation(anno map[string]string, pod *v1.Pod) {
+	if pod == nil || len(pod.Status.ContainerStatuses) == 0 || pod.Status.ContainerStatuses[0].State.Terminated == nil ||
+		pod.Status.ContainerStatuses[0].State.Terminated.ExitCode != 0 {
+		return
+	}
+	message := pod.Status.ContainerStatuses[0].State.Terminated.Message
+	if strings.Contains(message, common.SourceNotModified) {
+		return
+	}
+	digest := &util.SourceDigest{}
+	if matches := sourceDigestMatch.FindStringSubmatch(message); matches != nil {
+		if err := json.Unmarshal([]byte(matches[sourceDigestMatch.SubexpIndex("info")]), digest); err != nil {
+			return
+		}
+	}
+	if digest.Algorithm == "" || digest.Value == "" {
+		delete(anno, cc.AnnSourceDigest)
+		return
+	}
+	anno[cc.AnnSourceDigest] = digest.String()
+}
+
 func setBoundConditionFromPVC(anno map[string]string, prefix string, pvc *v1.PersistentVolumeClaim) {
 	switch pvc.Status.Phase {
 	case v1.ClaimBound:
@@ -357,9 +508,23 @@ func setBoundConditionFromPVC(anno map[string]string, prefix string, pvc *v1.Per
 	}
 }
 
+// setScratchBoundConditionFromPVC sets the bound condition of the target PVC from its scratch PVC, so the DataVolume
+// shows when it is waiting for the scratch space rather than for the target PVC.
+func setScratchBoundConditionFromPVC(anno map[string]string, scratchPvc *v1.PersistentVolumeClaim) {
+	setBoundConditionFromPVC(anno, cc.AnnBoundCondition, scratchPvc)
+	switch scratchPvc.Status.Phase {
+	case v1.ClaimPending:
+		anno[cc.AnnBoundConditionMessage] = fmt.Sprintf("Waiting for scratch space PVC %s to be bound", scratchPvc.Name)
+		anno[cc.AnnBoundConditionReason] = cc.ScratchSpacePending
+	case v1.ClaimLost:
+		anno[cc.AnnBoundConditionMessage] = fmt.Sprintf("Scratch space PVC %s lost", scratchPvc.Name)
+		anno[cc.AnnBoundConditionReason] = cc.ScratchSpaceLost
+	}
+}
+
 func getScratchNameFromPod(pod *v1.Pod) (string, bool) {
 	for _, vol := range pod.Spec.Volumes {
-		if vol.Name == cc.ScratchVolName {
+		if vol.Name == cc.ScratchVolName && vol.PersistentVolumeClaim != nil {
 			return vol.PersistentVolumeClaim.ClaimName, true
 		}
 	}
@@ -367,6 +532,16 @@ func getScratchNameFromPod(pod *v1.Pod) (string, bool) {
 	return "", false
 }
 
+// hasScratchEmptyDir returns true if the scratch space of the pod is backed by an emptyDir
+func hasScratchEmptyDir(pod *v1.Pod) bool {
+	for _, vol := range pod.Spec.Volumes {
+		if vol.Name == cc.ScratchVolName {
+			return vol.EmptyDir != nil
+		}
+	}
+	return false
+}
+
 // setPodPvcAnnotations applies PVC annotations on the pod
 func setPodPvcAnnotations(pod *v1.Pod, pvc *v1.PersistentVolumeClaim) {
 	allowedAnnotations := map[string]string{
@@ -388,6 +563,25 @@ func setPodPvcAnnotations(pod *v1.Pod, pvc *v1.PersistentVolumeClaim) {
 	}
 }
 
+// addTargetEmptyCheck makes the worker pod check its adopted target volume is empty before writing to it. The
+// emptyDir survives container restarts, so a worker restarted after writing does not fail the check.
+func addTargetEmptyCheck(pod *v1.Pod) {
+	pod.Spec.Volumes = append(pod.Spec.Volumes, v1.Volume{
+		Name: targetCheckVolName,
+		VolumeSource: v1.VolumeSource{
+			EmptyDir: &v1.EmptyDirVolumeSource{},
+		},
+	})
+	pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, v1.VolumeMount{
+		Name:      targetCheckVolName,
+		MountPath: common.TargetCheckDir,
+	})
+	pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, v1.EnvVar{
+		Name:  common.TargetMustBeEmpty,
+		Value: "true",
+	})
+}
+
 func podUsingPVC(pvc *corev1.PersistentVolumeClaim, readOnly bool) *corev1.Pod {
 	return &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -212,6 +212,235 @@ var _ = Describe("setAnnotationsFromPod", func() {
 		setAnnotationsFromPodWithPrefix(result, testPod, AnnRunningCondition)
 		Expect(result[AnnPreallocationApplied]).To(Equal("true"))
 	})
+
+	It("Should set passthrough status", func() {
+		result := make(map[string]string)
+		testPod := CreateImporterTestPod(CreatePvc("test", metav1.NamespaceDefault, nil, nil), "test", nil)
+		testPod.Status = v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{
+				{
+					State: v1.ContainerState{
+						Terminated: &v1.ContainerStateTerminated{
+							Message: "Import Complete, " + common.PassthroughApplied + "; Image: {\"format\":\"raw\"}",
+							Reason:  "Completed",
+						},
+					},
+				},
+			},
+		}
+		setAnnotationsFromPodWithPrefix(result, testPod, AnnRunningCondition)
+		Expect(result[AnnImportPassthrough]).To(Equal("true"))
+		Expect(result).ToNot(HaveKey(AnnPreallocationApplied))
+	})
+
+	It("Should report the S3 KMS access denied reason", func() {
+		message := "Unable to connect to s3 data source: " + common.S3KMSAccessDeniedMessage + ", the credentials need the kms:Decrypt permission on KMS key \"key\""
+		result := make(map[string]string)
+		testPod := CreateImporterTestPod(CreatePvc("test", metav1.NamespaceDefault, nil, nil), "test", nil)
+		testPod.Status = v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{
+				{
+					State: v1.ContainerState{
+						Terminated: &v1.ContainerStateTerminated{
+							Message: message,
+							Reason:  "Error",
+						},
+					},
+				},
+			},
+		}
+		setAnnotationsFromPodWithPrefix(result, testPod, AnnRunningCondition)
+		Expect(result[AnnRunningCondition]).To(Equal("false"))
+		Expect(result[AnnRunningConditionMessage]).To(Equal(message))
+		Expect(result[AnnRunningConditionReason]).To(Equal(S3KMSAccessDenied))
+	})
+
+	It("Should report the clone checksum mismatch reason of the source pod", func() {
+		message := "Unexpected status code 500: " + common.CloneChecksumMismatchMessage + ", the source disk has SHA-256 aa but the target received bb"
+		result := make(map[string]string)
+		testPod := CreateImporterTestPod(CreatePvc("test", metav1.NamespaceDefault, nil, nil), "test", nil)
+		testPod.Status = v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{
+				{
+					State: v1.ContainerState{
+						Terminated: &v1.ContainerStateTerminated{
+							Message: message,
+							Reason:  "Error",
+						},
+					},
+				},
+			},
+		}
+		setAnnotationsFromPodWithPrefix(result, testPod, AnnSourceRunningCondition)
+		Expect(result[AnnSourceRunningConditionMessage]).To(Equal(message))
+		Expect(result[AnnSourceRunningConditionReason]).To(Equal(CloneChecksumMismatch))
+	})
+
+	table.DescribeTable("Should report the qemu-img failure reason", func(message, reason string) {
+		result := make(map[string]string)
+		testPod := CreateImporterTestPod(CreatePvc("test", metav1.NamespaceDefault, nil, nil), "test", nil)
+		testPod.Status = v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{
+				{
+					State: v1.ContainerState{
+						Terminated: &v1.ContainerStateTerminated{
+							Message: message,
+							Reason:  "Error",
+						},
+					},
+				},
+			},
+		}
+		setAnnotationsFromPodWithPrefix(result, testPod, AnnRunningCondition)
+		Expect(result[AnnRunningCondition]).To(Equal("false"))
+		Expect(result[AnnRunningConditionMessage]).To(Equal(message))
+		Expect(result[AnnRunningConditionReason]).To(Equal(reason))
+	},
+		table.Entry("of an unsupported format",
+			"Unable to process data: Unable to convert source data to target format: "+common.UnsupportedFormatMessage+": the installed qemu-img has no driver for the vhdx format",
+			UnsupportedFormat),
+		table.Entry("of an unavailable qemu-img",
+			"Unable to process data: Unable to convert source data to target format: "+common.QemuImgUnavailableMessage+", cannot convert the image: exec: \"qemu-img\": executable file not found in $PATH",
+			ToolUnavailable),
+	)
+
+	table.DescribeTable("Should tell the scratch space from the target ran out of space", func(message, expectedMessage, reason string) {
+		result := make(map[string]string)
+		testPod := CreateImporterTestPod(CreatePvc("test", metav1.NamespaceDefault, nil, nil), "test", nil)
+		testPod.Status = v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{
+				{
+					State: v1.ContainerState{
+						Terminated: &v1.ContainerStateTerminated{
+							Message: message,
+							Reason:  "Error",
+						},
+					},
+				},
+			},
+		}
+		setAnnotationsFromPodWithPrefix(result, testPod, AnnRunningCondition)
+		Expect(result[AnnRunningConditionMessage]).To(Equal(expectedMessage))
+		Expect(result[AnnRunningConditionReason]).To(Equal(reason))
+	},
+		table.Entry("for the scratch space",
+			"Unable to process data: Unable to transfer source data to scratch space: "+common.ScratchSpaceExhaustedMessage+", scratch space size 1Gi, needed 2Gi: unable to write to file: write /scratch/tmpimage: no space left on device",
+			"Unable to process data: Unable to transfer source data to scratch space: "+common.ScratchSpaceExhaustedMessage+", scratch space size 1Gi, needed 2Gi: unable to write to file: write /scratch/tmpimage: no space left on device",
+			ScratchSpaceExhausted),
+		table.Entry("for the target",
+			"Unable to process data: Unable to transfer source data to target file: unable to write to file: write /data/disk.img: no space left on device",
+			"DataVolume too small to contain image",
+			"Error"),
+	)
+})
+
+var _ = Describe("setImageAnnotations", func() {
+	createTerminatedPod := func(message string) *v1.Pod {
+		testPod := CreateImporterTestPod(CreatePvc("test", metav1.NamespaceDefault, nil, nil), "test", nil)
+		testPod.Status = v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{
+				{
+					State: v1.ContainerState{
+						Terminated: &v1.ContainerStateTerminated{
+							Message: message,
+							Reason:  "Completed",
+						},
+					},
+				},
+			},
+		}
+		return testPod
+	}
+
+	It("Should record the image info reported by the importer", func() {
+		result := make(map[string]string)
+		testPod := createTerminatedPod(`Import Complete; Image: {"Format":"qcow2","VirtualSize":46137344}; VDDK: {"Version":"7.0.3","Host":"esx"}`)
+		setImageAnnotations(result, testPod)
+		Expect(result[AnnImageFormat]).To(Equal("qcow2"))
+		Expect(result[AnnImageVirtualSize]).To(Equal("46137344"))
+		setVddkAnnotations(result, testPod)
+		Expect(result[AnnVddkVersion]).To(Equal("7.0.3"))
+	})
+
+	It("Should not record anything without image info", func() {
+		result := make(map[string]string)
+		setImageAnnotations(result, createTerminatedPod("Import Complete, "+common.PreallocationApplied))
+		setImageAnnotations(result, createTerminatedPod("Import Complete; Image: {invalid}"))
+		Expect(result).To(BeEmpty())
+	})
+
+	It("Should record the import timings reported by the importer", func() {
+		result := make(map[string]string)
+		testPod := createTerminatedPod(`Import Complete; Image: {"Format":"qcow2","VirtualSize":46137344}; Timings: {"downloadSeconds":42,"convertSeconds":7}`)
+		setImportTimingsAnnotations(result, testPod)
+		Expect(result).To(Equal(map[string]string{
+			AnnImportDownloadSeconds: "42",
+			AnnImportConvertSeconds:  "7",
+			AnnImportResizeSeconds:   "0",
+		}))
+		setImageAnnotations(result, testPod)
+		Expect(result[AnnImageFormat]).To(Equal("qcow2"))
+	})
+
+	It("Should record the format and compression of the image written to the target", func() {
+		result := make(map[string]string)
+		setTargetImageAnnotations(result, createTerminatedPod(`Import Complete; Image: {"Format":"raw","VirtualSize":46137344}; Target: {"Format":"qcow2","Compression":"zlib"}`))
+		Expect(result).To(Equal(map[string]string{
+			AnnImageTargetFormat:      "qcow2",
+			AnnImageTargetCompression: "zlib",
+		}))
+		result = make(map[string]string)
+		setTargetImageAnnotations(result, createTerminatedPod(`Import Complete; Image: {"Format":"raw","VirtualSize":46137344}`))
+		Expect(result).To(BeEmpty())
+	})
+
+	It("Should record the validators of the source of a successful import", func() {
+		result := map[string]string{AnnSourceLastModified: "Mon, 02 Jan 2023 15:04:05 GMT"}
+		setSourceValidatorsAnnotations(result, createTerminatedPod(`Import Complete; Image: {"Format":"qcow2","VirtualSize":46137344}; Source: {"ETag":"\"v1\""}`))
+		Expect(result).To(Equal(map[string]string{AnnSourceETag: `"v1"`}))
+
+		By("Recording the content was kept when the source was not modified")
+		setSourceValidatorsAnnotations(result, createTerminatedPod(`Import Complete, `+common.SourceNotModified+`; Source: {"ETag":"\"v1\""}`))
+		Expect(result).To(Equal(map[string]string{AnnSourceETag: `"v1"`, AnnSourceNotModified: "true"}))
+
+		By("Removing the validators once a source without them was imported")
+		setSourceValidatorsAnnotations(result, createTerminatedPod("Import Complete"))
+		Expect(result).To(BeEmpty())
+
+		By("Ignoring a failed import")
+		result = map[string]string{AnnSourceETag: `"v1"`}
+		testPod := createTerminatedPod("Unable to process data: some error")
+		testPod.Status.ContainerStatuses[0].State.Terminated.ExitCode = 1
+		setSourceValidatorsAnnotations(result, testPod)
+		Expect(result).To(Equal(map[string]string{AnnSourceETag: `"v1"`}))
+	})
+
+	It("Should record the digest of the source of a successful import", func() {
+		result := make(map[string]string)
+		setSourceDigestAnnotation(result, createTerminatedPod(`Import Complete; Image: {"Format":"qcow2","VirtualSize":46137344}; Digest: {"Algorithm":"sha256","Value":"a8dd75ecffd4cdd96072d60c2237b448e0c8b2bc94d57f10fdbc8c481d9005b8"}`))
+		Expect(result).To(Equal(map[string]string{AnnSourceDigest: "sha256:a8dd75ecffd4cdd96072d60c2237b448e0c8b2bc94d57f10fdbc8c481d9005b8"}))
+
+		By("Keeping the digest when the source was not modified")
+		setSourceDigestAnnotation(result, createTerminatedPod(`Import Complete, `+common.SourceNotModified))
+		Expect(result).To(HaveKey(AnnSourceDigest))
+
+		By("Ignoring a failed import")
+		testPod := createTerminatedPod("Unable to process data: some error")
+		testPod.Status.ContainerStatuses[0].State.Terminated.ExitCode = 1
+		setSourceDigestAnnotation(result, testPod)
+		Expect(result).To(HaveKey(AnnSourceDigest))
+
+		By("Removing the digest once a source was imported without it")
+		setSourceDigestAnnotation(result, createTerminatedPod("Import Complete"))
+		Expect(result).To(BeEmpty())
+	})
+
+	It("Should not record import timings without them", func() {
+		result := make(map[string]string)
+		setImportTimingsAnnotations(result, createTerminatedPod("Import Complete, "+common.PreallocationApplied))
+		setImportTimingsAnnotations(result, createTerminatedPod("Import Complete; Timings: {invalid}"))
+		Expect(result).To(BeEmpty())
+	})
 })
 
 var _ = Describe("GetPreallocation", func() {
@@ -251,6 +480,67 @@ var _ = Describe("GetPreallocation", func() {
 	})
 })
 
+var _ = Describe("StorageProfile recommendations", func() {
+	storageClassName := "test-class"
+
+	createStorageProfileWithRecommendations := func(preallocation *bool, overhead *cdiv1.Percent) *cdiv1.StorageProfile {
+		storageProfile := MakeEmptyStorageProfileSpec(storageClassName)
+		storageProfile.Status.Preallocation = preallocation
+		storageProfile.Status.FilesystemOverhead = overhead
+		return storageProfile
+	}
+
+	createCDIConfigWithOverhead := func(global cdiv1.Percent, perStorageClass map[string]cdiv1.Percent) *cdiv1.CDIConfig {
+		config := createCDIConfigWithGlobalPreallocation(false)
+		config.Spec.FilesystemOverhead = &cdiv1.FilesystemOverhead{Global: global, StorageClass: perStorageClass}
+		config.Status.FilesystemOverhead = &cdiv1.FilesystemOverhead{
+			Global:       global,
+			StorageClass: map[string]cdiv1.Percent{storageClassName: global},
+		}
+		for name, overhead := range perStorageClass {
+			config.Status.FilesystemOverhead.StorageClass[name] = overhead
+		}
+		return config
+	}
+
+	It("Should prefer the StorageProfile preallocation over the global one", func() {
+		preallocation := true
+		client := CreateClient(createCDIConfigWithGlobalPreallocation(false), CreateStorageClass(storageClassName, nil),
+			createStorageProfileWithRecommendations(&preallocation, nil))
+		dv := createDataVolumeWithStorageClass("test-dv", "test-ns", storageClassName)
+		Expect(GetPreallocation(client, dv)).To(BeTrue())
+
+		dv = createDataVolumeWithStorageClassPreallocation("test-dv", "test-ns", storageClassName, false)
+		Expect(GetPreallocation(client, dv)).To(BeFalse())
+	})
+
+	It("Should use the StorageProfile of the default storage class", func() {
+		preallocation := true
+		client := CreateClient(createCDIConfigWithGlobalPreallocation(false),
+			CreateStorageClass(storageClassName, map[string]string{AnnDefaultStorageClass: "true"}),
+			createStorageProfileWithRecommendations(&preallocation, nil))
+		dv := createDataVolumeWithPreallocation("test-dv", "test-ns", true)
+		dv.Spec.Preallocation = nil
+		Expect(GetPreallocation(client, dv)).To(BeTrue())
+	})
+
+	table.DescribeTable("Should resolve the filesystem overhead", func(perStorageClass map[string]cdiv1.Percent, profileOverhead *cdiv1.Percent, expected cdiv1.Percent) {
+		client := CreateClient(createCDIConfigWithOverhead("0.05", perStorageClass), CreateStorageClass(storageClassName, nil),
+			createStorageProfileWithRecommendations(nil, profileOverhead))
+		overhead, err := GetFilesystemOverheadForStorageClass(client, &storageClassName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(overhead).To(Equal(expected))
+	},
+		table.Entry("from the global setting", nil, nil, cdiv1.Percent("0.05")),
+		table.Entry("from the StorageProfile", nil, percentPtr("0.2"), cdiv1.Percent("0.2")),
+		table.Entry("from the CDIConfig storage class setting", map[string]cdiv1.Percent{storageClassName: "0.1"}, percentPtr("0.2"), cdiv1.Percent("0.1")),
+	)
+})
+
+func percentPtr(percent cdiv1.Percent) *cdiv1.Percent {
+	return &percent
+}
+
 var _ = Describe("ValidateClone", func() {
 	sourcePvc := CreatePvc("testPVC", "default", map[string]string{}, nil)
 	blockVM := corev1.PersistentVolumeBlock
@@ -260,7 +550,7 @@ var _ = Describe("ValidateClone", func() {
 		sourcePvc.Annotations[AnnContentType] = string(cdiv1.DataVolumeKubeVirt)
 		dvSpec := &cdiv1.DataVolumeSpec{ContentType: cdiv1.DataVolumeArchive}
 
-		err := ValidateClone(sourcePvc, dvSpec)
+		err := ValidateClone(sourcePvc, dvSpec, "0")
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring(
 			fmt.Sprintf("Source contentType (%s) and target contentType (%s) do not match", cdiv1.DataVolumeKubeVirt, cdiv1.DataVolumeArchive)))
@@ -278,7 +568,7 @@ var _ = Describe("ValidateClone", func() {
 		}
 		dvSpec := &cdiv1.DataVolumeSpec{Storage: storageSpec}
 
-		err := ValidateClone(sourcePvc, dvSpec)
+		err := ValidateClone(sourcePvc, dvSpec, "0")
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("target resources requests storage size is smaller than the source"))
 	})
@@ -295,7 +585,7 @@ var _ = Describe("ValidateClone", func() {
 		}
 		dvSpec := &cdiv1.DataVolumeSpec{Storage: storageSpec}
 
-		err := ValidateClone(sourcePvc, dvSpec)
+		err := ValidateClone(sourcePvc, dvSpec, "0")
 		Expect(err).ToNot(HaveOccurred())
 	})
 
@@ -305,7 +595,7 @@ var _ = Describe("ValidateClone", func() {
 		storageSpec := &cdiv1.StorageSpec{}
 		dvSpec := &cdiv1.DataVolumeSpec{Storage: storageSpec}
 
-		err := ValidateClone(sourcePvc, dvSpec)
+		err := ValidateClone(sourcePvc, dvSpec, "0")
 		Expect(err).ToNot(HaveOccurred())
 	})
 
@@ -320,7 +610,7 @@ var _ = Describe("ValidateClone", func() {
 		}
 		dvSpec := &cdiv1.DataVolumeSpec{PVC: pvcSpec}
 
-		err := ValidateClone(sourcePvc, dvSpec)
+		err := ValidateClone(sourcePvc, dvSpec, "0")
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("target resources requests storage size is smaller than the source"))
 
@@ -337,9 +627,55 @@ var _ = Describe("ValidateClone", func() {
 		}
 		dvSpec := &cdiv1.DataVolumeSpec{PVC: pvcSpec}
 
-		err := ValidateClone(sourcePvc, dvSpec)
+		err := ValidateClone(sourcePvc, dvSpec, "0")
 		Expect(err).ToNot(HaveOccurred())
 	})
+
+	table.DescribeTable("Should validate the size of the clone converting the volume mode against the disk image (PVC API)",
+		func(sourceVolumeMode, targetVolumeMode corev1.PersistentVolumeMode, targetSize string, valid bool) {
+			sourcePvc.Annotations[AnnContentType] = string(cdiv1.DataVolumeKubeVirt)
+			sourcePvc.Spec.VolumeMode = &sourceVolumeMode
+			pvcSpec := &corev1.PersistentVolumeClaimSpec{
+				VolumeMode: &targetVolumeMode,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse(targetSize),
+					},
+				},
+			}
+			dvSpec := &cdiv1.DataVolumeSpec{PVC: pvcSpec}
+
+			err := ValidateClone(sourcePvc, dvSpec, "0.055")
+			if valid {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("target resources requests storage size is smaller than the disk image of the source"))
+			}
+		},
+		// The disk.img of the 1G source is at most 1G without the 5.5% filesystem overhead
+		table.Entry("rejecting a block target smaller than the disk image", fsVM, blockVM, "1Mi", false),
+		table.Entry("rejecting a block target slightly smaller than the disk image", fsVM, blockVM, "900Mi", false),
+		table.Entry("accepting a block target of the disk image size", fsVM, blockVM, "901Mi", true),
+		table.Entry("accepting a filesystem target of a block source enlarged by the clone controller", blockVM, fsVM, "1Mi", true),
+	)
+
+	It("Should reject the clone converting the volume mode of archive content when the target is smaller (PVC API)", func() {
+		sourcePvc.Annotations[AnnContentType] = string(cdiv1.DataVolumeArchive)
+		sourcePvc.Spec.VolumeMode = &fsVM
+		pvcSpec := &corev1.PersistentVolumeClaimSpec{
+			VolumeMode: &blockVM,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse("1Mi"),
+				},
+			},
+		}
+		dvSpec := &cdiv1.DataVolumeSpec{ContentType: cdiv1.DataVolumeArchive, PVC: pvcSpec}
+
+		err := ValidateClone(sourcePvc, dvSpec, "0")
+		Expect(err).To(HaveOccurred())
+	})
 })
 
 var _ = Describe("HandleFailedPod", func() {
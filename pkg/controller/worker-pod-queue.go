@@ -0,0 +1,230 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+)
+
+const (
+	// activeWorkerPodField indexes the importer and upload server pods which did not complete
+	activeWorkerPodField = "activeWorkerPod"
+	// workerPodOwnerField indexes the importer and upload server pods by the PVC owning them
+	workerPodOwnerField = "workerPodOwner"
+	// workerPodQueuedField indexes the PVCs queued for a worker pod slot
+	workerPodQueuedField = "workerPodQueued"
+)
+
+var (
+	// workerPodQueueRequeueInterval is how often a queued PVC checks whether a worker pod slot is free
+	workerPodQueueRequeueInterval = 5 * time.Second
+	// workerPodSlotReservationTimeout releases the slot of an admitted PVC whose worker pod never shows up in the cache
+	workerPodSlotReservationTimeout = time.Minute
+	// workerPodSlots holds the slots reserved by the import and upload controllers
+	workerPodSlots = newWorkerPodSlotReservations()
+)
+
+// workerPodSlotReservations reserves a slot for each admitted PVC until its worker pod shows up in the cache. The import
+// and upload controllers check the limit concurrently, against a cache which lags behind the pods they create, so
+// without it both could take the last slot.
+type workerPodSlotReservations struct {
+	lock     sync.Mutex
+	reserved map[types.NamespacedName]time.Time
+}
+
+func newWorkerPodSlotReservations() *workerPodSlotReservations {
+	return &workerPodSlotReservations{reserved: make(map[types.NamespacedName]time.Time)}
+}
+
+// CreateWorkerPodQueueIndexes creates the indexes the import and upload controllers use to count the worker pods
+func CreateWorkerPodQueueIndexes(mgr manager.Manager) error {
+	for _, ia := range getWorkerPodQueueIndexArgs() {
+		if err := mgr.GetFieldIndexer().IndexField(context.TODO(), ia.obj, ia.field, ia.extractValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type indexArgs struct {
+	obj          client.Object
+	field        string
+	extractValue client.IndexerFunc
+}
+
+func getWorkerPodQueueIndexArgs() []indexArgs {
+	return []indexArgs{
+		{
+			obj:   &corev1.Pod{},
+			field: activeWorkerPodField,
+			extractValue: func(obj client.Object) []string {
+				if isActiveWorkerPod(obj.(*corev1.Pod)) {
+					return []string{"true"}
+				}
+				return nil
+			},
+		},
+		{
+			obj:   &corev1.Pod{},
+			field: workerPodOwnerField,
+			extractValue: func(obj client.Object) []string {
+				pod := obj.(*corev1.Pod)
+				owner := metav1.GetControllerOf(pod)
+				if !isWorkerPod(pod) || owner == nil || owner.Kind != "PersistentVolumeClaim" {
+					return nil
+				}
+				return []string{types.NamespacedName{Namespace: pod.Namespace, Name: owner.Name}.String()}
+			},
+		},
+		{
+			obj:   &corev1.PersistentVolumeClaim{},
+			field: workerPodQueuedField,
+			extractValue: func(obj client.Object) []string {
+				if cc.IsWorkerPodQueued(obj.(*corev1.PersistentVolumeClaim)) {
+					return []string{"true"}
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// reconcileWorkerPodQueue returns true if the worker pod of the PVC can be created under the MaxParallelWorkerPods limit
+// of the CDIConfig. Otherwise the PVC is queued, reporting it in its running condition, until enough worker pods of the
+// PVCs ahead of it in creation order complete. The limit is read on every call, so changing it applies to the queue.
+func reconcileWorkerPodQueue(c client.Client, pvc *corev1.PersistentVolumeClaim) (bool, error) {
+	limit := cc.GetMaxParallelWorkerPods(c)
+	admitted := true
+	if limit > 0 {
+		var err error
+		if admitted, err = hasFreeWorkerPodSlot(c, pvc, limit); err != nil {
+			return false, err
+		}
+	}
+
+	anno := pvc.GetAnnotations()
+	if admitted {
+		if !cc.IsWorkerPodQueued(pvc) {
+			return true, nil
+		}
+		delete(anno, cc.AnnWorkerPodQueued)
+		delete(anno, cc.AnnRunningCondition)
+		delete(anno, cc.AnnRunningConditionReason)
+		delete(anno, cc.AnnRunningConditionMessage)
+		return true, c.Update(context.TODO(), pvc)
+	}
+
+	message := fmt.Sprintf(cc.MessageWorkerPodSlotsInUse, limit)
+	if cc.IsWorkerPodQueued(pvc) && anno[cc.AnnRunningConditionMessage] == message {
+		return false, nil
+	}
+	cc.AddAnnotation(pvc, cc.AnnWorkerPodQueued, "true")
+	cc.AddAnnotation(pvc, cc.AnnRunningCondition, "false")
+	cc.AddAnnotation(pvc, cc.AnnRunningConditionReason, cc.WorkerPodQueued)
+	cc.AddAnnotation(pvc, cc.AnnRunningConditionMessage, message)
+	return false, c.Update(context.TODO(), pvc)
+}
+
+// hasFreeWorkerPodSlot returns true if fewer than limit worker pods are active or reserved, once the PVCs queued ahead
+// of the PVC got theirs, and reserves a slot for the PVC if so
+func hasFreeWorkerPodSlot(c client.Client, pvc *corev1.PersistentVolumeClaim, limit int32) (bool, error) {
+	workerPodSlots.lock.Lock()
+	defer workerPodSlots.lock.Unlock()
+
+	key := client.ObjectKeyFromObject(pvc)
+	if _, ok := workerPodSlots.reserved[key]; ok {
+		return true, nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := c.List(context.TODO(), pods, client.MatchingFields{activeWorkerPodField: "true"}); err != nil {
+		return false, err
+	}
+	slots := int(limit) - len(pods.Items)
+
+	for reservedKey, reserved := range workerPodSlots.reserved {
+		podCached, err := hasCachedWorkerPod(c, reservedKey)
+		if err != nil {
+			return false, err
+		}
+		if podCached || time.Since(reserved) > workerPodSlotReservationTimeout {
+			delete(workerPodSlots.reserved, reservedKey)
+			continue
+		}
+		slots--
+	}
+
+	pvcs := &corev1.PersistentVolumeClaimList{}
+	if err := c.List(context.TODO(), pvcs, client.MatchingFields{workerPodQueuedField: "true"}); err != nil {
+		return false, err
+	}
+	for i := range pvcs.Items {
+		if isCreatedBefore(&pvcs.Items[i], pvc) {
+			slots--
+		}
+	}
+
+	if slots <= 0 {
+		return false, nil
+	}
+	workerPodSlots.reserved[key] = time.Now()
+	return true, nil
+}
+
+// hasCachedWorkerPod returns true if the cache has the worker pod of the PVC, at which point it is counted as a pod
+func hasCachedWorkerPod(c client.Client, key types.NamespacedName) (bool, error) {
+	pods := &corev1.PodList{}
+	if err := c.List(context.TODO(), pods, client.MatchingFields{workerPodOwnerField: key.String()}); err != nil {
+		return false, err
+	}
+	return len(pods.Items) > 0, nil
+}
+
+// isWorkerPod returns true if the pod is an importer or upload server pod
+func isWorkerPod(pod *corev1.Pod) bool {
+	component := pod.Labels[common.CDIComponentLabel]
+	return component == common.ImporterPodName || component == common.UploadServerCDILabel
+}
+
+// isActiveWorkerPod returns true if the pod is an importer or upload server pod which did not complete. A host-assisted
+// clone counts once, through its upload server pod, as its source pod is only created once that one runs.
+func isActiveWorkerPod(pod *corev1.Pod) bool {
+	return isWorkerPod(pod) && pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed
+}
+
+// isCreatedBefore orders the PVCs by creation, breaking ties by namespace and name
+func isCreatedBefore(pvc, other *corev1.PersistentVolumeClaim) bool {
+	if !pvc.CreationTimestamp.Equal(&other.CreationTimestamp) {
+		return pvc.CreationTimestamp.Before(&other.CreationTimestamp)
+	}
+	if pvc.Namespace != other.Namespace {
+		return pvc.Namespace < other.Namespace
+	}
+	return pvc.Name < other.Name
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+)
+
+var _ = Describe("Worker pod queue", func() {
+	const numPvcs = 4
+	var created time.Time
+
+	newImportPvc := func(index int) *corev1.PersistentVolumeClaim {
+		name := fmt.Sprintf("testPvc%d", index)
+		pvc := cc.CreatePvc(name, "default", map[string]string{cc.AnnEndpoint: testEndPoint, cc.AnnImportPod: "importer-" + name}, nil)
+		pvc.Status.Phase = corev1.ClaimBound
+		pvc.CreationTimestamp = metav1.NewTime(created.Add(time.Duration(index) * time.Second))
+		return pvc
+	}
+
+	createQueueReconciler := func(limit *int32, objects ...runtime.Object) *ImportReconciler {
+		reconciler := createImportReconciler(objects...)
+		reconciler.recorder = record.NewFakeRecorder(100)
+		setMaxParallelWorkerPods(reconciler, limit)
+		return reconciler
+	}
+
+	reconcilePvc := func(reconciler *ImportReconciler, index int) reconcile.Result {
+		res, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: fmt.Sprintf("testPvc%d", index), Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		return res
+	}
+
+	importerPodExists := func(reconciler *ImportReconciler, index int) bool {
+		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: fmt.Sprintf("importer-testPvc%d", index), Namespace: "default"}, &corev1.Pod{})
+		if k8serrors.IsNotFound(err) {
+			return false
+		}
+		Expect(err).ToNot(HaveOccurred())
+		return true
+	}
+
+	getPvc := func(reconciler *ImportReconciler, index int) *corev1.PersistentVolumeClaim {
+		pvc := &corev1.PersistentVolumeClaim{}
+		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: fmt.Sprintf("testPvc%d", index), Namespace: "default"}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		return pvc
+	}
+
+	completeImporterPod := func(reconciler *ImportReconciler, index int) {
+		pod := &corev1.Pod{}
+		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: fmt.Sprintf("importer-testPvc%d", index), Namespace: "default"}, pod)
+		Expect(err).ToNot(HaveOccurred())
+		pod.Status.Phase = corev1.PodSucceeded
+		Expect(reconciler.client.Update(context.TODO(), pod)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		created = time.Now().Add(-time.Hour)
+		workerPodSlots = newWorkerPodSlotReservations()
+	})
+
+	It("Should run exactly the limit of worker pods and queue the rest", func() {
+		var objects []runtime.Object
+		for i := 0; i < numPvcs; i++ {
+			objects = append(objects, newImportPvc(i))
+		}
+		reconciler := createQueueReconciler(pointer.Int32(2), objects...)
+		for i := 0; i < numPvcs; i++ {
+			reconcilePvc(reconciler, i)
+		}
+
+		Expect(importerPodExists(reconciler, 0)).To(BeTrue())
+		Expect(importerPodExists(reconciler, 1)).To(BeTrue())
+		for i := 2; i < numPvcs; i++ {
+			Expect(importerPodExists(reconciler, i)).To(BeFalse())
+			pvc := getPvc(reconciler, i)
+			Expect(cc.IsWorkerPodQueued(pvc)).To(BeTrue())
+			Expect(pvc.Annotations[cc.AnnRunningCondition]).To(Equal("false"))
+			Expect(pvc.Annotations[cc.AnnRunningConditionReason]).To(Equal(cc.WorkerPodQueued))
+			Expect(pvc.Annotations[cc.AnnRunningConditionMessage]).To(Equal(fmt.Sprintf(cc.MessageWorkerPodSlotsInUse, 2)))
+		}
+	})
+
+	It("Should requeue the queued PVCs", func() {
+		reconciler := createQueueReconciler(pointer.Int32(1), newImportPvc(0), newImportPvc(1))
+		reconcilePvc(reconciler, 0)
+		res := reconcilePvc(reconciler, 1)
+		Expect(res.RequeueAfter).To(Equal(workerPodQueueRequeueInterval))
+		Expect(importerPodExists(reconciler, 1)).To(BeFalse())
+	})
+
+	It("Should admit the queued PVCs in creation order as slots free", func() {
+		var objects []runtime.Object
+		for i := 0; i < numPvcs; i++ {
+			objects = append(objects, newImportPvc(i))
+		}
+		reconciler := createQueueReconciler(pointer.Int32(2), objects...)
+		for i := 0; i < numPvcs; i++ {
+			reconcilePvc(reconciler, i)
+		}
+
+		By("Freeing one slot, only the oldest queued PVC proceeds, whatever the reconcile order")
+		completeImporterPod(reconciler, 0)
+		reconcilePvc(reconciler, 3)
+		Expect(importerPodExists(reconciler, 3)).To(BeFalse())
+		reconcilePvc(reconciler, 2)
+		Expect(importerPodExists(reconciler, 2)).To(BeTrue())
+		pvc := getPvc(reconciler, 2)
+		Expect(cc.IsWorkerPodQueued(pvc)).To(BeFalse())
+		Expect(pvc.Annotations).ToNot(HaveKey(cc.AnnRunningConditionReason))
+		reconcilePvc(reconciler, 3)
+		Expect(importerPodExists(reconciler, 3)).To(BeFalse())
+
+		By("Freeing another slot, the last queued PVC proceeds")
+		completeImporterPod(reconciler, 1)
+		reconcilePvc(reconciler, 3)
+		Expect(importerPodExists(reconciler, 3)).To(BeTrue())
+		Expect(cc.IsWorkerPodQueued(getPvc(reconciler, 3))).To(BeFalse())
+	})
+
+	It("Should apply a changed limit to the queued PVCs", func() {
+		reconciler := createQueueReconciler(pointer.Int32(1), newImportPvc(0), newImportPvc(1), newImportPvc(2))
+		for i := 0; i < 3; i++ {
+			reconcilePvc(reconciler, i)
+		}
+		Expect(importerPodExists(reconciler, 1)).To(BeFalse())
+
+		By("Raising the limit")
+		setMaxParallelWorkerPods(reconciler, pointer.Int32(2))
+		reconcilePvc(reconciler, 2)
+		Expect(importerPodExists(reconciler, 2)).To(BeFalse())
+		Expect(getPvc(reconciler, 2).Annotations[cc.AnnRunningConditionMessage]).To(Equal(fmt.Sprintf(cc.MessageWorkerPodSlotsInUse, 2)))
+		reconcilePvc(reconciler, 1)
+		Expect(importerPodExists(reconciler, 1)).To(BeTrue())
+
+		By("Removing the limit")
+		setMaxParallelWorkerPods(reconciler, nil)
+		reconcilePvc(reconciler, 2)
+		Expect(importerPodExists(reconciler, 2)).To(BeTrue())
+	})
+
+	It("Should count the upload server pods toward the limit", func() {
+		uploadPvc := cc.CreatePvc("uploadPvc", "default", map[string]string{cc.AnnUploadRequest: "", AnnUploadPod: "uploader"}, nil)
+		uploadPvc.CreationTimestamp = metav1.NewTime(created)
+		uploadReconciler := createUploadReconciler(uploadPvc, newImportPvc(1))
+		uploadReconciler.recorder = record.NewFakeRecorder(100)
+		cdiConfig := &cdiv1.CDIConfig{}
+		Expect(uploadReconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+		cdiConfig.Spec.MaxParallelWorkerPods = pointer.Int32(1)
+		Expect(uploadReconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+
+		_, err := uploadReconciler.reconcilePVC(uploadReconciler.log, uploadPvc, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(uploadReconciler.client.Get(context.TODO(), types.NamespacedName{Name: "uploader", Namespace: "default"}, &corev1.Pod{})).To(Succeed())
+
+		importPvc := &corev1.PersistentVolumeClaim{}
+		Expect(uploadReconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, importPvc)).To(Succeed())
+		admitted, err := reconcileWorkerPodQueue(uploadReconciler.client, importPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(admitted).To(BeFalse())
+	})
+
+	It("Should reserve the slot of an admitted PVC until its worker pod is cached", func() {
+		reconciler := createQueueReconciler(pointer.Int32(1), newImportPvc(0), newImportPvc(1))
+		admitted, err := reconcileWorkerPodQueue(reconciler.client, getPvc(reconciler, 0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(admitted).To(BeTrue())
+
+		By("Checking another PVC before the worker pod of the first one is created")
+		admitted, err = reconcileWorkerPodQueue(reconciler.client, getPvc(reconciler, 1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(admitted).To(BeFalse())
+
+		By("Admitting the first PVC again while it holds the reservation")
+		admitted, err = reconcileWorkerPodQueue(reconciler.client, getPvc(reconciler, 0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(admitted).To(BeTrue())
+
+		By("Releasing the reservation once the worker pod is cached, counting the pod instead")
+		reconcilePvc(reconciler, 0)
+		Expect(importerPodExists(reconciler, 0)).To(BeTrue())
+		completeImporterPod(reconciler, 0)
+		reconcilePvc(reconciler, 1)
+		Expect(importerPodExists(reconciler, 1)).To(BeTrue())
+		Expect(workerPodSlots.reserved).To(HaveLen(1))
+	})
+
+	It("Should release a reservation whose worker pod never shows up", func() {
+		reconciler := createQueueReconciler(pointer.Int32(1), newImportPvc(0), newImportPvc(1))
+		admitted, err := reconcileWorkerPodQueue(reconciler.client, getPvc(reconciler, 0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(admitted).To(BeTrue())
+		workerPodSlots.reserved[types.NamespacedName{Namespace: "default", Name: "testPvc0"}] = time.Now().Add(-2 * workerPodSlotReservationTimeout)
+
+		admitted, err = reconcileWorkerPodQueue(reconciler.client, getPvc(reconciler, 1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(admitted).To(BeTrue())
+		Expect(workerPodSlots.reserved).To(HaveKey(types.NamespacedName{Namespace: "default", Name: "testPvc1"}))
+		Expect(workerPodSlots.reserved).ToNot(HaveKey(types.NamespacedName{Namespace: "default", Name: "testPvc0"}))
+	})
+
+	It("Should not count the completed or non worker pods", func() {
+		importer := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{common.CDIComponentLabel: common.ImporterPodName}}}
+		Expect(isActiveWorkerPod(importer)).To(BeTrue())
+		importer.Status.Phase = corev1.PodFailed
+		Expect(isActiveWorkerPod(importer)).To(BeFalse())
+		source := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{common.CDIComponentLabel: common.ClonerSourcePodName}}}
+		Expect(isActiveWorkerPod(source)).To(BeFalse())
+	})
+})
+
+func setMaxParallelWorkerPods(reconciler *ImportReconciler, limit *int32) {
+	cdiConfig := &cdiv1.CDIConfig{}
+	Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+	cdiConfig.Spec.MaxParallelWorkerPods = limit
+	Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+}
@@ -14,12 +14,22 @@ import (
 const (
 	// HonorWaitForFirstConsumer - if enabled will not schedule worker pods on a storage with WaitForFirstConsumer binding mode
 	HonorWaitForFirstConsumer = "HonorWaitForFirstConsumer"
+
+	// SkipSnapshotSourceReadCheck - if enabled will not require read access to the source VolumeSnapshot when cloning
+	// from a snapshot with the implicit permissions. Only meant for migrating existing workflows.
+	SkipSnapshotSourceReadCheck = "SkipSnapshotSourceReadCheck"
+
+	// SharedSnapshotClone - if enabled concurrent clones of the same source PVC restore from a single shared snapshot,
+	// instead of copying or snapshotting the source once per clone
+	SharedSnapshotClone = "SharedSnapshotClone"
 )
 
 // FeatureGates is a util for determining whether an optional feature is enabled or not.
 type FeatureGates interface {
 	// HonorWaitForFirstConsumerEnabled - see the HonorWaitForFirstConsumer const
 	HonorWaitForFirstConsumerEnabled() (bool, error)
+	// SharedSnapshotCloneEnabled - see the SharedSnapshotClone const
+	SharedSnapshotCloneEnabled() (bool, error)
 }
 
 // CDIConfigFeatureGates is a util for determining whether an optional feature is enabled or not.
@@ -59,3 +69,8 @@ func (f *CDIConfigFeatureGates) getConfig() ([]string, error) {
 func (f *CDIConfigFeatureGates) HonorWaitForFirstConsumerEnabled() (bool, error) {
 	return f.isFeatureGateEnabled(HonorWaitForFirstConsumer)
 }
+
+// SharedSnapshotCloneEnabled - see the SharedSnapshotClone const
+func (f *CDIConfigFeatureGates) SharedSnapshotCloneEnabled() (bool, error) {
+	return f.isFeatureGateEnabled(SharedSnapshotClone)
+}
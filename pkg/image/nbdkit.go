@@ -2,6 +2,7 @@ package image
 
 import (
 	"bufio"
+	"crypto/tls"
 	"fmt"
 	"strings"
 
@@ -15,6 +16,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/util"
 )
 
 const (
@@ -82,12 +84,52 @@ func NewNbdkit(plugin NbdkitPlugin, nbdkitPidFile string) *Nbdkit {
 	}
 }
 
+// NbdkitCurlTLS is the TLS configuration of the curl plugin connection to the source
+type NbdkitCurlTLS struct {
+	// MinVersion is the minimal TLS version, one of the tls.Version constants
+	MinVersion uint16
+	// Ciphers are the allowed cipher suites in OpenSSL naming, the TLS 1.3 ones start with TLS_
+	Ciphers []string
+}
+
+var curlSSLVersions = map[uint16]string{
+	tls.VersionTLS10: "tlsv1.0",
+	tls.VersionTLS11: "tlsv1.1",
+	tls.VersionTLS12: "tlsv1.2",
+	tls.VersionTLS13: "tlsv1.3",
+}
+
+// pluginArgs returns the curl plugin arguments setting the minimal TLS version and the cipher suites
+func (t *NbdkitCurlTLS) pluginArgs() []string {
+	var args []string
+	if version, ok := curlSSLVersions[t.MinVersion]; ok {
+		args = append(args, "ssl-version="+version)
+	}
+	var ciphers, tls13Ciphers []string
+	for _, cipher := range t.Ciphers {
+		if strings.HasPrefix(cipher, "TLS_") {
+			tls13Ciphers = append(tls13Ciphers, cipher)
+		} else {
+			ciphers = append(ciphers, cipher)
+		}
+	}
+	if len(ciphers) > 0 {
+		args = append(args, "ssl-cipher-list="+strings.Join(ciphers, ":"))
+	}
+	if len(tls13Ciphers) > 0 {
+		args = append(args, "tls13-ciphers="+strings.Join(tls13Ciphers, ":"))
+	}
+	return args
+}
+
 // NewNbdkitCurl creates a new Nbdkit instance with the curl plugin
-func NewNbdkitCurl(nbdkitPidFile, user, password, certDir, socket string, extraHeaders, secretExtraHeaders []string) NbdkitOperation {
+func NewNbdkitCurl(nbdkitPidFile, user, password, certDir, socket string, extraHeaders, secretExtraHeaders []string, tlsConfig *NbdkitCurlTLS) NbdkitOperation {
 	var pluginArgs []string
 	var redactArgs []string
 	args := []string{"-r"}
-	pluginArgs = append(pluginArgs, fmt.Sprintf("header=User-Agent: %s", defaultUserAgent))
+	if !hasUserAgentHeader(extraHeaders) && !hasUserAgentHeader(secretExtraHeaders) {
+		pluginArgs = append(pluginArgs, fmt.Sprintf("header=User-Agent: %s", defaultUserAgent))
+	}
 	if user != "" {
 		pluginArgs = append(pluginArgs, "user="+user)
 	}
@@ -97,6 +139,9 @@ func NewNbdkitCurl(nbdkitPidFile, user, password, certDir, socket string, extraH
 	if certDir != "" {
 		pluginArgs = append(pluginArgs, fmt.Sprintf("cainfo=%s/%s", certDir, "tls.crt"))
 	}
+	if tlsConfig != nil {
+		pluginArgs = append(pluginArgs, tlsConfig.pluginArgs()...)
+	}
 	for _, header := range extraHeaders {
 		pluginArgs = append(pluginArgs, fmt.Sprintf("header=%s", header))
 	}
@@ -105,6 +150,11 @@ func NewNbdkitCurl(nbdkitPidFile, user, password, certDir, socket string, extraH
 	for _, header := range secretExtraHeaders {
 		redactArgs = append(redactArgs, fmt.Sprintf("header=%s", header))
 	}
+	if len(secretExtraHeaders) > 0 {
+		// curl sends the headers to every host it is redirected to, unlike the basic auth, so the secret headers
+		// would leak to another host
+		pluginArgs = append(pluginArgs, "followlocation=false")
+	}
 	n := &Nbdkit{
 		NbdPidFile: nbdkitPidFile,
 		plugin:     NbdkitCurlPlugin,
@@ -118,6 +168,17 @@ func NewNbdkitCurl(nbdkitPidFile, user, password, certDir, socket string, extraH
 	return n
 }
 
+// hasUserAgentHeader returns true if one of the headers is a User-Agent header, which replaces the default one
+func hasUserAgentHeader(headers []string) bool {
+	for _, header := range headers {
+		name := strings.SplitN(header, ":", 2)[0]
+		if strings.EqualFold(strings.TrimSpace(name), "User-Agent") {
+			return true
+		}
+	}
+	return false
+}
+
 // NewNbdkitVddk creates a new Nbdkit instance with the vddk plugin
 func NewNbdkitVddk(nbdkitPidFile, socket, server, username, password, thumbprint, moref string) (NbdkitOperation, error) {
 
@@ -196,6 +257,37 @@ func (n *Nbdkit) getSourceArg(s string) string {
 	return source
 }
 
+// quoteArgs returns the quoted nbdkit arguments to log, with the password and the secret arguments redacted, and the
+// credentials of the source URL stripped
+func (n *Nbdkit) quoteArgs(args []string) []string {
+	isRedacted := func(arg string) bool {
+		for _, value := range n.redactArgs {
+			if value == arg {
+				return true
+			}
+		}
+		return false
+	}
+
+	quotedArgs := make([]string, len(args))
+	for index, value := range args {
+		if strings.HasPrefix(value, "password=") {
+			quotedArgs[index] = "'password=*****'"
+		} else if n.plugin == NbdkitCurlPlugin && strings.HasPrefix(value, "url=") {
+			quotedArgs[index] = "'url=" + util.RedactSourceURL(strings.TrimPrefix(value, "url=")) + "'"
+		} else if isRedacted(value) {
+			if strings.HasPrefix(value, "header=") {
+				quotedArgs[index] = "'header=/secret redacted/'"
+			} else {
+				quotedArgs[index] = "'/secret redacted/'"
+			}
+		} else {
+			quotedArgs[index] = "'" + value + "'"
+		}
+	}
+	return quotedArgs
+}
+
 // StartNbdkit starts nbdkit process
 func (n *Nbdkit) StartNbdkit(source string) error {
 	var err error
@@ -219,30 +311,7 @@ func (n *Nbdkit) StartNbdkit(source string) error {
 	argsNbdkit = append(argsNbdkit, n.redactArgs...)
 	argsNbdkit = append(argsNbdkit, n.getSourceArg(source))
 
-	isRedacted := func(arg string) bool {
-		for _, value := range n.redactArgs {
-			if value == arg {
-				return true
-			}
-		}
-		return false
-	}
-
-	quotedArgs := make([]string, len(argsNbdkit))
-	for index, value := range argsNbdkit {
-		if strings.HasPrefix(value, "password=") {
-			quotedArgs[index] = "'password=*****'"
-		} else if isRedacted(value) {
-			if strings.HasPrefix(value, "header=") {
-				quotedArgs[index] = "'header=/secret redacted/'"
-			} else {
-				quotedArgs[index] = "'/secret redacted/'"
-			}
-		} else {
-			quotedArgs[index] = "'" + value + "'"
-		}
-	}
-	klog.V(3).Infof("Start nbdkit with: %v", quotedArgs)
+	klog.V(3).Infof("Start nbdkit with: %v", n.quoteArgs(argsNbdkit))
 
 	n.c = exec.Command("nbdkit", argsNbdkit...)
 	var stdout io.ReadCloser
@@ -284,7 +353,8 @@ func watchNbdLog(output *bufio.Reader) {
 
 	scanner := bufio.NewScanner(output)
 	for scanner.Scan() {
-		line := scanner.Text()
+		// The log may hold the URL of the source, like the error of the curl plugin reading it
+		line := util.RedactSourceURLs(scanner.Text())
 		logLine := fmt.Sprintf("Log line from nbdkit: %s", line)
 		klog.Info(logLine)
 		f.WriteString(logLine)
@@ -392,7 +462,7 @@ func (n *Nbdkit) validatePlugin() error {
 type mockNbdkit struct{}
 
 // NewMockNbdkitCurl creates a mock nbdkit curl plugin for testing
-func NewMockNbdkitCurl(nbdkitPidFile, user, password, certDir, socket string, extraHeaders, secretExtraHeaders []string) NbdkitOperation {
+func NewMockNbdkitCurl(nbdkitPidFile, user, password, certDir, socket string, extraHeaders, secretExtraHeaders []string, tlsConfig *NbdkitCurlTLS) NbdkitOperation {
 	return &mockNbdkit{}
 }
 
@@ -0,0 +1,141 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	qcow2HeaderSize = 72
+	// qcow2OffsetMask masks out the flag bits of L1 and standard L2 table entries.
+	qcow2OffsetMask = 0x00fffffffffffe00
+	// qcow2CompressedFlag marks an L2 entry that points to a compressed cluster.
+	qcow2CompressedFlag = uint64(1) << 62
+	// maxQcow2ClusterBits is the largest cluster size qemu supports (2MiB).
+	maxQcow2ClusterBits = 21
+)
+
+// ErrQcow2Truncated is returned when a qcow2 image references data beyond the end of the file.
+var ErrQcow2Truncated = errors.New("source image appears truncated/corrupt")
+
+var qcow2Magic = []byte{'Q', 'F', 'I', 0xfb}
+
+// qcow2Header contains the fields of the qcow2 header needed to find the image metadata.
+type qcow2Header struct {
+	Magic                 [4]byte
+	Version               uint32
+	BackingFileOffset     uint64
+	BackingFileSize       uint32
+	ClusterBits           uint32
+	Size                  uint64
+	CryptMethod           uint32
+	L1Size                uint32
+	L1TableOffset         uint64
+	RefcountTableOffset   uint64
+	RefcountTableClusters uint32
+	NbSnapshots           uint32
+	SnapshotsOffset       uint64
+}
+
+// CheckQcow2Truncation verifies that all the metadata tables and data clusters referenced by a qcow2 image are
+// within the file. Files that are not qcow2 images are ignored.
+func CheckQcow2Truncation(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.Wrapf(err, "could not open image %s", path)
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return errors.Wrapf(err, "could not stat image %s", path)
+	}
+	return checkQcow2Truncation(f, fi.Size())
+}
+
+func checkQcow2Truncation(r io.ReaderAt, fileSize int64) error {
+	buf := make([]byte, qcow2HeaderSize)
+	n, err := r.ReadAt(buf, 0)
+	if err != nil && err != io.EOF {
+		return errors.Wrap(err, "could not read qcow2 header")
+	}
+	if n < len(qcow2Magic) || !bytes.HasPrefix(buf, qcow2Magic) {
+		return nil
+	}
+	if n < qcow2HeaderSize {
+		return errors.Wrap(ErrQcow2Truncated, "qcow2 header is incomplete")
+	}
+	var header qcow2Header
+	if err := binary.Read(bytes.NewReader(buf), binary.BigEndian, &header); err != nil {
+		return errors.Wrap(err, "could not parse qcow2 header")
+	}
+	if header.ClusterBits < 9 || header.ClusterBits > maxQcow2ClusterBits {
+		return errors.Wrapf(ErrQcow2Truncated, "invalid cluster bits %d", header.ClusterBits)
+	}
+	clusterSize := int64(1) << header.ClusterBits
+
+	if !inFile(int64(header.RefcountTableOffset), int64(header.RefcountTableClusters)*clusterSize, fileSize) {
+		return errors.Wrap(ErrQcow2Truncated, "refcount table is beyond the end of the file")
+	}
+	l1TableSize := int64(header.L1Size) * 8
+	if !inFile(int64(header.L1TableOffset), l1TableSize, fileSize) {
+		return errors.Wrap(ErrQcow2Truncated, "L1 table is beyond the end of the file")
+	}
+	l1Table := make([]byte, l1TableSize)
+	if _, err := r.ReadAt(l1Table, int64(header.L1TableOffset)); err != nil {
+		return errors.Wrap(err, "could not read L1 table")
+	}
+
+	// Compressed cluster descriptors use the low bits of the entry for the host offset
+	compressedOffsetMask := uint64(1)<<(62-(header.ClusterBits-8)) - 1
+	l2Table := make([]byte, clusterSize)
+	for i := 0; i < len(l1Table); i += 8 {
+		l2Offset := int64(binary.BigEndian.Uint64(l1Table[i:]) & qcow2OffsetMask)
+		if l2Offset == 0 {
+			continue
+		}
+		if !inFile(l2Offset, clusterSize, fileSize) {
+			return errors.Wrapf(ErrQcow2Truncated, "L2 table at offset %d is beyond the end of the file", l2Offset)
+		}
+		if _, err := r.ReadAt(l2Table, l2Offset); err != nil {
+			return errors.Wrapf(err, "could not read L2 table at offset %d", l2Offset)
+		}
+		for j := 0; j < len(l2Table); j += 8 {
+			entry := binary.BigEndian.Uint64(l2Table[j:])
+			var dataOffset int64
+			if entry&qcow2CompressedFlag != 0 {
+				dataOffset = int64(entry & compressedOffsetMask)
+			} else {
+				dataOffset = int64(entry & qcow2OffsetMask)
+			}
+			// The last data cluster is not necessarily written in full, only its start has to be in the file
+			if dataOffset != 0 && dataOffset >= fileSize {
+				return errors.Wrapf(ErrQcow2Truncated, "data cluster at offset %d is beyond the end of the file", dataOffset)
+			}
+		}
+	}
+	return nil
+}
+
+func inFile(offset, length, fileSize int64) bool {
+	return offset >= 0 && length >= 0 && offset+length <= fileSize
+}
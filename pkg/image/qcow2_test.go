@@ -0,0 +1,76 @@
+package image
+
+import (
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+const testImagesDir = "../../tests/images"
+
+var _ = Describe("Qcow2 truncation check", func() {
+	var tmpDir string
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "qcow2")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	// createTruncatedImage copies the source image, keeping only the first size bytes
+	createTruncatedImage := func(source string, size int64) string {
+		in, err := os.Open(filepath.Join(testImagesDir, source))
+		Expect(err).ToNot(HaveOccurred())
+		defer in.Close()
+		path := filepath.Join(tmpDir, source)
+		out, err := os.Create(path)
+		Expect(err).ToNot(HaveOccurred())
+		defer out.Close()
+		_, err = io.CopyN(out, in, size)
+		Expect(err).ToNot(HaveOccurred())
+		return path
+	}
+
+	It("should accept a complete qcow2 image", func() {
+		Expect(CheckQcow2Truncation(filepath.Join(testImagesDir, "cirros-qcow2.img"))).To(Succeed())
+	})
+
+	It("should ignore images that are not qcow2", func() {
+		Expect(CheckQcow2Truncation(filepath.Join(testImagesDir, "cirros.raw"))).To(Succeed())
+		Expect(CheckQcow2Truncation(filepath.Join(testImagesDir, "tinyCore.iso"))).To(Succeed())
+	})
+
+	table.DescribeTable("should reject a truncated qcow2 image", func(size int64) {
+		path := createTruncatedImage("cirros-qcow2.img", size)
+		err := CheckQcow2Truncation(path)
+		Expect(err).To(HaveOccurred())
+		Expect(errors.Cause(err)).To(Equal(ErrQcow2Truncated))
+	},
+		table.Entry("with an incomplete header", int64(40)),
+		table.Entry("with missing metadata tables", int64(65536)),
+		table.Entry("with missing data clusters", int64(6*1024*1024)),
+		table.Entry("with the last cluster missing", int64(12648448)),
+	)
+
+	It("should fail validation of a truncated qcow2 image", func() {
+		path := createTruncatedImage("cirros-qcow2.img", 6*1024*1024)
+		imageURL, err := url.Parse(path)
+		Expect(err).ToNot(HaveOccurred())
+		replaceExecFunction(mockExecFunction(goodValidateJSON, "", expectedLimits, "info", "--output=json", path), func() {
+			err := Validate(imageURL, 42949672960)
+			Expect(err).To(HaveOccurred())
+			Expect(errors.Cause(err)).To(Equal(ErrQcow2Truncated))
+			Expect(err.Error()).To(ContainSubstring("source image appears truncated/corrupt"))
+		})
+	})
+})
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"os/exec"
 	"regexp"
 	"strconv"
 	"strings"
@@ -43,7 +44,15 @@ const (
 	networkTimeoutSecs = 3600    //max is 10000
 	maxMemory          = 1 << 30 //value from OpenStack Nova
 	maxCPUSecs         = 30      //value from OpenStack Nova
-	matcherString      = "\\((\\d?\\d\\.\\d\\d)\\/100%\\)"
+	matcherString      = "\\((\\d?\\d?\\d\\.\\d\\d)\\/100%\\)"
+	// conversionNoProgress is the conversion progress while qemu-img has not reported any progress yet
+	conversionNoProgress = -1
+	// defaultNbdPort is the port of an NBD server when the URL doesn't have one
+	defaultNbdPort = "10809"
+	// luksKeySecretID is the id of the qemu-img object holding the passphrase of a LUKS target
+	luksKeySecretID = "luks-key"
+	// nbdTLSCredsID is the id of the qemu-img object holding the TLS credentials of an NBD over TLS source
+	nbdTLSCredsID = "nbd-tls-creds"
 )
 
 // ImgInfo contains the virtual image information.
@@ -56,12 +65,17 @@ type ImgInfo struct {
 	VirtualSize int64 `json:"virtual-size"`
 	// ActualSize is the size of the qcow2 image
 	ActualSize int64 `json:"actual-size"`
+	// ClusterSize is the size of the clusters of a qcow2 image
+	ClusterSize int64 `json:"cluster-size,omitempty"`
 }
 
 // QEMUOperations defines the interface for executing qemu subprocesses
 type QEMUOperations interface {
 	ConvertToRawStream(*url.URL, string, bool) error
+	ConvertToQcow2Stream(*url.URL, string, string, int64) error
+	ConvertToLuksStream(*url.URL, string, string) error
 	Resize(string, resource.Quantity, bool) error
+	ResizeQcow2(string, resource.Quantity) error
 	Info(url *url.URL) (*ImgInfo, error)
 	Validate(*url.URL, int64) error
 	CreateBlankImage(string, resource.Quantity, bool) error
@@ -76,6 +90,10 @@ var (
 	qemuInfoLimits   = &system.ProcessLimitValues{AddressSpaceLimit: maxMemory, CPUTimeLimit: maxCPUSecs}
 	qemuIterface     = NewQEMUOperations()
 	re               = regexp.MustCompile(matcherString)
+	unknownDriverRe  = regexp.MustCompile(`Unknown driver '([^']+)'`)
+
+	// ErrQemuImgNotFound is returned when qemu-img is not installed
+	ErrQemuImgNotFound = errors.New("qemu-img not found")
 
 	progress = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -84,6 +102,13 @@ var (
 		},
 		[]string{"ownerUID"},
 	)
+	conversionProgress = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: monitoring.MetricOptsList[monitoring.ConversionProgress].Name,
+			Help: monitoring.MetricOptsList[monitoring.ConversionProgress].Help,
+		},
+		[]string{"ownerUID"},
+	)
 	ownerUID                    string
 	convertPreallocationMethods = [][]string{
 		{"-o", "preallocation=falloc"},
@@ -106,6 +131,13 @@ func init() {
 			klog.Errorf("Unable to create prometheus progress counter")
 		}
 	}
+	if err := prometheus.Register(conversionProgress); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			conversionProgress = are.ExistingCollector.(*prometheus.GaugeVec)
+		} else {
+			klog.Errorf("Unable to create prometheus conversion progress gauge")
+		}
+	}
 	ownerUID, _ = util.ParseEnvVar(common.OwnerUID, false)
 }
 
@@ -114,17 +146,19 @@ func NewQEMUOperations() QEMUOperations {
 	return &qemuOperations{}
 }
 
-func convertToRaw(src, dest string, preallocate bool) error {
-	args := []string{"convert", "-t", "writeback", "-p", "-O", "raw", src, dest}
+func convertToRaw(src, dest string, preallocate bool, srcOpts ...string) error {
+	args := append([]string{"convert"}, srcOpts...)
+	args = append(args, "-t", "writeback", "-p", "-O", "raw", src, dest)
 	var err error
 
+	setConversionProgress(conversionNoProgress)
 	if preallocate {
 		err = addPreallocation(args, convertPreallocationMethods, func(args []string) ([]byte, error) {
-			return qemuExecFunction(nil, reportProgress, "qemu-img", args...)
+			return qemuExecFunction(nil, reportConversionProgress, "qemu-img", args...)
 		})
 	} else {
 		klog.V(3).Infof("Running qemu-img convert with args: %v", args)
-		_, err = qemuExecFunction(nil, reportProgress, "qemu-img", args...)
+		_, err = qemuExecFunction(nil, reportConversionProgress, "qemu-img", args...)
 	}
 	if err != nil {
 		os.Remove(dest)
@@ -139,10 +173,106 @@ func convertToRaw(src, dest string, preallocate bool) error {
 }
 
 func (o *qemuOperations) ConvertToRawStream(url *url.URL, dest string, preallocate bool) error {
-	if len(url.Scheme) > 0 && url.Scheme != "nbd+unix" {
+	if len(url.Scheme) > 0 && url.Scheme != "nbd+unix" && !isNbdURL(url) {
 		return fmt.Errorf("not valid schema %s", url.Scheme)
 	}
-	return convertToRaw(url.String(), dest, preallocate)
+	srcOpts, src := sourceArgs(url)
+	return convertToRaw(src, dest, preallocate, srcOpts...)
+}
+
+func convertToQcow2(src, dest, compression string, clusterSize int64, srcOpts ...string) error {
+	args := append([]string{"convert"}, srcOpts...)
+	args = append(args, "-t", "writeback", "-p", "-O", "qcow2")
+	if compression != "" {
+		args = append(args, "-c", "-o", "compression_type="+compression)
+	}
+	if clusterSize > 0 {
+		args = append(args, "-o", "cluster_size="+strconv.FormatInt(clusterSize, 10))
+	}
+	args = append(args, src, dest)
+
+	setConversionProgress(conversionNoProgress)
+	klog.V(3).Infof("Running qemu-img convert with args: %v", args)
+	if _, err := qemuExecFunction(nil, reportConversionProgress, "qemu-img", args...); err != nil {
+		os.Remove(dest)
+		errorMsg := "could not convert image to qcow2"
+		if nbdkitLog, err := os.ReadFile(common.NbdkitLogPath); err == nil {
+			errorMsg += " " + string(nbdkitLog)
+		}
+		return errors.Wrap(err, errorMsg)
+	}
+
+	return nil
+}
+
+// ConvertToQcow2Stream converts the image to a qcow2 image, with its clusters compressed with the given compression
+// type when it is not empty, and of the given size in bytes, the qemu-img default when 0. qemu-img writes the
+// allocated clusters in order, so the image is defragmented and its unallocated and zeroed clusters are dropped.
+func (o *qemuOperations) ConvertToQcow2Stream(url *url.URL, dest, compression string, clusterSize int64) error {
+	if len(url.Scheme) > 0 && url.Scheme != "nbd+unix" && !isNbdURL(url) {
+		return fmt.Errorf("not valid schema %s", url.Scheme)
+	}
+	srcOpts, src := sourceArgs(url)
+	return convertToQcow2(src, dest, compression, clusterSize, srcOpts...)
+}
+
+func convertToLuks(src, dest, keyFile string, srcOpts ...string) error {
+	args := append([]string{"convert"}, srcOpts...)
+	args = append(args, "--object", fmt.Sprintf("secret,id=%s,file=%s,format=raw", luksKeySecretID, keyFile))
+	args = append(args, "-t", "writeback", "-p", "-O", "luks", "-o", "key-secret="+luksKeySecretID, src, dest)
+
+	setConversionProgress(conversionNoProgress)
+	klog.V(3).Infof("Running qemu-img convert with args: %v", args)
+	if _, err := qemuExecFunction(nil, reportConversionProgress, "qemu-img", args...); err != nil {
+		// the target is a block device, which is not removed as the file targets are
+		errorMsg := "could not convert image to luks"
+		if nbdkitLog, err := os.ReadFile(common.NbdkitLogPath); err == nil {
+			errorMsg += " " + string(nbdkitLog)
+		}
+		return errors.Wrap(err, errorMsg)
+	}
+
+	return nil
+}
+
+// ConvertToLuksStream converts the image to a raw image encrypted in a LUKS container, with the passphrase read from
+// keyFile. The LUKS payload spans the rest of the target when it is a block device.
+func (o *qemuOperations) ConvertToLuksStream(url *url.URL, dest, keyFile string) error {
+	if len(url.Scheme) > 0 && url.Scheme != "nbd+unix" && !isNbdURL(url) {
+		return fmt.Errorf("not valid schema %s", url.Scheme)
+	}
+	srcOpts, src := sourceArgs(url)
+	return convertToLuks(src, dest, keyFile, srcOpts...)
+}
+
+// isNbdURL returns true if the url points to an NBD export served over TCP, with or without TLS
+func isNbdURL(url *url.URL) bool {
+	return url.Scheme == "nbd" || url.Scheme == "nbds"
+}
+
+// sourceArgs returns the qemu-img options and the filename to read the image at url. qemu-img has no URL syntax for
+// NBD over TLS, so an nbds URL is passed as image options using the TLS credentials mounted in ImporterNbdCertDir.
+func sourceArgs(url *url.URL) ([]string, string) {
+	if url.Scheme != "nbds" {
+		return nil, url.String()
+	}
+	port := url.Port()
+	if port == "" {
+		port = defaultNbdPort
+	}
+	// The format is left to probe, as for the other sources, so only the protocol layer is set
+	opts := []string{
+		"file.driver=nbd",
+		"file.server.type=inet",
+		"file.server.host=" + url.Hostname(),
+		"file.server.port=" + port,
+	}
+	if export := strings.TrimPrefix(url.Path, "/"); export != "" {
+		opts = append(opts, "file.export="+strings.ReplaceAll(export, ",", ",,"))
+	}
+	opts = append(opts, "file.tls-creds="+nbdTLSCredsID)
+	tlsCreds := fmt.Sprintf("tls-creds-x509,id=%s,endpoint=client,dir=%s", nbdTLSCredsID, common.ImporterNbdCertDir)
+	return []string{"--object", tlsCreds, "--image-opts"}, strings.Join(opts, ",")
 }
 
 // convertQuantityToQemuSize translates a quantity string into a Qemu compatible string.
@@ -176,11 +306,19 @@ func (o *qemuOperations) Resize(image string, size resource.Quantity, preallocat
 	return nil
 }
 
+// ResizeQcow2 resizes the virtual size of the given qcow2 image
+func (o *qemuOperations) ResizeQcow2(image string, size resource.Quantity) error {
+	if _, err := qemuExecFunction(nil, nil, "qemu-img", "resize", "-f", "qcow2", image, convertQuantityToQemuSize(size)); err != nil {
+		return errors.Wrapf(err, "Error resizing image %s", image)
+	}
+	return nil
+}
+
 func checkOutputQemuImgInfo(output []byte, image string) (*ImgInfo, error) {
 	var info ImgInfo
 	err := json.Unmarshal(output, &info)
 	if err != nil {
-		klog.Errorf("Invalid JSON:\n%s\n", string(output))
+		klog.Errorf("Invalid JSON:\n%s\n", util.RedactSourceURLs(string(output)))
 		return nil, errors.Wrapf(err, "Invalid json for image %s", image)
 	}
 	return &info, nil
@@ -193,10 +331,15 @@ func Info(url *url.URL) (*ImgInfo, error) {
 }
 
 func (o *qemuOperations) Info(url *url.URL) (*ImgInfo, error) {
-	if len(url.Scheme) > 0 && url.Scheme != "nbd+unix" && url.Scheme != "file" {
+	if len(url.Scheme) > 0 && url.Scheme != "nbd+unix" && url.Scheme != "file" && !isNbdURL(url) {
 		return nil, fmt.Errorf("not valid schema %s", url.Scheme)
 	}
-	output, err := qemuExecFunction(qemuInfoLimits, nil, "qemu-img", "info", "--output=json", url.String())
+	srcOpts, src := sourceArgs(url)
+	args := append([]string{"info", "--output=json"}, srcOpts...)
+	output, err := qemuExecFunction(qemuInfoLimits, nil, "qemu-img", append(args, src)...)
+	if errors.Is(err, exec.ErrNotFound) {
+		return nil, errors.Wrap(ErrQemuImgNotFound, err.Error())
+	}
 	if err != nil {
 		errorMsg := fmt.Sprintf("%s, %s", output, err.Error())
 		if nbdkitLog, err := os.ReadFile(common.NbdkitLogPath); err == nil {
@@ -207,6 +350,18 @@ func (o *qemuOperations) Info(url *url.URL) (*ImgInfo, error) {
 	return checkOutputQemuImgInfo(output, url.String())
 }
 
+// UnknownDriver returns the image format of a qemu-img failure on an image format it has no driver for, empty for
+// other errors
+func UnknownDriver(err error) string {
+	if err == nil {
+		return ""
+	}
+	if match := unknownDriverRe.FindStringSubmatch(err.Error()); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
 func isSupportedFormat(value string) bool {
 	switch value {
 	case "raw", "qcow2", "vmdk", "vdi", "vpc", "vhdx":
@@ -234,11 +389,60 @@ func checkIfURLIsValid(info *ImgInfo, availableSize int64, image string) error {
 }
 
 func (o *qemuOperations) Validate(url *url.URL, availableSize int64) error {
+	isLocal := url.Scheme == "" || url.Scheme == "file"
 	info, err := o.Info(url)
 	if err != nil {
+		if isLocal {
+			if vhdxInfo, _ := GetVhdxInfo(url.Path); vhdxInfo != nil {
+				return errors.Wrapf(err, "qemu-img is unable to read VHDX image %s, it may lack VHDX support", url.String())
+			}
+		}
 		return err
 	}
-	return checkIfURLIsValid(info, availableSize, url.String())
+	// Streamed sources can only be checked once they have been downloaded to a local file
+	if isLocal {
+		if err := checkVhdxInfo(info, url.Path); err != nil {
+			return err
+		}
+	}
+	if err := checkIfURLIsValid(info, availableSize, url.String()); err != nil {
+		return err
+	}
+	if info.Format == "qcow2" && isLocal {
+		if _, err := os.Stat(url.Path); err == nil {
+			if err := CheckQcow2Truncation(url.Path); err != nil {
+				return errors.Wrapf(err, "Image %s is invalid", url.String())
+			}
+		}
+	}
+	return nil
+}
+
+// checkVhdxInfo cross checks the qemu-img information of a VHDX image with its metadata. A qemu-img without VHDX support
+// would silently treat the image as raw. The virtual size from the metadata is used to check the target size.
+func checkVhdxInfo(info *ImgInfo, path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return nil
+	}
+	vhdxInfo, err := GetVhdxInfo(path)
+	if err != nil {
+		return errors.Wrapf(err, "Image %s is invalid", path)
+	}
+	if vhdxInfo == nil {
+		return nil
+	}
+	if info.Format != "vhdx" {
+		return errors.Errorf("qemu-img reports format %s for VHDX image %s, it may lack VHDX support", info.Format, path)
+	}
+	if vhdxInfo.Differencing {
+		return errors.Errorf("VHDX image %s is a differencing image, which is not supported", path)
+	}
+	klog.V(1).Infof("VHDX image %s: dynamic %t, block size %d, virtual size %d", path, vhdxInfo.Dynamic, vhdxInfo.BlockSize, vhdxInfo.VirtualSize)
+	if vhdxInfo.VirtualSize != info.VirtualSize {
+		klog.Warningf("qemu-img reports virtual size %d for VHDX image %s, using virtual size %d from its metadata", info.VirtualSize, path, vhdxInfo.VirtualSize)
+		info.VirtualSize = vhdxInfo.VirtualSize
+	}
+	return nil
 }
 
 // ConvertToRawStream converts an http accessible image to raw format without locally caching the image
@@ -251,13 +455,22 @@ func Validate(url *url.URL, availableSize int64) error {
 	return qemuIterface.Validate(url, availableSize)
 }
 
-func reportProgress(line string) {
+// parseProgress returns the percentage of a qemu-img progress line, false if the line does not report progress
+func parseProgress(line string) (float64, bool) {
 	// (45.34/100%)
 	matches := re.FindStringSubmatch(line)
-	if len(matches) == 2 && ownerUID != "" {
-		klog.V(1).Info(matches[1])
-		// Don't need to check for an error, the regex made sure its a number we can parse.
-		v, _ := strconv.ParseFloat(matches[1], 64)
+	if len(matches) != 2 {
+		return 0, false
+	}
+	// Don't need to check for an error, the regex made sure its a number we can parse.
+	v, _ := strconv.ParseFloat(matches[1], 64)
+	return v, true
+}
+
+func reportProgress(line string) {
+	v, ok := parseProgress(line)
+	if ok && ownerUID != "" {
+		klog.V(1).Info(v)
 		metric := &dto.Metric{}
 		err := progress.WithLabelValues(ownerUID).Write(metric)
 		if err == nil && v > 0 && v > *metric.Counter.Value {
@@ -266,6 +479,21 @@ func reportProgress(line string) {
 	}
 }
 
+// reportConversionProgress reports the progress of a qemu-img conversion, both to the overall progress and to the
+// conversion progress
+func reportConversionProgress(line string) {
+	reportProgress(line)
+	if v, ok := parseProgress(line); ok {
+		setConversionProgress(v)
+	}
+}
+
+func setConversionProgress(v float64) {
+	if ownerUID != "" {
+		conversionProgress.WithLabelValues(ownerUID).Set(v)
+	}
+}
+
 // CreateBlankImage creates empty raw image
 func CreateBlankImage(dest string, size resource.Quantity, preallocate bool) error {
 	klog.V(1).Infof("creating raw image with size %s, preallocation %v", size.String(), preallocate)
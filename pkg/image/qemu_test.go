@@ -16,9 +16,12 @@ limitations under the License.
 package image
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"reflect"
 	"strings"
@@ -188,6 +191,271 @@ var _ = Describe("Convert to Raw", func() {
 	})
 })
 
+var _ = Describe("Convert to qcow2", func() {
+	var tmpDir string
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp(os.TempDir(), "qemutestdest")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	It("should convert to a qcow2 image without compression", func() {
+		dest := filepath.Join(tmpDir, "dest")
+		replaceExecFunction(mockExecFunctionStrict("", "", nil, "convert", "-t", "writeback", "-p", "-O", "qcow2", "/somefile/somewhere", dest), func() {
+			ep, err := url.Parse("/somefile/somewhere")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(NewQEMUOperations().ConvertToQcow2Stream(ep, dest, "", 0)).To(Succeed())
+		})
+	})
+
+	It("should compress the clusters of the qcow2 image with the requested compression type", func() {
+		dest := filepath.Join(tmpDir, "dest")
+		replaceExecFunction(mockExecFunctionStrict("", "", nil, "convert", "-t", "writeback", "-p", "-O", "qcow2", "-c", "-o", "compression_type=zstd", "/somefile/somewhere", dest), func() {
+			ep, err := url.Parse("/somefile/somewhere")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(NewQEMUOperations().ConvertToQcow2Stream(ep, dest, "zstd", 0)).To(Succeed())
+		})
+	})
+
+	It("should return conversion error if exec function returns error", func() {
+		dest := filepath.Join(tmpDir, "dest")
+		replaceExecFunction(mockExecFunction("", "exit 1", nil, "convert"), func() {
+			ep, err := url.Parse("/somefile/somewhere")
+			Expect(err).NotTo(HaveOccurred())
+			err = NewQEMUOperations().ConvertToQcow2Stream(ep, dest, "zlib", 0)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("could not convert image to qcow2"))
+		})
+	})
+
+	It("should produce a smaller compressed qcow2 image that is still readable", func() {
+		if _, err := exec.LookPath("qemu-img"); err != nil {
+			Skip("qemu-img is not available")
+		}
+		content := bytes.Repeat([]byte("compressible content "), 1024*1024)
+		src := filepath.Join(tmpDir, "disk.raw")
+		Expect(os.WriteFile(src, content, 0644)).To(Succeed())
+		srcURL, err := url.Parse(src)
+		Expect(err).NotTo(HaveOccurred())
+
+		uncompressed := filepath.Join(tmpDir, "uncompressed.qcow2")
+		Expect(NewQEMUOperations().ConvertToQcow2Stream(srcURL, uncompressed, "", 0)).To(Succeed())
+		for _, compression := range []string{"zlib", "zstd"} {
+			compressed := filepath.Join(tmpDir, compression+".qcow2")
+			Expect(NewQEMUOperations().ConvertToQcow2Stream(srcURL, compressed, compression, 0)).To(Succeed())
+			compressedStat, err := os.Stat(compressed)
+			Expect(err).NotTo(HaveOccurred())
+			uncompressedStat, err := os.Stat(uncompressed)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(compressedStat.Size()).To(BeNumerically("<", uncompressedStat.Size()/4))
+
+			compressedURL, err := url.Parse(compressed)
+			Expect(err).NotTo(HaveOccurred())
+			info, err := NewQEMUOperations().Info(compressedURL)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Format).To(Equal("qcow2"))
+			Expect(info.VirtualSize).To(Equal(int64(len(content))))
+			raw := filepath.Join(tmpDir, compression+".raw")
+			Expect(NewQEMUOperations().ConvertToRawStream(compressedURL, raw, false)).To(Succeed())
+			converted, err := os.ReadFile(raw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bytes.Equal(converted, content)).To(BeTrue())
+		}
+	})
+
+	It("should set the cluster size of the qcow2 image", func() {
+		dest := filepath.Join(tmpDir, "dest")
+		replaceExecFunction(mockExecFunctionStrict("", "", nil, "convert", "-t", "writeback", "-p", "-O", "qcow2", "-o", "cluster_size=2097152", "/somefile/somewhere", dest), func() {
+			ep, err := url.Parse("/somefile/somewhere")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(NewQEMUOperations().ConvertToQcow2Stream(ep, dest, "", 2*1024*1024)).To(Succeed())
+		})
+	})
+
+	It("should defragment and compact a fragmented qcow2 image", func() {
+		if _, err := exec.LookPath("qemu-img"); err != nil {
+			Skip("qemu-img is not available")
+		}
+		const mi = 1024 * 1024
+		qemuImg := func(args ...string) []byte {
+			out, err := exec.Command("qemu-img", args...).CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(out))
+			return out
+		}
+		// checkImage returns the fragmented and allocated clusters reported by qemu-img check, and the file size
+		checkImage := func(image string) (int64, int64, int64) {
+			// qemu-img check exits with an error for leaked clusters, which are still reported
+			out, _ := exec.Command("qemu-img", "check", "--output=json", image).Output()
+			check := struct {
+				FragmentedClusters int64 `json:"fragmented-clusters"`
+				AllocatedClusters  int64 `json:"allocated-clusters"`
+			}{}
+			Expect(json.Unmarshal(out, &check)).To(Succeed(), string(out))
+			stat, err := os.Stat(image)
+			Expect(err).NotTo(HaveOccurred())
+			return check.FragmentedClusters, check.AllocatedClusters, stat.Size()
+		}
+		toURL := func(path string) *url.URL {
+			u, err := url.Parse(path)
+			Expect(err).NotTo(HaveOccurred())
+			return u
+		}
+
+		By("Writing the end of the disk to the base image")
+		content := make([]byte, 16*mi)
+		copy(content[12*mi:], bytes.Repeat([]byte("disk end data   "), 4*mi/16))
+		endRaw := filepath.Join(tmpDir, "end.raw")
+		Expect(os.WriteFile(endRaw, content, 0644)).To(Succeed())
+		base := filepath.Join(tmpDir, "base.qcow2")
+		Expect(NewQEMUOperations().ConvertToQcow2Stream(toURL(endRaw), base, "", 0)).To(Succeed())
+
+		By("Committing an overlay writing the start of the disk and zeroing a part of its end")
+		copy(content, bytes.Repeat([]byte("disk start data "), 2*mi/16))
+		copy(content[14*mi:], make([]byte, 2*mi))
+		fullRaw := filepath.Join(tmpDir, "full.raw")
+		Expect(os.WriteFile(fullRaw, content, 0644)).To(Succeed())
+		overlay := filepath.Join(tmpDir, "overlay.qcow2")
+		qemuImg("convert", "-f", "raw", "-O", "qcow2", "-B", base, "-o", "backing_fmt=qcow2", fullRaw, overlay)
+		qemuImg("commit", overlay)
+		fragmentedBefore, allocatedBefore, sizeBefore := checkImage(base)
+		Expect(fragmentedBefore).To(BeNumerically(">", 0))
+
+		By("Compacting the base image")
+		compacted := filepath.Join(tmpDir, "compacted.qcow2")
+		Expect(NewQEMUOperations().ConvertToQcow2Stream(toURL(base), compacted, "", 0)).To(Succeed())
+		fragmentedAfter, allocatedAfter, sizeAfter := checkImage(compacted)
+		Expect(fragmentedAfter).To(BeZero())
+		Expect(allocatedAfter).To(BeNumerically("<=", allocatedBefore))
+		Expect(sizeAfter).To(BeNumerically("<", sizeBefore))
+
+		By("Compacting the base image with larger clusters")
+		largeClusters := filepath.Join(tmpDir, "large-clusters.qcow2")
+		Expect(NewQEMUOperations().ConvertToQcow2Stream(toURL(base), largeClusters, "", 2*mi)).To(Succeed())
+		fragmentedAfter, _, _ = checkImage(largeClusters)
+		Expect(fragmentedAfter).To(BeZero())
+		info, err := NewQEMUOperations().Info(toURL(largeClusters))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.ClusterSize).To(Equal(int64(2 * mi)))
+
+		for _, image := range []string{compacted, largeClusters} {
+			raw := filepath.Join(tmpDir, filepath.Base(image)+".raw")
+			Expect(NewQEMUOperations().ConvertToRawStream(toURL(image), raw, false)).To(Succeed())
+			converted, err := os.ReadFile(raw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bytes.Equal(converted, content)).To(BeTrue())
+		}
+	})
+})
+
+var _ = Describe("Convert to LUKS", func() {
+	var tmpDir string
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp(os.TempDir(), "qemutestluks")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	It("should convert into a LUKS container keyed by the passphrase file", func() {
+		dest := filepath.Join(tmpDir, "dest")
+		replaceExecFunction(mockExecFunctionStrict("", "", nil, "convert", "--object", "secret,id=luks-key,file=/encryption-key/passphrase,format=raw", "-t", "writeback", "-p", "-O", "luks", "-o", "key-secret=luks-key", "/somefile/somewhere", dest), func() {
+			ep, err := url.Parse("/somefile/somewhere")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(NewQEMUOperations().ConvertToLuksStream(ep, dest, "/encryption-key/passphrase")).To(Succeed())
+		})
+	})
+
+	It("should return conversion error if exec function returns error", func() {
+		replaceExecFunction(mockExecFunction("", "exit 1", nil, "convert"), func() {
+			ep, err := url.Parse("/somefile/somewhere")
+			Expect(err).NotTo(HaveOccurred())
+			err = NewQEMUOperations().ConvertToLuksStream(ep, filepath.Join(tmpDir, "dest"), "/encryption-key/passphrase")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("could not convert image to luks"))
+		})
+	})
+
+	It("should write a LUKS header to the target", func() {
+		if _, err := exec.LookPath("qemu-img"); err != nil {
+			Skip("qemu-img is not available")
+		}
+		src := filepath.Join(tmpDir, "disk.raw")
+		Expect(os.WriteFile(src, bytes.Repeat([]byte("plain content "), 64*1024), 0644)).To(Succeed())
+		keyFile := filepath.Join(tmpDir, "passphrase")
+		Expect(os.WriteFile(keyFile, []byte("secret"), 0600)).To(Succeed())
+		srcURL, err := url.Parse(src)
+		Expect(err).NotTo(HaveOccurred())
+
+		dest := filepath.Join(tmpDir, "disk.luks")
+		Expect(NewQEMUOperations().ConvertToLuksStream(srcURL, dest, keyFile)).To(Succeed())
+		encrypted, err := os.ReadFile(dest)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(encrypted[:6]).To(Equal([]byte{'L', 'U', 'K', 'S', 0xba, 0xbe}))
+		Expect(bytes.Contains(encrypted, []byte("plain content"))).To(BeFalse())
+	})
+})
+
+var _ = Describe("NBD source", func() {
+	tlsArgs := []string{"--object", "tls-creds-x509,id=nbd-tls-creds,endpoint=client,dir=/nbd-certs", "--image-opts"}
+
+	It("should convert from an NBD URL", func() {
+		replaceExecFunction(mockExecFunctionStrict("", "", nil, "convert", "-t", "writeback", "-p", "-O", "raw", "nbd://nbd.example.com:10810/disk0", "dest"), func() {
+			ep, err := url.Parse("nbd://nbd.example.com:10810/disk0")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(NewQEMUOperations().ConvertToRawStream(ep, "dest", false)).To(Succeed())
+		})
+	})
+
+	It("should convert from an NBD over TLS URL with the TLS credentials", func() {
+		args := append([]string{"convert"}, tlsArgs...)
+		args = append(args, "-t", "writeback", "-p", "-O", "qcow2",
+			"file.driver=nbd,file.server.type=inet,file.server.host=nbd.example.com,file.server.port=10809,file.export=disk,,0,file.tls-creds=nbd-tls-creds", "dest")
+		replaceExecFunction(mockExecFunctionStrict("", "", nil, args...), func() {
+			ep, err := url.Parse("nbds://nbd.example.com/disk,0")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(NewQEMUOperations().ConvertToQcow2Stream(ep, "dest", "", 0)).To(Succeed())
+		})
+	})
+
+	It("should add preallocation before the TLS credentials", func() {
+		args := append([]string{"convert", "-o", "preallocation=falloc"}, tlsArgs...)
+		args = append(args, "-t", "writeback", "-p", "-O", "raw",
+			"file.driver=nbd,file.server.type=inet,file.server.host=10.0.0.1,file.server.port=10810,file.tls-creds=nbd-tls-creds", "dest")
+		replaceExecFunction(mockExecFunctionStrict("", "", nil, args...), func() {
+			ep, err := url.Parse("nbds://10.0.0.1:10810")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(NewQEMUOperations().ConvertToRawStream(ep, "dest", true)).To(Succeed())
+		})
+	})
+
+	It("should get the info of an NBD over TLS export with the TLS credentials", func() {
+		args := append([]string{"info", "--output=json"}, tlsArgs...)
+		args = append(args, "file.driver=nbd,file.server.type=inet,file.server.host=nbd.example.com,file.server.port=10809,file.export=disk0,file.tls-creds=nbd-tls-creds")
+		replaceExecFunction(mockExecFunctionStrict(goodValidateJSON, "", expectedLimits, args...), func() {
+			ep, err := url.Parse("nbds://nbd.example.com/disk0")
+			Expect(err).NotTo(HaveOccurred())
+			info, err := NewQEMUOperations().Info(ep)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Format).To(Equal("qcow2"))
+		})
+	})
+
+	It("should reject other remote URLs", func() {
+		ep, err := url.Parse("http://nbd.example.com/disk0")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(NewQEMUOperations().ConvertToRawStream(ep, "dest", false)).To(MatchError("not valid schema http"))
+	})
+})
+
 var _ = Describe("Resize", func() {
 	It("Should complete successfully if qemu-img resize succeeds", func() {
 		quantity, err := resource.ParseQuantity("10Gi")
@@ -200,6 +468,15 @@ var _ = Describe("Resize", func() {
 		})
 	})
 
+	It("Should resize a qcow2 image without probing its format", func() {
+		quantity, err := resource.ParseQuantity("10Gi")
+		Expect(err).NotTo(HaveOccurred())
+		size := convertQuantityToQemuSize(quantity)
+		replaceExecFunction(mockExecFunctionStrict("", "", nil, "resize", "-f", "qcow2", "image", size), func() {
+			Expect(NewQEMUOperations().ResizeQcow2("image", quantity)).To(Succeed())
+		})
+	})
+
 	It("Should fail if qemu-img resize fails", func() {
 		quantity, err := resource.ParseQuantity("10Gi")
 		Expect(err).NotTo(HaveOccurred())
@@ -213,6 +490,36 @@ var _ = Describe("Resize", func() {
 	})
 })
 
+var _ = Describe("Unsupported formats", func() {
+	It("Should report qemu-img is not installed", func() {
+		execNotFound := func(limits *system.ProcessLimitValues, f func(string), cmd string, args ...string) ([]byte, error) {
+			return nil, errors.Wrap(&exec.Error{Name: "qemu-img", Err: exec.ErrNotFound}, "Couldn't start qemu-img")
+		}
+		replaceExecFunction(execNotFound, func() {
+			_, err := Info(&url.URL{Path: "myimage.vhdx"})
+			Expect(errors.Is(err, ErrQemuImgNotFound)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("executable file not found"))
+		})
+	})
+
+	It("Should not report qemu-img is not installed when it fails", func() {
+		replaceExecFunction(mockExecFunction("", "qemu-img: Could not open 'myimage.vhdx': Unknown driver 'vhdx'", qemuInfoLimits), func() {
+			_, err := Info(&url.URL{Path: "myimage.vhdx"})
+			Expect(err).To(HaveOccurred())
+			Expect(errors.Is(err, ErrQemuImgNotFound)).To(BeFalse())
+			Expect(UnknownDriver(err)).To(Equal("vhdx"))
+		})
+	})
+
+	table.DescribeTable("Should find the format qemu-img has no driver for", func(err error, format string) {
+		Expect(UnknownDriver(err)).To(Equal(format))
+	},
+		table.Entry("in an unknown driver failure", errors.New("qemu-img: Could not open 'disk.vhdx': Unknown driver 'vhdx', exit status 1"), "vhdx"),
+		table.Entry("not in another failure", errors.New("qemu-img: Could not open 'disk.img': Image is not in qcow2 format"), ""),
+		table.Entry("not without failure", nil, ""),
+	)
+})
+
 var _ = Describe("Validate", func() {
 	imageName, _ := url.Parse("myimage.qcow2")
 
@@ -279,6 +586,44 @@ var _ = Describe("Report Progress", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(*metric.Counter.Value).To(Equal(float64(0)))
 	})
+
+	table.DescribeTable("Parse qemu-img progress output", func(line string, expected float64, expectedOk bool) {
+		v, ok := parseProgress(line)
+		Expect(ok).To(Equal(expectedOk))
+		Expect(v).To(Equal(expected))
+	},
+		table.Entry("start", "    (0.00/100%)", float64(0), true),
+		table.Entry("in progress", "    (45.34/100%)\r", 45.34, true),
+		table.Entry("complete", "    (100.00/100%)\r", float64(100), true),
+		table.Entry("no progress", "qemu-img: warning: some warning", float64(0), false),
+		table.Entry("empty", "", float64(0), false),
+	)
+
+	It("Should report the conversion progress, indeterminate until qemu-img reports it", func() {
+		conversionProgress = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "conversion_progress_percent",
+				Help: "The qemu-img conversion progress in percentage",
+			},
+			[]string{"ownerUID"},
+		)
+		metric := &dto.Metric{}
+		replaceExecFunction(func(_ *system.ProcessLimitValues, f func(string), _ string, _ ...string) ([]byte, error) {
+			err := conversionProgress.WithLabelValues(ownerUID).Write(metric)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*metric.Gauge.Value).To(Equal(float64(conversionNoProgress)))
+			f("    (45.34/100%)")
+			return nil, nil
+		}, func() {
+			Expect(convertToRaw("source", "dest", false)).To(Succeed())
+		})
+		err := conversionProgress.WithLabelValues(ownerUID).Write(metric)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*metric.Gauge.Value).To(Equal(45.34))
+		err = progress.WithLabelValues(ownerUID).Write(metric)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*metric.Counter.Value).To(Equal(45.34))
+	})
 })
 
 var _ = Describe("quantity to qemu", func() {
@@ -0,0 +1,259 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	vhdxHeaderOffset1      = 64 * 1024
+	vhdxHeaderOffset2      = 128 * 1024
+	vhdxHeaderSize         = 4 * 1024
+	vhdxRegionTableOffset1 = 192 * 1024
+	vhdxRegionTableOffset2 = 256 * 1024
+	vhdxRegionTableSize    = 64 * 1024
+	vhdxRegionEntrySize    = 32
+	vhdxMetadataEntrySize  = 32
+	// vhdxMaxMetadataEntries is the maximum number of entries of the metadata table
+	vhdxMaxMetadataEntries = 2047
+
+	// vhdxLeaveBlocksAllocated is set in the file parameters of fixed images, which have all their blocks allocated
+	vhdxLeaveBlocksAllocated = 1
+	// vhdxHasParent is set in the file parameters of differencing images
+	vhdxHasParent = 2
+)
+
+var (
+	vhdxFileSignature     = []byte("vhdxfile")
+	vhdxHeaderSignature   = []byte("head")
+	vhdxRegionSignature   = []byte("regi")
+	vhdxMetadataSignature = []byte("metadata")
+
+	vhdxMetadataRegionGUID   = vhdxGUID(0x8B7CA206, 0x4790, 0x4B9A, [8]byte{0xB8, 0xFE, 0x57, 0x5F, 0x05, 0x0F, 0x88, 0x6E})
+	vhdxFileParametersGUID   = vhdxGUID(0xCAA16737, 0xFA36, 0x4D43, [8]byte{0xB3, 0xB6, 0x33, 0xF0, 0xAA, 0x44, 0xE7, 0x6B})
+	vhdxVirtualDiskSizeGUID  = vhdxGUID(0x2FA54224, 0xCD1B, 0x4876, [8]byte{0xB2, 0x11, 0x5D, 0xBE, 0xD8, 0x3B, 0xF4, 0xB8})
+	vhdxLogicalSectorGUID    = vhdxGUID(0x8141BF1D, 0xA96F, 0x4709, [8]byte{0xBA, 0x47, 0xF2, 0x33, 0xA8, 0xFA, 0xAB, 0x5F})
+	vhdxCastagnoliCRC32Table = crc32.MakeTable(crc32.Castagnoli)
+)
+
+// ErrVhdxInvalid is returned when the headers or metadata of a VHDX image can't be parsed.
+var ErrVhdxInvalid = errors.New("invalid VHDX image")
+
+// VhdxInfo contains the VHDX image information read from its metadata.
+type VhdxInfo struct {
+	// VirtualSize is the disk size of the image which will be read by vm
+	VirtualSize int64
+	// BlockSize is the size of the blocks allocated in the image
+	BlockSize uint32
+	// LogicalSectorSize is the sector size of the virtual disk
+	LogicalSectorSize uint32
+	// Dynamic is true for dynamically expanding images, which only allocate the blocks written to
+	Dynamic bool
+	// Differencing is true for images depending on a parent image
+	Differencing bool
+}
+
+// vhdxRegionEntry is an entry of the VHDX region table.
+type vhdxRegionEntry struct {
+	GUID       [16]byte
+	FileOffset uint64
+	Length     uint32
+	Required   uint32
+}
+
+// vhdxMetadataEntry is an entry of the VHDX metadata table.
+type vhdxMetadataEntry struct {
+	ItemID   [16]byte
+	Offset   uint32
+	Length   uint32
+	Flags    uint32
+	Reserved uint32
+}
+
+// GetVhdxInfo reads the headers and metadata of a VHDX image. Returns nil if the file is not a VHDX image.
+func GetVhdxInfo(path string) (*VhdxInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not open image %s", path)
+	}
+	defer f.Close()
+	return getVhdxInfo(f)
+}
+
+// ReadVhdxInfo reads the headers and metadata of a VHDX image from r. Returns nil if r is not a VHDX image.
+func ReadVhdxInfo(r io.ReaderAt) (*VhdxInfo, error) {
+	return getVhdxInfo(r)
+}
+
+func getVhdxInfo(r io.ReaderAt) (*VhdxInfo, error) {
+	signature := make([]byte, len(vhdxFileSignature))
+	if _, err := r.ReadAt(signature, 0); err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "could not read VHDX file type identifier")
+	}
+	if !bytes.Equal(signature, vhdxFileSignature) {
+		return nil, nil
+	}
+	if err := checkVhdxHeaders(r); err != nil {
+		return nil, err
+	}
+	metadataOffset, metadataLength, err := findVhdxMetadataRegion(r)
+	if err != nil {
+		return nil, err
+	}
+	return readVhdxMetadata(r, metadataOffset, metadataLength)
+}
+
+// checkVhdxHeaders makes sure at least one of the two VHDX headers is valid. The header only locates the log, which
+// is not needed to read the metadata.
+func checkVhdxHeaders(r io.ReaderAt) error {
+	for _, offset := range []int64{vhdxHeaderOffset1, vhdxHeaderOffset2} {
+		if _, err := readVhdxStructure(r, offset, vhdxHeaderSize, vhdxHeaderSignature); err == nil {
+			return nil
+		}
+	}
+	return errors.Wrap(ErrVhdxInvalid, "no valid header")
+}
+
+// findVhdxMetadataRegion returns the location of the metadata region from the first valid region table.
+func findVhdxMetadataRegion(r io.ReaderAt) (int64, int64, error) {
+	for _, offset := range []int64{vhdxRegionTableOffset1, vhdxRegionTableOffset2} {
+		buf, err := readVhdxStructure(r, offset, vhdxRegionTableSize, vhdxRegionSignature)
+		if err != nil {
+			continue
+		}
+		entryCount := int(binary.LittleEndian.Uint32(buf[8:]))
+		if 16+entryCount*vhdxRegionEntrySize > len(buf) {
+			continue
+		}
+		for i := 0; i < entryCount; i++ {
+			var entry vhdxRegionEntry
+			entryBuf := buf[16+i*vhdxRegionEntrySize:]
+			if err := binary.Read(bytes.NewReader(entryBuf), binary.LittleEndian, &entry); err != nil {
+				return 0, 0, errors.Wrap(err, "could not parse VHDX region table")
+			}
+			if entry.GUID == vhdxMetadataRegionGUID {
+				return int64(entry.FileOffset), int64(entry.Length), nil
+			}
+		}
+		return 0, 0, errors.Wrap(ErrVhdxInvalid, "no metadata region")
+	}
+	return 0, 0, errors.Wrap(ErrVhdxInvalid, "no valid region table")
+}
+
+func readVhdxMetadata(r io.ReaderAt, regionOffset, regionLength int64) (*VhdxInfo, error) {
+	table := make([]byte, vhdxMetadataEntrySize*(vhdxMaxMetadataEntries+1))
+	if int64(len(table)) > regionLength {
+		table = table[:regionLength]
+	}
+	if _, err := r.ReadAt(table, regionOffset); err != nil {
+		return nil, errors.Wrap(err, "could not read VHDX metadata table")
+	}
+	if !bytes.HasPrefix(table, vhdxMetadataSignature) {
+		return nil, errors.Wrap(ErrVhdxInvalid, "invalid metadata table signature")
+	}
+	entryCount := int(binary.LittleEndian.Uint16(table[10:]))
+	if (entryCount+1)*vhdxMetadataEntrySize > len(table) {
+		return nil, errors.Wrapf(ErrVhdxInvalid, "invalid metadata entry count %d", entryCount)
+	}
+
+	info := &VhdxInfo{}
+	var foundFileParameters, foundVirtualDiskSize bool
+	for i := 1; i <= entryCount; i++ {
+		var entry vhdxMetadataEntry
+		if err := binary.Read(bytes.NewReader(table[i*vhdxMetadataEntrySize:]), binary.LittleEndian, &entry); err != nil {
+			return nil, errors.Wrap(err, "could not parse VHDX metadata table")
+		}
+		if int64(entry.Offset)+int64(entry.Length) > regionLength {
+			return nil, errors.Wrap(ErrVhdxInvalid, "metadata item is beyond the metadata region")
+		}
+		switch entry.ItemID {
+		case vhdxFileParametersGUID:
+			item, err := readVhdxMetadataItem(r, regionOffset, entry, 8)
+			if err != nil {
+				return nil, err
+			}
+			info.BlockSize = binary.LittleEndian.Uint32(item)
+			flags := binary.LittleEndian.Uint32(item[4:])
+			info.Dynamic = flags&vhdxLeaveBlocksAllocated == 0
+			info.Differencing = flags&vhdxHasParent != 0
+			foundFileParameters = true
+		case vhdxVirtualDiskSizeGUID:
+			item, err := readVhdxMetadataItem(r, regionOffset, entry, 8)
+			if err != nil {
+				return nil, err
+			}
+			info.VirtualSize = int64(binary.LittleEndian.Uint64(item))
+			foundVirtualDiskSize = true
+		case vhdxLogicalSectorGUID:
+			item, err := readVhdxMetadataItem(r, regionOffset, entry, 4)
+			if err != nil {
+				return nil, err
+			}
+			info.LogicalSectorSize = binary.LittleEndian.Uint32(item)
+		}
+	}
+	if !foundFileParameters || !foundVirtualDiskSize {
+		return nil, errors.Wrap(ErrVhdxInvalid, "missing required metadata items")
+	}
+	return info, nil
+}
+
+func readVhdxMetadataItem(r io.ReaderAt, regionOffset int64, entry vhdxMetadataEntry, size uint32) ([]byte, error) {
+	if entry.Length < size {
+		return nil, errors.Wrapf(ErrVhdxInvalid, "metadata item of %d bytes is too small", entry.Length)
+	}
+	item := make([]byte, size)
+	if _, err := r.ReadAt(item, regionOffset+int64(entry.Offset)); err != nil {
+		return nil, errors.Wrap(err, "could not read VHDX metadata item")
+	}
+	return item, nil
+}
+
+// readVhdxStructure reads a header or region table, checking its signature and CRC-32C checksum.
+func readVhdxStructure(r io.ReaderAt, offset, size int64, signature []byte) ([]byte, error) {
+	buf := make([]byte, size)
+	if _, err := r.ReadAt(buf, offset); err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(buf, signature) {
+		return nil, errors.Wrap(ErrVhdxInvalid, "invalid signature")
+	}
+	checksum := binary.LittleEndian.Uint32(buf[4:])
+	binary.LittleEndian.PutUint32(buf[4:], 0)
+	if crc32.Checksum(buf, vhdxCastagnoliCRC32Table) != checksum {
+		return nil, errors.Wrap(ErrVhdxInvalid, "invalid checksum")
+	}
+	binary.LittleEndian.PutUint32(buf[4:], checksum)
+	return buf, nil
+}
+
+// vhdxGUID returns the on disk representation of a GUID, with the first three fields in little endian.
+func vhdxGUID(data1 uint32, data2, data3 uint16, data4 [8]byte) [16]byte {
+	var guid [16]byte
+	binary.LittleEndian.PutUint32(guid[0:], data1)
+	binary.LittleEndian.PutUint16(guid[4:], data2)
+	binary.LittleEndian.PutUint16(guid[6:], data3)
+	copy(guid[8:], data4[:])
+	return guid
+}
@@ -0,0 +1,258 @@
+package image
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+const (
+	vhdxTestVirtualSize = int64(8 * 1024 * 1024)
+	vhdxTestBlockSize   = 1024 * 1024
+
+	vhdxTestLogOffset      = 1024 * 1024
+	vhdxTestMetadataOffset = 2 * 1024 * 1024
+	vhdxTestBatOffset      = 3 * 1024 * 1024
+	vhdxTestPayloadOffset  = 4 * 1024 * 1024
+	vhdxTestRegionLength   = 1024 * 1024
+
+	// vhdxPayloadBlockFullyPresent is the BAT state of an allocated payload block
+	vhdxPayloadBlockFullyPresent = 6
+)
+
+var (
+	vhdxBatRegionGUID      = vhdxGUID(0x2DC27766, 0xF623, 0x4200, [8]byte{0x9D, 0x64, 0x11, 0x5E, 0x9B, 0xFD, 0x4A, 0x08})
+	vhdxPhysicalSectorGUID = vhdxGUID(0xCDA348C7, 0x445D, 0x4471, [8]byte{0x9C, 0xC9, 0xE9, 0x88, 0x52, 0x51, 0xC5, 0x56})
+	vhdxPage83GUID         = vhdxGUID(0xBECA12AB, 0xB2E6, 0x4523, [8]byte{0x93, 0xEF, 0xC3, 0x09, 0xE0, 0x00, 0xC7, 0x46})
+)
+
+var _ = Describe("VHDX image", func() {
+	var tmpDir string
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "vhdx")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	table.DescribeTable("should read the metadata", func(flags uint32, dynamic, differencing bool) {
+		path := createVhdxImage(filepath.Join(tmpDir, "disk.vhdx"), flags, nil)
+		info, err := GetVhdxInfo(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info).To(Equal(&VhdxInfo{
+			VirtualSize:       vhdxTestVirtualSize,
+			BlockSize:         vhdxTestBlockSize,
+			LogicalSectorSize: 512,
+			Dynamic:           dynamic,
+			Differencing:      differencing,
+		}))
+	},
+		table.Entry("of a dynamic image", uint32(0), true, false),
+		table.Entry("of a fixed image", uint32(vhdxLeaveBlocksAllocated), false, false),
+		table.Entry("of a differencing image", uint32(vhdxHasParent), true, true),
+	)
+
+	It("should ignore images that are not VHDX", func() {
+		info, err := GetVhdxInfo(filepath.Join(testImagesDir, "cirros-qcow2.img"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info).To(BeNil())
+	})
+
+	It("should use the second region table if the first one is corrupt", func() {
+		path := createVhdxImage(filepath.Join(tmpDir, "disk.vhdx"), 0, nil)
+		corruptFile(path, vhdxRegionTableOffset1+16)
+		info, err := GetVhdxInfo(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.VirtualSize).To(Equal(vhdxTestVirtualSize))
+	})
+
+	It("should reject an image with corrupt headers", func() {
+		path := createVhdxImage(filepath.Join(tmpDir, "disk.vhdx"), 0, nil)
+		corruptFile(path, vhdxHeaderOffset1+8)
+		corruptFile(path, vhdxHeaderOffset2+8)
+		_, err := GetVhdxInfo(path)
+		Expect(err).To(HaveOccurred())
+		Expect(errors.Cause(err)).To(Equal(ErrVhdxInvalid))
+	})
+
+	table.DescribeTable("Validate should", func(flags uint32, format string, virtualSize int64, errString string) {
+		path := createVhdxImage(filepath.Join(tmpDir, "disk.vhdx"), flags, nil)
+		imageURL, err := url.Parse(path)
+		Expect(err).ToNot(HaveOccurred())
+		output := fmt.Sprintf(`{"format": %q, "virtual-size": %d}`, format, virtualSize)
+		replaceExecFunction(mockExecFunction(output, "", expectedLimits, "info", "--output=json", path), func() {
+			err = Validate(imageURL, vhdxTestVirtualSize)
+		})
+		if errString == "" {
+			Expect(err).ToNot(HaveOccurred())
+		} else {
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(errString))
+		}
+	},
+		table.Entry("accept a dynamic image", uint32(0), "vhdx", vhdxTestVirtualSize, ""),
+		table.Entry("use the virtual size from the metadata", uint32(0), "vhdx", vhdxTestVirtualSize*2, ""),
+		table.Entry("fail if qemu-img does not detect the VHDX image", uint32(0), "raw", vhdxTestVirtualSize, "it may lack VHDX support"),
+		table.Entry("reject a differencing image", uint32(vhdxHasParent), "vhdx", vhdxTestVirtualSize, "differencing image"),
+	)
+
+	It("Validate should fail clearly if qemu-img can't read the VHDX image", func() {
+		path := createVhdxImage(filepath.Join(tmpDir, "disk.vhdx"), 0, nil)
+		imageURL, err := url.Parse(path)
+		Expect(err).ToNot(HaveOccurred())
+		replaceExecFunction(mockExecFunction("qemu-img: Unknown driver 'vhdx'", "exit 1", expectedLimits), func() {
+			err = Validate(imageURL, vhdxTestVirtualSize)
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("qemu-img is unable to read VHDX image"))
+	})
+
+	It("should convert a dynamic image with qemu-img", func() {
+		if _, err := exec.LookPath("qemu-img"); err != nil {
+			Skip("qemu-img is not available")
+		}
+		blocks := map[int][]byte{
+			0: bytes.Repeat([]byte{0xaa}, vhdxTestBlockSize),
+			5: bytes.Repeat([]byte{0x55}, vhdxTestBlockSize),
+		}
+		path := createVhdxImage(filepath.Join(tmpDir, "disk.vhdx"), 0, blocks)
+		imageURL, err := url.Parse(path)
+		Expect(err).ToNot(HaveOccurred())
+		info, err := NewQEMUOperations().Info(imageURL)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Format).To(Equal("vhdx"))
+		Expect(info.VirtualSize).To(Equal(vhdxTestVirtualSize))
+
+		dest := filepath.Join(tmpDir, "disk.raw")
+		Expect(NewQEMUOperations().ConvertToRawStream(imageURL, dest, false)).To(Succeed())
+		content, err := os.ReadFile(dest)
+		Expect(err).ToNot(HaveOccurred())
+		expected := make([]byte, vhdxTestVirtualSize)
+		for block, data := range blocks {
+			copy(expected[block*vhdxTestBlockSize:], data)
+		}
+		Expect(bytes.Equal(content, expected)).To(BeTrue())
+	})
+})
+
+// createVhdxImage creates a VHDX image of vhdxTestVirtualSize, with the passed in file parameter flags. The blocks map
+// holds the content of the allocated payload blocks, indexed by block number.
+func createVhdxImage(path string, flags uint32, blocks map[int][]byte) string {
+	f, err := os.Create(path)
+	Expect(err).ToNot(HaveOccurred())
+	defer f.Close()
+	writeAt := func(data []byte, offset int64) {
+		_, err := f.WriteAt(data, offset)
+		Expect(err).ToNot(HaveOccurred())
+	}
+	le := binary.LittleEndian
+
+	writeAt(vhdxFileSignature, 0)
+
+	for i, offset := range []int64{vhdxHeaderOffset1, vhdxHeaderOffset2} {
+		header := make([]byte, vhdxHeaderSize)
+		copy(header, vhdxHeaderSignature)
+		le.PutUint64(header[8:], uint64(i+1))
+		// version 1, no log to replay
+		le.PutUint16(header[66:], 0)
+		le.PutUint16(header[68:], 1)
+		le.PutUint32(header[72:], vhdxTestRegionLength)
+		le.PutUint64(header[76:], vhdxTestLogOffset)
+		writeAt(withVhdxChecksum(header), offset)
+	}
+
+	regionTable := make([]byte, vhdxRegionTableSize)
+	copy(regionTable, vhdxRegionSignature)
+	le.PutUint32(regionTable[8:], 2)
+	for i, region := range []struct {
+		guid   [16]byte
+		offset uint64
+	}{{vhdxBatRegionGUID, vhdxTestBatOffset}, {vhdxMetadataRegionGUID, vhdxTestMetadataOffset}} {
+		entry := regionTable[16+i*vhdxRegionEntrySize:]
+		copy(entry, region.guid[:])
+		le.PutUint64(entry[16:], region.offset)
+		le.PutUint32(entry[24:], vhdxTestRegionLength)
+		le.PutUint32(entry[28:], 1)
+	}
+	regionTable = withVhdxChecksum(regionTable)
+	writeAt(regionTable, vhdxRegionTableOffset1)
+	writeAt(regionTable, vhdxRegionTableOffset2)
+
+	fileParameters := make([]byte, 8)
+	le.PutUint32(fileParameters, vhdxTestBlockSize)
+	le.PutUint32(fileParameters[4:], flags)
+	virtualDiskSize := make([]byte, 8)
+	le.PutUint64(virtualDiskSize, uint64(vhdxTestVirtualSize))
+	sectorSize := make([]byte, 4)
+	le.PutUint32(sectorSize, 512)
+	page83 := bytes.Repeat([]byte{0x42}, 16)
+	items := []struct {
+		guid  [16]byte
+		data  []byte
+		flags uint32
+	}{
+		{vhdxFileParametersGUID, fileParameters, 4},
+		{vhdxVirtualDiskSizeGUID, virtualDiskSize, 6},
+		{vhdxLogicalSectorGUID, sectorSize, 6},
+		{vhdxPhysicalSectorGUID, sectorSize, 6},
+		{vhdxPage83GUID, page83, 6},
+	}
+	metadataTable := make([]byte, vhdxMetadataEntrySize*(len(items)+1))
+	copy(metadataTable, vhdxMetadataSignature)
+	le.PutUint16(metadataTable[10:], uint16(len(items)))
+	itemOffset := uint32(64 * 1024)
+	for i, item := range items {
+		entry := metadataTable[(i+1)*vhdxMetadataEntrySize:]
+		copy(entry, item.guid[:])
+		le.PutUint32(entry[16:], itemOffset)
+		le.PutUint32(entry[20:], uint32(len(item.data)))
+		le.PutUint32(entry[24:], item.flags)
+		writeAt(item.data, vhdxTestMetadataOffset+int64(itemOffset))
+		itemOffset += uint32(len(item.data))
+	}
+	writeAt(metadataTable, vhdxTestMetadataOffset)
+
+	// A sector bitmap entry follows every chunk of payload blocks, there is less than a chunk here
+	bat := make([]byte, vhdxTestRegionLength)
+	payloadOffset := int64(vhdxTestPayloadOffset)
+	for block := 0; block < int(vhdxTestVirtualSize/vhdxTestBlockSize); block++ {
+		data, ok := blocks[block]
+		if !ok {
+			continue
+		}
+		le.PutUint64(bat[block*8:], uint64(payloadOffset)|vhdxPayloadBlockFullyPresent)
+		writeAt(data, payloadOffset)
+		payloadOffset += vhdxTestBlockSize
+	}
+	writeAt(bat, vhdxTestBatOffset)
+	Expect(f.Truncate(payloadOffset)).To(Succeed())
+	return path
+}
+
+func withVhdxChecksum(buf []byte) []byte {
+	binary.LittleEndian.PutUint32(buf[4:], 0)
+	binary.LittleEndian.PutUint32(buf[4:], crc32.Checksum(buf, vhdxCastagnoliCRC32Table))
+	return buf
+}
+
+func corruptFile(path string, offset int64) {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	Expect(err).ToNot(HaveOccurred())
+	defer f.Close()
+	_, err = f.WriteAt([]byte{0xff, 0xff, 0xff, 0xff}, offset)
+	Expect(err).ToNot(HaveOccurred())
+}
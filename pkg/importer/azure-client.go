@@ -0,0 +1,322 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+const (
+	// azureAPIVersion is the version of the Blob service REST API sent in the x-ms-version header
+	azureAPIVersion = "2021-08-06"
+	// azureStorageScope is the scope of the access tokens of a service principal reading Azure Storage
+	azureStorageScope = "https://storage.azure.com/.default"
+	// azurePageBlob is the x-ms-blob-type of a page blob, the type of the VHDs exported from managed disks
+	azurePageBlob = "PageBlob"
+	// azureTokenExpiryMargin is how long before its expiry an access token is renewed
+	azureTokenExpiryMargin = 5 * time.Minute
+)
+
+// may be overridden in tests
+var azureAuthorityHost = "https://login.microsoftonline.com"
+
+// azureCredentials are the credentials of an Azure Blob source, read from the files of its mounted secret. The blob is
+// read anonymously without them, from a container allowing public access.
+type azureCredentials struct {
+	sasToken     string
+	tenantID     string
+	clientID     string
+	clientSecret string
+}
+
+// readAzureCredentials reads the SAS token, or the service principal, from the secret mounted in dir
+func readAzureCredentials(dir string) (*azureCredentials, error) {
+	creds := &azureCredentials{}
+	for key, value := range map[string]*string{
+		common.KeyAzureSASToken:     &creds.sasToken,
+		common.KeyAzureTenantID:     &creds.tenantID,
+		common.KeyAzureClientID:     &creds.clientID,
+		common.KeyAzureClientSecret: &creds.clientSecret,
+	} {
+		data, err := os.ReadFile(filepath.Join(dir, key))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "could not read %s of the Azure secret", key)
+		}
+		*value = strings.TrimSpace(string(data))
+	}
+	if creds.sasToken == "" && (creds.tenantID != "" || creds.clientID != "" || creds.clientSecret != "") &&
+		(creds.tenantID == "" || creds.clientID == "" || creds.clientSecret == "") {
+		return nil, errors.Errorf("the service principal of the Azure secret needs %s, %s and %s",
+			common.KeyAzureTenantID, common.KeyAzureClientID, common.KeyAzureClientSecret)
+	}
+	return creds, nil
+}
+
+// azureStatusError is the error response of the Blob service
+type azureStatusError struct {
+	statusCode int
+	code       string
+}
+
+func (e *azureStatusError) Error() string {
+	if e.code == "" {
+		return fmt.Sprintf("Azure Blob request failed with status %d", e.statusCode)
+	}
+	return fmt.Sprintf("Azure Blob request failed with status %d: %s", e.statusCode, e.code)
+}
+
+// isPermanentAzureError returns true if the request failed with a client error a retry does not fix, like a missing
+// blob, missing permissions, or a blob modified since the import started. A success status other than the expected
+// one, like a range request answered with the whole blob, is not fixed by a retry either.
+func isPermanentAzureError(err error) bool {
+	var statusErr *azureStatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	if statusErr.statusCode < 300 {
+		return true
+	}
+	return statusErr.statusCode >= 400 && statusErr.statusCode < 500 &&
+		statusErr.statusCode != http.StatusRequestTimeout && statusErr.statusCode != http.StatusTooManyRequests
+}
+
+// azureBlobProperties are the properties of a blob used by the import
+type azureBlobProperties struct {
+	blobType string
+	size     int64
+	etag     string
+}
+
+// azurePageList is the response of Get Page Ranges, the ends of the ranges are inclusive
+type azurePageList struct {
+	PageRanges []struct {
+		Start int64 `xml:"Start"`
+		End   int64 `xml:"End"`
+	} `xml:"PageRange"`
+	NextMarker string `xml:"NextMarker"`
+}
+
+// azureBlobClient calls the Blob service REST API on a blob, authorized with a SAS token, with the access token of a
+// service principal, or anonymously
+type azureBlobClient struct {
+	httpClient *http.Client
+	blobURL    *url.URL
+	creds      *azureCredentials
+
+	tokenMutex  sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+func newAzureBlobClient(httpClient *http.Client, blobURL *url.URL, creds *azureCredentials) *azureBlobClient {
+	return &azureBlobClient{
+		httpClient: httpClient,
+		blobURL:    blobURL,
+		creds:      creds,
+	}
+}
+
+// String returns the URL of the blob, without the SAS token
+func (c *azureBlobClient) String() string {
+	return c.blobURL.String()
+}
+
+func (c *azureBlobClient) newRequest(ctx context.Context, method string, query url.Values) (*http.Request, error) {
+	u := *c.blobURL
+	if c.creds.sasToken != "" {
+		sas, err := url.ParseQuery(strings.TrimPrefix(c.creds.sasToken, "?"))
+		if err != nil {
+			return nil, errors.New("invalid SAS token in the Azure secret")
+		}
+		for key, values := range sas {
+			query[key] = values
+		}
+	}
+	u.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+	if err != nil {
+		return nil, redactURLError(err)
+	}
+	req.Header.Set("x-ms-version", azureAPIVersion)
+	if c.creds.sasToken == "" && c.creds.clientID != "" {
+		token, err := c.accessToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req, nil
+}
+
+func (c *azureBlobClient) do(req *http.Request, expectedStatus ...int) (*http.Response, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, redactURLError(err)
+	}
+	for _, status := range expectedStatus {
+		if resp.StatusCode == status {
+			return resp, nil
+		}
+	}
+	resp.Body.Close()
+	return nil, &azureStatusError{statusCode: resp.StatusCode, code: resp.Header.Get("x-ms-error-code")}
+}
+
+// accessToken returns the access token of the service principal, requesting a new one with the client credentials
+// flow once the current one is about to expire
+func (c *azureBlobClient) accessToken(ctx context.Context) (string, error) {
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+	if c.token != "" && time.Now().Before(c.tokenExpiry) {
+		return c.token, nil
+	}
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {c.creds.clientID},
+		"client_secret": {c.creds.clientSecret},
+		"scope":         {azureStorageScope},
+	}
+	tokenURL := fmt.Sprintf("%s/%s/oauth2/v2.0/token", azureAuthorityHost, url.PathEscape(c.creds.tenantID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "could not get an access token for the Azure service principal")
+	}
+	defer resp.Body.Close()
+	var token struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int64  `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil && resp.StatusCode == http.StatusOK {
+		return "", errors.Wrap(err, "could not decode the access token of the Azure service principal")
+	}
+	if resp.StatusCode != http.StatusOK || token.AccessToken == "" {
+		return "", errors.Errorf("could not get an access token for the Azure service principal, status %d: %s %s", resp.StatusCode, token.Error, token.ErrorDescription)
+	}
+	c.token = token.AccessToken
+	c.tokenExpiry = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - azureTokenExpiryMargin)
+	klog.V(3).Infof("Got an access token for the Azure service principal %s, expiring in %ds", c.creds.clientID, token.ExpiresIn)
+	return c.token, nil
+}
+
+// getProperties returns the type, size and ETag of the blob
+func (c *azureBlobClient) getProperties(ctx context.Context) (*azureBlobProperties, error) {
+	req, err := c.newRequest(ctx, http.MethodHead, url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(req, http.StatusOK)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not get the properties of the blob %s", c)
+	}
+	resp.Body.Close()
+	size, err := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid size of the blob %s", c)
+	}
+	return &azureBlobProperties{
+		blobType: resp.Header.Get("x-ms-blob-type"),
+		size:     size,
+		etag:     resp.Header.Get("ETag"),
+	}, nil
+}
+
+// getPageRanges returns the ranges of the page blob holding data, its other pages are zeroes. The ranges are sorted,
+// and their ends are exclusive.
+func (c *azureBlobClient) getPageRanges(ctx context.Context, etag string) ([]dataRange, error) {
+	var ranges []dataRange
+	marker := ""
+	for {
+		query := url.Values{"comp": {"pagelist"}}
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+		req, err := c.newRequest(ctx, http.MethodGet, query)
+		if err != nil {
+			return nil, err
+		}
+		if etag != "" {
+			req.Header.Set("If-Match", etag)
+		}
+		resp, err := c.do(req, http.StatusOK)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not get the page ranges of the blob %s", c)
+		}
+		pageList := &azurePageList{}
+		err = xml.NewDecoder(resp.Body).Decode(pageList)
+		resp.Body.Close()
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not decode the page ranges of the blob %s", c)
+		}
+		for _, pageRange := range pageList.PageRanges {
+			r := dataRange{start: pageRange.Start, end: pageRange.End + 1}
+			if n := len(ranges); n > 0 && ranges[n-1].end == r.start {
+				ranges[n-1].end = r.end
+			} else {
+				ranges = append(ranges, r)
+			}
+		}
+		if pageList.NextMarker == "" {
+			return ranges, nil
+		}
+		marker = pageList.NextMarker
+	}
+}
+
+// getRange returns the body of the bytes of the blob from start to end, exclusive, failing if the blob no longer
+// matches etag
+func (c *azureBlobClient) getRange(ctx context.Context, start, end int64, etag string) (io.ReadCloser, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-ms-range", fmt.Sprintf("bytes=%d-%d", start, end-1))
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
+	}
+	// a server ignoring the range answers with the whole blob, the download must not continue from its start
+	resp, err := c.do(req, http.StatusPartialContent)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read the range %d-%d of the blob %s", start, end, c)
+	}
+	return resp.Body, nil
+}
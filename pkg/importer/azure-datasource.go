@@ -0,0 +1,368 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net/url"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/util"
+	prometheusutil "kubevirt.io/containerized-data-importer/pkg/util/prometheus"
+)
+
+const (
+	// azureMaxResumes is the number of times an interrupted download of a range is resumed
+	azureMaxResumes = 5
+	// vhdFooterSize is the size of the footer ending a VHD
+	vhdFooterSize = 512
+	// vhdFixedDiskType is the disk type of a fixed VHD, the raw disk followed by the footer
+	vhdFixedDiskType = 2
+)
+
+// may be overridden in tests
+var azureResumeDelay = 5 * time.Second
+
+// AzureDataSource is the struct containing the information needed to import from an Azure Blob data source.
+// Sequence of phases:
+// 1. Info -> Transfer (image to convert) or TransferFile (raw image, or fixed VHD page blob)
+// 2. Transfer -> Convert
+type AzureDataSource struct {
+	// Azure Blob end point
+	ep     *url.URL
+	client *azureBlobClient
+	// properties of the blob, its download is pinned to their ETag
+	properties *azureBlobProperties
+	// pageRanges are the ranges holding data of a page blob holding a fixed VHD, written sparsely to the target
+	pageRanges []dataRange
+	// diskSize is the size of the raw disk of a fixed VHD, without its footer
+	diskSize      int64
+	preallocation bool
+	// Reader of the blob, when it is not written sparsely
+	blobReader *azureBlobReader
+	// progress of the sparse transfer
+	progressReader *prometheusutil.ProgressReader
+	// stack of readers
+	readers *FormatReaders
+	// The image file in scratch space.
+	url *url.URL
+}
+
+// NewAzureDataSource creates a new instance of the AzureDataSource. The blob is read with the SAS token or the service
+// principal of the secret mounted in credentialDir, or anonymously if the secret holds neither, and the certificate of
+// the Blob service is verified with the system CAs and the CAs in certDir.
+func NewAzureDataSource(endpoint, credentialDir, certDir string, preallocation bool) (*AzureDataSource, error) {
+	ep, err := ParseEndpoint(endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse endpoint %q", redactSourceURL(endpoint))
+	}
+	if (ep.Scheme != "https" && ep.Scheme != "http") || ep.Host == "" {
+		return nil, errors.Errorf("invalid Azure Blob endpoint %q, expected an https url", redactSourceURL(endpoint))
+	}
+	ep.RawQuery = ""
+	creds, err := readAzureCredentials(credentialDir)
+	if err != nil {
+		return nil, err
+	}
+	if ep.Scheme != "https" && (creds.sasToken != "" || creds.clientSecret != "") {
+		return nil, errors.Errorf("the credentials of the Azure secret are only sent over https, not to %q", redactSourceURL(endpoint))
+	}
+	httpClient, err := createHTTPClient(certDir)
+	if err != nil {
+		return nil, err
+	}
+	client := newAzureBlobClient(httpClient, ep, creds)
+	properties, err := client.getProperties(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	klog.Infof("Importing the %s %s of %d bytes", properties.blobType, client, properties.size)
+	return &AzureDataSource{
+		ep:            ep,
+		client:        client,
+		properties:    properties,
+		preallocation: preallocation,
+	}, nil
+}
+
+// Info is called to get initial information about the data.
+func (ad *AzureDataSource) Info() (ProcessingPhase, error) {
+	if ad.properties.blobType == azurePageBlob {
+		fixed, err := ad.isFixedVHD()
+		if err != nil {
+			return ProcessingPhaseError, err
+		}
+		if fixed {
+			ad.diskSize = ad.properties.size - vhdFooterSize
+			if ad.pageRanges, err = ad.client.getPageRanges(context.Background(), ad.properties.etag); err != nil {
+				return ProcessingPhaseError, err
+			}
+			ad.pageRanges = clipDataRanges(ad.pageRanges, ad.diskSize)
+			klog.Infof("Blob %s is a fixed VHD of %d bytes, transferring its %d page ranges", ad.client, ad.diskSize, len(ad.pageRanges))
+			// The raw disk is written to the target without its footer
+			return ProcessingPhaseTransferDataFile, nil
+		}
+	}
+	ad.blobReader = newAzureBlobReader(ad.client, ad.properties.etag, 0, ad.properties.size)
+	var err error
+	ad.readers, err = NewDiskFormatReaders(ad.blobReader, uint64(ad.properties.size))
+	if err != nil {
+		klog.Errorf("Error creating readers: %v", err)
+		return ProcessingPhaseError, err
+	}
+	ad.readers.CheckExtensionHint(ad.ep.Path)
+	if !ad.readers.Convert {
+		// Downloading a raw file, we can write that directly to the target.
+		return ProcessingPhaseTransferDataFile, nil
+	}
+	return ProcessingPhaseTransferScratch, nil
+}
+
+// isFixedVHD returns true if the blob ends with the footer of a fixed VHD, as the VHDs exported from managed disks
+func (ad *AzureDataSource) isFixedVHD() (bool, error) {
+	size := ad.properties.size
+	if size < vhdFooterSize || size%vhdFooterSize != 0 {
+		return false, nil
+	}
+	body, err := ad.client.getRange(context.Background(), size-vhdFooterSize, size, ad.properties.etag)
+	if err != nil {
+		return false, err
+	}
+	defer body.Close()
+	footer := make([]byte, vhdFooterSize)
+	if _, err := io.ReadFull(body, footer); err != nil {
+		return false, errors.Wrapf(err, "could not read the footer of the blob %s", ad.client)
+	}
+	return isFixedVHDFooter(footer), nil
+}
+
+// isFixedVHDFooter returns true if footer is the footer of a fixed VHD
+func isFixedVHDFooter(footer []byte) bool {
+	return bytes.HasPrefix(footer, []byte("conectix")) && binary.BigEndian.Uint32(footer[60:64]) == vhdFixedDiskType
+}
+
+// clipDataRanges returns the ranges ending before size
+func clipDataRanges(ranges []dataRange, size int64) []dataRange {
+	clipped := make([]dataRange, 0, len(ranges))
+	for _, r := range ranges {
+		if r.start >= size {
+			break
+		}
+		if r.end > size {
+			r.end = size
+		}
+		clipped = append(clipped, r)
+	}
+	return clipped
+}
+
+// Transfer is called to transfer the data from the source to a temporary location.
+func (ad *AzureDataSource) Transfer(path string) (ProcessingPhase, error) {
+	file := filepath.Join(path, tempFile)
+	if err := CleanAll(file); err != nil {
+		return ProcessingPhaseError, err
+	}
+	size, err := util.GetAvailableSpace(path)
+	if err != nil || size <= 0 {
+		return ProcessingPhaseError, ErrInvalidPath
+	}
+	ad.readers.StartProgressUpdate()
+	if err := util.StreamDataToFile(ad.readers.TopReader(), file); err != nil {
+		return ProcessingPhaseError, err
+	}
+	// If we successfully wrote to the file, then the parse will succeed.
+	ad.url, _ = url.Parse(file)
+	return ProcessingPhaseConvert, nil
+}
+
+// TransferFile is called to transfer the data from the source to the passed in file.
+func (ad *AzureDataSource) TransferFile(fileName string) (ProcessingPhase, error) {
+	if err := CleanAll(fileName); err != nil {
+		return ProcessingPhaseError, err
+	}
+	if ad.readers == nil {
+		if err := ad.transferPageRanges(fileName); err != nil {
+			return ProcessingPhaseError, err
+		}
+		return ProcessingPhaseResize, nil
+	}
+	ad.readers.StartProgressUpdate()
+	if err := util.StreamDataToFile(ad.readers.TopReader(), fileName); err != nil {
+		return ProcessingPhaseError, err
+	}
+	return ProcessingPhaseResize, nil
+}
+
+// transferPageRanges writes the raw disk of a fixed VHD page blob to fileName, only downloading its page ranges. The
+// pages between them are zeroes, they are zeroed on the target by punching holes, or by writing zeroes when
+// preallocation is requested.
+func (ad *AzureDataSource) transferPageRanges(fileName string) error {
+	destFile, err := util.OpenFileOrBlockDevice(fileName)
+	if err != nil {
+		return err
+	}
+	defer destFile.Close()
+
+	ad.progressReader = prometheusutil.NewProgressReader(io.NopCloser(bytes.NewReader(nil)), uint64(ad.diskSize), progress, ownerUID)
+	ad.progressReader.StartTimedUpdate()
+	zeroer := newRangeZeroer(destFile, ad.preallocation)
+	offset := int64(0)
+	for index, pageRange := range ad.pageRanges {
+		if err := zeroer.zero(offset, pageRange.start-offset); err != nil {
+			return err
+		}
+		ad.progressReader.AddBytes(uint64(pageRange.start - offset))
+		klog.V(3).Infof("Downloading %d-byte page range at offset %d", pageRange.end-pageRange.start, pageRange.start)
+		reader := newAzureBlobReader(ad.client, ad.properties.etag, pageRange.start, pageRange.end)
+		ad.progressReader.SetNextReader(reader, index == len(ad.pageRanges)-1)
+		end, err := writeRawAt(ad.progressReader, destFile, pageRange.start, ad.preallocation, nil)
+		reader.Close()
+		if err != nil {
+			return err
+		}
+		if end != pageRange.end {
+			return errors.Errorf("the download of the page range %d-%d of the blob %s ended at offset %d", pageRange.start, pageRange.end, ad.client, end)
+		}
+		offset = end
+	}
+	if err := zeroer.zero(offset, ad.diskSize-offset); err != nil {
+		return err
+	}
+	ad.progressReader.AddBytes(uint64(ad.diskSize - offset))
+	// Punching a hole doesn't extend a regular file, make sure a trailing hole is kept
+	if err := extendRegularFile(destFile, ad.diskSize); err != nil {
+		return err
+	}
+	return destFile.Sync()
+}
+
+// GetURL returns the url that the data processor can use when converting the data.
+func (ad *AzureDataSource) GetURL() *url.URL {
+	return ad.url
+}
+
+func (ad *AzureDataSource) formatReaders() *FormatReaders {
+	return ad.readers
+}
+
+// Close closes any readers or other open resources.
+func (ad *AzureDataSource) Close() error {
+	if ad.readers != nil {
+		return ad.readers.Close()
+	}
+	if ad.blobReader != nil {
+		return ad.blobReader.Close()
+	}
+	return nil
+}
+
+// azureBlobReader reads a range of a blob. An interrupted download is resumed from the offset it reached, with a new
+// range request, failing if the blob was modified since the import started.
+type azureBlobReader struct {
+	client  *azureBlobClient
+	etag    string
+	offset  int64
+	end     int64
+	resumes int
+	body    io.ReadCloser
+}
+
+// newAzureBlobReader returns a reader of the bytes of the blob from start to end, exclusive
+func newAzureBlobReader(client *azureBlobClient, etag string, start, end int64) *azureBlobReader {
+	return &azureBlobReader{
+		client: client,
+		etag:   etag,
+		offset: start,
+		end:    end,
+	}
+}
+
+func (r *azureBlobReader) Read(p []byte) (int, error) {
+	if r.offset >= r.end {
+		return 0, io.EOF
+	}
+	for {
+		if r.body == nil {
+			body, err := r.client.getRange(context.Background(), r.offset, r.end, r.etag)
+			if err != nil {
+				if resumeErr := r.resume(err); resumeErr != nil {
+					return 0, resumeErr
+				}
+				continue
+			}
+			r.body = body
+		}
+		if remaining := r.end - r.offset; int64(len(p)) > remaining {
+			p = p[:remaining]
+		}
+		n, err := r.body.Read(p)
+		r.offset += int64(n)
+		if r.offset >= r.end {
+			return n, io.EOF
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err == nil {
+			return n, nil
+		}
+		if n > 0 {
+			// the next read fails again, and resumes the download
+			return n, nil
+		}
+		if resumeErr := r.resume(err); resumeErr != nil {
+			return 0, resumeErr
+		}
+	}
+}
+
+// resume closes the download interrupted by err, so the next read requests the rest of the range
+func (r *azureBlobReader) resume(err error) error {
+	if isPermanentAzureError(err) {
+		return err
+	}
+	if r.resumes >= azureMaxResumes {
+		return errors.Wrapf(err, "Azure download of %s interrupted at offset %d, after %d resumes", r.client, r.offset, r.resumes)
+	}
+	r.resumes++
+	klog.Warningf("Azure download of %s interrupted at offset %d, resuming: %v", r.client, r.offset, err)
+	r.closeBody()
+	time.Sleep(azureResumeDelay)
+	return nil
+}
+
+func (r *azureBlobReader) closeBody() {
+	if r.body != nil {
+		if err := r.body.Close(); err != nil {
+			klog.V(3).Infof("Closing the Azure download: %v", err)
+		}
+		r.body = nil
+	}
+}
+
+// Close closes the download
+func (r *azureBlobReader) Close() error {
+	r.closeBody()
+	return nil
+}
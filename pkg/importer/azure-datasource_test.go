@@ -0,0 +1,418 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+// mockAzureBlob is a blob of the mock Blob service, its page ranges are the ranges of a page blob holding data
+type mockAzureBlob struct {
+	data       []byte
+	blobType   string
+	etag       string
+	pageRanges []dataRange
+}
+
+// mockAzureServer serves blobs like the Blob service, and access tokens like the Microsoft identity platform
+type mockAzureServer struct {
+	server *httptest.Server
+	blobs  map[string]*mockAzureBlob
+	// sasSignature is the sig of the SAS token required to read the blobs
+	sasSignature string
+	// clientSecret is the secret of the service principal allowed to read the blobs
+	clientSecret string
+	// pageListSize is the number of page ranges returned by a Get Page Ranges request
+	pageListSize int
+	// dropAfter is the number of bytes sent before the first drops range downloads are interrupted
+	dropAfter int
+	drops     int
+	// ignoreRange answers the range downloads with the whole blob
+	ignoreRange bool
+
+	mutex     sync.Mutex
+	ranges    []string
+	tokenHits int
+}
+
+func newMockAzureServer() *mockAzureServer {
+	s := &mockAzureServer{blobs: map[string]*mockAzureBlob{}, pageListSize: 1000}
+	s.server = httptest.NewTLSServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// writeCert writes the certificate of the server in certDir, for the importer to trust it
+func (s *mockAzureServer) writeCert(certDir string) {
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.server.Certificate().Raw})
+	Expect(os.WriteFile(filepath.Join(certDir, "tls.crt"), certPEM, 0644)).To(Succeed())
+}
+
+func (s *mockAzureServer) url(blob string) string {
+	return s.server.URL + "/vhds/" + blob
+}
+
+func (s *mockAzureServer) requestedRanges() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string{}, s.ranges...)
+}
+
+func (s *mockAzureServer) serve(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.URL.Path, "/oauth2/v2.0/token") {
+		s.serveToken(w, r)
+		return
+	}
+	if !s.authorized(r) {
+		w.Header().Set("x-ms-error-code", "AuthenticationFailed")
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	if r.Header.Get("x-ms-version") != azureAPIVersion {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	blob, ok := s.blobs[strings.TrimPrefix(r.URL.Path, "/vhds/")]
+	if !ok {
+		w.Header().Set("x-ms-error-code", "BlobNotFound")
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if match := r.Header.Get("If-Match"); match != "" && match != blob.etag {
+		w.Header().Set("x-ms-error-code", "ConditionNotMet")
+		w.WriteHeader(http.StatusPreconditionFailed)
+		return
+	}
+	switch {
+	case r.Method == http.MethodHead:
+		w.Header().Set("Content-Length", strconv.Itoa(len(blob.data)))
+		w.Header().Set("x-ms-blob-type", blob.blobType)
+		w.Header().Set("ETag", blob.etag)
+	case r.URL.Query().Get("comp") == "pagelist":
+		s.servePageList(w, r, blob)
+	default:
+		s.serveRange(w, r, blob)
+	}
+}
+
+func (s *mockAzureServer) authorized(r *http.Request) bool {
+	if s.sasSignature != "" {
+		return r.URL.Query().Get("sig") == s.sasSignature
+	}
+	if s.clientSecret != "" {
+		return r.Header.Get("Authorization") == "Bearer token-"+s.clientSecret
+	}
+	return true
+}
+
+func (s *mockAzureServer) serveToken(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	s.tokenHits++
+	s.mutex.Unlock()
+	if r.Method != http.MethodPost || r.URL.Path != "/tenant/oauth2/v2.0/token" || r.ParseForm() != nil ||
+		r.PostForm.Get("grant_type") != "client_credentials" || r.PostForm.Get("client_id") != "client" ||
+		r.PostForm.Get("scope") != azureStorageScope || r.PostForm.Get("client_secret") != s.clientSecret {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":"invalid_client","error_description":"Invalid client secret"}`)
+		return
+	}
+	fmt.Fprintf(w, `{"token_type":"Bearer","expires_in":3599,"access_token":"token-%s"}`, s.clientSecret)
+}
+
+func (s *mockAzureServer) servePageList(w http.ResponseWriter, r *http.Request, blob *mockAzureBlob) {
+	start, _ := strconv.Atoi(r.URL.Query().Get("marker"))
+	end := start + s.pageListSize
+	nextMarker := strconv.Itoa(end)
+	if end >= len(blob.pageRanges) {
+		end, nextMarker = len(blob.pageRanges), ""
+	}
+	fmt.Fprint(w, `<?xml version="1.0" encoding="utf-8"?><PageList>`)
+	for _, pageRange := range blob.pageRanges[start:end] {
+		fmt.Fprintf(w, "<PageRange><Start>%d</Start><End>%d</End></PageRange>", pageRange.start, pageRange.end-1)
+	}
+	fmt.Fprintf(w, "<NextMarker>%s</NextMarker></PageList>", nextMarker)
+}
+
+func (s *mockAzureServer) serveRange(w http.ResponseWriter, r *http.Request, blob *mockAzureBlob) {
+	var start, end int
+	if _, err := fmt.Sscanf(r.Header.Get("x-ms-range"), "bytes=%d-%d", &start, &end); err != nil || end >= len(blob.data) {
+		w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+		return
+	}
+	s.mutex.Lock()
+	s.ranges = append(s.ranges, r.Header.Get("x-ms-range"))
+	drop := s.drops > 0 && end+1-start > s.dropAfter
+	if drop {
+		s.drops--
+	}
+	s.mutex.Unlock()
+	if s.ignoreRange {
+		w.Header().Set("Content-Length", strconv.Itoa(len(blob.data)))
+		w.WriteHeader(http.StatusOK)
+		w.Write(blob.data)
+		return
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(end+1-start))
+	w.WriteHeader(http.StatusPartialContent)
+	if drop {
+		w.Write(blob.data[start : start+s.dropAfter])
+		w.(http.Flusher).Flush()
+		panic(http.ErrAbortHandler)
+	}
+	w.Write(blob.data[start : end+1])
+}
+
+// newFixedVHD returns a fixed VHD holding random data in the given ranges of its raw disk of size bytes
+func newFixedVHD(size int64, dataRanges ...dataRange) []byte {
+	vhd := make([]byte, size+vhdFooterSize)
+	for _, r := range dataRanges {
+		rand.Read(vhd[r.start:r.end])
+	}
+	footer := vhd[size:]
+	copy(footer, "conectix")
+	binary.BigEndian.PutUint64(footer[48:56], uint64(size))
+	binary.BigEndian.PutUint32(footer[60:64], vhdFixedDiskType)
+	return vhd
+}
+
+var _ = Describe("Azure Blob data source", func() {
+	const (
+		mib = int64(1024 * 1024)
+		kib = int64(1024)
+	)
+	var (
+		server        *mockAzureServer
+		tmpDir        string
+		credentialDir string
+		certDir       string
+		ds            *AzureDataSource
+		origDelay     time.Duration
+		origAuthority string
+		vhd           []byte
+	)
+
+	BeforeEach(func() {
+		var err error
+		origDelay = azureResumeDelay
+		azureResumeDelay = 0
+		origAuthority = azureAuthorityHost
+		tmpDir, err = os.MkdirTemp("", "scratch")
+		Expect(err).ToNot(HaveOccurred())
+		credentialDir, err = os.MkdirTemp("", "azure-credentials")
+		Expect(err).ToNot(HaveOccurred())
+		certDir, err = os.MkdirTemp("", "azure-cert")
+		Expect(err).ToNot(HaveOccurred())
+		server = newMockAzureServer()
+		server.writeCert(certDir)
+		azureAuthorityHost = server.server.URL
+		vhd = newFixedVHD(4*mib, dataRange{0, 128 * kib}, dataRange{mib, mib + 4*kib})
+		server.blobs["disk.vhd"] = &mockAzureBlob{
+			data:     vhd,
+			blobType: azurePageBlob,
+			etag:     `"0x8DB1"`,
+			pageRanges: []dataRange{
+				{0, 64 * kib}, {64 * kib, 128 * kib}, {mib, mib + 4*kib}, {4 * mib, 4*mib + vhdFooterSize},
+			},
+		}
+		ds = nil
+	})
+
+	AfterEach(func() {
+		azureResumeDelay = origDelay
+		azureAuthorityHost = origAuthority
+		if ds != nil {
+			ds.Close()
+		}
+		server.server.Close()
+		os.RemoveAll(tmpDir)
+		os.RemoveAll(credentialDir)
+		os.RemoveAll(certDir)
+	})
+
+	writeCredential := func(key, value string) {
+		Expect(os.WriteFile(filepath.Join(credentialDir, key), []byte(value), 0600)).To(Succeed())
+	}
+
+	transferBlob := func(blob string) (ProcessingPhase, error) {
+		var err error
+		ds, err = NewAzureDataSource(server.url(blob), credentialDir, certDir, false)
+		if err != nil {
+			return ProcessingPhaseError, err
+		}
+		phase, err := ds.Info()
+		if err != nil {
+			return phase, err
+		}
+		Expect(phase).To(Equal(ProcessingPhaseTransferDataFile))
+		return ds.TransferFile(filepath.Join(tmpDir, "disk.img"))
+	}
+
+	expectImported := func(expected []byte) {
+		imported, err := os.ReadFile(filepath.Join(tmpDir, "disk.img"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bytes.Equal(imported, expected)).To(BeTrue())
+	}
+
+	It("should only download the page ranges of a fixed VHD, without its footer", func() {
+		phase, err := transferBlob("disk.vhd")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseResize))
+		Expect(ds.formatReaders()).To(BeNil())
+		expectImported(vhd[:4*mib])
+		Expect(server.requestedRanges()).To(Equal([]string{
+			fmt.Sprintf("bytes=%d-%d", 4*mib, 4*mib+vhdFooterSize-1),
+			fmt.Sprintf("bytes=0-%d", 128*kib-1),
+			fmt.Sprintf("bytes=%d-%d", mib, mib+4*kib-1),
+		}))
+		Expect(ds.progressReader.Current).To(Equal(uint64(4 * mib)))
+	})
+
+	It("should follow the markers of the page ranges", func() {
+		server.pageListSize = 1
+		_, err := transferBlob("disk.vhd")
+		Expect(err).ToNot(HaveOccurred())
+		expectImported(vhd[:4*mib])
+		Expect(server.requestedRanges()).To(HaveLen(3))
+	})
+
+	It("should stream a block blob holding a raw image", func() {
+		tinyCore, err := os.ReadFile(filepath.Join(imageDir, tinyCoreFileName))
+		Expect(err).ToNot(HaveOccurred())
+		server.blobs[tinyCoreFileName] = &mockAzureBlob{data: tinyCore, blobType: "BlockBlob", etag: `"0x8DB2"`}
+		_, err = transferBlob(tinyCoreFileName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ds.formatReaders()).ToNot(BeNil())
+		expectImported(tinyCore)
+	})
+
+	It("should transfer a qcow2 image to scratch space", func() {
+		cirros, err := os.ReadFile(filepath.Join(imageDir, cirrosFileName))
+		Expect(err).ToNot(HaveOccurred())
+		server.blobs[cirrosFileName] = &mockAzureBlob{data: cirros, blobType: "BlockBlob", etag: `"0x8DB3"`}
+		ds, err = NewAzureDataSource(server.url(cirrosFileName), credentialDir, certDir, false)
+		Expect(err).ToNot(HaveOccurred())
+		phase, err := ds.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferScratch))
+		phase, err = ds.Transfer(tmpDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseConvert))
+		Expect(ds.GetURL().String()).To(Equal(filepath.Join(tmpDir, tempFile)))
+	})
+
+	It("should read the blob with the SAS token of the secret", func() {
+		server.sasSignature = "signature"
+		writeCredential(common.KeyAzureSASToken, "?sv=2021-08-06&sr=b&sp=r&sig=signature\n")
+		_, err := transferBlob("disk.vhd")
+		Expect(err).ToNot(HaveOccurred())
+		expectImported(vhd[:4*mib])
+	})
+
+	It("should fail to read the blob with a wrong SAS token", func() {
+		server.sasSignature = "signature"
+		writeCredential(common.KeyAzureSASToken, "sv=2021-08-06&sr=b&sp=r&sig=wrong")
+		_, err := transferBlob("disk.vhd")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("status 403: AuthenticationFailed"))
+		Expect(err.Error()).ToNot(ContainSubstring("wrong"))
+	})
+
+	It("should read the blob with the access token of the service principal", func() {
+		server.clientSecret = "secret"
+		writeCredential(common.KeyAzureTenantID, "tenant")
+		writeCredential(common.KeyAzureClientID, "client")
+		writeCredential(common.KeyAzureClientSecret, "secret")
+		_, err := transferBlob("disk.vhd")
+		Expect(err).ToNot(HaveOccurred())
+		expectImported(vhd[:4*mib])
+		Expect(server.tokenHits).To(Equal(1))
+	})
+
+	It("should fail to get an access token with a wrong client secret", func() {
+		server.clientSecret = "secret"
+		writeCredential(common.KeyAzureTenantID, "tenant")
+		writeCredential(common.KeyAzureClientID, "client")
+		writeCredential(common.KeyAzureClientSecret, "wrong")
+		_, err := transferBlob("disk.vhd")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("invalid_client"))
+	})
+
+	It("should reject a service principal without client secret", func() {
+		writeCredential(common.KeyAzureTenantID, "tenant")
+		writeCredential(common.KeyAzureClientID, "client")
+		_, err := NewAzureDataSource(server.url("disk.vhd"), credentialDir, certDir, false)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("needs tenantId, clientId and clientSecret"))
+	})
+
+	It("should not send the credentials of the secret over http", func() {
+		writeCredential(common.KeyAzureSASToken, "sv=2021-08-06&sr=b&sp=r&sig=signature")
+		_, err := NewAzureDataSource(strings.Replace(server.url("disk.vhd"), "https://", "http://", 1), credentialDir, certDir, false)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("only sent over https"))
+	})
+
+	It("should fail on a missing blob", func() {
+		_, err := NewAzureDataSource(server.url("missing.vhd"), credentialDir, certDir, false)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("status 404"))
+	})
+
+	It("should resume an interrupted download of a page range", func() {
+		server.dropAfter = int(16 * kib)
+		server.drops = 2
+		_, err := transferBlob("disk.vhd")
+		Expect(err).ToNot(HaveOccurred())
+		expectImported(vhd[:4*mib])
+		Expect(server.requestedRanges()).To(ContainElements(
+			fmt.Sprintf("bytes=%d-%d", 16*kib, 128*kib-1),
+			fmt.Sprintf("bytes=%d-%d", 32*kib, 128*kib-1),
+		))
+	})
+
+	It("should fail an interrupted download after the maximum number of resumes", func() {
+		server.dropAfter = int(16 * kib)
+		server.drops = azureMaxResumes + 1
+		server.blobs["disk.vhd"].pageRanges = []dataRange{{0, 4 * mib}}
+		_, err := transferBlob("disk.vhd")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("after 5 resumes"))
+	})
+
+	It("should fail a range download answered with the whole blob, instead of writing it at the offset", func() {
+		server.ignoreRange = true
+		_, err := transferBlob("disk.vhd")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("status 200"))
+		Expect(server.requestedRanges()).To(HaveLen(1))
+	})
+
+	It("should fail when the blob is modified during the import", func() {
+		var err error
+		ds, err = NewAzureDataSource(server.url("disk.vhd"), credentialDir, certDir, false)
+		Expect(err).ToNot(HaveOccurred())
+		phase, err := ds.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferDataFile))
+		server.blobs["disk.vhd"].etag = `"0x8DB9"`
+		_, err = ds.TransferFile(filepath.Join(tmpDir, "disk.img"))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("status 412: ConditionNotMet"))
+		Expect(server.requestedRanges()).To(HaveLen(1))
+	})
+})
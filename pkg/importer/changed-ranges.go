@@ -0,0 +1,320 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+const (
+	// maxChangedRangesManifestSize bounds the size of the changed ranges manifest read from the server
+	maxChangedRangesManifestSize = 16 * 1024 * 1024
+	// changedRangesStagingFile is the file of the scratch space the changed ranges are downloaded to
+	changedRangesStagingFile = "changed-ranges"
+	// changedRangesJournalFile is the file of the scratch space recording the manifest of the changed ranges fully
+	// downloaded to the staging file. The ranges are written again when the importer restarts while writing them.
+	changedRangesJournalFile = "changed-ranges.json"
+)
+
+// ChangedRanges is the manifest of the byte ranges of a raw disk image changed since its base image
+type ChangedRanges struct {
+	// Size is the size of the raw image, the ranges are within it
+	Size int64 `json:"size"`
+	// BaseSHA256 is the hex encoded SHA-256 digest of the base image, the first Size bytes of the target
+	BaseSHA256 string `json:"baseSha256"`
+	// Ranges are the byte ranges of the image changed since the base image
+	Ranges []ChangedRange `json:"ranges"`
+}
+
+// ChangedRange is a byte range of a disk image
+type ChangedRange struct {
+	Offset int64 `json:"offset"`
+	Length int64 `json:"length"`
+}
+
+// validate checks the manifest describes sorted and not overlapping ranges within the image, and a SHA-256 digest
+func (r *ChangedRanges) validate() error {
+	if r.Size <= 0 {
+		return errors.Errorf("invalid image size %d", r.Size)
+	}
+	if digest, err := hex.DecodeString(r.BaseSHA256); err != nil || len(digest) != sha256.Size {
+		return errors.Errorf("invalid base image SHA-256 digest %q", r.BaseSHA256)
+	}
+	var end int64
+	for _, changedRange := range r.Ranges {
+		// Length is compared to the rest of the image, Offset+Length may overflow
+		if changedRange.Offset < end || changedRange.Length <= 0 || changedRange.Offset > r.Size || changedRange.Length > r.Size-changedRange.Offset {
+			return errors.Errorf("invalid range of offset %d and length %d in an image of size %d, the ranges must be sorted and not overlap", changedRange.Offset, changedRange.Length, r.Size)
+		}
+		end = changedRange.Offset + changedRange.Length
+	}
+	return nil
+}
+
+// stagedSize returns the size of the changed ranges, at most Size for a valid manifest
+func (r *ChangedRanges) stagedSize() int64 {
+	var size int64
+	for _, changedRange := range r.Ranges {
+		size += changedRange.Length
+	}
+	return size
+}
+
+// ImportChangedRanges writes the ranges of the raw image at endpoint listed by the manifest at manifestURL into
+// target, which must hold the base image of the manifest. Nothing is written when it doesn't, or when a range cannot
+// be downloaded: all the ranges are downloaded to scratchDir before the first one is written, and are written again
+// by the next importer when it stops while writing them. ErrRequiresScratchSpace is returned without scratch space.
+// The scratch space is limited to scratchSpaceLimit bytes, unlimited when 0. Returns the number of bytes written.
+func ImportChangedRanges(endpoint, manifestURL, accessKey, secKey, certDir, target, scratchDir string, scratchSpaceLimit int64) (int64, error) {
+	if written, resumed, err := resumeChangedRanges(scratchDir, target); resumed || err != nil {
+		return written, err
+	}
+	ep, err := ParseEndpoint(endpoint)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to parse endpoint %q", redactSourceURL(endpoint))
+	}
+	manifestEp, err := url.Parse(manifestURL)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to parse changed ranges URL %q", manifestURL)
+	}
+	extraHeaders, secretExtraHeaders, err := getExtraHeaders()
+	if err != nil {
+		return 0, errors.Wrap(err, "Error getting extra headers for HTTP client")
+	}
+	client, err := createHTTPClient(certDir)
+	if err != nil {
+		return 0, errors.Wrap(err, "Error creating http client")
+	}
+	client.CheckRedirect = checkRedirect(accessKey, secKey, extraHeaders, secretExtraHeaders)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The credentials of the endpoint are only sent for a manifest on the same host
+	allExtraHeaders := append(append([]string{}, extraHeaders...), secretExtraHeaders...)
+	if !strings.EqualFold(manifestEp.Hostname(), ep.Hostname()) {
+		accessKey, secKey, allExtraHeaders = "", "", extraHeaders
+	}
+	manifest, err := fetchChangedRanges(ctx, client, manifestEp, accessKey, secKey, allExtraHeaders)
+	if err != nil {
+		return 0, err
+	}
+	klog.V(1).Infof("Applying %d changed ranges of %q", len(manifest.Ranges), redactSourceURL(ep.String()))
+	return applyChangedRanges(ctx, manifest, httpRangeReader(client, ep, accessKey, secKey, allExtraHeaders), target, scratchDir, scratchSpaceLimit)
+}
+
+// fetchChangedRanges gets and validates the changed ranges manifest
+func fetchChangedRanges(ctx context.Context, client *http.Client, manifestURL *url.URL, accessKey, secKey string, extraHeaders []string) (*ChangedRanges, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", manifestURL.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create HTTP request")
+	}
+	addExtraheaders(req, extraHeaders)
+	if len(accessKey) > 0 && len(secKey) > 0 {
+		req.SetBasicAuth(accessKey, secKey)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(redactURLError(err), "HTTP request errored")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("expected status code 200 getting the changed ranges, got %d. Status: %s", resp.StatusCode, resp.Status)
+	}
+	manifest := &ChangedRanges{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxChangedRangesManifestSize)).Decode(manifest); err != nil {
+		return nil, errors.Wrap(err, "could not decode the changed ranges")
+	}
+	if err := manifest.validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid changed ranges")
+	}
+	return manifest, nil
+}
+
+// applyChangedRanges checks target holds the base image of the manifest, downloads the changed ranges to a staging
+// file of the scratch space, records the manifest in the journal, then writes them into target
+func applyChangedRanges(ctx context.Context, manifest *ChangedRanges, openRange rangeReaderFunc, target, scratchDir string, scratchSpaceLimit int64) (int64, error) {
+	available, err := util.GetAvailableSpace(scratchDir)
+	if err != nil || available <= 0 {
+		return 0, ErrRequiresScratchSpace
+	}
+	if scratchSpaceLimit > 0 && scratchSpaceLimit < available {
+		available = scratchSpaceLimit
+	}
+	if needed := manifest.stagedSize(); needed > available {
+		return 0, errors.Errorf("the changed ranges need %d bytes of scratch space, %d bytes are available", needed, available)
+	}
+
+	targetFile, err := os.OpenFile(target, os.O_RDWR, 0)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not open the target")
+	}
+	defer targetFile.Close()
+
+	targetSize, err := targetFile.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not get the size of the target")
+	}
+	if targetSize < manifest.Size {
+		return 0, errors.Errorf("the target of size %d is smaller than the image size %d", targetSize, manifest.Size)
+	}
+	if _, err := targetFile.Seek(0, io.SeekStart); err != nil {
+		return 0, errors.Wrap(err, "could not seek the target")
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(hash, io.LimitReader(targetFile, manifest.Size)); err != nil {
+		return 0, errors.Wrap(err, "could not read the target")
+	}
+	if digest := hex.EncodeToString(hash.Sum(nil)); !strings.EqualFold(digest, manifest.BaseSHA256) {
+		return 0, errors.Errorf("the target does not hold the base image of the changed ranges, expected SHA-256 digest %s, got %s", manifest.BaseSHA256, digest)
+	}
+
+	stagingPath := filepath.Join(scratchDir, changedRangesStagingFile)
+	staging, err := os.Create(stagingPath)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not create the staging file")
+	}
+	defer staging.Close()
+	if err := stageChangedRanges(ctx, manifest, openRange, staging); err != nil {
+		os.Remove(stagingPath)
+		return 0, err
+	}
+	if err := writeChangedRangesJournal(scratchDir, manifest); err != nil {
+		os.Remove(stagingPath)
+		return 0, err
+	}
+	return writeChangedRanges(manifest, staging, targetFile, scratchDir)
+}
+
+// stageChangedRanges downloads the changed ranges to the staging file, and syncs it
+func stageChangedRanges(ctx context.Context, manifest *ChangedRanges, openRange rangeReaderFunc, staging *os.File) error {
+	for _, changedRange := range manifest.Ranges {
+		if err := downloadRange(ctx, openRange, changedRange, staging); err != nil {
+			return err
+		}
+	}
+	return errors.Wrap(staging.Sync(), "could not sync the staging file")
+}
+
+// writeChangedRangesJournal records the manifest of the staged changed ranges. The journal is written to a temporary
+// file renamed once synced, so it is either missing or complete.
+func writeChangedRangesJournal(scratchDir string, manifest *ChangedRanges) error {
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return errors.Wrap(err, "could not encode the changed ranges journal")
+	}
+	journalPath := filepath.Join(scratchDir, changedRangesJournalFile)
+	tempPath := journalPath + ".tmp"
+	journal, err := os.Create(tempPath)
+	if err != nil {
+		return errors.Wrap(err, "could not create the changed ranges journal")
+	}
+	defer journal.Close()
+	if _, err := journal.Write(data); err != nil {
+		return errors.Wrap(err, "could not write the changed ranges journal")
+	}
+	if err := journal.Sync(); err != nil {
+		return errors.Wrap(err, "could not sync the changed ranges journal")
+	}
+	if err := os.Rename(tempPath, journalPath); err != nil {
+		return errors.Wrap(err, "could not commit the changed ranges journal")
+	}
+	return nil
+}
+
+// resumeChangedRanges writes the changed ranges of the journal left in the scratch space by an importer that stopped
+// while writing them into target. Writing a range again is harmless, so all of them are written. Returns false when
+// there is no journal.
+func resumeChangedRanges(scratchDir, target string) (int64, bool, error) {
+	data, err := os.ReadFile(filepath.Join(scratchDir, changedRangesJournalFile))
+	if os.IsNotExist(err) {
+		return 0, false, nil
+	} else if err != nil {
+		return 0, true, errors.Wrap(err, "could not read the changed ranges journal")
+	}
+	manifest := &ChangedRanges{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return 0, true, errors.Wrap(err, "could not decode the changed ranges journal")
+	}
+	staging, err := os.Open(filepath.Join(scratchDir, changedRangesStagingFile))
+	if err != nil {
+		return 0, true, errors.Wrap(err, "could not open the staging file")
+	}
+	defer staging.Close()
+	targetFile, err := os.OpenFile(target, os.O_RDWR, 0)
+	if err != nil {
+		return 0, true, errors.Wrap(err, "could not open the target")
+	}
+	defer targetFile.Close()
+	klog.V(1).Infof("Resuming the write of %d changed ranges", len(manifest.Ranges))
+	written, err := writeChangedRanges(manifest, staging, targetFile, scratchDir)
+	return written, true, err
+}
+
+// writeChangedRanges writes the staged changed ranges at their offsets of target, then removes the journal and the
+// staging file
+func writeChangedRanges(manifest *ChangedRanges, staging io.ReaderAt, targetFile *os.File, scratchDir string) (int64, error) {
+	var written int64
+	for _, changedRange := range manifest.Ranges {
+		if _, err := targetFile.Seek(changedRange.Offset, io.SeekStart); err != nil {
+			return written, errors.Wrap(err, "could not seek the target")
+		}
+		n, err := io.Copy(targetFile, io.NewSectionReader(staging, written, changedRange.Length))
+		written += n
+		if err != nil {
+			return written, errors.Wrapf(err, "could not write the range of offset %d", changedRange.Offset)
+		}
+	}
+	if err := targetFile.Sync(); err != nil {
+		return written, errors.Wrap(err, "could not sync the target")
+	}
+	if err := os.Remove(filepath.Join(scratchDir, changedRangesJournalFile)); err != nil {
+		return written, errors.Wrap(err, "could not remove the changed ranges journal")
+	}
+	if err := os.Remove(filepath.Join(scratchDir, changedRangesStagingFile)); err != nil {
+		return written, errors.Wrap(err, "could not remove the staging file")
+	}
+	klog.V(1).Infof("Wrote %d bytes of changed ranges", written)
+	return written, nil
+}
+
+// downloadRange appends the changed range of the source to the staging file
+func downloadRange(ctx context.Context, openRange rangeReaderFunc, changedRange ChangedRange, staging io.Writer) error {
+	reader, err := openRange(ctx, changedRange.Offset, changedRange.Offset+changedRange.Length-1)
+	if err != nil {
+		return errors.Wrapf(err, "could not download the range of offset %d", changedRange.Offset)
+	}
+	defer reader.Close()
+	if _, err := io.CopyN(staging, reader, changedRange.Length); err != nil {
+		return errors.Wrapf(err, "could not download the range of offset %d", changedRange.Offset)
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package importer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Changed ranges import", func() {
+	const imageSize = 1024 * 1024
+
+	var (
+		tmpDir         string
+		scratchDir     string
+		base, newImage []byte
+		manifest       *ChangedRanges
+		rangeRequests  bool
+		ts             *httptest.Server
+	)
+
+	sha256Hex := func(data []byte) string {
+		digest := sha256.Sum256(data)
+		return hex.EncodeToString(digest[:])
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "changed-ranges")
+		Expect(err).ToNot(HaveOccurred())
+		scratchDir = filepath.Join(tmpDir, "scratch")
+		Expect(os.Mkdir(scratchDir, 0755)).To(Succeed())
+
+		base = make([]byte, imageSize)
+		_, err = rand.Read(base)
+		Expect(err).ToNot(HaveOccurred())
+		newImage = append([]byte{}, base...)
+		manifest = &ChangedRanges{
+			Size:       imageSize,
+			BaseSHA256: sha256Hex(base),
+			Ranges:     []ChangedRange{{Offset: 4096, Length: 4096}, {Offset: 65536, Length: 100}, {Offset: imageSize - 512, Length: 512}},
+		}
+		for _, changedRange := range manifest.Ranges {
+			_, err = rand.Read(newImage[changedRange.Offset : changedRange.Offset+changedRange.Length])
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		rangeRequests = true
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/ranges.json":
+				Expect(json.NewEncoder(w).Encode(manifest)).To(Succeed())
+			case "/disk.img":
+				if !rangeRequests {
+					r.Header.Del("Range")
+				}
+				http.ServeContent(w, r, "disk.img", time.Time{}, bytes.NewReader(newImage))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	})
+
+	AfterEach(func() {
+		ts.Close()
+		os.RemoveAll(tmpDir)
+	})
+
+	writeTarget := func(content []byte) string {
+		target := filepath.Join(tmpDir, "disk.img")
+		Expect(os.WriteFile(target, content, 0644)).To(Succeed())
+		return target
+	}
+
+	importChangedRanges := func(target string) (int64, error) {
+		return ImportChangedRanges(ts.URL+"/disk.img", ts.URL+"/ranges.json", "", "", "", target, scratchDir, 0)
+	}
+
+	expectEmptyScratch := func() {
+		entries, err := os.ReadDir(scratchDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(BeEmpty())
+	}
+
+	It("should write only the changed ranges into the target", func() {
+		target := writeTarget(base)
+
+		written, err := importChangedRanges(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(written).To(Equal(int64(4096 + 100 + 512)))
+
+		content, err := os.ReadFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal(newImage))
+		By("Checking the regions outside the ranges are unchanged")
+		Expect(content[:4096]).To(Equal(base[:4096]))
+		Expect(content[8192:65536]).To(Equal(base[8192:65536]))
+		Expect(content[65636 : imageSize-512]).To(Equal(base[65636 : imageSize-512]))
+		expectEmptyScratch()
+	})
+
+	It("should require scratch space to stage the changed ranges", func() {
+		target := writeTarget(base)
+
+		_, err := ImportChangedRanges(ts.URL+"/disk.img", ts.URL+"/ranges.json", "", "", "", target, filepath.Join(tmpDir, "missing"), 0)
+		Expect(err).To(Equal(ErrRequiresScratchSpace))
+	})
+
+	It("should not write anything when the changed ranges don't fit the scratch space limit", func() {
+		target := writeTarget(base)
+
+		_, err := ImportChangedRanges(ts.URL+"/disk.img", ts.URL+"/ranges.json", "", "", "", target, scratchDir, 4096)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("the changed ranges need 4708 bytes of scratch space, 4096 bytes are available"))
+
+		content, err := os.ReadFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal(base))
+	})
+
+	It("should write the staged changed ranges again when the previous importer stopped while writing them", func() {
+		By("Staging the ranges, and writing only the first one")
+		var staged []byte
+		for _, changedRange := range manifest.Ranges {
+			staged = append(staged, newImage[changedRange.Offset:changedRange.Offset+changedRange.Length]...)
+		}
+		Expect(os.WriteFile(filepath.Join(scratchDir, changedRangesStagingFile), staged, 0644)).To(Succeed())
+		Expect(writeChangedRangesJournal(scratchDir, manifest)).To(Succeed())
+		partial := append([]byte{}, base...)
+		copy(partial[4096:8192], newImage[4096:8192])
+		target := writeTarget(partial)
+
+		By("Checking the journal is used even though the target is no longer the base image, and the manifest changed")
+		manifest.BaseSHA256 = sha256Hex(newImage)
+		written, err := importChangedRanges(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(written).To(Equal(int64(len(staged))))
+		content, err := os.ReadFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal(newImage))
+		expectEmptyScratch()
+	})
+
+	It("should only check the image size of a larger target", func() {
+		padding := bytes.Repeat([]byte{0xff}, 4096)
+		target := writeTarget(append(append([]byte{}, base...), padding...))
+
+		_, err := importChangedRanges(target)
+		Expect(err).ToNot(HaveOccurred())
+
+		content, err := os.ReadFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content[:imageSize]).To(Equal(newImage))
+		Expect(content[imageSize:]).To(Equal(padding))
+	})
+
+	It("should not write anything into a target not holding the base image", func() {
+		modified := append([]byte{}, base...)
+		modified[imageSize/2]++
+		target := writeTarget(modified)
+
+		_, err := importChangedRanges(target)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("the target does not hold the base image of the changed ranges"))
+
+		content, err := os.ReadFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal(modified))
+	})
+
+	It("should not write anything when the server does not serve ranges", func() {
+		rangeRequests = false
+		target := writeTarget(base)
+
+		_, err := importChangedRanges(target)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("expected status code 206"))
+
+		content, err := os.ReadFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal(base))
+		expectEmptyScratch()
+	})
+
+	table.DescribeTable("should reject an invalid manifest with", func(modify func(*ChangedRanges), message string) {
+		modify(manifest)
+		target := writeTarget(base)
+
+		_, err := importChangedRanges(target)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(message))
+
+		content, err := os.ReadFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal(base))
+		expectEmptyScratch()
+	},
+		table.Entry("a range beyond the image", func(m *ChangedRanges) {
+			m.Ranges = append(m.Ranges, ChangedRange{Offset: imageSize - 10, Length: 20})
+		}, "invalid range of offset"),
+		table.Entry("a range whose end overflows", func(m *ChangedRanges) {
+			m.Ranges = []ChangedRange{{Offset: 4096, Length: math.MaxInt64}}
+		}, "invalid range of offset"),
+		table.Entry("overlapping ranges", func(m *ChangedRanges) {
+			m.Ranges = []ChangedRange{{Offset: 4096, Length: 4096}, {Offset: 8000, Length: 100}}
+		}, "invalid range of offset"),
+		table.Entry("unsorted ranges", func(m *ChangedRanges) {
+			m.Ranges = []ChangedRange{{Offset: 65536, Length: 100}, {Offset: 4096, Length: 4096}}
+		}, "invalid range of offset"),
+		table.Entry("an empty range", func(m *ChangedRanges) {
+			m.Ranges = append(m.Ranges, ChangedRange{Offset: 10})
+		}, "invalid range of offset"),
+		table.Entry("an invalid digest", func(m *ChangedRanges) { m.BaseSHA256 = "abc" }, "invalid base image SHA-256 digest"),
+		table.Entry("an image larger than the target", func(m *ChangedRanges) { m.Size = imageSize + 1 }, "is smaller than the image size"),
+	)
+})
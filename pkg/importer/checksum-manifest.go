@@ -0,0 +1,219 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+// maxChecksumManifestSize bounds the size of the checksum manifest read from the server
+const maxChecksumManifestSize = 16 * 1024 * 1024
+
+// ChecksumManifest is the manifest of the digests of the regions of a source, verified as the source is downloaded
+type ChecksumManifest struct {
+	// Algorithm is the digest algorithm of the regions, sha256 or sha512
+	Algorithm string `json:"algorithm"`
+	// Regions are the regions of the source to verify, sorted by offset and not overlapping
+	Regions []ChecksumRegion `json:"regions"`
+}
+
+// ChecksumRegion is a byte range of a source with its hex encoded digest
+type ChecksumRegion struct {
+	Offset int64  `json:"offset"`
+	Length int64  `json:"length"`
+	Digest string `json:"digest"`
+}
+
+// newHash returns a hash of the manifest algorithm
+func (m *ChecksumManifest) newHash() (hash.Hash, error) {
+	switch m.Algorithm {
+	case common.SourceDigestSHA256:
+		return sha256.New(), nil
+	case common.SourceDigestSHA512:
+		return sha512.New(), nil
+	}
+	return nil, errors.Errorf("unsupported checksum algorithm %q, should be %q or %q", m.Algorithm, common.SourceDigestSHA256, common.SourceDigestSHA512)
+}
+
+// validate checks the manifest describes sorted regions which don't overlap, with digests of the manifest algorithm
+func (m *ChecksumManifest) validate() error {
+	h, err := m.newHash()
+	if err != nil {
+		return err
+	}
+	if len(m.Regions) == 0 {
+		return errors.New("no regions")
+	}
+	var end int64
+	for _, region := range m.Regions {
+		if region.Offset < end || region.Length <= 0 {
+			return errors.Errorf("invalid region of offset %d and length %d, the regions must be sorted and not overlap", region.Offset, region.Length)
+		}
+		if digest, err := hex.DecodeString(region.Digest); err != nil || len(digest) != h.Size() {
+			return errors.Errorf("invalid %s digest %q of the region of offset %d", m.Algorithm, region.Digest, region.Offset)
+		}
+		end = region.Offset + region.Length
+	}
+	return nil
+}
+
+// getChecksumManifestFromEnvironment fetches the checksum manifest of the source at ep, when one is provided
+func getChecksumManifestFromEnvironment(ctx context.Context, ep *url.URL, accessKey, secKey, certDir string, extraHeaders, secretExtraHeaders []string) (*ChecksumManifest, error) {
+	manifestURL := os.Getenv(common.ImporterChecksumManifestURL)
+	if manifestURL == "" {
+		return nil, nil
+	}
+	manifestEp, err := url.Parse(manifestURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse checksum manifest URL %q", manifestURL)
+	}
+	client, err := createHTTPClient(certDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating http client")
+	}
+
+	// The credentials of the endpoint are only sent for a manifest on the same host, nor on its redirects
+	if !strings.EqualFold(manifestEp.Hostname(), ep.Hostname()) {
+		accessKey, secKey, secretExtraHeaders = "", "", nil
+	}
+	client.CheckRedirect = checkRedirect(accessKey, secKey, extraHeaders, secretExtraHeaders)
+	allExtraHeaders := append(append([]string{}, extraHeaders...), secretExtraHeaders...)
+	manifest, err := fetchChecksumManifest(ctx, client, manifestEp, accessKey, secKey, allExtraHeaders)
+	if err != nil {
+		return nil, err
+	}
+	klog.V(1).Infof("Verifying %d regions of %q against the checksum manifest", len(manifest.Regions), redactSourceURL(ep.String()))
+	return manifest, nil
+}
+
+// fetchChecksumManifest gets and validates the checksum manifest
+func fetchChecksumManifest(ctx context.Context, client *http.Client, manifestURL *url.URL, accessKey, secKey string, extraHeaders []string) (*ChecksumManifest, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", manifestURL.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create HTTP request")
+	}
+	addExtraheaders(req, extraHeaders)
+	if len(accessKey) > 0 && len(secKey) > 0 {
+		req.SetBasicAuth(accessKey, secKey)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(redactURLError(err), "HTTP request errored")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("expected status code 200 getting the checksum manifest, got %d. Status: %s", resp.StatusCode, resp.Status)
+	}
+	manifest := &ChecksumManifest{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxChecksumManifestSize)).Decode(manifest); err != nil {
+		return nil, errors.Wrap(err, "could not decode the checksum manifest")
+	}
+	if err := manifest.validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid checksum manifest")
+	}
+	return manifest, nil
+}
+
+// checksumReader verifies the digests of the regions of the manifest as the source is read, failing the read which
+// completes a region of a mismatching digest, so a corruption is caught before the rest of the source is downloaded
+type checksumReader struct {
+	io.ReadCloser
+	manifest *ChecksumManifest
+	hash     hash.Hash
+	// offset of the next byte read from the source
+	offset int64
+	// index of the region being verified
+	region int
+	err    error
+}
+
+func newChecksumReader(r io.ReadCloser, manifest *ChecksumManifest) (*checksumReader, error) {
+	h, err := manifest.newHash()
+	if err != nil {
+		return nil, err
+	}
+	return &checksumReader{ReadCloser: r, manifest: manifest, hash: h}, nil
+}
+
+func (r *checksumReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	n, err := r.ReadCloser.Read(p)
+	if verifyErr := r.verify(p[:n]); verifyErr != nil {
+		r.err = verifyErr
+		return n, verifyErr
+	}
+	if err == io.EOF && r.region < len(r.manifest.Regions) {
+		region := r.manifest.Regions[r.region]
+		r.err = errors.Errorf("the source ended at offset %d, before the end of the region of offset %d and length %d", r.offset, region.Offset, region.Length)
+		return n, r.err
+	}
+	return n, err
+}
+
+// verify hashes the bytes of data within the regions, and checks the digest of each region data completes
+func (r *checksumReader) verify(data []byte) error {
+	for len(data) > 0 && r.region < len(r.manifest.Regions) {
+		region := r.manifest.Regions[r.region]
+		if r.offset < region.Offset {
+			// Skip the bytes before the region, which are not verified
+			skip := region.Offset - r.offset
+			if skip > int64(len(data)) {
+				skip = int64(len(data))
+			}
+			r.offset += skip
+			data = data[skip:]
+			continue
+		}
+		n := region.Offset + region.Length - r.offset
+		if n > int64(len(data)) {
+			n = int64(len(data))
+		}
+		r.hash.Write(data[:n])
+		r.offset += n
+		data = data[n:]
+		if r.offset < region.Offset+region.Length {
+			continue
+		}
+		digest := hex.EncodeToString(r.hash.Sum(nil))
+		if !strings.EqualFold(digest, region.Digest) {
+			return errors.Errorf("checksum mismatch in the region of offset %d and length %d (bytes %d-%d of the source), expected %s digest %s, got %s",
+				region.Offset, region.Length, region.Offset, region.Offset+region.Length-1, r.manifest.Algorithm, region.Digest, digest)
+		}
+		klog.V(3).Infof("Verified the region of offset %d and length %d", region.Offset, region.Length)
+		r.hash.Reset()
+		r.region++
+	}
+	r.offset += int64(len(data))
+	return nil
+}
@@ -0,0 +1,147 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/image"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+var _ = Describe("Checksum manifest verification", func() {
+	const imageSize = 1024 * 1024
+
+	var (
+		tmpDir   string
+		disk     []byte
+		manifest *ChecksumManifest
+		ts       *httptest.Server
+		authSent []string
+	)
+
+	sha256Hex := func(data []byte) string {
+		digest := sha256.Sum256(data)
+		return hex.EncodeToString(digest[:])
+	}
+
+	BeforeEach(func() {
+		createNbdkitCurl = image.NewMockNbdkitCurl
+		var err error
+		tmpDir, err = os.MkdirTemp("", "checksum-manifest")
+		Expect(err).ToNot(HaveOccurred())
+
+		disk = make([]byte, imageSize)
+		_, err = rand.Read(disk)
+		Expect(err).ToNot(HaveOccurred())
+		manifest = &ChecksumManifest{Algorithm: common.SourceDigestSHA256}
+		for offset := int64(0); offset < imageSize; offset += imageSize / 4 {
+			manifest.Regions = append(manifest.Regions, ChecksumRegion{
+				Offset: offset,
+				Length: imageSize / 4,
+				Digest: sha256Hex(disk[offset : offset+imageSize/4]),
+			})
+		}
+
+		authSent = nil
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authSent = append(authSent, r.Header.Get("Authorization")+r.Header.Get("X-Token"))
+			switch r.URL.Path {
+			case "/redirect/checksums.json":
+				http.Redirect(w, r, "/checksums.json", http.StatusFound)
+			case "/checksums.json":
+				Expect(json.NewEncoder(w).Encode(manifest)).To(Succeed())
+			case "/disk.img":
+				_, _ = w.Write(disk)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		os.Setenv(common.ImporterChecksumManifestURL, ts.URL+"/checksums.json")
+	})
+
+	AfterEach(func() {
+		os.Unsetenv(common.ImporterChecksumManifestURL)
+		createNbdkitCurl = image.NewNbdkitCurl
+		ts.Close()
+		os.RemoveAll(tmpDir)
+	})
+
+	transfer := func() error {
+		ds, err := NewHTTPDataSource(ts.URL+"/disk.img", "", "", "", cdiv1.DataVolumeKubeVirt)
+		Expect(err).ToNot(HaveOccurred())
+		defer ds.Close()
+		phase, err := ds.Info()
+		Expect(err).ToNot(HaveOccurred())
+		// The source is read by the importer to be verified, rather than by qemu-img
+		Expect(phase).To(Equal(ProcessingPhaseTransferDataFile))
+		_, err = ds.TransferFile(filepath.Join(tmpDir, "disk.img"))
+		return err
+	}
+
+	It("should import a source matching the manifest", func() {
+		Expect(transfer()).To(Succeed())
+		content, err := os.ReadFile(filepath.Join(tmpDir, "disk.img"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal(disk))
+	})
+
+	It("should fail the import at the first region not matching the manifest, naming its range", func() {
+		disk[imageSize/4+100] ^= 0xff
+		err := transfer()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("checksum mismatch in the region of offset 262144 and length 262144 (bytes 262144-524287 of the source)"))
+		Expect(filepath.Join(tmpDir, "disk.img")).ToNot(BeAnExistingFile())
+	})
+
+	It("should stop reading the source at the region not matching the manifest", func() {
+		disk[100] ^= 0xff
+		source := &util.CountingReader{Reader: io.NopCloser(bytes.NewReader(disk))}
+		reader, err := newChecksumReader(source, manifest)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = io.Copy(io.Discard, reader)
+		Expect(err).To(MatchError(ContainSubstring("region of offset 0 and length 262144")))
+		read := source.Current
+		Expect(read).To(BeNumerically("<", imageSize/2))
+		_, err = reader.Read(make([]byte, 4096))
+		Expect(err).To(HaveOccurred())
+		Expect(source.Current).To(Equal(read))
+	})
+
+	It("should fail when the source ends before the last region", func() {
+		reader, err := newChecksumReader(io.NopCloser(bytes.NewReader(disk[:imageSize-10])), manifest)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = io.Copy(io.Discard, reader)
+		Expect(err).To(MatchError(ContainSubstring("before the end of the region of offset 786432 and length 262144")))
+	})
+
+	It("should not send the credentials to a manifest on another host, nor on its redirects", func() {
+		os.Setenv(common.ImporterChecksumManifestURL, ts.URL+"/redirect/checksums.json")
+		ep, err := url.Parse(strings.Replace(ts.URL, "127.0.0.1", "localhost", 1) + "/disk.img")
+		Expect(err).ToNot(HaveOccurred())
+		m, err := getChecksumManifestFromEnvironment(context.Background(), ep, "user", "password", "", nil, []string{"X-Token: secret"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(m.Regions).To(HaveLen(4))
+		Expect(authSent).To(Equal([]string{"", ""}))
+	})
+
+	It("should reject a manifest of overlapping regions", func() {
+		manifest.Regions[1].Offset--
+		Expect(manifest.validate()).To(MatchError(ContainSubstring("must be sorted and not overlap")))
+	})
+})
@@ -0,0 +1,173 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+// cloneCheckpointInterval is the number of bytes of a raw clone stream written to the target between two checkpoints,
+// may be overridden in tests
+var cloneCheckpointInterval int64 = 64 * 1024 * 1024
+
+// CloneCheckpointer keeps a checkpoint of the raw clone stream written to the target, so a clone restarted after its
+// source or target pod crashed resumes from the last checkpoint instead of from scratch. A checkpoint is only written
+// once the target is synced up to its offset, and holds the SHA-256 of the chunk written since the previous one, which
+// the source and the target check before resuming.
+type CloneCheckpointer struct {
+	// path of the checkpoint file
+	path string
+	// offset the clone stream resumes from, 0 when it starts from scratch
+	offset      int64
+	chunkOffset int64
+	chunkHash   hash.Hash
+}
+
+// NewCloneCheckpointer creates a new instance of a CloneCheckpointer keeping its checkpoint in the file at path
+func NewCloneCheckpointer(path string) *CloneCheckpointer {
+	return &CloneCheckpointer{
+		path: path,
+	}
+}
+
+// Checkpoint returns the last checkpoint of the clone stream written to dest, or an error telling why the clone
+// cannot resume from it
+func (c *CloneCheckpointer) Checkpoint(dest string) (*util.CloneCheckpoint, error) {
+	data, err := os.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return nil, errors.New("no clone checkpoint")
+	} else if err != nil {
+		return nil, errors.Wrap(err, "unable to read the clone checkpoint")
+	}
+	checkpoint := &util.CloneCheckpoint{}
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, errors.Wrap(err, "unable to parse the clone checkpoint")
+	}
+	if checkpoint.Offset <= 0 || checkpoint.ChunkOffset < 0 || checkpoint.ChunkOffset >= checkpoint.Offset {
+		return nil, errors.Errorf("invalid clone checkpoint at offset %d", checkpoint.Offset)
+	}
+	sum, err := util.Sha256sumRange(dest, checkpoint.ChunkOffset, checkpoint.Offset-checkpoint.ChunkOffset)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to check the target against the clone checkpoint")
+	}
+	if sum != checkpoint.ChunkSHA256 {
+		return nil, errors.Errorf("the target does not match the clone checkpoint at offset %d", checkpoint.Offset)
+	}
+	return checkpoint, nil
+}
+
+// Resume makes the clone stream written to dest resume from offset, which must be the offset of its last checkpoint
+func (c *CloneCheckpointer) Resume(dest string, offset int64) error {
+	checkpoint, err := c.Checkpoint(dest)
+	if err != nil {
+		return err
+	}
+	if checkpoint.Offset != offset {
+		return errors.Errorf("the clone checkpoint is at offset %d, not %d", checkpoint.Offset, offset)
+	}
+	c.offset = offset
+	return nil
+}
+
+// Offset returns the offset the clone stream resumes from, 0 when it starts from scratch
+func (c *CloneCheckpointer) Offset() int64 {
+	return c.offset
+}
+
+// Clear removes the checkpoint
+func (c *CloneCheckpointer) Clear() error {
+	if err := os.Remove(c.path); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "unable to remove the clone checkpoint")
+	}
+	return nil
+}
+
+// stream writes the raw clone stream read from r to dest from the resume offset, checkpointing it as it goes. The
+// checkpoint is removed once the whole stream is written.
+func (c *CloneCheckpointer) stream(r io.Reader, dest string, preallocate bool) error {
+	if c.offset == 0 {
+		if err := c.Clear(); err != nil {
+			return err
+		}
+	}
+	c.chunkOffset = c.offset
+	c.chunkHash = sha256.New()
+	if err := streamRawAt(r, dest, c.offset, preallocate, c.written); err != nil {
+		return err
+	}
+	return c.Clear()
+}
+
+// written checkpoints the stream once cloneCheckpointInterval bytes were written since the previous checkpoint. Not
+// being able to write a checkpoint doesn't fail the clone, it can still resume from the previous one.
+func (c *CloneCheckpointer) written(destFile *os.File, offset int64, chunk []byte) error {
+	c.chunkHash.Write(chunk)
+	if offset-c.chunkOffset < cloneCheckpointInterval {
+		return nil
+	}
+	if err := destFile.Sync(); err != nil {
+		return errors.Wrap(err, "unable to sync the target")
+	}
+	checkpoint := util.CloneCheckpoint{
+		Offset:      offset,
+		ChunkOffset: c.chunkOffset,
+		ChunkSHA256: hex.EncodeToString(c.chunkHash.Sum(nil)),
+	}
+	if err := c.write(checkpoint); err != nil {
+		klog.Warningf("Unable to write the clone checkpoint at offset %d: %v", offset, err)
+	} else {
+		klog.V(3).Infof("Wrote the clone checkpoint at offset %d", offset)
+	}
+	c.chunkOffset = offset
+	c.chunkHash.Reset()
+	return nil
+}
+
+// write replaces the checkpoint file atomically, so a crash never leaves a partial checkpoint
+func (c *CloneCheckpointer) write(checkpoint util.CloneCheckpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	tmpPath := c.path + ".tmp"
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, c.path)
+}
@@ -0,0 +1,138 @@
+package importer
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	testCloneSize       = 10 * 1024 * 1024
+	testCloneInterval   = 1024 * 1024
+	testCloneCrashAfter = 5*testCloneInterval + testCloneInterval/2
+)
+
+// crashingReader fails like a clone source pod crashing once it read crashAfter bytes
+type crashingReader struct {
+	r          io.Reader
+	crashAfter int64
+	read       int64
+}
+
+func (r *crashingReader) Read(p []byte) (int, error) {
+	if r.read >= r.crashAfter {
+		return 0, errors.New("connection reset by peer")
+	}
+	if int64(len(p)) > r.crashAfter-r.read {
+		p = p[:r.crashAfter-r.read]
+	}
+	n, err := r.r.Read(p)
+	r.read += int64(n)
+	return n, err
+}
+
+var _ = Describe("Clone checkpoint", func() {
+	var (
+		tmpDir         string
+		dest           string
+		checkpointPath string
+		source         []byte
+		origInterval   = cloneCheckpointInterval
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "clone-checkpoint")
+		Expect(err).ToNot(HaveOccurred())
+		dest = filepath.Join(tmpDir, "disk.img")
+		checkpointPath = filepath.Join(tmpDir, "checkpoint.json")
+		cloneCheckpointInterval = testCloneInterval
+		source = make([]byte, testCloneSize)
+		rand.New(rand.NewSource(1)).Read(source)
+		// An empty range, zeroed instead of written
+		copy(source[2*testCloneInterval:3*testCloneInterval], make([]byte, testCloneInterval))
+	})
+
+	AfterEach(func() {
+		cloneCheckpointInterval = origInterval
+		os.RemoveAll(tmpDir)
+	})
+
+	streamClone := func(stream io.Reader, checkpointer *CloneCheckpointer) error {
+		ud := NewUploadDataSource(io.NopCloser(stream), dvKubevirt, false)
+		defer ud.Close()
+		ud.SetCloneCheckpointer(checkpointer)
+		phase, err := ud.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferDataFile))
+		_, err = ud.TransferFile(dest)
+		return err
+	}
+
+	crashMidCopy := func() {
+		err := streamClone(&crashingReader{r: bytes.NewReader(source), crashAfter: testCloneCrashAfter}, NewCloneCheckpointer(checkpointPath))
+		Expect(err).To(HaveOccurred())
+	}
+
+	It("should resume a clone stream interrupted mid-copy from the last checkpoint", func() {
+		crashMidCopy()
+		checkpoint, err := NewCloneCheckpointer(checkpointPath).Checkpoint(dest)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(checkpoint.Offset).To(Equal(int64(5 * testCloneInterval)))
+		Expect(checkpoint.ChunkOffset).To(Equal(int64(4 * testCloneInterval)))
+
+		checkpointer := NewCloneCheckpointer(checkpointPath)
+		Expect(checkpointer.Resume(dest, checkpoint.Offset)).To(Succeed())
+		remaining := &crashingReader{r: bytes.NewReader(source[checkpoint.Offset:]), crashAfter: testCloneSize}
+		Expect(streamClone(remaining, checkpointer)).To(Succeed())
+
+		By("Checking the resumed stream skipped the bytes already copied")
+		Expect(remaining.read).To(Equal(testCloneSize - checkpoint.Offset))
+		written, err := os.ReadFile(dest)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bytes.Equal(written, source)).To(BeTrue())
+		_, err = os.Stat(checkpointPath)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("should discard a previous checkpoint when streaming from scratch", func() {
+		crashMidCopy()
+		Expect(os.Remove(dest)).To(Succeed())
+		Expect(streamClone(bytes.NewReader(source), NewCloneCheckpointer(checkpointPath))).To(Succeed())
+		written, err := os.ReadFile(dest)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bytes.Equal(written, source)).To(BeTrue())
+		_, err = NewCloneCheckpointer(checkpointPath).Checkpoint(dest)
+		Expect(err).To(MatchError("no clone checkpoint"))
+	})
+
+	It("should not resume when the target does not match the checkpoint", func() {
+		crashMidCopy()
+		f, err := os.OpenFile(dest, os.O_WRONLY, 0)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = f.WriteAt([]byte{^source[4*testCloneInterval+1]}, 4*testCloneInterval+1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(f.Close()).To(Succeed())
+
+		checkpointer := NewCloneCheckpointer(checkpointPath)
+		err = checkpointer.Resume(dest, 5*testCloneInterval)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("does not match the clone checkpoint"))
+		Expect(checkpointer.Offset()).To(BeZero())
+	})
+
+	It("should not resume from another offset than the checkpoint", func() {
+		crashMidCopy()
+		checkpointer := NewCloneCheckpointer(checkpointPath)
+		err := checkpointer.Resume(dest, 3*testCloneInterval)
+		Expect(err).To(MatchError("the clone checkpoint is at offset 5242880, not 3145728"))
+		Expect(checkpointer.Offset()).To(BeZero())
+	})
+})
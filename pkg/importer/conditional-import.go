@@ -0,0 +1,117 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+const (
+	// importInProgressFile marks a filesystem target whose content is being overwritten by an import, so it is not
+	// trusted by a conditional request of a restarted importer
+	importInProgressFile = ".import-in-progress"
+)
+
+// importTargetDir is the directory of a filesystem import target, may be overridden in tests
+var importTargetDir = common.ImporterVolumePath
+
+// errSourceNotModified is returned when a conditional request tells the source is unchanged since the previous import
+var errSourceNotModified = errors.New("source not modified")
+
+// ConditionalDataSource is the interface of the data sources able to skip the transfer of a source unchanged since the
+// previous import of the target
+type ConditionalDataSource interface {
+	DataSourceInterface
+	// SourceValidators returns the validators of the source, to send with the conditional request of a later import
+	SourceValidators() util.SourceValidators
+	// SourceNotModified tells whether the source was unchanged since the previous import, and was not transferred
+	SourceNotModified() bool
+}
+
+// getSourceValidatorsFromEnvironment returns the validators recorded by the previous import of the target, nil if
+// there are none or the existing content of the target cannot be kept. Only a disk image on a filesystem target can be.
+func getSourceValidatorsFromEnvironment(contentType cdiv1.DataVolumeContentType) *util.SourceValidators {
+	validators := &util.SourceValidators{
+		ETag:         os.Getenv(common.ImporterSourceETag),
+		LastModified: os.Getenv(common.ImporterSourceLastModified),
+	}
+	if *validators == (util.SourceValidators{}) || contentType != cdiv1.DataVolumeKubeVirt {
+		return nil
+	}
+	if !targetHasContent() {
+		klog.V(1).Infoln("The target has no complete content, ignoring the validators of the previous import")
+		return nil
+	}
+	return validators
+}
+
+// targetHasContent tells whether the filesystem target holds the disk image of a completed import
+func targetHasContent() bool {
+	if _, err := os.Stat(filepath.Join(importTargetDir, importInProgressFile)); !os.IsNotExist(err) {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(importTargetDir, common.DiskImageName))
+	return err == nil && info.Mode().IsRegular() && info.Size() > 0
+}
+
+// markImportInProgress marks the content of the filesystem target as being overwritten
+func markImportInProgress() error {
+	f, err := os.OpenFile(filepath.Join(importTargetDir, importInProgressFile), os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrap(err, "unable to mark the import in progress")
+	}
+	return f.Close()
+}
+
+// CompleteConditionalImport marks the content of the target as complete, so a later import may keep it when its
+// source is unchanged
+func CompleteConditionalImport() error {
+	if err := os.Remove(filepath.Join(importTargetDir, importInProgressFile)); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "unable to mark the import complete")
+	}
+	return nil
+}
+
+// addConditionalHeaders makes req conditional on the source having changed since it returned validators
+func addConditionalHeaders(req *http.Request, validators *util.SourceValidators) {
+	if validators == nil {
+		return
+	}
+	if validators.ETag != "" {
+		req.Header.Set("If-None-Match", validators.ETag)
+	}
+	if validators.LastModified != "" {
+		req.Header.Set("If-Modified-Since", validators.LastModified)
+	}
+}
+
+// sourceValidatorsFromResponse returns the validators of the source returned in resp
+func sourceValidatorsFromResponse(resp *http.Response) util.SourceValidators {
+	return util.SourceValidators{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+}
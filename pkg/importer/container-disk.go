@@ -0,0 +1,232 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"archive/tar"
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/containers/image/v5/pkg/blobinfocache"
+	"github.com/containers/image/v5/types"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/image"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+const (
+	// whOpaqueDir is the whiteout hiding the content of its directory in the lower layers
+	whOpaqueDir = whFilePrefix + whFilePrefix + ".opq"
+	// rawDiskSectorSize is the size of the sectors a raw disk is made of
+	rawDiskSectorSize = 512
+)
+
+// containerDiskLayers extracts the disks of a containerDisk image, reading its layers from the top one, so a disk of
+// an upper layer replaces or removes the disk at the same path of the lower layers
+type containerDiskLayers struct {
+	destDir string
+	// diskPath selects the disk to extract, relative to the disk directory of the image, any disk if empty
+	diskPath string
+	// shadowed are the paths of the entries of the upper layers, which hide the same paths of the lower layers
+	shadowed map[string]bool
+	// hiddenDirs are the directories removed or made opaque by the upper layers
+	hiddenDirs []string
+	// disks are the disks found in the layer holding the disk, relative to the disk directory of the image. Only the
+	// first one is extracted.
+	disks []string
+}
+
+// CopyContainerDisk extracts the disk of the containerDisk image at url to the disk directory of destDir, and returns
+// the disks found in the layer holding it, relative to its disk directory. A containerDisk image holds its disk in its
+// /disk/ directory, the disk at diskPath in the directory is extracted when diskPath is not empty. Otherwise the first
+// file of the directory with the format of a disk image is extracted, the caller checks the layer holds a single disk.
+// The layers below the one holding the disk are not read.
+func CopyContainerDisk(url, destDir, diskPath, accessKey, secKey, certDir string, insecureRegistry bool) ([]string, error) {
+	klog.Infof("Downloading containerDisk image from '%v', copying its disk to '%v'", redactSourceURL(url), destDir)
+
+	ctx, cancel := commandTimeoutContext()
+	defer cancel()
+	srcCtx, src, imgCloser, err := openRegistryImage(ctx, url, accessKey, secKey, certDir, insecureRegistry)
+	if err != nil {
+		return nil, err
+	}
+	defer closeImage(src)
+	defer imgCloser.Close()
+
+	c := &containerDiskLayers{destDir: destDir, diskPath: diskPath, shadowed: map[string]bool{}}
+	cache := blobinfocache.DefaultCache(srcCtx)
+	layers := imgCloser.LayerInfos()
+	for i := len(layers) - 1; i >= 0; i-- {
+		klog.Infof("Processing layer %+v", layers[i])
+		if err := c.processLayer(ctx, srcCtx, src, layers[i], cache); err != nil {
+			return nil, err
+		}
+		// The disk of an upper layer hides the disks of the lower layers
+		if len(c.disks) > 0 {
+			break
+		}
+	}
+	return c.disks, nil
+}
+
+func (c *containerDiskLayers) processLayer(ctx context.Context, sys *types.SystemContext, src types.ImageSource, layer types.BlobInfo, cache types.BlobInfoCache) error {
+	var reader io.ReadCloser
+	err := retryRegistryRequest(ctx, func() error {
+		var err error
+		reader, _, err = src.GetBlob(ctx, layer, cache)
+		return err
+	})
+	if err != nil {
+		klog.Errorf("Could not read layer: %v", err)
+		return errors.Wrap(err, "Could not read layer")
+	}
+	fr, err := NewFormatReaders(reader, 0)
+	if err != nil {
+		return errors.Wrap(err, "Could not read layer")
+	}
+	defer fr.Close()
+
+	// The entries of the layer only hide the ones of the lower layers
+	layerShadowed := map[string]bool{}
+	var layerHiddenDirs []string
+	tarReader := tar.NewReader(fr.TopReader())
+	for {
+		hdr, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			klog.Errorf("Error reading layer: %v", err)
+			return errors.Wrap(err, "Error reading layer")
+		}
+
+		name := path.Clean("/" + hdr.Name)[1:]
+		if !strings.HasPrefix(name, containerDiskImageDir+"/") || c.isHidden(name) {
+			continue
+		}
+		base := path.Base(name)
+		if base == whOpaqueDir {
+			layerHiddenDirs = append(layerHiddenDirs, path.Dir(name))
+			continue
+		}
+		if strings.HasPrefix(base, whFilePrefix) {
+			removed := path.Join(path.Dir(name), strings.TrimPrefix(base, whFilePrefix))
+			layerShadowed[removed] = true
+			layerHiddenDirs = append(layerHiddenDirs, removed)
+			continue
+		}
+		duplicate := layerShadowed[name]
+		layerShadowed[name] = true
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		disk := strings.TrimPrefix(name, containerDiskImageDir+"/")
+		if c.diskPath != "" && disk != c.diskPath {
+			continue
+		}
+		// The other files of the disk directory, like a checksum or a README, are not disks
+		content := bufio.NewReaderSize(tarReader, image.MaxExpectedHdrSize)
+		if c.diskPath == "" && !isDiskImage(content, hdr.Size) {
+			klog.Infof("File '%v' found in the layer is not a disk image, skipping it", hdr.Name)
+			continue
+		}
+		if len(c.disks) > 0 && c.disks[0] != disk {
+			klog.Infof("Disk '%v' found in the layer", hdr.Name)
+			if !duplicate {
+				c.disks = append(c.disks, disk)
+			}
+			continue
+		}
+		klog.Infof("Disk '%v' found in the layer, extracting it", hdr.Name)
+		destFile := filepath.Join(c.destDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(destFile), os.ModePerm); err != nil {
+			klog.Errorf("Error creating output file's directory: %v", err)
+			return errors.Wrap(err, "Error creating output file's directory")
+		}
+		if err := util.StreamDataToFile(content, destFile); err != nil {
+			klog.Errorf("Error copying file: %v", err)
+			return errors.Wrap(err, "Error copying file")
+		}
+		if len(c.disks) == 0 {
+			c.disks = []string{disk}
+		}
+	}
+
+	for name := range layerShadowed {
+		c.shadowed[name] = true
+	}
+	c.hiddenDirs = append(c.hiddenDirs, layerHiddenDirs...)
+	return nil
+}
+
+// isHidden tells whether the upper layers replaced or removed the entry at name
+func (c *containerDiskLayers) isHidden(name string) bool {
+	if c.shadowed[name] {
+		return true
+	}
+	for _, dir := range c.hiddenDirs {
+		if strings.HasPrefix(name, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// isDiskImage tells whether the file of the given size read by r has the format of a disk image, the header of a
+// known format or the size of a raw disk, a whole number of sectors
+func isDiskImage(r *bufio.Reader, size int64) bool {
+	header := make([]byte, image.MaxExpectedHdrSize)
+	peeked, _ := r.Peek(image.MaxExpectedHdrSize)
+	copy(header, peeked)
+	for _, hdr := range image.CopyKnownHdrs() {
+		if hdr.Match(header) {
+			return true
+		}
+	}
+	return size > 0 && size%rawDiskSectorSize == 0
+}
+
+// validateContainerDisks checks the disks found in a containerDisk image hold the disk to import, the disk at
+// diskPath if not empty, or the single disk of the layer holding it otherwise
+func validateContainerDisks(disks []string, diskPath string) error {
+	if diskPath != "" {
+		if len(disks) == 0 {
+			return errors.Errorf("disk %s not found in the /%s/ directory of the containerDisk image", diskPath, containerDiskImageDir)
+		}
+		return nil
+	}
+	switch len(disks) {
+	case 0:
+		return errors.Errorf("no disk found in the /%s/ directory of the containerDisk image, the disk image must be a file of /%s/",
+			containerDiskImageDir, containerDiskImageDir)
+	case 1:
+		return nil
+	}
+	sorted := append([]string{}, disks...)
+	sort.Strings(sorted)
+	return errors.Errorf("found %d disks in the /%s/ directory of the containerDisk image (%s), a containerDisk image holds a single disk, select one with the path of a disk of the registry source",
+		len(sorted), containerDiskImageDir, strings.Join(sorted, ", "))
+}
@@ -0,0 +1,98 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("containerDisk image", func() {
+	var tmpDir string
+
+	// diskContent is the content of a disk, large enough for the format of the disk to be probed
+	diskContent := func(name string) string {
+		return strings.Repeat(name, 1024)
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "container-disk")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	transfer := func(diskPath string, layers ...map[string]string) (*RegistryDataSource, error) {
+		image := createRegistryImage(tmpDir, layers...)
+		scratchPath := filepath.Join(tmpDir, "scratch")
+		Expect(os.Mkdir(scratchPath, 0700)).To(Succeed())
+		ds := NewRegistryDataSource("oci-archive:"+image, "", "", "", true, diskPath)
+		result, err := ds.Transfer(scratchPath)
+		if err != nil {
+			Expect(result).To(Equal(ProcessingPhaseError))
+			return ds, err
+		}
+		Expect(result).To(Equal(ProcessingPhaseConvert))
+		return ds, nil
+	}
+
+	table.DescribeTable("should import the disk in /disk/", func(diskPath, expectedDisk, expectedContent string, layers ...map[string]string) {
+		ds, err := transfer(diskPath, layers...)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ds.GetURL().Path).To(Equal(filepath.Join(tmpDir, "scratch", containerDiskImageDir, expectedDisk)))
+		content, err := os.ReadFile(ds.GetURL().Path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal(expectedContent))
+	},
+		table.Entry("of a single layer image", "", "disk.qcow2", diskContent("disk"),
+			map[string]string{"disk/disk.qcow2": diskContent("disk")}),
+		table.Entry("ignoring the files of /disk/ which are not disk images", "", "disk.img", diskContent("disk"),
+			map[string]string{"disk/disk.img.sha256": "checksum", "disk/README": "readme", "disk/disk.img": diskContent("disk")}),
+		table.Entry("of the upper layer holding a disk, without reading the lower layers", "", "data.img", diskContent("data"),
+			map[string]string{"disk/os.img": diskContent("os")},
+			map[string]string{"disk/data.img": diskContent("data")}),
+		table.Entry("ignoring the files outside of /disk/", "", "disk.qcow2", diskContent("disk"),
+			map[string]string{"etc/hosts": "hosts", "diskette/other.img": "other"},
+			map[string]string{"./disk/disk.qcow2": diskContent("disk")}),
+		table.Entry("replaced by an upper layer", "", "disk.qcow2", diskContent("new disk"),
+			map[string]string{"disk/disk.qcow2": diskContent("old disk")},
+			map[string]string{"disk/disk.qcow2": diskContent("new disk")}),
+		table.Entry("added by an upper layer removing the disk of a lower layer", "", "new.qcow2", diskContent("new disk"),
+			map[string]string{"disk/old.qcow2": diskContent("old disk")},
+			map[string]string{"disk/.wh.old.qcow2": "", "disk/new.qcow2": diskContent("new disk")}),
+		table.Entry("of an upper layer making /disk/ opaque", "", "new.qcow2", diskContent("new disk"),
+			map[string]string{"disk/old.qcow2": diskContent("old disk")},
+			map[string]string{"disk/.wh..wh..opq": "", "disk/new.qcow2": diskContent("new disk")}),
+		table.Entry("selected by its path and replaced by an upper layer", "data.img", "data.img", diskContent("new data"),
+			map[string]string{"disk/os.img": diskContent("os"), "disk/data.img": diskContent("old data")},
+			map[string]string{"disk/data.img": diskContent("new data")}),
+		table.Entry("selected by its exact path, ignoring the files it prefixes", "os.qcow2", "os.qcow2", diskContent("os"),
+			map[string]string{"disk/os.qcow2": diskContent("os")},
+			map[string]string{"disk/os.qcow2.sha256": "checksum"}),
+	)
+
+	table.DescribeTable("should fail", func(diskPath, expectedError string, layers ...map[string]string) {
+		_, err := transfer(diskPath, layers...)
+		Expect(err).To(MatchError(ContainSubstring(expectedError)))
+	},
+		table.Entry("without a disk in /disk/", "", "no disk found in the /disk/ directory of the containerDisk image",
+			map[string]string{"etc/hosts": "hosts", "disk.img": diskContent("disk")}),
+		table.Entry("with the disk removed by an upper layer", "", "no disk found in the /disk/ directory of the containerDisk image",
+			map[string]string{"disk/disk.img": diskContent("disk")},
+			map[string]string{"disk/.wh.disk.img": ""}),
+		table.Entry("with only files which are not disk images in /disk/", "", "no disk found in the /disk/ directory of the containerDisk image",
+			map[string]string{"disk/README": "readme"}),
+		table.Entry("with several disks in the layer holding the disk", "", "found 2 disks in the /disk/ directory of the containerDisk image (data.img, os.img)",
+			map[string]string{"disk/os.img": diskContent("os"), "disk/data.img": diskContent("data")}),
+		table.Entry("without the selected disk", "missing.img", "disk missing.img not found in the /disk/ directory of the containerDisk image",
+			map[string]string{"disk/disk.img": diskContent("disk")}),
+		table.Entry("with the selected disk only prefixing a disk", "disk", "disk disk not found in the /disk/ directory of the containerDisk image",
+			map[string]string{"disk/disk.img": diskContent("disk")}),
+	)
+})
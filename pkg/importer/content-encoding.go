@@ -0,0 +1,121 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// acceptedContentEncoding is the Accept-Encoding of the source requests, the gzip the Go client asks for by default.
+// Setting it stops the client from decoding the body itself, which it would only do when it asked for gzip.
+const acceptedContentEncoding = "gzip"
+
+// contentEncodings returns the transport encodings applied to the body of the response, in the order they were
+// applied, without identity
+func contentEncodings(resp *http.Response) []string {
+	var encodings []string
+	for _, header := range resp.Header.Values("Content-Encoding") {
+		for _, encoding := range strings.Split(header, ",") {
+			encoding = strings.ToLower(strings.TrimSpace(encoding))
+			if encoding != "" && encoding != "identity" {
+				encodings = append(encodings, encoding)
+			}
+		}
+	}
+	return encodings
+}
+
+// decodeContentEncoding returns a reader of the body of the response without its transport encodings, so the format
+// readers see the bytes of the source itself. The body is returned as is when it is not encoded.
+func decodeContentEncoding(resp *http.Response) (io.ReadCloser, error) {
+	encodings := contentEncodings(resp)
+	if len(encodings) == 0 {
+		return resp.Body, nil
+	}
+	klog.V(1).Infof("Decoding the %s Content-Encoding of the source", strings.Join(encodings, ", "))
+	var reader io.Reader = resp.Body
+	closers := []io.Closer{resp.Body}
+	// The encodings are listed in the order they were applied, they are decoded in the reverse order
+	for i := len(encodings) - 1; i >= 0; i-- {
+		decoder, err := newContentDecoder(encodings[i], reader)
+		if err != nil {
+			for _, closer := range closers {
+				closer.Close()
+			}
+			return nil, err
+		}
+		reader = decoder
+		closers = append(closers, decoder)
+	}
+	return &decodedBody{Reader: reader, closers: closers}, nil
+}
+
+func newContentDecoder(encoding string, r io.Reader) (io.ReadCloser, error) {
+	switch encoding {
+	case "gzip", "x-gzip":
+		decoder, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not decode the gzip Content-Encoding")
+		}
+		return decoder, nil
+	case "deflate":
+		// deflate is the zlib format, though some servers send a raw deflate stream
+		buffered := bufio.NewReader(r)
+		if header, err := buffered.Peek(2); err == nil && isZlibHeader(header) {
+			decoder, err := zlib.NewReader(buffered)
+			if err != nil {
+				return nil, errors.Wrap(err, "could not decode the deflate Content-Encoding")
+			}
+			return decoder, nil
+		}
+		return flate.NewReader(buffered), nil
+	case "br":
+		return io.NopCloser(brotli.NewReader(r)), nil
+	}
+	return nil, errors.Errorf("unsupported Content-Encoding %q, the supported encodings are gzip, deflate and br", encoding)
+}
+
+// isZlibHeader tells whether the header is a zlib header of a deflate stream
+func isZlibHeader(header []byte) bool {
+	return header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0
+}
+
+// decodedBody is the decoded body of a response, closing the decoders and the body
+type decodedBody struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (b *decodedBody) Close() error {
+	var err error
+	for i := len(b.closers) - 1; i >= 0; i-- {
+		if closeErr := b.closers[i].Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,121 @@
+package importer
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/image"
+)
+
+var _ = Describe("HTTP Content-Encoding", func() {
+	var (
+		tmpDir         string
+		ts             *httptest.Server
+		acceptEncoding string
+	)
+
+	encode := func(encoding string, data []byte) []byte {
+		var buf bytes.Buffer
+		var writer io.WriteCloser
+		switch encoding {
+		case "gzip":
+			writer = gzip.NewWriter(&buf)
+		case "deflate":
+			writer = zlib.NewWriter(&buf)
+		case "raw-deflate":
+			writer, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+		case "br":
+			writer = brotli.NewWriter(&buf)
+		default:
+			return data
+		}
+		_, err := writer.Write(data)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(writer.Close()).To(Succeed())
+		return buf.Bytes()
+	}
+
+	serve := func(encoding string, body []byte) {
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			acceptEncoding = r.Header.Get("Accept-Encoding")
+			if encoding != "" {
+				w.Header().Set("Content-Encoding", encoding)
+			}
+			w.Header().Set("Accept-Ranges", "bytes")
+			http.ServeContent(w, r, "disk.img", time.Time{}, bytes.NewReader(body))
+		}))
+	}
+
+	BeforeEach(func() {
+		createNbdkitCurl = image.NewMockNbdkitCurl
+		var err error
+		tmpDir, err = os.MkdirTemp("", "content-encoding")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		createNbdkitCurl = image.NewNbdkitCurl
+		if ts != nil {
+			ts.Close()
+		}
+		os.RemoveAll(tmpDir)
+	})
+
+	table.DescribeTable("should decode the transport encoding of a qcow2 image before detecting its format", func(encoding, headerValue string) {
+		serve(headerValue, encode(encoding, cirrosData))
+		ds, err := NewHTTPDataSource(ts.URL+"/disk.img", "", "", "", cdiv1.DataVolumeKubeVirt)
+		Expect(err).ToNot(HaveOccurred())
+		defer ds.Close()
+		Expect(acceptEncoding).To(Equal(acceptedContentEncoding))
+
+		phase, err := ds.Info()
+		Expect(err).ToNot(HaveOccurred())
+		// qemu-img would read the encoded bytes from the server, the decoded image is converted from scratch space
+		Expect(phase).To(Equal(ProcessingPhaseTransferScratch))
+		Expect(ds.readers.ImageFormat()).To(Equal("qcow2"))
+		Expect(ds.readers.Archived).To(BeFalse())
+
+		phase, err = ds.Transfer(tmpDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseConvert))
+		content, err := os.ReadFile(filepath.Join(tmpDir, tempFile))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal(cirrosData))
+	},
+		table.Entry("gzip", "gzip", "gzip"),
+		table.Entry("x-gzip", "gzip", "x-gzip"),
+		table.Entry("deflate", "deflate", "deflate"),
+		table.Entry("raw deflate", "raw-deflate", "deflate"),
+		table.Entry("br", "br", "br"),
+	)
+
+	It("should let qemu-img read a source without transport encoding", func() {
+		serve("identity", cirrosData)
+		ds, err := NewHTTPDataSource(ts.URL+"/disk.img", "", "", "", cdiv1.DataVolumeKubeVirt)
+		Expect(err).ToNot(HaveOccurred())
+		defer ds.Close()
+		phase, err := ds.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseConvert))
+	})
+
+	It("should fail on an unsupported transport encoding", func() {
+		serve("compress", cirrosData)
+		_, err := NewHTTPDataSource(ts.URL+"/disk.img", "", "", "", cdiv1.DataVolumeKubeVirt)
+		Expect(err).To(MatchError(ContainSubstring(`unsupported Content-Encoding "compress"`)))
+	})
+})
@@ -18,17 +18,22 @@ package importer
 
 import (
 	"fmt"
+	"math"
 	"net/url"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/klog/v2"
 
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/image"
 	"kubevirt.io/containerized-data-importer/pkg/util"
+	prometheusutil "kubevirt.io/containerized-data-importer/pkg/util/prometheus"
 )
 
 var qemuOperations = image.NewQEMUOperations()
@@ -62,6 +67,15 @@ const (
 	ProcessingPhaseMergeDelta ProcessingPhase = "MergeDelta"
 )
 
+// qcow2MetadataReserve is the space reserved for the header and the L1 and refcount tables of a qcow2 target
+const qcow2MetadataReserve = 1024 * 1024
+
+// qcow2DefaultClusterSize is the qemu-img default size of the clusters of a qcow2 image
+const qcow2DefaultClusterSize = 64 * 1024
+
+// luksHeaderReserve is the space reserved for the header and the key slots of a LUKS target
+const luksHeaderReserve = 2 * 1024 * 1024
+
 // ValidationSizeError is an error indication size validation failure.
 type ValidationSizeError struct {
 	err error
@@ -79,6 +93,10 @@ var ErrInvalidPath = fmt.Errorf("invalid transfer path")
 var getAvailableSpaceBlockFunc = util.GetAvailableSpaceBlock
 var getAvailableSpaceFunc = util.GetAvailableSpace
 
+// noSpaceLeftThreshold is the available space under which a filesystem is considered the one a write failing with
+// ENOSPC ran out of
+const noSpaceLeftThreshold = 1024 * 1024
+
 // DataSourceInterface is the interface all data sources should implement.
 type DataSourceInterface interface {
 	// Info is called to get initial information about the data.
@@ -121,8 +139,37 @@ type DataProcessor struct {
 	preallocation bool
 	// preallocationApplied is used to pass information whether preallocation has been performed, or not
 	preallocationApplied bool
+	// passthrough tells whether the image already had the target format, and was copied as is instead of converted
+	passthrough bool
+	// withoutQemuImg tells whether the raw image was copied without qemu-img, as it could not be run
+	withoutQemuImg bool
+	// shrinkToUsedSize is the flag shrinking the image to the used space of its filesystem instead of resizing it
+	shrinkToUsedSize bool
+	// targetFormat is the format of the image written to the target, raw when empty
+	targetFormat string
+	// targetCompression is the compression type of the clusters of a qcow2 target, uncompressed when empty
+	targetCompression string
+	// qcow2ClusterSize is the size of the clusters of a qcow2 target, the qemu-img default when 0
+	qcow2ClusterSize int64
+	// compactTarget rewrites a qcow2 source with qemu-img instead of copying it as is, defragmenting it
+	compactTarget bool
+	// encryptionKeyFile is the file holding the passphrase of a LUKS target
+	encryptionKeyFile string
+	// stagedImage is the raw image staged in scratch space to be converted into the LUKS container, instead of being
+	// written to the target by the data source
+	stagedImage *url.URL
+	// freeSpaceMargin is the space kept free on the target, none when nil
+	freeSpaceMargin *util.FreeSpaceMargin
+	// scratchSpaceLimit is the size limit of an emptyDir scratch space, unlimited when 0
+	scratchSpaceLimit int64
+	// targetImageInfo is the format and compression of the image written to the target, empty for a raw image
+	targetImageInfo util.TargetImageInfo
+	// imageInfo is the format and virtual size of the source image, read before converting it
+	imageInfo util.ImageInfo
 	// phaseExecutors is a mapping from the given processing phase to its execution function. The function returns the next processing phase or error.
 	phaseExecutors map[ProcessingPhase]func() (ProcessingPhase, error)
+	// phaseDurations is the time spent executing each processing phase
+	phaseDurations map[ProcessingPhase]time.Duration
 }
 
 // NewDataProcessor create a new instance of a data processor using the passed in data provider.
@@ -136,6 +183,7 @@ func NewDataProcessor(dataSource DataSourceInterface, dataFile, dataDir, scratch
 		requestImageSize:   requestImageSize,
 		filesystemOverhead: filesystemOverhead,
 		preallocation:      preallocation,
+		phaseDurations:     make(map[ProcessingPhase]time.Duration),
 	}
 	// Calculate available space before doing anything.
 	dp.availableSpace = dp.calculateTargetSize()
@@ -152,6 +200,58 @@ func (dp *DataProcessor) RegisterPhaseExecutor(pp ProcessingPhase, executor func
 	dp.phaseExecutors[pp] = executor
 }
 
+// SetShrinkToUsedSize makes the resize phase shrink the image to the used space of its filesystem, when its layout is
+// safely shrinkable, instead of expanding it to the requested size
+func (dp *DataProcessor) SetShrinkToUsedSize(shrink bool) {
+	dp.shrinkToUsedSize = shrink
+}
+
+// SetTargetFormat makes the convert phase write a qcow2 image to the target instead of a raw one, with its clusters
+// compressed with the given compression type when it is not empty. Preallocation and shrinking only apply to raw
+// targets.
+func (dp *DataProcessor) SetTargetFormat(format, compression string) {
+	if format == common.ImportTargetFormatRaw {
+		format = ""
+	}
+	dp.targetFormat = format
+	dp.targetCompression = compression
+}
+
+// SetQcow2Layout makes the convert phase write a qcow2 target with clusters of clusterSize bytes, the qemu-img default
+// when 0, and rewrite a qcow2 source with qemu-img convert when compact is true, or when its cluster size differs,
+// instead of copying it as is. qemu-img writes the allocated clusters in order, defragmenting the image.
+func (dp *DataProcessor) SetQcow2Layout(clusterSize int64, compact bool) {
+	dp.qcow2ClusterSize = clusterSize
+	dp.compactTarget = compact
+}
+
+// SetTargetEncryption makes the convert phase write the raw image into a LUKS container on the target block device,
+// with the passphrase read from keyFile, when it is not empty. It overrides the target format.
+func (dp *DataProcessor) SetTargetEncryption(keyFile string) {
+	if keyFile == "" {
+		return
+	}
+	dp.targetFormat = common.ImportTargetFormatLuks
+	dp.targetCompression = ""
+	dp.encryptionKeyFile = keyFile
+}
+
+// SetFreeSpaceMargin makes the import keep the margin free on the usable space of the target, left once the filesystem
+// overhead is reserved, failing before writing the image when the image and the margin don't fit
+func (dp *DataProcessor) SetFreeSpaceMargin(margin *util.FreeSpaceMargin) {
+	dp.freeSpaceMargin = margin
+	if margin != nil {
+		usableSpace := util.GetUsableSpace(dp.filesystemOverhead, dp.availableSpace)
+		klog.Infof("Keeping a free-space margin of %s, the image can use %d bytes of the %d usable bytes of the target", margin, dp.imageSpace(), usableSpace)
+	}
+}
+
+// SetScratchSpaceLimit sets the size limit of an emptyDir scratch space. The pod is evicted when the emptyDir grows
+// past its limit instead of the writes failing, so a source known to be larger fails before being transferred.
+func (dp *DataProcessor) SetScratchSpaceLimit(limit int64) {
+	dp.scratchSpaceLimit = limit
+}
+
 // ProcessData is the main synchronous processing loop
 func (dp *DataProcessor) ProcessData() error {
 	return dp.ProcessDataWithPause()
@@ -178,11 +278,18 @@ func (dp *DataProcessor) initDefaultPhases() {
 		return pp, err
 	})
 	dp.RegisterPhaseExecutor(ProcessingPhaseTransferScratch, func() (ProcessingPhase, error) {
+		if needed := dp.storedSize(); dp.scratchSpaceLimit > 0 && needed > dp.scratchSpaceLimit {
+			err := dp.scratchSpaceExhaustedError(errors.Errorf("the source needs %d bytes, the scratch space is limited to %d bytes", needed, dp.scratchSpaceLimit))
+			return ProcessingPhaseError, errors.Wrap(err, "Unable to transfer source data to scratch space")
+		}
 		pp, err := dp.source.Transfer(dp.scratchDataDir)
 		if err == ErrInvalidPath {
 			// Passed in invalid scratch space path, return scratch space needed error.
 			err = ErrRequiresScratchSpace
 		} else if err != nil {
+			if util.IsNoSpaceLeft(err) {
+				err = dp.scratchSpaceExhaustedError(err)
+			}
 			err = errors.Wrap(err, "Unable to transfer source data to scratch space")
 		}
 		return pp, err
@@ -195,24 +302,35 @@ func (dp *DataProcessor) initDefaultPhases() {
 		return pp, err
 	})
 	dp.RegisterPhaseExecutor(ProcessingPhaseTransferDataFile, func() (ProcessingPhase, error) {
+		if dp.encryptionKeyFile != "" {
+			return dp.stageForEncryption()
+		}
+		// the raw image is written to the target as is, without the convert phase validating it first
+		if err := dp.validateFreeSpaceMargin(dp.storedSize(), dp.imageSpace()); err != nil {
+			return ProcessingPhaseError, err
+		}
 		pp, err := dp.source.TransferFile(dp.dataFile)
 		if err != nil {
-			err = errors.Wrap(err, "Unable to transfer source data to target file")
+			err = errors.Wrap(dp.noSpaceLeftError(err), "Unable to transfer source data to target file")
 		}
 		return pp, err
 	})
 	dp.RegisterPhaseExecutor(ProcessingPhaseValidatePause, func() (ProcessingPhase, error) {
 		pp := ProcessingPhasePause
-		err := dp.validate(dp.source.GetURL())
+		err := dp.validate(dp.source.GetURL(), dp.imageSpace())
 		if err != nil {
 			pp = ProcessingPhaseError
 		}
 		return pp, err
 	})
 	dp.RegisterPhaseExecutor(ProcessingPhaseConvert, func() (ProcessingPhase, error) {
-		pp, err := dp.convert(dp.source.GetURL())
+		imageURL := dp.source.GetURL()
+		if dp.stagedImage != nil {
+			imageURL = dp.stagedImage
+		}
+		pp, err := dp.convert(imageURL)
 		if err != nil {
-			err = errors.Wrap(err, "Unable to convert source data to target format")
+			err = errors.Wrap(dp.noSpaceLeftError(err), "Unable to convert source data to target format")
 		}
 		return pp, err
 	})
@@ -232,6 +350,28 @@ func (dp *DataProcessor) initDefaultPhases() {
 	})
 }
 
+// stageForEncryption transfers the raw image the data source would write to the target in scratch space instead, for
+// the convert phase to write it into the LUKS container. Writing it to the target would leave it unencrypted.
+func (dp *DataProcessor) stageForEncryption() (ProcessingPhase, error) {
+	if size, err := util.GetAvailableSpace(dp.scratchDataDir); dp.scratchDataDir == "" || err != nil || size <= 0 {
+		return ProcessingPhaseError, ErrRequiresScratchSpace
+	}
+	if needed := dp.storedSize(); dp.scratchSpaceLimit > 0 && needed > dp.scratchSpaceLimit {
+		err := dp.scratchSpaceExhaustedError(errors.Errorf("the source needs %d bytes, the scratch space is limited to %d bytes", needed, dp.scratchSpaceLimit))
+		return ProcessingPhaseError, errors.Wrap(err, "Unable to transfer source data to scratch space")
+	}
+	file := filepath.Join(dp.scratchDataDir, tempFile)
+	klog.V(1).Infoln("Staging the raw image in scratch space to encrypt it")
+	if _, err := dp.source.TransferFile(file); err != nil {
+		if util.IsNoSpaceLeft(err) {
+			err = dp.scratchSpaceExhaustedError(err)
+		}
+		return ProcessingPhaseError, errors.Wrap(err, "Unable to transfer source data to scratch space")
+	}
+	dp.stagedImage = &url.URL{Path: file}
+	return ProcessingPhaseConvert, nil
+}
+
 // ProcessDataWithPause is the main processing loop.
 func (dp *DataProcessor) ProcessDataWithPause() error {
 	visited := make(map[ProcessingPhase]bool, len(dp.phaseExecutors))
@@ -245,41 +385,152 @@ func (dp *DataProcessor) ProcessDataWithPause() error {
 		if !ok {
 			return errors.Errorf("Unknown processing phase %s", dp.currentPhase)
 		}
+		prometheusutil.SetPhase(string(dp.currentPhase))
+		start := time.Now()
 		nextPhase, err := executor()
+		dp.phaseDurations[dp.currentPhase] += time.Since(start)
 		visited[dp.currentPhase] = true
 		if err != nil {
 			klog.Errorf("%+v", err)
+			prometheusutil.SetLastError(err)
 			return err
 		}
 		dp.currentPhase = nextPhase
 		klog.V(1).Infof("New phase: %s\n", dp.currentPhase)
 	}
+	prometheusutil.SetPhase(string(dp.currentPhase))
 	return nil
 }
 
-func (dp *DataProcessor) validate(url *url.URL) error {
+func (dp *DataProcessor) validate(url *url.URL, availableSpace int64) error {
 	klog.V(1).Infoln("Validating image")
-	err := qemuOperations.Validate(url, dp.availableSpace)
+	err := qemuOperations.Validate(url, availableSpace)
 	if err != nil {
 		return ValidationSizeError{err: err}
 	}
 	return nil
 }
 
+// validateFreeSpaceMargin fails with a message naming the free-space margin when an image of the virtual size only fits
+// in the target without the margin, before it is written. Other validation failures are left to the image validation.
+func (dp *DataProcessor) validateFreeSpaceMargin(virtualSize, availableSpace int64) error {
+	if dp.freeSpaceMargin == nil || virtualSize <= availableSpace {
+		return nil
+	}
+	usableSpace := util.GetUsableSpace(dp.filesystemOverhead, dp.availableSpace)
+	return ValidationSizeError{err: errors.Errorf("Virtual image size %d and the free-space margin of %s don't fit in the usable storage %d. A larger PVC is required.",
+		virtualSize, dp.freeSpaceMargin, usableSpace)}
+}
+
+// shrinksTarget returns true if the resize phase will shrink the image, which only happens to a raw image written by
+// qemu-img to a filesystem when e2fsprogs is installed
+func (dp *DataProcessor) shrinksTarget() bool {
+	if !dp.shrinkToUsedSize || dp.targetFormat != "" || dp.withoutQemuImg || !shrinkToolsAvailable() {
+		return false
+	}
+	size, _ := getAvailableSpaceBlockFunc(dp.dataFile)
+	return size < 0
+}
+
+// validateShrinkable validates an image shrunk in the resize phase. Its virtual size only has to fit in the available
+// space once shrunk, which is validated after the resize phase, but the data allocated in the image has to fit to be
+// written at all.
+func (dp *DataProcessor) validateShrinkable(url *url.URL, availableSpace int64) error {
+	klog.V(1).Infoln("Validating image to shrink")
+	info, err := qemuOperations.Info(url)
+	if err != nil {
+		return err
+	}
+	if info.ActualSize > availableSpace {
+		return ValidationSizeError{err: errors.Errorf("Allocated image size %d is larger than the reported available storage %d. A larger PVC is required.", info.ActualSize, availableSpace)}
+	}
+	if err := qemuOperations.Validate(url, info.VirtualSize); err != nil {
+		return ValidationSizeError{err: err}
+	}
+	return nil
+}
+
 // convert is called when convert the image from the url to a RAW disk image. Source formats include RAW/QCOW2 (Raw to raw conversion is a copy)
 func (dp *DataProcessor) convert(url *url.URL) (ProcessingPhase, error) {
-	err := dp.validate(url)
+	availableSpace := dp.targetSpace(dp.imageSpace())
+	info, withoutQemuImg, err := dp.probeQemuImg(url)
 	if err != nil {
 		return ProcessingPhaseError, err
 	}
-	err = CleanAll(dp.dataFile)
+	if withoutQemuImg {
+		if err := dp.copyRawWithoutQemuImg(url, availableSpace); err != nil {
+			return ProcessingPhaseError, err
+		}
+		return ProcessingPhaseResize, nil
+	}
+	if dp.shrinksTarget() {
+		err = dp.validateShrinkable(url, availableSpace)
+	} else {
+		// the margin is checked on the virtual size probed above, without another qemu-img info
+		if info != nil {
+			err = dp.validateFreeSpaceMargin(info.VirtualSize, availableSpace)
+		}
+		if err == nil {
+			err = dp.validate(url, availableSpace)
+		}
+	}
 	if err != nil {
 		return ProcessingPhaseError, err
 	}
-	klog.V(3).Infoln("Converting to Raw")
-	err = qemuOperations.ConvertToRawStream(url, dp.dataFile, dp.preallocation)
+	if info != nil {
+		dp.imageInfo = util.ImageInfo{Format: info.Format, VirtualSize: info.VirtualSize}
+	}
+	err = CleanAll(dp.dataFile)
 	if err != nil {
-		return ProcessingPhaseError, errors.Wrap(err, "Conversion to Raw failed")
+		return ProcessingPhaseError, err
+	}
+	passthrough := dp.canPassthrough(url, info)
+	if dp.targetFormat == common.ImportTargetFormatLuks {
+		if dp.preallocation {
+			klog.Warningln("Not preallocating the image, preallocation only applies to raw targets")
+		}
+		if err := checkLuksTarget(dp.dataFile); err != nil {
+			return ProcessingPhaseError, err
+		}
+		klog.V(3).Infoln("Converting to raw in a LUKS container")
+		if err := qemuOperations.ConvertToLuksStream(url, dp.dataFile, dp.encryptionKeyFile); err != nil {
+			return ProcessingPhaseError, wrapConvertError(err, "Conversion to LUKS failed")
+		}
+		if err := checkLuksHeader(dp.dataFile); err != nil {
+			return ProcessingPhaseError, err
+		}
+		dp.targetImageInfo = util.TargetImageInfo{Format: dp.targetFormat}
+		return ProcessingPhaseResize, nil
+	} else if dp.targetFormat == common.ImportTargetFormatQcow2 {
+		if dp.preallocation {
+			klog.Warningln("Not preallocating the image, preallocation only applies to raw targets")
+		}
+		if passthrough {
+			klog.V(3).Infoln("Copying qcow2 image as is")
+			err = copySparse(url.Path, dp.dataFile, false)
+		} else {
+			klog.V(3).Infof("Converting to qcow2, compression: %q, cluster size: %d", dp.targetCompression, dp.qcow2ClusterSize)
+			err = qemuOperations.ConvertToQcow2Stream(url, dp.dataFile, dp.targetCompression, dp.qcow2ClusterSize)
+		}
+		if err != nil {
+			return ProcessingPhaseError, wrapConvertError(err, "Conversion to qcow2 failed")
+		}
+		dp.passthrough = passthrough
+		dp.targetImageInfo = util.TargetImageInfo{Format: dp.targetFormat, Compression: dp.targetCompression}
+		return ProcessingPhaseResize, nil
+	} else if passthrough {
+		klog.V(3).Infoln("Copying raw image as is")
+		err = copySparse(url.Path, dp.dataFile, dp.preallocation)
+		if err != nil {
+			return ProcessingPhaseError, errors.Wrap(err, "Copy of raw image failed")
+		}
+		dp.passthrough = true
+	} else {
+		klog.V(3).Infoln("Converting to Raw")
+		err = qemuOperations.ConvertToRawStream(url, dp.dataFile, dp.preallocation)
+		if err != nil {
+			return ProcessingPhaseError, wrapConvertError(err, "Conversion to Raw failed")
+		}
 	}
 	dp.preallocationApplied = dp.preallocation
 
@@ -290,8 +541,41 @@ func (dp *DataProcessor) resize() (ProcessingPhase, error) {
 	size, _ := getAvailableSpaceBlockFunc(dp.dataFile)
 	klog.V(3).Infof("Available space in dataFile: %d", size)
 	isBlockDev := size >= int64(0)
-	if !isBlockDev {
+	if dp.shrinkToUsedSize && dp.targetFormat != "" {
+		klog.Warningln("Not shrinking the image, shrinking only applies to raw targets")
+	} else if isBlockDev && dp.shrinkToUsedSize {
+		klog.Warningln("Not shrinking the image, the target is a block device")
+	} else if dp.shrinkToUsedSize && !shrinkToolsAvailable() {
+		klog.Warningln("Not shrinking the image, shrinking needs e2fsprogs")
+	}
+	if !isBlockDev && dp.withoutQemuImg {
+		if dp.shrinkToUsedSize {
+			klog.Warningln("Not shrinking the image, shrinking needs qemu-img")
+		}
 		if dp.requestImageSize != "" {
+			klog.V(3).Infoln("Resizing image without qemu-img")
+			if err := resizeRawWithoutQemuImg(dp.dataFile, dp.requestImageSize, dp.getUsableSpace()); err != nil {
+				return ProcessingPhaseError, errors.Wrap(err, "Resize of image failed")
+			}
+		}
+	} else if !isBlockDev {
+		// A raw image transferred as is, without the convert phase, is resized as raw whatever the target format
+		if dp.targetImageInfo.Format == common.ImportTargetFormatQcow2 {
+			if dp.requestImageSize != "" {
+				klog.V(3).Infoln("Resizing qcow2 image")
+				err := resizeImage(dp.dataFile, dp.requestImageSize, dp.targetSpace(dp.getUsableSpace()), func(size resource.Quantity) error {
+					return qemuOperations.ResizeQcow2(dp.dataFile, size)
+				})
+				if err != nil {
+					return ProcessingPhaseError, errors.Wrap(err, "Resize of image failed")
+				}
+			}
+		} else if dp.shrinksTarget() {
+			klog.V(3).Infoln("Shrinking image")
+			if err := shrinkImage(dp.dataFile); err != nil {
+				return ProcessingPhaseError, errors.Wrap(err, "Shrink of image failed")
+			}
+		} else if dp.requestImageSize != "" {
 			klog.V(3).Infoln("Resizing image")
 			err := ResizeImage(dp.dataFile, dp.requestImageSize, dp.getUsableSpace(), dp.preallocation)
 			if err != nil {
@@ -303,11 +587,15 @@ func (dp *DataProcessor) resize() (ProcessingPhase, error) {
 		if err != nil {
 			return ProcessingPhaseError, err
 		}
-		err = dp.validate(dataFileURL)
+		space := dp.imageSpace()
+		if dp.targetImageInfo.Format != "" {
+			space = dp.targetSpace(space)
+		}
+		err = dp.validate(dataFileURL, space)
 		if err != nil {
 			return ProcessingPhaseError, err
 		}
-		dp.preallocationApplied = dp.preallocation
+		dp.preallocationApplied = dp.preallocation && dp.targetImageInfo.Format == ""
 	}
 	if dp.dataFile != "" && !isBlockDev {
 		// Change permissions to 0660
@@ -324,6 +612,12 @@ func (dp *DataProcessor) resize() (ProcessingPhase, error) {
 // is not the same as the requested space. For those situations we compare the available space to the requested space and
 // use the smallest of the two values.
 func ResizeImage(dataFile, imageSize string, totalTargetSpace int64, preallocation bool) error {
+	return resizeImage(dataFile, imageSize, totalTargetSpace, func(size resource.Quantity) error {
+		return qemuOperations.Resize(dataFile, size, preallocation)
+	})
+}
+
+func resizeImage(dataFile, imageSize string, totalTargetSpace int64, resize func(resource.Quantity) error) error {
 	dataFileURL, _ := url.Parse(dataFile)
 	info, err := qemuOperations.Info(dataFileURL)
 	if err != nil {
@@ -347,7 +641,7 @@ func ResizeImage(dataFile, imageSize string, totalTargetSpace int64, preallocati
 			return nil
 		}
 		klog.V(1).Infof("Expanding image size to: %s\n", minSizeQuantity.String())
-		return qemuOperations.Resize(dataFile, minSizeQuantity, preallocation)
+		return resize(minSizeQuantity)
 	}
 	return errors.New("Image resize called with blank resize")
 }
@@ -388,8 +682,155 @@ func (dp *DataProcessor) PreallocationApplied() bool {
 	return dp.preallocationApplied
 }
 
+// Passthrough returns true if the image already had the target format, so it was copied as is instead of converted
+func (dp *DataProcessor) Passthrough() bool {
+	return dp.passthrough
+}
+
+// SourceDigest returns the digest of the source bytes computed while they were read, nil if none was requested or the
+// data source does not compute it
+func (dp *DataProcessor) SourceDigest() (*util.SourceDigest, error) {
+	fds, ok := dp.source.(formatDetectingDataSource)
+	if !ok || fds.formatReaders() == nil {
+		return nil, nil
+	}
+	return fds.formatReaders().SourceDigest()
+}
+
+// ImageInfo returns the format and virtual size of the source image, empty if the image was not converted
+func (dp *DataProcessor) ImageInfo() util.ImageInfo {
+	return dp.imageInfo
+}
+
+// TargetImageInfo returns the format and compression of the image written to the target, empty for a raw image
+func (dp *DataProcessor) TargetImageInfo() util.TargetImageInfo {
+	return dp.targetImageInfo
+}
+
+// ImportTimings returns the time spent downloading, converting and resizing the image, rounded to whole seconds. The
+// download includes getting the source information and the transfer, whichever target it was transferred to.
+func (dp *DataProcessor) ImportTimings() cdiv1.DataVolumeImportTimings {
+	seconds := func(phases ...ProcessingPhase) int64 {
+		var d time.Duration
+		for _, phase := range phases {
+			d += dp.phaseDurations[phase]
+		}
+		return int64(d.Round(time.Second) / time.Second)
+	}
+	return cdiv1.DataVolumeImportTimings{
+		DownloadSeconds: seconds(ProcessingPhaseInfo, ProcessingPhaseTransferScratch, ProcessingPhaseTransferDataDir, ProcessingPhaseTransferDataFile),
+		ConvertSeconds:  seconds(ProcessingPhaseConvert),
+		ResizeSeconds:   seconds(ProcessingPhaseResize),
+	}
+}
+
+// scratchSpaceExhaustedError tells the scratch space ran out while the source was transferred to it or converted from it,
+// with the size of the scratch space and the size the source needs when they are known
+func (dp *DataProcessor) scratchSpaceExhaustedError(err error) error {
+	message := common.ScratchSpaceExhaustedMessage
+	size, statErr := util.GetTotalSpace(dp.scratchDataDir)
+	if statErr != nil {
+		size = 0
+	}
+	if dp.scratchSpaceLimit > 0 && (size <= 0 || dp.scratchSpaceLimit < size) {
+		size = dp.scratchSpaceLimit
+	}
+	if size > 0 {
+		message += ", scratch space size " + resource.NewQuantity(size, resource.BinarySI).String()
+	}
+	if needed := dp.storedSize(); needed > 0 {
+		message += ", needed " + resource.NewQuantity(needed, resource.BinarySI).String()
+	}
+	return errors.Errorf("%s: %v", message, err)
+}
+
+// noSpaceLeftError returns the scratch space exhausted error for an ENOSPC error of a phase using both the scratch space
+// and the target, when the scratch space is the volume that ran out: it is full while the target is not, or while the
+// free space of the target is unknown, such as for a block device. The other errors are returned as is.
+func (dp *DataProcessor) noSpaceLeftError(err error) error {
+	if !util.IsNoSpaceLeft(err) || !isFull(dp.scratchDataDir) || isFull(dp.dataDir) {
+		return err
+	}
+	return dp.scratchSpaceExhaustedError(err)
+}
+
+// isFull returns true if the filesystem of the path has less than noSpaceLeftThreshold bytes available, false when it is
+// unknown
+func isFull(path string) bool {
+	if path == "" {
+		return false
+	}
+	available, err := getAvailableSpaceFunc(path)
+	return err == nil && available >= 0 && available < noSpaceLeftThreshold
+}
+
+// storedSize returns the size the source takes once stored, 0 when the data source does not know it
+func (dp *DataProcessor) storedSize() int64 {
+	if fds, ok := dp.source.(formatDetectingDataSource); ok && fds.formatReaders() != nil {
+		return fds.formatReaders().StoredSize()
+	}
+	return 0
+}
+
+// getUsableSpace returns the space the image can be resized to, the usable space less the free-space margin
 func (dp *DataProcessor) getUsableSpace() int64 {
-	return util.GetUsableSpace(dp.filesystemOverhead, dp.availableSpace)
+	usableSpace := util.GetUsableSpace(dp.filesystemOverhead, dp.availableSpace)
+	if dp.freeSpaceMargin == nil {
+		return usableSpace
+	}
+	space := usableSpace - dp.freeSpaceMargin.Bytes(usableSpace)
+	if space <= 0 {
+		return 0
+	}
+	return util.RoundDown(space, util.DefaultAlignBlockSize)
+}
+
+// imageSpace returns the space the image is validated against: the available space, or the usable space less the
+// free-space margin when one is kept, the margin applying to the space left once the filesystem overhead is reserved
+func (dp *DataProcessor) imageSpace() int64 {
+	if dp.freeSpaceMargin == nil {
+		return dp.availableSpace
+	}
+	return dp.getUsableSpace()
+}
+
+// targetSpace returns the largest virtual size of the target image fitting in the space once fully allocated. A qcow2
+// image needs room for its metadata: 10 bytes of L2 table and refcount per 64KiB cluster, rounded up to 1/1024 of the
+// space and scaled up for smaller clusters, plus 1MiB or 4 clusters, whichever is larger, for its header and L1 and
+// refcount tables. A LUKS container needs room for its header.
+func (dp *DataProcessor) targetSpace(space int64) int64 {
+	if space <= 0 || space == math.MaxInt64 {
+		return space
+	}
+	switch dp.targetFormat {
+	case common.ImportTargetFormatQcow2:
+		space -= dp.qcow2Metadata(space)
+	case common.ImportTargetFormatLuks:
+		space -= luksHeaderReserve
+	default:
+		return space
+	}
+	if space < 0 {
+		return 0
+	}
+	return space
+}
+
+// qcow2Metadata returns the space reserved for the metadata of a qcow2 target filling the space
+func (dp *DataProcessor) qcow2Metadata(space int64) int64 {
+	clusterSize := dp.qcow2ClusterSize
+	if clusterSize == 0 {
+		clusterSize = qcow2DefaultClusterSize
+	}
+	tables := space / 1024
+	if clusterSize < qcow2DefaultClusterSize {
+		tables *= qcow2DefaultClusterSize / clusterSize
+	}
+	reserve := int64(qcow2MetadataReserve)
+	if 4*clusterSize > reserve {
+		reserve = 4 * clusterSize
+	}
+	return tables + reserve
 }
 
 // Rebase and commit a delta image to its backing file
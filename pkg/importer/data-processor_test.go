@@ -1,9 +1,14 @@
 package importer
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"net/url"
 	"os"
+	"path/filepath"
+	"syscall"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/extensions/table"
@@ -13,7 +18,11 @@ import (
 
 	"github.com/pkg/errors"
 
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/image"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+	prometheusutil "kubevirt.io/containerized-data-importer/pkg/util/prometheus"
 )
 
 type fakeInfoOpRetVal struct {
@@ -51,6 +60,7 @@ type MockDataProvider struct {
 	transferFile     string
 	calledPhases     []ProcessingPhase
 	needsScratch     bool
+	transferError    error
 }
 
 // Info is called to get initial information about the data
@@ -70,6 +80,9 @@ func (m *MockDataProvider) Transfer(path string) (ProcessingPhase, error) {
 		if m.needsScratch {
 			return ProcessingPhaseError, ErrInvalidPath
 		}
+		if m.transferError != nil {
+			return ProcessingPhaseError, m.transferError
+		}
 		return ProcessingPhaseError, errors.New("Transfer errored")
 	}
 	return m.transferResponse, nil
@@ -80,6 +93,9 @@ func (m *MockDataProvider) TransferFile(fileName string) (ProcessingPhase, error
 	m.calledPhases = append(m.calledPhases, ProcessingPhaseTransferDataFile)
 	m.transferFile = fileName
 	if m.transferResponse == ProcessingPhaseError {
+		if m.transferError != nil {
+			return ProcessingPhaseError, m.transferError
+		}
 		return ProcessingPhaseError, errors.New("TransferFile errored")
 	}
 	return m.transferResponse, nil
@@ -199,6 +215,93 @@ var _ = Describe("Data Processor", func() {
 		Expect(ProcessingPhaseTransferScratch).To(Equal(mdp.calledPhases[1]))
 	})
 
+	It("should tell the scratch space ran out when the transfer to scratch space fails with ENOSPC", func() {
+		tmpDir, err := os.MkdirTemp("", "scratch")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(tmpDir)
+		mdp := &MockDataProvider{
+			infoResponse:     ProcessingPhaseTransferScratch,
+			transferResponse: ProcessingPhaseError,
+			transferError:    errors.Wrap(&os.PathError{Op: "write", Path: "tmpimage", Err: syscall.ENOSPC}, "unable to write to file"),
+		}
+		dp := NewDataProcessor(mdp, "dest", "dataDir", tmpDir, "1G", 0.055, false)
+		err = dp.ProcessData()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Unable to transfer source data to scratch space: " + common.ScratchSpaceExhaustedMessage + ", scratch space size "))
+		Expect(err.Error()).To(ContainSubstring("no space left on device"))
+	})
+
+	It("should fail before the transfer when the source exceeds the scratch space limit", func() {
+		readers, err := NewFormatReaders(io.NopCloser(bytes.NewReader(make([]byte, 1024))), 1024)
+		Expect(err).ToNot(HaveOccurred())
+		mdp := &formatDetectingMockDataProvider{
+			MockDataProvider: MockDataProvider{
+				infoResponse:     ProcessingPhaseTransferScratch,
+				transferResponse: ProcessingPhaseConvert,
+			},
+			readers: readers,
+		}
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.055, false)
+		dp.SetScratchSpaceLimit(512)
+		err = dp.ProcessData()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(common.ScratchSpaceExhaustedMessage + ", scratch space size 512, needed 1Ki"))
+		Expect(mdp.calledPhases).To(Equal([]ProcessingPhase{ProcessingPhaseInfo}))
+	})
+
+	It("should not tell the scratch space ran out when the transfer to the target fails with ENOSPC", func() {
+		mdp := &MockDataProvider{
+			infoResponse:     ProcessingPhaseTransferDataDir,
+			transferResponse: ProcessingPhaseError,
+			transferError:    errors.Wrap(&os.PathError{Op: "write", Path: "disk.img", Err: syscall.ENOSPC}, "unable to write to file"),
+		}
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.055, false)
+		err := dp.ProcessData()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("no space left on device"))
+		Expect(err.Error()).ToNot(ContainSubstring(common.ScratchSpaceExhaustedMessage))
+	})
+
+	table.DescribeTable("should tell which volume ran out when a phase using the scratch space and the target fails with ENOSPC", func(infoResponse ProcessingPhase, scratchAvailable, targetAvailable int64, scratchExhausted bool) {
+		url, err := url.Parse("http://fakeurl-notreal.fake")
+		Expect(err).ToNot(HaveOccurred())
+		mdp := &MockDataProvider{
+			infoResponse:     infoResponse,
+			transferResponse: ProcessingPhaseError,
+			transferError:    errors.Wrap(&os.PathError{Op: "write", Path: "disk.img", Err: syscall.ENOSPC}, "unable to write to file"),
+			url:              url,
+		}
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.055, false)
+		origFunc := getAvailableSpaceFunc
+		defer func() {
+			getAvailableSpaceFunc = origFunc
+		}()
+		getAvailableSpaceFunc = func(path string) (int64, error) {
+			if path == "scratchDataDir" {
+				return scratchAvailable, nil
+			}
+			return targetAvailable, nil
+		}
+		convertErr := errors.New("error while writing at byte 1048576: No space left on device")
+		qemuOperations := NewFakeQEMUOperations(convertErr, nil, fakeInfoRet, nil, nil, nil)
+		replaceQEMUOperations(qemuOperations, func() {
+			err = dp.ProcessData()
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(MatchRegexp("(?i)no space left on device"))
+		if scratchExhausted {
+			Expect(err.Error()).To(ContainSubstring(common.ScratchSpaceExhaustedMessage))
+		} else {
+			Expect(err.Error()).ToNot(ContainSubstring(common.ScratchSpaceExhaustedMessage))
+		}
+	},
+		table.Entry("on the transfer to the target file with a full scratch space", ProcessingPhaseTransferDataFile, int64(4096), int64(1024*1024*1024), true),
+		table.Entry("on the transfer to the target file with a full target", ProcessingPhaseTransferDataFile, int64(1024*1024*1024), int64(0), false),
+		table.Entry("on the transfer to the target file with full scratch space and target", ProcessingPhaseTransferDataFile, int64(0), int64(0), false),
+		table.Entry("on the conversion with a full scratch space", ProcessingPhaseConvert, int64(0), int64(1024*1024*1024), true),
+		table.Entry("on the conversion with a full target", ProcessingPhaseConvert, int64(1024*1024*1024), int64(4096), false),
+	)
+
 	It("should call the right phases based on the responses from the provider, TransferDataFile should pass the data file", func() {
 		mdp := &MockDataProvider{
 			infoResponse:     ProcessingPhaseTransferDataFile,
@@ -228,6 +331,10 @@ var _ = Describe("Data Processor", func() {
 			Expect(2).To(Equal(len(mdp.calledPhases)))
 			Expect(ProcessingPhaseInfo).To(Equal(mdp.calledPhases[0]))
 			Expect(ProcessingPhaseTransferDataFile).To(Equal(mdp.calledPhases[1]))
+			By("Verifying the status reports the failed phase and its error")
+			status := prometheusutil.GetStatus()
+			Expect(status.Phase).To(Equal(string(ProcessingPhaseTransferDataFile)))
+			Expect(status.LastError).To(ContainSubstring("TransferFile errored"))
 		})
 	})
 
@@ -317,6 +424,41 @@ var _ = Describe("Data Processor", func() {
 	})
 })
 
+var _ = Describe("Import timings", func() {
+	It("should record the time spent in each phase of a simulated import", func() {
+		mdp := &MockDataProvider{
+			infoResponse:     ProcessingPhaseTransferScratch,
+			transferResponse: ProcessingPhaseConvert,
+		}
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.055, false)
+		simulatePhase := func(next ProcessingPhase) func() (ProcessingPhase, error) {
+			return func() (ProcessingPhase, error) {
+				time.Sleep(10 * time.Millisecond)
+				return next, nil
+			}
+		}
+		dp.RegisterPhaseExecutor(ProcessingPhaseConvert, simulatePhase(ProcessingPhaseResize))
+		dp.RegisterPhaseExecutor(ProcessingPhaseResize, simulatePhase(ProcessingPhaseComplete))
+		Expect(dp.ProcessData()).To(Succeed())
+		Expect(dp.phaseDurations).To(HaveKey(ProcessingPhaseInfo))
+		Expect(dp.phaseDurations).To(HaveKey(ProcessingPhaseTransferScratch))
+		Expect(dp.phaseDurations[ProcessingPhaseConvert]).To(BeNumerically(">=", 10*time.Millisecond))
+		Expect(dp.phaseDurations[ProcessingPhaseResize]).To(BeNumerically(">=", 10*time.Millisecond))
+		Expect(dp.phaseDurations).ToNot(HaveKey(ProcessingPhaseMergeDelta))
+	})
+
+	It("should sum the download phases and round to whole seconds", func() {
+		dp := NewDataProcessor(&MockDataProvider{}, "dest", "dataDir", "scratchDataDir", "1G", 0.055, false)
+		dp.phaseDurations = map[ProcessingPhase]time.Duration{
+			ProcessingPhaseInfo:            300 * time.Millisecond,
+			ProcessingPhaseTransferScratch: 41400 * time.Millisecond,
+			ProcessingPhaseConvert:         6600 * time.Millisecond,
+			ProcessingPhaseResize:          200 * time.Millisecond,
+		}
+		Expect(dp.ImportTimings()).To(Equal(cdiv1.DataVolumeImportTimings{DownloadSeconds: 42, ConvertSeconds: 7}))
+	})
+})
+
 var _ = Describe("Convert", func() {
 	It("Should successfully convert and return resize", func() {
 		url, err := url.Parse("http://fakeurl-notreal.fake")
@@ -333,6 +475,22 @@ var _ = Describe("Convert", func() {
 		})
 	})
 
+	It("Should record the format and virtual size of the source image", func() {
+		url, err := url.Parse("http://fakeurl-notreal.fake")
+		Expect(err).ToNot(HaveOccurred())
+		mdp := &MockDataProvider{
+			url: url,
+		}
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.055, false)
+		imgInfo := image.ImgInfo{Format: "qcow2", VirtualSize: 46137344}
+		qemuOperations := NewFakeQEMUOperations(nil, nil, fakeInfoOpRetVal{&imgInfo, nil}, nil, nil, nil)
+		replaceQEMUOperations(qemuOperations, func() {
+			_, err := dp.convert(mdp.GetURL())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dp.ImageInfo()).To(Equal(util.ImageInfo{Format: "qcow2", VirtualSize: 46137344}))
+		})
+	})
+
 	It("Should fail when validation fails and return Error", func() {
 		url, err := url.Parse("http://fakeurl-notreal.fake")
 		Expect(err).ToNot(HaveOccurred())
@@ -364,6 +522,431 @@ var _ = Describe("Convert", func() {
 	})
 })
 
+var _ = Describe("Qcow2 target", func() {
+	var (
+		mdp *MockDataProvider
+		ops *targetRecordingQEMUOperations
+	)
+
+	BeforeEach(func() {
+		url, err := url.Parse("http://fakeurl-notreal.fake")
+		Expect(err).ToNot(HaveOccurred())
+		mdp = &MockDataProvider{url: url}
+		ops = &targetRecordingQEMUOperations{QEMUOperations: NewFakeQEMUOperations(nil, nil, fakeInfoRet, nil, nil, nil)}
+	})
+
+	It("Should convert to a compressed qcow2 image without preallocating it", func() {
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.055, true)
+		dp.SetTargetFormat(common.ImportTargetFormatQcow2, common.ImportTargetCompressionZstd)
+		replaceQEMUOperations(ops, func() {
+			nextPhase, err := dp.convert(mdp.GetURL())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nextPhase).To(Equal(ProcessingPhaseResize))
+		})
+		Expect(ops.calls).To(Equal([]string{"ConvertToQcow2Stream dest zstd"}))
+		Expect(dp.TargetImageInfo()).To(Equal(util.TargetImageInfo{Format: "qcow2", Compression: "zstd"}))
+		Expect(dp.PreallocationApplied()).To(BeFalse())
+	})
+
+	It("Should convert to a raw image when the raw format is requested", func() {
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.055, false)
+		dp.SetTargetFormat(common.ImportTargetFormatRaw, "")
+		replaceQEMUOperations(ops, func() {
+			_, err := dp.convert(mdp.GetURL())
+			Expect(err).ToNot(HaveOccurred())
+		})
+		Expect(ops.calls).To(Equal([]string{"ConvertToRawStream dest"}))
+		Expect(dp.TargetImageInfo()).To(Equal(util.TargetImageInfo{}))
+	})
+
+	It("Should resize the qcow2 image as qcow2", func() {
+		tmpDir, err := os.MkdirTemp(os.TempDir(), "data")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(tmpDir)
+		dp := NewDataProcessor(mdp, tmpDir, tmpDir, "scratchDataDir", "1G", 0.055, false)
+		dp.SetTargetFormat(common.ImportTargetFormatQcow2, "")
+		// Written by the convert phase
+		dp.targetImageInfo = util.TargetImageInfo{Format: common.ImportTargetFormatQcow2}
+		replaceQEMUOperations(ops, func() {
+			nextPhase, err := dp.resize()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nextPhase).To(Equal(ProcessingPhaseComplete))
+		})
+		Expect(ops.calls).To(HaveLen(1))
+		Expect(ops.calls[0]).To(HavePrefix("ResizeQcow2 " + tmpDir))
+	})
+
+	It("Should resize a raw image transferred without converting it as raw", func() {
+		tmpDir, err := os.MkdirTemp(os.TempDir(), "data")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(tmpDir)
+		dp := NewDataProcessor(mdp, tmpDir, tmpDir, "scratchDataDir", "1G", 0.055, false)
+		dp.SetTargetFormat(common.ImportTargetFormatQcow2, "")
+		replaceQEMUOperations(ops, func() {
+			nextPhase, err := dp.resize()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nextPhase).To(Equal(ProcessingPhaseComplete))
+		})
+		Expect(ops.calls).To(HaveLen(1))
+		Expect(ops.calls[0]).To(HavePrefix("Resize " + tmpDir))
+		Expect(dp.TargetImageInfo()).To(Equal(util.TargetImageInfo{}))
+	})
+
+	It("Should keep room for the qcow2 metadata in the target space", func() {
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.055, false)
+		Expect(dp.targetSpace(1024 * 1024 * 1024)).To(Equal(int64(1024 * 1024 * 1024)))
+		dp.SetTargetFormat(common.ImportTargetFormatQcow2, common.ImportTargetCompressionZlib)
+		Expect(dp.targetSpace(1024 * 1024 * 1024)).To(Equal(int64(1022 * 1024 * 1024)))
+		Expect(dp.targetSpace(512 * 1024)).To(BeZero())
+		Expect(dp.targetSpace(-1)).To(Equal(int64(-1)))
+	})
+
+	It("Should convert to a qcow2 image with the requested cluster size", func() {
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.055, false)
+		dp.SetTargetFormat(common.ImportTargetFormatQcow2, "")
+		dp.SetQcow2Layout(2*1024*1024, false)
+		replaceQEMUOperations(ops, func() {
+			nextPhase, err := dp.convert(mdp.GetURL())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nextPhase).To(Equal(ProcessingPhaseResize))
+		})
+		Expect(ops.calls).To(Equal([]string{"ConvertToQcow2Stream dest  2097152"}))
+	})
+
+	It("Should keep room for the metadata of the qcow2 cluster size in the target space", func() {
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.055, false)
+		dp.SetTargetFormat(common.ImportTargetFormatQcow2, "")
+		dp.SetQcow2Layout(2*1024*1024, false)
+		Expect(dp.targetSpace(1024 * 1024 * 1024)).To(Equal(int64(1015 * 1024 * 1024)))
+		dp.SetQcow2Layout(512, false)
+		Expect(dp.targetSpace(1024 * 1024 * 1024)).To(Equal(int64(895 * 1024 * 1024)))
+	})
+
+	table.DescribeTable("Should only copy a qcow2 image as is when its layout is kept", func(clusterSize int64, compact, passthrough bool) {
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.055, false)
+		dp.SetTargetFormat(common.ImportTargetFormatQcow2, "")
+		dp.SetQcow2Layout(clusterSize, compact)
+		srcURL, err := url.Parse("/scratch/tmpimage")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dp.canPassthrough(srcURL, &image.ImgInfo{Format: "qcow2", ClusterSize: 64 * 1024})).To(Equal(passthrough))
+	},
+		table.Entry("without layout options", int64(0), false, true),
+		table.Entry("with the cluster size of the image", int64(64*1024), false, true),
+		table.Entry("with another cluster size", int64(2*1024*1024), false, false),
+		table.Entry("with compaction", int64(0), true, false),
+	)
+})
+
+var _ = Describe("LUKS target", func() {
+	var (
+		mdp      *MockDataProvider
+		ops      *targetRecordingQEMUOperations
+		tmpDir   string
+		dataFile string
+	)
+
+	BeforeEach(func() {
+		url, err := url.Parse("http://fakeurl-notreal.fake")
+		Expect(err).ToNot(HaveOccurred())
+		mdp = &MockDataProvider{url: url}
+		ops = &targetRecordingQEMUOperations{QEMUOperations: NewFakeQEMUOperations(nil, nil, fakeInfoRet, nil, nil, nil)}
+		tmpDir, err = os.MkdirTemp(os.TempDir(), "luks")
+		Expect(err).ToNot(HaveOccurred())
+		dataFile = filepath.Join(tmpDir, "disk.img")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	blockDevice := func(string) (int64, error) {
+		return 1024 * 1024 * 1024, nil
+	}
+
+	It("Should convert into a LUKS container on the block device", func() {
+		dp := NewDataProcessor(mdp, dataFile, tmpDir, "scratchDataDir", "1G", 0.055, true)
+		dp.SetTargetFormat(common.ImportTargetFormatRaw, "")
+		dp.SetTargetEncryption("/encryption-key/passphrase")
+		replaceAvailableSpaceBlockFunc(blockDevice, func() {
+			replaceQEMUOperations(ops, func() {
+				nextPhase, err := dp.convert(mdp.GetURL())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(nextPhase).To(Equal(ProcessingPhaseResize))
+			})
+		})
+		Expect(ops.calls).To(Equal([]string{"ConvertToLuksStream " + dataFile + " /encryption-key/passphrase"}))
+		Expect(dp.TargetImageInfo()).To(Equal(util.TargetImageInfo{Format: "luks"}))
+		Expect(dp.PreallocationApplied()).To(BeFalse())
+	})
+
+	It("Should not encrypt without a passphrase file", func() {
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.055, false)
+		dp.SetTargetEncryption("")
+		replaceQEMUOperations(ops, func() {
+			_, err := dp.convert(mdp.GetURL())
+			Expect(err).ToNot(HaveOccurred())
+		})
+		Expect(ops.calls).To(Equal([]string{"ConvertToRawStream dest"}))
+	})
+
+	It("Should fail when the target is not a block device", func() {
+		dp := NewDataProcessor(mdp, dataFile, tmpDir, "scratchDataDir", "1G", 0.055, false)
+		dp.SetTargetEncryption("/encryption-key/passphrase")
+		replaceQEMUOperations(ops, func() {
+			nextPhase, err := dp.convert(mdp.GetURL())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("needs a block volume"))
+			Expect(nextPhase).To(Equal(ProcessingPhaseError))
+		})
+		Expect(ops.calls).To(BeEmpty())
+	})
+
+	It("Should fail when the target has no LUKS header after the conversion", func() {
+		dp := NewDataProcessor(mdp, dataFile, tmpDir, "scratchDataDir", "1G", 0.055, false)
+		dp.SetTargetEncryption("/encryption-key/passphrase")
+		replaceAvailableSpaceBlockFunc(blockDevice, func() {
+			replaceQEMUOperations(&headerlessLuksQEMUOperations{ops}, func() {
+				nextPhase, err := dp.convert(mdp.GetURL())
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("no LUKS header"))
+				Expect(nextPhase).To(Equal(ProcessingPhaseError))
+			})
+		})
+	})
+
+	It("Should stage in scratch space the raw image the data source writes to the target, and convert it", func() {
+		scratchDir := filepath.Join(tmpDir, "scratch")
+		Expect(os.Mkdir(scratchDir, 0755)).To(Succeed())
+		mdp.transferResponse = ProcessingPhaseResize
+		dp := NewDataProcessor(mdp, dataFile, tmpDir, scratchDir, "1G", 0.055, false)
+		dp.SetTargetEncryption("/encryption-key/passphrase")
+		nextPhase, err := dp.phaseExecutors[ProcessingPhaseTransferDataFile]()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(nextPhase).To(Equal(ProcessingPhaseConvert))
+		Expect(mdp.transferFile).To(Equal(filepath.Join(scratchDir, tempFile)))
+		Expect(dp.stagedImage.Path).To(Equal(filepath.Join(scratchDir, tempFile)))
+
+		replaceAvailableSpaceBlockFunc(blockDevice, func() {
+			replaceQEMUOperations(ops, func() {
+				nextPhase, err = dp.phaseExecutors[ProcessingPhaseConvert]()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(nextPhase).To(Equal(ProcessingPhaseResize))
+			})
+		})
+		Expect(ops.calls).To(Equal([]string{"ConvertToLuksStream " + dataFile + " /encryption-key/passphrase"}))
+	})
+
+	It("Should require scratch space to stage the raw image instead of writing it to the target", func() {
+		mdp.transferResponse = ProcessingPhaseResize
+		dp := NewDataProcessor(mdp, dataFile, tmpDir, "", "1G", 0.055, false)
+		dp.SetTargetEncryption("/encryption-key/passphrase")
+		nextPhase, err := dp.phaseExecutors[ProcessingPhaseTransferDataFile]()
+		Expect(err).To(Equal(ErrRequiresScratchSpace))
+		Expect(nextPhase).To(Equal(ProcessingPhaseError))
+		Expect(mdp.transferFile).To(BeEmpty())
+	})
+
+	It("Should keep room for the LUKS header in the target space", func() {
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.055, false)
+		dp.SetTargetEncryption("/encryption-key/passphrase")
+		Expect(dp.targetSpace(1024 * 1024 * 1024)).To(Equal(int64(1022 * 1024 * 1024)))
+	})
+})
+
+// infoCountingQEMUOperations counts the qemu-img info calls
+type infoCountingQEMUOperations struct {
+	image.QEMUOperations
+	infoCalls int
+}
+
+func (o *infoCountingQEMUOperations) Info(url *url.URL) (*image.ImgInfo, error) {
+	o.infoCalls++
+	return o.QEMUOperations.Info(url)
+}
+
+var _ = Describe("Free-space margin", func() {
+	const (
+		Mi = int64(1024 * 1024)
+		Gi = 1024 * Mi
+	)
+	var mdp *MockDataProvider
+
+	BeforeEach(func() {
+		url, err := url.Parse("http://fakeurl-notreal.fake")
+		Expect(err).ToNot(HaveOccurred())
+		mdp = &MockDataProvider{
+			url: url,
+		}
+	})
+
+	It("Should fail before converting when the image and the margin don't fit", func() {
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "10Gi", 0.055, false)
+		dp.availableSpace = 10 * Gi
+		dp.SetFreeSpaceMargin(&util.FreeSpaceMargin{Size: Gi})
+		imgInfo := image.ImgInfo{Format: "qcow2", VirtualSize: 9 * Gi}
+		qemuOperations := &infoCountingQEMUOperations{
+			QEMUOperations: NewFakeQEMUOperations(errors.New("should not convert"), nil, fakeInfoOpRetVal{&imgInfo, nil}, nil, nil, nil),
+		}
+		replaceQEMUOperations(qemuOperations, func() {
+			nextPhase, err := dp.convert(mdp.GetURL())
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(BeAssignableToTypeOf(ValidationSizeError{}))
+			Expect(err.Error()).To(Equal(fmt.Sprintf("Virtual image size %d and the free-space margin of 1Gi don't fit in the usable storage %d. A larger PVC is required.", 9*Gi, 9676*Mi)))
+			Expect(ProcessingPhaseError).To(Equal(nextPhase))
+			Expect(qemuOperations.infoCalls).To(Equal(1))
+		})
+	})
+
+	It("Should fail before transferring a raw image to the target when the image and the margin don't fit", func() {
+		readers, err := NewFormatReaders(io.NopCloser(bytes.NewReader(make([]byte, 1024))), uint64(9*Gi))
+		Expect(err).ToNot(HaveOccurred())
+		mdp := &formatDetectingMockDataProvider{
+			MockDataProvider: MockDataProvider{
+				infoResponse:     ProcessingPhaseTransferDataFile,
+				transferResponse: ProcessingPhaseResize,
+			},
+			readers: readers,
+		}
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "10Gi", 0.055, false)
+		dp.availableSpace = 10 * Gi
+		dp.SetFreeSpaceMargin(&util.FreeSpaceMargin{Size: Gi})
+		err = dp.ProcessData()
+		Expect(err).To(HaveOccurred())
+		Expect(err).To(BeAssignableToTypeOf(ValidationSizeError{}))
+		Expect(err.Error()).To(Equal(fmt.Sprintf("Virtual image size %d and the free-space margin of 1Gi don't fit in the usable storage %d. A larger PVC is required.", 9*Gi, 9676*Mi)))
+		Expect(mdp.calledPhases).To(Equal([]ProcessingPhase{ProcessingPhaseInfo}))
+	})
+
+	It("Should transfer a raw image to the target when the image and the margin fit", func() {
+		readers, err := NewFormatReaders(io.NopCloser(bytes.NewReader(make([]byte, 1024))), uint64(8*Gi))
+		Expect(err).ToNot(HaveOccurred())
+		mdp := &formatDetectingMockDataProvider{
+			MockDataProvider: MockDataProvider{
+				infoResponse:     ProcessingPhaseTransferDataFile,
+				transferResponse: ProcessingPhaseComplete,
+			},
+			readers: readers,
+		}
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "10Gi", 0.055, false)
+		dp.availableSpace = 10 * Gi
+		dp.SetFreeSpaceMargin(&util.FreeSpaceMargin{Size: Gi})
+		Expect(dp.ProcessData()).To(Succeed())
+		Expect(mdp.calledPhases).To(Equal([]ProcessingPhase{ProcessingPhaseInfo, ProcessingPhaseTransferDataFile}))
+	})
+
+	It("Should convert when the image and the margin fit", func() {
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "10Gi", 0.055, false)
+		dp.availableSpace = 10 * Gi
+		dp.SetFreeSpaceMargin(&util.FreeSpaceMargin{Size: Gi})
+		Expect(dp.imageSpace()).To(Equal(8652 * Mi))
+		imgInfo := image.ImgInfo{Format: "qcow2", VirtualSize: 8 * Gi}
+		qemuOperations := NewFakeQEMUOperations(nil, nil, fakeInfoOpRetVal{&imgInfo, nil}, nil, nil, nil)
+		replaceQEMUOperations(qemuOperations, func() {
+			nextPhase, err := dp.convert(mdp.GetURL())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ProcessingPhaseResize).To(Equal(nextPhase))
+		})
+	})
+
+	It("Should leave the margin free when resizing", func() {
+		tmpDir, err := os.MkdirTemp(os.TempDir(), "data")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(tmpDir)
+		dp := NewDataProcessor(mdp, tmpDir, tmpDir, "scratchDataDir", "20Gi", 0.05, false)
+		dp.availableSpace = 10 * Gi
+		dp.SetFreeSpaceMargin(&util.FreeSpaceMargin{Percent: 5})
+		qemuOperations := NewFakeQEMUOperations(nil, nil, fakeInfoOpRetVal{&fakeZeroImageInfo, nil}, nil, nil, resource.NewQuantity(9241*Mi, resource.BinarySI))
+		replaceQEMUOperations(qemuOperations, func() {
+			nextPhase, err := dp.resize()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ProcessingPhaseComplete).To(Equal(nextPhase))
+		})
+	})
+
+	It("Should use the available space without a margin", func() {
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "10Gi", 0.055, false)
+		dp.availableSpace = 10 * Gi
+		dp.SetFreeSpaceMargin(nil)
+		Expect(dp.imageSpace()).To(Equal(10 * Gi))
+		Expect(dp.getUsableSpace()).To(Equal(9676 * Mi))
+	})
+})
+
+var _ = Describe("Shrink validation", func() {
+	const Gi = int64(1024 * 1024 * 1024)
+	var (
+		mdp       *MockDataProvider
+		origTools = shrinkToolsAvailable
+	)
+
+	BeforeEach(func() {
+		url, err := url.Parse("http://fakeurl-notreal.fake")
+		Expect(err).ToNot(HaveOccurred())
+		mdp = &MockDataProvider{
+			url: url,
+		}
+		shrinkToolsAvailable = func() bool { return true }
+	})
+
+	AfterEach(func() {
+		shrinkToolsAvailable = origTools
+	})
+
+	table.DescribeTable("should validate the image against the available space", func(blockSize, actualSize int64, errMessage string) {
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "", 0.055, false)
+		dp.availableSpace = 10 * Gi
+		dp.SetShrinkToUsedSize(true)
+		imgInfo := image.ImgInfo{Format: "raw", VirtualSize: 20 * Gi, ActualSize: actualSize}
+		qemuOperations := &validateRecordingQEMUOperations{
+			QEMUOperations: NewFakeQEMUOperations(nil, nil, fakeInfoOpRetVal{&imgInfo, nil}, nil, nil, nil),
+		}
+		replaceAvailableSpaceBlockFunc(func(string) (int64, error) {
+			return blockSize, nil
+		}, func() {
+			replaceQEMUOperations(qemuOperations, func() {
+				nextPhase, err := dp.convert(mdp.GetURL())
+				if errMessage == "" {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(nextPhase).To(Equal(ProcessingPhaseResize))
+					Expect(qemuOperations.availableSizes).To(ConsistOf(imgInfo.VirtualSize))
+					return
+				}
+				Expect(err).To(HaveOccurred())
+				Expect(err).To(BeAssignableToTypeOf(ValidationSizeError{}))
+				Expect(err.Error()).To(Equal(errMessage))
+				Expect(nextPhase).To(Equal(ProcessingPhaseError))
+			})
+		})
+	},
+		table.Entry("allow a sparse image larger than a filesystem target", int64(-1), 2*Gi, ""),
+		table.Entry("fail when the allocated data doesn't fit in a filesystem target", int64(-1), 11*Gi,
+			fmt.Sprintf("Allocated image size %d is larger than the reported available storage %d. A larger PVC is required.", 11*Gi, 10*Gi)),
+	)
+
+	table.DescribeTable("should validate the virtual size when the image is not shrunk", func(blockSize int64, toolsAvailable bool) {
+		shrinkToolsAvailable = func() bool { return toolsAvailable }
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "", 0.055, false)
+		dp.availableSpace = 10 * Gi
+		dp.SetShrinkToUsedSize(true)
+		imgInfo := image.ImgInfo{Format: "raw", VirtualSize: 20 * Gi, ActualSize: 2 * Gi}
+		qemuOperations := &validateRecordingQEMUOperations{
+			QEMUOperations: NewFakeQEMUOperations(nil, nil, fakeInfoOpRetVal{&imgInfo, nil}, nil, nil, nil),
+		}
+		replaceAvailableSpaceBlockFunc(func(string) (int64, error) {
+			return blockSize, nil
+		}, func() {
+			replaceQEMUOperations(qemuOperations, func() {
+				_, err := dp.convert(mdp.GetURL())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(qemuOperations.availableSizes).To(ConsistOf(10 * Gi))
+			})
+		})
+	},
+		table.Entry("on a block target", 10*Gi, true),
+		table.Entry("without e2fsprogs", int64(-1), false),
+	)
+})
+
 var _ = Describe("Resize", func() {
 	It("Should not resize and return complete, when requestedSize is blank", func() {
 		tempDir, err := os.MkdirTemp(os.TempDir(), "dest")
@@ -564,6 +1147,18 @@ func (o *fakeQEMUOperations) ConvertToRawStream(*url.URL, string, bool) error {
 	return o.e2
 }
 
+func (o *fakeQEMUOperations) ConvertToQcow2Stream(*url.URL, string, string, int64) error {
+	return o.e2
+}
+
+// Simulate the LUKS header written by qemu-img
+func (o *fakeQEMUOperations) ConvertToLuksStream(url *url.URL, dest, keyFile string) error {
+	if o.e2 != nil {
+		return o.e2
+	}
+	return os.WriteFile(dest, append(luksMagic, make([]byte, 4096)...), 0600)
+}
+
 func (o *fakeQEMUOperations) Validate(*url.URL, int64) error {
 	return o.e5
 }
@@ -575,6 +1170,10 @@ func (o *fakeQEMUOperations) Resize(dest string, size resource.Quantity, preallo
 	return o.e3
 }
 
+func (o *fakeQEMUOperations) ResizeQcow2(dest string, size resource.Quantity) error {
+	return o.Resize(dest, size, false)
+}
+
 func (o *fakeQEMUOperations) Info(url *url.URL) (*image.ImgInfo, error) {
 	return o.ret4.imgInfo, o.ret4.e
 }
@@ -601,6 +1200,61 @@ func (o *fakeQEMUOperations) Commit(image string) error {
 	return nil
 }
 
+// targetRecordingQEMUOperations records the conversions and resizes of the target
+type targetRecordingQEMUOperations struct {
+	image.QEMUOperations
+	calls []string
+}
+
+func (o *targetRecordingQEMUOperations) ConvertToRawStream(src *url.URL, dest string, preallocate bool) error {
+	o.calls = append(o.calls, "ConvertToRawStream "+dest)
+	return o.QEMUOperations.ConvertToRawStream(src, dest, preallocate)
+}
+
+func (o *targetRecordingQEMUOperations) ConvertToQcow2Stream(src *url.URL, dest, compression string, clusterSize int64) error {
+	call := "ConvertToQcow2Stream " + dest + " " + compression
+	if clusterSize > 0 {
+		call += fmt.Sprintf(" %d", clusterSize)
+	}
+	o.calls = append(o.calls, call)
+	return o.QEMUOperations.ConvertToQcow2Stream(src, dest, compression, clusterSize)
+}
+
+func (o *targetRecordingQEMUOperations) ConvertToLuksStream(src *url.URL, dest, keyFile string) error {
+	o.calls = append(o.calls, "ConvertToLuksStream "+dest+" "+keyFile)
+	return o.QEMUOperations.ConvertToLuksStream(src, dest, keyFile)
+}
+
+// validateRecordingQEMUOperations records the available sizes the images are validated against
+type validateRecordingQEMUOperations struct {
+	image.QEMUOperations
+	availableSizes []int64
+}
+
+func (o *validateRecordingQEMUOperations) Validate(url *url.URL, availableSize int64) error {
+	o.availableSizes = append(o.availableSizes, availableSize)
+	return o.QEMUOperations.Validate(url, availableSize)
+}
+
+// headerlessLuksQEMUOperations writes zeroes instead of a LUKS header when converting to LUKS
+type headerlessLuksQEMUOperations struct {
+	image.QEMUOperations
+}
+
+func (o *headerlessLuksQEMUOperations) ConvertToLuksStream(url *url.URL, dest, keyFile string) error {
+	return os.WriteFile(dest, make([]byte, 4096), 0600)
+}
+
+func (o *targetRecordingQEMUOperations) Resize(dest string, size resource.Quantity, preallocate bool) error {
+	o.calls = append(o.calls, "Resize "+dest+" "+size.String())
+	return o.QEMUOperations.Resize(dest, size, preallocate)
+}
+
+func (o *targetRecordingQEMUOperations) ResizeQcow2(dest string, size resource.Quantity) error {
+	o.calls = append(o.calls, "ResizeQcow2 "+dest+" "+size.String())
+	return o.QEMUOperations.ResizeQcow2(dest, size)
+}
+
 func NewQEMUAllErrors() image.QEMUOperations {
 	err := errors.New("qemu should not be called from this test override with replaceQEMUOperations")
 	return NewFakeQEMUOperations(err, err, fakeInfoOpRetVal{nil, err}, err, err, nil)
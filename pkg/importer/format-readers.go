@@ -19,9 +19,13 @@ package importer
 import (
 	"bytes"
 	"compress/gzip"
+	"encoding/binary"
 	"encoding/hex"
 	"io"
+	"math"
+	"path"
 	"strconv"
+	"strings"
 
 	"github.com/klauspost/compress/zstd"
 	"github.com/pkg/errors"
@@ -45,6 +49,10 @@ var (
 		[]string{"ownerUID"},
 	)
 	ownerUID string
+	// digest algorithm of the source bytes, none is computed when empty
+	sourceDigestAlgorithm string
+	// member of a plain tar archive to import, the only disk image member is imported when empty
+	tarDiskMember string
 )
 
 func init() {
@@ -58,6 +66,8 @@ func init() {
 		}
 	}
 	ownerUID, _ = util.ParseEnvVar(common.OwnerUID, false)
+	sourceDigestAlgorithm, _ = util.ParseEnvVar(common.ImporterSourceDigestAlgorithm, false)
+	tarDiskMember, _ = util.ParseEnvVar(common.ImporterTarMember, false)
 }
 
 type reader struct {
@@ -74,7 +84,17 @@ type FormatReaders struct {
 	ArchiveXz      bool
 	ArchiveGz      bool
 	ArchiveZstd    bool
+	OVA            bool // the top reader is the primary disk of the OVA
+	unpackTar      bool // a plain tar archive is unpacked to its disk image member
 	progressReader *prometheusutil.ProgressReader
+	// hashes the source bytes, nil if no digest was requested
+	digestReader *digestReader
+	// formats detected from the headers, outermost first
+	formats []string
+	// size of the stream, 0 if unknown
+	total uint64
+	// virtual size read from the image header
+	headerSize int64
 }
 
 const (
@@ -82,6 +102,7 @@ const (
 	rdrMulti
 	rdrXz
 	rdrStream
+	rdrTar
 )
 
 // map scheme and format to rdrType
@@ -89,19 +110,38 @@ var rdrTypM = map[string]int{
 	"gz":     rdrGz,
 	"xz":     rdrXz,
 	"stream": rdrStream,
+	"tar":    rdrTar,
 }
 
 // NewFormatReaders creates a new instance of FormatReaders using the input stream and content type passed in.
 func NewFormatReaders(stream io.ReadCloser, total uint64) (*FormatReaders, error) {
+	return createFormatReaders(stream, total, false)
+}
+
+// NewDiskFormatReaders creates a new instance of FormatReaders of a stream holding a disk image. A plain tar archive is
+// unpacked to its disk image member, instead of being read as a raw image.
+func NewDiskFormatReaders(stream io.ReadCloser, total uint64) (*FormatReaders, error) {
+	return createFormatReaders(stream, total, true)
+}
+
+func createFormatReaders(stream io.ReadCloser, total uint64, unpackTar bool) (*FormatReaders, error) {
 	var err error
 	readers := &FormatReaders{
-		buf: make([]byte, image.MaxExpectedHdrSize),
+		buf:       make([]byte, image.MaxExpectedHdrSize),
+		total:     total,
+		unpackTar: unpackTar,
+	}
+	if sourceDigestAlgorithm != "" {
+		if readers.digestReader, err = newDigestReader(stream, sourceDigestAlgorithm); err != nil {
+			return nil, err
+		}
+		stream = readers.digestReader
 	}
 	if total > uint64(0) {
 		readers.progressReader = prometheusutil.NewProgressReader(stream, total, progress, ownerUID)
 		err = readers.constructReaders(readers.progressReader)
 	} else {
-		err = readers.constructReaders(stream)
+		err = readers.constructReaders(prometheusutil.TrackBytes(stream))
 	}
 	return readers, err
 }
@@ -119,6 +159,28 @@ func (fr *FormatReaders) constructReaders(r io.ReadCloser) error {
 			break // done processing headers, we have the orig source file
 		}
 		klog.V(2).Infof("found header of type %q\n", hdr.Format)
+		fr.formats = append(fr.formats, hdr.Format)
+		// a tar archive is only unpacked if it is an OVA, or a disk image archive when requested, the data sources
+		// handle the other archives
+		if hdr.Format == "tar" && isOva(fr.buf) {
+			r, err := fr.ovaReader()
+			if err != nil {
+				return errors.WithMessage(err, "could not process OVA")
+			}
+			fr.OVA = true
+			fr.Archived = true
+			fr.appendReader(rdrTypM[hdr.Format], r)
+			continue
+		}
+		if hdr.Format == "tar" && fr.unpackTar {
+			r, err := fr.tarDiskReader()
+			if err != nil {
+				return errors.WithMessage(err, "could not process tar archive")
+			}
+			fr.Archived = true
+			fr.appendReader(rdrTypM[hdr.Format], r)
+			continue
+		}
 		// create format-specific reader and append it to dataStream readers stack
 		fr.fileFormatSelector(hdr)
 		// exit loop if hdr is qcow2
@@ -130,6 +192,132 @@ func (fr *FormatReaders) constructReaders(r io.ReadCloser) error {
 	return nil
 }
 
+// ImageFormat returns the disk image format detected from the headers, under any compression or archive layer. An
+// image without a known header is raw.
+func (fr *FormatReaders) ImageFormat() string {
+	if n := len(fr.formats); n > 0 && !isLayerFormat(fr.formats[n-1]) {
+		return fr.formats[n-1]
+	}
+	return "raw"
+}
+
+// HashesSource returns true if the digest of the source bytes was requested, so they must all be read by the importer
+// instead of streamed to qemu-img
+func (fr *FormatReaders) HashesSource() bool {
+	return fr.digestReader != nil
+}
+
+// SourceDigest returns the digest of the source bytes, nil if none was requested. The remaining source bytes are
+// read, the import must be complete.
+func (fr *FormatReaders) SourceDigest() (*util.SourceDigest, error) {
+	if fr.digestReader == nil {
+		return nil, nil
+	}
+	return fr.digestReader.digest()
+}
+
+// VirtualSize returns the virtual size of the disk image, from the qcow2, vmdk, vdi or vhd header or the size of the
+// stream of an uncompressed raw image. It is 0 if it cannot be known without reading the whole image.
+func (fr *FormatReaders) VirtualSize() int64 {
+	if fr.headerSize > 0 {
+		return fr.headerSize
+	}
+	if fr.Archived || fr.Convert {
+		return 0
+	}
+	return int64(fr.total)
+}
+
+// StoredSize returns the size of the image once written out of its compression, the size of the stream of an
+// uncompressed image. It is 0 if it cannot be known without reading the whole image.
+func (fr *FormatReaders) StoredSize() int64 {
+	if fr.Archived {
+		return 0
+	}
+	return int64(fr.total)
+}
+
+// StreamableArchive returns true if the image is only xz compressed. nbdkit can decompress the ranges qemu-img reads
+// from such an image, so it does not have to be staged in scratch space before the conversion.
+func (fr *FormatReaders) StreamableArchive() bool {
+	return len(fr.formats) == 2 && fr.formats[0] == "xz" && !isLayerFormat(fr.formats[1])
+}
+
+// CheckExtensionHint compares the formats hinted by the extensions of the passed in file name with the detected
+// formats. The detected formats always drive the processing, a conflict is only logged. Returns false on conflict.
+func (fr *FormatReaders) CheckExtensionHint(fileName string) bool {
+	hint := parseExtensionHint(fileName)
+	if hint == nil {
+		return true
+	}
+	var layers []string
+	for _, format := range fr.formats {
+		if isLayerFormat(format) {
+			layers = append(layers, format)
+		}
+	}
+	if strings.Join(hint.layers, ".") != strings.Join(layers, ".") || (hint.format != "" && hint.format != fr.ImageFormat()) {
+		klog.Warningf("The extension of %q doesn't match the detected format %q, using the detected format",
+			path.Base(fileName), strings.Join(append(layers, fr.ImageFormat()), "."))
+		return false
+	}
+	return true
+}
+
+// extensionFormats maps the file extensions to the formats they hint at. The ".img" extension is used for any disk
+// image format, so it only hints there is no compression.
+var extensionFormats = map[string]string{
+	".gz":    "gz",
+	".xz":    "xz",
+	".zst":   "zst",
+	".tar":   "tar",
+	".ova":   "tar",
+	".qcow2": "qcow2",
+	".vmdk":  "vmdk",
+	".vdi":   "vdi",
+	".vhd":   "vhd",
+	".vhdx":  "vhdx",
+	".raw":   "raw",
+	".iso":   "raw",
+	".img":   "",
+}
+
+// extensionHint holds the formats hinted by the extensions of a file name
+type extensionHint struct {
+	// compression and archive formats, outermost first
+	layers []string
+	// disk image format, empty if not hinted
+	format string
+}
+
+// parseExtensionHint returns the formats hinted by the extensions of the passed in file name, or nil if the file name
+// has no known extension.
+func parseExtensionHint(fileName string) *extensionHint {
+	var hint *extensionHint
+	name := strings.ToLower(path.Base(fileName))
+	for {
+		ext := path.Ext(name)
+		format, ok := extensionFormats[ext]
+		if !ok {
+			break
+		}
+		if hint == nil {
+			hint = &extensionHint{}
+		}
+		name = strings.TrimSuffix(name, ext)
+		if !isLayerFormat(format) {
+			hint.format = format
+			break
+		}
+		hint.layers = append(hint.layers, format)
+	}
+	return hint
+}
+
+func isLayerFormat(format string) bool {
+	return format == "gz" || format == "xz" || format == "zst" || format == "tar"
+}
+
 // Append to the receiver's reader stack the passed in reader. If the reader type is multi-reader
 // then wrap a multi-reader around the passed in reader. If the reader is not a Closer then wrap a
 // nop closer.
@@ -184,15 +372,10 @@ func (fr *FormatReaders) fileFormatSelector(hdr *image.Header) {
 			fr.Archived = true
 			fr.ArchiveXz = true
 		}
-	case "vmdk":
-		r = nil
-		fr.Convert = true
-	case "vdi":
-		r = nil
-		fr.Convert = true
-	case "vhd":
+	case "vmdk", "vdi", "vhd":
 		r = nil
 		fr.Convert = true
+		fr.headerSize = headerVirtualSize(fFmt, fr.buf)
 	case "vhdx":
 		r = nil
 		fr.Convert = true
@@ -232,13 +415,37 @@ func (fr *FormatReaders) zstReader() (io.ReadCloser, error) {
 // Note: size is stored at offset 24 in the qcow2 header.
 func (fr *FormatReaders) qcow2NopReader(h *image.Header) (io.Reader, error) {
 	s := hex.EncodeToString(fr.buf[h.SizeOff : h.SizeOff+h.SizeLen])
-	_, err := strconv.ParseInt(s, 16, 64)
+	size, err := strconv.ParseInt(s, 16, 64)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to determine original qcow2 file size from %+v", s)
 	}
+	fr.headerSize = size
 	return nil, nil
 }
 
+// headerVirtualSize returns the virtual size stored in the header of a vmdk, vdi or vhd image, 0 if it is not there
+func headerVirtualSize(format string, hdr []byte) int64 {
+	var size uint64
+	switch format {
+	case "vmdk":
+		// capacity of the sparse extent, in sectors
+		sectors := binary.LittleEndian.Uint64(hdr[12:20])
+		if sectors > math.MaxInt64/512 {
+			return 0
+		}
+		size = sectors * 512
+	case "vdi":
+		size = binary.LittleEndian.Uint64(hdr[0x170:0x178])
+	case "vhd":
+		// current size in the copy of the footer starting a dynamic disk
+		size = binary.BigEndian.Uint64(hdr[48:56])
+	}
+	if size > math.MaxInt64 {
+		return 0
+	}
+	return int64(size)
+}
+
 // Return the xz reader and size of the endpoint "through the eye" of the previous reader.
 // Assumes a single file was compressed. Note: the xz reader is not a closer so we wrap a
 // nop Closer around it.
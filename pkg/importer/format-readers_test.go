@@ -90,6 +90,61 @@ var _ = Describe("Format Readers", func() {
 		table.Entry("should append io.Multireader", rdrMulti, stringRdr, 3, false),
 	)
 
+	Context("with a mislabeled source", func() {
+		var tmpDir string
+
+		BeforeEach(func() {
+			var err error
+			tmpDir, err = os.MkdirTemp("", "format-readers")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(tmpDir)
+		})
+
+		table.DescribeTable("should detect the format from the headers", func(source, compression, fileName, imageFormat string, archived, convert, extensionMatches bool) {
+			if compression != "" {
+				var err error
+				source, err = utils.FormatTestData(source, tmpDir, compression)
+				Expect(err).ToNot(HaveOccurred())
+			}
+			fileName = filepath.Join(tmpDir, fileName)
+			Expect(os.Link(source, fileName)).To(Succeed())
+			f, err := os.Open(fileName)
+			Expect(err).ToNot(HaveOccurred())
+			defer f.Close()
+
+			fr, err = NewFormatReaders(f, uint64(0))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fr.ImageFormat()).To(Equal(imageFormat))
+			Expect(fr.Archived).To(Equal(archived))
+			Expect(fr.Convert).To(Equal(convert))
+			Expect(fr.CheckExtensionHint(fileName)).To(Equal(extensionMatches))
+		},
+			table.Entry("gzipped qcow2 named .img", cirrosFilePath, image.ExtGz, "disk.img", "qcow2", true, true, false),
+			table.Entry("qcow2 named .raw", cirrosFilePath, "", "disk.raw", "qcow2", false, true, false),
+			table.Entry("xz compressed raw named .iso.gz", tinyCoreFilePath, image.ExtXz, "disk.iso.gz", "raw", true, false, false),
+			table.Entry("raw named .qcow2", tinyCoreFilePath, "", "disk.qcow2", "raw", false, false, false),
+			table.Entry("gzipped raw named .qcow2.gz", tinyCoreFilePath, image.ExtGz, "disk.qcow2.gz", "raw", true, false, false),
+			table.Entry("gzipped qcow2 named .qcow2.gz", cirrosFilePath, image.ExtGz, "disk.qcow2.gz", "qcow2", true, true, true),
+			table.Entry("qcow2 named .img", cirrosFilePath, "", "disk.img", "qcow2", false, true, true),
+			table.Entry("raw without extension", tinyCoreFilePath, "", "disk", "raw", false, false, true),
+		)
+	})
+
+	table.DescribeTable("should parse the extension hint", func(fileName string, expected *extensionHint) {
+		Expect(parseExtensionHint(fileName)).To(Equal(expected))
+	},
+		table.Entry("without extension", "/images/disk", nil),
+		table.Entry("with an unknown extension", "/images/disk.bin", nil),
+		table.Entry("with a generic disk image extension", "/images/disk.img", &extensionHint{}),
+		table.Entry("with an image format", "/images/disk.QCOW2", &extensionHint{format: "qcow2"}),
+		table.Entry("with compression layers", "/images/disk.raw.tar.gz", &extensionHint{layers: []string{"gz", "tar"}, format: "raw"}),
+		table.Entry("with only compression", "/images/disk.xz", &extensionHint{layers: []string{"xz"}}),
+		table.Entry("with an OVA extension", "/images/vm.ova", &extensionHint{layers: []string{"tar"}}),
+	)
+
 	It("should not crash on no progress reader", func() {
 		stringReader := io.NopCloser(strings.NewReader("This is a test string"))
 		testReader, err := NewFormatReaders(stringReader, uint64(0))
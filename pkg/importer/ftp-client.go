@@ -0,0 +1,279 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+const (
+	ftpDefaultPort   = "21"
+	ftpAnonymousUser = "anonymous"
+	ftpAnonymousPass = "anonymous@"
+)
+
+var (
+	// may be overridden in tests
+	ftpDialTimeout = 30 * time.Second
+	ftpIdleTimeout = 2 * time.Minute
+
+	ftpEPSVPort = regexp.MustCompile(`\(\|\|\|(\d+)\|\)`)
+	ftpPASVAddr = regexp.MustCompile(`(\d+),(\d+),(\d+),(\d+),(\d+),(\d+)`)
+
+	errFTPRestartNotSupported = errors.New("the FTP server does not support restarting a transfer")
+)
+
+// ftpClient is a minimal FTP client reading files in binary mode over passive data connections. With a TLS
+// configuration, the control connection is upgraded with AUTH TLS, and the data connections are protected too.
+type ftpClient struct {
+	conn      net.Conn
+	text      *textproto.Conn
+	host      string
+	tlsConfig *tls.Config
+}
+
+// dialFTP connects and logs in to the FTP server at the address host:port, anonymously without a user
+func dialFTP(address, user, password string, tlsConfig *tls.Config) (*ftpClient, error) {
+	conn, err := net.DialTimeout("tcp", address, ftpDialTimeout)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not connect to the FTP server %s", address)
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		conn.Close()
+		return nil, errors.Wrapf(err, "invalid FTP server address %s", address)
+	}
+	c := &ftpClient{
+		conn:      conn,
+		text:      textproto.NewConn(conn),
+		host:      host,
+		tlsConfig: tlsConfig,
+	}
+	// the server must answer the login in time, the deadline is cleared for the transfer
+	if err := conn.SetDeadline(time.Now().Add(ftpDialTimeout)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := c.login(user, password); err != nil {
+		c.conn.Close()
+		return nil, err
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		c.conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *ftpClient) login(user, password string) error {
+	if _, _, err := c.text.ReadResponse(220); err != nil {
+		return errors.Wrap(err, "unexpected FTP server greeting")
+	}
+	if c.tlsConfig != nil {
+		if _, _, err := c.cmd(234, "AUTH TLS"); err != nil {
+			return errors.Wrap(err, "FTP server refused to secure the connection")
+		}
+		tlsConn := tls.Client(c.conn, c.dataTLSConfig())
+		if err := tlsConn.SetDeadline(time.Now().Add(ftpDialTimeout)); err != nil {
+			return err
+		}
+		if err := tlsConn.Handshake(); err != nil {
+			return errors.Wrap(err, "TLS handshake with the FTP server failed")
+		}
+		c.conn = tlsConn
+		c.text = textproto.NewConn(tlsConn)
+	}
+	if user == "" {
+		user, password = ftpAnonymousUser, ftpAnonymousPass
+	}
+	code, _, err := c.cmd(3, "USER %s", user)
+	if err != nil && code != 230 {
+		return errors.Wrap(err, "FTP login failed")
+	}
+	if code != 230 {
+		if _, _, err := c.cmd(230, "PASS %s", password); err != nil {
+			return errors.Wrap(err, "FTP login failed")
+		}
+	}
+	if c.tlsConfig != nil {
+		if _, _, err := c.cmd(200, "PBSZ 0"); err != nil {
+			return errors.Wrap(err, "FTP server refused the protection buffer size")
+		}
+		if _, _, err := c.cmd(200, "PROT P"); err != nil {
+			return errors.Wrap(err, "FTP server refused to protect the data connections")
+		}
+	}
+	if _, _, err := c.cmd(200, "TYPE I"); err != nil {
+		return errors.Wrap(err, "FTP server refused the binary transfer type")
+	}
+	return nil
+}
+
+// dataTLSConfig returns the TLS configuration of the connections, verifying the name of the server
+func (c *ftpClient) dataTLSConfig() *tls.Config {
+	config := c.tlsConfig.Clone()
+	if config.ServerName == "" {
+		config.ServerName = c.host
+	}
+	return config
+}
+
+// cmd sends a command and reads its reply, which the server must send within ftpIdleTimeout
+func (c *ftpClient) cmd(expectCode int, format string, args ...interface{}) (int, string, error) {
+	line := fmt.Sprintf(format, args...)
+	if strings.ContainsAny(line, "\r\n") {
+		return 0, "", errors.Errorf("invalid FTP command %q, the arguments must not hold line breaks", strings.Fields(line)[0])
+	}
+	if err := c.conn.SetDeadline(time.Now().Add(ftpIdleTimeout)); err != nil {
+		return 0, "", err
+	}
+	defer c.conn.SetDeadline(time.Time{})
+	if _, err := c.text.Cmd("%s", line); err != nil {
+		return 0, "", err
+	}
+	return c.text.ReadResponse(expectCode)
+}
+
+// size returns the size of the file reported by the SIZE command
+func (c *ftpClient) size(path string) (uint64, error) {
+	_, msg, err := c.cmd(213, "SIZE %s", path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(msg, 10, 64)
+}
+
+// openDataConn opens a passive data connection, with EPSV or with PASV if the server does not support EPSV. The data
+// connection is made to the host of the control connection, whatever the address PASV returns.
+func (c *ftpClient) openDataConn() (net.Conn, error) {
+	var port int
+	if _, msg, err := c.cmd(229, "EPSV"); err == nil {
+		match := ftpEPSVPort.FindStringSubmatch(msg)
+		if match == nil {
+			return nil, errors.Errorf("invalid EPSV response %q", msg)
+		}
+		port, _ = strconv.Atoi(match[1])
+	} else {
+		_, msg, err := c.cmd(227, "PASV")
+		if err != nil {
+			return nil, errors.Wrap(err, "FTP server refused a passive data connection")
+		}
+		match := ftpPASVAddr.FindStringSubmatch(msg)
+		if match == nil {
+			return nil, errors.Errorf("invalid PASV response %q", msg)
+		}
+		high, _ := strconv.Atoi(match[5])
+		low, _ := strconv.Atoi(match[6])
+		port = high<<8 | low
+	}
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(c.host, strconv.Itoa(port)), ftpDialTimeout)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open the FTP data connection")
+	}
+	if c.tlsConfig != nil {
+		return tls.Client(conn, c.dataTLSConfig()), nil
+	}
+	return conn, nil
+}
+
+// retrieve returns a reader of the file from the offset, restarting the transfer with REST if the offset is not 0
+func (c *ftpClient) retrieve(path string, offset uint64) (io.ReadCloser, error) {
+	conn, err := c.openDataConn()
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		if _, _, err := c.cmd(350, "REST %d", offset); err != nil {
+			conn.Close()
+			return nil, errors.Wrap(errFTPRestartNotSupported, err.Error())
+		}
+	}
+	if _, _, err := c.cmd(1, "RETR %s", path); err != nil {
+		conn.Close()
+		return nil, errors.Wrapf(err, "could not retrieve %s", path)
+	}
+	return &ftpDataReader{conn: conn, client: c}, nil
+}
+
+// Close ends the session and closes the control connection
+func (c *ftpClient) Close() error {
+	if _, _, err := c.cmd(2, "QUIT"); err != nil {
+		klog.V(3).Infof("FTP QUIT failed: %v", err)
+	}
+	return c.text.Close()
+}
+
+// ftpDataReader reads a data connection, failing if it is idle for ftpIdleTimeout. The end of the data is only returned
+// once the server confirmed the transfer completed, the data connection being closed when a transfer is aborted too.
+type ftpDataReader struct {
+	conn   net.Conn
+	client *ftpClient
+	// done is set once the response ending the transfer was read
+	done bool
+}
+
+func (r *ftpDataReader) Read(p []byte) (int, error) {
+	if r.done {
+		return 0, errors.New("FTP transfer is closed")
+	}
+	if err := r.conn.SetReadDeadline(time.Now().Add(ftpIdleTimeout)); err != nil {
+		return 0, err
+	}
+	n, err := r.conn.Read(p)
+	if err == io.EOF {
+		if completeErr := r.complete(); completeErr != nil {
+			return n, completeErr
+		}
+	}
+	return n, err
+}
+
+// Close closes the data connection, and reads the response ending the transfer
+func (r *ftpDataReader) Close() error {
+	if r.done {
+		return nil
+	}
+	return r.complete()
+}
+
+// complete closes the data connection, and reads the response ending the transfer, failing if it is not a completion
+// reply such as 226
+func (r *ftpDataReader) complete() error {
+	r.done = true
+	if err := r.conn.Close(); err != nil {
+		klog.V(3).Infof("Closing the FTP data connection: %v", err)
+	}
+	if err := r.client.conn.SetReadDeadline(time.Now().Add(ftpIdleTimeout)); err != nil {
+		return err
+	}
+	defer r.client.conn.SetReadDeadline(time.Time{})
+	if _, _, err := r.client.text.ReadResponse(2); err != nil {
+		return errors.Wrap(err, "FTP transfer did not complete")
+	}
+	return nil
+}
@@ -0,0 +1,286 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+const (
+	ftpScheme  = "ftp"
+	ftpsScheme = "ftps"
+	// ftpMaxResumes is the number of times an interrupted transfer is resumed
+	ftpMaxResumes = 5
+)
+
+// may be overridden in tests
+var ftpResumeDelay = 5 * time.Second
+
+// FTPDataSource is the struct containing the information needed to import from an FTP or FTPS data source.
+// Sequence of phases:
+// 1. Info -> Transfer (image to convert) or TransferFile (raw image)
+// 2. Transfer -> Convert
+type FTPDataSource struct {
+	// FTP end point
+	ep *url.URL
+	// Reader of the file, resuming interrupted transfers
+	ftpReader *ftpReader
+	// stack of readers
+	readers *FormatReaders
+	// The image file in scratch space.
+	url *url.URL
+}
+
+// NewFTPDataSource creates a new instance of the FTPDataSource. The login is anonymous without a user, the
+// certificates of an FTPS server are verified with the system CAs and the CAs in certDir.
+func NewFTPDataSource(endpoint, user, password, certDir string) (*FTPDataSource, error) {
+	ep, err := ParseEndpoint(endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse endpoint %q", redactSourceURL(endpoint))
+	}
+	if ep.Scheme != ftpScheme && ep.Scheme != ftpsScheme {
+		return nil, errors.Errorf("invalid FTP endpoint %q, expected an ftp or ftps url", redactSourceURL(endpoint))
+	}
+	var tlsConfig *tls.Config
+	if ep.Scheme == ftpsScheme {
+		if tlsConfig, err = createFTPTLSConfig(certDir); err != nil {
+			return nil, err
+		}
+	}
+	address := ep.Host
+	if ep.Port() == "" {
+		address = net.JoinHostPort(ep.Hostname(), ftpDefaultPort)
+	}
+	// the path of an FTP URL is relative to the login directory, an absolute path starting with %2F (RFC 1738)
+	reader, err := newFTPReader(address, strings.TrimPrefix(ep.Path, "/"), user, password, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &FTPDataSource{
+		ep:        ep,
+		ftpReader: reader,
+	}, nil
+}
+
+// createFTPTLSConfig returns the TLS configuration of an FTPS server, the same as the one of an HTTPS server
+func createFTPTLSConfig(certDir string) (*tls.Config, error) {
+	importTLS, err := newImportTLSFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := importTLS.clientConfig()
+	// FTPS servers commonly require the data connections to resume the TLS session of the control connection
+	tlsConfig.ClientSessionCache = tls.NewLRUClientSessionCache(0)
+	if certDir != "" {
+		if tlsConfig.RootCAs, err = createCertPool(certDir); err != nil {
+			return nil, err
+		}
+	}
+	return tlsConfig, nil
+}
+
+// Info is called to get initial information about the data.
+func (fd *FTPDataSource) Info() (ProcessingPhase, error) {
+	var err error
+	fd.readers, err = NewDiskFormatReaders(fd.ftpReader, fd.ftpReader.size)
+	if err != nil {
+		klog.Errorf("Error creating readers: %v", err)
+		return ProcessingPhaseError, err
+	}
+	fd.readers.CheckExtensionHint(fd.ep.Path)
+	if !fd.readers.Convert {
+		// Downloading a raw file, we can write that directly to the target.
+		return ProcessingPhaseTransferDataFile, nil
+	}
+	return ProcessingPhaseTransferScratch, nil
+}
+
+// Transfer is called to transfer the data from the source to a temporary location.
+func (fd *FTPDataSource) Transfer(path string) (ProcessingPhase, error) {
+	file := filepath.Join(path, tempFile)
+	if err := CleanAll(file); err != nil {
+		return ProcessingPhaseError, err
+	}
+	size, err := util.GetAvailableSpace(path)
+	if err != nil || size <= 0 {
+		return ProcessingPhaseError, ErrInvalidPath
+	}
+	fd.readers.StartProgressUpdate()
+	if err := util.StreamDataToFile(fd.readers.TopReader(), file); err != nil {
+		return ProcessingPhaseError, err
+	}
+	// If we successfully wrote to the file, then the parse will succeed.
+	fd.url, _ = url.Parse(file)
+	return ProcessingPhaseConvert, nil
+}
+
+// TransferFile is called to transfer the data from the source to the passed in file.
+func (fd *FTPDataSource) TransferFile(fileName string) (ProcessingPhase, error) {
+	if err := CleanAll(fileName); err != nil {
+		return ProcessingPhaseError, err
+	}
+	fd.readers.StartProgressUpdate()
+	if err := util.StreamDataToFile(fd.readers.TopReader(), fileName); err != nil {
+		return ProcessingPhaseError, err
+	}
+	return ProcessingPhaseResize, nil
+}
+
+// GetURL returns the url that the data processor can use when converting the data.
+func (fd *FTPDataSource) GetURL() *url.URL {
+	return fd.url
+}
+
+func (fd *FTPDataSource) formatReaders() *FormatReaders {
+	return fd.readers
+}
+
+// Close closes any readers or other open resources.
+func (fd *FTPDataSource) Close() error {
+	if fd.readers != nil {
+		return fd.readers.Close()
+	}
+	if fd.ftpReader != nil {
+		return fd.ftpReader.Close()
+	}
+	return nil
+}
+
+// ftpReader reads a file of an FTP server. An interrupted transfer is resumed from the offset it reached, with a new
+// session restarting the transfer with REST, if the server supports it.
+type ftpReader struct {
+	address   string
+	path      string
+	user      string
+	password  string
+	tlsConfig *tls.Config
+	// size is the size of the file reported by the server, 0 if the server does not support SIZE
+	size    uint64
+	offset  uint64
+	resumes int
+	client  *ftpClient
+	body    io.ReadCloser
+}
+
+func newFTPReader(address, path, user, password string, tlsConfig *tls.Config) (*ftpReader, error) {
+	r := &ftpReader{
+		address:   address,
+		path:      path,
+		user:      user,
+		password:  password,
+		tlsConfig: tlsConfig,
+	}
+	if err := r.connect(); err != nil {
+		return nil, err
+	}
+	size, err := r.client.size(path)
+	if err != nil {
+		klog.Warningf("Could not get the size of %s from the FTP server, the progress is not reported: %v", path, err)
+	}
+	r.size = size
+	if r.body, err = r.client.retrieve(path, 0); err != nil {
+		r.client.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *ftpReader) connect() error {
+	client, err := dialFTP(r.address, r.user, r.password, r.tlsConfig)
+	if err != nil {
+		return err
+	}
+	r.client = client
+	return nil
+}
+
+func (r *ftpReader) Read(p []byte) (int, error) {
+	for {
+		if r.body == nil {
+			return 0, errors.Errorf("FTP transfer of %s is closed", r.path)
+		}
+		n, err := r.body.Read(p)
+		r.offset += uint64(n)
+		if err == io.EOF && r.size > 0 && r.offset < r.size {
+			err = io.ErrUnexpectedEOF
+		}
+		if err == nil || err == io.EOF {
+			return n, err
+		}
+		if n > 0 {
+			// the next read fails again, and resumes the transfer
+			return n, nil
+		}
+		if resumeErr := r.resume(err); resumeErr != nil {
+			return 0, resumeErr
+		}
+	}
+}
+
+// resume retrieves the rest of the file after the transfer was interrupted by err
+func (r *ftpReader) resume(err error) error {
+	if r.resumes >= ftpMaxResumes {
+		return errors.Wrapf(err, "FTP transfer of %s interrupted at offset %d, after %d resumes", r.path, r.offset, r.resumes)
+	}
+	r.resumes++
+	klog.Warningf("FTP transfer of %s interrupted at offset %d, resuming: %v", r.path, r.offset, err)
+	r.closeSession()
+	time.Sleep(ftpResumeDelay)
+	if connectErr := r.connect(); connectErr != nil {
+		return errors.Wrapf(connectErr, "could not resume the FTP transfer of %s interrupted by %v", r.path, err)
+	}
+	body, retrieveErr := r.client.retrieve(r.path, r.offset)
+	if retrieveErr != nil {
+		r.client.Close()
+		r.client = nil
+		return errors.Wrapf(retrieveErr, "could not resume the FTP transfer of %s interrupted by %v", r.path, err)
+	}
+	r.body = body
+	return nil
+}
+
+func (r *ftpReader) closeSession() {
+	if r.body != nil {
+		if err := r.body.Close(); err != nil {
+			klog.V(3).Infof("Closing the FTP transfer: %v", err)
+		}
+		r.body = nil
+	}
+	if r.client != nil {
+		if err := r.client.Close(); err != nil {
+			klog.V(3).Infof("Closing the FTP session: %v", err)
+		}
+		r.client = nil
+	}
+}
+
+// Close closes the transfer and the session
+func (r *ftpReader) Close() error {
+	r.closeSession()
+	return nil
+}
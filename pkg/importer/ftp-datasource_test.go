@@ -0,0 +1,435 @@
+package importer
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"kubevirt.io/containerized-data-importer/pkg/util/cert"
+	"kubevirt.io/containerized-data-importer/pkg/util/cert/triple"
+)
+
+// mockFTPServer serves the files of a directory over FTP, or FTPS with a TLS configuration
+type mockFTPServer struct {
+	listener  net.Listener
+	dir       string
+	tlsConfig *tls.Config
+	user      string
+	password  string
+	noSize    bool
+	noEPSV    bool
+	noRest    bool
+	// stallRetrieve makes the server never reply to RETR
+	stallRetrieve bool
+	// dropAfter is the number of bytes sent before the data connection of the first drops transfers is closed
+	dropAfter int64
+	drops     int
+
+	mutex       sync.Mutex
+	restOffsets []int64
+	commands    []string
+	retrieved   []string
+}
+
+func newMockFTPServer(dir string) *mockFTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).ToNot(HaveOccurred())
+	return &mockFTPServer{listener: listener, dir: dir}
+}
+
+func (s *mockFTPServer) start() {
+	go func() {
+		for {
+			conn, err := s.listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+}
+
+func (s *mockFTPServer) url(scheme, path string) string {
+	return fmt.Sprintf("%s://%s/%s", scheme, s.listener.Addr().String(), path)
+}
+
+func (s *mockFTPServer) close() {
+	s.listener.Close()
+}
+
+func (s *mockFTPServer) receivedCommand(name string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, command := range s.commands {
+		if command == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *mockFTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(format string, args ...interface{}) {
+		fmt.Fprintf(conn, format+"\r\n", args...)
+	}
+	var user string
+	var offset int64
+	var protect bool
+	var dataListener net.Listener
+	reply("220 mock FTP server ready")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command, arg, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+		s.mutex.Lock()
+		s.commands = append(s.commands, command)
+		s.mutex.Unlock()
+		switch command {
+		case "AUTH":
+			if s.tlsConfig == nil {
+				reply("502 TLS not configured")
+				continue
+			}
+			reply("234 proceed with negotiation")
+			conn = tls.Server(conn, s.tlsConfig)
+			reader = bufio.NewReader(conn)
+		case "USER":
+			user = arg
+			reply("331 password required")
+		case "PASS":
+			if s.user != "" && (user != s.user || arg != s.password) {
+				reply("530 login incorrect")
+				continue
+			}
+			reply("230 logged in")
+		case "PBSZ", "TYPE":
+			reply("200 ok")
+		case "PROT":
+			protect = arg == "P"
+			reply("200 ok")
+		case "SIZE":
+			info, err := os.Stat(filepath.Join(s.dir, arg))
+			if s.noSize || err != nil {
+				reply("550 size not available")
+				continue
+			}
+			reply("213 %d", info.Size())
+		case "EPSV", "PASV":
+			if command == "EPSV" && s.noEPSV {
+				reply("500 unknown command")
+				continue
+			}
+			if dataListener, err = net.Listen("tcp", "127.0.0.1:0"); err != nil {
+				reply("425 cannot open data connection")
+				continue
+			}
+			port := dataListener.Addr().(*net.TCPAddr).Port
+			if command == "EPSV" {
+				reply("229 entering extended passive mode (|||%d|)", port)
+			} else {
+				reply("227 entering passive mode (10,0,0,1,%d,%d)", port>>8, port&0xff)
+			}
+		case "REST":
+			if s.noRest {
+				reply("502 REST not implemented")
+				continue
+			}
+			offset, _ = strconv.ParseInt(arg, 10, 64)
+			s.mutex.Lock()
+			s.restOffsets = append(s.restOffsets, offset)
+			s.mutex.Unlock()
+			reply("350 restarting at %d", offset)
+		case "RETR":
+			s.mutex.Lock()
+			s.retrieved = append(s.retrieved, arg)
+			s.mutex.Unlock()
+			if s.stallRetrieve {
+				continue
+			}
+			s.retrieve(filepath.Join(s.dir, arg), offset, protect, dataListener, reply)
+			offset = 0
+			dataListener = nil
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func (s *mockFTPServer) retrieve(path string, offset int64, protect bool, dataListener net.Listener, reply func(string, ...interface{})) {
+	if dataListener == nil {
+		reply("425 use EPSV or PASV first")
+		return
+	}
+	defer dataListener.Close()
+	file, err := os.Open(path)
+	if err != nil {
+		reply("550 file not found")
+		return
+	}
+	defer file.Close()
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		reply("550 cannot restart")
+		return
+	}
+	reply("150 opening data connection")
+	conn, err := dataListener.Accept()
+	if err != nil {
+		reply("425 cannot open data connection")
+		return
+	}
+	if protect {
+		conn = tls.Server(conn, s.tlsConfig)
+	}
+	s.mutex.Lock()
+	drop := s.drops > 0
+	if drop {
+		s.drops--
+	}
+	s.mutex.Unlock()
+	if drop {
+		io.CopyN(conn, file, s.dropAfter)
+		conn.Close()
+		reply("426 connection closed, transfer aborted")
+		return
+	}
+	io.Copy(conn, file)
+	conn.Close()
+	reply("226 transfer complete")
+}
+
+var _ = Describe("FTP data source", func() {
+	var (
+		server         *mockFTPServer
+		tmpDir         string
+		ds             *FTPDataSource
+		origDelay      time.Duration
+		tinyCoreSize   int64
+		tinyCoreSource []byte
+	)
+
+	BeforeEach(func() {
+		var err error
+		origDelay = ftpResumeDelay
+		ftpResumeDelay = 0
+		tmpDir, err = os.MkdirTemp("", "scratch")
+		Expect(err).ToNot(HaveOccurred())
+		tinyCoreSource, err = os.ReadFile(filepath.Join(imageDir, tinyCoreFileName))
+		Expect(err).ToNot(HaveOccurred())
+		tinyCoreSize = int64(len(tinyCoreSource))
+		server = newMockFTPServer(imageDir)
+		ds = nil
+	})
+
+	AfterEach(func() {
+		ftpResumeDelay = origDelay
+		if ds != nil {
+			ds.Close()
+		}
+		server.close()
+		os.RemoveAll(tmpDir)
+	})
+
+	transferTinyCore := func(user, password string) error {
+		var err error
+		ds, err = NewFTPDataSource(server.url("ftp", tinyCoreFileName), user, password, "")
+		if err != nil {
+			return err
+		}
+		phase, err := ds.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferDataFile))
+		_, err = ds.TransferFile(filepath.Join(tmpDir, "disk.img"))
+		return err
+	}
+
+	expectTinyCoreImported := func() {
+		imported, err := os.ReadFile(filepath.Join(tmpDir, "disk.img"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(imported).To(Equal(tinyCoreSource))
+	}
+
+	It("should import a raw image and report its size", func() {
+		server.start()
+		Expect(transferTinyCore("", "")).To(Succeed())
+		Expect(ds.ftpReader.size).To(Equal(uint64(tinyCoreSize)))
+		expectTinyCoreImported()
+		Expect(server.receivedCommand("EPSV")).To(BeTrue())
+	})
+
+	It("should transfer a qcow2 image to scratch space", func() {
+		server.start()
+		var err error
+		ds, err = NewFTPDataSource(server.url("ftp", cirrosFileName), "", "", "")
+		Expect(err).ToNot(HaveOccurred())
+		phase, err := ds.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferScratch))
+		phase, err = ds.Transfer(tmpDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseConvert))
+		Expect(ds.GetURL().String()).To(Equal(filepath.Join(tmpDir, tempFile)))
+	})
+
+	It("should import without the size when the server does not support SIZE", func() {
+		server.noSize = true
+		server.start()
+		Expect(transferTinyCore("", "")).To(Succeed())
+		Expect(ds.ftpReader.size).To(BeZero())
+		expectTinyCoreImported()
+	})
+
+	It("should fall back to PASV when the server does not support EPSV", func() {
+		server.noEPSV = true
+		server.start()
+		Expect(transferTinyCore("", "")).To(Succeed())
+		Expect(server.receivedCommand("PASV")).To(BeTrue())
+		expectTinyCoreImported()
+	})
+
+	It("should log in with the user and password", func() {
+		server.user, server.password = "user", "password"
+		server.start()
+		Expect(transferTinyCore("user", "password")).To(Succeed())
+		expectTinyCoreImported()
+	})
+
+	It("should fail to log in with a wrong password", func() {
+		server.user, server.password = "user", "password"
+		server.start()
+		err := transferTinyCore("user", "wrong")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("FTP login failed"))
+	})
+
+	It("should resume an interrupted transfer with REST", func() {
+		server.dropAfter = 100000
+		server.drops = 2
+		server.start()
+		Expect(transferTinyCore("", "")).To(Succeed())
+		expectTinyCoreImported()
+		Expect(server.restOffsets).To(Equal([]int64{100000, 200000}))
+	})
+
+	It("should resume an interrupted transfer when the server does not support SIZE", func() {
+		server.dropAfter = 100000
+		server.drops = 1
+		server.noSize = true
+		server.start()
+		Expect(transferTinyCore("", "")).To(Succeed())
+		expectTinyCoreImported()
+		Expect(server.restOffsets).To(Equal([]int64{100000}))
+	})
+
+	It("should retrieve the file relative to the login directory", func() {
+		server.start()
+		Expect(transferTinyCore("", "")).To(Succeed())
+		Expect(server.retrieved).To(Equal([]string{tinyCoreFileName}))
+	})
+
+	It("should not send a path with a line break", func() {
+		server.start()
+		var err error
+		ds, err = NewFTPDataSource(server.url("ftp", tinyCoreFileName+"%0D%0ADELE%20"+tinyCoreFileName), "", "", "")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("must not hold line breaks"))
+		Expect(server.receivedCommand("DELE")).To(BeFalse())
+		Expect(server.retrieved).To(BeEmpty())
+	})
+
+	It("should fail when the server does not reply to RETR in time", func() {
+		origTimeout := ftpIdleTimeout
+		ftpIdleTimeout = 100 * time.Millisecond
+		defer func() {
+			ftpIdleTimeout = origTimeout
+		}()
+		server.stallRetrieve = true
+		server.start()
+		var err error
+		ds, err = NewFTPDataSource(server.url("ftp", tinyCoreFileName), "", "", "")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("could not retrieve " + tinyCoreFileName))
+		Expect(err.Error()).To(ContainSubstring("timeout"))
+	})
+
+	It("should fail an interrupted transfer when the server does not support REST", func() {
+		server.dropAfter = 100000
+		server.drops = 1
+		server.noRest = true
+		server.start()
+		err := transferTinyCore("", "")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("does not support restarting a transfer"))
+	})
+
+	It("should fail an interrupted transfer after the maximum number of resumes", func() {
+		server.dropAfter = 100000
+		server.drops = ftpMaxResumes + 1
+		server.start()
+		err := transferTinyCore("", "")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("after %d resumes", ftpMaxResumes)))
+	})
+
+	Context("over TLS", func() {
+		var certDir string
+
+		BeforeEach(func() {
+			var err error
+			ca, err := triple.NewCA("ftp.cdi.kubevirt.io")
+			Expect(err).ToNot(HaveOccurred())
+			serverKeyPair, err := triple.NewServerKeyPair(ca, "127.0.0.1", "ftp", "cdi", "cluster.local", []string{"127.0.0.1"}, nil)
+			Expect(err).ToNot(HaveOccurred())
+			server.tlsConfig = &tls.Config{
+				Certificates: []tls.Certificate{{
+					Certificate: [][]byte{serverKeyPair.Cert.Raw},
+					PrivateKey:  serverKeyPair.Key,
+				}},
+			}
+			certDir, err = os.MkdirTemp("", "ftp-cert")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(os.WriteFile(filepath.Join(certDir, "ca.pem"), cert.EncodeCertPEM(ca.Cert), 0644)).To(Succeed())
+			server.start()
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(certDir)
+		})
+
+		It("should import over FTPS verifying the server with the CA of the cert dir", func() {
+			var err error
+			ds, err = NewFTPDataSource(server.url("ftps", tinyCoreFileName), "", "", certDir)
+			Expect(err).ToNot(HaveOccurred())
+			phase, err := ds.Info()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(phase).To(Equal(ProcessingPhaseTransferDataFile))
+			_, err = ds.TransferFile(filepath.Join(tmpDir, "disk.img"))
+			Expect(err).ToNot(HaveOccurred())
+			expectTinyCoreImported()
+			Expect(server.receivedCommand("PROT")).To(BeTrue())
+		})
+
+		It("should fail to verify the FTPS server without its CA", func() {
+			_, err := NewFTPDataSource(server.url("ftps", tinyCoreFileName), "", "", "")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("TLS handshake with the FTP server failed"))
+		})
+	})
+})
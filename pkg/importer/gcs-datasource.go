@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
 	"net/url"
 	"path/filepath"
 	"strings"
@@ -12,6 +13,7 @@ import (
 	"github.com/pkg/errors"
 
 	"cloud.google.com/go/storage"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/option"
 
 	"k8s.io/klog/v2"
@@ -22,11 +24,21 @@ import (
 const (
 	gcsFolderSep = "/"
 	gcsScheme    = "gs"
+
+	// gcsUserProjectMissing is the error code of GCS when a requester-pays bucket is read without a user project
+	gcsUserProjectMissing = "UserProjectMissing"
+	// gcsRequesterPays is part of the error message of GCS when a requester-pays bucket is read without a user project
+	gcsRequesterPays = "requester pays bucket"
+	// gcsMaxResumes is the number of times an interrupted download is resumed
+	gcsMaxResumes = 5
 )
 
 // Helper for unit-testing
 var newReaderFunc = getGcsObjectReader
 
+// may be overridden in tests
+var gcsResumeDelay = 5 * time.Second
+
 // GCSDataSource is the struct containing the information needed to import from a GCS data source.
 // Sequence of phases:
 // 1. Info -> Transfer
@@ -44,8 +56,10 @@ type GCSDataSource struct {
 	url *url.URL
 }
 
-// NewGCSDataSource creates a new instance of the GCSDataSource
-func NewGCSDataSource(endpoint, keyFile string) (*GCSDataSource, error) {
+// NewGCSDataSource creates a new instance of the GCSDataSource. The token credentials, if any, are used instead of the key file.
+// The user project, if any, is billed for the requests to a requester-pays bucket. The download is pinned to the generation
+// of the object, the latest one if generation is 0.
+func NewGCSDataSource(endpoint, keyFile, userProject string, generation int64, token *TokenCredentials) (*GCSDataSource, error) {
 	klog.V(3).Infoln("GCS Importer: New Data Source")
 
 	// Placeholders
@@ -56,12 +70,9 @@ func NewGCSDataSource(endpoint, keyFile string) (*GCSDataSource, error) {
 	ep, err := ParseEndpoint(endpoint)
 
 	if err != nil {
-		return nil, errors.Wrapf(err, fmt.Sprintf("GCS Importer: unable to parse endpoint %q", endpoint))
+		return nil, errors.Wrapf(err, fmt.Sprintf("GCS Importer: unable to parse endpoint %q", redactSourceURL(endpoint)))
 	}
 
-	// Getting Context
-	ctx, _ := context.WithTimeout(context.Background(), time.Second*60) //nolint:govet // todo - solve this: the cancel function returned by context.WithTimeout should be called, not discarded, to avoid a context leak
-
 	if ep.Scheme == "gs" {
 		// Using gs:// endpoint and extracting bucket and object name
 		bucket, object = extractGcsBucketAndObject(endpoint)
@@ -71,8 +82,8 @@ func NewGCSDataSource(endpoint, keyFile string) (*GCSDataSource, error) {
 		options = append(options, option.WithEndpoint(host))
 	}
 
-	// Creating GCS Client
-	client, err := getGcsClient(ctx, keyFile, options...)
+	// Creating GCS Client, its credentials refresh their tokens with its context for the whole download
+	client, err := getGcsClient(context.Background(), keyFile, token, options...)
 
 	if err != nil {
 		klog.Errorf("GCS Importer: Error creating GCS Client")
@@ -80,9 +91,12 @@ func NewGCSDataSource(endpoint, keyFile string) (*GCSDataSource, error) {
 	}
 
 	// Creating GCS Reader
-	gcsReader, err := newReaderFunc(ctx, client, bucket, object)
+	gcsReader, err := newReaderFunc(client, bucket, object, userProject, generation)
 	if err != nil {
 		klog.Errorf("GCS Importer: Error creating Reader")
+		if userProject == "" && isGcsUserProjectMissing(err) {
+			return nil, errors.Wrapf(err, "GCS Importer: bucket %q is a requester-pays bucket, a user project is required", bucket)
+		}
 		return nil, err
 	}
 
@@ -97,11 +111,12 @@ func NewGCSDataSource(endpoint, keyFile string) (*GCSDataSource, error) {
 // Info is called to get initial information about the data.
 func (sd *GCSDataSource) Info() (ProcessingPhase, error) {
 	var err error
-	sd.readers, err = NewFormatReaders(sd.gcsReader, uint64(0))
+	sd.readers, err = NewDiskFormatReaders(sd.gcsReader, uint64(0))
 	if err != nil {
 		klog.Errorf("GCS Importer: Error creating readers: %v", err)
 		return ProcessingPhaseError, err
 	}
+	sd.readers.CheckExtensionHint(sd.ep.Path)
 	if !sd.readers.Convert {
 		// Downloading a raw file, we can write that directly to the target.
 		return ProcessingPhaseTransferDataFile, nil
@@ -156,29 +171,168 @@ func (sd *GCSDataSource) GetURL() *url.URL {
 	return sd.url
 }
 
+func (sd *GCSDataSource) formatReaders() *FormatReaders {
+	return sd.readers
+}
+
 // Close closes any readers or other open resources.
 func (sd *GCSDataSource) Close() error {
-	var err error
 	if sd.readers != nil {
-		err = sd.readers.Close()
+		return sd.readers.Close()
+	}
+	if sd.gcsReader != nil {
+		return sd.gcsReader.Close()
 	}
-	return err
+	return nil
 }
 
 // Create a Cloud Storage Client
-func getGcsClient(ctx context.Context, keyFile string, options ...option.ClientOption) (*storage.Client, error) {
+func getGcsClient(ctx context.Context, keyFile string, token *TokenCredentials, options ...option.ClientOption) (*storage.Client, error) {
 	klog.V(3).Infoln("GCS Importer: Creating Client")
-	if keyFile == "" {
+	if token != nil {
+		credentialsJSON, err := newGcsCredentialsJSON(token)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, option.WithCredentialsJSON(credentialsJSON))
+		klog.V(3).Infoln("GCS Importer: Authentication: Workload identity federation")
+	} else if keyFile == "" {
 		options = append(options, option.WithoutAuthentication())
 		klog.V(3).Infoln("GCS Importer: Authentication: Anonymous")
 	}
 	return storage.NewClient(ctx, options...)
 }
 
-// Create Cloud Storage Object Reader
-func getGcsObjectReader(ctx context.Context, client *storage.Client, bucket, object string) (io.ReadCloser, error) {
+// Create Cloud Storage Object Reader, billing the user project if any. The reader is not bound to the timeout of the
+// client creation, the download lasting as long as the image is big.
+func getGcsObjectReader(client *storage.Client, bucket, object, userProject string, generation int64) (io.ReadCloser, error) {
 	klog.V(3).Infoln("GCS Importer: Creating Reader for bucket:", bucket, "object:", object)
-	return client.Bucket(bucket).Object(object).NewReader(ctx)
+	handle := client.Bucket(bucket)
+	if userProject != "" {
+		klog.V(3).Infoln("GCS Importer: Billing user project:", userProject)
+		handle = handle.UserProject(userProject)
+	}
+	r := &gcsReader{
+		object:     handle.Object(object),
+		name:       fmt.Sprintf("%s://%s/%s", gcsScheme, bucket, object),
+		generation: generation,
+	}
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+	if err := r.open(); err != nil {
+		r.cancel()
+		if generation != 0 && errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, errors.Wrapf(err, "GCS Importer: generation %d of %s does not exist", generation, r.name)
+		}
+		return nil, err
+	}
+	klog.V(3).Infoln("GCS Importer: Reading generation", r.generation, "of", r.name)
+	return r, nil
+}
+
+// gcsReader reads an object of a GCS bucket, pinned to a generation of the object so the download never mixes the
+// content of two generations. An interrupted download is resumed from the offset it reached with a range read, once the
+// storage client gave up retrying it.
+type gcsReader struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	object *storage.ObjectHandle
+	name   string
+	// generation is the generation of the object read, the one requested or else the latest one when the download started
+	generation int64
+	offset     int64
+	resumes    int
+	body       io.ReadCloser
+}
+
+// open reads the object from the offset reached
+func (r *gcsReader) open() error {
+	object := r.object
+	if r.generation != 0 {
+		object = object.Generation(r.generation)
+	}
+	body, err := object.NewRangeReader(r.ctx, r.offset, -1)
+	if err != nil {
+		return err
+	}
+	if r.generation == 0 {
+		r.generation = body.Attrs.Generation
+	}
+	if skip := r.offset - body.Attrs.StartOffset; skip > 0 {
+		// GCS ignores the range of a gzip encoded object it decompresses, the object is read again from its start
+		klog.V(3).Infof("GCS download of %s restarted from offset %d, skipping the %d bytes read already", r.name, body.Attrs.StartOffset, skip)
+		if _, err := io.CopyN(io.Discard, body, skip); err != nil {
+			body.Close()
+			return errors.Wrapf(err, "unable to skip the %d bytes of %s read already", skip, r.name)
+		}
+	}
+	r.body = body
+	return nil
+}
+
+func (r *gcsReader) Read(p []byte) (int, error) {
+	for {
+		if r.body == nil {
+			return 0, errors.Errorf("GCS download of %s is closed", r.name)
+		}
+		n, err := r.body.Read(p)
+		r.offset += int64(n)
+		if err == nil || err == io.EOF {
+			return n, err
+		}
+		if n > 0 {
+			// the next read fails again, and resumes the download
+			return n, nil
+		}
+		if resumeErr := r.resume(err); resumeErr != nil {
+			return 0, resumeErr
+		}
+	}
+}
+
+// resume reads the rest of the object after the download was interrupted by err, a failed resume counting as one
+func (r *gcsReader) resume(err error) error {
+	r.closeBody()
+	for r.resumes < gcsMaxResumes && r.ctx.Err() == nil {
+		r.resumes++
+		klog.Warningf("GCS download of %s interrupted at offset %d, resuming: %v", r.name, r.offset, err)
+		time.Sleep(gcsResumeDelay)
+		openErr := r.open()
+		if openErr == nil {
+			return nil
+		}
+		if errors.Is(openErr, storage.ErrObjectNotExist) {
+			return errors.Wrapf(openErr, "could not resume the GCS download of %s, generation %d does not exist anymore", r.name, r.generation)
+		}
+		err = openErr
+	}
+	return errors.Wrapf(err, "GCS download of %s interrupted at offset %d, after %d resumes", r.name, r.offset, r.resumes)
+}
+
+func (r *gcsReader) closeBody() {
+	if r.body != nil {
+		if err := r.body.Close(); err != nil {
+			klog.V(3).Infof("Closing the GCS download: %v", err)
+		}
+		r.body = nil
+	}
+}
+
+// Close closes the download
+func (r *gcsReader) Close() error {
+	r.closeBody()
+	r.cancel()
+	return nil
+}
+
+// isGcsUserProjectMissing returns true if GCS refused the request because the bucket is requester-pays and no user
+// project was set
+func isGcsUserProjectMissing(err error) bool {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusBadRequest {
+		return false
+	}
+	return strings.Contains(apiErr.Body, gcsUserProjectMissing) || strings.Contains(apiErr.Message, gcsRequesterPays) ||
+		strings.Contains(apiErr.Body, gcsRequesterPays)
 }
 
 // Extract url in format gs://bucket/filename or gs://bucket/subdir/filename
@@ -1,10 +1,14 @@
 package importer
 
 import (
-	"context"
+	"bytes"
+	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"time"
 
 	"cloud.google.com/go/storage"
 
@@ -54,40 +58,210 @@ var _ = Describe("Google Cloud Storage data source", func() {
 	})
 
 	It("NewGCSDataSource should Error, when passed in an invalid endpoint", func() {
-		sd, err = NewGCSDataSource("thisisinvalid#$%#ep", "")
+		sd, err = NewGCSDataSource("thisisinvalid#$%#ep", "", "", 0, nil)
 		Expect(err).To(HaveOccurred())
 	})
 
 	It("NewGCSDataSource should Pass, when passed in an valid https endpoint without authentication", func() {
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/Object.tmp", "")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/Object.tmp", "", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 	})
 
 	It("NewGCSDataSource should Pass, when passed in an valid gs endpoint without authentication", func() {
-		sd, err = NewGCSDataSource("gs://Bucket1/Object.tmp", "")
+		sd, err = NewGCSDataSource("gs://Bucket1/Object.tmp", "", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 	})
 
 	It("NewGCSDataSource should Pass, when passed in an valid https endpoint with authentication", func() {
 		var sampleCredential = filepath.Join(imageDir, "gcs-secret.txt")
 		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", sampleCredential)
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/Object.tmp", "gcs-secret")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/Object.tmp", "gcs-secret", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 	})
 
 	It("NewGCSDataSource should Pass, when passed in an valid gs endpoint with authentication", func() {
 		var sampleCredential = filepath.Join(imageDir, "gcs-secret.txt")
 		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", sampleCredential)
-		sd, err = NewGCSDataSource("gs://Bucket1/Object.tmp", "gcs-secret")
+		sd, err = NewGCSDataSource("gs://Bucket1/Object.tmp", "gcs-secret", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 	})
 
+	It("NewGCSDataSource should Pass, when passed in token credentials", func() {
+		sd, err = NewGCSDataSource("gs://Bucket1/Object.tmp", "", "", 0, &TokenCredentials{
+			TokenFile: filepath.Join(tmpDir, "token"),
+			Audience:  "//iam.googleapis.com/projects/1/locations/global/workloadIdentityPools/pool/providers/provider",
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("NewGCSDataSource should bill the user project on the requests to a requester-pays bucket", func() {
+		var userProjects []string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userProjects = append(userProjects, r.Header.Get("X-Goog-User-Project"))
+			http.ServeFile(w, r, filepath.Join(imageDir, "cirros.raw"))
+		}))
+		defer ts.Close()
+		newReaderFunc = getGcsObjectReader
+		sd, err = NewGCSDataSource(ts.URL+"/Bucket1/cirros.raw", "", "billing-project", 0, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(userProjects).ToNot(BeEmpty())
+		for _, userProject := range userProjects {
+			Expect(userProject).To(Equal("billing-project"))
+		}
+		Expect(sd.gcsReader.Close()).To(Succeed())
+	})
+
+	It("NewGCSDataSource should Error clearly, when reading a requester-pays bucket without user project", func() {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Header.Get("X-Goog-User-Project")).To(BeEmpty())
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("<?xml version='1.0' encoding='UTF-8'?><Error><Code>UserProjectMissing</Code>" +
+				"<Message>Bucket is a requester pays bucket but no user project provided.</Message></Error>"))
+		}))
+		defer ts.Close()
+		newReaderFunc = getGcsObjectReader
+		sd, err = NewGCSDataSource(ts.URL+"/Bucket1/cirros.raw", "", "", 0, nil)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("bucket \"Bucket1\" is a requester-pays bucket, a user project is required"))
+	})
+
+	It("NewGCSDataSource should pin the download to the requested generation", func() {
+		var generations []string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			generations = append(generations, r.URL.Query().Get("generation"))
+			w.Header().Set("X-Goog-Generation", "1234")
+			http.ServeFile(w, r, filepath.Join(imageDir, "cirros.raw"))
+		}))
+		defer ts.Close()
+		newReaderFunc = getGcsObjectReader
+		sd, err = NewGCSDataSource(ts.URL+"/Bucket1/cirros.raw", "", "", 1234, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(generations).To(ConsistOf("1234"))
+		Expect(sd.gcsReader.(*gcsReader).generation).To(Equal(int64(1234)))
+		Expect(sd.Close()).To(Succeed())
+	})
+
+	It("NewGCSDataSource should Error clearly, when the requested generation does not exist", func() {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer ts.Close()
+		newReaderFunc = getGcsObjectReader
+		sd, err = NewGCSDataSource(ts.URL+"/Bucket1/cirros.raw", "", "", 1234, nil)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("generation 1234 of gs://Bucket1/cirros.raw does not exist"))
+	})
+
+	Context("with an interrupted download", func() {
+		var (
+			image    []byte
+			requests []*http.Request
+		)
+
+		BeforeEach(func() {
+			gcsResumeDelay = 0
+			image, err = os.ReadFile(filepath.Join(imageDir, "cirros.raw"))
+			Expect(err).NotTo(HaveOccurred())
+			requests = nil
+			newReaderFunc = getGcsObjectReader
+		})
+
+		AfterEach(func() {
+			gcsResumeDelay = 5 * time.Second
+		})
+
+		// interruptingServer serves the image, interrupting the first download half way and failing the retries of the
+		// storage client, so only the resume of the reader completes the download
+		interruptingServer := func(failures int) *httptest.Server {
+			return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r)
+				switch {
+				case len(requests) == 1:
+					w.Header().Set("X-Goog-Generation", "42")
+					w.Header().Set("Content-Length", fmt.Sprint(len(image)))
+					w.WriteHeader(http.StatusOK)
+					w.Write(image[:len(image)/2])
+					w.(http.Flusher).Flush()
+					conn, _, err := w.(http.Hijacker).Hijack()
+					Expect(err).NotTo(HaveOccurred())
+					conn.Close()
+				case len(requests) <= 1+failures:
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.Header().Set("X-Goog-Generation", "42")
+					http.ServeContent(w, r, "cirros.raw", time.Time{}, bytes.NewReader(image))
+				}
+			}))
+		}
+
+		It("should resume the download from its offset on the same generation", func() {
+			ts := interruptingServer(2)
+			defer ts.Close()
+			sd, err = NewGCSDataSource(ts.URL+"/Bucket1/cirros.raw", "", "", 0, nil)
+			Expect(err).NotTo(HaveOccurred())
+			content, err := io.ReadAll(sd.gcsReader)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(content).To(Equal(image))
+			Expect(sd.gcsReader.(*gcsReader).resumes).To(Equal(2))
+			Expect(requests[0].URL.Query().Get("generation")).To(BeEmpty())
+			for _, r := range requests[1:] {
+				Expect(r.URL.Query().Get("generation")).To(Equal("42"))
+				Expect(r.Header.Get("Range")).To(Equal(fmt.Sprintf("bytes=%d-", len(image)/2)))
+			}
+			Expect(sd.Close()).To(Succeed())
+		})
+
+		It("should skip the bytes read already when resuming a decompressed object served from its start", func() {
+			// the retry of the storage client fails, so the reader resumes the download
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r)
+				if len(requests) == 2 {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				w.Header().Set("X-Goog-Generation", "42")
+				// GCS ignores the range of the gzip encoded objects it decompresses
+				w.Header().Set("X-Goog-Stored-Content-Encoding", "gzip")
+				w.Header().Set("Content-Length", fmt.Sprint(len(image)))
+				w.WriteHeader(http.StatusOK)
+				if len(requests) > 2 {
+					w.Write(image)
+					return
+				}
+				w.Write(image[:len(image)/2])
+				w.(http.Flusher).Flush()
+				conn, _, err := w.(http.Hijacker).Hijack()
+				Expect(err).NotTo(HaveOccurred())
+				conn.Close()
+			}))
+			defer ts.Close()
+			sd, err = NewGCSDataSource(ts.URL+"/Bucket1/cirros.raw", "", "", 0, nil)
+			Expect(err).NotTo(HaveOccurred())
+			content, err := io.ReadAll(sd.gcsReader)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(content).To(Equal(image))
+			Expect(sd.gcsReader.(*gcsReader).resumes).ToNot(BeZero())
+			Expect(sd.Close()).To(Succeed())
+		})
+
+		It("should fail after the maximum number of resumes", func() {
+			ts := interruptingServer(gcsMaxResumes + 1)
+			defer ts.Close()
+			sd, err = NewGCSDataSource(ts.URL+"/Bucket1/cirros.raw", "", "", 0, nil)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = io.ReadAll(sd.gcsReader)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("GCS download of gs://Bucket1/cirros.raw interrupted at offset %d, after %d resumes", len(image)/2, gcsMaxResumes)))
+			Expect(sd.Close()).To(Succeed())
+		})
+	})
+
 	It("Info should return Error, when passed in an invalid image using anonymous client and GCS endpoint", func() {
 		file, err := os.Open(filepath.Join(imageDir, "content.tar"))
 		Expect(err).NotTo(HaveOccurred())
 		err = file.Close()
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("gs://Bucket1/content.tar", "")
+		sd, err = NewGCSDataSource("gs://Bucket1/content.tar", "", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -98,7 +272,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 	It("Info should return TransferDataFile, when passed in a valid RAW image using anonymous client and GCS endpoint", func() {
 		file, err := os.Open(filepath.Join(imageDir, "cirros.raw"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("gs://Bucket1/cirros.raw", "")
+		sd, err = NewGCSDataSource("gs://Bucket1/cirros.raw", "", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -109,7 +283,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 	It("Info should return TransferScratch, when passed in a valid QCOW2 image using anonymous client and GCS endpoint", func() {
 		file, err := os.Open(filepath.Join(imageDir, "cirros-qcow2.img"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("gs://Bucket1/cirros-qcow2.img", "")
+		sd, err = NewGCSDataSource("gs://Bucket1/cirros-qcow2.img", "", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -124,7 +298,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		Expect(err).NotTo(HaveOccurred())
 		err = file.Close()
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("gs://Bucket1/content.tar", "gcs-secret")
+		sd, err = NewGCSDataSource("gs://Bucket1/content.tar", "gcs-secret", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -137,7 +311,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", sampleCredential)
 		file, err := os.Open(filepath.Join(imageDir, "cirros.raw"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("gs://Bucket1/cirros.raw", "gcs-secret")
+		sd, err = NewGCSDataSource("gs://Bucket1/cirros.raw", "gcs-secret", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -150,7 +324,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", sampleCredential)
 		file, err := os.Open(filepath.Join(imageDir, "cirros-qcow2.img"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("gs://Bucket1/cirros-qcow2.img", "gcs-secret")
+		sd, err = NewGCSDataSource("gs://Bucket1/cirros-qcow2.img", "gcs-secret", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -163,7 +337,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		Expect(err).NotTo(HaveOccurred())
 		err = file.Close()
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/content.tar", "")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/content.tar", "", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -174,7 +348,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 	It("Info should return TransferDataFile, when passed in a valid RAW image using anonymous client and HTTP(s) endpoint", func() {
 		file, err := os.Open(filepath.Join(imageDir, "cirros.raw"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros.raw", "")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros.raw", "", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -185,7 +359,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 	It("Info should return TransferScratch, when passed in a valid QCOW2 image using anonymous client and HTTP(s) endpoint", func() {
 		file, err := os.Open(filepath.Join(imageDir, "cirros-qcow2.img"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros-qcow2.img", "")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros-qcow2.img", "", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -200,7 +374,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		Expect(err).NotTo(HaveOccurred())
 		err = file.Close()
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/content.tar", "gcs-secret")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/content.tar", "gcs-secret", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -213,7 +387,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", sampleCredential)
 		file, err := os.Open(filepath.Join(imageDir, "cirros.raw"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros.raw", "gcs-secret")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros.raw", "gcs-secret", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -226,7 +400,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", sampleCredential)
 		file, err := os.Open(filepath.Join(imageDir, "cirros-qcow2.img"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros-qcow2.img", "gcs-secret")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros-qcow2.img", "gcs-secret", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -237,7 +411,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 	It("TransferFile using anonymous client and GCS URL should succeed reading RAW image when writing to valid file", func() {
 		file, err := os.Open(filepath.Join(imageDir, "cirros.raw"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("gs://Bucket1/cirros.raw", "")
+		sd, err = NewGCSDataSource("gs://Bucket1/cirros.raw", "", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -251,7 +425,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 	It("TransferFile using anonymous client and GCS URL should succeed reading QCOW2 image when writing to valid file", func() {
 		file, err := os.Open(filepath.Join(imageDir, "cirros-qcow2.img"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("gs://Bucket1/cirros-qcow2.img", "")
+		sd, err = NewGCSDataSource("gs://Bucket1/cirros-qcow2.img", "", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -265,7 +439,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 	It("TransferFile using anonymous client and GCS should fail reading RAW image on streaming error", func() {
 		file, err := os.Open(filepath.Join(imageDir, "cirros.raw"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("gs://Bucket1/cirros.raw", "")
+		sd, err = NewGCSDataSource("gs://Bucket1/cirros.raw", "", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -279,7 +453,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 	It("TransferFile using anonymous client and GCS should fail reading QCOW2 image on streaming error", func() {
 		file, err := os.Open(filepath.Join(imageDir, "cirros-qcow2.img"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("gs://Bucket1/cirros-qcow2.img", "")
+		sd, err = NewGCSDataSource("gs://Bucket1/cirros-qcow2.img", "", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -295,7 +469,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", sampleCredential)
 		file, err := os.Open(filepath.Join(imageDir, "cirros.raw"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("gs://Bucket1/cirros.raw", "gcs-secret")
+		sd, err = NewGCSDataSource("gs://Bucket1/cirros.raw", "gcs-secret", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -311,7 +485,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", sampleCredential)
 		file, err := os.Open(filepath.Join(imageDir, "cirros-qcow2.img"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("gs://Bucket1/cirros-qcow2.img", "gcs-secret")
+		sd, err = NewGCSDataSource("gs://Bucket1/cirros-qcow2.img", "gcs-secret", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -327,7 +501,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", sampleCredential)
 		file, err := os.Open(filepath.Join(imageDir, "cirros.raw"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("gs://Bucket1/cirros.raw", "gcs-secret")
+		sd, err = NewGCSDataSource("gs://Bucket1/cirros.raw", "gcs-secret", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -343,7 +517,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", sampleCredential)
 		file, err := os.Open(filepath.Join(imageDir, "cirros-qcow2.img"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("gs://Bucket1/cirros-qcow2.img", "gcs-secret")
+		sd, err = NewGCSDataSource("gs://Bucket1/cirros-qcow2.img", "gcs-secret", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -357,7 +531,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 	It("TransferFile using anonymous client and HTTP(s) URL should succeed reading RAW image when writing to valid file", func() {
 		file, err := os.Open(filepath.Join(imageDir, "cirros.raw"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros.raw", "")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros.raw", "", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -371,7 +545,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 	It("TransferFile using anonymous client and HTTP(s) URL should succeed reading QCOW2 image when writing to valid file", func() {
 		file, err := os.Open(filepath.Join(imageDir, "cirros-qcow2.img"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros-qcow2.img", "")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros-qcow2.img", "", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -385,7 +559,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 	It("TransferFile using anonymous client and HTTP(s) should fail reading RAW image on streaming error", func() {
 		file, err := os.Open(filepath.Join(imageDir, "cirros.raw"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros.raw", "")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros.raw", "", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -399,7 +573,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 	It("TransferFile using anonymous client and HTTP(s) should fail reading QCOW2 image on streaming error", func() {
 		file, err := os.Open(filepath.Join(imageDir, "cirros-qcow2.img"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros-qcow2.img", "")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros-qcow2.img", "", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -415,7 +589,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", sampleCredential)
 		file, err := os.Open(filepath.Join(imageDir, "cirros.raw"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros.raw", "gcs-secret")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros.raw", "gcs-secret", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -431,7 +605,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", sampleCredential)
 		file, err := os.Open(filepath.Join(imageDir, "cirros-qcow2.img"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros-qcow2.img", "gcs-secret")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros-qcow2.img", "gcs-secret", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -447,7 +621,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", sampleCredential)
 		file, err := os.Open(filepath.Join(imageDir, "cirros.raw"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros.raw", "gcs-secret")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros.raw", "gcs-secret", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -463,7 +637,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", sampleCredential)
 		file, err := os.Open(filepath.Join(imageDir, "cirros-qcow2.img"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros-qcow2.img", "gcs-secret")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros-qcow2.img", "gcs-secret", "", 0, nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -476,7 +650,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 })
 
 // Create Cloud Storage Object Reader pointing to a sample image
-func mockGcsObjectReader(ctx context.Context, client *storage.Client, bucket, object string) (io.ReadCloser, error) {
+func mockGcsObjectReader(client *storage.Client, bucket, object, userProject string, generation int64) (io.ReadCloser, error) {
 	var sampleImage = filepath.Join(imageDir, "cirros.raw")
 	return os.Open(sampleImage)
 }
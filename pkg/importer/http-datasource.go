@@ -18,7 +18,6 @@ package importer
 
 import (
 	"context"
-	"crypto/tls"
 	"crypto/x509"
 	"fmt"
 	"io"
@@ -48,6 +47,8 @@ const (
 	nbdkitPid        = "/tmp/nbdkit.pid"
 	nbdkitSocket     = "/tmp/nbdkit.sock"
 	defaultUserAgent = "cdi-golang-importer"
+	// maxHTTPRedirects is the number of redirects the http client follows, like the default Go client
+	maxHTTPRedirects = 10
 )
 
 // HTTPDataSource is the data provider for http(s) endpoints.
@@ -76,6 +77,14 @@ type HTTPDataSource struct {
 	brokenForQemuImg bool
 	// the content length reported by the http server.
 	contentLength uint64
+	// the validators of the source returned by the http server.
+	sourceValidators util.SourceValidators
+	// true if the source was unchanged since the previous import, and is not transferred.
+	notModified bool
+	// true if the source bytes are verified against a checksum manifest as they are read
+	verifiesChecksums bool
+	// reads byte ranges of the endpoint
+	rangeReader rangeReaderFunc
 
 	n image.NbdkitOperation
 }
@@ -86,7 +95,7 @@ var createNbdkitCurl = image.NewNbdkitCurl
 func NewHTTPDataSource(endpoint, accessKey, secKey, certDir string, contentType cdiv1.DataVolumeContentType) (*HTTPDataSource, error) {
 	ep, err := ParseEndpoint(endpoint)
 	if err != nil {
-		return nil, errors.Wrapf(err, fmt.Sprintf("unable to parse endpoint %q", endpoint))
+		return nil, errors.Wrapf(err, fmt.Sprintf("unable to parse endpoint %q", redactSourceURL(endpoint)))
 	}
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -95,12 +104,50 @@ func NewHTTPDataSource(endpoint, accessKey, secKey, certDir string, contentType
 		cancel()
 		return nil, errors.Wrap(err, "Error getting extra headers for HTTP client")
 	}
+	if len(extraHeaders) > 0 || len(secretExtraHeaders) > 0 {
+		klog.V(3).Infof("Extra HTTP headers: %q", redactExtraHeaders(extraHeaders, secretExtraHeaders))
+	}
+
+	tlsConfig, err := newImportTLSFromEnv()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
 
-	httpReader, contentLength, brokenForQemuImg, err := createHTTPReader(ctx, ep, accessKey, secKey, certDir, extraHeaders, secretExtraHeaders)
+	validators := getSourceValidatorsFromEnvironment(contentType)
+	httpReader, contentLength, brokenForQemuImg, sourceValidators, err := createConditionalHTTPReader(ctx, ep, accessKey, secKey, certDir, extraHeaders, secretExtraHeaders, validators)
+	if errors.Is(err, errSourceNotModified) {
+		klog.V(1).Infof("%q was not modified since the previous import, keeping the existing content", redactSourceURL(ep.String()))
+		if sourceValidators == (util.SourceValidators{}) {
+			sourceValidators = *validators
+		}
+		return &HTTPDataSource{
+			ctx:              ctx,
+			cancel:           cancel,
+			contentType:      contentType,
+			endpoint:         ep,
+			sourceValidators: sourceValidators,
+			notModified:      true,
+		}, nil
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	checksumManifest, err := getChecksumManifestFromEnvironment(ctx, ep, accessKey, secKey, certDir, extraHeaders, secretExtraHeaders)
 	if err != nil {
+		httpReader.Close()
 		cancel()
 		return nil, err
 	}
+	if validators != nil {
+		// The existing content is overwritten from now on, a restarted importer must not keep it
+		if err := markImportInProgress(); err != nil {
+			httpReader.Close()
+			cancel()
+			return nil, err
+		}
+	}
 
 	httpSource := &HTTPDataSource{
 		ctx:              ctx,
@@ -111,31 +158,71 @@ func NewHTTPDataSource(endpoint, accessKey, secKey, certDir string, contentType
 		customCA:         certDir,
 		brokenForQemuImg: brokenForQemuImg,
 		contentLength:    contentLength,
+		sourceValidators: sourceValidators,
+	}
+	client, err := createHTTPClient(certDir)
+	if err != nil {
+		httpReader.Close()
+		cancel()
+		return nil, errors.Wrap(err, "Error creating http client")
 	}
-	httpSource.n = createNbdkitCurl(nbdkitPid, accessKey, secKey, certDir, nbdkitSocket, extraHeaders, secretExtraHeaders)
+	client.CheckRedirect = checkRedirect(accessKey, secKey, extraHeaders, secretExtraHeaders)
+	httpSource.rangeReader = httpRangeReader(client, ep, accessKey, secKey, append(extraHeaders, secretExtraHeaders...))
+	httpSource.n = createNbdkitCurl(nbdkitPid, accessKey, secKey, certDir, nbdkitSocket, extraHeaders, secretExtraHeaders, tlsConfig.nbdkitCurlTLS())
 	// We know this is a counting reader, so no need to check.
 	countingReader := httpReader.(*util.CountingReader)
 	go httpSource.pollProgress(countingReader, 10*time.Minute, time.Second)
+	if checksumManifest != nil {
+		if httpSource.httpReader, err = newChecksumReader(httpReader, checksumManifest); err != nil {
+			httpReader.Close()
+			cancel()
+			return nil, err
+		}
+		httpSource.verifiesChecksums = true
+	}
 	return httpSource, nil
 }
 
 // Info is called to get initial information about the data.
 func (hs *HTTPDataSource) Info() (ProcessingPhase, error) {
+	if hs.notModified {
+		return ProcessingPhaseComplete, nil
+	}
 	var err error
-	hs.readers, err = NewFormatReaders(hs.httpReader, hs.contentLength)
+	if hs.contentType == cdiv1.DataVolumeArchive {
+		hs.readers, err = NewFormatReaders(hs.httpReader, hs.contentLength)
+	} else {
+		hs.readers, err = NewDiskFormatReaders(hs.httpReader, hs.contentLength)
+	}
 	if err != nil {
 		klog.Errorf("Error creating readers: %v", err)
 		return ProcessingPhaseError, err
 	}
+	hs.readers.CheckExtensionHint(hs.endpoint.Path)
 	if hs.contentType == cdiv1.DataVolumeArchive {
 		return ProcessingPhaseTransferDataDir, nil
 	}
+	// The importer reads the source instead of qemu-img with a custom CA, or to hash or verify the source bytes
+	readLocally := hs.customCA != "" || hs.readers.HashesSource() || hs.verifiesChecksums
 	if hs.readers.Convert {
-		if hs.brokenForQemuImg || hs.readers.Archived || hs.customCA != "" {
+		if hs.brokenForQemuImg || (hs.readers.Archived && !hs.readers.StreamableArchive()) || readLocally {
 			return ProcessingPhaseTransferScratch, nil
 		}
+		if hs.readers.Archived {
+			seekable, err := xzSeekable(hs.ctx, hs.rangeReader, hs.contentLength)
+			if err != nil {
+				klog.Warningf("Unable to read the xz index, staging the image in scratch space: %v", err)
+			}
+			if !seekable {
+				klog.V(1).Infoln("The blocks of the xz compressed image are too large to decompress ranges, staging it in scratch space")
+				return ProcessingPhaseTransferScratch, nil
+			}
+			// qemu-img needs random access to the image, nbdkit decompresses the ranges it reads from the xz stream
+			klog.V(1).Infoln("Streaming the xz compressed image to qemu-img without scratch space")
+			hs.n.AddFilter(image.NbdkitXzFilter)
+		}
 	} else {
-		if hs.readers.Archived || hs.customCA != "" {
+		if hs.readers.Archived || hs.brokenForQemuImg || readLocally {
 			return ProcessingPhaseTransferDataFile, nil
 		}
 	}
@@ -192,6 +279,20 @@ func (hs *HTTPDataSource) GetURL() *url.URL {
 	return hs.url
 }
 
+// SourceValidators returns the validators of the source returned by the http server.
+func (hs *HTTPDataSource) SourceValidators() util.SourceValidators {
+	return hs.sourceValidators
+}
+
+// SourceNotModified tells whether the source was unchanged since the previous import, and was not transferred.
+func (hs *HTTPDataSource) SourceNotModified() bool {
+	return hs.notModified
+}
+
+func (hs *HTTPDataSource) formatReaders() *FormatReaders {
+	return hs.readers
+}
+
 // Close all readers.
 func (hs *HTTPDataSource) Close() error {
 	var err error
@@ -260,20 +361,20 @@ func createHTTPClient(certDir string) (*http.Client, error) {
 		// Don't set timeout here, since that will be an absolute timeout, we need a relative to last progress timeout.
 	}
 
-	if certDir == "" {
-		return client, nil
+	proxy, err := newImportProxyFromEnv()
+	if err != nil {
+		return nil, err
 	}
-
-	certPool, err := createCertPool(certDir)
+	tlsConfig, err := newImportTLSFromEnv()
 	if err != nil {
 		return nil, err
 	}
 
-	// the default transport contains Proxy configurations to use environment variables and default timeouts
+	// the default transport contains default timeouts, the proxy is taken from the import proxy configuration and
+	// the minimal TLS version and cipher suites from the import TLS configuration
 	transport := http.DefaultTransport.(*http.Transport).Clone()
-	transport.TLSClientConfig = &tls.Config{
-		RootCAs: certPool,
-	}
+	transport.TLSClientConfig = tlsConfig.clientConfig()
+	transport.Proxy = proxy.proxyFunc
 	transport.GetProxyConnectHeader = func(ctx context.Context, proxyURL *url.URL, target string) (http.Header, error) {
 		h := http.Header{}
 		h.Add("User-Agent", defaultUserAgent)
@@ -281,35 +382,91 @@ func createHTTPClient(certDir string) (*http.Client, error) {
 	}
 	client.Transport = transport
 
+	if certDir == "" {
+		return client, nil
+	}
+
+	certPool, err := createCertPool(certDir)
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig.RootCAs = certPool
+
 	return client, nil
 }
 
+// addExtraheaders sets the extra headers on req, replacing the values a redirect copied from the previous request. A
+// User-Agent extra header replaces the default one.
 func addExtraheaders(req *http.Request, extraHeaders []string) {
-	for _, header := range extraHeaders {
-		parts := strings.SplitN(header, ":", 2)
+	header := http.Header{}
+	for _, extraHeader := range extraHeaders {
+		parts := strings.SplitN(extraHeader, ":", 2)
 		if len(parts) > 1 {
-			req.Header.Add(parts[0], parts[1])
+			header.Add(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+		}
+	}
+	if header.Get("User-Agent") == "" {
+		header.Set("User-Agent", defaultUserAgent)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+}
+
+// checkRedirect returns the redirect policy of the http client, following up to maxHTTPRedirects redirects. The basic
+// auth and the secret extra headers are only sent again on a redirect to the host of the endpoint. A redirect to
+// another host, like a presigned object store URL, gets no Authorization header: it would leak the credentials to
+// that host, or be rejected next to the signature of the URL.
+func checkRedirect(accessKey, secKey string, extraHeaders, secretExtraHeaders []string) func(*http.Request, []*http.Request) error {
+	allExtraHeaders := append(append([]string{}, extraHeaders...), secretExtraHeaders...)
+	return func(r *http.Request, via []*http.Request) error {
+		if len(via) >= maxHTTPRedirects {
+			return errors.Errorf("stopped after %d redirects", maxHTTPRedirects)
+		}
+		if host := via[0].URL.Hostname(); !strings.EqualFold(r.URL.Hostname(), host) {
+			klog.V(2).Infof("Redirected from host %s to host %s, not sending the credentials", host, r.URL.Hostname())
+			r.Header.Del("Authorization")
+			for _, secretExtraHeader := range secretExtraHeaders {
+				r.Header.Del(strings.TrimSpace(strings.SplitN(secretExtraHeader, ":", 2)[0]))
+			}
+			addExtraheaders(r, extraHeaders)
+			return nil
+		}
+		if len(accessKey) > 0 && len(secKey) > 0 {
+			r.SetBasicAuth(accessKey, secKey) // Redirects will lose basic auth, so reset them manually
 		}
+		addExtraheaders(r, allExtraHeaders)
+		return nil
+	}
+}
+
+// redactExtraHeaders returns the extra headers to log, the values of the ones read from secrets are redacted
+func redactExtraHeaders(extraHeaders, secretExtraHeaders []string) []string {
+	redacted := append([]string{}, extraHeaders...)
+	for _, secretExtraHeader := range secretExtraHeaders {
+		name := strings.TrimSpace(strings.SplitN(secretExtraHeader, ":", 2)[0])
+		redacted = append(redacted, name+": /secret redacted/")
 	}
-	req.Header.Add("User-Agent", defaultUserAgent)
+	return redacted
 }
 
 func createHTTPReader(ctx context.Context, ep *url.URL, accessKey, secKey, certDir string, extraHeaders, secretExtraHeaders []string) (io.ReadCloser, uint64, bool, error) {
+	reader, total, brokenForQemuImg, _, err := createConditionalHTTPReader(ctx, ep, accessKey, secKey, certDir, extraHeaders, secretExtraHeaders, nil)
+	return reader, total, brokenForQemuImg, err
+}
+
+// createConditionalHTTPReader is createHTTPReader, also returning the validators of the source. With validators, the
+// request is conditional and errSourceNotModified is returned when the source is unchanged.
+func createConditionalHTTPReader(ctx context.Context, ep *url.URL, accessKey, secKey, certDir string, extraHeaders, secretExtraHeaders []string, validators *util.SourceValidators) (io.ReadCloser, uint64, bool, util.SourceValidators, error) {
 	var brokenForQemuImg bool
 	client, err := createHTTPClient(certDir)
 	if err != nil {
-		return nil, uint64(0), false, errors.Wrap(err, "Error creating http client")
+		return nil, uint64(0), false, util.SourceValidators{}, errors.Wrap(err, "Error creating http client")
 	}
 
 	allExtraHeaders := append(extraHeaders, secretExtraHeaders...)
 
-	client.CheckRedirect = func(r *http.Request, via []*http.Request) error {
-		if len(accessKey) > 0 && len(secKey) > 0 {
-			r.SetBasicAuth(accessKey, secKey) // Redirects will lose basic auth, so reset them manually
-		}
-		addExtraheaders(r, allExtraHeaders)
-		return nil
-	}
+	client.CheckRedirect = checkRedirect(accessKey, secKey, extraHeaders, secretExtraHeaders)
 
 	total, err := getContentLength(client, ep, accessKey, secKey, allExtraHeaders)
 	if err != nil {
@@ -319,19 +476,33 @@ func createHTTPReader(ctx context.Context, ep *url.URL, accessKey, secKey, certD
 	req, _ := http.NewRequest("GET", ep.String(), nil)
 
 	addExtraheaders(req, allExtraHeaders)
+	addConditionalHeaders(req, validators)
+	if req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", acceptedContentEncoding)
+	}
 
 	req = req.WithContext(ctx)
 	if len(accessKey) > 0 && len(secKey) > 0 {
 		req.SetBasicAuth(accessKey, secKey)
 	}
-	klog.V(2).Infof("Attempting to get object %q via http client\n", ep.String())
+	klog.V(2).Infof("Attempting to get object %q via http client\n", redactSourceURL(ep.String()))
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, uint64(0), true, errors.Wrap(err, "HTTP request errored")
+		return nil, uint64(0), true, util.SourceValidators{}, errors.Wrap(redactURLError(err), "HTTP request errored")
+	}
+	sourceValidators := sourceValidatorsFromResponse(resp)
+	if resp.StatusCode == http.StatusNotModified && validators != nil {
+		resp.Body.Close()
+		return nil, uint64(0), false, sourceValidators, errSourceNotModified
 	}
 	if resp.StatusCode != 200 {
 		klog.Errorf("http: expected status code 200, got %d", resp.StatusCode)
-		return nil, uint64(0), true, errors.Errorf("expected status code 200, got %d. Status: %s", resp.StatusCode, resp.Status)
+		return nil, uint64(0), true, util.SourceValidators{}, errors.Errorf("expected status code 200, got %d. Status: %s", resp.StatusCode, resp.Status)
+	}
+	if len(secretExtraHeaders) > 0 && resp.Request.URL.String() != ep.String() {
+		// nbdkit doesn't follow redirects with secret extra headers, it can't drop them on a redirect to another host
+		klog.V(2).Infof("The source is redirected and has secret extra headers, avoiding qemu-img")
+		brokenForQemuImg = true
 	}
 
 	acceptRanges, ok := resp.Header["Accept-Ranges"]
@@ -340,25 +511,67 @@ func createHTTPReader(ctx context.Context, ep *url.URL, accessKey, secKey, certD
 		brokenForQemuImg = true
 	}
 
-	if total == 0 {
+	contentEncoded := len(contentEncodings(resp)) > 0
+	if contentEncoded {
+		// qemu-img and the range requests would read the encoded bytes, and the length is the encoded one
+		klog.V(2).Infof("The source has a Content-Encoding, avoiding qemu-img")
+		brokenForQemuImg = true
+		total = 0
+	} else if total == 0 {
 		// The total seems bogus. Let's try the GET Content-Length header
 		total = parseHTTPHeader(resp)
 	}
+
+	body, err := decodeContentEncoding(resp)
+	if err != nil {
+		return nil, uint64(0), true, util.SourceValidators{}, err
+	}
+	if config := getMultipartConfig(); !contentEncoded && config.enabled(total) && ok && acceptRanges[0] == "bytes" {
+		// The server supports byte ranges, close the single stream and download the ranges in parallel.
+		if err := resp.Body.Close(); err != nil {
+			return nil, uint64(0), true, util.SourceValidators{}, errors.Wrap(err, "could not close http response")
+		}
+		body = newMultipartReader(ctx, total, config, httpRangeReader(client, ep, accessKey, secKey, allExtraHeaders))
+	}
 	countingReader := &util.CountingReader{
-		Reader:  resp.Body,
+		Reader:  body,
 		Current: 0,
 	}
-	return countingReader, total, brokenForQemuImg, nil
+	return countingReader, total, brokenForQemuImg, sourceValidators, nil
+}
+
+// httpRangeReader returns a rangeReaderFunc that requests byte ranges of the endpoint.
+func httpRangeReader(client *http.Client, ep *url.URL, accessKey, secKey string, extraHeaders []string) rangeReaderFunc {
+	return func(ctx context.Context, start, end int64) (io.ReadCloser, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", ep.String(), nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create HTTP request")
+		}
+		addExtraheaders(req, extraHeaders)
+		if len(accessKey) > 0 && len(secKey) > 0 {
+			req.SetBasicAuth(accessKey, secKey)
+		}
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, errors.Wrap(redactURLError(err), "HTTP request errored")
+		}
+		if resp.StatusCode != http.StatusPartialContent {
+			resp.Body.Close()
+			return nil, errors.Errorf("expected status code 206, got %d. Status: %s", resp.StatusCode, resp.Status)
+		}
+		return resp.Body, nil
+	}
 }
 
 func (hs *HTTPDataSource) pollProgress(reader *util.CountingReader, idleTime, pollInterval time.Duration) {
-	count := reader.Current
+	count := reader.BytesRead()
 	lastUpdate := time.Now()
 	for {
-		if count < reader.Current {
+		if current := reader.BytesRead(); count < current {
 			// Some progress was made, reset now.
 			lastUpdate = time.Now()
-			count = reader.Current
+			count = current
 		}
 
 		if time.Until(lastUpdate.Add(idleTime)).Nanoseconds() < 0 {
@@ -389,10 +602,10 @@ func getContentLength(client *http.Client, ep *url.URL, accessKey, secKey string
 
 	addExtraheaders(req, extraHeaders)
 
-	klog.V(2).Infof("Attempting to HEAD %q via http client\n", ep.String())
+	klog.V(2).Infof("Attempting to HEAD %q via http client\n", redactSourceURL(ep.String()))
 	resp, err := client.Do(req)
 	if err != nil {
-		return uint64(0), errors.Wrap(err, "HTTP request errored")
+		return uint64(0), errors.Wrap(redactURLError(err), "HTTP request errored")
 	}
 
 	if resp.StatusCode != 200 {
@@ -431,6 +644,10 @@ func parseHTTPHeader(resp *http.Response) uint64 {
 func getExtraHeaders() ([]string, []string, error) {
 	extraHeaders := getExtraHeadersFromEnvironment()
 	secretExtraHeaders, err := getExtraHeadersFromSecrets()
+	// The token of a remote clone source authorizes its export, it is only sent to its host
+	if token := strings.TrimSpace(os.Getenv(common.ImporterBearerToken)); token != "" {
+		secretExtraHeaders = append(secretExtraHeaders, "Authorization: Bearer "+token)
+	}
 	return extraHeaders, secretExtraHeaders, err
 }
 
@@ -24,6 +24,7 @@ import (
 	"kubevirt.io/containerized-data-importer/pkg/util"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert/triple"
+	"kubevirt.io/containerized-data-importer/tests/utils"
 )
 
 var (
@@ -115,6 +116,68 @@ var _ = Describe("Http data source", func() {
 		Expect(ProcessingPhaseTransferDataFile).To(Equal(newPhase))
 	})
 
+	It("calling info with an xz compressed qcow2 image should stream it to qemu-img without scratch space", func() {
+		nbdkit := &filterRecordingNbdkit{}
+		createNbdkitCurl = func(string, string, string, string, string, []string, []string, *image.NbdkitCurlTLS) image.NbdkitOperation {
+			return nbdkit
+		}
+		source, err := utils.FormatTestData(cirrosFilePath, tmpDir, image.ExtXz)
+		Expect(err).NotTo(HaveOccurred())
+		xzTs := createTestServer(tmpDir)
+		defer xzTs.Close()
+		dp, err = NewHTTPDataSource(xzTs.URL+"/"+filepath.Base(source), "", "", "", cdiv1.DataVolumeKubeVirt)
+		Expect(err).NotTo(HaveOccurred())
+		newPhase, err := dp.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(newPhase).To(Equal(ProcessingPhaseConvert))
+		Expect(nbdkit.filters).To(ConsistOf(image.NbdkitXzFilter))
+		Expect(nbdkit.started).To(BeTrue())
+		Expect(dp.GetURL().Scheme).To(Equal("nbd+unix"))
+		// Close the data source before the server, which waits for the open connections
+		Expect(dp.Close()).To(Succeed())
+		dp = nil
+	})
+
+	It("calling info with an xz compressed qcow2 image with a block too large for nbdkit should return TransferScratch", func() {
+		nbdkit := &filterRecordingNbdkit{}
+		createNbdkitCurl = func(string, string, string, string, string, []string, []string, *image.NbdkitCurlTLS) image.NbdkitOperation {
+			return nbdkit
+		}
+		defaultMaxBlock := nbdkitXzMaxBlock
+		nbdkitXzMaxBlock = 1024
+		defer func() { nbdkitXzMaxBlock = defaultMaxBlock }()
+		source, err := utils.FormatTestData(cirrosFilePath, tmpDir, image.ExtXz)
+		Expect(err).NotTo(HaveOccurred())
+		xzTs := createTestServer(tmpDir)
+		defer xzTs.Close()
+		dp, err = NewHTTPDataSource(xzTs.URL+"/"+filepath.Base(source), "", "", "", cdiv1.DataVolumeKubeVirt)
+		Expect(err).NotTo(HaveOccurred())
+		newPhase, err := dp.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(newPhase).To(Equal(ProcessingPhaseTransferScratch))
+		Expect(nbdkit.filters).To(BeEmpty())
+		Expect(nbdkit.started).To(BeFalse())
+		Expect(dp.Close()).To(Succeed())
+		dp = nil
+	})
+
+	It("calling info with a mislabeled gzipped qcow2 image should return TransferScratch", func() {
+		source, err := utils.FormatTestData(cirrosFilePath, tmpDir, image.ExtGz)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Rename(source, filepath.Join(tmpDir, "disk.img"))).To(Succeed())
+		mislabeledTs := createTestServer(tmpDir)
+		defer mislabeledTs.Close()
+		dp, err = NewHTTPDataSource(mislabeledTs.URL+"/disk.img", "", "", "", cdiv1.DataVolumeKubeVirt)
+		Expect(err).NotTo(HaveOccurred())
+		newPhase, err := dp.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ProcessingPhaseTransferScratch).To(Equal(newPhase))
+		Expect(dp.readers.ImageFormat()).To(Equal("qcow2"))
+		// Close the data source before the server, which waits for the open connections
+		Expect(dp.Close()).To(Succeed())
+		dp = nil
+	})
+
 	table.DescribeTable("calling transfer should", func(image string, contentType cdiv1.DataVolumeContentType, expectedPhase ProcessingPhase, scratchPath string, want []byte, wantErr bool) {
 		flushRead = want
 		if scratchPath == "" {
@@ -191,6 +254,37 @@ var _ = Describe("Http data source", func() {
 		_, err := dp.Info()
 		Expect(err).NotTo(HaveOccurred())
 	})
+
+	It("should authorize the transfer from a remote clone source with its bearer token", func() {
+		os.Setenv(common.ImporterBearerToken, "hub-t0ken\n")
+		defer os.Unsetenv(common.ImporterBearerToken)
+		ts2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			if r.Header.Get("Authorization") != "Bearer hub-t0ken" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			response, err := ts.Client().Get(ts.URL + "/" + r.RequestURI)
+			Expect(err).NotTo(HaveOccurred())
+			body, err := io.ReadAll(response.Body)
+			Expect(err).NotTo(HaveOccurred())
+			w.Write(body)
+		}))
+		dp, err = NewHTTPDataSource(ts2.URL+"/"+tinyCoreGz, "", "", "", cdiv1.DataVolumeKubeVirt)
+		Expect(err).NotTo(HaveOccurred())
+		result, err := dp.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(ProcessingPhaseTransferDataFile))
+	})
+
+	It("should redact the bearer token of a remote clone source", func() {
+		os.Setenv(common.ImporterBearerToken, "hub-t0ken")
+		defer os.Unsetenv(common.ImporterBearerToken)
+		extraHeaders, secretExtraHeaders, err := getExtraHeaders()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secretExtraHeaders).To(ContainElement("Authorization: Bearer hub-t0ken"))
+		Expect(redactExtraHeaders(extraHeaders, secretExtraHeaders)).ToNot(ContainElement(ContainSubstring("hub-t0ken")))
+	})
 })
 
 var _ = Describe("Http client", func() {
@@ -307,6 +401,170 @@ var _ = Describe("Http reader", func() {
 		Expect(err).ToNot(HaveOccurred())
 	})
 
+	It("should send the extra headers and a custom User-Agent once on every request, including redirects and ranges", func() {
+		var received []http.Header
+		redirTs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = append(received, r.Header.Clone())
+			w.Header().Add("Content-Length", "25")
+			if r.Header.Get("Range") != "" {
+				w.WriteHeader(http.StatusPartialContent)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer redirTs.Close()
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = append(received, r.Header.Clone())
+			http.Redirect(w, r, redirTs.URL, http.StatusFound)
+		}))
+		defer ts.Close()
+		ep, err := url.Parse(ts.URL)
+		Expect(err).ToNot(HaveOccurred())
+		extraHeaders := []string{"User-Agent: mirror-client/1.0", "X-Mirror: eu"}
+		secretExtraHeaders := []string{"X-Api-Key: s3cr3t"}
+		r, _, _, err := createHTTPReader(context.Background(), ep, "", "", "", extraHeaders, secretExtraHeaders)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(r.Close()).To(Succeed())
+
+		client, err := createHTTPClient("")
+		Expect(err).ToNot(HaveOccurred())
+		rangeEp, err := url.Parse(redirTs.URL)
+		Expect(err).ToNot(HaveOccurred())
+		body, err := httpRangeReader(client, rangeEp, "", "", append(extraHeaders, secretExtraHeaders...))(context.Background(), 0, 24)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(body.Close()).To(Succeed())
+
+		// HEAD and GET, each redirected, and the range request
+		Expect(received).To(HaveLen(5))
+		for _, header := range received {
+			Expect(header.Values("User-Agent")).To(Equal([]string{"mirror-client/1.0"}))
+			Expect(header.Values("X-Mirror")).To(Equal([]string{"eu"}))
+			Expect(header.Values("X-Api-Key")).To(Equal([]string{"s3cr3t"}))
+		}
+	})
+
+	Context("redirected", func() {
+		var received []http.Header
+
+		// newRedirectedServers returns a server redirecting to a presigned URL on redirectHost, and the server of the
+		// object behind that URL
+		newRedirectedServers := func(redirectHost string) (*httptest.Server, *httptest.Server) {
+			received = nil
+			objectTs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = append(received, r.Header.Clone())
+				w.Header().Add("Content-Length", "25")
+				w.WriteHeader(http.StatusOK)
+			}))
+			objectURL, err := url.Parse(objectTs.URL)
+			Expect(err).ToNot(HaveOccurred())
+			objectURL.Host = redirectHost + ":" + objectURL.Port()
+			objectURL.Path = "/bucket/disk.img"
+			objectURL.RawQuery = "X-Amz-Signature=abc"
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, objectURL.String(), http.StatusFound)
+			}))
+			return ts, objectTs
+		}
+
+		readRedirected := func(ts *httptest.Server, accessKey, secKey string, secretExtraHeaders []string) {
+			ep, err := url.Parse(ts.URL)
+			Expect(err).ToNot(HaveOccurred())
+			r, total, _, err := createHTTPReader(context.Background(), ep, accessKey, secKey, "", []string{"X-Mirror: eu"}, secretExtraHeaders)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(total).To(Equal(uint64(25)))
+			Expect(r.Close()).To(Succeed())
+			// HEAD and GET
+			Expect(received).To(HaveLen(2))
+		}
+
+		table.DescribeTable("to another host should not send the credentials", func(accessKey, secKey string, secretExtraHeaders []string) {
+			ts, objectTs := newRedirectedServers("localhost")
+			defer ts.Close()
+			defer objectTs.Close()
+			readRedirected(ts, accessKey, secKey, secretExtraHeaders)
+			for _, header := range received {
+				Expect(header.Get("Authorization")).To(BeEmpty())
+				Expect(header.Get("X-Api-Key")).To(BeEmpty())
+				Expect(header.Values("X-Mirror")).To(Equal([]string{"eu"}))
+				Expect(header.Values("User-Agent")).To(Equal([]string{defaultUserAgent}))
+			}
+		},
+			table.Entry("with basic auth", "user", "password", nil),
+			table.Entry("with a secret Authorization extra header", "", "", []string{"Authorization: Bearer t0k3n"}),
+			table.Entry("with a secret extra header", "", "", []string{"X-Api-Key: s3cr3t"}),
+		)
+
+		It("to the same host should send the credentials", func() {
+			ts, objectTs := newRedirectedServers("127.0.0.1")
+			defer ts.Close()
+			defer objectTs.Close()
+			readRedirected(ts, "", "", []string{"Authorization: Bearer t0k3n", "X-Api-Key: s3cr3t"})
+			for _, header := range received {
+				Expect(header.Values("Authorization")).To(Equal([]string{"Bearer t0k3n"}))
+				Expect(header.Values("X-Api-Key")).To(Equal([]string{"s3cr3t"}))
+			}
+		})
+
+		table.DescribeTable("should avoid qemu-img", func(secretExtraHeaders []string, broken bool) {
+			objectTs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("Accept-Ranges", "bytes")
+				w.Header().Add("Content-Length", "25")
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer objectTs.Close()
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, objectTs.URL+"/disk.img", http.StatusFound)
+			}))
+			defer ts.Close()
+			ep, err := url.Parse(ts.URL)
+			Expect(err).ToNot(HaveOccurred())
+			r, _, brokenForQemuImg, err := createHTTPReader(context.Background(), ep, "", "", "", nil, secretExtraHeaders)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Close()).To(Succeed())
+			Expect(brokenForQemuImg).To(Equal(broken))
+		},
+			table.Entry("on a redirect with secret extra headers, which nbdkit doesn't follow", []string{"X-Api-Key: s3cr3t"}, true),
+			table.Entry("not on a redirect without secret extra headers", nil, false),
+		)
+
+		It("should stop after too many redirects", func() {
+			redirects := 0
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				redirects++
+				http.Redirect(w, r, "/loop", http.StatusFound)
+			}))
+			defer ts.Close()
+			ep, err := url.Parse(ts.URL)
+			Expect(err).ToNot(HaveOccurred())
+			_, _, _, err = createHTTPReader(context.Background(), ep, "", "", "", nil, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("stopped after 10 redirects"))
+			// HEAD and GET
+			Expect(redirects).To(Equal(2 * maxHTTPRedirects))
+		})
+	})
+
+	It("should send the default User-Agent without a User-Agent extra header", func() {
+		var userAgent string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userAgent = r.Header.Get("User-Agent")
+			w.Header().Add("Content-Length", "25")
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer ts.Close()
+		ep, err := url.Parse(ts.URL)
+		Expect(err).ToNot(HaveOccurred())
+		r, _, _, err := createHTTPReader(context.Background(), ep, "", "", "", []string{"X-Mirror: eu"}, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(r.Close()).To(Succeed())
+		Expect(userAgent).To(Equal(defaultUserAgent))
+	})
+
+	It("should redact the values of the secret extra headers to log", func() {
+		redacted := redactExtraHeaders([]string{"X-Mirror: eu"}, []string{"X-Api-Key: s3cr3t", "Authorization:Token t0k3n"})
+		Expect(redacted).To(Equal([]string{"X-Mirror: eu", "X-Api-Key: /secret redacted/", "Authorization: /secret redacted/"}))
+	})
+
 	It("should continue even if Content-Length is bogus", func() {
 		redirTs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			defer w.WriteHeader(http.StatusOK)
@@ -435,6 +693,120 @@ var _ = Describe("http pollprogress", func() {
 	})
 })
 
+var _ = Describe("Conditional http import", func() {
+	var (
+		ts               *httptest.Server
+		ds               *HTTPDataSource
+		targetDir        string
+		sourceETag       string
+		ignoreConditions bool
+		ifNoneMatch      string
+		origTargetDir    = importTargetDir
+	)
+
+	BeforeEach(func() {
+		createNbdkitCurl = image.NewMockNbdkitCurl
+		var err error
+		targetDir, err = os.MkdirTemp("", "target")
+		Expect(err).NotTo(HaveOccurred())
+		importTargetDir = targetDir
+		Expect(os.WriteFile(filepath.Join(targetDir, common.DiskImageName), cirrosData, 0644)).To(Succeed())
+		sourceETag = `"v1"`
+		ignoreConditions = false
+		ds = nil
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				ifNoneMatch = r.Header.Get("If-None-Match")
+			}
+			if ignoreConditions {
+				r.Header.Del("If-None-Match")
+			}
+			w.Header().Set("ETag", sourceETag)
+			http.ServeContent(w, r, cirrosFileName, time.Time{}, strings.NewReader(string(cirrosData)))
+		}))
+		os.Setenv(common.ImporterSourceETag, `"v1"`)
+	})
+
+	AfterEach(func() {
+		if ds != nil {
+			Expect(ds.Close()).To(Succeed())
+		}
+		ts.Close()
+		os.Unsetenv(common.ImporterSourceETag)
+		importTargetDir = origTargetDir
+		os.RemoveAll(targetDir)
+	})
+
+	It("should skip the transfer of an unchanged source", func() {
+		var err error
+		ds, err = NewHTTPDataSource(ts.URL+"/"+cirrosFileName, "", "", "", cdiv1.DataVolumeKubeVirt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ifNoneMatch).To(Equal(`"v1"`))
+		Expect(ds.SourceNotModified()).To(BeTrue())
+		Expect(ds.SourceValidators()).To(Equal(util.SourceValidators{ETag: `"v1"`}))
+		phase, err := ds.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseComplete))
+		Expect(filepath.Join(targetDir, importInProgressFile)).ToNot(BeAnExistingFile())
+		content, err := os.ReadFile(filepath.Join(targetDir, common.DiskImageName))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(content).To(Equal(cirrosData))
+	})
+
+	It("should import a source whose ETag changed", func() {
+		sourceETag = `"v2"`
+		var err error
+		ds, err = NewHTTPDataSource(ts.URL+"/"+cirrosFileName, "", "", "", cdiv1.DataVolumeKubeVirt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ifNoneMatch).To(Equal(`"v1"`))
+		Expect(ds.SourceNotModified()).To(BeFalse())
+		Expect(ds.SourceValidators()).To(Equal(util.SourceValidators{ETag: `"v2"`}))
+		phase, err := ds.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseConvert))
+
+		By("Checking a restarted importer does not keep the partially overwritten content")
+		Expect(filepath.Join(targetDir, importInProgressFile)).To(BeAnExistingFile())
+		Expect(getSourceValidatorsFromEnvironment(cdiv1.DataVolumeKubeVirt)).To(BeNil())
+		Expect(CompleteConditionalImport()).To(Succeed())
+		Expect(filepath.Join(targetDir, importInProgressFile)).ToNot(BeAnExistingFile())
+		Expect(getSourceValidatorsFromEnvironment(cdiv1.DataVolumeKubeVirt)).ToNot(BeNil())
+	})
+
+	It("should import the source when the server doesn't support conditional requests", func() {
+		ignoreConditions = true
+		var err error
+		ds, err = NewHTTPDataSource(ts.URL+"/"+cirrosFileName, "", "", "", cdiv1.DataVolumeKubeVirt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ifNoneMatch).To(Equal(`"v1"`))
+		Expect(ds.SourceNotModified()).To(BeFalse())
+		phase, err := ds.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseConvert))
+	})
+
+	table.DescribeTable("should not send a conditional request", func(prepare func(), contentType cdiv1.DataVolumeContentType) {
+		prepare()
+		var err error
+		ds, err = NewHTTPDataSource(ts.URL+"/"+cirrosFileName, "", "", "", contentType)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ifNoneMatch).To(BeEmpty())
+		Expect(ds.SourceNotModified()).To(BeFalse())
+		Expect(ds.SourceValidators()).To(Equal(util.SourceValidators{ETag: `"v1"`}))
+	},
+		table.Entry("without validators of a previous import", func() {
+			os.Unsetenv(common.ImporterSourceETag)
+		}, cdiv1.DataVolumeKubeVirt),
+		table.Entry("when the target has no disk image", func() {
+			Expect(os.Remove(filepath.Join(targetDir, common.DiskImageName))).To(Succeed())
+		}, cdiv1.DataVolumeKubeVirt),
+		table.Entry("when a previous import was interrupted", func() {
+			Expect(markImportInProgress()).To(Succeed())
+		}, cdiv1.DataVolumeKubeVirt),
+		table.Entry("for an archive", func() {}, cdiv1.DataVolumeArchive),
+	)
+})
+
 func createTestServer(imageDir string) *httptest.Server {
 	return httptest.NewServer(http.FileServer(http.Dir(imageDir)))
 }
@@ -466,3 +838,19 @@ func (r *EndlessReader) Read(p []byte) (int, error) {
 func (r *EndlessReader) Close() error {
 	return r.Reader.Close()
 }
+
+// filterRecordingNbdkit records the filters added to nbdkit
+type filterRecordingNbdkit struct {
+	filters []image.NbdkitFilter
+	started bool
+}
+
+func (n *filterRecordingNbdkit) StartNbdkit(source string) error {
+	n.started = true
+	return nil
+}
+func (n *filterRecordingNbdkit) KillNbdkit() error       { return nil }
+func (n *filterRecordingNbdkit) AddEnvVariable(v string) {}
+func (n *filterRecordingNbdkit) AddFilter(filter image.NbdkitFilter) {
+	n.filters = append(n.filters, filter)
+}
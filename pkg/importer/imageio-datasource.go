@@ -213,13 +213,13 @@ func (is *ImageioDataSource) Close() error {
 }
 
 func (is *ImageioDataSource) pollProgress(reader *util.CountingReader, idleTime, pollInterval time.Duration) {
-	count := reader.Current
+	count := reader.BytesRead()
 	lastUpdate := time.Now()
 	for {
-		if count < reader.Current {
+		if current := reader.BytesRead(); count < current {
 			// Some progress was made, reset now.
 			lastUpdate = time.Now()
-			count = reader.Current
+			count = current
 		}
 
 		if time.Until(lastUpdate.Add(idleTime)).Nanoseconds() < 0 {
@@ -307,7 +307,7 @@ func (is *ImageioDataSource) StreamExtents(extentsReader *extentReader, fileName
 					return errors.Wrap(err, "failed to zero range on destination")
 				}
 			}
-			is.readers.progressReader.Current += uint64(extent.Length)
+			is.readers.progressReader.AddBytes(uint64(extent.Length))
 		} else {
 			klog.Infof("Downloading %d-byte extent at offset %d", extent.Length, extent.Start)
 			responseBody, err := extentsReader.GetRange(extent.Start, extent.Start+extent.Length-1)
@@ -351,17 +351,17 @@ func (is *ImageioDataSource) transferExtent(source io.ReadCloser, dest io.Writer
 // monitorExtentsProgress sends a ticket renewal if there has been no download progress during the
 // polling time. This can happen if the destination storage does not have a fast way to punch holes.
 func (is *ImageioDataSource) monitorExtentsProgress(transferID string, extentsReader *extentReader, pollTime time.Duration, doneChannel chan struct{}) {
-	current := is.readers.progressReader.Current
+	current := is.readers.progressReader.BytesRead()
 	for {
 		select {
 		case <-time.After(pollTime):
-			if is.readers.progressReader.Current <= current {
+			if is.readers.progressReader.BytesRead() <= current {
 				klog.Infof("No progress in the last %s, attempting ticket renewal to avoid timeout", pollTime)
 				if err := is.renewExtentsTicket(transferID, extentsReader); err != nil {
 					klog.Infof("Error renewing ticket: %v", err)
 				}
 			} else {
-				current = is.readers.progressReader.Current
+				current = is.readers.progressReader.BytesRead()
 			}
 		case <-doneChannel:
 			klog.Info("Closing ticket expiration monitor")
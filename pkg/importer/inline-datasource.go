@@ -0,0 +1,47 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"encoding/base64"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+)
+
+// inlineStream decodes the base64 encoded image of an inline source
+type inlineStream struct {
+	io.Reader
+	io.Closer
+}
+
+// NewInlineDataSource creates a new instance of an UploadDataSource importing the image of an inline source, read base64
+// encoded from the file at path. The image then goes through the same format readers and conversion as an upload.
+func NewInlineDataSource(path string, contentType cdiv1.DataVolumeContentType, preallocation bool) (*UploadDataSource, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to open the inline source")
+	}
+	stream := &inlineStream{
+		Reader: base64.NewDecoder(base64.StdEncoding, file),
+		Closer: file,
+	}
+	return NewUploadDataSource(stream, contentType, preallocation), nil
+}
@@ -0,0 +1,97 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Inline data source", func() {
+	var (
+		ud     *UploadDataSource
+		tmpDir string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "inline")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		if ud != nil {
+			ud.Close()
+		}
+		os.RemoveAll(tmpDir)
+	})
+
+	// writeInlineSource writes the file at imagePath base64 encoded, wrapped like in a YAML manifest
+	writeInlineSource := func(imagePath string) string {
+		data, err := os.ReadFile(imagePath)
+		Expect(err).NotTo(HaveOccurred())
+		encoded := []byte(base64.StdEncoding.EncodeToString(data))
+		var wrapped bytes.Buffer
+		for len(encoded) > 76 {
+			wrapped.Write(encoded[:76])
+			wrapped.WriteByte('\n')
+			encoded = encoded[76:]
+		}
+		wrapped.Write(encoded)
+		path := filepath.Join(tmpDir, "data")
+		Expect(os.WriteFile(path, wrapped.Bytes(), 0600)).To(Succeed())
+		return path
+	}
+
+	It("should decode and import a gzip compressed raw image", func() {
+		var err error
+		ud, err = NewInlineDataSource(writeInlineSource(tinyCoreGzFilePath), dvKubevirt, false)
+		Expect(err).NotTo(HaveOccurred())
+		phase, err := ud.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferDataFile))
+		dest := filepath.Join(tmpDir, "disk.img")
+		phase, err = ud.TransferFile(dest)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseResize))
+
+		expected, err := os.ReadFile(tinyCoreFilePath)
+		Expect(err).NotTo(HaveOccurred())
+		written, err := os.ReadFile(dest)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bytes.Equal(written, expected)).To(BeTrue())
+	})
+
+	It("should decode a qcow2 image to scratch space for conversion", func() {
+		var err error
+		ud, err = NewInlineDataSource(writeInlineSource(cirrosFilePath), dvKubevirt, false)
+		Expect(err).NotTo(HaveOccurred())
+		phase, err := ud.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferScratch))
+		phase, err = ud.Transfer(tmpDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseConvert))
+		Expect(ud.GetURL().Path).To(Equal(filepath.Join(tmpDir, tempFile)))
+	})
+
+	It("should fail on invalid base64 data", func() {
+		path := filepath.Join(tmpDir, "data")
+		Expect(os.WriteFile(path, []byte("not base64!"), 0600)).To(Succeed())
+		var err error
+		ud, err = NewInlineDataSource(path, dvKubevirt, false)
+		Expect(err).NotTo(HaveOccurred())
+		phase, err := ud.Info()
+		Expect(err).To(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseError))
+	})
+
+	It("should fail when the inline source is not mounted", func() {
+		_, err := NewInlineDataSource(filepath.Join(tmpDir, "missing"), dvKubevirt, false)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("unable to open the inline source"))
+	})
+})
@@ -0,0 +1,57 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bytes"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// luksMagic starts the header of a LUKS container
+var luksMagic = []byte{'L', 'U', 'K', 'S', 0xba, 0xbe}
+
+// checkLuksTarget checks the target of a LUKS container is a block device, the container spanning the whole device
+func checkLuksTarget(dataFile string) error {
+	size, err := getAvailableSpaceBlockFunc(dataFile)
+	if err != nil {
+		return errors.Wrap(err, "could not get the size of the target")
+	}
+	if size < 0 {
+		return errors.New("the LUKS encryption of the target needs a block volume")
+	}
+	return nil
+}
+
+// checkLuksHeader checks the target starts with a LUKS header once the image was converted into it
+func checkLuksHeader(dataFile string) error {
+	f, err := os.Open(dataFile)
+	if err != nil {
+		return errors.Wrap(err, "could not open the target")
+	}
+	defer f.Close()
+	magic := make([]byte, len(luksMagic))
+	if _, err := io.ReadFull(f, magic); err != nil {
+		return errors.Wrap(err, "could not read the LUKS header of the target")
+	}
+	if !bytes.Equal(magic, luksMagic) {
+		return errors.New("the target has no LUKS header after the conversion")
+	}
+	return nil
+}
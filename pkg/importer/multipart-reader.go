@@ -0,0 +1,224 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"context"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+const (
+	// defaultMultipartParallelism of 1 means the source is downloaded as a single stream.
+	defaultMultipartParallelism = 1
+	// defaultMultipartPartSize is the size of a single range request.
+	defaultMultipartPartSize = int64(64 * 1024 * 1024)
+	// multipartChunkSize is the size of the buffers a part is read into.
+	multipartChunkSize = int64(1024 * 1024)
+	// multipartBufferedChunks is the number of chunks of a part downloaded ahead of the reader.
+	multipartBufferedChunks = 4
+)
+
+// rangeReaderFunc returns a reader for the inclusive byte range [start, end] of the source.
+type rangeReaderFunc func(ctx context.Context, start, end int64) (io.ReadCloser, error)
+
+// multipartConfig holds the settings used to download a source in multiple concurrent parts.
+type multipartConfig struct {
+	parallelism int
+	partSize    int64
+}
+
+// enabled returns true if a source of the passed in length should be downloaded in multiple parts.
+func (c multipartConfig) enabled(length uint64) bool {
+	return c.parallelism > 1 && c.partSize > 0 && length > uint64(c.partSize)
+}
+
+// getMultipartConfig reads the multipart download settings from the environment.
+func getMultipartConfig() multipartConfig {
+	config := multipartConfig{
+		parallelism: defaultMultipartParallelism,
+		partSize:    defaultMultipartPartSize,
+	}
+	if value := os.Getenv(common.ImporterMultipartParallelism); value != "" {
+		parallelism, err := strconv.Atoi(value)
+		if err != nil || parallelism < 1 {
+			klog.Warningf("Ignoring invalid multipart parallelism %q", value)
+		} else {
+			config.parallelism = parallelism
+		}
+	}
+	if value := os.Getenv(common.ImporterMultipartPartSize); value != "" {
+		partSize, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || partSize < 1 {
+			klog.Warningf("Ignoring invalid multipart part size %q", value)
+		} else {
+			config.partSize = partSize
+		}
+	}
+	return config
+}
+
+// multipartPart is a part being downloaded. Its data is streamed through chunks, that is closed once the part is
+// downloaded or failed with err.
+type multipartPart struct {
+	chunks chan []byte
+	err    error
+}
+
+// multipartReader downloads a source of known length as a number of byte ranges that are fetched concurrently,
+// and returns the data in order. At most parallelism parts are being downloaded at any time, each holding at most
+// multipartBufferedChunks chunks that were not read yet.
+type multipartReader struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	length    int64
+	partSize  int64
+	openRange rangeReaderFunc
+	// parts contains the parts in source order.
+	parts chan *multipartPart
+	// tokens limits the number of parts downloading or waiting to be read.
+	tokens  chan struct{}
+	start   sync.Once
+	part    *multipartPart
+	current []byte
+	err     error
+}
+
+// newMultipartReader creates a reader that downloads length bytes in parts using openRange.
+// Downloading starts on the first Read.
+func newMultipartReader(ctx context.Context, length uint64, config multipartConfig, openRange rangeReaderFunc) *multipartReader {
+	ctx, cancel := context.WithCancel(ctx)
+	return &multipartReader{
+		ctx:       ctx,
+		cancel:    cancel,
+		length:    int64(length),
+		partSize:  config.partSize,
+		openRange: openRange,
+		parts:     make(chan *multipartPart, config.parallelism),
+		tokens:    make(chan struct{}, config.parallelism),
+	}
+}
+
+// dispatch starts the download of each part in order, while the number of outstanding parts allows it.
+func (mr *multipartReader) dispatch() {
+	klog.V(1).Infof("Downloading %d bytes in parts of %d bytes, %d at a time", mr.length, mr.partSize, cap(mr.tokens))
+	defer close(mr.parts)
+	for start := int64(0); start < mr.length; start += mr.partSize {
+		if mr.ctx.Err() != nil {
+			return
+		}
+		end := start + mr.partSize - 1
+		if end >= mr.length {
+			end = mr.length - 1
+		}
+		select {
+		case mr.tokens <- struct{}{}:
+		case <-mr.ctx.Done():
+			return
+		}
+		part := &multipartPart{chunks: make(chan []byte, multipartBufferedChunks)}
+		go func(start, end int64) {
+			part.err = fetchPart(mr.ctx, mr.openRange, start, end, part.chunks)
+			close(part.chunks)
+		}(start, end)
+		select {
+		case mr.parts <- part:
+		case <-mr.ctx.Done():
+			return
+		}
+	}
+}
+
+// fetchPart downloads the inclusive byte range [start, end], and sends its data to chunks as it arrives.
+func fetchPart(ctx context.Context, openRange rangeReaderFunc, start, end int64, chunks chan<- []byte) error {
+	reader, err := openRange(ctx, start, end)
+	if err != nil {
+		return errors.Wrapf(err, "could not request range %d-%d", start, end)
+	}
+	defer reader.Close()
+	for offset := start; offset <= end; {
+		size := end - offset + 1
+		if size > multipartChunkSize {
+			size = multipartChunkSize
+		}
+		chunk := make([]byte, size)
+		if _, err := io.ReadFull(reader, chunk); err != nil {
+			return errors.Wrapf(err, "could not read range %d-%d", start, end)
+		}
+		offset += size
+		select {
+		case chunks <- chunk:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Read returns the downloaded data in order, waiting for the next data when needed.
+func (mr *multipartReader) Read(p []byte) (int, error) {
+	mr.start.Do(func() {
+		go mr.dispatch()
+	})
+	for len(mr.current) == 0 {
+		if mr.err != nil {
+			return 0, mr.err
+		}
+		if mr.part == nil {
+			part, ok := <-mr.parts
+			if !ok {
+				if err := mr.ctx.Err(); err != nil {
+					mr.err = err
+				} else {
+					mr.err = io.EOF
+				}
+				continue
+			}
+			mr.part = part
+		}
+		chunk, ok := <-mr.part.chunks
+		if ok {
+			mr.current = chunk
+			continue
+		}
+		if mr.part.err != nil {
+			mr.err = mr.part.err
+			mr.cancel()
+			continue
+		}
+		// The current part has been consumed, allow the next download to start.
+		mr.part = nil
+		<-mr.tokens
+	}
+	n := copy(p, mr.current)
+	mr.current = mr.current[n:]
+	return n, nil
+}
+
+// Close stops all outstanding downloads.
+func (mr *multipartReader) Close() error {
+	mr.cancel()
+	return nil
+}
@@ -0,0 +1,267 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+const (
+	slowServerLatency   = 20 * time.Millisecond
+	slowServerChunkSize = 32 * 1024
+)
+
+var _ = Describe("Multipart reader", func() {
+	table.DescribeTable("should return the data in order", func(length int, parallelism int, partSize int64) {
+		data := randomData(length)
+		config := multipartConfig{parallelism: parallelism, partSize: partSize}
+		mr := newMultipartReader(context.Background(), uint64(length), config, bytesRangeReader(data, nil))
+		defer mr.Close()
+		result, err := io.ReadAll(mr)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(data))
+	},
+		table.Entry("with parts that divide the length", 4096, 4, int64(1024)),
+		table.Entry("with a short last part", 4000, 4, int64(1024)),
+		table.Entry("with more parts than parallelism", 10000, 2, int64(100)),
+		table.Entry("with a single part", 100, 4, int64(1024)),
+	)
+
+	It("should return an error if a part fails", func() {
+		data := randomData(4096)
+		failures := map[int64]error{2048: errors.New("range failure")}
+		config := multipartConfig{parallelism: 2, partSize: 1024}
+		mr := newMultipartReader(context.Background(), uint64(len(data)), config, bytesRangeReader(data, failures))
+		defer mr.Close()
+		_, err := io.ReadAll(mr)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("range failure"))
+	})
+
+	It("should return an error if a part is short", func() {
+		data := randomData(4096)
+		config := multipartConfig{parallelism: 2, partSize: 1024}
+		mr := newMultipartReader(context.Background(), uint64(len(data)+10), config, bytesRangeReader(data, nil))
+		defer mr.Close()
+		_, err := io.ReadAll(mr)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should stop reading once closed", func() {
+		data := randomData(4096)
+		config := multipartConfig{parallelism: 2, partSize: 1024}
+		mr := newMultipartReader(context.Background(), uint64(len(data)), config, bytesRangeReader(data, nil))
+		Expect(mr.Close()).To(Succeed())
+		_, err := io.ReadAll(mr)
+		Expect(err).To(MatchError(context.Canceled))
+	})
+
+	It("should stream a part before it is downloaded", func() {
+		data := randomData(int(3 * multipartChunkSize))
+		stalled, release := io.Pipe()
+		defer release.Close()
+		config := multipartConfig{parallelism: 2, partSize: int64(len(data))}
+		ctx, cancel := context.WithCancel(context.Background())
+		mr := newMultipartReader(ctx, uint64(len(data)), config, func(ctx context.Context, start, end int64) (io.ReadCloser, error) {
+			// the first chunk is available, the rest stalls
+			return io.NopCloser(io.MultiReader(bytes.NewReader(data[:multipartChunkSize]), stalled)), nil
+		})
+		defer mr.Close()
+		result := make([]byte, multipartChunkSize)
+		_, err := io.ReadFull(mr, result)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(data[:multipartChunkSize]))
+		cancel()
+		// a real range reader fails on cancellation, the stalled one once its pipe is closed
+		release.Close()
+		_, err = io.ReadAll(mr)
+		Expect(err).To(HaveOccurred())
+	})
+
+	table.DescribeTable("getMultipartConfig should", func(parallelism, partSize string, expected multipartConfig) {
+		os.Setenv(common.ImporterMultipartParallelism, parallelism)
+		os.Setenv(common.ImporterMultipartPartSize, partSize)
+		defer os.Unsetenv(common.ImporterMultipartParallelism)
+		defer os.Unsetenv(common.ImporterMultipartPartSize)
+		Expect(getMultipartConfig()).To(Equal(expected))
+	},
+		table.Entry("use defaults when not set", "", "", multipartConfig{parallelism: defaultMultipartParallelism, partSize: defaultMultipartPartSize}),
+		table.Entry("use values when set", "4", "1024", multipartConfig{parallelism: 4, partSize: 1024}),
+		table.Entry("ignore invalid values", "-1", "abc", multipartConfig{parallelism: defaultMultipartParallelism, partSize: defaultMultipartPartSize}),
+	)
+})
+
+var _ = Describe("Multipart HTTP download", func() {
+	var (
+		ts       *httptest.Server
+		data     []byte
+		requests int32
+	)
+
+	BeforeEach(func() {
+		data = randomData(1024 * 1024)
+		atomic.StoreInt32(&requests, 0)
+	})
+
+	AfterEach(func() {
+		os.Unsetenv(common.ImporterMultipartParallelism)
+		os.Unsetenv(common.ImporterMultipartPartSize)
+		ts.Close()
+	})
+
+	It("should download the ranges in parallel if the server supports ranges", func() {
+		ts = httptest.NewServer(newSlowServer(data, true, &requests))
+		os.Setenv(common.ImporterMultipartParallelism, "4")
+		os.Setenv(common.ImporterMultipartPartSize, strconv.Itoa(len(data)/8))
+		ep, err := ParseEndpoint(ts.URL)
+		Expect(err).ToNot(HaveOccurred())
+		reader, total, _, err := createHTTPReader(context.Background(), ep, "", "", "", nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		defer reader.Close()
+		Expect(total).To(Equal(uint64(len(data))))
+		result, err := io.ReadAll(reader)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(data))
+		Expect(reader.(*util.CountingReader).Current).To(Equal(uint64(len(data))))
+		// HEAD, the initial GET, and 8 parts
+		Expect(atomic.LoadInt32(&requests)).To(BeEquivalentTo(10))
+	})
+
+	It("should fall back to a single stream if the server does not support ranges", func() {
+		ts = httptest.NewServer(newSlowServer(data, false, &requests))
+		os.Setenv(common.ImporterMultipartParallelism, "4")
+		os.Setenv(common.ImporterMultipartPartSize, strconv.Itoa(len(data)/8))
+		ep, err := ParseEndpoint(ts.URL)
+		Expect(err).ToNot(HaveOccurred())
+		reader, _, _, err := createHTTPReader(context.Background(), ep, "", "", "", nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		defer reader.Close()
+		result, err := io.ReadAll(reader)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(data))
+		// HEAD and the initial GET
+		Expect(atomic.LoadInt32(&requests)).To(BeEquivalentTo(2))
+	})
+
+	It("should be faster than a single stream on a high latency server", func() {
+		ts = httptest.NewServer(newSlowServer(data, true, &requests))
+		single := timeDownload(ts.URL, 1, len(data)/8)
+		parallel := timeDownload(ts.URL, 4, len(data)/8)
+		Expect(parallel).To(BeNumerically("<", single))
+	})
+})
+
+func BenchmarkSingleStreamDownload(b *testing.B) {
+	benchmarkDownload(b, 1)
+}
+
+func BenchmarkMultipartDownload(b *testing.B) {
+	benchmarkDownload(b, 4)
+}
+
+func benchmarkDownload(b *testing.B, parallelism int) {
+	var requests int32
+	data := randomData(1024 * 1024)
+	ts := httptest.NewServer(newSlowServer(data, true, &requests))
+	defer ts.Close()
+	defer os.Unsetenv(common.ImporterMultipartParallelism)
+	defer os.Unsetenv(common.ImporterMultipartPartSize)
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		timeDownload(ts.URL, parallelism, len(data)/8)
+	}
+}
+
+func timeDownload(endpoint string, parallelism, partSize int) time.Duration {
+	os.Setenv(common.ImporterMultipartParallelism, strconv.Itoa(parallelism))
+	os.Setenv(common.ImporterMultipartPartSize, strconv.Itoa(partSize))
+	ep, err := ParseEndpoint(endpoint)
+	if err != nil {
+		panic(err)
+	}
+	start := time.Now()
+	reader, _, _, err := createHTTPReader(context.Background(), ep, "", "", "", nil, nil)
+	if err != nil {
+		panic(err)
+	}
+	defer reader.Close()
+	if _, err := io.Copy(io.Discard, reader); err != nil {
+		panic(err)
+	}
+	return time.Since(start)
+}
+
+// newSlowServer simulates a high latency link, every chunk written to the client is delayed.
+func newSlowServer(data []byte, acceptRanges bool, requests *int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		start, end := 0, len(data)-1
+		status := http.StatusOK
+		if acceptRanges {
+			w.Header().Set("Accept-Ranges", "bytes")
+			if rangeHeader := r.Header.Get("Range"); rangeHeader != "" {
+				if _, err := fmt.Sscanf(rangeHeader, "bytes=%d-%d", &start, &end); err != nil {
+					w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+					return
+				}
+				status = http.StatusPartialContent
+			}
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(end-start+1))
+		w.WriteHeader(status)
+		if r.Method == http.MethodHead {
+			return
+		}
+		for offset := start; offset <= end; offset += slowServerChunkSize {
+			chunkEnd := offset + slowServerChunkSize
+			if chunkEnd > end+1 {
+				chunkEnd = end + 1
+			}
+			time.Sleep(slowServerLatency)
+			if _, err := w.Write(data[offset:chunkEnd]); err != nil {
+				return
+			}
+			if f, ok := w.(http.Flusher); ok {
+				f.Flush()
+			}
+		}
+	})
+}
+
+func bytesRangeReader(data []byte, failures map[int64]error) rangeReaderFunc {
+	return func(ctx context.Context, start, end int64) (io.ReadCloser, error) {
+		if err, ok := failures[start]; ok {
+			return nil, err
+		}
+		if end >= int64(len(data)) {
+			end = int64(len(data)) - 1
+		}
+		return io.NopCloser(bytes.NewReader(data[start : end+1])), nil
+	}
+}
+
+func randomData(length int) []byte {
+	data := make([]byte, length)
+	if _, err := rand.Read(data); err != nil {
+		panic(err)
+	}
+	return data
+}
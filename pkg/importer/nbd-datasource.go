@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// nbdCACertFile is the file holding the CA certificate of an NBD over TLS server, as expected by qemu-img
+	nbdCACertFile = "ca-cert.pem"
+)
+
+// NBDDataSource is the data provider for disks exported over NBD. qemu-img reads the export directly, so the image
+// is converted from the NBD server into the target without being transferred first.
+type NBDDataSource struct {
+	// url of the NBD export
+	url *url.URL
+}
+
+// NewNBDDataSource creates a new instance of the NBDDataSource importing from the NBD export at endpoint. The TLS
+// credentials of an nbds endpoint are read from certDir.
+func NewNBDDataSource(endpoint, certDir string) (*NBDDataSource, error) {
+	ep, err := ParseEndpoint(endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse endpoint %q", redactSourceURL(endpoint))
+	}
+	if ep.Scheme != "nbd" && ep.Scheme != "nbds" {
+		return nil, errors.Errorf("invalid NBD url scheme %q, expected nbd or nbds", ep.Scheme)
+	}
+	if ep.Hostname() == "" {
+		return nil, errors.Errorf("NBD url %q has no host", redactSourceURL(endpoint))
+	}
+	if port := ep.Port(); port != "" {
+		if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			return nil, errors.Errorf("NBD url %q has an invalid port", redactSourceURL(endpoint))
+		}
+	}
+	if ep.RawQuery != "" {
+		return nil, errors.Errorf("NBD url %q has a query, only TCP exports are supported", redactSourceURL(endpoint))
+	}
+	if ep.Scheme == "nbds" {
+		if _, err := os.Stat(filepath.Join(certDir, nbdCACertFile)); err != nil {
+			return nil, errors.Wrapf(err, "NBD over TLS requires the %s of the server CA", nbdCACertFile)
+		}
+	}
+	return &NBDDataSource{
+		url: ep,
+	}, nil
+}
+
+// Info is called to get initial information about the data. The NBD export is converted directly.
+func (nd *NBDDataSource) Info() (ProcessingPhase, error) {
+	klog.V(1).Infof("Importing from NBD export %s", redactSourceURL(nd.url.String()))
+	return ProcessingPhaseConvert, nil
+}
+
+// Transfer is called to transfer the data from the source to a scratch location, which an NBD source never requires.
+func (nd *NBDDataSource) Transfer(path string) (ProcessingPhase, error) {
+	return ProcessingPhaseError, errors.New("transferring an NBD export is not supported, it is converted directly")
+}
+
+// TransferFile is called to transfer the data from the source to the passed in file, which an NBD source never requires.
+func (nd *NBDDataSource) TransferFile(fileName string) (ProcessingPhase, error) {
+	return ProcessingPhaseError, errors.New("transferring an NBD export is not supported, it is converted directly")
+}
+
+// GetURL returns the url of the NBD export, read by qemu-img.
+func (nd *NBDDataSource) GetURL() *url.URL {
+	return nd.url
+}
+
+// Close closes any readers or other open resources.
+func (nd *NBDDataSource) Close() error {
+	return nil
+}
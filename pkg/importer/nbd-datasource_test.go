@@ -0,0 +1,73 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NBD data source", func() {
+	var certDir string
+
+	BeforeEach(func() {
+		var err error
+		certDir, err = os.MkdirTemp("", "nbd-certs")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(certDir)
+	})
+
+	It("should convert directly from the NBD export", func() {
+		nd, err := NewNBDDataSource("nbd://nbd.example.com:10810/disk0", certDir)
+		Expect(err).NotTo(HaveOccurred())
+		phase, err := nd.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseConvert))
+		Expect(nd.GetURL().String()).To(Equal("nbd://nbd.example.com:10810/disk0"))
+		_, err = nd.TransferFile("dest")
+		Expect(err).To(HaveOccurred())
+		Expect(nd.Close()).To(Succeed())
+	})
+
+	It("should convert the NBD export into the target with qemu-img", func() {
+		nd, err := NewNBDDataSource("nbd://nbd.example.com/disk0", certDir)
+		Expect(err).NotTo(HaveOccurred())
+		dp := NewDataProcessor(nd, "dest", "dataDir", "scratchDataDir", "1G", 0.055, false)
+		ops := &targetRecordingQEMUOperations{QEMUOperations: NewFakeQEMUOperations(nil, nil, fakeInfoRet, nil, nil, nil)}
+		replaceQEMUOperations(ops, func() {
+			nextPhase, err := dp.convert(nd.GetURL())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nextPhase).To(Equal(ProcessingPhaseResize))
+		})
+		Expect(ops.calls).To(Equal([]string{"ConvertToRawStream dest"}))
+		Expect(dp.Passthrough()).To(BeFalse())
+	})
+
+	It("should require the CA certificate for NBD over TLS", func() {
+		_, err := NewNBDDataSource("nbds://nbd.example.com/disk0", certDir)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("NBD over TLS requires the ca-cert.pem of the server CA"))
+
+		Expect(os.WriteFile(filepath.Join(certDir, "ca-cert.pem"), []byte("ca"), 0644)).To(Succeed())
+		nd, err := NewNBDDataSource("nbds://nbd.example.com/disk0", certDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nd.GetURL().Scheme).To(Equal("nbds"))
+	})
+
+	table.DescribeTable("should reject an invalid NBD url", func(endpoint, errString string) {
+		_, err := NewNBDDataSource(endpoint, certDir)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(errString))
+	},
+		table.Entry("with another scheme", "http://nbd.example.com/disk0", "invalid NBD url scheme \"http\""),
+		table.Entry("with a unix socket", "nbd+unix:///disk0?socket=/tmp/nbd.sock", "invalid NBD url scheme \"nbd+unix\""),
+		table.Entry("without a host", "nbd:///disk0", "has no host"),
+		table.Entry("with an invalid port", "nbd://nbd.example.com:108090/disk0", "has an invalid port"),
+		table.Entry("with a query", "nbd://nbd.example.com/disk0?socket=/tmp/nbd.sock", "only TCP exports are supported"),
+	)
+})
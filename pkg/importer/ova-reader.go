@@ -0,0 +1,188 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+const (
+	// maxOvfDescriptorSize is the maximum size of the OVF descriptor read from an OVA
+	maxOvfDescriptorSize = 16 * 1024 * 1024
+	// ovfDiskResourceType is the CIM resource type of a disk drive in the OVF virtual hardware section
+	ovfDiskResourceType = 17
+)
+
+// ovfEnvelope is the part of the OVF descriptor needed to locate the disks of an OVA
+type ovfEnvelope struct {
+	Files []ovfFile `xml:"References>File"`
+	Disks []ovfDisk `xml:"DiskSection>Disk"`
+	// OVF 1.x describes the disk drives as items, OVF 2.x as storage items
+	Items        []ovfItem `xml:"VirtualSystem>VirtualHardwareSection>Item"`
+	StorageItems []ovfItem `xml:"VirtualSystem>VirtualHardwareSection>StorageItem"`
+}
+
+type ovfFile struct {
+	ID   string `xml:"id,attr"`
+	Href string `xml:"href,attr"`
+}
+
+type ovfDisk struct {
+	DiskID  string `xml:"diskId,attr"`
+	FileRef string `xml:"fileRef,attr"`
+}
+
+type ovfItem struct {
+	ResourceType int    `xml:"ResourceType"`
+	HostResource string `xml:"HostResource"`
+}
+
+// isOva returns true if the passed in tar header block is the one of an OVA: the OVF descriptor must be the first
+// file of the archive.
+func isOva(hdrBlock []byte) bool {
+	hdr, err := tar.NewReader(bytes.NewReader(hdrBlock)).Next()
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(path.Ext(hdr.Name), ".ovf")
+}
+
+// parseOvfDiskFiles returns the files of the disks described by the OVF descriptor, the primary disk first. The primary
+// disk is the first disk drive of the virtual hardware, the other disks follow in the disk section order.
+func parseOvfDiskFiles(descriptor []byte) ([]string, error) {
+	envelope := &ovfEnvelope{}
+	if err := xml.Unmarshal(descriptor, envelope); err != nil {
+		return nil, errors.Wrap(err, "could not parse the OVF descriptor")
+	}
+	files := map[string]string{}
+	for _, file := range envelope.Files {
+		files[file.ID] = file.Href
+	}
+	disks := map[string]string{}
+	for _, disk := range envelope.Disks {
+		disks[disk.DiskID] = files[disk.FileRef]
+	}
+
+	var diskFiles []string
+	appendDiskFile := func(file string) {
+		if file == "" {
+			return
+		}
+		for _, f := range diskFiles {
+			if f == file {
+				return
+			}
+		}
+		diskFiles = append(diskFiles, file)
+	}
+	for _, item := range append(envelope.Items, envelope.StorageItems...) {
+		if item.ResourceType != ovfDiskResourceType {
+			continue
+		}
+		// The host resource references a disk, ovf:/disk/<id>, or directly a file, ovf:/file/<id>
+		resource := strings.TrimPrefix(item.HostResource, "ovf:")
+		if id := strings.TrimPrefix(resource, "/disk/"); id != resource {
+			appendDiskFile(disks[id])
+		} else if id := strings.TrimPrefix(resource, "/file/"); id != resource {
+			appendDiskFile(files[id])
+		}
+	}
+	for _, disk := range envelope.Disks {
+		appendDiskFile(disks[disk.DiskID])
+	}
+	if len(diskFiles) == 0 {
+		return nil, errors.New("the OVF descriptor has no disk")
+	}
+	return diskFiles, nil
+}
+
+// ovaReader unpacks the OVA at the top of the reader stack. It parses the OVF descriptor and returns a reader of the
+// primary disk. Since the OVA is streamed, the other disks are ignored.
+func (fr *FormatReaders) ovaReader() (io.Reader, error) {
+	tarReader := tar.NewReader(fr.TopReader())
+	hdr, err := tarReader.Next()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read the OVA")
+	}
+	descriptor, err := io.ReadAll(io.LimitReader(tarReader, maxOvfDescriptorSize+1))
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read the OVF descriptor %q", hdr.Name)
+	}
+	if len(descriptor) > maxOvfDescriptorSize {
+		return nil, errors.Errorf("the OVF descriptor %q is larger than %d bytes", hdr.Name, maxOvfDescriptorSize)
+	}
+	diskFiles, err := parseOvfDiskFiles(descriptor)
+	if err != nil {
+		return nil, err
+	}
+	primary := diskFiles[0]
+	if len(diskFiles) > 1 {
+		klog.Warningf("The OVA has %d disks, only importing the primary disk %q, ignoring %s",
+			len(diskFiles), primary, strings.Join(diskFiles[1:], ", "))
+	}
+	for {
+		hdr, err = tarReader.Next()
+		if err == io.EOF {
+			return nil, errors.Errorf("the disk %q of the OVF descriptor is not in the OVA", primary)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read the OVA")
+		}
+		if path.Clean(hdr.Name) == path.Clean(primary) {
+			break
+		}
+	}
+	klog.V(2).Infof("ova: extracting %q\n", hdr.Name)
+	return tarReader, nil
+}
+
+// extractOvaDisk replaces the passed in OVA file with its primary disk, and returns the path of the disk. Any other
+// file is left untouched.
+func extractOvaDisk(fileName string) (string, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not open %q", fileName)
+	}
+	defer f.Close()
+	fr, err := NewFormatReaders(f, uint64(0))
+	if err != nil {
+		return "", err
+	}
+	defer fr.Close()
+	if !fr.OVA {
+		return fileName, nil
+	}
+	diskFileName := strings.TrimSuffix(fileName, path.Ext(fileName)) + ".disk"
+	if err := util.StreamDataToFile(fr.TopReader(), diskFileName); err != nil {
+		return "", err
+	}
+	if err := os.Remove(fileName); err != nil {
+		return "", errors.Wrapf(err, "could not remove %q", fileName)
+	}
+	return diskFileName, nil
+}
@@ -0,0 +1,170 @@
+package importer
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var (
+	multiDiskOvaFilePath = filepath.Join(imageDir, "multi-disk.ova")
+	fsOverheadFilePath   = filepath.Join(imageDir, "fs-overhead.qcow2")
+)
+
+const ovfDescriptorTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<Envelope xmlns="http://schemas.dmtf.org/ovf/envelope/%[1]d" xmlns:ovf="http://schemas.dmtf.org/ovf/envelope/%[1]d"
+  xmlns:rasd="http://schemas.dmtf.org/wbem/wscim/1/cim-schema/2/CIM_ResourceAllocationSettingData"
+  xmlns:sasd="http://schemas.dmtf.org/wbem/wscim/1/cim-schema/2/CIM_StorageAllocationSettingData">
+  <References>
+    <File ovf:id="file1" ovf:href="disk1.vmdk"/>
+    <File ovf:id="file2" ovf:href="disk2.vmdk"/>
+  </References>
+  <DiskSection>
+    <Disk ovf:diskId="vmdisk1" ovf:fileRef="file1"/>
+    <Disk ovf:diskId="vmdisk2" ovf:fileRef="file2"/>
+  </DiskSection>
+  <VirtualSystem ovf:id="vm">
+    <VirtualHardwareSection>%[2]s</VirtualHardwareSection>
+  </VirtualSystem>
+</Envelope>
+`
+
+var _ = Describe("OVA reader", func() {
+	var fr *FormatReaders
+
+	AfterEach(func() {
+		if fr != nil {
+			fr.Close()
+			fr = nil
+		}
+	})
+
+	It("should read the primary disk of the OVA", func() {
+		f, err := os.Open(multiDiskOvaFilePath)
+		Expect(err).ToNot(HaveOccurred())
+		defer f.Close()
+
+		fr, err = NewFormatReaders(f, uint64(0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(len(fr.readers)).To(Equal(4)) // [stream, multi-r, tar, multi-r]
+		Expect(fr.OVA).To(BeTrue())
+		Expect(fr.Archived).To(BeTrue())
+		Expect(fr.Convert).To(BeTrue())
+		Expect(fr.ImageFormat()).To(Equal("qcow2"))
+		Expect(fr.CheckExtensionHint(multiDiskOvaFilePath)).To(BeTrue())
+
+		content, err := io.ReadAll(fr.TopReader())
+		Expect(err).ToNot(HaveOccurred())
+		expected, err := os.ReadFile(fsOverheadFilePath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bytes.Equal(content, expected)).To(BeTrue())
+	})
+
+	It("should not unpack a tar archive that is not an OVA", func() {
+		f, err := os.Open(tinyCoreTarFilePath)
+		Expect(err).ToNot(HaveOccurred())
+		defer f.Close()
+
+		fr, err = NewFormatReaders(f, uint64(0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fr.OVA).To(BeFalse())
+		Expect(fr.Archived).To(BeFalse())
+	})
+
+	It("should fail if the primary disk is not in the OVA", func() {
+		buf := &bytes.Buffer{}
+		writer := tar.NewWriter(buf)
+		for _, file := range []struct {
+			name    string
+			content []byte
+		}{
+			{"vm.ovf", []byte(formatOvfDescriptor(1, hardwareItem(17, "ovf:/disk/vmdisk1")))},
+			{"disk2.vmdk", make([]byte, 4096)},
+		} {
+			Expect(writer.WriteHeader(&tar.Header{Name: file.name, Mode: 0644, Size: int64(len(file.content))})).To(Succeed())
+			_, err := writer.Write(file.content)
+			Expect(err).ToNot(HaveOccurred())
+		}
+		Expect(writer.Close()).To(Succeed())
+
+		var err error
+		fr, err = NewFormatReaders(io.NopCloser(buf), uint64(0))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`the disk "disk1.vmdk" of the OVF descriptor is not in the OVA`))
+	})
+
+	table.DescribeTable("should find the disks of the OVF descriptor", func(descriptor string, expected []string) {
+		diskFiles, err := parseOvfDiskFiles([]byte(descriptor))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(diskFiles).To(Equal(expected))
+	},
+		table.Entry("in the disk section order without hardware", formatOvfDescriptor(1, ""), []string{"disk1.vmdk", "disk2.vmdk"}),
+		table.Entry("with the first disk drive of the hardware first",
+			formatOvfDescriptor(1, hardwareItem(10, "ovf:/disk/vmdisk1")+hardwareItem(17, "ovf:/disk/vmdisk2")),
+			[]string{"disk2.vmdk", "disk1.vmdk"}),
+		table.Entry("with a disk drive referencing a file",
+			formatOvfDescriptor(1, hardwareItem(17, "ovf:/file/file2")), []string{"disk2.vmdk", "disk1.vmdk"}),
+		table.Entry("with OVF 2 storage items",
+			formatOvfDescriptor(2, `<StorageItem><sasd:HostResource>ovf:/disk/vmdisk2</sasd:HostResource><sasd:ResourceType>17</sasd:ResourceType></StorageItem>`),
+			[]string{"disk2.vmdk", "disk1.vmdk"}),
+	)
+
+	It("should fail if the OVF descriptor has no disk", func() {
+		_, err := parseOvfDiskFiles([]byte(`<Envelope><References/><VirtualSystem/></Envelope>`))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("the OVF descriptor has no disk"))
+	})
+
+	Context("extracting the disk", func() {
+		var tmpDir string
+
+		BeforeEach(func() {
+			var err error
+			tmpDir, err = os.MkdirTemp("", "ova-reader")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(tmpDir)
+		})
+
+		It("should replace the OVA with its primary disk", func() {
+			ova := filepath.Join(tmpDir, "multi-disk.ova")
+			content, err := os.ReadFile(multiDiskOvaFilePath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(os.WriteFile(ova, content, 0644)).To(Succeed())
+
+			disk, err := extractOvaDisk(ova)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(disk).To(Equal(filepath.Join(tmpDir, "multi-disk.disk")))
+			Expect(ova).ToNot(BeAnExistingFile())
+			content, err = os.ReadFile(disk)
+			Expect(err).ToNot(HaveOccurred())
+			expected, err := os.ReadFile(fsOverheadFilePath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bytes.Equal(content, expected)).To(BeTrue())
+		})
+
+		It("should leave a disk image untouched", func() {
+			disk, err := extractOvaDisk(cirrosFilePath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(disk).To(Equal(cirrosFilePath))
+			Expect(cirrosFilePath).To(BeAnExistingFile())
+		})
+	})
+})
+
+func formatOvfDescriptor(version int, hardware string) string {
+	return fmt.Sprintf(ovfDescriptorTemplate, version, hardware)
+}
+
+func hardwareItem(resourceType int, hostResource string) string {
+	return fmt.Sprintf(`<Item><rasd:HostResource>%s</rasd:HostResource><rasd:ResourceType>%d</rasd:ResourceType></Item>`, hostResource, resourceType)
+}
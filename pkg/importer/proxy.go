@@ -0,0 +1,189 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+// importProxy is the proxy configuration of the importer, as injected by the import controller from the CDIConfig.
+type importProxy struct {
+	httpProxy  *url.URL
+	httpsProxy *url.URL
+	noProxy    []string
+}
+
+// newImportProxyFromEnv returns the proxy configuration from the environment. The lower case variables set by the
+// import controller take precedence over any upper case variables inherited from the image or the node.
+func newImportProxyFromEnv() (*importProxy, error) {
+	return newImportProxy(
+		getEnvAny(common.ImportProxyHTTP, strings.ToUpper(common.ImportProxyHTTP)),
+		getEnvAny(common.ImportProxyHTTPS, strings.ToUpper(common.ImportProxyHTTPS)),
+		getEnvAny(common.ImportProxyNoProxy, strings.ToUpper(common.ImportProxyNoProxy)),
+		os.Getenv(common.ImportProxyUsername),
+		os.Getenv(common.ImportProxyPassword),
+	)
+}
+
+func newImportProxy(httpProxy, httpsProxy, noProxy, username, password string) (*importProxy, error) {
+	var err error
+	p := &importProxy{}
+	if p.httpProxy, err = parseProxyURL(httpProxy, username, password); err != nil {
+		return nil, err
+	}
+	if p.httpsProxy, err = parseProxyURL(httpsProxy, username, password); err != nil {
+		return nil, err
+	}
+	for _, entry := range strings.Split(noProxy, ",") {
+		if entry = strings.ToLower(strings.TrimSpace(entry)); entry != "" {
+			p.noProxy = append(p.noProxy, entry)
+		}
+	}
+	return p, nil
+}
+
+func getEnvAny(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// parseProxyURL parses the proxy URL, defaulting to the http scheme. The username and password, if set, replace the
+// credentials in the URL.
+func parseProxyURL(proxy, username, password string) (*url.URL, error) {
+	if proxy == "" {
+		return nil, nil
+	}
+	if !strings.Contains(proxy, "://") {
+		proxy = "http://" + proxy
+	}
+	proxyURL, err := url.Parse(proxy)
+	if err != nil || proxyURL.Host == "" {
+		return nil, errors.Errorf("invalid proxy address %q", proxy)
+	}
+	if username != "" {
+		proxyURL.User = url.UserPassword(username, password)
+	}
+	return proxyURL, nil
+}
+
+// proxyFunc returns the proxy to use for the request, or nil if the request should not be proxied.
+func (p *importProxy) proxyFunc(req *http.Request) (*url.URL, error) {
+	var proxyURL *url.URL
+	switch req.URL.Scheme {
+	case "http":
+		proxyURL = p.httpProxy
+	case "https":
+		proxyURL = p.httpsProxy
+	}
+	if proxyURL == nil || !p.useProxy(req.URL.Host) {
+		return nil, nil
+	}
+	return proxyURL, nil
+}
+
+// useProxy returns false if the host matches a no proxy entry or is the local host. A no proxy entry is either "*",
+// an IP address, a CIDR, or a domain name with an optional port. Domain names also match their subdomains, while
+// entries with a leading "." only match the subdomains.
+func (p *importProxy) useProxy(hostport string) bool {
+	host, port, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = hostport
+	}
+	host = strings.ToLower(host)
+	if host == "localhost" {
+		return false
+	}
+	ip := net.ParseIP(host)
+	if ip != nil && ip.IsLoopback() {
+		return false
+	}
+	for _, entry := range p.noProxy {
+		if entry == "*" {
+			return false
+		}
+		if _, cidr, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && cidr.Contains(ip) {
+				return false
+			}
+			continue
+		}
+		entryHost, entryPort, err := net.SplitHostPort(entry)
+		if err != nil {
+			entryHost, entryPort = entry, ""
+		}
+		if entryPort != "" && entryPort != port {
+			continue
+		}
+		if entryIP := net.ParseIP(entryHost); entryIP != nil {
+			if ip != nil && entryIP.Equal(ip) {
+				return false
+			}
+			continue
+		}
+		entryHost = strings.TrimPrefix(entryHost, "*")
+		if strings.HasPrefix(entryHost, ".") {
+			if strings.HasSuffix(host, entryHost) {
+				return false
+			}
+		} else if host == entryHost || strings.HasSuffix(host, "."+entryHost) {
+			return false
+		}
+	}
+	return true
+}
+
+// ApplyProxyEnvironment exports the importer proxy configuration, including the credentials, to both the lower and upper
+// case proxy variables, so the clients relying on the environment, like the registry client and nbdkit, use the same
+// proxy. It must be called before any such client is used.
+func ApplyProxyEnvironment() error {
+	p, err := newImportProxyFromEnv()
+	if err != nil {
+		return err
+	}
+	set := func(name, value string) error {
+		if value == "" {
+			return nil
+		}
+		if err := os.Setenv(name, value); err != nil {
+			return err
+		}
+		return os.Setenv(strings.ToUpper(name), value)
+	}
+	if p.httpProxy != nil {
+		if err := set(common.ImportProxyHTTP, p.httpProxy.String()); err != nil {
+			return err
+		}
+	}
+	if p.httpsProxy != nil {
+		if err := set(common.ImportProxyHTTPS, p.httpsProxy.String()); err != nil {
+			return err
+		}
+	}
+	return set(common.ImportProxyNoProxy, strings.Join(p.noProxy, ","))
+}
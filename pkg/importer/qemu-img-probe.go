@@ -0,0 +1,117 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"net/url"
+	"os"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/image"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+// sourceFormat returns the format of the source image detected from its headers, empty if the data source does not
+// detect it
+func (dp *DataProcessor) sourceFormat() string {
+	fds, ok := dp.source.(formatDetectingDataSource)
+	if !ok || fds.formatReaders() == nil {
+		return ""
+	}
+	return fds.formatReaders().ImageFormat()
+}
+
+// probeQemuImg reads the image information with qemu-img info before any conversion runs, nil when it can't be read.
+// An image format qemu-img has no driver for fails as unsupported, the other failures are left to the validation.
+// When qemu-img is not installed, true is returned if a local raw image can be copied to a raw target without it.
+func (dp *DataProcessor) probeQemuImg(url *url.URL) (*image.ImgInfo, bool, error) {
+	info, err := qemuOperations.Info(url)
+	if errors.Is(err, image.ErrQemuImgNotFound) {
+		isLocal := url != nil && (url.Scheme == "" || url.Scheme == "file")
+		if dp.sourceFormat() == "raw" && dp.targetFormat == "" && isLocal {
+			klog.Warningf("qemu-img is unavailable, copying the raw image without it: %v", err)
+			return nil, true, nil
+		}
+		return nil, false, errors.Errorf("%s, cannot convert the image: %v", common.QemuImgUnavailableMessage, err)
+	}
+	if err != nil {
+		return nil, false, unsupportedFormatError(err)
+	}
+	return info, false, nil
+}
+
+// unsupportedFormatError returns the error of a qemu-img failure on an image format it has no driver for, nil for
+// other errors
+func unsupportedFormatError(err error) error {
+	if format := image.UnknownDriver(err); format != "" {
+		return errors.Errorf("%s: the installed qemu-img has no driver for the %s format", common.UnsupportedFormatMessage, format)
+	}
+	return nil
+}
+
+// wrapConvertError wraps the error of a qemu-img conversion, reporting an image format qemu-img has no driver for
+func wrapConvertError(err error, message string) error {
+	if unsupported := unsupportedFormatError(err); unsupported != nil {
+		return unsupported
+	}
+	return errors.Wrap(err, message)
+}
+
+// copyRawWithoutQemuImg copies the local raw image to the raw target, and checks it fits the available space from its
+// file size, as qemu-img cannot read its virtual size
+func (dp *DataProcessor) copyRawWithoutQemuImg(url *url.URL, availableSpace int64) error {
+	info, err := os.Stat(url.Path)
+	if err != nil {
+		return errors.Wrap(err, "could not stat the raw image")
+	}
+	if availableSpace < info.Size() {
+		return ValidationSizeError{err: errors.Errorf("Virtual image size %d is larger than the reported available storage %d. A larger PVC is required.", info.Size(), availableSpace)}
+	}
+	dp.imageInfo = util.ImageInfo{Format: "raw", VirtualSize: info.Size()}
+	if err := CleanAll(dp.dataFile); err != nil {
+		return err
+	}
+	if dp.preallocation {
+		klog.Warningln("Not preallocating the image, preallocation needs qemu-img")
+	}
+	if err := copySparse(url.Path, dp.dataFile, false); err != nil {
+		return errors.Wrap(err, "Copy of raw image failed")
+	}
+	dp.passthrough = true
+	dp.withoutQemuImg = true
+	return nil
+}
+
+// resizeRawWithoutQemuImg grows the raw image file to the requested size, bounded by the target space
+func resizeRawWithoutQemuImg(dataFile, imageSize string, totalTargetSpace int64) error {
+	info, err := os.Stat(dataFile)
+	if err != nil {
+		return errors.Wrap(err, "could not stat the raw image")
+	}
+	requestedSize := resource.MustParse(imageSize)
+	size := util.MinQuantity(resource.NewScaledQuantity(totalTargetSpace, 0), &requestedSize)
+	if info.Size() >= size.Value() {
+		klog.V(1).Infof("No need to resize image. Requested size: %s, Image size: %d.\n", imageSize, info.Size())
+		return nil
+	}
+	klog.V(1).Infof("Expanding image size to: %s\n", size.String())
+	return os.Truncate(dataFile, size.Value())
+}
@@ -0,0 +1,150 @@
+package importer
+
+import (
+	"bytes"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/image"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+var _ = Describe("qemu-img probe", func() {
+	var (
+		mdp *formatDetectingMockDataProvider
+		ops *infoErrorQEMUOperations
+	)
+
+	unknownDriver := func(format string) error {
+		return errors.Errorf("qemu-img: Could not open 'nbd+unix:///?socket=/tmp/nbdkit.sock': Unknown driver '%s', exit status 1", format)
+	}
+
+	BeforeEach(func() {
+		url, err := url.Parse("http://fakeurl-notreal.fake")
+		Expect(err).ToNot(HaveOccurred())
+		mdp = &formatDetectingMockDataProvider{MockDataProvider: MockDataProvider{url: url}}
+		ops = &infoErrorQEMUOperations{
+			QEMUOperations: &targetRecordingQEMUOperations{QEMUOperations: NewFakeQEMUOperations(nil, nil, fakeInfoRet, nil, nil, nil)},
+		}
+	})
+
+	It("Should convert an image qemu-img reads, reusing its information", func() {
+		mdp.readers = &FormatReaders{formats: []string{"vmdk"}}
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.055, false)
+		replaceQEMUOperations(ops, func() {
+			nextPhase, err := dp.convert(mdp.GetURL())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nextPhase).To(Equal(ProcessingPhaseResize))
+		})
+		Expect(ops.QEMUOperations.(*targetRecordingQEMUOperations).calls).To(Equal([]string{"ConvertToRawStream dest"}))
+		Expect(dp.ImageInfo()).To(Equal(util.ImageInfo{Format: fakeInfoRet.imgInfo.Format, VirtualSize: fakeInfoRet.imgInfo.VirtualSize}))
+	})
+
+	It("Should not convert an image of a format qemu-img has no driver for", func() {
+		mdp.readers = &FormatReaders{formats: []string{"vhdx"}}
+		ops.err = unknownDriver("vhdx")
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.055, false)
+		replaceQEMUOperations(ops, func() {
+			nextPhase, err := dp.convert(mdp.GetURL())
+			Expect(err).To(HaveOccurred())
+			Expect(nextPhase).To(Equal(ProcessingPhaseError))
+			Expect(err.Error()).To(ContainSubstring(common.UnsupportedFormatMessage + ": the installed qemu-img has no driver for the vhdx format"))
+		})
+		Expect(ops.QEMUOperations.(*targetRecordingQEMUOperations).calls).To(BeEmpty())
+	})
+
+	It("Should leave the other qemu-img info failures to the validation", func() {
+		mdp.readers = &FormatReaders{formats: []string{"qcow2"}}
+		ops.err = errors.New("qemu-img: Could not open 'disk.img': Image is not in qcow2 format")
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.055, false)
+		replaceQEMUOperations(ops, func() {
+			_, err := dp.convert(mdp.GetURL())
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	It("Should report a format qemu-img has no driver for while converting", func() {
+		mdp.readers = &FormatReaders{formats: []string{"qcow2"}}
+		failingOps := NewFakeQEMUOperations(unknownDriver("raw"), nil, fakeInfoRet, nil, nil, nil)
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.055, false)
+		replaceQEMUOperations(failingOps, func() {
+			_, err := dp.convert(mdp.GetURL())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(common.UnsupportedFormatMessage + ": the installed qemu-img has no driver for the raw format"))
+		})
+	})
+
+	It("Should not convert an image when qemu-img is unavailable", func() {
+		mdp.readers = &FormatReaders{formats: []string{"qcow2"}}
+		ops.err = errors.Wrap(image.ErrQemuImgNotFound, `Couldn't start qemu-img: exec: "qemu-img": executable file not found in $PATH`)
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.055, false)
+		replaceQEMUOperations(ops, func() {
+			nextPhase, err := dp.convert(mdp.GetURL())
+			Expect(err).To(HaveOccurred())
+			Expect(nextPhase).To(Equal(ProcessingPhaseError))
+			Expect(err.Error()).To(ContainSubstring(common.QemuImgUnavailableMessage))
+			Expect(err.Error()).To(ContainSubstring("executable file not found"))
+		})
+		Expect(ops.QEMUOperations.(*targetRecordingQEMUOperations).calls).To(BeEmpty())
+	})
+
+	It("Should copy and resize a local raw image when qemu-img is unavailable", func() {
+		tmpDir, err := os.MkdirTemp("", "qemu-img-probe")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(tmpDir)
+		content := bytes.Repeat([]byte("0123456789abcdef"), 64*1024)
+		source := filepath.Join(tmpDir, "source.img")
+		Expect(os.WriteFile(source, content, 0644)).To(Succeed())
+		dest := filepath.Join(tmpDir, "disk.img")
+
+		mdp.url = &url.URL{Path: source}
+		mdp.readers = &FormatReaders{}
+		failingOps := &infoErrorQEMUOperations{QEMUOperations: NewQEMUAllErrors(), err: errors.Wrap(image.ErrQemuImgNotFound, "qemu-img: not found")}
+		dp := NewDataProcessor(mdp, dest, tmpDir, "scratchDataDir", "2Mi", 0, true)
+		replaceQEMUOperations(failingOps, func() {
+			nextPhase, err := dp.convert(mdp.GetURL())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nextPhase).To(Equal(ProcessingPhaseResize))
+			nextPhase, err = dp.resize()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nextPhase).To(Equal(ProcessingPhaseComplete))
+		})
+		Expect(dp.Passthrough()).To(BeTrue())
+		Expect(dp.PreallocationApplied()).To(BeFalse())
+		Expect(dp.ImageInfo()).To(Equal(util.ImageInfo{Format: "raw", VirtualSize: int64(len(content))}))
+		written, err := os.ReadFile(dest)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(written).To(HaveLen(2 * 1024 * 1024))
+		Expect(written[:len(content)]).To(Equal(content))
+		Expect(written[len(content):]).To(Equal(make([]byte, 2*1024*1024-len(content))))
+	})
+})
+
+// formatDetectingMockDataProvider is a MockDataProvider detecting the format of its image
+type formatDetectingMockDataProvider struct {
+	MockDataProvider
+	readers *FormatReaders
+}
+
+func (m *formatDetectingMockDataProvider) formatReaders() *FormatReaders {
+	return m.readers
+}
+
+// infoErrorQEMUOperations fails qemu-img info with the passed in error, if any
+type infoErrorQEMUOperations struct {
+	image.QEMUOperations
+	err error
+}
+
+func (o *infoErrorQEMUOperations) Info(url *url.URL) (*image.ImgInfo, error) {
+	if o.err != nil {
+		return nil, o.err
+	}
+	return o.QEMUOperations.Info(url)
+}
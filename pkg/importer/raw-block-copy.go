@@ -0,0 +1,323 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/url"
+	"os"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/image"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+// rawBlockChunkSize is the size of the chunks written when streaming a raw image to a block device
+const rawBlockChunkSize = 1024 * 1024
+
+// canPassthrough returns true if the image at url is a local file already in the format of the target, without
+// backing file. Converting it with qemu-img would be a no-op, so it can be copied as is instead.
+func (dp *DataProcessor) canPassthrough(url *url.URL, info *image.ImgInfo) bool {
+	if url == nil || (url.Scheme != "" && url.Scheme != "file") || info == nil || info.BackingFile != "" {
+		return false
+	}
+	switch dp.targetFormat {
+	case "":
+		return info.Format == "raw"
+	case common.ImportTargetFormatQcow2:
+		// Compressing, compacting or resizing the clusters needs qemu-img to rewrite them
+		return info.Format == "qcow2" && dp.targetCompression == "" && !dp.compactTarget &&
+			(dp.qcow2ClusterSize == 0 || dp.qcow2ClusterSize == info.ClusterSize)
+	}
+	return false
+}
+
+// dataRange is a range of an image holding data, from start to end
+type dataRange struct {
+	start, end int64
+}
+
+// copySparse copies the image src as is to dest, only reading the data ranges of src by using SEEK_DATA/SEEK_HOLE.
+// The holes are zeroed on dest, by punching holes or by writing zeroes when preallocation is requested. The copy is
+// checked by comparing the size and the SHA-256 of the data ranges of dest and src.
+func copySparse(src, dest string, preallocate bool) error {
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return errors.Wrapf(err, "could not open source image %s", src)
+	}
+	defer srcFile.Close()
+	fi, err := srcFile.Stat()
+	if err != nil {
+		return errors.Wrapf(err, "could not stat source image %s", src)
+	}
+	size := fi.Size()
+
+	destFile, err := util.OpenFileOrBlockDevice(dest)
+	if err != nil {
+		return err
+	}
+	defer destFile.Close()
+
+	zeroer := newRangeZeroer(destFile, preallocate)
+	hash := sha256.New()
+	var ranges []dataRange
+
+	klog.V(1).Infof("Copying %d bytes image %s to %s", size, src, dest)
+	for offset := int64(0); offset < size; {
+		dataStart, dataEnd, err := nextDataRange(srcFile, offset, size)
+		if err != nil {
+			return err
+		}
+		if err := zeroer.zero(offset, dataStart-offset); err != nil {
+			return err
+		}
+		if dataStart >= size {
+			break
+		}
+		if _, err := srcFile.Seek(dataStart, io.SeekStart); err != nil {
+			return err
+		}
+		if _, err := destFile.Seek(dataStart, io.SeekStart); err != nil {
+			return err
+		}
+		if _, err := io.CopyN(io.MultiWriter(destFile, hash), srcFile, dataEnd-dataStart); err != nil {
+			return errors.Wrapf(err, "unable to copy range %d-%d", dataStart, dataEnd)
+		}
+		ranges = append(ranges, dataRange{start: dataStart, end: dataEnd})
+		offset = dataEnd
+	}
+	// Punching a hole doesn't extend a regular file, make sure a trailing hole is kept
+	if destInfo, err := destFile.Stat(); err == nil && destInfo.Mode().IsRegular() && destInfo.Size() < size {
+		if err := destFile.Truncate(size); err != nil {
+			return err
+		}
+	}
+	if err := destFile.Sync(); err != nil {
+		return err
+	}
+	return verifyCopy(dest, size, ranges, hex.EncodeToString(hash.Sum(nil)))
+}
+
+// verifyCopy checks dest has the size of the copied image, or is a larger block device, and that its data ranges
+// have the SHA-256 of the data ranges of the image
+func verifyCopy(dest string, size int64, ranges []dataRange, digest string) error {
+	destFile, err := os.Open(dest)
+	if err != nil {
+		return errors.Wrapf(err, "could not open %s to check the copy", dest)
+	}
+	defer destFile.Close()
+	destInfo, err := destFile.Stat()
+	if err != nil {
+		return errors.Wrapf(err, "could not stat %s to check the copy", dest)
+	}
+	destSize, err := destFile.Seek(0, io.SeekEnd)
+	if err != nil {
+		return errors.Wrapf(err, "could not read the size of %s", dest)
+	}
+	if destSize < size || (destInfo.Mode().IsRegular() && destSize != size) {
+		return errors.Errorf("the copy is %d bytes, the image %d bytes", destSize, size)
+	}
+	hash := sha256.New()
+	for _, r := range ranges {
+		if _, err := io.Copy(hash, io.NewSectionReader(destFile, r.start, r.end-r.start)); err != nil {
+			return errors.Wrapf(err, "could not read range %d-%d of the copy", r.start, r.end)
+		}
+	}
+	if hex.EncodeToString(hash.Sum(nil)) != digest {
+		return errors.New("the copy does not match the image")
+	}
+	return nil
+}
+
+// streamRawToBlock writes the raw image read from r to the block device dest, in chunks. The chunks only containing
+// zeroes are not written but zeroed on dest, by punching holes or by writing zeroes when preallocation is requested.
+func streamRawToBlock(r io.Reader, dest string, preallocate bool) error {
+	return streamRawAt(r, dest, 0, preallocate, nil)
+}
+
+// streamRawAt writes the raw image read from r to dest like streamRawToBlock, r starting at offset start of the
+// image. When set, written is called after each chunk with the offset of its end.
+func streamRawAt(r io.Reader, dest string, start int64, preallocate bool, written func(destFile *os.File, offset int64, chunk []byte) error) error {
+	var destFile *os.File
+	var err error
+	if start > 0 {
+		// Resuming, dest already holds the image up to start
+		destFile, err = os.OpenFile(dest, os.O_WRONLY, 0)
+		if err != nil {
+			return errors.Wrapf(err, "could not open file %q", dest)
+		}
+	} else {
+		destFile, err = util.OpenFileOrBlockDevice(dest)
+		if err != nil {
+			return err
+		}
+	}
+	defer destFile.Close()
+
+	klog.V(1).Infof("Streaming raw image to %s from offset %d", dest, start)
+	offset, err := writeRawAt(r, destFile, start, preallocate, written)
+	if err != nil {
+		return err
+	}
+	// Punching a hole doesn't extend a regular file, make sure a trailing hole is kept
+	if err := extendRegularFile(destFile, offset); err != nil {
+		return err
+	}
+	return destFile.Sync()
+}
+
+// StreamRawRange writes a range of the raw image read from r to dest, r holding the bytes of the range. The ranges of
+// an image can be written concurrently, dest is neither created nor truncated, only the last range extends a regular
+// file to the size of the image.
+func StreamRawRange(r io.Reader, dest string, cloneRange util.CloneRange, preallocate bool) error {
+	destFile, err := os.OpenFile(dest, os.O_WRONLY, 0)
+	if err != nil {
+		return errors.Wrapf(err, "could not open file %q", dest)
+	}
+	defer destFile.Close()
+
+	klog.V(1).Infof("Streaming raw image range %s to %s", cloneRange, dest)
+	end, err := writeRawAt(io.LimitReader(r, cloneRange.Length()), destFile, cloneRange.Start, preallocate, nil)
+	if err != nil {
+		return err
+	}
+	if end != cloneRange.End {
+		return errors.Errorf("the stream of range %s ended at offset %d", cloneRange, end)
+	}
+	if n, _ := r.Read(make([]byte, 1)); n > 0 {
+		return errors.Errorf("the stream of range %s is longer than the range", cloneRange)
+	}
+	if cloneRange.End == cloneRange.Size {
+		if err := extendRegularFile(destFile, cloneRange.End); err != nil {
+			return err
+		}
+	}
+	return destFile.Sync()
+}
+
+// writeRawAt writes the raw image read from r to destFile from offset start, and returns the offset it ended at. When
+// set, written is called after each chunk with the offset of its end.
+func writeRawAt(r io.Reader, destFile *os.File, start int64, preallocate bool, written func(destFile *os.File, offset int64, chunk []byte) error) (int64, error) {
+	zeroer := newRangeZeroer(destFile, preallocate)
+	buf := make([]byte, rawBlockChunkSize)
+	offset := start
+	for {
+		n, readErr := io.ReadFull(r, buf)
+		if n > 0 {
+			var err error
+			if isZero(buf[:n]) {
+				err = zeroer.zero(offset, int64(n))
+			} else {
+				_, err = destFile.WriteAt(buf[:n], offset)
+			}
+			if err != nil {
+				return offset, errors.Wrapf(err, "unable to write range %d-%d", offset, offset+int64(n))
+			}
+			offset += int64(n)
+			if written != nil {
+				if err := written(destFile, offset, buf[:n]); err != nil {
+					return offset, err
+				}
+			}
+		}
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			return offset, nil
+		} else if readErr != nil {
+			return offset, errors.Wrap(readErr, "unable to read the raw image")
+		}
+	}
+}
+
+// extendRegularFile extends destFile to size if it is a smaller regular file
+func extendRegularFile(destFile *os.File, size int64) error {
+	if destInfo, err := destFile.Stat(); err == nil && destInfo.Mode().IsRegular() && destInfo.Size() < size {
+		return destFile.Truncate(size)
+	}
+	return nil
+}
+
+// rangeZeroer zeroes ranges of a file or block device. It punches holes, unless preallocation is requested, and falls
+// back to writing zeroes if punching holes fails.
+type rangeZeroer struct {
+	dest      *os.File
+	zeroRange func(*os.File, int64, int64) error
+}
+
+func newRangeZeroer(dest *os.File, preallocate bool) *rangeZeroer {
+	zeroRange := util.PunchHole
+	if preallocate {
+		zeroRange = util.AppendZeroWithWrite
+	}
+	return &rangeZeroer{dest: dest, zeroRange: zeroRange}
+}
+
+func (z *rangeZeroer) zero(start, length int64) error {
+	if length <= 0 {
+		return nil
+	}
+	if _, err := z.dest.Seek(start, io.SeekStart); err != nil {
+		return err
+	}
+	if err := z.zeroRange(z.dest, start, length); err != nil {
+		klog.Infof("Initial zero method failed, trying AppendZeroWithWrite instead. Error was: %v", err)
+		z.zeroRange = util.AppendZeroWithWrite
+		if _, err := z.dest.Seek(start, io.SeekStart); err != nil {
+			return err
+		}
+		if err := z.zeroRange(z.dest, start, length); err != nil {
+			return errors.Wrap(err, "failed to zero range on destination")
+		}
+	}
+	return nil
+}
+
+func isZero(buf []byte) bool {
+	for _, b := range buf {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// nextDataRange returns the start and end of the next data range of f at or after offset. If there is no more data
+// both are size. If f doesn't support SEEK_DATA, the rest of the file is considered data.
+func nextDataRange(f *os.File, offset, size int64) (int64, int64, error) {
+	dataStart, err := unix.Seek(int(f.Fd()), offset, unix.SEEK_DATA)
+	if err == unix.ENXIO {
+		return size, size, nil
+	} else if err == unix.EINVAL || err == unix.EOPNOTSUPP {
+		return offset, size, nil
+	} else if err != nil {
+		return 0, 0, errors.Wrapf(err, "unable to find data at offset %d", offset)
+	}
+	dataEnd, err := unix.Seek(int(f.Fd()), dataStart, unix.SEEK_HOLE)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "unable to find hole at offset %d", dataStart)
+	}
+	if dataEnd > size {
+		dataEnd = size
+	}
+	return dataStart, dataEnd, nil
+}
@@ -0,0 +1,246 @@
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"kubevirt.io/containerized-data-importer/pkg/image"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+const (
+	sparseImageSize   = int64(64 * 1024 * 1024)
+	sparseImageExtent = 1024 * 1024
+)
+
+var _ = Describe("Passthrough copy", func() {
+	var tmpDir string
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "raw-block-copy")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	table.DescribeTable("should copy a sparse raw image", func(preallocate bool) {
+		src := createSparseImage(filepath.Join(tmpDir, "source.raw"), sparseImageSize)
+		dest := filepath.Join(tmpDir, "dest")
+		Expect(copySparse(src, dest, preallocate)).To(Succeed())
+		expected, err := os.ReadFile(src)
+		Expect(err).ToNot(HaveOccurred())
+		result, err := os.ReadFile(dest)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bytes.Equal(expected, result)).To(BeTrue())
+	},
+		table.Entry("without preallocation", false),
+		table.Entry("with preallocation", true),
+	)
+
+	It("should copy a raw image without holes", func() {
+		src := createFilledFile(filepath.Join(tmpDir, "source.raw"), sparseImageExtent, 0x55)
+		dest := filepath.Join(tmpDir, "dest")
+		Expect(copySparse(src, dest, false)).To(Succeed())
+		result, err := os.ReadFile(dest)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bytes.Equal(result, bytes.Repeat([]byte{0x55}, sparseImageExtent))).To(BeTrue())
+	})
+
+	It("should fail if the source does not exist", func() {
+		Expect(copySparse(filepath.Join(tmpDir, "missing"), filepath.Join(tmpDir, "dest"), false)).ToNot(Succeed())
+	})
+
+	It("should fail the check of a copy not matching the image", func() {
+		src := createSparseImage(filepath.Join(tmpDir, "source.raw"), sparseImageSize)
+		dest := filepath.Join(tmpDir, "dest")
+		Expect(copySparse(src, dest, false)).To(Succeed())
+		ranges := []dataRange{{start: 0, end: sparseImageExtent}}
+		err := verifyCopy(dest, sparseImageSize, ranges, strings.Repeat("0", 64))
+		Expect(err).To(MatchError("the copy does not match the image"))
+		err = verifyCopy(dest, sparseImageSize+1, ranges, "")
+		Expect(err).To(MatchError(fmt.Sprintf("the copy is %d bytes, the image %d bytes", sparseImageSize, sparseImageSize+1)))
+	})
+
+	It("should produce the same content as qemu-img convert", func() {
+		if _, err := exec.LookPath("qemu-img"); err != nil {
+			Skip("qemu-img is not available")
+		}
+		src := createSparseImage(filepath.Join(tmpDir, "source.raw"), sparseImageSize)
+		srcURL, err := url.Parse(src)
+		Expect(err).ToNot(HaveOccurred())
+		converted := filepath.Join(tmpDir, "converted")
+		Expect(image.NewQEMUOperations().ConvertToRawStream(srcURL, converted, false)).To(Succeed())
+		copied := filepath.Join(tmpDir, "copied")
+		Expect(copySparse(src, copied, false)).To(Succeed())
+		expected, err := os.ReadFile(converted)
+		Expect(err).ToNot(HaveOccurred())
+		result, err := os.ReadFile(copied)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bytes.Equal(expected, result)).To(BeTrue())
+	})
+
+	table.DescribeTable("convert should", func(format, targetFormat, targetCompression string, blockSize int64, expectedPhase ProcessingPhase) {
+		src := createSparseImage(filepath.Join(tmpDir, "source.raw"), sparseImageSize)
+		dest := createFilledFile(filepath.Join(tmpDir, "dest"), sparseImageSize, 0xff)
+		srcURL, err := url.Parse(src)
+		Expect(err).ToNot(HaveOccurred())
+		dp := NewDataProcessor(&MockDataProvider{url: srcURL}, dest, "dataDir", "scratchDataDir", "", 0.055, false)
+		dp.SetTargetFormat(targetFormat, targetCompression)
+		info := &image.ImgInfo{Format: format, VirtualSize: sparseImageSize}
+		// The conversion fails, so only the passthrough copy can succeed
+		qemuOperations := NewFakeQEMUOperations(errors.New("qemu-img convert should not be called"), nil, fakeInfoOpRetVal{info, nil}, nil, nil, nil)
+		replaceQEMUOperations(qemuOperations, func() {
+			replaceAvailableSpaceBlockFunc(func(string) (int64, error) {
+				return blockSize, nil
+			}, func() {
+				nextPhase, err := dp.convert(srcURL)
+				Expect(nextPhase).To(Equal(expectedPhase))
+				if expectedPhase == ProcessingPhaseError {
+					Expect(err).To(HaveOccurred())
+					Expect(dp.Passthrough()).To(BeFalse())
+					return
+				}
+				Expect(err).ToNot(HaveOccurred())
+				Expect(dp.Passthrough()).To(BeTrue())
+				expected, err := os.ReadFile(src)
+				Expect(err).ToNot(HaveOccurred())
+				result, err := os.ReadFile(dest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(bytes.Equal(expected, result)).To(BeTrue())
+			})
+		})
+	},
+		table.Entry("copy a raw image to a block device as is", "raw", "", "", sparseImageSize, ProcessingPhaseResize),
+		table.Entry("copy a raw image to a file as is", "raw", "", "", int64(-1), ProcessingPhaseResize),
+		table.Entry("copy a qcow2 image to a qcow2 target as is", "qcow2", "qcow2", "", int64(-1), ProcessingPhaseResize),
+		table.Entry("convert a qcow2 image to raw with qemu-img", "qcow2", "", "", sparseImageSize, ProcessingPhaseError),
+		table.Entry("convert a raw image to qcow2 with qemu-img", "raw", "qcow2", "", int64(-1), ProcessingPhaseError),
+		table.Entry("convert a qcow2 image to compressed qcow2 with qemu-img", "qcow2", "qcow2", "zstd", int64(-1), ProcessingPhaseError),
+	)
+
+	It("should write the ranges of a sparse raw image concurrently", func() {
+		src := createSparseImage(filepath.Join(tmpDir, "source.raw"), sparseImageSize)
+		data, err := os.ReadFile(src)
+		Expect(err).ToNot(HaveOccurred())
+		dest := filepath.Join(tmpDir, "dest")
+		Expect(os.WriteFile(dest, nil, 0600)).To(Succeed())
+
+		ranges := util.SplitCloneRanges(sparseImageSize, 3)
+		errs := make(chan error, len(ranges))
+		for _, r := range ranges {
+			go func(r util.CloneRange) {
+				errs <- StreamRawRange(bytes.NewReader(data[r.Start:r.End]), dest, r, false)
+			}(r)
+		}
+		for range ranges {
+			Expect(<-errs).To(Succeed())
+		}
+		copied, err := os.ReadFile(dest)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(copied).To(Equal(data))
+	})
+
+	It("should fail a range stream not matching the range", func() {
+		dest := createFilledFile(filepath.Join(tmpDir, "dest"), 4*sparseImageExtent, 0)
+		r := util.CloneRange{Start: sparseImageExtent, End: 2 * sparseImageExtent, Size: 4 * sparseImageExtent}
+		err := StreamRawRange(bytes.NewReader(make([]byte, sparseImageExtent/2)), dest, r, false)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("ended at offset"))
+		err = StreamRawRange(bytes.NewReader(make([]byte, sparseImageExtent+1)), dest, r, false)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("longer than the range"))
+	})
+
+	It("should convert an image with a backing file with qemu-img", func() {
+		src := createSparseImage(filepath.Join(tmpDir, "source.raw"), sparseImageSize)
+		srcURL, err := url.Parse(src)
+		Expect(err).ToNot(HaveOccurred())
+		dp := NewDataProcessor(&MockDataProvider{url: srcURL}, filepath.Join(tmpDir, "dest"), "dataDir", "scratchDataDir", "", 0.055, false)
+		info := &image.ImgInfo{Format: "raw", BackingFile: "base.raw", VirtualSize: sparseImageSize}
+		replaceQEMUOperations(NewFakeQEMUOperations(nil, nil, fakeInfoOpRetVal{info, nil}, nil, nil, nil), func() {
+			nextPhase, err := dp.convert(srcURL)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nextPhase).To(Equal(ProcessingPhaseResize))
+			Expect(dp.Passthrough()).To(BeFalse())
+		})
+	})
+})
+
+func BenchmarkPassthroughCopy(b *testing.B) {
+	benchmarkPassthrough(b, func(src, dest string) error {
+		return copySparse(src, dest, false)
+	})
+}
+
+func BenchmarkPassthroughQemuImg(b *testing.B) {
+	if _, err := exec.LookPath("qemu-img"); err != nil {
+		b.Skip("qemu-img is not available")
+	}
+	benchmarkPassthrough(b, func(src, dest string) error {
+		srcURL, err := url.Parse(src)
+		if err != nil {
+			return err
+		}
+		return image.NewQEMUOperations().ConvertToRawStream(srcURL, dest, false)
+	})
+}
+
+func benchmarkPassthrough(b *testing.B, copyFunc func(src, dest string) error) {
+	tmpDir, err := os.MkdirTemp("", "raw-block-copy")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	src := createSparseImage(filepath.Join(tmpDir, "source.raw"), sparseImageSize)
+	dest := filepath.Join(tmpDir, "dest")
+	b.SetBytes(sparseImageSize)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		os.Remove(dest)
+		b.StartTimer()
+		if err := copyFunc(src, dest); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// createSparseImage creates a raw image of the passed in size, with a data extent every 8 extents.
+func createSparseImage(path string, size int64) string {
+	f, err := os.Create(path)
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+	data := bytes.Repeat([]byte{0xaa}, sparseImageExtent)
+	for offset := int64(0); offset < size; offset += 8 * sparseImageExtent {
+		if _, err := f.WriteAt(data, offset); err != nil {
+			panic(err)
+		}
+	}
+	if err := f.Truncate(size); err != nil {
+		panic(err)
+	}
+	return path
+}
+
+func createFilledFile(path string, size int64, value byte) string {
+	if err := os.WriteFile(path, bytes.Repeat([]byte{value}, int(size)), 0600); err != nil {
+		panic(err)
+	}
+	return path
+}
@@ -45,13 +45,16 @@ type RegistryDataSource struct {
 	secKey      string
 	certDir     string
 	insecureTLS bool
-	imageDir    string
+	// diskPath selects a disk of a multi-disk image, relative to the disk directory of the image
+	diskPath string
+	imageDir string
 	//The discovered image file in scratch space.
 	url *url.URL
 }
 
-// NewRegistryDataSource creates a new instance of the Registry Data Source.
-func NewRegistryDataSource(endpoint, accessKey, secKey, certDir string, insecureTLS bool) *RegistryDataSource {
+// NewRegistryDataSource creates a new instance of the Registry Data Source. A non empty diskPath selects the disk to
+// import from an image holding several disks.
+func NewRegistryDataSource(endpoint, accessKey, secKey, certDir string, insecureTLS bool, diskPath string) *RegistryDataSource {
 	allCertDir, err := CreateCertificateDir(certDir)
 	if err != nil {
 		klog.Infof("Error creating allCertDir %v", err)
@@ -69,6 +72,7 @@ func NewRegistryDataSource(endpoint, accessKey, secKey, certDir string, insecure
 		secKey:      secKey,
 		certDir:     allCertDir,
 		insecureTLS: insecureTLS,
+		diskPath:    diskPath,
 	}
 }
 
@@ -93,19 +97,41 @@ func (rd *RegistryDataSource) Transfer(path string) (ProcessingPhase, error) {
 		return ProcessingPhaseError, ErrInvalidPath
 	}
 
+	diskPath := ""
+	if rd.diskPath != "" {
+		if !isLocalDiskPath(rd.diskPath) {
+			return ProcessingPhaseError, errors.Errorf("invalid disk path %s", rd.diskPath)
+		}
+		diskPath = filepath.ToSlash(filepath.Clean(rd.diskPath))
+	}
+
 	klog.V(1).Infof("Copying registry image to scratch space.")
-	err = CopyRegistryImage(rd.endpoint, path, containerDiskImageDir, rd.accessKey, rd.secKey, rd.certDir, rd.insecureTLS)
+	disks, err := CopyContainerDisk(rd.endpoint, path, diskPath, rd.accessKey, rd.secKey, rd.certDir, rd.insecureTLS)
 	if err != nil {
 		return ProcessingPhaseError, errors.Wrapf(err, "Failed to read registry image")
 	}
+	if err := validateContainerDisks(disks, diskPath); err != nil {
+		return ProcessingPhaseError, err
+	}
 
-	imageFile, err := getImageFileName(rd.imageDir)
+	var imageFile string
+	if rd.diskPath != "" {
+		imageFile, err = getDiskFileName(rd.imageDir, filepath.Clean(rd.diskPath))
+	} else {
+		imageFile, err = getImageFileName(rd.imageDir)
+	}
 	if err != nil {
 		return ProcessingPhaseError, errors.Wrapf(err, "Cannot locate image file")
 	}
 
-	// imageFile and rd.imageDir are both valid, thus the Join will be valid, and the parse will work, no need to check for parse errors
-	rd.url, _ = url.Parse(filepath.Join(rd.imageDir, imageFile))
+	// The disk of an OVA has to be extracted, qemu-img can't convert the archive
+	imagePath, err := extractOvaDisk(filepath.Join(rd.imageDir, imageFile))
+	if err != nil {
+		return ProcessingPhaseError, errors.Wrapf(err, "Cannot extract the disk of the OVA")
+	}
+
+	// imagePath is valid, thus the parse will work, no need to check for parse errors
+	rd.url, _ = url.Parse(imagePath)
 	klog.V(3).Infof("Successfully found file. VM disk image filename is %s", rd.url.String())
 	return ProcessingPhaseConvert, nil
 }
@@ -166,6 +192,29 @@ func getImageFileName(dir string) (string, error) {
 	return filename, nil
 }
 
+// isLocalDiskPath tells whether the path of a disk stays inside the disk directory of the image
+func isLocalDiskPath(diskPath string) bool {
+	diskPath = filepath.Clean(diskPath)
+	return !filepath.IsAbs(diskPath) && diskPath != "." && diskPath != ".." && !strings.HasPrefix(diskPath, "../")
+}
+
+// getDiskFileName returns the file of the disk selected in a multi-disk image, once it is extracted to dir
+func getDiskFileName(dir, diskPath string) (string, error) {
+	fileinfo, err := os.Stat(filepath.Join(dir, diskPath))
+	if err != nil {
+		klog.Errorf("disk %s does not exist in image directory", diskPath)
+		return "", errors.Errorf("disk %s does not exist in image directory", diskPath)
+	}
+	if !fileinfo.Mode().IsRegular() {
+		klog.Errorf("disk %s is not a regular file", diskPath)
+		return "", errors.Errorf("disk %s is not a regular file", diskPath)
+	}
+
+	klog.V(1).Infof("VM disk image filename is %s", diskPath)
+
+	return diskPath, nil
+}
+
 // CreateCertificateDir creates a common certificate dir
 func CreateCertificateDir(registryCertDir string) (string, error) {
 	allCerts := "/tmp/all_certs"
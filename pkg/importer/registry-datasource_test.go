@@ -1,6 +1,11 @@
 package importer
 
 import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -35,7 +40,7 @@ var _ = Describe("Registry data source", func() {
 	})
 
 	It("should return transfer after info is called", func() {
-		ds = NewRegistryDataSource("", "", "", "", true)
+		ds = NewRegistryDataSource("", "", "", "", true, "")
 		result, err := ds.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferScratch).To(Equal(result))
@@ -45,7 +50,7 @@ var _ = Describe("Registry data source", func() {
 		if scratchPath == "" {
 			scratchPath = tmpDir
 		}
-		ds = NewRegistryDataSource(ep, accKey, secKey, certDir, insecureRegistry)
+		ds = NewRegistryDataSource(ep, accKey, secKey, certDir, insecureRegistry, "")
 
 		// Need to pass in a real path if we don't want scratch space needed error.
 		result, err := ds.Transfer(scratchPath)
@@ -65,7 +70,7 @@ var _ = Describe("Registry data source", func() {
 	)
 
 	It("TransferFile should not be called", func() {
-		ds = NewRegistryDataSource("", "", "", "", true)
+		ds = NewRegistryDataSource("", "", "", "", true, "")
 		result, err := ds.TransferFile("file")
 		Expect(err).To(HaveOccurred())
 		Expect(ProcessingPhaseError).To(Equal(result))
@@ -113,6 +118,44 @@ var _ = Describe("Registry data source", func() {
 		Expect("image file does has no name").To(Equal(err.Error()))
 	})
 
+	Context("with a multi-disk image", func() {
+		var multiDiskImage string
+
+		BeforeEach(func() {
+			multiDiskImage = createMultiDiskRegistryImage(tmpDir, map[string]string{
+				"os.img":   strings.Repeat("os disk", 1024),
+				"data.img": strings.Repeat("data disk", 1024),
+			})
+		})
+
+		It("Transfer should extract only the selected disk", func() {
+			ds = NewRegistryDataSource("oci-archive:"+multiDiskImage, "", "", "", true, "data.img")
+			scratchPath := filepath.Join(tmpDir, "scratch")
+			Expect(os.Mkdir(scratchPath, 0700)).To(Succeed())
+			result, err := ds.Transfer(scratchPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ProcessingPhaseConvert))
+			Expect(ds.GetURL().Path).To(Equal(filepath.Join(scratchPath, containerDiskImageDir, "data.img")))
+			content, err := os.ReadFile(ds.GetURL().Path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal(strings.Repeat("data disk", 1024)))
+			_, err = os.Stat(filepath.Join(scratchPath, containerDiskImageDir, "os.img"))
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+
+		table.DescribeTable("Transfer should fail with the disk path", func(diskPath string) {
+			ds = NewRegistryDataSource("oci-archive:"+multiDiskImage, "", "", "", true, diskPath)
+			result, err := ds.Transfer(tmpDir)
+			Expect(err).To(HaveOccurred())
+			Expect(result).To(Equal(ProcessingPhaseError))
+		},
+			table.Entry("of a missing disk", "missing.img"),
+			table.Entry("outside of the disk directory", "../os.img"),
+			table.Entry("of the disk directory", "."),
+			table.Entry("absolute", "/disk/os.img"),
+		)
+	})
+
 	It("getImageFileName should return an error with multiple files in the image directory", func() {
 		err := os.Mkdir(filepath.Join(tmpDir, containerDiskImageDir), os.ModeDir)
 		Expect(err).NotTo(HaveOccurred())
@@ -125,3 +168,80 @@ var _ = Describe("Registry data source", func() {
 		Expect("image directory contains more than one file").To(Equal(err.Error()))
 	})
 })
+
+// createMultiDiskRegistryImage writes an OCI archive holding each disk in its own layer, under the disk directory
+func createMultiDiskRegistryImage(dir string, disks map[string]string) string {
+	var layers []map[string]string
+	for name, content := range disks {
+		layers = append(layers, map[string]string{filepath.Join(containerDiskImageDir, name): content})
+	}
+	return createRegistryImage(dir, layers...)
+}
+
+// createRegistryImage writes an OCI archive holding the layers, from the lowest one, each with the files of the map
+func createRegistryImage(dir string, files ...map[string]string) string {
+	imageDir, err := os.MkdirTemp(dir, "image")
+	Expect(err).NotTo(HaveOccurred())
+	layout := filepath.Join(imageDir, "layout")
+	Expect(os.MkdirAll(filepath.Join(layout, "blobs", "sha256"), 0700)).To(Succeed())
+	writeBlob := func(mediaType string, content []byte) map[string]interface{} {
+		digest := fmt.Sprintf("%x", sha256.Sum256(content))
+		Expect(os.WriteFile(filepath.Join(layout, "blobs", "sha256", digest), content, 0600)).To(Succeed())
+		return map[string]interface{}{"mediaType": mediaType, "digest": "sha256:" + digest, "size": len(content)}
+	}
+	marshal := func(v interface{}) []byte {
+		content, err := json.Marshal(v)
+		Expect(err).NotTo(HaveOccurred())
+		return content
+	}
+
+	var layers []map[string]interface{}
+	var diffIDs []string
+	for _, layerFiles := range files {
+		layer := writeTar(layerFiles)
+		layers = append(layers, writeBlob("application/vnd.oci.image.layer.v1.tar", layer))
+		diffIDs = append(diffIDs, fmt.Sprintf("sha256:%x", sha256.Sum256(layer)))
+	}
+	config := writeBlob("application/vnd.oci.image.config.v1+json", marshal(map[string]interface{}{
+		"architecture": "amd64",
+		"os":           "linux",
+		"rootfs":       map[string]interface{}{"type": "layers", "diff_ids": diffIDs},
+	}))
+	manifest := writeBlob("application/vnd.oci.image.manifest.v1+json", marshal(map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     "application/vnd.oci.image.manifest.v1+json",
+		"config":        config,
+		"layers":        layers,
+	}))
+	index := marshal(map[string]interface{}{
+		"schemaVersion": 2,
+		"manifests":     []interface{}{manifest},
+	})
+
+	archiveFiles := map[string]string{
+		"oci-layout": `{"imageLayoutVersion": "1.0.0"}`,
+		"index.json": string(index),
+	}
+	entries, err := os.ReadDir(filepath.Join(layout, "blobs", "sha256"))
+	Expect(err).NotTo(HaveOccurred())
+	for _, entry := range entries {
+		content, err := os.ReadFile(filepath.Join(layout, "blobs", "sha256", entry.Name()))
+		Expect(err).NotTo(HaveOccurred())
+		archiveFiles[filepath.Join("blobs", "sha256", entry.Name())] = string(content)
+	}
+	archive := filepath.Join(imageDir, "image.tar")
+	Expect(os.WriteFile(archive, writeTar(archiveFiles), 0600)).To(Succeed())
+	return archive
+}
+
+func writeTar(files map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for name, content := range files {
+		Expect(tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})).To(Succeed())
+		_, err := tw.Write([]byte(content))
+		Expect(err).NotTo(HaveOccurred())
+	}
+	Expect(tw.Close()).To(Succeed())
+	return buf.Bytes()
+}
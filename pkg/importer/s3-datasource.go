@@ -1,6 +1,7 @@
 package importer
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/url"
@@ -9,7 +10,9 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 
@@ -17,17 +20,21 @@ import (
 
 	"k8s.io/klog/v2"
 
+	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/util"
 )
 
 const (
 	s3FolderSep = "/"
 	httpScheme  = "http"
+	// s3KMSErrorPrefix prefixes the codes of the errors S3 returns when the KMS key of an object cannot be used
+	s3KMSErrorPrefix = "KMS."
+	s3AccessDenied   = "AccessDenied"
 )
 
 // S3Client is the interface to the used S3 client.
 type S3Client interface {
-	GetObject(input *s3.GetObjectInput) (*s3.GetObjectOutput, error)
+	GetObjectWithContext(ctx aws.Context, input *s3.GetObjectInput, opts ...request.Option) (*s3.GetObjectOutput, error)
 }
 
 // may be overridden in tests
@@ -44,6 +51,9 @@ type S3DataSource struct {
 	accessKey string
 	// Password
 	secKey string
+	// Context of the download, canceled on Close
+	ctx    context.Context
+	cancel context.CancelFunc
 	// Reader
 	s3Reader io.ReadCloser
 	// stack of readers
@@ -52,17 +62,22 @@ type S3DataSource struct {
 	url *url.URL
 }
 
-// NewS3DataSource creates a new instance of the S3DataSource
-func NewS3DataSource(endpoint, accessKey, secKey string, certDir string) (*S3DataSource, error) {
+// NewS3DataSource creates a new instance of the S3DataSource. The token credentials, if any, are used instead of the keys.
+// If a KMS key id is passed, the object must be SSE-KMS encrypted with that key.
+func NewS3DataSource(endpoint, accessKey, secKey string, certDir string, kmsKeyID string, token *TokenCredentials) (*S3DataSource, error) {
 	ep, err := ParseEndpoint(endpoint)
 	if err != nil {
-		return nil, errors.Wrapf(err, fmt.Sprintf("unable to parse endpoint %q", endpoint))
+		return nil, errors.Wrapf(err, fmt.Sprintf("unable to parse endpoint %q", redactSourceURL(endpoint)))
 	}
-	s3Reader, err := createS3Reader(ep, accessKey, secKey, certDir)
+	ctx, cancel := context.WithCancel(context.Background())
+	s3Reader, err := createS3Reader(ctx, ep, accessKey, secKey, certDir, kmsKeyID, token)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 	return &S3DataSource{
+		ctx:       ctx,
+		cancel:    cancel,
 		ep:        ep,
 		accessKey: accessKey,
 		secKey:    secKey,
@@ -73,11 +88,12 @@ func NewS3DataSource(endpoint, accessKey, secKey string, certDir string) (*S3Dat
 // Info is called to get initial information about the data.
 func (sd *S3DataSource) Info() (ProcessingPhase, error) {
 	var err error
-	sd.readers, err = NewFormatReaders(sd.s3Reader, uint64(0))
+	sd.readers, err = NewDiskFormatReaders(sd.s3Reader, uint64(0))
 	if err != nil {
 		klog.Errorf("Error creating readers: %v", err)
 		return ProcessingPhaseError, err
 	}
+	sd.readers.CheckExtensionHint(sd.ep.Path)
 	if !sd.readers.Convert {
 		// Downloading a raw file, we can write that directly to the target.
 		return ProcessingPhaseTransferDataFile, nil
@@ -126,16 +142,23 @@ func (sd *S3DataSource) GetURL() *url.URL {
 	return sd.url
 }
 
+func (sd *S3DataSource) formatReaders() *FormatReaders {
+	return sd.readers
+}
+
 // Close closes any readers or other open resources.
 func (sd *S3DataSource) Close() error {
 	var err error
+	if sd.cancel != nil {
+		sd.cancel()
+	}
 	if sd.readers != nil {
 		err = sd.readers.Close()
 	}
 	return err
 }
 
-func createS3Reader(ep *url.URL, accessKey, secKey string, certDir string) (io.ReadCloser, error) {
+func createS3Reader(ctx context.Context, ep *url.URL, accessKey, secKey string, certDir string, kmsKeyID string, token *TokenCredentials) (io.ReadCloser, error) {
 	klog.V(3).Infoln("Using S3 client to get data")
 
 	endpoint := ep.Host
@@ -146,7 +169,11 @@ func createS3Reader(ep *url.URL, accessKey, secKey string, certDir string) (io.R
 
 	klog.V(1).Infof("bucket %s", bucket)
 	klog.V(1).Infof("object %s", object)
-	svc, err := newClientFunc(endpoint, accessKey, secKey, certDir, urlScheme)
+	creds, err := newS3Credentials(extractRegion(endpoint), accessKey, secKey, token)
+	if err != nil {
+		return nil, err
+	}
+	svc, err := newClientFunc(endpoint, creds, certDir, urlScheme)
 	if err != nil {
 		return nil, errors.Wrapf(err, "could not build s3 client for %q", ep.Host)
 	}
@@ -155,15 +182,83 @@ func createS3Reader(ep *url.URL, accessKey, secKey string, certDir string) (io.R
 		Bucket: aws.String(bucket),
 		Key:    aws.String(object),
 	}
-	objOutput, err := svc.GetObject(objInput)
+	objOutput, err := svc.GetObjectWithContext(ctx, objInput)
 	if err != nil {
+		if isS3KMSAccessDenied(err) {
+			return nil, newS3KMSAccessDeniedError(err, kmsKeyID)
+		}
 		return nil, errors.Wrapf(err, "could not get s3 object: \"%s/%s\"", bucket, object)
 	}
 	objectReader := objOutput.Body
+	if err := checkS3KMSKey(objOutput, kmsKeyID); err != nil {
+		objectReader.Close()
+		return nil, errors.Wrapf(err, "s3 object \"%s/%s\"", bucket, object)
+	}
+	if length := aws.Int64Value(objOutput.ContentLength); length > 0 {
+		if config := getMultipartConfig(); config.enabled(uint64(length)) {
+			// S3 always supports byte ranges, close the single stream and download the ranges in parallel.
+			if err := objectReader.Close(); err != nil {
+				return nil, errors.Wrap(err, "could not close s3 object reader")
+			}
+			objectReader = newMultipartReader(ctx, uint64(length), config, s3RangeReader(svc, bucket, object))
+		}
+	}
 	return objectReader, nil
 }
 
-func getS3Client(endpoint, accessKey, secKey string, certDir string, urlScheme string) (S3Client, error) {
+// s3RangeReader returns a rangeReaderFunc that requests byte ranges of the object.
+func s3RangeReader(svc S3Client, bucket, object string) rangeReaderFunc {
+	return func(ctx context.Context, start, end int64) (io.ReadCloser, error) {
+		objOutput, err := svc.GetObjectWithContext(ctx, &s3.GetObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(object),
+			Range:  aws.String(fmt.Sprintf("bytes=%d-%d", start, end)),
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not get s3 object: \"%s/%s\"", bucket, object)
+		}
+		return objOutput.Body, nil
+	}
+}
+
+// checkS3KMSKey returns an error if a KMS key id is passed and the object is not SSE-KMS encrypted with that key. S3
+// decrypts SSE-KMS objects transparently and takes no key in the read request, so the key is checked against the one
+// the response reports. The key id may be the key ARN, or the key id which the ARN ends with.
+func checkS3KMSKey(objOutput *s3.GetObjectOutput, kmsKeyID string) error {
+	if kmsKeyID == "" {
+		return nil
+	}
+	if !strings.HasPrefix(aws.StringValue(objOutput.ServerSideEncryption), s3.ServerSideEncryptionAwsKms) {
+		return errors.Errorf("not encrypted with SSE-KMS, expected KMS key %q", kmsKeyID)
+	}
+	objectKeyID := aws.StringValue(objOutput.SSEKMSKeyId)
+	if objectKeyID != kmsKeyID && !strings.HasSuffix(objectKeyID, ":key/"+kmsKeyID) {
+		return errors.Errorf("encrypted with KMS key %q, expected KMS key %q", objectKeyID, kmsKeyID)
+	}
+	return nil
+}
+
+// isS3KMSAccessDenied returns true if S3 could not decrypt the object because the credentials may not use its KMS key
+func isS3KMSAccessDenied(err error) bool {
+	var awsErr awserr.Error
+	if !errors.As(err, &awsErr) {
+		return false
+	}
+	if strings.HasPrefix(awsErr.Code(), s3KMSErrorPrefix) {
+		return true
+	}
+	return awsErr.Code() == s3AccessDenied && strings.Contains(strings.ToLower(awsErr.Message()), "kms")
+}
+
+func newS3KMSAccessDeniedError(err error, kmsKeyID string) error {
+	key := "the KMS key of the object"
+	if kmsKeyID != "" {
+		key = fmt.Sprintf("KMS key %q", kmsKeyID)
+	}
+	return errors.Errorf("%s, the credentials need the kms:Decrypt permission on %s: %v", common.S3KMSAccessDeniedMessage, key, err)
+}
+
+func getS3Client(endpoint string, creds *credentials.Credentials, certDir string, urlScheme string) (S3Client, error) {
 	// Adding certs using CustomCABundle will overwrite the SystemCerts, so we opt by creating a custom HTTPClient
 	httpClient, err := createHTTPClient(certDir)
 
@@ -171,7 +266,6 @@ func getS3Client(endpoint, accessKey, secKey string, certDir string, urlScheme s
 		return nil, errors.Wrap(err, "Error creating http client for s3")
 	}
 
-	creds := credentials.NewStaticCredentials(accessKey, secKey, "")
 	region := extractRegion(endpoint)
 	disableSSL := false
 	// Disable SSL for http endpoint. This should cause the s3 client to create http requests.
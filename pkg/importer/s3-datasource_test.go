@@ -5,7 +5,12 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/s3"
 
 	. "github.com/onsi/ginkgo"
@@ -13,6 +18,8 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/pkg/errors"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
 )
 
 var _ = Describe("S3 data source", func() {
@@ -38,25 +45,25 @@ var _ = Describe("S3 data source", func() {
 	})
 
 	It("NewS3DataSource should Error, when passed in an invalid endpoint", func() {
-		sd, err = NewS3DataSource("thisisinvalid#$%#ep", "", "", "")
+		sd, err = NewS3DataSource("thisisinvalid#$%#ep", "", "", "", "", nil)
 		Expect(err).To(HaveOccurred())
 	})
 
 	It("NewS3DataSource should Error, when failing to create S3 client", func() {
 		newClientFunc = failMockS3Client
-		sd, err = NewS3DataSource("http://amazon.com", "", "", "")
+		sd, err = NewS3DataSource("http://amazon.com", "", "", "", "", nil)
 		Expect(err).To(HaveOccurred())
 	})
 
 	It("NewS3DataSource should Error, when failing to get object", func() {
 		newClientFunc = createErrMockS3Client
-		sd, err = NewS3DataSource("http://amazon.com", "", "", "")
+		sd, err = NewS3DataSource("http://amazon.com", "", "", "", "", nil)
 		Expect(err).To(HaveOccurred())
 	})
 
 	It("NewS3DataSource should fail when called with an invalid certdir", func() {
 		newClientFunc = getS3Client
-		sd, err = NewS3DataSource("http://amazon.com", "", "", "/invaliddir")
+		sd, err = NewS3DataSource("http://amazon.com", "", "", "/invaliddir", "", nil)
 		Expect(err).To(HaveOccurred())
 	})
 
@@ -66,7 +73,7 @@ var _ = Describe("S3 data source", func() {
 		Expect(err).NotTo(HaveOccurred())
 		err = file.Close()
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "")
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "", "", nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.s3Reader = file
 		result, err := sd.Info()
@@ -78,7 +85,7 @@ var _ = Describe("S3 data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		file, err := os.Open(cirrosFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "")
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "", "", nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.s3Reader = file
 		result, err := sd.Info()
@@ -90,7 +97,7 @@ var _ = Describe("S3 data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		file, err := os.Open(tinyCoreFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "")
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "", "", nil)
 		Expect(err).NotTo(HaveOccurred())
 		sd.s3Reader = file
 		result, err := sd.Info()
@@ -105,7 +112,7 @@ var _ = Describe("S3 data source", func() {
 		sourceFile, err := os.Open(fileName)
 		Expect(err).NotTo(HaveOccurred())
 
-		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "")
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "", "", nil)
 		Expect(err).NotTo(HaveOccurred())
 		// Replace minio.Object with a reader we can use.
 		sd.s3Reader = sourceFile
@@ -139,7 +146,7 @@ var _ = Describe("S3 data source", func() {
 		sourceFile, err := os.Open(cirrosFilePath)
 		Expect(err).NotTo(HaveOccurred())
 
-		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "")
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "", "", nil)
 		Expect(err).NotTo(HaveOccurred())
 		// Replace minio.Object with a reader we can use.
 		sd.s3Reader = sourceFile
@@ -157,7 +164,7 @@ var _ = Describe("S3 data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		file, err := os.Open(tinyCoreFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "")
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "", "", nil)
 		Expect(err).NotTo(HaveOccurred())
 		// Replace minio.Object with a reader we can use.
 		sd.s3Reader = file
@@ -173,7 +180,7 @@ var _ = Describe("S3 data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		file, err := os.Open(tinyCoreFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "")
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "", "", nil)
 		Expect(err).NotTo(HaveOccurred())
 		// Replace minio.Object with a reader we can use.
 		sd.s3Reader = file
@@ -185,8 +192,68 @@ var _ = Describe("S3 data source", func() {
 		Expect(ProcessingPhaseError).To(Equal(result))
 	})
 
+	It("NewS3DataSource should use the token credentials instead of the keys", func() {
+		newStsClientFunc = func(region string) (stsWebIdentityClient, error) {
+			return &fakeStsClient{}, nil
+		}
+		defer func() {
+			newStsClientFunc = getStsClient
+		}()
+		var client *MockS3Client
+		newClientFunc = func(endpoint string, creds *credentials.Credentials, certDir string, urlScheme string) (S3Client, error) {
+			client = &MockS3Client{creds: creds}
+			return client, nil
+		}
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "access", "secret", "", "",
+			&TokenCredentials{TokenFile: filepath.Join(tmpDir, "token"), RoleARN: "arn:aws:iam::123456789012:role/importer"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.creds.Get()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("could not read the service account token"))
+	})
+
+	table.DescribeTable("NewS3DataSource should check the KMS key of the object", func(kmsKeyID, encryption, objectKeyID, wantErr string) {
+		newClientFunc = func(endpoint string, creds *credentials.Credentials, certDir string, urlScheme string) (S3Client, error) {
+			return &MockS3Client{output: &s3.GetObjectOutput{
+				Body:                 io.NopCloser(strings.NewReader("")),
+				ServerSideEncryption: aws.String(encryption),
+				SSEKMSKeyId:          aws.String(objectKeyID),
+			}}, nil
+		}
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "", kmsKeyID, nil)
+		if wantErr == "" {
+			Expect(err).NotTo(HaveOccurred())
+		} else {
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(wantErr))
+		}
+	},
+		table.Entry("succeed without a KMS key", "", s3.ServerSideEncryptionAes256, "", ""),
+		table.Entry("succeed with the key ARN", testKMSKeyARN, s3.ServerSideEncryptionAwsKms, testKMSKeyARN, ""),
+		table.Entry("succeed with the key id", testKMSKeyID, s3.ServerSideEncryptionAwsKms, testKMSKeyARN, ""),
+		table.Entry("fail with another key", "other-key", s3.ServerSideEncryptionAwsKms, testKMSKeyARN, `encrypted with KMS key "`+testKMSKeyARN+`", expected KMS key "other-key"`),
+		table.Entry("fail when the object is not SSE-KMS encrypted", testKMSKeyID, s3.ServerSideEncryptionAes256, "", "not encrypted with SSE-KMS"),
+	)
+
+	table.DescribeTable("NewS3DataSource should report the KMS access denied errors", func(getErr error, kmsKeyID string, isKMS bool, wantMessage string) {
+		newClientFunc = func(endpoint string, creds *credentials.Credentials, certDir string, urlScheme string) (S3Client, error) {
+			return &MockS3Client{err: getErr}, nil
+		}
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "", kmsKeyID, nil)
+		Expect(err).To(HaveOccurred())
+		Expect(strings.Contains(err.Error(), common.S3KMSAccessDeniedMessage)).To(Equal(isKMS))
+		Expect(err.Error()).To(ContainSubstring(wantMessage))
+	},
+		table.Entry("when denied kms:Decrypt on the configured key", awserr.New(s3AccessDenied, "not authorized to perform: kms:Decrypt", nil), testKMSKeyID, true,
+			`the credentials need the kms:Decrypt permission on KMS key "`+testKMSKeyID+`"`),
+		table.Entry("when the key of the object is disabled", awserr.New("KMS.DisabledException", "key is disabled", nil), "", true,
+			"the credentials need the kms:Decrypt permission on the KMS key of the object"),
+		table.Entry("but not when denied access to the object", awserr.New(s3AccessDenied, "Access Denied", nil), testKMSKeyID, false,
+			"could not get s3 object"),
+	)
+
 	It("GetS3Client should return a real client", func() {
-		_, err := getS3Client("", "", "", "", "")
+		_, err := getS3Client("", credentials.NewStaticCredentials("", "", ""), "", "")
 		Expect(err).NotTo(HaveOccurred())
 	})
 
@@ -201,35 +268,46 @@ var _ = Describe("S3 data source", func() {
 	})
 })
 
+const (
+	testKMSKeyID  = "1234abcd-12ab-34cd-56ef-1234567890ab"
+	testKMSKeyARN = "arn:aws:kms:us-east-1:123456789012:key/" + testKMSKeyID
+)
+
 // MockS3Client is a mock AWS S3 client
 type MockS3Client struct {
 	endpoint string //nolint:unused // TODO: check if need to remove this field
-	accKey   string
-	secKey   string
+	creds    *credentials.Credentials
 	certDir  string
 	doErr    bool
+	output   *s3.GetObjectOutput
+	err      error
 }
 
-func failMockS3Client(endpoint, accKey, secKey string, certDir string, urlScheme string) (S3Client, error) {
+func failMockS3Client(endpoint string, creds *credentials.Credentials, certDir string, urlScheme string) (S3Client, error) {
 	return nil, errors.New("Failed to create client")
 }
 
-func createMockS3Client(endpoint, accKey, secKey string, certDir string, urlScheme string) (S3Client, error) {
+func createMockS3Client(endpoint string, creds *credentials.Credentials, certDir string, urlScheme string) (S3Client, error) {
 	return &MockS3Client{
-		accKey:  accKey,
-		secKey:  secKey,
+		creds:   creds,
 		certDir: certDir,
 		doErr:   false,
 	}, nil
 }
 
-func createErrMockS3Client(endpoint, accKey, secKey string, certDir string, urlScheme string) (S3Client, error) {
+func createErrMockS3Client(endpoint string, creds *credentials.Credentials, certDir string, urlScheme string) (S3Client, error) {
 	return &MockS3Client{
 		doErr: true,
 	}, nil
 }
 
-func (mc *MockS3Client) GetObject(input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+func (mc *MockS3Client) GetObjectWithContext(ctx aws.Context, input *s3.GetObjectInput, opts ...request.Option) (*s3.GetObjectOutput, error) {
+	if mc.err != nil {
+		return nil, mc.err
+	}
+	if mc.output != nil {
+		return mc.output, nil
+	}
 	if !mc.doErr {
 		return &s3.GetObjectOutput{}, nil
 	}
@@ -0,0 +1,492 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+const (
+	sftpDefaultPort = "22"
+	// sftpVersion is the version of the SFTP protocol spoken by the client, the one of OpenSSH
+	sftpVersion = 3
+	// sftpMaxPacketSize bounds the packets accepted from the server, above the largest read response
+	sftpMaxPacketSize = 256 * 1024
+
+	sshFxpInit    = 1
+	sshFxpVersion = 2
+	sshFxpOpen    = 3
+	sshFxpClose   = 4
+	sshFxpRead    = 5
+	sshFxpFstat   = 8
+	sshFxpStatus  = 101
+	sshFxpHandle  = 102
+	sshFxpData    = 103
+	sshFxpAttrs   = 105
+
+	sshFxOK               = 0
+	sshFxEOF              = 1
+	sshFxNoSuchFile       = 2
+	sshFxPermissionDenied = 3
+
+	sshFxfRead          = 0x00000001
+	sshFilexferAttrSize = 0x00000001
+)
+
+// may be overridden in tests
+var (
+	sftpDialTimeout = 30 * time.Second
+	sftpIdleTimeout = 2 * time.Minute
+)
+
+// sftpCredentials are the credentials of an SFTP source, read from the files of its mounted secret. The user logs in
+// with the private key, the password, or both if the server asks for both.
+type sftpCredentials struct {
+	username   string
+	password   string
+	privateKey string
+	passphrase string
+}
+
+// readSFTPCredentials reads the user and its private key or password from the secret mounted in dir
+func readSFTPCredentials(dir string) (*sftpCredentials, error) {
+	creds := &sftpCredentials{}
+	for key, value := range map[string]*string{
+		common.KeySFTPUsername:   &creds.username,
+		common.KeySFTPPassword:   &creds.password,
+		common.KeySFTPPrivateKey: &creds.privateKey,
+		common.KeySFTPPassphrase: &creds.passphrase,
+	} {
+		data, err := os.ReadFile(filepath.Join(dir, key))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "could not read %s of the SFTP secret", key)
+		}
+		*value = string(data)
+	}
+	// the password and the passphrase may end with spaces, only the line ending of the file is dropped
+	creds.username = strings.TrimSpace(creds.username)
+	creds.password = strings.TrimRight(creds.password, "\r\n")
+	creds.passphrase = strings.TrimRight(creds.passphrase, "\r\n")
+	if creds.username == "" {
+		return nil, errors.Errorf("the SFTP secret needs a %s", common.KeySFTPUsername)
+	}
+	if creds.password == "" && creds.privateKey == "" {
+		return nil, errors.Errorf("the SFTP secret needs a %s or a %s", common.KeySFTPPrivateKey, common.KeySFTPPassword)
+	}
+	return creds, nil
+}
+
+// authMethods returns the public key authentication with the private key, and the password and keyboard-interactive
+// authentications with the password
+func (creds *sftpCredentials) authMethods() ([]ssh.AuthMethod, error) {
+	var methods []ssh.AuthMethod
+	if creds.privateKey != "" {
+		var signer ssh.Signer
+		var err error
+		if creds.passphrase != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase([]byte(creds.privateKey), []byte(creds.passphrase))
+		} else {
+			signer, err = ssh.ParsePrivateKey([]byte(creds.privateKey))
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s in the SFTP secret", common.KeySFTPPrivateKey)
+		}
+		methods = append(methods, ssh.PublicKeys(signer))
+	}
+	if creds.password != "" {
+		password := creds.password
+		methods = append(methods, ssh.Password(password),
+			ssh.KeyboardInteractive(func(user, instruction string, questions []string, echos []bool) ([]string, error) {
+				answers := make([]string, len(questions))
+				for i := range answers {
+					answers[i] = password
+				}
+				return answers, nil
+			}))
+	}
+	return methods, nil
+}
+
+// sftpHostKeyConfig returns the callback accepting only the pinned host key of the server, and the host key algorithms
+// to negotiate for it. The pinned key is either a public key in the authorized_keys format, or the SHA256 fingerprint
+// of the key printed by ssh-keygen -l. A fingerprint doesn't tell the type of the key, so the server presents the key
+// of the type it prefers among the ones the client supports, which must be the fingerprinted one. The full key is
+// needed for a server with keys of several types.
+func sftpHostKeyConfig(hostKey string) (ssh.HostKeyCallback, []string, error) {
+	hostKey = strings.TrimSpace(hostKey)
+	if hostKey == "" {
+		return nil, nil, errors.New("the host key of the SFTP server is not pinned")
+	}
+	if strings.HasPrefix(hostKey, "SHA256:") {
+		return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			if fingerprint := ssh.FingerprintSHA256(key); fingerprint != hostKey {
+				return errors.Errorf("the %s host key %s of the SFTP server %s does not match the pinned fingerprint %s, pin the full host key if the server has keys of several types", key.Type(), fingerprint, hostname, hostKey)
+			}
+			return nil
+		}, nil, nil
+	}
+	pinned, _, _, _, err := ssh.ParseAuthorizedKey([]byte(hostKey))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "invalid pinned host key of the SFTP server")
+	}
+	algorithms := []string{pinned.Type()}
+	if pinned.Type() == ssh.KeyAlgoRSA {
+		// RSA keys sign with SHA-2 on current servers
+		algorithms = []string{ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSA}
+	}
+	fixed := ssh.FixedHostKey(pinned)
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		if err := fixed(hostname, remote, key); err != nil {
+			return errors.Errorf("the %s host key %s of the SFTP server %s does not match the pinned key %s", key.Type(), ssh.FingerprintSHA256(key), hostname, ssh.FingerprintSHA256(pinned))
+		}
+		return nil
+	}, algorithms, nil
+}
+
+// sftpStatusError is an SSH_FXP_STATUS response reporting a failed request
+type sftpStatusError struct {
+	code uint32
+	msg  string
+}
+
+func (e *sftpStatusError) Error() string {
+	return fmt.Sprintf("SFTP request failed with status %d: %s", e.code, e.msg)
+}
+
+// isPermanentSFTPError returns true if the request failed because the file is missing or not readable, which a
+// retry does not fix
+func isPermanentSFTPError(err error) bool {
+	var statusErr *sftpStatusError
+	return errors.As(err, &statusErr) && (statusErr.code == sshFxNoSuchFile || statusErr.code == sshFxPermissionDenied)
+}
+
+// sftpPacket is a response of the server to the request id
+type sftpPacket struct {
+	typ  byte
+	id   uint32
+	data []byte
+}
+
+// sftpClient is a minimal SFTP version 3 client reading files, over the sftp subsystem of an SSH session. Requests are
+// sent without waiting for the responses of the previous ones, and the responses, which the server may send in any
+// order, are matched by their request id.
+type sftpClient struct {
+	sshClient *ssh.Client
+	session   *ssh.Session
+	w         io.WriteCloser
+	r         *bufio.Reader
+	nextID    uint32
+	// responses are the responses read while waiting for the response to another request
+	responses map[uint32]*sftpPacket
+	// discarded are the requests not waited for anymore, their responses are dropped when they are read
+	discarded map[uint32]bool
+}
+
+// dialSFTP connects and logs in to the SSH server at the address host:port, and starts its sftp subsystem
+func dialSFTP(address string, config *ssh.ClientConfig) (*sftpClient, error) {
+	conn, err := net.DialTimeout("tcp", address, sftpDialTimeout)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not connect to the SFTP server %s", address)
+	}
+	// the server must complete the login in time, the deadline is cleared before the connection is handed to the SSH
+	// client, the responses of the session are then bounded by sftpIdleTimeout
+	if err := conn.SetDeadline(time.Now().Add(sftpDialTimeout)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, address, config)
+	if err != nil {
+		conn.Close()
+		return nil, errors.Wrapf(err, "SSH login to the SFTP server %s failed", address)
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		sshConn.Close()
+		return nil, err
+	}
+	c := &sftpClient{
+		sshClient: ssh.NewClient(sshConn, chans, reqs),
+		responses: map[uint32]*sftpPacket{},
+		discarded: map[uint32]bool{},
+	}
+	if err := c.start(); err != nil {
+		c.sshClient.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// start starts the sftp subsystem and negotiates the version of the protocol
+func (c *sftpClient) start() error {
+	session, err := c.sshClient.NewSession()
+	if err != nil {
+		return errors.Wrap(err, "could not open an SSH session")
+	}
+	c.session = session
+	if c.w, err = session.StdinPipe(); err != nil {
+		return err
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	c.r = bufio.NewReaderSize(stdout, sftpMaxPacketSize)
+	if err := session.RequestSubsystem("sftp"); err != nil {
+		return errors.Wrap(err, "the SSH server refused the sftp subsystem")
+	}
+	// SSH_FXP_INIT has no request id, the version takes its place
+	if err := c.send(sshFxpInit, sftpVersion); err != nil {
+		return err
+	}
+	p, err := c.recv()
+	if err != nil {
+		return errors.Wrap(err, "SFTP version negotiation failed")
+	}
+	if p.typ != sshFxpVersion || p.id < sftpVersion {
+		return errors.Errorf("the SFTP server does not support the version %d of the protocol", sftpVersion)
+	}
+	return nil
+}
+
+// send writes a packet of type typ with the request id and the payload fields, uint32, uint64, string or []byte
+func (c *sftpClient) send(typ byte, id uint32, fields ...interface{}) error {
+	buf := []byte{0, 0, 0, 0, typ}
+	buf = binary.BigEndian.AppendUint32(buf, id)
+	for _, field := range fields {
+		switch v := field.(type) {
+		case uint32:
+			buf = binary.BigEndian.AppendUint32(buf, v)
+		case uint64:
+			buf = binary.BigEndian.AppendUint64(buf, v)
+		case string:
+			buf = binary.BigEndian.AppendUint32(buf, uint32(len(v)))
+			buf = append(buf, v...)
+		default:
+			return errors.Errorf("unsupported SFTP field %T", field)
+		}
+	}
+	binary.BigEndian.PutUint32(buf, uint32(len(buf)-4))
+	_, err := c.w.Write(buf)
+	return err
+}
+
+// recv reads the next packet, failing if the server is idle for sftpIdleTimeout. The connection is read by the
+// goroutine of the SSH client, so an idle server is not detected with a deadline on it, the SSH client is closed
+// instead, which ends the read.
+func (c *sftpClient) recv() (*sftpPacket, error) {
+	idle := time.AfterFunc(sftpIdleTimeout, func() {
+		c.sshClient.Close()
+	})
+	p, err := c.readPacket()
+	if !idle.Stop() {
+		return nil, errors.Errorf("the SFTP server did not respond for %s", sftpIdleTimeout)
+	}
+	return p, err
+}
+
+// readPacket reads the next packet of the session
+func (c *sftpClient) readPacket() (*sftpPacket, error) {
+	var header [9]byte
+	if _, err := io.ReadFull(c.r, header[:]); err != nil {
+		return nil, errors.Wrap(err, "could not read the SFTP response")
+	}
+	length := binary.BigEndian.Uint32(header[:4])
+	if length < 5 || length > sftpMaxPacketSize {
+		return nil, errors.Errorf("invalid SFTP packet length %d", length)
+	}
+	p := &sftpPacket{
+		typ:  header[4],
+		id:   binary.BigEndian.Uint32(header[5:9]),
+		data: make([]byte, length-5),
+	}
+	if _, err := io.ReadFull(c.r, p.data); err != nil {
+		return nil, errors.Wrap(err, "could not read the SFTP response")
+	}
+	return p, nil
+}
+
+// request sends a request, and returns its id to wait for its response
+func (c *sftpClient) request(typ byte, fields ...interface{}) (uint32, error) {
+	c.nextID++
+	id := c.nextID
+	if err := c.send(typ, id, fields...); err != nil {
+		return 0, errors.Wrap(err, "could not send the SFTP request")
+	}
+	return id, nil
+}
+
+// wait returns the response to the request id, keeping the responses to the other requests read before it
+func (c *sftpClient) wait(id uint32) (*sftpPacket, error) {
+	for {
+		if p, ok := c.responses[id]; ok {
+			delete(c.responses, id)
+			return p, nil
+		}
+		p, err := c.recv()
+		if err != nil {
+			return nil, err
+		}
+		if p.id == 0 || p.id > c.nextID || c.responses[p.id] != nil {
+			return nil, errors.Errorf("unexpected SFTP response to the request %d", p.id)
+		}
+		if c.discarded[p.id] {
+			delete(c.discarded, p.id)
+			continue
+		}
+		c.responses[p.id] = p
+	}
+}
+
+// discard drops the response to the request id, which is not waited for anymore
+func (c *sftpClient) discard(id uint32) {
+	if _, ok := c.responses[id]; ok {
+		delete(c.responses, id)
+		return
+	}
+	c.discarded[id] = true
+}
+
+// call sends a request and waits for its response
+func (c *sftpClient) call(typ byte, fields ...interface{}) (*sftpPacket, error) {
+	id, err := c.request(typ, fields...)
+	if err != nil {
+		return nil, err
+	}
+	return c.wait(id)
+}
+
+// open opens the file at path for reading, and returns its handle
+func (c *sftpClient) open(path string) (string, error) {
+	// the empty attributes have no flags
+	p, err := c.call(sshFxpOpen, path, uint32(sshFxfRead), uint32(0))
+	if err != nil {
+		return "", err
+	}
+	if p.typ != sshFxpHandle {
+		return "", errors.Wrapf(parseSFTPStatus(p), "could not open %s", path)
+	}
+	handle, _, ok := parseSFTPString(p.data)
+	if !ok {
+		return "", errors.New("invalid SFTP handle")
+	}
+	return handle, nil
+}
+
+// size returns the size of the open file, 0 if the server does not report it
+func (c *sftpClient) size(handle string) (uint64, error) {
+	p, err := c.call(sshFxpFstat, handle)
+	if err != nil {
+		return 0, err
+	}
+	if p.typ != sshFxpAttrs {
+		return 0, parseSFTPStatus(p)
+	}
+	if len(p.data) < 4 || binary.BigEndian.Uint32(p.data)&sshFilexferAttrSize == 0 {
+		return 0, nil
+	}
+	if len(p.data) < 12 {
+		return 0, errors.New("invalid SFTP file attributes")
+	}
+	return binary.BigEndian.Uint64(p.data[4:12]), nil
+}
+
+// read requests length bytes of the open file from offset, and returns the id to wait for the data with readData
+func (c *sftpClient) read(handle string, offset uint64, length uint32) (uint32, error) {
+	return c.request(sshFxpRead, handle, offset, length)
+}
+
+// readData returns the data read by the request id, or io.EOF if the read started at the end of the file
+func (c *sftpClient) readData(id uint32) ([]byte, error) {
+	p, err := c.wait(id)
+	if err != nil {
+		return nil, err
+	}
+	if p.typ != sshFxpData {
+		return nil, parseSFTPStatus(p)
+	}
+	data, _, ok := parseSFTPString(p.data)
+	if !ok {
+		return nil, errors.New("invalid SFTP data response")
+	}
+	return []byte(data), nil
+}
+
+// closeHandle closes the open file
+func (c *sftpClient) closeHandle(handle string) error {
+	p, err := c.call(sshFxpClose, handle)
+	if err != nil {
+		return err
+	}
+	if err := parseSFTPStatus(p); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+// Close ends the session and closes the connection
+func (c *sftpClient) Close() error {
+	if c.session != nil {
+		if err := c.session.Close(); err != nil && err != io.EOF {
+			klog.V(3).Infof("Closing the SFTP session: %v", err)
+		}
+	}
+	return c.sshClient.Close()
+}
+
+// parseSFTPStatus returns the error of an SSH_FXP_STATUS response, nil if the status is OK and io.EOF at the end of
+// the file
+func parseSFTPStatus(p *sftpPacket) error {
+	if p.typ != sshFxpStatus || len(p.data) < 4 {
+		return errors.Errorf("unexpected SFTP response type %d", p.typ)
+	}
+	code := binary.BigEndian.Uint32(p.data)
+	switch code {
+	case sshFxOK:
+		return nil
+	case sshFxEOF:
+		return io.EOF
+	}
+	msg, _, _ := parseSFTPString(p.data[4:])
+	return &sftpStatusError{code: code, msg: msg}
+}
+
+// parseSFTPString returns the string at the start of data, and the rest of data
+func parseSFTPString(data []byte) (string, []byte, bool) {
+	if len(data) < 4 {
+		return "", nil, false
+	}
+	length := binary.BigEndian.Uint32(data)
+	if uint64(len(data)-4) < uint64(length) {
+		return "", nil, false
+	}
+	return string(data[4 : 4+length]), data[4+length:], true
+}
@@ -0,0 +1,378 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"io"
+	"net"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+const (
+	sftpScheme = "sftp"
+	// sftpMaxResumes is the number of times an interrupted transfer is resumed
+	sftpMaxResumes = 5
+	// sftpReadSize is the length of a read request, the largest one all servers accept
+	sftpReadSize = 32 * 1024
+	// sftpMaxPendingReads is the number of read requests sent ahead of the data being consumed, hiding the round trips
+	sftpMaxPendingReads = 64
+)
+
+// may be overridden in tests
+var sftpResumeDelay = 5 * time.Second
+
+// SFTPDataSource is the struct containing the information needed to import from an SFTP data source.
+// Sequence of phases:
+// 1. Info -> Transfer (image to convert) or TransferFile (raw image)
+// 2. Transfer -> Convert
+type SFTPDataSource struct {
+	// SFTP end point
+	ep *url.URL
+	// Reader of the file, resuming interrupted transfers
+	sftpReader *sftpReader
+	// stack of readers
+	readers *FormatReaders
+	// The image file in scratch space.
+	url *url.URL
+}
+
+// NewSFTPDataSource creates a new instance of the SFTPDataSource. The user logs in with the private key or the
+// password of the secret mounted in credentialDir, to a server presenting the pinned hostKey.
+func NewSFTPDataSource(endpoint, credentialDir, hostKey string) (*SFTPDataSource, error) {
+	ep, err := ParseEndpoint(endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse endpoint %q", redactSourceURL(endpoint))
+	}
+	if ep.Scheme != sftpScheme {
+		return nil, errors.Errorf("invalid SFTP endpoint %q, expected an sftp url", redactSourceURL(endpoint))
+	}
+	config, err := createSFTPClientConfig(credentialDir, hostKey)
+	if err != nil {
+		return nil, err
+	}
+	address := ep.Host
+	if ep.Port() == "" {
+		address = net.JoinHostPort(ep.Hostname(), sftpDefaultPort)
+	}
+	reader, err := newSFTPReader(address, sftpPath(ep.Path), config)
+	if err != nil {
+		return nil, err
+	}
+	return &SFTPDataSource{
+		ep:         ep,
+		sftpReader: reader,
+	}, nil
+}
+
+// createSFTPClientConfig returns the SSH configuration logging in with the credentials in credentialDir, to a server
+// presenting the pinned hostKey
+func createSFTPClientConfig(credentialDir, hostKey string) (*ssh.ClientConfig, error) {
+	creds, err := readSFTPCredentials(credentialDir)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := creds.authMethods()
+	if err != nil {
+		return nil, err
+	}
+	hostKeyCallback, hostKeyAlgorithms, err := sftpHostKeyConfig(hostKey)
+	if err != nil {
+		return nil, err
+	}
+	return &ssh.ClientConfig{
+		User:              creds.username,
+		Auth:              auth,
+		HostKeyCallback:   hostKeyCallback,
+		HostKeyAlgorithms: hostKeyAlgorithms,
+		Timeout:           sftpDialTimeout,
+	}, nil
+}
+
+// sftpPath returns the path of the file on the server, relative to the home directory of the user if the path of the
+// URL starts with /~/
+func sftpPath(urlPath string) string {
+	if strings.HasPrefix(urlPath, "/~/") {
+		return strings.TrimPrefix(urlPath, "/~/")
+	}
+	return urlPath
+}
+
+// Info is called to get initial information about the data.
+func (sd *SFTPDataSource) Info() (ProcessingPhase, error) {
+	var err error
+	sd.readers, err = NewDiskFormatReaders(sd.sftpReader, sd.sftpReader.size)
+	if err != nil {
+		klog.Errorf("Error creating readers: %v", err)
+		return ProcessingPhaseError, err
+	}
+	sd.readers.CheckExtensionHint(sd.ep.Path)
+	if !sd.readers.Convert {
+		// Downloading a raw file, we can write that directly to the target.
+		return ProcessingPhaseTransferDataFile, nil
+	}
+	return ProcessingPhaseTransferScratch, nil
+}
+
+// Transfer is called to transfer the data from the source to a temporary location.
+func (sd *SFTPDataSource) Transfer(path string) (ProcessingPhase, error) {
+	file := filepath.Join(path, tempFile)
+	if err := CleanAll(file); err != nil {
+		return ProcessingPhaseError, err
+	}
+	size, err := util.GetAvailableSpace(path)
+	if err != nil || size <= 0 {
+		return ProcessingPhaseError, ErrInvalidPath
+	}
+	sd.readers.StartProgressUpdate()
+	if err := util.StreamDataToFile(sd.readers.TopReader(), file); err != nil {
+		return ProcessingPhaseError, err
+	}
+	// If we successfully wrote to the file, then the parse will succeed.
+	sd.url, _ = url.Parse(file)
+	return ProcessingPhaseConvert, nil
+}
+
+// TransferFile is called to transfer the data from the source to the passed in file.
+func (sd *SFTPDataSource) TransferFile(fileName string) (ProcessingPhase, error) {
+	if err := CleanAll(fileName); err != nil {
+		return ProcessingPhaseError, err
+	}
+	sd.readers.StartProgressUpdate()
+	if err := util.StreamDataToFile(sd.readers.TopReader(), fileName); err != nil {
+		return ProcessingPhaseError, err
+	}
+	return ProcessingPhaseResize, nil
+}
+
+// GetURL returns the url that the data processor can use when converting the data.
+func (sd *SFTPDataSource) GetURL() *url.URL {
+	return sd.url
+}
+
+func (sd *SFTPDataSource) formatReaders() *FormatReaders {
+	return sd.readers
+}
+
+// Close closes any readers or other open resources.
+func (sd *SFTPDataSource) Close() error {
+	if sd.readers != nil {
+		return sd.readers.Close()
+	}
+	if sd.sftpReader != nil {
+		return sd.sftpReader.Close()
+	}
+	return nil
+}
+
+// sftpPendingRead is a read request waiting for its data
+type sftpPendingRead struct {
+	id     uint32
+	offset uint64
+	length uint32
+}
+
+// sftpReader reads a file of an SFTP server, with up to sftpMaxPendingReads read requests in flight. An interrupted
+// transfer is resumed from the offset it reached, in a new session.
+type sftpReader struct {
+	address string
+	path    string
+	config  *ssh.ClientConfig
+	// size is the size of the file reported by the server, 0 if the server does not report it
+	size    uint64
+	offset  uint64
+	resumes int
+	client  *sftpClient
+	handle  string
+	// pending are the read requests sent, in the order of their offsets
+	pending []sftpPendingRead
+	// nextOffset is the offset of the next read request
+	nextOffset uint64
+	// requestedAll is set once the reads up to the end of the file are requested
+	requestedAll bool
+	buf          []byte
+}
+
+func newSFTPReader(address, path string, config *ssh.ClientConfig) (*sftpReader, error) {
+	r := &sftpReader{
+		address: address,
+		path:    path,
+		config:  config,
+	}
+	if err := r.connect(); err != nil {
+		return nil, err
+	}
+	size, err := r.client.size(r.handle)
+	if err != nil {
+		klog.Warningf("Could not get the size of %s from the SFTP server, the progress is not reported: %v", path, err)
+	}
+	r.size = size
+	return r, nil
+}
+
+// connect logs in to the server, and opens the file to read it from the offset
+func (r *sftpReader) connect() error {
+	client, err := dialSFTP(r.address, r.config)
+	if err != nil {
+		return err
+	}
+	handle, err := client.open(r.path)
+	if err != nil {
+		client.Close()
+		return err
+	}
+	r.client = client
+	r.handle = handle
+	r.pending = nil
+	r.nextOffset = r.offset
+	r.requestedAll = false
+	return nil
+}
+
+func (r *sftpReader) Read(p []byte) (int, error) {
+	for {
+		if len(r.buf) > 0 {
+			n := copy(p, r.buf)
+			r.buf = r.buf[n:]
+			r.offset += uint64(n)
+			return n, nil
+		}
+		if r.client == nil {
+			return 0, errors.Errorf("SFTP transfer of %s is closed", r.path)
+		}
+		data, err := r.next()
+		if err == nil {
+			r.buf = data
+			continue
+		}
+		if err == io.EOF {
+			if r.size == 0 || r.offset >= r.size {
+				return 0, io.EOF
+			}
+			err = io.ErrUnexpectedEOF
+		}
+		if isPermanentSFTPError(err) {
+			return 0, errors.Wrapf(err, "SFTP transfer of %s failed at offset %d", r.path, r.offset)
+		}
+		if resumeErr := r.resume(err); resumeErr != nil {
+			return 0, resumeErr
+		}
+	}
+}
+
+// next requests the reads ahead, and returns the data of the first one, io.EOF at the end of the file
+func (r *sftpReader) next() ([]byte, error) {
+	for !r.requestedAll && len(r.pending) < sftpMaxPendingReads {
+		length := uint32(sftpReadSize)
+		if r.size > 0 {
+			if r.nextOffset >= r.size {
+				r.requestedAll = true
+				break
+			}
+			if remaining := r.size - r.nextOffset; remaining < uint64(length) {
+				length = uint32(remaining)
+			}
+		}
+		id, err := r.client.read(r.handle, r.nextOffset, length)
+		if err != nil {
+			if len(r.pending) > 0 {
+				// the data of the pending reads may have arrived before the session failed
+				break
+			}
+			return nil, err
+		}
+		r.pending = append(r.pending, sftpPendingRead{id: id, offset: r.nextOffset, length: length})
+		r.nextOffset += uint64(length)
+	}
+	if len(r.pending) == 0 {
+		return nil, io.EOF
+	}
+	read := r.pending[0]
+	r.pending = r.pending[1:]
+	data, err := r.client.readData(read.id)
+	if err == io.EOF {
+		// the reads after the end of the file are not needed
+		r.requestedAll = true
+		for _, pending := range r.pending {
+			r.client.discard(pending.id)
+		}
+		r.pending = nil
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 || uint32(len(data)) > read.length {
+		return nil, errors.Errorf("invalid SFTP read of %d bytes at offset %d", len(data), read.offset)
+	}
+	if n := uint32(len(data)); n < read.length {
+		// servers may return less data than requested, the rest is requested before the reads following it
+		offset := read.offset + uint64(n)
+		id, err := r.client.read(r.handle, offset, read.length-n)
+		if err != nil {
+			return nil, err
+		}
+		r.pending = append([]sftpPendingRead{{id: id, offset: offset, length: read.length - n}}, r.pending...)
+	}
+	return data, nil
+}
+
+// resume reopens the file to read the rest of it after the transfer was interrupted by err
+func (r *sftpReader) resume(err error) error {
+	if r.resumes >= sftpMaxResumes {
+		return errors.Wrapf(err, "SFTP transfer of %s interrupted at offset %d, after %d resumes", r.path, r.offset, r.resumes)
+	}
+	r.resumes++
+	klog.Warningf("SFTP transfer of %s interrupted at offset %d, resuming: %v", r.path, r.offset, err)
+	r.closeSession()
+	time.Sleep(sftpResumeDelay)
+	if connectErr := r.connect(); connectErr != nil {
+		return errors.Wrapf(connectErr, "could not resume the SFTP transfer of %s interrupted by %v", r.path, err)
+	}
+	return nil
+}
+
+func (r *sftpReader) closeSession() {
+	if r.client == nil {
+		return
+	}
+	// the handle is closed with the session when reads are still pending
+	if len(r.pending) == 0 {
+		if err := r.client.closeHandle(r.handle); err != nil {
+			klog.V(3).Infof("Closing the SFTP file: %v", err)
+		}
+	}
+	if err := r.client.Close(); err != nil {
+		klog.V(3).Infof("Closing the SFTP session: %v", err)
+	}
+	r.client = nil
+	r.pending = nil
+}
+
+// Close closes the file and the session
+func (r *sftpReader) Close() error {
+	r.closeSession()
+	return nil
+}
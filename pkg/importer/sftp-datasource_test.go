@@ -0,0 +1,482 @@
+package importer
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/ssh"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+// mockSFTPServer serves the files of a directory over SFTP, to a user logging in with a password or a public key
+type mockSFTPServer struct {
+	listener      net.Listener
+	dir           string
+	hostKey       ssh.Signer
+	user          string
+	password      string
+	authorizedKey ssh.PublicKey
+	noSize        bool
+	// maxRead is the largest data returned by a read, the reads asking for more are short
+	maxRead int
+	// dropAfter is the number of bytes sent before the connection of the first drops sessions is closed
+	dropAfter int
+	drops     int
+	// stallAfter is the number of bytes sent before the reads of the first stalls sessions are not answered anymore
+	stallAfter int
+	stalls     int
+
+	mutex sync.Mutex
+	// opened are the paths of the opened files
+	opened []string
+	// firstReads are the offsets of the first read of each session
+	firstReads []uint64
+}
+
+func newMockSFTPServer(dir string) *mockSFTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).ToNot(HaveOccurred())
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+	hostKey, err := ssh.NewSignerFromKey(key)
+	Expect(err).ToNot(HaveOccurred())
+	return &mockSFTPServer{listener: listener, dir: dir, hostKey: hostKey}
+}
+
+func (s *mockSFTPServer) start() {
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if s.password != "" && conn.User() == s.user && string(password) == s.password {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("password rejected for %s", conn.User())
+		},
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if s.authorizedKey != nil && conn.User() == s.user && string(key.Marshal()) == string(s.authorizedKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("public key rejected for %s", conn.User())
+		},
+	}
+	config.AddHostKey(s.hostKey)
+	go func() {
+		for {
+			conn, err := s.listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn, config)
+		}
+	}()
+}
+
+func (s *mockSFTPServer) url(path string) string {
+	return fmt.Sprintf("sftp://%s/%s", s.listener.Addr().String(), path)
+}
+
+func (s *mockSFTPServer) authorizedHostKey() string {
+	return string(ssh.MarshalAuthorizedKey(s.hostKey.PublicKey()))
+}
+
+func (s *mockSFTPServer) close() {
+	s.listener.Close()
+}
+
+func (s *mockSFTPServer) serve(conn net.Conn, config *ssh.ServerConfig) {
+	defer conn.Close()
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			for req := range requests {
+				ok := req.Type == "subsystem" && len(req.Payload) > 4 && string(req.Payload[4:]) == "sftp"
+				req.Reply(ok, nil)
+				if ok {
+					go s.serveSFTP(conn, channel)
+				}
+			}
+		}()
+	}
+}
+
+func (s *mockSFTPServer) serveSFTP(conn net.Conn, channel ssh.Channel) {
+	defer channel.Close()
+	s.mutex.Lock()
+	drop := s.drops > 0
+	if drop {
+		s.drops--
+	}
+	stall := !drop && s.stalls > 0
+	if stall {
+		s.stalls--
+	}
+	s.mutex.Unlock()
+	files := map[string]*os.File{}
+	defer func() {
+		for _, file := range files {
+			file.Close()
+		}
+	}()
+	sent := 0
+	firstRead := true
+	for {
+		var header [5]byte
+		if _, err := io.ReadFull(channel, header[:]); err != nil {
+			return
+		}
+		data := make([]byte, binary.BigEndian.Uint32(header[:4])-1)
+		if _, err := io.ReadFull(channel, data); err != nil {
+			return
+		}
+		if header[4] == sshFxpInit {
+			s.reply(channel, sshFxpVersion, sftpVersion)
+			continue
+		}
+		id := binary.BigEndian.Uint32(data)
+		data = data[4:]
+		switch header[4] {
+		case sshFxpOpen:
+			path, _, _ := parseSFTPString(data)
+			s.mutex.Lock()
+			s.opened = append(s.opened, path)
+			s.mutex.Unlock()
+			file, err := os.Open(filepath.Join(s.dir, strings.TrimPrefix(path, "/")))
+			if err != nil {
+				s.reply(channel, sshFxpStatus, id, uint32(sshFxNoSuchFile), "no such file", "")
+				continue
+			}
+			handle := fmt.Sprintf("handle-%d", id)
+			files[handle] = file
+			s.reply(channel, sshFxpHandle, id, handle)
+		case sshFxpFstat:
+			handle, _, _ := parseSFTPString(data)
+			info, err := files[handle].Stat()
+			if err != nil || s.noSize {
+				s.reply(channel, sshFxpAttrs, id, uint32(0))
+				continue
+			}
+			s.reply(channel, sshFxpAttrs, id, uint32(sshFilexferAttrSize), uint64(info.Size()))
+		case sshFxpRead:
+			handle, rest, _ := parseSFTPString(data)
+			offset := binary.BigEndian.Uint64(rest)
+			length := int(binary.BigEndian.Uint32(rest[8:]))
+			if firstRead {
+				s.mutex.Lock()
+				s.firstReads = append(s.firstReads, offset)
+				s.mutex.Unlock()
+				firstRead = false
+			}
+			if s.maxRead > 0 && length > s.maxRead {
+				length = s.maxRead
+			}
+			buf := make([]byte, length)
+			n, err := files[handle].ReadAt(buf, int64(offset))
+			if n == 0 && err == io.EOF {
+				s.reply(channel, sshFxpStatus, id, uint32(sshFxEOF), "end of file", "")
+				continue
+			}
+			if drop && sent+n > s.dropAfter {
+				conn.Close()
+				return
+			}
+			if stall && sent+n > s.stallAfter {
+				continue
+			}
+			sent += n
+			s.reply(channel, sshFxpData, id, string(buf[:n]))
+		case sshFxpClose:
+			handle, _, _ := parseSFTPString(data)
+			if file, ok := files[handle]; ok {
+				file.Close()
+				delete(files, handle)
+			}
+			s.reply(channel, sshFxpStatus, id, uint32(sshFxOK), "", "")
+		default:
+			s.reply(channel, sshFxpStatus, id, uint32(8), "unsupported", "")
+		}
+	}
+}
+
+// reply sends a packet of type typ with the fields, uint32, uint64 or string
+func (s *mockSFTPServer) reply(w io.Writer, typ byte, fields ...interface{}) {
+	buf := []byte{0, 0, 0, 0, typ}
+	for _, field := range fields {
+		switch v := field.(type) {
+		case int:
+			buf = binary.BigEndian.AppendUint32(buf, uint32(v))
+		case uint32:
+			buf = binary.BigEndian.AppendUint32(buf, v)
+		case uint64:
+			buf = binary.BigEndian.AppendUint64(buf, v)
+		case string:
+			buf = binary.BigEndian.AppendUint32(buf, uint32(len(v)))
+			buf = append(buf, v...)
+		}
+	}
+	binary.BigEndian.PutUint32(buf, uint32(len(buf)-4))
+	w.Write(buf)
+}
+
+var _ = Describe("SFTP data source", func() {
+	var (
+		server         *mockSFTPServer
+		tmpDir         string
+		credentialDir  string
+		ds             *SFTPDataSource
+		origDelay      time.Duration
+		tinyCoreSize   int64
+		tinyCoreSource []byte
+	)
+
+	BeforeEach(func() {
+		var err error
+		origDelay = sftpResumeDelay
+		sftpResumeDelay = 0
+		tmpDir, err = os.MkdirTemp("", "scratch")
+		Expect(err).ToNot(HaveOccurred())
+		credentialDir, err = os.MkdirTemp("", "sftp-credentials")
+		Expect(err).ToNot(HaveOccurred())
+		tinyCoreSource, err = os.ReadFile(filepath.Join(imageDir, tinyCoreFileName))
+		Expect(err).ToNot(HaveOccurred())
+		tinyCoreSize = int64(len(tinyCoreSource))
+		server = newMockSFTPServer(imageDir)
+		server.user, server.password = "user", "password"
+		ds = nil
+	})
+
+	AfterEach(func() {
+		sftpResumeDelay = origDelay
+		if ds != nil {
+			ds.Close()
+		}
+		server.close()
+		os.RemoveAll(tmpDir)
+		os.RemoveAll(credentialDir)
+	})
+
+	writeCredentials := func(keys map[string]string) {
+		for key, value := range keys {
+			Expect(os.WriteFile(filepath.Join(credentialDir, key), []byte(value), 0600)).To(Succeed())
+		}
+	}
+
+	transferTinyCore := func(hostKey string) error {
+		var err error
+		ds, err = NewSFTPDataSource(server.url(tinyCoreFileName), credentialDir, hostKey)
+		if err != nil {
+			return err
+		}
+		phase, err := ds.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferDataFile))
+		_, err = ds.TransferFile(filepath.Join(tmpDir, "disk.img"))
+		return err
+	}
+
+	expectTinyCoreImported := func() {
+		imported, err := os.ReadFile(filepath.Join(tmpDir, "disk.img"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(imported).To(Equal(tinyCoreSource))
+	}
+
+	It("should import a raw image with a password and report its size", func() {
+		server.start()
+		writeCredentials(map[string]string{common.KeySFTPUsername: "user\n", common.KeySFTPPassword: "password\n"})
+		Expect(transferTinyCore(server.authorizedHostKey())).To(Succeed())
+		Expect(ds.sftpReader.size).To(Equal(uint64(tinyCoreSize)))
+		expectTinyCoreImported()
+		Expect(server.opened).To(Equal([]string{"/" + tinyCoreFileName}))
+	})
+
+	It("should transfer a qcow2 image to scratch space with a private key", func() {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		Expect(err).ToNot(HaveOccurred())
+		signer, err := ssh.NewSignerFromKey(key)
+		Expect(err).ToNot(HaveOccurred())
+		server.password = ""
+		server.authorizedKey = signer.PublicKey()
+		server.start()
+		writeCredentials(map[string]string{
+			common.KeySFTPUsername:   "user",
+			common.KeySFTPPrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		})
+		ds, err = NewSFTPDataSource(server.url(cirrosFileName), credentialDir, server.authorizedHostKey())
+		Expect(err).ToNot(HaveOccurred())
+		phase, err := ds.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferScratch))
+		phase, err = ds.Transfer(tmpDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseConvert))
+		Expect(ds.GetURL().String()).To(Equal(filepath.Join(tmpDir, tempFile)))
+	})
+
+	It("should log in with a private key encrypted with the passphrase", func() {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+		der, err := x509.MarshalECPrivateKey(key)
+		Expect(err).ToNot(HaveOccurred())
+		block, err := x509.EncryptPEMBlock(rand.Reader, "EC PRIVATE KEY", der, []byte("secret phrase"), x509.PEMCipherAES256) //nolint:staticcheck // legacy encrypted keys are still in use
+		Expect(err).ToNot(HaveOccurred())
+		signer, err := ssh.NewSignerFromKey(key)
+		Expect(err).ToNot(HaveOccurred())
+		server.password = ""
+		server.authorizedKey = signer.PublicKey()
+		server.start()
+		writeCredentials(map[string]string{
+			common.KeySFTPUsername:   "user",
+			common.KeySFTPPrivateKey: string(pem.EncodeToMemory(block)),
+			common.KeySFTPPassphrase: "secret phrase\n",
+		})
+		Expect(transferTinyCore(server.authorizedHostKey())).To(Succeed())
+		expectTinyCoreImported()
+	})
+
+	It("should accept a server presenting the pinned host key fingerprint", func() {
+		server.start()
+		writeCredentials(map[string]string{common.KeySFTPUsername: "user", common.KeySFTPPassword: "password"})
+		Expect(transferTinyCore(ssh.FingerprintSHA256(server.hostKey.PublicKey()))).To(Succeed())
+		expectTinyCoreImported()
+	})
+
+	It("should reject a server presenting another host key", func() {
+		server.start()
+		writeCredentials(map[string]string{common.KeySFTPUsername: "user", common.KeySFTPPassword: "password"})
+		other := newMockSFTPServer(imageDir)
+		defer other.close()
+		err := transferTinyCore(other.authorizedHostKey())
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("does not match the pinned key"))
+		err = transferTinyCore(ssh.FingerprintSHA256(other.hostKey.PublicKey()))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("does not match the pinned fingerprint"))
+		Expect(server.opened).To(BeEmpty())
+	})
+
+	It("should refuse to connect without a pinned host key", func() {
+		server.start()
+		writeCredentials(map[string]string{common.KeySFTPUsername: "user", common.KeySFTPPassword: "password"})
+		err := transferTinyCore("")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("not pinned"))
+	})
+
+	It("should fail to log in with a wrong password", func() {
+		server.start()
+		writeCredentials(map[string]string{common.KeySFTPUsername: "user", common.KeySFTPPassword: "wrong"})
+		err := transferTinyCore(server.authorizedHostKey())
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("SSH login to the SFTP server"))
+	})
+
+	It("should fail without the user in the secret", func() {
+		server.start()
+		writeCredentials(map[string]string{common.KeySFTPPassword: "password"})
+		err := transferTinyCore(server.authorizedHostKey())
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("needs a username"))
+	})
+
+	It("should fail to open a missing file", func() {
+		server.start()
+		writeCredentials(map[string]string{common.KeySFTPUsername: "user", common.KeySFTPPassword: "password"})
+		_, err := NewSFTPDataSource(server.url("missing.img"), credentialDir, server.authorizedHostKey())
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("could not open /missing.img"))
+		Expect(isPermanentSFTPError(err)).To(BeTrue())
+	})
+
+	It("should open a path relative to the home directory", func() {
+		server.start()
+		writeCredentials(map[string]string{common.KeySFTPUsername: "user", common.KeySFTPPassword: "password"})
+		var err error
+		ds, err = NewSFTPDataSource(server.url("~/"+tinyCoreFileName), credentialDir, server.authorizedHostKey())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(server.opened).To(Equal([]string{tinyCoreFileName}))
+	})
+
+	It("should import with short reads and without the size", func() {
+		server.maxRead = 10000
+		server.noSize = true
+		server.start()
+		writeCredentials(map[string]string{common.KeySFTPUsername: "user", common.KeySFTPPassword: "password"})
+		Expect(transferTinyCore(server.authorizedHostKey())).To(Succeed())
+		Expect(ds.sftpReader.size).To(BeZero())
+		expectTinyCoreImported()
+	})
+
+	It("should drop the responses to the reads requested after the end of the file", func() {
+		server.noSize = true
+		server.start()
+		writeCredentials(map[string]string{common.KeySFTPUsername: "user", common.KeySFTPPassword: "password"})
+		Expect(transferTinyCore(server.authorizedHostKey())).To(Succeed())
+		Expect(ds.sftpReader.pending).To(BeEmpty())
+		client := ds.sftpReader.client
+		Expect(client.closeHandle(ds.sftpReader.handle)).To(Succeed())
+		Expect(client.responses).To(BeEmpty())
+		Expect(client.discarded).To(BeEmpty())
+	})
+
+	It("should resume a transfer the server stopped answering", func() {
+		origIdleTimeout := sftpIdleTimeout
+		sftpIdleTimeout = 200 * time.Millisecond
+		defer func() { sftpIdleTimeout = origIdleTimeout }()
+		server.stallAfter = 3 * sftpReadSize
+		server.stalls = 1
+		server.start()
+		writeCredentials(map[string]string{common.KeySFTPUsername: "user", common.KeySFTPPassword: "password"})
+		Expect(transferTinyCore(server.authorizedHostKey())).To(Succeed())
+		expectTinyCoreImported()
+		Expect(ds.sftpReader.resumes).To(Equal(1))
+		Expect(server.firstReads).To(Equal([]uint64{0, 3 * sftpReadSize}))
+	})
+
+	It("should resume an interrupted transfer from the offset it reached", func() {
+		server.dropAfter = 3 * sftpReadSize
+		server.drops = 2
+		server.start()
+		writeCredentials(map[string]string{common.KeySFTPUsername: "user", common.KeySFTPPassword: "password"})
+		Expect(transferTinyCore(server.authorizedHostKey())).To(Succeed())
+		expectTinyCoreImported()
+		Expect(server.firstReads).To(Equal([]uint64{0, 3 * sftpReadSize, 6 * sftpReadSize}))
+	})
+
+	It("should fail an interrupted transfer after the maximum number of resumes", func() {
+		server.dropAfter = 3 * sftpReadSize
+		server.drops = sftpMaxResumes + 1
+		server.start()
+		writeCredentials(map[string]string{common.KeySFTPUsername: "user", common.KeySFTPPassword: "password"})
+		err := transferTinyCore(server.authorizedHostKey())
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("after %d resumes", sftpMaxResumes)))
+	})
+})
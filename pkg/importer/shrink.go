@@ -0,0 +1,267 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/system"
+)
+
+const (
+	// shrinkHeadroomPercent is the free space kept in the shrunk filesystem, in percent of its used space
+	shrinkHeadroomPercent = 10
+	// shrinkAlignment is the alignment of the shrunk filesystem and image sizes
+	shrinkAlignment = 1024 * 1024
+
+	sectorSize          = 512
+	mbrPartitionTable   = 446
+	mbrPartitionSize    = 16
+	mbrPartitionCount   = 4
+	mbrTypeGPT          = 0xee
+	mbrTypeLinux        = 0x83
+	ext4SuperblockStart = 1024
+	ext4SuperblockSize  = 1024
+	ext4Magic           = 0xef53
+	ext4StateClean      = 0x1
+	ext4StateErrors     = 0x2
+	ext4IncompatRecover = 0x4
+	ext4Incompat64Bit   = 0x80
+)
+
+// may be overridden in tests
+var shrinkExecFunction = system.ExecWithLimits
+
+// shrinkToolsAvailable returns true if the e2fsprogs tools checking and shrinking the filesystem are installed, may be
+// overridden in tests
+var shrinkToolsAvailable = func() bool {
+	for _, tool := range []string{"e2fsck", "resize2fs"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+var resize2fsMinimumMatch = regexp.MustCompile(`minimum size of the filesystem: (\d+)`)
+
+// shrinkableImage is the layout of a raw image holding a single ext4 filesystem, either on the whole disk or in the
+// only partition of an MBR partition table
+type shrinkableImage struct {
+	path string
+	// partition is the index of the filesystem partition in the MBR partition table, -1 without partition table
+	partition int
+	// offset is the offset in bytes of the filesystem in the image
+	offset     int64
+	blockSize  int64
+	blockCount uint64
+	freeBlocks uint64
+}
+
+// shrinkImage shrinks the filesystem of the raw image to its used space plus headroom, then trims the image after
+// it. The image is left untouched with a warning if its layout is not safely shrinkable.
+func shrinkImage(path string) error {
+	img, err := inspectShrinkableImage(path)
+	if err != nil {
+		klog.Warningf("Not shrinking the image: %v", err)
+		return nil
+	}
+	if _, err := shrinkExecFunction(nil, nil, "e2fsck", "-f", "-n", img.device()); err != nil {
+		klog.Warningf("Not shrinking the image, its filesystem did not pass the check: %v", err)
+		return nil
+	}
+	targetBlocks, err := img.targetBlockCount()
+	if err != nil {
+		klog.Warningf("Not shrinking the image: %v", err)
+		return nil
+	}
+	if targetBlocks >= img.blockCount {
+		klog.V(1).Infof("Not shrinking the image, its filesystem of %d blocks is not larger than %d blocks", img.blockCount, targetBlocks)
+		return nil
+	}
+
+	klog.V(1).Infof("Shrinking the filesystem of the image from %d to %d blocks", img.blockCount, targetBlocks)
+	if _, err := shrinkExecFunction(nil, nil, "resize2fs", "-f", img.device(), strconv.FormatUint(targetBlocks, 10)); err != nil {
+		return errors.Wrap(err, "could not shrink the filesystem")
+	}
+	if err := img.readSuperblock(); err != nil {
+		return err
+	}
+	fsSize := int64(img.blockCount) * img.blockSize
+	if img.partition >= 0 {
+		if err := img.setPartitionSectors(uint32(alignUp(fsSize, sectorSize) / sectorSize)); err != nil {
+			return err
+		}
+	}
+	imageSize := alignUp(img.offset+fsSize, shrinkAlignment)
+	if err := os.Truncate(path, imageSize); err != nil {
+		return errors.Wrap(err, "could not trim the image")
+	}
+	klog.V(1).Infof("Shrunk the image to %d bytes", imageSize)
+	return nil
+}
+
+// inspectShrinkableImage returns the layout of the raw image, or an error telling why it is not safely shrinkable
+func inspectShrinkableImage(path string) (*shrinkableImage, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open the image")
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not stat the image")
+	}
+
+	img := &shrinkableImage{path: path, partition: -1}
+	mbr := make([]byte, sectorSize)
+	if _, err := f.ReadAt(mbr, 0); err != nil {
+		return nil, errors.Wrap(err, "could not read the partition table")
+	}
+	if mbr[510] == 0x55 && mbr[511] == 0xaa && !hasExt4Magic(f, 0) {
+		for i := 0; i < mbrPartitionCount; i++ {
+			entry := mbr[mbrPartitionTable+i*mbrPartitionSize : mbrPartitionTable+(i+1)*mbrPartitionSize]
+			switch partitionType := entry[4]; {
+			case partitionType == 0:
+				continue
+			case partitionType == mbrTypeGPT:
+				return nil, errors.New("GPT partition tables are not supported")
+			case img.partition >= 0:
+				return nil, errors.New("the image has more than one partition")
+			case partitionType != mbrTypeLinux:
+				return nil, errors.Errorf("partition type 0x%02x is not supported", partitionType)
+			}
+			img.partition = i
+			img.offset = int64(binary.LittleEndian.Uint32(entry[8:12])) * sectorSize
+			end := img.offset + int64(binary.LittleEndian.Uint32(entry[12:16]))*sectorSize
+			if end > stat.Size() {
+				return nil, errors.New("the partition ends after the image")
+			}
+		}
+		if img.partition < 0 {
+			return nil, errors.New("the partition table is empty")
+		}
+	}
+
+	magic := make([]byte, 4)
+	if _, err := f.ReadAt(magic, img.offset); err != nil {
+		return nil, errors.Wrap(err, "could not read the filesystem")
+	}
+	if bytes.Equal(magic, []byte("XFSB")) {
+		return nil, errors.New("XFS filesystems cannot be shrunk")
+	}
+	if !hasExt4Magic(f, img.offset) {
+		return nil, errors.New("only ext4 filesystems are supported")
+	}
+	if err := img.readSuperblock(); err != nil {
+		return nil, err
+	}
+	return img, nil
+}
+
+// readSuperblock reads the block size and counts of the filesystem, and checks it was cleanly unmounted
+func (img *shrinkableImage) readSuperblock() error {
+	f, err := os.Open(img.path)
+	if err != nil {
+		return errors.Wrap(err, "could not open the image")
+	}
+	defer f.Close()
+	sb := make([]byte, ext4SuperblockSize)
+	if _, err := f.ReadAt(sb, img.offset+ext4SuperblockStart); err != nil {
+		return errors.Wrap(err, "could not read the filesystem superblock")
+	}
+	state := binary.LittleEndian.Uint16(sb[0x3a:])
+	incompat := binary.LittleEndian.Uint32(sb[0x60:])
+	if state&ext4StateClean == 0 || state&ext4StateErrors != 0 || incompat&ext4IncompatRecover != 0 {
+		return errors.New("the filesystem was not cleanly unmounted")
+	}
+	img.blockSize = 1024 << binary.LittleEndian.Uint32(sb[0x18:])
+	img.blockCount = uint64(binary.LittleEndian.Uint32(sb[0x04:]))
+	img.freeBlocks = uint64(binary.LittleEndian.Uint32(sb[0x0c:]))
+	if incompat&ext4Incompat64Bit != 0 {
+		img.blockCount |= uint64(binary.LittleEndian.Uint32(sb[0x150:])) << 32
+		img.freeBlocks |= uint64(binary.LittleEndian.Uint32(sb[0x158:])) << 32
+	}
+	return nil
+}
+
+// targetBlockCount returns the block count of the filesystem shrunk to its used space plus headroom, not smaller
+// than the minimum resize2fs estimates
+func (img *shrinkableImage) targetBlockCount() (uint64, error) {
+	used := img.blockCount - img.freeBlocks
+	target := used + used*shrinkHeadroomPercent/100
+	alignment := uint64(shrinkAlignment / img.blockSize)
+	target = (target + alignment - 1) / alignment * alignment
+
+	output, err := shrinkExecFunction(nil, nil, "resize2fs", "-P", img.device())
+	if err != nil {
+		return 0, errors.Wrap(err, "could not estimate the minimum size of the filesystem")
+	}
+	matches := resize2fsMinimumMatch.FindSubmatch(output)
+	if matches == nil {
+		return 0, errors.New("could not estimate the minimum size of the filesystem")
+	}
+	minimum, err := strconv.ParseUint(string(matches[1]), 10, 64)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not estimate the minimum size of the filesystem")
+	}
+	if target < minimum {
+		target = minimum
+	}
+	return target, nil
+}
+
+// setPartitionSectors sets the size in sectors of the filesystem partition in the MBR partition table
+func (img *shrinkableImage) setPartitionSectors(sectors uint32) error {
+	f, err := os.OpenFile(img.path, os.O_WRONLY, 0)
+	if err != nil {
+		return errors.Wrap(err, "could not open the image")
+	}
+	defer f.Close()
+	size := make([]byte, 4)
+	binary.LittleEndian.PutUint32(size, sectors)
+	if _, err := f.WriteAt(size, int64(mbrPartitionTable+img.partition*mbrPartitionSize+12)); err != nil {
+		return errors.Wrap(err, "could not update the partition table")
+	}
+	return f.Sync()
+}
+
+// device returns the filesystem as understood by e2fsprogs, with the offset of its partition
+func (img *shrinkableImage) device() string {
+	return fmt.Sprintf("%s?offset=%d", img.path, img.offset)
+}
+
+func hasExt4Magic(f *os.File, offset int64) bool {
+	magic := make([]byte, 2)
+	if _, err := f.ReadAt(magic, offset+ext4SuperblockStart+0x38); err != nil {
+		return false
+	}
+	return binary.LittleEndian.Uint16(magic) == ext4Magic
+}
+
+func alignUp(size, alignment int64) int64 {
+	return (size + alignment - 1) / alignment * alignment
+}
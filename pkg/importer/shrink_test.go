@@ -0,0 +1,235 @@
+package importer
+
+import (
+	"encoding/binary"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/pkg/errors"
+
+	"kubevirt.io/containerized-data-importer/pkg/system"
+)
+
+const (
+	testShrinkImageSize = 100 * 1024 * 1024
+	testShrinkBlockSize = 4096
+	testShrinkOffset    = 1024 * 1024
+)
+
+type testPartition struct {
+	partitionType byte
+	offset        int64
+	size          int64
+}
+
+var _ = Describe("Shrink image", func() {
+	var (
+		tmpDir        string
+		imagePath     string
+		commands      []string
+		e2fsckErr     error
+		minimumBlocks uint64
+		origExec      = shrinkExecFunction
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "shrink")
+		Expect(err).ToNot(HaveOccurred())
+		imagePath = filepath.Join(tmpDir, "disk.img")
+		commands = nil
+		e2fsckErr = nil
+		minimumBlocks = 1000
+		shrinkExecFunction = func(_ *system.ProcessLimitValues, _ func(string), command string, args ...string) ([]byte, error) {
+			commands = append(commands, command+" "+strings.Join(args, " "))
+			switch {
+			case command == "e2fsck":
+				return nil, e2fsckErr
+			case command == "resize2fs" && args[0] == "-P":
+				return []byte("Estimated minimum size of the filesystem: " + strconv.FormatUint(minimumBlocks, 10) + "\n"), nil
+			case command == "resize2fs":
+				// Simulate resize2fs updating the block count of the filesystem
+				device := strings.SplitN(args[1], "?offset=", 2)
+				offset, _ := strconv.ParseInt(device[1], 10, 64)
+				blocks, _ := strconv.ParseUint(args[2], 10, 32)
+				writeTestExt4Superblock(device[0], offset, blocks, 0, ext4StateClean)
+			}
+			return nil, nil
+		}
+	})
+
+	AfterEach(func() {
+		shrinkExecFunction = origExec
+		os.RemoveAll(tmpDir)
+	})
+
+	createImage := func(partitions ...testPartition) {
+		Expect(os.WriteFile(imagePath, nil, 0644)).To(Succeed())
+		Expect(os.Truncate(imagePath, testShrinkImageSize)).To(Succeed())
+		if len(partitions) == 0 {
+			return
+		}
+		mbr := make([]byte, sectorSize)
+		for i, p := range partitions {
+			entry := mbr[mbrPartitionTable+i*mbrPartitionSize:]
+			entry[4] = p.partitionType
+			binary.LittleEndian.PutUint32(entry[8:], uint32(p.offset/sectorSize))
+			binary.LittleEndian.PutUint32(entry[12:], uint32(p.size/sectorSize))
+		}
+		mbr[510], mbr[511] = 0x55, 0xaa
+		f, err := os.OpenFile(imagePath, os.O_WRONLY, 0)
+		Expect(err).ToNot(HaveOccurred())
+		defer f.Close()
+		_, err = f.WriteAt(mbr, 0)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	linuxPartition := testPartition{partitionType: mbrTypeLinux, offset: testShrinkOffset, size: testShrinkImageSize - testShrinkOffset}
+	partitionBlocks := uint64((testShrinkImageSize - testShrinkOffset) / testShrinkBlockSize)
+	usedBlocks := uint64(10 * 1024 * 1024 / testShrinkBlockSize)
+
+	getImageSize := func() int64 {
+		stat, err := os.Stat(imagePath)
+		Expect(err).ToNot(HaveOccurred())
+		return stat.Size()
+	}
+
+	It("should shrink the filesystem of the only partition and trim the image after it", func() {
+		createImage(linuxPartition)
+		writeTestExt4Superblock(imagePath, testShrinkOffset, partitionBlocks, partitionBlocks-usedBlocks, ext4StateClean)
+		Expect(shrinkImage(imagePath)).To(Succeed())
+
+		device := imagePath + "?offset=1048576"
+		// 10MiB used plus 10% headroom, aligned to 1MiB
+		Expect(commands).To(Equal([]string{
+			"e2fsck -f -n " + device,
+			"resize2fs -P " + device,
+			"resize2fs -f " + device + " 2816",
+		}))
+		img, err := inspectShrinkableImage(imagePath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(img.blockCount).To(Equal(uint64(2816)))
+		mbr, err := os.ReadFile(imagePath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(binary.LittleEndian.Uint32(mbr[mbrPartitionTable+12:])).To(Equal(uint32(11 * 1024 * 1024 / sectorSize)))
+		Expect(getImageSize()).To(Equal(int64(12 * 1024 * 1024)))
+	})
+
+	It("should shrink a filesystem spanning the whole image", func() {
+		createImage()
+		writeTestExt4Superblock(imagePath, 0, testShrinkImageSize/testShrinkBlockSize, testShrinkImageSize/testShrinkBlockSize-usedBlocks, ext4StateClean)
+		Expect(shrinkImage(imagePath)).To(Succeed())
+		Expect(commands).To(ContainElement("resize2fs -f " + imagePath + "?offset=0 2816"))
+		Expect(getImageSize()).To(Equal(int64(11 * 1024 * 1024)))
+	})
+
+	It("should not shrink the filesystem below the minimum size estimated by resize2fs", func() {
+		minimumBlocks = 5000
+		createImage(linuxPartition)
+		writeTestExt4Superblock(imagePath, testShrinkOffset, partitionBlocks, partitionBlocks-usedBlocks, ext4StateClean)
+		Expect(shrinkImage(imagePath)).To(Succeed())
+		Expect(commands).To(ContainElement("resize2fs -f " + imagePath + "?offset=1048576 5000"))
+		Expect(getImageSize()).To(Equal(int64(21 * 1024 * 1024)))
+	})
+
+	It("should fail when resize2fs fails", func() {
+		createImage(linuxPartition)
+		writeTestExt4Superblock(imagePath, testShrinkOffset, partitionBlocks, partitionBlocks-usedBlocks, ext4StateClean)
+		shrinkExecFunction = func(_ *system.ProcessLimitValues, _ func(string), command string, args ...string) ([]byte, error) {
+			if command == "resize2fs" && args[0] == "-f" {
+				return nil, errors.New("resize2fs failed")
+			}
+			return []byte("Estimated minimum size of the filesystem: 1000\n"), nil
+		}
+		err := shrinkImage(imagePath)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("could not shrink the filesystem"))
+		Expect(getImageSize()).To(Equal(int64(testShrinkImageSize)))
+	})
+
+	table.DescribeTable("should leave an image that is not safely shrinkable untouched", func(setup func(), reason string) {
+		setup()
+		if reason != "" {
+			_, err := inspectShrinkableImage(imagePath)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(reason))
+		}
+		Expect(shrinkImage(imagePath)).To(Succeed())
+		for _, command := range commands {
+			Expect(command).ToNot(HavePrefix("resize2fs -f"))
+		}
+		Expect(getImageSize()).To(Equal(int64(testShrinkImageSize)))
+	},
+		table.Entry("with an XFS filesystem", func() {
+			createImage(linuxPartition)
+			f, err := os.OpenFile(imagePath, os.O_WRONLY, 0)
+			Expect(err).ToNot(HaveOccurred())
+			defer f.Close()
+			_, err = f.WriteAt([]byte("XFSB"), testShrinkOffset)
+			Expect(err).ToNot(HaveOccurred())
+		}, "XFS filesystems cannot be shrunk"),
+		table.Entry("with an unknown filesystem", func() {
+			createImage(linuxPartition)
+		}, "only ext4 filesystems are supported"),
+		table.Entry("with a GPT partition table", func() {
+			createImage(testPartition{partitionType: mbrTypeGPT, offset: sectorSize, size: testShrinkImageSize - sectorSize})
+		}, "GPT partition tables are not supported"),
+		table.Entry("with several partitions", func() {
+			createImage(testPartition{partitionType: mbrTypeLinux, offset: testShrinkOffset, size: testShrinkOffset},
+				testPartition{partitionType: mbrTypeLinux, offset: 2 * testShrinkOffset, size: testShrinkImageSize - 2*testShrinkOffset})
+		}, "more than one partition"),
+		table.Entry("with a non Linux partition", func() {
+			createImage(testPartition{partitionType: 0x07, offset: testShrinkOffset, size: testShrinkImageSize - testShrinkOffset})
+		}, "partition type 0x07 is not supported"),
+		table.Entry("with a filesystem that was not cleanly unmounted", func() {
+			createImage(linuxPartition)
+			writeTestExt4Superblock(imagePath, testShrinkOffset, partitionBlocks, partitionBlocks-usedBlocks, 0)
+		}, "not cleanly unmounted"),
+		table.Entry("with a filesystem failing the check", func() {
+			createImage(linuxPartition)
+			writeTestExt4Superblock(imagePath, testShrinkOffset, partitionBlocks, partitionBlocks-usedBlocks, ext4StateClean)
+			e2fsckErr = errors.New("e2fsck failed")
+		}, ""),
+		table.Entry("with a filesystem that is already small enough", func() {
+			createImage(linuxPartition)
+			writeTestExt4Superblock(imagePath, testShrinkOffset, partitionBlocks, 1, ext4StateClean)
+		}, ""),
+	)
+
+	It("should shrink the image instead of resizing it in the resize phase", func() {
+		createImage()
+		writeTestExt4Superblock(imagePath, 0, testShrinkImageSize/testShrinkBlockSize, testShrinkImageSize/testShrinkBlockSize-usedBlocks, ext4StateClean)
+		url, err := url.Parse("http://fakeurl-notreal.fake")
+		Expect(err).ToNot(HaveOccurred())
+		dp := NewDataProcessor(&MockDataProvider{url: url}, imagePath, tmpDir, "scratchDataDir", "1G", 0.055, false)
+		dp.SetShrinkToUsedSize(true)
+		replaceQEMUOperations(NewFakeQEMUOperations(nil, errors.New("should not resize"), fakeInfoRet, nil, nil, nil), func() {
+			nextPhase, err := dp.resize()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nextPhase).To(Equal(ProcessingPhaseComplete))
+		})
+		Expect(getImageSize()).To(Equal(int64(11 * 1024 * 1024)))
+	})
+})
+
+// writeTestExt4Superblock writes the fields of an ext4 superblock the shrink reads, with 4KiB blocks
+func writeTestExt4Superblock(path string, offset int64, blockCount, freeBlocks uint64, state uint16) {
+	sb := make([]byte, ext4SuperblockSize)
+	binary.LittleEndian.PutUint32(sb[0x04:], uint32(blockCount))
+	binary.LittleEndian.PutUint32(sb[0x0c:], uint32(freeBlocks))
+	binary.LittleEndian.PutUint32(sb[0x18:], 2)
+	binary.LittleEndian.PutUint16(sb[0x38:], ext4Magic)
+	binary.LittleEndian.PutUint16(sb[0x3a:], state)
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	Expect(err).ToNot(HaveOccurred())
+	defer f.Close()
+	_, err = f.WriteAt(sb, offset+ext4SuperblockStart)
+	Expect(err).ToNot(HaveOccurred())
+}
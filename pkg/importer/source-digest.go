@@ -0,0 +1,74 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"hash"
+	"io"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+// digestReader hashes the source bytes as they are read, so the digest of the source is computed without another pass
+type digestReader struct {
+	io.ReadCloser
+	algorithm string
+	hash      hash.Hash
+	eof       bool
+}
+
+func newDigestReader(r io.ReadCloser, algorithm string) (*digestReader, error) {
+	var h hash.Hash
+	switch algorithm {
+	case common.SourceDigestSHA256:
+		h = sha256.New()
+	case common.SourceDigestSHA512:
+		h = sha512.New()
+	default:
+		return nil, errors.Errorf("unsupported source digest algorithm %q, should be %q or %q", algorithm, common.SourceDigestSHA256, common.SourceDigestSHA512)
+	}
+	return &digestReader{ReadCloser: r, algorithm: algorithm, hash: h}, nil
+}
+
+func (r *digestReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.hash.Write(p[:n])
+	if err == io.EOF {
+		r.eof = true
+	}
+	return n, err
+}
+
+// digest reads the source bytes not consumed by the import, such as the padding after the end of an archive, and
+// returns the digest of the whole source
+func (r *digestReader) digest() (*util.SourceDigest, error) {
+	if !r.eof {
+		n, err := io.Copy(io.Discard, r)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read the end of the source")
+		}
+		klog.V(3).Infof("Read the %d trailing bytes of the source for its digest", n)
+	}
+	return &util.SourceDigest{Algorithm: r.algorithm, Value: hex.EncodeToString(r.hash.Sum(nil))}, nil
+}
@@ -0,0 +1,110 @@
+package importer
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/image"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+const (
+	tinyCoreSHA256   = "11d74aa12309da7240f171c140394729bb9b407e8fa3cb52c6dcbf7009352fab"
+	tinyCoreSHA512   = "496f917365499065bc111070f1371138900203e63a71cf4eb83a1a3b685faded33a5213601cf275c70c006f8c8eaa218866bf829682d7e50e7dd82c4fef406b2"
+	tinyCoreXzSHA256 = "37f80de3e7ec66dba10d42226e99a0f12af1128aa1082914893a6e53d5ab7194"
+	cirrosSHA256     = "a8dd75ecffd4cdd96072d60c2237b448e0c8b2bc94d57f10fdbc8c481d9005b8"
+)
+
+var _ = Describe("Source digest", func() {
+	var fr *FormatReaders
+
+	BeforeEach(func() {
+		fr = nil
+	})
+
+	AfterEach(func() {
+		sourceDigestAlgorithm = ""
+		if fr != nil {
+			fr.Close()
+		}
+	})
+
+	newFormatReaders := func(fileName, algorithm string) {
+		f, err := os.Open(filepath.Join(imageDir, fileName))
+		Expect(err).ToNot(HaveOccurred())
+		sourceDigestAlgorithm = algorithm
+		fr, err = NewFormatReaders(f, uint64(0))
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	table.DescribeTable("should compute the digest of the source bytes read through the readers of", func(fileName, algorithm, expectedDigest string) {
+		newFormatReaders(fileName, algorithm)
+		Expect(fr.HashesSource()).To(BeTrue())
+		_, err := io.Copy(io.Discard, fr.TopReader())
+		Expect(err).ToNot(HaveOccurred())
+		digest, err := fr.SourceDigest()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(digest).To(Equal(&util.SourceDigest{Algorithm: algorithm, Value: expectedDigest}))
+	},
+		table.Entry("a raw image", tinyCoreFileName, "sha256", tinyCoreSHA256),
+		table.Entry("a raw image with sha512", tinyCoreFileName, "sha512", tinyCoreSHA512),
+		table.Entry("an xz compressed image", "tinyCore.iso.xz", "sha256", tinyCoreXzSHA256),
+		table.Entry("a qcow2 image", cirrosFileName, "sha256", cirrosSHA256),
+	)
+
+	It("should read the source bytes not read by the import to compute the digest", func() {
+		newFormatReaders(tinyCoreFileName, "sha256")
+		digest, err := fr.SourceDigest()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(digest.String()).To(Equal("sha256:" + tinyCoreSHA256))
+	})
+
+	It("should not compute a digest when none is requested", func() {
+		newFormatReaders(tinyCoreFileName, "")
+		Expect(fr.HashesSource()).To(BeFalse())
+		digest, err := fr.SourceDigest()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(digest).To(BeNil())
+	})
+
+	It("should fail with an unsupported algorithm", func() {
+		f, err := os.Open(filepath.Join(imageDir, tinyCoreFileName))
+		Expect(err).ToNot(HaveOccurred())
+		defer f.Close()
+		sourceDigestAlgorithm = "md5"
+		_, err = NewFormatReaders(f, uint64(0))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`unsupported source digest algorithm "md5"`))
+	})
+
+	It("should transfer an HTTP source to scratch space to compute its digest", func() {
+		createNbdkitCurl = image.NewMockNbdkitCurl
+		ts := createTestServer(imageDir)
+		defer ts.Close()
+		tmpDir, err := os.MkdirTemp("", "scratch")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(tmpDir)
+
+		sourceDigestAlgorithm = "sha256"
+		dp, err := NewHTTPDataSource(ts.URL+"/"+cirrosFileName, "", "", "", cdiv1.DataVolumeKubeVirt)
+		Expect(err).ToNot(HaveOccurred())
+		defer dp.Close()
+		phase, err := dp.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferScratch))
+		phase, err = dp.Transfer(tmpDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseConvert))
+
+		processor := NewDataProcessor(dp, "dest", "dataDir", tmpDir, "1G", 0.055, false)
+		digest, err := processor.SourceDigest()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(digest.String()).To(Equal("sha256:" + cirrosSHA256))
+	})
+})
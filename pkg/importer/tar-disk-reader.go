@@ -0,0 +1,101 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"archive/tar"
+	"io"
+	"path"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// tarDiskReader unpacks the plain tar archive at the top of the reader stack, and returns a reader of its disk image
+// member: the member selected by tarDiskMember, or else the only member named like a disk image. Since the archive is
+// streamed, another disk image member following the imported one is only detected at the end of the archive, failing
+// the read then.
+func (fr *FormatReaders) tarDiskReader() (io.Reader, error) {
+	tarReader := tar.NewReader(fr.TopReader())
+	for {
+		hdr, err := tarReader.Next()
+		if err == io.EOF {
+			if tarDiskMember != "" {
+				return nil, errors.Errorf("the member %q is not in the tar archive", tarDiskMember)
+			}
+			return nil, errors.New("the tar archive has no disk image member")
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read the tar archive")
+		}
+		if !hdr.FileInfo().Mode().IsRegular() {
+			continue
+		}
+		if tarDiskMember != "" {
+			if path.Clean(hdr.Name) == path.Clean(tarDiskMember) {
+				klog.V(2).Infof("tar: extracting %q\n", hdr.Name)
+				return tarReader, nil
+			}
+			continue
+		}
+		if isTarDiskCandidate(hdr) {
+			klog.V(2).Infof("tar: extracting %q\n", hdr.Name)
+			return &tarDiskMemberReader{tarReader: tarReader, name: hdr.Name}, nil
+		}
+		klog.V(3).Infof("tar: skipping %q, not named like a disk image\n", hdr.Name)
+	}
+}
+
+// isTarDiskCandidate returns true if the tar member is named like a disk image, possibly compressed
+func isTarDiskCandidate(hdr *tar.Header) bool {
+	hint := parseExtensionHint(hdr.Name)
+	if hint == nil {
+		return false
+	}
+	for _, layer := range hint.layers {
+		if layer == "tar" {
+			return false
+		}
+	}
+	return true
+}
+
+// tarDiskMemberReader reads the disk image member of a tar archive, and checks the rest of the archive has no other
+// disk image member once the member is read
+type tarDiskMemberReader struct {
+	tarReader *tar.Reader
+	name      string
+}
+
+func (r *tarDiskMemberReader) Read(p []byte) (int, error) {
+	n, err := r.tarReader.Read(p)
+	if err != io.EOF {
+		return n, err
+	}
+	for {
+		hdr, nextErr := r.tarReader.Next()
+		if nextErr == io.EOF {
+			return n, io.EOF
+		}
+		if nextErr != nil {
+			return n, errors.Wrap(nextErr, "could not read the tar archive")
+		}
+		if hdr.FileInfo().Mode().IsRegular() && isTarDiskCandidate(hdr) {
+			return n, errors.Errorf("the tar archive has more than one disk image member, %q and %q, the member to import must be selected", r.name, hdr.Name)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package importer
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type tarMember struct {
+	name    string
+	content []byte
+}
+
+func tarMembers(members ...tarMember) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	writer := tar.NewWriter(buf)
+	for _, member := range members {
+		Expect(writer.WriteHeader(&tar.Header{Name: member.name, Mode: 0644, Size: int64(len(member.content))})).To(Succeed())
+		_, err := writer.Write(member.content)
+		Expect(err).ToNot(HaveOccurred())
+	}
+	Expect(writer.Close()).To(Succeed())
+	return buf
+}
+
+var _ = Describe("Tar disk reader", func() {
+	var (
+		fr              *FormatReaders
+		tinyCoreContent []byte
+		cirrosContent   []byte
+	)
+
+	BeforeEach(func() {
+		var err error
+		tinyCoreContent, err = os.ReadFile(tinyCoreFilePath)
+		Expect(err).ToNot(HaveOccurred())
+		cirrosContent, err = os.ReadFile(cirrosFilePath)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		tarDiskMember = ""
+		if fr != nil {
+			fr.Close()
+			fr = nil
+		}
+	})
+
+	It("should read the only disk image member of a tar archive", func() {
+		archive := tarMembers(tarMember{"README", []byte("tiny core")}, tarMember{"disk/tinyCore.iso", tinyCoreContent})
+		var err error
+		fr, err = NewDiskFormatReaders(io.NopCloser(archive), uint64(0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fr.Archived).To(BeTrue())
+		Expect(fr.Convert).To(BeFalse())
+		Expect(fr.CheckExtensionHint("/images/disk.tar")).To(BeTrue())
+
+		content, err := io.ReadAll(fr.TopReader())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bytes.Equal(content, tinyCoreContent)).To(BeTrue())
+	})
+
+	It("should convert the qcow2 member of a gzip compressed tar archive", func() {
+		compressed := &bytes.Buffer{}
+		gzipWriter := gzip.NewWriter(compressed)
+		_, err := io.Copy(gzipWriter, tarMembers(tarMember{"cirros.qcow2", cirrosContent}))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(gzipWriter.Close()).To(Succeed())
+
+		fr, err = NewDiskFormatReaders(io.NopCloser(compressed), uint64(0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fr.Archived).To(BeTrue())
+		Expect(fr.Convert).To(BeTrue())
+		Expect(fr.ImageFormat()).To(Equal("qcow2"))
+		Expect(fr.CheckExtensionHint("/images/cirros.qcow2.tar.gz")).To(BeTrue())
+
+		content, err := io.ReadAll(fr.TopReader())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bytes.Equal(content, cirrosContent)).To(BeTrue())
+	})
+
+	It("should fail a tar archive with several disk image members when none is selected", func() {
+		archive := tarMembers(tarMember{"tinyCore.iso", tinyCoreContent}, tarMember{"cirros.qcow2", cirrosContent})
+		var err error
+		fr, err = NewDiskFormatReaders(io.NopCloser(archive), uint64(0))
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = io.ReadAll(fr.TopReader())
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`more than one disk image member, "tinyCore.iso" and "cirros.qcow2"`))
+	})
+
+	It("should read the selected member of a tar archive with several disk image members", func() {
+		tarDiskMember = "./disks/cirros.qcow2"
+		archive := tarMembers(tarMember{"disks/tinyCore.iso", tinyCoreContent}, tarMember{"disks/cirros.qcow2", cirrosContent})
+		var err error
+		fr, err = NewDiskFormatReaders(io.NopCloser(archive), uint64(0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fr.ImageFormat()).To(Equal("qcow2"))
+
+		content, err := io.ReadAll(fr.TopReader())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bytes.Equal(content, cirrosContent)).To(BeTrue())
+	})
+
+	It("should fail if the selected member is not in the tar archive", func() {
+		tarDiskMember = "missing.qcow2"
+		_, err := NewDiskFormatReaders(io.NopCloser(tarMembers(tarMember{"tinyCore.iso", tinyCoreContent})), uint64(0))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`the member "missing.qcow2" is not in the tar archive`))
+	})
+
+	It("should fail if the tar archive has no disk image member", func() {
+		_, err := NewDiskFormatReaders(io.NopCloser(tarMembers(tarMember{"README", tinyCoreContent})), uint64(0))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("the tar archive has no disk image member"))
+	})
+})
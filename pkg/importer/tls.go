@@ -0,0 +1,84 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"crypto/tls"
+	"os"
+	"strings"
+
+	ocpcrypto "github.com/openshift/library-go/pkg/crypto"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/image"
+	cryptowatch "kubevirt.io/containerized-data-importer/pkg/util/tls-crypto-watch"
+)
+
+// importTLS is the TLS configuration of the clients connecting to the import source, as injected by the import
+// controller from the CDIConfig and the annotations of the DataVolume.
+type importTLS struct {
+	minVersion uint16
+	ciphers    []string
+}
+
+// newImportTLSFromEnv returns the TLS configuration from the environment. Without it TLS 1.2 is the minimal version
+// and the cipher suites are not restricted.
+func newImportTLSFromEnv() (*importTLS, error) {
+	return newImportTLS(os.Getenv(common.MinVersionTLSVar), os.Getenv(common.CiphersTLSVar))
+}
+
+func newImportTLS(minVersion, ciphers string) (*importTLS, error) {
+	t := &importTLS{minVersion: tls.VersionTLS12}
+	if minVersion != "" {
+		version, err := ocpcrypto.TLSVersion(minVersion)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid minimal TLS version")
+		}
+		if version < tls.VersionTLS12 {
+			klog.Warningf("Connecting to the import source with the minimal TLS version %s, older than TLS 1.2", minVersion)
+		}
+		t.minVersion = version
+	}
+	for _, cipher := range strings.Split(ciphers, ",") {
+		if cipher = strings.TrimSpace(cipher); cipher != "" {
+			t.ciphers = append(t.ciphers, cipher)
+		}
+	}
+	return t, nil
+}
+
+// clientConfig returns the TLS configuration of the Go clients. Go does not allow restricting the TLS 1.3 cipher
+// suites, only the ones of the older versions are.
+func (t *importTLS) clientConfig() *tls.Config {
+	config := &tls.Config{
+		MinVersion: t.minVersion,
+	}
+	if len(t.ciphers) > 0 {
+		config.CipherSuites = cryptowatch.CipherSuitesIDs(t.ciphers)
+	}
+	return config
+}
+
+// nbdkitCurlTLS returns the TLS configuration of the nbdkit curl plugin
+func (t *importTLS) nbdkitCurlTLS() *image.NbdkitCurlTLS {
+	return &image.NbdkitCurlTLS{
+		MinVersion: t.minVersion,
+		Ciphers:    t.ciphers,
+	}
+}
@@ -0,0 +1,127 @@
+package importer
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/image"
+)
+
+var _ = Describe("Import TLS", func() {
+	tlsEnvVars := []string{common.MinVersionTLSVar, common.CiphersTLSVar}
+	savedEnv := map[string]string{}
+
+	BeforeEach(func() {
+		for _, name := range tlsEnvVars {
+			if value, ok := os.LookupEnv(name); ok {
+				savedEnv[name] = value
+			}
+			os.Unsetenv(name)
+		}
+	})
+
+	AfterEach(func() {
+		for _, name := range tlsEnvVars {
+			os.Unsetenv(name)
+			if value, ok := savedEnv[name]; ok {
+				os.Setenv(name, value)
+			}
+		}
+	})
+
+	getTransportTLSConfig := func(certDir string) *tls.Config {
+		client, err := createHTTPClient(certDir)
+		Expect(err).ToNot(HaveOccurred())
+		return client.Transport.(*http.Transport).TLSClientConfig
+	}
+
+	It("should default to TLS 1.2 without restricting the cipher suites", func() {
+		config := getTransportTLSConfig("")
+		Expect(config.MinVersion).To(Equal(uint16(tls.VersionTLS12)))
+		Expect(config.CipherSuites).To(BeEmpty())
+		Expect(config.RootCAs).To(BeNil())
+	})
+
+	It("should apply the minimal version and the cipher suites from the environment", func() {
+		os.Setenv(common.MinVersionTLSVar, "VersionTLS13")
+		os.Setenv(common.CiphersTLSVar, "ECDHE-RSA-AES128-GCM-SHA256,ECDHE-RSA-AES256-GCM-SHA384,TLS_AES_128_GCM_SHA256")
+		config := getTransportTLSConfig("")
+		Expect(config.MinVersion).To(Equal(uint16(tls.VersionTLS13)))
+		Expect(config.CipherSuites).To(Equal([]uint16{
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_AES_128_GCM_SHA256,
+		}))
+	})
+
+	It("should keep the TLS settings along with the custom CA certificates", func() {
+		os.Setenv(common.MinVersionTLSVar, "VersionTLS10")
+		certDir := createCert()
+		defer os.RemoveAll(certDir)
+		config := getTransportTLSConfig(certDir)
+		Expect(config.MinVersion).To(Equal(uint16(tls.VersionTLS10)))
+		Expect(config.RootCAs).ToNot(BeNil())
+	})
+
+	It("should apply the TLS settings to the S3 client", func() {
+		os.Setenv(common.MinVersionTLSVar, "VersionTLS13")
+		client, err := getS3Client("https://s3.us-east-1.amazonaws.com", credentials.AnonymousCredentials, "", "https")
+		Expect(err).ToNot(HaveOccurred())
+		transport := client.(*s3.S3).Client.Config.HTTPClient.Transport.(*http.Transport)
+		Expect(transport.TLSClientConfig.MinVersion).To(Equal(uint16(tls.VersionTLS13)))
+	})
+
+	It("should fail with an invalid minimal version", func() {
+		os.Setenv(common.MinVersionTLSVar, "TLS1.2")
+		_, err := createHTTPClient("")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("invalid minimal TLS version"))
+	})
+
+	It("should refuse a server below the minimal version", func() {
+		ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		ts.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+		ts.StartTLS()
+		defer ts.Close()
+		certDir, err := os.MkdirTemp("", "import-tls")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(certDir)
+		certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+		Expect(os.WriteFile(filepath.Join(certDir, "tls.crt"), certPEM, 0600)).To(Succeed())
+
+		client, err := createHTTPClient(certDir)
+		Expect(err).ToNot(HaveOccurred())
+		resp, err := client.Get(ts.URL)
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+
+		os.Setenv(common.MinVersionTLSVar, "VersionTLS13")
+		client, err = createHTTPClient(certDir)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = client.Get(ts.URL)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("protocol version"))
+	})
+
+	It("should pass the TLS settings to the nbdkit curl plugin", func() {
+		tlsConfig, err := newImportTLS("VersionTLS11", "ECDHE-RSA-AES128-SHA, TLS_AES_128_GCM_SHA256")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tlsConfig.nbdkitCurlTLS()).To(Equal(&image.NbdkitCurlTLS{
+			MinVersion: tls.VersionTLS11,
+			Ciphers:    []string{"ECDHE-RSA-AES128-SHA", "TLS_AES_128_GCM_SHA256"},
+		}))
+	})
+})
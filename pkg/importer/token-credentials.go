@@ -0,0 +1,164 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/pkg/errors"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	webIdentityProviderName = "WebIdentityCredentials"
+	// webIdentityExpiryWindow renews the assumed role credentials before they expire
+	webIdentityExpiryWindow = time.Minute
+
+	gcsExternalAccountType = "external_account"
+	gcsSubjectTokenType    = "urn:ietf:params:oauth:token-type:jwt"
+	gcsTokenURL            = "https://sts.googleapis.com/v1/token"
+	gcsImpersonationURLFmt = "https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/%s:generateAccessToken"
+)
+
+// TokenCredentials is the configuration to get the credentials of a source by exchanging a projected service account
+// token, instead of using static keys
+type TokenCredentials struct {
+	// TokenFile is the projected service account token, it is read on every exchange as the kubelet rotates it
+	TokenFile string
+	// Audience is the GCP workload identity provider the token is exchanged with
+	Audience string
+	// RoleARN is the AWS IAM role assumed with the token
+	RoleARN string
+	// ServiceAccount is the GCP service account impersonated with the federated token, optional
+	ServiceAccount string
+}
+
+// stsWebIdentityClient is the interface to the STS client used to assume a role with a web identity token
+type stsWebIdentityClient interface {
+	AssumeRoleWithWebIdentity(input *sts.AssumeRoleWithWebIdentityInput) (*sts.AssumeRoleWithWebIdentityOutput, error)
+}
+
+// may be overridden in tests
+var newStsClientFunc = getStsClient
+
+func getStsClient(region string) (stsWebIdentityClient, error) {
+	// AssumeRoleWithWebIdentity is not signed, the token is the credential
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String(region),
+		Credentials: credentials.AnonymousCredentials,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sts.New(sess), nil
+}
+
+// webIdentityRoleProvider is a credentials.Provider assuming an IAM role with the projected service account token, as
+// the AWS SDKs do for IRSA
+type webIdentityRoleProvider struct {
+	credentials.Expiry
+	client      stsWebIdentityClient
+	roleARN     string
+	tokenFile   string
+	sessionName string
+}
+
+// Retrieve assumes the role with the current token
+func (p *webIdentityRoleProvider) Retrieve() (credentials.Value, error) {
+	token, err := os.ReadFile(p.tokenFile)
+	if err != nil {
+		return credentials.Value{ProviderName: webIdentityProviderName}, errors.Wrap(err, "could not read the service account token")
+	}
+	output, err := p.client.AssumeRoleWithWebIdentity(&sts.AssumeRoleWithWebIdentityInput{
+		RoleArn:          aws.String(p.roleARN),
+		RoleSessionName:  aws.String(p.sessionName),
+		WebIdentityToken: aws.String(string(token)),
+	})
+	if err != nil {
+		return credentials.Value{ProviderName: webIdentityProviderName}, errors.Wrapf(err, "could not assume role %q", p.roleARN)
+	}
+	p.SetExpiration(aws.TimeValue(output.Credentials.Expiration), webIdentityExpiryWindow)
+	return credentials.Value{
+		AccessKeyID:     aws.StringValue(output.Credentials.AccessKeyId),
+		SecretAccessKey: aws.StringValue(output.Credentials.SecretAccessKey),
+		SessionToken:    aws.StringValue(output.Credentials.SessionToken),
+		ProviderName:    webIdentityProviderName,
+	}, nil
+}
+
+// newS3Credentials returns the credentials assuming the role of the token configuration, or the static keys if there
+// is none
+func newS3Credentials(region, accessKey, secKey string, token *TokenCredentials) (*credentials.Credentials, error) {
+	if token == nil {
+		return credentials.NewStaticCredentials(accessKey, secKey, ""), nil
+	}
+	if token.RoleARN == "" {
+		return nil, errors.New("a role ARN is required to get the S3 credentials with a service account token")
+	}
+	klog.V(1).Infof("Assuming role %s with the service account token", token.RoleARN)
+	client, err := newStsClientFunc(region)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not build sts client")
+	}
+	return credentials.NewCredentials(&webIdentityRoleProvider{
+		client:      client,
+		roleARN:     token.RoleARN,
+		tokenFile:   token.TokenFile,
+		sessionName: fmt.Sprintf("cdi-importer-%d", time.Now().UnixNano()),
+	}), nil
+}
+
+// gcsExternalAccount is the google external_account credentials configuration, exchanging the token through workload
+// identity federation
+type gcsExternalAccount struct {
+	Type                           string                   `json:"type"`
+	Audience                       string                   `json:"audience"`
+	SubjectTokenType               string                   `json:"subject_token_type"`
+	TokenURL                       string                   `json:"token_url"`
+	ServiceAccountImpersonationURL string                   `json:"service_account_impersonation_url,omitempty"`
+	CredentialSource               gcsExternalAccountSource `json:"credential_source"`
+}
+
+type gcsExternalAccountSource struct {
+	File string `json:"file"`
+}
+
+// newGcsCredentialsJSON returns the external_account credentials of the token configuration
+func newGcsCredentialsJSON(token *TokenCredentials) ([]byte, error) {
+	if token.Audience == "" {
+		return nil, errors.New("a workload identity provider audience is required to get the GCS credentials with a service account token")
+	}
+	account := gcsExternalAccount{
+		Type:             gcsExternalAccountType,
+		Audience:         token.Audience,
+		SubjectTokenType: gcsSubjectTokenType,
+		TokenURL:         gcsTokenURL,
+		CredentialSource: gcsExternalAccountSource{File: token.TokenFile},
+	}
+	if token.ServiceAccount != "" {
+		account.ServiceAccountImpersonationURL = fmt.Sprintf(gcsImpersonationURLFmt, token.ServiceAccount)
+	}
+	return json.Marshal(account)
+}
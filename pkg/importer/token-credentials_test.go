@@ -0,0 +1,144 @@
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2/google"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Token credentials", func() {
+	var (
+		tmpDir    string
+		tokenFile string
+		stsClient *fakeStsClient
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "token")
+		Expect(err).NotTo(HaveOccurred())
+		tokenFile = filepath.Join(tmpDir, "token")
+		Expect(os.WriteFile(tokenFile, []byte("first-token"), 0600)).To(Succeed())
+
+		stsClient = &fakeStsClient{expiration: time.Now().Add(time.Hour)}
+		newStsClientFunc = func(region string) (stsWebIdentityClient, error) {
+			stsClient.region = region
+			return stsClient, nil
+		}
+	})
+
+	AfterEach(func() {
+		newStsClientFunc = getStsClient
+		os.RemoveAll(tmpDir)
+	})
+
+	It("Should fall back to the static keys without token configuration", func() {
+		creds, err := newS3Credentials("us-east-1", "access", "secret", nil)
+		Expect(err).NotTo(HaveOccurred())
+		value, err := creds.Get()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value.AccessKeyID).To(Equal("access"))
+		Expect(value.SecretAccessKey).To(Equal("secret"))
+		Expect(stsClient.inputs).To(BeEmpty())
+	})
+
+	It("Should assume the role with the projected token", func() {
+		creds, err := newS3Credentials("us-east-1", "", "", &TokenCredentials{TokenFile: tokenFile, RoleARN: "arn:aws:iam::123456789012:role/importer"})
+		Expect(err).NotTo(HaveOccurred())
+		value, err := creds.Get()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value.AccessKeyID).To(Equal("assumed-access"))
+		Expect(value.SecretAccessKey).To(Equal("assumed-secret"))
+		Expect(value.SessionToken).To(Equal("session-token"))
+		Expect(value.ProviderName).To(Equal(webIdentityProviderName))
+		Expect(stsClient.region).To(Equal("us-east-1"))
+		Expect(stsClient.inputs).To(HaveLen(1))
+		Expect(aws.StringValue(stsClient.inputs[0].RoleArn)).To(Equal("arn:aws:iam::123456789012:role/importer"))
+		Expect(aws.StringValue(stsClient.inputs[0].WebIdentityToken)).To(Equal("first-token"))
+		Expect(aws.StringValue(stsClient.inputs[0].RoleSessionName)).To(HavePrefix("cdi-importer-"))
+	})
+
+	It("Should assume the role again with the rotated token once the credentials expire", func() {
+		stsClient.expiration = time.Now()
+		creds, err := newS3Credentials("us-east-1", "", "", &TokenCredentials{TokenFile: tokenFile, RoleARN: "arn:aws:iam::123456789012:role/importer"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = creds.Get()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.WriteFile(tokenFile, []byte("second-token"), 0600)).To(Succeed())
+		_, err = creds.Get()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stsClient.inputs).To(HaveLen(2))
+		Expect(aws.StringValue(stsClient.inputs[1].WebIdentityToken)).To(Equal("second-token"))
+	})
+
+	It("Should fail to get the S3 credentials when the role cannot be assumed", func() {
+		stsClient.err = errors.New("access denied")
+		creds, err := newS3Credentials("us-east-1", "", "", &TokenCredentials{TokenFile: tokenFile, RoleARN: "arn:aws:iam::123456789012:role/importer"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = creds.Get()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("access denied"))
+	})
+
+	It("Should require a role ARN for S3", func() {
+		_, err := newS3Credentials("us-east-1", "", "", &TokenCredentials{TokenFile: tokenFile, Audience: "sts.amazonaws.com"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should build the GCS external account credentials from the token configuration", func() {
+		audience := "//iam.googleapis.com/projects/1/locations/global/workloadIdentityPools/pool/providers/provider"
+		credentialsJSON, err := newGcsCredentialsJSON(&TokenCredentials{
+			TokenFile:      tokenFile,
+			Audience:       audience,
+			ServiceAccount: "importer@project.iam.gserviceaccount.com",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		account := &gcsExternalAccount{}
+		Expect(json.Unmarshal(credentialsJSON, account)).To(Succeed())
+		Expect(account.Type).To(Equal(gcsExternalAccountType))
+		Expect(account.Audience).To(Equal(audience))
+		Expect(account.CredentialSource.File).To(Equal(tokenFile))
+		Expect(account.ServiceAccountImpersonationURL).To(Equal(
+			"https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/importer@project.iam.gserviceaccount.com:generateAccessToken"))
+
+		_, err = google.CredentialsFromJSON(context.Background(), credentialsJSON, "https://www.googleapis.com/auth/devstorage.read_only")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Should require a workload identity provider audience for GCS", func() {
+		_, err := newGcsCredentialsJSON(&TokenCredentials{TokenFile: tokenFile})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+type fakeStsClient struct {
+	region     string
+	expiration time.Time
+	err        error
+	inputs     []*sts.AssumeRoleWithWebIdentityInput
+}
+
+func (c *fakeStsClient) AssumeRoleWithWebIdentity(input *sts.AssumeRoleWithWebIdentityInput) (*sts.AssumeRoleWithWebIdentityOutput, error) {
+	c.inputs = append(c.inputs, input)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &sts.AssumeRoleWithWebIdentityOutput{
+		Credentials: &sts.Credentials{
+			AccessKeyId:     aws.String("assumed-access"),
+			SecretAccessKey: aws.String("assumed-secret"),
+			SessionToken:    aws.String("session-token"),
+			Expiration:      aws.Time(c.expiration),
+		},
+	}, nil
+}
@@ -20,20 +20,28 @@ import (
 	"archive/tar"
 	"context"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/docker/reference"
 	"github.com/containers/image/v5/image"
 	"github.com/containers/image/v5/manifest"
 	"github.com/containers/image/v5/oci/archive"
 	"github.com/containers/image/v5/pkg/blobinfocache"
 	"github.com/containers/image/v5/types"
+	"github.com/coreos/go-semver/semver"
+	"github.com/docker/distribution/registry/api/errcode"
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
 
 	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/util"
 )
 
@@ -41,6 +49,29 @@ const (
 	whFilePrefix = ".wh."
 )
 
+var (
+	// registryRetryInitialDelay is the delay before the first retry of a failed registry request, doubled on each retry
+	registryRetryInitialDelay = 2 * time.Second
+	// registryRetryMaxDelay is the maximum delay between two attempts of a registry request
+	registryRetryMaxDelay = 30 * time.Second
+	// registryRetryBudget is the total time spent waiting between the attempts of a registry request
+	registryRetryBudget = 5 * time.Minute
+	// registryCredentialRefreshTimeout is how long an unauthorized registry request waits for the mounted credentials
+	// to be rotated before giving up, the kubelet only updates a mounted secret on its periodic sync
+	registryCredentialRefreshTimeout = 2 * time.Minute
+	// registryCredentialPollInterval is the interval between two reads of the mounted credentials while waiting for
+	// their rotation
+	registryCredentialPollInterval = 5 * time.Second
+	// registryCredentialDir is where the secret holding the registry credentials is mounted, if any
+	registryCredentialDir string
+
+	registryStatusCodeRegexp = regexp.MustCompile(`(?:status code from registry|unexpected HTTP status:|error parsing HTTP|StatusCode:) (\d{3})`)
+)
+
+func init() {
+	registryCredentialDir, _ = util.ParseEnvVar(common.ImporterRegistryCredentialDirVar, false)
+}
+
 func commandTimeoutContext() (context.Context, context.CancelFunc) {
 	return context.WithCancel(context.Background())
 }
@@ -82,6 +113,131 @@ func readImageSource(ctx context.Context, sys *types.SystemContext, img string)
 	return src, nil
 }
 
+// isRetryableRegistryError returns true if the registry request failed because the registry is rate limiting or is
+// temporarily unavailable, which is reported as a 429 or 5xx status code.
+func isRetryableRegistryError(err error) bool {
+	if errors.Is(err, docker.ErrTooManyRequests) {
+		return true
+	}
+	var unauthorized docker.ErrUnauthorizedForCredentials
+	if errors.As(err, &unauthorized) {
+		return false
+	}
+	match := registryStatusCodeRegexp.FindStringSubmatch(err.Error())
+	if match == nil {
+		return false
+	}
+	statusCode, _ := strconv.Atoi(match[1])
+	return statusCode == http.StatusTooManyRequests || (statusCode >= 500 && statusCode <= 599)
+}
+
+// retryRegistryRequest calls fn until it succeeds or fails with an error that is not retryable, backing off
+// exponentially between the attempts until registryRetryBudget is exhausted. The registry client already honors the
+// Retry-After header of 429 responses before giving up, so this only adds retries on top of it.
+func retryRegistryRequest(ctx context.Context, fn func() error) error {
+	delay := registryRetryInitialDelay
+	var waited time.Duration
+	for {
+		err := fn()
+		if err == nil || !isRetryableRegistryError(err) {
+			return err
+		}
+		if waited+delay > registryRetryBudget {
+			klog.Errorf("Registry request failed after retrying for %v: %v", waited, err)
+			return err
+		}
+		klog.Warningf("Registry request failed with a transient error, retrying in %v: %v", delay, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		waited += delay
+		delay *= 2
+		if delay > registryRetryMaxDelay {
+			delay = registryRetryMaxDelay
+		}
+	}
+}
+
+// registryAuth holds what is needed to build the system context of a registry request. The credentials are read from
+// the mounted secret on each attempt when there is one, the given keys are only used when there is none.
+type registryAuth struct {
+	accessKey        string
+	secKey           string
+	certDir          string
+	insecureRegistry bool
+}
+
+// credentials returns the current registry credentials, read from the mounted secret if there is one
+func (a *registryAuth) credentials() (string, string) {
+	if registryCredentialDir == "" {
+		return a.accessKey, a.secKey
+	}
+	accessKey, err := os.ReadFile(filepath.Join(registryCredentialDir, common.KeyAccess))
+	if err != nil {
+		klog.Warningf("Could not read the registry access key, using the one of the environment: %v", err)
+		return a.accessKey, a.secKey
+	}
+	secKey, err := os.ReadFile(filepath.Join(registryCredentialDir, common.KeySecret))
+	if err != nil {
+		klog.Warningf("Could not read the registry secret key, using the one of the environment: %v", err)
+		return a.accessKey, a.secKey
+	}
+	return string(accessKey), string(secKey)
+}
+
+// waitForRotatedCredentials polls the mounted secret until its credentials differ from the given ones, returning
+// false if they were not rotated within registryCredentialRefreshTimeout
+func (a *registryAuth) waitForRotatedCredentials(ctx context.Context, accessKey, secKey string) bool {
+	timeout := time.After(registryCredentialRefreshTimeout)
+	for {
+		if newAccessKey, newSecKey := a.credentials(); newAccessKey != accessKey || newSecKey != secKey {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-timeout:
+			return false
+		case <-time.After(registryCredentialPollInterval):
+		}
+	}
+}
+
+// isUnauthorizedRegistryError returns true if the registry rejected the credentials of the request, which the manifest
+// requests report as a registry error code rather than as ErrUnauthorizedForCredentials
+func isUnauthorizedRegistryError(err error) bool {
+	var unauthorized docker.ErrUnauthorizedForCredentials
+	if errors.As(err, &unauthorized) {
+		return true
+	}
+	var registryErr errcode.Error
+	return errors.As(err, &registryErr) && registryErr.Code == errcode.ErrorCodeUnauthorized
+}
+
+// retryAuthenticatedRegistryRequest calls fn with a system context built from the current credentials on each attempt,
+// retrying as retryRegistryRequest does. When the registry rejects the credentials of the mounted secret, it waits for
+// the secret to be rotated and tries once more with the new credentials before giving up.
+func retryAuthenticatedRegistryRequest(ctx context.Context, auth *registryAuth, fn func(*types.SystemContext) error) error {
+	var accessKey, secKey string
+	attempt := func() error {
+		accessKey, secKey = auth.credentials()
+		return fn(buildSourceContext(accessKey, secKey, auth.certDir, auth.insecureRegistry))
+	}
+	err := retryRegistryRequest(ctx, attempt)
+	if err == nil || registryCredentialDir == "" || !isUnauthorizedRegistryError(err) {
+		return err
+	}
+	klog.Warningf("Registry request unauthorized, waiting for the credentials to be rotated: %v", err)
+	if !auth.waitForRotatedCredentials(ctx, accessKey, secKey) {
+		klog.Errorf("Registry credentials were not rotated within %v", registryCredentialRefreshTimeout)
+		return err
+	}
+	klog.Infof("Registry credentials were rotated, retrying the request")
+	return retryRegistryRequest(ctx, attempt)
+}
+
 func parseImageName(img string) (types.ImageReference, error) {
 	parts := strings.SplitN(img, ":", 2)
 	if len(parts) != 2 {
@@ -125,7 +281,11 @@ func processLayer(ctx context.Context,
 	stopAtFirst bool) (bool, error) {
 
 	var reader io.ReadCloser
-	reader, _, err := src.GetBlob(ctx, layer, cache)
+	err := retryRegistryRequest(ctx, func() error {
+		var err error
+		reader, _, err = src.GetBlob(ctx, layer, cache)
+		return err
+	})
 	if err != nil {
 		klog.Errorf("Could not read layer: %v", err)
 		return false, errors.Wrap(err, "Could not read layer")
@@ -172,24 +332,43 @@ func processLayer(ctx context.Context,
 	return found, nil
 }
 
+// openRegistryImage opens the image at url, the caller closes the returned image and image source
+func openRegistryImage(ctx context.Context, url, accessKey, secKey, certDir string, insecureRegistry bool) (*types.SystemContext, types.ImageSource, types.ImageCloser, error) {
+	auth := &registryAuth{accessKey: accessKey, secKey: secKey, certDir: certDir, insecureRegistry: insecureRegistry}
+
+	var srcCtx *types.SystemContext
+	var src types.ImageSource
+	var imgCloser types.ImageCloser
+	err := retryAuthenticatedRegistryRequest(ctx, auth, func(sys *types.SystemContext) error {
+		var err error
+		srcCtx = sys
+		// The image source caches the registry errors, so a new one is needed for each attempt
+		if src, err = readImageSource(ctx, srcCtx, url); err != nil {
+			return err
+		}
+		if imgCloser, err = image.FromSource(ctx, srcCtx, src); err != nil {
+			closeImage(src)
+			klog.Errorf("Error retrieving image: %v", err)
+			return errors.Wrap(err, "Error retrieving image")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return srcCtx, src, imgCloser, nil
+}
+
 func copyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, certDir string, insecureRegistry, stopAtFirst bool) error {
-	klog.Infof("Downloading image from '%v', copying file from '%v' to '%v'", url, pathPrefix, destDir)
+	klog.Infof("Downloading image from '%v', copying file from '%v' to '%v'", redactSourceURL(url), pathPrefix, destDir)
 
 	ctx, cancel := commandTimeoutContext()
 	defer cancel()
-	srcCtx := buildSourceContext(accessKey, secKey, certDir, insecureRegistry)
-
-	src, err := readImageSource(ctx, srcCtx, url)
+	srcCtx, src, imgCloser, err := openRegistryImage(ctx, url, accessKey, secKey, certDir, insecureRegistry)
 	if err != nil {
 		return err
 	}
 	defer closeImage(src)
-
-	imgCloser, err := image.FromSource(ctx, srcCtx, src)
-	if err != nil {
-		klog.Errorf("Error retrieving image: %v", err)
-		return errors.Wrap(err, "Error retrieving image")
-	}
 	defer imgCloser.Close()
 
 	cache := blobinfocache.DefaultCache(srcCtx)
@@ -225,29 +404,100 @@ func copyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, certDir stri
 // certDir: directory public CA keys are stored for registry identity verification
 // insecureRegistry: boolean if true will allow insecure registries.
 func GetImageDigest(url, accessKey, secKey, certDir string, insecureRegistry bool) (string, error) {
-	klog.Infof("Inspecting image from '%v'", url)
+	klog.Infof("Inspecting image from '%v'", redactSourceURL(url))
 
 	ctx, cancel := commandTimeoutContext()
 	defer cancel()
-	srcCtx := buildSourceContext(accessKey, secKey, certDir, insecureRegistry)
+	auth := &registryAuth{accessKey: accessKey, secKey: secKey, certDir: certDir, insecureRegistry: insecureRegistry}
+
+	var imageManifest []byte
+	err := retryAuthenticatedRegistryRequest(ctx, auth, func(srcCtx *types.SystemContext) error {
+		src, err := readImageSource(ctx, srcCtx, url)
+		if err != nil {
+			return err
+		}
+		defer closeImage(src)
 
-	src, err := readImageSource(ctx, srcCtx, url)
+		imageManifest, _, err = src.GetManifest(context.Background(), nil)
+		return err
+	})
 	if err != nil {
 		return "", err
 	}
-	defer closeImage(src)
 
-	imageManifest, _, err := src.GetManifest(context.Background(), nil)
+	digest, err := manifest.Digest(imageManifest)
 	if err != nil {
 		return "", err
 	}
 
-	digest, err := manifest.Digest(imageManifest)
+	return digest.String(), nil
+}
+
+// GetHighestSemverTaggedImage returns the url of the container image tagged with the highest semantic version among
+// the tags of the url repository matching tagPattern, or an empty url if no tag matches.
+// url: source registry url, its tag is ignored.
+// tagPattern: regular expression the tags must match.
+// accessKey: accessKey for the registry described in url.
+// secKey: secretKey for the registry described in url.
+// certDir: directory public CA keys are stored for registry identity verification
+// insecureRegistry: boolean if true will allow insecure registries.
+func GetHighestSemverTaggedImage(url, tagPattern, accessKey, secKey, certDir string, insecureRegistry bool) (string, error) {
+	tagRegexp, err := regexp.Compile(tagPattern)
+	if err != nil {
+		return "", errors.Wrap(err, "Invalid tag pattern")
+	}
+	ref, err := parseImageName(url)
+	if err != nil {
+		return "", errors.Wrap(err, "Could not parse image")
+	}
+	if ref.Transport().Name() != docker.Transport.Name() {
+		return "", errors.Errorf("Cannot list the tags of image %q, only docker registries are supported", redactSourceURL(url))
+	}
+	klog.Infof("Listing the tags of '%v'", redactSourceURL(url))
+
+	ctx, cancel := commandTimeoutContext()
+	defer cancel()
+	auth := &registryAuth{accessKey: accessKey, secKey: secKey, certDir: certDir, insecureRegistry: insecureRegistry}
+
+	var tags []string
+	err = retryAuthenticatedRegistryRequest(ctx, auth, func(srcCtx *types.SystemContext) error {
+		tags, err = docker.GetRepositoryTags(ctx, srcCtx, ref)
+		return err
+	})
 	if err != nil {
 		return "", err
 	}
 
-	return digest.String(), nil
+	tag := highestSemverTag(tags, tagRegexp)
+	if tag == "" {
+		return "", nil
+	}
+	tagged, err := reference.WithTag(reference.TrimNamed(ref.DockerReference()), tag)
+	if err != nil {
+		return "", err
+	}
+	return cdiv1.RegistrySchemeDocker + "://" + tagged.String(), nil
+}
+
+// highestSemverTag returns the tag with the highest semantic version, with an optional "v" prefix, among the tags
+// matching tagRegexp. The tags that are not semantic versions are ignored.
+func highestSemverTag(tags []string, tagRegexp *regexp.Regexp) string {
+	var highestTag string
+	var highest *semver.Version
+	for _, tag := range tags {
+		if !tagRegexp.MatchString(tag) {
+			continue
+		}
+		version, err := semver.NewVersion(strings.TrimPrefix(tag, "v"))
+		if err != nil {
+			klog.V(3).Infof("Ignoring tag %q, not a semantic version: %v", tag, err)
+			continue
+		}
+		if highest == nil || highest.LessThan(*version) {
+			highestTag, highest = tag, version
+		}
+	}
+	return highestTag
 }
 
 // CopyRegistryImage download image from registry with docker image API. It will extract first file under the pathPrefix
@@ -16,13 +16,34 @@ limitations under the License.
 package importer
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
 )
 
+const fakeRegistryManifest = `{
+	"schemaVersion": 2,
+	"mediaType": "application/vnd.docker.distribution.manifest.v2+json",
+	"config": {
+		"mediaType": "application/vnd.docker.container.image.v1+json",
+		"size": 2,
+		"digest": "sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"
+	},
+	"layers": []
+}`
+
 var _ = Describe("Registry Importer", func() {
 	source := "oci-archive:" + imageFile
 	var tmpDir string
@@ -68,3 +89,229 @@ var _ = Describe("Registry Importer", func() {
 		Expect(err).To(HaveOccurred())
 	})
 })
+
+var _ = Describe("Registry request retry", func() {
+	var savedInitialDelay time.Duration
+
+	BeforeEach(func() {
+		savedInitialDelay = registryRetryInitialDelay
+		registryRetryInitialDelay = 10 * time.Millisecond
+	})
+
+	AfterEach(func() {
+		registryRetryInitialDelay = savedInitialDelay
+	})
+
+	// newFakeRegistry returns a registry answering the manifest requests with the failures status codes, then the manifest
+	newFakeRegistry := func(manifestRequests *int32, failures ...int) *httptest.Server {
+		return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.Contains(r.URL.Path, "/manifests/") {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			request := int(atomic.AddInt32(manifestRequests, 1))
+			if request <= len(failures) {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(failures[request-1])
+				return
+			}
+			w.Header().Set("Content-Type", "application/vnd.docker.distribution.manifest.v2+json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(fakeRegistryManifest))
+		}))
+	}
+
+	table.DescribeTable("should retry transient registry errors", func(failures ...int) {
+		var manifestRequests int32
+		registry := newFakeRegistry(&manifestRequests, failures...)
+		defer registry.Close()
+
+		url := "docker://" + strings.TrimPrefix(registry.URL, "https://") + "/test/image:latest"
+		digest, err := GetImageDigest(url, "", "", "", true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(digest).To(HavePrefix("sha256:"))
+		Expect(manifestRequests).To(BeEquivalentTo(len(failures) + 1))
+	},
+		table.Entry("when rate limited", http.StatusTooManyRequests),
+		table.Entry("when unavailable", http.StatusServiceUnavailable, http.StatusBadGateway),
+	)
+
+	table.DescribeTable("should not retry", func(statusCode int) {
+		var manifestRequests int32
+		registry := newFakeRegistry(&manifestRequests, statusCode)
+		defer registry.Close()
+
+		url := "docker://" + strings.TrimPrefix(registry.URL, "https://") + "/test/image:latest"
+		_, err := GetImageDigest(url, "", "", "", true)
+		Expect(err).To(HaveOccurred())
+		Expect(manifestRequests).To(BeEquivalentTo(1))
+	},
+		table.Entry("when forbidden", http.StatusForbidden),
+		table.Entry("when not found", http.StatusNotFound),
+		table.Entry("when unauthorized", http.StatusUnauthorized),
+	)
+
+	It("should give up when the retry budget is exhausted", func() {
+		savedBudget := registryRetryBudget
+		registryRetryBudget = 50 * time.Millisecond
+		defer func() { registryRetryBudget = savedBudget }()
+
+		var manifestRequests int32
+		failures := make([]int, 100)
+		for i := range failures {
+			failures[i] = http.StatusServiceUnavailable
+		}
+		registry := newFakeRegistry(&manifestRequests, failures...)
+		defer registry.Close()
+
+		url := "docker://" + strings.TrimPrefix(registry.URL, "https://") + "/test/image:latest"
+		_, err := GetImageDigest(url, "", "", "", true)
+		Expect(err).To(HaveOccurred())
+		// 10ms, 20ms then 40ms would exceed the 50ms budget
+		Expect(manifestRequests).To(BeEquivalentTo(3))
+	})
+})
+
+var _ = Describe("Registry credential rotation", func() {
+	var (
+		credentialDir    string
+		savedTimeout     time.Duration
+		savedInterval    time.Duration
+		validCredentials atomic.Value
+		manifestRequests int32
+		registry         *httptest.Server
+		url              string
+	)
+
+	writeCredentials := func(accessKey, secKey string) {
+		Expect(os.WriteFile(filepath.Join(credentialDir, common.KeyAccess), []byte(accessKey), 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(credentialDir, common.KeySecret), []byte(secKey), 0600)).To(Succeed())
+	}
+
+	// onUnauthorized is called by the fake registry when it rejects the credentials of a manifest request
+	var onUnauthorized func()
+
+	BeforeEach(func() {
+		var err error
+		credentialDir, err = os.MkdirTemp("", "registry-credentials")
+		Expect(err).ToNot(HaveOccurred())
+		registryCredentialDir = credentialDir
+		savedTimeout, savedInterval = registryCredentialRefreshTimeout, registryCredentialPollInterval
+		registryCredentialRefreshTimeout = 5 * time.Second
+		registryCredentialPollInterval = 10 * time.Millisecond
+		onUnauthorized = func() {}
+		manifestRequests = 0
+
+		// The fake registry only accepts the valid credentials, challenging the requests to use basic auth
+		registry = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, password, ok := r.BasicAuth()
+			if !ok || user+":"+password != validCredentials.Load().(string) {
+				if strings.Contains(r.URL.Path, "/manifests/") {
+					atomic.AddInt32(&manifestRequests, 1)
+					onUnauthorized()
+				}
+				w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if !strings.Contains(r.URL.Path, "/manifests/") {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			atomic.AddInt32(&manifestRequests, 1)
+			w.Header().Set("Content-Type", "application/vnd.docker.distribution.manifest.v2+json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(fakeRegistryManifest))
+		}))
+		url = "docker://" + strings.TrimPrefix(registry.URL, "https://") + "/test/image:latest"
+	})
+
+	AfterEach(func() {
+		registry.Close()
+		registryCredentialDir = ""
+		registryCredentialRefreshTimeout, registryCredentialPollInterval = savedTimeout, savedInterval
+		os.RemoveAll(credentialDir)
+	})
+
+	It("should use the rotated credentials on the subsequent attempt", func() {
+		validCredentials.Store("user:old")
+		writeCredentials("user", "old")
+		_, err := GetImageDigest(url, "env-user", "env-password", "", true)
+		Expect(err).ToNot(HaveOccurred())
+
+		validCredentials.Store("user:new")
+		writeCredentials("user", "new")
+		_, err = GetImageDigest(url, "env-user", "env-password", "", true)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should retry with the rotated credentials when unauthorized", func() {
+		validCredentials.Store("user:new")
+		writeCredentials("user", "old")
+		onUnauthorized = func() {
+			writeCredentials("user", "new")
+		}
+		digest, err := GetImageDigest(url, "", "", "", true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(digest).To(HavePrefix("sha256:"))
+		Expect(manifestRequests).To(BeEquivalentTo(2))
+	})
+
+	It("should give up when unauthorized and the credentials are not rotated", func() {
+		registryCredentialRefreshTimeout = 50 * time.Millisecond
+		validCredentials.Store("user:new")
+		writeCredentials("user", "old")
+		_, err := GetImageDigest(url, "", "", "", true)
+		Expect(err).To(HaveOccurred())
+		Expect(manifestRequests).To(BeEquivalentTo(1))
+	})
+
+	It("should fall back to the credentials of the environment when the secret is not mounted", func() {
+		validCredentials.Store("env-user:env-password")
+		_, err := GetImageDigest(url, "env-user", "env-password", "", true)
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
+
+var _ = Describe("Registry semantic version tag selection", func() {
+	table.DescribeTable("should select the highest semantic version tag", func(tags []string, pattern, expected string) {
+		Expect(highestSemverTag(tags, regexp.MustCompile(pattern))).To(Equal(expected))
+	},
+		table.Entry("among versions", []string{"v1.2.3", "v1.3.0", "v1.2.4"}, ".*", "v1.3.0"),
+		table.Entry("comparing the versions numerically", []string{"v1.9.0", "v1.10.0"}, ".*", "v1.10.0"),
+		table.Entry("with and without the v prefix", []string{"1.2.3", "v1.2.4"}, ".*", "v1.2.4"),
+		table.Entry("ignoring a pre-release of the same version", []string{"v1.3.0-rc.1", "v1.3.0"}, ".*", "v1.3.0"),
+		table.Entry("ignoring the tags that are not versions", []string{"latest", "v1.2", "nightly-2.0.0", "v1.2.3"}, ".*", "v1.2.3"),
+		table.Entry("ignoring the tags that do not match", []string{"v1.2.3", "v2.0.0"}, `^v1\.`, "v1.2.3"),
+		table.Entry("returning none if no tag matches", []string{"latest", "v2.0.0"}, `^v1\.`, ""),
+	)
+
+	It("should list the registry tags and follow a new higher tag", func() {
+		tags := []string{"latest", "v1.2.3", "v1.2.4", "v1.3.0", "v2.0.0"}
+		registry := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v2/test/image/tags/list" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"name": "test/image", "tags": tags})
+		}))
+		defer registry.Close()
+
+		repository := strings.TrimPrefix(registry.URL, "https://") + "/test/image"
+		url, err := GetHighestSemverTaggedImage("docker://"+repository+":latest", `^v1\.`, "", "", "", true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(url).To(Equal("docker://" + repository + ":v1.3.0"))
+
+		tags = append(tags, "v1.3.1")
+		url, err = GetHighestSemverTaggedImage("docker://"+repository+":latest", `^v1\.`, "", "", "", true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(url).To(Equal("docker://" + repository + ":v1.3.1"))
+	})
+
+	It("should fail with an invalid tag pattern", func() {
+		_, err := GetHighestSemverTaggedImage("docker://registry:5000/test/image", "v1.(", "", "", "", true)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -27,21 +27,48 @@ type UploadDataSource struct {
 	url *url.URL
 	// contentType expected from the upload content
 	contentType cdiv1.DataVolumeContentType
+	// preallocation requests zeroing the empty ranges when writing directly to a block device
+	preallocation bool
+	// checkpointer checkpoints a raw clone stream, and resumes it from its offset
+	checkpointer *CloneCheckpointer
+	// convert transfers a raw stream to scratch space too, to be written to the target in another format
+	convert bool
 }
 
 // NewUploadDataSource creates a new instance of an UploadDataSource
-func NewUploadDataSource(stream io.ReadCloser, contentType cdiv1.DataVolumeContentType) *UploadDataSource {
+func NewUploadDataSource(stream io.ReadCloser, contentType cdiv1.DataVolumeContentType, preallocation bool) *UploadDataSource {
 	return &UploadDataSource{
-		stream:      stream,
-		contentType: contentType,
+		stream:        stream,
+		contentType:   contentType,
+		preallocation: preallocation,
 	}
 }
 
+// SetCloneCheckpointer makes the upload checkpoint the raw clone stream written to the target, the stream resuming
+// from the offset of the checkpointer
+func (ud *UploadDataSource) SetCloneCheckpointer(checkpointer *CloneCheckpointer) {
+	ud.checkpointer = checkpointer
+}
+
+// SetConvert makes the upload transfer a raw stream to scratch space too, for the convert phase to write it to the
+// target in another format, instead of writing it directly to the target
+func (ud *UploadDataSource) SetConvert(convert bool) {
+	ud.convert = convert
+}
+
 // Info is called to get initial information about the data.
 func (ud *UploadDataSource) Info() (ProcessingPhase, error) {
 	var err error
+	if ud.resuming() {
+		// A resumed clone stream starts in the middle of a raw image, detected when the stream started from scratch
+		return ProcessingPhaseTransferDataFile, nil
+	}
 	// Hardcoded to only accept kubevirt content type.
-	ud.readers, err = NewFormatReaders(ud.stream, uint64(0))
+	if ud.contentType == cdiv1.DataVolumeArchive {
+		ud.readers, err = NewFormatReaders(ud.stream, uint64(0))
+	} else {
+		ud.readers, err = NewDiskFormatReaders(ud.stream, uint64(0))
+	}
 	if err != nil {
 		klog.Errorf("Error creating readers: %v", err)
 		return ProcessingPhaseError, err
@@ -49,7 +76,7 @@ func (ud *UploadDataSource) Info() (ProcessingPhase, error) {
 	if ud.contentType == cdiv1.DataVolumeArchive {
 		return ProcessingPhaseTransferDataDir, nil
 	}
-	if !ud.readers.Convert {
+	if !ud.readers.Convert && !ud.convert {
 		// Uploading a raw file, we can write that directly to the target.
 		return ProcessingPhaseTransferDataFile, nil
 	}
@@ -90,11 +117,12 @@ func (ud *UploadDataSource) Transfer(path string) (ProcessingPhase, error) {
 
 // TransferFile is called to transfer the data from the source to the passed in file.
 func (ud *UploadDataSource) TransferFile(fileName string) (ProcessingPhase, error) {
-	if err := CleanAll(fileName); err != nil {
-		return ProcessingPhaseError, err
+	if !ud.resuming() {
+		if err := CleanAll(fileName); err != nil {
+			return ProcessingPhaseError, err
+		}
 	}
-	err := util.StreamDataToFile(ud.readers.TopReader(), fileName)
-	if err != nil {
+	if err := ud.streamToFile(fileName); err != nil {
 		return ProcessingPhaseError, err
 	}
 	// If we successfully wrote to the file, then the parse will succeed.
@@ -102,6 +130,30 @@ func (ud *UploadDataSource) TransferFile(fileName string) (ProcessingPhase, erro
 	return ProcessingPhaseResize, nil
 }
 
+// streamToFile writes the raw upload to the passed in file. A block device is written directly, skipping its empty
+// ranges, so the upload doesn't need to go through an intermediate file.
+func (ud *UploadDataSource) streamToFile(fileName string) error {
+	if ud.checkpointer != nil {
+		return ud.checkpointer.stream(ud.topReader(), fileName, ud.preallocation)
+	}
+	if size, _ := getAvailableSpaceBlockFunc(fileName); size >= int64(0) {
+		return streamRawToBlock(ud.readers.TopReader(), fileName, ud.preallocation)
+	}
+	return util.StreamDataToFile(ud.readers.TopReader(), fileName)
+}
+
+func (ud *UploadDataSource) resuming() bool {
+	return ud.checkpointer != nil && ud.checkpointer.Offset() > 0
+}
+
+// topReader returns the reader of the upload, the stream itself when resuming as no format readers are configured
+func (ud *UploadDataSource) topReader() io.Reader {
+	if ud.readers == nil {
+		return ud.stream
+	}
+	return ud.readers.TopReader()
+}
+
 // GetURL returns the url that the data processor can use when converting the data.
 func (ud *UploadDataSource) GetURL() *url.URL {
 	return ud.url
@@ -124,10 +176,11 @@ type AsyncUploadDataSource struct {
 }
 
 // NewAsyncUploadDataSource creates a new instance of an UploadDataSource
-func NewAsyncUploadDataSource(stream io.ReadCloser) *AsyncUploadDataSource {
+func NewAsyncUploadDataSource(stream io.ReadCloser, preallocation bool) *AsyncUploadDataSource {
 	return &AsyncUploadDataSource{
 		uploadDataSource: UploadDataSource{
-			stream: stream,
+			stream:        stream,
+			preallocation: preallocation,
 		},
 		ResumePhase: ProcessingPhaseInfo,
 	}
@@ -167,8 +220,7 @@ func (aud *AsyncUploadDataSource) TransferFile(fileName string) (ProcessingPhase
 	if err := CleanAll(fileName); err != nil {
 		return ProcessingPhaseError, err
 	}
-	err := util.StreamDataToFile(aud.uploadDataSource.readers.TopReader(), fileName)
-	if err != nil {
+	if err := aud.uploadDataSource.streamToFile(fileName); err != nil {
 		return ProcessingPhaseError, err
 	}
 	// If we successfully wrote to the file, then the parse will succeed.
@@ -1,7 +1,9 @@
 package importer
 
 import (
+	"bytes"
 	"io"
+	"net/url"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -11,6 +13,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/image"
 )
 
 const (
@@ -44,7 +47,7 @@ var _ = Describe("Upload data source", func() {
 		Expect(err).NotTo(HaveOccurred())
 		err = file.Close()
 		Expect(err).NotTo(HaveOccurred())
-		ud = NewUploadDataSource(file, dvKubevirt)
+		ud = NewUploadDataSource(file, dvKubevirt, false)
 		result, err := ud.Info()
 		Expect(err).To(HaveOccurred())
 		Expect(ProcessingPhaseError).To(Equal(result))
@@ -54,7 +57,7 @@ var _ = Describe("Upload data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		file, err := os.Open(cirrosFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		ud = NewUploadDataSource(file, dvKubevirt)
+		ud = NewUploadDataSource(file, dvKubevirt, false)
 		result, err := ud.Info()
 
 		Expect(err).NotTo(HaveOccurred())
@@ -65,7 +68,7 @@ var _ = Describe("Upload data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		file, err := os.Open(tinyCoreTarFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		ud = NewUploadDataSource(file, dvArchive)
+		ud = NewUploadDataSource(file, dvArchive, false)
 		result, err := ud.Info()
 
 		Expect(err).NotTo(HaveOccurred())
@@ -76,12 +79,23 @@ var _ = Describe("Upload data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		file, err := os.Open(tinyCoreFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		ud = NewUploadDataSource(file, dvKubevirt)
+		ud = NewUploadDataSource(file, dvKubevirt, false)
 		result, err := ud.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
 	})
 
+	It("Info should return TransferScratch, when passed in a valid raw image to convert", func() {
+		// Don't need to defer close, since ud.Close will close the reader
+		file, err := os.Open(tinyCoreFilePath)
+		Expect(err).NotTo(HaveOccurred())
+		ud = NewUploadDataSource(file, dvKubevirt, false)
+		ud.SetConvert(true)
+		result, err := ud.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ProcessingPhaseTransferScratch).To(Equal(result))
+	})
+
 	table.DescribeTable("calling transfer should", func(fileName string, dvContentType cdiv1.DataVolumeContentType, expectedPhase ProcessingPhase, scratchPath string, want []byte, wantErr bool) {
 		if scratchPath == "" {
 			scratchPath = tmpDir
@@ -89,7 +103,7 @@ var _ = Describe("Upload data source", func() {
 		sourceFile, err := os.Open(fileName)
 		Expect(err).NotTo(HaveOccurred())
 
-		ud = NewUploadDataSource(sourceFile, dvContentType)
+		ud = NewUploadDataSource(sourceFile, dvContentType, false)
 		_, err = ud.Info()
 		Expect(err).NotTo(HaveOccurred())
 		nextPhase, err := ud.Transfer(scratchPath)
@@ -119,7 +133,7 @@ var _ = Describe("Upload data source", func() {
 		sourceFile, err := os.Open(cirrosFilePath)
 		Expect(err).NotTo(HaveOccurred())
 
-		ud = NewUploadDataSource(sourceFile, dvKubevirt)
+		ud = NewUploadDataSource(sourceFile, dvKubevirt, false)
 		nextPhase, err := ud.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferScratch).To(Equal(nextPhase))
@@ -134,7 +148,7 @@ var _ = Describe("Upload data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		sourceFile, err := os.Open(tinyCoreFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		ud = NewUploadDataSource(sourceFile, dvKubevirt)
+		ud = NewUploadDataSource(sourceFile, dvKubevirt, false)
 		result, err := ud.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
@@ -147,7 +161,7 @@ var _ = Describe("Upload data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		sourceFile, err := os.Open(tinyCoreFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		ud = NewUploadDataSource(sourceFile, dvKubevirt)
+		ud = NewUploadDataSource(sourceFile, dvKubevirt, false)
 		result, err := ud.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
@@ -156,8 +170,56 @@ var _ = Describe("Upload data source", func() {
 		Expect(ProcessingPhaseError).To(Equal(result))
 	})
 
+	table.DescribeTable("TransferFile should write a raw image directly to a block device", func(preallocation bool) {
+		src := createSparseImage(filepath.Join(tmpDir, "source.raw"), sparseImageSize)
+		dest := createFilledFile(filepath.Join(tmpDir, "dest"), sparseImageSize, 0xff)
+		sourceFile, err := os.Open(src)
+		Expect(err).NotTo(HaveOccurred())
+		ud = NewUploadDataSource(sourceFile, dvKubevirt, preallocation)
+		result, err := ud.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
+		replaceAvailableSpaceBlockFunc(func(string) (int64, error) {
+			return sparseImageSize, nil
+		}, func() {
+			result, err = ud.TransferFile(dest)
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ProcessingPhaseResize).To(Equal(result))
+		expected, err := os.ReadFile(src)
+		Expect(err).NotTo(HaveOccurred())
+		written, err := os.ReadFile(dest)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bytes.Equal(expected, written)).To(BeTrue())
+	},
+		table.Entry("without preallocation", false),
+		table.Entry("with preallocation", true),
+	)
+
+	It("should convert a qcow2 upload onto a block device", func() {
+		scratchDir := filepath.Join(tmpDir, "scratch")
+		Expect(os.Mkdir(scratchDir, 0700)).To(Succeed())
+		dest := filepath.Join(tmpDir, "dest")
+		sourceFile, err := os.Open(cirrosFilePath)
+		Expect(err).NotTo(HaveOccurred())
+		ud = NewUploadDataSource(sourceFile, dvKubevirt, false)
+		qemuOperations := &convertRecordingQEMUOperations{
+			QEMUOperations: NewFakeQEMUOperations(nil, nil, fakeInfoOpRetVal{&image.ImgInfo{Format: "qcow2"}, nil}, nil, nil, nil),
+		}
+		dp := NewDataProcessor(ud, dest, tmpDir, scratchDir, "", 0.055, false)
+		replaceQEMUOperations(qemuOperations, func() {
+			replaceAvailableSpaceBlockFunc(func(string) (int64, error) {
+				return sparseImageSize, nil
+			}, func() {
+				Expect(dp.ProcessData()).To(Succeed())
+			})
+		})
+		Expect(qemuOperations.src).To(Equal(filepath.Join(scratchDir, tempFile)))
+		Expect(qemuOperations.dest).To(Equal(dest))
+	})
+
 	It("Close with nil stream should not fail", func() {
-		ud = NewUploadDataSource(nil, dvKubevirt)
+		ud = NewUploadDataSource(nil, dvKubevirt, false)
 		err := ud.Close()
 		Expect(err).NotTo(HaveOccurred())
 	})
@@ -189,7 +251,7 @@ var _ = Describe("Async Upload data source", func() {
 		Expect(err).NotTo(HaveOccurred())
 		err = file.Close()
 		Expect(err).NotTo(HaveOccurred())
-		aud = NewAsyncUploadDataSource(file)
+		aud = NewAsyncUploadDataSource(file, false)
 		result, err := aud.Info()
 		Expect(err).To(HaveOccurred())
 		Expect(ProcessingPhaseError).To(Equal(result))
@@ -199,7 +261,7 @@ var _ = Describe("Async Upload data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		file, err := os.Open(cirrosFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		aud = NewAsyncUploadDataSource(file)
+		aud = NewAsyncUploadDataSource(file, false)
 		result, err := aud.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferScratch).To(Equal(result))
@@ -209,7 +271,7 @@ var _ = Describe("Async Upload data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		file, err := os.Open(tinyCoreFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		aud = NewAsyncUploadDataSource(file)
+		aud = NewAsyncUploadDataSource(file, false)
 		result, err := aud.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
@@ -222,7 +284,7 @@ var _ = Describe("Async Upload data source", func() {
 		sourceFile, err := os.Open(fileName)
 		Expect(err).NotTo(HaveOccurred())
 
-		aud = NewAsyncUploadDataSource(sourceFile)
+		aud = NewAsyncUploadDataSource(sourceFile, false)
 		nextPhase, err := aud.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferScratch).To(Equal(nextPhase))
@@ -243,7 +305,7 @@ var _ = Describe("Async Upload data source", func() {
 		sourceFile, err := os.Open(cirrosFilePath)
 		Expect(err).NotTo(HaveOccurred())
 
-		aud = NewAsyncUploadDataSource(sourceFile)
+		aud = NewAsyncUploadDataSource(sourceFile, false)
 		nextPhase, err := aud.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferScratch).To(Equal(nextPhase))
@@ -258,7 +320,7 @@ var _ = Describe("Async Upload data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		sourceFile, err := os.Open(tinyCoreFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		aud = NewAsyncUploadDataSource(sourceFile)
+		aud = NewAsyncUploadDataSource(sourceFile, false)
 		result, err := aud.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
@@ -272,7 +334,7 @@ var _ = Describe("Async Upload data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		sourceFile, err := os.Open(tinyCoreFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		aud = NewAsyncUploadDataSource(sourceFile)
+		aud = NewAsyncUploadDataSource(sourceFile, false)
 		result, err := aud.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
@@ -282,8 +344,21 @@ var _ = Describe("Async Upload data source", func() {
 	})
 
 	It("Close with nil stream should not fail", func() {
-		aud = NewAsyncUploadDataSource(nil)
+		aud = NewAsyncUploadDataSource(nil, false)
 		err := aud.Close()
 		Expect(err).NotTo(HaveOccurred())
 	})
 })
+
+// convertRecordingQEMUOperations records the source and destination of the conversion
+type convertRecordingQEMUOperations struct {
+	image.QEMUOperations
+	src  string
+	dest string
+}
+
+func (o *convertRecordingQEMUOperations) ConvertToRawStream(src *url.URL, dest string, preallocate bool) error {
+	o.src = src.String()
+	o.dest = dest
+	return o.QEMUOperations.ConvertToRawStream(src, dest, preallocate)
+}
@@ -21,7 +21,24 @@ func ParseEndpoint(endpt string) (*url.URL, error) {
 			return nil, errors.Errorf("endpoint %q is missing or blank", common.ImporterEndpoint)
 		}
 	}
-	return url.Parse(endpt)
+	ep, err := url.Parse(endpt)
+	return ep, redactURLError(err)
+}
+
+// redactSourceURL returns the source URL to log or report without the credentials it may hold, keeping its host and
+// path for debugging
+func redactSourceURL(sourceURL string) string {
+	return util.RedactSourceURL(sourceURL)
+}
+
+// redactURLError redacts the URL of a *url.Error, the error of a URL parse or an HTTP request, so the error can be
+// logged and reported without the credentials the URL may hold
+func redactURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = redactSourceURL(urlErr.URL)
+	}
+	return err
 }
 
 // CleanAll deletes all files at specified paths (recursively)
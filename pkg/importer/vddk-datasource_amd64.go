@@ -176,7 +176,7 @@ func (watcher NbdKitLogWatcherVddk) watchNbdLog() {
 			}
 		}
 
-		logRing.Value = line
+		logRing.Value = util.RedactSourceURLs(line)
 		logRing = logRing.Next()
 	}
 
@@ -253,8 +253,8 @@ type VMwareClient struct {
 func createVMwareClient(endpoint string, accessKey string, secKey string, thumbprint string, uuid string) (*VMwareClient, error) {
 	vmwURL, err := url.Parse(endpoint)
 	if err != nil {
-		klog.Errorf("Unable to parse endpoint: %v", endpoint)
-		return nil, err
+		klog.Errorf("Unable to parse endpoint: %v", redactSourceURL(endpoint))
+		return nil, redactURLError(err)
 	}
 
 	vmwURL.User = url.UserPassword(accessKey, secKey)
@@ -0,0 +1,150 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/image"
+)
+
+// formatDetectingDataSource is implemented by the data sources detecting the format of the image from the headers of
+// their stream
+type formatDetectingDataSource interface {
+	// formatReaders returns the readers detecting the format of the image, set by Info. It is nil until then, or when
+	// the data source does not stream the image, like an Azure page blob written sparsely.
+	formatReaders() *FormatReaders
+}
+
+// vhdxMaxMetadataEnd bounds how much of the stream of a vhdx image is kept in memory to find its metadata
+const vhdxMaxMetadataEnd = 64 * 1024 * 1024
+
+// SourceInfo is what verifying a data source found out about its image
+type SourceInfo struct {
+	// Format is the format of the disk image, under any compression
+	Format string
+	// VirtualSize is the virtual size of the disk image
+	VirtualSize int64
+}
+
+// String returns the summary of the source info reported once the source is verified
+func (i *SourceInfo) String() string {
+	return fmt.Sprintf("format: %s, virtual size: %d", i.Format, i.VirtualSize)
+}
+
+// VerifySource checks the image of the data source without writing it: Info reads the headers to detect the format,
+// then the virtual size is read by qemu-img when the source serves the image to it, otherwise from the stream. The
+// source credentials were already checked when the data source connected. The verification fails if the image does
+// not fit in usableSpace, 0 skips that check.
+func VerifySource(ds DataSourceInterface, usableSpace int64) (*SourceInfo, error) {
+	phase, err := ds.Info()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read the image headers")
+	}
+	info := &SourceInfo{}
+	switch phase {
+	case ProcessingPhaseConvert:
+		if ds.GetURL() == nil {
+			return nil, errors.New("the data source did not provide the image url")
+		}
+		imgInfo, err := qemuOperations.Info(ds.GetURL())
+		if err != nil {
+			return nil, err
+		}
+		info.Format = imgInfo.Format
+		info.VirtualSize = imgInfo.VirtualSize
+	case ProcessingPhaseTransferScratch, ProcessingPhaseTransferDataFile:
+		fds, ok := ds.(formatDetectingDataSource)
+		if !ok || fds.formatReaders() == nil {
+			return nil, errors.New("the data source does not support verification")
+		}
+		info.Format = fds.formatReaders().ImageFormat()
+		if info.VirtualSize, err = streamVirtualSize(fds.formatReaders()); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.Errorf("cannot verify a source in phase %s", phase)
+	}
+	klog.V(1).Infof("Verified the source, %s", info)
+
+	if usableSpace > 0 && info.VirtualSize > usableSpace {
+		return info, errors.Errorf("virtual image size %d is larger than the usable space %d of the requested size", info.VirtualSize, usableSpace)
+	}
+	return info, nil
+}
+
+// streamVirtualSize returns the virtual size of the image read by the format readers, from its header or the size of
+// the stream of an uncompressed raw image. The stream of a compressed raw image is read through to count its bytes,
+// the metadata of a vhdx image is read from the start of its stream.
+func streamVirtualSize(fr *FormatReaders) (int64, error) {
+	if size := fr.VirtualSize(); size > 0 {
+		return size, nil
+	}
+	switch format := fr.ImageFormat(); format {
+	case "raw":
+		size, err := io.Copy(io.Discard, fr.TopReader())
+		if err != nil {
+			return 0, errors.Wrap(err, "could not read the image to find its virtual size")
+		}
+		return size, nil
+	case "vhdx":
+		vhdxInfo, err := image.ReadVhdxInfo(&streamReaderAt{r: fr.TopReader(), limit: vhdxMaxMetadataEnd})
+		if err != nil {
+			return 0, errors.Wrap(err, "could not read the vhdx metadata")
+		}
+		if vhdxInfo == nil {
+			return 0, errors.New("the vhdx image has no valid file type identifier")
+		}
+		return vhdxInfo.VirtualSize, nil
+	default:
+		return 0, errors.Errorf("could not determine the virtual size of the %s image", format)
+	}
+}
+
+// streamReaderAt reads at any offset of a stream, up to limit, by keeping what was read of the stream in memory
+type streamReaderAt struct {
+	r     io.Reader
+	buf   []byte
+	limit int64
+}
+
+func (s *streamReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	end := off + int64(len(p))
+	if off < 0 || end > s.limit {
+		return 0, errors.Errorf("cannot read past the first %d bytes of the stream", s.limit)
+	}
+	if missing := end - int64(len(s.buf)); missing > 0 {
+		chunk := make([]byte, missing)
+		n, err := io.ReadFull(s.r, chunk)
+		s.buf = append(s.buf, chunk[:n]...)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return 0, err
+		}
+	}
+	if off >= int64(len(s.buf)) {
+		return 0, io.EOF
+	}
+	n := copy(p, s.buf[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
@@ -0,0 +1,137 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/image"
+)
+
+const (
+	// cirrosVirtualSize is the virtual size in the header of the cirros qcow2 test image
+	cirrosVirtualSize = int64(46137344)
+	// tinyCoreVirtualSize is the virtual size of the tinyCore test images
+	tinyCoreVirtualSize = int64(18874368)
+)
+
+var _ = Describe("Verify source", func() {
+	var ts *httptest.Server
+
+	BeforeEach(func() {
+		createNbdkitCurl = image.NewMockNbdkitCurl
+		fileServer := http.FileServer(http.Dir(imageDir))
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if user, password, ok := r.BasicAuth(); !ok || user != "user" || password != "password" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fileServer.ServeHTTP(w, r)
+		}))
+	})
+
+	AfterEach(func() {
+		newClientFunc = getS3Client
+		ts.Close()
+	})
+
+	verifyHTTPSource := func(usableSpace int64) (*SourceInfo, error) {
+		ds, err := NewHTTPDataSource(ts.URL+"/"+cirrosFileName, "user", "password", "", cdiv1.DataVolumeKubeVirt)
+		Expect(err).NotTo(HaveOccurred())
+		defer ds.Close()
+		var info *SourceInfo
+		imgInfo := image.ImgInfo{Format: "qcow2", VirtualSize: cirrosVirtualSize}
+		replaceQEMUOperations(NewFakeQEMUOperations(nil, nil, fakeInfoOpRetVal{imgInfo: &imgInfo}, nil, nil, nil), func() {
+			info, err = VerifySource(ds, usableSpace)
+		})
+		return info, err
+	}
+
+	It("Should verify a good http source with qemu-img", func() {
+		info, err := verifyHTTPSource(2 * cirrosVirtualSize)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Format).To(Equal("qcow2"))
+		Expect(info.VirtualSize).To(Equal(cirrosVirtualSize))
+		Expect(info.String()).To(Equal("format: qcow2, virtual size: 46137344"))
+	})
+
+	It("Should fail to verify an image larger than the requested size", func() {
+		_, err := verifyHTTPSource(cirrosVirtualSize - 1)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("is larger than the usable space"))
+	})
+
+	It("Should fail to connect to a source with bad credentials", func() {
+		_, err := NewHTTPDataSource(ts.URL+"/"+cirrosFileName, "user", "wrong", "", cdiv1.DataVolumeKubeVirt)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("401"))
+	})
+
+	table.DescribeTable("Should verify a source which is not served to qemu-img", func(fileName, format string, virtualSize int64) {
+		f, err := os.Open(filepath.Join(imageDir, fileName))
+		Expect(err).NotTo(HaveOccurred())
+		newClientFunc = func(string, *credentials.Credentials, string, string) (S3Client, error) {
+			return &MockS3Client{output: &s3.GetObjectOutput{Body: io.NopCloser(f)}}, nil
+		}
+		ds, err := NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "", "", nil)
+		Expect(err).NotTo(HaveOccurred())
+		defer ds.Close()
+		info, err := VerifySource(ds, 2*virtualSize)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Format).To(Equal(format))
+		Expect(info.VirtualSize).To(Equal(virtualSize))
+	},
+		table.Entry("qcow2 from the header", cirrosFileName, "qcow2", cirrosVirtualSize),
+		table.Entry("vdi from the header", "tinyCore.vdi", "vdi", tinyCoreVirtualSize),
+		table.Entry("gz compressed raw from the stream", "tinyCore.iso.gz", "raw", tinyCoreVirtualSize),
+		table.Entry("xz compressed raw from the stream", "tinyCore.iso.xz", "raw", tinyCoreVirtualSize),
+	)
+
+	table.DescribeTable("Should read the virtual size from the header of", func(format string, hdr []byte, expected int64) {
+		Expect(headerVirtualSize(format, hdr)).To(Equal(expected))
+	},
+		table.Entry("vmdk", "vmdk", func() []byte {
+			hdr := make([]byte, 512)
+			copy(hdr, "KDMV")
+			binary.LittleEndian.PutUint64(hdr[12:], 2048)
+			return hdr
+		}(), int64(1024*1024)),
+		table.Entry("vhd", "vhd", func() []byte {
+			hdr := make([]byte, 512)
+			copy(hdr, "conectix")
+			binary.BigEndian.PutUint64(hdr[48:], 1024*1024)
+			return hdr
+		}(), int64(1024*1024)),
+		table.Entry("vmdk with an overflowing capacity", "vmdk", func() []byte {
+			hdr := make([]byte, 512)
+			binary.LittleEndian.PutUint64(hdr[12:], math.MaxUint64)
+			return hdr
+		}(), int64(0)),
+	)
+
+	It("Should read at the offsets of a stream up to its limit", func() {
+		r := &streamReaderAt{r: bytes.NewReader([]byte("0123456789")), limit: 8}
+		buf := make([]byte, 3)
+		n, err := r.ReadAt(buf, 4)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(buf[:n])).To(Equal("456"))
+		n, err = r.ReadAt(buf, 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(buf[:n])).To(Equal("123"))
+		_, err = r.ReadAt(buf, 6)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,125 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	xzFooterSize = 12
+	xzHeaderSize = 12
+	// xzMaxIndexSize bounds the index read from the source, an image with more blocks is staged in scratch space
+	xzMaxIndexSize = 16 * 1024 * 1024
+)
+
+// nbdkitXzMaxBlock is the largest uncompressed block the nbdkit xz filter accepts, its xz-max-block default
+var nbdkitXzMaxBlock = uint64(512 * 1024 * 1024)
+
+// xzSeekable returns true if nbdkit can decompress the ranges qemu-img reads from the xz image of the passed in size.
+// nbdkit decompresses whole blocks, and refuses a block larger than nbdkitXzMaxBlock, as the single block of a stream
+// compressed without a block size usually is. The block sizes are read from the index at the end of the image, anything
+// but a single stream with a valid index is reported as not seekable.
+func xzSeekable(ctx context.Context, openRange rangeReaderFunc, size uint64) (bool, error) {
+	if size < xzHeaderSize+xzFooterSize {
+		return false, nil
+	}
+	footer, err := readRange(ctx, openRange, size-xzFooterSize, xzFooterSize)
+	if err != nil {
+		return false, err
+	}
+	if !bytes.Equal(footer[10:], []byte("YZ")) {
+		// Stream padding or several streams
+		return false, nil
+	}
+	indexSize := (uint64(binary.LittleEndian.Uint32(footer[4:8])) + 1) * 4
+	if indexSize > xzMaxIndexSize || indexSize > size-xzHeaderSize-xzFooterSize {
+		return false, nil
+	}
+	index, err := readRange(ctx, openRange, size-xzFooterSize-indexSize, indexSize)
+	if err != nil {
+		return false, err
+	}
+	blocksSize, maxBlock, ok := parseXzIndex(index)
+	if !ok || xzHeaderSize+blocksSize+indexSize+xzFooterSize != size {
+		return false, nil
+	}
+	return maxBlock <= nbdkitXzMaxBlock, nil
+}
+
+// parseXzIndex returns the size of the blocks of the stream, with their padding, and the largest uncompressed block
+func parseXzIndex(index []byte) (uint64, uint64, bool) {
+	if len(index) == 0 || index[0] != 0 {
+		return 0, 0, false
+	}
+	pos := 1
+	records, ok := xzVarint(index, &pos)
+	if !ok || records == 0 {
+		return 0, 0, false
+	}
+	var blocksSize, maxBlock uint64
+	for i := uint64(0); i < records; i++ {
+		unpadded, ok := xzVarint(index, &pos)
+		if !ok {
+			return 0, 0, false
+		}
+		uncompressed, ok := xzVarint(index, &pos)
+		if !ok {
+			return 0, 0, false
+		}
+		blocksSize += (unpadded + 3) &^ 3
+		if uncompressed > maxBlock {
+			maxBlock = uncompressed
+		}
+	}
+	return blocksSize, maxBlock, true
+}
+
+// xzVarint decodes the variable length integer of the xz format at pos, and moves pos past it
+func xzVarint(buf []byte, pos *int) (uint64, bool) {
+	var value uint64
+	for i := 0; i < 9; i++ {
+		if *pos >= len(buf) {
+			return 0, false
+		}
+		b := buf[*pos]
+		*pos++
+		value |= uint64(b&0x7f) << (7 * i)
+		if b&0x80 == 0 {
+			return value, true
+		}
+	}
+	return 0, false
+}
+
+func readRange(ctx context.Context, openRange rangeReaderFunc, start, length uint64) ([]byte, error) {
+	body, err := openRange(ctx, int64(start), int64(start+length-1))
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	buf := make([]byte, length)
+	if _, err := io.ReadFull(body, buf); err != nil {
+		return nil, errors.Wrap(err, "could not read the xz index")
+	}
+	return buf, nil
+}
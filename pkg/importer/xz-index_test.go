@@ -0,0 +1,60 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/ulikunitz/xz"
+)
+
+var _ = Describe("xz index", func() {
+	compress := func(data []byte, blockSize int64) []byte {
+		var buf bytes.Buffer
+		w, err := xz.WriterConfig{BlockSize: blockSize}.NewWriter(&buf)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = w.Write(data)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(w.Close()).To(Succeed())
+		return buf.Bytes()
+	}
+
+	table.DescribeTable("should report an image as seekable", func(blockSize int64, maxBlock uint64, trailer []byte, expected bool) {
+		defaultMaxBlock := nbdkitXzMaxBlock
+		nbdkitXzMaxBlock = maxBlock
+		defer func() { nbdkitXzMaxBlock = defaultMaxBlock }()
+		image := append(compress(randomData(64*1024), blockSize), trailer...)
+		seekable, err := xzSeekable(context.Background(), bytesRangeReader(image, nil), uint64(len(image)))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(seekable).To(Equal(expected))
+	},
+		table.Entry("with blocks nbdkit accepts", int64(16*1024), uint64(16*1024), nil, true),
+		table.Entry("not with a single block larger than nbdkit accepts", int64(0), uint64(16*1024), nil, false),
+		table.Entry("not with stream padding", int64(16*1024), uint64(16*1024), make([]byte, 4), false),
+		table.Entry("not with trailing garbage", int64(16*1024), uint64(16*1024), []byte("garbage"), false),
+	)
+
+	It("should not report a tiny image as seekable", func() {
+		seekable, err := xzSeekable(context.Background(), bytesRangeReader([]byte("YZ"), nil), 2)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(seekable).To(BeFalse())
+	})
+})
@@ -29,6 +29,9 @@ const (
 	IncompleteProfile      MetricsKey = "incompleteProfile"
 	DataImportCronOutdated MetricsKey = "dataImportCronOutdated"
 	CloneProgress          MetricsKey = "cloneProgress"
+	ConversionProgress     MetricsKey = "conversionProgress"
+	DataVolumeReconcile    MetricsKey = "dataVolumeReconcile"
+	ControllerLeader       MetricsKey = "controllerLeader"
 )
 
 // MetricOptsList list all CDI metrics
@@ -38,11 +41,26 @@ var MetricOptsList = map[MetricsKey]MetricOpts{
 		Help: "The clone progress in percentage",
 		Type: "Counter",
 	},
+	ConversionProgress: {
+		Name: "conversion_progress_percent",
+		Help: "The qemu-img conversion progress in percentage, -1 while qemu-img has not reported any progress",
+		Type: "Gauge",
+	},
+	ControllerLeader: {
+		Name: "kubevirt_cdi_controller_leader",
+		Help: "CDI controller replica holds the leader election, only reported by the leader",
+		Type: "Gauge",
+	},
 	DataImportCronOutdated: {
 		Name: "kubevirt_cdi_dataimportcron_outdated",
 		Help: "DataImportCron has an outdated import",
 		Type: "Gauge",
 	},
+	DataVolumeReconcile: {
+		Name: "kubevirt_cdi_datavolume_reconcile_duration_seconds",
+		Help: "The duration of the DataVolume reconciles of the leader CDI controller, by controller and result",
+		Type: "Histogram",
+	},
 	IncompleteProfile: {
 		Name: "kubevirt_cdi_incomplete_storageprofiles_total",
 		Help: "Total number of incomplete and hence unusable StorageProfile",
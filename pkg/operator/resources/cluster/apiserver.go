@@ -69,6 +69,18 @@ func getAPIServerClusterPolicyRules() []rbacv1.PolicyRule {
 				"create",
 			},
 		},
+		{
+			APIGroups: []string{
+				"",
+			},
+			Resources: []string{
+				"events",
+			},
+			Verbs: []string{
+				"create",
+				"patch",
+			},
+		},
 		{
 			APIGroups: []string{
 				"",
@@ -115,6 +127,18 @@ func getAPIServerClusterPolicyRules() []rbacv1.PolicyRule {
 				"get",
 			},
 		},
+		{
+			APIGroups: []string{
+				"storage.k8s.io",
+			},
+			Resources: []string{
+				"storageclasses",
+			},
+			Verbs: []string{
+				"list",
+				"get",
+			},
+		},
 		{
 			APIGroups: []string{
 				"cdi.kubevirt.io",
@@ -139,6 +163,30 @@ func getAPIServerClusterPolicyRules() []rbacv1.PolicyRule {
 				"get",
 			},
 		},
+		{
+			APIGroups: []string{
+				"cdi.kubevirt.io",
+			},
+			Resources: []string{
+				"clonegrants",
+			},
+			Verbs: []string{
+				"list",
+				"get",
+				"watch",
+			},
+		},
+		{
+			APIGroups: []string{
+				"cdi.kubevirt.io",
+			},
+			Resources: []string{
+				"storageprofiles",
+			},
+			Verbs: []string{
+				"get",
+			},
+		},
 		{
 			APIGroups: []string{
 				"cdi.kubevirt.io",
@@ -340,6 +388,7 @@ func createCDIValidatingWebhook(namespace string, c client.Client, l logr.Logger
 	allScopes := admissionregistrationv1.AllScopes
 	exactPolicy := admissionregistrationv1.Exact
 	failurePolicy := admissionregistrationv1.Fail
+	ignorePolicy := admissionregistrationv1.Ignore
 	defaultTimeoutSeconds := int32(30)
 	whc := &admissionregistrationv1.ValidatingWebhookConfiguration{
 		TypeMeta: metav1.TypeMeta{
@@ -384,6 +433,40 @@ func createCDIValidatingWebhook(namespace string, c client.Client, l logr.Logger
 				},
 				ObjectSelector: &metav1.LabelSelector{},
 			},
+			{
+				// The config is checked on a best effort basis, not to block the updates of the CDI while the
+				// apiserver is down, for instance its finalizer removal on uninstall
+				Name: "cdi-config-validate.cdi.kubevirt.io",
+				Rules: []admissionregistrationv1.RuleWithOperations{{
+					Operations: []admissionregistrationv1.OperationType{
+						admissionregistrationv1.Create,
+						admissionregistrationv1.Update,
+					},
+					Rule: admissionregistrationv1.Rule{
+						APIGroups:   []string{cdicorev1.SchemeGroupVersion.Group},
+						APIVersions: []string{cdicorev1.SchemeGroupVersion.Version},
+						Resources:   []string{"cdis"},
+						Scope:       &allScopes,
+					},
+				}},
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					Service: &admissionregistrationv1.ServiceReference{
+						Namespace: namespace,
+						Name:      apiServerServiceName,
+						Path:      &path,
+						Port:      &defaultServicePort,
+					},
+				},
+				SideEffects:       &sideEffect,
+				FailurePolicy:     &ignorePolicy,
+				MatchPolicy:       &exactPolicy,
+				NamespaceSelector: &metav1.LabelSelector{},
+				TimeoutSeconds:    &defaultTimeoutSeconds,
+				AdmissionReviewVersions: []string{
+					"v1", "v1beta1",
+				},
+				ObjectSelector: &metav1.LabelSelector{},
+			},
 		},
 	}
 
@@ -395,7 +478,6 @@ func createCDIValidatingWebhook(namespace string, c client.Client, l logr.Logger
 	if bundle != nil {
 		for i := range whc.Webhooks {
 			whc.Webhooks[i].ClientConfig.CABundle = bundle
-			whc.Webhooks[i].FailurePolicy = &failurePolicy
 		}
 	}
 
@@ -57,6 +57,7 @@ func createCRDResources(args *FactoryArgs) []client.Object {
 		createCDIConfigCRD(),
 		createStorageProfileCRD(),
 		createDataSourceCRD(),
+		createCloneGrantCRD(),
 		createDataImportCronCRD(),
 		createObjectTransferCRD(),
 	}
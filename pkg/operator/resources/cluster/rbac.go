@@ -35,6 +35,23 @@ func createAggregateClusterRoles(_ *FactoryArgs) []client.Object {
 }
 
 func getAdminPolicyRules() []rbacv1.PolicyRule {
+	// CloneGrants pre-approve the cross-namespace clones of the namespace, like RBAC only admins manage them
+	return append(getEditPolicyRules(), rbacv1.PolicyRule{
+		APIGroups: []string{
+			"cdi.kubevirt.io",
+		},
+		Resources: []string{
+			"clonegrants",
+		},
+		Verbs: []string{
+			"*",
+		},
+	})
+}
+
+func getEditPolicyRules() []rbacv1.PolicyRule {
+	// diff between admin and edit ClusterRoles is minimal and limited to RBAC
+	// both can CRUD pods/PVCs/etc
 	return []rbacv1.PolicyRule{
 		{
 			APIGroups: []string{
@@ -72,12 +89,6 @@ func getAdminPolicyRules() []rbacv1.PolicyRule {
 	}
 }
 
-func getEditPolicyRules() []rbacv1.PolicyRule {
-	// diff between admin and edit ClusterRoles is minimal and limited to RBAC
-	// both can CRUD pods/PVCs/etc
-	return getAdminPolicyRules()
-}
-
 func getViewPolicyRules() []rbacv1.PolicyRule {
 	return []rbacv1.PolicyRule{
 		{
@@ -86,6 +97,7 @@ func getViewPolicyRules() []rbacv1.PolicyRule {
 			},
 			Resources: []string{
 				"cdiconfigs",
+				"clonegrants",
 				"dataimportcrons",
 				"datasources",
 				"datavolumes",
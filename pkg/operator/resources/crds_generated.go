@@ -89,6 +89,265 @@ spec:
               config:
                 description: CDIConfig at CDI level
                 properties:
+                  allowedWorkerPodPlacement:
+                    description: AllowedWorkerPodPlacement is the node selector labels
+                      and tolerations the DataVolumes are allowed to set in their
+                      workerPodPlacement. Unset means the DataVolumes cannot set a worker
+                      pod placement.
+                    properties:
+                      nodeSelector:
+                        additionalProperties:
+                          type: string
+                        description: NodeSelector is the node selector of the worker
+                          pods, merged with the node selector of the workload node
+                          placement of the CDI CR
+                        type: object
+                      tolerations:
+                        description: Tolerations are the tolerations of the worker
+                          pods, in addition to the tolerations of the workload node
+                          placement of the CDI CR
+                        items:
+                          description: The pod this Toleration is attached to tolerates
+                            any taint that matches the triple <key,value,effect> using
+                            the matching operator <operator>.
+                          properties:
+                            effect:
+                              description: Effect indicates the taint effect to match.
+                                Empty means match all taint effects. When specified,
+                                allowed values are NoSchedule, PreferNoSchedule and
+                                NoExecute.
+                              type: string
+                            key:
+                              description: Key is the taint key that the toleration
+                                applies to. Empty means match all taint keys. If the
+                                key is empty, operator must be Exists; this combination
+                                means to match all values and all keys.
+                              type: string
+                            operator:
+                              description: Operator represents a key's relationship
+                                to the value. Valid operators are Exists and Equal.
+                                Defaults to Equal. Exists is equivalent to wildcard
+                                for value, so that a pod can tolerate all taints of
+                                a particular category.
+                              type: string
+                            tolerationSeconds:
+                              description: TolerationSeconds represents the period
+                                of time the toleration (which must be of effect NoExecute,
+                                otherwise this field is ignored) tolerates the taint.
+                                By default, it is not set, which means tolerate the
+                                taint forever (do not evict). Zero and negative values
+                                will be treated as 0 (evict immediately) by the system.
+                              format: int64
+                              type: integer
+                            value:
+                              description: Value is the taint value the toleration
+                                matches to. If the operator is Exists, the value should
+                                be empty, otherwise just a regular string.
+                              type: string
+                          type: object
+                        type: array
+                    type: object
+                  allowedWorkerPodRegistries:
+                    description: AllowedWorkerPodRegistries are the image registries the
+                      DataVolumes are allowed to set in their workerPodImage. Unset means
+                      the DataVolumes cannot set a worker pod image registry.
+                    items:
+                      type: string
+                    type: array
+                  cloneAnnotationAllowlist:
+                    description: CloneAnnotationAllowlist is the list of
+                      annotations copied from the source to the target PVC of a
+                      host-assisted clone, in addition to the image format and
+                      virtual size recorded by CDI. Other annotations of the
+                      source are not copied.
+                    items:
+                      type: string
+                    type: array
+                  cloneAuthorization:
+                    description: CloneAuthorization overrides or extends the resource
+                      attributes of the SubjectAccessReviews allowing the cross-namespace
+                      clones, and configures the cache of their decisions
+                    properties:
+                      auditLog:
+                        description: AuditLog writes the decisions of the cross-namespace
+                          clone authorizations as JSON lines to the log of the CDI API
+                          server, in addition to the Events recorded on the clone
+                          sources.
+                        type: boolean
+                      cacheTTL:
+                        description: CacheTTL is the time the decisions of the
+                          SubjectAccessReviews allowing the cross-namespace clones are
+                          cached by the CDI API server, such as 30s. Unset or zero
+                          disables the cache.
+                        type: string
+                      mode:
+                        description: Mode tells whether ResourceAttributes extend the
+                          built-in create datavolumes/source and create pods checks, or
+                          override them. The default is Extend.
+                        enum:
+                        - Extend
+                        - Override
+                        type: string
+                      resourceAttributes:
+                        description: ResourceAttributes are the verbs on resources of the
+                          clone source namespace allowing a user or ServiceAccount to
+                          clone from it, any of them allowing the clone. The namespace
+                          and name of the reviewed resource are those of the clone
+                          source.
+                        items:
+                          description: CloneResourceAttributes defines a verb on a
+                            resource of the clone source namespace allowing to clone from
+                            it
+                          properties:
+                            group:
+                              description: Group is the API group of the resource, empty
+                                for the core group
+                              type: string
+                            resource:
+                              description: Resource is the resource, such as datavolumes
+                                or a custom resource of an aggregated role
+                              type: string
+                            subresource:
+                              description: Subresource is the subresource, such as source
+                              type: string
+                            verb:
+                              description: Verb is the verb allowed on the resource, such
+                                as create or a dedicated clone verb
+                              type: string
+                          required:
+                          - resource
+                          - verb
+                          type: object
+                        type: array
+                    type: object
+                  cloneBandwidthLimit:
+                    anyOf:
+                    - type: integer
+                    - type: string
+                    description: CloneBandwidthLimit is the default network
+                      bandwidth limit of the host-assisted clones, in bytes per
+                      second, enforced by the clone source pod streaming to the
+                      target. A DataVolume overrides it with its
+                      cdi.kubevirt.io/storage.clone.bandwidthLimit annotation.
+                      Unset or 0 means no limit.
+                    pattern: ^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$
+                    x-kubernetes-int-or-string: true
+                  cloneCompression:
+                    description: CloneCompression is the default compression
+                      codec of the stream of the host-assisted clones,
+                      negotiated by the clone source with the target, which
+                      falls back to snappy if the target does not support it. A
+                      DataVolume overrides it with its
+                      cdi.kubevirt.io/storage.clone.compression annotation.
+                      Unset means snappy.
+                    enum:
+                    - none
+                    - gzip
+                    - snappy
+                    - zstd
+                    type: string
+                  cloneStreams:
+                    description: CloneStreams is the default number of parallel
+                      streams of the host-assisted clones of block disks, each
+                      streaming a range of the disk over its own connection. The
+                      StorageProfile of the target storage class overrides it.
+                      Unset means a single stream.
+                    format: int32
+                    maximum: 16
+                    minimum: 1
+                    type: integer
+                  clusterDelegatedAuthorizer:
+                    description: ClusterDelegatedAuthorizer is an external HTTP
+                      authorization webhook deciding the cross-namespace clones,
+                      instead of or in addition to the built-in
+                      SubjectAccessReview checks.
+                    properties:
+                      caBundle:
+                        description: CABundle is the PEM encoded CA bundle
+                          verifying the certificate of an https webhook. The
+                          system trust roots are used when empty.
+                        format: byte
+                        type: string
+                      mode:
+                        description: Mode tells whether the webhook decides the
+                          cross-namespace clones in addition to the built-in
+                          SubjectAccessReview checks, both having to allow a
+                          clone, or instead of them. The default is Additional.
+                        enum:
+                        - Additional
+                        - Replace
+                        type: string
+                      timeoutSeconds:
+                        description: TimeoutSeconds is the timeout of a webhook
+                          request, after which the clone is denied. The default
+                          is 10 seconds.
+                        format: int32
+                        type: integer
+                      url:
+                        description: URL is the http or https URL the clone
+                          authorization requests are POSTed to, such as the data
+                          API URL of an Open Policy Agent policy. The request
+                          body holds the clone in its input field, and the
+                          response body tells whether it is allowed in its
+                          result field, either a boolean or an object with
+                          allowed and reason fields.
+                        type: string
+                    required:
+                    - url
+                    type: object
+                  completionHooks:
+                    description: CompletionHooks are the HTTP endpoints notified
+                      once the DataVolumes naming them in their
+                      cdi.kubevirt.io/storage.completionHook annotation
+                      succeeded. Unset means the DataVolumes cannot notify any
+                      endpoint.
+                    items:
+                      description: CompletionHook defines an HTTP endpoint
+                        notified once a DataVolume naming it succeeded
+                      properties:
+                        name:
+                          description: Name is the name the DataVolumes give to
+                            the hook in their
+                            cdi.kubevirt.io/storage.completionHook annotation
+                          type: string
+                        tokenSecret:
+                          description: TokenSecret is the name of the Secret of
+                            the CDI namespace holding the bearer token of the
+                            POST in its token key
+                          type: string
+                        url:
+                          description: URL is the http or https URL receiving a
+                            POST with a JSON body once a DataVolume naming the
+                            hook succeeded
+                          type: string
+                      required:
+                      - name
+                      - url
+                      type: object
+                    type: array
+                  dataImportCronPolling:
+                    description: DataImportCronPolling configures how the CDI controller
+                      polls the sources of the DataImportCrons.
+                    properties:
+                      parallelism:
+                        description: Parallelism is the maximum number of DataImportCron
+                          sources polled concurrently, at most 16. The default is
+                          1. The polls of the same DataImportCron never overlap.
+                        format: int32
+                        type: integer
+                      registryPollsPerMinute:
+                        description: RegistryPollsPerMinute is the maximum number
+                          of polls per minute of the same registry. Unset means no
+                          limit.
+                        format: int32
+                        type: integer
+                    type: object
+                  dataVolumeCompletionTimeout:
+                    description: DataVolumeCompletionTimeout is the time from its
+                      creation within which a DataVolume must succeed, not counting
+                      the time it is paused, after which it fails. Unset means no
+                      timeout.
+                    type: string
                   dataVolumeTTLSeconds:
                     description: DataVolumeTTLSeconds is the time in seconds after
                       DataVolume completion it can be garbage collected. The default
@@ -138,6 +397,12 @@ spec:
                       type: object
                       x-kubernetes-map-type: atomic
                     type: array
+                  importMaxAttempts:
+                    description: ImportMaxAttempts is the number of failed
+                      attempts after which an import DataVolume fails. Unset
+                      means the import is retried indefinitely.
+                    format: int32
+                    type: integer
                   importProxy:
                     description: ImportProxy contains importer pod proxy configuration.
                     properties:
@@ -156,6 +421,12 @@ spec:
                           and/or CIDRs for which the proxy should not be used. Empty
                           means unset and will not result in the import pod env var.
                         type: string
+                      proxyAuthSecret:
+                        description: ProxyAuthSecret is the name of a Secret in the
+                          cdi namespace that contains the username and password keys
+                          used to authenticate against the import proxy. The credentials
+                          in the Secret take precedence over the ones in the proxy URLs.
+                        type: string
                       trustedCAProxy:
                         description: "TrustedCAProxy is the name of a ConfigMap in
                           the cdi namespace that contains a user-provided trusted
@@ -169,11 +440,143 @@ spec:
                           ... -----END CERTIFICATE-----"
                         type: string
                     type: object
+                  importTLSSecurityProfile:
+                    description: ImportTLSSecurityProfile is the TLS security profile of the
+                      importer clients connecting to the HTTP, S3 and ImageIO import sources. The
+                      default is the intermediate profile, TLS 1.2 and above. A DataVolume can
+                      override the minimal version and the ciphers of its source with annotations.
+                    properties:
+                      custom:
+                        description: "custom is a user-defined TLS security profile.
+                          Be extremely careful using a custom profile as invalid configurations
+                          can be catastrophic. An example custom profile looks like
+                          this: \n ciphers: - ECDHE-ECDSA-CHACHA20-POLY1305 - ECDHE-RSA-CHACHA20-POLY1305
+                          - ECDHE-RSA-AES128-GCM-SHA256 - ECDHE-ECDSA-AES128-GCM-SHA256
+                          minTLSVersion: TLSv1.1"
+                        nullable: true
+                        properties:
+                          ciphers:
+                            description: "ciphers is used to specify the cipher algorithms
+                              that are negotiated during the TLS handshake.  Operators
+                              may remove entries their operands do not support.  For
+                              example, to use DES-CBC3-SHA  (yaml): \n ciphers: -
+                              DES-CBC3-SHA"
+                            items:
+                              type: string
+                            type: array
+                          minTLSVersion:
+                            description: "minTLSVersion is used to specify the minimal
+                              version of the TLS protocol that is negotiated during
+                              the TLS handshake. For example, to use TLS versions
+                              1.1, 1.2 and 1.3 (yaml): \n minTLSVersion: TLSv1.1 \n
+                              NOTE: currently the highest minTLSVersion allowed is
+                              VersionTLS12"
+                            enum:
+                            - VersionTLS10
+                            - VersionTLS11
+                            - VersionTLS12
+                            - VersionTLS13
+                            type: string
+                        type: object
+                      intermediate:
+                        description: "intermediate is a TLS security profile based
+                          on: \n https://wiki.mozilla.org/Security/Server_Side_TLS#Intermediate_compatibility_.28recommended.29
+                          \n and looks like this (yaml): \n ciphers: - TLS_AES_128_GCM_SHA256
+                          - TLS_AES_256_GCM_SHA384 - TLS_CHACHA20_POLY1305_SHA256
+                          - ECDHE-ECDSA-AES128-GCM-SHA256 - ECDHE-RSA-AES128-GCM-SHA256
+                          - ECDHE-ECDSA-AES256-GCM-SHA384 - ECDHE-RSA-AES256-GCM-SHA384
+                          - ECDHE-ECDSA-CHACHA20-POLY1305 - ECDHE-RSA-CHACHA20-POLY1305
+                          - DHE-RSA-AES128-GCM-SHA256 - DHE-RSA-AES256-GCM-SHA384
+                          minTLSVersion: TLSv1.2"
+                        nullable: true
+                        type: object
+                      modern:
+                        description: "modern is a TLS security profile based on: \n
+                          https://wiki.mozilla.org/Security/Server_Side_TLS#Modern_compatibility
+                          \n and looks like this (yaml): \n ciphers: - TLS_AES_128_GCM_SHA256
+                          - TLS_AES_256_GCM_SHA384 - TLS_CHACHA20_POLY1305_SHA256
+                          minTLSVersion: TLSv1.3 \n NOTE: Currently unsupported."
+                        nullable: true
+                        type: object
+                      old:
+                        description: "old is a TLS security profile based on: \n https://wiki.mozilla.org/Security/Server_Side_TLS#Old_backward_compatibility
+                          \n and looks like this (yaml): \n ciphers: - TLS_AES_128_GCM_SHA256
+                          - TLS_AES_256_GCM_SHA384 - TLS_CHACHA20_POLY1305_SHA256
+                          - ECDHE-ECDSA-AES128-GCM-SHA256 - ECDHE-RSA-AES128-GCM-SHA256
+                          - ECDHE-ECDSA-AES256-GCM-SHA384 - ECDHE-RSA-AES256-GCM-SHA384
+                          - ECDHE-ECDSA-CHACHA20-POLY1305 - ECDHE-RSA-CHACHA20-POLY1305
+                          - DHE-RSA-AES128-GCM-SHA256 - DHE-RSA-AES256-GCM-SHA384
+                          - DHE-RSA-CHACHA20-POLY1305 - ECDHE-ECDSA-AES128-SHA256
+                          - ECDHE-RSA-AES128-SHA256 - ECDHE-ECDSA-AES128-SHA - ECDHE-RSA-AES128-SHA
+                          - ECDHE-ECDSA-AES256-SHA384 - ECDHE-RSA-AES256-SHA384 -
+                          ECDHE-ECDSA-AES256-SHA - ECDHE-RSA-AES256-SHA - DHE-RSA-AES128-SHA256
+                          - DHE-RSA-AES256-SHA256 - AES128-GCM-SHA256 - AES256-GCM-SHA384
+                          - AES128-SHA256 - AES256-SHA256 - AES128-SHA - AES256-SHA
+                          - DES-CBC3-SHA minTLSVersion: TLSv1.0"
+                        nullable: true
+                        type: object
+                      type:
+                        description: "type is one of Old, Intermediate, Modern or
+                          Custom. Custom provides the ability to specify individual
+                          TLS security profile parameters. Old, Intermediate and Modern
+                          are TLS security profiles based on: \n https://wiki.mozilla.org/Security/Server_Side_TLS#Recommended_configurations
+                          \n The profiles are intent based, so they may change over
+                          time as new ciphers are developed and existing ciphers are
+                          found to be insecure.  Depending on precisely which ciphers
+                          are available to a process, the list may be reduced. \n
+                          Note that the Modern profile is currently not supported
+                          because it is not yet well adopted by common software libraries."
+                        enum:
+                        - Old
+                        - Intermediate
+                        - Modern
+                        - Custom
+                        type: string
+                    type: object
                   insecureRegistries:
                     description: InsecureRegistries is a list of TLS disabled registries
                     items:
                       type: string
                     type: array
+                  maxParallelWorkerPods:
+                    description: MaxParallelWorkerPods is the maximum number of import,
+                      upload and host-assisted clone worker pods CDI runs simultaneously,
+                      the DataVolumes beyond it wait in creation order. Unset means
+                      no limit.
+                    format: int32
+                    type: integer
+                  podIOLimits:
+                    description: PodIOLimits are the disk IO limits of the importer and
+                      clone source pods, applied through the cgroup of the pod where the
+                      container runtime supports it.
+                    properties:
+                      blockIOClass:
+                        description: BlockIOClass is the blockio class of the pods, set
+                          with the blockio.resources.beta.kubernetes.io/pod annotation.
+                          The container runtime applies the IO limits the class is
+                          configured with on the nodes, also when the pod can't write its
+                          own cgroup.
+                        type: string
+                      maxBytesPerSecond:
+                        anyOf:
+                        - type: integer
+                        - type: string
+                        description: MaxBytesPerSecond is the maximum read and write
+                          throughput of the pod on its volume, in bytes per second.
+                        pattern: ^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$
+                        x-kubernetes-int-or-string: true
+                      maxIOPS:
+                        description: MaxIOPS is the maximum number of read and write IO
+                          operations per second of the pod on its volume.
+                        format: int64
+                        type: integer
+                      weight:
+                        description: Weight is the proportional disk IO weight of the pod,
+                          between 1 and 10000, the cgroup v2 io.weight. It is scaled to the
+                          10 to 1000 range of the cgroup v1 blkio.weight.
+                        format: int32
+                        type: integer
+                    type: object
                   podResourceRequirements:
                     description: ResourceRequirements describes the compute resource
                       requirements.
@@ -226,6 +629,29 @@ spec:
                     description: Preallocation controls whether storage for DataVolumes
                       should be allocated in advance.
                     type: boolean
+                  scratchSpace:
+                    description: ScratchSpace configures the volume backing the scratch
+                      space of the importer pods. The default is a PVC.
+                    properties:
+                      backend:
+                        description: Backend is the kind of volume backing the scratch
+                          space, PVC, EmptyDirDisk or EmptyDirMemory. The default
+                          is PVC. A DataVolume can override it with an annotation.
+                        enum:
+                        - PVC
+                        - EmptyDirDisk
+                        - EmptyDirMemory
+                        type: string
+                      maxEmptyDirSize:
+                        anyOf:
+                        - type: integer
+                        - type: string
+                        description: MaxEmptyDirSize is the largest scratch space
+                          backed by an emptyDir, a larger scratch space is backed
+                          by a PVC. Unset means no limit.
+                        pattern: ^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$
+                        x-kubernetes-int-or-string: true
+                    type: object
                   scratchSpaceStorageClass:
                     description: 'Override the storage class to used for scratch space
                       during transfer operations. The scratch space storage class
@@ -329,6 +755,83 @@ spec:
                   uploadProxyURLOverride:
                     description: Override the URL used when uploading to a DataVolume
                     type: string
+                  workerPodImage:
+                    description: WorkerPodImage is the default image registry and
+                      pull policy of the importer, clone and upload pods of the DataVolumes
+                      not setting them.
+                    properties:
+                      pullPolicy:
+                        description: PullPolicy is the image pull policy of the worker
+                          pods, overriding the one of the CDI CR
+                        enum:
+                        - Always
+                        - IfNotPresent
+                        - Never
+                        type: string
+                      registry:
+                        description: Registry replaces the registry and repository
+                          of the importer, cloner and upload server images, keeping
+                          their name, tag and digest, for instance to pull them from
+                          a mirror in an air-gapped cluster
+                        type: string
+                    type: object
+                  workerPodPlacement:
+                    description: WorkerPodPlacement is the default node selector and
+                      tolerations of the importer, clone and upload pods of the DataVolumes
+                      not setting them.
+                    properties:
+                      nodeSelector:
+                        additionalProperties:
+                          type: string
+                        description: NodeSelector is the node selector of the worker
+                          pods, merged with the node selector of the workload node
+                          placement of the CDI CR
+                        type: object
+                      tolerations:
+                        description: Tolerations are the tolerations of the worker
+                          pods, in addition to the tolerations of the workload node
+                          placement of the CDI CR
+                        items:
+                          description: The pod this Toleration is attached to tolerates
+                            any taint that matches the triple <key,value,effect> using
+                            the matching operator <operator>.
+                          properties:
+                            effect:
+                              description: Effect indicates the taint effect to match.
+                                Empty means match all taint effects. When specified,
+                                allowed values are NoSchedule, PreferNoSchedule and
+                                NoExecute.
+                              type: string
+                            key:
+                              description: Key is the taint key that the toleration
+                                applies to. Empty means match all taint keys. If the
+                                key is empty, operator must be Exists; this combination
+                                means to match all values and all keys.
+                              type: string
+                            operator:
+                              description: Operator represents a key's relationship
+                                to the value. Valid operators are Exists and Equal.
+                                Defaults to Equal. Exists is equivalent to wildcard
+                                for value, so that a pod can tolerate all taints of
+                                a particular category.
+                              type: string
+                            tolerationSeconds:
+                              description: TolerationSeconds represents the period
+                                of time the toleration (which must be of effect NoExecute,
+                                otherwise this field is ignored) tolerates the taint.
+                                By default, it is not set, which means tolerate the
+                                taint forever (do not evict). Zero and negative values
+                                will be treated as 0 (evict immediately) by the system.
+                              format: int64
+                              type: integer
+                            value:
+                              description: Value is the taint value the toleration
+                                matches to. If the operator is Exists, the value should
+                                be empty, otherwise just a regular string.
+                              type: string
+                          type: object
+                        type: array
+                    type: object
                 type: object
               imagePullPolicy:
                 description: PullPolicy describes a policy for if/when to pull a container
@@ -2323,65 +2826,330 @@ spec:
               config:
                 description: CDIConfig at CDI level
                 properties:
-                  dataVolumeTTLSeconds:
-                    description: DataVolumeTTLSeconds is the time in seconds after
-                      DataVolume completion it can be garbage collected. The default
-                      is 0 sec. To disable GC use -1.
-                    format: int32
-                    type: integer
-                  featureGates:
-                    description: FeatureGates are a list of specific enabled feature
-                      gates
-                    items:
-                      type: string
-                    type: array
-                  filesystemOverhead:
-                    description: FilesystemOverhead describes the space reserved for
-                      overhead when using Filesystem volumes. A value is between 0
-                      and 1, if not defined it is 0.055 (5.5% overhead)
+                  allowedWorkerPodPlacement:
+                    description: AllowedWorkerPodPlacement is the node selector labels
+                      and tolerations the DataVolumes are allowed to set in their
+                      workerPodPlacement. Unset means the DataVolumes cannot set a worker
+                      pod placement.
                     properties:
-                      global:
-                        description: Global is how much space of a Filesystem volume
-                          should be reserved for overhead. This value is used unless
-                          overridden by a more specific value (per storageClass)
-                        pattern: ^(0(?:\.\d{1,3})?|1)$
-                        type: string
-                      storageClass:
+                      nodeSelector:
                         additionalProperties:
-                          description: 'Percent is a string that can only be a value
-                            between [0,1) (Note: we actually rely on reconcile to
-                            reject invalid values)'
-                          pattern: ^(0(?:\.\d{1,3})?|1)$
                           type: string
-                        description: StorageClass specifies how much space of a Filesystem
-                          volume should be reserved for safety. The keys are the storageClass
-                          and the values are the overhead. This value overrides the
-                          global value
+                        description: NodeSelector is the node selector of the worker
+                          pods, merged with the node selector of the workload node
+                          placement of the CDI CR
                         type: object
+                      tolerations:
+                        description: Tolerations are the tolerations of the worker
+                          pods, in addition to the tolerations of the workload node
+                          placement of the CDI CR
+                        items:
+                          description: The pod this Toleration is attached to tolerates
+                            any taint that matches the triple <key,value,effect> using
+                            the matching operator <operator>.
+                          properties:
+                            effect:
+                              description: Effect indicates the taint effect to match.
+                                Empty means match all taint effects. When specified,
+                                allowed values are NoSchedule, PreferNoSchedule and
+                                NoExecute.
+                              type: string
+                            key:
+                              description: Key is the taint key that the toleration
+                                applies to. Empty means match all taint keys. If the
+                                key is empty, operator must be Exists; this combination
+                                means to match all values and all keys.
+                              type: string
+                            operator:
+                              description: Operator represents a key's relationship
+                                to the value. Valid operators are Exists and Equal.
+                                Defaults to Equal. Exists is equivalent to wildcard
+                                for value, so that a pod can tolerate all taints of
+                                a particular category.
+                              type: string
+                            tolerationSeconds:
+                              description: TolerationSeconds represents the period
+                                of time the toleration (which must be of effect NoExecute,
+                                otherwise this field is ignored) tolerates the taint.
+                                By default, it is not set, which means tolerate the
+                                taint forever (do not evict). Zero and negative values
+                                will be treated as 0 (evict immediately) by the system.
+                              format: int64
+                              type: integer
+                            value:
+                              description: Value is the taint value the toleration
+                                matches to. If the operator is Exists, the value should
+                                be empty, otherwise just a regular string.
+                              type: string
+                          type: object
+                        type: array
                     type: object
-                  imagePullSecrets:
-                    description: The imagePullSecrets used to pull the container images
+                  allowedWorkerPodRegistries:
+                    description: AllowedWorkerPodRegistries are the image registries the
+                      DataVolumes are allowed to set in their workerPodImage. Unset means
+                      the DataVolumes cannot set a worker pod image registry.
                     items:
-                      description: LocalObjectReference contains enough information
-                        to let you locate the referenced object inside the same namespace.
-                      properties:
-                        name:
-                          description: 'Name of the referent. More info: https://kubernetes.io/docs/concepts/overview/working-with-objects/names/#names
-                            TODO: Add other useful fields. apiVersion, kind, uid?'
-                          type: string
-                      type: object
-                      x-kubernetes-map-type: atomic
+                      type: string
                     type: array
-                  importProxy:
-                    description: ImportProxy contains importer pod proxy configuration.
+                  cloneAnnotationAllowlist:
+                    description: CloneAnnotationAllowlist is the list of
+                      annotations copied from the source to the target PVC of a
+                      host-assisted clone, in addition to the image format and
+                      virtual size recorded by CDI. Other annotations of the
+                      source are not copied.
+                    items:
+                      type: string
+                    type: array
+                  cloneAuthorization:
+                    description: CloneAuthorization overrides or extends the resource
+                      attributes of the SubjectAccessReviews allowing the cross-namespace
+                      clones, and configures the cache of their decisions
                     properties:
-                      HTTPProxy:
-                        description: HTTPProxy is the URL http://<username>:<pswd>@<ip>:<port>
-                          of the import proxy for HTTP requests.  Empty means unset
-                          and will not result in the import pod env var.
+                      auditLog:
+                        description: AuditLog writes the decisions of the cross-namespace
+                          clone authorizations as JSON lines to the log of the CDI API
+                          server, in addition to the Events recorded on the clone
+                          sources.
+                        type: boolean
+                      cacheTTL:
+                        description: CacheTTL is the time the decisions of the
+                          SubjectAccessReviews allowing the cross-namespace clones are
+                          cached by the CDI API server, such as 30s. Unset or zero
+                          disables the cache.
                         type: string
-                      HTTPSProxy:
-                        description: HTTPSProxy is the URL https://<username>:<pswd>@<ip>:<port>
+                      mode:
+                        description: Mode tells whether ResourceAttributes extend the
+                          built-in create datavolumes/source and create pods checks, or
+                          override them. The default is Extend.
+                        enum:
+                        - Extend
+                        - Override
+                        type: string
+                      resourceAttributes:
+                        description: ResourceAttributes are the verbs on resources of the
+                          clone source namespace allowing a user or ServiceAccount to
+                          clone from it, any of them allowing the clone. The namespace
+                          and name of the reviewed resource are those of the clone
+                          source.
+                        items:
+                          description: CloneResourceAttributes defines a verb on a
+                            resource of the clone source namespace allowing to clone from
+                            it
+                          properties:
+                            group:
+                              description: Group is the API group of the resource, empty
+                                for the core group
+                              type: string
+                            resource:
+                              description: Resource is the resource, such as datavolumes
+                                or a custom resource of an aggregated role
+                              type: string
+                            subresource:
+                              description: Subresource is the subresource, such as source
+                              type: string
+                            verb:
+                              description: Verb is the verb allowed on the resource, such
+                                as create or a dedicated clone verb
+                              type: string
+                          required:
+                          - resource
+                          - verb
+                          type: object
+                        type: array
+                    type: object
+                  cloneBandwidthLimit:
+                    anyOf:
+                    - type: integer
+                    - type: string
+                    description: CloneBandwidthLimit is the default network
+                      bandwidth limit of the host-assisted clones, in bytes per
+                      second, enforced by the clone source pod streaming to the
+                      target. A DataVolume overrides it with its
+                      cdi.kubevirt.io/storage.clone.bandwidthLimit annotation.
+                      Unset or 0 means no limit.
+                    pattern: ^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$
+                    x-kubernetes-int-or-string: true
+                  cloneCompression:
+                    description: CloneCompression is the default compression
+                      codec of the stream of the host-assisted clones,
+                      negotiated by the clone source with the target, which
+                      falls back to snappy if the target does not support it. A
+                      DataVolume overrides it with its
+                      cdi.kubevirt.io/storage.clone.compression annotation.
+                      Unset means snappy.
+                    enum:
+                    - none
+                    - gzip
+                    - snappy
+                    - zstd
+                    type: string
+                  cloneStreams:
+                    description: CloneStreams is the default number of parallel
+                      streams of the host-assisted clones of block disks, each
+                      streaming a range of the disk over its own connection. The
+                      StorageProfile of the target storage class overrides it.
+                      Unset means a single stream.
+                    format: int32
+                    maximum: 16
+                    minimum: 1
+                    type: integer
+                  clusterDelegatedAuthorizer:
+                    description: ClusterDelegatedAuthorizer is an external HTTP
+                      authorization webhook deciding the cross-namespace clones,
+                      instead of or in addition to the built-in
+                      SubjectAccessReview checks.
+                    properties:
+                      caBundle:
+                        description: CABundle is the PEM encoded CA bundle
+                          verifying the certificate of an https webhook. The
+                          system trust roots are used when empty.
+                        format: byte
+                        type: string
+                      mode:
+                        description: Mode tells whether the webhook decides the
+                          cross-namespace clones in addition to the built-in
+                          SubjectAccessReview checks, both having to allow a
+                          clone, or instead of them. The default is Additional.
+                        enum:
+                        - Additional
+                        - Replace
+                        type: string
+                      timeoutSeconds:
+                        description: TimeoutSeconds is the timeout of a webhook
+                          request, after which the clone is denied. The default
+                          is 10 seconds.
+                        format: int32
+                        type: integer
+                      url:
+                        description: URL is the http or https URL the clone
+                          authorization requests are POSTed to, such as the data
+                          API URL of an Open Policy Agent policy. The request
+                          body holds the clone in its input field, and the
+                          response body tells whether it is allowed in its
+                          result field, either a boolean or an object with
+                          allowed and reason fields.
+                        type: string
+                    required:
+                    - url
+                    type: object
+                  completionHooks:
+                    description: CompletionHooks are the HTTP endpoints notified
+                      once the DataVolumes naming them in their
+                      cdi.kubevirt.io/storage.completionHook annotation
+                      succeeded. Unset means the DataVolumes cannot notify any
+                      endpoint.
+                    items:
+                      description: CompletionHook defines an HTTP endpoint
+                        notified once a DataVolume naming it succeeded
+                      properties:
+                        name:
+                          description: Name is the name the DataVolumes give to
+                            the hook in their
+                            cdi.kubevirt.io/storage.completionHook annotation
+                          type: string
+                        tokenSecret:
+                          description: TokenSecret is the name of the Secret of
+                            the CDI namespace holding the bearer token of the
+                            POST in its token key
+                          type: string
+                        url:
+                          description: URL is the http or https URL receiving a
+                            POST with a JSON body once a DataVolume naming the
+                            hook succeeded
+                          type: string
+                      required:
+                      - name
+                      - url
+                      type: object
+                    type: array
+                  dataImportCronPolling:
+                    description: DataImportCronPolling configures how the CDI controller
+                      polls the sources of the DataImportCrons.
+                    properties:
+                      parallelism:
+                        description: Parallelism is the maximum number of DataImportCron
+                          sources polled concurrently, at most 16. The default is
+                          1. The polls of the same DataImportCron never overlap.
+                        format: int32
+                        type: integer
+                      registryPollsPerMinute:
+                        description: RegistryPollsPerMinute is the maximum number
+                          of polls per minute of the same registry. Unset means no
+                          limit.
+                        format: int32
+                        type: integer
+                    type: object
+                  dataVolumeCompletionTimeout:
+                    description: DataVolumeCompletionTimeout is the time from its
+                      creation within which a DataVolume must succeed, not counting
+                      the time it is paused, after which it fails. Unset means no
+                      timeout.
+                    type: string
+                  dataVolumeTTLSeconds:
+                    description: DataVolumeTTLSeconds is the time in seconds after
+                      DataVolume completion it can be garbage collected. The default
+                      is 0 sec. To disable GC use -1.
+                    format: int32
+                    type: integer
+                  featureGates:
+                    description: FeatureGates are a list of specific enabled feature
+                      gates
+                    items:
+                      type: string
+                    type: array
+                  filesystemOverhead:
+                    description: FilesystemOverhead describes the space reserved for
+                      overhead when using Filesystem volumes. A value is between 0
+                      and 1, if not defined it is 0.055 (5.5% overhead)
+                    properties:
+                      global:
+                        description: Global is how much space of a Filesystem volume
+                          should be reserved for overhead. This value is used unless
+                          overridden by a more specific value (per storageClass)
+                        pattern: ^(0(?:\.\d{1,3})?|1)$
+                        type: string
+                      storageClass:
+                        additionalProperties:
+                          description: 'Percent is a string that can only be a value
+                            between [0,1) (Note: we actually rely on reconcile to
+                            reject invalid values)'
+                          pattern: ^(0(?:\.\d{1,3})?|1)$
+                          type: string
+                        description: StorageClass specifies how much space of a Filesystem
+                          volume should be reserved for safety. The keys are the storageClass
+                          and the values are the overhead. This value overrides the
+                          global value
+                        type: object
+                    type: object
+                  imagePullSecrets:
+                    description: The imagePullSecrets used to pull the container images
+                    items:
+                      description: LocalObjectReference contains enough information
+                        to let you locate the referenced object inside the same namespace.
+                      properties:
+                        name:
+                          description: 'Name of the referent. More info: https://kubernetes.io/docs/concepts/overview/working-with-objects/names/#names
+                            TODO: Add other useful fields. apiVersion, kind, uid?'
+                          type: string
+                      type: object
+                      x-kubernetes-map-type: atomic
+                    type: array
+                  importMaxAttempts:
+                    description: ImportMaxAttempts is the number of failed
+                      attempts after which an import DataVolume fails. Unset
+                      means the import is retried indefinitely.
+                    format: int32
+                    type: integer
+                  importProxy:
+                    description: ImportProxy contains importer pod proxy configuration.
+                    properties:
+                      HTTPProxy:
+                        description: HTTPProxy is the URL http://<username>:<pswd>@<ip>:<port>
+                          of the import proxy for HTTP requests.  Empty means unset
+                          and will not result in the import pod env var.
+                        type: string
+                      HTTPSProxy:
+                        description: HTTPSProxy is the URL https://<username>:<pswd>@<ip>:<port>
                           of the import proxy for HTTPS requests.  Empty means unset
                           and will not result in the import pod env var.
                         type: string
@@ -2390,6 +3158,12 @@ spec:
                           and/or CIDRs for which the proxy should not be used. Empty
                           means unset and will not result in the import pod env var.
                         type: string
+                      proxyAuthSecret:
+                        description: ProxyAuthSecret is the name of a Secret in the
+                          cdi namespace that contains the username and password keys
+                          used to authenticate against the import proxy. The credentials
+                          in the Secret take precedence over the ones in the proxy URLs.
+                        type: string
                       trustedCAProxy:
                         description: "TrustedCAProxy is the name of a ConfigMap in
                           the cdi namespace that contains a user-provided trusted
@@ -2403,11 +3177,143 @@ spec:
                           ... -----END CERTIFICATE-----"
                         type: string
                     type: object
+                  importTLSSecurityProfile:
+                    description: ImportTLSSecurityProfile is the TLS security profile of the
+                      importer clients connecting to the HTTP, S3 and ImageIO import sources. The
+                      default is the intermediate profile, TLS 1.2 and above. A DataVolume can
+                      override the minimal version and the ciphers of its source with annotations.
+                    properties:
+                      custom:
+                        description: "custom is a user-defined TLS security profile.
+                          Be extremely careful using a custom profile as invalid configurations
+                          can be catastrophic. An example custom profile looks like
+                          this: \n ciphers: - ECDHE-ECDSA-CHACHA20-POLY1305 - ECDHE-RSA-CHACHA20-POLY1305
+                          - ECDHE-RSA-AES128-GCM-SHA256 - ECDHE-ECDSA-AES128-GCM-SHA256
+                          minTLSVersion: TLSv1.1"
+                        nullable: true
+                        properties:
+                          ciphers:
+                            description: "ciphers is used to specify the cipher algorithms
+                              that are negotiated during the TLS handshake.  Operators
+                              may remove entries their operands do not support.  For
+                              example, to use DES-CBC3-SHA  (yaml): \n ciphers: -
+                              DES-CBC3-SHA"
+                            items:
+                              type: string
+                            type: array
+                          minTLSVersion:
+                            description: "minTLSVersion is used to specify the minimal
+                              version of the TLS protocol that is negotiated during
+                              the TLS handshake. For example, to use TLS versions
+                              1.1, 1.2 and 1.3 (yaml): \n minTLSVersion: TLSv1.1 \n
+                              NOTE: currently the highest minTLSVersion allowed is
+                              VersionTLS12"
+                            enum:
+                            - VersionTLS10
+                            - VersionTLS11
+                            - VersionTLS12
+                            - VersionTLS13
+                            type: string
+                        type: object
+                      intermediate:
+                        description: "intermediate is a TLS security profile based
+                          on: \n https://wiki.mozilla.org/Security/Server_Side_TLS#Intermediate_compatibility_.28recommended.29
+                          \n and looks like this (yaml): \n ciphers: - TLS_AES_128_GCM_SHA256
+                          - TLS_AES_256_GCM_SHA384 - TLS_CHACHA20_POLY1305_SHA256
+                          - ECDHE-ECDSA-AES128-GCM-SHA256 - ECDHE-RSA-AES128-GCM-SHA256
+                          - ECDHE-ECDSA-AES256-GCM-SHA384 - ECDHE-RSA-AES256-GCM-SHA384
+                          - ECDHE-ECDSA-CHACHA20-POLY1305 - ECDHE-RSA-CHACHA20-POLY1305
+                          - DHE-RSA-AES128-GCM-SHA256 - DHE-RSA-AES256-GCM-SHA384
+                          minTLSVersion: TLSv1.2"
+                        nullable: true
+                        type: object
+                      modern:
+                        description: "modern is a TLS security profile based on: \n
+                          https://wiki.mozilla.org/Security/Server_Side_TLS#Modern_compatibility
+                          \n and looks like this (yaml): \n ciphers: - TLS_AES_128_GCM_SHA256
+                          - TLS_AES_256_GCM_SHA384 - TLS_CHACHA20_POLY1305_SHA256
+                          minTLSVersion: TLSv1.3 \n NOTE: Currently unsupported."
+                        nullable: true
+                        type: object
+                      old:
+                        description: "old is a TLS security profile based on: \n https://wiki.mozilla.org/Security/Server_Side_TLS#Old_backward_compatibility
+                          \n and looks like this (yaml): \n ciphers: - TLS_AES_128_GCM_SHA256
+                          - TLS_AES_256_GCM_SHA384 - TLS_CHACHA20_POLY1305_SHA256
+                          - ECDHE-ECDSA-AES128-GCM-SHA256 - ECDHE-RSA-AES128-GCM-SHA256
+                          - ECDHE-ECDSA-AES256-GCM-SHA384 - ECDHE-RSA-AES256-GCM-SHA384
+                          - ECDHE-ECDSA-CHACHA20-POLY1305 - ECDHE-RSA-CHACHA20-POLY1305
+                          - DHE-RSA-AES128-GCM-SHA256 - DHE-RSA-AES256-GCM-SHA384
+                          - DHE-RSA-CHACHA20-POLY1305 - ECDHE-ECDSA-AES128-SHA256
+                          - ECDHE-RSA-AES128-SHA256 - ECDHE-ECDSA-AES128-SHA - ECDHE-RSA-AES128-SHA
+                          - ECDHE-ECDSA-AES256-SHA384 - ECDHE-RSA-AES256-SHA384 -
+                          ECDHE-ECDSA-AES256-SHA - ECDHE-RSA-AES256-SHA - DHE-RSA-AES128-SHA256
+                          - DHE-RSA-AES256-SHA256 - AES128-GCM-SHA256 - AES256-GCM-SHA384
+                          - AES128-SHA256 - AES256-SHA256 - AES128-SHA - AES256-SHA
+                          - DES-CBC3-SHA minTLSVersion: TLSv1.0"
+                        nullable: true
+                        type: object
+                      type:
+                        description: "type is one of Old, Intermediate, Modern or
+                          Custom. Custom provides the ability to specify individual
+                          TLS security profile parameters. Old, Intermediate and Modern
+                          are TLS security profiles based on: \n https://wiki.mozilla.org/Security/Server_Side_TLS#Recommended_configurations
+                          \n The profiles are intent based, so they may change over
+                          time as new ciphers are developed and existing ciphers are
+                          found to be insecure.  Depending on precisely which ciphers
+                          are available to a process, the list may be reduced. \n
+                          Note that the Modern profile is currently not supported
+                          because it is not yet well adopted by common software libraries."
+                        enum:
+                        - Old
+                        - Intermediate
+                        - Modern
+                        - Custom
+                        type: string
+                    type: object
                   insecureRegistries:
                     description: InsecureRegistries is a list of TLS disabled registries
                     items:
                       type: string
                     type: array
+                  maxParallelWorkerPods:
+                    description: MaxParallelWorkerPods is the maximum number of import,
+                      upload and host-assisted clone worker pods CDI runs simultaneously,
+                      the DataVolumes beyond it wait in creation order. Unset means
+                      no limit.
+                    format: int32
+                    type: integer
+                  podIOLimits:
+                    description: PodIOLimits are the disk IO limits of the importer and
+                      clone source pods, applied through the cgroup of the pod where the
+                      container runtime supports it.
+                    properties:
+                      blockIOClass:
+                        description: BlockIOClass is the blockio class of the pods, set
+                          with the blockio.resources.beta.kubernetes.io/pod annotation.
+                          The container runtime applies the IO limits the class is
+                          configured with on the nodes, also when the pod can't write its
+                          own cgroup.
+                        type: string
+                      maxBytesPerSecond:
+                        anyOf:
+                        - type: integer
+                        - type: string
+                        description: MaxBytesPerSecond is the maximum read and write
+                          throughput of the pod on its volume, in bytes per second.
+                        pattern: ^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$
+                        x-kubernetes-int-or-string: true
+                      maxIOPS:
+                        description: MaxIOPS is the maximum number of read and write IO
+                          operations per second of the pod on its volume.
+                        format: int64
+                        type: integer
+                      weight:
+                        description: Weight is the proportional disk IO weight of the pod,
+                          between 1 and 10000, the cgroup v2 io.weight. It is scaled to the
+                          10 to 1000 range of the cgroup v1 blkio.weight.
+                        format: int32
+                        type: integer
+                    type: object
                   podResourceRequirements:
                     description: ResourceRequirements describes the compute resource
                       requirements.
@@ -2460,6 +3366,29 @@ spec:
                     description: Preallocation controls whether storage for DataVolumes
                       should be allocated in advance.
                     type: boolean
+                  scratchSpace:
+                    description: ScratchSpace configures the volume backing the scratch
+                      space of the importer pods. The default is a PVC.
+                    properties:
+                      backend:
+                        description: Backend is the kind of volume backing the scratch
+                          space, PVC, EmptyDirDisk or EmptyDirMemory. The default
+                          is PVC. A DataVolume can override it with an annotation.
+                        enum:
+                        - PVC
+                        - EmptyDirDisk
+                        - EmptyDirMemory
+                        type: string
+                      maxEmptyDirSize:
+                        anyOf:
+                        - type: integer
+                        - type: string
+                        description: MaxEmptyDirSize is the largest scratch space
+                          backed by an emptyDir, a larger scratch space is backed
+                          by a PVC. Unset means no limit.
+                        pattern: ^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$
+                        x-kubernetes-int-or-string: true
+                    type: object
                   scratchSpaceStorageClass:
                     description: 'Override the storage class to used for scratch space
                       during transfer operations. The scratch space storage class
@@ -2563,6 +3492,83 @@ spec:
                   uploadProxyURLOverride:
                     description: Override the URL used when uploading to a DataVolume
                     type: string
+                  workerPodImage:
+                    description: WorkerPodImage is the default image registry and
+                      pull policy of the importer, clone and upload pods of the DataVolumes
+                      not setting them.
+                    properties:
+                      pullPolicy:
+                        description: PullPolicy is the image pull policy of the worker
+                          pods, overriding the one of the CDI CR
+                        enum:
+                        - Always
+                        - IfNotPresent
+                        - Never
+                        type: string
+                      registry:
+                        description: Registry replaces the registry and repository
+                          of the importer, cloner and upload server images, keeping
+                          their name, tag and digest, for instance to pull them from
+                          a mirror in an air-gapped cluster
+                        type: string
+                    type: object
+                  workerPodPlacement:
+                    description: WorkerPodPlacement is the default node selector and
+                      tolerations of the importer, clone and upload pods of the DataVolumes
+                      not setting them.
+                    properties:
+                      nodeSelector:
+                        additionalProperties:
+                          type: string
+                        description: NodeSelector is the node selector of the worker
+                          pods, merged with the node selector of the workload node
+                          placement of the CDI CR
+                        type: object
+                      tolerations:
+                        description: Tolerations are the tolerations of the worker
+                          pods, in addition to the tolerations of the workload node
+                          placement of the CDI CR
+                        items:
+                          description: The pod this Toleration is attached to tolerates
+                            any taint that matches the triple <key,value,effect> using
+                            the matching operator <operator>.
+                          properties:
+                            effect:
+                              description: Effect indicates the taint effect to match.
+                                Empty means match all taint effects. When specified,
+                                allowed values are NoSchedule, PreferNoSchedule and
+                                NoExecute.
+                              type: string
+                            key:
+                              description: Key is the taint key that the toleration
+                                applies to. Empty means match all taint keys. If the
+                                key is empty, operator must be Exists; this combination
+                                means to match all values and all keys.
+                              type: string
+                            operator:
+                              description: Operator represents a key's relationship
+                                to the value. Valid operators are Exists and Equal.
+                                Defaults to Equal. Exists is equivalent to wildcard
+                                for value, so that a pod can tolerate all taints of
+                                a particular category.
+                              type: string
+                            tolerationSeconds:
+                              description: TolerationSeconds represents the period
+                                of time the toleration (which must be of effect NoExecute,
+                                otherwise this field is ignored) tolerates the taint.
+                                By default, it is not set, which means tolerate the
+                                taint forever (do not evict). Zero and negative values
+                                will be treated as 0 (evict immediately) by the system.
+                              format: int64
+                              type: integer
+                            value:
+                              description: Value is the taint value the toleration
+                                matches to. If the operator is Exists, the value should
+                                be empty, otherwise just a regular string.
+                              type: string
+                          type: object
+                        type: array
+                    type: object
                 type: object
               imagePullPolicy:
                 description: PullPolicy describes a policy for if/when to pull a container
@@ -4532,38 +5538,290 @@ spec:
           spec:
             description: CDIConfigSpec defines specification for user configuration
             properties:
-              dataVolumeTTLSeconds:
-                description: DataVolumeTTLSeconds is the time in seconds after DataVolume
-                  completion it can be garbage collected. The default is 0 sec. To
-                  disable GC use -1.
-                format: int32
-                type: integer
-              featureGates:
-                description: FeatureGates are a list of specific enabled feature gates
-                items:
-                  type: string
-                type: array
-              filesystemOverhead:
-                description: FilesystemOverhead describes the space reserved for overhead
-                  when using Filesystem volumes. A value is between 0 and 1, if not
-                  defined it is 0.055 (5.5% overhead)
+              allowedWorkerPodPlacement:
+                description: AllowedWorkerPodPlacement is the node selector labels and
+                  tolerations the DataVolumes are allowed to set in their
+                  workerPodPlacement. Unset means the DataVolumes cannot set a worker pod
+                  placement.
                 properties:
-                  global:
-                    description: Global is how much space of a Filesystem volume should
-                      be reserved for overhead. This value is used unless overridden
-                      by a more specific value (per storageClass)
-                    pattern: ^(0(?:\.\d{1,3})?|1)$
-                    type: string
-                  storageClass:
+                  nodeSelector:
                     additionalProperties:
-                      description: 'Percent is a string that can only be a value between
-                        [0,1) (Note: we actually rely on reconcile to reject invalid
-                        values)'
-                      pattern: ^(0(?:\.\d{1,3})?|1)$
                       type: string
-                    description: StorageClass specifies how much space of a Filesystem
-                      volume should be reserved for safety. The keys are the storageClass
-                      and the values are the overhead. This value overrides the global
+                    description: NodeSelector is the node selector of the worker pods,
+                      merged with the node selector of the workload node placement
+                      of the CDI CR
+                    type: object
+                  tolerations:
+                    description: Tolerations are the tolerations of the worker pods,
+                      in addition to the tolerations of the workload node placement
+                      of the CDI CR
+                    items:
+                      description: The pod this Toleration is attached to tolerates
+                        any taint that matches the triple <key,value,effect> using
+                        the matching operator <operator>.
+                      properties:
+                        effect:
+                          description: Effect indicates the taint effect to match.
+                            Empty means match all taint effects. When specified, allowed
+                            values are NoSchedule, PreferNoSchedule and NoExecute.
+                          type: string
+                        key:
+                          description: Key is the taint key that the toleration applies
+                            to. Empty means match all taint keys. If the key is empty,
+                            operator must be Exists; this combination means to match
+                            all values and all keys.
+                          type: string
+                        operator:
+                          description: Operator represents a key's relationship to
+                            the value. Valid operators are Exists and Equal. Defaults
+                            to Equal. Exists is equivalent to wildcard for value,
+                            so that a pod can tolerate all taints of a particular
+                            category.
+                          type: string
+                        tolerationSeconds:
+                          description: TolerationSeconds represents the period of
+                            time the toleration (which must be of effect NoExecute,
+                            otherwise this field is ignored) tolerates the taint.
+                            By default, it is not set, which means tolerate the taint
+                            forever (do not evict). Zero and negative values will
+                            be treated as 0 (evict immediately) by the system.
+                          format: int64
+                          type: integer
+                        value:
+                          description: Value is the taint value the toleration matches
+                            to. If the operator is Exists, the value should be empty,
+                            otherwise just a regular string.
+                          type: string
+                      type: object
+                    type: array
+                type: object
+              allowedWorkerPodRegistries:
+                description: AllowedWorkerPodRegistries are the image registries the
+                  DataVolumes are allowed to set in their workerPodImage. Unset means the
+                  DataVolumes cannot set a worker pod image registry.
+                items:
+                  type: string
+                type: array
+              cloneAnnotationAllowlist:
+                description: CloneAnnotationAllowlist is the list of annotations
+                  copied from the source to the target PVC of a host-assisted
+                  clone, in addition to the image format and virtual size recorded
+                  by CDI. Other annotations of the source are not copied.
+                items:
+                  type: string
+                type: array
+              cloneAuthorization:
+                description: CloneAuthorization overrides or extends the resource
+                  attributes of the SubjectAccessReviews allowing the cross-namespace
+                  clones, and configures the cache of their decisions
+                properties:
+                  auditLog:
+                    description: AuditLog writes the decisions of the cross-namespace
+                      clone authorizations as JSON lines to the log of the CDI API
+                      server, in addition to the Events recorded on the clone
+                      sources.
+                    type: boolean
+                  cacheTTL:
+                    description: CacheTTL is the time the decisions of the
+                      SubjectAccessReviews allowing the cross-namespace clones are
+                      cached by the CDI API server, such as 30s. Unset or zero
+                      disables the cache.
+                    type: string
+                  mode:
+                    description: Mode tells whether ResourceAttributes extend the
+                      built-in create datavolumes/source and create pods checks, or
+                      override them. The default is Extend.
+                    enum:
+                    - Extend
+                    - Override
+                    type: string
+                  resourceAttributes:
+                    description: ResourceAttributes are the verbs on resources of the
+                      clone source namespace allowing a user or ServiceAccount to
+                      clone from it, any of them allowing the clone. The namespace
+                      and name of the reviewed resource are those of the clone
+                      source.
+                    items:
+                      description: CloneResourceAttributes defines a verb on a
+                        resource of the clone source namespace allowing to clone from
+                        it
+                      properties:
+                        group:
+                          description: Group is the API group of the resource, empty
+                            for the core group
+                          type: string
+                        resource:
+                          description: Resource is the resource, such as datavolumes
+                            or a custom resource of an aggregated role
+                          type: string
+                        subresource:
+                          description: Subresource is the subresource, such as source
+                          type: string
+                        verb:
+                          description: Verb is the verb allowed on the resource, such
+                            as create or a dedicated clone verb
+                          type: string
+                      required:
+                      - resource
+                      - verb
+                      type: object
+                    type: array
+                type: object
+              cloneBandwidthLimit:
+                anyOf:
+                - type: integer
+                - type: string
+                description: CloneBandwidthLimit is the default network
+                  bandwidth limit of the host-assisted clones, in bytes per
+                  second, enforced by the clone source pod streaming to the
+                  target. A DataVolume overrides it with its
+                  cdi.kubevirt.io/storage.clone.bandwidthLimit annotation. Unset
+                  or 0 means no limit.
+                pattern: ^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$
+                x-kubernetes-int-or-string: true
+              cloneCompression:
+                description: CloneCompression is the default compression codec
+                  of the stream of the host-assisted clones, negotiated by the
+                  clone source with the target, which falls back to snappy if
+                  the target does not support it. A DataVolume overrides it with
+                  its cdi.kubevirt.io/storage.clone.compression annotation.
+                  Unset means snappy.
+                enum:
+                - none
+                - gzip
+                - snappy
+                - zstd
+                type: string
+              cloneStreams:
+                description: CloneStreams is the default number of parallel
+                  streams of the host-assisted clones of block disks, each
+                  streaming a range of the disk over its own connection. The
+                  StorageProfile of the target storage class overrides it. Unset
+                  means a single stream.
+                format: int32
+                maximum: 16
+                minimum: 1
+                type: integer
+              clusterDelegatedAuthorizer:
+                description: ClusterDelegatedAuthorizer is an external HTTP
+                  authorization webhook deciding the cross-namespace clones,
+                  instead of or in addition to the built-in SubjectAccessReview
+                  checks.
+                properties:
+                  caBundle:
+                    description: CABundle is the PEM encoded CA bundle verifying
+                      the certificate of an https webhook. The system trust
+                      roots are used when empty.
+                    format: byte
+                    type: string
+                  mode:
+                    description: Mode tells whether the webhook decides the
+                      cross-namespace clones in addition to the built-in
+                      SubjectAccessReview checks, both having to allow a clone,
+                      or instead of them. The default is Additional.
+                    enum:
+                    - Additional
+                    - Replace
+                    type: string
+                  timeoutSeconds:
+                    description: TimeoutSeconds is the timeout of a webhook
+                      request, after which the clone is denied. The default is
+                      10 seconds.
+                    format: int32
+                    type: integer
+                  url:
+                    description: URL is the http or https URL the clone
+                      authorization requests are POSTed to, such as the data API
+                      URL of an Open Policy Agent policy. The request body holds
+                      the clone in its input field, and the response body tells
+                      whether it is allowed in its result field, either a
+                      boolean or an object with allowed and reason fields.
+                    type: string
+                required:
+                - url
+                type: object
+              completionHooks:
+                description: CompletionHooks are the HTTP endpoints notified
+                  once the DataVolumes naming them in their
+                  cdi.kubevirt.io/storage.completionHook annotation succeeded.
+                  Unset means the DataVolumes cannot notify any endpoint.
+                items:
+                  description: CompletionHook defines an HTTP endpoint notified
+                    once a DataVolume naming it succeeded
+                  properties:
+                    name:
+                      description: Name is the name the DataVolumes give to the
+                        hook in their cdi.kubevirt.io/storage.completionHook
+                        annotation
+                      type: string
+                    tokenSecret:
+                      description: TokenSecret is the name of the Secret of the
+                        CDI namespace holding the bearer token of the POST in
+                        its token key
+                      type: string
+                    url:
+                      description: URL is the http or https URL receiving a POST
+                        with a JSON body once a DataVolume naming the hook
+                        succeeded
+                      type: string
+                  required:
+                  - name
+                  - url
+                  type: object
+                type: array
+              dataImportCronPolling:
+                description: DataImportCronPolling configures how the CDI controller
+                  polls the sources of the DataImportCrons.
+                properties:
+                  parallelism:
+                    description: Parallelism is the maximum number of DataImportCron
+                      sources polled concurrently, at most 16. The default is 1. The
+                      polls of the same DataImportCron never overlap.
+                    format: int32
+                    type: integer
+                  registryPollsPerMinute:
+                    description: RegistryPollsPerMinute is the maximum number of polls
+                      per minute of the same registry. Unset means no limit.
+                    format: int32
+                    type: integer
+                type: object
+              dataVolumeCompletionTimeout:
+                description: DataVolumeCompletionTimeout is the time from its creation
+                  within which a DataVolume must succeed, not counting the time it
+                  is paused, after which it fails. Unset means no timeout.
+                type: string
+              dataVolumeTTLSeconds:
+                description: DataVolumeTTLSeconds is the time in seconds after DataVolume
+                  completion it can be garbage collected. The default is 0 sec. To
+                  disable GC use -1.
+                format: int32
+                type: integer
+              featureGates:
+                description: FeatureGates are a list of specific enabled feature gates
+                items:
+                  type: string
+                type: array
+              filesystemOverhead:
+                description: FilesystemOverhead describes the space reserved for overhead
+                  when using Filesystem volumes. A value is between 0 and 1, if not
+                  defined it is 0.055 (5.5% overhead)
+                properties:
+                  global:
+                    description: Global is how much space of a Filesystem volume should
+                      be reserved for overhead. This value is used unless overridden
+                      by a more specific value (per storageClass)
+                    pattern: ^(0(?:\.\d{1,3})?|1)$
+                    type: string
+                  storageClass:
+                    additionalProperties:
+                      description: 'Percent is a string that can only be a value between
+                        [0,1) (Note: we actually rely on reconcile to reject invalid
+                        values)'
+                      pattern: ^(0(?:\.\d{1,3})?|1)$
+                      type: string
+                    description: StorageClass specifies how much space of a Filesystem
+                      volume should be reserved for safety. The keys are the storageClass
+                      and the values are the overhead. This value overrides the global
                       value
                     type: object
                 type: object
@@ -4580,6 +5838,12 @@ spec:
                   type: object
                   x-kubernetes-map-type: atomic
                 type: array
+              importMaxAttempts:
+                description: ImportMaxAttempts is the number of failed attempts
+                  after which an import DataVolume fails. Unset means the import
+                  is retried indefinitely.
+                format: int32
+                type: integer
               importProxy:
                 description: ImportProxy contains importer pod proxy configuration.
                 properties:
@@ -4598,6 +5862,12 @@ spec:
                       CIDRs for which the proxy should not be used. Empty means unset
                       and will not result in the import pod env var.
                     type: string
+                  proxyAuthSecret:
+                    description: ProxyAuthSecret is the name of a Secret in the cdi
+                      namespace that contains the username and password keys used to
+                      authenticate against the import proxy. The credentials in the
+                      Secret take precedence over the ones in the proxy URLs.
+                    type: string
                   trustedCAProxy:
                     description: "TrustedCAProxy is the name of a ConfigMap in the
                       cdi namespace that contains a user-provided trusted certificate
@@ -4610,11 +5880,137 @@ spec:
                       <base64 encoded cert> ... -----END CERTIFICATE-----"
                     type: string
                 type: object
+              importTLSSecurityProfile:
+                description: ImportTLSSecurityProfile is the TLS security profile of the
+                  importer clients connecting to the HTTP, S3 and ImageIO import sources. The
+                  default is the intermediate profile, TLS 1.2 and above. A DataVolume can
+                  override the minimal version and the ciphers of its source with annotations.
+                properties:
+                  custom:
+                    description: "custom is a user-defined TLS security profile. Be
+                      extremely careful using a custom profile as invalid configurations
+                      can be catastrophic. An example custom profile looks like this:
+                      \n ciphers: - ECDHE-ECDSA-CHACHA20-POLY1305 - ECDHE-RSA-CHACHA20-POLY1305
+                      - ECDHE-RSA-AES128-GCM-SHA256 - ECDHE-ECDSA-AES128-GCM-SHA256
+                      minTLSVersion: TLSv1.1"
+                    nullable: true
+                    properties:
+                      ciphers:
+                        description: "ciphers is used to specify the cipher algorithms
+                          that are negotiated during the TLS handshake.  Operators
+                          may remove entries their operands do not support.  For example,
+                          to use DES-CBC3-SHA  (yaml): \n ciphers: - DES-CBC3-SHA"
+                        items:
+                          type: string
+                        type: array
+                      minTLSVersion:
+                        description: "minTLSVersion is used to specify the minimal
+                          version of the TLS protocol that is negotiated during the
+                          TLS handshake. For example, to use TLS versions 1.1, 1.2
+                          and 1.3 (yaml): \n minTLSVersion: TLSv1.1 \n NOTE: currently
+                          the highest minTLSVersion allowed is VersionTLS12"
+                        enum:
+                        - VersionTLS10
+                        - VersionTLS11
+                        - VersionTLS12
+                        - VersionTLS13
+                        type: string
+                    type: object
+                  intermediate:
+                    description: "intermediate is a TLS security profile based on:
+                      \n https://wiki.mozilla.org/Security/Server_Side_TLS#Intermediate_compatibility_.28recommended.29
+                      \n and looks like this (yaml): \n ciphers: - TLS_AES_128_GCM_SHA256
+                      - TLS_AES_256_GCM_SHA384 - TLS_CHACHA20_POLY1305_SHA256 - ECDHE-ECDSA-AES128-GCM-SHA256
+                      - ECDHE-RSA-AES128-GCM-SHA256 - ECDHE-ECDSA-AES256-GCM-SHA384
+                      - ECDHE-RSA-AES256-GCM-SHA384 - ECDHE-ECDSA-CHACHA20-POLY1305
+                      - ECDHE-RSA-CHACHA20-POLY1305 - DHE-RSA-AES128-GCM-SHA256 -
+                      DHE-RSA-AES256-GCM-SHA384 minTLSVersion: TLSv1.2"
+                    nullable: true
+                    type: object
+                  modern:
+                    description: "modern is a TLS security profile based on: \n https://wiki.mozilla.org/Security/Server_Side_TLS#Modern_compatibility
+                      \n and looks like this (yaml): \n ciphers: - TLS_AES_128_GCM_SHA256
+                      - TLS_AES_256_GCM_SHA384 - TLS_CHACHA20_POLY1305_SHA256 minTLSVersion:
+                      TLSv1.3 \n NOTE: Currently unsupported."
+                    nullable: true
+                    type: object
+                  old:
+                    description: "old is a TLS security profile based on: \n https://wiki.mozilla.org/Security/Server_Side_TLS#Old_backward_compatibility
+                      \n and looks like this (yaml): \n ciphers: - TLS_AES_128_GCM_SHA256
+                      - TLS_AES_256_GCM_SHA384 - TLS_CHACHA20_POLY1305_SHA256 - ECDHE-ECDSA-AES128-GCM-SHA256
+                      - ECDHE-RSA-AES128-GCM-SHA256 - ECDHE-ECDSA-AES256-GCM-SHA384
+                      - ECDHE-RSA-AES256-GCM-SHA384 - ECDHE-ECDSA-CHACHA20-POLY1305
+                      - ECDHE-RSA-CHACHA20-POLY1305 - DHE-RSA-AES128-GCM-SHA256 -
+                      DHE-RSA-AES256-GCM-SHA384 - DHE-RSA-CHACHA20-POLY1305 - ECDHE-ECDSA-AES128-SHA256
+                      - ECDHE-RSA-AES128-SHA256 - ECDHE-ECDSA-AES128-SHA - ECDHE-RSA-AES128-SHA
+                      - ECDHE-ECDSA-AES256-SHA384 - ECDHE-RSA-AES256-SHA384 - ECDHE-ECDSA-AES256-SHA
+                      - ECDHE-RSA-AES256-SHA - DHE-RSA-AES128-SHA256 - DHE-RSA-AES256-SHA256
+                      - AES128-GCM-SHA256 - AES256-GCM-SHA384 - AES128-SHA256 - AES256-SHA256
+                      - AES128-SHA - AES256-SHA - DES-CBC3-SHA minTLSVersion: TLSv1.0"
+                    nullable: true
+                    type: object
+                  type:
+                    description: "type is one of Old, Intermediate, Modern or Custom.
+                      Custom provides the ability to specify individual TLS security
+                      profile parameters. Old, Intermediate and Modern are TLS security
+                      profiles based on: \n https://wiki.mozilla.org/Security/Server_Side_TLS#Recommended_configurations
+                      \n The profiles are intent based, so they may change over time
+                      as new ciphers are developed and existing ciphers are found
+                      to be insecure.  Depending on precisely which ciphers are available
+                      to a process, the list may be reduced. \n Note that the Modern
+                      profile is currently not supported because it is not yet well
+                      adopted by common software libraries."
+                    enum:
+                    - Old
+                    - Intermediate
+                    - Modern
+                    - Custom
+                    type: string
+                type: object
               insecureRegistries:
                 description: InsecureRegistries is a list of TLS disabled registries
                 items:
                   type: string
                 type: array
+              maxParallelWorkerPods:
+                description: MaxParallelWorkerPods is the maximum number of import,
+                  upload and host-assisted clone worker pods CDI runs simultaneously,
+                  the DataVolumes beyond it wait in creation order. Unset means no
+                  limit.
+                format: int32
+                type: integer
+              podIOLimits:
+                description: PodIOLimits are the disk IO limits of the importer and
+                  clone source pods, applied through the cgroup of the pod where the
+                  container runtime supports it.
+                properties:
+                  blockIOClass:
+                    description: BlockIOClass is the blockio class of the pods, set
+                      with the blockio.resources.beta.kubernetes.io/pod annotation.
+                      The container runtime applies the IO limits the class is
+                      configured with on the nodes, also when the pod can't write its
+                      own cgroup.
+                    type: string
+                  maxBytesPerSecond:
+                    anyOf:
+                    - type: integer
+                    - type: string
+                    description: MaxBytesPerSecond is the maximum read and write
+                      throughput of the pod on its volume, in bytes per second.
+                    pattern: ^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$
+                    x-kubernetes-int-or-string: true
+                  maxIOPS:
+                    description: MaxIOPS is the maximum number of read and write IO
+                      operations per second of the pod on its volume.
+                    format: int64
+                    type: integer
+                  weight:
+                    description: Weight is the proportional disk IO weight of the pod,
+                      between 1 and 10000, the cgroup v2 io.weight. It is scaled to the 10
+                      to 1000 range of the cgroup v1 blkio.weight.
+                    format: int32
+                    type: integer
+                type: object
               podResourceRequirements:
                 description: ResourceRequirements describes the compute resource requirements.
                 properties:
@@ -4665,6 +6061,29 @@ spec:
                 description: Preallocation controls whether storage for DataVolumes
                   should be allocated in advance.
                 type: boolean
+              scratchSpace:
+                description: ScratchSpace configures the volume backing the scratch
+                  space of the importer pods. The default is a PVC.
+                properties:
+                  backend:
+                    description: Backend is the kind of volume backing the scratch
+                      space, PVC, EmptyDirDisk or EmptyDirMemory. The default is PVC.
+                      A DataVolume can override it with an annotation.
+                    enum:
+                    - PVC
+                    - EmptyDirDisk
+                    - EmptyDirMemory
+                    type: string
+                  maxEmptyDirSize:
+                    anyOf:
+                    - type: integer
+                    - type: string
+                    description: MaxEmptyDirSize is the largest scratch space backed
+                      by an emptyDir, a larger scratch space is backed by a PVC. Unset
+                      means no limit.
+                    pattern: ^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$
+                    x-kubernetes-int-or-string: true
+                type: object
               scratchSpaceStorageClass:
                 description: 'Override the storage class to used for scratch space
                   during transfer operations. The scratch space storage class is determined
@@ -4762,6 +6181,82 @@ spec:
               uploadProxyURLOverride:
                 description: Override the URL used when uploading to a DataVolume
                 type: string
+              workerPodImage:
+                description: WorkerPodImage is the default image registry and pull
+                  policy of the importer, clone and upload pods of the DataVolumes
+                  not setting them.
+                properties:
+                  pullPolicy:
+                    description: PullPolicy is the image pull policy of the worker
+                      pods, overriding the one of the CDI CR
+                    enum:
+                    - Always
+                    - IfNotPresent
+                    - Never
+                    type: string
+                  registry:
+                    description: Registry replaces the registry and repository of
+                      the importer, cloner and upload server images, keeping their
+                      name, tag and digest, for instance to pull them from a mirror
+                      in an air-gapped cluster
+                    type: string
+                type: object
+              workerPodPlacement:
+                description: WorkerPodPlacement is the default node selector and tolerations
+                  of the importer, clone and upload pods of the DataVolumes not setting
+                  them.
+                properties:
+                  nodeSelector:
+                    additionalProperties:
+                      type: string
+                    description: NodeSelector is the node selector of the worker pods,
+                      merged with the node selector of the workload node placement
+                      of the CDI CR
+                    type: object
+                  tolerations:
+                    description: Tolerations are the tolerations of the worker pods,
+                      in addition to the tolerations of the workload node placement
+                      of the CDI CR
+                    items:
+                      description: The pod this Toleration is attached to tolerates
+                        any taint that matches the triple <key,value,effect> using
+                        the matching operator <operator>.
+                      properties:
+                        effect:
+                          description: Effect indicates the taint effect to match.
+                            Empty means match all taint effects. When specified, allowed
+                            values are NoSchedule, PreferNoSchedule and NoExecute.
+                          type: string
+                        key:
+                          description: Key is the taint key that the toleration applies
+                            to. Empty means match all taint keys. If the key is empty,
+                            operator must be Exists; this combination means to match
+                            all values and all keys.
+                          type: string
+                        operator:
+                          description: Operator represents a key's relationship to
+                            the value. Valid operators are Exists and Equal. Defaults
+                            to Equal. Exists is equivalent to wildcard for value,
+                            so that a pod can tolerate all taints of a particular
+                            category.
+                          type: string
+                        tolerationSeconds:
+                          description: TolerationSeconds represents the period of
+                            time the toleration (which must be of effect NoExecute,
+                            otherwise this field is ignored) tolerates the taint.
+                            By default, it is not set, which means tolerate the taint
+                            forever (do not evict). Zero and negative values will
+                            be treated as 0 (evict immediately) by the system.
+                          format: int64
+                          type: integer
+                        value:
+                          description: Value is the taint value the toleration matches
+                            to. If the operator is Exists, the value should be empty,
+                            otherwise just a regular string.
+                          type: string
+                      type: object
+                    type: array
+                type: object
             type: object
           status:
             description: CDIConfigStatus provides the most recently observed status
@@ -4868,6 +6363,12 @@ spec:
                       CIDRs for which the proxy should not be used. Empty means unset
                       and will not result in the import pod env var.
                     type: string
+                  proxyAuthSecret:
+                    description: ProxyAuthSecret is the name of a Secret in the cdi
+                      namespace that contains the username and password keys used to
+                      authenticate against the import proxy. The credentials in the
+                      Secret take precedence over the ones in the proxy URLs.
+                    type: string
                   trustedCAProxy:
                     description: "TrustedCAProxy is the name of a ConfigMap in the
                       cdi namespace that contains a user-provided trusted certificate
@@ -4902,6 +6403,107 @@ status:
     plural: ""
   conditions: null
   storedVersions: null
+`,
+	"clonegrant": `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  annotations:
+    controller-gen.kubebuilder.io/version: v0.11.3
+  creationTimestamp: null
+  name: clonegrants.cdi.kubevirt.io
+spec:
+  group: cdi.kubevirt.io
+  names:
+    categories:
+    - all
+    kind: CloneGrant
+    listKind: CloneGrantList
+    plural: clonegrants
+    shortNames:
+    - cg
+    - cgs
+    singular: clonegrant
+  scope: Namespaced
+  versions:
+  - name: v1beta1
+    schema:
+      openAPIV3Schema:
+        description: CloneGrant pre-approves the cross-namespace clones of the PVCs
+          and VolumeSnapshots of its namespace
+        properties:
+          apiVersion:
+            description: 'APIVersion defines the versioned schema of this representation
+              of an object. Servers should convert recognized schemas to the latest
+              internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources'
+            type: string
+          kind:
+            description: 'Kind is a string value representing the REST resource this
+              object represents. Servers may infer this from the endpoint the client
+              submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds'
+            type: string
+          metadata:
+            type: object
+          spec:
+            description: CloneGrantSpec defines the clones pre-approved by the CloneGrant
+            properties:
+              serviceAccounts:
+                description: ServiceAccounts are the ServiceAccounts that may clone
+                  the sources to their namespace
+                items:
+                  description: CloneGrantServiceAccount is a ServiceAccount granted
+                    by a CloneGrant
+                  properties:
+                    name:
+                      description: Name is the name of the ServiceAccount
+                      type: string
+                    namespace:
+                      description: Namespace is the namespace of the ServiceAccount
+                      type: string
+                  required:
+                  - name
+                  - namespace
+                  type: object
+                type: array
+              sources:
+                description: Sources are the PVCs and VolumeSnapshots of the namespace
+                  the grant applies to, all of them when empty
+                items:
+                  description: CloneGrantSource is a PVC or VolumeSnapshot granted
+                    by a CloneGrant
+                  properties:
+                    kind:
+                      description: Kind is the kind of the source, PersistentVolumeClaim
+                        or VolumeSnapshot
+                      enum:
+                      - PersistentVolumeClaim
+                      - VolumeSnapshot
+                      type: string
+                    name:
+                      description: Name is the name of the source
+                      type: string
+                  required:
+                  - kind
+                  - name
+                  type: object
+                type: array
+              targetNamespaces:
+                description: TargetNamespaces are the namespaces any user may clone
+                  the sources to
+                items:
+                  type: string
+                type: array
+            type: object
+        required:
+        - spec
+        type: object
+    served: true
+    storage: true
+status:
+  acceptedNames:
+    kind: ""
+    plural: ""
+  conditions: null
+  storedVersions: null
 `,
 	"dataimportcron": `apiVersion: apiextensions.k8s.io/v1
 kind: CustomResourceDefinition
@@ -4969,6 +6571,13 @@ spec:
                 description: Schedule specifies in cron format when and how often
                   to look for new imports
                 type: string
+              sourceTagPattern:
+                description: SourceTagPattern is a regular expression selecting
+                  the tags of the registry URL source repository to import. The
+                  matching tag with the highest semantic version is imported, with
+                  an optional "v" prefix. Tags that do not match or are not
+                  semantic versions are ignored, as is the tag of the source URL.
+                type: string
               template:
                 description: Template specifies template for the DVs to be created
                 properties:
@@ -5238,25 +6847,128 @@ spec:
                               PersistentVolume backing this claim.
                             type: string
                         type: object
+                      snapshotTarget:
+                        description: SnapshotTarget makes a VolumeSnapshot of the
+                          imported PVC the final artifact of the DataVolume
+                        properties:
+                          name:
+                            description: Name is the name of the VolumeSnapshot, the
+                              name of the DataVolume if empty
+                            type: string
+                          pvcRetainPolicy:
+                            description: PVCRetainPolicy tells whether the PVC is
+                              deleted once the VolumeSnapshot is ready, Delete if
+                              empty
+                            enum:
+                            - Delete
+                            - Retain
+                            type: string
+                          volumeSnapshotClassName:
+                            description: VolumeSnapshotClassName is the class of the
+                              VolumeSnapshot. If empty, a class of the provisioner
+                              of the storage class of the PVC is used
+                            type: string
+                        type: object
                       source:
                         description: Source is the src of the data for the requested
                           DataVolume
                         properties:
+                          azure:
+                            description: DataVolumeSourceAzure provides the parameters
+                              to create a Data Volume from an Azure Blob Storage source
+                            properties:
+                              certConfigMap:
+                                description: CertConfigMap is a configmap reference,
+                                  containing a Certificate Authority(CA) public key
+                                  of the Blob service
+                                type: string
+                              secretRef:
+                                description: SecretRef provides the secret holding
+                                  a SAS token in its sasToken key, or the tenantId,
+                                  clientId and clientSecret of a service principal,
+                                  the blob is read anonymously without it
+                                type: string
+                              url:
+                                description: URL is the url of the blob, as in https://<account>.blob.core.windows.net/<container>/<blob>,
+                                  without a SAS token
+                                type: string
+                            required:
+                            - url
+                            type: object
                           blank:
                             description: DataVolumeBlankImage provides the parameters
                               to create a new raw blank image for the PVC
                             type: object
+                          ftp:
+                            description: DataVolumeSourceFTP provides the parameters
+                              to create a Data Volume from an FTP or FTPS source
+                            properties:
+                              certConfigMap:
+                                description: CertConfigMap is a configmap reference,
+                                  containing a Certificate Authority(CA) public key
+                                  of the FTPS server
+                                type: string
+                              secretRef:
+                                description: SecretRef provides the secret holding
+                                  the user name and password of the FTP server in
+                                  its accessKeyId and secretKey keys, the login is
+                                  anonymous without it
+                                type: string
+                              url:
+                                description: URL is the url of the image on the FTP
+                                  server, as in ftp://host[:port]/path, or ftps://host[:port]/path
+                                  for FTP over explicit TLS
+                                type: string
+                            required:
+                            - url
+                            type: object
                           gcs:
                             description: DataVolumeSourceGCS provides the parameters
                               to create a Data Volume from an GCS source
                             properties:
+                              generation:
+                                description: Generation pins the import to this
+                                  generation of the object, the import fails if
+                                  the generation does not exist. By default the
+                                  import reads the latest generation, pinned for
+                                  the whole download so a resumed download does
+                                  not mix two generations
+                                format: int64
+                                type: integer
                               secretRef:
                                 description: SecretRef provides the secret reference
                                   needed to access the GCS source
                                 type: string
+                              tokenCredentials:
+                                description: TokenCredentials gets the credentials
+                                  through workload identity federation with a projected
+                                  service account token, instead of the SecretRef
+                                  key file
+                                properties:
+                                  audience:
+                                    description: Audience is the audience of the projected
+                                      token. For S3 it defaults to sts.amazonaws.com,
+                                      for GCS it is required and is the workload identity
+                                      pool provider, as in //iam.googleapis.com/projects/<number>/locations/global/workloadIdentityPools/<pool>/providers/<provider>
+                                    type: string
+                                  roleARN:
+                                    description: RoleARN is the ARN of the IAM role
+                                      assumed with the token, required for S3
+                                    type: string
+                                  serviceAccount:
+                                    description: ServiceAccount is the email of the
+                                      GCP service account impersonated with the federated
+                                      token, optional for GCS
+                                    type: string
+                                type: object
                               url:
                                 description: URL is the url of the GCS source
                                 type: string
+                              userProject:
+                                description: UserProject is the project billed for
+                                  the requests to a requester-pays bucket, required
+                                  to read from such a bucket
+                                type: string
                             required:
                             - url
                             type: object
@@ -5307,14 +7019,46 @@ spec:
                                 description: DiskID provides id of a disk to be imported
                                 type: string
                               secretRef:
-                                description: SecretRef provides the secret reference
-                                  needed to access the ovirt-engine
+                                description: SecretRef provides the secret reference
+                                  needed to access the ovirt-engine
+                                type: string
+                              url:
+                                description: URL is the URL of the ovirt-engine
+                                type: string
+                            required:
+                            - diskId
+                            - url
+                            type: object
+                          inline:
+                            description: DataVolumeSourceInline provides the parameters
+                              to create a Data Volume from a small image embedded in
+                              the DataVolume
+                            properties:
+                              data:
+                                description: Data is the base64 encoded image, optionally
+                                  gzip compressed, of at most 1MiB once encoded
+                                type: string
+                            required:
+                            - data
+                            type: object
+                          nbd:
+                            description: DataVolumeSourceNBD provides the
+                              parameters to create a Data Volume from a disk
+                              exported over NBD
+                            properties:
+                              secretRef:
+                                description: SecretRef provides the secret
+                                  holding the ca-cert.pem of the NBD server CA,
+                                  and optionally the client-cert.pem and
+                                  client-key.pem of a client certificate,
+                                  required for NBD over TLS
                                 type: string
                               url:
-                                description: URL is the URL of the ovirt-engine
+                                description: URL is the url of the NBD export,
+                                  as in nbd://host[:port]/export, or
+                                  nbds://host[:port]/export for NBD over TLS
                                 type: string
                             required:
-                            - diskId
                             - url
                             type: object
                           pvc:
@@ -5339,6 +7083,37 @@ spec:
                                 description: CertConfigMap provides a reference to
                                   the Registry certs
                                 type: string
+                              disks:
+                                description: Disks lists the disks of an image holding
+                                  several of them, each imported into its own PVC
+                                items:
+                                  description: RegistryDisk selects a disk of a multi-disk
+                                    registry image, and the PVC it is imported into
+                                  properties:
+                                    path:
+                                      description: Path is the path of the disk in
+                                        the image, relative to its disk directory
+                                      type: string
+                                    pvcName:
+                                      description: PVCName is the name of the PVC
+                                        the disk is imported into, the PVC of the
+                                        DataVolume when empty
+                                      type: string
+                                    size:
+                                      anyOf:
+                                      - type: integer
+                                      - type: string
+                                      description: Size is the size requested
+                                        for the PVC of the disk, the size of the
+                                        DataVolume when not set. The disk
+                                        imported into the PVC of the DataVolume
+                                        gets the size of the DataVolume.
+                                      pattern: ^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$
+                                      x-kubernetes-int-or-string: true
+                                  required:
+                                  - path
+                                  type: object
+                                type: array
                               imageStream:
                                 description: ImageStream is the name of image stream
                                   for import
@@ -5356,6 +7131,51 @@ spec:
                                   (starting with the scheme: docker, oci-archive)'
                                 type: string
                             type: object
+                          remote:
+                            description: DataVolumeSourceRemote provides the parameters
+                              to clone a Data Volume from a disk exported by a remote
+                              cluster
+                            properties:
+                              certConfigMap:
+                                description: CertConfigMap is a configmap reference,
+                                  containing a Certificate Authority(CA) public key
+                                  of the remote endpoint
+                                type: string
+                              secretRef:
+                                description: SecretRef provides the secret holding
+                                  the token authorizing the export, in its token key
+                                type: string
+                              url:
+                                description: URL is the url of the disk image exported
+                                  by the remote cluster
+                                type: string
+                            required:
+                            - secretRef
+                            - url
+                            type: object
+                          remotePVC:
+                            description: DataVolumeSourceRemotePVC provides the
+                              parameters to clone a Data Volume from a PVC of a remote
+                              cluster
+                            properties:
+                              kubeconfigSecretRef:
+                                description: KubeconfigSecretRef provides the secret
+                                  holding the kubeconfig of the remote cluster, in
+                                  its kubeconfig key
+                                type: string
+                              name:
+                                description: The name of the source PVC in the remote
+                                  cluster
+                                type: string
+                              namespace:
+                                description: The namespace of the source PVC in the
+                                  remote cluster
+                                type: string
+                            required:
+                            - kubeconfigSecretRef
+                            - name
+                            - namespace
+                            type: object
                           s3:
                             description: DataVolumeSourceS3 provides the parameters
                               to create a Data Volume from an S3 source
@@ -5365,16 +7185,71 @@ spec:
                                   containing a Certificate Authority(CA) public key,
                                   and a base64 encoded pem certificate
                                 type: string
+                              kmsKeyId:
+                                description: KMSKeyID is the ID or ARN of the AWS
+                                  KMS key the object is encrypted with (SSE-KMS),
+                                  the import fails if the object is not encrypted
+                                  with this key
+                                type: string
                               secretRef:
                                 description: SecretRef provides the secret reference
                                   needed to access the S3 source
                                 type: string
+                              tokenCredentials:
+                                description: TokenCredentials gets the credentials
+                                  by assuming an IAM role with a projected service
+                                  account token (IRSA), instead of the SecretRef keys
+                                properties:
+                                  audience:
+                                    description: Audience is the audience of the projected
+                                      token. For S3 it defaults to sts.amazonaws.com,
+                                      for GCS it is required and is the workload identity
+                                      pool provider, as in //iam.googleapis.com/projects/<number>/locations/global/workloadIdentityPools/<pool>/providers/<provider>
+                                    type: string
+                                  roleARN:
+                                    description: RoleARN is the ARN of the IAM role
+                                      assumed with the token, required for S3
+                                    type: string
+                                  serviceAccount:
+                                    description: ServiceAccount is the email of the
+                                      GCP service account impersonated with the federated
+                                      token, optional for GCS
+                                    type: string
+                                type: object
                               url:
                                 description: URL is the url of the S3 source
                                 type: string
                             required:
                             - url
                             type: object
+                          sftp:
+                            description: DataVolumeSourceSFTP provides the parameters
+                              to create a Data Volume from an SFTP source
+                            properties:
+                              hostKey:
+                                description: HostKey is the public host key the SFTP
+                                  server must present, in the authorized_keys format,
+                                  as in "ssh-ed25519 AAAA...", or its SHA256 fingerprint,
+                                  as in "SHA256:...", printed by ssh-keygen -l
+                                type: string
+                              secretRef:
+                                description: SecretRef provides the secret holding
+                                  the user name in its username key, and its private
+                                  key in the ssh-privatekey key, with the passphrase
+                                  of an encrypted key in the passphrase key, or its
+                                  password in the password key
+                                type: string
+                              url:
+                                description: URL is the url of the image on the SFTP
+                                  server, as in sftp://host[:port]/path, the path
+                                  is relative to the home directory of the user if
+                                  it starts with /~/
+                                type: string
+                            required:
+                            - hostKey
+                            - secretRef
+                            - url
+                            type: object
                           snapshot:
                             description: DataVolumeSourceSnapshot provides the parameters
                               to create a Data Volume from an existing VolumeSnapshot
@@ -5645,6 +7520,85 @@ spec:
                               PersistentVolume backing this claim.
                             type: string
                         type: object
+                      workerPodImage:
+                        description: WorkerPodImage is the image registry and pull
+                          policy of the importer, clone and upload pods of the DataVolume.
+                          Unset fields default to the ones of the CDIConfig.
+                        properties:
+                          pullPolicy:
+                            description: PullPolicy is the image pull policy of the
+                              worker pods, overriding the one of the CDI CR
+                            enum:
+                            - Always
+                            - IfNotPresent
+                            - Never
+                            type: string
+                          registry:
+                            description: Registry replaces the registry and repository
+                              of the importer, cloner and upload server images, keeping
+                              their name, tag and digest, for instance to pull them
+                              from a mirror in an air-gapped cluster
+                            type: string
+                        type: object
+                      workerPodPlacement:
+                        description: WorkerPodPlacement is the node selector and tolerations
+                          of the importer, clone and upload pods of the DataVolume.
+                          Unset fields default to the ones of the CDIConfig.
+                        properties:
+                          nodeSelector:
+                            additionalProperties:
+                              type: string
+                            description: NodeSelector is the node selector of the
+                              worker pods, merged with the node selector of the workload
+                              node placement of the CDI CR
+                            type: object
+                          tolerations:
+                            description: Tolerations are the tolerations of the worker
+                              pods, in addition to the tolerations of the workload
+                              node placement of the CDI CR
+                            items:
+                              description: The pod this Toleration is attached to
+                                tolerates any taint that matches the triple <key,value,effect>
+                                using the matching operator <operator>.
+                              properties:
+                                effect:
+                                  description: Effect indicates the taint effect to
+                                    match. Empty means match all taint effects. When
+                                    specified, allowed values are NoSchedule, PreferNoSchedule
+                                    and NoExecute.
+                                  type: string
+                                key:
+                                  description: Key is the taint key that the toleration
+                                    applies to. Empty means match all taint keys.
+                                    If the key is empty, operator must be Exists;
+                                    this combination means to match all values and
+                                    all keys.
+                                  type: string
+                                operator:
+                                  description: Operator represents a key's relationship
+                                    to the value. Valid operators are Exists and Equal.
+                                    Defaults to Equal. Exists is equivalent to wildcard
+                                    for value, so that a pod can tolerate all taints
+                                    of a particular category.
+                                  type: string
+                                tolerationSeconds:
+                                  description: TolerationSeconds represents the period
+                                    of time the toleration (which must be of effect
+                                    NoExecute, otherwise this field is ignored) tolerates
+                                    the taint. By default, it is not set, which means
+                                    tolerate the taint forever (do not evict). Zero
+                                    and negative values will be treated as 0 (evict
+                                    immediately) by the system.
+                                  format: int64
+                                  type: integer
+                                value:
+                                  description: Value is the taint value the toleration
+                                    matches to. If the operator is Exists, the value
+                                    should be empty, otherwise just a regular string.
+                                  type: string
+                              type: object
+                            type: array
+                        type: object
                     type: object
                   status:
                     description: DataVolumeStatus contains the current status of the
@@ -5680,6 +7634,71 @@ spec:
                           - type
                           type: object
                         type: array
+                      disks:
+                        description: Disks reports the import of each disk of a multi-disk
+                          registry image
+                        items:
+                          description: DataVolumeDiskStatus reports the import of
+                            a disk of a multi-disk registry image
+                          properties:
+                            claimName:
+                              description: ClaimName is the name of the PVC the disk
+                                is imported into
+                              type: string
+                            path:
+                              description: Path is the path of the disk in the image
+                              type: string
+                            phase:
+                              description: Phase is the current phase of the import
+                                of the disk
+                              type: string
+                          required:
+                          - claimName
+                          - path
+                          type: object
+                        type: array
+                      importPassthrough:
+                        description: ImportPassthrough tells the importer copied the source
+                          image as is, without conversion, as it already had the target
+                          format
+                        type: boolean
+                      importSourceDigest:
+                        description: ImportSourceDigest is the digest of the source
+                          bytes the importer read, as <algorithm>:<hex>, when requested
+                          with the storage.import.sourceDigestAlgorithm annotation
+                        type: string
+                      importTimings:
+                        description: ImportTimings is the time the importer spent in the phases of the
+                          import
+                        properties:
+                          convertSeconds:
+                            description: ConvertSeconds is the time spent converting the image to the
+                              target volume
+                            format: int64
+                            type: integer
+                          downloadSeconds:
+                            description: DownloadSeconds is the time spent transferring the source to the
+                              scratch space or the target volume
+                            format: int64
+                            type: integer
+                          resizeSeconds:
+                            description: ResizeSeconds is the time spent resizing the image to the
+                              requested size
+                            format: int64
+                            type: integer
+                        type: object
+                      pausedSeconds:
+                        description: PausedSeconds is the time in seconds the DataVolume
+                          previously spent in the Paused phase, not counted toward
+                          its completion timeout
+                        format: int64
+                        type: integer
+                      pausedSince:
+                        description: PausedSince is the time the DataVolume entered
+                          the Paused phase of a multi-stage import, the time it stays
+                          paused does not count toward its completion timeout
+                        format: date-time
+                        type: string
                       phase:
                         description: Phase is the current phase of the data volume
                         type: string
@@ -6230,24 +8249,121 @@ spec:
                       backing this claim.
                     type: string
                 type: object
+              snapshotTarget:
+                description: SnapshotTarget makes a VolumeSnapshot of the imported
+                  PVC the final artifact of the DataVolume
+                properties:
+                  name:
+                    description: Name is the name of the VolumeSnapshot, the name
+                      of the DataVolume if empty
+                    type: string
+                  pvcRetainPolicy:
+                    description: PVCRetainPolicy tells whether the PVC is deleted
+                      once the VolumeSnapshot is ready, Delete if empty
+                    enum:
+                    - Delete
+                    - Retain
+                    type: string
+                  volumeSnapshotClassName:
+                    description: VolumeSnapshotClassName is the class of the VolumeSnapshot.
+                      If empty, a class of the provisioner of the storage class of
+                      the PVC is used
+                    type: string
+                type: object
               source:
                 description: Source is the src of the data for the requested DataVolume
                 properties:
+                  azure:
+                    description: DataVolumeSourceAzure provides the parameters to
+                      create a Data Volume from an Azure Blob Storage source
+                    properties:
+                      certConfigMap:
+                        description: CertConfigMap is a configmap reference, containing
+                          a Certificate Authority(CA) public key of the Blob service
+                        type: string
+                      secretRef:
+                        description: SecretRef provides the secret holding a SAS token
+                          in its sasToken key, or the tenantId, clientId and clientSecret
+                          of a service principal, the blob is read anonymously without
+                          it
+                        type: string
+                      url:
+                        description: URL is the url of the blob, as in https://<account>.blob.core.windows.net/<container>/<blob>,
+                          without a SAS token
+                        type: string
+                    required:
+                    - url
+                    type: object
                   blank:
                     description: DataVolumeBlankImage provides the parameters to create
                       a new raw blank image for the PVC
                     type: object
+                  ftp:
+                    description: DataVolumeSourceFTP provides the parameters to create
+                      a Data Volume from an FTP or FTPS source
+                    properties:
+                      certConfigMap:
+                        description: CertConfigMap is a configmap reference, containing
+                          a Certificate Authority(CA) public key of the FTPS server
+                        type: string
+                      secretRef:
+                        description: SecretRef provides the secret holding the user
+                          name and password of the FTP server in its accessKeyId and
+                          secretKey keys, the login is anonymous without it
+                        type: string
+                      url:
+                        description: URL is the url of the image on the FTP server,
+                          as in ftp://host[:port]/path, or ftps://host[:port]/path
+                          for FTP over explicit TLS
+                        type: string
+                    required:
+                    - url
+                    type: object
                   gcs:
                     description: DataVolumeSourceGCS provides the parameters to create
                       a Data Volume from an GCS source
                     properties:
+                      generation:
+                        description: Generation pins the import to this
+                          generation of the object, the import fails if the
+                          generation does not exist. By default the import reads
+                          the latest generation, pinned for the whole download
+                          so a resumed download does not mix two generations
+                        format: int64
+                        type: integer
                       secretRef:
                         description: SecretRef provides the secret reference needed
                           to access the GCS source
                         type: string
+                      tokenCredentials:
+                        description: TokenCredentials gets the credentials through
+                          workload identity federation with a projected service account
+                          token, instead of the SecretRef key file
+                        properties:
+                          audience:
+                            description: Audience is the audience of the projected
+                              token. For S3 it defaults to sts.amazonaws.com, for
+                              GCS it is required and is the workload identity pool
+                              provider, as in //iam.googleapis.com/projects/<number>/locations/global/workloadIdentityPools/<pool>/providers/<provider>
+                            type: string
+                          roleARN:
+                            description: RoleARN is the ARN of the IAM role assumed
+                              with the token, required for S3
+                            type: string
+                          serviceAccount:
+                            description: ServiceAccount is the email of the GCP service
+                              account impersonated with the federated token, optional
+                              for GCS
+                            type: string
+                        type: object
                       url:
                         description: URL is the url of the GCS source
                         type: string
+                      userProject:
+                        description: UserProject is the project billed for the requests
+                          to a requester-pays bucket, required to read from such a
+                          bucket
+                        type: string
                     required:
                     - url
                     type: object
@@ -6307,6 +8423,35 @@ spec:
                     - diskId
                     - url
                     type: object
+                  inline:
+                    description: DataVolumeSourceInline provides the parameters to
+                      create a Data Volume from a small image embedded in the DataVolume
+                    properties:
+                      data:
+                        description: Data is the base64 encoded image, optionally gzip
+                          compressed, of at most 1MiB once encoded
+                        type: string
+                    required:
+                    - data
+                    type: object
+                  nbd:
+                    description: DataVolumeSourceNBD provides the parameters to
+                      create a Data Volume from a disk exported over NBD
+                    properties:
+                      secretRef:
+                        description: SecretRef provides the secret holding the
+                          ca-cert.pem of the NBD server CA, and optionally the
+                          client-cert.pem and client-key.pem of a client
+                          certificate, required for NBD over TLS
+                        type: string
+                      url:
+                        description: URL is the url of the NBD export, as in
+                          nbd://host[:port]/export, or nbds://host[:port]/export
+                          for NBD over TLS
+                        type: string
+                    required:
+                    - url
+                    type: object
                   pvc:
                     description: DataVolumeSourcePVC provides the parameters to create
                       a Data Volume from an existing PVC
@@ -6329,6 +8474,35 @@ spec:
                         description: CertConfigMap provides a reference to the Registry
                           certs
                         type: string
+                      disks:
+                        description: Disks lists the disks of an image holding several
+                          of them, each imported into its own PVC
+                        items:
+                          description: RegistryDisk selects a disk of a multi-disk
+                            registry image, and the PVC it is imported into
+                          properties:
+                            path:
+                              description: Path is the path of the disk in the image,
+                                relative to its disk directory
+                              type: string
+                            pvcName:
+                              description: PVCName is the name of the PVC the disk
+                                is imported into, the PVC of the DataVolume when empty
+                              type: string
+                            size:
+                              anyOf:
+                              - type: integer
+                              - type: string
+                              description: Size is the size requested for the
+                                PVC of the disk, the size of the DataVolume when
+                                not set. The disk imported into the PVC of the
+                                DataVolume gets the size of the DataVolume.
+                              pattern: ^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$
+                              x-kubernetes-int-or-string: true
+                          required:
+                          - path
+                          type: object
+                        type: array
                       imageStream:
                         description: ImageStream is the name of image stream for import
                         type: string
@@ -6345,6 +8519,47 @@ spec:
                           with the scheme: docker, oci-archive)'
                         type: string
                     type: object
+                  remote:
+                    description: DataVolumeSourceRemote provides the parameters to
+                      clone a Data Volume from a disk exported by a remote cluster
+                    properties:
+                      certConfigMap:
+                        description: CertConfigMap is a configmap reference, containing
+                          a Certificate Authority(CA) public key of the remote endpoint
+                        type: string
+                      secretRef:
+                        description: SecretRef provides the secret holding the token
+                          authorizing the export, in its token key
+                        type: string
+                      url:
+                        description: URL is the url of the disk image exported by
+                          the remote cluster
+                        type: string
+                    required:
+                    - secretRef
+                    - url
+                    type: object
+                  remotePVC:
+                    description: DataVolumeSourceRemotePVC provides the parameters
+                      to clone a Data Volume from a PVC of a remote cluster
+                    properties:
+                      kubeconfigSecretRef:
+                        description: KubeconfigSecretRef provides the secret holding
+                          the kubeconfig of the remote cluster, in its kubeconfig
+                          key
+                        type: string
+                      name:
+                        description: The name of the source PVC in the remote cluster
+                        type: string
+                      namespace:
+                        description: The namespace of the source PVC in the remote
+                          cluster
+                        type: string
+                    required:
+                    - kubeconfigSecretRef
+                    - name
+                    - namespace
+                    type: object
                   s3:
                     description: DataVolumeSourceS3 provides the parameters to create
                       a Data Volume from an S3 source
@@ -6354,16 +8569,68 @@ spec:
                           a Certificate Authority(CA) public key, and a base64 encoded
                           pem certificate
                         type: string
+                      kmsKeyId:
+                        description: KMSKeyID is the ID or ARN of the AWS KMS key
+                          the object is encrypted with (SSE-KMS), the import fails
+                          if the object is not encrypted with this key
+                        type: string
                       secretRef:
                         description: SecretRef provides the secret reference needed
                           to access the S3 source
                         type: string
+                      tokenCredentials:
+                        description: TokenCredentials gets the credentials by assuming
+                          an IAM role with a projected service account token (IRSA),
+                          instead of the SecretRef keys
+                        properties:
+                          audience:
+                            description: Audience is the audience of the projected
+                              token. For S3 it defaults to sts.amazonaws.com, for
+                              GCS it is required and is the workload identity pool
+                              provider, as in //iam.googleapis.com/projects/<number>/locations/global/workloadIdentityPools/<pool>/providers/<provider>
+                            type: string
+                          roleARN:
+                            description: RoleARN is the ARN of the IAM role assumed
+                              with the token, required for S3
+                            type: string
+                          serviceAccount:
+                            description: ServiceAccount is the email of the GCP service
+                              account impersonated with the federated token, optional
+                              for GCS
+                            type: string
+                        type: object
                       url:
                         description: URL is the url of the S3 source
                         type: string
                     required:
                     - url
                     type: object
+                  sftp:
+                    description: DataVolumeSourceSFTP provides the parameters to create
+                      a Data Volume from an SFTP source
+                    properties:
+                      hostKey:
+                        description: HostKey is the public host key the SFTP server
+                          must present, in the authorized_keys format, as in "ssh-ed25519
+                          AAAA...", or its SHA256 fingerprint, as in "SHA256:...",
+                          printed by ssh-keygen -l
+                        type: string
+                      secretRef:
+                        description: SecretRef provides the secret holding the user
+                          name in its username key, and its private key in the ssh-privatekey
+                          key, with the passphrase of an encrypted key in the passphrase
+                          key, or its password in the password key
+                        type: string
+                      url:
+                        description: URL is the url of the image on the SFTP server,
+                          as in sftp://host[:port]/path, the path is relative to the
+                          home directory of the user if it starts with /~/
+                        type: string
+                    required:
+                    - hostKey
+                    - secretRef
+                    - url
+                    type: object
                   snapshot:
                     description: DataVolumeSourceSnapshot provides the parameters
                       to create a Data Volume from an existing VolumeSnapshot
@@ -6622,6 +8889,82 @@ spec:
                       backing this claim.
                     type: string
                 type: object
+              workerPodImage:
+                description: WorkerPodImage is the image registry and pull policy
+                  of the importer, clone and upload pods of the DataVolume. Unset
+                  fields default to the ones of the CDIConfig.
+                properties:
+                  pullPolicy:
+                    description: PullPolicy is the image pull policy of the worker
+                      pods, overriding the one of the CDI CR
+                    enum:
+                    - Always
+                    - IfNotPresent
+                    - Never
+                    type: string
+                  registry:
+                    description: Registry replaces the registry and repository of
+                      the importer, cloner and upload server images, keeping their
+                      name, tag and digest, for instance to pull them from a mirror
+                      in an air-gapped cluster
+                    type: string
+                type: object
+              workerPodPlacement:
+                description: WorkerPodPlacement is the node selector and tolerations
+                  of the importer, clone and upload pods of the DataVolume. Unset
+                  fields default to the ones of the CDIConfig.
+                properties:
+                  nodeSelector:
+                    additionalProperties:
+                      type: string
+                    description: NodeSelector is the node selector of the worker pods,
+                      merged with the node selector of the workload node placement
+                      of the CDI CR
+                    type: object
+                  tolerations:
+                    description: Tolerations are the tolerations of the worker pods,
+                      in addition to the tolerations of the workload node placement
+                      of the CDI CR
+                    items:
+                      description: The pod this Toleration is attached to tolerates
+                        any taint that matches the triple <key,value,effect> using
+                        the matching operator <operator>.
+                      properties:
+                        effect:
+                          description: Effect indicates the taint effect to match.
+                            Empty means match all taint effects. When specified, allowed
+                            values are NoSchedule, PreferNoSchedule and NoExecute.
+                          type: string
+                        key:
+                          description: Key is the taint key that the toleration applies
+                            to. Empty means match all taint keys. If the key is empty,
+                            operator must be Exists; this combination means to match
+                            all values and all keys.
+                          type: string
+                        operator:
+                          description: Operator represents a key's relationship to
+                            the value. Valid operators are Exists and Equal. Defaults
+                            to Equal. Exists is equivalent to wildcard for value,
+                            so that a pod can tolerate all taints of a particular
+                            category.
+                          type: string
+                        tolerationSeconds:
+                          description: TolerationSeconds represents the period of
+                            time the toleration (which must be of effect NoExecute,
+                            otherwise this field is ignored) tolerates the taint.
+                            By default, it is not set, which means tolerate the taint
+                            forever (do not evict). Zero and negative values will
+                            be treated as 0 (evict immediately) by the system.
+                          format: int64
+                          type: integer
+                        value:
+                          description: Value is the taint value the toleration matches
+                            to. If the operator is Exists, the value should be empty,
+                            otherwise just a regular string.
+                          type: string
+                      type: object
+                    type: array
+                type: object
             type: object
           status:
             description: DataVolumeStatus contains the current status of the DataVolume
@@ -6656,6 +8999,69 @@ spec:
                   - type
                   type: object
                 type: array
+              disks:
+                description: Disks reports the import of each disk of a multi-disk
+                  registry image
+                items:
+                  description: DataVolumeDiskStatus reports the import of a disk of
+                    a multi-disk registry image
+                  properties:
+                    claimName:
+                      description: ClaimName is the name of the PVC the disk is imported
+                        into
+                      type: string
+                    path:
+                      description: Path is the path of the disk in the image
+                      type: string
+                    phase:
+                      description: Phase is the current phase of the import of the
+                        disk
+                      type: string
+                  required:
+                  - claimName
+                  - path
+                  type: object
+                type: array
+              importPassthrough:
+                description: ImportPassthrough tells the importer copied the source image
+                  as is, without conversion, as it already had the target format
+                type: boolean
+              importSourceDigest:
+                description: ImportSourceDigest is the digest of the source bytes
+                  the importer read, as <algorithm>:<hex>, when requested with the
+                  storage.import.sourceDigestAlgorithm annotation
+                type: string
+              importTimings:
+                description: ImportTimings is the time the importer spent in the phases of the
+                  import
+                properties:
+                  convertSeconds:
+                    description: ConvertSeconds is the time spent converting the image to the
+                      target volume
+                    format: int64
+                    type: integer
+                  downloadSeconds:
+                    description: DownloadSeconds is the time spent transferring the source to the
+                      scratch space or the target volume
+                    format: int64
+                    type: integer
+                  resizeSeconds:
+                    description: ResizeSeconds is the time spent resizing the image to the
+                      requested size
+                    format: int64
+                    type: integer
+                type: object
+              pausedSeconds:
+                description: PausedSeconds is the time in seconds the DataVolume previously
+                  spent in the Paused phase, not counted toward its completion timeout
+                format: int64
+                type: integer
+              pausedSince:
+                description: PausedSince is the time the DataVolume entered the Paused
+                  phase of a multi-stage import, the time it stays paused does not
+                  count toward its completion timeout
+                format: date-time
+                type: string
               phase:
                 description: Phase is the current phase of the data volume
                 type: string
@@ -6877,6 +9283,33 @@ spec:
                 description: CloneStrategy defines the preferred method for performing
                   a CDI clone
                 type: string
+              cloneStreams:
+                description: CloneStreams is the number of parallel streams of
+                  the host-assisted clones of block disks into the storage class
+                format: int32
+                maximum: 16
+                minimum: 1
+                type: integer
+              crossClassSnapshotRestore:
+                description: CrossClassSnapshotRestore marks the CSI driver of
+                  the storage class as able to restore the snapshots of the volumes
+                  of its other storage classes into this one, so snapshot clones
+                  from them are not host-assisted
+                type: boolean
+              filesystemOverhead:
+                description: FilesystemOverhead is the recommended filesystem overhead
+                  for Filesystem volumes of the storage class
+                pattern: ^(0(?:\.\d{1,3})?|1)$
+                type: string
+              preallocation:
+                description: Preallocation is the recommended preallocation setting
+                  for DataVolumes which don't set their own
+                type: boolean
+              thinProvisioned:
+                description: ThinProvisioned marks the storage of the class as thin
+                  provisioned, DataVolumes which don't set their own preallocation
+                  are then not preallocated unless Preallocation is set
+                type: boolean
             type: object
           status:
             description: StorageProfileStatus provides the most recently observed
@@ -6906,12 +9339,37 @@ spec:
                 description: CloneStrategy defines the preferred method for performing
                   a CDI clone
                 type: string
+              cloneStreams:
+                description: CloneStreams is the number of parallel streams of
+                  the host-assisted clones of block disks into the storage class
+                format: int32
+                type: integer
+              crossClassSnapshotRestore:
+                description: CrossClassSnapshotRestore marks the CSI driver of
+                  the storage class as able to restore the snapshots of the volumes
+                  of its other storage classes into this one, so snapshot clones
+                  from them are not host-assisted
+                type: boolean
+              filesystemOverhead:
+                description: FilesystemOverhead is the recommended filesystem overhead
+                  for Filesystem volumes of the storage class
+                pattern: ^(0(?:\.\d{1,3})?|1)$
+                type: string
+              preallocation:
+                description: Preallocation is the recommended preallocation setting
+                  for DataVolumes which don't set their own
+                type: boolean
               provisioner:
                 description: The Storage class provisioner plugin name
                 type: string
               storageClass:
                 description: The StorageClass name for which capabilities are defined
                 type: string
+              thinProvisioned:
+                description: ThinProvisioned marks the storage of the class as thin
+                  provisioned, DataVolumes which don't set their own preallocation
+                  are then not preallocated unless Preallocation is set
+                type: boolean
             type: object
         required:
         - spec
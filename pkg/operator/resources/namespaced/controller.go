@@ -52,7 +52,8 @@ func createControllerResources(args *FactoryArgs) []client.Object {
 			args.PullPolicy,
 			args.ImagePullSecrets,
 			args.PriorityClassName,
-			args.InfraNodePlacement),
+			args.InfraNodePlacement,
+			args.LogFormat),
 		createInsecureRegConfigMap(),
 		createPrometheusService(),
 	}
@@ -114,6 +115,7 @@ func createControllerRole() *rbacv1.Role {
 			},
 			Verbs: []string{
 				"create",
+				"update",
 				"delete",
 			},
 		},
@@ -162,7 +164,7 @@ func createControllerServiceAccount() *corev1.ServiceAccount {
 	return utils.ResourceBuilder.CreateServiceAccount(common.ControllerServiceAccountName)
 }
 
-func createControllerDeployment(controllerImage, importerImage, clonerImage, uploadServerImage, verbosity, pullPolicy string, imagePullSecrets []corev1.LocalObjectReference, priorityClassName string, infraNodePlacement *sdkapi.NodePlacement) *appsv1.Deployment {
+func createControllerDeployment(controllerImage, importerImage, clonerImage, uploadServerImage, verbosity, pullPolicy string, imagePullSecrets []corev1.LocalObjectReference, priorityClassName string, infraNodePlacement *sdkapi.NodePlacement, logFormat string) *appsv1.Deployment {
 	defaultMode := corev1.ConfigMapVolumeSourceDefaultMode
 	deployment := utils.CreateDeployment(controllerResourceName, "app", "containerized-data-importer", common.ControllerServiceAccountName, imagePullSecrets, int32(1), infraNodePlacement)
 	if priorityClassName != "" {
@@ -219,6 +221,12 @@ func createControllerDeployment(controllerImage, importerImage, clonerImage, upl
 			},
 		},
 	}
+	if logFormat != "" {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  common.LogFormat,
+			Value: logFormat,
+		})
+	}
 	container.ReadinessProbe = &corev1.Probe{
 		ProbeHandler: corev1.ProbeHandler{
 			Exec: &corev1.ExecAction{
@@ -44,6 +44,7 @@ type FactoryArgs struct {
 	PriorityClassName      string
 	Namespace              string
 	InfraNodePlacement     *sdkapi.NodePlacement
+	LogFormat              string `split_words:"true"`
 }
 
 type factoryFunc func(*FactoryArgs) []client.Object
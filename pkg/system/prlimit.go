@@ -28,6 +28,8 @@ import (
 	"github.com/pkg/errors"
 	"golang.org/x/sys/unix"
 	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/util"
 )
 
 // ProcessLimiter defines the methods limiting resources of a Process
@@ -170,12 +172,14 @@ func executeWithLimits(limits *ProcessLimitValues, callback func(string), logErr
 
 	output := buf.Bytes()
 	if err != nil {
+		// The output may hold the URL of a source, like the error of qemu-img reading it
+		errOutput := util.RedactSourceURLs(errBuf.String())
 		if logErr {
 			klog.Errorf("%s failed output is:\n", command)
-			klog.Errorf("%s\n", string(output))
-			klog.Errorf("%s\n", errBuf.String())
+			klog.Errorf("%s\n", util.RedactSourceURLs(string(output)))
+			klog.Errorf("%s\n", errOutput)
 		}
-		return errBuf.Bytes(), errors.Wrapf(err, "%s execution failed", command)
+		return []byte(errOutput), errors.Wrapf(err, "%s execution failed", command)
 	}
 	return output, nil
 }
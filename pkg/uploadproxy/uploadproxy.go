@@ -292,6 +292,11 @@ func (app *uploadProxyApp) proxyUploadRequest(namespace, pvcName, uploadPath str
 				// explicitly disable User-Agent so it's not set to default value
 				req.Header.Set("User-Agent", "")
 			}
+			if len(r.Trailer) > 0 && req.Body != nil {
+				// The reverse proxy only announces the trailers of the request, like the checksum of a clone, the
+				// values are received with the end of the body and have to be copied to the proxied request
+				req.Body = &trailerForwardingBody{ReadCloser: req.Body, from: r.Trailer, to: req.Trailer}
+			}
 		},
 		Transport: client.Transport,
 	}
@@ -299,6 +304,23 @@ func (app *uploadProxyApp) proxyUploadRequest(namespace, pvcName, uploadPath str
 	p.ServeHTTP(w, r)
 }
 
+// trailerForwardingBody copies the trailers of the incoming request to the proxied request once its body is read
+type trailerForwardingBody struct {
+	io.ReadCloser
+	from http.Header
+	to   http.Header
+}
+
+func (b *trailerForwardingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		for key, values := range b.from {
+			b.to[key] = values
+		}
+	}
+	return n, err
+}
+
 func (app *uploadProxyApp) getSigningKey(publicKeyPEM string) error {
 	publicKey, err := controller.DecodePublicKey([]byte(publicKeyPEM))
 	if err != nil {
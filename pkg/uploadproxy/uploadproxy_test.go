@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -116,6 +117,19 @@ func submitRequestAndCheckStatusAndCORS(request *http.Request, expectedCode int,
 	Expect(rr.Header().Get("Access-Control-Allow-Origin")).To(Equal("*"))
 }
 
+type trailerSettingReader struct {
+	io.Reader
+	trailer http.Header
+}
+
+func (r *trailerSettingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err == io.EOF {
+		r.trailer.Set(common.CloneSHA256Trailer, "checksum")
+	}
+	return n, err
+}
+
 func createApp() *uploadProxyApp {
 	app := &uploadProxyApp{}
 	app.initHandler()
@@ -230,6 +244,24 @@ var _ = Describe("submit request and check status", func() {
 		table.Entry("Test OK", http.StatusOK),
 		table.Entry("Test error", http.StatusInternalServerError),
 	)
+	It("Test proxy forwards the request trailers", func() {
+		var trailer string
+		app := setupProxyTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := io.Copy(io.Discard, r.Body)
+			Expect(err).ToNot(HaveOccurred())
+			trailer = r.Trailer.Get(common.CloneSHA256Trailer)
+			w.WriteHeader(http.StatusOK)
+		}))
+		app.uploadPossible = func(*v1.PersistentVolumeClaim) error { return nil }
+
+		req := newProxyRequest(common.UploadPathSync, "Bearer valid")
+		// Like a request of the server, the value of the trailer is only set at the end of the body
+		req.ContentLength = -1
+		req.Trailer = http.Header{common.CloneSHA256Trailer: nil}
+		req.Body = io.NopCloser(&trailerSettingReader{Reader: req.Body, trailer: req.Trailer})
+		submitRequestAndCheckStatus(req, http.StatusOK, app)
+		Expect(trailer).To(Equal("checksum"))
+	})
 	It("Invalid token", func() {
 		app := createApp()
 		app.tokenValidator = &validateFailure{}
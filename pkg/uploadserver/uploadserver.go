@@ -22,19 +22,23 @@ package uploadserver
 import (
 	"archive/tar"
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"hash"
 	"io"
 	"mime/multipart"
 	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 
-	"github.com/golang/snappy"
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
 
@@ -42,12 +46,15 @@ import (
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/importer"
 	"kubevirt.io/containerized-data-importer/pkg/util"
+	prometheusutil "kubevirt.io/containerized-data-importer/pkg/util/prometheus"
 	cryptowatch "kubevirt.io/containerized-data-importer/pkg/util/tls-crypto-watch"
 )
 
 const (
 	healthzPort = 8080
 	healthzPath = "/healthz"
+
+	cloneCheckpointFileName = "checkpoint.json"
 )
 
 // UploadServer is the interface to uploadServerApp
@@ -56,6 +63,16 @@ type UploadServer interface {
 	PreallocationApplied() bool
 }
 
+// CloneTargetImage is the qcow2 image a clone is converted into with qemu-img convert, instead of being copied as is
+type CloneTargetImage struct {
+	// Compression is the compression type of the clusters, uncompressed when empty
+	Compression string
+	// ClusterSize is the size of the clusters in bytes, the qemu-img default when 0
+	ClusterSize int64
+	// Compact rewrites a qcow2 source even when it could be copied as is, defragmenting it
+	Compact bool
+}
+
 type uploadServerApp struct {
 	bindAddress          string
 	bindPort             int
@@ -70,11 +87,14 @@ type uploadServerApp struct {
 	imageSize            string
 	filesystemOverhead   float64
 	preallocation        bool
+	cloneTarget          *CloneTargetImage
 	mux                  *http.ServeMux
 	uploading            bool
 	processing           bool
 	done                 bool
 	preallocationApplied bool
+	cloneCheckpointFile  string
+	cloneRanges          *cloneRangeTracker
 	doneChan             chan struct{}
 	errChan              chan error
 	mutex                sync.Mutex
@@ -85,6 +105,44 @@ type imageReadCloser func(*http.Request) (io.ReadCloser, error)
 // may be overridden in tests
 var uploadProcessorFunc = newUploadStreamProcessor
 var uploadProcessorFuncAsync = newAsyncUploadStreamProcessor
+var cloneRangeWriterFunc = importer.StreamRawRange
+
+// cloneRangeTracker tracks the ranges of the disk written by the parallel streams of a clone
+type cloneRangeTracker struct {
+	// size of the disk
+	size int64
+	// start offsets of the ranges being streamed
+	streaming map[int64]bool
+	// end offsets of the ranges written, by start offset
+	written map[int64]int64
+	// incremental is set when the ranges only cover the blocks changed since the target was cloned, the clone source
+	// ending the upload once they are written
+	incremental bool
+}
+
+func newCloneRangeTracker(size int64) *cloneRangeTracker {
+	return &cloneRangeTracker{
+		size:      size,
+		streaming: map[int64]bool{},
+		written:   map[int64]int64{},
+	}
+}
+
+func (t *cloneRangeTracker) isStreaming() bool {
+	return t != nil && len(t.streaming) > 0
+}
+
+// complete returns whether the written ranges cover the whole disk
+func (t *cloneRangeTracker) complete() bool {
+	for offset := int64(0); offset < t.size; {
+		end, ok := t.written[offset]
+		if !ok {
+			return false
+		}
+		offset = end
+	}
+	return true
+}
 
 func bodyReadCloser(r *http.Request) (io.ReadCloser, error) {
 	return r.Body, nil
@@ -114,25 +172,27 @@ func formReadCloser(r *http.Request) (io.ReadCloser, error) {
 	return filePart, nil
 }
 
-// NewUploadServer returns a new instance of uploadServerApp
-func NewUploadServer(bindAddress string, bindPort int, destination, tlsKey, tlsCert, clientCert, clientName, imageSize string, filesystemOverhead float64, preallocation bool, cryptoConfig cryptowatch.CryptoConfig) UploadServer {
+// NewUploadServer returns a new instance of uploadServerApp. A clone is converted into cloneTarget when it is not nil.
+func NewUploadServer(bindAddress string, bindPort int, destination, tlsKey, tlsCert, clientCert, clientName, imageSize string, filesystemOverhead float64, preallocation bool, cryptoConfig cryptowatch.CryptoConfig, cloneTarget *CloneTargetImage) UploadServer {
 	server := &uploadServerApp{
-		bindAddress:        bindAddress,
-		bindPort:           bindPort,
-		destination:        destination,
-		tlsKey:             tlsKey,
-		tlsCert:            tlsCert,
-		clientCert:         clientCert,
-		clientName:         clientName,
-		cryptoConfig:       cryptoConfig,
-		filesystemOverhead: filesystemOverhead,
-		preallocation:      preallocation,
-		imageSize:          imageSize,
-		mux:                http.NewServeMux(),
-		uploading:          false,
-		done:               false,
-		doneChan:           make(chan struct{}),
-		errChan:            make(chan error),
+		bindAddress:         bindAddress,
+		bindPort:            bindPort,
+		destination:         destination,
+		tlsKey:              tlsKey,
+		tlsCert:             tlsCert,
+		clientCert:          clientCert,
+		clientName:          clientName,
+		cryptoConfig:        cryptoConfig,
+		filesystemOverhead:  filesystemOverhead,
+		preallocation:       preallocation,
+		cloneTarget:         cloneTarget,
+		imageSize:           imageSize,
+		cloneCheckpointFile: filepath.Join(common.CloneCheckpointDir, cloneCheckpointFileName),
+		mux:                 http.NewServeMux(),
+		uploading:           false,
+		done:                false,
+		doneChan:            make(chan struct{}),
+		errChan:             make(chan error),
 	}
 
 	for _, path := range common.SyncUploadPaths {
@@ -150,6 +210,9 @@ func NewUploadServer(bindAddress string, bindPort int, destination, tlsKey, tlsC
 	for _, path := range common.AsyncUploadFormPaths {
 		server.mux.HandleFunc(path, server.uploadHandlerAsync(formReadCloser))
 	}
+	server.mux.HandleFunc(common.UploadPathCloneCheckpoint, server.cloneCheckpointHandler)
+	server.mux.HandleFunc(common.UploadPathCloneBlocks, server.cloneBlocksHandler)
+	server.mux.HandleFunc(prometheusutil.StatusPath, server.statusHandler)
 
 	return server
 }
@@ -270,6 +333,31 @@ func (app *uploadServerApp) validateShouldHandleRequest(w http.ResponseWriter, r
 		return false
 	}
 
+	if !app.validateClient(w, r) {
+		return false
+	}
+
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	if app.uploading || app.processing || app.cloneRanges.isStreaming() {
+		klog.Warning("Got concurrent upload request")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return false
+	}
+
+	if app.done {
+		klog.Warning("Got upload request after already done")
+		w.WriteHeader(http.StatusConflict)
+		return false
+	}
+
+	app.uploading = true
+
+	return true
+}
+
+func (app *uploadServerApp) validateClient(w http.ResponseWriter, r *http.Request) bool {
 	if r.TLS != nil {
 		found := false
 
@@ -288,24 +376,114 @@ func (app *uploadServerApp) validateShouldHandleRequest(w http.ResponseWriter, r
 		klog.V(3).Infof("Handling HTTP connection")
 	}
 
-	app.mutex.Lock()
-	defer app.mutex.Unlock()
+	return true
+}
 
-	if app.uploading || app.processing {
-		klog.Warning("Got concurrent upload request")
+// statusHandler returns the live state of the upload, to the clients allowed to upload
+func (app *uploadServerApp) statusHandler(w http.ResponseWriter, r *http.Request) {
+	if !app.validateClient(w, r) {
+		return
+	}
+	prometheusutil.StatusHandler(w, r)
+}
+
+// cloneCheckpointHandler returns the checkpoint a restarted clone source may resume its raw clone stream from
+func (app *uploadServerApp) cloneCheckpointHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if !app.validateClient(w, r) {
+		return
+	}
+
+	checkpointer := app.newCloneCheckpointer()
+	if checkpointer == nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("clone checkpoints are not kept"))
+		return
+	}
+	checkpoint, err := checkpointer.Checkpoint(app.destination)
+	if err != nil {
+		klog.Infof("No clone checkpoint to resume from: %v", err)
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	klog.Infof("Clone checkpoint at offset %d", checkpoint.Offset)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(checkpoint)
+}
+
+// cloneBlocksHandler returns the checksums of the blocks of the first size bytes of the target, for an incremental
+// clone source to only stream the blocks it changed since the target was cloned. The target has to be a raw block
+// device, written as is.
+func (app *uploadServerApp) cloneBlocksHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if !app.validateClient(w, r) {
+		return
+	}
+
+	if !app.isRawBlockTarget() {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("incremental clones need a raw block device target"))
+		return
+	}
+
+	size, err := strconv.ParseInt(r.URL.Query().Get("size"), 10, 64)
+	if err != nil || size < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf("invalid disk size %q", r.URL.Query().Get("size"))))
+		return
+	}
+
+	app.mutex.Lock()
+	busy := app.uploading || app.processing || app.cloneRanges.isStreaming() || app.done
+	app.mutex.Unlock()
+	if busy {
 		w.WriteHeader(http.StatusServiceUnavailable)
-		return false
+		return
 	}
 
-	if app.done {
-		klog.Warning("Got upload request after already done")
-		w.WriteHeader(http.StatusConflict)
-		return false
+	f, err := os.Open(app.destination)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
 	}
+	defer f.Close()
+	checksums, err := util.ComputeCloneBlockChecksums(f, size, util.CloneBlockSize)
+	if err != nil {
+		klog.Warningf("Unable to compute the checksums of the blocks of the target: %v", err)
+		w.WriteHeader(http.StatusPreconditionFailed)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	klog.Infof("Computed the checksums of %d blocks of the target", len(checksums.Checksums))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(checksums)
+}
 
-	app.uploading = true
+// isRawBlockTarget returns whether the clone is written as is to a block device, so ranges can be written to it
+func (app *uploadServerApp) isRawBlockTarget() bool {
+	return app.cloneTarget == nil && app.destination == common.WriteBlockPath
+}
 
-	return true
+// newCloneCheckpointer returns the checkpointer of raw clone streams, nil if the clone checkpoint directory is not
+// mounted or the clone is converted, as it is not written to the target as is
+func (app *uploadServerApp) newCloneCheckpointer() *importer.CloneCheckpointer {
+	if app.cloneTarget != nil {
+		return nil
+	}
+	if _, err := os.Stat(filepath.Dir(app.cloneCheckpointFile)); err != nil {
+		return nil
+	}
+	return importer.NewCloneCheckpointer(app.cloneCheckpointFile)
 }
 
 func (app *uploadServerApp) uploadHandlerAsync(irc imageReadCloser) http.HandlerFunc {
@@ -369,6 +547,11 @@ func (app *uploadServerApp) uploadHandlerAsync(irc imageReadCloser) http.Handler
 }
 
 func (app *uploadServerApp) processUpload(irc imageReadCloser, w http.ResponseWriter, r *http.Request, dvContentType cdiv1.DataVolumeContentType) {
+	compression, ok := cloneCompression(w, r)
+	if !ok {
+		return
+	}
+
 	if !app.validateShouldHandleRequest(w, r) {
 		return
 	}
@@ -377,12 +560,49 @@ func (app *uploadServerApp) processUpload(irc imageReadCloser, w http.ResponseWr
 
 	klog.Infof("Content type header is %q\n", cdiContentType)
 
+	var checkpointer *importer.CloneCheckpointer
+	var cloneTarget *CloneTargetImage
+	var digest hash.Hash
+	if _, ok := r.Trailer[common.CloneSHA256Trailer]; ok && isCloneContentType(cdiContentType) {
+		digest = sha256.New()
+	}
+	if cdiContentType == common.BlockdeviceClone && dvContentType == cdiv1.DataVolumeKubeVirt {
+		cloneTarget = app.cloneTarget
+		checkpointer = app.newCloneCheckpointer()
+		if offset := r.Header.Get(common.CloneOffsetHeader); offset != "" {
+			if err := resumeClone(checkpointer, app.destination, offset); err != nil {
+				klog.Errorf("Unable to resume the clone: %v", err)
+				w.WriteHeader(http.StatusPreconditionFailed)
+				w.Write([]byte(err.Error()))
+				app.mutex.Lock()
+				app.uploading = false
+				app.mutex.Unlock()
+				return
+			}
+			klog.Infof("Resuming the clone from offset %d", checkpointer.Offset())
+		}
+		if digest != nil && checkpointer != nil && checkpointer.Offset() > 0 {
+			// The source hashes the whole disk, including the part written before the clone was interrupted
+			if err := hashFilePrefix(digest, app.destination, checkpointer.Offset()); err != nil {
+				klog.Errorf("Unable to hash the resumed target: %v", err)
+				w.WriteHeader(http.StatusInternalServerError)
+				app.mutex.Lock()
+				app.uploading = false
+				app.mutex.Unlock()
+				return
+			}
+		}
+	}
+
 	readCloser, err := irc(r)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 	}
 
-	app.preallocationApplied, err = uploadProcessorFunc(readCloser, app.destination, app.imageSize, app.filesystemOverhead, app.preallocation, cdiContentType, dvContentType)
+	app.preallocationApplied, err = uploadProcessorFunc(readCloser, app.destination, app.imageSize, app.filesystemOverhead, app.preallocation, cdiContentType, compression, dvContentType, checkpointer, cloneTarget, digest)
+	if err == nil && digest != nil {
+		err = verifyCloneChecksum(digest, r.Trailer.Get(common.CloneSHA256Trailer))
+	}
 
 	app.mutex.Lock()
 	defer app.mutex.Unlock()
@@ -390,6 +610,16 @@ func (app *uploadServerApp) processUpload(irc imageReadCloser, w http.ResponseWr
 	if err != nil {
 		klog.Errorf("Saving stream failed: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
+		if strings.Contains(err.Error(), common.CloneChecksumMismatchMessage) {
+			// The clone source reports it, and streams the clone again from scratch: resuming from the checkpoint
+			// would hash the same mismatching prefix again
+			if checkpointer != nil {
+				if err := checkpointer.Clear(); err != nil {
+					klog.Errorf("Unable to clear the clone checkpoint: %v", err)
+				}
+			}
+			w.Write([]byte(err.Error()))
+		}
 		app.uploading = false
 		return
 	}
@@ -406,12 +636,261 @@ func (app *uploadServerApp) processUpload(irc imageReadCloser, w http.ResponseWr
 	}
 }
 
+// cloneCompression returns the compression codec of the clone stream of the request, answering 415 if it is not one
+// the upload server knows
+func cloneCompression(w http.ResponseWriter, r *http.Request) (string, bool) {
+	compression := r.Header.Get(common.CloneCompressionHeader)
+	if compression == "" {
+		return common.CloneCompressionSnappy, true
+	}
+	if err := util.ValidateCloneCompression(compression); err != nil {
+		klog.Warningf("Got a clone stream with an unknown compression: %v", err)
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		w.Write([]byte(err.Error()))
+		return "", false
+	}
+	return compression, true
+}
+
+// cloneCompressionHandler answers a HEAD request with the compression codecs of the clone streams the upload server
+// accepts, for the clone source to pick the one it was asked for. An older upload server answering without them only
+// accepts snappy.
+func (app *uploadServerApp) cloneCompressionHandler(w http.ResponseWriter, r *http.Request) {
+	if !app.validateClient(w, r) {
+		return
+	}
+	w.Header().Set(common.CloneCompressionAcceptHeader, strings.Join(util.CloneCompressionCodecs, ","))
+	w.WriteHeader(http.StatusOK)
+}
+
+func isCloneContentType(contentType string) bool {
+	return contentType == common.BlockdeviceClone || contentType == common.FilesystemCloneContentType
+}
+
+// hashFilePrefix writes the first size bytes of the file at path to digest
+func hashFilePrefix(digest hash.Hash, path string, size int64) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.CopyN(digest, f, size)
+	return err
+}
+
+// verifyCloneChecksum checks the SHA-256 of the clone stream received matches the one of the source, sent in the
+// trailer of the request. A source not sending it is not verified.
+func verifyCloneChecksum(digest hash.Hash, expected string) error {
+	if expected == "" {
+		klog.Warning("The clone source sent no checksum, the clone is not verified")
+		return nil
+	}
+	received := hex.EncodeToString(digest.Sum(nil))
+	if received != expected {
+		return errors.Errorf("%s, the source disk has SHA-256 %s but the target received %s", common.CloneChecksumMismatchMessage, expected, received)
+	}
+	klog.Infof("Verified the clone checksum, SHA-256 %s", received)
+	return nil
+}
+
+func resumeClone(checkpointer *importer.CloneCheckpointer, dest, offset string) error {
+	if checkpointer == nil {
+		return errors.New("clone checkpoints are not kept")
+	}
+	resumeOffset, err := strconv.ParseInt(offset, 10, 64)
+	if err != nil {
+		return errors.Wrapf(err, "invalid clone offset %q", offset)
+	}
+	return checkpointer.Resume(dest, resumeOffset)
+}
+
 func (app *uploadServerApp) uploadHandler(irc imageReadCloser) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "HEAD" {
+			app.cloneCompressionHandler(w, r)
+			return
+		}
+		if value := r.Header.Get(common.CloneRangeHeader); value != "" {
+			app.processCloneRange(w, r, value)
+			return
+		}
+		if value := r.Header.Get(common.CloneIncrementalHeader); value != "" {
+			app.processCloneIncrementalEnd(w, r, value)
+			return
+		}
 		app.processUpload(irc, w, r, cdiv1.DataVolumeKubeVirt)
 	}
 }
 
+func (app *uploadServerApp) validateShouldHandleRangeRequest(w http.ResponseWriter, r *http.Request, cloneRange util.CloneRange) bool {
+	if r.Method != "POST" {
+		w.WriteHeader(http.StatusNotFound)
+		return false
+	}
+
+	if !app.validateClient(w, r) {
+		return false
+	}
+
+	// The ranges are written as is, the target has to be a raw block device
+	if r.Header.Get(common.UploadContentTypeHeader) != common.BlockdeviceClone || !app.isRawBlockTarget() {
+		klog.Warningf("Got clone range %s for a target not written as a raw block device", cloneRange)
+		w.WriteHeader(http.StatusPreconditionFailed)
+		w.Write([]byte("parallel clone streams need a raw block device target"))
+		return false
+	}
+
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	if app.uploading || app.processing {
+		klog.Warning("Got clone range request during an upload")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return false
+	}
+
+	if app.done {
+		klog.Warning("Got clone range request after already done")
+		w.WriteHeader(http.StatusConflict)
+		return false
+	}
+
+	if app.cloneRanges == nil || app.cloneRanges.size != cloneRange.Size {
+		if app.cloneRanges.isStreaming() {
+			klog.Warningf("Got clone range %s of a disk of another size than the one streamed", cloneRange)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return false
+		}
+		app.cloneRanges = newCloneRangeTracker(cloneRange.Size)
+	}
+	if r.Header.Get(common.CloneIncrementalHeader) != "" {
+		app.cloneRanges.incremental = true
+	}
+
+	if app.cloneRanges.streaming[cloneRange.Start] {
+		klog.Warningf("Got concurrent clone range %s request", cloneRange)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return false
+	}
+
+	app.cloneRanges.streaming[cloneRange.Start] = true
+
+	return true
+}
+
+// processCloneRange writes the range of a raw disk streamed by one of the parallel streams of a clone, the upload is
+// done once the written ranges cover the whole disk. The SHA-256 trailer of the stream is the one of the range.
+func (app *uploadServerApp) processCloneRange(w http.ResponseWriter, r *http.Request, value string) {
+	cloneRange, err := util.ParseCloneRange(value)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	compression, ok := cloneCompression(w, r)
+	if !ok {
+		return
+	}
+
+	if !app.validateShouldHandleRangeRequest(w, r, cloneRange) {
+		return
+	}
+
+	var digest hash.Hash
+	if _, ok := r.Trailer[common.CloneSHA256Trailer]; ok {
+		digest = sha256.New()
+	}
+	reader, err := newCloneStreamReader(r.Body, compression, digest)
+	if err == nil {
+		err = cloneRangeWriterFunc(reader, app.destination, cloneRange, app.preallocation)
+	}
+	if err == nil && digest != nil {
+		if err = drainCloneStream(reader); err == nil {
+			err = verifyCloneChecksum(digest, r.Trailer.Get(common.CloneSHA256Trailer))
+		}
+	}
+
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	delete(app.cloneRanges.streaming, cloneRange.Start)
+
+	if err != nil {
+		klog.Errorf("Saving clone range %s failed: %s", cloneRange, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		if strings.Contains(err.Error(), common.CloneChecksumMismatchMessage) {
+			w.Write([]byte(err.Error()))
+		}
+		return
+	}
+
+	app.cloneRanges.written[cloneRange.Start] = cloneRange.End
+	klog.Infof("Wrote clone range %s to %s", cloneRange, app.destination)
+
+	if !app.cloneRanges.incremental && app.cloneRanges.complete() {
+		// The ranges cover the whole disk, their zeroes were written when preallocating
+		app.done = true
+		app.preallocationApplied = app.preallocation
+		close(app.doneChan)
+		klog.Infof("Wrote data to %s", app.destination)
+	}
+}
+
+// processCloneIncrementalEnd ends an incremental clone once the clone source streamed the ranges of the blocks it
+// changed: the target then holds the whole disk of size bytes, verified against the SHA-256 trailer of the request.
+func (app *uploadServerApp) processCloneIncrementalEnd(w http.ResponseWriter, r *http.Request, value string) {
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || size < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf("invalid disk size %q", value)))
+		return
+	}
+
+	if !app.validateShouldHandleRequest(w, r) {
+		return
+	}
+
+	if r.Header.Get(common.UploadContentTypeHeader) != common.BlockdeviceClone || !app.isRawBlockTarget() {
+		app.mutex.Lock()
+		app.uploading = false
+		app.mutex.Unlock()
+		w.WriteHeader(http.StatusPreconditionFailed)
+		w.Write([]byte("incremental clones need a raw block device target"))
+		return
+	}
+
+	// The trailer is only received with the end of the body
+	_, err = io.Copy(io.Discard, r.Body)
+	if err == nil {
+		digest := sha256.New()
+		if err = hashFilePrefix(digest, app.destination, size); err == nil {
+			err = verifyCloneChecksum(digest, r.Trailer.Get(common.CloneSHA256Trailer))
+		}
+	}
+
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	app.uploading = false
+
+	if err != nil {
+		klog.Errorf("Verifying the incremental clone failed: %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		if strings.Contains(err.Error(), common.CloneChecksumMismatchMessage) {
+			w.Write([]byte(err.Error()))
+		}
+		return
+	}
+
+	// Only the changed blocks were written, the other blocks are the ones of the previous clone whether it was
+	// preallocated or not
+	app.done = true
+	app.preallocationApplied = false
+	close(app.doneChan)
+	klog.Infof("Wrote the changed blocks to %s", app.destination)
+}
+
 func (app *uploadServerApp) uploadArchiveHandler(irc imageReadCloser) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		app.processUpload(irc, w, r, cdiv1.DataVolumeArchive)
@@ -427,37 +906,74 @@ func newAsyncUploadStreamProcessor(stream io.ReadCloser, dest, imageSize string,
 		return nil, fmt.Errorf("async filesystem clone not supported")
 	}
 
-	uds := importer.NewAsyncUploadDataSource(newContentReader(stream, sourceContentType))
+	contentReader, err := newContentReader(stream, sourceContentType, common.CloneCompressionSnappy, nil)
+	if err != nil {
+		return nil, err
+	}
+	uds := importer.NewAsyncUploadDataSource(contentReader, preallocation)
 	processor := importer.NewDataProcessor(uds, dest, common.ImporterVolumePath, common.ScratchDataDir, imageSize, filesystemOverhead, preallocation)
 	return processor, processor.ProcessDataWithPause()
 }
 
-func newUploadStreamProcessor(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, sourceContentType string, dvContentType cdiv1.DataVolumeContentType) (bool, error) {
+// newUploadStreamProcessor processes the upload stream, writing the decoded clone stream to digest when it is not nil
+func newUploadStreamProcessor(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, sourceContentType, compression string, dvContentType cdiv1.DataVolumeContentType, checkpointer *importer.CloneCheckpointer, cloneTarget *CloneTargetImage, digest hash.Hash) (bool, error) {
 	if sourceContentType == common.FilesystemCloneContentType {
-		return false, filesystemCloneProcessor(stream, dest)
+		return false, filesystemCloneProcessor(stream, dest, compression, digest)
 	}
 
 	// Clone block device to block device or file system
-	uds := importer.NewUploadDataSource(newContentReader(stream, sourceContentType), dvContentType)
+	contentReader, err := newContentReader(stream, sourceContentType, compression, digest)
+	if err != nil {
+		return false, err
+	}
+	uds := importer.NewUploadDataSource(contentReader, dvContentType, preallocation)
+	if checkpointer != nil {
+		uds.SetCloneCheckpointer(checkpointer)
+	}
 	processor := importer.NewDataProcessor(uds, dest, common.ImporterVolumePath, common.ScratchDataDir, imageSize, filesystemOverhead, preallocation)
-	err := processor.ProcessData()
+	if cloneTarget != nil {
+		// A raw stream is saved to scratch space too, qemu-img converts it from there
+		uds.SetConvert(true)
+		klog.Infof("Converting the clone to qcow2, compression: %q, cluster size: %d, compact: %t", cloneTarget.Compression, cloneTarget.ClusterSize, cloneTarget.Compact)
+		processor.SetTargetFormat(common.ImportTargetFormatQcow2, cloneTarget.Compression)
+		processor.SetQcow2Layout(cloneTarget.ClusterSize, cloneTarget.Compact)
+	}
+	err = processor.ProcessData()
+	if err == nil && digest != nil {
+		err = drainCloneStream(contentReader)
+	}
 	return processor.PreallocationApplied(), err
 }
 
 // Clone file system to block device or file system
-func filesystemCloneProcessor(stream io.ReadCloser, dest string) error {
+func filesystemCloneProcessor(stream io.ReadCloser, dest, compression string, digest hash.Hash) error {
+	reader, err := newCloneStreamReader(stream, compression, digest)
+	if err != nil {
+		return err
+	}
 	// Clone to block device
 	if dest == common.WriteBlockPath {
-		if err := untarToBlockdev(newSnappyReadCloser(stream), dest); err != nil {
+		if err := untarToBlockdev(reader, dest); err != nil {
 			return errors.Wrapf(err, "error unarchiving to %s", dest)
 		}
-		return nil
+	} else {
+		// Clone to file system
+		destDir := common.ImporterVolumePath
+		if err := util.UnArchiveTar(reader, destDir); err != nil {
+			return errors.Wrapf(err, "error unarchiving to %s", destDir)
+		}
 	}
+	if digest != nil {
+		return drainCloneStream(reader)
+	}
+	return nil
+}
 
-	// Clone to file system
-	destDir := common.ImporterVolumePath
-	if err := util.UnArchiveTar(newSnappyReadCloser(stream), destDir); err != nil {
-		return errors.Wrapf(err, "error unarchiving to %s", destDir)
+// drainCloneStream reads the rest of a clone stream the processor did not need, like the padding of a tar archive, so
+// its checksum covers the whole stream and the trailer of the request is received
+func drainCloneStream(reader io.Reader) error {
+	if _, err := io.Copy(io.Discard, reader); err != nil {
+		return errors.Wrap(err, "error reading the end of the clone stream")
 	}
 	return nil
 }
@@ -468,13 +984,14 @@ func untarToBlockdev(stream io.Reader, dest string) error {
 		header, err := tr.Next()
 		switch {
 		case err == io.EOF:
-			return nil
+			return errors.Errorf("no %s found in the source filesystem, unable to convert it to a block device", common.DiskImageName)
 		case err != nil:
 			return err
 		case header == nil:
 			continue
 		}
-		if header.Typeflag == tar.TypeGNUSparse && strings.Contains(header.Name, common.DiskImageName) {
+		isFile := header.Typeflag == tar.TypeGNUSparse || header.Typeflag == tar.TypeReg
+		if isFile && strings.Contains(header.Name, common.DiskImageName) {
 			klog.Infof("Untaring %d bytes to %s", header.Size, dest)
 			f, err := os.OpenFile(dest, os.O_APPEND|os.O_WRONLY, os.ModeDevice|os.ModePerm)
 			if err != nil {
@@ -491,14 +1008,23 @@ func untarToBlockdev(stream io.Reader, dest string) error {
 	}
 }
 
-func newContentReader(stream io.ReadCloser, contentType string) io.ReadCloser {
+func newContentReader(stream io.ReadCloser, contentType, compression string, digest hash.Hash) (io.ReadCloser, error) {
 	if contentType == common.BlockdeviceClone {
-		return newSnappyReadCloser(stream)
+		return newCloneStreamReader(stream, compression, digest)
 	}
 
-	return stream
+	return stream, nil
 }
 
-func newSnappyReadCloser(stream io.ReadCloser) io.ReadCloser {
-	return io.NopCloser(snappy.NewReader(stream))
+// newCloneStreamReader decompresses the clone stream, writing the decompressed data to digest when it is not nil
+func newCloneStreamReader(stream io.ReadCloser, compression string, digest hash.Hash) (io.ReadCloser, error) {
+	decompressed, err := util.NewCloneDecompressionReader(stream, compression)
+	if err != nil {
+		return nil, err
+	}
+	var reader io.Reader = decompressed
+	if digest != nil {
+		reader = io.TeeReader(reader, digest)
+	}
+	return io.NopCloser(reader), nil
 }
@@ -20,18 +20,27 @@
 package uploadserver
 
 import (
+	"archive/tar"
 	"bytes"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"hash"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/golang/snappy"
 	. "github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
@@ -39,13 +48,15 @@ import (
 	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/importer"
+	"kubevirt.io/containerized-data-importer/pkg/util"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert/triple"
+	prometheusutil "kubevirt.io/containerized-data-importer/pkg/util/prometheus"
 	cryptowatch "kubevirt.io/containerized-data-importer/pkg/util/tls-crypto-watch"
 )
 
 func newServer() *uploadServerApp {
-	server := NewUploadServer("127.0.0.1", 0, "disk.img", "", "", "", "", "", 0.055, false, *cryptowatch.DefaultCryptoConfig())
+	server := NewUploadServer("127.0.0.1", 0, "disk.img", "", "", "", "", "", 0.055, false, *cryptowatch.DefaultCryptoConfig(), nil)
 	return server.(*uploadServerApp)
 }
 
@@ -63,7 +74,7 @@ func newTLSServer(clientCertName, expectedName string) (*uploadServerApp, *tripl
 	tlsCert := string(cert.EncodeCertPEM(serverKeyPair.Cert))
 	clientCert := string(cert.EncodeCertPEM(clientCA.Cert))
 
-	server := NewUploadServer("127.0.0.1", 0, "disk.img", tlsKey, tlsCert, clientCert, expectedName, "", 0.055, false, *cryptowatch.DefaultCryptoConfig()).(*uploadServerApp)
+	server := NewUploadServer("127.0.0.1", 0, "disk.img", tlsKey, tlsCert, clientCert, expectedName, "", 0.055, false, *cryptowatch.DefaultCryptoConfig(), nil).(*uploadServerApp)
 
 	clientKeyPair, err := triple.NewClientKeyPair(clientCA, clientCertName, []string{})
 	Expect(err).ToNot(HaveOccurred())
@@ -90,11 +101,11 @@ func newHTTPClient(clientKeyPair *triple.KeyPair, serverCACert *x509.Certificate
 	return client
 }
 
-func saveProcessorSuccess(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType string, dvContentType cdiv1.DataVolumeContentType) (bool, error) {
+func saveProcessorSuccess(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType, compression string, dvContentType cdiv1.DataVolumeContentType, checkpointer *importer.CloneCheckpointer, cloneTarget *CloneTargetImage, digest hash.Hash) (bool, error) {
 	return false, nil
 }
 
-func saveProcessorFailure(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType string, dvContentType cdiv1.DataVolumeContentType) (bool, error) {
+func saveProcessorFailure(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType, compression string, dvContentType cdiv1.DataVolumeContentType, checkpointer *importer.CloneCheckpointer, cloneTarget *CloneTargetImage, digest hash.Hash) (bool, error) {
 	return false, fmt.Errorf("Error using datastream")
 }
 
@@ -106,7 +117,7 @@ func withProcessorFailure(f func()) {
 	replaceProcessorFunc(saveProcessorFailure, f)
 }
 
-func replaceProcessorFunc(replacement func(io.ReadCloser, string, string, float64, bool, string, cdiv1.DataVolumeContentType) (bool, error), f func()) {
+func replaceProcessorFunc(replacement func(io.ReadCloser, string, string, float64, bool, string, string, cdiv1.DataVolumeContentType, *importer.CloneCheckpointer, *CloneTargetImage, hash.Hash) (bool, error), f func()) {
 	origProcessorFunc := uploadProcessorFunc
 	uploadProcessorFunc = replacement
 	defer func() {
@@ -376,6 +387,524 @@ var _ = Describe("Upload server tests", func() {
 		table.Entry("Valid data", "client", "client", 200),
 		table.Entry("Invalid data", "foo", "bar", 401),
 	)
+
+	table.DescribeTable("Status with client", func(certName string, expectedName string, expectedResponse int) {
+		server, clientKeyPair, serverCACert := newTLSServer(certName, expectedName)
+
+		client := newHTTPClient(clientKeyPair, serverCACert)
+
+		ch := make(chan struct{})
+
+		go func() {
+			server.Run()
+			close(ch)
+		}()
+
+		for i := 0; i < 10; i++ {
+			if server.bindPort != 0 {
+				break
+			}
+			time.Sleep(500 * time.Millisecond)
+		}
+
+		Expect(server.bindPort).ToNot(Equal(0))
+
+		prometheusutil.SetPhase("TransferDataFile")
+		url := fmt.Sprintf("https://localhost:%d%s", server.bindPort, prometheusutil.StatusPath)
+		resp, err := client.Get(url)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(expectedResponse))
+		if expectedResponse == http.StatusOK {
+			status := prometheusutil.TransferStatus{}
+			Expect(json.NewDecoder(resp.Body).Decode(&status)).To(Succeed())
+			Expect(status.Phase).To(Equal("TransferDataFile"))
+		}
+		resp.Body.Close()
+
+		close(server.doneChan)
+		<-ch
+	},
+		table.Entry("Valid client", "client", "client", http.StatusOK),
+		table.Entry("Invalid client", "foo", "bar", http.StatusUnauthorized),
+	)
+
+	table.DescribeTable("Filesystem to block clone", func(name string, typeflag byte, expectErr bool) {
+		dest, err := os.CreateTemp("", "blockdev")
+		Expect(err).ToNot(HaveOccurred())
+		dest.Close()
+		defer os.Remove(dest.Name())
+
+		data := []byte("disk image data")
+		var b bytes.Buffer
+		tw := tar.NewWriter(&b)
+		err = tw.WriteHeader(&tar.Header{Name: name, Typeflag: typeflag, Size: int64(len(data)), Mode: 0644})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = tw.Write(data)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tw.Close()).To(Succeed())
+
+		err = untarToBlockdev(&b, dest.Name())
+		if expectErr {
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(common.DiskImageName))
+			return
+		}
+		Expect(err).ToNot(HaveOccurred())
+		written, err := os.ReadFile(dest.Name())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(written).To(Equal(data))
+	},
+		table.Entry("should copy a regular disk image", "./"+common.DiskImageName, byte(tar.TypeReg), false),
+		table.Entry("should fail if the source has no disk image", "./other.img", byte(tar.TypeReg), true),
+	)
+})
+
+var _ = Describe("Clone checkpoint", func() {
+	const chunkSize = 1024 * 1024
+
+	var (
+		tmpDir     string
+		server     *uploadServerApp
+		checkpoint util.CloneCheckpoint
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "clone-checkpoint")
+		Expect(err).ToNot(HaveOccurred())
+		server = newServer()
+		server.destination = filepath.Join(tmpDir, "disk.img")
+		server.cloneCheckpointFile = filepath.Join(tmpDir, cloneCheckpointFileName)
+
+		// The target of a clone interrupted after writing two chunks
+		Expect(os.WriteFile(server.destination, bytes.Repeat([]byte("cloned data"), 2*chunkSize/10), 0644)).To(Succeed())
+		sum, err := util.Sha256sumRange(server.destination, chunkSize, chunkSize)
+		Expect(err).ToNot(HaveOccurred())
+		checkpoint = util.CloneCheckpoint{Offset: 2 * chunkSize, ChunkOffset: chunkSize, ChunkSHA256: sum}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	writeCheckpoint := func() {
+		data, err := json.Marshal(checkpoint)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.WriteFile(server.cloneCheckpointFile, data, 0600)).To(Succeed())
+	}
+
+	getCheckpoint := func() *httptest.ResponseRecorder {
+		req, err := http.NewRequest("GET", common.UploadPathCloneCheckpoint, nil)
+		Expect(err).ToNot(HaveOccurred())
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		return rr
+	}
+
+	var cloneTarget *CloneTargetImage
+
+	postClone := func(offset string) (*httptest.ResponseRecorder, *importer.CloneCheckpointer) {
+		var checkpointer *importer.CloneCheckpointer
+		cloneTarget = nil
+		rr := httptest.NewRecorder()
+		replaceProcessorFunc(func(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType, compression string, dvContentType cdiv1.DataVolumeContentType, c *importer.CloneCheckpointer, t *CloneTargetImage, _ hash.Hash) (bool, error) {
+			checkpointer = c
+			cloneTarget = t
+			return false, nil
+		}, func() {
+			req, err := http.NewRequest("POST", common.UploadPathSync, strings.NewReader("data"))
+			Expect(err).ToNot(HaveOccurred())
+			req.Header.Set(common.UploadContentTypeHeader, common.BlockdeviceClone)
+			if offset != "" {
+				req.Header.Set(common.CloneOffsetHeader, offset)
+			}
+			server.ServeHTTP(rr, req)
+		})
+		return rr, checkpointer
+	}
+
+	It("should return the checkpoint matching the target", func() {
+		writeCheckpoint()
+		rr := getCheckpoint()
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		returned := util.CloneCheckpoint{}
+		Expect(json.Unmarshal(rr.Body.Bytes(), &returned)).To(Succeed())
+		Expect(returned).To(Equal(checkpoint))
+	})
+
+	table.DescribeTable("should not return a checkpoint", func(setup func(), reason string) {
+		setup()
+		rr := getCheckpoint()
+		Expect(rr.Code).To(Equal(http.StatusNotFound))
+		Expect(rr.Body.String()).To(ContainSubstring(reason))
+	},
+		table.Entry("without checkpoint", func() {}, "no clone checkpoint"),
+		table.Entry("not matching the target", func() {
+			checkpoint.ChunkSHA256 = strings.Repeat("0", 64)
+			writeCheckpoint()
+		}, "does not match"),
+		table.Entry("when not kept", func() {
+			server.cloneCheckpointFile = filepath.Join(tmpDir, "missing", cloneCheckpointFileName)
+		}, "not kept"),
+	)
+
+	It("should checkpoint a raw clone stream from scratch", func() {
+		rr, checkpointer := postClone("")
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(checkpointer).ToNot(BeNil())
+		Expect(checkpointer.Offset()).To(BeZero())
+	})
+
+	It("should resume a raw clone stream from the checkpoint", func() {
+		writeCheckpoint()
+		rr, checkpointer := postClone("2097152")
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(checkpointer.Offset()).To(Equal(int64(2 * chunkSize)))
+	})
+
+	It("should refuse resuming a raw clone stream from another offset than the checkpoint", func() {
+		writeCheckpoint()
+		rr, checkpointer := postClone("1048576")
+		Expect(rr.Code).To(Equal(http.StatusPreconditionFailed))
+		Expect(rr.Body.String()).To(ContainSubstring("not 1048576"))
+		Expect(checkpointer).To(BeNil())
+		Expect(server.uploading).To(BeFalse())
+	})
+
+	It("should convert a raw clone stream into the clone target image without checkpoints", func() {
+		server.cloneTarget = &CloneTargetImage{Compression: "zlib", ClusterSize: 2097152, Compact: true}
+		writeCheckpoint()
+		rr, checkpointer := postClone("")
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(checkpointer).To(BeNil())
+		Expect(cloneTarget).To(Equal(server.cloneTarget))
+		Expect(getCheckpoint().Code).To(Equal(http.StatusNotFound))
+	})
+})
+
+var _ = Describe("Clone checksum", func() {
+	var (
+		tmpDir string
+		server *uploadServerApp
+		data   = bytes.Repeat([]byte("cloned data"), 1000)
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "clone-checksum")
+		Expect(err).ToNot(HaveOccurred())
+		server = newServer()
+		server.destination = filepath.Join(tmpDir, "disk.img")
+		server.cloneCheckpointFile = filepath.Join(tmpDir, cloneCheckpointFileName)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	sha256sum := func(data []byte) string {
+		sum := sha256.Sum256(data)
+		return hex.EncodeToString(sum[:])
+	}
+
+	// postClone posts the snappy encoded stream to a processor reading it like the clone processors
+	postClone := func(stream []byte, trailer http.Header, offset string) (*httptest.ResponseRecorder, hash.Hash) {
+		var digest hash.Hash
+		var body bytes.Buffer
+		sw := snappy.NewBufferedWriter(&body)
+		_, err := sw.Write(stream)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sw.Close()).To(Succeed())
+		rr := httptest.NewRecorder()
+		replaceProcessorFunc(func(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType, compression string, dvContentType cdiv1.DataVolumeContentType, c *importer.CloneCheckpointer, t *CloneTargetImage, d hash.Hash) (bool, error) {
+			digest = d
+			reader, err := newContentReader(stream, contentType, compression, d)
+			if err != nil {
+				return false, err
+			}
+			// Reads part of the stream, like the processor of a tar archive not reading its padding
+			if _, err := reader.Read(make([]byte, 10)); err != nil {
+				return false, err
+			}
+			return false, drainCloneStream(reader)
+		}, func() {
+			req, err := http.NewRequest("POST", common.UploadPathSync, &body)
+			Expect(err).ToNot(HaveOccurred())
+			req.Header.Set(common.UploadContentTypeHeader, common.BlockdeviceClone)
+			if offset != "" {
+				req.Header.Set(common.CloneOffsetHeader, offset)
+			}
+			req.Trailer = trailer
+			server.ServeHTTP(rr, req)
+		})
+		return rr, digest
+	}
+
+	It("should verify the clone stream against the checksum of the source", func() {
+		rr, digest := postClone(data, http.Header{common.CloneSHA256Trailer: []string{sha256sum(data)}}, "")
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(digest).ToNot(BeNil())
+	})
+
+	It("should reject a clone stream not matching the checksum of the source", func() {
+		rr, _ := postClone(data, http.Header{common.CloneSHA256Trailer: []string{sha256sum([]byte("source data"))}}, "")
+		Expect(rr.Code).To(Equal(http.StatusInternalServerError))
+		Expect(rr.Body.String()).To(ContainSubstring(common.CloneChecksumMismatchMessage))
+		Expect(rr.Body.String()).To(ContainSubstring(sha256sum(data)))
+		Expect(server.uploading).To(BeFalse())
+		Expect(server.done).To(BeFalse())
+	})
+
+	It("should clear the clone checkpoint of a resumed clone stream not matching the checksum of the source", func() {
+		const chunkSize = 1024 * 1024
+		Expect(os.WriteFile(server.destination, bytes.Repeat([]byte("written data"), 2*chunkSize/10), 0644)).To(Succeed())
+		sum, err := util.Sha256sumRange(server.destination, chunkSize, chunkSize)
+		Expect(err).ToNot(HaveOccurred())
+		checkpoint, err := json.Marshal(util.CloneCheckpoint{Offset: 2 * chunkSize, ChunkOffset: chunkSize, ChunkSHA256: sum})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.WriteFile(server.cloneCheckpointFile, checkpoint, 0600)).To(Succeed())
+
+		rr, _ := postClone(data, http.Header{common.CloneSHA256Trailer: []string{sha256sum([]byte("source data"))}}, "2097152")
+		Expect(rr.Code).To(Equal(http.StatusInternalServerError))
+		Expect(rr.Body.String()).To(ContainSubstring(common.CloneChecksumMismatchMessage))
+		_, err = os.Stat(server.cloneCheckpointFile)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("should not verify a clone stream without checksum", func() {
+		rr, digest := postClone(data, nil, "")
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(digest).To(BeNil())
+	})
+
+	It("should verify a resumed clone stream with the part written before", func() {
+		const chunkSize = 1024 * 1024
+		written := bytes.Repeat([]byte("written data"), 2*chunkSize/10)
+		Expect(os.WriteFile(server.destination, written, 0644)).To(Succeed())
+		sum, err := util.Sha256sumRange(server.destination, chunkSize, chunkSize)
+		Expect(err).ToNot(HaveOccurred())
+		checkpoint, err := json.Marshal(util.CloneCheckpoint{Offset: 2 * chunkSize, ChunkOffset: chunkSize, ChunkSHA256: sum})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.WriteFile(server.cloneCheckpointFile, checkpoint, 0600)).To(Succeed())
+
+		disk := append(append([]byte{}, written[:2*chunkSize]...), data...)
+		rr, _ := postClone(data, http.Header{common.CloneSHA256Trailer: []string{sha256sum(disk)}}, "2097152")
+		Expect(rr.Code).To(Equal(http.StatusOK))
+	})
+})
+
+var _ = Describe("Clone compression", func() {
+	var (
+		server *uploadServerApp
+		data   = bytes.Repeat([]byte("compressed data"), 1000)
+	)
+
+	BeforeEach(func() {
+		server = newServer()
+	})
+
+	It("should list the accepted codecs on a HEAD request", func() {
+		req, err := http.NewRequest("HEAD", common.UploadPathSync, nil)
+		Expect(err).ToNot(HaveOccurred())
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(rr.Header().Get(common.CloneCompressionAcceptHeader)).To(Equal("none,gzip,snappy,zstd"))
+	})
+
+	table.DescribeTable("should decompress the clone stream", func(codec string) {
+		var body bytes.Buffer
+		w, err := util.NewCloneCompressionWriter(&body, codec)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = w.Write(data)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(w.Close()).To(Succeed())
+		var received []byte
+		rr := httptest.NewRecorder()
+		replaceProcessorFunc(func(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType, compression string, dvContentType cdiv1.DataVolumeContentType, c *importer.CloneCheckpointer, t *CloneTargetImage, d hash.Hash) (bool, error) {
+			reader, err := newContentReader(stream, contentType, compression, d)
+			if err != nil {
+				return false, err
+			}
+			received, err = io.ReadAll(reader)
+			return false, err
+		}, func() {
+			req, err := http.NewRequest("POST", common.UploadPathSync, &body)
+			Expect(err).ToNot(HaveOccurred())
+			req.Header.Set(common.UploadContentTypeHeader, common.BlockdeviceClone)
+			if codec != common.CloneCompressionSnappy {
+				req.Header.Set(common.CloneCompressionHeader, codec)
+			}
+			server.ServeHTTP(rr, req)
+		})
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(received).To(Equal(data))
+	},
+		table.Entry("uncompressed", common.CloneCompressionNone),
+		table.Entry("with gzip", common.CloneCompressionGzip),
+		table.Entry("with snappy by default", common.CloneCompressionSnappy),
+		table.Entry("with zstd", common.CloneCompressionZstd),
+	)
+
+	It("should reject a clone stream of an unknown codec", func() {
+		rr := httptest.NewRecorder()
+		withProcessorSuccess(func() {
+			req, err := http.NewRequest("POST", common.UploadPathSync, bytes.NewReader(data))
+			Expect(err).ToNot(HaveOccurred())
+			req.Header.Set(common.UploadContentTypeHeader, common.BlockdeviceClone)
+			req.Header.Set(common.CloneCompressionHeader, "lz4")
+			server.ServeHTTP(rr, req)
+		})
+		Expect(rr.Code).To(Equal(http.StatusUnsupportedMediaType))
+		Expect(server.uploading).To(BeFalse())
+	})
+})
+
+var _ = Describe("Parallel clone streams", func() {
+	const mi = 1024 * 1024
+
+	var (
+		tmpDir      string
+		dest        string
+		server      *uploadServerApp
+		data        []byte
+		origWriter  = cloneRangeWriterFunc
+		writtenDest string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "clone-ranges")
+		Expect(err).ToNot(HaveOccurred())
+		dest = filepath.Join(tmpDir, "disk.img")
+		Expect(os.WriteFile(dest, nil, 0600)).To(Succeed())
+		data = bytes.Repeat([]byte("cloned disk data"), 3*mi/16)
+		server = newServer()
+		server.destination = common.WriteBlockPath
+		cloneRangeWriterFunc = func(r io.Reader, d string, cloneRange util.CloneRange, preallocate bool) error {
+			writtenDest = d
+			return importer.StreamRawRange(r, dest, cloneRange, preallocate)
+		}
+	})
+
+	AfterEach(func() {
+		cloneRangeWriterFunc = origWriter
+		os.RemoveAll(tmpDir)
+	})
+
+	sha256sum := func(data []byte) string {
+		sum := sha256.Sum256(data)
+		return hex.EncodeToString(sum[:])
+	}
+
+	postRange := func(cloneRange util.CloneRange, sum string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		sw := snappy.NewBufferedWriter(&body)
+		_, err := sw.Write(data[cloneRange.Start:cloneRange.End])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sw.Close()).To(Succeed())
+		req, err := http.NewRequest("POST", common.UploadPathSync, &body)
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set(common.UploadContentTypeHeader, common.BlockdeviceClone)
+		req.Header.Set(common.CloneRangeHeader, cloneRange.String())
+		req.Trailer = http.Header{common.CloneSHA256Trailer: []string{sum}}
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		return rr
+	}
+
+	It("should be done once the ranges cover the whole disk", func() {
+		server.preallocation = true
+		ranges := util.SplitCloneRanges(int64(len(data)), 3)
+		Expect(ranges).To(HaveLen(3))
+		for _, i := range []int{2, 0, 1} {
+			Expect(server.done).To(BeFalse())
+			r := ranges[i]
+			rr := postRange(r, sha256sum(data[r.Start:r.End]))
+			Expect(rr.Code).To(Equal(http.StatusOK))
+		}
+		Expect(server.done).To(BeTrue())
+		Expect(server.preallocationApplied).To(BeTrue())
+		Expect(server.cloneRanges.isStreaming()).To(BeFalse())
+		Expect(writtenDest).To(Equal(common.WriteBlockPath))
+		written, err := os.ReadFile(dest)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(written).To(Equal(data))
+	})
+
+	It("should reject a range not matching the checksum of the source", func() {
+		r := util.SplitCloneRanges(int64(len(data)), 3)[1]
+		rr := postRange(r, sha256sum([]byte("source data")))
+		Expect(rr.Code).To(Equal(http.StatusInternalServerError))
+		Expect(rr.Body.String()).To(ContainSubstring(common.CloneChecksumMismatchMessage))
+		Expect(server.done).To(BeFalse())
+		Expect(server.cloneRanges.isStreaming()).To(BeFalse())
+		Expect(server.cloneRanges.written).To(BeEmpty())
+	})
+
+	It("should reject ranges for a target not written as a raw block device", func() {
+		server.destination = dest
+		r := util.SplitCloneRanges(int64(len(data)), 3)[0]
+		rr := postRange(r, sha256sum(data[r.Start:r.End]))
+		Expect(rr.Code).To(Equal(http.StatusPreconditionFailed))
+		Expect(server.cloneRanges).To(BeNil())
+	})
+
+	It("should reject an invalid range", func() {
+		rr := postRange(util.CloneRange{Start: 0, End: mi, Size: mi / 2}, "")
+		Expect(rr.Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("should not be done once the changed ranges of an incremental clone are written", func() {
+		r := util.CloneRange{Start: 0, End: int64(len(data)), Size: int64(len(data))}
+		var body bytes.Buffer
+		sw := snappy.NewBufferedWriter(&body)
+		_, err := sw.Write(data)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sw.Close()).To(Succeed())
+		req, err := http.NewRequest("POST", common.UploadPathSync, &body)
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set(common.UploadContentTypeHeader, common.BlockdeviceClone)
+		req.Header.Set(common.CloneRangeHeader, r.String())
+		req.Header.Set(common.CloneIncrementalHeader, strconv.Itoa(len(data)))
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(server.cloneRanges.incremental).To(BeTrue())
+		Expect(server.done).To(BeFalse())
+	})
+
+	It("should reject the end of an incremental clone to a target not written as a raw block device", func() {
+		server.destination = dest
+		req, err := http.NewRequest("POST", common.UploadPathSync, strings.NewReader(""))
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set(common.UploadContentTypeHeader, common.BlockdeviceClone)
+		req.Header.Set(common.CloneIncrementalHeader, strconv.Itoa(len(data)))
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusPreconditionFailed))
+		Expect(server.uploading).To(BeFalse())
+		Expect(server.done).To(BeFalse())
+	})
+
+	It("should not return the block checksums of a target not written as a raw block device", func() {
+		server.destination = dest
+		req, err := http.NewRequest("GET", common.UploadPathCloneBlocks+"?size=16", nil)
+		Expect(err).ToNot(HaveOccurred())
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("should reject an upload while ranges are streamed", func() {
+		server.cloneRanges = newCloneRangeTracker(int64(len(data)))
+		server.cloneRanges.streaming[0] = true
+		req, err := http.NewRequest("POST", common.UploadPathSync, bytes.NewReader(data))
+		Expect(err).ToNot(HaveOccurred())
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusServiceUnavailable))
+	})
 })
 
 func newFormRequest(path string) *http.Request {
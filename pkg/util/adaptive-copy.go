@@ -0,0 +1,181 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"io"
+	"math"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// MinCopyBufferSize is the size the adaptive copy buffer starts at, the buffer size of io.Copy
+	MinCopyBufferSize = 32 * 1024
+	// DefaultMaxCopyBufferSize is the default bound of the adaptive copy buffer
+	DefaultMaxCopyBufferSize = 4 * 1024 * 1024
+	// MaxCopyBufferSizeLimit is the largest bound the adaptive copy buffer can be given
+	MaxCopyBufferSizeLimit = 64 * 1024 * 1024
+
+	// copyWindowReads is the number of reads the throughput is observed over before resizing the buffer
+	copyWindowReads = 16
+	// copyThroughputTolerance is the share of the previous throughput a grown buffer must keep, it shrinks back below it
+	copyThroughputTolerance = 0.9
+)
+
+// maxCopyBufferSize bounds the adaptive copy buffer of StreamDataToFile
+var maxCopyBufferSize = DefaultMaxCopyBufferSize
+
+// SetMaxCopyBufferSize bounds the adaptive copy buffer of StreamDataToFile, within MinCopyBufferSize and
+// MaxCopyBufferSizeLimit
+func SetMaxCopyBufferSize(size int64) {
+	switch {
+	case size < MinCopyBufferSize:
+		size = MinCopyBufferSize
+	case size > MaxCopyBufferSizeLimit:
+		size = MaxCopyBufferSizeLimit
+	}
+	maxCopyBufferSize = int(size)
+}
+
+// ParseMaxCopyBufferSize parses the bound of the adaptive copy buffer, a quantity like "8Mi" between 32Ki and 64Mi.
+// An empty value means the default bound.
+func ParseMaxCopyBufferSize(value string) (int64, error) {
+	if value == "" {
+		return DefaultMaxCopyBufferSize, nil
+	}
+	size, err := resource.ParseQuantity(value)
+	if err != nil || size.Value() < MinCopyBufferSize || size.Value() > MaxCopyBufferSizeLimit {
+		return 0, errors.Errorf("invalid copy buffer size %q, must be a size between 32Ki and 64Mi like 8Mi", value)
+	}
+	return size.Value(), nil
+}
+
+// AdaptiveCopy copies src to dst like io.Copy, through a buffer starting at MinCopyBufferSize and resized on the
+// throughput observed over the reads, up to maxBufferSize. The buffer grows while the reads fill it and the throughput
+// keeps up, so a large source is copied with fewer syscalls, and shrinks when the reads only use a small part of it,
+// so a slow source does not hold memory it does not use. A file or a socket is copied with io.Copy, so the ReadFrom of
+// a file destination lets the kernel copy it.
+func AdaptiveCopy(dst io.Writer, src io.Reader, maxBufferSize int) (int64, error) {
+	if kernelCopyable(src) {
+		return io.Copy(dst, src)
+	}
+	return newAdaptiveBuffer(maxBufferSize, time.Now).copy(dst, src)
+}
+
+// kernelCopyable tells whether src is a file or a socket, possibly limited, which os.File.ReadFrom copies with
+// copy_file_range or splice
+func kernelCopyable(src io.Reader) bool {
+	if lr, ok := src.(*io.LimitedReader); ok {
+		src = lr.R
+	}
+	_, ok := src.(syscall.Conn)
+	return ok
+}
+
+// adaptiveBuffer is the buffer of an adaptive copy, with the reads observed since it was last resized
+type adaptiveBuffer struct {
+	buf []byte
+	max int
+	// ceiling is the size the buffer does not grow to anymore, after growing to it made the copy slower
+	ceiling int
+	now     func() time.Time
+
+	reads       int
+	fullReads   int
+	bytes       int64
+	windowStart time.Time
+	// lastThroughput is the throughput of the previous window in bytes per second, grown tells the buffer grew after it
+	lastThroughput float64
+	grown          bool
+}
+
+func newAdaptiveBuffer(maxBufferSize int, now func() time.Time) *adaptiveBuffer {
+	if maxBufferSize < MinCopyBufferSize {
+		maxBufferSize = MinCopyBufferSize
+	}
+	return &adaptiveBuffer{buf: make([]byte, MinCopyBufferSize), max: maxBufferSize, now: now}
+}
+
+func (b *adaptiveBuffer) copy(dst io.Writer, src io.Reader) (int64, error) {
+	var written int64
+	// The reads are timed as a window, so the clock is only read once per window
+	b.windowStart = b.now()
+	for {
+		nr, readErr := src.Read(b.buf)
+		if nr > 0 {
+			nw, err := dst.Write(b.buf[:nr])
+			if nw < 0 || nw > nr {
+				nw = 0
+				if err == nil {
+					err = errors.New("invalid write result")
+				}
+			}
+			written += int64(nw)
+			if err != nil {
+				return written, err
+			}
+			if nw != nr {
+				return written, io.ErrShortWrite
+			}
+			b.observe(nr)
+		}
+		if readErr == io.EOF {
+			return written, nil
+		}
+		if readErr != nil {
+			return written, readErr
+		}
+	}
+}
+
+// observe records a read and its write, and resizes the buffer once a window of reads was observed
+func (b *adaptiveBuffer) observe(n int) {
+	b.reads++
+	if n == len(b.buf) {
+		b.fullReads++
+	}
+	b.bytes += int64(n)
+	if b.reads < copyWindowReads {
+		return
+	}
+
+	now := b.now()
+	throughput := float64(b.bytes) / math.Max(now.Sub(b.windowStart).Seconds(), 1e-9)
+	size := len(b.buf)
+	switch {
+	case b.grown && throughput < b.lastThroughput*copyThroughputTolerance:
+		// The larger buffer made the copy slower, go back to the previous size and stay below
+		b.ceiling = size
+		size /= 2
+	case b.fullReads*2 >= b.reads && size*2 <= b.max && (b.ceiling == 0 || size*2 < b.ceiling):
+		// Most reads fill the buffer, the source has more to give per read
+		size *= 2
+	case b.fullReads == 0 && b.bytes/int64(b.reads) < int64(size/4) && size > MinCopyBufferSize:
+		// The reads only use a small part of the buffer
+		size /= 2
+	}
+	b.grown = size > len(b.buf)
+	b.lastThroughput = throughput
+	if size != len(b.buf) {
+		b.buf = make([]byte, size)
+	}
+	b.reads, b.fullReads, b.bytes, b.windowStart = 0, 0, 0, now
+}
@@ -0,0 +1,211 @@
+package util
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+// streamReader hides the WriterTo of a reader, so it is read through the copy buffer like a network stream
+type streamReader struct {
+	io.Reader
+}
+
+// chunkReader returns at most size bytes per read, like a source delivering its data in chunks
+type chunkReader struct {
+	r    io.Reader
+	size int
+}
+
+func (c *chunkReader) Read(p []byte) (int, error) {
+	if len(p) > c.size {
+		p = p[:c.size]
+	}
+	return c.r.Read(p)
+}
+
+// clockedWriter discards its writes, advancing a fake clock by the cost of each write
+type clockedWriter struct {
+	clock time.Time
+	cost  func(n int) time.Duration
+}
+
+func (w *clockedWriter) Write(p []byte) (int, error) {
+	w.clock = w.clock.Add(w.cost(len(p)))
+	return len(p), nil
+}
+
+func (w *clockedWriter) now() time.Time {
+	return w.clock
+}
+
+func randomData(size int) []byte {
+	data := make([]byte, size)
+	_, err := rand.Read(data)
+	Expect(err).ToNot(HaveOccurred())
+	return data
+}
+
+var _ = Describe("Adaptive copy", func() {
+	table.DescribeTable("should copy the source as is", func(size int, reader func(io.Reader) io.Reader) {
+		data := randomData(size)
+		var out bytes.Buffer
+		written, err := AdaptiveCopy(&out, reader(bytes.NewReader(data)), DefaultMaxCopyBufferSize)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(written).To(Equal(int64(size)))
+		Expect(out.Bytes()).To(Equal(data))
+	},
+		table.Entry("when empty", 0, func(r io.Reader) io.Reader { return streamReader{r} }),
+		table.Entry("when smaller than the buffer", 1000, func(r io.Reader) io.Reader { return streamReader{r} }),
+		table.Entry("when larger than the largest buffer", 10*1024*1024+7, func(r io.Reader) io.Reader { return streamReader{r} }),
+		table.Entry("when read byte by byte", 100*1024, iotest.OneByteReader),
+		table.Entry("when read in halves", 1024*1024, iotest.HalfReader),
+		table.Entry("when read with its last data", 1024*1024, iotest.DataErrReader),
+		table.Entry("when read in small chunks", 1024*1024, func(r io.Reader) io.Reader { return &chunkReader{r: r, size: 1000} }),
+	)
+
+	It("should return the read error", func() {
+		data := randomData(100 * 1024)
+		var out bytes.Buffer
+		written, err := AdaptiveCopy(&out, iotest.TimeoutReader(iotest.HalfReader(bytes.NewReader(data))), DefaultMaxCopyBufferSize)
+		Expect(err).To(Equal(iotest.ErrTimeout))
+		Expect(written).To(Equal(int64(out.Len())))
+		Expect(out.Bytes()).To(Equal(data[:out.Len()]))
+	})
+
+	It("should return the write error", func() {
+		file, err := os.CreateTemp("", "adaptive-copy")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.Remove(file.Name())
+		file.Close()
+		_, err = AdaptiveCopy(file, streamReader{bytes.NewReader(randomData(1024))}, DefaultMaxCopyBufferSize)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should grow the buffer up to its bound on a fast source", func() {
+		w := &clockedWriter{cost: func(n int) time.Duration { return time.Duration(n) }}
+		b := newAdaptiveBuffer(1024*1024, w.now)
+		_, err := b.copy(w, streamReader{bytes.NewReader(make([]byte, 64*1024*1024))})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(b.buf).To(HaveLen(1024 * 1024))
+	})
+
+	It("should shrink the buffer back when growing it made the copy slower", func() {
+		w := &clockedWriter{cost: func(n int) time.Duration {
+			if n > 128*1024 {
+				return time.Duration(4 * n)
+			}
+			return time.Duration(n)
+		}}
+		b := newAdaptiveBuffer(DefaultMaxCopyBufferSize, w.now)
+		_, err := b.copy(w, streamReader{bytes.NewReader(make([]byte, 64*1024*1024))})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(b.buf).To(HaveLen(128 * 1024))
+		Expect(b.ceiling).To(Equal(256 * 1024))
+	})
+
+	It("should keep the buffer small on a source delivering small chunks", func() {
+		w := &clockedWriter{cost: func(n int) time.Duration { return time.Duration(n) }}
+		b := newAdaptiveBuffer(DefaultMaxCopyBufferSize, w.now)
+		_, err := b.copy(w, &chunkReader{r: bytes.NewReader(make([]byte, 16*1024*1024)), size: 4 * 1024})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(b.buf).To(HaveLen(MinCopyBufferSize))
+	})
+
+	It("should shrink the buffer when the source slows down to small chunks", func() {
+		w := &clockedWriter{cost: func(n int) time.Duration { return time.Duration(n) }}
+		b := newAdaptiveBuffer(DefaultMaxCopyBufferSize, w.now)
+		source := io.MultiReader(streamReader{bytes.NewReader(make([]byte, 64*1024*1024))},
+			&chunkReader{r: bytes.NewReader(make([]byte, 4*1024*1024)), size: 1024})
+		_, err := b.copy(w, source)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(b.buf).To(HaveLen(MinCopyBufferSize))
+	})
+
+	It("should leave a file source to the kernel copy", func() {
+		file, err := os.CreateTemp("", "adaptive-copy")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.Remove(file.Name())
+		defer file.Close()
+		Expect(kernelCopyable(file)).To(BeTrue())
+		Expect(kernelCopyable(io.LimitReader(file, 1024))).To(BeTrue())
+		Expect(kernelCopyable(streamReader{file})).To(BeFalse())
+		Expect(kernelCopyable(bytes.NewReader(nil))).To(BeFalse())
+	})
+
+	table.DescribeTable("should parse the bound of the copy buffer", func(value string, expected int64, valid bool) {
+		size, err := ParseMaxCopyBufferSize(value)
+		if !valid {
+			Expect(err).To(HaveOccurred())
+			return
+		}
+		Expect(err).ToNot(HaveOccurred())
+		Expect(size).To(Equal(expected))
+	},
+		table.Entry("defaulting when empty", "", int64(DefaultMaxCopyBufferSize), true),
+		table.Entry("as a quantity", "8Mi", int64(8*1024*1024), true),
+		table.Entry("at the smallest bound", "32Ki", int64(MinCopyBufferSize), true),
+		table.Entry("rejecting a size below the starting buffer", "16Ki", int64(0), false),
+		table.Entry("rejecting a size above the limit", "128Mi", int64(0), false),
+		table.Entry("rejecting an invalid quantity", "large", int64(0), false),
+	)
+})
+
+// latencyWriter spins for a fixed time on each write, like the syscalls of a network-backed volume
+type latencyWriter struct {
+	io.Writer
+	latency time.Duration
+}
+
+func (w *latencyWriter) Write(p []byte) (int, error) {
+	for start := time.Now(); time.Since(start) < w.latency; {
+	}
+	return w.Writer.Write(p)
+}
+
+// benchmarkCopy copies payloads of the size through a stream into a file, like the importer copies a source to its
+// scratch space, each write taking at least the latency
+func benchmarkCopy(b *testing.B, size int, latency time.Duration, copyFunc func(io.Writer, io.Reader) (int64, error)) {
+	data := make([]byte, size)
+	file, err := os.CreateTemp("", "copy-benchmark")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+	b.SetBytes(int64(size))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			b.Fatal(err)
+		}
+		// The file is wrapped so its ReadFrom does not bypass the copy buffer
+		if _, err := copyFunc(&latencyWriter{Writer: file, latency: latency}, streamReader{bytes.NewReader(data)}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCopy(b *testing.B) {
+	fixed := func(dst io.Writer, src io.Reader) (int64, error) {
+		return io.CopyBuffer(dst, src, make([]byte, MinCopyBufferSize))
+	}
+	adaptive := func(dst io.Writer, src io.Reader) (int64, error) {
+		return AdaptiveCopy(dst, src, DefaultMaxCopyBufferSize)
+	}
+	for _, latency := range []time.Duration{0, 20 * time.Microsecond} {
+		for _, size := range []int{4 * 1024, 256 * 1024, 16 * 1024 * 1024, 256 * 1024 * 1024} {
+			b.Run(fmt.Sprintf("fixed/latency=%v/%d", latency, size), func(b *testing.B) { benchmarkCopy(b, size, latency, fixed) })
+			b.Run(fmt.Sprintf("adaptive/latency=%v/%d", latency, size), func(b *testing.B) { benchmarkCopy(b, size, latency, adaptive) })
+		}
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"io"
+
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// MinBandwidthLimit is the lowest bandwidth limit a transfer can be given, in bytes per second
+const MinBandwidthLimit = 64 * 1024
+
+// ParseBandwidthLimit parses a bandwidth limit in bytes per second, a quantity like "100Mi" of at least 64Ki, or "0"
+// for no limit. An empty value means no limit.
+func ParseBandwidthLimit(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	limit, err := resource.ParseQuantity(value)
+	if err != nil || limit.Sign() < 0 || (!limit.IsZero() && limit.Value() < MinBandwidthLimit) {
+		return 0, errors.Errorf("invalid bandwidth limit %q, must be 0 or a number of bytes per second of at least 64Ki like 100Mi", value)
+	}
+	return limit.Value(), nil
+}
+
+type throttledReader struct {
+	reader  io.Reader
+	limiter *rate.Limiter
+}
+
+// NewThrottledReader returns a reader reading r at no more than bytesPerSecond on average, r itself when
+// bytesPerSecond is not positive. A burst of up to one second of reads is allowed, so a transfer is not slowed by
+// the size of its reads.
+func NewThrottledReader(r io.Reader, bytesPerSecond int64) io.Reader {
+	if bytesPerSecond <= 0 {
+		return r
+	}
+	return &throttledReader{
+		reader:  r,
+		limiter: rate.NewLimiter(rate.Limit(bytesPerSecond), int(bytesPerSecond)),
+	}
+}
+
+func (t *throttledReader) Read(p []byte) (int, error) {
+	if len(p) > t.limiter.Burst() {
+		p = p[:t.limiter.Burst()]
+	}
+	n, err := t.reader.Read(p)
+	if n > 0 {
+		if waitErr := t.limiter.WaitN(context.Background(), n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
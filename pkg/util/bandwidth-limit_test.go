@@ -0,0 +1,48 @@
+package util
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bandwidth limit", func() {
+	table.DescribeTable("should parse", func(value string, expected int64, valid bool) {
+		limit, err := ParseBandwidthLimit(value)
+		if !valid {
+			Expect(err).To(HaveOccurred())
+			return
+		}
+		Expect(err).ToNot(HaveOccurred())
+		Expect(limit).To(Equal(expected))
+	},
+		table.Entry("no limit when empty", "", int64(0), true),
+		table.Entry("no limit when 0", "0", int64(0), true),
+		table.Entry("a binary quantity", "100Mi", int64(100*1024*1024), true),
+		table.Entry("a decimal quantity", "1G", int64(1000*1000*1000), true),
+		table.Entry("the minimal limit", "64Ki", int64(MinBandwidthLimit), true),
+		table.Entry("not a limit below 64Ki", "1Ki", int64(0), false),
+		table.Entry("not a negative limit", "-1Mi", int64(0), false),
+		table.Entry("not an invalid quantity", "fast", int64(0), false),
+	)
+
+	It("should not wrap the reader without a limit", func() {
+		reader := bytes.NewReader([]byte("data"))
+		Expect(NewThrottledReader(reader, 0)).To(BeIdenticalTo(reader))
+	})
+
+	It("should read at the limit", func() {
+		const limit = 1024 * 1024
+		data := bytes.Repeat([]byte{0x55}, limit*3/2)
+		start := time.Now()
+		read, err := io.ReadAll(NewThrottledReader(bytes.NewReader(data), limit))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(read).To(Equal(data))
+		// The first second of reads is the burst, the remaining half second is throttled
+		Expect(time.Since(start)).To(BeNumerically(">=", 400*time.Millisecond))
+	})
+})
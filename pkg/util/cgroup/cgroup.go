@@ -0,0 +1,229 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package cgroup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+const (
+	// MinIOWeight and MaxIOWeight bound the cgroup v2 io.weight, the weight of PodIOLimits
+	MinIOWeight = 1
+	MaxIOWeight = 10000
+	// minBlkioWeight and maxBlkioWeight bound the cgroup v1 blkio.weight
+	minBlkioWeight = 10
+	maxBlkioWeight = 1000
+)
+
+// BlockIOClassAnnotation is the pod annotation the container runtime reads the blockio class of the pod from
+const BlockIOClassAnnotation = "blockio.resources.beta.kubernetes.io/pod"
+
+// cgroupRoot is where the cgroup of the container is mounted, overridden in tests
+var cgroupRoot = "/sys/fs/cgroup"
+
+// IOLimits are the disk IO limits of the pod, a zero value means unlimited
+type IOLimits struct {
+	BytesPerSecond int64
+	IOPS           int64
+	Weight         int64
+}
+
+// IsEmpty returns true if no limit is set
+func (l IOLimits) IsEmpty() bool {
+	return l.BytesPerSecond == 0 && l.IOPS == 0 && l.Weight == 0
+}
+
+// PodEnv returns the env variables passing the IO limits to a CDI worker pod
+func PodEnv(limits *cdiv1.PodIOLimits) []corev1.EnvVar {
+	if limits == nil {
+		return nil
+	}
+	var env []corev1.EnvVar
+	if limits.MaxBytesPerSecond != nil && limits.MaxBytesPerSecond.Value() > 0 {
+		env = append(env, corev1.EnvVar{Name: common.IOMaxBytesPerSecond, Value: strconv.FormatInt(limits.MaxBytesPerSecond.Value(), 10)})
+	}
+	if limits.MaxIOPS != nil && *limits.MaxIOPS > 0 {
+		env = append(env, corev1.EnvVar{Name: common.IOMaxIOPS, Value: strconv.FormatInt(*limits.MaxIOPS, 10)})
+	}
+	if limits.Weight != nil && *limits.Weight > 0 {
+		env = append(env, corev1.EnvVar{Name: common.IOWeight, Value: strconv.FormatInt(int64(*limits.Weight), 10)})
+	}
+	return env
+}
+
+// SetPodBlockIOClass sets the blockio class of the IO limits on the pod. The container runtime applies the class when it
+// creates the pod, which doesn't need the pod to write its cgroup.
+func SetPodBlockIOClass(pod *corev1.Pod, limits *cdiv1.PodIOLimits) {
+	if limits == nil || limits.BlockIOClass == nil || *limits.BlockIOClass == "" {
+		return
+	}
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[BlockIOClassAnnotation] = *limits.BlockIOClass
+}
+
+// IOLimitsFromEnv reads the IO limits passed to the pod by PodEnv
+func IOLimitsFromEnv() (IOLimits, error) {
+	limits := IOLimits{}
+	for name, value := range map[string]*int64{
+		common.IOMaxBytesPerSecond: &limits.BytesPerSecond,
+		common.IOMaxIOPS:           &limits.IOPS,
+		common.IOWeight:            &limits.Weight,
+	} {
+		env := os.Getenv(name)
+		if env == "" {
+			continue
+		}
+		val, err := strconv.ParseInt(env, 10, 64)
+		if err != nil || val < 0 {
+			return IOLimits{}, errors.Errorf("invalid %s %q", name, env)
+		}
+		*value = val
+	}
+	if limits.Weight != 0 && (limits.Weight < MinIOWeight || limits.Weight > MaxIOWeight) {
+		return IOLimits{}, errors.Errorf("invalid %s %d, must be between %d and %d", common.IOWeight, limits.Weight, MinIOWeight, MaxIOWeight)
+	}
+	return limits, nil
+}
+
+// ApplyIOLimitsFromEnv applies the IO limits passed to the pod on the device backing path. Most container runtimes
+// mount the cgroupfs read-only in unprivileged pods, the limits are then only applied by the runtime through the
+// blockio class of the pod, and a failure is only logged.
+func ApplyIOLimitsFromEnv(path string) {
+	limits, err := IOLimitsFromEnv()
+	if err != nil {
+		klog.Errorf("Ignoring the disk IO limits: %v", err)
+		return
+	}
+	if limits.IsEmpty() {
+		return
+	}
+	if err := ApplyIOLimits(path, limits); err != nil {
+		if errors.Is(err, unix.EROFS) || errors.Is(err, unix.EACCES) || errors.Is(err, unix.EPERM) {
+			klog.Warningf("Unable to apply the disk IO limits, the cgroup of the pod is read-only, set the blockIOClass of the podIOLimits instead: %v", err)
+			return
+		}
+		klog.Warningf("Unable to apply the disk IO limits, the container runtime may not support them: %v", err)
+		return
+	}
+	klog.Infof("Applied the disk IO limits %+v on %s", limits, path)
+}
+
+// ApplyIOLimits sets the IO limits of the cgroup of the container on the device backing path, a block device or a
+// file of a filesystem
+func ApplyIOLimits(path string, limits IOLimits) error {
+	device, err := deviceNumber(path)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err == nil {
+		return applyV2(device, limits)
+	}
+	return applyV1(device, limits)
+}
+
+// deviceNumber returns the MAJ:MIN of the device backing path
+func deviceNumber(path string) (string, error) {
+	var stat unix.Stat_t
+	if err := unix.Stat(path, &stat); err != nil {
+		return "", errors.Wrapf(err, "could not stat %s", path)
+	}
+	dev := stat.Dev
+	if stat.Mode&unix.S_IFMT == unix.S_IFBLK {
+		dev = stat.Rdev
+	}
+	return fmt.Sprintf("%d:%d", unix.Major(uint64(dev)), unix.Minor(uint64(dev))), nil
+}
+
+func applyV2(device string, limits IOLimits) error {
+	var max []string
+	if limits.BytesPerSecond > 0 {
+		max = append(max, fmt.Sprintf("rbps=%d", limits.BytesPerSecond), fmt.Sprintf("wbps=%d", limits.BytesPerSecond))
+	}
+	if limits.IOPS > 0 {
+		max = append(max, fmt.Sprintf("riops=%d", limits.IOPS), fmt.Sprintf("wiops=%d", limits.IOPS))
+	}
+	if len(max) > 0 {
+		if err := writeCgroupFile("io.max", device+" "+strings.Join(max, " ")); err != nil {
+			return err
+		}
+	}
+	if limits.Weight > 0 {
+		if err := writeCgroupFile("io.weight", fmt.Sprintf("default %d", limits.Weight)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyV1(device string, limits IOLimits) error {
+	files := map[string]int64{
+		"blkio.throttle.read_bps_device":   limits.BytesPerSecond,
+		"blkio.throttle.write_bps_device":  limits.BytesPerSecond,
+		"blkio.throttle.read_iops_device":  limits.IOPS,
+		"blkio.throttle.write_iops_device": limits.IOPS,
+	}
+	for file, limit := range files {
+		if limit == 0 {
+			continue
+		}
+		if err := writeCgroupFile(filepath.Join("blkio", file), fmt.Sprintf("%s %d", device, limit)); err != nil {
+			return err
+		}
+	}
+	if limits.Weight > 0 {
+		if err := writeCgroupFile(filepath.Join("blkio", "blkio.weight"), strconv.FormatInt(blkioWeight(limits.Weight), 10)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blkioWeight scales a cgroup v2 io.weight to the cgroup v1 blkio.weight range, the inverse of the runc conversion
+func blkioWeight(weight int64) int64 {
+	return minBlkioWeight + (weight-MinIOWeight)*(maxBlkioWeight-minBlkioWeight)/(MaxIOWeight-MinIOWeight)
+}
+
+// writeCgroupFile writes an existing cgroup interface file, a missing one means the controller is not available
+func writeCgroupFile(name, value string) error {
+	path := filepath.Join(cgroupRoot, name)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_TRUNC, 0)
+	if err != nil {
+		return errors.Wrapf(err, "could not open %s", path)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(value); err != nil {
+		return errors.Wrapf(err, "could not write %q to %s", value, path)
+	}
+	return nil
+}
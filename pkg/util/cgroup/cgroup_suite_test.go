@@ -0,0 +1,15 @@
+package cgroup
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+
+	"kubevirt.io/containerized-data-importer/tests/reporters"
+)
+
+func TestCgroup(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecsWithDefaultAndCustomReporters(t, "Cgroup Test Suite", reporters.NewReporters())
+}
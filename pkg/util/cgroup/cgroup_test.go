@@ -0,0 +1,144 @@
+package cgroup
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+var _ = Describe("IO limits", func() {
+	var (
+		tmpDir         string
+		dataFile       string
+		device         string
+		origCgroupRoot string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "cgroup")
+		Expect(err).ToNot(HaveOccurred())
+		origCgroupRoot = cgroupRoot
+		cgroupRoot = filepath.Join(tmpDir, "cgroup")
+		Expect(os.MkdirAll(filepath.Join(cgroupRoot, "blkio"), 0755)).To(Succeed())
+		dataFile = filepath.Join(tmpDir, "disk.img")
+		Expect(os.WriteFile(dataFile, []byte{}, 0644)).To(Succeed())
+		device, err = deviceNumber(dataFile)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		cgroupRoot = origCgroupRoot
+		os.RemoveAll(tmpDir)
+	})
+
+	createCgroupFiles := func(names ...string) {
+		for _, name := range names {
+			Expect(os.WriteFile(filepath.Join(cgroupRoot, name), []byte{}, 0644)).To(Succeed())
+		}
+	}
+
+	readCgroupFile := func(name string) string {
+		content, err := os.ReadFile(filepath.Join(cgroupRoot, name))
+		Expect(err).ToNot(HaveOccurred())
+		return string(content)
+	}
+
+	It("should write the cgroup v2 io.max and io.weight", func() {
+		createCgroupFiles("cgroup.controllers", "io.max", "io.weight")
+		Expect(ApplyIOLimits(dataFile, IOLimits{BytesPerSecond: 1048576, IOPS: 100, Weight: 500})).To(Succeed())
+		Expect(readCgroupFile("io.max")).To(Equal(device + " rbps=1048576 wbps=1048576 riops=100 wiops=100"))
+		Expect(readCgroupFile("io.weight")).To(Equal("default 500"))
+	})
+
+	It("should only write the cgroup v2 limits that are set", func() {
+		createCgroupFiles("cgroup.controllers", "io.max", "io.weight")
+		Expect(ApplyIOLimits(dataFile, IOLimits{IOPS: 100})).To(Succeed())
+		Expect(readCgroupFile("io.max")).To(Equal(device + " riops=100 wiops=100"))
+		Expect(readCgroupFile("io.weight")).To(BeEmpty())
+	})
+
+	It("should write the cgroup v1 blkio files", func() {
+		createCgroupFiles("blkio/blkio.throttle.read_bps_device", "blkio/blkio.throttle.write_bps_device",
+			"blkio/blkio.throttle.read_iops_device", "blkio/blkio.throttle.write_iops_device", "blkio/blkio.weight")
+		Expect(ApplyIOLimits(dataFile, IOLimits{BytesPerSecond: 1048576, IOPS: 100, Weight: 10000})).To(Succeed())
+		Expect(readCgroupFile("blkio/blkio.throttle.read_bps_device")).To(Equal(device + " 1048576"))
+		Expect(readCgroupFile("blkio/blkio.throttle.write_bps_device")).To(Equal(device + " 1048576"))
+		Expect(readCgroupFile("blkio/blkio.throttle.read_iops_device")).To(Equal(device + " 100"))
+		Expect(readCgroupFile("blkio/blkio.throttle.write_iops_device")).To(Equal(device + " 100"))
+		Expect(readCgroupFile("blkio/blkio.weight")).To(Equal("1000"))
+	})
+
+	It("should fail if the io controller is not available", func() {
+		createCgroupFiles("cgroup.controllers")
+		err := ApplyIOLimits(dataFile, IOLimits{IOPS: 100})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("io.max"))
+	})
+
+	table.DescribeTable("should scale the io.weight to the blkio.weight", func(weight, expected int64) {
+		Expect(blkioWeight(weight)).To(Equal(expected))
+	},
+		table.Entry("minimum", int64(1), int64(10)),
+		table.Entry("default", int64(100), int64(19)),
+		table.Entry("maximum", int64(10000), int64(1000)),
+	)
+
+	Context("passed through the env", func() {
+		AfterEach(func() {
+			os.Unsetenv(common.IOMaxBytesPerSecond)
+			os.Unsetenv(common.IOMaxIOPS)
+			os.Unsetenv(common.IOWeight)
+		})
+
+		It("should read the limits set by PodEnv", func() {
+			bps := resource.MustParse("10Mi")
+			iops := int64(200)
+			weight := int32(50)
+			env := PodEnv(&cdiv1.PodIOLimits{MaxBytesPerSecond: &bps, MaxIOPS: &iops, Weight: &weight})
+			Expect(env).To(HaveLen(3))
+			for _, e := range env {
+				os.Setenv(e.Name, e.Value)
+			}
+			limits, err := IOLimitsFromEnv()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(limits).To(Equal(IOLimits{BytesPerSecond: 10 * 1024 * 1024, IOPS: 200, Weight: 50}))
+		})
+
+		It("should not pass unset limits", func() {
+			Expect(PodEnv(nil)).To(BeEmpty())
+			Expect(PodEnv(&cdiv1.PodIOLimits{})).To(BeEmpty())
+			limits, err := IOLimitsFromEnv()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(limits.IsEmpty()).To(BeTrue())
+		})
+
+		It("should set the blockio class on the pod", func() {
+			pod := &corev1.Pod{}
+			SetPodBlockIOClass(pod, nil)
+			Expect(pod.Annotations).To(BeEmpty())
+			class := "throttled"
+			SetPodBlockIOClass(pod, &cdiv1.PodIOLimits{BlockIOClass: &class})
+			Expect(pod.Annotations).To(HaveKeyWithValue(BlockIOClassAnnotation, "throttled"))
+		})
+
+		table.DescribeTable("should reject", func(name, value string) {
+			os.Setenv(name, value)
+			_, err := IOLimitsFromEnv()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(name))
+		},
+			table.Entry("a non numeric limit", common.IOMaxIOPS, "fast"),
+			table.Entry("a negative limit", common.IOMaxBytesPerSecond, "-1"),
+			table.Entry("an out of range weight", common.IOWeight, "20000"),
+		)
+	})
+})
@@ -0,0 +1,84 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// CloneBlockSize is the size of the blocks an incremental clone compares between its source and target
+const CloneBlockSize = 1024 * 1024
+
+// CloneBlockChecksums are the SHA-256 of the blocks of the first Size bytes of a disk, compared by an incremental clone
+// to only stream the blocks of the source that changed since the target was cloned
+type CloneBlockChecksums struct {
+	Size      int64    `json:"size"`
+	BlockSize int64    `json:"blockSize"`
+	Checksums []string `json:"checksums"`
+}
+
+// ComputeCloneBlockChecksums computes the SHA-256 of the blocks of the first size bytes read from r, the last block
+// being shorter when size is not a multiple of blockSize
+func ComputeCloneBlockChecksums(r io.Reader, size, blockSize int64) (*CloneBlockChecksums, error) {
+	if size < 0 || blockSize <= 0 {
+		return nil, errors.Errorf("invalid clone blocks of %d bytes for a disk of %d bytes", blockSize, size)
+	}
+	checksums := &CloneBlockChecksums{Size: size, BlockSize: blockSize}
+	digest := sha256.New()
+	for offset := int64(0); offset < size; offset += blockSize {
+		length := blockSize
+		if offset+length > size {
+			length = size - offset
+		}
+		digest.Reset()
+		if _, err := io.CopyN(digest, r, length); err != nil {
+			return nil, errors.Wrapf(err, "unable to read the block at offset %d", offset)
+		}
+		checksums.Checksums = append(checksums.Checksums, hex.EncodeToString(digest.Sum(nil)))
+	}
+	return checksums, nil
+}
+
+// ChangedRanges returns the ranges of the disk whose blocks differ from the ones of target, adjacent changed blocks
+// being merged into a single range
+func (c *CloneBlockChecksums) ChangedRanges(target *CloneBlockChecksums) ([]CloneRange, error) {
+	if target.Size != c.Size || target.BlockSize != c.BlockSize || len(target.Checksums) != len(c.Checksums) {
+		return nil, errors.Errorf("the target has %d blocks of %d bytes for %d bytes, the source %d blocks of %d bytes for %d bytes",
+			len(target.Checksums), target.BlockSize, target.Size, len(c.Checksums), c.BlockSize, c.Size)
+	}
+	var ranges []CloneRange
+	for i, checksum := range c.Checksums {
+		if checksum == target.Checksums[i] {
+			continue
+		}
+		start := int64(i) * c.BlockSize
+		end := start + c.BlockSize
+		if end > c.Size {
+			end = c.Size
+		}
+		if last := len(ranges) - 1; last >= 0 && ranges[last].End == start {
+			ranges[last].End = end
+			continue
+		}
+		ranges = append(ranges, CloneRange{Start: start, End: end, Size: c.Size})
+	}
+	return ranges, nil
+}
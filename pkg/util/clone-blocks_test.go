@@ -0,0 +1,54 @@
+package util
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Clone block checksums", func() {
+	const blockSize = 4
+
+	checksums := func(data []byte) *CloneBlockChecksums {
+		c, err := ComputeCloneBlockChecksums(bytes.NewReader(data), int64(len(data)), blockSize)
+		Expect(err).ToNot(HaveOccurred())
+		return c
+	}
+
+	It("should compute the checksum of each block, the last one shorter", func() {
+		c := checksums([]byte("aaaabbbbcc"))
+		Expect(c.Size).To(Equal(int64(10)))
+		Expect(c.BlockSize).To(Equal(int64(blockSize)))
+		Expect(c.Checksums).To(HaveLen(3))
+		Expect(c.Checksums[0]).ToNot(Equal(c.Checksums[1]))
+		Expect(checksums([]byte("aaaa")).Checksums[0]).To(Equal(c.Checksums[0]))
+	})
+
+	It("should fail on a disk shorter than its size", func() {
+		_, err := ComputeCloneBlockChecksums(bytes.NewReader([]byte("aaaa")), 8, blockSize)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should merge the adjacent changed blocks", func() {
+		source := checksums([]byte("aaaaXXXXYYYYddddZZ"))
+		target := checksums([]byte("aaaabbbbccccddddee"))
+		ranges, err := source.ChangedRanges(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ranges).To(Equal([]CloneRange{
+			{Start: 4, End: 12, Size: 18},
+			{Start: 16, End: 18, Size: 18},
+		}))
+	})
+
+	It("should find no changed range between identical disks", func() {
+		ranges, err := checksums([]byte("aaaabbbb")).ChangedRanges(checksums([]byte("aaaabbbb")))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ranges).To(BeEmpty())
+	})
+
+	It("should not compare disks of different sizes", func() {
+		_, err := checksums([]byte("aaaabbbb")).ChangedRanges(checksums([]byte("aaaabbbbcc")))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,93 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"compress/gzip"
+	"io"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+// CloneCompressionCodecs are the compression codecs of the host-assisted clone streams
+var CloneCompressionCodecs = []string{
+	common.CloneCompressionNone,
+	common.CloneCompressionGzip,
+	common.CloneCompressionSnappy,
+	common.CloneCompressionZstd,
+}
+
+// ValidateCloneCompression checks codec is a compression codec of the clone streams
+func ValidateCloneCompression(codec string) error {
+	for _, c := range CloneCompressionCodecs {
+		if codec == c {
+			return nil
+		}
+	}
+	return errors.Errorf("invalid clone compression %q, must be one of none, gzip, snappy or zstd", codec)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// NewCloneCompressionWriter returns a writer compressing the clone stream written to w with codec. Closing it flushes
+// the compressed stream, but does not close w.
+func NewCloneCompressionWriter(w io.Writer, codec string) (io.WriteCloser, error) {
+	switch codec {
+	case common.CloneCompressionNone:
+		return nopWriteCloser{w}, nil
+	case common.CloneCompressionGzip:
+		return gzip.NewWriter(w), nil
+	case common.CloneCompressionSnappy:
+		return snappy.NewBufferedWriter(w), nil
+	case common.CloneCompressionZstd:
+		return zstd.NewWriter(w)
+	}
+	return nil, ValidateCloneCompression(codec)
+}
+
+// NewCloneDecompressionReader returns a reader decompressing the clone stream read from r with codec
+func NewCloneDecompressionReader(r io.Reader, codec string) (io.ReadCloser, error) {
+	switch codec {
+	case common.CloneCompressionNone:
+		return io.NopCloser(r), nil
+	case common.CloneCompressionGzip:
+		gr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read the gzip clone stream")
+		}
+		return gr, nil
+	case common.CloneCompressionSnappy:
+		return io.NopCloser(snappy.NewReader(r)), nil
+	case common.CloneCompressionZstd:
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read the zstd clone stream")
+		}
+		return zr.IOReadCloser(), nil
+	}
+	return nil, ValidateCloneCompression(codec)
+}
@@ -0,0 +1,58 @@
+package util
+
+import (
+	"bytes"
+	"io"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+var _ = Describe("Clone compression", func() {
+	table.DescribeTable("should round trip the clone stream", func(codec string) {
+		data := bytes.Repeat([]byte("clone stream "), 64*1024)
+		var compressed bytes.Buffer
+		w, err := NewCloneCompressionWriter(&compressed, codec)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = w.Write(data)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(w.Close()).To(Succeed())
+		if codec != common.CloneCompressionNone {
+			Expect(compressed.Len()).To(BeNumerically("<", len(data)))
+		}
+
+		r, err := NewCloneDecompressionReader(&compressed, codec)
+		Expect(err).ToNot(HaveOccurred())
+		decompressed, err := io.ReadAll(r)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(r.Close()).To(Succeed())
+		Expect(decompressed).To(Equal(data))
+	},
+		table.Entry("uncompressed", common.CloneCompressionNone),
+		table.Entry("with gzip", common.CloneCompressionGzip),
+		table.Entry("with snappy", common.CloneCompressionSnappy),
+		table.Entry("with zstd", common.CloneCompressionZstd),
+	)
+
+	It("should reject an unknown codec", func() {
+		Expect(ValidateCloneCompression("lz4")).To(HaveOccurred())
+		_, err := NewCloneCompressionWriter(io.Discard, "lz4")
+		Expect(err).To(HaveOccurred())
+		_, err = NewCloneDecompressionReader(bytes.NewReader(nil), "lz4")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail on a stream of another codec", func() {
+		var compressed bytes.Buffer
+		w, err := NewCloneCompressionWriter(&compressed, common.CloneCompressionSnappy)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = w.Write([]byte("clone stream"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(w.Close()).To(Succeed())
+		_, err = NewCloneDecompressionReader(&compressed, common.CloneCompressionGzip)
+		Expect(err).To(HaveOccurred())
+	})
+})
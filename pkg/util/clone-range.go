@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// cloneRangeAlignment is the alignment of the ranges a disk is split into for a parallel clone
+const cloneRangeAlignment = 1024 * 1024
+
+// CloneRange is the range of a disk of Size bytes held by one of the parallel streams of a clone, from Start to End
+// excluded
+type CloneRange struct {
+	Start int64
+	End   int64
+	Size  int64
+}
+
+// String formats the range as the value of the clone range header
+func (r CloneRange) String() string {
+	return fmt.Sprintf("%d-%d/%d", r.Start, r.End, r.Size)
+}
+
+// Length returns the number of bytes of the range
+func (r CloneRange) Length() int64 {
+	return r.End - r.Start
+}
+
+// ParseCloneRange parses the value of the clone range header, "<start>-<end>/<size>"
+func ParseCloneRange(value string) (CloneRange, error) {
+	r := CloneRange{}
+	if _, err := fmt.Sscanf(value, "%d-%d/%d", &r.Start, &r.End, &r.Size); err != nil {
+		return r, errors.Wrapf(err, "invalid clone range %q", value)
+	}
+	if r.Start < 0 || r.End <= r.Start || r.End > r.Size || r.String() != value {
+		return r, errors.Errorf("invalid clone range %q", value)
+	}
+	return r, nil
+}
+
+// SplitCloneRanges splits a disk of size bytes into at most streams contiguous ranges of about the same length,
+// aligned to 1MiB
+func SplitCloneRanges(size int64, streams int) []CloneRange {
+	if streams < 1 {
+		streams = 1
+	}
+	length := (size + int64(streams) - 1) / int64(streams)
+	length = (length + cloneRangeAlignment - 1) / cloneRangeAlignment * cloneRangeAlignment
+	var ranges []CloneRange
+	for start := int64(0); start < size; start += length {
+		end := start + length
+		if end > size {
+			end = size
+		}
+		ranges = append(ranges, CloneRange{Start: start, End: end, Size: size})
+	}
+	return ranges
+}
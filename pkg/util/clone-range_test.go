@@ -0,0 +1,46 @@
+package util
+
+import (
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Clone range", func() {
+	const mi = 1024 * 1024
+
+	table.DescribeTable("should split a disk", func(size int64, streams int, expected []CloneRange) {
+		Expect(SplitCloneRanges(size, streams)).To(Equal(expected))
+	},
+		table.Entry("in a single range for a single stream", int64(10*mi), 1, []CloneRange{{Start: 0, End: 10 * mi, Size: 10 * mi}}),
+		table.Entry("in aligned ranges", int64(10*mi), 4, []CloneRange{
+			{Start: 0, End: 3 * mi, Size: 10 * mi},
+			{Start: 3 * mi, End: 6 * mi, Size: 10 * mi},
+			{Start: 6 * mi, End: 9 * mi, Size: 10 * mi},
+			{Start: 9 * mi, End: 10 * mi, Size: 10 * mi},
+		}),
+		table.Entry("in fewer ranges than streams when small", int64(mi+1), 4, []CloneRange{
+			{Start: 0, End: mi, Size: mi + 1},
+			{Start: mi, End: mi + 1, Size: mi + 1},
+		}),
+		table.Entry("in no range when empty", int64(0), 4, nil),
+	)
+
+	table.DescribeTable("should parse", func(value string, expected CloneRange, valid bool) {
+		r, err := ParseCloneRange(value)
+		if !valid {
+			Expect(err).To(HaveOccurred())
+			return
+		}
+		Expect(err).ToNot(HaveOccurred())
+		Expect(r).To(Equal(expected))
+		Expect(r.String()).To(Equal(value))
+	},
+		table.Entry("a range", "1048576-2097152/4194304", CloneRange{Start: mi, End: 2 * mi, Size: 4 * mi}, true),
+		table.Entry("not an empty range", "10-10/20", CloneRange{}, false),
+		table.Entry("not a range past the size", "10-30/20", CloneRange{}, false),
+		table.Entry("not a negative range", "-10-10/20", CloneRange{}, false),
+		table.Entry("not trailing data", "0-10/20x", CloneRange{}, false),
+		table.Entry("not garbage", "bytes", CloneRange{}, false),
+	)
+})
@@ -0,0 +1,125 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+const (
+	// FormatText is the default klog text format
+	FormatText = "text"
+	// FormatJSON emits every log line as a JSON object
+	FormatJSON = "json"
+
+	// FieldNamespace is the structured log field of the namespace
+	FieldNamespace = "namespace"
+	// FieldDataVolume is the structured log field of the DataVolume name
+	FieldDataVolume = "dv"
+	// FieldPhase is the structured log field of the DataVolume phase
+	FieldPhase = "phase"
+	// FieldBytes is the structured log field of the number of bytes transferred
+	FieldBytes = "bytes"
+	// FieldError is the structured log field of the error
+	FieldError = "error"
+	// FieldEvent is the structured log field of the lifecycle event
+	FieldEvent = "event"
+
+	// EventStart is the lifecycle event of a starting transfer
+	EventStart = "start"
+	// EventProgress is the lifecycle event of a progressing transfer
+	EventProgress = "progress"
+	// EventComplete is the lifecycle event of a completed transfer
+	EventComplete = "complete"
+	// EventFail is the lifecycle event of a failed transfer
+	EventFail = "fail"
+)
+
+// GetFormat returns the log format set in the environment, text by default
+func GetFormat() string {
+	if os.Getenv(common.LogFormat) == FormatJSON {
+		return FormatJSON
+	}
+	return FormatText
+}
+
+// SetupFromEnv configures klog with the log format set in the environment. With the JSON format, the namespace and
+// DataVolume set in the environment are added to every log line.
+func SetupFromEnv() {
+	if GetFormat() != FormatJSON {
+		return
+	}
+	var keysAndValues []interface{}
+	if namespace := os.Getenv(common.LogNamespace); namespace != "" {
+		keysAndValues = append(keysAndValues, FieldNamespace, namespace)
+	}
+	if dv := os.Getenv(common.LogDataVolume); dv != "" {
+		keysAndValues = append(keysAndValues, FieldDataVolume, dv)
+	}
+	klog.SetLogger(NewJSONLogger(os.Stderr).WithValues(keysAndValues...))
+}
+
+// NewJSONLogger returns a logger writing every log line as a JSON object to w. It logs all verbosity levels, the
+// verbosity is left to the caller, like klog.
+func NewJSONLogger(w io.Writer) logr.Logger {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.TimeKey = "ts"
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.AddSync(w), zap.NewAtomicLevelAt(zapcore.Level(-127)))
+	return zapr.NewLogger(zap.New(core, zap.AddCaller()))
+}
+
+// PodEnv returns the environment variables passing the log format of the controller to the pods it creates for the
+// DataVolume. Nothing is passed with the default text format, so the pods keep their default.
+func PodEnv(namespace, dvName string) []corev1.EnvVar {
+	if GetFormat() != FormatJSON {
+		return nil
+	}
+	return []corev1.EnvVar{
+		{Name: common.LogFormat, Value: FormatJSON},
+		{Name: common.LogNamespace, Value: namespace},
+		{Name: common.LogDataVolume, Value: dvName},
+	}
+}
+
+// Lifecycle logs a lifecycle event of a transfer, with the consistent structured fields
+func Lifecycle(event string, keysAndValues ...interface{}) {
+	klog.InfoSDepth(1, fmt.Sprintf("Transfer %s", event), append([]interface{}{FieldEvent, event}, keysAndValues...)...)
+}
+
+// LifecycleError logs the fail lifecycle event of a transfer. The stack trace of the error is kept, as the
+// errorVerbose field with the JSON format, and on a line of its own with the text format.
+func LifecycleError(err error, keysAndValues ...interface{}) {
+	if GetFormat() != FormatJSON {
+		klog.ErrorDepth(1, fmt.Sprintf("%+v", err))
+	}
+	klog.ErrorSDepth(1, err, fmt.Sprintf("Transfer %s", EventFail), append([]interface{}{FieldEvent, EventFail}, keysAndValues...)...)
+}
@@ -0,0 +1,70 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+var _ = Describe("JSON log format", func() {
+	var buf *bytes.Buffer
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		os.Setenv(common.LogFormat, FormatJSON)
+		klog.SetLogger(NewJSONLogger(buf))
+	})
+
+	AfterEach(func() {
+		klog.ClearLogger()
+		os.Unsetenv(common.LogFormat)
+	})
+
+	decodeLine := func() map[string]interface{} {
+		line := map[string]interface{}{}
+		Expect(json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &line)).To(Succeed())
+		return line
+	}
+
+	It("should write a lifecycle event as a JSON object with the structured fields", func() {
+		Lifecycle(EventComplete, FieldNamespace, "ns", FieldDataVolume, "dv", FieldPhase, "Succeeded", FieldBytes, 1024)
+		line := decodeLine()
+		Expect(line).To(HaveKeyWithValue("msg", "Transfer complete"))
+		Expect(line).To(HaveKeyWithValue(FieldEvent, EventComplete))
+		Expect(line).To(HaveKeyWithValue(FieldNamespace, "ns"))
+		Expect(line).To(HaveKeyWithValue(FieldDataVolume, "dv"))
+		Expect(line).To(HaveKeyWithValue(FieldPhase, "Succeeded"))
+		Expect(line).To(HaveKeyWithValue(FieldBytes, float64(1024)))
+		Expect(line).To(HaveKey("ts"))
+	})
+
+	It("should write the error of a failed transfer", func() {
+		LifecycleError(errors.New("boom"), FieldNamespace, "ns", FieldDataVolume, "dv")
+		line := decodeLine()
+		Expect(line).To(HaveKeyWithValue(FieldEvent, EventFail))
+		Expect(line).To(HaveKeyWithValue(FieldError, "boom"))
+		Expect(line).To(HaveKeyWithValue(FieldNamespace, "ns"))
+		Expect(line).To(HaveKeyWithValue("errorVerbose", ContainSubstring("logging_test.go")))
+	})
+
+	It("should pass the JSON format to the pods only if enabled", func() {
+		os.Unsetenv(common.LogFormat)
+		Expect(GetFormat()).To(Equal(FormatText))
+		Expect(PodEnv("ns", "dv")).To(BeEmpty())
+		os.Setenv(common.LogFormat, FormatJSON)
+		Expect(GetFormat()).To(Equal(FormatJSON))
+		env := PodEnv("ns", "dv")
+		Expect(env).To(HaveLen(3))
+		Expect(env[0].Name).To(Equal(common.LogFormat))
+		Expect(env[0].Value).To(Equal(FormatJSON))
+		Expect(env[1].Value).To(Equal("ns"))
+		Expect(env[2].Value).To(Equal("dv"))
+	})
+})
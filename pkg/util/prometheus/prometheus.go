@@ -38,6 +38,7 @@ func NewProgressReader(r io.ReadCloser, total uint64, progress *prometheus.Count
 		ownerUID: ownerUID,
 		final:    true,
 	}
+	trackProgress(promReader)
 
 	return promReader
 }
@@ -57,13 +58,19 @@ func (r *ProgressReader) timedUpdateProgress() {
 	}
 }
 
+// currentProgress returns the percentage of the total read, and whether the final reader is done
+func (r *ProgressReader) currentProgress() (float64, bool) {
+	finished := r.final && r.Done
+	currentProgress := 100.0
+	if current := r.BytesRead(); !finished && current < r.total {
+		currentProgress = float64(current) / float64(r.total) * 100.0
+	}
+	return currentProgress, finished
+}
+
 func (r *ProgressReader) updateProgress() bool {
 	if r.total > 0 {
-		finished := r.final && r.Done
-		currentProgress := 100.0
-		if !finished && r.Current < r.total {
-			currentProgress = float64(r.Current) / float64(r.total) * 100.0
-		}
+		currentProgress, finished := r.currentProgress()
 		metric := &dto.Metric{}
 		r.progress.WithLabelValues(r.ownerUID).Write(metric)
 		if currentProgress > *metric.Counter.Value {
@@ -78,17 +85,14 @@ func (r *ProgressReader) updateProgress() bool {
 // SetNextReader replaces the current counting reader with a new one,
 // for tracking progress over multiple readers.
 func (r *ProgressReader) SetNextReader(reader io.ReadCloser, final bool) {
-	r.CountingReader = util.CountingReader{
-		Reader:  reader,
-		Current: r.Current,
-		Done:    false,
-	}
+	r.Reader = reader
+	r.Done = false
 	r.final = final
 }
 
-// StartPrometheusEndpoint starts an http server providing a prometheus endpoint using the passed
-// in directory to store the self signed certificates that will be generated before starting the
-// http server.
+// StartPrometheusEndpoint starts an http server providing a prometheus endpoint, and an http server providing the status
+// endpoint of the transfer on the loopback interface, using the passed in directory to store the self signed
+// certificates that will be generated before starting the http servers.
 func StartPrometheusEndpoint(certsDirectory string) {
 	certBytes, keyBytes, err := cert.GenerateSelfSignedCertKey("cloner_target", nil, nil)
 	if err != nil {
@@ -114,4 +118,12 @@ func StartPrometheusEndpoint(certsDirectory string) {
 			return
 		}
 	}()
+	go func() {
+		// The status is only reachable from the pod itself, or through a port forward authorized by the API server
+		mux := http.NewServeMux()
+		mux.HandleFunc(StatusPath, StatusHandler)
+		if err := http.ListenAndServeTLS(StatusAddress, certFile, keyFile, mux); err != nil {
+			klog.Errorf("Error serving the transfer status: %v", err)
+		}
+	}()
 }
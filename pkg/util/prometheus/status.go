@@ -0,0 +1,127 @@
+package prometheus
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"sync"
+
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+const (
+	// StatusPath is the path of the endpoint serving the live state of the transfer of a worker pod
+	StatusPath = "/status"
+	// StatusAddress is the loopback address the importer and clone source pods serve the status endpoint on
+	StatusAddress = "127.0.0.1:8444"
+)
+
+// TransferStatus is the live state of the transfer of a worker pod, served as JSON
+type TransferStatus struct {
+	// Phase is the current phase of the transfer
+	Phase string `json:"phase,omitempty"`
+	// BytesTransferred is the number of bytes read from the source so far
+	BytesTransferred uint64 `json:"bytesTransferred"`
+	// TotalBytes is the expected number of bytes of the source, 0 when it is not known
+	TotalBytes uint64 `json:"totalBytes,omitempty"`
+	// Progress is the percentage of the source read so far, when its size is known
+	Progress float64 `json:"progress,omitempty"`
+	// LastError is the last error the transfer ran into
+	LastError string `json:"lastError,omitempty"`
+	// Source is the metadata of the source, such as its type and endpoint
+	Source map[string]string `json:"source,omitempty"`
+}
+
+// statusTracker tracks the state of the transfer, reading the bytes transferred from the current progress reader, or
+// the counting reader of a source of unknown size
+type statusTracker struct {
+	mutex     sync.Mutex
+	phase     string
+	lastError string
+	source    map[string]string
+	reader    *ProgressReader
+	counter   *util.CountingReader
+}
+
+var status = &statusTracker{}
+
+// SetPhase records the current phase of the transfer
+func SetPhase(phase string) {
+	status.mutex.Lock()
+	defer status.mutex.Unlock()
+	status.phase = phase
+}
+
+// SetLastError records the last error the transfer ran into, without the credentials of the URLs it holds
+func SetLastError(err error) {
+	status.mutex.Lock()
+	defer status.mutex.Unlock()
+	status.lastError = util.RedactSourceURLs(err.Error())
+}
+
+// SetSourceMetadata records a metadata of the source, skipped when the value is empty. It must not hold credentials.
+func SetSourceMetadata(key, value string) {
+	if value == "" {
+		return
+	}
+	status.mutex.Lock()
+	defer status.mutex.Unlock()
+	if status.source == nil {
+		status.source = make(map[string]string)
+	}
+	status.source[key] = value
+}
+
+// trackProgress makes the status report the bytes read by the progress reader
+func trackProgress(reader *ProgressReader) {
+	status.mutex.Lock()
+	defer status.mutex.Unlock()
+	status.reader, status.counter = reader, nil
+}
+
+// TrackBytes returns a reader counting the bytes read from a source of unknown size, which has no progress reader,
+// for the status to report them
+func TrackBytes(r io.ReadCloser) io.ReadCloser {
+	counter := &util.CountingReader{Reader: r}
+	status.mutex.Lock()
+	defer status.mutex.Unlock()
+	status.reader, status.counter = nil, counter
+	return counter
+}
+
+// GetStatus returns the current state of the transfer
+func GetStatus() TransferStatus {
+	status.mutex.Lock()
+	defer status.mutex.Unlock()
+	current := TransferStatus{
+		Phase:     status.phase,
+		LastError: status.lastError,
+	}
+	if len(status.source) > 0 {
+		current.Source = make(map[string]string, len(status.source))
+		for key, value := range status.source {
+			current.Source[key] = value
+		}
+	}
+	if status.reader != nil {
+		current.BytesTransferred = status.reader.BytesRead()
+		if total := status.reader.total; total > 0 {
+			current.TotalBytes = total
+			current.Progress = math.Min(float64(current.BytesTransferred)/float64(total)*100.0, 100.0)
+		}
+	} else if status.counter != nil {
+		current.BytesTransferred = status.counter.BytesRead()
+	}
+	return current
+}
+
+// StatusHandler serves the current state of the transfer as JSON
+func StatusHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(GetStatus())
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2023 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// emptyBlockCheckSize is the size of the start of a block device that must be zeroed for the device to be empty
+	emptyBlockCheckSize = 1024 * 1024
+	// targetCheckedFile is the file recording the target was found empty
+	targetCheckedFile = "checked"
+	lostAndFound      = "lost+found"
+)
+
+// ErrTargetNotEmpty is returned when the target volume already contains data
+var ErrTargetNotEmpty = errors.New("the target volume already contains data")
+
+// CheckTargetEmpty fails with ErrTargetNotEmpty when the target volume already contains data: a block device at
+// blockPath whose first MiB is not zeroed, or anything but lost+found in the filesystem at dataDir. A passed check
+// is recorded in checkDir, so a worker restarted after writing to the target does not fail it.
+func CheckTargetEmpty(checkDir, dataDir, blockPath string) error {
+	checkedPath := filepath.Join(checkDir, targetCheckedFile)
+	if _, err := os.Stat(checkedPath); err == nil {
+		return nil
+	}
+	if _, err := os.Stat(blockPath); err == nil {
+		if err := checkBlockEmpty(blockPath); err != nil {
+			return err
+		}
+	} else if err := checkDirEmpty(dataDir); err != nil {
+		return err
+	}
+	klog.V(1).Infoln("The target volume is empty")
+	return os.WriteFile(checkedPath, nil, 0600)
+}
+
+func checkBlockEmpty(blockPath string) error {
+	f, err := os.Open(blockPath)
+	if err != nil {
+		return errors.Wrap(err, "could not open the target device")
+	}
+	defer f.Close()
+	buf := make([]byte, emptyBlockCheckSize)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return errors.Wrap(err, "could not read the target device")
+	}
+	if !bytes.Equal(buf[:n], make([]byte, n)) {
+		return ErrTargetNotEmpty
+	}
+	return nil
+}
+
+func checkDirEmpty(dataDir string) error {
+	entries, err := os.ReadDir(dataDir)
+	if err != nil {
+		return errors.Wrap(err, "could not list the target volume")
+	}
+	for _, entry := range entries {
+		if entry.Name() != lostAndFound {
+			return errors.Wrapf(ErrTargetNotEmpty, "found %s", entry.Name())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Target empty check", func() {
+	var checkDir, dataDir, blockPath string
+
+	BeforeEach(func() {
+		var err error
+		checkDir, err = os.MkdirTemp("", "check")
+		Expect(err).ToNot(HaveOccurred())
+		dataDir, err = os.MkdirTemp("", "data")
+		Expect(err).ToNot(HaveOccurred())
+		blockPath = filepath.Join(dataDir, "no-block-device")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(checkDir)
+		os.RemoveAll(dataDir)
+	})
+
+	It("should accept a filesystem with only lost+found", func() {
+		Expect(os.Mkdir(filepath.Join(dataDir, lostAndFound), 0700)).To(Succeed())
+		Expect(CheckTargetEmpty(checkDir, dataDir, blockPath)).To(Succeed())
+		Expect(filepath.Join(checkDir, targetCheckedFile)).To(BeAnExistingFile())
+	})
+
+	It("should reject a filesystem with a disk image", func() {
+		Expect(os.WriteFile(filepath.Join(dataDir, "disk.img"), []byte("data"), 0600)).To(Succeed())
+		err := CheckTargetEmpty(checkDir, dataDir, blockPath)
+		Expect(errors.Is(err, ErrTargetNotEmpty)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("disk.img"))
+		Expect(filepath.Join(checkDir, targetCheckedFile)).ToNot(BeAnExistingFile())
+	})
+
+	It("should reject a filesystem with files written by a workload", func() {
+		Expect(os.Mkdir(filepath.Join(dataDir, lostAndFound), 0700)).To(Succeed())
+		Expect(os.Mkdir(filepath.Join(dataDir, "db"), 0700)).To(Succeed())
+		Expect(errors.Is(CheckTargetEmpty(checkDir, dataDir, blockPath), ErrTargetNotEmpty)).To(BeTrue())
+	})
+
+	It("should accept a zeroed block device", func() {
+		blockPath = filepath.Join(checkDir, "block")
+		Expect(os.WriteFile(blockPath, make([]byte, 2*emptyBlockCheckSize), 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dataDir, "ignored"), []byte("data"), 0600)).To(Succeed())
+		Expect(CheckTargetEmpty(checkDir, dataDir, blockPath)).To(Succeed())
+	})
+
+	It("should reject a block device with data in the first MiB", func() {
+		blockPath = filepath.Join(dataDir, "block")
+		data := make([]byte, 2*emptyBlockCheckSize)
+		data[emptyBlockCheckSize-1] = 1
+		Expect(os.WriteFile(blockPath, data, 0600)).To(Succeed())
+		Expect(errors.Is(CheckTargetEmpty(checkDir, dataDir, blockPath), ErrTargetNotEmpty)).To(BeTrue())
+	})
+
+	It("should accept a target written after a passed check", func() {
+		Expect(CheckTargetEmpty(checkDir, dataDir, blockPath)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dataDir, "disk.img"), []byte("data"), 0600)).To(Succeed())
+		Expect(CheckTargetEmpty(checkDir, dataDir, blockPath)).To(Succeed())
+	})
+})
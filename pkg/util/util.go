@@ -4,17 +4,21 @@ import (
 	"bufio"
 	"bytes"
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"io"
 	"math"
 	"math/rand"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -48,6 +52,44 @@ type VddkInfo struct {
 	Host    string
 }
 
+// ImageInfo holds the disk image information returned by an importer or clone source pod
+type ImageInfo struct {
+	Format      string `json:",omitempty"`
+	VirtualSize int64  `json:",omitempty"`
+}
+
+// SourceValidators are the ETag and Last-Modified validators an HTTP server returned for the source of an import, used
+// to tell whether the source changed since that import
+type SourceValidators struct {
+	ETag         string `json:",omitempty"`
+	LastModified string `json:",omitempty"`
+}
+
+// SourceDigest is the digest of the source bytes read by an import
+type SourceDigest struct {
+	Algorithm string `json:",omitempty"`
+	Value     string `json:",omitempty"`
+}
+
+// String returns the digest as <algorithm>:<hex>
+func (d SourceDigest) String() string {
+	return d.Algorithm + ":" + d.Value
+}
+
+// TargetImageInfo is the format and compression of the image written to the target of an import, when it is not raw
+type TargetImageInfo struct {
+	Format      string `json:",omitempty"`
+	Compression string `json:",omitempty"`
+}
+
+// CloneCheckpoint is the offset up to which a raw clone stream was durably written to the target, with the SHA-256 of
+// the chunk written since the previous checkpoint, so a resumed clone can check its source and target still match
+type CloneCheckpoint struct {
+	Offset      int64  `json:"offset"`
+	ChunkOffset int64  `json:"chunkOffset"`
+	ChunkSHA256 string `json:"chunkSHA256"`
+}
+
 // RandAlphaNum provides an implementation to generate a random alpha numeric string of the specified length
 func RandAlphaNum(n int) string {
 	rand.Seed(time.Now().UnixNano())
@@ -89,11 +131,21 @@ func ParseEnvVar(envVarName string, decode bool) (string, error) {
 // Read reads bytes from the stream and updates the prometheus clone_progress metric according to the progress.
 func (r *CountingReader) Read(p []byte) (n int, err error) {
 	n, err = r.Reader.Read(p)
-	r.Current += uint64(n)
+	atomic.AddUint64(&r.Current, uint64(n))
 	r.Done = err == io.EOF
 	return n, err
 }
 
+// BytesRead returns the number of bytes counted so far, while another goroutine may be reading the stream
+func (r *CountingReader) BytesRead() uint64 {
+	return atomic.LoadUint64(&r.Current)
+}
+
+// AddBytes counts bytes transferred without reading them from the stream, such as the zeroed ranges of a sparse image
+func (r *CountingReader) AddBytes(n uint64) {
+	atomic.AddUint64(&r.Current, n)
+}
+
 // Close closes the stream
 func (r *CountingReader) Close() error {
 	return r.Reader.Close()
@@ -118,6 +170,21 @@ func GetAvailableSpace(path string) (int64, error) {
 	return int64(stat.Bavail) * int64(stat.Bsize), nil
 }
 
+// GetTotalSpace gets the size of the filesystem at the path specified.
+func GetTotalSpace(path string) (int64, error) {
+	var stat syscall.Statfs_t
+	err := syscall.Statfs(path, &stat)
+	if err != nil {
+		return int64(-1), err
+	}
+	return int64(stat.Blocks) * int64(stat.Bsize), nil
+}
+
+// IsNoSpaceLeft returns true if the error is the ENOSPC of a write to a full filesystem
+func IsNoSpaceLeft(err error) bool {
+	return errors.Is(err, syscall.ENOSPC) || strings.Contains(strings.ToLower(err.Error()), "no space left on device")
+}
+
 // GetAvailableSpaceBlock gets the amount of available space at the block device path specified.
 func GetAvailableSpaceBlock(deviceName string) (int64, error) {
 	// Check if the file exists and is a device file.
@@ -192,7 +259,7 @@ func StreamDataToFile(r io.Reader, fileName string) error {
 	}
 	defer outFile.Close()
 	klog.V(1).Infof("Writing data...\n")
-	if _, err = io.Copy(outFile, r); err != nil {
+	if _, err = AdaptiveCopy(outFile, r, maxCopyBufferSize); err != nil {
 		klog.Errorf("Unable to write file from dataReader: %v\n", err)
 		os.Remove(outFile.Name())
 		return errors.Wrapf(err, "unable to write to file")
@@ -384,6 +451,25 @@ func Md5sum(filePath string) (string, error) {
 	return hex.EncodeToString(hashInBytes), nil
 }
 
+// Sha256sumRange calculates the SHA-256 of length bytes of a file or block device, starting at offset
+func Sha256sumRange(filePath string, offset, length int64) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	n, err := io.Copy(hash, io.NewSectionReader(file, offset, length))
+	if err != nil {
+		return "", err
+	}
+	if n != length {
+		return "", errors.Errorf("%s ends before offset %d", filePath, offset+length)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
 // Three functions for zeroing a range in the destination file:
 
 // PunchHole attempts to zero a range in a file with fallocate, for block devices and pre-allocated files.
@@ -455,6 +541,71 @@ func GetUsableSpace(filesystemOverhead float64, availableSpace int64) int64 {
 	return RoundDown(spaceWithOverhead, DefaultAlignBlockSize)
 }
 
+// FreeSpaceMargin is the space an import keeps free on its target, a size or a percentage of the usable space
+type FreeSpaceMargin struct {
+	// Size is the size of the margin in bytes, when it is not a percentage
+	Size int64
+	// Percent is the percentage of the usable space kept free, when it is not 0
+	Percent float64
+}
+
+// ParseFreeSpaceMargin parses a free-space margin, either a quantity like "512Mi" or a percentage of the usable space
+// below 100 like "5%". An empty value means no margin, returned as nil.
+func ParseFreeSpaceMargin(value string) (*FreeSpaceMargin, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if strings.HasSuffix(value, "%") {
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+		if err != nil || !(percent > 0 && percent < 100) {
+			return nil, errors.Errorf("invalid free-space margin %q, the percentage must be above 0 and below 100", value)
+		}
+		return &FreeSpaceMargin{Percent: percent}, nil
+	}
+	size, err := resource.ParseQuantity(value)
+	if err != nil || size.Sign() <= 0 {
+		return nil, errors.Errorf("invalid free-space margin %q, must be a positive size like 512Mi or a percentage like 5%%", value)
+	}
+	return &FreeSpaceMargin{Size: size.Value()}, nil
+}
+
+// Bytes returns the size of the margin kept free on the usable space
+func (m *FreeSpaceMargin) Bytes(usableSpace int64) int64 {
+	if m == nil {
+		return 0
+	}
+	if m.Percent > 0 {
+		return int64(math.Ceil(m.Percent / 100 * float64(usableSpace)))
+	}
+	return m.Size
+}
+
+func (m *FreeSpaceMargin) String() string {
+	if m.Percent > 0 {
+		return strconv.FormatFloat(m.Percent, 'f', -1, 64) + "%"
+	}
+	return resource.NewQuantity(m.Size, resource.BinarySI).String()
+}
+
+const (
+	minQcow2ClusterSize = 512
+	maxQcow2ClusterSize = 2 * 1024 * 1024
+)
+
+// ParseQcow2ClusterSize parses the cluster size of a qcow2 image, a quantity like "1Mi" that qemu-img accepts: a power
+// of two from 512 bytes to 2Mi
+func ParseQcow2ClusterSize(value string) (int64, error) {
+	size, err := resource.ParseQuantity(value)
+	if err != nil {
+		return 0, errors.Errorf("invalid qcow2 cluster size %q, must be a size like 1Mi", value)
+	}
+	clusterSize := size.Value()
+	if clusterSize < minQcow2ClusterSize || clusterSize > maxQcow2ClusterSize || clusterSize&(clusterSize-1) != 0 {
+		return 0, errors.Errorf("invalid qcow2 cluster size %q, must be a power of two from 512 to 2Mi", value)
+	}
+	return clusterSize, nil
+}
+
 // ResolveVolumeMode returns the volume mode if set, otherwise defaults to file system mode
 func ResolveVolumeMode(volumeMode *v1.PersistentVolumeMode) v1.PersistentVolumeMode {
 	retVolumeMode := v1.PersistentVolumeFilesystem
@@ -463,3 +614,67 @@ func ResolveVolumeMode(volumeMode *v1.PersistentVolumeMode) v1.PersistentVolumeM
 	}
 	return retVolumeMode
 }
+
+// ValidateCloneSourcePath checks a clone source path selector is a valid glob pattern relative to the source volume
+func ValidateCloneSourcePath(sourcePath string) error {
+	if sourcePath == "" {
+		return errors.New("clone source path is empty")
+	}
+	if filepath.IsAbs(sourcePath) {
+		return errors.Errorf("clone source path %s must be relative to the source volume", sourcePath)
+	}
+	cleanPath := filepath.Clean(sourcePath)
+	if cleanPath == "." || cleanPath == ".." || strings.HasPrefix(cleanPath, "../") {
+		return errors.Errorf("clone source path %s must be inside the source volume", sourcePath)
+	}
+	if _, err := filepath.Match(sourcePath, ""); err != nil {
+		return errors.Wrapf(err, "invalid clone source path %s", sourcePath)
+	}
+	return nil
+}
+
+// RedactSourceURL returns the source URL to log or report, without the credentials it may hold: its user info, like
+// user:pass@ or a token, and its query and fragment, like the signature of a presigned URL or an access token. The
+// scheme, host and path remain visible for debugging.
+func RedactSourceURL(sourceURL string) string {
+	u, err := url.Parse(sourceURL)
+	// Without scheme, the user of user:pass@host is parsed as the scheme of an opaque URL
+	if err != nil || u.Opaque != "" {
+		return redactUnparsableURL(sourceURL)
+	}
+	u.User = nil
+	u.RawQuery = ""
+	u.ForceQuery = false
+	u.Fragment = ""
+	u.RawFragment = ""
+	return u.String()
+}
+
+// urlPattern matches the URLs in a text, up to the first white space or quote
+var urlPattern = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^\s"'<>]+`)
+
+// RedactSourceURLs returns the text, such as an error message, with the credentials of the URLs it holds redacted by
+// RedactSourceURL
+func RedactSourceURLs(text string) string {
+	return urlPattern.ReplaceAllStringFunc(text, RedactSourceURL)
+}
+
+// redactUnparsableURL strips what follows the path of a URL that can't be parsed as a hierarchical URL, and the user
+// info preceding its host
+func redactUnparsableURL(sourceURL string) string {
+	if i := strings.IndexAny(sourceURL, "?#"); i >= 0 {
+		sourceURL = sourceURL[:i]
+	}
+	prefix := ""
+	if scheme, rest, found := strings.Cut(sourceURL, "://"); found {
+		prefix, sourceURL = scheme+"://", rest
+	}
+	authority, path := sourceURL, ""
+	if i := strings.Index(sourceURL, "/"); i >= 0 {
+		authority, path = sourceURL[:i], sourceURL[i:]
+	}
+	if i := strings.LastIndex(authority, "@"); i >= 0 {
+		authority = authority[i+1:]
+	}
+	return prefix + authority + path
+}
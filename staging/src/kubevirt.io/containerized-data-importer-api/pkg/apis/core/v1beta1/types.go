@@ -19,6 +19,7 @@ package v1beta1
 import (
 	ocpconfigv1 "github.com/openshift/api/config/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	sdkapi "kubevirt.io/controller-lifecycle-operator-sdk/api"
 )
@@ -66,6 +67,36 @@ type DataVolumeSpec struct {
 	FinalCheckpoint bool `json:"finalCheckpoint,omitempty"`
 	// Preallocation controls whether storage for DataVolumes should be allocated in advance.
 	Preallocation *bool `json:"preallocation,omitempty"`
+	// SnapshotTarget makes a VolumeSnapshot of the imported PVC the final artifact of the DataVolume
+	// +optional
+	SnapshotTarget *DataVolumeSnapshotTarget `json:"snapshotTarget,omitempty"`
+	// WorkerPodPlacement is the node selector and tolerations of the importer, clone and upload pods of the DataVolume. Unset fields default to the ones of the CDIConfig.
+	// +optional
+	WorkerPodPlacement *WorkerPodPlacement `json:"workerPodPlacement,omitempty"`
+	// WorkerPodImage is the image registry and pull policy of the importer, clone and upload pods of the DataVolume. Unset fields default to the ones of the CDIConfig.
+	// +optional
+	WorkerPodImage *WorkerPodImage `json:"workerPodImage,omitempty"`
+}
+
+// WorkerPodImage overrides the image registry and pull policy of the worker pods populating a DataVolume
+type WorkerPodImage struct {
+	// Registry replaces the registry and repository of the importer, cloner and upload server images, keeping their name, tag and digest, for instance to pull them from a mirror in an air-gapped cluster
+	// +optional
+	Registry string `json:"registry,omitempty"`
+	// PullPolicy is the image pull policy of the worker pods, overriding the one of the CDI CR
+	// +optional
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	PullPolicy corev1.PullPolicy `json:"pullPolicy,omitempty"`
+}
+
+// WorkerPodPlacement is the node selector and tolerations of the worker pods populating a DataVolume
+type WorkerPodPlacement struct {
+	// NodeSelector is the node selector of the worker pods, merged with the node selector of the workload node placement of the CDI CR
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations are the tolerations of the worker pods, in addition to the tolerations of the workload node placement of the CDI CR
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 }
 
 // StorageSpec defines the Storage type specification
@@ -114,6 +145,31 @@ type DataVolumeCheckpoint struct {
 	Current string `json:"current"`
 }
 
+// DataVolumeSnapshotTarget defines the VolumeSnapshot a DataVolume imports into
+type DataVolumeSnapshotTarget struct {
+	// Name is the name of the VolumeSnapshot, the name of the DataVolume if empty
+	// +optional
+	Name string `json:"name,omitempty"`
+	// VolumeSnapshotClassName is the class of the VolumeSnapshot. If empty, a class of the provisioner of the
+	// storage class of the PVC is used
+	// +optional
+	VolumeSnapshotClassName *string `json:"volumeSnapshotClassName,omitempty"`
+	// PVCRetainPolicy tells whether the PVC is deleted once the VolumeSnapshot is ready, Delete if empty
+	// +kubebuilder:validation:Enum="Delete";"Retain"
+	// +optional
+	PVCRetainPolicy SnapshotTargetPVCRetainPolicy `json:"pvcRetainPolicy,omitempty"`
+}
+
+// SnapshotTargetPVCRetainPolicy defines whether the PVC of a DataVolume importing into a VolumeSnapshot is kept
+type SnapshotTargetPVCRetainPolicy string
+
+const (
+	// SnapshotTargetPVCDelete deletes the PVC once the VolumeSnapshot is ready
+	SnapshotTargetPVCDelete SnapshotTargetPVCRetainPolicy = "Delete"
+	// SnapshotTargetPVCRetain keeps the PVC once the VolumeSnapshot is ready
+	SnapshotTargetPVCRetain SnapshotTargetPVCRetainPolicy = "Retain"
+)
+
 // DataVolumeContentType represents the types of the imported data
 type DataVolumeContentType string
 
@@ -126,16 +182,23 @@ const (
 
 // DataVolumeSource represents the source for our Data Volume, this can be HTTP, Imageio, S3, GCS, Registry or an existing PVC
 type DataVolumeSource struct {
-	HTTP     *DataVolumeSourceHTTP     `json:"http,omitempty"`
-	S3       *DataVolumeSourceS3       `json:"s3,omitempty"`
-	GCS      *DataVolumeSourceGCS      `json:"gcs,omitempty"`
-	Registry *DataVolumeSourceRegistry `json:"registry,omitempty"`
-	PVC      *DataVolumeSourcePVC      `json:"pvc,omitempty"`
-	Upload   *DataVolumeSourceUpload   `json:"upload,omitempty"`
-	Blank    *DataVolumeBlankImage     `json:"blank,omitempty"`
-	Imageio  *DataVolumeSourceImageIO  `json:"imageio,omitempty"`
-	VDDK     *DataVolumeSourceVDDK     `json:"vddk,omitempty"`
-	Snapshot *DataVolumeSourceSnapshot `json:"snapshot,omitempty"`
+	HTTP      *DataVolumeSourceHTTP      `json:"http,omitempty"`
+	S3        *DataVolumeSourceS3        `json:"s3,omitempty"`
+	GCS       *DataVolumeSourceGCS       `json:"gcs,omitempty"`
+	Registry  *DataVolumeSourceRegistry  `json:"registry,omitempty"`
+	PVC       *DataVolumeSourcePVC       `json:"pvc,omitempty"`
+	Upload    *DataVolumeSourceUpload    `json:"upload,omitempty"`
+	Blank     *DataVolumeBlankImage      `json:"blank,omitempty"`
+	Imageio   *DataVolumeSourceImageIO   `json:"imageio,omitempty"`
+	VDDK      *DataVolumeSourceVDDK      `json:"vddk,omitempty"`
+	Snapshot  *DataVolumeSourceSnapshot  `json:"snapshot,omitempty"`
+	Inline    *DataVolumeSourceInline    `json:"inline,omitempty"`
+	NBD       *DataVolumeSourceNBD       `json:"nbd,omitempty"`
+	Remote    *DataVolumeSourceRemote    `json:"remote,omitempty"`
+	RemotePVC *DataVolumeSourceRemotePVC `json:"remotePVC,omitempty"`
+	FTP       *DataVolumeSourceFTP       `json:"ftp,omitempty"`
+	Azure     *DataVolumeSourceAzure     `json:"azure,omitempty"`
+	SFTP      *DataVolumeSourceSFTP      `json:"sftp,omitempty"`
 }
 
 // DataVolumeSourcePVC provides the parameters to create a Data Volume from an existing PVC
@@ -161,6 +224,85 @@ type DataVolumeBlankImage struct{}
 type DataVolumeSourceUpload struct {
 }
 
+// DataVolumeSourceInline provides the parameters to create a Data Volume from a small image embedded in the DataVolume
+type DataVolumeSourceInline struct {
+	// Data is the base64 encoded image, optionally gzip compressed, of at most 1MiB once encoded
+	Data string `json:"data"`
+}
+
+// DataVolumeSourceNBD provides the parameters to create a Data Volume from a disk exported over NBD
+type DataVolumeSourceNBD struct {
+	// URL is the url of the NBD export, as in nbd://host[:port]/export, or nbds://host[:port]/export for NBD over TLS
+	URL string `json:"url"`
+	// SecretRef provides the secret holding the ca-cert.pem of the NBD server CA, and optionally the client-cert.pem and
+	// client-key.pem of a client certificate, required for NBD over TLS
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+}
+
+// DataVolumeSourceRemote provides the parameters to clone a Data Volume from a disk exported by a remote cluster
+type DataVolumeSourceRemote struct {
+	// URL is the url of the disk image exported by the remote cluster
+	URL string `json:"url"`
+	// SecretRef provides the secret holding the token authorizing the export, in its token key
+	SecretRef string `json:"secretRef"`
+	// CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key of the remote endpoint
+	// +optional
+	CertConfigMap string `json:"certConfigMap,omitempty"`
+}
+
+// DataVolumeSourceRemotePVC provides the parameters to clone a Data Volume from a PVC of a remote cluster
+type DataVolumeSourceRemotePVC struct {
+	// KubeconfigSecretRef provides the secret holding the kubeconfig of the remote cluster, in its kubeconfig key
+	KubeconfigSecretRef string `json:"kubeconfigSecretRef"`
+	// The namespace of the source PVC in the remote cluster
+	Namespace string `json:"namespace"`
+	// The name of the source PVC in the remote cluster
+	Name string `json:"name"`
+}
+
+// DataVolumeSourceFTP provides the parameters to create a Data Volume from an FTP or FTPS source
+type DataVolumeSourceFTP struct {
+	// URL is the url of the image on the FTP server, as in ftp://host[:port]/path, or ftps://host[:port]/path for FTP
+	// over explicit TLS
+	URL string `json:"url"`
+	// SecretRef provides the secret holding the user name and password of the FTP server in its accessKeyId and
+	// secretKey keys, the login is anonymous without it
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+	// CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key of the FTPS server
+	// +optional
+	CertConfigMap string `json:"certConfigMap,omitempty"`
+}
+
+// DataVolumeSourceSFTP provides the parameters to create a Data Volume from an SFTP source
+type DataVolumeSourceSFTP struct {
+	// URL is the url of the image on the SFTP server, as in sftp://host[:port]/path, the path is relative to the home
+	// directory of the user if it starts with /~/
+	URL string `json:"url"`
+	// SecretRef provides the secret holding the user name in its username key, and its private key in the
+	// ssh-privatekey key, with the passphrase of an encrypted key in the passphrase key, or its password in the password
+	// key
+	SecretRef string `json:"secretRef"`
+	// HostKey is the public host key the SFTP server must present, in the authorized_keys format, as in
+	// "ssh-ed25519 AAAA...", or its SHA256 fingerprint, as in "SHA256:...", printed by ssh-keygen -l
+	HostKey string `json:"hostKey"`
+}
+
+// DataVolumeSourceAzure provides the parameters to create a Data Volume from an Azure Blob Storage source
+type DataVolumeSourceAzure struct {
+	// URL is the url of the blob, as in https://<account>.blob.core.windows.net/<container>/<blob>, without a SAS
+	// token
+	URL string `json:"url"`
+	// SecretRef provides the secret holding a SAS token in its sasToken key, or the tenantId, clientId and clientSecret
+	// of a service principal, the blob is read anonymously without it
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+	// CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key of the Blob service
+	// +optional
+	CertConfigMap string `json:"certConfigMap,omitempty"`
+}
+
 // DataVolumeSourceS3 provides the parameters to create a Data Volume from an S3 source
 type DataVolumeSourceS3 struct {
 	//URL is the url of the S3 source
@@ -170,6 +312,12 @@ type DataVolumeSourceS3 struct {
 	// CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key, and a base64 encoded pem certificate
 	// +optional
 	CertConfigMap string `json:"certConfigMap,omitempty"`
+	// TokenCredentials gets the credentials by assuming an IAM role with a projected service account token (IRSA), instead of the SecretRef keys
+	// +optional
+	TokenCredentials *DataVolumeSourceTokenCredentials `json:"tokenCredentials,omitempty"`
+	// KMSKeyID is the ID or ARN of the AWS KMS key the object is encrypted with (SSE-KMS), the import fails if the object is not encrypted with this key
+	// +optional
+	KMSKeyID string `json:"kmsKeyId,omitempty"`
 }
 
 // DataVolumeSourceGCS provides the parameters to create a Data Volume from an GCS source
@@ -178,6 +326,31 @@ type DataVolumeSourceGCS struct {
 	URL string `json:"url"`
 	//SecretRef provides the secret reference needed to access the GCS source
 	SecretRef string `json:"secretRef,omitempty"`
+	// TokenCredentials gets the credentials through workload identity federation with a projected service account token, instead of the SecretRef key file
+	// +optional
+	TokenCredentials *DataVolumeSourceTokenCredentials `json:"tokenCredentials,omitempty"`
+	// UserProject is the project billed for the requests to a requester-pays bucket, required to read from such a bucket
+	// +optional
+	UserProject string `json:"userProject,omitempty"`
+	// Generation pins the import to this generation of the object, the import fails if the generation does not exist. By default
+	// the import reads the latest generation, pinned for the whole download so a resumed download does not mix two generations
+	// +optional
+	Generation int64 `json:"generation,omitempty"`
+}
+
+// DataVolumeSourceTokenCredentials provides the parameters to get the credentials of an S3 or GCS source by exchanging
+// a service account token projected in the importer pod
+type DataVolumeSourceTokenCredentials struct {
+	// Audience is the audience of the projected token. For S3 it defaults to sts.amazonaws.com, for GCS it is required and is
+	// the workload identity pool provider, as in //iam.googleapis.com/projects/<number>/locations/global/workloadIdentityPools/<pool>/providers/<provider>
+	// +optional
+	Audience string `json:"audience,omitempty"`
+	// RoleARN is the ARN of the IAM role assumed with the token, required for S3
+	// +optional
+	RoleARN string `json:"roleARN,omitempty"`
+	// ServiceAccount is the email of the GCP service account impersonated with the federated token, optional for GCS
+	// +optional
+	ServiceAccount string `json:"serviceAccount,omitempty"`
 }
 
 // DataVolumeSourceRegistry provides the parameters to create a Data Volume from an registry source
@@ -197,6 +370,21 @@ type DataVolumeSourceRegistry struct {
 	//CertConfigMap provides a reference to the Registry certs
 	// +optional
 	CertConfigMap *string `json:"certConfigMap,omitempty"`
+	//Disks lists the disks of an image holding several of them, each imported into its own PVC
+	// +optional
+	Disks []RegistryDisk `json:"disks,omitempty"`
+}
+
+// RegistryDisk selects a disk of a multi-disk registry image, and the PVC it is imported into
+type RegistryDisk struct {
+	//Path is the path of the disk in the image, relative to its disk directory
+	Path string `json:"path"`
+	//PVCName is the name of the PVC the disk is imported into, the PVC of the DataVolume when empty
+	// +optional
+	PVCName string `json:"pvcName,omitempty"`
+	//Size is the size requested for the PVC of the disk, the size of the DataVolume when not set. The disk imported into the PVC of the DataVolume gets the size of the DataVolume.
+	// +optional
+	Size *resource.Quantity `json:"size,omitempty"`
 }
 
 const (
@@ -288,6 +476,40 @@ type DataVolumeStatus struct {
 	// RestartCount is the number of times the pod populating the DataVolume has restarted
 	RestartCount int32                 `json:"restartCount,omitempty"`
 	Conditions   []DataVolumeCondition `json:"conditions,omitempty" optional:"true"`
+	// ImportTimings is the time the importer spent in the phases of the import
+	ImportTimings *DataVolumeImportTimings `json:"importTimings,omitempty"`
+	// ImportPassthrough tells the importer copied the source image as is, without conversion, as it already had the target format
+	ImportPassthrough bool `json:"importPassthrough,omitempty"`
+	// ImportSourceDigest is the digest of the source bytes the importer read, as <algorithm>:<hex>, when requested with the storage.import.sourceDigestAlgorithm annotation
+	ImportSourceDigest string `json:"importSourceDigest,omitempty"`
+	// PausedSince is the time the DataVolume entered the Paused phase of a multi-stage import, the time it stays paused does not count toward its completion timeout
+	PausedSince *metav1.Time `json:"pausedSince,omitempty"`
+	// PausedSeconds is the time in seconds the DataVolume previously spent in the Paused phase, not counted toward its completion timeout
+	PausedSeconds int64 `json:"pausedSeconds,omitempty"`
+	// Disks reports the import of each disk of a multi-disk registry image
+	Disks []DataVolumeDiskStatus `json:"disks,omitempty"`
+}
+
+// DataVolumeDiskStatus reports the import of a disk of a multi-disk registry image
+type DataVolumeDiskStatus struct {
+	// Path is the path of the disk in the image
+	Path string `json:"path"`
+	// ClaimName is the name of the PVC the disk is imported into
+	ClaimName string `json:"claimName"`
+	// Phase is the current phase of the import of the disk
+	Phase DataVolumePhase `json:"phase,omitempty"`
+}
+
+// DataVolumeImportTimings holds the time the importer spent in the phases of the import, in whole seconds. A phase
+// that was skipped or took less than a second is omitted. The decompression is part of the download, and the
+// preallocation is part of the conversion and the resize.
+type DataVolumeImportTimings struct {
+	// DownloadSeconds is the time spent transferring the source to the scratch space or the target volume
+	DownloadSeconds int64 `json:"downloadSeconds,omitempty"`
+	// ConvertSeconds is the time spent converting the image to the target volume
+	ConvertSeconds int64 `json:"convertSeconds,omitempty"`
+	// ResizeSeconds is the time spent resizing the image to the requested size
+	ResizeSeconds int64 `json:"resizeSeconds,omitempty"`
 }
 
 // DataVolumeList provides the needed parameters to do request a list of Data Volumes from the system
@@ -375,6 +597,10 @@ const (
 	Unknown DataVolumePhase = "Unknown"
 	// Paused represents a DataVolumePhase of Paused
 	Paused DataVolumePhase = "Paused"
+	// Canceled represents a DataVolumePhase of Canceled
+	Canceled DataVolumePhase = "Canceled"
+	// SourceVerified represents a DataVolumePhase of SourceVerified, the import source of a verify-only DataVolume was verified
+	SourceVerified DataVolumePhase = "SourceVerified"
 
 	// DataVolumeReady is the condition that indicates if the data volume is ready to be consumed.
 	DataVolumeReady DataVolumeConditionType = "Ready"
@@ -382,6 +608,20 @@ const (
 	DataVolumeBound DataVolumeConditionType = "Bound"
 	// DataVolumeRunning is the condition that indicates if the import/upload/clone container is running.
 	DataVolumeRunning DataVolumeConditionType = "Running"
+	// DataVolumeVerified is the condition that indicates if the populated PVC was verified, it is only set when requested.
+	DataVolumeVerified DataVolumeConditionType = "Verified"
+	// DataVolumeConverting is the condition that indicates the progress of the qemu-img conversion, it is only set when
+	// the population converts the image.
+	DataVolumeConverting DataVolumeConditionType = "Converting"
+	// DataVolumeCloneStrategy is the condition that reports the clone strategy selected for the data volume, it is only
+	// set when the auto clone strategy is used.
+	DataVolumeCloneStrategy DataVolumeConditionType = "CloneStrategy"
+	// DataVolumeCloneFallback is the condition that reports why a snapshot or CSI clone fell back to a host assisted
+	// clone, it is only set when the clone fell back.
+	DataVolumeCloneFallback DataVolumeConditionType = "CloneFallback"
+	// DataVolumeCanceled is the condition that indicates the transfer of the data volume was canceled and torn down,
+	// it is only set once the data volume is canceled.
+	DataVolumeCanceled DataVolumeConditionType = "Canceled"
 )
 
 // DataVolumeCloneSourceSubresource is the subresource checked for permission to clone
@@ -411,6 +651,20 @@ type StorageProfileSpec struct {
 	CloneStrategy *CDICloneStrategy `json:"cloneStrategy,omitempty"`
 	// ClaimPropertySets is a provided set of properties applicable to PVC
 	ClaimPropertySets []ClaimPropertySet `json:"claimPropertySets,omitempty"`
+	// Preallocation is the recommended preallocation setting for DataVolumes which don't set their own
+	Preallocation *bool `json:"preallocation,omitempty"`
+	// ThinProvisioned marks the storage of the class as thin provisioned, DataVolumes which don't set their own
+	// preallocation are then not preallocated unless Preallocation is set
+	ThinProvisioned *bool `json:"thinProvisioned,omitempty"`
+	// FilesystemOverhead is the recommended filesystem overhead for Filesystem volumes of the storage class
+	FilesystemOverhead *Percent `json:"filesystemOverhead,omitempty"`
+	// CloneStreams is the number of parallel streams of the host-assisted clones of block disks into the storage class
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=16
+	CloneStreams *int32 `json:"cloneStreams,omitempty"`
+	// CrossClassSnapshotRestore marks the CSI driver of the storage class as able to restore the snapshots of the volumes
+	// of its other storage classes into this one, so snapshot clones from them are not host-assisted
+	CrossClassSnapshotRestore *bool `json:"crossClassSnapshotRestore,omitempty"`
 }
 
 // StorageProfileStatus provides the most recently observed status of the StorageProfile
@@ -423,6 +677,18 @@ type StorageProfileStatus struct {
 	CloneStrategy *CDICloneStrategy `json:"cloneStrategy,omitempty"`
 	// ClaimPropertySets computed from the spec and detected in the system
 	ClaimPropertySets []ClaimPropertySet `json:"claimPropertySets,omitempty"`
+	// Preallocation is the recommended preallocation setting for DataVolumes which don't set their own
+	Preallocation *bool `json:"preallocation,omitempty"`
+	// ThinProvisioned marks the storage of the class as thin provisioned, DataVolumes which don't set their own
+	// preallocation are then not preallocated unless Preallocation is set
+	ThinProvisioned *bool `json:"thinProvisioned,omitempty"`
+	// FilesystemOverhead is the recommended filesystem overhead for Filesystem volumes of the storage class
+	FilesystemOverhead *Percent `json:"filesystemOverhead,omitempty"`
+	// CloneStreams is the number of parallel streams of the host-assisted clones of block disks into the storage class
+	CloneStreams *int32 `json:"cloneStreams,omitempty"`
+	// CrossClassSnapshotRestore marks the CSI driver of the storage class as able to restore the snapshots of the volumes
+	// of its other storage classes into this one, so snapshot clones from them are not host-assisted
+	CrossClassSnapshotRestore *bool `json:"crossClassSnapshotRestore,omitempty"`
 }
 
 // ClaimPropertySet is a set of properties applicable to PVC
@@ -515,6 +781,59 @@ type DataSourceList struct {
 	Items []DataSource `json:"items"`
 }
 
+// CloneGrant pre-approves the cross-namespace clones of the PVCs and VolumeSnapshots of its namespace
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:resource:shortName=cg;cgs,categories=all
+type CloneGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CloneGrantSpec `json:"spec"`
+}
+
+// CloneGrantSpec defines the clones pre-approved by the CloneGrant
+type CloneGrantSpec struct {
+	// Sources are the PVCs and VolumeSnapshots of the namespace the grant applies to, all of them when empty
+	// +optional
+	Sources []CloneGrantSource `json:"sources,omitempty"`
+	// TargetNamespaces are the namespaces any user may clone the sources to
+	// +optional
+	TargetNamespaces []string `json:"targetNamespaces,omitempty"`
+	// ServiceAccounts are the ServiceAccounts that may clone the sources to their namespace
+	// +optional
+	ServiceAccounts []CloneGrantServiceAccount `json:"serviceAccounts,omitempty"`
+}
+
+// CloneGrantSource is a PVC or VolumeSnapshot granted by a CloneGrant
+type CloneGrantSource struct {
+	// Kind is the kind of the source, PersistentVolumeClaim or VolumeSnapshot
+	// +kubebuilder:validation:Enum=PersistentVolumeClaim;VolumeSnapshot
+	Kind string `json:"kind"`
+	// Name is the name of the source
+	Name string `json:"name"`
+}
+
+// CloneGrantServiceAccount is a ServiceAccount granted by a CloneGrant
+type CloneGrantServiceAccount struct {
+	// Namespace is the namespace of the ServiceAccount
+	Namespace string `json:"namespace"`
+	// Name is the name of the ServiceAccount
+	Name string `json:"name"`
+}
+
+// CloneGrantList provides the needed parameters to do request a list of CloneGrants from the system
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type CloneGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	// Items provides a list of CloneGrants
+	Items []CloneGrant `json:"items"`
+}
+
 // DataImportCron defines a cron job for recurring polling/importing disk images as PVCs into a golden image namespace
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -548,6 +867,11 @@ type DataImportCronSpec struct {
 	// RetentionPolicy specifies whether the created DataVolumes and DataSources are retained when their DataImportCron is deleted. Default is RatainAll.
 	// +optional
 	RetentionPolicy *DataImportCronRetentionPolicy `json:"retentionPolicy,omitempty"`
+	// SourceTagPattern is a regular expression selecting the tags of the registry URL source repository to import.
+	// The matching tag with the highest semantic version is imported, with an optional "v" prefix.
+	// Tags that do not match or are not semantic versions are ignored, as is the tag of the source URL.
+	// +optional
+	SourceTagPattern *string `json:"sourceTagPattern,omitempty"`
 }
 
 // DataImportCronGarbageCollect represents the DataImportCron garbage collection mode
@@ -698,6 +1022,10 @@ const (
 
 	// CloneStrategyCsiClone specifies csi volume clone based cloning
 	CloneStrategyCsiClone CDICloneStrategy = "csi-clone"
+
+	// CloneStrategyAuto specifies csi volume clone within a storage class, snapshot-based copying across the storage
+	// classes of a provisioner, and host-assisted copy otherwise
+	CloneStrategyAuto CDICloneStrategy = "auto"
 )
 
 // CDIUninstallStrategy defines the state to leave CDI on uninstall
@@ -786,6 +1114,63 @@ type CDIConfigSpec struct {
 	TLSSecurityProfile *ocpconfigv1.TLSSecurityProfile `json:"tlsSecurityProfile,omitempty"`
 	// The imagePullSecrets used to pull the container images
 	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// ImportMaxAttempts is the number of failed attempts after which an import DataVolume fails. Unset means the import is retried indefinitely.
+	// +optional
+	ImportMaxAttempts *int32 `json:"importMaxAttempts,omitempty"`
+	// PodIOLimits are the disk IO limits of the importer and clone source pods, applied through the cgroup of the pod where the container runtime supports it.
+	// +optional
+	PodIOLimits *PodIOLimits `json:"podIOLimits,omitempty"`
+	// MaxParallelWorkerPods is the maximum number of import, upload and host-assisted clone worker pods CDI runs simultaneously, the DataVolumes beyond it wait in creation order. Unset means no limit.
+	// +optional
+	MaxParallelWorkerPods *int32 `json:"maxParallelWorkerPods,omitempty"`
+	// CloneAnnotationAllowlist is the list of annotations copied from the source to the target PVC of a host-assisted clone, in addition to the image format and virtual size recorded by CDI. Other annotations of the source are not copied.
+	// +optional
+	CloneAnnotationAllowlist []string `json:"cloneAnnotationAllowlist,omitempty"`
+	// ImportTLSSecurityProfile is the TLS security profile of the importer clients connecting to the HTTP, S3 and ImageIO import sources. The default is the intermediate profile, TLS 1.2 and above. A DataVolume can override the minimal version and the ciphers of its source with annotations.
+	// +optional
+	ImportTLSSecurityProfile *ocpconfigv1.TLSSecurityProfile `json:"importTLSSecurityProfile,omitempty"`
+	// ScratchSpace configures the volume backing the scratch space of the importer pods. The default is a PVC.
+	// +optional
+	ScratchSpace *ScratchSpaceConfig `json:"scratchSpace,omitempty"`
+	// DataImportCronPolling configures how the CDI controller polls the sources of the DataImportCrons.
+	// +optional
+	DataImportCronPolling *DataImportCronPollingConfig `json:"dataImportCronPolling,omitempty"`
+	// WorkerPodPlacement is the default node selector and tolerations of the importer, clone and upload pods of the DataVolumes not setting them.
+	// +optional
+	WorkerPodPlacement *WorkerPodPlacement `json:"workerPodPlacement,omitempty"`
+	// AllowedWorkerPodPlacement is the node selector labels and tolerations the DataVolumes are allowed to set in their workerPodPlacement. Unset means the DataVolumes cannot set a worker pod placement.
+	// +optional
+	AllowedWorkerPodPlacement *WorkerPodPlacement `json:"allowedWorkerPodPlacement,omitempty"`
+	// WorkerPodImage is the default image registry and pull policy of the importer, clone and upload pods of the DataVolumes not setting them.
+	// +optional
+	WorkerPodImage *WorkerPodImage `json:"workerPodImage,omitempty"`
+	// AllowedWorkerPodRegistries are the image registries the DataVolumes are allowed to set in their workerPodImage. Unset means the DataVolumes cannot set a worker pod image registry.
+	// +optional
+	AllowedWorkerPodRegistries []string `json:"allowedWorkerPodRegistries,omitempty"`
+	// DataVolumeCompletionTimeout is the time from its creation within which a DataVolume must succeed, not counting the time it is paused, after which it fails. Unset means no timeout.
+	// +optional
+	DataVolumeCompletionTimeout *metav1.Duration `json:"dataVolumeCompletionTimeout,omitempty"`
+	// CompletionHooks are the HTTP endpoints notified once the DataVolumes naming them in their cdi.kubevirt.io/storage.completionHook annotation succeeded. Unset means the DataVolumes cannot notify any endpoint.
+	// +optional
+	CompletionHooks []CompletionHook `json:"completionHooks,omitempty"`
+	// ClusterDelegatedAuthorizer is an external HTTP authorization webhook deciding the cross-namespace clones, instead of or in addition to the built-in SubjectAccessReview checks.
+	// +optional
+	ClusterDelegatedAuthorizer *ClusterDelegatedAuthorizer `json:"clusterDelegatedAuthorizer,omitempty"`
+	// CloneAuthorization overrides or extends the resource attributes of the SubjectAccessReviews allowing the cross-namespace clones, and configures the cache of their decisions
+	// +optional
+	CloneAuthorization *CloneAuthorizationConfig `json:"cloneAuthorization,omitempty"`
+	// CloneBandwidthLimit is the default network bandwidth limit of the host-assisted clones, in bytes per second, enforced by the clone source pod streaming to the target. A DataVolume overrides it with its cdi.kubevirt.io/storage.clone.bandwidthLimit annotation. Unset or 0 means no limit.
+	// +optional
+	CloneBandwidthLimit *resource.Quantity `json:"cloneBandwidthLimit,omitempty"`
+	// CloneStreams is the default number of parallel streams of the host-assisted clones of block disks, each streaming a range of the disk over its own connection. The StorageProfile of the target storage class overrides it. Unset means a single stream.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=16
+	// +optional
+	CloneStreams *int32 `json:"cloneStreams,omitempty"`
+	// CloneCompression is the default compression codec of the stream of the host-assisted clones, negotiated by the clone source with the target, which falls back to snappy if the target does not support it. A DataVolume overrides it with its cdi.kubevirt.io/storage.clone.compression annotation. Unset means snappy.
+	// +kubebuilder:validation:Enum="none";"gzip";"snappy";"zstd"
+	// +optional
+	CloneCompression *string `json:"cloneCompression,omitempty"`
 }
 
 // CDIConfigStatus provides the most recently observed status of the CDI Config resource
@@ -817,6 +1202,133 @@ type CDIConfigList struct {
 	Items []CDIConfig `json:"items"`
 }
 
+// PodIOLimits defines the disk IO limits of a CDI worker pod on its volume
+type PodIOLimits struct {
+	// BlockIOClass is the blockio class of the pods, set with the blockio.resources.beta.kubernetes.io/pod annotation. The container runtime applies the IO limits the class is configured with on the nodes, also when the pod can't write its own cgroup.
+	// +optional
+	BlockIOClass *string `json:"blockIOClass,omitempty"`
+	// MaxBytesPerSecond is the maximum read and write throughput of the pod on its volume, in bytes per second.
+	// +optional
+	MaxBytesPerSecond *resource.Quantity `json:"maxBytesPerSecond,omitempty"`
+	// MaxIOPS is the maximum number of read and write IO operations per second of the pod on its volume.
+	// +optional
+	MaxIOPS *int64 `json:"maxIOPS,omitempty"`
+	// Weight is the proportional disk IO weight of the pod, between 1 and 10000, the cgroup v2 io.weight. It is scaled to the 10 to 1000 range of the cgroup v1 blkio.weight.
+	// +optional
+	Weight *int32 `json:"weight,omitempty"`
+}
+
+// ScratchSpaceBackend is the kind of volume backing the scratch space of an importer pod
+type ScratchSpaceBackend string
+
+const (
+	// ScratchSpaceBackendPVC backs the scratch space with a PVC provisioned for the import
+	ScratchSpaceBackendPVC ScratchSpaceBackend = "PVC"
+	// ScratchSpaceBackendEmptyDirDisk backs the scratch space with an emptyDir on the disk of the node
+	ScratchSpaceBackendEmptyDirDisk ScratchSpaceBackend = "EmptyDirDisk"
+	// ScratchSpaceBackendEmptyDirMemory backs the scratch space with an emptyDir in the memory of the node
+	ScratchSpaceBackendEmptyDirMemory ScratchSpaceBackend = "EmptyDirMemory"
+)
+
+// ScratchSpaceConfig defines the volume backing the scratch space of the importer pods
+type ScratchSpaceConfig struct {
+	// Backend is the kind of volume backing the scratch space, PVC, EmptyDirDisk or EmptyDirMemory. The default is PVC. A DataVolume can override it with an annotation.
+	// +kubebuilder:validation:Enum=PVC;EmptyDirDisk;EmptyDirMemory
+	// +optional
+	Backend ScratchSpaceBackend `json:"backend,omitempty"`
+	// MaxEmptyDirSize is the largest scratch space backed by an emptyDir, a larger scratch space is backed by a PVC. Unset means no limit.
+	// +optional
+	MaxEmptyDirSize *resource.Quantity `json:"maxEmptyDirSize,omitempty"`
+}
+
+// DataImportCronPollingConfig defines how the CDI controller polls the sources of the DataImportCrons
+type DataImportCronPollingConfig struct {
+	// Parallelism is the maximum number of DataImportCron sources polled concurrently, at most 16. The default is 1. The polls of the same DataImportCron never overlap.
+	// +optional
+	Parallelism *int32 `json:"parallelism,omitempty"`
+	// RegistryPollsPerMinute is the maximum number of polls per minute of the same registry. Unset means no limit.
+	// +optional
+	RegistryPollsPerMinute *int32 `json:"registryPollsPerMinute,omitempty"`
+}
+
+// ClusterDelegatedAuthorizer defines an external HTTP authorization webhook deciding the cross-namespace clones, compatible with the data API of Open Policy Agent
+type ClusterDelegatedAuthorizer struct {
+	// URL is the http or https URL the clone authorization requests are POSTed to, such as the data API URL of an Open Policy Agent policy. The request body holds the clone in its input field, and the response body tells whether it is allowed in its result field, either a boolean or an object with allowed and reason fields.
+	URL string `json:"url"`
+	// Mode tells whether the webhook decides the cross-namespace clones in addition to the built-in SubjectAccessReview checks, both having to allow a clone, or instead of them. The default is Additional.
+	// +kubebuilder:validation:Enum=Additional;Replace
+	// +optional
+	Mode DelegatedAuthorizerMode `json:"mode,omitempty"`
+	// CABundle is the PEM encoded CA bundle verifying the certificate of an https webhook. The system trust roots are used when empty.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+	// TimeoutSeconds is the timeout of a webhook request, after which the clone is denied. The default is 10 seconds.
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// CompletionHook defines an HTTP endpoint notified once a DataVolume naming it succeeded
+type CompletionHook struct {
+	// Name is the name the DataVolumes give to the hook in their cdi.kubevirt.io/storage.completionHook annotation
+	Name string `json:"name"`
+	// URL is the http or https URL receiving a POST with a JSON body once a DataVolume naming the hook succeeded
+	URL string `json:"url"`
+	// TokenSecret is the name of the Secret of the CDI namespace holding the bearer token of the POST in its token key
+	// +optional
+	TokenSecret string `json:"tokenSecret,omitempty"`
+}
+
+// DelegatedAuthorizerMode tells how the delegated authorizer of the clones combines with the built-in checks
+type DelegatedAuthorizerMode string
+
+const (
+	// DelegatedAuthorizerModeAdditional allows a clone when both the built-in checks and the delegated authorizer allow it
+	DelegatedAuthorizerModeAdditional DelegatedAuthorizerMode = "Additional"
+	// DelegatedAuthorizerModeReplace allows a clone when the delegated authorizer allows it, without the built-in checks
+	DelegatedAuthorizerModeReplace DelegatedAuthorizerMode = "Replace"
+)
+
+// CloneAuthorizationConfig defines the resource attributes of the SubjectAccessReviews allowing the cross-namespace clones, and the cache of their decisions
+type CloneAuthorizationConfig struct {
+	// ResourceAttributes are the verbs on resources of the clone source namespace allowing a user or ServiceAccount to clone from it, any of them allowing the clone. The namespace and name of the reviewed resource are those of the clone source.
+	// +optional
+	ResourceAttributes []CloneResourceAttributes `json:"resourceAttributes,omitempty"`
+	// Mode tells whether ResourceAttributes extend the built-in create datavolumes/source and create pods checks, or override them. The default is Extend.
+	// +kubebuilder:validation:Enum=Extend;Override
+	// +optional
+	Mode CloneAuthorizationMode `json:"mode,omitempty"`
+	// CacheTTL is the time the decisions of the SubjectAccessReviews allowing the cross-namespace clones are cached by the CDI API server, such as 30s. Unset or zero disables the cache.
+	// +optional
+	CacheTTL *metav1.Duration `json:"cacheTTL,omitempty"`
+	// AuditLog writes the decisions of the cross-namespace clone authorizations as JSON lines to the log of the CDI API server, in addition to the Events recorded on the clone sources.
+	// +optional
+	AuditLog bool `json:"auditLog,omitempty"`
+}
+
+// CloneResourceAttributes defines a verb on a resource of the clone source namespace allowing to clone from it
+type CloneResourceAttributes struct {
+	// Verb is the verb allowed on the resource, such as create or a dedicated clone verb
+	Verb string `json:"verb"`
+	// Group is the API group of the resource, empty for the core group
+	// +optional
+	Group string `json:"group,omitempty"`
+	// Resource is the resource, such as datavolumes or a custom resource of an aggregated role
+	Resource string `json:"resource"`
+	// Subresource is the subresource, such as source
+	// +optional
+	Subresource string `json:"subresource,omitempty"`
+}
+
+// CloneAuthorizationMode tells how the configured resource attributes of the clone authorization combine with the built-in ones
+type CloneAuthorizationMode string
+
+const (
+	// CloneAuthorizationModeExtend allows a clone when any of the built-in or configured resource attributes is allowed
+	CloneAuthorizationModeExtend CloneAuthorizationMode = "Extend"
+	// CloneAuthorizationModeOverride allows a clone when any of the configured resource attributes is allowed, ignoring the built-in ones
+	CloneAuthorizationModeOverride CloneAuthorizationMode = "Override"
+)
+
 // ImportProxy provides the information on how to configure the importer pod proxy.
 type ImportProxy struct {
 	// HTTPProxy is the URL http://<username>:<pswd>@<ip>:<port> of the import proxy for HTTP requests.  Empty means unset and will not result in the import pod env var.
@@ -844,4 +1356,8 @@ type ImportProxy struct {
 	// 	   -----END CERTIFICATE-----
 	// +optional
 	TrustedCAProxy *string `json:"trustedCAProxy,omitempty"`
+	// ProxyAuthSecret is the name of a Secret in the cdi namespace that contains the username and password keys used to authenticate against the import proxy.
+	// The credentials in the Secret take precedence over the ones in the proxy URLs.
+	// +optional
+	ProxyAuthSecret *string `json:"proxyAuthSecret,omitempty"`
 }
@@ -11,16 +11,35 @@ func (DataVolume) SwaggerDoc() map[string]string {
 
 func (DataVolumeSpec) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":                  "DataVolumeSpec defines the DataVolume type specification",
-		"source":            "Source is the src of the data for the requested DataVolume\n+optional",
-		"sourceRef":         "SourceRef is an indirect reference to the source of data for the requested DataVolume\n+optional",
-		"pvc":               "PVC is the PVC specification",
-		"storage":           "Storage is the requested storage specification",
-		"priorityClassName": "PriorityClassName for Importer, Cloner and Uploader pod",
-		"contentType":       "DataVolumeContentType options: \"kubevirt\", \"archive\"\n+kubebuilder:validation:Enum=\"kubevirt\";\"archive\"",
-		"checkpoints":       "Checkpoints is a list of DataVolumeCheckpoints, representing stages in a multistage import.",
-		"finalCheckpoint":   "FinalCheckpoint indicates whether the current DataVolumeCheckpoint is the final checkpoint.",
-		"preallocation":     "Preallocation controls whether storage for DataVolumes should be allocated in advance.",
+		"":                   "DataVolumeSpec defines the DataVolume type specification",
+		"source":             "Source is the src of the data for the requested DataVolume\n+optional",
+		"sourceRef":          "SourceRef is an indirect reference to the source of data for the requested DataVolume\n+optional",
+		"pvc":                "PVC is the PVC specification",
+		"storage":            "Storage is the requested storage specification",
+		"priorityClassName":  "PriorityClassName for Importer, Cloner and Uploader pod",
+		"contentType":        "DataVolumeContentType options: \"kubevirt\", \"archive\"\n+kubebuilder:validation:Enum=\"kubevirt\";\"archive\"",
+		"checkpoints":        "Checkpoints is a list of DataVolumeCheckpoints, representing stages in a multistage import.",
+		"finalCheckpoint":    "FinalCheckpoint indicates whether the current DataVolumeCheckpoint is the final checkpoint.",
+		"preallocation":      "Preallocation controls whether storage for DataVolumes should be allocated in advance.",
+		"snapshotTarget":     "SnapshotTarget makes a VolumeSnapshot of the imported PVC the final artifact of the DataVolume\n+optional",
+		"workerPodPlacement": "WorkerPodPlacement is the node selector and tolerations of the importer, clone and upload pods of the DataVolume. Unset fields default to the ones of the CDIConfig.\n+optional",
+		"workerPodImage":     "WorkerPodImage is the image registry and pull policy of the importer, clone and upload pods of the DataVolume. Unset fields default to the ones of the CDIConfig.\n+optional",
+	}
+}
+
+func (WorkerPodImage) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":           "WorkerPodImage overrides the image registry and pull policy of the worker pods populating a DataVolume",
+		"registry":   "Registry replaces the registry and repository of the importer, cloner and upload server images, keeping their name, tag and digest, for instance to pull them from a mirror in an air-gapped cluster\n+optional",
+		"pullPolicy": "PullPolicy is the image pull policy of the worker pods, overriding the one of the CDI CR\n+optional\n+kubebuilder:validation:Enum=Always;IfNotPresent;Never",
+	}
+}
+
+func (WorkerPodPlacement) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":             "WorkerPodPlacement is the node selector and tolerations of the worker pods populating a DataVolume",
+		"nodeSelector": "NodeSelector is the node selector of the worker pods, merged with the node selector of the workload node placement of the CDI CR\n+optional",
+		"tolerations":  "Tolerations are the tolerations of the worker pods, in addition to the tolerations of the workload node placement of the CDI CR\n+optional",
 	}
 }
 
@@ -46,6 +65,15 @@ func (DataVolumeCheckpoint) SwaggerDoc() map[string]string {
 	}
 }
 
+func (DataVolumeSnapshotTarget) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                        "DataVolumeSnapshotTarget defines the VolumeSnapshot a DataVolume imports into",
+		"name":                    "Name is the name of the VolumeSnapshot, the name of the DataVolume if empty\n+optional",
+		"volumeSnapshotClassName": "VolumeSnapshotClassName is the class of the VolumeSnapshot. If empty, a class of the provisioner of the\nstorage class of the PVC is used\n+optional",
+		"pvcRetainPolicy":         "PVCRetainPolicy tells whether the PVC is deleted once the VolumeSnapshot is ready, Delete if empty\n+kubebuilder:validation:Enum=\"Delete\";\"Retain\"\n+optional",
+	}
+}
+
 func (DataVolumeSource) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"": "DataVolumeSource represents the source for our Data Volume, this can be HTTP, Imageio, S3, GCS, Registry or an existing PVC",
@@ -80,20 +108,94 @@ func (DataVolumeSourceUpload) SwaggerDoc() map[string]string {
 	}
 }
 
+func (DataVolumeSourceInline) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":     "DataVolumeSourceInline provides the parameters to create a Data Volume from a small image embedded in the DataVolume",
+		"data": "Data is the base64 encoded image, optionally gzip compressed, of at most 1MiB once encoded",
+	}
+}
+
+func (DataVolumeSourceNBD) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":          "DataVolumeSourceNBD provides the parameters to create a Data Volume from a disk exported over NBD",
+		"url":       "URL is the url of the NBD export, as in nbd://host[:port]/export, or nbds://host[:port]/export for NBD over TLS",
+		"secretRef": "SecretRef provides the secret holding the ca-cert.pem of the NBD server CA, and optionally the client-cert.pem and\nclient-key.pem of a client certificate, required for NBD over TLS\n+optional",
+	}
+}
+
+func (DataVolumeSourceRemote) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":              "DataVolumeSourceRemote provides the parameters to clone a Data Volume from a disk exported by a remote cluster",
+		"url":           "URL is the url of the disk image exported by the remote cluster",
+		"secretRef":     "SecretRef provides the secret holding the token authorizing the export, in its token key",
+		"certConfigMap": "CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key of the remote endpoint\n+optional",
+	}
+}
+
+func (DataVolumeSourceFTP) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":              "DataVolumeSourceFTP provides the parameters to create a Data Volume from an FTP or FTPS source",
+		"url":           "URL is the url of the image on the FTP server, as in ftp://host[:port]/path, or ftps://host[:port]/path for FTP\nover explicit TLS",
+		"secretRef":     "SecretRef provides the secret holding the user name and password of the FTP server in its accessKeyId and\nsecretKey keys, the login is anonymous without it\n+optional",
+		"certConfigMap": "CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key of the FTPS server\n+optional",
+	}
+}
+
+func (DataVolumeSourceRemotePVC) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                    "DataVolumeSourceRemotePVC provides the parameters to clone a Data Volume from a PVC of a remote cluster",
+		"kubeconfigSecretRef": "KubeconfigSecretRef provides the secret holding the kubeconfig of the remote cluster, in its kubeconfig key",
+		"namespace":           "The namespace of the source PVC in the remote cluster",
+		"name":                "The name of the source PVC in the remote cluster",
+	}
+}
+
+func (DataVolumeSourceSFTP) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":          "DataVolumeSourceSFTP provides the parameters to create a Data Volume from an SFTP source",
+		"url":       "URL is the url of the image on the SFTP server, as in sftp://host[:port]/path, the path is relative to the home\ndirectory of the user if it starts with /~/",
+		"secretRef": "SecretRef provides the secret holding the user name in its username key, and its private key in the\nssh-privatekey key, with the passphrase of an encrypted key in the passphrase key, or its password in the password\nkey",
+		"hostKey":   "HostKey is the public host key the SFTP server must present, in the authorized_keys format, as in\n\"ssh-ed25519 AAAA...\", or its SHA256 fingerprint, as in \"SHA256:...\", printed by ssh-keygen -l",
+	}
+}
+
+func (DataVolumeSourceAzure) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":              "DataVolumeSourceAzure provides the parameters to create a Data Volume from an Azure Blob Storage source",
+		"url":           "URL is the url of the blob, as in https://<account>.blob.core.windows.net/<container>/<blob>, without a SAS\ntoken",
+		"secretRef":     "SecretRef provides the secret holding a SAS token in its sasToken key, or the tenantId, clientId and clientSecret\nof a service principal, the blob is read anonymously without it\n+optional",
+		"certConfigMap": "CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key of the Blob service\n+optional",
+	}
+}
+
 func (DataVolumeSourceS3) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":              "DataVolumeSourceS3 provides the parameters to create a Data Volume from an S3 source",
-		"url":           "URL is the url of the S3 source",
-		"secretRef":     "SecretRef provides the secret reference needed to access the S3 source",
-		"certConfigMap": "CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key, and a base64 encoded pem certificate\n+optional",
+		"":                 "DataVolumeSourceS3 provides the parameters to create a Data Volume from an S3 source",
+		"url":              "URL is the url of the S3 source",
+		"secretRef":        "SecretRef provides the secret reference needed to access the S3 source",
+		"certConfigMap":    "CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key, and a base64 encoded pem certificate\n+optional",
+		"tokenCredentials": "TokenCredentials gets the credentials by assuming an IAM role with a projected service account token (IRSA), instead of the SecretRef keys\n+optional",
+		"kmsKeyId":         "KMSKeyID is the ID or ARN of the AWS KMS key the object is encrypted with (SSE-KMS), the import fails if the object is not encrypted with this key\n+optional",
 	}
 }
 
 func (DataVolumeSourceGCS) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":          "DataVolumeSourceGCS provides the parameters to create a Data Volume from an GCS source",
-		"url":       "URL is the url of the GCS source",
-		"secretRef": "SecretRef provides the secret reference needed to access the GCS source",
+		"":                 "DataVolumeSourceGCS provides the parameters to create a Data Volume from an GCS source",
+		"url":              "URL is the url of the GCS source",
+		"secretRef":        "SecretRef provides the secret reference needed to access the GCS source",
+		"tokenCredentials": "TokenCredentials gets the credentials through workload identity federation with a projected service account token, instead of the SecretRef key file\n+optional",
+		"userProject":      "UserProject is the project billed for the requests to a requester-pays bucket, required to read from such a bucket\n+optional",
+		"generation":       "Generation pins the import to this generation of the object, the import fails if the generation does not exist. By default\nthe import reads the latest generation, pinned for the whole download so a resumed download does not mix two generations\n+optional",
+	}
+}
+
+func (DataVolumeSourceTokenCredentials) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":               "DataVolumeSourceTokenCredentials provides the parameters to get the credentials of an S3 or GCS source by exchanging\na service account token projected in the importer pod",
+		"audience":       "Audience is the audience of the projected token. For S3 it defaults to sts.amazonaws.com, for GCS it is required and is\nthe workload identity pool provider, as in //iam.googleapis.com/projects/<number>/locations/global/workloadIdentityPools/<pool>/providers/<provider>\n+optional",
+		"roleARN":        "RoleARN is the ARN of the IAM role assumed with the token, required for S3\n+optional",
+		"serviceAccount": "ServiceAccount is the email of the GCP service account impersonated with the federated token, optional for GCS\n+optional",
 	}
 }
 
@@ -105,6 +207,16 @@ func (DataVolumeSourceRegistry) SwaggerDoc() map[string]string {
 		"pullMethod":    "PullMethod can be either \"pod\" (default import), or \"node\" (node docker cache based import)\n+optional",
 		"secretRef":     "SecretRef provides the secret reference needed to access the Registry source\n+optional",
 		"certConfigMap": "CertConfigMap provides a reference to the Registry certs\n+optional",
+		"disks":         "Disks lists the disks of an image holding several of them, each imported into its own PVC\n+optional",
+	}
+}
+
+func (RegistryDisk) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":        "RegistryDisk selects a disk of a multi-disk registry image, and the PVC it is imported into",
+		"path":    "Path is the path of the disk in the image, relative to its disk directory",
+		"pvcName": "PVCName is the name of the PVC the disk is imported into, the PVC of the DataVolume when empty\n+optional",
+		"size":    "Size is the size requested for the PVC of the disk, the size of the DataVolume when not set. The disk imported into the PVC of the DataVolume gets the size of the DataVolume.\n+optional",
 	}
 }
 
@@ -152,10 +264,34 @@ func (DataVolumeSourceRef) SwaggerDoc() map[string]string {
 
 func (DataVolumeStatus) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":             "DataVolumeStatus contains the current status of the DataVolume",
-		"claimName":    "ClaimName is the name of the underlying PVC used by the DataVolume.",
-		"phase":        "Phase is the current phase of the data volume",
-		"restartCount": "RestartCount is the number of times the pod populating the DataVolume has restarted",
+		"":                   "DataVolumeStatus contains the current status of the DataVolume",
+		"claimName":          "ClaimName is the name of the underlying PVC used by the DataVolume.",
+		"phase":              "Phase is the current phase of the data volume",
+		"restartCount":       "RestartCount is the number of times the pod populating the DataVolume has restarted",
+		"importTimings":      "ImportTimings is the time the importer spent in the phases of the import",
+		"importPassthrough":  "ImportPassthrough tells the importer copied the source image as is, without conversion, as it already had the target format",
+		"importSourceDigest": "ImportSourceDigest is the digest of the source bytes the importer read, as <algorithm>:<hex>, when requested with the storage.import.sourceDigestAlgorithm annotation",
+		"pausedSince":        "PausedSince is the time the DataVolume entered the Paused phase of a multi-stage import, the time it stays paused does not count toward its completion timeout",
+		"pausedSeconds":      "PausedSeconds is the time in seconds the DataVolume previously spent in the Paused phase, not counted toward its completion timeout",
+		"disks":              "Disks reports the import of each disk of a multi-disk registry image",
+	}
+}
+
+func (DataVolumeDiskStatus) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":          "DataVolumeDiskStatus reports the import of a disk of a multi-disk registry image",
+		"path":      "Path is the path of the disk in the image",
+		"claimName": "ClaimName is the name of the PVC the disk is imported into",
+		"phase":     "Phase is the current phase of the import of the disk",
+	}
+}
+
+func (DataVolumeImportTimings) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                "DataVolumeImportTimings holds the time the importer spent in the phases of the import, in whole seconds. A phase\nthat was skipped or took less than a second is omitted. The decompression is part of the download, and the\npreallocation is part of the conversion and the resize.",
+		"downloadSeconds": "DownloadSeconds is the time spent transferring the source to the scratch space or the target volume",
+		"convertSeconds":  "ConvertSeconds is the time spent converting the image to the target volume",
+		"resizeSeconds":   "ResizeSeconds is the time spent resizing the image to the requested size",
 	}
 }
 
@@ -180,19 +316,29 @@ func (StorageProfile) SwaggerDoc() map[string]string {
 
 func (StorageProfileSpec) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":                  "StorageProfileSpec defines specification for StorageProfile",
-		"cloneStrategy":     "CloneStrategy defines the preferred method for performing a CDI clone",
-		"claimPropertySets": "ClaimPropertySets is a provided set of properties applicable to PVC",
+		"":                          "StorageProfileSpec defines specification for StorageProfile",
+		"cloneStrategy":             "CloneStrategy defines the preferred method for performing a CDI clone",
+		"claimPropertySets":         "ClaimPropertySets is a provided set of properties applicable to PVC",
+		"preallocation":             "Preallocation is the recommended preallocation setting for DataVolumes which don't set their own",
+		"thinProvisioned":           "ThinProvisioned marks the storage of the class as thin provisioned, DataVolumes which don't set their own preallocation are then not preallocated unless Preallocation is set",
+		"filesystemOverhead":        "FilesystemOverhead is the recommended filesystem overhead for Filesystem volumes of the storage class",
+		"cloneStreams":              "CloneStreams is the number of parallel streams of the host-assisted clones of block disks into the storage class\n+kubebuilder:validation:Minimum=1\n+kubebuilder:validation:Maximum=16",
+		"crossClassSnapshotRestore": "CrossClassSnapshotRestore marks the CSI driver of the storage class as able to restore the snapshots of the volumes of its other storage classes into this one, so snapshot clones from them are not host-assisted",
 	}
 }
 
 func (StorageProfileStatus) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":                  "StorageProfileStatus provides the most recently observed status of the StorageProfile",
-		"storageClass":      "The StorageClass name for which capabilities are defined",
-		"provisioner":       "The Storage class provisioner plugin name",
-		"cloneStrategy":     "CloneStrategy defines the preferred method for performing a CDI clone",
-		"claimPropertySets": "ClaimPropertySets computed from the spec and detected in the system",
+		"":                          "StorageProfileStatus provides the most recently observed status of the StorageProfile",
+		"storageClass":              "The StorageClass name for which capabilities are defined",
+		"provisioner":               "The Storage class provisioner plugin name",
+		"cloneStrategy":             "CloneStrategy defines the preferred method for performing a CDI clone",
+		"claimPropertySets":         "ClaimPropertySets computed from the spec and detected in the system",
+		"preallocation":             "Preallocation is the recommended preallocation setting for DataVolumes which don't set their own",
+		"thinProvisioned":           "ThinProvisioned marks the storage of the class as thin provisioned, DataVolumes which don't set their own preallocation are then not preallocated unless Preallocation is set",
+		"filesystemOverhead":        "FilesystemOverhead is the recommended filesystem overhead for Filesystem volumes of the storage class",
+		"cloneStreams":              "CloneStreams is the number of parallel streams of the host-assisted clones of block disks into the storage class",
+		"crossClassSnapshotRestore": "CrossClassSnapshotRestore marks the CSI driver of the storage class as able to restore the snapshots of the volumes of its other storage classes into this one, so snapshot clones from them are not host-assisted",
 	}
 }
 
@@ -258,6 +404,44 @@ func (DataSourceList) SwaggerDoc() map[string]string {
 	}
 }
 
+func (CloneGrant) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"": "CloneGrant pre-approves the cross-namespace clones of the PVCs and VolumeSnapshots of its namespace\n+genclient\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object\n+kubebuilder:object:root=true\n+kubebuilder:storageversion\n+kubebuilder:resource:shortName=cg;cgs,categories=all",
+	}
+}
+
+func (CloneGrantSpec) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                 "CloneGrantSpec defines the clones pre-approved by the CloneGrant",
+		"sources":          "Sources are the PVCs and VolumeSnapshots of the namespace the grant applies to, all of them when empty\n+optional",
+		"targetNamespaces": "TargetNamespaces are the namespaces any user may clone the sources to\n+optional",
+		"serviceAccounts":  "ServiceAccounts are the ServiceAccounts that may clone the sources to their namespace\n+optional",
+	}
+}
+
+func (CloneGrantSource) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":     "CloneGrantSource is a PVC or VolumeSnapshot granted by a CloneGrant",
+		"kind": "Kind is the kind of the source, PersistentVolumeClaim or VolumeSnapshot\n+kubebuilder:validation:Enum=PersistentVolumeClaim;VolumeSnapshot",
+		"name": "Name is the name of the source",
+	}
+}
+
+func (CloneGrantServiceAccount) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":          "CloneGrantServiceAccount is a ServiceAccount granted by a CloneGrant",
+		"namespace": "Namespace is the namespace of the ServiceAccount",
+		"name":      "Name is the name of the ServiceAccount",
+	}
+}
+
+func (CloneGrantList) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":      "CloneGrantList provides the needed parameters to do request a list of CloneGrants from the system\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object",
+		"items": "Items provides a list of CloneGrants",
+	}
+}
+
 func (DataImportCron) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"": "DataImportCron defines a cron job for recurring polling/importing disk images as PVCs into a golden image namespace\n+genclient\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object\n+kubebuilder:object:root=true\n+kubebuilder:storageversion\n+kubebuilder:resource:shortName=dic;dics,categories=all",
@@ -273,6 +457,7 @@ func (DataImportCronSpec) SwaggerDoc() map[string]string {
 		"importsToKeep":     "Number of import PVCs to keep when garbage collecting. Default is 3.\n+optional",
 		"managedDataSource": "ManagedDataSource specifies the name of the corresponding DataSource this cron will manage.\nDataSource has to be in the same namespace.",
 		"retentionPolicy":   "RetentionPolicy specifies whether the created DataVolumes and DataSources are retained when their DataImportCron is deleted. Default is RatainAll.\n+optional",
+		"sourceTagPattern":  "SourceTagPattern is a regular expression selecting the tags of the registry URL source repository to import.\nThe matching tag with the highest semantic version is imported, with an optional \"v\" prefix.\nTags that do not match or are not semantic versions are ignored, as is the tag of the source URL.\n+optional",
 	}
 }
 
@@ -373,18 +558,36 @@ func (FilesystemOverhead) SwaggerDoc() map[string]string {
 
 func (CDIConfigSpec) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":                         "CDIConfigSpec defines specification for user configuration",
-		"uploadProxyURLOverride":   "Override the URL used when uploading to a DataVolume",
-		"importProxy":              "ImportProxy contains importer pod proxy configuration.\n+optional",
-		"scratchSpaceStorageClass": "Override the storage class to used for scratch space during transfer operations. The scratch space storage class is determined in the following order: 1. value of scratchSpaceStorageClass, if that doesn't exist, use the default storage class, if there is no default storage class, use the storage class of the DataVolume, if no storage class specified, use no storage class for scratch space",
-		"podResourceRequirements":  "ResourceRequirements describes the compute resource requirements.",
-		"featureGates":             "FeatureGates are a list of specific enabled feature gates",
-		"filesystemOverhead":       "FilesystemOverhead describes the space reserved for overhead when using Filesystem volumes. A value is between 0 and 1, if not defined it is 0.055 (5.5% overhead)",
-		"preallocation":            "Preallocation controls whether storage for DataVolumes should be allocated in advance.",
-		"insecureRegistries":       "InsecureRegistries is a list of TLS disabled registries",
-		"dataVolumeTTLSeconds":     "DataVolumeTTLSeconds is the time in seconds after DataVolume completion it can be garbage collected. The default is 0 sec. To disable GC use -1.\n+optional",
-		"tlsSecurityProfile":       "TLSSecurityProfile is used by operators to apply cluster-wide TLS security settings to operands.",
-		"imagePullSecrets":         "The imagePullSecrets used to pull the container images",
+		"":                            "CDIConfigSpec defines specification for user configuration",
+		"uploadProxyURLOverride":      "Override the URL used when uploading to a DataVolume",
+		"importProxy":                 "ImportProxy contains importer pod proxy configuration.\n+optional",
+		"scratchSpaceStorageClass":    "Override the storage class to used for scratch space during transfer operations. The scratch space storage class is determined in the following order: 1. value of scratchSpaceStorageClass, if that doesn't exist, use the default storage class, if there is no default storage class, use the storage class of the DataVolume, if no storage class specified, use no storage class for scratch space",
+		"podResourceRequirements":     "ResourceRequirements describes the compute resource requirements.",
+		"featureGates":                "FeatureGates are a list of specific enabled feature gates",
+		"filesystemOverhead":          "FilesystemOverhead describes the space reserved for overhead when using Filesystem volumes. A value is between 0 and 1, if not defined it is 0.055 (5.5% overhead)",
+		"preallocation":               "Preallocation controls whether storage for DataVolumes should be allocated in advance.",
+		"insecureRegistries":          "InsecureRegistries is a list of TLS disabled registries",
+		"dataVolumeTTLSeconds":        "DataVolumeTTLSeconds is the time in seconds after DataVolume completion it can be garbage collected. The default is 0 sec. To disable GC use -1.\n+optional",
+		"tlsSecurityProfile":          "TLSSecurityProfile is used by operators to apply cluster-wide TLS security settings to operands.",
+		"imagePullSecrets":            "The imagePullSecrets used to pull the container images",
+		"importMaxAttempts":           "ImportMaxAttempts is the number of failed attempts after which an import DataVolume fails. Unset means the import is retried indefinitely.\n+optional",
+		"podIOLimits":                 "PodIOLimits are the disk IO limits of the importer and clone source pods, applied through the cgroup of the pod where the container runtime supports it.\n+optional",
+		"cloneAnnotationAllowlist":    "CloneAnnotationAllowlist is the list of annotations copied from the source to the target PVC of a host-assisted clone, in addition to the image format and virtual size recorded by CDI. Other annotations of the source are not copied.\n+optional",
+		"maxParallelWorkerPods":       "MaxParallelWorkerPods is the maximum number of import, upload and host-assisted clone worker pods CDI runs simultaneously, the DataVolumes beyond it wait in creation order. Unset means no limit.\n+optional",
+		"importTLSSecurityProfile":    "ImportTLSSecurityProfile is the TLS security profile of the importer clients connecting to the HTTP, S3 and ImageIO import sources. The default is the intermediate profile, TLS 1.2 and above. A DataVolume can override the minimal version and the ciphers of its source with annotations.\n+optional",
+		"scratchSpace":                "ScratchSpace configures the volume backing the scratch space of the importer pods. The default is a PVC.\n+optional",
+		"dataImportCronPolling":       "DataImportCronPolling configures how the CDI controller polls the sources of the DataImportCrons.\n+optional",
+		"workerPodPlacement":          "WorkerPodPlacement is the default node selector and tolerations of the importer, clone and upload pods of the DataVolumes not setting them.\n+optional",
+		"allowedWorkerPodPlacement":   "AllowedWorkerPodPlacement is the node selector labels and tolerations the DataVolumes are allowed to set in their workerPodPlacement. Unset means the DataVolumes cannot set a worker pod placement.\n+optional",
+		"workerPodImage":              "WorkerPodImage is the default image registry and pull policy of the importer, clone and upload pods of the DataVolumes not setting them.\n+optional",
+		"allowedWorkerPodRegistries":  "AllowedWorkerPodRegistries are the image registries the DataVolumes are allowed to set in their workerPodImage. Unset means the DataVolumes cannot set a worker pod image registry.\n+optional",
+		"dataVolumeCompletionTimeout": "DataVolumeCompletionTimeout is the time from its creation within which a DataVolume must succeed, not counting the time it is paused, after which it fails. Unset means no timeout.\n+optional",
+		"completionHooks":             "CompletionHooks are the HTTP endpoints notified once the DataVolumes naming them in their cdi.kubevirt.io/storage.completionHook annotation succeeded. Unset means the DataVolumes cannot notify any endpoint.\n+optional",
+		"clusterDelegatedAuthorizer":  "ClusterDelegatedAuthorizer is an external HTTP authorization webhook deciding the cross-namespace clones, instead of or in addition to the built-in SubjectAccessReview checks.\n+optional",
+		"cloneAuthorization":          "CloneAuthorization overrides or extends the resource attributes of the SubjectAccessReviews allowing the cross-namespace clones, and configures the cache of their decisions\n+optional",
+		"cloneBandwidthLimit":         "CloneBandwidthLimit is the default network bandwidth limit of the host-assisted clones, in bytes per second, enforced by the clone source pod streaming to the target. A DataVolume overrides it with its cdi.kubevirt.io/storage.clone.bandwidthLimit annotation. Unset or 0 means no limit.\n+optional",
+		"cloneStreams":                "CloneStreams is the default number of parallel streams of the host-assisted clones of block disks, each streaming a range of the disk over its own connection. The StorageProfile of the target storage class overrides it. Unset means a single stream.\n+kubebuilder:validation:Minimum=1\n+kubebuilder:validation:Maximum=16\n+optional",
+		"cloneCompression":            "CloneCompression is the default compression codec of the stream of the host-assisted clones, negotiated by the clone source with the target, which falls back to snappy if the target does not support it. A DataVolume overrides it with its cdi.kubevirt.io/storage.clone.compression annotation. Unset means snappy.\n+kubebuilder:validation:Enum=\"none\";\"gzip\";\"snappy\";\"zstd\"\n+optional",
 	}
 }
 
@@ -408,12 +611,78 @@ func (CDIConfigList) SwaggerDoc() map[string]string {
 	}
 }
 
+func (PodIOLimits) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                  "PodIOLimits defines the disk IO limits of a CDI worker pod on its volume",
+		"blockIOClass":      "BlockIOClass is the blockio class of the pods, set with the blockio.resources.beta.kubernetes.io/pod annotation. The container runtime applies the IO limits the class is configured with on the nodes, also when the pod can't write its own cgroup.\n+optional",
+		"maxBytesPerSecond": "MaxBytesPerSecond is the maximum read and write throughput of the pod on its volume, in bytes per second.\n+optional",
+		"maxIOPS":           "MaxIOPS is the maximum number of read and write IO operations per second of the pod on its volume.\n+optional",
+		"weight":            "Weight is the proportional disk IO weight of the pod, between 1 and 10000, the cgroup v2 io.weight. It is scaled to the 10 to 1000 range of the cgroup v1 blkio.weight.\n+optional",
+	}
+}
+
+func (ScratchSpaceConfig) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                "ScratchSpaceConfig defines the volume backing the scratch space of the importer pods",
+		"backend":         "Backend is the kind of volume backing the scratch space, PVC, EmptyDirDisk or EmptyDirMemory. The default is PVC. A DataVolume can override it with an annotation.\n+kubebuilder:validation:Enum=PVC;EmptyDirDisk;EmptyDirMemory\n+optional",
+		"maxEmptyDirSize": "MaxEmptyDirSize is the largest scratch space backed by an emptyDir, a larger scratch space is backed by a PVC. Unset means no limit.\n+optional",
+	}
+}
+
+func (DataImportCronPollingConfig) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                       "DataImportCronPollingConfig defines how the CDI controller polls the sources of the DataImportCrons",
+		"parallelism":            "Parallelism is the maximum number of DataImportCron sources polled concurrently, at most 16. The default is 1. The polls of the same DataImportCron never overlap.\n+optional",
+		"registryPollsPerMinute": "RegistryPollsPerMinute is the maximum number of polls per minute of the same registry. Unset means no limit.\n+optional",
+	}
+}
+
+func (ClusterDelegatedAuthorizer) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":               "ClusterDelegatedAuthorizer defines an external HTTP authorization webhook deciding the cross-namespace clones, compatible with the data API of Open Policy Agent",
+		"url":            "URL is the http or https URL the clone authorization requests are POSTed to, such as the data API URL of an Open Policy Agent policy. The request body holds the clone in its input field, and the response body tells whether it is allowed in its result field, either a boolean or an object with allowed and reason fields.",
+		"mode":           "Mode tells whether the webhook decides the cross-namespace clones in addition to the built-in SubjectAccessReview checks, both having to allow a clone, or instead of them. The default is Additional.\n+kubebuilder:validation:Enum=Additional;Replace\n+optional",
+		"caBundle":       "CABundle is the PEM encoded CA bundle verifying the certificate of an https webhook. The system trust roots are used when empty.\n+optional",
+		"timeoutSeconds": "TimeoutSeconds is the timeout of a webhook request, after which the clone is denied. The default is 10 seconds.\n+optional",
+	}
+}
+
+func (CompletionHook) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":            "CompletionHook defines an HTTP endpoint notified once a DataVolume naming it succeeded",
+		"name":        "Name is the name the DataVolumes give to the hook in their cdi.kubevirt.io/storage.completionHook annotation",
+		"url":         "URL is the http or https URL receiving a POST with a JSON body once a DataVolume naming the hook succeeded",
+		"tokenSecret": "TokenSecret is the name of the Secret of the CDI namespace holding the bearer token of the POST in its token key\n+optional",
+	}
+}
+
+func (CloneAuthorizationConfig) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                   "CloneAuthorizationConfig defines the resource attributes of the SubjectAccessReviews allowing the cross-namespace clones, and the cache of their decisions",
+		"resourceAttributes": "ResourceAttributes are the verbs on resources of the clone source namespace allowing a user or ServiceAccount to clone from it, any of them allowing the clone. The namespace and name of the reviewed resource are those of the clone source.\n+optional",
+		"mode":               "Mode tells whether ResourceAttributes extend the built-in create datavolumes/source and create pods checks, or override them. The default is Extend.\n+kubebuilder:validation:Enum=Extend;Override\n+optional",
+		"cacheTTL":           "CacheTTL is the time the decisions of the SubjectAccessReviews allowing the cross-namespace clones are cached by the CDI API server, such as 30s. Unset or zero disables the cache.\n+optional",
+		"auditLog":           "AuditLog writes the decisions of the cross-namespace clone authorizations as JSON lines to the log of the CDI API server, in addition to the Events recorded on the clone sources.\n+optional",
+	}
+}
+
+func (CloneResourceAttributes) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":            "CloneResourceAttributes defines a verb on a resource of the clone source namespace allowing to clone from it",
+		"verb":        "Verb is the verb allowed on the resource, such as create or a dedicated clone verb",
+		"group":       "Group is the API group of the resource, empty for the core group\n+optional",
+		"resource":    "Resource is the resource, such as datavolumes or a custom resource of an aggregated role",
+		"subresource": "Subresource is the subresource, such as source\n+optional",
+	}
+}
+
 func (ImportProxy) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":               "ImportProxy provides the information on how to configure the importer pod proxy.",
-		"HTTPProxy":      "HTTPProxy is the URL http://<username>:<pswd>@<ip>:<port> of the import proxy for HTTP requests.  Empty means unset and will not result in the import pod env var.\n+optional",
-		"HTTPSProxy":     "HTTPSProxy is the URL https://<username>:<pswd>@<ip>:<port> of the import proxy for HTTPS requests.  Empty means unset and will not result in the import pod env var.\n+optional",
-		"noProxy":        "NoProxy is a comma-separated list of hostnames and/or CIDRs for which the proxy should not be used. Empty means unset and will not result in the import pod env var.\n+optional",
-		"trustedCAProxy": "TrustedCAProxy is the name of a ConfigMap in the cdi namespace that contains a user-provided trusted certificate authority (CA) bundle.\nThe TrustedCAProxy ConfigMap is consumed by the DataImportCron controller for creating cronjobs, and by the import controller referring a copy of the ConfigMap in the import namespace.\nHere is an example of the ConfigMap (in yaml):\n\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: my-ca-proxy-cm\n  namespace: cdi\ndata:\n  ca.pem: |",
+		"":                "ImportProxy provides the information on how to configure the importer pod proxy.",
+		"HTTPProxy":       "HTTPProxy is the URL http://<username>:<pswd>@<ip>:<port> of the import proxy for HTTP requests.  Empty means unset and will not result in the import pod env var.\n+optional",
+		"HTTPSProxy":      "HTTPSProxy is the URL https://<username>:<pswd>@<ip>:<port> of the import proxy for HTTPS requests.  Empty means unset and will not result in the import pod env var.\n+optional",
+		"noProxy":         "NoProxy is a comma-separated list of hostnames and/or CIDRs for which the proxy should not be used. Empty means unset and will not result in the import pod env var.\n+optional",
+		"trustedCAProxy":  "TrustedCAProxy is the name of a ConfigMap in the cdi namespace that contains a user-provided trusted certificate authority (CA) bundle.\nThe TrustedCAProxy ConfigMap is consumed by the DataImportCron controller for creating cronjobs, and by the import controller referring a copy of the ConfigMap in the import namespace.\nHere is an example of the ConfigMap (in yaml):\n\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: my-ca-proxy-cm\n  namespace: cdi\ndata:\n  ca.pem: |",
+		"proxyAuthSecret": "ProxyAuthSecret is the name of a Secret in the cdi namespace that contains the username and password keys used to authenticate against the import proxy.\nThe credentials in the Secret take precedence over the ones in the proxy URLs.\n+optional",
 	}
 }
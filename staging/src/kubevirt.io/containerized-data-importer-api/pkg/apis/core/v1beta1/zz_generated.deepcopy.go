@@ -201,6 +201,96 @@ func (in *CDIConfigSpec) DeepCopyInto(out *CDIConfigSpec) {
 		*out = make([]v1.LocalObjectReference, len(*in))
 		copy(*out, *in)
 	}
+	if in.ImportMaxAttempts != nil {
+		in, out := &in.ImportMaxAttempts, &out.ImportMaxAttempts
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PodIOLimits != nil {
+		in, out := &in.PodIOLimits, &out.PodIOLimits
+		*out = new(PodIOLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxParallelWorkerPods != nil {
+		in, out := &in.MaxParallelWorkerPods, &out.MaxParallelWorkerPods
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CloneAnnotationAllowlist != nil {
+		in, out := &in.CloneAnnotationAllowlist, &out.CloneAnnotationAllowlist
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImportTLSSecurityProfile != nil {
+		in, out := &in.ImportTLSSecurityProfile, &out.ImportTLSSecurityProfile
+		*out = new(configv1.TLSSecurityProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScratchSpace != nil {
+		in, out := &in.ScratchSpace, &out.ScratchSpace
+		*out = new(ScratchSpaceConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DataImportCronPolling != nil {
+		in, out := &in.DataImportCronPolling, &out.DataImportCronPolling
+		*out = new(DataImportCronPollingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WorkerPodPlacement != nil {
+		in, out := &in.WorkerPodPlacement, &out.WorkerPodPlacement
+		*out = new(WorkerPodPlacement)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowedWorkerPodPlacement != nil {
+		in, out := &in.AllowedWorkerPodPlacement, &out.AllowedWorkerPodPlacement
+		*out = new(WorkerPodPlacement)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WorkerPodImage != nil {
+		in, out := &in.WorkerPodImage, &out.WorkerPodImage
+		*out = new(WorkerPodImage)
+		**out = **in
+	}
+	if in.AllowedWorkerPodRegistries != nil {
+		in, out := &in.AllowedWorkerPodRegistries, &out.AllowedWorkerPodRegistries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DataVolumeCompletionTimeout != nil {
+		in, out := &in.DataVolumeCompletionTimeout, &out.DataVolumeCompletionTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.CompletionHooks != nil {
+		in, out := &in.CompletionHooks, &out.CompletionHooks
+		*out = make([]CompletionHook, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterDelegatedAuthorizer != nil {
+		in, out := &in.ClusterDelegatedAuthorizer, &out.ClusterDelegatedAuthorizer
+		*out = new(ClusterDelegatedAuthorizer)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CloneAuthorization != nil {
+		in, out := &in.CloneAuthorization, &out.CloneAuthorization
+		*out = new(CloneAuthorizationConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CloneBandwidthLimit != nil {
+		in, out := &in.CloneBandwidthLimit, &out.CloneBandwidthLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.CloneStreams != nil {
+		in, out := &in.CloneStreams, &out.CloneStreams
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CloneCompression != nil {
+		in, out := &in.CloneCompression, &out.CloneCompression
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -400,6 +490,213 @@ func (in *ClaimPropertySet) DeepCopy() *ClaimPropertySet {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneAuthorizationConfig) DeepCopyInto(out *CloneAuthorizationConfig) {
+	*out = *in
+	if in.ResourceAttributes != nil {
+		in, out := &in.ResourceAttributes, &out.ResourceAttributes
+		*out = make([]CloneResourceAttributes, len(*in))
+		copy(*out, *in)
+	}
+	if in.CacheTTL != nil {
+		in, out := &in.CacheTTL, &out.CacheTTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneAuthorizationConfig.
+func (in *CloneAuthorizationConfig) DeepCopy() *CloneAuthorizationConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneAuthorizationConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneGrant) DeepCopyInto(out *CloneGrant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneGrant.
+func (in *CloneGrant) DeepCopy() *CloneGrant {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneGrant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CloneGrant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneGrantList) DeepCopyInto(out *CloneGrantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CloneGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneGrantList.
+func (in *CloneGrantList) DeepCopy() *CloneGrantList {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneGrantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CloneGrantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneGrantServiceAccount) DeepCopyInto(out *CloneGrantServiceAccount) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneGrantServiceAccount.
+func (in *CloneGrantServiceAccount) DeepCopy() *CloneGrantServiceAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneGrantServiceAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneGrantSource) DeepCopyInto(out *CloneGrantSource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneGrantSource.
+func (in *CloneGrantSource) DeepCopy() *CloneGrantSource {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneGrantSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneGrantSpec) DeepCopyInto(out *CloneGrantSpec) {
+	*out = *in
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]CloneGrantSource, len(*in))
+		copy(*out, *in)
+	}
+	if in.TargetNamespaces != nil {
+		in, out := &in.TargetNamespaces, &out.TargetNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceAccounts != nil {
+		in, out := &in.ServiceAccounts, &out.ServiceAccounts
+		*out = make([]CloneGrantServiceAccount, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneGrantSpec.
+func (in *CloneGrantSpec) DeepCopy() *CloneGrantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneGrantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneResourceAttributes) DeepCopyInto(out *CloneResourceAttributes) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneResourceAttributes.
+func (in *CloneResourceAttributes) DeepCopy() *CloneResourceAttributes {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneResourceAttributes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDelegatedAuthorizer) DeepCopyInto(out *ClusterDelegatedAuthorizer) {
+	*out = *in
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDelegatedAuthorizer.
+func (in *ClusterDelegatedAuthorizer) DeepCopy() *ClusterDelegatedAuthorizer {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDelegatedAuthorizer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompletionHook) DeepCopyInto(out *CompletionHook) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompletionHook.
+func (in *CompletionHook) DeepCopy() *CompletionHook {
+	if in == nil {
+		return nil
+	}
+	out := new(CompletionHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConditionState) DeepCopyInto(out *ConditionState) {
 	*out = *in
@@ -496,6 +793,32 @@ func (in *DataImportCronList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataImportCronPollingConfig) DeepCopyInto(out *DataImportCronPollingConfig) {
+	*out = *in
+	if in.Parallelism != nil {
+		in, out := &in.Parallelism, &out.Parallelism
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RegistryPollsPerMinute != nil {
+		in, out := &in.RegistryPollsPerMinute, &out.RegistryPollsPerMinute
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataImportCronPollingConfig.
+func (in *DataImportCronPollingConfig) DeepCopy() *DataImportCronPollingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DataImportCronPollingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataImportCronSpec) DeepCopyInto(out *DataImportCronSpec) {
 	*out = *in
@@ -515,6 +838,11 @@ func (in *DataImportCronSpec) DeepCopyInto(out *DataImportCronSpec) {
 		*out = new(DataImportCronRetentionPolicy)
 		**out = **in
 	}
+	if in.SourceTagPattern != nil {
+		in, out := &in.SourceTagPattern, &out.SourceTagPattern
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -792,6 +1120,38 @@ func (in *DataVolumeCondition) DeepCopy() *DataVolumeCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeDiskStatus) DeepCopyInto(out *DataVolumeDiskStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeDiskStatus.
+func (in *DataVolumeDiskStatus) DeepCopy() *DataVolumeDiskStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeDiskStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeImportTimings) DeepCopyInto(out *DataVolumeImportTimings) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeImportTimings.
+func (in *DataVolumeImportTimings) DeepCopy() *DataVolumeImportTimings {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeImportTimings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataVolumeList) DeepCopyInto(out *DataVolumeList) {
 	*out = *in
@@ -825,6 +1185,27 @@ func (in *DataVolumeList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSnapshotTarget) DeepCopyInto(out *DataVolumeSnapshotTarget) {
+	*out = *in
+	if in.VolumeSnapshotClassName != nil {
+		in, out := &in.VolumeSnapshotClassName, &out.VolumeSnapshotClassName
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSnapshotTarget.
+func (in *DataVolumeSnapshotTarget) DeepCopy() *DataVolumeSnapshotTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSnapshotTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataVolumeSource) DeepCopyInto(out *DataVolumeSource) {
 	*out = *in
@@ -836,12 +1217,12 @@ func (in *DataVolumeSource) DeepCopyInto(out *DataVolumeSource) {
 	if in.S3 != nil {
 		in, out := &in.S3, &out.S3
 		*out = new(DataVolumeSourceS3)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.GCS != nil {
 		in, out := &in.GCS, &out.GCS
 		*out = new(DataVolumeSourceGCS)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Registry != nil {
 		in, out := &in.Registry, &out.Registry
@@ -878,6 +1259,41 @@ func (in *DataVolumeSource) DeepCopyInto(out *DataVolumeSource) {
 		*out = new(DataVolumeSourceSnapshot)
 		**out = **in
 	}
+	if in.Inline != nil {
+		in, out := &in.Inline, &out.Inline
+		*out = new(DataVolumeSourceInline)
+		**out = **in
+	}
+	if in.NBD != nil {
+		in, out := &in.NBD, &out.NBD
+		*out = new(DataVolumeSourceNBD)
+		**out = **in
+	}
+	if in.Remote != nil {
+		in, out := &in.Remote, &out.Remote
+		*out = new(DataVolumeSourceRemote)
+		**out = **in
+	}
+	if in.RemotePVC != nil {
+		in, out := &in.RemotePVC, &out.RemotePVC
+		*out = new(DataVolumeSourceRemotePVC)
+		**out = **in
+	}
+	if in.FTP != nil {
+		in, out := &in.FTP, &out.FTP
+		*out = new(DataVolumeSourceFTP)
+		**out = **in
+	}
+	if in.Azure != nil {
+		in, out := &in.Azure, &out.Azure
+		*out = new(DataVolumeSourceAzure)
+		**out = **in
+	}
+	if in.SFTP != nil {
+		in, out := &in.SFTP, &out.SFTP
+		*out = new(DataVolumeSourceSFTP)
+		**out = **in
+	}
 	return
 }
 
@@ -891,9 +1307,46 @@ func (in *DataVolumeSource) DeepCopy() *DataVolumeSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceAzure) DeepCopyInto(out *DataVolumeSourceAzure) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceAzure.
+func (in *DataVolumeSourceAzure) DeepCopy() *DataVolumeSourceAzure {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceAzure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceFTP) DeepCopyInto(out *DataVolumeSourceFTP) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceFTP.
+func (in *DataVolumeSourceFTP) DeepCopy() *DataVolumeSourceFTP {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceFTP)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataVolumeSourceGCS) DeepCopyInto(out *DataVolumeSourceGCS) {
 	*out = *in
+	if in.TokenCredentials != nil {
+		in, out := &in.TokenCredentials, &out.TokenCredentials
+		*out = new(DataVolumeSourceTokenCredentials)
+		**out = **in
+	}
 	return
 }
 
@@ -949,6 +1402,38 @@ func (in *DataVolumeSourceImageIO) DeepCopy() *DataVolumeSourceImageIO {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceInline) DeepCopyInto(out *DataVolumeSourceInline) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceInline.
+func (in *DataVolumeSourceInline) DeepCopy() *DataVolumeSourceInline {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceInline)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceNBD) DeepCopyInto(out *DataVolumeSourceNBD) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceNBD.
+func (in *DataVolumeSourceNBD) DeepCopy() *DataVolumeSourceNBD {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceNBD)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataVolumeSourcePVC) DeepCopyInto(out *DataVolumeSourcePVC) {
 	*out = *in
@@ -1014,6 +1499,13 @@ func (in *DataVolumeSourceRegistry) DeepCopyInto(out *DataVolumeSourceRegistry)
 		*out = new(string)
 		**out = **in
 	}
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]RegistryDisk, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -1027,9 +1519,46 @@ func (in *DataVolumeSourceRegistry) DeepCopy() *DataVolumeSourceRegistry {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceRemote) DeepCopyInto(out *DataVolumeSourceRemote) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceRemote.
+func (in *DataVolumeSourceRemote) DeepCopy() *DataVolumeSourceRemote {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceRemote)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceRemotePVC) DeepCopyInto(out *DataVolumeSourceRemotePVC) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceRemotePVC.
+func (in *DataVolumeSourceRemotePVC) DeepCopy() *DataVolumeSourceRemotePVC {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceRemotePVC)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataVolumeSourceS3) DeepCopyInto(out *DataVolumeSourceS3) {
 	*out = *in
+	if in.TokenCredentials != nil {
+		in, out := &in.TokenCredentials, &out.TokenCredentials
+		*out = new(DataVolumeSourceTokenCredentials)
+		**out = **in
+	}
 	return
 }
 
@@ -1043,6 +1572,22 @@ func (in *DataVolumeSourceS3) DeepCopy() *DataVolumeSourceS3 {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceSFTP) DeepCopyInto(out *DataVolumeSourceSFTP) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceSFTP.
+func (in *DataVolumeSourceSFTP) DeepCopy() *DataVolumeSourceSFTP {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceSFTP)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataVolumeSourceSnapshot) DeepCopyInto(out *DataVolumeSourceSnapshot) {
 	*out = *in
@@ -1059,6 +1604,22 @@ func (in *DataVolumeSourceSnapshot) DeepCopy() *DataVolumeSourceSnapshot {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceTokenCredentials) DeepCopyInto(out *DataVolumeSourceTokenCredentials) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceTokenCredentials.
+func (in *DataVolumeSourceTokenCredentials) DeepCopy() *DataVolumeSourceTokenCredentials {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceTokenCredentials)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataVolumeSourceUpload) DeepCopyInto(out *DataVolumeSourceUpload) {
 	*out = *in
@@ -1124,6 +1685,21 @@ func (in *DataVolumeSpec) DeepCopyInto(out *DataVolumeSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.SnapshotTarget != nil {
+		in, out := &in.SnapshotTarget, &out.SnapshotTarget
+		*out = new(DataVolumeSnapshotTarget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WorkerPodPlacement != nil {
+		in, out := &in.WorkerPodPlacement, &out.WorkerPodPlacement
+		*out = new(WorkerPodPlacement)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WorkerPodImage != nil {
+		in, out := &in.WorkerPodImage, &out.WorkerPodImage
+		*out = new(WorkerPodImage)
+		**out = **in
+	}
 	return
 }
 
@@ -1147,6 +1723,20 @@ func (in *DataVolumeStatus) DeepCopyInto(out *DataVolumeStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ImportTimings != nil {
+		in, out := &in.ImportTimings, &out.ImportTimings
+		*out = new(DataVolumeImportTimings)
+		**out = **in
+	}
+	if in.PausedSince != nil {
+		in, out := &in.PausedSince, &out.PausedSince
+		*out = (*in).DeepCopy()
+	}
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]DataVolumeDiskStatus, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -1206,6 +1796,11 @@ func (in *ImportProxy) DeepCopyInto(out *ImportProxy) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.ProxyAuthSecret != nil {
+		in, out := &in.ProxyAuthSecret, &out.ProxyAuthSecret
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -1367,6 +1962,84 @@ func (in *ObjectTransferStatus) DeepCopy() *ObjectTransferStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodIOLimits) DeepCopyInto(out *PodIOLimits) {
+	*out = *in
+	if in.BlockIOClass != nil {
+		in, out := &in.BlockIOClass, &out.BlockIOClass
+		*out = new(string)
+		**out = **in
+	}
+	if in.MaxBytesPerSecond != nil {
+		in, out := &in.MaxBytesPerSecond, &out.MaxBytesPerSecond
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxIOPS != nil {
+		in, out := &in.MaxIOPS, &out.MaxIOPS
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodIOLimits.
+func (in *PodIOLimits) DeepCopy() *PodIOLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(PodIOLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryDisk) DeepCopyInto(out *RegistryDisk) {
+	*out = *in
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryDisk.
+func (in *RegistryDisk) DeepCopy() *RegistryDisk {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryDisk)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScratchSpaceConfig) DeepCopyInto(out *ScratchSpaceConfig) {
+	*out = *in
+	if in.MaxEmptyDirSize != nil {
+		in, out := &in.MaxEmptyDirSize, &out.MaxEmptyDirSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScratchSpaceConfig.
+func (in *ScratchSpaceConfig) DeepCopy() *ScratchSpaceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ScratchSpaceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageProfile) DeepCopyInto(out *StorageProfile) {
 	*out = *in
@@ -1443,6 +2116,31 @@ func (in *StorageProfileSpec) DeepCopyInto(out *StorageProfileSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Preallocation != nil {
+		in, out := &in.Preallocation, &out.Preallocation
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ThinProvisioned != nil {
+		in, out := &in.ThinProvisioned, &out.ThinProvisioned
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FilesystemOverhead != nil {
+		in, out := &in.FilesystemOverhead, &out.FilesystemOverhead
+		*out = new(Percent)
+		**out = **in
+	}
+	if in.CloneStreams != nil {
+		in, out := &in.CloneStreams, &out.CloneStreams
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CrossClassSnapshotRestore != nil {
+		in, out := &in.CrossClassSnapshotRestore, &out.CrossClassSnapshotRestore
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -1481,6 +2179,31 @@ func (in *StorageProfileStatus) DeepCopyInto(out *StorageProfileStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Preallocation != nil {
+		in, out := &in.Preallocation, &out.Preallocation
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ThinProvisioned != nil {
+		in, out := &in.ThinProvisioned, &out.ThinProvisioned
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FilesystemOverhead != nil {
+		in, out := &in.FilesystemOverhead, &out.FilesystemOverhead
+		*out = new(Percent)
+		**out = **in
+	}
+	if in.CloneStreams != nil {
+		in, out := &in.CloneStreams, &out.CloneStreams
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CrossClassSnapshotRestore != nil {
+		in, out := &in.CrossClassSnapshotRestore, &out.CrossClassSnapshotRestore
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -1589,3 +2312,49 @@ func (in *TransferTarget) DeepCopy() *TransferTarget {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkerPodImage) DeepCopyInto(out *WorkerPodImage) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkerPodImage.
+func (in *WorkerPodImage) DeepCopy() *WorkerPodImage {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkerPodImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkerPodPlacement) DeepCopyInto(out *WorkerPodPlacement) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkerPodPlacement.
+func (in *WorkerPodPlacement) DeepCopy() *WorkerPodPlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkerPodPlacement)
+	in.DeepCopyInto(out)
+	return out
+}
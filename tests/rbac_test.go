@@ -211,7 +211,7 @@ var _ = Describe("Aggregated role in-action tests", func() {
 })
 
 var _ = Describe("Aggregated role definition tests", func() {
-	var adminRules = []rbacv1.PolicyRule{
+	var editRules = []rbacv1.PolicyRule{
 		{
 			APIGroups: []string{
 				"cdi.kubevirt.io",
@@ -247,7 +247,17 @@ var _ = Describe("Aggregated role definition tests", func() {
 		},
 	}
 
-	var editRules = adminRules
+	var adminRules = append(editRules, rbacv1.PolicyRule{
+		APIGroups: []string{
+			"cdi.kubevirt.io",
+		},
+		Resources: []string{
+			"clonegrants",
+		},
+		Verbs: []string{
+			"*",
+		},
+	})
 
 	var viewRules = []rbacv1.PolicyRule{
 		{
@@ -256,6 +266,7 @@ var _ = Describe("Aggregated role definition tests", func() {
 			},
 			Resources: []string{
 				"cdiconfigs",
+				"clonegrants",
 				"dataimportcrons",
 				"datasources",
 				"datavolumes",
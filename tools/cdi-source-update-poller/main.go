@@ -27,6 +27,7 @@ var (
 	cronNamespace string
 	cronName      string
 	url           string
+	tagPattern    string
 	certDir       string
 	accessKey     string
 	secretKey     string
@@ -39,6 +40,7 @@ func init() {
 	flag.StringVar(&cronNamespace, "ns", "", "DataImportCron namespace.")
 	flag.StringVar(&cronName, "cron", "", "DataImportCron name.")
 	flag.StringVar(&url, "url", "", "registry source url.")
+	flag.StringVar(&tagPattern, "tag-pattern", "", "(Optional) regular expression selecting the source tags, the highest semantic version is used.")
 	flag.StringVar(&certDir, "certdir", "", "registry certificates path.")
 	flag.Parse()
 	if url == "" || cronNamespace == "" || cronName == "" {
@@ -56,6 +58,18 @@ func main() {
 		allCertDir = certDir
 	}
 
+	if tagPattern != "" {
+		taggedURL, err := importer.GetHighestSemverTaggedImage(url, tagPattern, accessKey, secretKey, allCertDir, insecureTLS)
+		if err != nil {
+			log.Fatalf("Failed to get image tags: %v", err)
+		}
+		if taggedURL == "" {
+			log.Fatalf("No semantic version tag matches %q", tagPattern)
+		}
+		log.Printf("Highest semantic version image is %s", taggedURL)
+		url = taggedURL
+	}
+
 	digest, err := importer.GetImageDigest(url, accessKey, secretKey, allCertDir, insecureTLS)
 	if err != nil {
 		log.Fatalf("Failed to get image digest: %v", err)
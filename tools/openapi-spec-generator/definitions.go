@@ -56,6 +56,7 @@ func CoreAPI() []*restful.WebService {
 
 	dvGVR := getGVR("datavolumes")
 	dsGVR := getGVR("datasources")
+	cgGVR := getGVR("clonegrants")
 	dicGVR := getGVR("dataimportcrons")
 	cdiGVR := getGVR("cdis")
 	cdiConfigGVR := getGVR("cdiconfigs")
@@ -75,6 +76,11 @@ func CoreAPI() []*restful.WebService {
 		panic(err)
 	}
 
+	ws, err = genericResourceProxy(ws, cgGVR, &cdiv1.CloneGrant{}, "CloneGrant", &cdiv1.CloneGrantList{})
+	if err != nil {
+		panic(err)
+	}
+
 	ws, err = genericResourceProxy(ws, dicGVR, &cdiv1.DataImportCron{}, "DataImportCron", &cdiv1.DataImportCronList{})
 	if err != nil {
 		panic(err)